@@ -10,7 +10,8 @@ import (
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/plugin"
 
-	_ "modernc.org/sqlite" // sqlite driver for RPM DB and Java DB
+	_ "github.com/lib/pq"  // postgres driver for the SQL export
+	_ "modernc.org/sqlite" // sqlite driver for RPM DB, Java DB and the SQL export
 )
 
 func main() {
@@ -166,7 +166,7 @@ func Yacc() error {
 // Easyjson generates JSON marshaler/unmarshaler for TinyGo/WebAssembly as TinyGo doesn't support encoding/json.
 func Easyjson() error {
 	mg.Deps(Tool{}.EasyJSON)
-	return sh.Run("easyjson", "./pkg/module/serialize/types.go")
+	return sh.Run("easyjson", "./pkg/module/serialize/types.go", "./pkg/module/serialize/analysis.go")
 }
 
 type Test mg.Namespace
@@ -183,9 +183,13 @@ func (Test) FixtureVMImages() error {
 
 // GenerateModules compiles WASM modules for unit tests
 func (Test) GenerateModules() error {
-	pattern := filepath.Join("pkg", "module", "testdata", "*", "*.go")
-	if err := compileWasmModules(pattern); err != nil {
-		return err
+	for _, pattern := range []string{
+		filepath.Join("pkg", "module", "testdata", "*", "*.go"),
+		filepath.Join("pkg", "module", "moduletest", "testdata", "*", "*.go"),
+	} {
+		if err := compileWasmModules(pattern); err != nil {
+			return err
+		}
 	}
 	return nil
 }
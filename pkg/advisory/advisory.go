@@ -0,0 +1,149 @@
+package advisory
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	TypeOSV  = "osv"
+	TypeCSAF = "csaf"
+)
+
+var (
+	sources []Source
+	mu      sync.RWMutex
+)
+
+// Source is a data source of advisories in addition to trivy-db.
+// The advisories are merged with those of trivy-db at detection time,
+// so that packages trivy-db doesn't know, such as private packages, can be matched.
+type Source interface {
+	// DataSource returns the data source shown in detected vulnerabilities
+	DataSource() dbTypes.DataSource
+
+	// Advisories returns the advisories of the package.
+	// The package name is normalized in the same way as trivy-db.
+	Advisories(ecosystem dbTypes.Ecosystem, pkgName string) []dbTypes.Advisory
+
+	// Vulnerability returns the detail of the vulnerability
+	Vulnerability(vulnID string) (dbTypes.Vulnerability, bool)
+}
+
+// RegisterSource registers the advisory source
+func RegisterSource(s Source) {
+	mu.Lock()
+	defer mu.Unlock()
+	sources = append(sources, s)
+}
+
+// DeregisterSources removes all the registered sources
+func DeregisterSources() {
+	mu.Lock()
+	defer mu.Unlock()
+	sources = nil
+}
+
+// Load loads the advisory sources in the format "<type>:<dir>", e.g. "osv:./feeds/internal", and registers them
+func Load(specs []string) error {
+	for _, spec := range specs {
+		typ, dir, ok := strings.Cut(spec, ":")
+		if !ok || dir == "" {
+			return xerrors.Errorf("invalid advisory source %q: must be in the format '<type>:<dir>'", spec)
+		}
+
+		var s Source
+		var err error
+		switch typ {
+		case TypeOSV:
+			s, err = LoadOSV(dir)
+		case TypeCSAF:
+			s, err = LoadCSAF(dir)
+		default:
+			return xerrors.Errorf("unknown advisory source type %q: must be %q or %q", typ, TypeOSV, TypeCSAF)
+		}
+		if err != nil {
+			return xerrors.Errorf("advisory source error (%s): %w", spec, err)
+		}
+		RegisterSource(s)
+	}
+	return nil
+}
+
+// Advisories returns the advisories of the package from all the registered sources
+func Advisories(ecosystem dbTypes.Ecosystem, pkgName string) []dbTypes.Advisory {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var advisories []dbTypes.Advisory
+	for _, s := range sources {
+		dataSource := s.DataSource()
+		for _, adv := range s.Advisories(ecosystem, pkgName) {
+			adv.DataSource = &dataSource
+			advisories = append(advisories, adv)
+		}
+	}
+	return advisories
+}
+
+// Vulnerability returns the detail of the vulnerability from the registered sources.
+// The first source having the vulnerability takes precedence.
+func Vulnerability(vulnID string) (dbTypes.Vulnerability, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, s := range sources {
+		if vuln, ok := s.Vulnerability(vulnID); ok {
+			return vuln, true
+		}
+	}
+	return dbTypes.Vulnerability{}, false
+}
+
+// memorySource holds advisories loaded from files
+type memorySource struct {
+	dataSource      dbTypes.DataSource
+	advisories      map[string][]dbTypes.Advisory // "<ecosystem>::<package name>" => advisories
+	vulnerabilities map[string]dbTypes.Vulnerability
+}
+
+func newMemorySource(dataSource dbTypes.DataSource) *memorySource {
+	return &memorySource{
+		dataSource:      dataSource,
+		advisories:      make(map[string][]dbTypes.Advisory),
+		vulnerabilities: make(map[string]dbTypes.Vulnerability),
+	}
+}
+
+func (s *memorySource) DataSource() dbTypes.DataSource {
+	return s.dataSource
+}
+
+func (s *memorySource) Advisories(ecosystem dbTypes.Ecosystem, pkgName string) []dbTypes.Advisory {
+	return s.advisories[advisoryKey(ecosystem, pkgName)]
+}
+
+func (s *memorySource) Vulnerability(vulnID string) (dbTypes.Vulnerability, bool) {
+	vuln, ok := s.vulnerabilities[vulnID]
+	return vuln, ok
+}
+
+func (s *memorySource) add(ecosystem dbTypes.Ecosystem, pkgName string, adv dbTypes.Advisory) {
+	key := advisoryKey(ecosystem, pkgName)
+	s.advisories[key] = append(s.advisories[key], adv)
+}
+
+func (s *memorySource) summary() {
+	log.Logger.Infof("Loaded %d advisories of %d vulnerabilities from %s", len(s.advisories),
+		len(s.vulnerabilities), s.dataSource.Name)
+}
+
+func advisoryKey(ecosystem dbTypes.Ecosystem, pkgName string) string {
+	return fmt.Sprintf("%s::%s", ecosystem, pkgName)
+}
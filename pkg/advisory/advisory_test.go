@@ -0,0 +1,154 @@
+package advisory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/advisory"
+)
+
+func TestLoad(t *testing.T) {
+	osvSource := &dbTypes.DataSource{
+		ID:   "osv:osv",
+		Name: "OSV feed testdata/osv",
+	}
+	csafSource := &dbTypes.DataSource{
+		ID:   "csaf:csaf",
+		Name: "CSAF advisories testdata/csaf",
+	}
+
+	type query struct {
+		ecosystem dbTypes.Ecosystem
+		pkgName   string
+	}
+	tests := []struct {
+		name           string
+		specs          []string
+		query          query
+		want           []dbTypes.Advisory
+		vulnID         string
+		wantVuln       dbTypes.Vulnerability
+		wantVulnExists bool
+		wantErr        string
+	}{
+		{
+			name:  "osv",
+			specs: []string{"osv:testdata/osv"},
+			query: query{
+				ecosystem: vulnerability.Npm,
+				pkgName:   "@acme/utils",
+			},
+			want: []dbTypes.Advisory{
+				{
+					VulnerabilityID: "INTERNAL-2024-0001",
+					VendorIDs:       []string{"CVE-2024-1111"},
+					VulnerableVersions: []string{
+						">=1.0.0, <1.4.2",
+						"=0.9.0",
+					},
+					PatchedVersions: []string{"1.4.2"},
+					DataSource:      osvSource,
+				},
+			},
+			vulnID: "INTERNAL-2024-0001",
+			wantVuln: dbTypes.Vulnerability{
+				Title:       "Prototype pollution in @acme/utils",
+				Description: "Merging untrusted objects allows prototype pollution.",
+				CweIDs:      []string{"CWE-1321"},
+				VendorSeverity: dbTypes.VendorSeverity{
+					"osv:osv": dbTypes.SeverityCritical,
+				},
+				CVSS: dbTypes.VendorCVSS{
+					"osv:osv": {
+						V3Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+					},
+				},
+				References:       []string{"https://security.acme.example/INTERNAL-2024-0001"},
+				PublishedDate:    lo.ToPtr(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)),
+				LastModifiedDate: lo.ToPtr(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			wantVulnExists: true,
+		},
+		{
+			name:  "osv withdrawn",
+			specs: []string{"osv:testdata/osv"},
+			query: query{
+				ecosystem: vulnerability.Pip,
+				pkgName:   "acme-client",
+			},
+			vulnID: "INTERNAL-2023-0009",
+		},
+		{
+			name:  "csaf",
+			specs: []string{"csaf:testdata/csaf"},
+			query: query{
+				ecosystem: vulnerability.Maven,
+				pkgName:   "com.acme:acme-core",
+			},
+			want: []dbTypes.Advisory{
+				{
+					VulnerabilityID:    "CVE-2024-2222",
+					VulnerableVersions: []string{"=1.2.0"},
+					PatchedVersions:    []string{"1.3.0"},
+					DataSource:         csafSource,
+				},
+			},
+			vulnID: "CVE-2024-2222",
+			wantVuln: dbTypes.Vulnerability{
+				Title:       "Deserialization in acme-core",
+				Description: "Untrusted data is deserialized.",
+				VendorSeverity: dbTypes.VendorSeverity{
+					"csaf:csaf": dbTypes.SeverityHigh,
+				},
+				CVSS: dbTypes.VendorCVSS{
+					"csaf:csaf": {
+						V3Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N",
+						V3Score:  7.5,
+					},
+				},
+				References: []string{"https://security.acme.example/ACME-2024-0002"},
+			},
+			wantVulnExists: true,
+		},
+		{
+			name:    "invalid format",
+			specs:   []string{"testdata/osv"},
+			wantErr: "must be in the format '<type>:<dir>'",
+		},
+		{
+			name:    "unknown type",
+			specs:   []string{"oval:testdata/osv"},
+			wantErr: "unknown advisory source type",
+		},
+		{
+			name:    "no such directory",
+			specs:   []string{"osv:testdata/unknown"},
+			wantErr: "no such file or directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer advisory.DeregisterSources()
+
+			err := advisory.Load(tt.specs)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got := advisory.Advisories(tt.query.ecosystem, tt.query.pkgName)
+			assert.Equal(t, tt.want, got)
+
+			gotVuln, ok := advisory.Vulnerability(tt.vulnID)
+			assert.Equal(t, tt.wantVulnExists, ok)
+			assert.Equal(t, tt.wantVuln, gotVuln)
+		})
+	}
+}
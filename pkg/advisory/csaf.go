@@ -0,0 +1,228 @@
+package advisory
+
+import (
+	"io/fs"
+	"path/filepath"
+
+	csaf "github.com/csaf-poc/csaf_distribution/v3/csaf"
+	"github.com/package-url/packageurl-go"
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/purl"
+)
+
+// LoadCSAF loads CSAF advisories under the directory.
+// Only products identified by PURL of language-specific packages are supported.
+// cf. https://docs.oasis-open.org/csaf/csaf/v2.0/csaf-v2.0.html
+func LoadCSAF(dir string) (Source, error) {
+	s := newMemorySource(dbTypes.DataSource{
+		ID:   dbTypes.SourceID("csaf:" + filepath.Base(dir)),
+		Name: "CSAF advisories " + dir,
+	})
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		adv, err := csaf.LoadAdvisory(path)
+		if err != nil {
+			return xerrors.Errorf("CSAF load error (%s): %w", path, err)
+		}
+		s.addCSAFAdvisory(adv)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("walk error: %w", err)
+	}
+
+	s.summary()
+	return s, nil
+}
+
+func (s *memorySource) addCSAFAdvisory(adv *csaf.Advisory) {
+	for _, vuln := range adv.Vulnerabilities {
+		if vuln == nil {
+			continue
+		}
+		vulnID := csafVulnerabilityID(vuln)
+		if vulnID == "" {
+			continue
+		}
+
+		// Version constraints per package
+		advisories := make(map[string]*csafPackageAdvisory)
+		addVersions := func(products *csaf.Products, f func(a *dbTypes.Advisory, ver string)) {
+			for _, p := range csafPurls(adv, products) {
+				ecosystem := purlEcosystem(p)
+				if ecosystem == vulnerability.Unknown {
+					log.Logger.Debugf("Unsupported package in CSAF advisory: %s", p.String())
+					continue
+				} else if p.Version == "" {
+					continue
+				}
+				pkgName := vulnerability.NormalizePkgName(ecosystem, p.Package().Name)
+
+				key := advisoryKey(ecosystem, pkgName)
+				if _, ok := advisories[key]; !ok {
+					advisories[key] = &csafPackageAdvisory{
+						ecosystem: ecosystem,
+						pkgName:   pkgName,
+						advisory: dbTypes.Advisory{
+							VulnerabilityID: vulnID,
+						},
+					}
+				}
+				f(&advisories[key].advisory, p.Version)
+			}
+		}
+
+		if status := vuln.ProductStatus; status != nil {
+			addVersions(status.KnownAffected, func(a *dbTypes.Advisory, ver string) {
+				a.VulnerableVersions = append(a.VulnerableVersions, "="+ver)
+			})
+			addVersions(status.FirstAffected, func(a *dbTypes.Advisory, ver string) {
+				a.VulnerableVersions = append(a.VulnerableVersions, ">="+ver)
+			})
+			addVersions(status.LastAffected, func(a *dbTypes.Advisory, ver string) {
+				a.VulnerableVersions = append(a.VulnerableVersions, "<="+ver)
+			})
+			addVersions(status.Fixed, func(a *dbTypes.Advisory, ver string) {
+				a.PatchedVersions = append(a.PatchedVersions, ver)
+			})
+			addVersions(status.FirstFixed, func(a *dbTypes.Advisory, ver string) {
+				a.PatchedVersions = append(a.PatchedVersions, ver)
+				a.UnaffectedVersions = append(a.UnaffectedVersions, ">="+ver)
+			})
+		}
+
+		for _, a := range advisories {
+			s.add(a.ecosystem, a.pkgName, a.advisory)
+		}
+		s.vulnerabilities[vulnID] = s.csafVulnerability(vuln)
+	}
+}
+
+type csafPackageAdvisory struct {
+	ecosystem dbTypes.Ecosystem
+	pkgName   string
+	advisory  dbTypes.Advisory
+}
+
+func (s *memorySource) csafVulnerability(vuln *csaf.Vulnerability) dbTypes.Vulnerability {
+	v := dbTypes.Vulnerability{
+		Title: lo.FromPtr(vuln.Title),
+	}
+	for _, note := range vuln.Notes {
+		if note != nil && lo.FromPtr(note.NoteCategory) == csaf.CSAFNoteCategoryDescription {
+			v.Description = lo.FromPtr(note.Text)
+			break
+		}
+	}
+	if vuln.CWE != nil {
+		v.CweIDs = []string{string(lo.FromPtr(vuln.CWE.ID))}
+	}
+	for _, ref := range vuln.References {
+		if ref != nil && ref.URL != nil {
+			v.References = append(v.References, *ref.URL)
+		}
+	}
+	for _, score := range vuln.Scores {
+		if score == nil || score.CVSS3 == nil {
+			continue
+		}
+		v.CVSS = dbTypes.VendorCVSS{
+			s.dataSource.ID: {
+				V3Vector: string(lo.FromPtr(score.CVSS3.VectorString)),
+				V3Score:  lo.FromPtr(score.CVSS3.BaseScore),
+			},
+		}
+		v.VendorSeverity = dbTypes.VendorSeverity{
+			s.dataSource.ID: parseSeverity(string(lo.FromPtr(score.CVSS3.BaseSeverity))),
+		}
+		break
+	}
+	return v
+}
+
+// csafVulnerabilityID returns the CVE-ID, or the first vendor ID when the CVE-ID is not assigned
+func csafVulnerabilityID(vuln *csaf.Vulnerability) string {
+	if vuln.CVE != nil {
+		return string(*vuln.CVE)
+	}
+	for _, id := range vuln.IDs {
+		if id != nil && id.Text != nil {
+			return *id.Text
+		}
+	}
+	return ""
+}
+
+func csafPurls(adv *csaf.Advisory, products *csaf.Products) []*purl.PackageURL {
+	if adv.ProductTree == nil || products == nil {
+		return nil
+	}
+	var purls []*purl.PackageURL
+	for _, product := range *products {
+		if product == nil {
+			continue
+		}
+		for _, helper := range adv.ProductTree.CollectProductIdentificationHelpers(*product) {
+			if helper == nil || helper.PURL == nil {
+				continue
+			}
+			p, err := purl.FromString(string(*helper.PURL))
+			if err != nil {
+				log.Logger.Debugf("Invalid PURL in CSAF advisory: %s", err)
+				continue
+			}
+			purls = append(purls, p)
+		}
+	}
+	return purls
+}
+
+// purlEcosystem returns the trivy-db ecosystem of the package URL
+func purlEcosystem(p *purl.PackageURL) dbTypes.Ecosystem {
+	switch p.Type {
+	case packageurl.TypeNPM:
+		return vulnerability.Npm
+	case packageurl.TypePyPi:
+		return vulnerability.Pip
+	case packageurl.TypeMaven:
+		return vulnerability.Maven
+	case packageurl.TypeGolang:
+		return vulnerability.Go
+	case packageurl.TypeGem:
+		return vulnerability.RubyGems
+	case packageurl.TypeCargo:
+		return vulnerability.Cargo
+	case packageurl.TypeNuget:
+		return vulnerability.NuGet
+	case packageurl.TypeComposer:
+		return vulnerability.Composer
+	case packageurl.TypePub:
+		return vulnerability.Pub
+	case packageurl.TypeHex:
+		return vulnerability.Erlang
+	case packageurl.TypeSwift:
+		return vulnerability.Swift
+	case packageurl.TypeCocoapods:
+		return vulnerability.Cocoapods
+	case packageurl.TypeConan:
+		return vulnerability.Conan
+	case packageurl.TypeBitnami:
+		return vulnerability.Bitnami
+	case purl.TypeK8s:
+		return vulnerability.Kubernetes
+	default:
+		// OS packages are not supported since the detection is done per OS
+		return vulnerability.Unknown
+	}
+}
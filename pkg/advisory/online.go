@@ -0,0 +1,289 @@
+package advisory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/osv"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	DefaultOSVAPIURL = "https://api.osv.dev"
+
+	// OSV.dev accepts up to 1,000 queries per batch
+	// cf. https://google.github.io/osv.dev/post-v1-querybatch/
+	osvBatchSize = 1000
+
+	osvRequestTimeout = 30 * time.Second
+)
+
+var osvDataSource = dbTypes.DataSource{
+	ID:   "osv.dev",
+	Name: "OSV.dev",
+	URL:  "https://osv.dev",
+}
+
+// OnlineOptions represents options for the online lookup
+type OnlineOptions struct {
+	// URL is the base URL of the OSV API
+	URL string
+
+	// CacheDir is the directory where vulnerability details are cached
+	CacheDir string
+}
+
+// OnlineSource queries OSV.dev for ecosystems not covered by trivy-db.
+// The vulnerability details are cached in the cache directory and refreshed when they are modified.
+// It must be registered with RegisterSource so that the details are filled in.
+type OnlineSource struct {
+	url        string
+	cacheDir   string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	entries map[string]osv.Entry
+}
+
+// NewOnlineSource returns the source looking up OSV.dev
+func NewOnlineSource(opts OnlineOptions) *OnlineSource {
+	if opts.URL == "" {
+		opts.URL = DefaultOSVAPIURL
+	}
+	return &OnlineSource{
+		url:        strings.TrimSuffix(opts.URL, "/"),
+		cacheDir:   filepath.Join(opts.CacheDir, "osv-online"),
+		httpClient: &http.Client{Timeout: osvRequestTimeout},
+		entries:    make(map[string]osv.Entry),
+	}
+}
+
+type osvQuery struct {
+	Package   osv.Package `json:"package"`
+	Version   string      `json:"version"`
+	PageToken string      `json:"page_token,omitempty"`
+}
+
+type osvBatchRequest struct {
+	Queries []osvQuery `json:"queries"`
+}
+
+type osvBatchResponse struct {
+	Results []osvBatchResult `json:"results"`
+}
+
+type osvBatchResult struct {
+	Vulns []struct {
+		ID       string    `json:"id"`
+		Modified time.Time `json:"modified"`
+	} `json:"vulns"`
+	NextPageToken string `json:"next_page_token"`
+}
+
+func (s *OnlineSource) DataSource() dbTypes.DataSource {
+	return osvDataSource
+}
+
+// Advisories returns nothing since versions are matched by OSV.dev
+func (s *OnlineSource) Advisories(_ dbTypes.Ecosystem, _ string) []dbTypes.Advisory {
+	return nil
+}
+
+func (s *OnlineSource) Vulnerability(vulnID string) (dbTypes.Vulnerability, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[vulnID]
+	if !ok {
+		return dbTypes.Vulnerability{}, false
+	}
+	return osvVulnerability(entry, osvDataSource.ID), true
+}
+
+// Detect queries OSV.dev for vulnerabilities of the packages in the OSV ecosystem
+func (s *OnlineSource) Detect(ctx context.Context, ecosystem osv.Ecosystem, pkgs []ftypes.Package) ([]types.DetectedVulnerability, error) {
+	pkgs = lo.Filter(pkgs, func(pkg ftypes.Package, _ int) bool {
+		return pkg.Name != "" && pkg.Version != ""
+	})
+	if len(pkgs) == 0 {
+		return nil, nil
+	}
+
+	queries := lo.Map(pkgs, func(pkg ftypes.Package, _ int) osvQuery {
+		return osvQuery{
+			Package: osv.Package{
+				Name:      pkg.Name,
+				Ecosystem: ecosystem,
+			},
+			Version: pkg.Version,
+		}
+	})
+
+	// Vulnerability IDs and their modified time per package
+	vulnIDs := make([][]string, len(pkgs))
+	modified := make(map[string]time.Time)
+
+	pending := lo.Range(len(queries))
+	for len(pending) > 0 {
+		var next []int
+		for _, chunk := range lo.Chunk(pending, osvBatchSize) {
+			res, err := s.queryBatch(ctx, lo.Map(chunk, func(i int, _ int) osvQuery {
+				return queries[i]
+			}))
+			if err != nil {
+				return nil, xerrors.Errorf("OSV.dev query error: %w", err)
+			} else if len(res.Results) != len(chunk) {
+				return nil, xerrors.Errorf("OSV.dev returned %d results for %d queries", len(res.Results), len(chunk))
+			}
+
+			for j, result := range res.Results {
+				i := chunk[j]
+				for _, v := range result.Vulns {
+					vulnIDs[i] = append(vulnIDs[i], v.ID)
+					modified[v.ID] = v.Modified
+				}
+				// Large results are paginated
+				if result.NextPageToken != "" {
+					queries[i].PageToken = result.NextPageToken
+					next = append(next, i)
+				}
+			}
+		}
+		pending = next
+	}
+
+	var vulns []types.DetectedVulnerability
+	for i, pkg := range pkgs {
+		for _, vulnID := range lo.Uniq(vulnIDs[i]) {
+			entry, err := s.entry(ctx, vulnID, modified[vulnID])
+			if err != nil {
+				return nil, xerrors.Errorf("OSV.dev vulnerability error: %w", err)
+			}
+
+			vulns = append(vulns, types.DetectedVulnerability{
+				VulnerabilityID:  entry.ID,
+				VendorIDs:        entry.Aliases,
+				PkgID:            pkg.ID,
+				PkgName:          pkg.Name,
+				InstalledVersion: pkg.Version,
+				FixedVersion:     osvFixedVersion(entry, ecosystem, pkg.Name),
+				PkgPath:          pkg.FilePath,
+				PkgIdentifier:    pkg.Identifier,
+				Layer:            pkg.Layer,
+				DataSource:       lo.ToPtr(osvDataSource),
+			})
+		}
+	}
+	return vulns, nil
+}
+
+func (s *OnlineSource) queryBatch(ctx context.Context, queries []osvQuery) (osvBatchResponse, error) {
+	b, err := json.Marshal(osvBatchRequest{Queries: queries})
+	if err != nil {
+		return osvBatchResponse{}, xerrors.Errorf("JSON encode error: %w", err)
+	}
+
+	var res osvBatchResponse
+	if err = s.do(ctx, http.MethodPost, "/v1/querybatch", b, &res); err != nil {
+		return osvBatchResponse{}, err
+	}
+	return res, nil
+}
+
+// entry returns the OSV entry from the memory, the cache directory or OSV.dev in this order.
+// The cached entry is used only when it is not modified since then.
+func (s *OnlineSource) entry(ctx context.Context, vulnID string, modified time.Time) (osv.Entry, error) {
+	s.mu.Lock()
+	entry, ok := s.entries[vulnID]
+	s.mu.Unlock()
+	if ok && entry.Modified.Equal(modified) {
+		return entry, nil
+	}
+
+	cachePath := filepath.Join(s.cacheDir, filepath.Base(vulnID)+".json")
+	if b, err := os.ReadFile(cachePath); err == nil {
+		var cached osv.Entry
+		if err = json.Unmarshal(b, &cached); err == nil && cached.Modified.Equal(modified) {
+			s.store(cached)
+			return cached, nil
+		}
+	}
+
+	log.Logger.Debugf("Fetching %s from OSV.dev...", vulnID)
+	entry = osv.Entry{}
+	if err := s.do(ctx, http.MethodGet, "/v1/vulns/"+url.PathEscape(vulnID), nil, &entry); err != nil {
+		return osv.Entry{}, err
+	}
+	s.store(entry)
+
+	// The cache is best-effort
+	if b, err := json.Marshal(entry); err == nil {
+		if err = os.MkdirAll(s.cacheDir, 0o700); err == nil {
+			err = os.WriteFile(cachePath, b, 0o600)
+		}
+		if err != nil {
+			log.Logger.Debugf("Unable to cache %s: %s", vulnID, err)
+		}
+	}
+	return entry, nil
+}
+
+func (s *OnlineSource) store(entry osv.Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[entry.ID] = entry
+}
+
+func (s *OnlineSource) do(ctx context.Context, method, path string, body []byte, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.url+path, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("unable to create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return xerrors.Errorf("JSON decode error: %w", err)
+	}
+	return nil
+}
+
+// osvFixedVersion returns the fixed versions of the package in the entry
+func osvFixedVersion(entry osv.Entry, ecosystem osv.Ecosystem, pkgName string) string {
+	var fixedVersions []string
+	for _, affected := range entry.Affected {
+		if affected.Package.Ecosystem != ecosystem || affected.Package.Name != pkgName {
+			continue
+		}
+		for _, r := range affected.Ranges {
+			for _, event := range r.Events {
+				if event.Fixed != "" && r.Type != osv.RangeTypeGit {
+					fixedVersions = append(fixedVersions, event.Fixed)
+				}
+			}
+		}
+	}
+	return strings.Join(lo.Uniq(fixedVersions), ", ")
+}
@@ -0,0 +1,173 @@
+package advisory_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/advisory"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const testOSVEntry = `{
+  "id": "HSEC-2023-0001",
+  "modified": "2023-08-01T00:00:00Z",
+  "aliases": ["CVE-2022-3433"],
+  "summary": "Hash flooding in aeson",
+  "details": "Collisions in hashes allow denial of service.",
+  "affected": [
+    {
+      "package": {"ecosystem": "Hackage", "name": "aeson"},
+      "ranges": [
+        {
+          "type": "ECOSYSTEM",
+          "events": [{"introduced": "0.4.0.0"}, {"fixed": "2.0.1.0"}]
+        }
+      ]
+    }
+  ],
+  "database_specific": {"severity": "HIGH"}
+}`
+
+func TestOnlineSource_Detect(t *testing.T) {
+	var batches, details atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/querybatch":
+			batches.Add(1)
+			var req struct {
+				Queries []struct {
+					Package struct {
+						Name      string `json:"name"`
+						Ecosystem string `json:"ecosystem"`
+					} `json:"package"`
+					Version   string `json:"version"`
+					PageToken string `json:"page_token"`
+				} `json:"queries"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+			var results []string
+			for _, q := range req.Queries {
+				assert.Equal(t, "Hackage", q.Package.Ecosystem)
+				switch {
+				case q.Package.Name == "aeson" && q.PageToken == "":
+					// The first page is empty to check pagination
+					results = append(results, `{"next_page_token": "page2"}`)
+				case q.Package.Name == "aeson":
+					results = append(results, `{"vulns": [{"id": "HSEC-2023-0001", "modified": "2023-08-01T00:00:00Z"}]}`)
+				default:
+					results = append(results, `{}`)
+				}
+			}
+			_, _ = w.Write([]byte(`{"results": [` + strings.Join(results, ",") + `]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/vulns/HSEC-2023-0001":
+			details.Add(1)
+			_, _ = w.Write([]byte(testOSVEntry))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	pkgs := []ftypes.Package{
+		{
+			ID:       "aeson@1.5.6.0",
+			Name:     "aeson",
+			Version:  "1.5.6.0",
+			FilePath: "app.cabal",
+		},
+		{
+			ID:      "text@2.0.2",
+			Name:    "text",
+			Version: "2.0.2",
+		},
+		{
+			Name: "no-version",
+		},
+	}
+	want := []types.DetectedVulnerability{
+		{
+			VulnerabilityID:  "HSEC-2023-0001",
+			VendorIDs:        []string{"CVE-2022-3433"},
+			PkgID:            "aeson@1.5.6.0",
+			PkgName:          "aeson",
+			PkgPath:          "app.cabal",
+			InstalledVersion: "1.5.6.0",
+			FixedVersion:     "2.0.1.0",
+			DataSource: &dbTypes.DataSource{
+				ID:   "osv.dev",
+				Name: "OSV.dev",
+				URL:  "https://osv.dev",
+			},
+		},
+	}
+	cacheDir := t.TempDir()
+	ctx := context.Background()
+
+	t.Run("happy path", func(t *testing.T) {
+		online := advisory.NewOnlineSource(advisory.OnlineOptions{
+			URL:      ts.URL,
+			CacheDir: cacheDir,
+		})
+		advisory.RegisterSource(online)
+		defer advisory.DeregisterSources()
+
+		got, err := online.Detect(ctx, "Hackage", pkgs)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, int32(2), batches.Load())
+		assert.Equal(t, int32(1), details.Load())
+
+		// The detail is filled from the online source
+		vuln, ok := advisory.Vulnerability("HSEC-2023-0001")
+		require.True(t, ok)
+		assert.Equal(t, "Hash flooding in aeson", vuln.Title)
+		assert.Equal(t, dbTypes.SeverityHigh, vuln.VendorSeverity["osv.dev"])
+		assert.Equal(t, time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC), *vuln.LastModifiedDate)
+	})
+
+	t.Run("cached details", func(t *testing.T) {
+		online := advisory.NewOnlineSource(advisory.OnlineOptions{
+			URL:      ts.URL,
+			CacheDir: cacheDir,
+		})
+
+		got, err := online.Detect(ctx, "Hackage", pkgs)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, int32(1), details.Load(), "the detail should be read from the cache")
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		online := advisory.NewOnlineSource(advisory.OnlineOptions{
+			URL:      ts.URL,
+			CacheDir: t.TempDir(),
+		})
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := online.Detect(canceled, "Hackage", pkgs)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		online := advisory.NewOnlineSource(advisory.OnlineOptions{
+			URL:      ts.URL + "/unknown",
+			CacheDir: cacheDir,
+		})
+
+		_, err := online.Detect(ctx, "Hackage", pkgs)
+		require.ErrorContains(t, err, "404 Not Found")
+	})
+}
@@ -0,0 +1,200 @@
+package advisory
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/osv"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// osvDatabaseSpecific is "database_specific" in the GitHub Advisory Database format,
+// which is also commonly used by internal feeds to carry the severity.
+type osvDatabaseSpecific struct {
+	Severity string   `json:"severity"`
+	CweIDs   []string `json:"cwe_ids"`
+}
+
+// LoadOSV loads OSV JSON files under the directory
+// cf. https://ossf.github.io/osv-schema/
+func LoadOSV(dir string) (Source, error) {
+	s := newMemorySource(dbTypes.DataSource{
+		ID:   dbTypes.SourceID("osv:" + filepath.Base(dir)),
+		Name: "OSV feed " + dir,
+	})
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return xerrors.Errorf("file read error: %w", err)
+		}
+
+		var entry osv.Entry
+		if err = json.Unmarshal(b, &entry); err != nil {
+			return xerrors.Errorf("JSON decode error (%s): %w", path, err)
+		}
+		s.addOSVEntry(entry)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("walk error: %w", err)
+	}
+
+	s.summary()
+	return s, nil
+}
+
+func (s *memorySource) addOSVEntry(entry osv.Entry) {
+	if entry.Withdrawn != nil && entry.Withdrawn.Before(time.Now()) {
+		return
+	}
+
+	for _, affected := range entry.Affected {
+		ecosystem := osvEcosystem(affected.Package.Ecosystem)
+		if ecosystem == vulnerability.Unknown {
+			log.Logger.Debugf("Unsupported OSV ecosystem: %s", affected.Package.Ecosystem)
+			continue
+		}
+		pkgName := vulnerability.NormalizePkgName(ecosystem, affected.Package.Name)
+
+		vulnerableVersions, patchedVersions := osvAffectedVersions(affected)
+		s.add(ecosystem, pkgName, dbTypes.Advisory{
+			VulnerabilityID:    entry.ID,
+			VendorIDs:          entry.Aliases,
+			VulnerableVersions: vulnerableVersions,
+			PatchedVersions:    patchedVersions,
+		})
+	}
+
+	s.vulnerabilities[entry.ID] = osvVulnerability(entry, s.dataSource.ID)
+}
+
+// osvVulnerability converts the OSV entry into the vulnerability detail.
+// The severity and CVSS are stored as those of the given source.
+func osvVulnerability(entry osv.Entry, sourceID dbTypes.SourceID) dbTypes.Vulnerability {
+	var dbSpecific osvDatabaseSpecific
+	if len(entry.DatabaseSpecific) > 0 {
+		// The field is free-form, so the failure is not fatal
+		_ = json.Unmarshal(entry.DatabaseSpecific, &dbSpecific)
+	}
+
+	vuln := dbTypes.Vulnerability{
+		Title:       entry.Summary,
+		Description: entry.Details,
+		CweIDs:      dbSpecific.CweIDs,
+	}
+	for _, ref := range entry.References {
+		vuln.References = append(vuln.References, ref.URL)
+	}
+	for _, severity := range entry.Severities {
+		if severity.Type == "CVSS_V3" && severity.Score != "" {
+			vuln.CVSS = dbTypes.VendorCVSS{
+				sourceID: {V3Vector: strings.TrimSuffix(severity.Score, "/")},
+			}
+		}
+	}
+	if dbSpecific.Severity != "" {
+		vuln.VendorSeverity = dbTypes.VendorSeverity{
+			sourceID: parseSeverity(dbSpecific.Severity),
+		}
+	}
+	if !entry.Published.IsZero() {
+		vuln.PublishedDate = &entry.Published
+	}
+	if !entry.Modified.IsZero() {
+		vuln.LastModifiedDate = &entry.Modified
+	}
+	return vuln
+}
+
+// osvAffectedVersions converts affected[].ranges and affected[].versions into version constraints
+func osvAffectedVersions(affected osv.Affected) ([]string, []string) {
+	var vulnerableVersions, patchedVersions []string
+	var ranges []osv.VersionRange
+	for _, r := range affected.Ranges {
+		if r.Type == osv.RangeTypeGit {
+			continue
+		}
+
+		index := -1
+		for _, event := range r.Events {
+			switch {
+			case event.Introduced != "":
+				ranges = append(ranges, osv.NewVersionRange(affected.Package.Ecosystem, event.Introduced))
+				index = len(ranges) - 1
+			case event.Fixed != "" && index >= 0:
+				ranges[index].SetFixed(event.Fixed)
+				patchedVersions = append(patchedVersions, event.Fixed)
+			case event.LastAffected != "" && index >= 0:
+				ranges[index].SetLastAffected(event.LastAffected)
+			}
+		}
+	}
+
+	for _, r := range ranges {
+		vulnerableVersions = append(vulnerableVersions, r.String())
+	}
+	for _, v := range affected.Versions {
+		vulnerableVersions = append(vulnerableVersions, "="+v)
+	}
+	return vulnerableVersions, patchedVersions
+}
+
+// osvEcosystem converts the OSV ecosystem into the trivy-db ecosystem
+// cf. https://ossf.github.io/osv-schema/#affectedpackage-field
+func osvEcosystem(eco osv.Ecosystem) dbTypes.Ecosystem {
+	switch strings.ToLower(string(eco)) {
+	case "go":
+		return vulnerability.Go
+	case "npm":
+		return vulnerability.Npm
+	case "pypi":
+		return vulnerability.Pip
+	case "rubygems":
+		return vulnerability.RubyGems
+	case "crates.io":
+		return vulnerability.Cargo
+	case "packagist":
+		return vulnerability.Composer
+	case "maven":
+		return vulnerability.Maven
+	case "nuget":
+		return vulnerability.NuGet
+	case "hex":
+		return vulnerability.Erlang
+	case "pub":
+		return vulnerability.Pub
+	case "swifturl":
+		return vulnerability.Swift
+	case "bitnami":
+		return vulnerability.Bitnami
+	case "kubernetes":
+		return vulnerability.Kubernetes
+	default:
+		return vulnerability.Unknown
+	}
+}
+
+// parseSeverity accepts "MODERATE" used by GitHub as well as the Trivy severities
+func parseSeverity(severity string) dbTypes.Severity {
+	severity = strings.ToUpper(severity)
+	if severity == "MODERATE" {
+		return dbTypes.SeverityMedium
+	}
+	s, _ := dbTypes.NewSeverity(severity) // `SeverityUnknown` is returned in case of error
+	return s
+}
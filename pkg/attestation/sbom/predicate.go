@@ -0,0 +1,39 @@
+package sbom
+
+import (
+	"encoding/json"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"golang.org/x/xerrors"
+)
+
+// Predicate returns the CycloneDX or SPDX document in the in-toto statement of the SBOM attestation.
+// cosign wraps the document in the "Data" field of the predicate in older versions.
+func Predicate(statement []byte) ([]byte, error) {
+	var s struct {
+		PredicateType string          `json:"predicateType"`
+		Predicate     json.RawMessage `json:"predicate"`
+	}
+	if err := json.Unmarshal(statement, &s); err != nil {
+		return nil, xerrors.Errorf("attestation parse error: %w", err)
+	}
+	if s.PredicateType != in_toto.PredicateCycloneDX && s.PredicateType != in_toto.PredicateSPDX {
+		return nil, xerrors.Errorf("unsupported predicate type %s: %w", s.PredicateType, ErrNoSBOMAttestation)
+	}
+
+	var wrapped map[string]json.RawMessage
+	if err := json.Unmarshal(s.Predicate, &wrapped); err != nil {
+		return nil, xerrors.Errorf("predicate parse error: %w", err)
+	}
+	data, ok := wrapped["Data"]
+	if !ok {
+		return s.Predicate, nil
+	}
+
+	// SPDX tag-value documents are wrapped as strings
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		return []byte(str), nil
+	}
+	return data, nil
+}
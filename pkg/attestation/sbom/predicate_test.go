@@ -0,0 +1,51 @@
+package sbom_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/attestation/sbom"
+)
+
+func TestPredicate(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "CycloneDX",
+			statement: `{"predicateType":"https://cyclonedx.org/bom","predicate":{"bomFormat":"CycloneDX","specVersion":"1.5"}}`,
+			want:      `{"bomFormat":"CycloneDX","specVersion":"1.5"}`,
+		},
+		{
+			name:      "CycloneDX wrapped by cosign",
+			statement: `{"predicateType":"https://cyclonedx.org/bom","predicate":{"Data":{"bomFormat":"CycloneDX"},"Timestamp":"2024-01-01T00:00:00Z"}}`,
+			want:      `{"bomFormat":"CycloneDX"}`,
+		},
+		{
+			name:      "SPDX tag-value wrapped by cosign",
+			statement: `{"predicateType":"https://spdx.dev/Document","predicate":{"Data":"SPDXVersion: SPDX-2.3\n"}}`,
+			want:      "SPDXVersion: SPDX-2.3\n",
+		},
+		{
+			name:      "provenance",
+			statement: `{"predicateType":"https://slsa.dev/provenance/v1","predicate":{}}`,
+			wantErr:   sbom.ErrNoSBOMAttestation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sbom.Predicate([]byte(tt.statement))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/digest"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	manifestFile = "manifest.json"
+
+	binaryDir     = "bin"
+	cacheDir      = "cache"
+	templateDir   = "templates"
+	complianceDir = "compliance"
+)
+
+// cacheSubDirs are the directories in the cache directory to be bundled
+var cacheSubDirs = []string{
+	"db",
+	"java-db",
+	"policy",
+}
+
+// Manifest describes the files in the bundle
+type Manifest struct {
+	TrivyVersion string
+	CreatedAt    time.Time
+	Files        []File
+}
+
+type File struct {
+	Path   string
+	Digest digest.Digest
+	Size   int64
+}
+
+type CreateOption struct {
+	TrivyVersion    string
+	Binary          string
+	CacheDir        string
+	Templates       []string
+	ComplianceSpecs []string
+}
+
+type InstallOption struct {
+	CacheDir   string
+	InstallDir string
+}
+
+// entry maps a path in the bundle to the local file
+type entry struct {
+	path      string
+	localPath string
+	mode      fs.FileMode
+}
+
+// Create packages the binary, the databases and checks in the cache, templates and compliance specs into a tar.gz archive.
+// The archive starts with the manifest holding the digests of all the other files.
+func Create(output string, opt CreateOption) error {
+	entries, err := collect(opt)
+	if err != nil {
+		return xerrors.Errorf("unable to collect files: %w", err)
+	}
+
+	manifest := Manifest{
+		TrivyVersion: opt.TrivyVersion,
+		CreatedAt:    time.Now().UTC(),
+	}
+	for _, e := range entries {
+		f, err := newFile(e)
+		if err != nil {
+			return xerrors.Errorf("digest error: %w", err)
+		}
+		manifest.Files = append(manifest.Files, f)
+	}
+
+	out, err := os.Create(output)
+	if err != nil {
+		return xerrors.Errorf("unable to create %s: %w", output, err)
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("json marshal error: %w", err)
+	}
+	if err = writeEntry(tw, manifestFile, 0o644, int64(len(b)), bytes.NewReader(b)); err != nil {
+		return err
+	}
+
+	for i, e := range entries {
+		if err = addFile(tw, e, manifest.Files[i].Size); err != nil {
+			return xerrors.Errorf("unable to add %s: %w", e.localPath, err)
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return xerrors.Errorf("tar close error: %w", err)
+	}
+	if err = gw.Close(); err != nil {
+		return xerrors.Errorf("gzip close error: %w", err)
+	}
+	return nil
+}
+
+func collect(opt CreateOption) ([]entry, error) {
+	var entries []entry
+	if opt.Binary != "" {
+		entries = append(entries, entry{
+			path:      path.Join(binaryDir, "trivy"),
+			localPath: opt.Binary,
+			mode:      0o755,
+		})
+	}
+
+	for _, dir := range cacheSubDirs {
+		root := filepath.Join(opt.CacheDir, dir)
+		if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+			log.Logger.Warnf("%s not found in the cache, skipping", dir)
+			continue
+		}
+		err := filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			} else if !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(opt.CacheDir, filePath)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{
+				path:      path.Join(cacheDir, filepath.ToSlash(rel)),
+				localPath: filePath,
+				mode:      0o644,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("walk error: %w", err)
+		}
+	}
+
+	for _, f := range opt.Templates {
+		entries = append(entries, entry{
+			path:      path.Join(templateDir, filepath.Base(f)),
+			localPath: f,
+			mode:      0o644,
+		})
+	}
+	for _, f := range opt.ComplianceSpecs {
+		entries = append(entries, entry{
+			path:      path.Join(complianceDir, filepath.Base(f)),
+			localPath: f,
+			mode:      0o644,
+		})
+	}
+	return entries, nil
+}
+
+func newFile(e entry) (File, error) {
+	f, err := os.Open(e.localPath)
+	if err != nil {
+		return File{}, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return File{}, xerrors.Errorf("stat error: %w", err)
+	}
+	d, err := digest.CalcSHA256(f)
+	if err != nil {
+		return File{}, err
+	}
+	return File{
+		Path:   e.path,
+		Digest: d,
+		Size:   fi.Size(),
+	}, nil
+}
+
+func addFile(tw *tar.Writer, e entry, size int64) error {
+	f, err := os.Open(e.localPath)
+	if err != nil {
+		return xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+	return writeEntry(tw, e.path, e.mode, size, f)
+}
+
+func writeEntry(tw *tar.Writer, name string, mode fs.FileMode, size int64, r io.Reader) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    int64(mode),
+		Size:    size,
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return xerrors.Errorf("tar header error: %w", err)
+	}
+	if _, err := io.CopyN(tw, r, size); err != nil {
+		return xerrors.Errorf("tar write error: %w", err)
+	}
+	return nil
+}
+
+// Install verifies the bundle and lays it out.
+// The databases and checks go to the cache directory, and the others go to the install directory.
+// Nothing is installed unless all the files match the manifest.
+func Install(bundlePath string, opt InstallOption) (*Manifest, error) {
+	stagingDir, err := os.MkdirTemp("", "trivy-bundle-*")
+	if err != nil {
+		return nil, xerrors.Errorf("unable to create a temp dir: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	manifest, err := extract(bundlePath, stagingDir)
+	if err != nil {
+		return nil, xerrors.Errorf("bundle verification failed: %w", err)
+	}
+
+	// Replace the directories in the cache so that stale files don't remain
+	for _, dir := range cacheSubDirs {
+		src := filepath.Join(stagingDir, cacheDir, dir)
+		if _, err = os.Stat(src); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err = os.RemoveAll(filepath.Join(opt.CacheDir, dir)); err != nil {
+			return nil, xerrors.Errorf("unable to remove %s in the cache: %w", dir, err)
+		}
+	}
+
+	for _, f := range manifest.Files {
+		dst := filepath.Join(opt.InstallDir, filepath.FromSlash(f.Path))
+		if rel, ok := strings.CutPrefix(f.Path, cacheDir+"/"); ok {
+			dst = filepath.Join(opt.CacheDir, filepath.FromSlash(rel))
+		}
+		if err = moveFile(filepath.Join(stagingDir, filepath.FromSlash(f.Path)), dst); err != nil {
+			return nil, xerrors.Errorf("unable to install %s: %w", f.Path, err)
+		}
+	}
+	return manifest, nil
+}
+
+// extract extracts the bundle into the directory and verifies the files against the manifest.
+func extract(bundlePath, dir string) (*Manifest, error) {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, xerrors.Errorf("gzip error: %w", err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, xerrors.Errorf("tar error: %w", err)
+	} else if hdr.Name != manifestFile {
+		return nil, xerrors.Errorf("%s must be the first file in the bundle", manifestFile)
+	}
+
+	var manifest Manifest
+	if err = json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, xerrors.Errorf("manifest decode error: %w", err)
+	}
+
+	extracted := make(map[string]bool)
+	for {
+		hdr, err = tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, xerrors.Errorf("tar error: %w", err)
+		}
+
+		if !filepath.IsLocal(hdr.Name) {
+			return nil, xerrors.Errorf("invalid file path: %s", hdr.Name)
+		}
+		idx := slices.IndexFunc(manifest.Files, func(f File) bool {
+			return f.Path == hdr.Name
+		})
+		if idx < 0 {
+			return nil, xerrors.Errorf("%s is not listed in the manifest", hdr.Name)
+		}
+
+		if err = extractFile(tr, filepath.Join(dir, filepath.FromSlash(hdr.Name)), fs.FileMode(hdr.Mode),
+			manifest.Files[idx].Digest); err != nil {
+			return nil, xerrors.Errorf("%s: %w", hdr.Name, err)
+		}
+		extracted[hdr.Name] = true
+	}
+
+	for _, f := range manifest.Files {
+		if !extracted[f.Path] {
+			return nil, xerrors.Errorf("%s is missing in the bundle", f.Path)
+		}
+	}
+	return &manifest, nil
+}
+
+func extractFile(r io.Reader, dst string, mode fs.FileMode, want digest.Digest) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return xerrors.Errorf("mkdir error: %w", err)
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return xerrors.Errorf("file create error: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(f, h), r); err != nil {
+		return xerrors.Errorf("copy error: %w", err)
+	}
+	if got := digest.NewDigest(digest.SHA256, h); got != want {
+		return xerrors.Errorf("digest mismatch, want: %s, got: %s", want, got)
+	}
+	return nil
+}
+
+func moveFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return xerrors.Errorf("mkdir error: %w", err)
+	}
+	// The staging directory can be on another file system
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	fi, err := os.Stat(src)
+	if err != nil {
+		return xerrors.Errorf("stat error: %w", err)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return xerrors.Errorf("file open error: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fi.Mode().Perm())
+	if err != nil {
+		return xerrors.Errorf("file create error: %w", err)
+	}
+	defer out.Close()
+
+	if _, err = io.Copy(out, in); err != nil {
+		return xerrors.Errorf("copy error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func setUp(t *testing.T) (string, CreateOption) {
+	src := t.TempDir()
+	cacheDir := filepath.Join(src, "cache")
+	writeFile(t, filepath.Join(cacheDir, "db", "trivy.db"), "vuln db")
+	writeFile(t, filepath.Join(cacheDir, "db", "metadata.json"), `{"Version":2}`)
+	writeFile(t, filepath.Join(cacheDir, "policy", "content", "policies", "check.rego"), "package builtin")
+	writeFile(t, filepath.Join(cacheDir, "fanal", "fanal.db"), "not bundled")
+	writeFile(t, filepath.Join(src, "trivy"), "binary")
+	writeFile(t, filepath.Join(src, "html.tpl"), "template")
+	writeFile(t, filepath.Join(src, "my-spec.yaml"), "spec")
+
+	output := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	return output, CreateOption{
+		TrivyVersion:    "0.50.0",
+		Binary:          filepath.Join(src, "trivy"),
+		CacheDir:        cacheDir,
+		Templates:       []string{filepath.Join(src, "html.tpl")},
+		ComplianceSpecs: []string{filepath.Join(src, "my-spec.yaml")},
+	}
+}
+
+func TestCreateAndInstall(t *testing.T) {
+	output, opt := setUp(t)
+	require.NoError(t, Create(output, opt))
+
+	dst := t.TempDir()
+	cacheDir := filepath.Join(dst, "cache")
+	installDir := filepath.Join(dst, "trivy")
+
+	// Stale files should be removed
+	writeFile(t, filepath.Join(cacheDir, "policy", "content", "policies", "stale.rego"), "package stale")
+
+	manifest, err := Install(output, InstallOption{
+		CacheDir:   cacheDir,
+		InstallDir: installDir,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "0.50.0", manifest.TrivyVersion)
+
+	want := map[string]string{
+		filepath.Join(cacheDir, "db", "trivy.db"):                              "vuln db",
+		filepath.Join(cacheDir, "db", "metadata.json"):                         `{"Version":2}`,
+		filepath.Join(cacheDir, "policy", "content", "policies", "check.rego"): "package builtin",
+		filepath.Join(installDir, "bin", "trivy"):                              "binary",
+		filepath.Join(installDir, "templates", "html.tpl"):                     "template",
+		filepath.Join(installDir, "compliance", "my-spec.yaml"):                "spec",
+	}
+	for path, content := range want {
+		got, err := os.ReadFile(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, content, string(got), path)
+	}
+
+	fi, err := os.Stat(filepath.Join(installDir, "bin", "trivy"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
+
+	assert.NoFileExists(t, filepath.Join(cacheDir, "policy", "content", "policies", "stale.rego"))
+	assert.NoFileExists(t, filepath.Join(cacheDir, "fanal", "fanal.db"))
+}
+
+// tamper rewrites the bundle, replacing the content of the file
+func tamper(t *testing.T, bundlePath, name, content string) {
+	f, err := os.Open(bundlePath)
+	require.NoError(t, err)
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	tampered := bundlePath + ".tampered"
+	out, err := os.Create(tampered)
+	require.NoError(t, err)
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		if hdr.Name == name {
+			b = []byte(content)
+			hdr.Size = int64(len(b))
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err = tw.Write(b)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	require.NoError(t, out.Close())
+	require.NoError(t, f.Close())
+	require.NoError(t, os.Rename(tampered, bundlePath))
+}
+
+func TestInstall_Tampered(t *testing.T) {
+	output, opt := setUp(t)
+	require.NoError(t, Create(output, opt))
+	tamper(t, output, "cache/db/trivy.db", "malicious")
+
+	cacheDir := t.TempDir()
+	writeFile(t, filepath.Join(cacheDir, "db", "trivy.db"), "current")
+
+	_, err := Install(output, InstallOption{
+		CacheDir:   cacheDir,
+		InstallDir: t.TempDir(),
+	})
+	require.ErrorContains(t, err, "digest mismatch")
+
+	// Nothing should be installed
+	got, err := os.ReadFile(filepath.Join(cacheDir, "db", "trivy.db"))
+	require.NoError(t, err)
+	assert.Equal(t, "current", string(got))
+}
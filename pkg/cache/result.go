@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const resultDirName = "result"
+
+// ResultCache stores scan results on the local filesystem so that re-scanning an unchanged artifact returns immediately.
+// Cached results are invalidated when the TTL expires or the vulnerability DB is updated.
+type ResultCache struct {
+	dir         string
+	ttl         time.Duration
+	dbUpdatedAt time.Time
+}
+
+type resultEntry struct {
+	DBUpdatedAt time.Time
+	CreatedAt   time.Time
+	Report      types.Report
+}
+
+// NewResultCache returns the result cache in the cache directory.
+// The TTL of zero means results are kept until the vulnerability DB is updated.
+func NewResultCache(cacheDir string, ttl time.Duration, dbUpdatedAt time.Time) (ResultCache, error) {
+	dir := filepath.Join(cacheDir, resultDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return ResultCache{}, xerrors.Errorf("failed to create the result cache dir: %w", err)
+	}
+	return ResultCache{
+		dir:         dir,
+		ttl:         ttl,
+		dbUpdatedAt: dbUpdatedAt.UTC(),
+	}, nil
+}
+
+// ClearResults removes all the cached results
+func ClearResults(cacheDir string) error {
+	if err := os.RemoveAll(filepath.Join(cacheDir, resultDirName)); err != nil {
+		return xerrors.Errorf("failed to remove the result cache: %w", err)
+	}
+	return nil
+}
+
+// Get returns the cached result. Stale results are removed.
+func (c ResultCache) Get(ctx context.Context, key string) (types.Report, bool) {
+	filePath := c.path(key)
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return types.Report{}, false
+	}
+
+	var entry resultEntry
+	if err = json.Unmarshal(b, &entry); err != nil {
+		log.Logger.Debugf("Invalid result cache (%s): %s", filePath, err)
+		c.remove(filePath)
+		return types.Report{}, false
+	}
+
+	switch {
+	case !entry.DBUpdatedAt.Equal(c.dbUpdatedAt):
+		log.Logger.Debug("The cached result is stale as the vulnerability DB has been updated")
+	case c.ttl > 0 && clock.Now(ctx).After(entry.CreatedAt.Add(c.ttl)):
+		log.Logger.Debug("The cached result has expired")
+	default:
+		return entry.Report, true
+	}
+	c.remove(filePath)
+	return types.Report{}, false
+}
+
+// Put stores the result
+func (c ResultCache) Put(ctx context.Context, key string, report types.Report) error {
+	b, err := json.Marshal(resultEntry{
+		DBUpdatedAt: c.dbUpdatedAt,
+		CreatedAt:   clock.Now(ctx).UTC(),
+		Report:      report,
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to marshal the result: %w", err)
+	}
+	if err = os.WriteFile(c.path(key), b, 0600); err != nil {
+		return xerrors.Errorf("failed to write the result cache: %w", err)
+	}
+	return nil
+}
+
+func (c ResultCache) path(key string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(key))))
+}
+
+func (c ResultCache) remove(filePath string) {
+	if err := os.Remove(filePath); err != nil {
+		log.Logger.Debugf("Unable to remove the result cache (%s): %s", filePath, err)
+	}
+}
@@ -0,0 +1,106 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/cache"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestResultCache(t *testing.T) {
+	dbUpdatedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := types.Report{
+		ArtifactName: "alpine:3.19",
+		Results: types.Results{
+			{Target: "alpine:3.19 (alpine 3.19.1)"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		ttl         time.Duration
+		dbUpdatedAt time.Time
+		now         time.Time
+		key         string
+		wantHit     bool
+	}{
+		{
+			name:        "hit",
+			ttl:         24 * time.Hour,
+			dbUpdatedAt: dbUpdatedAt,
+			now:         createdAt.Add(time.Hour),
+			key:         "sha256:alpine",
+			wantHit:     true,
+		},
+		{
+			name:        "no TTL",
+			dbUpdatedAt: dbUpdatedAt,
+			now:         createdAt.Add(24 * 365 * time.Hour),
+			key:         "sha256:alpine",
+			wantHit:     true,
+		},
+		{
+			name:        "different key",
+			dbUpdatedAt: dbUpdatedAt,
+			now:         createdAt,
+			key:         "sha256:debian",
+		},
+		{
+			name:        "expired",
+			ttl:         time.Hour,
+			dbUpdatedAt: dbUpdatedAt,
+			now:         createdAt.Add(2 * time.Hour),
+			key:         "sha256:alpine",
+		},
+		{
+			name:        "DB updated",
+			dbUpdatedAt: dbUpdatedAt.Add(6 * time.Hour),
+			now:         createdAt,
+			key:         "sha256:alpine",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+
+			c, err := cache.NewResultCache(cacheDir, tt.ttl, dbUpdatedAt)
+			require.NoError(t, err)
+			err = c.Put(clock.With(context.Background(), createdAt), "sha256:alpine", report)
+			require.NoError(t, err)
+
+			c, err = cache.NewResultCache(cacheDir, tt.ttl, tt.dbUpdatedAt)
+			require.NoError(t, err)
+
+			ctx := clock.With(context.Background(), tt.now)
+			got, ok := c.Get(ctx, tt.key)
+			require.Equal(t, tt.wantHit, ok)
+			if !tt.wantHit {
+				// Stale results are removed
+				_, ok = c.Get(clock.With(context.Background(), createdAt), "sha256:alpine")
+				assert.Equal(t, tt.key != "sha256:alpine", ok)
+				return
+			}
+			assert.Equal(t, report, got)
+		})
+	}
+}
+
+func TestClearResults(t *testing.T) {
+	cacheDir := t.TempDir()
+	ctx := context.Background()
+
+	c, err := cache.NewResultCache(cacheDir, 0, time.Time{})
+	require.NoError(t, err)
+	require.NoError(t, c.Put(ctx, "sha256:alpine", types.Report{ArtifactName: "alpine:3.19"}))
+
+	require.NoError(t, cache.ClearResults(cacheDir))
+	_, ok := c.Get(ctx, "sha256:alpine")
+	assert.False(t, ok)
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/parallel"
+	"github.com/aquasecurity/trivy/pkg/remote"
+)
+
+// Filter selects the repositories and the tags in the catalog of a registry.
+// The patterns are matched with path.Match.
+type Filter struct {
+	Repositories        []string
+	ExcludeRepositories []string
+	Tags                []string
+	ExcludeTags         []string
+
+	// MaxAge filters out the images created before the duration if not zero
+	MaxAge time.Duration
+}
+
+// Images lists the images in the catalog of the registry matching the filter, e.g. "registry.example.com/app:1.0".
+// The tags of the repositories are listed in parallel.
+func Images(ctx context.Context, host string, filter Filter, option types.RegistryOptions, numWorkers int) ([]string, error) {
+	var nameOpts []name.Option
+	if option.Insecure {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+	reg, err := name.NewRegistry(host, nameOpts...)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid registry %q: %w", host, err)
+	}
+
+	repos, err := remote.Catalog(ctx, reg, option)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to list the repositories in %s: %w", host, err)
+	}
+	repos = slices.DeleteFunc(repos, func(repo string) bool {
+		return !match(repo, filter.Repositories, filter.ExcludeRepositories)
+	})
+	log.Logger.Infof("%d repositories found in %s", len(repos), host)
+
+	var images []string
+	onItem := func(ctx context.Context, repo string) ([]string, error) {
+		return listImages(ctx, reg.Repo(repo), filter, option)
+	}
+	onResult := func(refs []string) error {
+		images = append(images, refs...)
+		return nil
+	}
+	p := parallel.NewPipeline(numWorkers, false, repos, onItem, onResult)
+	if err = p.Do(ctx); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(images)
+	return images, nil
+}
+
+func listImages(ctx context.Context, repo name.Repository, filter Filter, option types.RegistryOptions) ([]string, error) {
+	tags, err := remote.List(ctx, repo, option)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to list the tags of %s: %w", repo, err)
+	}
+
+	var images []string
+	for _, tag := range tags {
+		if !match(tag, filter.Tags, filter.ExcludeTags) {
+			continue
+		}
+		ref := repo.Tag(tag)
+		if filter.MaxAge > 0 {
+			created, err := createdAt(ctx, ref, option)
+			if err != nil {
+				return nil, xerrors.Errorf("unable to get the creation time of %s: %w", ref, err)
+			}
+			if time.Since(created) > filter.MaxAge {
+				log.Logger.Debugf("Skipping %s created at %s", ref, created)
+				continue
+			}
+		}
+		images = append(images, ref.String())
+	}
+	return images, nil
+}
+
+func createdAt(ctx context.Context, ref name.Reference, option types.RegistryOptions) (time.Time, error) {
+	img, err := remote.Image(ctx, ref, option)
+	if err != nil {
+		return time.Time{}, err
+	}
+	config, err := img.ConfigFile()
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("config error: %w", err)
+	}
+	return config.Created.Time, nil
+}
+
+// match returns true if the value matches one of the patterns, or no patterns are given, and none of the excludes
+func match(value string, patterns, excludes []string) bool {
+	for _, exclude := range excludes {
+		if ok, _ := path.Match(exclude, value); ok {
+			return false
+		}
+	}
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
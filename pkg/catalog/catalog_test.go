@@ -0,0 +1,150 @@
+package catalog_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/catalog"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func pushImage(t *testing.T, ref string, created time.Time) {
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	img, err = mutate.CreatedAt(img, v1.Time{Time: created})
+	require.NoError(t, err)
+
+	r, err := name.ParseReference(ref, name.Insecure)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(r, img))
+}
+
+func TestImages(t *testing.T) {
+	ts := httptest.NewServer(registry.New())
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	now := time.Now()
+	pushImage(t, host+"/team-a/app:v1.0", now.Add(-24*time.Hour))
+	pushImage(t, host+"/team-a/app:v0.9", now.Add(-90*24*time.Hour))
+	pushImage(t, host+"/team-a/app:v1.0-dev", now)
+	pushImage(t, host+"/team-a/cache:latest", now)
+	pushImage(t, host+"/team-b/api:2.0", now)
+
+	tests := []struct {
+		name   string
+		filter catalog.Filter
+		want   []string
+	}{
+		{
+			name: "all images",
+			want: []string{
+				host + "/team-a/app:v0.9",
+				host + "/team-a/app:v1.0",
+				host + "/team-a/app:v1.0-dev",
+				host + "/team-a/cache:latest",
+				host + "/team-b/api:2.0",
+			},
+		},
+		{
+			name: "repository patterns",
+			filter: catalog.Filter{
+				Repositories:        []string{"team-a/*"},
+				ExcludeRepositories: []string{"*/cache"},
+			},
+			want: []string{
+				host + "/team-a/app:v0.9",
+				host + "/team-a/app:v1.0",
+				host + "/team-a/app:v1.0-dev",
+			},
+		},
+		{
+			name: "tag patterns",
+			filter: catalog.Filter{
+				Tags:        []string{"v*"},
+				ExcludeTags: []string{"*-dev"},
+			},
+			want: []string{
+				host + "/team-a/app:v0.9",
+				host + "/team-a/app:v1.0",
+			},
+		},
+		{
+			name: "max age",
+			filter: catalog.Filter{
+				Repositories: []string{"team-a/app"},
+				MaxAge:       30 * 24 * time.Hour,
+			},
+			want: []string{
+				host + "/team-a/app:v1.0",
+				host + "/team-a/app:v1.0-dev",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := catalog.Images(context.Background(), host, tt.filter, ftypes.RegistryOptions{Insecure: true}, 2)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	report := catalog.Report{
+		Registry: "registry.example.com",
+		Images: []types.Report{
+			{
+				ArtifactName: "registry.example.com/app:1.0",
+				Results: types.Results{
+					{
+						Target: "registry.example.com/app:1.0 (alpine 3.19.1)",
+						Vulnerabilities: []types.DetectedVulnerability{
+							{VulnerabilityID: "CVE-2024-0001", Vulnerability: dbTypes.Vulnerability{Severity: "CRITICAL"}},
+							{VulnerabilityID: "CVE-2024-0002", Vulnerability: dbTypes.Vulnerability{Severity: "LOW"}},
+						},
+						Secrets: []types.DetectedSecret{
+							{RuleID: "aws-access-key-id"},
+						},
+					},
+				},
+			},
+			{
+				ArtifactName: "registry.example.com/private:1.0",
+				Metadata: types.Metadata{
+					Error: &types.Error{Code: types.ErrorCodeAuthFailure},
+				},
+			},
+		},
+	}
+	assert.True(t, report.Failed())
+	assert.False(t, catalog.Report{Images: []types.Report{{ArtifactName: "registry.example.com/app:1.0"}}}.Failed())
+
+	var buf bytes.Buffer
+	require.NoError(t, catalog.Write(&buf, report, types.FormatTable))
+	assert.Contains(t, buf.String(), "registry.example.com (2 images)")
+	assert.Regexp(t, `registry\.example\.com/app:1\.0\s+│\s+1\s+│\s+0\s+│\s+0\s+│\s+1\s+│\s+0\s+│\s+0\s+│\s+1\s+│`, buf.String())
+	assert.Regexp(t, `registry\.example\.com/private:1\.0\s+│\s+-.*AUTH_FAILURE`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, catalog.Write(&buf, report, types.FormatJSON))
+	assert.Contains(t, buf.String(), `"Registry": "registry.example.com"`)
+	assert.Contains(t, buf.String(), `"Code": "AUTH_FAILURE"`)
+
+	assert.Error(t, catalog.Write(&buf, report, types.FormatSarif))
+}
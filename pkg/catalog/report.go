@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/table"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Report is the consolidated report of the images in a registry
+type Report struct {
+	SchemaVersion int `json:",omitempty"`
+	Registry      string
+	Images        []types.Report
+}
+
+// Failed returns true if one of the images has findings or failed to be scanned
+func (r Report) Failed() bool {
+	for _, image := range r.Images {
+		if image.Metadata.Error != nil || image.Results.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the report in the format
+func Write(w io.Writer, report Report, format types.Format) error {
+	switch format {
+	case types.FormatJSON:
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return xerrors.Errorf("json marshal error: %w", err)
+		}
+		if _, err = fmt.Fprintln(w, string(b)); err != nil {
+			return xerrors.Errorf("failed to write json: %w", err)
+		}
+	case types.FormatTable:
+		writeTable(w, report)
+	default:
+		return xerrors.Errorf("unsupported format for registry scanning: %s", format)
+	}
+	return nil
+}
+
+// writeTable writes the summary of the findings per image
+func writeTable(w io.Writer, report Report) {
+	title := fmt.Sprintf("%s (%d images)", report.Registry, len(report.Images))
+	fmt.Fprintf(w, "\n%s\n%s\n", title, strings.Repeat("=", len(title)))
+	if len(report.Images) == 0 {
+		return
+	}
+
+	t := table.New(w)
+	t.SetBorders(true)
+	t.SetRowLines(true)
+	t.SetHeaders("Image", "Critical", "High", "Medium", "Low", "Unknown", "Misconfigurations", "Secrets", "Error")
+
+	for _, image := range report.Images {
+		if image.Metadata.Error != nil {
+			t.AddRow(image.ArtifactName, "-", "-", "-", "-", "-", "-", "-", string(image.Metadata.Error.Code))
+			continue
+		}
+
+		severities := make(map[string]int)
+		var misconfs, secrets int
+		for _, result := range image.Results {
+			for _, vuln := range result.Vulnerabilities {
+				severities[vuln.Severity]++
+			}
+			for _, misconf := range result.Misconfigurations {
+				if misconf.Status != types.MisconfStatusPassed {
+					misconfs++
+				}
+			}
+			secrets += len(result.Secrets)
+		}
+		t.AddRow(image.ArtifactName,
+			strconv.Itoa(severities[dbTypes.SeverityCritical.String()]),
+			strconv.Itoa(severities[dbTypes.SeverityHigh.String()]),
+			strconv.Itoa(severities[dbTypes.SeverityMedium.String()]),
+			strconv.Itoa(severities[dbTypes.SeverityLow.String()]),
+			strconv.Itoa(severities[dbTypes.SeverityUnknown.String()]),
+			strconv.Itoa(misconfs),
+			strconv.Itoa(secrets),
+			"",
+		)
+	}
+	t.Render()
+}
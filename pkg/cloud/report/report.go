@@ -15,6 +15,7 @@ import (
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/flag"
 	"github.com/aquasecurity/trivy/pkg/iac/scan"
+	"github.com/aquasecurity/trivy/pkg/log"
 	pkgReport "github.com/aquasecurity/trivy/pkg/report"
 	"github.com/aquasecurity/trivy/pkg/result"
 	"github.com/aquasecurity/trivy/pkg/types"
@@ -60,6 +61,29 @@ func (r *Report) Failed() bool {
 
 // Write writes the results in the give format
 func Write(ctx context.Context, rep *Report, opt flag.Options, fromCache bool) error {
+	if len(opt.ExtraOutputs) > 0 {
+		log.Logger.Warnf("Only '--format %s' is written as multiple formats are not supported in cloud scanning", opt.Format)
+		opt.ExtraOutputs = nil
+	}
+	if len(opt.ReportHooks) > 0 {
+		log.Logger.Warn("'--report-hook' is ignored as report hooks are not supported in cloud scanning")
+		opt.ReportHooks = nil
+	}
+	// With '--summary', the full report goes only to '--output' and stdout gets the summary
+	if opt.Summary {
+		if opt.Output != "" {
+			opt.Summary = false
+			if err := Write(ctx, rep, opt, fromCache); err != nil {
+				return err
+			}
+		}
+		filtered, err := filterResults(ctx, rep, opt)
+		if err != nil {
+			return err
+		}
+		return pkgReport.WriteSummary(ctx, filtered, opt)
+	}
+
 	output, cleanup, err := opt.OutputWriter(ctx)
 	if err != nil {
 		return xerrors.Errorf("failed to create output file: %w", err)
@@ -70,27 +94,10 @@ func Write(ctx context.Context, rep *Report, opt flag.Options, fromCache bool) e
 		return writeCompliance(ctx, rep, opt, output)
 	}
 
-	var filtered []types.Result
-
-	// filter results
-	for _, resultsAtTime := range rep.Results {
-		for _, res := range resultsAtTime.Results {
-			resCopy := res
-			if err := result.FilterResult(ctx, &resCopy, result.IgnoreConfig{}, result.FilterOption{
-				Severities:         opt.Severities,
-				IncludeNonFailures: opt.IncludeNonFailures,
-			}); err != nil {
-				return err
-			}
-			sort.Slice(resCopy.Misconfigurations, func(i, j int) bool {
-				return resCopy.Misconfigurations[i].CauseMetadata.Resource < resCopy.Misconfigurations[j].CauseMetadata.Resource
-			})
-			filtered = append(filtered, resCopy)
-		}
+	filtered, err := filterResults(ctx, rep, opt)
+	if err != nil {
+		return err
 	}
-	sort.Slice(filtered, func(i, j int) bool {
-		return filtered[i].Target < filtered[j].Target
-	})
 
 	base := types.Report{
 		CreatedAt:    clock.Now(ctx),
@@ -139,6 +146,29 @@ func Write(ctx context.Context, rep *Report, opt flag.Options, fromCache bool) e
 	}
 }
 
+func filterResults(ctx context.Context, rep *Report, opt flag.Options) (types.Results, error) {
+	var filtered types.Results
+	for _, resultsAtTime := range rep.Results {
+		for _, res := range resultsAtTime.Results {
+			resCopy := res
+			if err := result.FilterResult(ctx, &resCopy, result.IgnoreConfig{}, result.FilterOption{
+				Severities:         opt.Severities,
+				IncludeNonFailures: opt.IncludeNonFailures,
+			}); err != nil {
+				return nil, err
+			}
+			sort.Slice(resCopy.Misconfigurations, func(i, j int) bool {
+				return resCopy.Misconfigurations[i].CauseMetadata.Resource < resCopy.Misconfigurations[j].CauseMetadata.Resource
+			})
+			filtered = append(filtered, resCopy)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].Target < filtered[j].Target
+	})
+	return filtered, nil
+}
+
 func writeCompliance(ctx context.Context, rep *Report, opt flag.Options, output io.Writer) error {
 	var crr []types.Results
 	for _, r := range rep.Results {
@@ -17,7 +17,13 @@ import (
 	awsScanner "github.com/aquasecurity/trivy-aws/pkg/scanner"
 	awscommands "github.com/aquasecurity/trivy/pkg/cloud/aws/commands"
 	"github.com/aquasecurity/trivy/pkg/commands/artifact"
+	bundlecommands "github.com/aquasecurity/trivy/pkg/commands/bundle"
+	"github.com/aquasecurity/trivy/pkg/commands/check"
+	"github.com/aquasecurity/trivy/pkg/commands/compare"
 	"github.com/aquasecurity/trivy/pkg/commands/convert"
+	"github.com/aquasecurity/trivy/pkg/commands/dev"
+	policycommands "github.com/aquasecurity/trivy/pkg/commands/policy"
+	registrycommands "github.com/aquasecurity/trivy/pkg/commands/registry"
 	"github.com/aquasecurity/trivy/pkg/commands/server"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
 	"github.com/aquasecurity/trivy/pkg/flag"
@@ -83,6 +89,7 @@ func NewApp() *cobra.Command {
 	rootCmd.SetHelpCommandGroupID(groupUtility)
 	rootCmd.AddCommand(
 		NewImageCommand(globalFlags),
+		NewRegistryCommand(globalFlags),
 		NewFilesystemCommand(globalFlags),
 		NewRootfsCommand(globalFlags),
 		NewRepositoryCommand(globalFlags),
@@ -90,13 +97,19 @@ func NewApp() *cobra.Command {
 		NewServerCommand(globalFlags),
 		NewConfigCommand(globalFlags),
 		NewConvertCommand(globalFlags),
-		NewPluginCommand(),
+		NewCompareCommand(globalFlags),
+		NewCheckCommand(globalFlags),
+		NewDevCommand(globalFlags),
+		NewPluginCommand(globalFlags),
 		NewModuleCommand(globalFlags),
+		NewPolicyCommand(globalFlags),
+		NewBundleCommand(globalFlags),
 		NewKubernetesCommand(globalFlags),
 		NewSBOMCommand(globalFlags),
 		NewVersionCommand(globalFlags),
 		NewAWSCommand(globalFlags),
 		NewVMCommand(globalFlags),
+		NewLambdaCommand(globalFlags),
 	)
 
 	if plugins := loadPluginCommands(); len(plugins) > 0 {
@@ -227,7 +240,8 @@ func NewRootCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 
 func NewImageCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	scanFlagGroup := flag.NewScanFlagGroup()
-	scanFlagGroup.IncludeDevDeps = nil // disable '--include-dev-deps'
+	scanFlagGroup.IncludeDevDeps = nil             // disable '--include-dev-deps'
+	scanFlagGroup.Budget = flag.BudgetFlag.Clone() // enable '--budget'
 
 	reportFlagGroup := flag.NewReportFlagGroup()
 	report := flag.ReportFormatFlag.Clone()
@@ -243,20 +257,25 @@ func NewImageCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	misconfFlagGroup.CloudformationParamVars = nil // disable '--cf-params'
 	misconfFlagGroup.TerraformTFVars = nil         // disable '--tf-vars'
 
+	cacheFlagGroup := flag.NewCacheFlagGroup()
+	cacheFlagGroup.ResultCache = flag.ResultCacheFlag.Clone() // enable '--result-cache'
+
 	imageFlags := &flag.Flags{
 		GlobalFlagGroup:        globalFlags,
-		CacheFlagGroup:         flag.NewCacheFlagGroup(),
+		CacheFlagGroup:         cacheFlagGroup,
 		DBFlagGroup:            flag.NewDBFlagGroup(),
 		ImageFlagGroup:         flag.NewImageFlagGroup(), // container image specific
 		LicenseFlagGroup:       flag.NewLicenseFlagGroup(),
 		MisconfFlagGroup:       misconfFlagGroup,
 		ModuleFlagGroup:        flag.NewModuleFlagGroup(),
+		PublishFlagGroup:       flag.NewPublishFlagGroup(),
 		RemoteFlagGroup:        flag.NewClientFlags(), // for client/server mode
 		RegistryFlagGroup:      flag.NewRegistryFlagGroup(),
 		RegoFlagGroup:          flag.NewRegoFlagGroup(),
 		ReportFlagGroup:        reportFlagGroup,
 		ScanFlagGroup:          scanFlagGroup,
 		SecretFlagGroup:        flag.NewSecretFlagGroup(),
+		SignatureFlagGroup:     flag.NewSignatureFlagGroup(),
 		VulnerabilityFlagGroup: flag.NewVulnerabilityFlagGroup(),
 	}
 
@@ -277,6 +296,9 @@ func NewImageCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
   # Ignore unfixed/unpatched vulnerabilities
   $ trivy image --ignore-unfixed alpine:3.15
 
+  # Report only vulnerabilities introduced on top of the base image
+  $ trivy image --compare alpine:3.19 myorg/app:1.0
+
   # Scan a container image in client mode
   $ trivy image --server http://127.0.0.1:4954 alpine:latest
 
@@ -317,6 +339,92 @@ func NewImageCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	return cmd
 }
 
+func NewRegistryCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
+	formatFlag := flag.FormatFlag.Clone()
+	formatFlag.Values = xstrings.ToStringSlice([]types.Format{
+		types.FormatTable,
+		types.FormatJSON,
+	})
+
+	scanFlagGroup := flag.NewScanFlagGroup()
+	scanFlagGroup.IncludeDevDeps = nil // disable '--include-dev-deps'
+
+	misconfFlagGroup := flag.NewMisconfFlagGroup()
+	misconfFlagGroup.CloudformationParamVars = nil // disable '--cf-params'
+	misconfFlagGroup.TerraformTFVars = nil         // disable '--tf-vars'
+
+	registryFlags := &flag.Flags{
+		GlobalFlagGroup:   globalFlags,
+		CacheFlagGroup:    flag.NewCacheFlagGroup(),
+		CatalogFlagGroup:  flag.NewCatalogFlagGroup(),
+		DBFlagGroup:       flag.NewDBFlagGroup(),
+		ImageFlagGroup:    &flag.ImageFlagGroup{Platform: flag.PlatformFlag.Clone()},
+		LicenseFlagGroup:  flag.NewLicenseFlagGroup(),
+		MisconfFlagGroup:  misconfFlagGroup,
+		ModuleFlagGroup:   flag.NewModuleFlagGroup(),
+		RemoteFlagGroup:   flag.NewClientFlags(), // for client/server mode
+		RegistryFlagGroup: flag.NewRegistryFlagGroup(),
+		RegoFlagGroup:     flag.NewRegoFlagGroup(),
+		ReportFlagGroup: &flag.ReportFlagGroup{
+			Format:         formatFlag,
+			ListAllPkgs:    flag.ListAllPkgsFlag.Clone(),
+			IgnoreFile:     flag.IgnoreFileFlag.Clone(),
+			IgnorePolicy:   flag.IgnorePolicyFlag.Clone(),
+			ExitCode:       flag.ExitCodeFlag.Clone(),
+			Output:         flag.OutputFlag.Clone(),
+			Severity:       flag.SeverityFlag.Clone(),
+			ShowSuppressed: flag.ShowSuppressedFlag.Clone(),
+		},
+		ScanFlagGroup:          scanFlagGroup,
+		SecretFlagGroup:        flag.NewSecretFlagGroup(),
+		SignatureFlagGroup:     flag.NewSignatureFlagGroup(),
+		VulnerabilityFlagGroup: flag.NewVulnerabilityFlagGroup(),
+	}
+
+	cmd := &cobra.Command{
+		Use:     "registry [flags] HOST",
+		GroupID: groupScanning,
+		Short:   "[EXPERIMENTAL] Scan the container images in a registry",
+		Long: `Scan the container images in a registry
+
+The repositories and the tags are listed with the catalog API of the registry.
+The images share the cache so that the common layers are analyzed only once.`,
+		Example: `  # Scan all the images in the registry
+  $ trivy registry registry.example.com
+
+  # Scan the release tags of the repositories of a team, except the cache repositories
+  $ trivy registry --repositories 'team-a/*' --exclude-repositories '*/cache' --tags 'v*' registry.example.com
+
+  # Scan only the images created within the last 30 days
+  $ trivy registry --max-tag-age 720h registry.example.com
+
+  # Generate the consolidated report in JSON
+  $ trivy registry --format json --output result.json registry.example.com
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := registryFlags.Bind(cmd); err != nil {
+				return xerrors.Errorf("flag bind error: %w", err)
+			}
+			return validateArgs(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := registryFlags.ToOptions(args)
+			if err != nil {
+				return xerrors.Errorf("flag error: %w", err)
+			}
+			return registrycommands.Run(cmd.Context(), options)
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	registryFlags.AddFlags(cmd)
+	cmd.SetFlagErrorFunc(flagErrorFunc)
+	cmd.SetUsageTemplate(fmt.Sprintf(usageTemplate, registryFlags.Usages(cmd)))
+
+	return cmd
+}
+
 func NewFilesystemCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	reportFlagGroup := flag.NewReportFlagGroup()
 	reportFormat := flag.ReportFormatFlag.Clone()
@@ -324,18 +432,28 @@ func NewFilesystemCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	reportFlagGroup.ReportFormat = reportFormat
 	reportFlagGroup.ExitOnEOL = nil // disable '--exit-on-eol'
 
+	scanFlagGroup := flag.NewScanFlagGroup()
+	scanFlagGroup.ChangedFrom = flag.ChangedFromFlag.Clone() // enable '--changed-from'
+	scanFlagGroup.Incremental = flag.IncrementalFlag.Clone() // enable '--incremental'
+	scanFlagGroup.Budget = flag.BudgetFlag.Clone()           // enable '--budget'
+
+	misconfFlagGroup := flag.NewMisconfFlagGroup()
+	misconfFlagGroup.K8sCRDSchemas = flag.K8sCRDSchemasFlag.Clone()                       // enable '--k8s-crd-schemas'
+	misconfFlagGroup.K8sCRDSchemasFromCluster = flag.K8sCRDSchemasFromClusterFlag.Clone() // enable '--k8s-crd-schemas-from-cluster'
+
 	fsFlags := &flag.Flags{
 		GlobalFlagGroup:        globalFlags,
 		CacheFlagGroup:         flag.NewCacheFlagGroup(),
 		DBFlagGroup:            flag.NewDBFlagGroup(),
 		LicenseFlagGroup:       flag.NewLicenseFlagGroup(),
-		MisconfFlagGroup:       flag.NewMisconfFlagGroup(),
+		MisconfFlagGroup:       misconfFlagGroup,
 		ModuleFlagGroup:        flag.NewModuleFlagGroup(),
+		PublishFlagGroup:       flag.NewPublishFlagGroup(),
 		RemoteFlagGroup:        flag.NewClientFlags(), // for client/server mode
 		RegistryFlagGroup:      flag.NewRegistryFlagGroup(),
 		RegoFlagGroup:          flag.NewRegoFlagGroup(),
 		ReportFlagGroup:        reportFlagGroup,
-		ScanFlagGroup:          flag.NewScanFlagGroup(),
+		ScanFlagGroup:          scanFlagGroup,
 		SecretFlagGroup:        flag.NewSecretFlagGroup(),
 		VulnerabilityFlagGroup: flag.NewVulnerabilityFlagGroup(),
 	}
@@ -349,7 +467,10 @@ func NewFilesystemCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
   $ trivy fs /path/to/your_project
 
   # Scan a single file
-  $ trivy fs ./trivy-ci-test/Pipfile.lock`,
+  $ trivy fs ./trivy-ci-test/Pipfile.lock
+
+  # Scan only files changed since the main branch
+  $ trivy fs --changed-from origin/main .`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if err := fsFlags.Bind(cmd); err != nil {
 				return xerrors.Errorf("flag bind error: %w", err)
@@ -385,6 +506,7 @@ func NewRootfsCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 		LicenseFlagGroup:       flag.NewLicenseFlagGroup(),
 		MisconfFlagGroup:       flag.NewMisconfFlagGroup(),
 		ModuleFlagGroup:        flag.NewModuleFlagGroup(),
+		PublishFlagGroup:       flag.NewPublishFlagGroup(),
 		RemoteFlagGroup:        flag.NewClientFlags(), // for client/server mode
 		RegistryFlagGroup:      flag.NewRegistryFlagGroup(),
 		RegoFlagGroup:          flag.NewRegoFlagGroup(),
@@ -398,6 +520,8 @@ func NewRootfsCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	rootfsFlags.ReportFlagGroup.ReportFormat = nil // disable '--report'
 	rootfsFlags.ScanFlagGroup.IncludeDevDeps = nil // disable '--include-dev-deps'
 
+	rootfsFlags.ScanFlagGroup.Budget = flag.BudgetFlag.Clone() // enable '--budget'
+
 	cmd := &cobra.Command{
 		Use:     "rootfs [flags] ROOTDIR",
 		Short:   "Scan rootfs",
@@ -437,13 +561,18 @@ func NewRootfsCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 }
 
 func NewRepositoryCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
+	misconfFlagGroup := flag.NewMisconfFlagGroup()
+	misconfFlagGroup.K8sCRDSchemas = flag.K8sCRDSchemasFlag.Clone()                       // enable '--k8s-crd-schemas'
+	misconfFlagGroup.K8sCRDSchemasFromCluster = flag.K8sCRDSchemasFromClusterFlag.Clone() // enable '--k8s-crd-schemas-from-cluster'
+
 	repoFlags := &flag.Flags{
 		GlobalFlagGroup:        globalFlags,
 		CacheFlagGroup:         flag.NewCacheFlagGroup(),
 		DBFlagGroup:            flag.NewDBFlagGroup(),
 		LicenseFlagGroup:       flag.NewLicenseFlagGroup(),
-		MisconfFlagGroup:       flag.NewMisconfFlagGroup(),
+		MisconfFlagGroup:       misconfFlagGroup,
 		ModuleFlagGroup:        flag.NewModuleFlagGroup(),
+		PublishFlagGroup:       flag.NewPublishFlagGroup(),
 		RegistryFlagGroup:      flag.NewRegistryFlagGroup(),
 		RegoFlagGroup:          flag.NewRegoFlagGroup(),
 		RemoteFlagGroup:        flag.NewClientFlags(), // for client/server mode
@@ -457,6 +586,8 @@ func NewRepositoryCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	repoFlags.ReportFlagGroup.Compliance = nil   // disable '--compliance'
 	repoFlags.ReportFlagGroup.ExitOnEOL = nil    // disable '--exit-on-eol'
 
+	repoFlags.ScanFlagGroup.Budget = flag.BudgetFlag.Clone() // enable '--budget'
+
 	cmd := &cobra.Command{
 		Use:     "repository [flags] (REPO_PATH | REPO_URL)",
 		Aliases: []string{"repo"},
@@ -534,6 +665,203 @@ func NewConvertCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	return cmd
 }
 
+func NewCompareCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
+	formatFlag := flag.FormatFlag.Clone()
+	formatFlag.Values = xstrings.ToStringSlice([]types.Format{
+		types.FormatTable,
+		types.FormatJSON,
+	})
+
+	compareFlags := &flag.Flags{
+		GlobalFlagGroup: globalFlags,
+		ReportFlagGroup: &flag.ReportFlagGroup{
+			Format:   formatFlag,
+			Output:   flag.OutputFlag.Clone(),
+			Severity: flag.SeverityFlag.Clone(),
+		},
+		AWSFlagGroup: &flag.AWSFlagGroup{
+			Region: &flag.Flag[string]{
+				Name:       "aws-region",
+				ConfigName: "aws.region",
+				Usage:      "AWS region of the ECR registry (default: the region in the image name)",
+			},
+			Endpoint: &flag.Flag[string]{
+				Name:       "endpoint",
+				ConfigName: "aws.endpoint",
+				Usage:      "AWS Endpoint override",
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "compare [flags] RESULT_JSON",
+		GroupID: groupUtility,
+		Short:   "[EXPERIMENTAL] Compare Trivy JSON report with Amazon ECR image scan findings",
+		Example: `  # Compare the vulnerabilities with the findings of ECR basic or enhanced scanning
+  $ trivy image --format json --output result.json 123456789012.dkr.ecr.us-east-1.amazonaws.com/app:1.0
+  $ trivy compare result.json
+
+  # Show the comparison in JSON
+  $ trivy compare --format json --output comparison.json result.json
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := compareFlags.Bind(cmd); err != nil {
+				return xerrors.Errorf("flag bind error: %w", err)
+			}
+			return validateArgs(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := compareFlags.Bind(cmd); err != nil {
+				return xerrors.Errorf("flag bind error: %w", err)
+			}
+			opts, err := compareFlags.ToOptions(args)
+			if err != nil {
+				return xerrors.Errorf("flag error: %w", err)
+			}
+
+			return compare.Run(cmd.Context(), opts)
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	cmd.SetFlagErrorFunc(flagErrorFunc)
+	compareFlags.AddFlags(cmd)
+	cmd.SetUsageTemplate(fmt.Sprintf(usageTemplate, compareFlags.Usages(cmd)))
+
+	return cmd
+}
+
+func NewCheckCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
+	formatFlag := flag.FormatFlag.Clone()
+	formatFlag.Values = xstrings.ToStringSlice([]types.Format{
+		types.FormatTable,
+		types.FormatJSON,
+	})
+
+	testFlags := &flag.Flags{
+		GlobalFlagGroup: globalFlags,
+		RegoFlagGroup: &flag.RegoFlagGroup{
+			Trace:      flag.TraceFlag.Clone(),
+			DataPaths:  flag.ConfigDataFlag.Clone(),
+			TestFilter: flag.TestFilterFlag.Clone(),
+		},
+		ReportFlagGroup: &flag.ReportFlagGroup{
+			Format: formatFlag,
+			Output: flag.OutputFlag.Clone(),
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:           "check subcommand",
+		GroupID:       groupUtility,
+		Short:         "Develop custom checks",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	testCmd := &cobra.Command{
+		Use:   "test [flags] PATH...",
+		Short: "Run the tests of custom checks",
+		Long: `Run the tests of custom checks written in Rego, as "opa test" does.
+The checks are compiled with the built-in libraries and the input schemas used when scanning,
+so that tests fail if checks refer to fields that Trivy never passes.
+JSON and YAML files in the directories are loaded as data, and can be used as fixture inputs.`,
+		Example: `  # Run all the tests in the directory
+  $ trivy check test ./checks
+
+  # Run the tests with fixture inputs in another directory
+  $ trivy check test --config-data ./fixtures ./checks
+
+  # Run only the tests matching the regular expression
+  $ trivy check test --run 'test_root' ./checks`,
+		Args: cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := testFlags.Bind(cmd); err != nil {
+				return xerrors.Errorf("flag bind error: %w", err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := testFlags.ToOptions(args)
+			if err != nil {
+				return xerrors.Errorf("flag error: %w", err)
+			}
+			return check.Test(cmd.Context(), opts, args)
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	testCmd.SetFlagErrorFunc(flagErrorFunc)
+	testFlags.AddFlags(testCmd)
+	testCmd.SetUsageTemplate(fmt.Sprintf(usageTemplate, testFlags.Usages(testCmd)))
+
+	cmd.AddCommand(testCmd)
+	cmd.SetFlagErrorFunc(flagErrorFunc)
+	return cmd
+}
+
+func NewDevCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
+	parseFlags := &flag.Flags{
+		GlobalFlagGroup: globalFlags,
+		DBFlagGroup: &flag.DBFlagGroup{
+			SkipJavaDBUpdate: flag.SkipJavaDBUpdateFlag.Clone(),
+			JavaDBRepository: flag.JavaDBRepositoryFlag.Clone(),
+		},
+		ReportFlagGroup: &flag.ReportFlagGroup{
+			Output: flag.OutputFlag.Clone(),
+		},
+		ScanFlagGroup: &flag.ScanFlagGroup{
+			OfflineScan:  flag.OfflineScanFlag.Clone(),
+			FilePatterns: flag.FilePatternsFlag.Clone(),
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:           "dev subcommand",
+		GroupID:       groupUtility,
+		Short:         "Tools for developing Trivy and reporting bugs",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	parseCmd := &cobra.Command{
+		Use:   "parse [flags] FILE",
+		Short: "Parse a file with the dependency parsers and print the result as JSON",
+		Long: `Parse a file with the dependency parsers supporting it, and print the detected packages
+with their dependencies and locations as JSON.
+The output can be attached to bug reports of parsers and used to write golden files of tests.
+The parsers are selected by the file name, so keep the original name of the file, e.g. package-lock.json.
+'--file-patterns' can select parsers of single files such as requirements.txt for files with other names.`,
+		Example: `  # Parse a lock file
+  $ trivy dev parse package-lock.json
+
+  # Parse a renamed file with the pip parser
+  $ trivy dev parse --file-patterns "pip:requirements-.*\\.txt" requirements-dev.txt`,
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := parseFlags.Bind(cmd); err != nil {
+				return xerrors.Errorf("flag bind error: %w", err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := parseFlags.ToOptions(args)
+			if err != nil {
+				return xerrors.Errorf("flag error: %w", err)
+			}
+			return dev.Parse(cmd.Context(), opts, args[0])
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	parseCmd.SetFlagErrorFunc(flagErrorFunc)
+	parseFlags.AddFlags(parseCmd)
+	parseCmd.SetUsageTemplate(fmt.Sprintf(usageTemplate, parseFlags.Usages(parseCmd)))
+
+	cmd.AddCommand(parseCmd)
+	cmd.SetFlagErrorFunc(flagErrorFunc)
+	return cmd
+}
+
 // NewClientCommand returns the 'client' subcommand that is deprecated
 func NewClientCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	remoteFlags := flag.NewClientFlags()
@@ -592,6 +920,10 @@ func NewClientCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 }
 
 func NewServerCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
+	// The server has no '--severity' and counts all the findings
+	webhookThreshold := flag.WebhookThresholdFlag.Clone()
+	webhookThreshold.Usage = "[EXPERIMENTAL] number of findings required to post to '--webhook-url'. Every scan is posted if 0"
+
 	serverFlags := &flag.Flags{
 		GlobalFlagGroup:   globalFlags,
 		CacheFlagGroup:    flag.NewCacheFlagGroup(),
@@ -599,11 +931,18 @@ func NewServerCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 		ModuleFlagGroup:   flag.NewModuleFlagGroup(),
 		RemoteFlagGroup:   flag.NewServerFlags(),
 		RegistryFlagGroup: flag.NewRegistryFlagGroup(),
+		ReportFlagGroup: &flag.ReportFlagGroup{
+			WebhookURL:       flag.WebhookURLFlag.Clone(),
+			WebhookTemplate:  flag.WebhookTemplateFlag.Clone(),
+			WebhookThreshold: webhookThreshold,
+		},
 	}
 
 	// java-db only works on client side.
 	serverFlags.DBFlagGroup.DownloadJavaDBOnly = nil // disable '--download-java-db-only'
 	serverFlags.DBFlagGroup.SkipJavaDBUpdate = nil   // disable '--skip-java-db-update'
+	serverFlags.DBFlagGroup.JavaDBAllowStale = nil   // disable '--java-db-allow-stale'
+	serverFlags.DBFlagGroup.JavaDBShards = nil       // disable '--java-db-shards'
 	serverFlags.DBFlagGroup.JavaDBRepository = nil   // disable '--java-db-repository'
 
 	cmd := &cobra.Command{
@@ -640,10 +979,11 @@ func NewServerCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 
 func NewConfigCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	reportFlagGroup := flag.NewReportFlagGroup()
-	reportFlagGroup.DependencyTree = nil // disable '--dependency-tree'
-	reportFlagGroup.ListAllPkgs = nil    // disable '--list-all-pkgs'
-	reportFlagGroup.ExitOnEOL = nil      // disable '--exit-on-eol'
-	reportFlagGroup.ShowSuppressed = nil // disable '--show-suppressed'
+	reportFlagGroup.DependencyTree = nil       // disable '--dependency-tree'
+	reportFlagGroup.ListAllPkgs = nil          // disable '--list-all-pkgs'
+	reportFlagGroup.ExitOnEOL = nil            // disable '--exit-on-eol'
+	reportFlagGroup.ShowSuppressed = nil       // disable '--show-suppressed'
+	reportFlagGroup.InstalledManifestDir = nil // disable '--installed-manifest-dir'
 	reportFormat := flag.ReportFormatFlag.Clone()
 	reportFormat.Usage = "specify a compliance report format for the output" // @TODO: support --report summary for non compliance reports
 	reportFlagGroup.ReportFormat = reportFormat
@@ -655,11 +995,17 @@ func NewConfigCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 		FilePatterns: flag.FilePatternsFlag.Clone(),
 	}
 
+	misconfFlagGroup := flag.NewMisconfFlagGroup()
+	misconfFlagGroup.K8sCRDSchemas = flag.K8sCRDSchemasFlag.Clone()                       // enable '--k8s-crd-schemas'
+	misconfFlagGroup.K8sCRDSchemasFromCluster = flag.K8sCRDSchemasFromClusterFlag.Clone() // enable '--k8s-crd-schemas-from-cluster'
+	misconfFlagGroup.FixDryRun = flag.FixDryRunFlag.Clone()                               // enable '--fix-dry-run'
+
 	configFlags := &flag.Flags{
 		GlobalFlagGroup:   globalFlags,
 		CacheFlagGroup:    flag.NewCacheFlagGroup(),
-		MisconfFlagGroup:  flag.NewMisconfFlagGroup(),
+		MisconfFlagGroup:  misconfFlagGroup,
 		ModuleFlagGroup:   flag.NewModuleFlagGroup(),
+		PublishFlagGroup:  flag.NewPublishFlagGroup(),
 		RegistryFlagGroup: flag.NewRegistryFlagGroup(),
 		RegoFlagGroup:     flag.NewRegoFlagGroup(),
 		K8sFlagGroup: &flag.K8sFlagGroup{
@@ -708,7 +1054,13 @@ func NewConfigCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	return cmd
 }
 
-func NewPluginCommand() *cobra.Command {
+func NewPluginCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
+	pluginFlags := &flag.Flags{
+		GlobalFlagGroup:   globalFlags,
+		PluginFlagGroup:   flag.NewPluginFlagGroup(),
+		RegistryFlagGroup: flag.NewRegistryFlagGroup(),
+	}
+
 	cmd := &cobra.Command{
 		Use:           "plugin subcommand",
 		Aliases:       []string{"p"},
@@ -717,21 +1069,65 @@ func NewPluginCommand() *cobra.Command {
 		SilenceErrors: true,
 		SilenceUsage:  true,
 	}
-	cmd.AddCommand(
-		&cobra.Command{
-			Use:                   "install URL | FILE_PATH",
-			Aliases:               []string{"i"},
-			Short:                 "Install a plugin",
-			SilenceErrors:         true,
-			DisableFlagsInUseLine: true,
-			Args:                  cobra.ExactArgs(1),
-			RunE: func(cmd *cobra.Command, args []string) error {
-				if _, err := plugin.Install(cmd.Context(), args[0], true); err != nil {
-					return xerrors.Errorf("plugin install error: %w", err)
-				}
-				return nil
-			},
+
+	bindFlags := func(cmd *cobra.Command, args []string) error {
+		if err := pluginFlags.Bind(cmd); err != nil {
+			return xerrors.Errorf("flag bind error: %w", err)
+		}
+		return nil
+	}
+
+	installCmd := &cobra.Command{
+		Use:     "install [flags] NAME[@VERSION] | URL | FILE_PATH",
+		Aliases: []string{"i"},
+		Short:   "Install a plugin",
+		Example: `  # Install the plugin in the index
+  $ trivy plugin install count
+  # Install the specific version and pin it in the lockfile
+  $ trivy plugin install --plugin-lockfile trivy-plugins.lock.yaml count@0.2.0`,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		PreRunE:       bindFlags,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := pluginFlags.ToOptions(args)
+			if err != nil {
+				return xerrors.Errorf("flag error: %w", err)
+			}
+			if _, err = plugin.Install(cmd.Context(), args[0], true, opts.PluginOpts()); err != nil {
+				return xerrors.Errorf("plugin install error: %w", err)
+			}
+			return nil
 		},
+	}
+	updateCmd := &cobra.Command{
+		Use:           "update [flags] [PLUGIN_NAME]",
+		Short:         "Update an existing plugin, or all the installed plugins if the name is not specified",
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		PreRunE:       bindFlags,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := pluginFlags.ToOptions(args)
+			if err != nil {
+				return xerrors.Errorf("flag error: %w", err)
+			}
+			if len(args) == 0 {
+				err = plugin.UpdateAll(cmd.Context(), opts.PluginOpts())
+			} else {
+				err = plugin.Update(cmd.Context(), args[0], opts.PluginOpts())
+			}
+			if err != nil {
+				return xerrors.Errorf("plugin update error: %w", err)
+			}
+			return nil
+		},
+	}
+	for _, subcmd := range []*cobra.Command{installCmd, updateCmd} {
+		pluginFlags.AddFlags(subcmd)
+		subcmd.SetFlagErrorFunc(flagErrorFunc)
+	}
+
+	cmd.AddCommand(
+		installCmd,
 		&cobra.Command{
 			Use:                   "uninstall PLUGIN_NAME",
 			Aliases:               []string{"u"},
@@ -792,19 +1188,7 @@ func NewPluginCommand() *cobra.Command {
 				return plugin.RunWithURL(cmd.Context(), args[0], plugin.RunOptions{Args: args[1:]})
 			},
 		},
-		&cobra.Command{
-			Use:                   "update PLUGIN_NAME",
-			Short:                 "Update an existing plugin",
-			SilenceErrors:         true,
-			DisableFlagsInUseLine: true,
-			Args:                  cobra.ExactArgs(1),
-			RunE: func(_ *cobra.Command, args []string) error {
-				if err := plugin.Update(args[0]); err != nil {
-					return xerrors.Errorf("plugin update error: %w", err)
-				}
-				return nil
-			},
-		},
+		updateCmd,
 	)
 	cmd.SetFlagErrorFunc(flagErrorFunc)
 	return cmd
@@ -881,6 +1265,227 @@ func NewModuleCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	return cmd
 }
 
+func NewPolicyCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
+	policyFlags := &flag.Flags{
+		GlobalFlagGroup: globalFlags,
+		MisconfFlagGroup: &flag.MisconfFlagGroup{
+			PolicyBundleRepository: flag.PolicyBundleRepositoryFlag.Clone(),
+		},
+		RegistryFlagGroup: flag.NewRegistryFlagGroup(),
+	}
+
+	cmd := &cobra.Command{
+		Use:           "policy subcommand",
+		GroupID:       groupManagement,
+		Short:         "Manage the policy bundle of built-in checks",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	bindFlags := func(cmd *cobra.Command, args []string) error {
+		if err := policyFlags.Bind(cmd); err != nil {
+			return xerrors.Errorf("flag bind error: %w", err)
+		}
+		return nil
+	}
+
+	// Add subcommands
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:     "download [flags]",
+			Short:   "Download the policy bundle into the cache",
+			Args:    cobra.NoArgs,
+			PreRunE: bindFlags,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				opts, err := policyFlags.ToOptions(args)
+				if err != nil {
+					return xerrors.Errorf("flag error: %w", err)
+				}
+				return policycommands.Download(cmd.Context(), opts)
+			},
+		},
+		&cobra.Command{
+			Use:     "verify [flags]",
+			Short:   "Verify that the policy bundle in the cache has not been modified since the download",
+			Args:    cobra.NoArgs,
+			PreRunE: bindFlags,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				opts, err := policyFlags.ToOptions(args)
+				if err != nil {
+					return xerrors.Errorf("flag error: %w", err)
+				}
+				return policycommands.Verify(opts)
+			},
+		},
+		&cobra.Command{
+			Use:   "pin [flags] [DIGEST]",
+			Short: "Pin the policy bundle so that it is not updated when scanning",
+			Long: `Pin the policy bundle in the cache so that it is not updated when scanning.
+If a digest is specified, the bundle with the digest is downloaded from the repository and pinned.`,
+			Example: `  # Pin the current policy bundle
+  $ trivy policy pin
+
+  # Pin the policy bundle with the digest
+  $ trivy policy pin sha256:9d1d7d4c0e5a4b5f0d6f5c0c2b0f3d5e1a9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a`,
+			Args:    cobra.MaximumNArgs(1),
+			PreRunE: bindFlags,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				opts, err := policyFlags.ToOptions(args)
+				if err != nil {
+					return xerrors.Errorf("flag error: %w", err)
+				}
+				var digest string
+				if len(args) == 1 {
+					digest = args[0]
+				}
+				return policycommands.Pin(cmd.Context(), opts, digest)
+			},
+		},
+		&cobra.Command{
+			Use:     "unpin [flags]",
+			Short:   "Unpin the policy bundle so that it is updated when scanning",
+			Args:    cobra.NoArgs,
+			PreRunE: bindFlags,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				opts, err := policyFlags.ToOptions(args)
+				if err != nil {
+					return xerrors.Errorf("flag error: %w", err)
+				}
+				return policycommands.Unpin(opts)
+			},
+		},
+		&cobra.Command{
+			Use:     "list [flags]",
+			Aliases: []string{"ls"},
+			Short:   "List the checks in the policy bundle",
+			Args:    cobra.NoArgs,
+			PreRunE: bindFlags,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				opts, err := policyFlags.ToOptions(args)
+				if err != nil {
+					return xerrors.Errorf("flag error: %w", err)
+				}
+				return policycommands.List(cmd.OutOrStdout(), opts)
+			},
+		},
+		&cobra.Command{
+			Use:   "vendor [flags] DIR",
+			Short: "Copy the policy bundle into a directory for use in other environments",
+			Long: `Copy the policy bundle in the cache into a directory for use in other environments such as air-gapped ones.
+The vendored bundle is pinned. Place the directory as "policy" in the cache directory of the other environment.`,
+			Example: `  $ trivy policy download
+  $ trivy policy vendor ./policy
+  # On the air-gapped machine
+  $ cp -r ./policy ~/.cache/trivy/policy`,
+			Args:    cobra.ExactArgs(1),
+			PreRunE: bindFlags,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				opts, err := policyFlags.ToOptions(args)
+				if err != nil {
+					return xerrors.Errorf("flag error: %w", err)
+				}
+				return policycommands.Vendor(opts, args[0])
+			},
+		},
+	)
+	for _, subcmd := range cmd.Commands() {
+		policyFlags.AddFlags(subcmd)
+		subcmd.SetFlagErrorFunc(flagErrorFunc)
+	}
+	cmd.SetFlagErrorFunc(flagErrorFunc)
+	return cmd
+}
+
+func NewBundleCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
+	bundleFlags := &flag.Flags{
+		GlobalFlagGroup: globalFlags,
+		BundleFlagGroup: &flag.BundleFlagGroup{
+			Templates:       flag.BundleTemplatesFlag.Clone(),
+			ComplianceSpecs: flag.BundleComplianceSpecsFlag.Clone(),
+		},
+		DBFlagGroup: flag.NewDBFlagGroup(),
+		MisconfFlagGroup: &flag.MisconfFlagGroup{
+			PolicyBundleRepository: flag.PolicyBundleRepositoryFlag.Clone(),
+		},
+		RegistryFlagGroup: flag.NewRegistryFlagGroup(),
+		RegoFlagGroup: &flag.RegoFlagGroup{
+			SkipPolicyUpdate: flag.SkipPolicyUpdateFlag.Clone(),
+		},
+	}
+	bundleFlags.DBFlagGroup.JavaDBAllowStale = nil // the bundle must not contain an outdated Java DB
+	bundleFlags.DBFlagGroup.JavaDBShards = nil     // the bundle contains the full Java DB for the fallback
+	installFlags := &flag.Flags{
+		GlobalFlagGroup: globalFlags,
+		BundleFlagGroup: &flag.BundleFlagGroup{
+			InstallDir: flag.BundleInstallDirFlag.Clone(),
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:           "bundle subcommand",
+		GroupID:       groupManagement,
+		Short:         "[EXPERIMENTAL] Bundle Trivy, the databases and checks for air-gapped environments",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create [flags] OUTPUT",
+		Short: "Create a bundle of the binary, databases, checks, templates and compliance specs",
+		Long: `Create a tar.gz archive containing the binary, the vulnerability DB, the Java DB, the policy bundle,
+templates and custom compliance specs. The databases and checks in the cache are updated before bundling.
+The archive contains a manifest with the digests of all the files, which are verified on installation.`,
+		Example: `  $ trivy bundle create --templates contrib/html.tpl --compliance-specs ./my-spec.yaml trivy-bundle.tar.gz`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := bundleFlags.Bind(cmd); err != nil {
+				return xerrors.Errorf("flag bind error: %w", err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := bundleFlags.ToOptions(args)
+			if err != nil {
+				return xerrors.Errorf("flag error: %w", err)
+			}
+			return bundlecommands.Create(cmd.Context(), opts, args[0])
+		},
+	}
+	bundleFlags.AddFlags(createCmd)
+
+	installCmd := &cobra.Command{
+		Use:   "install [flags] BUNDLE",
+		Short: "Verify a bundle and install it",
+		Long: `Verify the digests of all the files in a bundle and install it.
+The databases and checks are installed into the cache directory, and the checks are pinned.
+The binary, templates and compliance specs are installed into the install directory.`,
+		Example: `  $ trivy bundle install --cache-dir /var/lib/trivy --install-dir /opt/trivy trivy-bundle.tar.gz
+  $ /opt/trivy/bin/trivy image --cache-dir /var/lib/trivy --skip-db-update --skip-java-db-update alpine:3.19`,
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := installFlags.Bind(cmd); err != nil {
+				return xerrors.Errorf("flag bind error: %w", err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := installFlags.ToOptions(args)
+			if err != nil {
+				return xerrors.Errorf("flag error: %w", err)
+			}
+			return bundlecommands.Install(cmd.Context(), opts, args[0])
+		},
+	}
+	installFlags.AddFlags(installCmd)
+
+	cmd.AddCommand(createCmd, installCmd)
+	for _, subcmd := range cmd.Commands() {
+		subcmd.SetFlagErrorFunc(flagErrorFunc)
+	}
+	cmd.SetFlagErrorFunc(flagErrorFunc)
+	return cmd
+}
+
 func NewKubernetesCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	scanFlags := flag.NewScanFlagGroup()
 	scanners := flag.ScannersFlag.Clone()
@@ -894,6 +1499,7 @@ func NewKubernetesCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	scanners.Default = scanners.Values
 	scanFlags.Scanners = scanners
 	scanFlags.IncludeDevDeps = nil // disable '--include-dev-deps'
+	scanFlags.DebugReport = nil    // disable '--debug-report'
 
 	// required only SourceFlag
 	imageFlags := &flag.ImageFlagGroup{ImageSources: flag.SourceFlag.Clone()}
@@ -906,14 +1512,18 @@ func NewKubernetesCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 		types.ComplianceK8sPSSBaseline,
 		types.ComplianceK8sPSSRestricted,
 	}
-	reportFlagGroup.Compliance = compliance // override usage as the accepted values differ for each subcommand.
-	reportFlagGroup.ExitOnEOL = nil         // disable '--exit-on-eol'
+	reportFlagGroup.Compliance = compliance    // override usage as the accepted values differ for each subcommand.
+	reportFlagGroup.ExitOnEOL = nil            // disable '--exit-on-eol'
+	reportFlagGroup.InstalledManifestDir = nil // disable '--installed-manifest-dir'
+	reportFlagGroup.ResultPolicy = nil         // disable '--result-policy'
+	reportFlagGroup.Lang = nil                 // disable '--lang'
 
 	formatFlag := flag.FormatFlag.Clone()
 	formatFlag.Values = xstrings.ToStringSlice([]types.Format{
 		types.FormatTable,
 		types.FormatJSON,
 		types.FormatCycloneDX,
+		types.FormatHTML, // only with '--compliance'
 	})
 	reportFlagGroup.Format = formatFlag
 
@@ -921,6 +1531,9 @@ func NewKubernetesCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	misconfFlagGroup.CloudformationParamVars = nil // disable '--cf-params'
 	misconfFlagGroup.TerraformTFVars = nil         // disable '--tf-vars'
 
+	misconfFlagGroup.K8sCRDSchemas = flag.K8sCRDSchemasFlag.Clone()                       // enable '--k8s-crd-schemas'
+	misconfFlagGroup.K8sCRDSchemasFromCluster = flag.K8sCRDSchemasFromClusterFlag.Clone() // enable '--k8s-crd-schemas-from-cluster'
+
 	k8sFlags := &flag.Flags{
 		GlobalFlagGroup:        globalFlags,
 		CacheFlagGroup:         flag.NewCacheFlagGroup(),
@@ -987,9 +1600,12 @@ func NewAWSCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 		types.ComplianceAWSCIS12,
 		types.ComplianceAWSCIS14,
 	}
-	reportFlagGroup.Compliance = &compliance // override usage as the accepted values differ for each subcommand.
-	reportFlagGroup.ExitOnEOL = nil          // disable '--exit-on-eol'
-	reportFlagGroup.ShowSuppressed = nil     // disable '--show-suppressed'
+	reportFlagGroup.Compliance = &compliance   // override usage as the accepted values differ for each subcommand.
+	reportFlagGroup.ExitOnEOL = nil            // disable '--exit-on-eol'
+	reportFlagGroup.ShowSuppressed = nil       // disable '--show-suppressed'
+	reportFlagGroup.InstalledManifestDir = nil // disable '--installed-manifest-dir'
+	reportFlagGroup.ResultPolicy = nil         // disable '--result-policy'
+	reportFlagGroup.Lang = nil                 // disable '--lang'
 
 	awsFlags := &flag.Flags{
 		GlobalFlagGroup:  globalFlags,
@@ -1061,10 +1677,12 @@ func NewVMCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 		DBFlagGroup:            flag.NewDBFlagGroup(),
 		MisconfFlagGroup:       flag.NewMisconfFlagGroup(),
 		ModuleFlagGroup:        flag.NewModuleFlagGroup(),
+		PublishFlagGroup:       flag.NewPublishFlagGroup(),
 		RemoteFlagGroup:        flag.NewClientFlags(), // for client/server mode
 		ReportFlagGroup:        flag.NewReportFlagGroup(),
 		ScanFlagGroup:          flag.NewScanFlagGroup(),
 		SecretFlagGroup:        flag.NewSecretFlagGroup(),
+		VMFlagGroup:            flag.NewVMFlagGroup(),
 		VulnerabilityFlagGroup: flag.NewVulnerabilityFlagGroup(),
 		AWSFlagGroup: &flag.AWSFlagGroup{
 			Region: &flag.Flag[string]{
@@ -1089,6 +1707,9 @@ func NewVMCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 
   # Scan your AWS EBS snapshot
   $ trivy vm ebs:${your_ebs_snapshot_id}
+
+  # Scan your local VM image with LUKS-encrypted volumes
+  $ TRIVY_LUKS_PASSPHRASE=${your_passphrase} trivy vm ./disk.qcow2
 `,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if err := vmFlags.Bind(cmd); err != nil {
@@ -1120,6 +1741,75 @@ func NewVMCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	return cmd
 }
 
+func NewLambdaCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
+	lambdaFlags := &flag.Flags{
+		GlobalFlagGroup:        globalFlags,
+		CacheFlagGroup:         flag.NewCacheFlagGroup(),
+		DBFlagGroup:            flag.NewDBFlagGroup(),
+		LicenseFlagGroup:       flag.NewLicenseFlagGroup(),
+		ModuleFlagGroup:        flag.NewModuleFlagGroup(),
+		PublishFlagGroup:       flag.NewPublishFlagGroup(),
+		RemoteFlagGroup:        flag.NewClientFlags(), // for client/server mode
+		ReportFlagGroup:        flag.NewReportFlagGroup(),
+		ScanFlagGroup:          flag.NewScanFlagGroup(),
+		SecretFlagGroup:        flag.NewSecretFlagGroup(),
+		VulnerabilityFlagGroup: flag.NewVulnerabilityFlagGroup(),
+		AWSFlagGroup: &flag.AWSFlagGroup{
+			Region: &flag.Flag[string]{
+				Name:       "aws-region",
+				ConfigName: "aws.region",
+				Usage:      "AWS region of the function",
+			},
+			Endpoint: &flag.Flag[string]{
+				Name:       "endpoint",
+				ConfigName: "aws.endpoint",
+				Usage:      "AWS Endpoint override",
+			},
+		},
+	}
+	lambdaFlags.ReportFlagGroup.ReportFormat = nil // disable '--report'
+	lambdaFlags.ScanFlagGroup.IncludeDevDeps = nil // disable '--include-dev-deps'
+
+	cmd := &cobra.Command{
+		Use:     "lambda [flags] FUNCTION",
+		Aliases: []string{},
+		GroupID: groupScanning,
+		Short:   "[EXPERIMENTAL] Scan an AWS Lambda function with its layers",
+		Example: `  # Scan your Lambda function
+  $ trivy lambda arn:aws:lambda:us-east-1:123456789012:function:my-function
+
+  # Scan a version of the function
+  $ trivy lambda arn:aws:lambda:us-east-1:123456789012:function:my-function:3
+
+  # Scan your Lambda function by name
+  $ trivy lambda --aws-region us-east-1 my-function
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := lambdaFlags.Bind(cmd); err != nil {
+				return xerrors.Errorf("flag bind error: %w", err)
+			}
+			return validateArgs(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := lambdaFlags.Bind(cmd); err != nil {
+				return xerrors.Errorf("flag bind error: %w", err)
+			}
+			options, err := lambdaFlags.ToOptions(args)
+			if err != nil {
+				return xerrors.Errorf("flag error: %w", err)
+			}
+			return artifact.Run(cmd.Context(), options, artifact.TargetLambda)
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	cmd.SetFlagErrorFunc(flagErrorFunc)
+	lambdaFlags.AddFlags(cmd)
+	cmd.SetUsageTemplate(fmt.Sprintf(usageTemplate, lambdaFlags.Usages(cmd)))
+
+	return cmd
+}
+
 func NewSBOMCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 	reportFlagGroup := flag.NewReportFlagGroup()
 	reportFlagGroup.DependencyTree = nil // disable '--dependency-tree'
@@ -1134,6 +1824,7 @@ func NewSBOMCommand(globalFlags *flag.GlobalFlagGroup) *cobra.Command {
 		GlobalFlagGroup:        globalFlags,
 		CacheFlagGroup:         flag.NewCacheFlagGroup(),
 		DBFlagGroup:            flag.NewDBFlagGroup(),
+		PublishFlagGroup:       flag.NewPublishFlagGroup(),
 		RemoteFlagGroup:        flag.NewClientFlags(), // for client/server mode
 		ReportFlagGroup:        reportFlagGroup,
 		ScanFlagGroup:          scanFlagGroup,
@@ -250,7 +250,7 @@ func TestFlags(t *testing.T) {
 				"--format",
 				"foo",
 			},
-			wantErr: `invalid argument "foo" for "--format" flag`,
+			wantErr: `invalid argument "[foo]" for "--format" flag`,
 		},
 	}
 
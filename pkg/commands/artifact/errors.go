@@ -0,0 +1,76 @@
+package artifact
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/twitchtv/twirp"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+	"github.com/aquasecurity/trivy/pkg/sbom"
+	"github.com/aquasecurity/trivy/pkg/signature"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	hintRegistryAuth = "The registry denied access. Log in with 'docker login' or set '--username' and '--password', " +
+		"and remove expired credentials with 'docker logout' if the repository is public"
+	hintServerAuth = "The Trivy server rejected the request. Check '--token' and '--token-header'"
+	hintRateLimit  = "The registry rejected too many requests. Retry later, log in to the registry to raise the limit, " +
+		"or use a mirror with '--db-repository' and '--java-db-repository'"
+	hintUnsupportedArtifact = "The artifact type or format is not supported. Check the target and the subcommand, " +
+		"e.g. 'trivy sbom' for CycloneDX and SPDX files"
+	hintDBCorrupt = "The local vulnerability DB is broken. Remove it with '--reset' and run the scan again to download it"
+	hintUnsigned  = "The image has no signature matching '--signature-key' or '--certificate-identity'. " +
+		"Sign it with 'cosign sign', or remove '--require-signature' to record the result without failing"
+)
+
+// ClassifyError returns the error code and the hint for the cause of the error
+func ClassifyError(err error) types.Error {
+	code, hint := errorCode(err)
+	return types.Error{
+		Code:    code,
+		Message: err.Error(),
+		Hint:    hint,
+	}
+}
+
+func errorCode(err error) (types.ErrorCode, string) {
+	switch {
+	case errors.Is(err, bolt.ErrInvalid), errors.Is(err, bolt.ErrVersionMismatch), errors.Is(err, bolt.ErrChecksum):
+		return types.ErrorCodeDBCorrupt, hintDBCorrupt
+	case errors.Is(err, sbom.ErrUnknownFormat), errors.Is(err, vm.ErrUnsupportedType):
+		return types.ErrorCodeUnsupportedArtifact, hintUnsupportedArtifact
+	case errors.Is(err, signature.ErrUnsigned):
+		return types.ErrorCodeUnsigned, hintUnsigned
+	}
+
+	// Client/server mode
+	var twerr twirp.Error
+	if errors.As(err, &twerr) && twerr.Code() == twirp.Unauthenticated {
+		return types.ErrorCodeAuthFailure, hintServerAuth
+	}
+
+	// Registry errors, including those of the DB repositories
+	var terr *transport.Error
+	if !errors.As(err, &terr) {
+		return types.ErrorCodeUnknown, ""
+	}
+	for _, diagnostic := range terr.Errors {
+		switch diagnostic.Code {
+		case transport.TooManyRequestsErrorCode:
+			return types.ErrorCodeRateLimit, hintRateLimit
+		case transport.UnauthorizedErrorCode, transport.DeniedErrorCode:
+			return types.ErrorCodeAuthFailure, hintRegistryAuth
+		}
+	}
+	switch terr.StatusCode {
+	case http.StatusTooManyRequests:
+		return types.ErrorCodeRateLimit, hintRateLimit
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return types.ErrorCodeAuthFailure, hintRegistryAuth
+	}
+	return types.ErrorCodeUnknown, ""
+}
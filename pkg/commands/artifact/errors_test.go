@@ -0,0 +1,89 @@
+package artifact
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/twitchtv/twirp"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/sbom"
+	"github.com/aquasecurity/trivy/pkg/signature"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func Test_classifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want types.ErrorCode
+	}{
+		{
+			name: "registry denied",
+			err: multierror.Prefix(&transport.Error{
+				Errors: []transport.Diagnostic{
+					{Code: transport.DeniedErrorCode},
+				},
+				StatusCode: http.StatusForbidden,
+			}, "remote error:"),
+			want: types.ErrorCodeAuthFailure,
+		},
+		{
+			name: "unauthorized without diagnostics",
+			err:  xerrors.Errorf("OCI artifact error: %w", &transport.Error{StatusCode: http.StatusUnauthorized}),
+			want: types.ErrorCodeAuthFailure,
+		},
+		{
+			name: "invalid token of the server",
+			err:  xerrors.Errorf("scan error: %w", twirp.NewError(twirp.Unauthenticated, "invalid token")),
+			want: types.ErrorCodeAuthFailure,
+		},
+		{
+			name: "rate limit",
+			err: xerrors.Errorf("failed to download vulnerability DB: %w", &transport.Error{
+				Errors: []transport.Diagnostic{
+					{Code: transport.TooManyRequestsErrorCode},
+				},
+				StatusCode: http.StatusTooManyRequests,
+			}),
+			want: types.ErrorCodeRateLimit,
+		},
+		{
+			name: "unknown SBOM format",
+			err:  xerrors.Errorf("SBOM decode error: %w", xerrors.Errorf("unknown: %w", sbom.ErrUnknownFormat)),
+			want: types.ErrorCodeUnsupportedArtifact,
+		},
+		{
+			name: "broken DB",
+			err:  xerrors.Errorf("failed to open db: %w", bolt.ErrInvalid),
+			want: types.ErrorCodeDBCorrupt,
+		},
+		{
+			name: "unsigned image",
+			err:  xerrors.Errorf("signature verification error: %w", xerrors.Errorf("no signature found: %w", signature.ErrUnsigned)),
+			want: types.ErrorCodeUnsigned,
+		},
+		{
+			name: "not found",
+			err:  &transport.Error{StatusCode: http.StatusNotFound},
+			want: types.ErrorCodeUnknown,
+		},
+		{
+			name: "unknown",
+			err:  xerrors.New("something wrong"),
+			want: types.ErrorCodeUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyError(tt.err)
+			assert.Equal(t, tt.want, got.Code)
+			assert.Equal(t, tt.err.Error(), got.Message)
+			assert.Equal(t, tt.want == types.ErrorCodeUnknown, got.Hint == "")
+		})
+	}
+}
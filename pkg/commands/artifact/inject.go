@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/wire"
 
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
 	"github.com/aquasecurity/trivy/pkg/fanal/cache"
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
@@ -23,8 +24,8 @@ import (
 // initializeImageScanner is for container image scanning in standalone mode
 // e.g. dockerd, container registry, podman, etc.
 func initializeImageScanner(ctx context.Context, imageName string, artifactCache cache.ArtifactCache,
-	localArtifactCache cache.LocalArtifactCache, imageOpt types.ImageOptions, artifactOption artifact.Option) (
-	scanner.Scanner, func(), error) {
+	localArtifactCache cache.LocalArtifactCache, online *advisory.OnlineSource, imageOpt types.ImageOptions,
+	artifactOption artifact.Option) (scanner.Scanner, func(), error) {
 	wire.Build(scanner.StandaloneDockerSet)
 	return scanner.Scanner{}, nil, nil
 }
@@ -32,37 +33,49 @@ func initializeImageScanner(ctx context.Context, imageName string, artifactCache
 // initializeArchiveScanner is for container image archive scanning in standalone mode
 // e.g. docker save -o alpine.tar alpine:3.15
 func initializeArchiveScanner(ctx context.Context, filePath string, artifactCache cache.ArtifactCache,
-	localArtifactCache cache.LocalArtifactCache, artifactOption artifact.Option) (scanner.Scanner, error) {
+	localArtifactCache cache.LocalArtifactCache, online *advisory.OnlineSource, artifactOption artifact.Option) (
+	scanner.Scanner, error) {
 	wire.Build(scanner.StandaloneArchiveSet)
 	return scanner.Scanner{}, nil
 }
 
 // initializeFilesystemScanner is for filesystem scanning in standalone mode
 func initializeFilesystemScanner(ctx context.Context, path string, artifactCache cache.ArtifactCache,
-	localArtifactCache cache.LocalArtifactCache, artifactOption artifact.Option) (scanner.Scanner, func(), error) {
+	localArtifactCache cache.LocalArtifactCache, online *advisory.OnlineSource, artifactOption artifact.Option) (
+	scanner.Scanner, func(), error) {
 	wire.Build(scanner.StandaloneFilesystemSet)
 	return scanner.Scanner{}, nil, nil
 }
 
 func initializeRepositoryScanner(ctx context.Context, url string, artifactCache cache.ArtifactCache,
-	localArtifactCache cache.LocalArtifactCache, artifactOption artifact.Option) (scanner.Scanner, func(), error) {
+	localArtifactCache cache.LocalArtifactCache, online *advisory.OnlineSource, artifactOption artifact.Option) (
+	scanner.Scanner, func(), error) {
 	wire.Build(scanner.StandaloneRepositorySet)
 	return scanner.Scanner{}, nil, nil
 }
 
 func initializeSBOMScanner(ctx context.Context, filePath string, artifactCache cache.ArtifactCache,
-	localArtifactCache cache.LocalArtifactCache, artifactOption artifact.Option) (scanner.Scanner, func(), error) {
+	localArtifactCache cache.LocalArtifactCache, online *advisory.OnlineSource, artifactOption artifact.Option) (
+	scanner.Scanner, func(), error) {
 	wire.Build(scanner.StandaloneSBOMSet)
 	return scanner.Scanner{}, nil, nil
 }
 
 func initializeVMScanner(ctx context.Context, filePath string, artifactCache cache.ArtifactCache,
-	localArtifactCache cache.LocalArtifactCache, walker vm.Walker, artifactOption artifact.Option) (
-	scanner.Scanner, func(), error) {
+	localArtifactCache cache.LocalArtifactCache, online *advisory.OnlineSource, walker vm.Walker,
+	artifactOption artifact.Option) (scanner.Scanner, func(), error) {
 	wire.Build(scanner.StandaloneVMSet)
 	return scanner.Scanner{}, nil, nil
 }
 
+// initializeLambdaScanner is for AWS Lambda function scanning in standalone mode
+func initializeLambdaScanner(ctx context.Context, functionName string, artifactCache cache.ArtifactCache,
+	localArtifactCache cache.LocalArtifactCache, online *advisory.OnlineSource, artifactOption artifact.Option) (
+	scanner.Scanner, func(), error) {
+	wire.Build(scanner.StandaloneLambdaSet)
+	return scanner.Scanner{}, nil, nil
+}
+
 /////////////////
 // Client/Server
 /////////////////
@@ -112,3 +125,10 @@ func initializeRemoteVMScanner(ctx context.Context, path string, artifactCache c
 	wire.Build(scanner.RemoteVMSet)
 	return scanner.Scanner{}, nil, nil
 }
+
+// initializeRemoteLambdaScanner is for AWS Lambda function scanning in client/server mode
+func initializeRemoteLambdaScanner(ctx context.Context, functionName string, artifactCache cache.ArtifactCache,
+	remoteScanOptions client.ScannerOption, artifactOption artifact.Option) (scanner.Scanner, func(), error) {
+	wire.Build(scanner.RemoteLambdaSet)
+	return scanner.Scanner{}, nil, nil
+}
@@ -2,9 +2,15 @@ package artifact
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/hashicorp/go-multierror"
 	"github.com/samber/lo"
 	"github.com/spf13/viper"
@@ -13,22 +19,42 @@ import (
 
 	"github.com/aquasecurity/go-version/pkg/semver"
 	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy-kubernetes/pkg/k8s"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	tcache "github.com/aquasecurity/trivy/pkg/cache"
+	"github.com/aquasecurity/trivy/pkg/clock"
 	"github.com/aquasecurity/trivy/pkg/commands/operation"
+	"github.com/aquasecurity/trivy/pkg/debugreport"
+	"github.com/aquasecurity/trivy/pkg/delta"
+	"github.com/aquasecurity/trivy/pkg/exploit"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
 	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
 	"github.com/aquasecurity/trivy/pkg/fanal/cache"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/flag"
 	"github.com/aquasecurity/trivy/pkg/javadb"
+	k8sRep "github.com/aquasecurity/trivy/pkg/k8s"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/metrics"
 	"github.com/aquasecurity/trivy/pkg/misconf"
+	"github.com/aquasecurity/trivy/pkg/misconf/fix"
 	"github.com/aquasecurity/trivy/pkg/module"
 	"github.com/aquasecurity/trivy/pkg/policy"
+	"github.com/aquasecurity/trivy/pkg/reachability"
+	"github.com/aquasecurity/trivy/pkg/remote"
 	pkgReport "github.com/aquasecurity/trivy/pkg/report"
+	"github.com/aquasecurity/trivy/pkg/report/hook"
+	"github.com/aquasecurity/trivy/pkg/report/installed"
+	"github.com/aquasecurity/trivy/pkg/report/publish"
+	"github.com/aquasecurity/trivy/pkg/report/sqlexport"
+	"github.com/aquasecurity/trivy/pkg/report/webhook"
 	"github.com/aquasecurity/trivy/pkg/result"
+	"github.com/aquasecurity/trivy/pkg/rpc"
 	"github.com/aquasecurity/trivy/pkg/rpc/client"
 	"github.com/aquasecurity/trivy/pkg/scanner"
+	"github.com/aquasecurity/trivy/pkg/scanpolicy"
+	"github.com/aquasecurity/trivy/pkg/signature"
 	"github.com/aquasecurity/trivy/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/utils/fsutils"
 )
@@ -44,6 +70,7 @@ const (
 	TargetImageArchive   TargetKind = "archive"
 	TargetSBOM           TargetKind = "sbom"
 	TargetVM             TargetKind = "vm"
+	TargetLambda         TargetKind = "lambda"
 
 	devVersion = "dev"
 )
@@ -73,6 +100,9 @@ type ScannerConfig struct {
 
 	// Artifact options
 	ArtifactOption artifact.Option
+
+	// OnlineSource is nil unless '--osv-online' is specified
+	OnlineSource *advisory.OnlineSource
 }
 
 type Runner interface {
@@ -88,6 +118,8 @@ type Runner interface {
 	ScanSBOM(ctx context.Context, opts flag.Options) (types.Report, error)
 	// ScanVM scans VM
 	ScanVM(ctx context.Context, opts flag.Options) (types.Report, error)
+	// ScanLambda scans Lambda function
+	ScanLambda(ctx context.Context, opts flag.Options) (types.Report, error)
 	// Filter filter a report
 	Filter(ctx context.Context, opts flag.Options, report types.Report) (types.Report, error)
 	// Report a writes a report
@@ -100,6 +132,12 @@ type runner struct {
 	cache  cache.Cache
 	dbOpen bool
 
+	// The OSV.dev lookup enabled by '--osv-online'
+	online *advisory.OnlineSource
+
+	// The Pushgateway URL where the metrics are pushed on close
+	metricsPush string
+
 	// WASM modules
 	module *module.Manager
 }
@@ -122,10 +160,21 @@ func NewRunner(ctx context.Context, cliOptions flag.Options, opts ...runnerOptio
 		opt(r)
 	}
 
+	if cliOptions.MaxMemory > 0 {
+		// The garbage collector runs more often as the heap approaches the limit
+		debug.SetMemoryLimit(cliOptions.MaxMemory)
+	}
+
 	if err := r.initCache(cliOptions); err != nil {
 		return nil, xerrors.Errorf("cache error: %w", err)
 	}
 
+	if cliOptions.MetricsPush != "" {
+		r.metricsPush = cliOptions.MetricsPush
+		r.cache = metrics.NewCache(r.cache)
+		metrics.SetCacheDir(cliOptions.CacheDir)
+	}
+
 	// Update the vulnerability database if needed.
 	if err := r.initDB(ctx, cliOptions); err != nil {
 		return nil, xerrors.Errorf("DB error: %w", err)
@@ -156,11 +205,18 @@ func (r *runner) Close(ctx context.Context) error {
 		if err := db.Close(); err != nil {
 			errs = multierror.Append(errs, err)
 		}
+		advisory.DeregisterSources()
 	}
 
 	if err := r.module.Close(ctx); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+
+	if r.metricsPush != "" {
+		if err := metrics.Push(r.metricsPush); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
 	return errs
 }
 
@@ -184,7 +240,152 @@ func (r *runner) ScanImage(ctx context.Context, opts flag.Options) (types.Report
 		s = imageRemoteScanner
 	}
 
-	return r.scanArtifact(ctx, opts, s)
+	// Verify the signatures before pulling the image so that untrusted images are never analyzed
+	var sig *types.SignatureVerification
+	if opts.VerifySignature {
+		result, digest, err := verifySignature(ctx, opts)
+		if err != nil {
+			return types.Report{}, xerrors.Errorf("signature verification error: %w", err)
+		}
+		sig = &result
+		if digest != nil {
+			// Scan the verified digest, not the tag which might be moved meanwhile
+			opts.Target = digest.String()
+		}
+	}
+
+	report, err := r.scanArtifact(ctx, opts, s)
+	if err != nil {
+		return report, err
+	}
+	if sig != nil {
+		report.Metadata.Signature = sig
+	}
+
+	if opts.CompareBase == "" {
+		return report, nil
+	}
+	return r.compareWithBase(ctx, opts, report)
+}
+
+// verifySignature resolves the digest of the image in the registry and verifies its cosign signatures.
+// The image without valid signatures fails only with '--require-signature'.
+// The digest is returned to scan the same image as verified, or nil if it is not resolved.
+func verifySignature(ctx context.Context, opts flag.Options) (types.SignatureVerification, *name.Digest, error) {
+	if opts.Input != "" {
+		return types.SignatureVerification{}, nil, xerrors.New("signatures of image archives (--input) cannot be verified")
+	}
+
+	verifier, err := signature.NewCosignVerifier(opts.SignaturePolicy())
+	if err != nil {
+		return types.SignatureVerification{}, nil, xerrors.Errorf("verifier error: %w", err)
+	}
+
+	result, digest, err := verifyImageSignature(ctx, opts, verifier)
+	if err != nil {
+		log.Logger.Warnf("Unable to verify the signatures of %s: %s", opts.Target, err)
+		result = types.SignatureVerification{
+			Message: err.Error(),
+		}
+	}
+
+	if result.Verified {
+		log.Logger.Infof("Verified the signature of %s@%s", opts.Target, result.Digest)
+		return result, digest, nil
+	}
+	log.Logger.Warnf("No valid signature of %s: %s", opts.Target, result.Message)
+	if opts.RequireSignature {
+		return types.SignatureVerification{}, nil, xerrors.Errorf("%s: %w", result.Message, signature.ErrUnsigned)
+	}
+	return result, digest, nil
+}
+
+func verifyImageSignature(ctx context.Context, opts flag.Options,
+	verifier *signature.CosignVerifier) (types.SignatureVerification, *name.Digest, error) {
+	nameOpts := lo.Ternary(opts.Insecure, []name.Option{name.Insecure}, nil)
+	ref, err := name.ParseReference(opts.Target, nameOpts...)
+	if err != nil {
+		return types.SignatureVerification{}, nil, xerrors.Errorf("invalid image reference: %w", err)
+	}
+
+	digest, ok := ref.(name.Digest)
+	if !ok {
+		desc, err := remote.Get(ctx, ref, opts.RegistryOpts())
+		if err != nil {
+			return types.SignatureVerification{}, nil, xerrors.Errorf("unable to resolve the digest: %w", err)
+		}
+		digest = ref.Context().Digest(desc.Digest.String())
+	}
+
+	result, err := verifier.Verify(ctx, digest, opts.RegistryOpts())
+	if err != nil {
+		return types.SignatureVerification{}, nil, err
+	}
+	return result, &digest, nil
+}
+
+// compareWithBase scans the base image and removes the vulnerabilities and packages inherited from it
+func (r *runner) compareWithBase(ctx context.Context, opts flag.Options, report types.Report) (types.Report, error) {
+	log.Logger.Infof("Scanning the base image %s to compare...", opts.CompareBase)
+
+	baseOpts := opts
+	baseOpts.Target = opts.CompareBase
+	baseOpts.Input = ""
+	// All the packages are needed to compare packages without vulnerabilities
+	baseOpts.ListAllPkgs = true
+
+	s := imageStandaloneScanner
+	if opts.ServerAddr != "" {
+		s = imageRemoteScanner
+	}
+	base, err := r.scanArtifact(ctx, baseOpts, s)
+	if err != nil {
+		return types.Report{}, xerrors.Errorf("base image scan error: %w", err)
+	}
+	return delta.Subtract(report, base), nil
+}
+
+// ScanRegistryImage scans vulnerabilities of the container image in the registry with the vulnerability DB initialized by the caller.
+// It is used by the server, which doesn't have the Java DB, to render admission verdicts and run scan jobs.
+// The online source is nil unless the online lookup is enabled.
+func ScanRegistryImage(ctx context.Context, opts flag.Options, imageRef string, cacheClient cache.Cache,
+	online *advisory.OnlineSource) (types.Report, error) {
+	opts.Target = imageRef
+	opts.Input = ""
+	opts.ImageSources = ftypes.ImageSources{ftypes.RemoteImageSource}
+	opts.Scanners = types.Scanners{types.VulnerabilityScanner}
+	opts.VulnType = []string{
+		types.VulnTypeOS,
+		types.VulnTypeLibrary,
+	}
+	opts.NoProgress = true
+	opts.DisabledAnalyzers = append(slices.Clone(analyzer.TypeLockfiles), analyzer.TypeJar)
+
+	return scan(ctx, opts, imageStandaloneScanner, cacheClient, online)
+}
+
+// ScanManifest scans misconfigurations of the Kubernetes manifest with the built-in checks.
+// It is used by the server to review the workloads of admission requests.
+func ScanManifest(ctx context.Context, opts flag.Options, manifest []byte, cacheClient cache.Cache) (types.Report, error) {
+	dir, err := os.MkdirTemp("", "trivy-manifest-*")
+	if err != nil {
+		return types.Report{}, xerrors.Errorf("failed to create a temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = os.WriteFile(filepath.Join(dir, "manifest.yaml"), manifest, 0o600); err != nil {
+		return types.Report{}, xerrors.Errorf("failed to write the manifest: %w", err)
+	}
+
+	opts.Target = dir
+	opts.Input = ""
+	opts.Scanners = types.Scanners{types.MisconfigScanner}
+	opts.MisconfigScanners = []analyzer.Type{analyzer.TypeKubernetes}
+	opts.NoProgress = true
+	// The checks downloaded by clients are used if any, and the embedded checks otherwise
+	opts.SkipPolicyUpdate = true
+
+	return scan(ctx, opts, filesystemStandaloneScanner, cacheClient, nil)
 }
 
 func (r *runner) ScanFilesystem(ctx context.Context, opts flag.Options) (types.Report, error) {
@@ -263,28 +464,127 @@ func (r *runner) ScanVM(ctx context.Context, opts flag.Options) (types.Report, e
 	return r.scanArtifact(ctx, opts, s)
 }
 
+func (r *runner) ScanLambda(ctx context.Context, opts flag.Options) (types.Report, error) {
+	// Deployment packages contain installed packages, e.g. node_modules, like rootfs
+	opts.DisabledAnalyzers = append(opts.DisabledAnalyzers, analyzer.TypeLockfiles...)
+
+	var s InitializeScanner
+	if opts.ServerAddr == "" {
+		// Scan Lambda function in standalone mode
+		s = lambdaStandaloneScanner
+	} else {
+		// Scan Lambda function in client/server mode
+		s = lambdaRemoteScanner
+	}
+
+	return r.scanArtifact(ctx, opts, s)
+}
+
 func (r *runner) scanArtifact(ctx context.Context, opts flag.Options, initializeScanner InitializeScanner) (types.Report, error) {
-	report, err := scan(ctx, opts, initializeScanner, r.cache)
+	start := time.Now()
+	report, err := scan(ctx, opts, initializeScanner, r.cache, r.online)
+	metrics.ObserveScan(start, err)
 	if err != nil {
 		return types.Report{}, xerrors.Errorf("scan error: %w", err)
 	}
+	metrics.RecordFindings(report.Results)
 
 	return report, nil
 }
 
 func (r *runner) Filter(ctx context.Context, opts flag.Options, report types.Report) (types.Report, error) {
+	// EPSS and KEV must be filled before filtering as the severity may be derived from them
+	if opts.ExploitEnabled() {
+		if err := fillExploits(opts, report); err != nil {
+			return types.Report{}, xerrors.Errorf("exploit data error: %w", err)
+		}
+	}
+
+	// The reachability must be determined before filtering as unreachable vulnerabilities may be skipped
+	if opts.ReachabilitySymbols != "" {
+		if err := fillReachability(opts, report); err != nil {
+			return types.Report{}, xerrors.Errorf("reachability error: %w", err)
+		}
+	}
+
+	filterOpts := opts.FilterOpts()
+
+	// Scan policies published by the image producer are applied in addition to the local ones
+	if opts.ScanPolicyKey != "" && report.ArtifactType == ftypes.ArtifactContainerImage {
+		dir, err := os.MkdirTemp("", "trivy-scan-policy-*")
+		if err != nil {
+			return types.Report{}, xerrors.Errorf("failed to create a temp dir: %w", err)
+		}
+		defer os.RemoveAll(dir)
+
+		if filterOpts.ScanPolicyDirs, err = discoverScanPolicies(ctx, opts, report, dir); err != nil {
+			return types.Report{}, xerrors.Errorf("scan policy error: %w", err)
+		}
+	}
+
 	// Filter results
-	if err := result.Filter(ctx, report, opts.FilterOpts()); err != nil {
+	if err := result.Filter(ctx, report, filterOpts); err != nil {
 		return types.Report{}, xerrors.Errorf("filtering error: %w", err)
 	}
 	return report, nil
 }
 
+// discoverScanPolicies downloads the signed scan policy bundles attached to the repo digests of the image
+func discoverScanPolicies(ctx context.Context, opts flag.Options, report types.Report, dir string) ([]string, error) {
+	verifier, err := signature.NewVerifier(opts.ScanPolicyKey)
+	if err != nil {
+		return nil, xerrors.Errorf("verifier error: %w", err)
+	}
+
+	var dirs []string
+	nameOpts := lo.Ternary(opts.Insecure, []name.Option{name.Insecure}, nil)
+	for _, rd := range report.Metadata.RepoDigests {
+		digest, err := name.NewDigest(rd, nameOpts...)
+		if err != nil {
+			log.Logger.Debugf("Invalid repo digest (%s): %s", rd, err)
+			continue
+		}
+		// The referrers may not be available, e.g. the registry doesn't support the referrers API
+		found, err := scanpolicy.Discover(ctx, digest, verifier, dir, opts.RegistryOpts())
+		if err != nil {
+			log.Logger.Warnf("Unable to discover scan policies of %s: %s", rd, err)
+			continue
+		}
+		dirs = append(dirs, found...)
+	}
+	return lo.Uniq(dirs), nil
+}
+
 func (r *runner) Report(ctx context.Context, opts flag.Options, report types.Report) error {
 	if err := pkgReport.Write(ctx, report, opts); err != nil {
 		return xerrors.Errorf("unable to write results: %w", err)
 	}
 
+	if opts.InstalledManifestDir != "" {
+		if _, err := installed.Write(opts.InstalledManifestDir, report); err != nil {
+			return xerrors.Errorf("unable to write installed package manifests: %w", err)
+		}
+	}
+
+	if opts.SQLExport != "" {
+		if err := sqlexport.Export(ctx, opts.SQLExport, report); err != nil {
+			return xerrors.Errorf("SQL export error: %w", err)
+		}
+	}
+
+	if err := publish.Publish(ctx, report, opts.PublishOpts()); err != nil {
+		return xerrors.Errorf("publish error: %w", err)
+	}
+
+	// The notification doesn't fail the scan as the report has already been written
+	notifier, err := webhook.NewNotifier(opts.WebhookOpts())
+	if err != nil {
+		return xerrors.Errorf("webhook error: %w", err)
+	}
+	if err = notifier.Notify(ctx, report); err != nil {
+		log.Logger.Warnf("Unable to send the webhook notification: %s", err)
+	}
+
 	return nil
 }
 
@@ -293,6 +593,10 @@ func (r *runner) initDB(ctx context.Context, opts flag.Options) error {
 		return err
 	}
 
+	if err := r.initExploitDB(ctx, opts); err != nil {
+		return err
+	}
+
 	// When scanning config files or running as client mode, it doesn't need to download the vulnerability database.
 	if opts.ServerAddr != "" || !opts.Scanners.Enabled(types.VulnerabilityScanner) {
 		return nil
@@ -313,6 +617,19 @@ func (r *runner) initDB(ctx context.Context, opts flag.Options) error {
 	}
 	r.dbOpen = true
 
+	if err := advisory.Load(opts.AdvisorySources); err != nil {
+		return xerrors.Errorf("advisory source error: %w", err)
+	}
+
+	if opts.OSVOnline {
+		r.online = advisory.NewOnlineSource(advisory.OnlineOptions{
+			URL:      opts.OSVAPIURL,
+			CacheDir: opts.CacheDir,
+		})
+		// Registered so that vulnerability details are filled in
+		advisory.RegisterSource(r.online)
+	}
+
 	return nil
 }
 
@@ -330,7 +647,7 @@ func (r *runner) initJavaDB(opts flag.Options) error {
 
 	// Update the Java DB
 	noProgress := opts.Quiet || opts.NoProgress
-	javadb.Init(opts.CacheDir, opts.JavaDBRepository, opts.SkipJavaDBUpdate, noProgress, opts.RegistryOpts())
+	javadb.Init(opts.CacheDir, opts.JavaDBRepository, opts.SkipJavaDBUpdate, opts.JavaDBAllowStale, opts.JavaDBShards, noProgress, opts.RegistryOpts())
 	if opts.DownloadJavaDBOnly {
 		if err := javadb.Update(); err != nil {
 			return xerrors.Errorf("Java DB error: %w", err)
@@ -341,6 +658,18 @@ func (r *runner) initJavaDB(opts flag.Options) error {
 	return nil
 }
 
+func (r *runner) initExploitDB(ctx context.Context, opts flag.Options) error {
+	// When running as server mode, the exploit data is filled in by clients.
+	if opts.Listen != "" || !opts.Scanners.Enabled(types.VulnerabilityScanner) || !opts.ExploitEnabled() {
+		return nil
+	}
+
+	if err := operation.DownloadExploitDB(ctx, opts.CacheDir, opts.SkipDBUpdate); err != nil {
+		return xerrors.Errorf("exploit data error: %w", err)
+	}
+	return nil
+}
+
 func (r *runner) initCache(opts flag.Options) error {
 	// Skip initializing cache when custom cache is passed
 	if r.cache != nil {
@@ -349,7 +678,11 @@ func (r *runner) initCache(opts flag.Options) error {
 
 	// client/server mode
 	if opts.ServerAddr != "" {
-		remoteCache := tcache.NewRemoteCache(opts.ServerAddr, opts.CustomHeaders, opts.Insecure)
+		tlsConfig, err := clientTLSConfig(opts)
+		if err != nil {
+			return err
+		}
+		remoteCache := tcache.NewRemoteCache(opts.ServerAddr, opts.CustomHeaders, tlsConfig)
 		r.cache = tcache.NopCache(remoteCache)
 		return nil
 	}
@@ -386,6 +719,12 @@ func (r *runner) initCache(opts flag.Options) error {
 		if err = cacheClient.ClearArtifacts(); err != nil {
 			return xerrors.Errorf("cache clear error: %w", err)
 		}
+		if err = tcache.ClearResults(fsutils.CacheDir()); err != nil {
+			return xerrors.Errorf("cache clear error: %w", err)
+		}
+		if err = os.RemoveAll(incrementalDir()); err != nil {
+			return xerrors.Errorf("failed to remove the manifests for incremental scanning: %w", err)
+		}
 		return SkipScan
 	}
 
@@ -393,15 +732,60 @@ func (r *runner) initCache(opts flag.Options) error {
 	return nil
 }
 
+// incrementalDir returns the directory of the manifests for '--incremental'
+func incrementalDir() string {
+	return filepath.Join(fsutils.CacheDir(), "incremental")
+}
+
+// clientTLSConfig returns the TLS config to connect to the server in client/server mode
+func clientTLSConfig(opts flag.Options) (*tls.Config, error) {
+	tlsConfig, err := rpc.NewClientTLSConfig(rpc.TLSOptions{
+		CACert:    opts.TLSCACert,
+		Cert:      opts.TLSCert,
+		Key:       opts.TLSKey,
+		SPIFFEIDs: opts.TLSSPIFFEIDs,
+		Insecure:  opts.Insecure,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("TLS config error: %w", err)
+	}
+	return tlsConfig, nil
+}
+
+// layerMemoryLimit returns the memory limit of file buffers for each layer.
+// Unless '--layer-memory-limit' is specified, a quarter of '--max-memory' is shared by the layers analyzed in parallel.
+func layerMemoryLimit(opts flag.Options) int64 {
+	if opts.LayerMemoryLimit > 0 || opts.MaxMemory <= 0 {
+		return opts.LayerMemoryLimit
+	}
+	return opts.MaxMemory / 4 / int64(max(opts.Parallel, 1))
+}
+
 // Run performs artifact scanning
 func Run(ctx context.Context, opts flag.Options, targetKind TargetKind) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
+	var reported bool
 	defer func() {
+		if err == nil {
+			return
+		}
 		if errors.Is(err, context.DeadlineExceeded) {
 			log.Logger.Warn("Increase --timeout value")
 		}
+
+		// Surface the cause of the failure as a stable error code
+		scanErr := ClassifyError(err)
+		if scanErr.Hint != "" {
+			log.Logger.Warn(scanErr.Hint)
+		}
+		if !reported {
+			if rerr := writeErrorReport(ctx, opts, scanErr); rerr != nil {
+				log.Logger.Warnf("Unable to write the error to the report: %s", rerr)
+			}
+		}
+		err = xerrors.Errorf("%s: %w", scanErr.Code, err)
 	}()
 
 	if opts.GenerateDefaultConfig {
@@ -409,6 +793,17 @@ func Run(ctx context.Context, opts flag.Options, targetKind TargetKind) (err err
 		return viper.SafeWriteConfigAs("trivy-default.yaml")
 	}
 
+	// The time budget must expire before the hard timeout to get partial results
+	if opts.Budget.Time > 0 && opts.Budget.Time >= opts.Timeout {
+		log.Logger.Warnf("The time budget (%s) should be shorter than '--timeout' (%s)", opts.Budget.Time, opts.Timeout)
+	}
+
+	var collector *debugreport.Collector
+	if opts.DebugReport != "" {
+		collector = debugreport.NewCollector()
+		ctx = debugreport.With(ctx, collector)
+	}
+
 	r, err := NewRunner(ctx, opts)
 	if err != nil {
 		if errors.Is(err, SkipScan) {
@@ -444,6 +839,17 @@ func Run(ctx context.Context, opts flag.Options, targetKind TargetKind) (err err
 		if report, err = r.ScanVM(ctx, opts); err != nil {
 			return xerrors.Errorf("vm scan error: %w", err)
 		}
+	case TargetLambda:
+		if report, err = r.ScanLambda(ctx, opts); err != nil {
+			return xerrors.Errorf("lambda scan error: %w", err)
+		}
+	}
+
+	if collector != nil {
+		if err = collector.Write(opts.DebugReport); err != nil {
+			return xerrors.Errorf("debug report error: %w", err)
+		}
+		log.Logger.Infof("The debug report has been written to %s", opts.DebugReport)
 	}
 
 	report, err = r.Filter(ctx, opts, report)
@@ -451,16 +857,119 @@ func Run(ctx context.Context, opts flag.Options, targetKind TargetKind) (err err
 		return xerrors.Errorf("filter error: %w", err)
 	}
 
-	if err = r.Report(ctx, opts, report); err != nil {
+	// The hooks run before writing and exiting so that the filtering by them is also applied to '--exit-code'
+	report, err = hook.Run(ctx, report, opts.ReportHooks)
+	if err != nil {
+		return xerrors.Errorf("report hook error: %w", err)
+	}
+
+	reported = true
+	if opts.FixDryRun {
+		err = writeFixes(ctx, opts, report)
+	} else {
+		err = r.Report(ctx, opts, report)
+	}
+	if err != nil {
 		return xerrors.Errorf("report error: %w", err)
 	}
 
 	operation.ExitOnEOL(opts, report.Metadata)
+	if opts.ResultPolicy != "" {
+		return operation.ExitOnResultPolicy(ctx, opts, report)
+	}
 	operation.Exit(opts, report.Results.Failed())
 
 	return nil
 }
 
+// writeErrorReport writes the JSON report with the error in the metadata so that wrappers can read the cause of the failure
+func writeErrorReport(ctx context.Context, opts flag.Options, scanErr types.Error) error {
+	if opts.Compliance.Spec.ID != "" {
+		return nil
+	}
+	// Only the JSON output gets the error when '--format' is specified multiple times
+	o, ok := lo.Find(append([]flag.ReportOutput{{Format: opts.Format, Output: opts.Output}}, opts.ExtraOutputs...),
+		func(o flag.ReportOutput) bool {
+			return o.Format == types.FormatJSON
+		})
+	if !ok {
+		return nil
+	}
+	opts.Format, opts.Output, opts.ExtraOutputs = o.Format, o.Output, nil
+
+	report := types.Report{
+		SchemaVersion: pkgReport.SchemaVersion,
+		CreatedAt:     clock.Now(ctx),
+		ArtifactName:  opts.Target,
+		Metadata: types.Metadata{
+			Error: &scanErr,
+		},
+	}
+	// The summary of a failed scan would be a misleading PASS, so only '--output' gets the error
+	if opts.Summary {
+		if opts.Output == "" {
+			return nil
+		}
+		opts.Summary = false
+	}
+	return pkgReport.Write(ctx, report, opts)
+}
+
+// writeFixes writes patches fixing the detected misconfigurations instead of the report
+func writeFixes(ctx context.Context, opts flag.Options, report types.Report) (err error) {
+	root := opts.Target
+	if fi, err := os.Stat(root); err == nil && !fi.IsDir() {
+		root = filepath.Dir(root)
+	}
+
+	patches, err := fix.Generate(os.DirFS(root), report.Results)
+	if err != nil {
+		return xerrors.Errorf("unable to generate fixes: %w", err)
+	}
+
+	output, cleanup, err := opts.OutputWriter(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to create a file: %w", err)
+	}
+	defer func() {
+		if cerr := cleanup(); cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+	}()
+
+	return fix.Write(output, patches)
+}
+
+// fillExploits fills in EPSS and CISA KEV of the detected vulnerabilities
+func fillExploits(opts flag.Options, report types.Report) error {
+	db, err := exploit.NewClient(opts.CacheDir).Load()
+	if err != nil {
+		return xerrors.Errorf("unable to load the exploit data: %w", err)
+	}
+
+	for _, res := range report.Results {
+		db.Fill(res.Vulnerabilities)
+		if opts.SeveritySource == flag.SeveritySourceEPSS {
+			exploit.SetSeverity(res.Vulnerabilities)
+		}
+	}
+	return nil
+}
+
+// fillReachability determines the reachability of vulnerabilities in Go and Java packages.
+// It requires the files of the scan target, so only local filesystems and repositories are supported.
+func fillReachability(opts flag.Options, report types.Report) error {
+	if report.ArtifactType != ftypes.ArtifactFilesystem && report.ArtifactType != ftypes.ArtifactRepository {
+		log.Logger.Warnf("Reachability analysis is not supported for %s", report.ArtifactType)
+		return nil
+	}
+	if fi, err := os.Stat(opts.Target); err != nil || !fi.IsDir() {
+		log.Logger.Warn("Reachability analysis is supported only for local directories")
+		return nil
+	}
+	return reachability.Fill(opts.Target, opts.ReachabilitySymbols, report.Results)
+}
+
 func disabledAnalyzers(opts flag.Options) []analyzer.Type {
 	// Specified analyzers to be disabled depending on scanning modes
 	// e.g. The 'image' subcommand should disable the lock file scanning.
@@ -492,6 +1001,7 @@ func disabledAnalyzers(opts flag.Options) []analyzer.Type {
 	// Do not perform misconfiguration scanning when it is not specified.
 	if !opts.Scanners.AnyEnabled(types.MisconfigScanner, types.RBACScanner) {
 		analyzers = append(analyzers, analyzer.TypeConfigFiles...)
+		analyzers = append(analyzers, analyzer.TypeTrustStore, analyzer.TypeCertificate, analyzer.TypeSSH, analyzer.TypeCloudInit, analyzer.TypeBootConfig)
 	}
 
 	// Scanning file headers and license files is expensive.
@@ -532,7 +1042,8 @@ func filterMisconfigAnalyzers(included, all []analyzer.Type) ([]analyzer.Type, e
 	return lo.Without(all, included...), nil
 }
 
-func initScannerConfig(opts flag.Options, cacheClient cache.Cache) (ScannerConfig, types.ScanOptions, error) {
+func initScannerConfig(opts flag.Options, cacheClient cache.Cache, online *advisory.OnlineSource) (
+	ScannerConfig, types.ScanOptions, error) {
 	target := opts.Target
 	if opts.Input != "" {
 		target = opts.Input
@@ -584,7 +1095,7 @@ func initScannerConfig(opts flag.Options, cacheClient cache.Cache) (ScannerConfi
 
 		var downloadedPolicyPaths []string
 		var disableEmbedded bool
-		downloadedPolicyPaths, err := operation.InitBuiltinPolicies(context.Background(), opts.CacheDir, opts.Quiet, opts.SkipPolicyUpdate, opts.MisconfOptions.PolicyBundleRepository)
+		downloadedPolicyPaths, err := operation.InitBuiltinPolicies(context.Background(), opts.CacheDir, opts.Quiet, opts.SkipPolicyUpdate, opts.MisconfOptions.PolicyBundleRepository, opts.RegistryOpts())
 		if err != nil {
 			if !opts.SkipPolicyUpdate {
 				log.Logger.Errorf("Falling back to embedded policies: %s", err)
@@ -593,6 +1104,16 @@ func initScannerConfig(opts flag.Options, cacheClient cache.Cache) (ScannerConfi
 			log.Logger.Debug("Policies successfully loaded from disk")
 			disableEmbedded = true
 		}
+
+		crdSchemas := opts.K8sCRDSchemas
+		if opts.K8sCRDSchemasFromCluster {
+			crdPath, err := saveClusterCRDs(opts)
+			if err != nil {
+				return ScannerConfig{}, types.ScanOptions{}, xerrors.Errorf("unable to fetch CRDs from the cluster: %w", err)
+			}
+			crdSchemas = append(crdSchemas, crdPath)
+		}
+
 		configScannerOptions = misconf.ScannerOption{
 			Debug:                    opts.Debug,
 			Trace:                    opts.Trace,
@@ -603,9 +1124,12 @@ func initScannerConfig(opts flag.Options, cacheClient cache.Cache) (ScannerConfi
 			HelmValueFiles:           opts.HelmValueFiles,
 			HelmFileValues:           opts.HelmFileValues,
 			HelmStringValues:         opts.HelmStringValues,
+			HelmDependencyCacheDir:   filepath.Join(fsutils.CacheDir(), "helm"),
+			HelmDownloadDeps:         opts.HelmDownloadDeps && !opts.OfflineScan,
 			TerraformTFVars:          opts.TerraformTFVars,
 			CloudFormationParamVars:  opts.CloudFormationParamVars,
 			K8sVersion:               opts.K8sVersion,
+			K8sCRDSchemas:            crdSchemas,
 			DisableEmbeddedPolicies:  disableEmbedded,
 			DisableEmbeddedLibraries: disableEmbedded,
 			TfExcludeDownloaded:      opts.TfExcludeDownloaded,
@@ -620,6 +1144,7 @@ func initScannerConfig(opts flag.Options, cacheClient cache.Cache) (ScannerConfi
 		log.Logger.Infof("Please see also https://aquasecurity.github.io/trivy/%s/docs/scanner/secret/#recommendation for faster secret detection", ver)
 	} else {
 		opts.SecretConfigPath = ""
+		opts.SecretFingerprintSalt = ""
 	}
 
 	if opts.Scanners.Enabled(types.LicenseScanner) {
@@ -630,20 +1155,43 @@ func initScannerConfig(opts flag.Options, cacheClient cache.Cache) (ScannerConfi
 		}
 	}
 
+	var tlsConfig *tls.Config
+	if opts.ServerAddr != "" {
+		var err error
+		if tlsConfig, err = clientTLSConfig(opts); err != nil {
+			return ScannerConfig{}, types.ScanOptions{}, err
+		}
+	}
+
 	// SPDX needs to calculate digests for package files
 	var fileChecksum bool
-	if opts.Format == types.FormatSPDXJSON || opts.Format == types.FormatSPDX {
-		fileChecksum = true
+	for _, format := range opts.Formats() {
+		if format == types.FormatSPDXJSON || format == types.FormatSPDX {
+			fileChecksum = true
+		}
+	}
+
+	// The manifests of '--incremental' are stored in the cache dir
+	var incDir string
+	if opts.Incremental {
+		incDir = incrementalDir()
+	}
+
+	luksKeys, err := opts.LUKSKeys()
+	if err != nil {
+		return ScannerConfig{}, types.ScanOptions{}, err
 	}
 
 	return ScannerConfig{
 		Target:             target,
 		ArtifactCache:      cacheClient,
 		LocalArtifactCache: cacheClient,
+		OnlineSource:       online,
 		ServerOption: client.ScannerOption{
 			RemoteURL:     opts.ServerAddr,
 			CustomHeaders: opts.CustomHeaders,
 			Insecure:      opts.Insecure,
+			TLSConfig:     tlsConfig,
 		},
 		ArtifactOption: artifact.Option{
 			DisabledAnalyzers: disabledAnalyzers(opts),
@@ -656,6 +1204,9 @@ func initScannerConfig(opts flag.Options, cacheClient cache.Cache) (ScannerConfi
 			RepoBranch:        opts.RepoBranch,
 			RepoCommit:        opts.RepoCommit,
 			RepoTag:           opts.RepoTag,
+			ChangedFrom:       opts.ChangedFrom,
+			IncrementalDir:    incDir,
+			Budget:            opts.Budget,
 			SBOMSources:       opts.SBOMSources,
 			RekorURL:          opts.RekorURL,
 			//Platform:          opts.Platform,
@@ -670,15 +1221,19 @@ func initScannerConfig(opts flag.Options, cacheClient cache.Cache) (ScannerConfi
 				DockerOptions: ftypes.DockerOptions{
 					Host: opts.DockerHost,
 				},
-				ImageSources: opts.ImageSources,
+				ImageSources:     opts.ImageSources,
+				LayerMemoryLimit: layerMemoryLimit(opts),
 			},
+			SignaturePolicy: opts.SignaturePolicy(),
+			LUKSKeys:        luksKeys,
 
 			// For misconfiguration scanning
 			MisconfScannerOption: configScannerOptions,
 
 			// For secret scanning
 			SecretScannerOption: analyzer.SecretScannerOption{
-				ConfigPath: opts.SecretConfigPath,
+				ConfigPath:      opts.SecretConfigPath,
+				FingerprintSalt: opts.SecretFingerprintSalt,
 			},
 
 			// For license scanning
@@ -690,9 +1245,9 @@ func initScannerConfig(opts flag.Options, cacheClient cache.Cache) (ScannerConfi
 	}, scanOptions, nil
 }
 
-func scan(ctx context.Context, opts flag.Options, initializeScanner InitializeScanner, cacheClient cache.Cache) (
-	types.Report, error) {
-	scannerConfig, scanOptions, err := initScannerConfig(opts, cacheClient)
+func scan(ctx context.Context, opts flag.Options, initializeScanner InitializeScanner, cacheClient cache.Cache,
+	online *advisory.OnlineSource) (types.Report, error) {
+	scannerConfig, scanOptions, err := initScannerConfig(opts, cacheClient, online)
 	if err != nil {
 		return types.Report{}, err
 	}
@@ -702,6 +1257,12 @@ func scan(ctx context.Context, opts flag.Options, initializeScanner InitializeSc
 	}
 	defer cleanup()
 
+	if opts.ResultCache {
+		if s, err = withResultCache(s, opts); err != nil {
+			return types.Report{}, err
+		}
+	}
+
 	report, err := s.ScanArtifact(ctx, scanOptions)
 	if err != nil {
 		return types.Report{}, xerrors.Errorf("scan failed: %w", err)
@@ -709,6 +1270,41 @@ func scan(ctx context.Context, opts flag.Options, initializeScanner InitializeSc
 	return report, nil
 }
 
+// withResultCache enables the result cache, which is invalidated when the vulnerability DB is updated
+func withResultCache(s scanner.Scanner, opts flag.Options) (scanner.Scanner, error) {
+	// The vulnerability DB is managed by the server in client/server mode
+	if opts.ServerAddr != "" {
+		log.Logger.Warn("'--result-cache' is not available in client/server mode")
+		return s, nil
+	}
+
+	var dbUpdatedAt time.Time
+	if meta, err := metadata.NewClient(opts.CacheDir).Get(); err == nil {
+		dbUpdatedAt = meta.UpdatedAt
+	}
+	resultCache, err := tcache.NewResultCache(opts.CacheDir, opts.CacheTTL, dbUpdatedAt)
+	if err != nil {
+		return s, xerrors.Errorf("unable to initialize the result cache: %w", err)
+	}
+	return s.WithResultCache(resultCache), nil
+}
+
+// saveClusterCRDs saves the CRDs of the cluster to the cache directory, so that they are a part of the cache key
+func saveClusterCRDs(opts flag.Options) (string, error) {
+	cluster, err := k8s.GetCluster(
+		k8s.WithContext(opts.K8sOptions.ClusterContext),
+		k8s.WithKubeConfig(opts.K8sOptions.KubeConfig),
+	)
+	if err != nil {
+		return "", xerrors.Errorf("failed getting k8s cluster: %w", err)
+	}
+	crdPath := filepath.Join(fsutils.CacheDir(), "k8s", "crds.json")
+	if err = k8sRep.SaveCRDs(context.Background(), cluster, crdPath); err != nil {
+		return "", err
+	}
+	return crdPath, nil
+}
+
 func canonicalVersion(ver string) string {
 	if ver == devVersion {
 		return ver
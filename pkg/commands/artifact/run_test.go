@@ -1,9 +1,27 @@
 package artifact
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/signature"
 )
 
 func TestCanonicalVersion(t *testing.T) {
@@ -46,3 +64,133 @@ func TestCanonicalVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestLayerMemoryLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		opts flag.Options
+		want int64
+	}{
+		{
+			name: "no limit",
+			want: 0,
+		},
+		{
+			name: "layer memory limit",
+			opts: flag.Options{
+				ImageOptions: flag.ImageOptions{LayerMemoryLimit: 100 << 20},
+				ScanOptions:  flag.ScanOptions{MaxMemory: 512 << 20, Parallel: 2},
+			},
+			want: 100 << 20,
+		},
+		{
+			name: "derived from max memory",
+			opts: flag.Options{
+				ScanOptions: flag.ScanOptions{MaxMemory: 512 << 20, Parallel: 2},
+			},
+			want: 64 << 20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := layerMemoryLimit(tt.opts)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	ts := httptest.NewServer(registry.New())
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	// Push an unsigned image
+	tag, err := name.NewTag(host + "/app:1.0")
+	require.NoError(t, err)
+	img, err := random.Image(128, 1)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(tag, img))
+	d, err := img.Digest()
+	require.NoError(t, err)
+	digest := tag.Context().Digest(d.String())
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "cosign.pub")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	tests := []struct {
+		name        string
+		target      string
+		input       string
+		require     bool
+		wantMessage string
+		wantDigest  string
+		wantErr     error
+		wantErrMsg  string
+	}{
+		{
+			name:        "digest resolved from the tag",
+			target:      tag.String(),
+			wantMessage: "no signature found",
+			wantDigest:  digest.String(),
+		},
+		{
+			name:        "digest",
+			target:      digest.String(),
+			wantMessage: "no signature found",
+			wantDigest:  digest.String(),
+		},
+		{
+			name:    "signature required",
+			target:  tag.String(),
+			require: true,
+			wantErr: signature.ErrUnsigned,
+		},
+		{
+			name:        "image not in the registry",
+			target:      host + "/missing:1.0",
+			wantMessage: "unable to resolve the digest",
+		},
+		{
+			name:       "image archive",
+			input:      "alpine.tar",
+			wantErrMsg: "signatures of image archives (--input) cannot be verified",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := flag.Options{
+				GlobalOptions: flag.GlobalOptions{Insecure: true},
+				ImageOptions:  flag.ImageOptions{Input: tt.input},
+				ScanOptions:   flag.ScanOptions{Target: tt.target},
+				SignatureOptions: flag.SignatureOptions{
+					VerifySignature:  true,
+					SignatureKey:     keyPath,
+					RequireSignature: tt.require,
+				},
+			}
+			got, gotDigest, err := verifySignature(context.Background(), opts)
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			case tt.wantErrMsg != "":
+				require.ErrorContains(t, err, tt.wantErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.False(t, got.Verified)
+			assert.Contains(t, got.Message, tt.wantMessage)
+			if tt.wantDigest == "" {
+				assert.Nil(t, gotDigest)
+				return
+			}
+			require.NotNil(t, gotDigest)
+			assert.Equal(t, tt.wantDigest, gotDigest.String())
+		})
+	}
+}
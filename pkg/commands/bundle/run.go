@@ -0,0 +1,93 @@
+package bundle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/bundle"
+	"github.com/aquasecurity/trivy/pkg/commands/operation"
+	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/javadb"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/policy"
+)
+
+// Create updates the databases and checks in the cache, and packages them into the bundle with the binary
+func Create(ctx context.Context, opts flag.Options, output string) error {
+	if err := operation.DownloadDB(ctx, opts.AppVersion, opts.CacheDir, opts.DBRepository, opts.Quiet,
+		opts.SkipDBUpdate, opts.RegistryOpts()); err != nil {
+		return err
+	}
+
+	// The bundle must not contain an outdated Java DB
+	javadb.Init(opts.CacheDir, opts.JavaDBRepository, opts.SkipJavaDBUpdate, false, false, opts.Quiet, opts.RegistryOpts())
+	if err := javadb.Update(); err != nil {
+		return xerrors.Errorf("Java DB error: %w", err)
+	}
+
+	if _, err := operation.InitBuiltinPolicies(ctx, opts.CacheDir, opts.Quiet, opts.SkipPolicyUpdate,
+		opts.PolicyBundleRepository, opts.RegistryOpts()); err != nil {
+		return xerrors.Errorf("failed to initialize built-in policies: %w", err)
+	}
+
+	// Make sure the checks are the same as the downloaded ones before bundling them
+	c, err := policy.NewClient(opts.CacheDir, opts.Quiet, opts.PolicyBundleRepository)
+	if err != nil {
+		return xerrors.Errorf("policy client error: %w", err)
+	}
+	if err = c.Verify(); err != nil {
+		return xerrors.Errorf("policy bundle verification failed: %w", err)
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		return xerrors.Errorf("unable to find the binary: %w", err)
+	}
+
+	log.Logger.Infof("Creating the bundle %s...", output)
+	if err = bundle.Create(output, bundle.CreateOption{
+		TrivyVersion:    opts.AppVersion,
+		Binary:          binary,
+		CacheDir:        opts.CacheDir,
+		Templates:       opts.BundleTemplates,
+		ComplianceSpecs: opts.BundleComplianceSpecs,
+	}); err != nil {
+		return xerrors.Errorf("bundle error: %w", err)
+	}
+	log.Logger.Infof("The bundle has been created: %s", output)
+	return nil
+}
+
+// Install verifies the bundle and lays it out into the cache and install directories
+func Install(ctx context.Context, opts flag.Options, bundlePath string) error {
+	manifest, err := bundle.Install(bundlePath, bundle.InstallOption{
+		CacheDir:   opts.CacheDir,
+		InstallDir: opts.BundleInstallDir,
+	})
+	if err != nil {
+		return xerrors.Errorf("bundle error: %w", err)
+	}
+
+	// Pin the checks as vendored ones so that scans don't try to update them.
+	// The repository is not used as the bundle is already in the cache.
+	c, err := policy.NewClient(opts.CacheDir, opts.Quiet, "")
+	if err != nil {
+		return xerrors.Errorf("policy client error: %w", err)
+	}
+	if err = c.Verify(); err != nil {
+		return xerrors.Errorf("policy bundle verification failed: %w", err)
+	}
+	if err = c.Pin(ctx, ""); err != nil {
+		return xerrors.Errorf("unable to pin the policy bundle: %w", err)
+	}
+
+	log.Logger.Infof("The bundle of Trivy %s has been installed", manifest.TrivyVersion)
+	log.Logger.Infof("Binary, templates and compliance specs: %s", opts.BundleInstallDir)
+	log.Logger.Infof("Databases and checks: %s", opts.CacheDir)
+	log.Logger.Infof("Run %s with '--skip-db-update --skip-java-db-update' so that it doesn't try to update the databases",
+		filepath.Join(opts.BundleInstallDir, "bin", "trivy"))
+	return nil
+}
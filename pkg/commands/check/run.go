@@ -0,0 +1,85 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/open-policy-agent/opa/tester"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/iac/rego"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/misconf"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Test runs the tests of the custom checks in the paths and reports the results
+func Test(ctx context.Context, opts flag.Options, paths []string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	policyFS, policyPaths, err := misconf.CreatePolicyFS(paths)
+	if err != nil {
+		return xerrors.Errorf("policy filesystem error: %w", err)
+	}
+
+	// Fixture inputs next to the checks are loaded as well as "opa test" does
+	dataPaths := opts.DataPaths
+	for _, p := range paths {
+		if fi, err := os.Stat(p); err == nil && fi.IsDir() {
+			dataPaths = append(dataPaths, p)
+		}
+	}
+	dataFS, dataPaths, err := misconf.CreateDataFS(dataPaths)
+	if err != nil {
+		return xerrors.Errorf("data filesystem error: %w", err)
+	}
+
+	log.Logger.Debugf("Running the tests of checks in %v", paths)
+	results, err := rego.RunTests(ctx, policyFS, policyPaths, dataFS, dataPaths, rego.TestOptions{
+		Filter: opts.TestFilter,
+		Trace:  opts.Trace,
+	})
+	if err != nil {
+		return xerrors.Errorf("test error: %w", err)
+	}
+
+	w, cleanup, err := opts.OutputWriter(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to create a file: %w", err)
+	}
+	defer func() {
+		if cerr := cleanup(); cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+	}()
+
+	var reporter tester.Reporter = tester.PrettyReporter{
+		Output:      w,
+		FailureLine: true,
+	}
+	if opts.Format == types.FormatJSON {
+		reporter = tester.JSONReporter{Output: w}
+	}
+
+	ch := make(chan *tester.Result, len(results))
+	var failures int
+	for _, result := range results {
+		if result.Fail || result.Error != nil {
+			failures++
+		}
+		ch <- result
+	}
+	close(ch)
+
+	if err = reporter.Report(ch); err != nil {
+		return xerrors.Errorf("report error: %w", err)
+	}
+
+	if failures > 0 {
+		return xerrors.Errorf("%d of %d tests failed", failures, len(results))
+	}
+	return nil
+}
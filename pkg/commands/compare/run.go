@@ -0,0 +1,104 @@
+package compare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/compare"
+	"github.com/aquasecurity/trivy/pkg/compare/ecr"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Run compares the vulnerabilities in the Trivy JSON report with the findings of ECR image scanning
+func Run(ctx context.Context, opts flag.Options) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	f, err := os.Open(opts.Target)
+	if err != nil {
+		return xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	var r types.Report
+	if err = json.NewDecoder(f).Decode(&r); err != nil {
+		return xerrors.Errorf("json decode error: %w", err)
+	}
+
+	if r.ArtifactType != "" && r.ArtifactType != ftypes.ArtifactContainerImage {
+		return xerrors.Errorf("only container image reports can be compared with ECR: %s", r.ArtifactType)
+	}
+
+	image, err := ecr.ParseImage(r)
+	if err != nil {
+		return xerrors.Errorf("ECR image error: %w", err)
+	}
+
+	// The region of the registry is used by default
+	region := opts.Region
+	if region == "" {
+		region = image.Region
+	}
+	importer, err := ecr.NewImporter(ctx, region, opts.Endpoint)
+	if err != nil {
+		return xerrors.Errorf("ECR importer error: %w", err)
+	}
+
+	log.Logger.Infof("Importing the ECR scan findings of %s...", image)
+	findings, err := importer.Findings(ctx, image)
+	if err != nil {
+		return xerrors.Errorf("unable to import ECR findings: %w", err)
+	}
+
+	comparison := compare.Compare(filterReport(r, opts.Severities), ecr.Source, filterFindings(findings, opts.Severities))
+
+	w, cleanup, err := opts.OutputWriter(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to create a file: %w", err)
+	}
+	defer func() {
+		if cerr := cleanup(); cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+	}()
+
+	if err = compare.Write(w, comparison, opts.Format); err != nil {
+		return xerrors.Errorf("unable to write the comparison: %w", err)
+	}
+	return nil
+}
+
+// filterReport removes the vulnerabilities of the severities which are not specified
+func filterReport(r types.Report, severities []dbTypes.Severity) types.Report {
+	for i, result := range r.Results {
+		r.Results[i].Vulnerabilities = lo.Filter(result.Vulnerabilities, func(v types.DetectedVulnerability, _ int) bool {
+			return hasSeverity(v.Severity, severities)
+		})
+	}
+	return r
+}
+
+// filterFindings removes the findings of the severities which are not specified
+func filterFindings(findings []compare.Finding, severities []dbTypes.Severity) []compare.Finding {
+	return lo.Filter(findings, func(f compare.Finding, _ int) bool {
+		return hasSeverity(f.Severity, severities)
+	})
+}
+
+func hasSeverity(severity string, severities []dbTypes.Severity) bool {
+	s, err := dbTypes.NewSeverity(severity)
+	if err != nil {
+		s = dbTypes.SeverityUnknown
+	}
+	return slices.Contains(severities, s)
+}
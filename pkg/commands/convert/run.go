@@ -11,6 +11,8 @@ import (
 	"github.com/aquasecurity/trivy/pkg/flag"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/report"
+	"github.com/aquasecurity/trivy/pkg/report/hook"
+	"github.com/aquasecurity/trivy/pkg/report/installed"
 	"github.com/aquasecurity/trivy/pkg/result"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
@@ -39,12 +41,25 @@ func Run(ctx context.Context, opts flag.Options) (err error) {
 		return xerrors.Errorf("unable to filter results: %w", err)
 	}
 
+	if r, err = hook.Run(ctx, r, opts.ReportHooks); err != nil {
+		return xerrors.Errorf("report hook error: %w", err)
+	}
+
 	log.Logger.Debug("Writing report to output...")
 	if err = report.Write(ctx, r, opts); err != nil {
 		return xerrors.Errorf("unable to write results: %w", err)
 	}
 
+	if opts.InstalledManifestDir != "" {
+		if _, err = installed.Write(opts.InstalledManifestDir, r); err != nil {
+			return xerrors.Errorf("unable to write installed package manifests: %w", err)
+		}
+	}
+
 	operation.ExitOnEOL(opts, r.Metadata)
+	if opts.ResultPolicy != "" {
+		return operation.ExitOnResultPolicy(ctx, opts, r)
+	}
 	operation.Exit(opts, r.Results.Failed())
 
 	return nil
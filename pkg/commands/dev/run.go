@@ -0,0 +1,110 @@
+package dev
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/all"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/javadb"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/semaphore"
+	xio "github.com/aquasecurity/trivy/pkg/x/io"
+)
+
+// Parse runs the dependency parsers supporting the file and writes the detected applications as JSON,
+// so that the exact output can be attached to bug reports and used as golden files.
+func Parse(ctx context.Context, opts flag.Options, filePath string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	apps, err := parse(ctx, opts, filePath)
+	if err != nil {
+		return err
+	}
+
+	w, cleanup, err := opts.OutputWriter(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to create a file: %w", err)
+	}
+	defer func() {
+		if cerr := cleanup(); cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+	}()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(apps); err != nil {
+		return xerrors.Errorf("json encode error: %w", err)
+	}
+	return nil
+}
+
+func parse(ctx context.Context, opts flag.Options, filePath string) ([]ftypes.Application, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, xerrors.Errorf("file stat error: %w", err)
+	} else if info.IsDir() {
+		return nil, xerrors.Errorf("%s is a directory", filePath)
+	}
+
+	// JAR files are looked up in the Java DB
+	javadb.Init(opts.CacheDir, opts.JavaDBRepository, opts.SkipJavaDBUpdate, opts.JavaDBAllowStale, opts.JavaDBShards, opts.Quiet, opts.RegistryOpts())
+
+	// Only the analyzers of language-specific packages are enabled
+	var disabled []analyzer.Type
+	disabled = append(disabled, analyzer.TypeOSes...)
+	disabled = append(disabled, analyzer.TypeConfigFiles...)
+	disabled = append(disabled, analyzer.TypeSecret, analyzer.TypeLicenseFile, analyzer.TypeSBOM)
+	ag, err := analyzer.NewAnalyzerGroup(analyzer.AnalyzerOptions{
+		Parallel:          1,
+		FilePatterns:      opts.FilePatterns,
+		DisabledAnalyzers: disabled,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("analyzer group error: %w", err)
+	}
+
+	composite, err := ag.PostAnalyzerFS()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to prepare filesystem for post analysis: %w", err)
+	}
+	defer composite.Cleanup()
+
+	var wg sync.WaitGroup
+	result := analyzer.NewAnalysisResult()
+	analysisOpts := analyzer.AnalysisOptions{Offline: opts.OfflineScan}
+	dir, name := filepath.Split(filePath)
+	opener := func() (xio.ReadSeekCloserAt, error) { return os.Open(filePath) }
+
+	if err = ag.AnalyzeFile(ctx, &wg, semaphore.New(1), result, dir, name, info, opener, nil, analysisOpts); err != nil {
+		return nil, xerrors.Errorf("analyze file (%s): %w", filePath, err)
+	}
+	wg.Wait()
+
+	if analyzerTypes := ag.RequiredPostAnalyzers(name, info); len(analyzerTypes) > 0 {
+		if err = composite.CreateLink(analyzerTypes, dir, name, filePath); err != nil {
+			return nil, xerrors.Errorf("failed to create link: %w", err)
+		}
+		if err = ag.PostAnalyze(ctx, composite, result, analysisOpts); err != nil {
+			return nil, xerrors.Errorf("post analysis error: %w", err)
+		}
+	}
+
+	if len(result.Applications) == 0 {
+		log.Logger.Warnf("No parser supports %s. Check the file name or use '--file-patterns', e.g. \"pip:%s\"", filePath, name)
+		return []ftypes.Application{}, nil
+	}
+
+	result.Sort()
+	return result.Applications, nil
+}
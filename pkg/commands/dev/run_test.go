@@ -0,0 +1,109 @@
+package dev
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/flag"
+)
+
+func Test_parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		opts     flag.Options
+		want     []ftypes.Application
+		wantErr  string
+	}{
+		{
+			name:     "requirements.txt",
+			filePath: "testdata/requirements.txt",
+			want: []ftypes.Application{
+				{
+					Type:     ftypes.Pip,
+					FilePath: "requirements.txt",
+					Libraries: ftypes.Packages{
+						{
+							Name:    "Flask",
+							Version: "2.0.0",
+							Provenance: &ftypes.Provenance{
+								Analyzer: "pip",
+								Version:  1,
+								FilePath: "requirements.txt",
+							},
+						},
+						{
+							Name:    "click",
+							Version: "8.0.0",
+							Provenance: &ftypes.Provenance{
+								Analyzer: "pip",
+								Version:  1,
+								FilePath: "requirements.txt",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "unsupported file name",
+			filePath: "testdata/deps.txt",
+			want:     []ftypes.Application{},
+		},
+		{
+			name:     "file patterns",
+			filePath: "testdata/deps.txt",
+			opts: flag.Options{
+				ScanOptions: flag.ScanOptions{
+					FilePatterns: []string{`pip:deps\.txt`},
+				},
+			},
+			want: []ftypes.Application{
+				{
+					Type:     ftypes.Pip,
+					FilePath: "deps.txt",
+					Libraries: ftypes.Packages{
+						{
+							Name:    "Flask",
+							Version: "2.0.0",
+							Provenance: &ftypes.Provenance{
+								Analyzer: "pip",
+								Version:  1,
+								FilePath: "deps.txt",
+							},
+						},
+						{
+							Name:    "click",
+							Version: "8.0.0",
+							Provenance: &ftypes.Provenance{
+								Analyzer: "pip",
+								Version:  1,
+								FilePath: "deps.txt",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "directory",
+			filePath: "testdata",
+			wantErr:  "testdata is a directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parse(context.Background(), tt.opts, tt.filePath)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
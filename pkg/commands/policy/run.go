@@ -0,0 +1,120 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/table"
+	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/policy"
+)
+
+func newClient(opts flag.Options) (*policy.Client, error) {
+	c, err := policy.NewClient(opts.CacheDir, opts.Quiet, opts.MisconfOptions.PolicyBundleRepository,
+		policy.WithRegistryOptions(opts.RegistryOpts()))
+	if err != nil {
+		return nil, xerrors.Errorf("policy client error: %w", err)
+	}
+	return c, nil
+}
+
+// Download downloads the policy bundle into the cache even if it is pinned
+func Download(ctx context.Context, opts flag.Options) error {
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+
+	log.Logger.Infof("Downloading the policy bundle from %s...", opts.MisconfOptions.PolicyBundleRepository)
+	if err = c.DownloadBuiltinPolicies(ctx); err != nil {
+		return xerrors.Errorf("failed to download the policy bundle: %w", err)
+	}
+
+	meta, err := c.GetMetadata()
+	if err != nil {
+		return xerrors.Errorf("policy metadata error: %w", err)
+	}
+	log.Logger.Infof("Downloaded the policy bundle: %s", meta.Digest)
+	return nil
+}
+
+// Verify verifies the integrity of the policy bundle in the cache
+func Verify(opts flag.Options) error {
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	if err = c.Verify(); err != nil {
+		return xerrors.Errorf("policy bundle verification failed: %w", err)
+	}
+	log.Logger.Info("The policy bundle has been verified")
+	return nil
+}
+
+// Pin pins the policy bundle in the cache, or the bundle with the digest, so that scans don't update it
+func Pin(ctx context.Context, opts flag.Options, digest string) error {
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	return c.Pin(ctx, digest)
+}
+
+// Unpin lets scans update the policy bundle again
+func Unpin(opts flag.Options) error {
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	return c.Unpin()
+}
+
+// List shows the policy bundle in the cache and the checks in it
+func List(w io.Writer, opts flag.Options) error {
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+
+	meta, err := c.GetMetadata()
+	if err != nil {
+		return xerrors.New("no policy bundle found, run 'trivy policy download' first")
+	}
+	checks, err := c.ListChecks()
+	if err != nil {
+		return xerrors.Errorf("unable to list checks: %w", err)
+	}
+
+	fmt.Fprintf(w, `Policy Bundle:
+  Repository: %s
+  Digest: %s
+  Pinned: %t
+  DownloadedAt: %s
+
+`, meta.Repository, meta.Digest, meta.Pinned, meta.DownloadedAt.UTC())
+
+	t := table.New(w)
+	t.SetHeaders("ID", "AVD ID", "Severity", "Title")
+	for _, check := range checks {
+		t.AddRow(check.ID, check.AVDID, check.Severity, check.Title)
+	}
+	t.Render()
+	return nil
+}
+
+// Vendor copies the policy bundle in the cache into the directory
+func Vendor(opts flag.Options, dir string) error {
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	if err = c.Vendor(dir); err != nil {
+		return xerrors.Errorf("unable to vendor the policy bundle: %w", err)
+	}
+	log.Logger.Infof("The policy bundle has been vendored into %s", dir)
+	return nil
+}
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/catalog"
+	"github.com/aquasecurity/trivy/pkg/commands/artifact"
+	"github.com/aquasecurity/trivy/pkg/commands/operation"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/parallel"
+	pkgReport "github.com/aquasecurity/trivy/pkg/report"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Run scans the images in the catalog of the registry and writes the consolidated report.
+// The images share the cache so that the common layers are analyzed only once.
+func Run(ctx context.Context, opts flag.Options) (err error) {
+	r, err := artifact.NewRunner(ctx, opts)
+	if err != nil {
+		if errors.Is(err, artifact.SkipScan) {
+			return nil
+		}
+		return xerrors.Errorf("init error: %w", err)
+	}
+	defer r.Close(ctx)
+
+	filter := catalog.Filter{
+		Repositories:        opts.Repositories,
+		ExcludeRepositories: opts.ExcludeRepositories,
+		Tags:                opts.Tags,
+		ExcludeTags:         opts.ExcludeTags,
+		MaxAge:              opts.MaxTagAge,
+	}
+	images, err := catalog.Images(ctx, opts.Target, filter, opts.RegistryOpts(), opts.Parallel)
+	if err != nil {
+		return xerrors.Errorf("catalog error: %w", err)
+	}
+	log.Logger.Infof("%d images to be scanned", len(images))
+
+	rpt := catalog.Report{
+		SchemaVersion: pkgReport.SchemaVersion,
+		Registry:      opts.Target,
+	}
+	onItem := func(ctx context.Context, image string) (types.Report, error) {
+		return scanImage(ctx, r, opts, image)
+	}
+	onResult := func(report types.Report) error {
+		rpt.Images = append(rpt.Images, report)
+		return nil
+	}
+	p := parallel.NewPipeline(opts.Parallel, !opts.Quiet, images, onItem, onResult)
+	if err = p.Do(ctx); err != nil {
+		return xerrors.Errorf("scan error: %w", err)
+	}
+
+	// The reports are sorted as the images are scanned in parallel
+	slices.SortFunc(rpt.Images, func(a, b types.Report) int {
+		return strings.Compare(a.ArtifactName, b.ArtifactName)
+	})
+
+	w, cleanup, err := opts.OutputWriter(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to create a file: %w", err)
+	}
+	defer func() {
+		if cerr := cleanup(); cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+	}()
+
+	if err = catalog.Write(w, rpt, opts.Format); err != nil {
+		return xerrors.Errorf("unable to write the report: %w", err)
+	}
+
+	operation.Exit(opts, rpt.Failed())
+	return nil
+}
+
+// scanImage scans the image within the timeout.
+// The failure is recorded in the report so that the other images are still scanned.
+func scanImage(ctx context.Context, r artifact.Runner, opts flag.Options, image string) (types.Report, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	opts.Target = image
+	report, err := r.ScanImage(ctx, opts)
+	if err == nil {
+		report, err = r.Filter(ctx, opts, report)
+	}
+	if err != nil {
+		log.Logger.Warnf("Failed to scan %s: %s", image, err)
+		scanErr := artifact.ClassifyError(err)
+		return types.Report{
+			SchemaVersion: pkgReport.SchemaVersion,
+			ArtifactName:  image,
+			ArtifactType:  ftypes.ArtifactContainerImage,
+			Metadata: types.Metadata{
+				Error: &scanErr,
+			},
+		}, nil
+	}
+	return report, nil
+}
@@ -3,14 +3,20 @@ package server
 import (
 	"context"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/advisory"
+	"github.com/aquasecurity/trivy/pkg/commands/artifact"
 	"github.com/aquasecurity/trivy/pkg/commands/operation"
 	"github.com/aquasecurity/trivy/pkg/flag"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/module"
+	"github.com/aquasecurity/trivy/pkg/remote"
+	"github.com/aquasecurity/trivy/pkg/rpc"
 	rpcServer "github.com/aquasecurity/trivy/pkg/rpc/server"
+	"github.com/aquasecurity/trivy/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/utils/fsutils"
 )
 
@@ -47,6 +53,20 @@ func Run(ctx context.Context, opts flag.Options) (err error) {
 		return xerrors.Errorf("error in vulnerability DB initialize: %w", err)
 	}
 
+	if err = advisory.Load(opts.AdvisorySources); err != nil {
+		return xerrors.Errorf("advisory source error: %w", err)
+	}
+
+	var online *advisory.OnlineSource
+	if opts.OSVOnline {
+		online = advisory.NewOnlineSource(advisory.OnlineOptions{
+			URL:      opts.OSVAPIURL,
+			CacheDir: opts.CacheDir,
+		})
+		// Registered so that vulnerability details are filled in
+		advisory.RegisterSource(online)
+	}
+
 	// Initialize WASM modules
 	m, err := module.NewManager(ctx, module.Options{
 		Dir:            opts.ModuleDir,
@@ -57,7 +77,63 @@ func Run(ctx context.Context, opts flag.Options) (err error) {
 	}
 	m.Register()
 
-	server := rpcServer.NewServer(opts.AppVersion, opts.Listen, opts.CacheDir, opts.Token, opts.TokenHeader,
-		opts.DBRepository, opts.RegistryOpts())
+	queueOpts := rpcServer.QueueOptions{
+		MaxScans:             opts.MaxScans,
+		MaxHighPriorityScans: opts.MaxHighPriorityScans,
+		MaxLowPriorityScans:  opts.MaxLowPriorityScans,
+	}
+	tlsConfig, err := rpc.NewServerTLSConfig(rpc.TLSOptions{
+		CACert:    opts.TLSCACert,
+		Cert:      opts.TLSCert,
+		Key:       opts.TLSKey,
+		SPIFFEIDs: opts.TLSSPIFFEIDs,
+	})
+	if err != nil {
+		return xerrors.Errorf("TLS config error: %w", err)
+	}
+
+	verdictOpts := rpcServer.VerdictOptions{
+		PolicyDir: opts.VerdictPolicyDir,
+		Timeout:   opts.VerdictTimeout,
+		FailOpen:  opts.VerdictFailOpen,
+		Scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+			return artifact.ScanRegistryImage(ctx, opts, imageRef, cache, online)
+		},
+		ScanManifest: func(ctx context.Context, manifest []byte) (types.Report, error) {
+			return artifact.ScanManifest(ctx, opts, manifest, cache)
+		},
+		Resolve: func(ctx context.Context, image string) (name.Digest, error) {
+			ref, err := name.ParseReference(image)
+			if err != nil {
+				return name.Digest{}, xerrors.Errorf("image reference parse error: %w", err)
+			}
+			desc, err := remote.Get(ctx, ref, opts.RegistryOpts())
+			if err != nil {
+				return name.Digest{}, xerrors.Errorf("failed to get the image descriptor: %w", err)
+			}
+			return ref.Context().Digest(desc.Digest.String()), nil
+		},
+	}
+
+	jobOpts := rpcServer.JobOptions{
+		Workers:   opts.JobWorkers,
+		QueueSize: opts.JobQueueSize,
+		Scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+			return artifact.ScanRegistryImage(ctx, opts, imageRef, cache, online)
+		},
+	}
+
+	authConfig, err := rpcServer.LoadAuthConfig(opts.AuthConfig)
+	if err != nil {
+		return xerrors.Errorf("auth config error: %w", err)
+	}
+	authOpts := rpcServer.AuthOptions{
+		Token:       opts.Token,
+		TokenHeader: opts.TokenHeader,
+		Config:      authConfig,
+	}
+
+	server := rpcServer.NewServer(opts.AppVersion, opts.Listen, opts.CacheDir, opts.DBRepository, queueOpts,
+		verdictOpts, jobOpts, authOpts, opts.WebhookOpts(), tlsConfig, online, opts.RegistryOpts())
 	return server.ListenAndServe(ctx, cache, opts.SkipDBUpdate)
 }
@@ -0,0 +1,182 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/table"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Finding represents a vulnerability of a package detected by a scanner
+type Finding struct {
+	VulnerabilityID  string
+	VendorIDs        []string `json:",omitempty"` // Other IDs of the same vulnerability, e.g. GHSA IDs for CVEs
+	PkgName          string
+	InstalledVersion string `json:",omitempty"`
+	Severity         string
+}
+
+// Match represents a vulnerability detected by both Trivy and another scanner
+type Match struct {
+	Trivy Finding
+	Other Finding
+}
+
+// Comparison holds the result of the reconciliation between Trivy and another scanner
+type Comparison struct {
+	ArtifactName string
+	Source       string    // The other scanner, e.g. "Inspector"
+	Matched      []Match   `json:",omitempty"`
+	TrivyOnly    []Finding `json:",omitempty"`
+	OtherOnly    []Finding `json:",omitempty"`
+}
+
+// Compare reconciles the vulnerabilities in the Trivy report with the findings of another scanner.
+// Findings are matched by the package name and the vulnerability ID or one of its aliases.
+func Compare(report types.Report, source string, others []Finding) Comparison {
+	comparison := Comparison{
+		ArtifactName: report.ArtifactName,
+		Source:       source,
+	}
+
+	matched := make([]bool, len(others))
+	for _, trivyFinding := range trivyFindings(report) {
+		i := slices.IndexFunc(others, func(other Finding) bool {
+			return normalizePkgName(trivyFinding.PkgName) == normalizePkgName(other.PkgName) &&
+				len(lo.Intersect(trivyFinding.ids(), other.ids())) > 0
+		})
+		if i < 0 {
+			comparison.TrivyOnly = append(comparison.TrivyOnly, trivyFinding)
+			continue
+		}
+		matched[i] = true
+		comparison.Matched = append(comparison.Matched, Match{
+			Trivy: trivyFinding,
+			Other: others[i],
+		})
+	}
+
+	for i, other := range others {
+		if !matched[i] {
+			comparison.OtherOnly = append(comparison.OtherOnly, other)
+		}
+	}
+	return comparison
+}
+
+// trivyFindings returns the unique pairs of vulnerabilities and packages in the report,
+// as other scanners don't report the same package in different locations separately.
+func trivyFindings(report types.Report) []Finding {
+	var findings []Finding
+	seen := make(map[string]struct{})
+	for _, result := range report.Results {
+		for _, vuln := range result.Vulnerabilities {
+			key := fmt.Sprintf("%s/%s/%s", vuln.VulnerabilityID, vuln.PkgName, vuln.InstalledVersion)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			findings = append(findings, Finding{
+				VulnerabilityID:  vuln.VulnerabilityID,
+				VendorIDs:        vuln.VendorIDs,
+				PkgName:          vuln.PkgName,
+				InstalledVersion: vuln.InstalledVersion,
+				Severity:         vuln.Severity,
+			})
+		}
+	}
+	return findings
+}
+
+func (f Finding) ids() []string {
+	return append([]string{f.VulnerabilityID}, f.VendorIDs...)
+}
+
+// normalizePkgName absorbs the differences of package names between scanners,
+// e.g. "PyYAML" and "pyyaml", "typing_extensions" and "typing-extensions"
+func normalizePkgName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
+
+// Write writes the comparison in the format
+func Write(w io.Writer, comparison Comparison, format types.Format) error {
+	switch format {
+	case types.FormatJSON:
+		b, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			return xerrors.Errorf("json marshal error: %w", err)
+		}
+		if _, err = fmt.Fprintln(w, string(b)); err != nil {
+			return xerrors.Errorf("failed to write json: %w", err)
+		}
+	case types.FormatTable:
+		writeTable(w, comparison)
+	default:
+		return xerrors.Errorf("unsupported format for comparison: %s", format)
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, comparison Comparison) {
+	title := fmt.Sprintf("%s (Trivy vs %s)", comparison.ArtifactName, comparison.Source)
+	fmt.Fprintf(w, "\n%s\n%s\n", title, strings.Repeat("=", len(title)))
+	fmt.Fprintf(w, "Matched: %d, Trivy only: %d, %s only: %d\n\n", len(comparison.Matched), len(comparison.TrivyOnly),
+		comparison.Source, len(comparison.OtherOnly))
+
+	if len(comparison.Matched)+len(comparison.TrivyOnly)+len(comparison.OtherOnly) == 0 {
+		return
+	}
+
+	t := table.New(w)
+	t.SetBorders(true)
+	t.SetRowLines(true)
+	t.SetHeaders("Status", "Library", "Vulnerability", "Installed Version", "Trivy Severity",
+		comparison.Source+" Severity")
+
+	for _, m := range sortFindings(comparison.Matched, func(m Match) Finding { return m.Trivy }) {
+		severity := m.Other.Severity
+		if severity != m.Trivy.Severity {
+			severity += " (differs)"
+		}
+		t.AddRow("Matched", m.Trivy.PkgName, m.Trivy.VulnerabilityID, m.Trivy.InstalledVersion, m.Trivy.Severity, severity)
+	}
+	for _, f := range sortFindings(comparison.TrivyOnly, func(f Finding) Finding { return f }) {
+		t.AddRow("Trivy only", f.PkgName, f.VulnerabilityID, f.InstalledVersion, f.Severity, "-")
+	}
+	for _, f := range sortFindings(comparison.OtherOnly, func(f Finding) Finding { return f }) {
+		t.AddRow(comparison.Source+" only", f.PkgName, f.VulnerabilityID, f.InstalledVersion, "-", f.Severity)
+	}
+	t.Render()
+}
+
+// sortFindings sorts the findings by package name, severity and vulnerability ID like the vulnerability table
+func sortFindings[T any](items []T, finding func(T) Finding) []T {
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		fa, fb := finding(a), finding(b)
+		if fa.PkgName != fb.PkgName {
+			return strings.Compare(fa.PkgName, fb.PkgName)
+		}
+		if sa, sb := severityOf(fa.Severity), severityOf(fb.Severity); sa != sb {
+			return int(sb) - int(sa)
+		}
+		return strings.Compare(fa.VulnerabilityID, fb.VulnerabilityID)
+	})
+	return sorted
+}
+
+func severityOf(s string) dbTypes.Severity {
+	severity, err := dbTypes.NewSeverity(s)
+	if err != nil {
+		return dbTypes.SeverityUnknown
+	}
+	return severity
+}
@@ -0,0 +1,111 @@
+package compare_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/compare"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var testReport = types.Report{
+	ArtifactName: "123456789012.dkr.ecr.us-east-1.amazonaws.com/app:1.0",
+	Results: types.Results{
+		{
+			Target: "app (alpine 3.19.1)",
+			Vulnerabilities: []types.DetectedVulnerability{
+				newVuln("CVE-2023-5678", "libssl3", "3.1.4-r5", "MEDIUM"),
+				newVuln("CVE-2024-0727", "libssl3", "3.1.4-r5", "MEDIUM"),
+			},
+		},
+		{
+			Target: "Python",
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2024-1135",
+					VendorIDs:        []string{"GHSA-w3h3-4rj7-4ph4"},
+					PkgName:          "gunicorn",
+					InstalledVersion: "21.2.0",
+					Vulnerability:    dbTypes.Vulnerability{Severity: "HIGH"},
+				},
+				newVuln("CVE-2023-49083", "cryptography", "41.0.5", "HIGH"),
+			},
+		},
+		{
+			// The same package in another location
+			Target: "Python",
+			Vulnerabilities: []types.DetectedVulnerability{
+				newVuln("CVE-2023-49083", "cryptography", "41.0.5", "HIGH"),
+			},
+		},
+	},
+}
+
+var testFindings = []compare.Finding{
+	{VulnerabilityID: "CVE-2023-5678", PkgName: "libssl3", InstalledVersion: "3.1.4-r5", Severity: "LOW"},
+	{VulnerabilityID: "GHSA-w3h3-4rj7-4ph4", PkgName: "gunicorn", InstalledVersion: "21.2.0", Severity: "HIGH"},
+	{VulnerabilityID: "CVE-2023-6129", PkgName: "libcrypto3", InstalledVersion: "3.1.4-r5", Severity: "MEDIUM"},
+}
+
+func newVuln(id, pkgName, version, severity string) types.DetectedVulnerability {
+	return types.DetectedVulnerability{
+		VulnerabilityID:  id,
+		PkgName:          pkgName,
+		InstalledVersion: version,
+		Vulnerability:    dbTypes.Vulnerability{Severity: severity},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	got := compare.Compare(testReport, "Inspector", testFindings)
+
+	want := compare.Comparison{
+		ArtifactName: "123456789012.dkr.ecr.us-east-1.amazonaws.com/app:1.0",
+		Source:       "Inspector",
+		Matched: []compare.Match{
+			{
+				Trivy: compare.Finding{VulnerabilityID: "CVE-2023-5678", PkgName: "libssl3", InstalledVersion: "3.1.4-r5", Severity: "MEDIUM"},
+				Other: testFindings[0],
+			},
+			{
+				// Matched by the alias
+				Trivy: compare.Finding{VulnerabilityID: "CVE-2024-1135", VendorIDs: []string{"GHSA-w3h3-4rj7-4ph4"}, PkgName: "gunicorn", InstalledVersion: "21.2.0", Severity: "HIGH"},
+				Other: testFindings[1],
+			},
+		},
+		TrivyOnly: []compare.Finding{
+			{VulnerabilityID: "CVE-2024-0727", PkgName: "libssl3", InstalledVersion: "3.1.4-r5", Severity: "MEDIUM"},
+			{VulnerabilityID: "CVE-2023-49083", PkgName: "cryptography", InstalledVersion: "41.0.5", Severity: "HIGH"},
+		},
+		OtherOnly: []compare.Finding{
+			testFindings[2],
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestWrite(t *testing.T) {
+	comparison := compare.Compare(testReport, "Inspector", testFindings)
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, compare.Write(&buf, comparison, types.FormatTable))
+		assert.Contains(t, buf.String(), "Matched: 2, Trivy only: 2, Inspector only: 1")
+		assert.Contains(t, buf.String(), "LOW (differs)")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, compare.Write(&buf, comparison, types.FormatJSON))
+		assert.Contains(t, buf.String(), `"VulnerabilityID": "CVE-2023-6129"`)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		err := compare.Write(&bytes.Buffer{}, comparison, types.FormatSarif)
+		require.ErrorContains(t, err, "unsupported format")
+	})
+}
@@ -0,0 +1,203 @@
+package ecr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/cloud/aws/config"
+	"github.com/aquasecurity/trivy/pkg/compare"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Source is the name of the scanner behind ECR image scanning
+const Source = "Inspector"
+
+const (
+	attrPackageName    = "package_name"
+	attrPackageVersion = "package_version"
+)
+
+type ecrAPI interface {
+	DescribeImageScanFindings(ctx context.Context, params *ecr.DescribeImageScanFindingsInput, optFns ...func(*ecr.Options)) (*ecr.DescribeImageScanFindingsOutput, error)
+}
+
+// Image identifies an image in an ECR repository
+type Image struct {
+	RegistryID string
+	Region     string
+	Repository string
+	Digest     string
+	Tag        string
+}
+
+func (i Image) String() string {
+	if i.Digest != "" {
+		return fmt.Sprintf("%s@%s", i.Repository, i.Digest)
+	}
+	return fmt.Sprintf("%s:%s", i.Repository, i.Tag)
+}
+
+// Importer fetches the findings of ECR image scanning, basic or enhanced
+type Importer struct {
+	client ecrAPI
+}
+
+func NewImporter(ctx context.Context, region, endpoint string) (*Importer, error) {
+	cfg, err := config.LoadDefaultAWSConfig(ctx, region, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &Importer{client: ecr.NewFromConfig(cfg)}, nil
+}
+
+// ParseImage returns the ECR image scanned in the report.
+// The digest is preferred to the tag as tags may have been moved to other images since the scan.
+func ParseImage(report types.Report) (Image, error) {
+	ref, err := name.ParseReference(report.ArtifactName)
+	if err != nil {
+		return Image{}, xerrors.Errorf("unable to parse the image name (%s): %w", report.ArtifactName, err)
+	}
+
+	// e.g. 123456789012.dkr.ecr.us-east-1.amazonaws.com
+	host := ref.Context().RegistryStr()
+	parts := strings.Split(host, ".")
+	if len(parts) < 6 || parts[1] != "dkr" || parts[2] != "ecr" {
+		return Image{}, xerrors.Errorf("%s is not an ECR image", report.ArtifactName)
+	}
+
+	image := Image{
+		RegistryID: parts[0],
+		Region:     parts[3],
+		Repository: ref.Context().RepositoryStr(),
+	}
+
+	switch r := ref.(type) {
+	case name.Digest:
+		image.Digest = r.DigestStr()
+	case name.Tag:
+		image.Tag = r.TagStr()
+	}
+
+	for _, repoDigest := range report.Metadata.RepoDigests {
+		d, err := name.NewDigest(repoDigest)
+		if err != nil || d.Context().String() != ref.Context().String() {
+			continue
+		}
+		image.Digest = d.DigestStr()
+	}
+	return image, nil
+}
+
+// Findings returns the vulnerabilities detected by ECR image scanning
+func (i *Importer) Findings(ctx context.Context, image Image) ([]compare.Finding, error) {
+	input := &ecr.DescribeImageScanFindingsInput{
+		RegistryId:     aws.String(image.RegistryID),
+		RepositoryName: aws.String(image.Repository),
+		ImageId:        &ecrtypes.ImageIdentifier{},
+	}
+	if image.Digest != "" {
+		input.ImageId.ImageDigest = aws.String(image.Digest)
+	} else {
+		input.ImageId.ImageTag = aws.String(image.Tag)
+	}
+
+	var findings []compare.Finding
+	for {
+		output, err := i.client.DescribeImageScanFindings(ctx, input)
+		if err != nil {
+			return nil, xerrors.Errorf("ecr.DescribeImageScanFindings: %w", err)
+		}
+
+		if status := output.ImageScanStatus; status != nil && status.Status != ecrtypes.ScanStatusComplete &&
+			status.Status != ecrtypes.ScanStatusActive {
+			return nil, xerrors.Errorf("the scan of %s is not complete (%s): %s", image, status.Status,
+				aws.ToString(status.Description))
+		}
+
+		if output.ImageScanFindings != nil {
+			findings = append(findings, convertBasicFindings(output.ImageScanFindings.Findings)...)
+			findings = append(findings, convertEnhancedFindings(output.ImageScanFindings.EnhancedFindings)...)
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	log.Logger.Debugf("%d findings imported from ECR for %s", len(findings), image)
+	return findings, nil
+}
+
+// convertBasicFindings converts the findings of basic scanning, which hold the package in the attributes
+func convertBasicFindings(scanFindings []ecrtypes.ImageScanFinding) []compare.Finding {
+	var findings []compare.Finding
+	for _, f := range scanFindings {
+		finding := compare.Finding{
+			VulnerabilityID: aws.ToString(f.Name),
+			Severity:        convertSeverity(string(f.Severity)),
+		}
+		for _, attr := range f.Attributes {
+			switch aws.ToString(attr.Key) {
+			case attrPackageName:
+				finding.PkgName = aws.ToString(attr.Value)
+			case attrPackageVersion:
+				finding.InstalledVersion = aws.ToString(attr.Value)
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// convertEnhancedFindings converts the findings of enhanced scanning with Amazon Inspector.
+// One finding is returned for each vulnerable package.
+func convertEnhancedFindings(scanFindings []ecrtypes.EnhancedImageScanFinding) []compare.Finding {
+	var findings []compare.Finding
+	for _, f := range scanFindings {
+		details := f.PackageVulnerabilityDetails
+		if details == nil {
+			continue
+		}
+		for _, pkg := range details.VulnerablePackages {
+			findings = append(findings, compare.Finding{
+				VulnerabilityID:  aws.ToString(details.VulnerabilityId),
+				VendorIDs:        details.RelatedVulnerabilities,
+				PkgName:          aws.ToString(pkg.Name),
+				InstalledVersion: packageVersion(pkg),
+				Severity:         convertSeverity(aws.ToString(f.Severity)),
+			})
+		}
+	}
+	return findings
+}
+
+// packageVersion formats the version of OS packages like Trivy, e.g. "1:2.3.4-5.el9"
+func packageVersion(pkg ecrtypes.VulnerablePackage) string {
+	version := aws.ToString(pkg.Version)
+	if release := aws.ToString(pkg.Release); release != "" {
+		version += "-" + release
+	}
+	if epoch := aws.ToInt32(pkg.Epoch); epoch != 0 {
+		version = fmt.Sprintf("%d:%s", epoch, version)
+	}
+	return version
+}
+
+// convertSeverity converts the severity of ECR and Inspector, which has other levels such as "INFORMATIONAL"
+func convertSeverity(severity string) string {
+	switch severity {
+	case "CRITICAL", "HIGH", "MEDIUM", "LOW":
+		return severity
+	default:
+		return "UNKNOWN"
+	}
+}
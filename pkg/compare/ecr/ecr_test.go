@@ -0,0 +1,188 @@
+package ecr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/compare"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  types.Report
+		want    Image
+		wantErr string
+	}{
+		{
+			name: "tag with repo digest",
+			report: types.Report{
+				ArtifactName: "123456789012.dkr.ecr.us-east-1.amazonaws.com/team/app:1.0",
+				Metadata: types.Metadata{
+					RepoDigests: []string{
+						"public.ecr.aws/team/app@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+						"123456789012.dkr.ecr.us-east-1.amazonaws.com/team/app@sha256:2222222222222222222222222222222222222222222222222222222222222222",
+					},
+				},
+			},
+			want: Image{
+				RegistryID: "123456789012",
+				Region:     "us-east-1",
+				Repository: "team/app",
+				Digest:     "sha256:2222222222222222222222222222222222222222222222222222222222222222",
+				Tag:        "1.0",
+			},
+		},
+		{
+			name: "tag only",
+			report: types.Report{
+				ArtifactName: "123456789012.dkr.ecr.eu-west-1.amazonaws.com/app:latest",
+			},
+			want: Image{
+				RegistryID: "123456789012",
+				Region:     "eu-west-1",
+				Repository: "app",
+				Tag:        "latest",
+			},
+		},
+		{
+			name: "not ECR",
+			report: types.Report{
+				ArtifactName: "alpine:3.19",
+			},
+			wantErr: "not an ECR image",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseImage(tt.report)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type mockECR struct {
+	pages []*ecr.DescribeImageScanFindingsOutput
+	calls int
+}
+
+func (m *mockECR) DescribeImageScanFindings(_ context.Context, params *ecr.DescribeImageScanFindingsInput, _ ...func(*ecr.Options)) (*ecr.DescribeImageScanFindingsOutput, error) {
+	if m.calls > 0 && aws.ToString(params.NextToken) != "next" {
+		return nil, assert.AnError
+	}
+	page := m.pages[m.calls]
+	m.calls++
+	return page, nil
+}
+
+func TestImporter_Findings(t *testing.T) {
+	complete := &ecrtypes.ImageScanStatus{Status: ecrtypes.ScanStatusComplete}
+	client := &mockECR{
+		pages: []*ecr.DescribeImageScanFindingsOutput{
+			{
+				ImageScanStatus: complete,
+				ImageScanFindings: &ecrtypes.ImageScanFindings{
+					Findings: []ecrtypes.ImageScanFinding{
+						{
+							Name:     aws.String("CVE-2023-5678"),
+							Severity: ecrtypes.FindingSeverityMedium,
+							Attributes: []ecrtypes.Attribute{
+								{Key: aws.String("package_version"), Value: aws.String("3.1.4-r5")},
+								{Key: aws.String("package_name"), Value: aws.String("openssl")},
+							},
+						},
+					},
+				},
+				NextToken: aws.String("next"),
+			},
+			{
+				ImageScanStatus: complete,
+				ImageScanFindings: &ecrtypes.ImageScanFindings{
+					EnhancedFindings: []ecrtypes.EnhancedImageScanFinding{
+						{
+							Severity: aws.String("INFORMATIONAL"),
+							PackageVulnerabilityDetails: &ecrtypes.PackageVulnerabilityDetails{
+								VulnerabilityId:        aws.String("CVE-2023-38545"),
+								RelatedVulnerabilities: []string{"ALAS2023-2023-356"},
+								VulnerablePackages: []ecrtypes.VulnerablePackage{
+									{
+										Name:    aws.String("curl-minimal"),
+										Epoch:   aws.Int32(0),
+										Version: aws.String("8.2.1"),
+										Release: aws.String("1.amzn2023.0.3"),
+									},
+									{
+										Name:    aws.String("libcurl-minimal"),
+										Epoch:   aws.Int32(1),
+										Version: aws.String("8.2.1"),
+										Release: aws.String("1.amzn2023.0.3"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	importer := &Importer{client: client}
+	got, err := importer.Findings(context.Background(), Image{
+		RegistryID: "123456789012",
+		Repository: "app",
+		Digest:     "sha256:2222222222222222222222222222222222222222222222222222222222222222",
+	})
+	require.NoError(t, err)
+
+	want := []compare.Finding{
+		{
+			VulnerabilityID:  "CVE-2023-5678",
+			PkgName:          "openssl",
+			InstalledVersion: "3.1.4-r5",
+			Severity:         "MEDIUM",
+		},
+		{
+			VulnerabilityID:  "CVE-2023-38545",
+			VendorIDs:        []string{"ALAS2023-2023-356"},
+			PkgName:          "curl-minimal",
+			InstalledVersion: "8.2.1-1.amzn2023.0.3",
+			Severity:         "UNKNOWN",
+		},
+		{
+			VulnerabilityID:  "CVE-2023-38545",
+			VendorIDs:        []string{"ALAS2023-2023-356"},
+			PkgName:          "libcurl-minimal",
+			InstalledVersion: "1:8.2.1-1.amzn2023.0.3",
+			Severity:         "UNKNOWN",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestImporter_Findings_InProgress(t *testing.T) {
+	client := &mockECR{
+		pages: []*ecr.DescribeImageScanFindingsOutput{
+			{
+				ImageScanStatus: &ecrtypes.ImageScanStatus{
+					Status:      ecrtypes.ScanStatusInProgress,
+					Description: aws.String("The scan is in progress."),
+				},
+			},
+		},
+	}
+	importer := &Importer{client: client}
+	_, err := importer.Findings(context.Background(), Image{Repository: "app", Tag: "latest"})
+	require.ErrorContains(t, err, "the scan of app:latest is not complete (IN_PROGRESS)")
+}
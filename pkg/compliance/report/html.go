@@ -0,0 +1,226 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// The template has the styles and charts inline so that the report can be printed or saved as PDF from a browser
+//
+//go:embed report.html.tpl
+var htmlTemplate string
+
+var htmlTmpl = template.Must(template.New("compliance").Parse(htmlTemplate))
+
+// barHeight is the height of a bar in the severity chart, including the spacing
+const barHeight = 16
+
+const (
+	statusPass   = "PASS"
+	statusFail   = "FAIL"
+	statusManual = "MANUAL"
+)
+
+// HTMLWriter writes the compliance report as a print-ready HTML page with summary charts.
+// Auditors can open it in a browser and print it or save it as PDF.
+type HTMLWriter struct {
+	Report string
+	Output io.Writer
+}
+
+type htmlPage struct {
+	ID               string
+	Title            string
+	Description      string
+	Version          string
+	RelatedResources []string
+
+	// Status is the donut chart of the control statuses
+	Status []chartSegment
+	// Severities is the bar chart of the failed controls by severity, the most severe first
+	Severities       []chartBar
+	SeveritiesHeight int
+	BarHeight        int
+
+	Controls []htmlControl
+	Detailed bool
+}
+
+type chartSegment struct {
+	Label string
+	Class string
+	Count int
+	// Dash and Offset draw the segment on a circle with a circumference of 100
+	Dash   string
+	Offset string
+}
+
+type chartBar struct {
+	Label string
+	Count int
+	// Width is the percentage of the longest bar
+	Width int
+	Y     int
+}
+
+type htmlControl struct {
+	ID          string
+	Name        string
+	Description string
+	Severity    string
+	Status      string
+	Issues      string
+	Findings    []htmlFinding
+}
+
+type htmlFinding struct {
+	Target   string
+	ID       string
+	Severity string
+	Message  string
+}
+
+func (hw HTMLWriter) Write(report *ComplianceReport) error {
+	if hw.Report != allReport && hw.Report != summaryReport {
+		return xerrors.Errorf(`report %q not supported. Use "summary" or "all"`, hw.Report)
+	}
+
+	p := htmlPage{
+		ID:               report.ID,
+		Title:            report.Title,
+		Description:      report.Description,
+		Version:          report.Version,
+		RelatedResources: report.RelatedResources,
+		Detailed:         hw.Report == allReport,
+	}
+
+	statuses := make(map[string]int)
+	failedSeverities := make(map[string]int)
+	summary := BuildSummary(report)
+	for i, control := range report.Results {
+		c := newHTMLControl(control, summary.SummaryControls[i])
+		statuses[c.Status]++
+		if c.Status == statusFail {
+			failedSeverities[c.Severity]++
+		}
+		p.Controls = append(p.Controls, c)
+	}
+
+	p.Status = donut(report.Results, statuses)
+	p.Severities = bars(failedSeverities)
+	p.SeveritiesHeight = len(p.Severities) * barHeight
+	p.BarHeight = barHeight
+
+	if err := htmlTmpl.Execute(hw.Output, p); err != nil {
+		return xerrors.Errorf("failed to write HTML compliance report: %w", err)
+	}
+	return nil
+}
+
+func newHTMLControl(control *ControlCheckResult, summary ControlCheckSummary) htmlControl {
+	c := htmlControl{
+		ID:          control.ID,
+		Name:        control.Name,
+		Description: control.Description,
+		Severity:    control.Severity,
+		Status:      statusManual,
+		Issues:      "-",
+	}
+	// Manual controls have no number of failures, as in the summary table
+	if summary.TotalFail != nil {
+		c.Status = statusPass
+		if *summary.TotalFail > 0 {
+			c.Status = statusFail
+		}
+		c.Issues = fmt.Sprint(*summary.TotalFail)
+	}
+
+	for _, result := range control.Results {
+		c.Findings = append(c.Findings, findings(result)...)
+	}
+	return c
+}
+
+func findings(result types.Result) []htmlFinding {
+	var fs []htmlFinding
+	for _, v := range result.Vulnerabilities {
+		fs = append(fs, htmlFinding{
+			Target:   result.Target,
+			ID:       v.VulnerabilityID,
+			Severity: v.Severity,
+			Message:  fmt.Sprintf("%s %s: %s", v.PkgName, v.InstalledVersion, v.Title),
+		})
+	}
+	for _, m := range result.Misconfigurations {
+		fs = append(fs, htmlFinding{
+			Target:   result.Target,
+			ID:       m.AVDID,
+			Severity: m.Severity,
+			Message:  m.Message,
+		})
+	}
+	for _, s := range result.Secrets {
+		fs = append(fs, htmlFinding{
+			Target:   result.Target,
+			ID:       s.RuleID,
+			Severity: s.Severity,
+			Message:  s.Title,
+		})
+	}
+	return fs
+}
+
+// donut returns the segments of the status chart in the order of PASS, FAIL and MANUAL
+func donut(controls []*ControlCheckResult, statuses map[string]int) []chartSegment {
+	var segments []chartSegment
+	var offset float64
+	for _, status := range []string{statusPass, statusFail, statusManual} {
+		count := statuses[status]
+		if count == 0 {
+			continue
+		}
+		percent := float64(count) * 100 / float64(len(controls))
+		segments = append(segments, chartSegment{
+			Label:  status,
+			Class:  strings.ToLower(status),
+			Count:  count,
+			Dash:   fmt.Sprintf("%.2f %.2f", percent, 100-percent),
+			Offset: fmt.Sprintf("%.2f", 25-offset), // start at 12 o'clock
+		})
+		offset += percent
+	}
+	return segments
+}
+
+// bars returns the bars of the failed controls by severity, the most severe first
+func bars(severities map[string]int) []chartBar {
+	var longest int
+	for _, count := range severities {
+		if count > longest {
+			longest = count
+		}
+	}
+
+	var bs []chartBar
+	for i := len(dbTypes.SeverityNames) - 1; i >= 0; i-- {
+		severity := dbTypes.SeverityNames[i]
+		bar := chartBar{
+			Label: severity,
+			Count: severities[severity],
+			Y:     len(bs) * barHeight,
+		}
+		if longest > 0 {
+			bar.Width = bar.Count * 100 / longest
+		}
+		bs = append(bs, bar)
+	}
+	return bs
+}
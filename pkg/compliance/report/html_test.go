@@ -0,0 +1,120 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/compliance/report"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestHTMLWriter_Write(t *testing.T) {
+	input := &report.ComplianceReport{
+		ID:               "k8s-nsa",
+		Title:            "National Security Agency - Kubernetes Hardening Guidance v1.0",
+		Version:          "1.0",
+		RelatedResources: []string{"https://example.com"},
+		Results: []*report.ControlCheckResult{
+			{
+				ID:       "1.0",
+				Name:     "Non-root containers",
+				Severity: "MEDIUM",
+				Results: types.Results{
+					{
+						Target: "Deployment/<app>",
+						Misconfigurations: []types.DetectedMisconfiguration{
+							{
+								AVDID:    "AVD-KSV-0012",
+								Severity: "MEDIUM",
+								Message:  "Container 'app' of Deployment 'app' should set 'securityContext.runAsNonRoot' to true",
+								Status:   types.MisconfStatusFailure,
+							},
+						},
+					},
+				},
+			},
+			{
+				ID:       "1.1",
+				Name:     "Immutable container file systems",
+				Severity: "LOW",
+			},
+			{
+				ID:       "1.2",
+				Name:     "Audit log path is configure (Manual)",
+				Severity: "MEDIUM",
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		report  string
+		want    []string
+		notWant []string
+		wantErr string
+	}{
+		{
+			name:   "summary",
+			report: "summary",
+			want: []string{
+				"<title>National Security Agency - Kubernetes Hardening Guidance v1.0</title>",
+				"k8s-nsa &middot; version 1.0",
+				`<a href="https://example.com">https://example.com</a>`,
+				// One third for each status, starting at 12 o'clock
+				`<circle class="pass" cx="21" cy="21" r="15.915" fill="none" stroke-width="6" stroke-dasharray="33.33 66.67" stroke-dashoffset="25.00"></circle>`,
+				`<circle class="fail" cx="21" cy="21" r="15.915" fill="none" stroke-width="6" stroke-dasharray="33.33 66.67" stroke-dashoffset="-8.33"></circle>`,
+				`<circle class="manual" cx="21" cy="21" r="15.915" fill="none" stroke-width="6" stroke-dasharray="33.33 66.67" stroke-dashoffset="-41.67"></circle>`,
+				`<div><span class="swatch fail"></span>FAIL: 1</div>`,
+				`<svg y="32" width="320" height="16">`,
+				`<rect class="bar" y="2" height="10" width="100%"></rect>`,
+				`<td class="status-FAIL">FAIL</td>`,
+				`<td class="status-PASS">PASS</td>`,
+				`<td class="status-MANUAL">MANUAL</td>`,
+			},
+			notWant: []string{
+				`<div class="details">`,
+				"AVD-KSV-0012",
+			},
+		},
+		{
+			name:   "all",
+			report: "all",
+			want: []string{
+				`<div class="details">`,
+				"<h3>1.0 Non-root containers (FAIL)</h3>",
+				"<td>Deployment/&lt;app&gt;</td><td>AVD-KSV-0012</td><td>MEDIUM</td>",
+			},
+		},
+		{
+			name:    "unknown report",
+			report:  "detail",
+			wantErr: `report "detail" not supported`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			w := report.HTMLWriter{
+				Report: tt.report,
+				Output: out,
+			}
+			err := w.Write(input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got := out.String()
+			for _, want := range tt.want {
+				assert.Contains(t, got, want)
+			}
+			for _, notWant := range tt.notWant {
+				assert.NotContains(t, got, notWant)
+			}
+		})
+	}
+}
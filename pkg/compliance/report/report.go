@@ -86,8 +86,14 @@ func Write(ctx context.Context, report *ComplianceReport, option Option) error {
 			}
 		}
 		return nil
+	case types.FormatHTML:
+		hwriter := HTMLWriter{
+			Output: option.Output,
+			Report: option.Report,
+		}
+		return hwriter.Write(report)
 	default:
-		return xerrors.Errorf(`unknown format %q. Use "json" or "table"`, option.Format)
+		return xerrors.Errorf(`unknown format %q. Use "json", "table" or "html"`, option.Format)
 	}
 }
 
@@ -0,0 +1,119 @@
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+  @page { size: A4; margin: 15mm; }
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; font-size: 10pt; }
+  h1 { font-size: 1.8em; margin-bottom: 0.2em; }
+  h2 { font-size: 1.3em; margin-top: 1.5em; border-bottom: 1px solid #d0d7de; padding-bottom: 0.2em; }
+  h3 { font-size: 1.1em; margin-bottom: 0.3em; }
+  .meta { color: #656d76; }
+  .charts { display: flex; gap: 4em; align-items: center; margin: 1em 0; }
+  .legend div { margin: 0.3em 0; }
+  .swatch { display: inline-block; width: 0.8em; height: 0.8em; margin-right: 0.4em; }
+  .pass { stroke: #1a7f37; fill: #1a7f37; background: #1a7f37; }
+  .fail { stroke: #d1242f; fill: #d1242f; background: #d1242f; }
+  .manual { stroke: #8c959f; fill: #8c959f; background: #8c959f; }
+  .bar { fill: #d1242f; }
+  table { border-collapse: collapse; width: 100%; margin-top: 0.5em; }
+  th, td { border: 1px solid #d0d7de; padding: 0.3em 0.5em; text-align: left; vertical-align: top; }
+  th { background: #f6f8fa; }
+  tr, .control { page-break-inside: avoid; }
+  td.status-PASS { color: #1a7f37; font-weight: bold; }
+  td.status-FAIL { color: #d1242f; font-weight: bold; }
+  td.status-MANUAL { color: #656d76; }
+  .details { page-break-before: always; }
+  @media print {
+    body { margin: 0; }
+    a { color: inherit; text-decoration: none; }
+  }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<div class="meta">{{ .ID }}{{ if .Version }} &middot; version {{ .Version }}{{ end }}</div>
+{{- if .Description }}
+<p>{{ .Description }}</p>
+{{- end }}
+{{- if .RelatedResources }}
+<div class="meta">Related resources:
+  {{- range .RelatedResources }}
+  <div><a href="{{ . }}">{{ . }}</a></div>
+  {{- end }}
+</div>
+{{- end }}
+
+<h2>Summary</h2>
+<div class="charts">
+  <svg width="160" height="160" viewBox="0 0 42 42" role="img" aria-label="Controls by status">
+    <circle cx="21" cy="21" r="15.915" fill="none" stroke="#eaeef2" stroke-width="6"></circle>
+    {{- range .Status }}
+    <circle class="{{ .Class }}" cx="21" cy="21" r="15.915" fill="none" stroke-width="6" stroke-dasharray="{{ .Dash }}" stroke-dashoffset="{{ .Offset }}"></circle>
+    {{- end }}
+    <text x="21" y="23" text-anchor="middle" font-size="6">{{ len .Controls }}</text>
+  </svg>
+  <div class="legend">
+    {{- range .Status }}
+    <div><span class="swatch {{ .Class }}"></span>{{ .Label }}: {{ .Count }}</div>
+    {{- end }}
+  </div>
+  <div>
+    <h3>Failed controls by severity</h3>
+    <svg width="320" height="{{ .SeveritiesHeight }}" role="img" aria-label="Failed controls by severity">
+      {{- range .Severities }}
+      <svg y="{{ .Y }}" width="320" height="{{ $.BarHeight }}">
+        <text y="10" font-size="10">{{ .Label }}</text>
+        <svg x="80" width="200" height="14"><rect class="bar" y="2" height="10" width="{{ .Width }}%"></rect></svg>
+        <text x="290" y="10" font-size="10">{{ .Count }}</text>
+      </svg>
+      {{- end }}
+    </svg>
+  </div>
+</div>
+
+<table>
+  <thead>
+    <tr><th>ID</th><th>Severity</th><th>Control Name</th><th>Status</th><th>Issues</th></tr>
+  </thead>
+  <tbody>
+  {{- range .Controls }}
+    <tr>
+      <td>{{ .ID }}</td>
+      <td>{{ .Severity }}</td>
+      <td>{{ .Name }}</td>
+      <td class="status-{{ .Status }}">{{ .Status }}</td>
+      <td>{{ .Issues }}</td>
+    </tr>
+  {{- end }}
+  </tbody>
+</table>
+
+{{- if .Detailed }}
+<div class="details">
+<h2>Controls</h2>
+{{- range .Controls }}
+<div class="control">
+  <h3>{{ .ID }} {{ .Name }} ({{ .Status }})</h3>
+  {{- if .Description }}
+  <p>{{ .Description }}</p>
+  {{- end }}
+  {{- if .Findings }}
+  <table>
+    <thead>
+      <tr><th>Target</th><th>ID</th><th>Severity</th><th>Message</th></tr>
+    </thead>
+    <tbody>
+    {{- range .Findings }}
+      <tr><td>{{ .Target }}</td><td>{{ .ID }}</td><td>{{ .Severity }}</td><td>{{ .Message }}</td></tr>
+    {{- end }}
+    </tbody>
+  </table>
+  {{- end }}
+</div>
+{{- end }}
+</div>
+{{- end }}
+</body>
+</html>
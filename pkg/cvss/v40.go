@@ -0,0 +1,339 @@
+package cvss
+
+import (
+	"math"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+)
+
+// V40Prefix is the prefix of CVSS v4.0 vectors
+const V40Prefix = "CVSS:4.0/"
+
+// v40Metrics lists the allowed values of CVSS v4.0 metrics in the order of the specification
+var v40Metrics = []v40Metric{
+	// Base metrics
+	{name: "AV", values: []string{"N", "A", "L", "P"}, required: true},
+	{name: "AC", values: []string{"L", "H"}, required: true},
+	{name: "AT", values: []string{"N", "P"}, required: true},
+	{name: "PR", values: []string{"N", "L", "H"}, required: true},
+	{name: "UI", values: []string{"N", "P", "A"}, required: true},
+	{name: "VC", values: []string{"H", "L", "N"}, required: true},
+	{name: "VI", values: []string{"H", "L", "N"}, required: true},
+	{name: "VA", values: []string{"H", "L", "N"}, required: true},
+	{name: "SC", values: []string{"H", "L", "N"}, required: true},
+	{name: "SI", values: []string{"H", "L", "N"}, required: true},
+	{name: "SA", values: []string{"H", "L", "N"}, required: true},
+	// Threat metrics
+	{name: "E", values: []string{"X", "A", "P", "U"}},
+	// Environmental metrics
+	{name: "CR", values: []string{"X", "H", "M", "L"}},
+	{name: "IR", values: []string{"X", "H", "M", "L"}},
+	{name: "AR", values: []string{"X", "H", "M", "L"}},
+	{name: "MAV", values: []string{"X", "N", "A", "L", "P"}},
+	{name: "MAC", values: []string{"X", "L", "H"}},
+	{name: "MAT", values: []string{"X", "N", "P"}},
+	{name: "MPR", values: []string{"X", "N", "L", "H"}},
+	{name: "MUI", values: []string{"X", "N", "P", "A"}},
+	{name: "MVC", values: []string{"X", "H", "L", "N"}},
+	{name: "MVI", values: []string{"X", "H", "L", "N"}},
+	{name: "MVA", values: []string{"X", "H", "L", "N"}},
+	{name: "MSC", values: []string{"X", "H", "L", "N"}},
+	{name: "MSI", values: []string{"X", "S", "H", "L", "N"}},
+	{name: "MSA", values: []string{"X", "S", "H", "L", "N"}},
+	// Supplemental metrics
+	{name: "S", values: []string{"X", "N", "P"}},
+	{name: "AU", values: []string{"X", "N", "Y"}},
+	{name: "R", values: []string{"X", "A", "U", "I"}},
+	{name: "V", values: []string{"X", "D", "C"}},
+	{name: "RE", values: []string{"X", "L", "M", "H"}},
+	{name: "U", values: []string{"X", "Clear", "Green", "Amber", "Red"}},
+}
+
+type v40Metric struct {
+	name     string
+	values   []string
+	required bool
+}
+
+// V40 represents a CVSS v4.0 vector
+type V40 struct {
+	metrics map[string]string
+}
+
+// ParseV40 parses a CVSS v4.0 vector such as "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N"
+func ParseV40(vector string) (V40, error) {
+	s, ok := strings.CutPrefix(vector, V40Prefix)
+	if !ok {
+		return V40{}, xerrors.Errorf("vector must start with %q: %s", V40Prefix, vector)
+	}
+
+	metrics := make(map[string]string)
+	for _, part := range strings.Split(s, "/") {
+		name, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return V40{}, xerrors.Errorf("invalid metric: %q", part)
+		}
+		idx := slices.IndexFunc(v40Metrics, func(m v40Metric) bool {
+			return m.name == name
+		})
+		if idx < 0 {
+			return V40{}, xerrors.Errorf("unknown metric: %s", name)
+		} else if !slices.Contains(v40Metrics[idx].values, value) {
+			return V40{}, xerrors.Errorf("invalid value of %s: %s", name, value)
+		} else if _, ok = metrics[name]; ok {
+			return V40{}, xerrors.Errorf("duplicate metric: %s", name)
+		}
+		metrics[name] = value
+	}
+
+	for _, m := range v40Metrics {
+		if _, ok := metrics[m.name]; m.required && !ok {
+			return V40{}, xerrors.Errorf("missing metric: %s", m.name)
+		}
+	}
+	return V40{metrics: metrics}, nil
+}
+
+// metric returns the effective value of the metric.
+// Modified metrics override the base metrics, and "Not Defined" falls back to the worst case.
+func (v V40) metric(name string) string {
+	value, ok := v.metrics[name]
+	if !ok {
+		value = "X"
+	}
+	switch {
+	case name == "E" && value == "X":
+		return "A"
+	case (name == "CR" || name == "IR" || name == "AR") && value == "X":
+		return "H"
+	}
+	if modified, ok := v.metrics["M"+name]; ok && modified != "X" {
+		return modified
+	}
+	return value
+}
+
+// macroVector returns the equivalence classes EQ1 to EQ6
+func (v V40) macroVector() [6]int {
+	var eq [6]int
+	av, pr, ui := v.metric("AV"), v.metric("PR"), v.metric("UI")
+	switch {
+	case av == "N" && pr == "N" && ui == "N":
+		eq[0] = 0
+	case (av == "N" || pr == "N" || ui == "N") && av != "P":
+		eq[0] = 1
+	default:
+		eq[0] = 2
+	}
+
+	if v.metric("AC") != "L" || v.metric("AT") != "N" {
+		eq[1] = 1
+	}
+
+	vc, vi, va := v.metric("VC"), v.metric("VI"), v.metric("VA")
+	switch {
+	case vc == "H" && vi == "H":
+		eq[2] = 0
+	case vc == "H" || vi == "H" || va == "H":
+		eq[2] = 1
+	default:
+		eq[2] = 2
+	}
+
+	sc, si, sa := v.metric("SC"), v.metric("SI"), v.metric("SA")
+	switch {
+	case si == "S" || sa == "S":
+		eq[3] = 0
+	case sc == "H" || si == "H" || sa == "H":
+		eq[3] = 1
+	default:
+		eq[3] = 2
+	}
+
+	switch v.metric("E") {
+	case "P":
+		eq[4] = 1
+	case "U":
+		eq[4] = 2
+	}
+
+	if !(v.metric("CR") == "H" && vc == "H") && !(v.metric("IR") == "H" && vi == "H") &&
+		!(v.metric("AR") == "H" && va == "H") {
+		eq[5] = 1
+	}
+	return eq
+}
+
+// Score calculates the score of the vector following the algorithm of the CVSS v4.0 specification.
+// https://www.first.org/cvss/v4.0/specification-document#CVSS-v4-0-Scoring
+func (v V40) Score() float64 {
+	// No impact on the vulnerable and subsequent systems
+	impacts := []string{"VC", "VI", "VA", "SC", "SI", "SA"}
+	if !slices.ContainsFunc(impacts, func(m string) bool {
+		return v.metric(m) != "N"
+	}) {
+		return 0
+	}
+
+	eq := v.macroVector()
+	value := v40Lookup[macroVectorKey(eq)]
+
+	// The scores of the next lower macro vectors, which are NaN if they don't exist
+	lower := [6]float64{}
+	for i := range eq {
+		if i == 2 || i == 5 {
+			continue // EQ3 and EQ6 are handled together
+		}
+		next := eq
+		next[i]++
+		lower[i] = lookup(next)
+	}
+	var eq3eq6 float64
+	switch {
+	case eq[2] == 1 && eq[5] == 1, eq[2] == 0 && eq[5] == 1:
+		eq3eq6 = lookup([6]int{eq[0], eq[1], eq[2] + 1, eq[3], eq[4], eq[5]})
+	case eq[2] == 1 && eq[5] == 0:
+		eq3eq6 = lookup([6]int{eq[0], eq[1], eq[2], eq[3], eq[4], eq[5] + 1})
+	case eq[2] == 0 && eq[5] == 0:
+		// 00 can go to 01 or 10, and the higher score is chosen
+		eq3eq6 = math.Max(
+			lookup([6]int{eq[0], eq[1], eq[2], eq[3], eq[4], eq[5] + 1}),
+			lookup([6]int{eq[0], eq[1], eq[2] + 1, eq[3], eq[4], eq[5]}),
+		)
+	default:
+		// 21 is the lowest
+		eq3eq6 = math.NaN()
+	}
+
+	distance := v.severityDistances(eq)
+	current := map[string]float64{
+		"eq1":    distance["AV"] + distance["PR"] + distance["UI"],
+		"eq2":    distance["AC"] + distance["AT"],
+		"eq3eq6": distance["VC"] + distance["VI"] + distance["VA"] + distance["CR"] + distance["IR"] + distance["AR"],
+		"eq4":    distance["SC"] + distance["SI"] + distance["SA"],
+	}
+	const step = 0.1
+	maxSeverity := map[string]float64{
+		"eq1":    float64(v40MaxSeverity.eq1[eq[0]]) * step,
+		"eq2":    float64(v40MaxSeverity.eq2[eq[1]]) * step,
+		"eq3eq6": float64(v40MaxSeverity.eq3eq6[eq[2]][eq[5]]) * step,
+		"eq4":    float64(v40MaxSeverity.eq4[eq[3]]) * step,
+	}
+	available := map[string]float64{
+		"eq1":    value - lower[0],
+		"eq2":    value - lower[1],
+		"eq3eq6": value - eq3eq6,
+		"eq4":    value - lower[3],
+		"eq5":    value - lower[4],
+	}
+
+	// The mean of the proportional distances from the highest severity vector in each macro vector
+	var sum float64
+	var n int
+	for _, key := range []string{"eq1", "eq2", "eq3eq6", "eq4", "eq5"} {
+		if math.IsNaN(available[key]) {
+			continue
+		}
+		n++
+		if key == "eq5" {
+			// EQ5 has only one vector in each macro vector
+			continue
+		}
+		sum += available[key] * current[key] / maxSeverity[key]
+	}
+
+	if n > 0 {
+		value -= sum / float64(n)
+	}
+	value = math.Max(0, math.Min(10, value))
+	return roundV40(value)
+}
+
+// Severity returns the qualitative severity rating of the vector
+func (v V40) Severity() dbTypes.Severity {
+	return V40Severity(v.Score())
+}
+
+// V40Severity converts a CVSS v4.0 score into the severity.
+// "None" (0.0) is mapped to UNKNOWN as there is no corresponding severity.
+func V40Severity(score float64) dbTypes.Severity {
+	switch {
+	case score >= 9.0:
+		return dbTypes.SeverityCritical
+	case score >= 7.0:
+		return dbTypes.SeverityHigh
+	case score >= 4.0:
+		return dbTypes.SeverityMedium
+	case score > 0:
+		return dbTypes.SeverityLow
+	}
+	return dbTypes.SeverityUnknown
+}
+
+// severityDistances returns the distances of each metric from the first max vector
+// of the macro vector that the vector doesn't exceed.
+func (v V40) severityDistances(eq [6]int) map[string]float64 {
+	var maxVectors []string
+	for _, eq1 := range v40MaxComposed.eq1[eq[0]] {
+		for _, eq2 := range v40MaxComposed.eq2[eq[1]] {
+			for _, eq3eq6 := range v40MaxComposed.eq3eq6[eq[2]][eq[5]] {
+				for _, eq4 := range v40MaxComposed.eq4[eq[3]] {
+					for _, eq5 := range v40MaxComposed.eq5[eq[4]] {
+						maxVectors = append(maxVectors, eq1+eq2+eq3eq6+eq4+eq5)
+					}
+				}
+			}
+		}
+	}
+
+	var distance map[string]float64
+	for _, maxVector := range maxVectors {
+		distance = make(map[string]float64)
+		negative := false
+		for metric, levels := range v40Levels {
+			distance[metric] = levels[v.metric(metric)] - levels[extractMetric(maxVector, metric)]
+			if distance[metric] < 0 {
+				negative = true
+			}
+		}
+		if !negative {
+			break
+		}
+	}
+	return distance
+}
+
+// extractMetric extracts the value of the metric from a max vector such as "AV:N/PR:N/UI:N/"
+func extractMetric(vector, metric string) string {
+	for _, part := range strings.Split(vector, "/") {
+		if name, value, ok := strings.Cut(part, ":"); ok && name == metric {
+			return value
+		}
+	}
+	return ""
+}
+
+func macroVectorKey(eq [6]int) string {
+	var b strings.Builder
+	for _, n := range eq {
+		b.WriteByte(byte('0' + n))
+	}
+	return b.String()
+}
+
+func lookup(eq [6]int) float64 {
+	score, ok := v40Lookup[macroVectorKey(eq)]
+	if !ok {
+		return math.NaN()
+	}
+	return score
+}
+
+func roundV40(value float64) float64 {
+	const epsilon = 1e-6
+	return math.Round((value+epsilon)*10) / 10
+}
@@ -0,0 +1,129 @@
+package cvss_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/cvss"
+)
+
+func TestParseV40(t *testing.T) {
+	tests := []struct {
+		name    string
+		vector  string
+		wantErr string
+	}{
+		{
+			name:   "base metrics",
+			vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+		},
+		{
+			name:   "all metric groups",
+			vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:P/CR:M/MSI:S/S:N/U:Amber",
+		},
+		{
+			name:    "CVSS v3.1",
+			vector:  "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			wantErr: "vector must start with",
+		},
+		{
+			name:    "missing metric",
+			vector:  "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N",
+			wantErr: "missing metric: SA",
+		},
+		{
+			name:    "unknown metric",
+			vector:  "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/XX:N",
+			wantErr: "unknown metric: XX",
+		},
+		{
+			name:    "invalid value",
+			vector:  "CVSS:4.0/AV:X/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			wantErr: "invalid value of AV: X",
+		},
+		{
+			name:    "duplicate metric",
+			vector:  "CVSS:4.0/AV:N/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			wantErr: "duplicate metric: AV",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cvss.ParseV40(tt.vector)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestV40_Score(t *testing.T) {
+	tests := []struct {
+		name         string
+		vector       string
+		wantScore    float64
+		wantSeverity dbTypes.Severity
+	}{
+		{
+			name:         "critical",
+			vector:       "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			wantScore:    9.3,
+			wantSeverity: dbTypes.SeverityCritical,
+		},
+		{
+			name:         "subsequent system impact",
+			vector:       "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H",
+			wantScore:    10,
+			wantSeverity: dbTypes.SeverityCritical,
+		},
+		{
+			name:         "local with privileges",
+			vector:       "CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			wantScore:    8.5,
+			wantSeverity: dbTypes.SeverityHigh,
+		},
+		{
+			name:         "high privileges",
+			vector:       "CVSS:4.0/AV:N/AC:L/AT:N/PR:H/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+			wantScore:    8.6,
+			wantSeverity: dbTypes.SeverityHigh,
+		},
+		{
+			name:         "low impact",
+			vector:       "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:L/VA:L/SC:N/SI:N/SA:N",
+			wantScore:    6.9,
+			wantSeverity: dbTypes.SeverityMedium,
+		},
+		{
+			name:         "active user interaction",
+			vector:       "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:A/VC:N/VI:N/VA:N/SC:L/SI:L/SA:N",
+			wantScore:    5.1,
+			wantSeverity: dbTypes.SeverityMedium,
+		},
+		{
+			name:         "modified safety impact",
+			vector:       "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/MSI:S",
+			wantScore:    10,
+			wantSeverity: dbTypes.SeverityCritical,
+		},
+		{
+			name:         "no impact",
+			vector:       "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:N/SC:N/SI:N/SA:N",
+			wantScore:    0,
+			wantSeverity: dbTypes.SeverityUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := cvss.ParseV40(tt.vector)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantScore, v.Score())
+			assert.Equal(t, tt.wantSeverity, v.Severity())
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package debugreport
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// SkipReason represents why a file was not analyzed
+type SkipReason string
+
+// CacheStatus represents whether the analysis result of a layer was found in the cache
+type CacheStatus string
+
+const (
+	// SkipReasonPattern means the file or directory matched --skip-files or --skip-dirs
+	SkipReasonPattern SkipReason = "pattern"
+
+	// SkipReasonSizeLimit means the file would be analyzed if it was not larger than the limit of the analyzer
+	SkipReasonSizeLimit SkipReason = "size limit"
+
+	// SkipReasonUnsupported means no analyzer requires the file
+	SkipReasonUnsupported SkipReason = "unsupported"
+
+	// SkipReasonUnchanged means the file was not changed since the revision given by --changed-from
+	SkipReasonUnchanged SkipReason = "unchanged"
+
+	// SkipReasonBudget means the file was not analyzed as the budget given by --budget was exceeded
+	SkipReasonBudget SkipReason = "budget"
+
+	CacheHit  CacheStatus = "hit"
+	CacheMiss CacheStatus = "miss"
+)
+
+// Duration is time.Duration encoded as a string such as "1.5s" in JSON
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Report is the debug report written by --debug-report
+type Report struct {
+	Phases       []Phase       `json:",omitempty"`
+	Analyzers    []Analyzer    `json:",omitempty"`
+	SkippedFiles []SkippedFile `json:",omitempty"`
+	Layers       []Layer       `json:",omitempty"`
+}
+
+// Phase represents the wall time of a scan phase such as analysis and detection
+type Phase struct {
+	Name     string
+	WallTime Duration
+}
+
+// Analyzer represents the sum of the wall time of an analyzer and the files it processed.
+// Files and Bytes are not recorded for post-analyzers since they receive a filesystem.
+type Analyzer struct {
+	Name     string
+	WallTime Duration
+	Files    int   `json:",omitempty"`
+	Bytes    int64 `json:",omitempty"`
+}
+
+// SkippedFile represents a file or directory which was not analyzed
+type SkippedFile struct {
+	Path   string
+	Reason SkipReason
+}
+
+// Layer represents the cache status of a container image layer
+type Layer struct {
+	DiffID string
+	Cache  CacheStatus
+}
+
+// Collector collects the debug information during a scan.
+// All methods are safe for concurrent use and do nothing on a nil Collector,
+// so that callers don't need to check if --debug-report is enabled.
+type Collector struct {
+	mu        sync.Mutex
+	phases    []Phase
+	analyzers map[string]*Analyzer
+	skipped   []SkippedFile
+	layers    []Layer
+}
+
+func NewCollector() *Collector {
+	return &Collector{analyzers: make(map[string]*Analyzer)}
+}
+
+// collectorKey is the context key for the collector. It is unexported to prevent collisions with context keys defined in
+// other packages.
+type collectorKey struct{}
+
+// With returns a new context with the given collector.
+func With(ctx context.Context, c *Collector) context.Context {
+	return context.WithValue(ctx, collectorKey{}, c)
+}
+
+// FromContext returns the collector from the context, or nil if --debug-report is not enabled.
+func FromContext(ctx context.Context) *Collector {
+	c, _ := ctx.Value(collectorKey{}).(*Collector)
+	return c
+}
+
+// AddPhase records the wall time of the scan phase
+func (c *Collector) AddPhase(name string, d time.Duration) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.phases = append(c.phases, Phase{
+		Name:     name,
+		WallTime: Duration(d),
+	})
+}
+
+// AddAnalysis records that the analyzer took the given time to analyze a file of the given size
+func (c *Collector) AddAnalysis(analyzer string, d time.Duration, size int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	a := c.analyzer(analyzer)
+	a.WallTime += Duration(d)
+	a.Files++
+	a.Bytes += size
+}
+
+// AddPostAnalysis records the wall time of the post-analyzer
+func (c *Collector) AddPostAnalysis(analyzer string, d time.Duration) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.analyzer(analyzer).WallTime += Duration(d)
+}
+
+func (c *Collector) analyzer(name string) *Analyzer {
+	a, ok := c.analyzers[name]
+	if !ok {
+		a = &Analyzer{Name: name}
+		c.analyzers[name] = a
+	}
+	return a
+}
+
+// AddSkip records that the file or directory was not analyzed
+func (c *Collector) AddSkip(path string, reason SkipReason) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.skipped = append(c.skipped, SkippedFile{
+		Path:   path,
+		Reason: reason,
+	})
+}
+
+// AddLayer records whether the analysis result of the layer was found in the cache
+func (c *Collector) AddLayer(diffID string, cached bool) {
+	if c == nil {
+		return
+	}
+	status := CacheMiss
+	if cached {
+		status = CacheHit
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.layers = append(c.layers, Layer{
+		DiffID: diffID,
+		Cache:  status,
+	})
+}
+
+// Report returns the collected information.
+// Analyzers are sorted by the wall time in descending order so that slow analyzers come first.
+func (c *Collector) Report() Report {
+	if c == nil {
+		return Report{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var analyzers []Analyzer
+	for _, a := range c.analyzers {
+		analyzers = append(analyzers, *a)
+	}
+	sort.Slice(analyzers, func(i, j int) bool {
+		if analyzers[i].WallTime != analyzers[j].WallTime {
+			return analyzers[i].WallTime > analyzers[j].WallTime
+		}
+		return analyzers[i].Name < analyzers[j].Name
+	})
+
+	// Files are skipped concurrently
+	skipped := append([]SkippedFile(nil), c.skipped...)
+	sort.Slice(skipped, func(i, j int) bool {
+		return skipped[i].Path < skipped[j].Path
+	})
+
+	return Report{
+		Phases:       append([]Phase(nil), c.phases...),
+		Analyzers:    analyzers,
+		SkippedFiles: skipped,
+		Layers:       append([]Layer(nil), c.layers...),
+	}
+}
+
+// Write writes the collected information to the file as JSON
+func (c *Collector) Write(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return xerrors.Errorf("failed to create %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(c.Report()); err != nil {
+		return xerrors.Errorf("json encode error: %w", err)
+	}
+	return nil
+}
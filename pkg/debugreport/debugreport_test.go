@@ -0,0 +1,67 @@
+package debugreport_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/debugreport"
+)
+
+func TestCollector_Write(t *testing.T) {
+	c := debugreport.NewCollector()
+	ctx := debugreport.With(context.Background(), c)
+	require.Same(t, c, debugreport.FromContext(ctx))
+
+	c.AddPhase("analysis", 3*time.Second)
+	c.AddAnalysis("npm", time.Second, 100)
+	c.AddAnalysis("npm", time.Second, 50)
+	c.AddAnalysis("apk", 500*time.Millisecond, 1000)
+	c.AddPostAnalysis("jar", 3*time.Second)
+	c.AddSkip("var/lib/data.bin", debugreport.SkipReasonUnsupported)
+	c.AddSkip("node_modules", debugreport.SkipReasonPattern)
+	c.AddLayer("sha256:aaa", true)
+	c.AddLayer("sha256:bbb", false)
+
+	filePath := filepath.Join(t.TempDir(), "timings.json")
+	require.NoError(t, c.Write(filePath))
+
+	got, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "Phases": [
+    {"Name": "analysis", "WallTime": "3s"}
+  ],
+  "Analyzers": [
+    {"Name": "jar", "WallTime": "3s"},
+    {"Name": "npm", "WallTime": "2s", "Files": 2, "Bytes": 150},
+    {"Name": "apk", "WallTime": "500ms", "Files": 1, "Bytes": 1000}
+  ],
+  "SkippedFiles": [
+    {"Path": "node_modules", "Reason": "pattern"},
+    {"Path": "var/lib/data.bin", "Reason": "unsupported"}
+  ],
+  "Layers": [
+    {"DiffID": "sha256:aaa", "Cache": "hit"},
+    {"DiffID": "sha256:bbb", "Cache": "miss"}
+  ]
+}`, string(got))
+}
+
+func TestCollector_Nil(t *testing.T) {
+	// The collector is nil when --debug-report is not specified
+	c := debugreport.FromContext(context.Background())
+	require.Nil(t, c)
+
+	c.AddPhase("analysis", time.Second)
+	c.AddAnalysis("npm", time.Second, 100)
+	c.AddPostAnalysis("jar", time.Second)
+	c.AddSkip("foo", debugreport.SkipReasonPattern)
+	c.AddLayer("sha256:aaa", true)
+	assert.Equal(t, debugreport.Report{}, c.Report())
+}
@@ -0,0 +1,113 @@
+package delta
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/scanner/utils"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// baseIndex holds what the base image contains
+type baseIndex struct {
+	diffIDs  map[string]struct{}
+	pkgs     map[string]struct{}
+	vulns    map[string]struct{}
+	artifact string
+}
+
+// Subtract removes the vulnerabilities and packages inherited from the base image from the target report.
+//
+// They are compared at two levels:
+//   - Layer: findings in layers shared with the base image, i.e. the target image is built on top of it.
+//   - Package: findings in packages installed in the base image with the same version,
+//     e.g. the target image is rebuilt from the same base, and the layers are not shared.
+func Subtract(target, base types.Report) types.Report {
+	idx := newBaseIndex(base)
+
+	var vulns, pkgs int
+	for i, result := range target.Results {
+		filtered := lo.Filter(result.Vulnerabilities, func(vuln types.DetectedVulnerability, _ int) bool {
+			return !idx.hasVuln(result, vuln)
+		})
+		vulns += len(result.Vulnerabilities) - len(filtered)
+		target.Results[i].Vulnerabilities = filtered
+		if len(filtered) == 0 {
+			target.Results[i].Vulnerabilities = nil
+		}
+
+		filteredPkgs := lo.Filter(result.Packages, func(pkg ftypes.Package, _ int) bool {
+			return !idx.hasPkg(result, pkg)
+		})
+		pkgs += len(result.Packages) - len(filteredPkgs)
+		target.Results[i].Packages = filteredPkgs
+		if len(filteredPkgs) == 0 {
+			target.Results[i].Packages = nil
+		}
+	}
+	log.Logger.Infof("%d vulnerabilities and %d packages inherited from %s are excluded", vulns, pkgs, idx.artifact)
+	return target
+}
+
+func newBaseIndex(base types.Report) baseIndex {
+	idx := baseIndex{
+		diffIDs:  make(map[string]struct{}),
+		pkgs:     make(map[string]struct{}),
+		vulns:    make(map[string]struct{}),
+		artifact: base.ArtifactName,
+	}
+	for _, diffID := range base.Metadata.DiffIDs {
+		idx.diffIDs[diffID] = struct{}{}
+	}
+	for _, result := range base.Results {
+		for _, pkg := range result.Packages {
+			idx.pkgs[pkgKey(result, pkg.Name, utils.FormatVersion(pkg), pkg.FilePath)] = struct{}{}
+		}
+		for _, vuln := range result.Vulnerabilities {
+			idx.vulns[vulnKey(result, vuln)] = struct{}{}
+		}
+	}
+	return idx
+}
+
+func (idx baseIndex) inBaseLayer(layer ftypes.Layer) bool {
+	_, ok := idx.diffIDs[layer.DiffID]
+	return layer.DiffID != "" && ok
+}
+
+func (idx baseIndex) hasVuln(result types.Result, vuln types.DetectedVulnerability) bool {
+	if idx.inBaseLayer(vuln.Layer) {
+		return true
+	}
+	// The vulnerable package might be in the base image, even if the vulnerability is not detected there,
+	// e.g. the base image was scanned with different options.
+	if _, ok := idx.pkgs[pkgKey(result, vuln.PkgName, vuln.InstalledVersion, vuln.PkgPath)]; ok {
+		return true
+	}
+	_, ok := idx.vulns[vulnKey(result, vuln)]
+	return ok
+}
+
+func (idx baseIndex) hasPkg(result types.Result, pkg ftypes.Package) bool {
+	if idx.inBaseLayer(pkg.Layer) {
+		return true
+	}
+	_, ok := idx.pkgs[pkgKey(result, pkg.Name, utils.FormatVersion(pkg), pkg.FilePath)]
+	return ok
+}
+
+// pkgKey returns the key of the package, where the version includes the epoch and the release.
+// The file path is taken into account only for language-specific packages, as OS packages are unique in the image.
+func pkgKey(result types.Result, name, version, filePath string) string {
+	if result.Class == types.ClassOSPkg {
+		filePath = ""
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", result.Type, name, version, filePath)
+}
+
+func vulnKey(result types.Result, vuln types.DetectedVulnerability) string {
+	return vuln.VulnerabilityID + "/" + pkgKey(result, vuln.PkgName, vuln.InstalledVersion, vuln.PkgPath)
+}
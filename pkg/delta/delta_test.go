@@ -0,0 +1,163 @@
+package delta_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/delta"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestSubtract(t *testing.T) {
+	var (
+		baseLayer = ftypes.Layer{DiffID: "sha256:base"}
+		appLayer  = ftypes.Layer{DiffID: "sha256:app"}
+
+		// Installed in the base image
+		musl = ftypes.Package{
+			Name:    "musl",
+			Version: "1.2.4",
+			Release: "r2",
+			Layer:   baseLayer,
+		}
+		// Upgraded in the application layer
+		libcrypto = ftypes.Package{
+			Name:    "libcrypto3",
+			Version: "3.1.4-r5",
+			Layer:   appLayer,
+		}
+		// Reinstalled with the same version in the application layer
+		zlib = ftypes.Package{
+			Name:    "zlib",
+			Version: "1.3.1-r0",
+			Layer:   appLayer,
+		}
+		// Installed in the application layer
+		curl = ftypes.Package{
+			Name:    "curl",
+			Version: "8.5.0-r0",
+			Layer:   appLayer,
+		}
+		lodash = ftypes.Package{
+			Name:     "lodash",
+			Version:  "4.17.4",
+			FilePath: "app/node_modules/lodash/package.json",
+			Layer:    appLayer,
+		}
+	)
+
+	base := types.Report{
+		ArtifactName: "alpine:3.19",
+		Metadata: types.Metadata{
+			DiffIDs: []string{"sha256:base"},
+		},
+		Results: types.Results{
+			{
+				Target: "alpine:3.19 (alpine 3.19.1)",
+				Class:  types.ClassOSPkg,
+				Type:   ftypes.Alpine,
+				Packages: []ftypes.Package{
+					musl,
+					{
+						Name:    "libcrypto3",
+						Version: "3.1.4-r4",
+						Layer:   baseLayer,
+					},
+					{
+						Name:    "zlib",
+						Version: "1.3.1-r0",
+						Layer:   baseLayer,
+					},
+				},
+			},
+		},
+	}
+
+	target := types.Report{
+		ArtifactName: "app:1.0",
+		Metadata: types.Metadata{
+			DiffIDs: []string{"sha256:base", "sha256:app"},
+		},
+		Results: types.Results{
+			{
+				Target:   "app:1.0 (alpine 3.19.1)",
+				Class:    types.ClassOSPkg,
+				Type:     ftypes.Alpine,
+				Packages: []ftypes.Package{musl, libcrypto, zlib, curl},
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2024-0001",
+						PkgName:          "musl",
+						InstalledVersion: "1.2.4-r2",
+						Layer:            baseLayer,
+					},
+					{
+						VulnerabilityID:  "CVE-2024-0002",
+						PkgName:          "libcrypto3",
+						InstalledVersion: "3.1.4-r5",
+						Layer:            appLayer,
+					},
+					{
+						VulnerabilityID:  "CVE-2024-0003",
+						PkgName:          "zlib",
+						InstalledVersion: "1.3.1-r0",
+						Layer:            appLayer,
+					},
+				},
+			},
+			{
+				Target:   "Node.js",
+				Class:    types.ClassLangPkg,
+				Type:     ftypes.NodePkg,
+				Packages: []ftypes.Package{lodash},
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID:  "CVE-2021-23337",
+						PkgName:          "lodash",
+						PkgPath:          "app/node_modules/lodash/package.json",
+						InstalledVersion: "4.17.4",
+						Layer:            appLayer,
+					},
+				},
+			},
+		},
+	}
+
+	want := types.Results{
+		{
+			Target:   "app:1.0 (alpine 3.19.1)",
+			Class:    types.ClassOSPkg,
+			Type:     ftypes.Alpine,
+			Packages: []ftypes.Package{libcrypto, curl},
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2024-0002",
+					PkgName:          "libcrypto3",
+					InstalledVersion: "3.1.4-r5",
+					Layer:            appLayer,
+				},
+			},
+		},
+		{
+			Target:   "Node.js",
+			Class:    types.ClassLangPkg,
+			Type:     ftypes.NodePkg,
+			Packages: []ftypes.Package{lodash},
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2021-23337",
+					PkgName:          "lodash",
+					PkgPath:          "app/node_modules/lodash/package.json",
+					InstalledVersion: "4.17.4",
+					Layer:            appLayer,
+				},
+			},
+		},
+	}
+
+	got := delta.Subtract(target, base)
+	assert.Equal(t, want, got.Results)
+	assert.Equal(t, "app:1.0", got.ArtifactName)
+}
@@ -0,0 +1,101 @@
+package jre
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/types"
+	xio "github.com/aquasecurity/trivy/pkg/x/io"
+)
+
+// The advisories for the Java runtime are published under this package name
+const pkgName = "java"
+
+var (
+	// e.g. 1.8.0_382, 1.8.0_382-b05
+	legacyVersionRegex = regexp.MustCompile(`^1\.(\d+)\.\d+_(\d+)(?:-b(\d+))?`)
+	// e.g. 17.0.8, 17.0.8+7, 21+35, 17.0.8.1+1-LTS
+	versionRegex = regexp.MustCompile(`^(\d+(?:\.\d+)*)(?:\+(\d+))?`)
+)
+
+type Parser struct{}
+
+func NewParser() types.Parser {
+	return &Parser{}
+}
+
+// Parse parses the "release" file shipped at the root of JDK and JRE distributions.
+// e.g.
+//
+//	JAVA_VERSION="17.0.8"
+//	JAVA_RUNTIME_VERSION="17.0.8+7"
+//	IMPLEMENTOR="Eclipse Adoptium"
+func (p *Parser) Parse(r xio.ReadSeekerAt) ([]types.Library, []types.Dependency, error) {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		fields[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, xerrors.Errorf("scan error: %w", err)
+	}
+
+	javaVersion := fields["JAVA_VERSION"]
+	if javaVersion == "" {
+		return nil, nil, xerrors.New("unable to find the Java version")
+	}
+
+	// The runtime version contains the build number, which is not available in JAVA_VERSION
+	ver := javaVersion
+	if runtimeVersion := fields["JAVA_RUNTIME_VERSION"]; strings.HasPrefix(runtimeVersion, javaVersion) {
+		ver = runtimeVersion
+	}
+
+	return []types.Library{
+		{
+			Name:    pkgName,
+			Version: normalizeVersion(ver),
+		},
+	}, nil, nil
+}
+
+// normalizeVersion converts Java versions to the "major.minor.patch-build" format used in the advisories.
+// e.g.
+//
+//	1.8.0_382-b05 => 1.8.382-5
+//	17.0.8+7      => 17.0.8-7
+//	21+35         => 21.0.0-35
+func normalizeVersion(ver string) string {
+	if m := legacyVersionRegex.FindStringSubmatch(ver); m != nil {
+		return withBuild("1."+m[1]+"."+m[2], m[3])
+	}
+
+	m := versionRegex.FindStringSubmatch(ver)
+	if m == nil {
+		return ver
+	}
+	parts := strings.Split(m[1], ".")
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	return withBuild(strings.Join(parts, "."), m[2])
+}
+
+func withBuild(ver, build string) string {
+	if build == "" {
+		return ver
+	}
+	// Trim leading zeros, e.g. "b05"
+	if n, err := strconv.Atoi(build); err == nil {
+		build = strconv.Itoa(n)
+	}
+	return ver + "-" + build
+}
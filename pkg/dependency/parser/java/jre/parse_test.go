@@ -0,0 +1,59 @@
+package jre_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/java/jre"
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/types"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []types.Library
+		wantErr string
+	}{
+		{
+			name:  "Java 17 with build number",
+			input: "testdata/temurin-17",
+			want:  []types.Library{{Name: "java", Version: "17.0.8-7"}},
+		},
+		{
+			name:  "Java 8",
+			input: "testdata/temurin-8",
+			want:  []types.Library{{Name: "java", Version: "1.8.382-5"}},
+		},
+		{
+			name:  "GA release without build number",
+			input: "testdata/openjdk-21",
+			want:  []types.Library{{Name: "java", Version: "21.0.0"}},
+		},
+		{
+			name:    "no version",
+			input:   "testdata/no-version",
+			wantErr: "unable to find the Java version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.input)
+			require.NoError(t, err)
+			defer f.Close()
+
+			got, _, err := jre.NewParser().Parse(f)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -34,10 +34,16 @@ func NewParser() types.Parser {
 	return &Parser{}
 }
 
-// Parse scans files to try to report Rust crates and version injected into Rust binaries
+// Parse scans files to try to report Rust crates and version injected into Rust binaries and WebAssembly modules
 // via https://github.com/rust-secure-code/cargo-auditable
 func (p *Parser) Parse(r xio.ReadSeekerAt) ([]types.Library, []types.Dependency, error) {
-	info, err := rustaudit.GetDependencyInfo(r)
+	var info rustaudit.VersionInfo
+	var err error
+	if isWasm(r) {
+		info, err = wasmDependencyInfo(r)
+	} else {
+		info, err = rustaudit.GetDependencyInfo(r)
+	}
 	if err != nil {
 		return nil, nil, convertError(err)
 	}
@@ -63,6 +63,17 @@ func TestParse(t *testing.T) {
 			want:      libs,
 			wantDeps:  deps,
 		},
+		{
+			name:      "WebAssembly",
+			inputFile: "testdata/test.wasm",
+			want:      libs,
+			wantDeps:  deps,
+		},
+		{
+			name:      "WebAssembly without dependencies",
+			inputFile: "testdata/no-deps.wasm",
+			wantErr:   "non Rust auditable binary",
+		},
 		{
 			name:      "sad path",
 			inputFile: "testdata/dummy",
@@ -0,0 +1,116 @@
+package binary
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+
+	rustaudit "github.com/microsoft/go-rustaudit"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// cargo-auditable embeds the dependencies in this custom section of WebAssembly modules
+	// cf. https://github.com/rust-secure-code/cargo-auditable/blob/master/PARAMETERS.md
+	wasmDepSection = ".dep-v0"
+
+	wasmCustomSectionID = 0
+)
+
+var wasmHeader = []byte("\x00asm")
+
+// isWasm returns true if the file starts with the WebAssembly magic number
+func isWasm(r io.ReaderAt) bool {
+	header := make([]byte, len(wasmHeader))
+	if n, err := r.ReadAt(header, 0); n < len(header) || err != nil {
+		return false
+	}
+	return bytes.Equal(header, wasmHeader)
+}
+
+// wasmDependencyInfo reads the dependencies from the custom section of the WebAssembly module,
+// as go-rustaudit supports only ELF, PE and Mach-O.
+func wasmDependencyInfo(r io.ReadSeeker) (rustaudit.VersionInfo, error) {
+	// Skip the magic number and the version
+	if _, err := r.Seek(8, io.SeekStart); err != nil {
+		return rustaudit.VersionInfo{}, xerrors.Errorf("seek error: %w", err)
+	}
+	br := bufio.NewReader(r)
+
+	for {
+		id, err := br.ReadByte()
+		if err == io.EOF {
+			return rustaudit.VersionInfo{}, ErrNonRustBinary
+		} else if err != nil {
+			return rustaudit.VersionInfo{}, xerrors.Errorf("section read error: %w", err)
+		}
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return rustaudit.VersionInfo{}, xerrors.Errorf("section size error: %w", err)
+		}
+		section := io.LimitReader(br, int64(size))
+
+		if id == wasmCustomSectionID {
+			found, err := wasmSectionName(section, wasmDepSection)
+			if err != nil {
+				return rustaudit.VersionInfo{}, xerrors.Errorf("section name error: %w", err)
+			}
+			if found {
+				return decodeDependencyInfo(section)
+			}
+		}
+
+		// Skip the rest of the section
+		if _, err = io.Copy(io.Discard, section); err != nil {
+			return rustaudit.VersionInfo{}, xerrors.Errorf("section read error: %w", err)
+		}
+	}
+}
+
+// wasmSectionName reads the name of the custom section and returns true if it is the given name
+func wasmSectionName(r io.Reader, want string) (bool, error) {
+	length, err := binary.ReadUvarint(byteReader{r})
+	if err != nil {
+		return false, err
+	}
+	if length != uint64(len(want)) {
+		_, err = io.CopyN(io.Discard, r, int64(length))
+		return false, err
+	}
+	name := make([]byte, length)
+	if _, err = io.ReadFull(r, name); err != nil {
+		return false, err
+	}
+	return string(name) == want, nil
+}
+
+// decodeDependencyInfo decodes the zlib-compressed JSON in the same way as go-rustaudit
+func decodeDependencyInfo(r io.Reader) (rustaudit.VersionInfo, error) {
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return rustaudit.VersionInfo{}, xerrors.Errorf("zlib error: %w", err)
+	}
+	defer zr.Close()
+
+	var info rustaudit.VersionInfo
+	if err = json.NewDecoder(zr).Decode(&info); err != nil {
+		return rustaudit.VersionInfo{}, xerrors.Errorf("json decode error: %w", err)
+	}
+	return info, nil
+}
+
+// byteReader reads the LEB128 integer from the section without reading ahead of it
+type byteReader struct {
+	io.Reader
+}
+
+func (r byteReader) ReadByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(r.Reader, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
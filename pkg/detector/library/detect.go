@@ -1,14 +1,39 @@
 package library
 
 import (
+	"context"
+
 	"golang.org/x/xerrors"
 
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/osv"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
-// Detect scans and returns vulnerabilities of library
-func Detect(libType ftypes.LangType, pkgs []ftypes.Package) ([]types.DetectedVulnerability, error) {
+// onlineEcosystems are not covered by trivy-db and looked up in OSV.dev when the online lookup is enabled
+var onlineEcosystems = map[ftypes.LangType]osv.Ecosystem{
+	ftypes.Hackage: "Hackage",
+	ftypes.CRAN:    "CRAN",
+}
+
+// Detect scans and returns vulnerabilities of library.
+// The online source is nil unless the online lookup is enabled.
+func Detect(ctx context.Context, libType ftypes.LangType, pkgs []ftypes.Package, online *advisory.OnlineSource) (
+	[]types.DetectedVulnerability, error) {
+	if ecosystem, ok := onlineEcosystems[libType]; ok {
+		if online == nil {
+			log.Logger.Warnf("The %q library type is supported only with '--osv-online'", libType)
+			return nil, nil
+		}
+		vulns, err := online.Detect(ctx, ecosystem, pkgs)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to look up %s vulnerabilities online: %w", libType, err)
+		}
+		return vulns, nil
+	}
+
 	driver, ok := NewDriver(libType)
 	if !ok {
 		return nil, nil
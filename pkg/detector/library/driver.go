@@ -10,6 +10,7 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/detector/library/compare"
 	"github.com/aquasecurity/trivy/pkg/detector/library/compare/bitnami"
 	"github.com/aquasecurity/trivy/pkg/detector/library/compare/maven"
@@ -78,6 +79,10 @@ func NewDriver(libType ftypes.LangType) (Driver, bool) {
 	case ftypes.Bitnami:
 		ecosystem = vulnerability.Bitnami
 		comparer = bitnami.Comparer{}
+	case ftypes.JRE:
+		// OpenJDK advisories are taken from the Bitnami Vulnerability Database
+		ecosystem = vulnerability.Bitnami
+		comparer = bitnami.Comparer{}
 	case ftypes.K8sUpstream:
 		ecosystem = vulnerability.Kubernetes
 		comparer = compare.GenericComparer{}
@@ -111,11 +116,15 @@ func (d *Driver) Type() string {
 func (d *Driver) DetectVulnerabilities(pkgID, pkgName, pkgVer string) ([]types.DetectedVulnerability, error) {
 	// e.g. "pip::", "npm::"
 	prefix := fmt.Sprintf("%s::", d.ecosystem)
-	advisories, err := d.dbc.GetAdvisories(prefix, vulnerability.NormalizePkgName(d.ecosystem, pkgName))
+	normalizedName := vulnerability.NormalizePkgName(d.ecosystem, pkgName)
+	advisories, err := d.dbc.GetAdvisories(prefix, normalizedName)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to get %s advisories: %w", d.ecosystem, err)
 	}
 
+	// Merge advisories from additional sources such as internal feeds
+	advisories = append(advisories, advisory.Advisories(d.ecosystem, normalizedName)...)
+
 	var vulns []types.DetectedVulnerability
 	for _, adv := range advisories {
 		if !d.comparer.IsVulnerable(pkgVer, adv) {
@@ -9,6 +9,7 @@ import (
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/dbtest"
 	"github.com/aquasecurity/trivy/pkg/detector/library"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
@@ -21,12 +22,13 @@ func TestDriver_Detect(t *testing.T) {
 		pkgVer  string
 	}
 	tests := []struct {
-		name     string
-		fixtures []string
-		libType  ftypes.LangType
-		args     args
-		want     []types.DetectedVulnerability
-		wantErr  string
+		name            string
+		fixtures        []string
+		advisorySources []string
+		libType         ftypes.LangType
+		args            args
+		want            []types.DetectedVulnerability
+		wantErr         string
 	}{
 		{
 			name: "happy path",
@@ -138,6 +140,32 @@ func TestDriver_Detect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "additional advisory source",
+			fixtures: []string{
+				"testdata/fixtures/data-source.yaml",
+			},
+			advisorySources: []string{
+				"osv:../../advisory/testdata/osv",
+			},
+			libType: ftypes.Npm,
+			args: args{
+				pkgName: "@acme/utils",
+				pkgVer:  "1.4.1",
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "INTERNAL-2024-0001",
+					PkgName:          "@acme/utils",
+					InstalledVersion: "1.4.1",
+					FixedVersion:     "1.4.2",
+					DataSource: &dbTypes.DataSource{
+						ID:   "osv:osv",
+						Name: "OSV feed ../../advisory/testdata/osv",
+					},
+				},
+			},
+		},
 		{
 			name:     "no vulnerability",
 			fixtures: []string{"testdata/fixtures/php.yaml"},
@@ -189,6 +217,9 @@ func TestDriver_Detect(t *testing.T) {
 			_ = dbtest.InitDB(t, tt.fixtures)
 			defer db.Close()
 
+			require.NoError(t, advisory.Load(tt.advisorySources))
+			defer advisory.DeregisterSources()
+
 			driver, ok := library.NewDriver(tt.libType)
 			require.True(t, ok)
 
@@ -0,0 +1,351 @@
+package exploit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	DefaultKEVURL  = "https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json"
+	DefaultEPSSURL = "https://epss.cyentia.com/epss_scores-current.csv.gz"
+
+	// SeveritySourceEPSS is set to vulnerabilities whose severity is derived from EPSS and KEV
+	SeveritySourceEPSS dbTypes.SourceID = "epss"
+
+	kevFile      = "kev.json"
+	epssFile     = "epss.csv.gz"
+	metadataFile = "metadata.json"
+
+	updateInterval = 24 * time.Hour
+)
+
+type Metadata struct {
+	KEVCatalogVersion string
+	EPSSModelVersion  string
+	EPSSScoreDate     string
+	DownloadedAt      time.Time
+}
+
+type options struct {
+	kevURL  string
+	epssURL string
+}
+
+type Option func(*options)
+
+// WithKEVURL takes the URL of the CISA KEV catalog
+func WithKEVURL(url string) Option {
+	return func(opts *options) {
+		opts.kevURL = url
+	}
+}
+
+// WithEPSSURL takes the URL of the gzipped EPSS scores
+func WithEPSSURL(url string) Option {
+	return func(opts *options) {
+		opts.epssURL = url
+	}
+}
+
+// Client downloads and loads the exploit data, the CISA KEV catalog and EPSS scores.
+type Client struct {
+	dir     string
+	kevURL  string
+	epssURL string
+}
+
+func NewClient(cacheDir string, opts ...Option) *Client {
+	o := &options{
+		kevURL:  DefaultKEVURL,
+		epssURL: DefaultEPSSURL,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Client{
+		dir:     filepath.Join(cacheDir, "exploit-db"),
+		kevURL:  o.kevURL,
+		epssURL: o.epssURL,
+	}
+}
+
+// NeedsUpdate returns true if the exploit data doesn't exist or is older than a day.
+func (c *Client) NeedsUpdate(ctx context.Context, skip bool) (bool, error) {
+	meta, err := c.metadata()
+	if errors.Is(err, os.ErrNotExist) {
+		if skip {
+			log.Logger.Error("The first run cannot skip downloading the exploit data")
+			return false, xerrors.New("'--skip-db-update' cannot be specified on the first run")
+		}
+		return true, nil
+	} else if err != nil {
+		return false, xerrors.Errorf("exploit data metadata error: %w", err)
+	}
+
+	if skip {
+		log.Logger.Debug("Skipping the exploit data update...")
+		return false, nil
+	}
+	return meta.DownloadedAt.Add(updateInterval).Before(clock.Now(ctx)), nil
+}
+
+// Download fetches the CISA KEV catalog and EPSS scores into the cache directory.
+func (c *Client) Download(ctx context.Context) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return xerrors.Errorf("mkdir error: %w", err)
+	}
+
+	if err := c.download(ctx, c.kevURL, kevFile); err != nil {
+		return xerrors.Errorf("CISA KEV download error: %w", err)
+	}
+	if err := c.download(ctx, c.epssURL, epssFile); err != nil {
+		return xerrors.Errorf("EPSS download error: %w", err)
+	}
+
+	db, err := c.Load()
+	if err != nil {
+		return xerrors.Errorf("exploit data error: %w", err)
+	}
+
+	meta := Metadata{
+		KEVCatalogVersion: db.kevVersion,
+		EPSSModelVersion:  db.epssModelVersion,
+		EPSSScoreDate:     db.epssScoreDate,
+		DownloadedAt:      clock.Now(ctx).UTC(),
+	}
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return xerrors.Errorf("json marshal error: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(c.dir, metadataFile), b, 0o600); err != nil {
+		return xerrors.Errorf("metadata write error: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) download(ctx context.Context, url, fileName string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return xerrors.Errorf("request error: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("unexpected status code from %s: %d", url, resp.StatusCode)
+	}
+
+	// Write to a temporary file first so that a broken download doesn't replace the cached data
+	f, err := os.CreateTemp(c.dir, fileName+".*")
+	if err != nil {
+		return xerrors.Errorf("temp file error: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = io.Copy(f, resp.Body); err != nil {
+		_ = f.Close()
+		return xerrors.Errorf("copy error: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return xerrors.Errorf("close error: %w", err)
+	}
+	return os.Rename(f.Name(), filepath.Join(c.dir, fileName))
+}
+
+func (c *Client) metadata() (Metadata, error) {
+	f, err := os.Open(filepath.Join(c.dir, metadataFile))
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer f.Close()
+
+	var meta Metadata
+	if err = json.NewDecoder(f).Decode(&meta); err != nil {
+		return Metadata{}, xerrors.Errorf("json decode error: %w", err)
+	}
+	return meta, nil
+}
+
+// DB holds the exploit data keyed by CVE-ID.
+type DB struct {
+	kev  map[string]types.KEV
+	epss map[string]types.EPSS
+
+	kevVersion       string
+	epssModelVersion string
+	epssScoreDate    string
+}
+
+// Load reads the downloaded CISA KEV catalog and EPSS scores.
+func (c *Client) Load() (*DB, error) {
+	db := &DB{}
+	if err := db.loadKEV(filepath.Join(c.dir, kevFile)); err != nil {
+		return nil, xerrors.Errorf("CISA KEV error: %w", err)
+	}
+	if err := db.loadEPSS(filepath.Join(c.dir, epssFile)); err != nil {
+		return nil, xerrors.Errorf("EPSS error: %w", err)
+	}
+	return db, nil
+}
+
+type kevCatalog struct {
+	CatalogVersion  string `json:"catalogVersion"`
+	Vulnerabilities []struct {
+		CveID                      string `json:"cveID"`
+		DateAdded                  string `json:"dateAdded"`
+		DueDate                    string `json:"dueDate"`
+		KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+	} `json:"vulnerabilities"`
+}
+
+func (d *DB) loadKEV(filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	var catalog kevCatalog
+	if err = json.NewDecoder(f).Decode(&catalog); err != nil {
+		return xerrors.Errorf("json decode error: %w", err)
+	}
+
+	d.kevVersion = catalog.CatalogVersion
+	d.kev = make(map[string]types.KEV, len(catalog.Vulnerabilities))
+	for _, v := range catalog.Vulnerabilities {
+		d.kev[v.CveID] = types.KEV{
+			DateAdded:                  v.DateAdded,
+			DueDate:                    v.DueDate,
+			KnownRansomwareCampaignUse: v.KnownRansomwareCampaignUse,
+		}
+	}
+	return nil
+}
+
+// loadEPSS parses the EPSS scores in the following format.
+//
+//	#model_version:v2023.03.01,score_date:2024-03-01T00:00:00+0000
+//	cve,epss,percentile
+//	CVE-1999-0001,0.0109,0.83816
+func (d *DB) loadEPSS(filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return xerrors.Errorf("gzip error: %w", err)
+	}
+	defer gr.Close()
+
+	br := bufio.NewReader(gr)
+	if b, err := br.Peek(1); err == nil && b[0] == '#' {
+		comment, err := br.ReadString('\n')
+		if err != nil {
+			return xerrors.Errorf("read error: %w", err)
+		}
+		for _, field := range strings.Split(strings.TrimSpace(strings.TrimPrefix(comment, "#")), ",") {
+			key, value, _ := strings.Cut(field, ":")
+			switch key {
+			case "model_version":
+				d.epssModelVersion = value
+			case "score_date":
+				d.epssScoreDate = value
+			}
+		}
+	}
+
+	r := csv.NewReader(br)
+	r.FieldsPerRecord = 3
+	records, err := r.ReadAll()
+	if err != nil {
+		return xerrors.Errorf("csv error: %w", err)
+	}
+
+	d.epss = make(map[string]types.EPSS, len(records))
+	for _, record := range records {
+		// Skip the header
+		if record[0] == "cve" {
+			continue
+		}
+		score, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return xerrors.Errorf("invalid EPSS score of %s: %w", record[0], err)
+		}
+		percentile, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return xerrors.Errorf("invalid EPSS percentile of %s: %w", record[0], err)
+		}
+		d.epss[record[0]] = types.EPSS{
+			Score:      score,
+			Percentile: percentile,
+		}
+	}
+	return nil
+}
+
+// Fill fills in EPSS and KEV of the given vulnerabilities.
+// Vendor IDs are also looked up since some advisories are not identified by CVE-ID.
+func (d *DB) Fill(vulns []types.DetectedVulnerability) {
+	for i := range vulns {
+		for _, id := range append([]string{vulns[i].VulnerabilityID}, vulns[i].VendorIDs...) {
+			if epss, ok := d.epss[id]; ok && vulns[i].EPSS == nil {
+				vulns[i].EPSS = &epss
+			}
+			if kev, ok := d.kev[id]; ok && vulns[i].KEV == nil {
+				vulns[i].KEV = &kev
+			}
+		}
+	}
+}
+
+// SetSeverity replaces the severity of the given vulnerabilities with the one derived from EPSS and KEV.
+// Vulnerabilities in the KEV catalog are CRITICAL as they are exploited in the wild.
+func SetSeverity(vulns []types.DetectedVulnerability) {
+	for i := range vulns {
+		vulns[i].Severity = Severity(vulns[i].EPSS, vulns[i].KEV != nil).String()
+		vulns[i].SeveritySource = SeveritySourceEPSS
+	}
+}
+
+// Severity maps the probability of exploitation to severity.
+func Severity(epss *types.EPSS, kev bool) dbTypes.Severity {
+	switch {
+	case kev:
+		return dbTypes.SeverityCritical
+	case epss == nil:
+		return dbTypes.SeverityUnknown
+	case epss.Score >= 0.5:
+		return dbTypes.SeverityCritical
+	case epss.Score >= 0.1:
+		return dbTypes.SeverityHigh
+	case epss.Score >= 0.01:
+		return dbTypes.SeverityMedium
+	default:
+		return dbTypes.SeverityLow
+	}
+}
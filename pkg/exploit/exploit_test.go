@@ -0,0 +1,173 @@
+package exploit_test
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/exploit"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/kev.json":
+			http.ServeFile(w, r, "testdata/kev.json")
+		case "/epss.csv.gz":
+			b, err := os.ReadFile("testdata/epss.csv")
+			require.NoError(t, err)
+			gw := gzip.NewWriter(w)
+			_, err = gw.Write(b)
+			require.NoError(t, err)
+			require.NoError(t, gw.Close())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestClient(t *testing.T) {
+	ts := newServer(t)
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		kevURL  string
+		vulns   []types.DetectedVulnerability
+		want    []types.DetectedVulnerability
+		wantErr string
+	}{
+		{
+			name:   "happy path",
+			kevURL: ts.URL + "/kev.json",
+			vulns: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "CVE-2021-44228",
+				},
+				{
+					VulnerabilityID: "GHSA-xxxx-yyyy-zzzz",
+					VendorIDs:       []string{"CVE-2022-0001"},
+				},
+				{
+					VulnerabilityID: "CVE-2023-0001",
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "CVE-2021-44228",
+					EPSS: &types.EPSS{
+						Score:      0.97565,
+						Percentile: 0.99996,
+					},
+					KEV: &types.KEV{
+						DateAdded:                  "2021-12-10",
+						DueDate:                    "2021-12-24",
+						KnownRansomwareCampaignUse: "Known",
+					},
+				},
+				{
+					VulnerabilityID: "GHSA-xxxx-yyyy-zzzz",
+					VendorIDs:       []string{"CVE-2022-0001"},
+					EPSS: &types.EPSS{
+						Score:      0.12,
+						Percentile: 0.95,
+					},
+				},
+				{
+					VulnerabilityID: "CVE-2023-0001",
+				},
+			},
+		},
+		{
+			name:    "sad path",
+			kevURL:  ts.URL + "/unknown.json",
+			wantErr: "unexpected status code",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := clock.With(context.Background(), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+			c := exploit.NewClient(t.TempDir(), exploit.WithKEVURL(tt.kevURL), exploit.WithEPSSURL(ts.URL+"/epss.csv.gz"))
+
+			needsUpdate, err := c.NeedsUpdate(ctx, false)
+			require.NoError(t, err)
+			assert.True(t, needsUpdate)
+
+			err = c.Download(ctx)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// The downloaded data is cached for a day
+			needsUpdate, err = c.NeedsUpdate(ctx, false)
+			require.NoError(t, err)
+			assert.False(t, needsUpdate)
+
+			needsUpdate, err = c.NeedsUpdate(clock.With(ctx, time.Date(2024, 3, 2, 1, 0, 0, 0, time.UTC)), false)
+			require.NoError(t, err)
+			assert.True(t, needsUpdate)
+
+			db, err := c.Load()
+			require.NoError(t, err)
+
+			db.Fill(tt.vulns)
+			assert.Equal(t, tt.want, tt.vulns)
+		})
+	}
+}
+
+func TestClient_NeedsUpdate(t *testing.T) {
+	c := exploit.NewClient(t.TempDir())
+	_, err := c.NeedsUpdate(context.Background(), true)
+	assert.ErrorContains(t, err, "'--skip-db-update' cannot be specified on the first run")
+}
+
+func TestSetSeverity(t *testing.T) {
+	vulns := []types.DetectedVulnerability{
+		{
+			VulnerabilityID: "CVE-2021-44228",
+			EPSS:            &types.EPSS{Score: 0.001},
+			KEV:             &types.KEV{DateAdded: "2021-12-10"},
+		},
+		{
+			VulnerabilityID: "CVE-2022-0001",
+			EPSS:            &types.EPSS{Score: 0.5},
+		},
+		{
+			VulnerabilityID: "CVE-2022-0002",
+			EPSS:            &types.EPSS{Score: 0.1},
+		},
+		{
+			VulnerabilityID: "CVE-2022-0003",
+			EPSS:            &types.EPSS{Score: 0.01},
+		},
+		{
+			VulnerabilityID: "CVE-2022-0004",
+			EPSS:            &types.EPSS{Score: 0.0005},
+		},
+		{
+			VulnerabilityID: "CVE-2022-0005",
+			Vulnerability:   dbTypes.Vulnerability{Severity: "HIGH"},
+		},
+	}
+	exploit.SetSeverity(vulns)
+
+	var got []string
+	for _, v := range vulns {
+		assert.Equal(t, exploit.SeveritySourceEPSS, v.SeveritySource)
+		got = append(got, v.Severity)
+	}
+	assert.Equal(t, []string{"CRITICAL", "CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"}, got)
+}
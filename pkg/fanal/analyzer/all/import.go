@@ -1,7 +1,10 @@
 package all
 
 import (
+	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/bootconfig"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/buildinfo"
+	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/certificate"
+	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/cloudinit"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/config/all"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/executable"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/imgconf/apk"
@@ -18,6 +21,7 @@ import (
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/language/golang/mod"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/language/java/gradle"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/language/java/jar"
+	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/language/java/jre"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/language/java/pom"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/language/nodejs/npm"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/language/nodejs/pkg"
@@ -42,10 +46,13 @@ import (
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/os/redhatbase"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/os/release"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/os/ubuntu"
+	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/os/windows"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/pkg/apk"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/pkg/dpkg"
+	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/pkg/hotfix"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/pkg/rpm"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/repo/apk"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/sbom"
 	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/secret"
+	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/ssh"
 )
@@ -9,12 +9,14 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/samber/lo"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/semaphore"
 	"golang.org/x/xerrors"
 
+	"github.com/aquasecurity/trivy/pkg/debugreport"
 	fos "github.com/aquasecurity/trivy/pkg/fanal/analyzer/os"
 	"github.com/aquasecurity/trivy/pkg/fanal/log"
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
@@ -50,7 +52,8 @@ type AnalyzerOptions struct {
 }
 
 type SecretScannerOption struct {
-	ConfigPath string
+	ConfigPath      string
+	FingerprintSalt string
 }
 
 type LicenseScannerOption struct {
@@ -157,6 +160,7 @@ type AnalysisResult struct {
 	Misconfigurations    []types.Misconfiguration
 	Secrets              []types.Secret
 	Licenses             []types.LicenseFile
+	Certificates         []types.CertificateInventory
 	SystemInstalledFiles []string // A list of files installed by OS package manager
 
 	// Digests contains SHA-256 digests of unpackaged files
@@ -176,10 +180,11 @@ func NewAnalysisResult() *AnalysisResult {
 	return result
 }
 
-func (r *AnalysisResult) isEmpty() bool {
+// IsEmpty reports whether nothing has been detected
+func (r *AnalysisResult) IsEmpty() bool {
 	return lo.IsEmpty(r.OS) && r.Repository == nil && len(r.PackageInfos) == 0 && len(r.Applications) == 0 &&
-		len(r.Misconfigurations) == 0 && len(r.Secrets) == 0 && len(r.Licenses) == 0 && len(r.SystemInstalledFiles) == 0 &&
-		r.BuildInfo == nil && len(r.Digests) == 0 && len(r.CustomResources) == 0
+		len(r.Misconfigurations) == 0 && len(r.Secrets) == 0 && len(r.Licenses) == 0 && len(r.Certificates) == 0 &&
+		len(r.SystemInstalledFiles) == 0 && r.BuildInfo == nil && len(r.Digests) == 0 && len(r.CustomResources) == 0
 }
 
 func (r *AnalysisResult) Sort() {
@@ -227,6 +232,11 @@ func (r *AnalysisResult) Sort() {
 		})
 	}
 
+	// Certificates
+	sort.Slice(r.Certificates, func(i, j int) bool {
+		return r.Certificates[i].FilePath < r.Certificates[j].FilePath
+	})
+
 	// License files
 	sort.Slice(r.Licenses, func(i, j int) bool {
 		if r.Licenses[i].Type == r.Licenses[j].Type {
@@ -241,8 +251,34 @@ func (r *AnalysisResult) Sort() {
 	})
 }
 
+// setProvenance records the analyzer on packages that don't have provenance yet.
+// The file path of the package list is preferred as post-analyzers analyze multiple files.
+func (r *AnalysisResult) setProvenance(analyzerType Type, version int, filePath string) {
+	if r == nil {
+		return
+	}
+	provenance := func(pkgs []types.Package, path string) {
+		for i := range pkgs {
+			if pkgs[i].Provenance != nil {
+				continue
+			}
+			pkgs[i].Provenance = &types.Provenance{
+				Analyzer: string(analyzerType),
+				Version:  version,
+				FilePath: lo.Ternary(path != "", path, filePath),
+			}
+		}
+	}
+	for _, pkgInfo := range r.PackageInfos {
+		provenance(pkgInfo.Packages, pkgInfo.FilePath)
+	}
+	for _, app := range r.Applications {
+		provenance(app.Libraries, app.FilePath)
+	}
+}
+
 func (r *AnalysisResult) Merge(newResult *AnalysisResult) {
-	if newResult == nil || newResult.isEmpty() {
+	if newResult == nil || newResult.IsEmpty() {
 		return
 	}
 
@@ -272,6 +308,7 @@ func (r *AnalysisResult) Merge(newResult *AnalysisResult) {
 	r.Misconfigurations = append(r.Misconfigurations, newResult.Misconfigurations...)
 	r.Secrets = append(r.Secrets, newResult.Secrets...)
 	r.Licenses = append(r.Licenses, newResult.Licenses...)
+	r.Certificates = append(r.Certificates, newResult.Certificates...)
 	r.SystemInstalledFiles = append(r.SystemInstalledFiles, newResult.SystemInstalledFiles...)
 
 	if newResult.BuildInfo != nil {
@@ -400,6 +437,8 @@ func (ag AnalyzerGroup) AnalyzeFile(ctx context.Context, wg *sync.WaitGroup, lim
 	// filepath extracted from tar file doesn't have the prefix "/"
 	cleanPath := strings.TrimLeft(filePath, "/")
 
+	collector := debugreport.FromContext(ctx)
+	var required bool
 	for _, a := range ag.analyzers {
 		// Skip disabled analyzers
 		if slices.Contains(disabled, a.Type()) {
@@ -409,6 +448,7 @@ func (ag AnalyzerGroup) AnalyzeFile(ctx context.Context, wg *sync.WaitGroup, lim
 		if !ag.filePatternMatch(a.Type(), cleanPath) && !a.Required(cleanPath, info) {
 			continue
 		}
+		required = true
 		rc, err := opener()
 		if errors.Is(err, fs.ErrPermission) {
 			log.Logger.Debugf("Permission error: %s", filePath)
@@ -418,6 +458,7 @@ func (ag AnalyzerGroup) AnalyzeFile(ctx context.Context, wg *sync.WaitGroup, lim
 		}
 
 		if err = limit.Acquire(ctx, 1); err != nil {
+			_ = rc.Close()
 			return xerrors.Errorf("semaphore acquire: %w", err)
 		}
 		wg.Add(1)
@@ -427,6 +468,7 @@ func (ag AnalyzerGroup) AnalyzeFile(ctx context.Context, wg *sync.WaitGroup, lim
 			defer wg.Done()
 			defer rc.Close()
 
+			start := time.Now()
 			ret, err := a.Analyze(ctx, AnalysisInput{
 				Dir:      dir,
 				FilePath: filePath,
@@ -434,17 +476,49 @@ func (ag AnalyzerGroup) AnalyzeFile(ctx context.Context, wg *sync.WaitGroup, lim
 				Content:  rc,
 				Options:  opts,
 			})
+			collector.AddAnalysis(string(a.Type()), time.Since(start), info.Size())
 			if err != nil && !errors.Is(err, fos.AnalyzeOSError) {
 				log.Logger.Debugf("Analysis error: %s", err)
 				return
 			}
+			ret.setProvenance(a.Type(), a.Version(), filePath)
 			result.Merge(ret)
 		}(a, rc)
 	}
 
+	if collector != nil && !required && len(ag.RequiredPostAnalyzers(cleanPath, info)) == 0 {
+		collector.AddSkip(filePath, ag.skipReason(cleanPath, info, disabled))
+	}
+
 	return nil
 }
 
+// sizelessFileInfo hides the file size so that Required can be evaluated without the size limit
+type sizelessFileInfo struct {
+	os.FileInfo
+}
+
+func (sizelessFileInfo) Size() int64 {
+	return 0
+}
+
+// skipReason returns why no analyzer requires the file.
+// It is called only when the debug report is enabled as Required is evaluated again for all analyzers.
+func (ag AnalyzerGroup) skipReason(filePath string, info os.FileInfo, disabled []Type) debugreport.SkipReason {
+	sizeless := sizelessFileInfo{FileInfo: info}
+	for _, a := range ag.analyzers {
+		if !slices.Contains(disabled, a.Type()) && a.Required(filePath, sizeless) {
+			return debugreport.SkipReasonSizeLimit
+		}
+	}
+	for _, a := range ag.postAnalyzers {
+		if a.Required(filePath, sizeless) {
+			return debugreport.SkipReasonSizeLimit
+		}
+	}
+	return debugreport.SkipReasonUnsupported
+}
+
 // RequiredPostAnalyzers returns a list of analyzer types that require the given file.
 func (ag AnalyzerGroup) RequiredPostAnalyzers(filePath string, info os.FileInfo) []Type {
 	if info.IsDir() {
@@ -488,13 +562,16 @@ func (ag AnalyzerGroup) PostAnalyze(ctx context.Context, compositeFS *CompositeF
 			return xerrors.Errorf("unable to filter filesystem: %w", err)
 		}
 
+		start := time.Now()
 		res, err := a.PostAnalyze(ctx, PostAnalysisInput{
 			FS:      filteredFS,
 			Options: opts,
 		})
+		debugreport.FromContext(ctx).AddPostAnalysis(string(a.Type()), time.Since(start))
 		if err != nil {
 			return xerrors.Errorf("post analysis error: %w", err)
 		}
+		res.setProvenance(a.Type(), a.Version(), "")
 		result.Merge(res)
 	}
 	return nil
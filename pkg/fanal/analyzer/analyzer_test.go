@@ -13,6 +13,7 @@ import (
 	"golang.org/x/xerrors"
 
 	xio "github.com/aquasecurity/trivy/pkg/x/io"
+	"github.com/aquasecurity/trivy/pkg/debugreport"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/javadb"
@@ -344,6 +345,11 @@ func TestAnalyzerGroup_AnalyzeFile(t *testing.T) {
 								Arch:           "x86_64",
 								Digest:         "sha1:cb2316a189ebee5282c4a9bd98794cc2477a74c6",
 								InstalledFiles: []string{"lib/libc.musl-x86_64.so.1", "lib/ld-musl-x86_64.so.1"},
+								Provenance: &types.Provenance{
+									Analyzer: "apk",
+									Version:  2,
+									FilePath: "/lib/apk/db/installed",
+								},
 							},
 						},
 					},
@@ -389,6 +395,11 @@ func TestAnalyzerGroup_AnalyzeFile(t *testing.T) {
 										EndLine:   4,
 									},
 								},
+								Provenance: &types.Provenance{
+									Analyzer: "bundler",
+									Version:  1,
+									FilePath: "/app/Gemfile.lock",
+								},
 							},
 							{
 								ID:       "actionpack@5.2.3",
@@ -401,6 +412,11 @@ func TestAnalyzerGroup_AnalyzeFile(t *testing.T) {
 										EndLine:   6,
 									},
 								},
+								Provenance: &types.Provenance{
+									Analyzer: "bundler",
+									Version:  1,
+									FilePath: "/app/Gemfile.lock",
+								},
 							},
 						},
 					},
@@ -450,6 +466,11 @@ func TestAnalyzerGroup_AnalyzeFile(t *testing.T) {
 										EndLine:   4,
 									},
 								},
+								Provenance: &types.Provenance{
+									Analyzer: "bundler",
+									Version:  1,
+									FilePath: "/app/Gemfile-dev.lock",
+								},
 							},
 							{
 								ID:       "actionpack@5.2.3",
@@ -462,6 +483,11 @@ func TestAnalyzerGroup_AnalyzeFile(t *testing.T) {
 										EndLine:   6,
 									},
 								},
+								Provenance: &types.Provenance{
+									Analyzer: "bundler",
+									Version:  1,
+									FilePath: "/app/Gemfile-dev.lock",
+								},
 							},
 						},
 					},
@@ -552,6 +578,46 @@ func TestAnalyzerGroup_AnalyzeFile(t *testing.T) {
 	}
 }
 
+func TestAnalyzerGroup_AnalyzeFile_DebugReport(t *testing.T) {
+	a, err := analyzer.NewAnalyzerGroup(analyzer.AnalyzerOptions{})
+	require.NoError(t, err)
+
+	collector := debugreport.NewCollector()
+	ctx := debugreport.With(context.Background(), collector)
+
+	var wg sync.WaitGroup
+	limit := semaphore.NewWeighted(3)
+	got := new(analyzer.AnalysisResult)
+	for filePath, testFilePath := range map[string]string{
+		"/etc/alpine-release": "testdata/etc/alpine-release",
+		"/etc/hostname":       "testdata/etc/hostname",
+	} {
+		info, err := os.Stat(testFilePath)
+		require.NoError(t, err)
+
+		err = a.AnalyzeFile(ctx, &wg, limit, got, "", filePath, info,
+			func() (xio.ReadSeekCloserAt, error) {
+				return os.Open(testFilePath)
+			},
+			nil, analyzer.AnalysisOptions{},
+		)
+		require.NoError(t, err)
+	}
+	wg.Wait()
+
+	report := collector.Report()
+	require.Len(t, report.Analyzers, 1)
+	assert.Equal(t, "alpine", report.Analyzers[0].Name)
+	assert.Equal(t, 1, report.Analyzers[0].Files)
+	assert.Equal(t, int64(7), report.Analyzers[0].Bytes)
+	assert.Equal(t, []debugreport.SkippedFile{
+		{
+			Path:   "/etc/hostname",
+			Reason: debugreport.SkipReasonUnsupported,
+		},
+	}, report.SkippedFiles)
+}
+
 func TestAnalyzerGroup_PostAnalyze(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -573,6 +639,11 @@ func TestAnalyzerGroup_PostAnalyze(t *testing.T) {
 								Name:     "com.fasterxml.jackson.core:jackson-annotations",
 								Version:  "2.15.0-rc2",
 								FilePath: "testdata/post-apps/jar/jackson-annotations-2.15.0-rc2.jar",
+								Provenance: &types.Provenance{
+									Analyzer: "jar",
+									Version:  1,
+									FilePath: "testdata/post-apps/jar/jackson-annotations-2.15.0-rc2.jar",
+								},
 							},
 						},
 					},
@@ -593,6 +664,11 @@ func TestAnalyzerGroup_PostAnalyze(t *testing.T) {
 								ID:      "certifi@2022.12.7",
 								Name:    "certifi",
 								Version: "2022.12.7",
+								Provenance: &types.Provenance{
+									Analyzer: "poetry",
+									Version:  1,
+									FilePath: "testdata/post-apps/poetry/happy/poetry.lock",
+								},
 							},
 						},
 					},
@@ -615,7 +691,7 @@ func TestAnalyzerGroup_PostAnalyze(t *testing.T) {
 
 			if tt.analyzerType == analyzer.TypeJar {
 				// init java-trivy-db with skip update
-				javadb.Init("./language/java/jar/testdata", "ghcr.io/aquasecurity/trivy-java-db", true, false, types.RegistryOptions{Insecure: false})
+				javadb.Init("./language/java/jar/testdata", "ghcr.io/aquasecurity/trivy-java-db", true, false, false, false, types.RegistryOptions{Insecure: false})
 			}
 
 			ctx := context.Background()
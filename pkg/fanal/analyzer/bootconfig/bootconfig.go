@@ -0,0 +1,205 @@
+package bootconfig
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func init() {
+	analyzer.RegisterAnalyzer(&bootConfigAnalyzer{})
+}
+
+const (
+	version = 1
+
+	// Generated grub.cfg files with many menu entries are still much smaller
+	maxFileSize = 1 << 20 // 1MB
+)
+
+type fileType int
+
+const (
+	unknownFile fileType = iota
+	// grubConfigFile is the configuration read by GRUB, i.e. grub.cfg or menu.lst of GRUB Legacy
+	grubConfigFile
+	// grubDefaultsFile is the shell script used to generate grub.cfg, i.e. /etc/default/grub
+	grubDefaultsFile
+	// grubEnvFile is the environment block of GRUB, which holds "kernelopts" on RHEL 8
+	grubEnvFile
+	// loaderEntryFile is a Boot Loader Specification entry
+	loaderEntryFile
+	// kernelCmdlineFile is the command line for unified kernel images and kernel-install
+	kernelCmdlineFile
+)
+
+// grubKernelCommands load the kernel in grub.cfg and menu.lst, followed by the kernel image and the parameters
+var grubKernelCommands = []string{"linux", "linux16", "linuxefi", "kernel"}
+
+// bootConfigAnalyzer checks the bootloader configuration and the kernel command line, mainly in VM images
+type bootConfigAnalyzer struct{}
+
+func (a bootConfigAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	content, err := io.ReadAll(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("read error %s: %w", input.FilePath, err)
+	}
+
+	typ := classify(input.FilePath)
+	var findings []finding
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		if cmdline, ok := kernelCmdline(typ, scanner.Text()); ok {
+			findings = append(findings, evaluateCmdline(cmdline, lineNum)...)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("scan error %s: %w", input.FilePath, err)
+	}
+
+	checks := kernelParamChecks
+	if typ == grubConfigFile {
+		checks = append(slices.Clone(checks), permissionCheck)
+		if input.Info != nil && input.Info.Mode().Perm()&0o077 != 0 {
+			findings = append(findings, finding{
+				check:   permissionCheck,
+				message: fmt.Sprintf("Bootloader configuration is accessible by group and other users (%04o)", input.Info.Mode().Perm()),
+			})
+		}
+	}
+
+	misconf := types.Misconfiguration{
+		FileType: types.BootConfig,
+		FilePath: input.FilePath,
+	}
+	for _, check := range checks {
+		var failures types.MisconfResults
+		for _, f := range findings {
+			if f.check.ID != check.ID {
+				continue
+			}
+			failures = append(failures, types.MisconfResult{
+				Namespace:      namespace,
+				Message:        f.message,
+				PolicyMetadata: check,
+				CauseMetadata: types.CauseMetadata{
+					StartLine: f.startLine,
+					EndLine:   f.endLine,
+				},
+			})
+		}
+		if len(failures) > 0 {
+			misconf.Failures = append(misconf.Failures, failures...)
+			continue
+		}
+		misconf.Successes = append(misconf.Successes, types.MisconfResult{
+			Namespace:      namespace,
+			PolicyMetadata: check,
+		})
+	}
+
+	return &analyzer.AnalysisResult{
+		Misconfigurations: []types.Misconfiguration{misconf},
+	}, nil
+}
+
+func (a bootConfigAnalyzer) Required(filePath string, info os.FileInfo) bool {
+	if info != nil && info.Size() > maxFileSize {
+		return false
+	}
+	return classify(filePath) != unknownFile
+}
+
+func (a bootConfigAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeBootConfig
+}
+
+func (a bootConfigAnalyzer) Version() int {
+	return version
+}
+
+// kernelCmdline returns the kernel parameters on a line of the given file type
+func kernelCmdline(typ fileType, line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", false
+	}
+
+	switch typ {
+	case grubConfigFile:
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !slices.Contains(grubKernelCommands, fields[0]) {
+			return "", false
+		}
+		// Skip the command and the kernel image
+		return strings.Join(fields[2:], " "), true
+	case grubDefaultsFile:
+		line = strings.TrimPrefix(line, "export ")
+		name, value, ok := strings.Cut(line, "=")
+		if !ok || (name != "GRUB_CMDLINE_LINUX" && name != "GRUB_CMDLINE_LINUX_DEFAULT") {
+			return "", false
+		}
+		return unquote(value), true
+	case grubEnvFile:
+		return strings.CutPrefix(line, "kernelopts=")
+	case loaderEntryFile:
+		key, value, ok := strings.Cut(line, " ")
+		if !ok || key != "options" {
+			return "", false
+		}
+		return value, true
+	case kernelCmdlineFile:
+		return line, true
+	}
+	return "", false
+}
+
+// unquote removes the shell quotes around a value, e.g. "quiet splash" or 'quiet splash'
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// classify returns the type of the file from its path
+func classify(filePath string) fileType {
+	filePath = filepath.ToSlash(filePath)
+	dir, name := path.Split(filePath)
+	dir = strings.TrimSuffix(dir, "/")
+
+	switch {
+	case (dir == "boot/grub" || dir == "boot/grub2") && name == "grub.cfg":
+		return grubConfigFile
+	case dir == "boot/grub" && name == "menu.lst":
+		return grubConfigFile
+	// e.g. boot/efi/EFI/redhat/grub.cfg
+	case path.Dir(dir) == "boot/efi/EFI" && name == "grub.cfg":
+		return grubConfigFile
+	case filePath == "etc/default/grub":
+		return grubDefaultsFile
+	case dir == "etc/default/grub.d" && path.Ext(name) == ".cfg":
+		return grubDefaultsFile
+	case (dir == "boot/grub" || dir == "boot/grub2") && name == "grubenv":
+		return grubEnvFile
+	// e.g. boot/loader/entries/6b7d1a9e-5.14.0-362.el9.x86_64.conf
+	case dir == "boot/loader/entries" && path.Ext(name) == ".conf":
+		return loaderEntryFile
+	case filePath == "etc/kernel/cmdline":
+		return kernelCmdlineFile
+	}
+	return unknownFile
+}
@@ -0,0 +1,188 @@
+package bootconfig
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func Test_bootConfigAnalyzer_Analyze(t *testing.T) {
+	type failure struct {
+		ID        string
+		Message   string
+		StartLine int
+		EndLine   int
+	}
+
+	tests := []struct {
+		name      string
+		filePath  string
+		content   string
+		inputFile string
+		mode      fs.FileMode
+		want      []failure
+		wantPass  []string
+	}{
+		{
+			name:      "grub.cfg",
+			filePath:  "boot/grub/grub.cfg",
+			inputFile: "testdata/grub.cfg",
+			mode:      0o444,
+			want: []failure{
+				{"BOOT002", "AppArmor is disabled by the kernel parameter 'apparmor=0'", 13, 13},
+				{"BOOT004", "CPU vulnerability mitigations are disabled by the kernel parameter 'mitigations=off'", 13, 13},
+				{"BOOT005", "Address space layout randomization is disabled by the kernel parameter 'nokaslr'", 17, 17},
+				{"BOOT006", "Bootloader configuration is accessible by group and other users (0444)", 0, 0},
+			},
+			wantPass: []string{"BOOT001", "BOOT003"},
+		},
+		{
+			name:     "restricted grub.cfg",
+			filePath: "boot/grub2/grub.cfg",
+			content:  "set default=0\n",
+			mode:     0o600,
+			wantPass: []string{"BOOT001", "BOOT002", "BOOT003", "BOOT004", "BOOT005", "BOOT006"},
+		},
+		{
+			name:      "GRUB defaults",
+			filePath:  "etc/default/grub",
+			inputFile: "testdata/grub",
+			want: []failure{
+				{"BOOT001", "Auditing is disabled by the kernel parameter 'audit=0'", 8, 8},
+				{"BOOT002", "SELinux is disabled by the kernel parameter 'selinux=0'", 8, 8},
+				{"BOOT003", "Kernel module signature enforcement is disabled by the kernel parameter 'module.sig_enforce=0'", 8, 8},
+			},
+			wantPass: []string{"BOOT004", "BOOT005"},
+		},
+		{
+			name:     "GRUB drop-in",
+			filePath: "etc/default/grub.d/50-cloudimg-settings.cfg",
+			content:  "GRUB_CMDLINE_LINUX_DEFAULT=\"$GRUB_CMDLINE_LINUX_DEFAULT enforcing=0\"\n",
+			want: []failure{
+				{"BOOT002", "SELinux is in permissive mode by the kernel parameter 'enforcing=0'", 1, 1},
+			},
+			wantPass: []string{"BOOT001", "BOOT003", "BOOT004", "BOOT005"},
+		},
+		{
+			name:     "grubenv",
+			filePath: "boot/grub2/grubenv",
+			content:  "# GRUB Environment Block\nsaved_entry=6b7d1a9e-5.14.0-362.el9.x86_64\nkernelopts=root=/dev/mapper/rhel-root ro nopti spectre_v2=off\n",
+			want: []failure{
+				{"BOOT004", "CPU vulnerability mitigations are disabled by the kernel parameter 'nopti'", 3, 3},
+				{"BOOT004", "CPU vulnerability mitigations are disabled by the kernel parameter 'spectre_v2=off'", 3, 3},
+			},
+			wantPass: []string{"BOOT001", "BOOT002", "BOOT003", "BOOT005"},
+		},
+		{
+			name:      "boot loader entry",
+			filePath:  "boot/loader/entries/6b7d1a9e-5.14.0-362.el9.x86_64.conf",
+			inputFile: "testdata/loader-entry.conf",
+			wantPass:  []string{"BOOT001", "BOOT002", "BOOT003", "BOOT004", "BOOT005"},
+		},
+		{
+			name:     "kernel cmdline",
+			filePath: "etc/kernel/cmdline",
+			content:  "root=/dev/sda1 module.sig-enforce=0 -- audit=0\n",
+			want: []failure{
+				{"BOOT003", "Kernel module signature enforcement is disabled by the kernel parameter 'module.sig-enforce=0'", 1, 1},
+			},
+			wantPass: []string{"BOOT001", "BOOT002", "BOOT004", "BOOT005"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := []byte(tt.content)
+			if tt.inputFile != "" {
+				var err error
+				content, err = os.ReadFile(tt.inputFile)
+				require.NoError(t, err)
+			}
+
+			var info fs.FileInfo
+			if tt.mode != 0 {
+				var err error
+				info, err = fs.Stat(fstest.MapFS{"file": {Data: content, Mode: tt.mode}}, "file")
+				require.NoError(t, err)
+			}
+
+			a := bootConfigAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  bytes.NewReader(content),
+				Info:     info,
+			})
+			require.NoError(t, err)
+			require.Len(t, got.Misconfigurations, 1)
+
+			misconf := got.Misconfigurations[0]
+			assert.Equal(t, types.BootConfig, misconf.FileType)
+			assert.Equal(t, tt.filePath, misconf.FilePath)
+
+			var failures []failure
+			for _, f := range misconf.Failures {
+				failures = append(failures, failure{f.ID, f.Message, f.StartLine, f.EndLine})
+			}
+			assert.Equal(t, tt.want, failures)
+
+			var passed []string
+			for _, s := range misconf.Successes {
+				passed = append(passed, s.ID)
+			}
+			assert.Equal(t, tt.wantPass, passed)
+		})
+	}
+}
+
+func Test_bootConfigAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "grub.cfg",
+			filePath: "boot/grub2/grub.cfg",
+			want:     true,
+		},
+		{
+			name:     "EFI grub.cfg",
+			filePath: "boot/efi/EFI/redhat/grub.cfg",
+			want:     true,
+		},
+		{
+			name:     "GRUB defaults",
+			filePath: "etc/default/grub",
+			want:     true,
+		},
+		{
+			name:     "boot loader entry",
+			filePath: "boot/loader/entries/6b7d1a9e-5.14.0-362.el9.x86_64.conf",
+			want:     true,
+		},
+		{
+			name:     "GRUB scripts",
+			filePath: "etc/grub.d/10_linux",
+			want:     false,
+		},
+		{
+			name:     "grub.cfg in another directory",
+			filePath: "usr/share/grub/grub.cfg",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := bootConfigAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package bootconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+const (
+	namespace = "bootconfig"
+	checkType = "Boot Configuration Security Check"
+)
+
+var (
+	auditCheck = types.PolicyMetadata{
+		ID:                 "BOOT001",
+		Type:               checkType,
+		Title:              "Auditing disabled at boot",
+		Description:        "With 'audit=0', the kernel doesn't audit any event, including the ones of processes started before auditd.",
+		Severity:           "MEDIUM",
+		RecommendedActions: "Remove 'audit=0' from the kernel command line, or set 'audit=1'.",
+	}
+	macCheck = types.PolicyMetadata{
+		ID:                 "BOOT002",
+		Type:               checkType,
+		Title:              "Mandatory access control disabled",
+		Description:        "Disabling SELinux or AppArmor on the kernel command line removes the confinement of every process on the machine.",
+		Severity:           "HIGH",
+		RecommendedActions: "Remove 'selinux=0', 'enforcing=0' and 'apparmor=0' from the kernel command line.",
+	}
+	moduleSignatureCheck = types.PolicyMetadata{
+		ID:                 "BOOT003",
+		Type:               checkType,
+		Title:              "Kernel module signature enforcement disabled",
+		Description:        "Without signature enforcement, unsigned and possibly malicious kernel modules can be loaded.",
+		Severity:           "MEDIUM",
+		RecommendedActions: "Set 'module.sig_enforce=1' on the kernel command line.",
+	}
+	mitigationsCheck = types.PolicyMetadata{
+		ID:                 "BOOT004",
+		Type:               checkType,
+		Title:              "CPU vulnerability mitigations disabled",
+		Description:        "Disabling the mitigations for CPU vulnerabilities such as Spectre and Meltdown allows processes to read the memory of other processes and of the kernel.",
+		Severity:           "MEDIUM",
+		RecommendedActions: "Remove 'mitigations=off' and the parameters disabling individual mitigations from the kernel command line.",
+	}
+	kaslrCheck = types.PolicyMetadata{
+		ID:                 "BOOT005",
+		Type:               checkType,
+		Title:              "Address space layout randomization disabled",
+		Description:        "Without address space layout randomization, the addresses of the kernel and of user processes are predictable, which eases exploitation of memory corruption bugs.",
+		Severity:           "MEDIUM",
+		RecommendedActions: "Remove 'nokaslr' and 'norandmaps' from the kernel command line.",
+	}
+	permissionCheck = types.PolicyMetadata{
+		ID:                 "BOOT006",
+		Type:               checkType,
+		Title:              "Bootloader configuration accessible by other users",
+		Description:        "The bootloader configuration may contain password hashes and shows the boot parameters, so it should be readable by root only.",
+		Severity:           "LOW",
+		RecommendedActions: "Set the permissions of the bootloader configuration to 0600 or stricter.",
+	}
+
+	// kernelParamChecks apply to any file containing kernel parameters
+	kernelParamChecks = []types.PolicyMetadata{
+		auditCheck,
+		macCheck,
+		moduleSignatureCheck,
+		mitigationsCheck,
+		kaslrCheck,
+	}
+)
+
+// paramRule fails a check when a kernel parameter matches
+type paramRule struct {
+	check   types.PolicyMetadata
+	message string
+	match   func(p param) bool
+}
+
+var paramRules = []paramRule{
+	{
+		check:   auditCheck,
+		message: "Auditing is disabled",
+		match: func(p param) bool {
+			return p.name == "audit" && isOff(p.value)
+		},
+	},
+	{
+		check:   macCheck,
+		message: "SELinux is disabled",
+		match: func(p param) bool {
+			return p.name == "selinux" && isOff(p.value)
+		},
+	},
+	{
+		check:   macCheck,
+		message: "SELinux is in permissive mode",
+		match: func(p param) bool {
+			return p.name == "enforcing" && isOff(p.value)
+		},
+	},
+	{
+		check:   macCheck,
+		message: "AppArmor is disabled",
+		match: func(p param) bool {
+			return p.name == "apparmor" && isOff(p.value)
+		},
+	},
+	{
+		check:   moduleSignatureCheck,
+		message: "Kernel module signature enforcement is disabled",
+		match: func(p param) bool {
+			return p.name == "module.sig_enforce" && isOff(p.value)
+		},
+	},
+	{
+		check:   mitigationsCheck,
+		message: "CPU vulnerability mitigations are disabled",
+		match: func(p param) bool {
+			switch p.name {
+			case "nopti", "nospectre_v1", "nospectre_v2", "nospec_store_bypass_disable", "nosmap", "nosmep":
+				return true
+			case "mitigations", "pti", "spectre_v2", "spec_store_bypass_disable", "l1tf", "mds", "tsx_async_abort",
+				"mmio_stale_data", "retbleed", "srbds", "gather_data_sampling":
+				return p.value == "off"
+			}
+			return false
+		},
+	},
+	{
+		check:   kaslrCheck,
+		message: "Address space layout randomization is disabled",
+		match: func(p param) bool {
+			return p.name == "nokaslr" || p.name == "norandmaps"
+		},
+	},
+}
+
+// finding is a failed check with the location of the cause
+type finding struct {
+	check     types.PolicyMetadata
+	message   string
+	startLine int
+	endLine   int
+}
+
+// param is a kernel parameter, e.g. "selinux=0"
+type param struct {
+	raw string
+	// name is normalized, as the kernel treats dashes and underscores in parameter names equally
+	name  string
+	value string
+}
+
+// evaluateCmdline checks the kernel parameters on a line
+func evaluateCmdline(cmdline string, line int) []finding {
+	var findings []finding
+	for _, p := range parseParams(cmdline) {
+		for _, rule := range paramRules {
+			if !rule.match(p) {
+				continue
+			}
+			findings = append(findings, finding{
+				check:     rule.check,
+				message:   fmt.Sprintf("%s by the kernel parameter '%s'", rule.message, p.raw),
+				startLine: line,
+				endLine:   line,
+			})
+		}
+	}
+	return findings
+}
+
+// parseParams splits a kernel command line into parameters.
+// Only the last occurrence of a parameter is returned, as it takes precedence over the previous ones.
+func parseParams(cmdline string) []param {
+	var params []param
+	for _, field := range splitFields(cmdline) {
+		// The arguments after "--" are passed to init
+		if field == "--" {
+			break
+		}
+		// Variables are expanded by the bootloader, e.g. "$kernelopts" or "${extra_cmdline}"
+		if field == "" || strings.HasPrefix(field, "$") {
+			continue
+		}
+		name, value, _ := strings.Cut(field, "=")
+		p := param{
+			raw:   field,
+			name:  strings.ReplaceAll(name, "-", "_"),
+			value: strings.ToLower(strings.Trim(value, `"`)),
+		}
+		params = removeParam(params, p.name)
+		params = append(params, p)
+	}
+	return params
+}
+
+func removeParam(params []param, name string) []param {
+	for i, p := range params {
+		if p.name == name {
+			return append(params[:i], params[i+1:]...)
+		}
+	}
+	return params
+}
+
+// splitFields splits on spaces outside double quotes, e.g. `dyndbg="file foo.c +p"`
+func splitFields(s string) []string {
+	var fields []string
+	var sb strings.Builder
+	quoted := false
+	for _, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+			sb.WriteRune(c)
+		case !quoted && (c == ' ' || c == '\t'):
+			if sb.Len() > 0 {
+				fields = append(fields, sb.String())
+				sb.Reset()
+			}
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	if sb.Len() > 0 {
+		fields = append(fields, sb.String())
+	}
+	return fields
+}
+
+func isOff(v string) bool {
+	switch v {
+	case "0", "off", "n", "no":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package certificate
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+func init() {
+	analyzer.RegisterAnalyzer(&certificateAnalyzer{})
+}
+
+const (
+	certificateVersion   = 2
+	certificateNamespace = "certificate"
+
+	// Certificates and keys are small, so bigger files are skipped
+	maxCertificateFileSize = 1 << 20 // 1MB
+)
+
+var (
+	certificateExtensions = []string{".pem", ".crt", ".cer", ".cert", ".der", ".key"}
+
+	// Directories of the CA certificates managed by the OS, which are checked as trust stores
+	caDirs = []string{
+		"etc/ssl/certs/",
+		"etc/pki/ca-trust/",
+		"etc/pki/tls/certs/",
+		"etc/ca-certificates/",
+		"usr/share/ca-certificates/",
+		"usr/share/pki/",
+	}
+)
+
+// certificateAnalyzer inventories the certificates and private keys, and flags the weak ones.
+// The expiry is checked at scan time by CheckExpiry.
+type certificateAnalyzer struct{}
+
+func (a certificateAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	content, err := io.ReadAll(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("read error %s: %w", input.FilePath, err)
+	}
+
+	certs, keys := parseCertificatesAndKeys(content)
+	if len(certs) == 0 && len(keys) == 0 {
+		return nil, nil
+	}
+
+	inventory := types.CertificateInventory{
+		FilePath: input.FilePath,
+		FileType: types.CertificateFile,
+	}
+	for _, cert := range certs {
+		inventory.Certificates = append(inventory.Certificates, toCertificate(cert))
+	}
+	for _, key := range keys {
+		inventory.PrivateKeys = append(inventory.PrivateKeys, key.PrivateKey)
+	}
+
+	misconf := types.Misconfiguration{}
+	if len(certs) > 0 {
+		misconf = evaluate(certificateChecks, certificateNamespace, certs)
+	}
+	if len(keys) > 0 {
+		successes, failures := evaluateKeys(keys)
+		misconf.Successes = append(misconf.Successes, successes...)
+		misconf.Failures = append(misconf.Failures, failures...)
+	}
+	misconf.FileType = types.CertificateFile
+	misconf.FilePath = input.FilePath
+
+	return &analyzer.AnalysisResult{
+		Misconfigurations: []types.Misconfiguration{misconf},
+		Certificates:      []types.CertificateInventory{inventory},
+	}, nil
+}
+
+func (a certificateAnalyzer) Required(filePath string, info os.FileInfo) bool {
+	if info != nil && info.Size() > maxCertificateFileSize {
+		return false
+	}
+	filePath = filepath.ToSlash(filePath)
+	if (trustStoreAnalyzer{}).Required(filePath, info) {
+		return false
+	}
+	if slices.ContainsFunc(caDirs, func(dir string) bool {
+		return strings.HasPrefix(filePath, dir)
+	}) {
+		return false
+	}
+	return slices.Contains(certificateExtensions, strings.ToLower(filepath.Ext(filePath)))
+}
+
+func (a certificateAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeCertificate
+}
+
+func (a certificateAnalyzer) Version() int {
+	return certificateVersion
+}
+
+type privateKey struct {
+	types.PrivateKey
+	weak bool
+}
+
+// parseCertificatesAndKeys parses PEM files, or a single certificate in DER
+func parseCertificatesAndKeys(content []byte) ([]certificate, []privateKey) {
+	blocks := parsePEM(content)
+	if len(blocks) == 0 {
+		if cert, err := x509.ParseCertificate(content); err == nil {
+			return []certificate{{Certificate: cert}}, nil
+		}
+		return nil, nil
+	}
+
+	var certs []certificate
+	var keys []privateKey
+	for _, block := range blocks {
+		if block.Type == "CERTIFICATE" {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				log.Logger.Debugf("Unable to parse the certificate: %s", err)
+				continue
+			}
+			certs = append(certs, certificate{
+				Certificate: cert,
+				startLine:   block.startLine,
+				endLine:     block.endLine,
+			})
+			continue
+		}
+
+		if !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			continue
+		}
+		key, err := parsePrivateKey(block.Block)
+		if err != nil {
+			log.Logger.Debugf("Unable to parse the private key: %s", err)
+			continue
+		}
+		key.StartLine = block.startLine
+		key.EndLine = block.endLine
+		keys = append(keys, key)
+	}
+	return certs, keys
+}
+
+func parsePrivateKey(block *pem.Block) (privateKey, error) {
+	// Legacy PEM encryption, e.g. "Proc-Type: 4,ENCRYPTED"
+	if block.Type == "ENCRYPTED PRIVATE KEY" || strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED") {
+		return privateKey{PrivateKey: types.PrivateKey{Algorithm: "unknown", Encrypted: true}}, nil
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "DSA PRIVATE KEY":
+		// DSA keys are weak regardless of their size
+		return privateKey{PrivateKey: types.PrivateKey{Algorithm: "DSA"}, weak: true}, nil
+	case "OPENSSH PRIVATE KEY":
+		key, err = ssh.ParseRawPrivateKey(pem.EncodeToMemory(block))
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return privateKey{PrivateKey: types.PrivateKey{Algorithm: "unknown", Encrypted: true}}, nil
+		}
+	default:
+		return privateKey{}, fmt.Errorf("unsupported private key type: %s", block.Type)
+	}
+	if err != nil {
+		return privateKey{}, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return privateKey{}, fmt.Errorf("unsupported private key: %T", key)
+	}
+	algorithm, size := keyInfo(signer.Public())
+	return privateKey{
+		PrivateKey: types.PrivateKey{
+			Algorithm: algorithm,
+			KeySize:   size,
+		},
+		weak: isWeakKey(algorithm, size),
+	}, nil
+}
+
+func evaluateKeys(keys []privateKey) (types.MisconfResults, types.MisconfResults) {
+	var failures types.MisconfResults
+	for _, key := range keys {
+		if !key.weak {
+			continue
+		}
+		failures = append(failures, types.MisconfResult{
+			Namespace:      certificateNamespace,
+			Message:        fmt.Sprintf("Private key uses %s", keyDescription(key.Algorithm, key.KeySize)),
+			PolicyMetadata: weakPrivateKeyCheck,
+			CauseMetadata: types.CauseMetadata{
+				StartLine: key.StartLine,
+				EndLine:   key.EndLine,
+			},
+		})
+	}
+	if len(failures) > 0 {
+		return nil, failures
+	}
+	return types.MisconfResults{
+		{
+			Namespace:      certificateNamespace,
+			PolicyMetadata: weakPrivateKeyCheck,
+		},
+	}, nil
+}
+
+func toCertificate(cert certificate) types.Certificate {
+	algorithm, size := keyInfo(cert.PublicKey)
+	return types.Certificate{
+		Subject:            cert.Subject.String(),
+		CommonName:         cert.Subject.CommonName,
+		Issuer:             cert.Issuer.String(),
+		SerialNumber:       cert.SerialNumber.String(),
+		NotBefore:          cert.NotBefore.UTC(),
+		NotAfter:           cert.NotAfter.UTC(),
+		KeyAlgorithm:       algorithm,
+		KeySize:            size,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		SelfSigned:         isSelfSigned(cert.Certificate),
+		IsCA:               cert.IsCA,
+		StartLine:          cert.startLine,
+		EndLine:            cert.endLine,
+	}
+}
@@ -0,0 +1,199 @@
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func Test_certificateAnalyzer_Analyze(t *testing.T) {
+	valid := newCertificate(t, "example.com", "Example", now.AddDate(1, 0, 0), 0)
+	expired := newCertificate(t, "expired.example.com", "Example", now.AddDate(0, -1, 0), 2048)
+	expiring := newCertificate(t, "expiring.example.com", "Example", now.AddDate(0, 0, 10), 0)
+
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+
+	var bundle bytes.Buffer
+	for _, der := range [][]byte{valid, expired, expiring} {
+		require.NoError(t, pem.Encode(&bundle, &pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+
+	tests := []struct {
+		name          string
+		filePath      string
+		content       []byte
+		wantIDs       []string
+		wantPass      int
+		wantCerts     []string
+		wantKeys      []types.PrivateKey
+		wantNoResults bool
+	}{
+		{
+			name:      "PEM certificates",
+			filePath:  "etc/nginx/certs/chain.pem",
+			content:   bundle.Bytes(),
+			wantPass:  2,
+			wantCerts: []string{"example.com", "expired.example.com", "expiring.example.com"},
+		},
+		{
+			name:      "DER certificate",
+			filePath:  "app/server.der",
+			content:   valid,
+			wantPass:  2,
+			wantCerts: []string{"example.com"},
+		},
+		{
+			name:     "weak RSA private key",
+			filePath: "app/tls.key",
+			content: pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(weakKey),
+			}),
+			wantIDs: []string{"CERT009"},
+			wantKeys: []types.PrivateKey{
+				{
+					Algorithm: "RSA",
+					KeySize:   1024,
+					StartLine: 1,
+					EndLine:   bytes.Count(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(weakKey)}), []byte("\n")),
+				},
+			},
+		},
+		{
+			name:     "PKCS#8 private key",
+			filePath: "app/tls.key",
+			content:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDER}),
+			wantPass: 1,
+			wantKeys: []types.PrivateKey{
+				{
+					Algorithm: "ECDSA",
+					KeySize:   256,
+					StartLine: 1,
+					EndLine:   bytes.Count(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDER}), []byte("\n")),
+				},
+			},
+		},
+		{
+			name:     "encrypted private key",
+			filePath: "app/tls.key",
+			content: pem.EncodeToMemory(&pem.Block{
+				Type:    "RSA PRIVATE KEY",
+				Headers: map[string]string{"Proc-Type": "4,ENCRYPTED"},
+				Bytes:   []byte("encrypted"),
+			}),
+			wantPass: 1,
+			wantKeys: []types.PrivateKey{
+				{
+					Algorithm: "unknown",
+					Encrypted: true,
+					StartLine: 1,
+					EndLine:   5,
+				},
+			},
+		},
+		{
+			name:          "not a certificate",
+			filePath:      "app/README.pem",
+			content:       []byte("not a certificate"),
+			wantNoResults: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := certificateAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  bytes.NewReader(tt.content),
+			})
+			require.NoError(t, err)
+			if tt.wantNoResults {
+				assert.Nil(t, got)
+				return
+			}
+			require.Len(t, got.Misconfigurations, 1)
+			require.Len(t, got.Certificates, 1)
+
+			misconf := got.Misconfigurations[0]
+			assert.Equal(t, types.CertificateFile, misconf.FileType)
+			assert.Equal(t, tt.filePath, misconf.FilePath)
+			assert.Len(t, misconf.Successes, tt.wantPass)
+
+			var ids []string
+			for _, failure := range misconf.Failures {
+				ids = append(ids, failure.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+
+			inventory := got.Certificates[0]
+			assert.Equal(t, types.CertificateFile, inventory.FileType)
+			assert.Equal(t, tt.filePath, inventory.FilePath)
+			var subjects []string
+			for _, cert := range inventory.Certificates {
+				subjects = append(subjects, cert.Subject)
+			}
+			var wantSubjects []string
+			for _, cn := range tt.wantCerts {
+				wantSubjects = append(wantSubjects, "CN="+cn+",O=Example")
+			}
+			assert.Equal(t, wantSubjects, subjects)
+			assert.Equal(t, tt.wantKeys, inventory.PrivateKeys)
+		})
+	}
+}
+
+func Test_certificateAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "certificate",
+			filePath: "etc/nginx/certs/server.crt",
+			want:     true,
+		},
+		{
+			name:     "private key",
+			filePath: "app/tls.key",
+			want:     true,
+		},
+		{
+			name:     "OS CA certificate",
+			filePath: "etc/ssl/certs/ISRG_Root_X1.pem",
+			want:     false,
+		},
+		{
+			name:     "trust store",
+			filePath: "usr/local/lib/python3.11/site-packages/certifi/cacert.pem",
+			want:     false,
+		},
+		{
+			name:     "other file",
+			filePath: "app/main.go",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := certificateAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
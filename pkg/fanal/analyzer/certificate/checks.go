@@ -0,0 +1,226 @@
+package certificate
+
+import (
+	"bytes"
+	"crypto/dsa" //nolint:staticcheck // DSA keys are still found in old trust stores
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+const (
+	trustStoreCheckType  = "Trust Store Security Check"
+	certificateCheckType = "Certificate Security Check"
+
+	minRSAKeySize   = 2048
+	minECDSAKeySize = 256
+)
+
+// Organizations whose CAs have been distrusted by the major root programs
+var distrustedOrganizations = []string{
+	"diginotar",
+	"wosign",
+	"startcom",
+	"trustcor",
+	"camerfirma",
+	"e-tuğra",
+}
+
+type check struct {
+	types.PolicyMetadata
+	// evaluate returns the reason why the certificate fails the check, or an empty string
+	evaluate func(cert *x509.Certificate) string
+}
+
+var trustStoreChecks = []check{
+	{
+		PolicyMetadata: types.PolicyMetadata{
+			ID:                 "CERT002",
+			Type:               trustStoreCheckType,
+			Title:              "Weak CA key",
+			Description:        "The trust store contains CA certificates with keys too short to be considered secure.",
+			Severity:           "HIGH",
+			RecommendedActions: "Update the CA bundle or remove the CA certificates with weak keys.",
+		},
+		evaluate: func(cert *x509.Certificate) string {
+			if algorithm, size := keyInfo(cert.PublicKey); isWeakKey(algorithm, size) {
+				return fmt.Sprintf("CA certificate '%s' uses %s", name(cert), keyDescription(algorithm, size))
+			}
+			return ""
+		},
+	},
+	{
+		PolicyMetadata: types.PolicyMetadata{
+			ID:                 "CERT003",
+			Type:               trustStoreCheckType,
+			Title:              "Broken CA signature algorithm",
+			Description:        "The trust store contains CA certificates signed with MD2 or MD5, which are broken hash algorithms.",
+			Severity:           "LOW",
+			RecommendedActions: "Update the CA bundle or remove the CA certificates signed with broken algorithms.",
+		},
+		evaluate: func(cert *x509.Certificate) string {
+			switch cert.SignatureAlgorithm {
+			case x509.MD2WithRSA, x509.MD5WithRSA:
+				return fmt.Sprintf("CA certificate '%s' is signed with %s", name(cert), cert.SignatureAlgorithm)
+			}
+			return ""
+		},
+	},
+	{
+		PolicyMetadata: types.PolicyMetadata{
+			ID:                 "CERT004",
+			Type:               trustStoreCheckType,
+			Title:              "Distrusted CA",
+			Description:        "The trust store contains CA certificates of organizations distrusted by the major root programs.",
+			Severity:           "CRITICAL",
+			RecommendedActions: "Update the CA bundle or remove the distrusted CA certificates.",
+		},
+		evaluate: func(cert *x509.Certificate) string {
+			for _, org := range cert.Subject.Organization {
+				org = strings.ToLower(org)
+				if lo.ContainsBy(distrustedOrganizations, func(distrusted string) bool {
+					return strings.Contains(org, distrusted)
+				}) {
+					return fmt.Sprintf("CA certificate '%s' belongs to the distrusted organization '%s'", name(cert), org)
+				}
+			}
+			return ""
+		},
+	},
+}
+
+var certificateChecks = []check{
+	{
+		PolicyMetadata: types.PolicyMetadata{
+			ID:                 "CERT007",
+			Type:               certificateCheckType,
+			Title:              "Weak certificate key",
+			Description:        "The certificate uses a key too short to be considered secure.",
+			Severity:           "HIGH",
+			RecommendedActions: "Issue a new certificate with an RSA key of at least 2048 bits or an ECDSA key of at least 256 bits.",
+		},
+		evaluate: func(cert *x509.Certificate) string {
+			if algorithm, size := keyInfo(cert.PublicKey); isWeakKey(algorithm, size) {
+				return fmt.Sprintf("Certificate '%s' uses %s", name(cert), keyDescription(algorithm, size))
+			}
+			return ""
+		},
+	},
+	{
+		PolicyMetadata: types.PolicyMetadata{
+			ID:                 "CERT008",
+			Type:               certificateCheckType,
+			Title:              "Weak certificate signature algorithm",
+			Description:        "The certificate is signed with MD2, MD5 or SHA-1, which are not collision resistant.",
+			Severity:           "MEDIUM",
+			RecommendedActions: "Issue a new certificate signed with SHA-256 or stronger.",
+		},
+		evaluate: func(cert *x509.Certificate) string {
+			// The signature of self-signed certificates is not verified by clients
+			if isSelfSigned(cert) {
+				return ""
+			}
+			switch cert.SignatureAlgorithm {
+			case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+				return fmt.Sprintf("Certificate '%s' is signed with %s", name(cert), cert.SignatureAlgorithm)
+			}
+			return ""
+		},
+	},
+}
+
+var weakPrivateKeyCheck = types.PolicyMetadata{
+	ID:                 "CERT009",
+	Type:               certificateCheckType,
+	Title:              "Weak private key",
+	Description:        "The private key is too short to be considered secure.",
+	Severity:           "HIGH",
+	RecommendedActions: "Generate a new RSA key of at least 2048 bits or an ECDSA key of at least 256 bits.",
+}
+
+// evaluate runs the checks against the certificates
+func evaluate(checks []check, namespace string, certs []certificate) types.Misconfiguration {
+	var misconf types.Misconfiguration
+	for _, c := range checks {
+		var failures types.MisconfResults
+		for _, cert := range certs {
+			msg := c.evaluate(cert.Certificate)
+			if msg == "" {
+				continue
+			}
+			failures = append(failures, types.MisconfResult{
+				Namespace:      namespace,
+				Message:        msg,
+				PolicyMetadata: c.PolicyMetadata,
+				CauseMetadata: types.CauseMetadata{
+					Resource:  name(cert.Certificate),
+					StartLine: cert.startLine,
+					EndLine:   cert.endLine,
+				},
+			})
+		}
+
+		if len(failures) == 0 {
+			misconf.Successes = append(misconf.Successes, types.MisconfResult{
+				Namespace:      namespace,
+				PolicyMetadata: c.PolicyMetadata,
+			})
+			continue
+		}
+		misconf.Failures = append(misconf.Failures, failures...)
+	}
+	return misconf
+}
+
+// keyInfo returns the algorithm and the size in bits of the public key
+func keyInfo(pub any) (string, int) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case *dsa.PublicKey:
+		return "DSA", key.P.BitLen()
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	}
+	return "unknown", 0
+}
+
+func isWeakKey(algorithm string, size int) bool {
+	switch algorithm {
+	case "RSA":
+		return size < minRSAKeySize
+	case "ECDSA":
+		return size < minECDSAKeySize
+	case "DSA":
+		return true
+	}
+	return false
+}
+
+func keyDescription(algorithm string, size int) string {
+	return fmt.Sprintf("a %d-bit %s key", size, algorithm)
+}
+
+// isSelfSigned doesn't verify the signature, as Go refuses to verify SHA-1 signatures
+func isSelfSigned(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		return false
+	}
+	return len(cert.AuthorityKeyId) == 0 || bytes.Equal(cert.AuthorityKeyId, cert.SubjectKeyId)
+}
+
+func name(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	return cert.Subject.String()
+}
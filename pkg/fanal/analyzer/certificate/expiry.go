@@ -0,0 +1,140 @@
+package certificate
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// Certificates expiring within this period are reported
+const expiryWarningPeriod = 30 * 24 * time.Hour
+
+type expiryCheck struct {
+	types.PolicyMetadata
+	// evaluate returns the reason why the certificate fails the check, or an empty string
+	evaluate func(cert types.Certificate, now time.Time) string
+}
+
+var trustStoreExpiryChecks = []expiryCheck{
+	{
+		PolicyMetadata: types.PolicyMetadata{
+			ID:                 "CERT001",
+			Type:               trustStoreCheckType,
+			Title:              "Expired CA certificate",
+			Description:        "The trust store contains expired CA certificates, which usually means that it hasn't been updated for a long time.",
+			Severity:           "MEDIUM",
+			RecommendedActions: "Update the CA bundle, e.g. the 'ca-certificates' package or the Java runtime.",
+		},
+		evaluate: func(cert types.Certificate, now time.Time) string {
+			if now.After(cert.NotAfter) {
+				return fmt.Sprintf("CA certificate '%s' expired on %s", cert.Name(), cert.NotAfter.Format(time.DateOnly))
+			}
+			return ""
+		},
+	},
+}
+
+var certificateExpiryChecks = []expiryCheck{
+	{
+		PolicyMetadata: types.PolicyMetadata{
+			ID:                 "CERT005",
+			Type:               certificateCheckType,
+			Title:              "Expired certificate",
+			Description:        "The certificate has expired, and TLS connections using it will be rejected.",
+			Severity:           "HIGH",
+			RecommendedActions: "Renew the certificate.",
+		},
+		evaluate: func(cert types.Certificate, now time.Time) string {
+			if now.After(cert.NotAfter) {
+				return fmt.Sprintf("Certificate '%s' expired on %s", cert.Name(), cert.NotAfter.Format(time.DateOnly))
+			}
+			return ""
+		},
+	},
+	{
+		PolicyMetadata: types.PolicyMetadata{
+			ID:                 "CERT006",
+			Type:               certificateCheckType,
+			Title:              "Certificate expiring soon",
+			Description:        "The certificate expires within 30 days.",
+			Severity:           "MEDIUM",
+			RecommendedActions: "Renew the certificate before it expires.",
+		},
+		evaluate: func(cert types.Certificate, now time.Time) string {
+			if !now.After(cert.NotAfter) && now.Add(expiryWarningPeriod).After(cert.NotAfter) {
+				return fmt.Sprintf("Certificate '%s' expires on %s", cert.Name(), cert.NotAfter.Format(time.DateOnly))
+			}
+			return ""
+		},
+	},
+}
+
+// CheckExpiry checks the expiry of the inventoried certificates and adds the results to the misconfigurations of their files.
+// Unlike the other checks, it is evaluated at scan time rather than analysis time, as the analysis results are cached.
+func CheckExpiry(misconfs []types.Misconfiguration, inventories []types.CertificateInventory, now time.Time) []types.Misconfiguration {
+	for _, inventory := range inventories {
+		var checks []expiryCheck
+		var namespace string
+		switch inventory.FileType {
+		case types.TrustStore:
+			checks, namespace = trustStoreExpiryChecks, trustStoreNamespace
+		case types.CertificateFile:
+			checks, namespace = certificateExpiryChecks, certificateNamespace
+		default:
+			continue
+		}
+		if len(inventory.Certificates) == 0 {
+			continue
+		}
+
+		misconf := evaluateExpiry(checks, namespace, inventory.Certificates, now)
+		i := slices.IndexFunc(misconfs, func(m types.Misconfiguration) bool {
+			return m.FilePath == inventory.FilePath && m.FileType == inventory.FileType
+		})
+		if i == -1 {
+			misconf.FileType = inventory.FileType
+			misconf.FilePath = inventory.FilePath
+			misconfs = append(misconfs, misconf)
+			continue
+		}
+		misconfs[i].Successes = append(misconfs[i].Successes, misconf.Successes...)
+		misconfs[i].Failures = append(misconfs[i].Failures, misconf.Failures...)
+	}
+	return misconfs
+}
+
+func evaluateExpiry(checks []expiryCheck, namespace string, certs []types.Certificate, now time.Time) types.Misconfiguration {
+	var misconf types.Misconfiguration
+	for _, c := range checks {
+		var failures types.MisconfResults
+		for _, cert := range certs {
+			msg := c.evaluate(cert, now)
+			if msg == "" {
+				continue
+			}
+			failures = append(failures, types.MisconfResult{
+				Namespace:      namespace,
+				Message:        msg,
+				PolicyMetadata: c.PolicyMetadata,
+				CauseMetadata: types.CauseMetadata{
+					Resource:  cert.Name(),
+					StartLine: cert.StartLine,
+					EndLine:   cert.EndLine,
+				},
+			})
+		}
+
+		if len(failures) == 0 {
+			misconf.Successes = append(misconf.Successes, types.MisconfResult{
+				Namespace:      namespace,
+				PolicyMetadata: c.PolicyMetadata,
+			})
+			continue
+		}
+		misconf.Failures = append(misconf.Failures, failures...)
+	}
+	return misconf
+}
@@ -0,0 +1,116 @@
+package certificate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func TestCheckExpiry(t *testing.T) {
+	valid := types.Certificate{
+		Subject:    "CN=example.com,O=Example",
+		CommonName: "example.com",
+		NotAfter:   now.AddDate(1, 0, 0),
+	}
+	expired := types.Certificate{
+		Subject:   "O=Expired",
+		NotAfter:  now.AddDate(0, -1, 0),
+		StartLine: 10,
+		EndLine:   20,
+	}
+	expiring := types.Certificate{
+		Subject:    "CN=expiring.example.com,O=Example",
+		CommonName: "expiring.example.com",
+		NotAfter:   now.AddDate(0, 0, 10),
+	}
+	keyCheck := types.MisconfResult{
+		Namespace:      certificateNamespace,
+		PolicyMetadata: weakPrivateKeyCheck,
+	}
+
+	tests := []struct {
+		name        string
+		misconfs    []types.Misconfiguration
+		inventories []types.CertificateInventory
+		want        []types.Misconfiguration
+	}{
+		{
+			name: "certificate file",
+			misconfs: []types.Misconfiguration{
+				{
+					FileType:  types.CertificateFile,
+					FilePath:  "app/tls.pem",
+					Successes: types.MisconfResults{keyCheck},
+				},
+			},
+			inventories: []types.CertificateInventory{
+				{
+					FilePath:     "app/tls.pem",
+					FileType:     types.CertificateFile,
+					Certificates: []types.Certificate{valid, expired, expiring},
+				},
+			},
+			want: []types.Misconfiguration{
+				{
+					FileType:  types.CertificateFile,
+					FilePath:  "app/tls.pem",
+					Successes: types.MisconfResults{keyCheck},
+					Failures: types.MisconfResults{
+						{
+							Namespace:      certificateNamespace,
+							Message:        "Certificate 'O=Expired' expired on 2024-02-01",
+							PolicyMetadata: certificateExpiryChecks[0].PolicyMetadata,
+							CauseMetadata: types.CauseMetadata{
+								Resource:  "O=Expired",
+								StartLine: 10,
+								EndLine:   20,
+							},
+						},
+						{
+							Namespace:      certificateNamespace,
+							Message:        "Certificate 'expiring.example.com' expires on 2024-03-11",
+							PolicyMetadata: certificateExpiryChecks[1].PolicyMetadata,
+							CauseMetadata: types.CauseMetadata{
+								Resource: "expiring.example.com",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "trust store without misconfigurations",
+			inventories: []types.CertificateInventory{
+				{
+					FilePath:     "etc/ssl/certs/ca-certificates.crt",
+					FileType:     types.TrustStore,
+					Certificates: []types.Certificate{valid, expiring},
+				},
+				{
+					FilePath: "app/tls.key",
+					FileType: types.CertificateFile,
+				},
+			},
+			want: []types.Misconfiguration{
+				{
+					FileType: types.TrustStore,
+					FilePath: "etc/ssl/certs/ca-certificates.crt",
+					Successes: types.MisconfResults{
+						{
+							Namespace:      trustStoreNamespace,
+							PolicyMetadata: trustStoreExpiryChecks[0].PolicyMetadata,
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckExpiry(tt.misconfs, tt.inventories, now)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
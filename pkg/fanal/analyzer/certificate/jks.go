@@ -0,0 +1,119 @@
+package certificate
+
+import (
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	jksMagic = 0xFEEDFEED
+
+	jksPrivateKeyTag  = 1
+	jksTrustedCertTag = 2
+)
+
+// parseJKS extracts the certificates from a Java KeyStore, such as "lib/security/cacerts".
+// The integrity of the keystore is not verified as it requires the store password.
+// cf. https://github.com/openjdk/jdk/blob/master/src/java.base/share/classes/sun/security/provider/JavaKeyStore.java
+func parseJKS(r io.Reader) ([]*x509.Certificate, error) {
+	var header struct {
+		Magic   uint32
+		Version uint32
+		Count   uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, xerrors.Errorf("unable to read the header: %w", err)
+	}
+	if header.Magic != jksMagic {
+		return nil, xerrors.New("not a Java KeyStore")
+	}
+
+	var certs []*x509.Certificate
+	for i := uint32(0); i < header.Count; i++ {
+		var tag uint32
+		if err := binary.Read(r, binary.BigEndian, &tag); err != nil {
+			return nil, xerrors.Errorf("unable to read the entry tag: %w", err)
+		}
+		// Alias
+		if _, err := readUTF(r); err != nil {
+			return nil, xerrors.Errorf("unable to read the alias: %w", err)
+		}
+		// Creation date
+		if _, err := readBytes(r, 8); err != nil {
+			return nil, xerrors.Errorf("unable to read the creation date: %w", err)
+		}
+
+		var chainLen uint32 = 1
+		switch tag {
+		case jksPrivateKeyTag:
+			// Skip the encrypted private key, only the certificate chain is kept
+			if _, err := readBlock(r); err != nil {
+				return nil, xerrors.Errorf("unable to read the private key: %w", err)
+			}
+			if err := binary.Read(r, binary.BigEndian, &chainLen); err != nil {
+				return nil, xerrors.Errorf("unable to read the certificate chain: %w", err)
+			}
+		case jksTrustedCertTag:
+		default:
+			return nil, xerrors.Errorf("unknown entry tag: %d", tag)
+		}
+
+		for j := uint32(0); j < chainLen; j++ {
+			cert, err := readCertificate(r, header.Version)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, cert)
+		}
+	}
+	return certs, nil
+}
+
+func readCertificate(r io.Reader, version uint32) (*x509.Certificate, error) {
+	// The certificate type (e.g. "X.509") is only available in version 2
+	if version == 2 {
+		if _, err := readUTF(r); err != nil {
+			return nil, xerrors.Errorf("unable to read the certificate type: %w", err)
+		}
+	}
+	der, err := readBlock(r)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read the certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse the certificate: %w", err)
+	}
+	return cert, nil
+}
+
+func readUTF(r io.Reader) (string, error) {
+	var size uint16
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return "", err
+	}
+	b, err := readBytes(r, int(size))
+	return string(b), err
+}
+
+func readBlock(r io.Reader) ([]byte, error) {
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	return readBytes(r, int(size))
+}
+
+// readBytes doesn't allocate the size up front, as it comes from the file and may be corrupted
+func readBytes(r io.Reader, size int) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, int64(size)))
+	if err != nil {
+		return nil, err
+	} else if len(b) != size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return b, nil
+}
@@ -0,0 +1,82 @@
+package certificate
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+type certificate struct {
+	*x509.Certificate
+
+	// Line numbers in PEM files
+	startLine int
+	endLine   int
+}
+
+// parseCertificates parses a Java KeyStore or a bundle of PEM certificates
+func parseCertificates(content []byte) ([]certificate, error) {
+	if bytes.HasPrefix(content, []byte{0xFE, 0xED, 0xFE, 0xED}) {
+		jksCerts, err := parseJKS(bytes.NewReader(content))
+		if err != nil {
+			return nil, xerrors.Errorf("JKS parse error: %w", err)
+		}
+		var certs []certificate
+		for _, cert := range jksCerts {
+			certs = append(certs, certificate{Certificate: cert})
+		}
+		return certs, nil
+	}
+
+	var certs []certificate
+	for _, block := range parsePEM(content) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			log.Logger.Debugf("Unable to parse the certificate: %s", err)
+			continue
+		}
+		certs = append(certs, certificate{
+			Certificate: cert,
+			startLine:   block.startLine,
+			endLine:     block.endLine,
+		})
+	}
+	return certs, nil
+}
+
+type pemBlock struct {
+	*pem.Block
+
+	startLine int
+	endLine   int
+}
+
+// parsePEM decodes all the PEM blocks and keeps their positions
+func parsePEM(content []byte) []pemBlock {
+	var blocks []pemBlock
+	rest := content
+	for {
+		offset := len(content) - len(rest)
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		start := offset + bytes.Index(content[offset:], []byte("-----BEGIN"))
+		end := len(content) - len(rest)
+		blocks = append(blocks, pemBlock{
+			Block:     block,
+			startLine: bytes.Count(content[:start], []byte("\n")) + 1,
+			endLine:   bytes.Count(bytes.TrimRight(content[:end], "\n"), []byte("\n")) + 1,
+		})
+	}
+	return blocks
+}
@@ -0,0 +1,98 @@
+package certificate
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+func init() {
+	analyzer.RegisterAnalyzer(&trustStoreAnalyzer{})
+}
+
+const (
+	trustStoreVersion   = 2
+	trustStoreNamespace = "truststore"
+)
+
+var (
+	// CA bundles of the OS distributions
+	requiredFiles = []string{
+		"etc/ssl/certs/ca-certificates.crt",                   // Debian, Ubuntu, Alpine
+		"etc/ssl/cert.pem",                                    // Alpine
+		"etc/pki/tls/certs/ca-bundle.crt",                     // RHEL, CentOS, Fedora
+		"etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem",    // RHEL, CentOS, Fedora
+		"etc/ssl/ca-bundle.pem",                               // SUSE
+		"var/lib/ca-certificates/ca-bundle.pem",               // SUSE
+		"usr/local/share/ca-certificates/ca-certificates.crt", // Custom CAs
+	}
+
+	// CA bundles embedded in runtimes and libraries
+	requiredSuffixes = []string{
+		"/lib/security/cacerts", // Java runtimes
+		"/certifi/cacert.pem",   // Python certifi
+	}
+)
+
+// trustStoreAnalyzer detects the CA trust stores and flags the weak and distrusted CAs.
+// The expired CAs are checked at scan time by CheckExpiry.
+type trustStoreAnalyzer struct{}
+
+func (a trustStoreAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	content, err := io.ReadAll(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("read error %s: %w", input.FilePath, err)
+	}
+
+	certs, err := parseCertificates(content)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to parse the trust store %s: %w", input.FilePath, err)
+	} else if len(certs) == 0 {
+		return nil, nil
+	}
+	log.Logger.Debugf("Detected trust store with %d certificates: %s", len(certs), input.FilePath)
+
+	misconf := evaluate(trustStoreChecks, trustStoreNamespace, certs)
+	misconf.FileType = types.TrustStore
+	misconf.FilePath = input.FilePath
+
+	inventory := types.CertificateInventory{
+		FilePath: input.FilePath,
+		FileType: types.TrustStore,
+	}
+	for _, cert := range certs {
+		inventory.Certificates = append(inventory.Certificates, toCertificate(cert))
+	}
+
+	return &analyzer.AnalysisResult{
+		Misconfigurations: []types.Misconfiguration{misconf},
+		Certificates:      []types.CertificateInventory{inventory},
+	}, nil
+}
+
+func (a trustStoreAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	filePath = filepath.ToSlash(filePath)
+	if slices.Contains(requiredFiles, filePath) {
+		return true
+	}
+	return slices.ContainsFunc(requiredSuffixes, func(suffix string) bool {
+		return strings.HasSuffix(filePath, suffix)
+	})
+}
+
+func (a trustStoreAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeTrustStore
+}
+
+func (a trustStoreAnalyzer) Version() int {
+	return trustStoreVersion
+}
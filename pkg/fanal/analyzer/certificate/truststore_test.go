@@ -0,0 +1,202 @@
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+var now = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func newCertificate(t *testing.T, commonName, org string, notAfter time.Time, rsaKeySize int) []byte {
+	t.Helper()
+
+	var key crypto.Signer
+	if rsaKeySize > 0 {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+		require.NoError(t, err)
+		key = rsaKey
+	} else {
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		key = ecKey
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{org},
+		},
+		NotBefore:             notAfter.AddDate(-10, 0, 0),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	require.NoError(t, err)
+	return der
+}
+
+func newJKS(t *testing.T, certs ...[]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	write := func(v any) {
+		require.NoError(t, binary.Write(&buf, binary.BigEndian, v))
+	}
+	write([]uint32{jksMagic, 2, uint32(len(certs))})
+	for i, cert := range certs {
+		alias := []byte{byte('a' + i)}
+		write(uint32(jksTrustedCertTag))
+		write(uint16(len(alias)))
+		buf.Write(alias)
+		write(uint64(0))
+		write(uint16(len("X.509")))
+		buf.WriteString("X.509")
+		write(uint32(len(cert)))
+		buf.Write(cert)
+	}
+	// Keystore digest
+	buf.Write(make([]byte, 20))
+	return buf.Bytes()
+}
+
+func Test_trustStoreAnalyzer_Analyze(t *testing.T) {
+	valid := newCertificate(t, "Valid Root CA", "Example", now.AddDate(5, 0, 0), 0)
+	expired := newCertificate(t, "Expired Root CA", "Example", now.AddDate(-1, 0, 0), 2048)
+	weak := newCertificate(t, "Weak Root CA", "Example", now.AddDate(5, 0, 0), 1024)
+	distrusted := newCertificate(t, "Distrusted Root CA", "TrustCor Systems S. de R.L.", now.AddDate(5, 0, 0), 0)
+
+	var bundle bytes.Buffer
+	for _, der := range [][]byte{valid, expired, weak} {
+		require.NoError(t, pem.Encode(&bundle, &pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+	validLines := bytes.Count(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: valid}), []byte("\n"))
+	expiredLines := bytes.Count(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: expired}), []byte("\n"))
+	weakLines := bytes.Count(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: weak}), []byte("\n"))
+
+	tests := []struct {
+		name      string
+		filePath  string
+		content   []byte
+		wantIDs   []string
+		wantCause []types.CauseMetadata
+		wantPass  int
+		wantCerts []string
+	}{
+		{
+			name:     "PEM bundle",
+			filePath: "etc/ssl/certs/ca-certificates.crt",
+			content:  bundle.Bytes(),
+			wantIDs:  []string{"CERT002"},
+			wantCause: []types.CauseMetadata{
+				{
+					Resource:  "Weak Root CA",
+					StartLine: validLines + expiredLines + 1,
+					EndLine:   validLines + expiredLines + weakLines,
+				},
+			},
+			wantPass:  2,
+			wantCerts: []string{"Valid Root CA", "Expired Root CA", "Weak Root CA"},
+		},
+		{
+			name:     "Java KeyStore",
+			filePath: "opt/java/openjdk/lib/security/cacerts",
+			content:  newJKS(t, valid, distrusted),
+			wantIDs:  []string{"CERT004"},
+			wantCause: []types.CauseMetadata{
+				{Resource: "Distrusted Root CA"},
+			},
+			wantPass:  2,
+			wantCerts: []string{"Valid Root CA", "Distrusted Root CA"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := trustStoreAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  bytes.NewReader(tt.content),
+			})
+			require.NoError(t, err)
+			require.Len(t, got.Misconfigurations, 1)
+
+			misconf := got.Misconfigurations[0]
+			assert.Equal(t, types.TrustStore, misconf.FileType)
+			assert.Equal(t, tt.filePath, misconf.FilePath)
+			assert.Len(t, misconf.Successes, tt.wantPass)
+
+			var ids []string
+			var causes []types.CauseMetadata
+			for _, failure := range misconf.Failures {
+				ids = append(ids, failure.ID)
+				causes = append(causes, failure.CauseMetadata)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantCause, causes)
+
+			require.Len(t, got.Certificates, 1)
+			inventory := got.Certificates[0]
+			assert.Equal(t, types.TrustStore, inventory.FileType)
+			assert.Equal(t, tt.filePath, inventory.FilePath)
+			var names []string
+			for _, cert := range inventory.Certificates {
+				names = append(names, cert.Name())
+			}
+			assert.Equal(t, tt.wantCerts, names)
+		})
+	}
+}
+
+func Test_trustStoreAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "Debian CA bundle",
+			filePath: "etc/ssl/certs/ca-certificates.crt",
+			want:     true,
+		},
+		{
+			name:     "Java cacerts",
+			filePath: "usr/lib/jvm/java-17-openjdk-amd64/lib/security/cacerts",
+			want:     true,
+		},
+		{
+			name:     "certifi",
+			filePath: "usr/local/lib/python3.11/site-packages/certifi/cacert.pem",
+			want:     true,
+		},
+		{
+			name:     "individual certificate",
+			filePath: "etc/ssl/certs/ISRG_Root_X1.pem",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := trustStoreAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package cloudinit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+const (
+	namespace = "cloudinit"
+	checkType = "Cloud-init Security Check"
+)
+
+var (
+	plaintextPasswordCheck = types.PolicyMetadata{
+		ID:                 "CLOUDINIT001",
+		Type:               checkType,
+		Title:              "Plaintext password",
+		Description:        "Passwords in cloud-init configuration and user-data are readable by anyone with access to the image or the instance metadata.",
+		Severity:           "HIGH",
+		RecommendedActions: "Use password hashes or SSH keys, and fetch credentials from a secret store at runtime.",
+	}
+	pipeToShellCheck = types.PolicyMetadata{
+		ID:                 "CLOUDINIT002",
+		Type:               checkType,
+		Title:              "Remote script piped to a shell",
+		Description:        "Downloading a script and piping it to a shell runs unverified code as root on every boot of the image.",
+		Severity:           "MEDIUM",
+		RecommendedActions: "Download the script, verify its checksum or signature, and then run it.",
+	}
+	passwordAuthCheck = types.PolicyMetadata{
+		ID:                 "CLOUDINIT003",
+		Type:               checkType,
+		Title:              "SSH password authentication enabled",
+		Description:        "Password authentication exposes SSH to brute-force attacks.",
+		Severity:           "MEDIUM",
+		RecommendedActions: "Set 'ssh_pwauth: false' and don't enable 'PasswordAuthentication' in sshd_config.",
+	}
+	rootLoginCheck = types.PolicyMetadata{
+		ID:                 "CLOUDINIT004",
+		Type:               checkType,
+		Title:              "SSH root login enabled",
+		Description:        "Logging in as root over SSH bypasses the audit trail of individual users.",
+		Severity:           "MEDIUM",
+		RecommendedActions: "Set 'disable_root: true' and don't enable 'PermitRootLogin' in sshd_config.",
+	}
+
+	checks = []types.PolicyMetadata{
+		plaintextPasswordCheck,
+		pipeToShellCheck,
+		passwordAuthCheck,
+		rootLoginCheck,
+	}
+)
+
+var (
+	pipeToShellRegexp  = regexp.MustCompile(`\b(?:curl|wget)\b[^|;&\n]*\|\s*(?:sudo\s+(?:-\S+\s+)*)?(?:ba|da|z|k)?sh\b`)
+	passwordAuthRegexp = regexp.MustCompile(`(?i)\bPasswordAuthentication\s+yes\b`)
+	rootLoginRegexp    = regexp.MustCompile(`(?i)\bPermitRootLogin\s+yes\b`)
+	chpasswdRegexp     = regexp.MustCompile(`\becho\s+["']?[\w.-]+:[^\s"'|]+["']?\s*\|\s*(?:sudo\s+)?chpasswd\b`)
+)
+
+// finding is a failed check with the location of the cause
+type finding struct {
+	check     types.PolicyMetadata
+	message   string
+	startLine int
+	endLine   int
+}
+
+// evaluateScript checks a shell script line by line
+func evaluateScript(script string, offset int) []finding {
+	var findings []finding
+	for i, line := range strings.Split(script, "\n") {
+		findings = append(findings, evaluateCommand(line, offset+i+1, offset+i+1)...)
+	}
+	return findings
+}
+
+func evaluateCommand(command string, startLine, endLine int) []finding {
+	trimmed := strings.TrimSpace(command)
+	if strings.HasPrefix(trimmed, "#") {
+		return nil
+	}
+
+	var findings []finding
+	add := func(check types.PolicyMetadata, message string) {
+		findings = append(findings, finding{
+			check:     check,
+			message:   message,
+			startLine: startLine,
+			endLine:   endLine,
+		})
+	}
+	if pipeToShellRegexp.MatchString(trimmed) {
+		add(pipeToShellCheck, fmt.Sprintf("Remote script is piped to a shell: %s", trimmed))
+	}
+	if chpasswdRegexp.MatchString(trimmed) {
+		add(plaintextPasswordCheck, "Plaintext password is passed to chpasswd")
+	}
+	if passwordAuthRegexp.MatchString(trimmed) {
+		add(passwordAuthCheck, "SSH password authentication is enabled in sshd_config")
+	}
+	if rootLoginRegexp.MatchString(trimmed) {
+		add(rootLoginCheck, "SSH root login is enabled in sshd_config")
+	}
+	return findings
+}
+
+// evaluateCloudConfig checks a "#cloud-config" document
+func evaluateCloudConfig(content []byte, offset int) ([]finding, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	root := doc.Content[0]
+
+	var findings []finding
+	add := func(check types.PolicyMetadata, message string, node *yaml.Node) {
+		findings = append(findings, finding{
+			check:     check,
+			message:   message,
+			startLine: offset + node.Line,
+			endLine:   offset + lastLine(node),
+		})
+	}
+
+	if password := mapValue(root, "password"); password != nil && password.Kind == yaml.ScalarNode {
+		add(plaintextPasswordCheck, "Plaintext password is set for the default user in 'password'", password)
+	}
+
+	if chpasswd := mapValue(root, "chpasswd"); chpasswd != nil {
+		// Deprecated format, e.g. "list: user:password"
+		if list := mapValue(chpasswd, "list"); list != nil {
+			for _, entry := range scalars(list) {
+				if user, password, ok := strings.Cut(entry.value, ":"); ok && !isHashOrRandom(password) {
+					add(plaintextPasswordCheck, fmt.Sprintf("Plaintext password is set for user '%s' in 'chpasswd.list'", strings.TrimSpace(user)), entry.node)
+				}
+			}
+		}
+		if users := mapValue(chpasswd, "users"); users != nil && users.Kind == yaml.SequenceNode {
+			for _, user := range users.Content {
+				if typ := mapValue(user, "type"); typ != nil && typ.Value == "text" {
+					add(plaintextPasswordCheck, fmt.Sprintf("Plaintext password is set for user '%s' in 'chpasswd.users'", scalarValue(user, "name")), user)
+				}
+			}
+		}
+	}
+
+	if users := mapValue(root, "users"); users != nil && users.Kind == yaml.SequenceNode {
+		for _, user := range users.Content {
+			if password := mapValue(user, "plain_text_passwd"); password != nil {
+				add(plaintextPasswordCheck, fmt.Sprintf("Plaintext password is set for user '%s' in 'plain_text_passwd'", scalarValue(user, "name")), password)
+			}
+		}
+	}
+
+	if pwauth := mapValue(root, "ssh_pwauth"); pwauth != nil && isTrue(pwauth.Value) {
+		add(passwordAuthCheck, "SSH password authentication is enabled by 'ssh_pwauth'", pwauth)
+	}
+	if disableRoot := mapValue(root, "disable_root"); disableRoot != nil && isFalse(disableRoot.Value) {
+		add(rootLoginCheck, "SSH root login is enabled by 'disable_root'", disableRoot)
+	}
+
+	for _, key := range []string{"bootcmd", "runcmd"} {
+		commands := mapValue(root, key)
+		if commands == nil || commands.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, command := range commands.Content {
+			switch command.Kind {
+			case yaml.ScalarNode:
+				// Block scalars may contain multiple lines
+				findings = append(findings, evaluateScript(command.Value, blockOffset(command, offset))...)
+			case yaml.SequenceNode:
+				var args []string
+				for _, arg := range command.Content {
+					args = append(args, arg.Value)
+				}
+				findings = append(findings, evaluateCommand(strings.Join(args, " "), offset+command.Line, offset+lastLine(command))...)
+			}
+		}
+	}
+
+	if files := mapValue(root, "write_files"); files != nil && files.Kind == yaml.SequenceNode {
+		for _, file := range files.Content {
+			content := mapValue(file, "content")
+			if content == nil || content.Kind != yaml.ScalarNode || scalarValue(file, "encoding") != "" {
+				continue
+			}
+			findings = append(findings, evaluateScript(content.Value, blockOffset(content, offset))...)
+		}
+	}
+	return findings, nil
+}
+
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalarValue(node *yaml.Node, key string) string {
+	if v := mapValue(node, key); v != nil && v.Kind == yaml.ScalarNode {
+		return v.Value
+	}
+	return ""
+}
+
+type scalar struct {
+	value string
+	node  *yaml.Node
+}
+
+// scalars returns the entries of a list, which can be a sequence or a multi-line string
+func scalars(node *yaml.Node) []scalar {
+	var entries []scalar
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, entry := range node.Content {
+			entries = append(entries, scalar{value: entry.Value, node: entry})
+		}
+	case yaml.ScalarNode:
+		for _, line := range strings.Split(strings.TrimSpace(node.Value), "\n") {
+			entries = append(entries, scalar{value: line, node: node})
+		}
+	}
+	return entries
+}
+
+// blockOffset returns the line before the first line of the scalar,
+// as the content of block scalars starts on the line after the indicator.
+func blockOffset(node *yaml.Node, offset int) int {
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return offset + node.Line
+	}
+	return offset + node.Line - 1
+}
+
+func lastLine(node *yaml.Node) int {
+	last := node.Line
+	if node.Kind == yaml.ScalarNode {
+		last += strings.Count(strings.TrimRight(node.Value, "\n"), "\n")
+		if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			last++
+		}
+	}
+	for _, child := range node.Content {
+		if l := lastLine(child); l > last {
+			last = l
+		}
+	}
+	return last
+}
+
+func isHashOrRandom(password string) bool {
+	password = strings.TrimSpace(password)
+	return strings.HasPrefix(password, "$") || password == "R" || password == "RANDOM"
+}
+
+func isTrue(v string) bool {
+	switch strings.ToLower(v) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
+
+func isFalse(v string) bool {
+	switch strings.ToLower(v) {
+	case "false", "no", "off", "0":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package cloudinit
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+func init() {
+	analyzer.RegisterAnalyzer(&cloudInitAnalyzer{})
+}
+
+const (
+	version = 1
+
+	// Bigger files are unlikely to be written by hand
+	maxFileSize = 1 << 20 // 1MB
+)
+
+type fileType int
+
+const (
+	unknownFile fileType = iota
+	configFile
+	userDataFile
+	scriptFile
+)
+
+// cloudInitAnalyzer checks the cloud-init configuration, user-data and boot scripts, mainly in VM images
+type cloudInitAnalyzer struct{}
+
+func (a cloudInitAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	content, err := io.ReadAll(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("read error %s: %w", input.FilePath, err)
+	}
+
+	var parts []part
+	switch classify(input.FilePath) {
+	case configFile:
+		// The configuration files of cloud-init don't have the "#cloud-config" header
+		parts = []part{{typ: cloudConfigPart, content: content}}
+	case userDataFile:
+		parts = splitUserData(content)
+	case scriptFile:
+		parts = []part{{typ: scriptPart, content: content}}
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	var findings []finding
+	for _, p := range parts {
+		offset := max(p.offset, 0)
+		var partFindings []finding
+		switch p.typ {
+		case cloudConfigPart:
+			partFindings, err = evaluateCloudConfig(p.content, offset)
+			if err != nil {
+				log.Logger.Debugf("Unable to parse cloud-config in %s: %s", input.FilePath, err)
+				continue
+			}
+		case scriptPart:
+			partFindings = evaluateScript(string(p.content), offset)
+		}
+		if p.offset < 0 {
+			for i := range partFindings {
+				partFindings[i].startLine, partFindings[i].endLine = 0, 0
+			}
+		}
+		findings = append(findings, partFindings...)
+	}
+
+	misconf := types.Misconfiguration{
+		FileType: types.CloudInit,
+		FilePath: input.FilePath,
+	}
+	for _, check := range checks {
+		var failures types.MisconfResults
+		for _, f := range findings {
+			if f.check.ID != check.ID {
+				continue
+			}
+			failures = append(failures, types.MisconfResult{
+				Namespace:      namespace,
+				Message:        f.message,
+				PolicyMetadata: check,
+				CauseMetadata: types.CauseMetadata{
+					StartLine: f.startLine,
+					EndLine:   f.endLine,
+				},
+			})
+		}
+		if len(failures) > 0 {
+			misconf.Failures = append(misconf.Failures, failures...)
+			continue
+		}
+		misconf.Successes = append(misconf.Successes, types.MisconfResult{
+			Namespace:      namespace,
+			PolicyMetadata: check,
+		})
+	}
+
+	return &analyzer.AnalysisResult{
+		Misconfigurations: []types.Misconfiguration{misconf},
+	}, nil
+}
+
+func (a cloudInitAnalyzer) Required(filePath string, info os.FileInfo) bool {
+	if info != nil && info.Size() > maxFileSize {
+		return false
+	}
+	return classify(filePath) != unknownFile
+}
+
+func (a cloudInitAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeCloudInit
+}
+
+func (a cloudInitAnalyzer) Version() int {
+	return version
+}
+
+// classify returns the type of the file from its path
+func classify(filePath string) fileType {
+	filePath = filepath.ToSlash(filePath)
+	dir, name := path.Split(filePath)
+	dir = strings.TrimSuffix(dir, "/")
+
+	switch {
+	case filePath == "etc/cloud/cloud.cfg":
+		return configFile
+	case dir == "etc/cloud/cloud.cfg.d" && path.Ext(name) == ".cfg":
+		return configFile
+	// e.g. var/lib/cloud/instances/i-0123456789abcdef0/user-data.txt
+	case path.Dir(dir) == "var/lib/cloud/instances" && name == "user-data.txt":
+		return userDataFile
+	// e.g. var/lib/cloud/seed/nocloud/user-data
+	case path.Dir(dir) == "var/lib/cloud/seed" && name == "user-data":
+		return userDataFile
+	// Scripts run by the scripts-per-* modules, e.g. var/lib/cloud/scripts/per-boot/setup.sh
+	case slices.Contains([]string{"per-boot", "per-instance", "per-once"}, path.Base(dir)) &&
+		path.Dir(dir) == "var/lib/cloud/scripts":
+		return scriptFile
+	}
+	return unknownFile
+}
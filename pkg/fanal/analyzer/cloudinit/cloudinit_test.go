@@ -0,0 +1,169 @@
+package cloudinit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func Test_cloudInitAnalyzer_Analyze(t *testing.T) {
+	type failure struct {
+		ID        string
+		Message   string
+		StartLine int
+		EndLine   int
+	}
+
+	userData, err := os.ReadFile("testdata/user-data.txt")
+	require.NoError(t, err)
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	_, err = w.Write(userData)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	tests := []struct {
+		name      string
+		filePath  string
+		content   []byte
+		inputFile string
+		want      []failure
+		wantPass  []string
+	}{
+		{
+			name:      "cloud.cfg",
+			filePath:  "etc/cloud/cloud.cfg",
+			inputFile: "testdata/cloud.cfg",
+			want: []failure{
+				{"CLOUDINIT001", "Plaintext password is set for user 'admin' in 'plain_text_passwd'", 4, 4},
+				{"CLOUDINIT002", "Remote script is piped to a shell: curl -fsSL https://example.com/install.sh | sudo bash", 11, 11},
+				{"CLOUDINIT003", "SSH password authentication is enabled by 'ssh_pwauth'", 8, 8},
+				{"CLOUDINIT004", "SSH root login is enabled by 'disable_root'", 7, 7},
+				{"CLOUDINIT004", "SSH root login is enabled in sshd_config", 15, 15},
+			},
+		},
+		{
+			name:      "hardened config",
+			filePath:  "etc/cloud/cloud.cfg.d/99-hardening.cfg",
+			inputFile: "testdata/hardened.cfg",
+			wantPass:  []string{"CLOUDINIT001", "CLOUDINIT002", "CLOUDINIT003", "CLOUDINIT004"},
+		},
+		{
+			name:      "multi-part user-data",
+			filePath:  "var/lib/cloud/instances/i-0123456789abcdef0/user-data.txt",
+			inputFile: "testdata/user-data.txt",
+			want: []failure{
+				{"CLOUDINIT001", "Plaintext password is set for user 'root' in 'chpasswd.list'", 10, 12},
+				{"CLOUDINIT002", "Remote script is piped to a shell: wget -qO- https://example.com/agent.sh | sh", 21, 21},
+				{"CLOUDINIT003", "SSH password authentication is enabled in sshd_config", 20, 20},
+			},
+			wantPass: []string{"CLOUDINIT004"},
+		},
+		{
+			name:     "compressed user-data",
+			filePath: "var/lib/cloud/seed/nocloud/user-data",
+			content:  compressed.Bytes(),
+			want: []failure{
+				{"CLOUDINIT001", "Plaintext password is set for user 'root' in 'chpasswd.list'", 0, 0},
+				{"CLOUDINIT002", "Remote script is piped to a shell: wget -qO- https://example.com/agent.sh | sh", 0, 0},
+				{"CLOUDINIT003", "SSH password authentication is enabled in sshd_config", 0, 0},
+			},
+			wantPass: []string{"CLOUDINIT004"},
+		},
+		{
+			name:      "boot script",
+			filePath:  "var/lib/cloud/scripts/per-boot/setup.sh",
+			inputFile: "testdata/setup.sh",
+			want: []failure{
+				{"CLOUDINIT001", "Plaintext password is passed to chpasswd", 3, 3},
+			},
+			wantPass: []string{"CLOUDINIT002", "CLOUDINIT003", "CLOUDINIT004"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := tt.content
+			if tt.inputFile != "" {
+				content, err = os.ReadFile(tt.inputFile)
+				require.NoError(t, err)
+			}
+
+			a := cloudInitAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  bytes.NewReader(content),
+			})
+			require.NoError(t, err)
+			require.Len(t, got.Misconfigurations, 1)
+
+			misconf := got.Misconfigurations[0]
+			assert.Equal(t, types.CloudInit, misconf.FileType)
+			assert.Equal(t, tt.filePath, misconf.FilePath)
+
+			var failures []failure
+			for _, f := range misconf.Failures {
+				failures = append(failures, failure{f.ID, f.Message, f.StartLine, f.EndLine})
+			}
+			assert.Equal(t, tt.want, failures)
+
+			var passed []string
+			for _, s := range misconf.Successes {
+				passed = append(passed, s.ID)
+			}
+			assert.Equal(t, tt.wantPass, passed)
+		})
+	}
+}
+
+func Test_cloudInitAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "cloud.cfg",
+			filePath: "etc/cloud/cloud.cfg",
+			want:     true,
+		},
+		{
+			name:     "drop-in config",
+			filePath: "etc/cloud/cloud.cfg.d/90_dpkg.cfg",
+			want:     true,
+		},
+		{
+			name:     "user-data",
+			filePath: "var/lib/cloud/instances/i-0123456789abcdef0/user-data.txt",
+			want:     true,
+		},
+		{
+			name:     "per-instance script",
+			filePath: "var/lib/cloud/scripts/per-instance/bootstrap",
+			want:     true,
+		},
+		{
+			name:     "README in cloud.cfg.d",
+			filePath: "etc/cloud/cloud.cfg.d/README",
+			want:     false,
+		},
+		{
+			name:     "vendor-data",
+			filePath: "var/lib/cloud/instances/i-0123456789abcdef0/vendor-data.txt",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := cloudInitAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
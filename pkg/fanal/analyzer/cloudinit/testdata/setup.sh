@@ -0,0 +1,3 @@
+#!/bin/sh
+# curl https://example.com/install.sh | sh
+echo 'deploy:s3cret' | chpasswd
@@ -0,0 +1,125 @@
+package cloudinit
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+type partType int
+
+const (
+	cloudConfigPart partType = iota
+	scriptPart
+)
+
+// part is a cloud-config document or a script in user-data
+type part struct {
+	typ     partType
+	content []byte
+	// offset is the number of lines before the part in the file, or -1 if the part is encoded
+	offset int
+}
+
+// maxUserDataSize limits the size of decompressed user-data
+const maxUserDataSize = 16 << 20 // 16MB
+
+// splitUserData returns the parts of user-data, which may be gzip-compressed or a MIME multi-part archive
+func splitUserData(content []byte) []part {
+	switch {
+	case bytes.HasPrefix(content, []byte{0x1f, 0x8b}):
+		r, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			log.Logger.Debugf("Unable to decompress user-data: %s", err)
+			return nil
+		}
+		decompressed, err := io.ReadAll(io.LimitReader(r, maxUserDataSize))
+		if err != nil {
+			log.Logger.Debugf("Unable to decompress user-data: %s", err)
+			return nil
+		}
+		return encoded(splitUserData(decompressed))
+	case hasHeader(content, "#cloud-config"):
+		return []part{{typ: cloudConfigPart, content: content}}
+	case hasHeader(content, "#!"):
+		return []part{{typ: scriptPart, content: content}}
+	case hasHeader(content, "Content-Type:") || hasHeader(content, "MIME-Version:"):
+		return splitMultipart(content)
+	}
+	return nil
+}
+
+func splitMultipart(content []byte) []part {
+	msg, err := mail.ReadMessage(bytes.NewReader(content))
+	if err != nil {
+		log.Logger.Debugf("Unable to parse the MIME user-data: %s", err)
+		return nil
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return nil
+	}
+
+	var parts []part
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		} else if err != nil {
+			log.Logger.Debugf("Unable to parse the MIME user-data: %s", err)
+			return parts
+		}
+
+		body, err := io.ReadAll(io.LimitReader(p, maxUserDataSize))
+		if err != nil {
+			return parts
+		}
+
+		isEncoded := strings.EqualFold(p.Header.Get("Content-Transfer-Encoding"), "base64")
+		if isEncoded {
+			if body, err = io.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(body))); err != nil {
+				log.Logger.Debugf("Unable to decode the user-data part: %s", err)
+				continue
+			}
+		}
+
+		partType, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		var pp part
+		switch partType {
+		case "text/cloud-config":
+			pp = part{typ: cloudConfigPart, content: body}
+		case "text/x-shellscript", "text/x-shellscript-per-boot", "text/x-shellscript-per-instance", "text/x-shellscript-per-once":
+			pp = part{typ: scriptPart, content: body}
+		default:
+			continue
+		}
+
+		pp.offset = -1
+		if i := bytes.Index(content, body); !isEncoded && i >= 0 {
+			pp.offset = bytes.Count(content[:i], []byte("\n"))
+		}
+		parts = append(parts, pp)
+	}
+}
+
+// encoded marks the parts as not having line numbers in the file
+func encoded(parts []part) []part {
+	for i := range parts {
+		parts[i].offset = -1
+	}
+	return parts
+}
+
+func hasHeader(content []byte, header string) bool {
+	line, _, _ := bufio.NewReader(bytes.NewReader(content)).ReadLine()
+	return strings.HasPrefix(strings.TrimSpace(string(line)), header)
+}
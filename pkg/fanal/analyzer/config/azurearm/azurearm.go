@@ -32,7 +32,8 @@ func newAzureARMConfigAnalyzer(opts analyzer.AnalyzerOptions) (analyzer.PostAnal
 	return &azureARMConfigAnalyzer{Analyzer: a}, nil
 }
 
-// Required overrides config.Analyzer.Required() and check if the given file is JSON.
+// Required overrides config.Analyzer.Required() and check if the given file is JSON or Bicep.
 func (a *azureARMConfigAnalyzer) Required(filePath string, _ os.FileInfo) bool {
-	return filepath.Ext(filePath) == ".json"
+	ext := filepath.Ext(filePath)
+	return ext == ".json" || ext == ".bicep"
 }
@@ -20,6 +20,11 @@ func Test_azureARMConfigAnalyzer_Required(t *testing.T) {
 			filePath: "test.json",
 			want:     true,
 		},
+		{
+			name:     "bicep",
+			filePath: "main.bicep",
+			want:     true,
+		},
 		{
 			name:     "yaml",
 			filePath: "test.yaml",
@@ -50,7 +50,7 @@ func (*helmConfigAnalyzer) Required(filePath string, info os.FileInfo) bool {
 	}
 
 	name := filepath.Base(filePath)
-	for _, acceptable := range []string{"Chart.yaml", ".helmignore"} {
+	for _, acceptable := range []string{"Chart.yaml", "Chart.lock", ".helmignore"} {
 		if strings.EqualFold(name, acceptable) {
 			return true
 		}
@@ -44,6 +44,11 @@ func Test_helmConfigAnalyzer_Required(t *testing.T) {
 			filePath: ".helmignore",
 			want:     true,
 		},
+		{
+			name:     "Chart.lock",
+			filePath: "Chart.lock",
+			want:     true,
+		},
 		{
 			name:     "testchart.tgz",
 			filePath: "testchart.tgz",
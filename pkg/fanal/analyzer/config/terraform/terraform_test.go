@@ -27,6 +27,26 @@ func TestConfigAnalyzer_Required(t *testing.T) {
 			filePath: "/path/to/some.tfvars",
 			want:     true,
 		},
+		{
+			name:     "tofu",
+			filePath: "/path/to/main.tofu",
+			want:     true,
+		},
+		{
+			name:     "tofu.json",
+			filePath: "/path/to/main.tofu.json",
+			want:     true,
+		},
+		{
+			name:     "tfstack.hcl",
+			filePath: "/path/to/components.tfstack.hcl",
+			want:     true,
+		},
+		{
+			name:     "tfdeploy.hcl",
+			filePath: "/path/to/deployments.tfdeploy.hcl",
+			want:     true,
+		},
 		{
 			name:     "json",
 			filePath: "/path/to/some.json",
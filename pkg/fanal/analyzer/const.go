@@ -25,6 +25,7 @@ const (
 	TypeSUSE       Type = "suse"
 	TypeUbuntu     Type = "ubuntu"
 	TypeUbuntuESM  Type = "ubuntu-esm"
+	TypeWindows    Type = "windows"
 
 	// OS Package
 	TypeApk         Type = "apk"
@@ -32,6 +33,7 @@ const (
 	TypeDpkgLicense Type = "dpkg-license" // For analyzing licenses
 	TypeRpm         Type = "rpm"
 	TypeRpmqa       Type = "rpmqa"
+	TypeHotfix      Type = "windows-hotfix" // For Windows updates installed by the servicing stack
 
 	// OS Package Repository
 	TypeApkRepo Type = "apk-repo"
@@ -55,6 +57,7 @@ const (
 	TypeJar        Type = "jar"
 	TypePom        Type = "pom"
 	TypeGradleLock Type = "gradle-lockfile"
+	TypeJRE        Type = "jre"
 
 	// Node.js
 	TypeNpmPkgLock Type = "npm"
@@ -106,6 +109,23 @@ const (
 	TypeHistoryDockerfile Type = "history-dockerfile"
 	TypeImageConfigSecret Type = "image-config-secret"
 
+	// =====================
+	// Certificates and keys
+	// =====================
+	TypeTrustStore  Type = "trust-store"
+	TypeCertificate Type = "certificate"
+	TypeSSH         Type = "ssh"
+
+	// ==========
+	// Cloud-init
+	// ==========
+	TypeCloudInit Type = "cloud-init"
+
+	// ===========
+	// Boot config
+	// ===========
+	TypeBootConfig Type = "boot-config"
+
 	// =================
 	// Structured Config
 	// =================
@@ -151,11 +171,13 @@ var (
 		TypeRedHatBase,
 		TypeSUSE,
 		TypeUbuntu,
+		TypeWindows,
 		TypeApk,
 		TypeDpkg,
 		TypeDpkgLicense,
 		TypeRpm,
 		TypeRpmqa,
+		TypeHotfix,
 		TypeApkRepo,
 	}
 
@@ -168,6 +190,7 @@ var (
 		TypeJar,
 		TypePom,
 		TypeGradleLock,
+		TypeJRE,
 		TypeNpmPkgLock,
 		TypeNodePkg,
 		TypeYarn,
@@ -217,6 +240,7 @@ var (
 		TypePythonPkg,
 		TypeGoBinary,
 		TypeJar,
+		TypeJRE,
 		TypeRustBinary,
 	}
 
@@ -0,0 +1,119 @@
+package jre
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/dependency/parser/java/jre"
+	godeptypes "github.com/aquasecurity/trivy/pkg/dependency/parser/types"
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/language"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/utils/fsutils"
+)
+
+func init() {
+	analyzer.RegisterPostAnalyzer(analyzer.TypeJRE, newJREAnalyzer)
+}
+
+const (
+	version = 2
+
+	// JDK and JRE distributions have the "release" file at the root, e.g. /opt/java/openjdk/release
+	releaseFile = "release"
+)
+
+// javaBinaries are the launchers that a JDK or JRE has next to the "release" file
+var javaBinaries = []string{
+	"bin/java",
+	"bin/java.exe",
+}
+
+// jreAnalyzer detects the bundled Java runtimes and their versions
+type jreAnalyzer struct {
+	parser godeptypes.Parser
+}
+
+func newJREAnalyzer(_ analyzer.AnalyzerOptions) (analyzer.PostAnalyzer, error) {
+	return &jreAnalyzer{
+		parser: jre.NewParser(),
+	}, nil
+}
+
+func (a jreAnalyzer) PostAnalyze(_ context.Context, input analyzer.PostAnalysisInput) (*analyzer.AnalysisResult, error) {
+	var apps []types.Application
+
+	required := func(path string, d fs.DirEntry) bool {
+		return filepath.Base(path) == releaseFile
+	}
+
+	err := fsutils.WalkDir(input.FS, ".", required, func(filePath string, d fs.DirEntry, r io.Reader) error {
+		// Other software also ships a file named "release", e.g. /etc/release.
+		// Only the file in the root directory of a Java runtime is parsed.
+		if !a.isRuntimeDir(input.FS, path.Dir(filePath)) {
+			log.Logger.Debugf("JRE: %s is not in a Java runtime directory", filePath)
+			return nil
+		}
+
+		app, err := language.Parse(types.JRE, filePath, r, a.parser)
+		if err != nil {
+			return xerrors.Errorf("parse error: %w", err)
+		} else if app == nil {
+			return nil
+		}
+
+		apps = append(apps, *app)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("JRE walk error: %w", err)
+	}
+
+	return &analyzer.AnalysisResult{
+		Applications: apps,
+	}, nil
+}
+
+// isRuntimeDir checks if the directory has the layout of a JDK or JRE
+func (a jreAnalyzer) isRuntimeDir(fsys fs.FS, dir string) bool {
+	for _, bin := range javaBinaries {
+		if _, err := fs.Stat(fsys, path.Join(dir, bin)); err == nil {
+			return true
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			log.Logger.Debugf("JRE: unable to stat %s: %s", path.Join(dir, bin), err)
+		}
+	}
+	return false
+}
+
+func (a jreAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	if filepath.Base(filePath) == releaseFile {
+		return true
+	}
+
+	// The launcher is needed to confirm the layout of the runtime
+	filePath = "/" + filepath.ToSlash(filePath)
+	for _, bin := range javaBinaries {
+		if strings.HasSuffix(filePath, "/"+bin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a jreAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeJRE
+}
+
+func (a jreAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,104 @@
+package jre
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func Test_jreAnalyzer_PostAnalyze(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  string
+		want *analyzer.AnalysisResult
+	}{
+		{
+			name: "happy path",
+			dir:  "testdata/happy",
+			want: &analyzer.AnalysisResult{
+				Applications: []types.Application{
+					{
+						Type:     types.JRE,
+						FilePath: "opt/java/openjdk/release",
+						Libraries: types.Packages{
+							{
+								Name:    "java",
+								Version: "17.0.8-7",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "not in a Java runtime directory",
+			dir:  "testdata/no-runtime",
+			want: &analyzer.AnalysisResult{},
+		},
+		{
+			name: "no Java version",
+			dir:  "testdata/no-version",
+			want: &analyzer.AnalysisResult{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := newJREAnalyzer(analyzer.AnalyzerOptions{})
+			require.NoError(t, err)
+
+			got, err := a.PostAnalyze(context.Background(), analyzer.PostAnalysisInput{
+				FS: os.DirFS(tt.dir),
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_jreAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "release file",
+			filePath: "opt/java/openjdk/release",
+			want:     true,
+		},
+		{
+			name:     "java launcher",
+			filePath: "opt/java/openjdk/bin/java",
+			want:     true,
+		},
+		{
+			name:     "java launcher on Windows",
+			filePath: "Program Files/Java/jdk-17/bin/java.exe",
+			want:     true,
+		},
+		{
+			name:     "other file",
+			filePath: "opt/java/openjdk/lib/modules",
+			want:     false,
+		},
+		{
+			name:     "other binary",
+			filePath: "opt/java/openjdk/bin/javac",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := jreAnalyzer{}
+			got := a.Required(tt.filePath, nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"golang.org/x/xerrors"
 
@@ -18,7 +20,7 @@ func init() {
 	analyzer.RegisterAnalyzer(&rustBinaryLibraryAnalyzer{})
 }
 
-const version = 1
+const version = 2
 
 type rustBinaryLibraryAnalyzer struct{}
 
@@ -32,7 +34,11 @@ func (a rustBinaryLibraryAnalyzer) Analyze(_ context.Context, input analyzer.Ana
 	return res, nil
 }
 
-func (a rustBinaryLibraryAnalyzer) Required(_ string, fileInfo os.FileInfo) bool {
+func (a rustBinaryLibraryAnalyzer) Required(filePath string, fileInfo os.FileInfo) bool {
+	// WebAssembly modules are not executable in the filesystem
+	if fileInfo.Mode().IsRegular() && strings.EqualFold(filepath.Ext(filePath), ".wasm") {
+		return true
+	}
 	return utils.IsExecutable(fileInfo)
 }
 
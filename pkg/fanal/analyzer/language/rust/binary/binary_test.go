@@ -90,6 +90,11 @@ func Test_rustBinaryLibraryAnalyzer_Required(t *testing.T) {
 			filePath: "testdata/0644",
 			want:     false,
 		},
+		{
+			name:     "WebAssembly module",
+			filePath: "testdata/module.wasm",
+			want:     true,
+		},
 		{
 			name:     "symlink",
 			filePath: "testdata/symlink",
@@ -0,0 +1,72 @@
+package windows
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	fos "github.com/aquasecurity/trivy/pkg/fanal/analyzer/os"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func init() {
+	analyzer.RegisterAnalyzer(&windowsOSAnalyzer{})
+}
+
+const (
+	version = 1
+
+	// The servicing packages are stored in the component store, e.g.
+	// Windows/servicing/Packages/Microsoft-Windows-Foundation-Package~31bf3856ad364e35~amd64~~10.0.17763.1.mum
+	packagesDir       = "windows/servicing/packages"
+	foundationPackage = "microsoft-windows-foundation-package~"
+)
+
+// windowsOSAnalyzer detects the Windows build from the foundation package, which every Windows Server Core image has.
+// Nano Server images don't have the servicing stack, so the OS is not detected.
+type windowsOSAnalyzer struct{}
+
+type manifest struct {
+	AssemblyIdentity struct {
+		Version string `xml:"version,attr"`
+	} `xml:"assemblyIdentity"`
+}
+
+func (a windowsOSAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	var m manifest
+	if err := xml.NewDecoder(input.Content).Decode(&m); err != nil {
+		return nil, xerrors.Errorf("windows: %s decode error: %w", input.FilePath, err)
+	}
+
+	// e.g. 10.0.17763.1 => 10.0.17763
+	ver := strings.Split(m.AssemblyIdentity.Version, ".")
+	if len(ver) < 3 {
+		return nil, xerrors.Errorf("windows: %w", fos.AnalyzeOSError)
+	}
+	return &analyzer.AnalysisResult{
+		OS: types.OS{
+			Family: types.Windows,
+			Name:   strings.Join(ver[:3], "."),
+		},
+	}, nil
+}
+
+// Required matches the file names case-insensitively, as the filesystem of Windows is case-insensitive
+func (a windowsOSAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	dir, fileName := path.Split(strings.ToLower(filePath))
+	return strings.TrimSuffix(dir, "/") == packagesDir && strings.HasPrefix(fileName, foundationPackage) &&
+		path.Ext(fileName) == ".mum"
+}
+
+func (a windowsOSAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeWindows
+}
+
+func (a windowsOSAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,98 @@
+package windows
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func Test_windowsOSAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   analyzer.AnalysisInput
+		want    *analyzer.AnalysisResult
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			input: analyzer.AnalysisInput{
+				FilePath: "Windows/servicing/Packages/Microsoft-Windows-Foundation-Package~31bf3856ad364e35~amd64~~10.0.17763.1.mum",
+			},
+			want: &analyzer.AnalysisResult{
+				OS: types.OS{
+					Family: types.Windows,
+					Name:   "10.0.17763",
+				},
+			},
+		},
+		{
+			name: "sad path without version",
+			input: analyzer.AnalysisInput{
+				FilePath: "Windows/servicing/Packages/Microsoft-Windows-Foundation-Package~31bf3856ad364e35~amd64~~.mum",
+				Content:  strings.NewReader(`<assembly><assemblyIdentity name="Microsoft-Windows-Foundation-Package"/></assembly>`),
+			},
+			wantErr: "windows: unable to analyze OS information",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.input.Content == nil {
+				f, err := os.Open("testdata/Microsoft-Windows-Foundation-Package~31bf3856ad364e35~amd64~~10.0.17763.1.mum")
+				require.NoError(t, err)
+				defer f.Close()
+				tt.input.Content = f
+			}
+
+			a := windowsOSAnalyzer{}
+			got, err := a.Analyze(context.Background(), tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_windowsOSAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "foundation package",
+			filePath: "Windows/servicing/Packages/Microsoft-Windows-Foundation-Package~31bf3856ad364e35~amd64~~10.0.17763.1.mum",
+			want:     true,
+		},
+		{
+			name:     "case-insensitive",
+			filePath: "windows/Servicing/packages/microsoft-windows-foundation-package~31bf3856ad364e35~amd64~~10.0.20348.1.mum",
+			want:     true,
+		},
+		{
+			name:     "catalog",
+			filePath: "Windows/servicing/Packages/Microsoft-Windows-Foundation-Package~31bf3856ad364e35~amd64~~10.0.17763.1.cat",
+			want:     false,
+		},
+		{
+			name:     "another package",
+			filePath: "Windows/servicing/Packages/Package_for_RollupFix~31bf3856ad364e35~amd64~~17763.2028.1.7.mum",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := windowsOSAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package hotfix
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func init() {
+	analyzer.RegisterAnalyzer(&hotfixAnalyzer{})
+}
+
+const (
+	version = 1
+
+	// Updates are installed as servicing packages, e.g.
+	// Windows/servicing/Packages/Package_for_RollupFix~31bf3856ad364e35~amd64~~17763.2028.1.7.mum
+	// Windows/servicing/Packages/Package_for_KB5005112~31bf3856ad364e35~amd64~~17763.2028.1.1.mum
+	// The sub-packages such as "Package_1_for_KB5005112" have the same KB number, so they are not analyzed.
+	packagesDir   = "windows/servicing/packages"
+	packagePrefix = "package_for_"
+)
+
+// hotfixAnalyzer analyzes the manifests of the servicing packages in the Windows component store,
+// so that the installed updates are reported as the KB numbers like "Get-HotFix".
+type hotfixAnalyzer struct{}
+
+// manifest is the package manifest (.mum)
+type manifest struct {
+	AssemblyIdentity struct {
+		Version               string `xml:"version,attr"`
+		ProcessorArchitecture string `xml:"processorArchitecture,attr"`
+	} `xml:"assemblyIdentity"`
+	Package struct {
+		Identifier string `xml:"identifier,attr"`
+	} `xml:"package"`
+}
+
+func (a hotfixAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	var m manifest
+	if err := xml.NewDecoder(input.Content).Decode(&m); err != nil {
+		return nil, xerrors.Errorf("%s decode error: %w", input.FilePath, err)
+	}
+
+	// Only updates have the KB number, e.g. KB5005112
+	kb := m.Package.Identifier
+	if !strings.HasPrefix(kb, "KB") || m.AssemblyIdentity.Version == "" {
+		return nil, nil
+	}
+
+	return &analyzer.AnalysisResult{
+		PackageInfos: []types.PackageInfo{
+			{
+				FilePath: input.FilePath,
+				Packages: types.Packages{
+					{
+						ID:      fmt.Sprintf("%s@%s", kb, m.AssemblyIdentity.Version),
+						Name:    kb,
+						Version: m.AssemblyIdentity.Version,
+						Arch:    m.AssemblyIdentity.ProcessorArchitecture,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// Required matches the file names case-insensitively, as the filesystem of Windows is case-insensitive
+func (a hotfixAnalyzer) Required(filePath string, _ os.FileInfo) bool {
+	dir, fileName := path.Split(strings.ToLower(filePath))
+	return strings.TrimSuffix(dir, "/") == packagesDir && strings.HasPrefix(fileName, packagePrefix) &&
+		path.Ext(fileName) == ".mum"
+}
+
+func (a hotfixAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeHotfix
+}
+
+func (a hotfixAnalyzer) Version() int {
+	return version
+}
@@ -0,0 +1,121 @@
+package hotfix
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func Test_hotfixAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputFile string
+		want      *analyzer.AnalysisResult
+	}{
+		{
+			name:      "cumulative update",
+			inputFile: "Package_for_RollupFix~31bf3856ad364e35~amd64~~17763.2028.1.7.mum",
+			want: &analyzer.AnalysisResult{
+				PackageInfos: []types.PackageInfo{
+					{
+						FilePath: "Windows/servicing/Packages/Package_for_RollupFix~31bf3856ad364e35~amd64~~17763.2028.1.7.mum",
+						Packages: types.Packages{
+							{
+								ID:      "KB5004244@17763.2028.1.7",
+								Name:    "KB5004244",
+								Version: "17763.2028.1.7",
+								Arch:    "amd64",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "servicing stack update",
+			inputFile: "Package_for_ServicingStack_2026~31bf3856ad364e35~amd64~~17763.2026.1.0.mum",
+			want: &analyzer.AnalysisResult{
+				PackageInfos: []types.PackageInfo{
+					{
+						FilePath: "Windows/servicing/Packages/Package_for_ServicingStack_2026~31bf3856ad364e35~amd64~~17763.2026.1.0.mum",
+						Packages: types.Packages{
+							{
+								ID:      "KB5003711@17763.2026.1.0",
+								Name:    "KB5003711",
+								Version: "17763.2026.1.0",
+								Arch:    "amd64",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "not an update",
+			inputFile: "Package_for_DotNetRollup~31bf3856ad364e35~amd64~~10.0.4330.1.mum",
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(path.Join("testdata", tt.inputFile))
+			require.NoError(t, err)
+			defer f.Close()
+
+			a := hotfixAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: path.Join("Windows/servicing/Packages", tt.inputFile),
+				Content:  f,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_hotfixAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "update",
+			filePath: "Windows/servicing/Packages/Package_for_KB5005112~31bf3856ad364e35~amd64~~17763.2028.1.1.mum",
+			want:     true,
+		},
+		{
+			name:     "case-insensitive",
+			filePath: "WINDOWS/servicing/Packages/package_for_rollupfix~31bf3856ad364e35~amd64~~17763.2028.1.7.mum",
+			want:     true,
+		},
+		{
+			name:     "sub-package",
+			filePath: "Windows/servicing/Packages/Package_1_for_KB5004244~31bf3856ad364e35~amd64~~17763.2028.1.7.mum",
+			want:     false,
+		},
+		{
+			name:     "catalog",
+			filePath: "Windows/servicing/Packages/Package_for_KB5005112~31bf3856ad364e35~amd64~~17763.2028.1.1.cat",
+			want:     false,
+		},
+		{
+			name:     "another directory",
+			filePath: "Windows/WinSxS/Manifests/Package_for_KB5005112~31bf3856ad364e35~amd64~~17763.2028.1.1.mum",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := hotfixAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
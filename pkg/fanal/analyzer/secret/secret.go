@@ -71,7 +71,7 @@ func (a *SecretAnalyzer) Init(opt analyzer.AnalyzerOptions) error {
 	if err != nil {
 		return xerrors.Errorf("secret config error: %w", err)
 	}
-	a.scanner = secret.NewScanner(c)
+	a.scanner = secret.NewScanner(c, secret.WithFingerprintSalt(opt.SecretScannerOption.FingerprintSalt))
 	a.configPath = configPath
 	return nil
 }
@@ -0,0 +1,306 @@
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/dsa" //nolint:staticcheck // DSA keys are still found in old images
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+func init() {
+	analyzer.RegisterAnalyzer(&sshAnalyzer{})
+}
+
+const (
+	version   = 1
+	namespace = "ssh"
+	checkType = "SSH Security Check"
+
+	minRSAKeySize = 2048
+
+	// SSH keys are small, so bigger files are skipped
+	maxFileSize = 1 << 20 // 1MB
+)
+
+var (
+	hostKeyCheck = types.PolicyMetadata{
+		ID:                 "SSH001",
+		Type:               checkType,
+		Title:              "SSH host private key embedded",
+		Description:        "The SSH host private key is a part of the image, so all the hosts or containers created from it share the same key and can impersonate each other.",
+		Severity:           "HIGH",
+		RecommendedActions: "Remove the host keys from the image and generate them when the host or container starts, e.g. with 'ssh-keygen -A'.",
+	}
+	weakKeyCheck = types.PolicyMetadata{
+		ID:                 "SSH002",
+		Type:               checkType,
+		Title:              "Weak SSH key",
+		Description:        "DSA keys and RSA keys shorter than 2048 bits are considered insecure, and DSA keys are disabled by default since OpenSSH 7.0.",
+		Severity:           "HIGH",
+		RecommendedActions: "Replace the key with an Ed25519 key, or an RSA key of at least 2048 bits.",
+	}
+	authorizedKeysCheck = types.PolicyMetadata{
+		ID:                 "SSH003",
+		Type:               checkType,
+		Title:              "SSH authorized keys embedded",
+		Description:        "The authorized_keys file is a part of the image, so the holders of the keys can log into every host or container created from it.",
+		Severity:           "MEDIUM",
+		RecommendedActions: "Remove authorized_keys from the image and provision the keys at runtime.",
+	}
+	userKeyCheck = types.PolicyMetadata{
+		ID:                 "SSH004",
+		Type:               checkType,
+		Title:              "SSH user private key embedded",
+		Description:        "The SSH private key of a user is a part of the image, so anyone with access to the image can use it.",
+		Severity:           "HIGH",
+		RecommendedActions: "Remove the private key from the image, and mount it or use an SSH agent when it is needed.",
+	}
+)
+
+type fileKind int
+
+const (
+	unknownFile fileKind = iota
+	hostPrivateKey
+	hostPublicKey
+	authorizedKeys
+	userPrivateKey
+	userPublicKey
+)
+
+// sshAnalyzer checks the SSH host keys and the .ssh directories of the users
+type sshAnalyzer struct{}
+
+func (a sshAnalyzer) Analyze(_ context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error) {
+	content, err := io.ReadAll(input.Content)
+	if err != nil {
+		return nil, xerrors.Errorf("read error %s: %w", input.FilePath, err)
+	}
+
+	kind := classify(input.FilePath)
+	var keys []key
+	switch kind {
+	case hostPrivateKey, userPrivateKey:
+		k, err := parsePrivateKey(content)
+		if err != nil {
+			log.Logger.Debugf("Unable to parse the SSH private key %s: %s", input.FilePath, err)
+			return nil, nil
+		}
+		keys = append(keys, k)
+	case hostPublicKey, userPublicKey, authorizedKeys:
+		keys = parsePublicKeys(content)
+		if kind != authorizedKeys && len(keys) == 0 {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	misconf := types.Misconfiguration{
+		FileType: types.SSH,
+		FilePath: input.FilePath,
+	}
+	add := func(check types.PolicyMetadata, failures types.MisconfResults) {
+		if len(failures) > 0 {
+			misconf.Failures = append(misconf.Failures, failures...)
+			return
+		}
+		misconf.Successes = append(misconf.Successes, types.MisconfResult{
+			Namespace:      namespace,
+			PolicyMetadata: check,
+		})
+	}
+
+	switch kind {
+	case hostPrivateKey:
+		add(hostKeyCheck, types.MisconfResults{
+			failure(hostKeyCheck, fmt.Sprintf("SSH host %s private key is embedded", keys[0].typ), keys[0]),
+		})
+	case userPrivateKey:
+		add(userKeyCheck, types.MisconfResults{
+			failure(userKeyCheck, fmt.Sprintf("SSH %s private key is embedded", keys[0].typ), keys[0]),
+		})
+	case authorizedKeys:
+		var failures types.MisconfResults
+		if len(keys) > 0 {
+			failures = append(failures, failure(authorizedKeysCheck,
+				fmt.Sprintf("authorized_keys allows %d keys to log in", len(keys)),
+				key{startLine: keys[0].startLine, endLine: keys[len(keys)-1].endLine}))
+		}
+		add(authorizedKeysCheck, failures)
+	}
+
+	var weakKeys types.MisconfResults
+	for _, k := range keys {
+		if k.isWeak() {
+			weakKeys = append(weakKeys, failure(weakKeyCheck, fmt.Sprintf("SSH key uses %s", k.description()), k))
+		}
+	}
+	add(weakKeyCheck, weakKeys)
+
+	return &analyzer.AnalysisResult{
+		Misconfigurations: []types.Misconfiguration{misconf},
+	}, nil
+}
+
+func (a sshAnalyzer) Required(filePath string, info os.FileInfo) bool {
+	if info != nil && info.Size() > maxFileSize {
+		return false
+	}
+	return classify(filePath) != unknownFile
+}
+
+func (a sshAnalyzer) Type() analyzer.Type {
+	return analyzer.TypeSSH
+}
+
+func (a sshAnalyzer) Version() int {
+	return version
+}
+
+// classify returns the kind of the file from its path, e.g. "etc/ssh/ssh_host_ed25519_key" or "root/.ssh/authorized_keys"
+func classify(filePath string) fileKind {
+	filePath = filepath.ToSlash(filePath)
+	dir, name := path.Split(filePath)
+
+	switch {
+	case (dir == "etc/ssh/" || strings.HasSuffix(dir, "/etc/ssh/")) && strings.HasPrefix(name, "ssh_host_"):
+		switch {
+		case strings.HasSuffix(name, "_key"):
+			return hostPrivateKey
+		case strings.HasSuffix(name, "_key.pub"):
+			return hostPublicKey
+		}
+	case path.Base(dir) == ".ssh":
+		switch {
+		case name == "authorized_keys" || name == "authorized_keys2":
+			return authorizedKeys
+		case strings.HasPrefix(name, "id_") && strings.HasSuffix(name, ".pub"):
+			return userPublicKey
+		case strings.HasPrefix(name, "id_") && !strings.Contains(name, "."):
+			return userPrivateKey
+		}
+	}
+	return unknownFile
+}
+
+type key struct {
+	typ       string
+	size      int
+	startLine int
+	endLine   int
+}
+
+func newKey(pub ssh.PublicKey, startLine, endLine int) key {
+	k := key{
+		typ:       pub.Type(),
+		startLine: startLine,
+		endLine:   endLine,
+	}
+	if cryptoKey, ok := pub.(ssh.CryptoPublicKey); ok {
+		switch pk := cryptoKey.CryptoPublicKey().(type) {
+		case *rsa.PublicKey:
+			k.size = pk.N.BitLen()
+		case *ecdsa.PublicKey:
+			k.size = pk.Curve.Params().BitSize
+		case *dsa.PublicKey:
+			k.size = pk.P.BitLen()
+		}
+	}
+	return k
+}
+
+func (k key) isWeak() bool {
+	switch k.typ {
+	case ssh.KeyAlgoDSA:
+		return true
+	case ssh.KeyAlgoRSA:
+		return k.size < minRSAKeySize
+	}
+	return false
+}
+
+func (k key) description() string {
+	if k.size == 0 {
+		return fmt.Sprintf("a %s key", k.typ)
+	}
+	return fmt.Sprintf("a %d-bit %s key", k.size, k.typ)
+}
+
+func parsePrivateKey(content []byte) (key, error) {
+	lines := bytes.Count(bytes.TrimRight(content, "\n"), []byte("\n")) + 1
+
+	raw, err := ssh.ParseRawPrivateKey(content)
+	var passphraseErr *ssh.PassphraseMissingError
+	switch {
+	case errors.As(err, &passphraseErr) && passphraseErr.PublicKey != nil:
+		// OpenSSH keys store the public key unencrypted
+		return newKey(passphraseErr.PublicKey, 1, lines), nil
+	case err != nil:
+		return key{}, err
+	}
+
+	var pub crypto.PublicKey
+	switch k := raw.(type) {
+	case crypto.Signer:
+		pub = k.Public()
+	case *dsa.PrivateKey:
+		pub = &k.PublicKey
+	default:
+		return key{}, xerrors.Errorf("unsupported private key: %T", raw)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return key{}, err
+	}
+	return newKey(sshPub, 1, lines), nil
+}
+
+// parsePublicKeys parses public keys in the authorized_keys format, which is also used by .pub files
+func parsePublicKeys(content []byte) []key {
+	var keys []key
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		pub, _, _, _, err := ssh.ParseAuthorizedKey(line)
+		if err != nil {
+			log.Logger.Debugf("Unable to parse the SSH public key at line %d: %s", lineNumber, err)
+			continue
+		}
+		keys = append(keys, newKey(pub, lineNumber, lineNumber))
+	}
+	return keys
+}
+
+func failure(check types.PolicyMetadata, message string, k key) types.MisconfResult {
+	return types.MisconfResult{
+		Namespace:      namespace,
+		Message:        message,
+		PolicyMetadata: check,
+		CauseMetadata: types.CauseMetadata{
+			StartLine: k.startLine,
+			EndLine:   k.endLine,
+		},
+	}
+}
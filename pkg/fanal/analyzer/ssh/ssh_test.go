@@ -0,0 +1,177 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func authorizedKey(t *testing.T, key crypto.Signer) []byte {
+	t.Helper()
+	pub, err := ssh.NewPublicKey(key.Public())
+	require.NoError(t, err)
+	return ssh.MarshalAuthorizedKey(pub)
+}
+
+func Test_sshAnalyzer_Analyze(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	ed25519PEM, err := ssh.MarshalPrivateKey(ed25519Key, "")
+	require.NoError(t, err)
+	encryptedPEM, err := ssh.MarshalPrivateKeyWithPassphrase(ed25519Key, "", []byte("passphrase"))
+	require.NoError(t, err)
+	weakPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(weakKey),
+	})
+
+	var authorizedKeys bytes.Buffer
+	authorizedKeys.WriteString("# deploy keys\n")
+	authorizedKeys.Write(authorizedKey(t, ed25519Key))
+	authorizedKeys.WriteString(`no-pty,command="/usr/bin/backup" `)
+	authorizedKeys.Write(authorizedKey(t, weakKey))
+
+	tests := []struct {
+		name         string
+		filePath     string
+		content      []byte
+		wantFailures []types.MisconfResult
+		wantPass     []string
+	}{
+		{
+			name:     "host private key",
+			filePath: "etc/ssh/ssh_host_ed25519_key",
+			content:  pem.EncodeToMemory(ed25519PEM),
+			wantFailures: []types.MisconfResult{
+				failure(hostKeyCheck, "SSH host ssh-ed25519 private key is embedded",
+					key{startLine: 1, endLine: bytes.Count(pem.EncodeToMemory(ed25519PEM), []byte("\n"))}),
+			},
+			wantPass: []string{"SSH002"},
+		},
+		{
+			name:     "weak host private key",
+			filePath: "etc/ssh/ssh_host_rsa_key",
+			content:  weakPEM,
+			wantFailures: []types.MisconfResult{
+				failure(hostKeyCheck, "SSH host ssh-rsa private key is embedded",
+					key{startLine: 1, endLine: bytes.Count(weakPEM, []byte("\n"))}),
+				failure(weakKeyCheck, "SSH key uses a 1024-bit ssh-rsa key",
+					key{startLine: 1, endLine: bytes.Count(weakPEM, []byte("\n"))}),
+			},
+		},
+		{
+			name:     "host public key",
+			filePath: "etc/ssh/ssh_host_ed25519_key.pub",
+			content:  authorizedKey(t, ed25519Key),
+			wantPass: []string{"SSH002"},
+		},
+		{
+			name:     "authorized_keys",
+			filePath: "root/.ssh/authorized_keys",
+			content:  authorizedKeys.Bytes(),
+			wantFailures: []types.MisconfResult{
+				failure(authorizedKeysCheck, "authorized_keys allows 2 keys to log in", key{startLine: 2, endLine: 3}),
+				failure(weakKeyCheck, "SSH key uses a 1024-bit ssh-rsa key", key{startLine: 3, endLine: 3}),
+			},
+		},
+		{
+			name:     "empty authorized_keys",
+			filePath: "home/app/.ssh/authorized_keys",
+			content:  []byte("# no keys\n"),
+			wantPass: []string{"SSH003", "SSH002"},
+		},
+		{
+			name:     "encrypted user private key",
+			filePath: "home/app/.ssh/id_ed25519",
+			content:  pem.EncodeToMemory(encryptedPEM),
+			wantFailures: []types.MisconfResult{
+				failure(userKeyCheck, "SSH ssh-ed25519 private key is embedded",
+					key{startLine: 1, endLine: bytes.Count(pem.EncodeToMemory(encryptedPEM), []byte("\n"))}),
+			},
+			wantPass: []string{"SSH002"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := sshAnalyzer{}
+			got, err := a.Analyze(context.Background(), analyzer.AnalysisInput{
+				FilePath: tt.filePath,
+				Content:  bytes.NewReader(tt.content),
+			})
+			require.NoError(t, err)
+			require.Len(t, got.Misconfigurations, 1)
+
+			misconf := got.Misconfigurations[0]
+			assert.Equal(t, types.SSH, misconf.FileType)
+			assert.Equal(t, tt.filePath, misconf.FilePath)
+			assert.Equal(t, types.MisconfResults(tt.wantFailures), misconf.Failures)
+
+			var passed []string
+			for _, success := range misconf.Successes {
+				passed = append(passed, success.ID)
+			}
+			assert.Equal(t, tt.wantPass, passed)
+		})
+	}
+}
+
+func Test_sshAnalyzer_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		want     bool
+	}{
+		{
+			name:     "host private key",
+			filePath: "etc/ssh/ssh_host_ecdsa_key",
+			want:     true,
+		},
+		{
+			name:     "host public key",
+			filePath: "etc/ssh/ssh_host_ecdsa_key.pub",
+			want:     true,
+		},
+		{
+			name:     "root authorized_keys",
+			filePath: "root/.ssh/authorized_keys",
+			want:     true,
+		},
+		{
+			name:     "user private key",
+			filePath: "home/app/.ssh/id_rsa",
+			want:     true,
+		},
+		{
+			name:     "known_hosts",
+			filePath: "home/app/.ssh/known_hosts",
+			want:     false,
+		},
+		{
+			name:     "sshd_config",
+			filePath: "etc/ssh/sshd_config",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := sshAnalyzer{}
+			assert.Equal(t, tt.want, a.Required(tt.filePath, nil))
+		})
+	}
+}
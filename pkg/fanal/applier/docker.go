@@ -94,6 +94,7 @@ func ApplyLayers(layers []ftypes.BlobInfo) ftypes.ArtifactDetail {
 	sep := "/"
 	nestedMap := nested.Nested{}
 	secretsMap := make(map[string]ftypes.Secret)
+	aliases := make(map[string][]ftypes.PkgIdentifier)
 	var mergedLayer ftypes.ArtifactDetail
 
 	for _, layer := range layers {
@@ -115,6 +116,14 @@ func ApplyLayers(layers []ftypes.BlobInfo) ftypes.ArtifactDetail {
 		for _, pkgInfo := range layer.PackageInfos {
 			key := fmt.Sprintf("%s/type:ospkg", pkgInfo.FilePath)
 			nestedMap.SetByString(key, sep, pkgInfo)
+
+			// Aliases are detected only in the layer installing the package,
+			// so they have to be kept even if the package database is overwritten in later layers.
+			for _, pkg := range pkgInfo.Packages {
+				if len(pkg.Aliases) > 0 {
+					aliases[pkg.Name] = pkg.Aliases
+				}
+			}
 		}
 
 		// Apply language-specific packages
@@ -153,6 +162,12 @@ func ApplyLayers(layers []ftypes.BlobInfo) ftypes.ArtifactDetail {
 			nestedMap.SetByString(key, sep, license)
 		}
 
+		// Apply certificates
+		for _, inventory := range layer.Certificates {
+			key := fmt.Sprintf("%s/type:certificate", inventory.FilePath)
+			nestedMap.SetByString(key, sep, inventory)
+		}
+
 		// Apply custom resources
 		for _, customResource := range layer.CustomResources {
 			key := fmt.Sprintf("%s/custom:%s", customResource.FilePath, customResource.Type)
@@ -175,6 +190,8 @@ func ApplyLayers(layers []ftypes.BlobInfo) ftypes.ArtifactDetail {
 			mergedLayer.Misconfigurations = append(mergedLayer.Misconfigurations, v)
 		case ftypes.LicenseFile:
 			mergedLayer.Licenses = append(mergedLayer.Licenses, v)
+		case ftypes.CertificateInventory:
+			mergedLayer.Certificates = append(mergedLayer.Certificates, v)
 		case ftypes.CustomResource:
 			mergedLayer.CustomResources = append(mergedLayer.CustomResources, v)
 		}
@@ -225,6 +242,10 @@ func ApplyLayers(layers []ftypes.BlobInfo) ftypes.ArtifactDetail {
 		if licenses, ok := dpkgLicenses[pkg.Name]; ok {
 			mergedLayer.Packages[i].Licenses = licenses
 		}
+
+		if len(pkg.Aliases) == 0 {
+			mergedLayer.Packages[i].Aliases = aliases[pkg.Name]
+		}
 	}
 
 	for _, app := range mergedLayer.Applications {
@@ -329,6 +329,127 @@ func TestApplyLayers(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "happy path with aliases of OS packages",
+			inputLayers: []types.BlobInfo{
+				{
+					SchemaVersion: 1,
+					Digest:        "sha256:932da51564135c98a49a34a193d6cd363d8fa4184d957fde16c9d8527b3f3b02",
+					DiffID:        "sha256:a187dde48cd289ac374ad8539930628314bc581a481cdb41409c9289419ddb72",
+					OS: types.OS{
+						Family: "debian",
+						Name:   "11.8",
+					},
+					PackageInfos: []types.PackageInfo{
+						{
+							FilePath: "var/lib/dpkg/status",
+							Packages: types.Packages{
+								{
+									ID:      "python3-yaml@5.3.1-5",
+									Name:    "python3-yaml",
+									Version: "5.3.1-5",
+									Aliases: []types.PkgIdentifier{
+										{
+											PURL: &packageurl.PackageURL{
+												Type:    packageurl.TypePyPi,
+												Name:    "pyyaml",
+												Version: "5.3.1",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					SchemaVersion: 1,
+					Digest:        "sha256:24df0d4e20c0f42d3703bf1f1db2bdd77346c7956f74f423603d651e8e5ae8a7",
+					DiffID:        "sha256:aad63a9339440e7c3e1fff2b988991b9bfb81280042fa7f39a5e327023056819",
+					PackageInfos: []types.PackageInfo{
+						{
+							// The status file is overwritten in this layer
+							FilePath: "var/lib/dpkg/status",
+							Packages: types.Packages{
+								{
+									ID:      "curl@7.74.0-1.3",
+									Name:    "curl",
+									Version: "7.74.0-1.3",
+								},
+								{
+									ID:      "python3-yaml@5.3.1-5",
+									Name:    "python3-yaml",
+									Version: "5.3.1-5",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: types.ArtifactDetail{
+				OS: types.OS{
+					Family: "debian",
+					Name:   "11.8",
+				},
+				Packages: types.Packages{
+					{
+						ID:      "curl@7.74.0-1.3",
+						Name:    "curl",
+						Version: "7.74.0-1.3",
+						Identifier: types.PkgIdentifier{
+							PURL: &packageurl.PackageURL{
+								Type:      packageurl.TypeDebian,
+								Namespace: "debian",
+								Name:      "curl",
+								Version:   "7.74.0-1.3",
+								Qualifiers: packageurl.Qualifiers{
+									{
+										Key:   "distro",
+										Value: "debian-11.8",
+									},
+								},
+							},
+						},
+						Layer: types.Layer{
+							Digest: "sha256:24df0d4e20c0f42d3703bf1f1db2bdd77346c7956f74f423603d651e8e5ae8a7",
+							DiffID: "sha256:aad63a9339440e7c3e1fff2b988991b9bfb81280042fa7f39a5e327023056819",
+						},
+					},
+					{
+						ID:      "python3-yaml@5.3.1-5",
+						Name:    "python3-yaml",
+						Version: "5.3.1-5",
+						Identifier: types.PkgIdentifier{
+							PURL: &packageurl.PackageURL{
+								Type:      packageurl.TypeDebian,
+								Namespace: "debian",
+								Name:      "python3-yaml",
+								Version:   "5.3.1-5",
+								Qualifiers: packageurl.Qualifiers{
+									{
+										Key:   "distro",
+										Value: "debian-11.8",
+									},
+								},
+							},
+						},
+						Layer: types.Layer{
+							Digest: "sha256:932da51564135c98a49a34a193d6cd363d8fa4184d957fde16c9d8527b3f3b02",
+							DiffID: "sha256:a187dde48cd289ac374ad8539930628314bc581a481cdb41409c9289419ddb72",
+						},
+						Aliases: []types.PkgIdentifier{
+							{
+								PURL: &packageurl.PackageURL{
+									Type:    packageurl.TypePyPi,
+									Name:    "pyyaml",
+									Version: "5.3.1",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "happy path with merging ubuntu version and ESM",
 			inputLayers: []types.BlobInfo{
@@ -8,6 +8,7 @@ import (
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/fanal/walker"
 	"github.com/aquasecurity/trivy/pkg/misconf"
+	"github.com/aquasecurity/trivy/pkg/signature"
 )
 
 type Option struct {
@@ -33,9 +34,25 @@ type Option struct {
 	RepoCommit string
 	RepoTag    string
 
+	// ChangedFrom limits the analysis to the files changed since the git revision
+	ChangedFrom string
+
+	// IncrementalDir is the directory of the manifests to reuse the analysis results of unchanged files.
+	// Incremental analysis is enabled when it is set.
+	IncrementalDir string
+
+	// Budget stops the analysis gracefully when the time or the bytes are exceeded
+	Budget types.Budget
+
 	// For image scanning
 	ImageOption types.ImageOptions
 
+	// For VM scanning, the passphrases or the contents of the key files to unlock LUKS volumes
+	LUKSKeys [][]byte
+
+	// SignaturePolicy verifies the SBOM attestations of the "registry" SBOM source
+	SignaturePolicy signature.Policy
+
 	MisconfScannerOption misconf.ScannerOption
 	SecretScannerOption  analyzer.SecretScannerOption
 	LicenseScannerOption analyzer.LicenseScannerOption
@@ -0,0 +1,135 @@
+package artifact
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// BudgetTracker tracks the time and the bytes spent on analysis against the budget.
+// Once the budget is exceeded, no more files should be analyzed, while the analyzers already running finish.
+// All methods are safe for concurrent use and do nothing on a nil BudgetTracker, which means no budget.
+type BudgetTracker struct {
+	budget types.Budget
+
+	mu      sync.Mutex
+	start   time.Time
+	bytes   int64
+	reason  string
+	skipped map[string]struct{}
+
+	// incompleteBlobs are the blobs stored in the cache with partial analysis results
+	incompleteBlobs []string
+}
+
+// NewBudgetTracker returns the tracker of the budget, or nil if the budget has no limit
+func NewBudgetTracker(budget types.Budget) *BudgetTracker {
+	if budget.Time <= 0 && budget.Bytes <= 0 {
+		return nil
+	}
+	return &BudgetTracker{
+		budget:  budget,
+		skipped: make(map[string]struct{}),
+	}
+}
+
+// Start starts the clock of the time budget
+func (t *BudgetTracker) Start() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.start.IsZero() {
+		t.start = time.Now()
+	}
+}
+
+// Consume reports whether a file of the given size can be analyzed within the budget, and consumes the size if so
+func (t *BudgetTracker) Consume(size int64) bool {
+	if t == nil {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case t.reason != "":
+		return false
+	case t.budget.Time > 0 && !t.start.IsZero() && time.Since(t.start) > t.budget.Time:
+		t.reason = fmt.Sprintf("the time budget (%s) was exceeded", t.budget.Time)
+	case t.budget.Bytes > 0 && t.bytes+size > t.budget.Bytes:
+		t.reason = fmt.Sprintf("the size budget (%s) was exceeded", humanize.Bytes(uint64(t.budget.Bytes)))
+	default:
+		t.bytes += size
+		return true
+	}
+	log.Logger.Warnf("Stop analyzing more files as %s", t.reason)
+	return false
+}
+
+// Skip records the target which was not fully analyzed due to the budget
+func (t *BudgetTracker) Skip(target string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.skipped[target] = struct{}{}
+}
+
+// Skipped reports whether the target was not fully analyzed due to the budget
+func (t *BudgetTracker) Skipped(target string) bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.skipped[target]
+	return ok
+}
+
+// AddIncompleteBlob records the blob stored in the cache with partial analysis results
+func (t *BudgetTracker) AddIncompleteBlob(blobID string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.incompleteBlobs = append(t.incompleteBlobs, blobID)
+}
+
+// IncompleteBlobs returns the blobs which must be removed from the cache after the scan
+func (t *BudgetTracker) IncompleteBlobs() []string {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.incompleteBlobs)
+}
+
+// Incomplete returns why the analysis is incomplete and the skipped targets, or nil if the budget was not exceeded
+func (t *BudgetTracker) Incomplete() *types.Incomplete {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.reason == "" {
+		return nil
+	}
+	skipped := maps.Keys(t.skipped)
+	slices.Sort(skipped)
+	return &types.Incomplete{
+		Reason:         t.reason,
+		SkippedTargets: skipped,
+	}
+}
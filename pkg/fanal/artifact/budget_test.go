@@ -0,0 +1,60 @@
+package artifact_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func TestBudgetTracker(t *testing.T) {
+	t.Run("no budget", func(t *testing.T) {
+		tracker := artifact.NewBudgetTracker(types.Budget{})
+		assert.Nil(t, tracker)
+
+		// A nil tracker never stops the analysis
+		tracker.Start()
+		assert.True(t, tracker.Consume(1<<40))
+		tracker.Skip("foo")
+		assert.False(t, tracker.Skipped("foo"))
+		assert.Nil(t, tracker.Incomplete())
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		tracker := artifact.NewBudgetTracker(types.Budget{Bytes: 100})
+		tracker.Start()
+		assert.True(t, tracker.Consume(60))
+		assert.Nil(t, tracker.Incomplete())
+
+		assert.False(t, tracker.Consume(60))
+		tracker.Skip("usr/lib/b.so")
+		// No more files are analyzed even if they fit in the rest
+		assert.False(t, tracker.Consume(10))
+		tracker.Skip("usr/lib/a.so")
+
+		assert.True(t, tracker.Skipped("usr/lib/a.so"))
+		assert.Equal(t, &types.Incomplete{
+			Reason:         "the size budget (100 B) was exceeded",
+			SkippedTargets: []string{"usr/lib/a.so", "usr/lib/b.so"},
+		}, tracker.Incomplete())
+	})
+
+	t.Run("time", func(t *testing.T) {
+		tracker := artifact.NewBudgetTracker(types.Budget{Time: time.Millisecond})
+
+		// The clock doesn't start until Start is called
+		time.Sleep(2 * time.Millisecond)
+		assert.True(t, tracker.Consume(1))
+
+		tracker.Start()
+		time.Sleep(2 * time.Millisecond)
+		assert.False(t, tracker.Consume(1))
+
+		tracker.AddIncompleteBlob("sha256:a")
+		assert.Equal(t, []string{"sha256:a"}, tracker.IncompleteBlobs())
+		assert.Equal(t, "the time budget (1ms) was exceeded", tracker.Incomplete().Reason)
+	})
+}
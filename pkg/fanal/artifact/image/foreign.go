@@ -0,0 +1,129 @@
+package image
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/log"
+)
+
+// foreignLayer is the non-distributable layer, such as the base layers of Windows images.
+// Registries and OCI layouts may not have the blob, which is downloaded from the URLs in the descriptor instead.
+type foreignLayer struct {
+	ctx  context.Context
+	desc v1.Descriptor
+}
+
+// newForeignLayer returns the layer to be downloaded from the URLs if the layer is non-distributable.
+func (a Artifact) newForeignLayer(ctx context.Context, layer v1.Layer) (v1.Layer, bool) {
+	digest, err := layer.Digest()
+	if err != nil {
+		return nil, false
+	}
+	manifest, err := a.image.Manifest()
+	if err != nil {
+		return nil, false
+	}
+	for _, desc := range manifest.Layers {
+		if desc.Digest == digest && !desc.MediaType.IsDistributable() && len(desc.URLs) > 0 {
+			log.Logger.Debugf("Downloading the foreign layer: %s", digest)
+			foreign, err := partial.CompressedToLayer(foreignLayer{
+				ctx:  ctx,
+				desc: desc,
+			})
+			if err != nil {
+				return nil, false
+			}
+			return foreign, true
+		}
+	}
+	return nil, false
+}
+
+func (l foreignLayer) Digest() (v1.Hash, error) {
+	return l.desc.Digest, nil
+}
+
+func (l foreignLayer) Size() (int64, error) {
+	return l.desc.Size, nil
+}
+
+func (l foreignLayer) MediaType() (types.MediaType, error) {
+	return l.desc.MediaType, nil
+}
+
+// Compressed downloads the layer into a temp file, as the digest must be verified before the layer is analyzed.
+// The temp file is removed when it is closed.
+func (l foreignLayer) Compressed() (io.ReadCloser, error) {
+	var errs error
+	for _, u := range l.desc.URLs {
+		f, err := l.download(u)
+		if err != nil {
+			log.Logger.Debugf("Unable to download the foreign layer from %s: %s", u, err)
+			errs = xerrors.Errorf("%s: %w", u, err)
+			continue
+		}
+		return f, nil
+	}
+	return nil, xerrors.Errorf("foreign layer download error: %w", errs)
+}
+
+func (l foreignLayer) download(url string) (io.ReadCloser, error) {
+	if l.desc.Digest.Algorithm != "sha256" {
+		return nil, xerrors.Errorf("unsupported digest algorithm: %s", l.desc.Digest.Algorithm)
+	}
+
+	req, err := http.NewRequestWithContext(l.ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, xerrors.Errorf("request error: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	f, err := os.CreateTemp("", "trivy-foreign-layer-*")
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create a temp file: %w", err)
+	}
+	tmp := &tempFile{File: f}
+
+	h := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(f, h), resp.Body); err != nil {
+		_ = tmp.Close()
+		return nil, xerrors.Errorf("download error: %w", err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != l.desc.Digest.Hex {
+		_ = tmp.Close()
+		return nil, xerrors.Errorf("digest mismatch: got sha256:%s, want %s", got, l.desc.Digest)
+	}
+
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		_ = tmp.Close()
+		return nil, xerrors.Errorf("seek error: %w", err)
+	}
+	return tmp, nil
+}
+
+// tempFile removes the file on Close
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	_ = f.File.Close()
+	return os.Remove(f.Name())
+}
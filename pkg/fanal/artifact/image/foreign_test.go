@@ -0,0 +1,111 @@
+package image_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	ggcrtarball "github.com/google/go-containerregistry/pkg/v1/tarball"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
+	image2 "github.com/aquasecurity/trivy/pkg/fanal/artifact/image"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	"github.com/aquasecurity/trivy/pkg/fanal/image"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func TestArtifact_InspectWindowsImage(t *testing.T) {
+	// Windows layers have the filesystem under "Files/"
+	layerTar := tarball(t, map[string][]byte{
+		"Files/app/composer.lock": []byte(`{"packages": [{"name": "pear/log", "version": "1.13.1"}]}`),
+		"Hives/Software_Delta":    []byte("regf"),
+	})
+	layer, err := ggcrtarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(layerTar)), nil
+	}, ggcrtarball.WithMediaType(ggcrtypes.DockerForeignLayer))
+	require.NoError(t, err)
+	digest, err := layer.Digest()
+	require.NoError(t, err)
+	compressed, err := layer.Compressed()
+	require.NoError(t, err)
+	blob, err := io.ReadAll(compressed)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		blob    []byte
+		wantErr string
+	}{
+		{
+			name: "foreign layer",
+			blob: blob,
+		},
+		{
+			name:    "tampered foreign layer",
+			blob:    []byte("tampered"),
+			wantErr: "digest mismatch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The server distributing the foreign layer
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(tt.blob)
+			}))
+			defer ts.Close()
+
+			img, err := mutate.Append(empty.Image, mutate.Addendum{
+				Layer: layer,
+				URLs:  []string{ts.URL + "/layer"},
+			})
+			require.NoError(t, err)
+			cfg, err := img.ConfigFile()
+			require.NoError(t, err)
+			cfg.OS = "windows"
+			img, err = mutate.ConfigFile(img, cfg)
+			require.NoError(t, err)
+
+			// The OCI layout doesn't have the non-distributable layer
+			dir := t.TempDir()
+			lp, err := layout.Write(dir, empty.Index)
+			require.NoError(t, err)
+			require.NoError(t, lp.AppendImage(img))
+			require.NoError(t, os.Remove(filepath.Join(dir, "blobs", digest.Algorithm, digest.Hex)))
+
+			archive, err := image.NewArchiveImage(dir)
+			require.NoError(t, err)
+
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			a, err := image2.NewArtifact(archive, c, artifact.Option{})
+			require.NoError(t, err)
+
+			got, err := a.Inspect(context.Background())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, got.BlobIDs, 1)
+			blobInfo, err := c.GetBlob(got.BlobIDs[0])
+			require.NoError(t, err)
+			require.Len(t, blobInfo.Applications, 1)
+			assert.Equal(t, types.Composer, blobInfo.Applications[0].Type)
+			assert.Equal(t, "app/composer.lock", blobInfo.Applications[0].FilePath)
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
+	"github.com/aquasecurity/trivy/pkg/debugreport"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
 	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
 	"github.com/aquasecurity/trivy/pkg/fanal/cache"
@@ -33,6 +34,7 @@ type Artifact struct {
 	analyzer       analyzer.AnalyzerGroup       // analyzer for files in container image
 	configAnalyzer analyzer.ConfigAnalyzerGroup // analyzer for container image config
 	handlerManager handler.Manager
+	budget         *artifact.BudgetTracker
 
 	artifactOption artifact.Option
 }
@@ -62,16 +64,24 @@ func NewArtifact(img types.Image, c cache.ArtifactCache, opt artifact.Option) (a
 	return Artifact{
 		image:          img,
 		cache:          c,
-		walker:         walker.NewLayerTar(opt.SkipFiles, opt.SkipDirs),
+		walker:         walker.NewLayerTar(opt.SkipFiles, opt.SkipDirs).WithMemoryLimit(opt.ImageOption.LayerMemoryLimit),
 		analyzer:       a,
 		configAnalyzer: ca,
 		handlerManager: handlerManager,
+		budget:         artifact.NewBudgetTracker(opt.Budget),
 
 		artifactOption: opt,
 	}, nil
 }
 
 func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error) {
+	a.budget.Start()
+
+	// OCI artifacts such as Helm charts are not container images
+	if art, ok := a.image.(ociArtifact); ok && art.ArtifactType() != "" {
+		return a.inspectOCIArtifact(ctx, art.ArtifactType())
+	}
+
 	imageID, err := a.image.ID()
 	if err != nil {
 		return types.ArtifactReference{}, xerrors.Errorf("unable to get the image ID: %w", err)
@@ -84,6 +94,12 @@ func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error)
 
 	diffIDs := a.diffIDs(configFile)
 
+	// Windows layers have the filesystem under "Files/"
+	if configFile.OS == "windows" {
+		log.Logger.Debug("Detected Windows container image")
+		a.walker = a.walker.WithWindowsLayer()
+	}
+
 	// Debug
 	log.Logger.Debugf("Image ID: %s", imageID)
 	log.Logger.Debugf("Diff IDs: %v", diffIDs)
@@ -114,6 +130,10 @@ func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error)
 	if err != nil {
 		return types.ArtifactReference{}, xerrors.Errorf("unable to get missing layers: %w", err)
 	}
+	collector := debugreport.FromContext(ctx)
+	for i, diffID := range diffIDs {
+		collector.AddLayer(diffID, !slices.Contains(missingLayers, layerKeys[i]))
+	}
 
 	missingImageKey := imageKey
 	if missingImage {
@@ -138,10 +158,19 @@ func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error)
 			RepoDigests: a.image.RepoDigests(),
 			ConfigFile:  *configFile,
 		},
+		Incomplete: a.budget.Incomplete(),
 	}, nil
 }
 
-func (Artifact) Clean(_ types.ArtifactReference) error {
+// Clean removes the layers with partial analysis results from the cache, so that they are analyzed again next time.
+// The analysis results of OCI artifacts are always removed as they are analyzed like a filesystem.
+func (a Artifact) Clean(reference types.ArtifactReference) error {
+	if reference.Type == types.ArtifactOCIArtifact {
+		return a.cache.DeleteBlobs(reference.BlobIDs)
+	}
+	if blobIDs := a.budget.IncompleteBlobs(); len(blobIDs) > 0 {
+		return a.cache.DeleteBlobs(blobIDs)
+	}
 	return nil
 }
 
@@ -202,6 +231,7 @@ func (a Artifact) inspect(ctx context.Context, missingImage string, layerKeys, b
 	layerKeyMap map[string]LayerInfo, configFile *v1.ConfigFile) error {
 
 	var osFound types.OS
+	var osMu sync.Mutex
 	p := parallel.NewPipeline(a.artifactOption.Parallel, false, layerKeys, func(ctx context.Context, layerKey string) (any, error) {
 		layer := layerKeyMap[layerKey]
 
@@ -211,15 +241,31 @@ func (a Artifact) inspect(ctx context.Context, missingImage string, layerKeys, b
 			disabledAnalyzers = append(disabledAnalyzers, analyzer.TypeSecret)
 		}
 
-		layerInfo, err := a.inspectLayer(ctx, layer, disabledAnalyzers)
-		if err != nil {
-			return nil, xerrors.Errorf("failed to analyze layer (%s): %w", layer.DiffID, err)
+		var layerInfo types.BlobInfo
+		if a.budget.Consume(0) {
+			var err error
+			if layerInfo, err = a.inspectLayer(ctx, layer, disabledAnalyzers); err != nil {
+				return nil, xerrors.Errorf("failed to analyze layer (%s): %w", layer.DiffID, err)
+			}
+		} else {
+			// Don't even download the layer as the budget has been exceeded
+			a.budget.Skip(layer.DiffID)
+			layerInfo = types.BlobInfo{
+				SchemaVersion: types.BlobJSONSchemaVersion,
+				DiffID:        layer.DiffID,
+				CreatedBy:     layer.CreatedBy,
+			}
 		}
-		if err = a.cache.PutBlob(layerKey, layerInfo); err != nil {
+		if err := a.cache.PutBlob(layerKey, layerInfo); err != nil {
 			return nil, xerrors.Errorf("failed to store layer: %s in cache: %w", layerKey, err)
 		}
+		if a.budget.Skipped(layer.DiffID) {
+			a.budget.AddIncompleteBlob(layerKey)
+		}
 		if lo.IsNotEmpty(layerInfo.OS) {
+			osMu.Lock()
 			osFound = layerInfo.OS
+			osMu.Unlock()
 		}
 		return nil, nil
 
@@ -241,7 +287,7 @@ func (a Artifact) inspect(ctx context.Context, missingImage string, layerKeys, b
 func (a Artifact) inspectLayer(ctx context.Context, layerInfo LayerInfo, disabled []analyzer.Type) (types.BlobInfo, error) {
 	log.Logger.Debugf("Missing diff ID in cache: %s", layerInfo.DiffID)
 
-	layerDigest, rc, err := a.uncompressedLayer(layerInfo.DiffID)
+	layerDigest, rc, err := a.uncompressedLayer(ctx, layerInfo.DiffID)
 	if err != nil {
 		return types.BlobInfo{}, xerrors.Errorf("unable to get uncompressed layer %s: %w", layerInfo.DiffID, err)
 	}
@@ -263,8 +309,16 @@ func (a Artifact) inspectLayer(ctx context.Context, layerInfo LayerInfo, disable
 	}
 	defer composite.Cleanup()
 
+	collector := debugreport.FromContext(ctx)
+
 	// Walk a tar layer
-	opqDirs, whFiles, err := a.walker.Walk(rc, func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+	opqDirs, whFiles, err := a.walker.Walk(ctx, rc, func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+		if !a.budget.Consume(info.Size()) {
+			a.budget.Skip(layerInfo.DiffID)
+			collector.AddSkip(filePath, debugreport.SkipReasonBudget)
+			return nil
+		}
+
 		if err = a.analyzer.AnalyzeFile(ctx, &wg, limit, result, "", filePath, info, opener, disabled, opts); err != nil {
 			return xerrors.Errorf("failed to analyze %s: %w", filePath, err)
 		}
@@ -315,6 +369,7 @@ func (a Artifact) inspectLayer(ctx context.Context, layerInfo LayerInfo, disable
 		Misconfigurations: result.Misconfigurations,
 		Secrets:           result.Secrets,
 		Licenses:          result.Licenses,
+		Certificates:      result.Certificates,
 		CustomResources:   result.CustomResources,
 
 		// For Red Hat
@@ -338,7 +393,7 @@ func (a Artifact) diffIDs(configFile *v1.ConfigFile) []string {
 	})
 }
 
-func (a Artifact) uncompressedLayer(diffID string) (string, io.ReadCloser, error) {
+func (a Artifact) uncompressedLayer(ctx context.Context, diffID string) (string, io.ReadCloser, error) {
 	// diffID is a hash of the uncompressed layer
 	h, err := v1.NewHash(diffID)
 	if err != nil {
@@ -362,7 +417,14 @@ func (a Artifact) uncompressedLayer(diffID string) (string, io.ReadCloser, error
 
 	rc, err := layer.Uncompressed()
 	if err != nil {
-		return "", nil, xerrors.Errorf("failed to get the layer content (%s): %w", diffID, err)
+		// Non-distributable layers such as Windows base layers may be missing, e.g. in OCI layouts
+		foreign, ok := a.newForeignLayer(ctx, layer)
+		if !ok {
+			return "", nil, xerrors.Errorf("failed to get the layer content (%s): %w", diffID, err)
+		}
+		if rc, err = foreign.Uncompressed(); err != nil {
+			return "", nil, xerrors.Errorf("failed to get the foreign layer content (%s): %w", diffID, err)
+		}
 	}
 	return digest, rc, nil
 }
@@ -76,6 +76,11 @@ func TestArtifact_Inspect(t *testing.T) {
 				"var/spool/mail",
 				"var/spool/cron/crontabs",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "alpine-keys@2.1-r2",
@@ -106,6 +111,11 @@ func TestArtifact_Inspect(t *testing.T) {
 				"usr/share/apk/keys/x86_64/alpine-devel@lists.alpinelinux.org-5261cecb.rsa.pub",
 				"usr/share/apk/keys/x86_64/alpine-devel@lists.alpinelinux.org-4a6a0840.rsa.pub",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "apk-tools@2.10.4-r3",
@@ -125,6 +135,11 @@ func TestArtifact_Inspect(t *testing.T) {
 			InstalledFiles: []string{
 				"sbin/apk",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "busybox@1.31.1-r9",
@@ -147,6 +162,11 @@ func TestArtifact_Inspect(t *testing.T) {
 				"etc/network/if-up.d/dad",
 				"usr/share/udhcpc/default.script",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "ca-certificates-cacert@20191127-r1",
@@ -163,6 +183,11 @@ func TestArtifact_Inspect(t *testing.T) {
 			InstalledFiles: []string{
 				"etc/ssl/cert.pem",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "libc-utils@0.7.2-r0",
@@ -176,6 +201,11 @@ func TestArtifact_Inspect(t *testing.T) {
 				"musl-utils@1.1.24-r2",
 			},
 			Arch: "x86_64",
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "libcrypto1.1@1.1.1d-r3",
@@ -203,6 +233,11 @@ func TestArtifact_Inspect(t *testing.T) {
 				"usr/lib/engines-1.1/padlock.so",
 				"usr/lib/engines-1.1/afalg.so",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "libssl1.1@1.1.1d-r3",
@@ -221,6 +256,11 @@ func TestArtifact_Inspect(t *testing.T) {
 				"lib/libssl.so.1.1",
 				"usr/lib/libssl.so.1.1",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "libtls-standalone@2.9.1-r0",
@@ -241,6 +281,11 @@ func TestArtifact_Inspect(t *testing.T) {
 				"usr/lib/libtls-standalone.so.1.0.0",
 				"usr/lib/libtls-standalone.so.1",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "musl@1.1.24-r2",
@@ -255,6 +300,11 @@ func TestArtifact_Inspect(t *testing.T) {
 				"lib/libc.musl-x86_64.so.1",
 				"lib/ld-musl-x86_64.so.1",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "musl-utils@1.1.24-r2",
@@ -280,6 +330,11 @@ func TestArtifact_Inspect(t *testing.T) {
 				"usr/bin/getconf",
 				"usr/bin/getent",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "scanelf@1.2.4-r0",
@@ -296,6 +351,11 @@ func TestArtifact_Inspect(t *testing.T) {
 			InstalledFiles: []string{
 				"usr/bin/scanelf",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "ssl_client@1.31.1-r9",
@@ -313,6 +373,11 @@ func TestArtifact_Inspect(t *testing.T) {
 			InstalledFiles: []string{
 				"usr/bin/ssl_client",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 		{
 			ID:         "zlib@1.2.11-r3",
@@ -330,6 +395,11 @@ func TestArtifact_Inspect(t *testing.T) {
 				"lib/libz.so.1.2.11",
 				"lib/libz.so.1",
 			},
+			Provenance: &types.Provenance{
+				Analyzer: "apk",
+				Version:  2,
+				FilePath: "lib/apk/db/installed",
+			},
 		},
 	}
 
@@ -352,17 +422,17 @@ func TestArtifact_Inspect(t *testing.T) {
 			missingBlobsExpectation: cache.ArtifactCacheMissingBlobsExpectation{
 				Args: cache.ArtifactCacheMissingBlobsArgs{
 					ArtifactID: "sha256:c232b7d8ac8aa08aa767313d0b53084c4380d1c01a213a5971bdb039e6538313",
-					BlobIDs:    []string{"sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897"},
+					BlobIDs:    []string{"sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a"},
 				},
 				Returns: cache.ArtifactCacheMissingBlobsReturns{
 					MissingArtifact: true,
-					MissingBlobIDs:  []string{"sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897"},
+					MissingBlobIDs:  []string{"sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a"},
 				},
 			},
 			putBlobExpectations: []cache.ArtifactCachePutBlobExpectation{
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897",
+						BlobID: "sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
@@ -429,7 +499,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				Name:    "../../test/testdata/alpine-311.tar.gz",
 				Type:    types.ArtifactContainerImage,
 				ID:      "sha256:c232b7d8ac8aa08aa767313d0b53084c4380d1c01a213a5971bdb039e6538313",
-				BlobIDs: []string{"sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897"},
+				BlobIDs: []string{"sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a"},
 				ImageMetadata: types.ImageMetadata{
 					ID: "sha256:a187dde48cd289ac374ad8539930628314bc581a481cdb41409c9289419ddb72",
 					DiffIDs: []string{
@@ -488,25 +558,25 @@ func TestArtifact_Inspect(t *testing.T) {
 				Args: cache.ArtifactCacheMissingBlobsArgs{
 					ArtifactID: "sha256:33f9415ed2cd5a9cef5d5144333619745b9ec0f851f0684dd45fa79c6b26a650",
 					BlobIDs: []string{
-						"sha256:db2b5b2f26f320b80ee549495888f111151fdb44fecb2c432dfd928e96978e74",
-						"sha256:718d6a3d0a24966f2d9f64e0c86695d1bb896fe81df634b85300d34142c80174",
-						"sha256:9f6bc14198fa983fd47079bcd82df1391bee8e529a8a131a0305874eb0a51ca0",
-						"sha256:44264715b7132cd14cd956bf385d5f875603461f5adeafde7fdecabe2e266291",
+						"sha256:2e001fcea3725c3ed7f48db6cce98c29d3a933509b442e0741255eaa4af0863b",
+						"sha256:257b1558c769b3484d49b45f43f25cc7d7ca925b529b8b6b728d44831cb92167",
+						"sha256:f82e898fc437d95ae9f5cefe22407c72f26c2f94f998a7e3cbcbe16735b28ac0",
+						"sha256:da28568acb19ac847fd31221b47007f02f9bd1178a213d7bebab66d71a7d225d",
 					},
 				},
 				Returns: cache.ArtifactCacheMissingBlobsReturns{
 					MissingBlobIDs: []string{
-						"sha256:db2b5b2f26f320b80ee549495888f111151fdb44fecb2c432dfd928e96978e74",
-						"sha256:718d6a3d0a24966f2d9f64e0c86695d1bb896fe81df634b85300d34142c80174",
-						"sha256:9f6bc14198fa983fd47079bcd82df1391bee8e529a8a131a0305874eb0a51ca0",
-						"sha256:44264715b7132cd14cd956bf385d5f875603461f5adeafde7fdecabe2e266291",
+						"sha256:2e001fcea3725c3ed7f48db6cce98c29d3a933509b442e0741255eaa4af0863b",
+						"sha256:257b1558c769b3484d49b45f43f25cc7d7ca925b529b8b6b728d44831cb92167",
+						"sha256:f82e898fc437d95ae9f5cefe22407c72f26c2f94f998a7e3cbcbe16735b28ac0",
+						"sha256:da28568acb19ac847fd31221b47007f02f9bd1178a213d7bebab66d71a7d225d",
 					},
 				},
 			},
 			putBlobExpectations: []cache.ArtifactCachePutBlobExpectation{
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:db2b5b2f26f320b80ee549495888f111151fdb44fecb2c432dfd928e96978e74",
+						BlobID: "sha256:2e001fcea3725c3ed7f48db6cce98c29d3a933509b442e0741255eaa4af0863b",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
@@ -528,6 +598,11 @@ func TestArtifact_Inspect(t *testing.T) {
 											SrcVersion: "9.9+deb9u9",
 											Maintainer: "Santiago Vila <sanvila@debian.org>",
 											Arch:       "amd64",
+											Provenance: &types.Provenance{
+												Analyzer: "dpkg",
+												Version:  5,
+												FilePath: "var/lib/dpkg/status.d/base",
+											},
 										},
 									},
 								},
@@ -542,6 +617,11 @@ func TestArtifact_Inspect(t *testing.T) {
 											SrcVersion: "5.4",
 											Maintainer: "Marco d'Itri <md@linux.it>",
 											Arch:       "all",
+											Provenance: &types.Provenance{
+												Analyzer: "dpkg",
+												Version:  5,
+												FilePath: "var/lib/dpkg/status.d/netbase",
+											},
 										},
 									},
 								},
@@ -558,6 +638,11 @@ func TestArtifact_Inspect(t *testing.T) {
 											SrcRelease: "0+deb9u1",
 											Maintainer: "GNU Libc Maintainers <debian-glibc@lists.debian.org>",
 											Arch:       "all",
+											Provenance: &types.Provenance{
+												Analyzer: "dpkg",
+												Version:  5,
+												FilePath: "var/lib/dpkg/status.d/tzdata",
+											},
 										},
 									},
 								},
@@ -594,7 +679,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				},
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:718d6a3d0a24966f2d9f64e0c86695d1bb896fe81df634b85300d34142c80174",
+						BlobID: "sha256:257b1558c769b3484d49b45f43f25cc7d7ca925b529b8b6b728d44831cb92167",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
@@ -614,6 +699,11 @@ func TestArtifact_Inspect(t *testing.T) {
 											SrcRelease: "11+deb9u4",
 											Maintainer: "GNU Libc Maintainers <debian-glibc@lists.debian.org>",
 											Arch:       "amd64",
+											Provenance: &types.Provenance{
+												Analyzer: "dpkg",
+												Version:  5,
+												FilePath: "var/lib/dpkg/status.d/libc6",
+											},
 										},
 									},
 								},
@@ -630,6 +720,11 @@ func TestArtifact_Inspect(t *testing.T) {
 											SrcRelease: "1~deb9u1",
 											Maintainer: "Debian OpenSSL Team <pkg-openssl-devel@lists.alioth.debian.org>",
 											Arch:       "amd64",
+											Provenance: &types.Provenance{
+												Analyzer: "dpkg",
+												Version:  5,
+												FilePath: "var/lib/dpkg/status.d/libssl1",
+											},
 										},
 									},
 								},
@@ -646,6 +741,11 @@ func TestArtifact_Inspect(t *testing.T) {
 											SrcRelease: "1~deb9u1",
 											Maintainer: "Debian OpenSSL Team <pkg-openssl-devel@lists.alioth.debian.org>",
 											Arch:       "amd64",
+											Provenance: &types.Provenance{
+												Analyzer: "dpkg",
+												Version:  5,
+												FilePath: "var/lib/dpkg/status.d/openssl",
+											},
 										},
 									},
 								},
@@ -690,7 +790,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				},
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:9f6bc14198fa983fd47079bcd82df1391bee8e529a8a131a0305874eb0a51ca0",
+						BlobID: "sha256:f82e898fc437d95ae9f5cefe22407c72f26c2f94f998a7e3cbcbe16735b28ac0",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
@@ -716,6 +816,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   73,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "guzzlehttp/promises@v1.3.1",
@@ -728,6 +833,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   124,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "guzzlehttp/psr7@1.5.2",
@@ -744,6 +854,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   191,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "laravel/installer@v2.0.1",
@@ -762,6 +877,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   237,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:        "pear/log@1.13.1",
@@ -775,6 +895,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   290,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "pear/pear_exception@v1.0.0",
@@ -787,6 +912,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   345,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "psr/http-message@1.0.1",
@@ -799,6 +929,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   395,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "ralouphie/getallheaders@2.0.5",
@@ -811,6 +946,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   435,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "symfony/console@v4.2.7",
@@ -827,6 +967,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   507,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "symfony/contracts@v1.0.2",
@@ -839,6 +984,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   575,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:        "symfony/filesystem@v4.2.7",
@@ -852,6 +1002,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   625,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "symfony/polyfill-ctype@v1.11.0",
@@ -864,6 +1019,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   683,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "symfony/polyfill-mbstring@v1.11.0",
@@ -876,6 +1036,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   742,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 										{
 											ID:       "symfony/process@v4.2.7",
@@ -888,6 +1053,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   791,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "composer",
+												Version:  1,
+												FilePath: "php-app/composer.lock",
+											},
 										},
 									},
 								},
@@ -898,7 +1068,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				},
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:44264715b7132cd14cd956bf385d5f875603461f5adeafde7fdecabe2e266291",
+						BlobID: "sha256:da28568acb19ac847fd31221b47007f02f9bd1178a213d7bebab66d71a7d225d",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
@@ -925,6 +1095,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   4,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "actionmailer@5.2.3",
@@ -944,6 +1119,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   8,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "actionpack@5.2.3",
@@ -964,6 +1144,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   14,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "actionview@5.2.3",
@@ -983,6 +1168,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   21,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "activejob@5.2.3",
@@ -999,6 +1189,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   27,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "activemodel@5.2.3",
@@ -1012,6 +1207,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   30,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "activerecord@5.2.3",
@@ -1029,6 +1229,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   32,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "activestorage@5.2.3",
@@ -1046,6 +1251,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   36,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "activesupport@5.2.3",
@@ -1064,6 +1274,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   40,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "arel@9.0.0",
@@ -1077,6 +1292,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   45,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "ast@2.4.0",
@@ -1090,6 +1310,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   46,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "builder@3.2.3",
@@ -1103,6 +1328,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   47,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "coderay@1.1.2",
@@ -1116,6 +1346,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   48,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "concurrent-ruby@1.1.5",
@@ -1129,6 +1364,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   49,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "crass@1.0.4",
@@ -1142,6 +1382,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   50,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "dotenv@2.7.2",
@@ -1155,6 +1400,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   51,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "erubi@1.8.0",
@@ -1168,6 +1418,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   52,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "faker@1.9.3",
@@ -1181,6 +1436,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   53,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "globalid@0.4.2",
@@ -1194,6 +1454,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   55,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "i18n@1.6.0",
@@ -1207,6 +1472,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   57,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "jaro_winkler@1.5.2",
@@ -1220,6 +1490,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   59,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "json@2.2.0",
@@ -1233,6 +1508,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   60,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "loofah@2.2.3",
@@ -1249,6 +1529,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   61,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "mail@2.7.1",
@@ -1262,6 +1547,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   64,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "marcel@0.3.3",
@@ -1275,6 +1565,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   66,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "method_source@0.9.2",
@@ -1288,6 +1583,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   68,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "mimemagic@0.3.3",
@@ -1301,6 +1601,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   69,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "mini_mime@1.0.1",
@@ -1314,6 +1619,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   70,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "mini_portile2@2.4.0",
@@ -1327,6 +1637,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   71,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "minitest@5.11.3",
@@ -1340,6 +1655,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   72,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "nio4r@2.3.1",
@@ -1353,6 +1673,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   73,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "nokogiri@1.10.3",
@@ -1366,6 +1691,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   74,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "parallel@1.17.0",
@@ -1379,6 +1709,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   76,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "parser@2.6.3.0",
@@ -1392,6 +1727,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   77,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "pry@0.12.2",
@@ -1408,6 +1748,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   79,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "psych@3.1.0",
@@ -1421,6 +1766,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   82,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "rack@2.0.7",
@@ -1434,6 +1784,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   83,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "rack-test@1.1.0",
@@ -1447,6 +1802,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   84,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "rails@5.2.0",
@@ -1472,6 +1832,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   86,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "rails-dom-testing@2.0.3",
@@ -1488,6 +1853,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   99,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "rails-html-sanitizer@1.0.3",
@@ -1501,6 +1871,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   102,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "railties@5.2.3",
@@ -1520,6 +1895,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   104,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "rainbow@3.0.0",
@@ -1533,6 +1913,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   110,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "rake@12.3.2",
@@ -1546,6 +1931,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   111,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "rubocop@0.67.2",
@@ -1567,6 +1957,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   112,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "ruby-progressbar@1.10.0",
@@ -1580,6 +1975,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   120,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "sprockets@3.7.2",
@@ -1596,6 +1996,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   121,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:       "sprockets-rails@3.2.1",
@@ -1613,6 +2018,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   124,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "thor@0.20.3",
@@ -1626,6 +2036,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   128,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "thread_safe@0.3.6",
@@ -1639,6 +2054,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   129,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "tzinfo@1.2.5",
@@ -1652,6 +2072,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   130,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "unicode-display_width@1.5.0",
@@ -1665,6 +2090,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   132,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "websocket-driver@0.7.0",
@@ -1678,6 +2108,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   133,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 										{
 											ID:        "websocket-extensions@0.1.3",
@@ -1691,6 +2126,11 @@ func TestArtifact_Inspect(t *testing.T) {
 													EndLine:   135,
 												},
 											},
+											Provenance: &types.Provenance{
+												Analyzer: "bundler",
+												Version:  1,
+												FilePath: "ruby-app/Gemfile.lock",
+											},
 										},
 									},
 								},
@@ -1707,10 +2147,10 @@ func TestArtifact_Inspect(t *testing.T) {
 				Type: types.ArtifactContainerImage,
 				ID:   "sha256:33f9415ed2cd5a9cef5d5144333619745b9ec0f851f0684dd45fa79c6b26a650",
 				BlobIDs: []string{
-					"sha256:db2b5b2f26f320b80ee549495888f111151fdb44fecb2c432dfd928e96978e74",
-					"sha256:718d6a3d0a24966f2d9f64e0c86695d1bb896fe81df634b85300d34142c80174",
-					"sha256:9f6bc14198fa983fd47079bcd82df1391bee8e529a8a131a0305874eb0a51ca0",
-					"sha256:44264715b7132cd14cd956bf385d5f875603461f5adeafde7fdecabe2e266291",
+					"sha256:2e001fcea3725c3ed7f48db6cce98c29d3a933509b442e0741255eaa4af0863b",
+					"sha256:257b1558c769b3484d49b45f43f25cc7d7ca925b529b8b6b728d44831cb92167",
+					"sha256:f82e898fc437d95ae9f5cefe22407c72f26c2f94f998a7e3cbcbe16735b28ac0",
+					"sha256:da28568acb19ac847fd31221b47007f02f9bd1178a213d7bebab66d71a7d225d",
 				},
 				ImageMetadata: types.ImageMetadata{
 					ID: "sha256:58701fd185bda36cab0557bb6438661831267aa4a9e0b54211c4d5317a48aff4",
@@ -1804,25 +2244,25 @@ func TestArtifact_Inspect(t *testing.T) {
 				Args: cache.ArtifactCacheMissingBlobsArgs{
 					ArtifactID: "sha256:33f9415ed2cd5a9cef5d5144333619745b9ec0f851f0684dd45fa79c6b26a650",
 					BlobIDs: []string{
-						"sha256:dc2c102de2a4dc82bc017564a2c1a46646f071367bd51d3ddd80171dc5a78ffe",
-						"sha256:e6bc45e7459162f5a27dd7bd310a420f27378f2ea24c6ed5f0a083f2377bde24",
-						"sha256:8184bee7b1985e0abd72288946c246b90e1847b36fdcd50ca3d588b9371ec920",
-						"sha256:f1bb0ccf476528c6165a2f1a74d0e5a0cb06c9b254a0def6b1183db662e83dd4",
+						"sha256:42b75c3c717a755c93dc426dbbbce153f07eb39a4eacef86a62f9e51d7718857",
+						"sha256:30ba242a0da139bb690f3eaf6d5c8861578d129dd55ddfab5f362dd973a4fc94",
+						"sha256:93ced183c74cce7db7bb863c845b80a3601d81aabb10247fff6279abc2c4f205",
+						"sha256:7e7d102766114f6548f8c0df7d2e1df2f4c73054b906fe03fbc5f69ebdacf203",
 					},
 				},
 				Returns: cache.ArtifactCacheMissingBlobsReturns{
 					MissingBlobIDs: []string{
-						"sha256:dc2c102de2a4dc82bc017564a2c1a46646f071367bd51d3ddd80171dc5a78ffe",
-						"sha256:e6bc45e7459162f5a27dd7bd310a420f27378f2ea24c6ed5f0a083f2377bde24",
-						"sha256:8184bee7b1985e0abd72288946c246b90e1847b36fdcd50ca3d588b9371ec920",
-						"sha256:f1bb0ccf476528c6165a2f1a74d0e5a0cb06c9b254a0def6b1183db662e83dd4",
+						"sha256:42b75c3c717a755c93dc426dbbbce153f07eb39a4eacef86a62f9e51d7718857",
+						"sha256:30ba242a0da139bb690f3eaf6d5c8861578d129dd55ddfab5f362dd973a4fc94",
+						"sha256:93ced183c74cce7db7bb863c845b80a3601d81aabb10247fff6279abc2c4f205",
+						"sha256:7e7d102766114f6548f8c0df7d2e1df2f4c73054b906fe03fbc5f69ebdacf203",
 					},
 				},
 			},
 			putBlobExpectations: []cache.ArtifactCachePutBlobExpectation{
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:dc2c102de2a4dc82bc017564a2c1a46646f071367bd51d3ddd80171dc5a78ffe",
+						BlobID: "sha256:42b75c3c717a755c93dc426dbbbce153f07eb39a4eacef86a62f9e51d7718857",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
@@ -1833,7 +2273,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				},
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:e6bc45e7459162f5a27dd7bd310a420f27378f2ea24c6ed5f0a083f2377bde24",
+						BlobID: "sha256:30ba242a0da139bb690f3eaf6d5c8861578d129dd55ddfab5f362dd973a4fc94",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
@@ -1844,7 +2284,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				},
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:8184bee7b1985e0abd72288946c246b90e1847b36fdcd50ca3d588b9371ec920",
+						BlobID: "sha256:93ced183c74cce7db7bb863c845b80a3601d81aabb10247fff6279abc2c4f205",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
@@ -1856,7 +2296,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				},
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:f1bb0ccf476528c6165a2f1a74d0e5a0cb06c9b254a0def6b1183db662e83dd4",
+						BlobID: "sha256:7e7d102766114f6548f8c0df7d2e1df2f4c73054b906fe03fbc5f69ebdacf203",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
@@ -1872,10 +2312,10 @@ func TestArtifact_Inspect(t *testing.T) {
 				Type: types.ArtifactContainerImage,
 				ID:   "sha256:33f9415ed2cd5a9cef5d5144333619745b9ec0f851f0684dd45fa79c6b26a650",
 				BlobIDs: []string{
-					"sha256:dc2c102de2a4dc82bc017564a2c1a46646f071367bd51d3ddd80171dc5a78ffe",
-					"sha256:e6bc45e7459162f5a27dd7bd310a420f27378f2ea24c6ed5f0a083f2377bde24",
-					"sha256:8184bee7b1985e0abd72288946c246b90e1847b36fdcd50ca3d588b9371ec920",
-					"sha256:f1bb0ccf476528c6165a2f1a74d0e5a0cb06c9b254a0def6b1183db662e83dd4",
+					"sha256:42b75c3c717a755c93dc426dbbbce153f07eb39a4eacef86a62f9e51d7718857",
+					"sha256:30ba242a0da139bb690f3eaf6d5c8861578d129dd55ddfab5f362dd973a4fc94",
+					"sha256:93ced183c74cce7db7bb863c845b80a3601d81aabb10247fff6279abc2c4f205",
+					"sha256:7e7d102766114f6548f8c0df7d2e1df2f4c73054b906fe03fbc5f69ebdacf203",
 				},
 				ImageMetadata: types.ImageMetadata{
 					ID: "sha256:58701fd185bda36cab0557bb6438661831267aa4a9e0b54211c4d5317a48aff4",
@@ -1958,7 +2398,7 @@ func TestArtifact_Inspect(t *testing.T) {
 			missingBlobsExpectation: cache.ArtifactCacheMissingBlobsExpectation{
 				Args: cache.ArtifactCacheMissingBlobsArgs{
 					ArtifactID: "sha256:c232b7d8ac8aa08aa767313d0b53084c4380d1c01a213a5971bdb039e6538313",
-					BlobIDs:    []string{"sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897"},
+					BlobIDs:    []string{"sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a"},
 				},
 				Returns: cache.ArtifactCacheMissingBlobsReturns{
 					Err: xerrors.New("MissingBlobs failed"),
@@ -1972,16 +2412,16 @@ func TestArtifact_Inspect(t *testing.T) {
 			missingBlobsExpectation: cache.ArtifactCacheMissingBlobsExpectation{
 				Args: cache.ArtifactCacheMissingBlobsArgs{
 					ArtifactID: "sha256:c232b7d8ac8aa08aa767313d0b53084c4380d1c01a213a5971bdb039e6538313",
-					BlobIDs:    []string{"sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897"},
+					BlobIDs:    []string{"sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a"},
 				},
 				Returns: cache.ArtifactCacheMissingBlobsReturns{
-					MissingBlobIDs: []string{"sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897"},
+					MissingBlobIDs: []string{"sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a"},
 				},
 			},
 			putBlobExpectations: []cache.ArtifactCachePutBlobExpectation{
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897",
+						BlobID: "sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
@@ -2041,18 +2481,18 @@ func TestArtifact_Inspect(t *testing.T) {
 				Args: cache.ArtifactCacheMissingBlobsArgs{
 					ArtifactID: "sha256:33f9415ed2cd5a9cef5d5144333619745b9ec0f851f0684dd45fa79c6b26a650",
 					BlobIDs: []string{
-						"sha256:db2b5b2f26f320b80ee549495888f111151fdb44fecb2c432dfd928e96978e74",
-						"sha256:718d6a3d0a24966f2d9f64e0c86695d1bb896fe81df634b85300d34142c80174",
-						"sha256:9f6bc14198fa983fd47079bcd82df1391bee8e529a8a131a0305874eb0a51ca0",
-						"sha256:44264715b7132cd14cd956bf385d5f875603461f5adeafde7fdecabe2e266291",
+						"sha256:2e001fcea3725c3ed7f48db6cce98c29d3a933509b442e0741255eaa4af0863b",
+						"sha256:257b1558c769b3484d49b45f43f25cc7d7ca925b529b8b6b728d44831cb92167",
+						"sha256:f82e898fc437d95ae9f5cefe22407c72f26c2f94f998a7e3cbcbe16735b28ac0",
+						"sha256:da28568acb19ac847fd31221b47007f02f9bd1178a213d7bebab66d71a7d225d",
 					},
 				},
 				Returns: cache.ArtifactCacheMissingBlobsReturns{
 					MissingBlobIDs: []string{
-						"sha256:db2b5b2f26f320b80ee549495888f111151fdb44fecb2c432dfd928e96978e74",
-						"sha256:718d6a3d0a24966f2d9f64e0c86695d1bb896fe81df634b85300d34142c80174",
-						"sha256:9f6bc14198fa983fd47079bcd82df1391bee8e529a8a131a0305874eb0a51ca0",
-						"sha256:44264715b7132cd14cd956bf385d5f875603461f5adeafde7fdecabe2e266291",
+						"sha256:2e001fcea3725c3ed7f48db6cce98c29d3a933509b442e0741255eaa4af0863b",
+						"sha256:257b1558c769b3484d49b45f43f25cc7d7ca925b529b8b6b728d44831cb92167",
+						"sha256:f82e898fc437d95ae9f5cefe22407c72f26c2f94f998a7e3cbcbe16735b28ac0",
+						"sha256:da28568acb19ac847fd31221b47007f02f9bd1178a213d7bebab66d71a7d225d",
 					},
 				},
 			},
@@ -2060,7 +2500,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				{
 
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID:           "sha256:db2b5b2f26f320b80ee549495888f111151fdb44fecb2c432dfd928e96978e74",
+						BlobID:           "sha256:2e001fcea3725c3ed7f48db6cce98c29d3a933509b442e0741255eaa4af0863b",
 						BlobInfoAnything: true,
 					},
 
@@ -2071,7 +2511,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				{
 
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID:           "sha256:718d6a3d0a24966f2d9f64e0c86695d1bb896fe81df634b85300d34142c80174",
+						BlobID:           "sha256:257b1558c769b3484d49b45f43f25cc7d7ca925b529b8b6b728d44831cb92167",
 						BlobInfoAnything: true,
 					},
 
@@ -2082,7 +2522,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				{
 
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID:           "sha256:9f6bc14198fa983fd47079bcd82df1391bee8e529a8a131a0305874eb0a51ca0",
+						BlobID:           "sha256:f82e898fc437d95ae9f5cefe22407c72f26c2f94f998a7e3cbcbe16735b28ac0",
 						BlobInfoAnything: true,
 					},
 
@@ -2093,7 +2533,7 @@ func TestArtifact_Inspect(t *testing.T) {
 				{
 
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID:           "sha256:44264715b7132cd14cd956bf385d5f875603461f5adeafde7fdecabe2e266291",
+						BlobID:           "sha256:da28568acb19ac847fd31221b47007f02f9bd1178a213d7bebab66d71a7d225d",
 						BlobInfoAnything: true,
 					},
 
@@ -2110,17 +2550,17 @@ func TestArtifact_Inspect(t *testing.T) {
 			missingBlobsExpectation: cache.ArtifactCacheMissingBlobsExpectation{
 				Args: cache.ArtifactCacheMissingBlobsArgs{
 					ArtifactID: "sha256:c232b7d8ac8aa08aa767313d0b53084c4380d1c01a213a5971bdb039e6538313",
-					BlobIDs:    []string{"sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897"},
+					BlobIDs:    []string{"sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a"},
 				},
 				Returns: cache.ArtifactCacheMissingBlobsReturns{
 					MissingArtifact: true,
-					MissingBlobIDs:  []string{"sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897"},
+					MissingBlobIDs:  []string{"sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a"},
 				},
 			},
 			putBlobExpectations: []cache.ArtifactCachePutBlobExpectation{
 				{
 					Args: cache.ArtifactCachePutBlobArgs{
-						BlobID: "sha256:0366f81c3cbeac047536f9ca133b5632544c8342544922c3ed8a3e51c0250897",
+						BlobID: "sha256:e882d94b1f45c62b367e6c67328ccac6fb3fd89701c0d80e6d701e82e40e137a",
 						BlobInfo: types.BlobInfo{
 							SchemaVersion: types.BlobJSONSchemaVersion,
 							Digest:        "",
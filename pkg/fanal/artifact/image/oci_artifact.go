@@ -0,0 +1,204 @@
+package image
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact/local"
+	"github.com/aquasecurity/trivy/pkg/fanal/log"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/oci"
+)
+
+// Media types of OCI artifacts
+const (
+	helmChartMediaType      = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+	helmProvenanceMediaType = "application/vnd.cncf.helm.chart.provenance.v1.prov"
+	wasmLayerMediaType      = "application/vnd.wasm.content.layer.v1+wasm"
+)
+
+// Annotations of the layers
+const (
+	titleAnnotation = "org.opencontainers.image.title"
+
+	// ORAS pushes directories as tarballs with this annotation
+	orasUnpackAnnotation = "io.deis.oras.content.unpack"
+)
+
+// ociArtifact is implemented by the images in registries, which may be OCI artifacts rather than container images
+type ociArtifact interface {
+	ArtifactType() string
+}
+
+// inspectOCIArtifact stores the layers of the OCI artifact as files and analyzes them like a filesystem,
+// so that the analyzers are selected by the files, e.g. Helm charts for misconfigurations and WebAssembly modules for Rust crates.
+func (a Artifact) inspectOCIArtifact(ctx context.Context, artifactType string) (ftypes.ArtifactReference, error) {
+	log.Logger.Infof("Detected an OCI artifact: %s", artifactType)
+
+	tmpDir, err := os.MkdirTemp("", "trivy-oci-artifact-*")
+	if err != nil {
+		return ftypes.ArtifactReference{}, xerrors.Errorf("mkdir temp error: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest, err := a.image.Manifest()
+	if err != nil {
+		return ftypes.ArtifactReference{}, xerrors.Errorf("manifest error: %w", err)
+	}
+
+	// SBOM documents are scanned as SBOM
+	if slices.Contains(oci.SupportedSBOMArtifactTypes, artifactType) {
+		if len(manifest.Layers) != 1 {
+			return ftypes.ArtifactReference{}, xerrors.Errorf("SBOM artifact must be a single layer: %d layers", len(manifest.Layers))
+		}
+		filePath := filepath.Join(tmpDir, "artifact.sbom")
+		if err = a.saveOCILayer(manifest.Layers[0], filePath, false); err != nil {
+			return ftypes.ArtifactReference{}, xerrors.Errorf("layer error: %w", err)
+		}
+		return a.inspectSBOMFile(ctx, filePath)
+	}
+
+	for _, desc := range manifest.Layers {
+		filePath, extract := a.ociLayerPath(desc)
+		if filePath == "" {
+			log.Logger.Debugf("Skipping the layer %s (%s)", desc.Digest, desc.MediaType)
+			continue
+		}
+		if err = a.saveOCILayer(desc, filepath.Join(tmpDir, cleanPath(filePath)), extract); err != nil {
+			return ftypes.ArtifactReference{}, xerrors.Errorf("layer error (%s): %w", desc.Digest, err)
+		}
+	}
+
+	art, err := local.NewArtifact(tmpDir, a.cache, a.artifactOption)
+	if err != nil {
+		return ftypes.ArtifactReference{}, xerrors.Errorf("fs artifact: %w", err)
+	}
+	ref, err := art.Inspect(ctx)
+	if err != nil {
+		return ftypes.ArtifactReference{}, xerrors.Errorf("inspection error: %w", err)
+	}
+
+	ref.Name = a.image.Name()
+	ref.Type = ftypes.ArtifactOCIArtifact
+	ref.ImageMetadata = ftypes.ImageMetadata{
+		RepoTags:    a.image.RepoTags(),
+		RepoDigests: a.image.RepoDigests(),
+	}
+	return ref, nil
+}
+
+// ociLayerPath returns the relative path where the layer is stored and whether the layer is a tarball to be extracted.
+// It returns empty for the layers which are not scanned.
+func (a Artifact) ociLayerPath(desc v1.Descriptor) (string, bool) {
+	title := desc.Annotations[titleAnnotation]
+	switch {
+	case desc.MediaType == helmChartMediaType:
+		// Helm charts are kept as archives as the Helm analyzer supports them
+		return lo.Ternary(title != "", title, a.helmChartName()), false
+	case desc.MediaType == helmProvenanceMediaType:
+		return "", false
+	case desc.MediaType == wasmLayerMediaType:
+		return lo.Ternary(title != "", title, "module.wasm"), false
+	case desc.MediaType.IsLayer(), desc.Annotations[orasUnpackAnnotation] == "true":
+		return lo.Ternary(title != "", title, "."), true
+	}
+	// Files pushed by ORAS
+	return title, false
+}
+
+// helmChartName returns the file name of the chart in the same way as "helm package", e.g. "nginx-1.2.3.tgz"
+func (a Artifact) helmChartName() string {
+	var chart struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if b, err := a.image.RawConfigFile(); err == nil && json.Unmarshal(b, &chart) == nil && chart.Name != "" {
+		return fmt.Sprintf("%s-%s.tgz", chart.Name, chart.Version)
+	}
+	return "chart.tgz"
+}
+
+func (a Artifact) saveOCILayer(desc v1.Descriptor, filePath string, extract bool) error {
+	layer, err := a.image.LayerByDigest(desc.Digest)
+	if err != nil {
+		return xerrors.Errorf("unable to get the layer: %w", err)
+	}
+
+	if extract {
+		// The layer is decompressed according to the magic number
+		rc, err := layer.Uncompressed()
+		if err != nil {
+			return xerrors.Errorf("failed to fetch the layer: %w", err)
+		}
+		defer rc.Close()
+		return untar(rc, filePath)
+	}
+
+	// The blob is the file itself
+	rc, err := layer.Compressed()
+	if err != nil {
+		return xerrors.Errorf("failed to fetch the layer: %w", err)
+	}
+	defer rc.Close()
+	return writeFile(rc, filePath, 0o600)
+}
+
+// untar extracts the regular files of the tarball into the directory.
+// Symbolic links are skipped, so that the files outside the artifact are not scanned.
+func untar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("tar error: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Keep the executable bit for executables such as Go binaries
+		perm := os.FileMode(0o600)
+		if hdr.Mode&0o111 != 0 {
+			perm = 0o700
+		}
+		if err = writeFile(tr, filepath.Join(dir, cleanPath(hdr.Name)), perm); err != nil {
+			return xerrors.Errorf("unable to extract %s: %w", hdr.Name, err)
+		}
+	}
+}
+
+func writeFile(r io.Reader, filePath string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err = io.Copy(f, r); err != nil {
+		return err
+	}
+	return nil
+}
+
+// cleanPath prevents path traversal, e.g. "../../etc/passwd"
+func cleanPath(name string) string {
+	return filepath.FromSlash(path.Clean("/" + strings.ReplaceAll(name, `\`, "/")))
+}
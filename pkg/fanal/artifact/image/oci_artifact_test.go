@@ -0,0 +1,213 @@
+package image_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
+	image2 "github.com/aquasecurity/trivy/pkg/fanal/artifact/image"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	"github.com/aquasecurity/trivy/pkg/fanal/image"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+type ociLayer struct {
+	content     []byte
+	mediaType   ggcrtypes.MediaType
+	annotations map[string]string
+}
+
+type rawManifest []byte
+
+func (m rawManifest) RawManifest() ([]byte, error) {
+	return m, nil
+}
+
+// pushArtifact pushes the OCI artifact as ORAS and Helm do
+func pushArtifact(t *testing.T, ref name.Reference, artifactType string, configMediaType ggcrtypes.MediaType, config []byte,
+	layers []ociLayer) {
+	upload := func(content []byte, mediaType ggcrtypes.MediaType) v1.Descriptor {
+		layer := static.NewLayer(content, mediaType)
+		require.NoError(t, remote.WriteLayer(ref.Context(), layer))
+		digest, err := layer.Digest()
+		require.NoError(t, err)
+		return v1.Descriptor{
+			MediaType: mediaType,
+			Size:      int64(len(content)),
+			Digest:    digest,
+		}
+	}
+
+	manifest := map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     ggcrtypes.OCIManifestSchema1,
+		"config":        upload(config, configMediaType),
+	}
+	if artifactType != "" {
+		manifest["artifactType"] = artifactType
+	}
+	var descs []v1.Descriptor
+	for _, l := range layers {
+		desc := upload(l.content, l.mediaType)
+		desc.Annotations = l.annotations
+		descs = append(descs, desc)
+	}
+	manifest["layers"] = descs
+
+	b, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, remote.Put(ref, rawManifest(b)))
+}
+
+func tarball(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for filePath, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     filePath,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestArtifact_InspectOCIArtifact(t *testing.T) {
+	ts := httptest.NewServer(registry.New())
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	// The analyzers registered in image_test.go are used, as registering others changes the cache keys.
+	// WebAssembly modules are tested in the test package with the Rust binary analyzer.
+	lockFile := []byte(`{"packages": [{"name": "pear/log", "version": "1.13.1"}, {"name": "pear/pear_exception", "version": "v1.0.0"}]}`)
+
+	tests := []struct {
+		name         string
+		imageName    string
+		push         func(t *testing.T, ref name.Reference)
+		wantType     types.ArtifactType
+		wantFilePath string
+	}{
+		{
+			name:      "ORAS file",
+			imageName: host + "/oras/lock:1.0",
+			push: func(t *testing.T, ref name.Reference) {
+				pushArtifact(t, ref, "application/vnd.example.lock", "application/vnd.oci.empty.v1+json", []byte(`{}`), []ociLayer{
+					{
+						content:   lockFile,
+						mediaType: "application/json",
+						annotations: map[string]string{
+							"org.opencontainers.image.title": "composer.lock",
+						},
+					},
+				})
+			},
+			wantType:     types.ArtifactOCIArtifact,
+			wantFilePath: "composer.lock",
+		},
+		{
+			name:      "ORAS directory",
+			imageName: host + "/oras/app:1.0",
+			push: func(t *testing.T, ref name.Reference) {
+				pushArtifact(t, ref, "application/vnd.example.app", "application/vnd.oci.empty.v1+json", []byte(`{}`), []ociLayer{
+					{
+						content: tarball(t, map[string][]byte{
+							"../../app/composer.lock": lockFile,
+						}),
+						mediaType: ggcrtypes.OCIUncompressedLayer,
+						annotations: map[string]string{
+							"org.opencontainers.image.title": "dist",
+							"io.deis.oras.content.unpack":    "true",
+						},
+					},
+					{
+						content:   []byte("# App"),
+						mediaType: "text/markdown",
+						annotations: map[string]string{
+							"org.opencontainers.image.title": "README.md",
+						},
+					},
+					{
+						// Layers without the title are skipped
+						content:   lockFile,
+						mediaType: "application/octet-stream",
+					},
+				})
+			},
+			wantType:     types.ArtifactOCIArtifact,
+			wantFilePath: "dist/app/composer.lock",
+		},
+		{
+			name:      "container image",
+			imageName: host + "/library/app:1.0",
+			push: func(t *testing.T, ref name.Reference) {
+				img, err := random.Image(64, 1)
+				require.NoError(t, err)
+				require.NoError(t, remote.Write(ref, img))
+			},
+			wantType: types.ArtifactContainerImage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := name.ParseReference(tt.imageName, name.Insecure)
+			require.NoError(t, err)
+			tt.push(t, ref)
+
+			opt := types.ImageOptions{
+				RegistryOptions: types.RegistryOptions{Insecure: true},
+				ImageSources:    types.ImageSources{types.RemoteImageSource},
+			}
+			img, cleanup, err := image.NewContainerImage(context.Background(), tt.imageName, opt)
+			require.NoError(t, err)
+			defer cleanup()
+
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			a, err := image2.NewArtifact(img, c, artifact.Option{ImageOption: opt})
+			require.NoError(t, err)
+
+			got, err := a.Inspect(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.imageName, got.Name)
+			assert.Equal(t, tt.wantType, got.Type)
+			if tt.wantFilePath == "" {
+				return
+			}
+			assert.Len(t, got.ImageMetadata.RepoDigests, 1)
+
+			require.Len(t, got.BlobIDs, 1)
+			blob, err := c.GetBlob(got.BlobIDs[0])
+			require.NoError(t, err)
+			require.Len(t, blob.Applications, 1)
+			assert.Equal(t, types.Composer, blob.Applications[0].Type)
+			assert.Equal(t, tt.wantFilePath, blob.Applications[0].FilePath)
+			assert.Len(t, blob.Applications[0].Libraries, 2)
+
+			require.NoError(t, a.Clean(got))
+			_, err = c.GetBlob(got.BlobIDs[0])
+			assert.Error(t, err)
+		})
+	}
+}
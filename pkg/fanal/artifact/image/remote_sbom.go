@@ -19,6 +19,7 @@ import (
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/oci"
 	"github.com/aquasecurity/trivy/pkg/remote"
+	"github.com/aquasecurity/trivy/pkg/signature"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -34,6 +35,8 @@ func (a Artifact) retrieveRemoteSBOM(ctx context.Context) (ftypes.ArtifactRefere
 			inspect = a.inspectOCIReferrerSBOM
 		case types.SBOMSourceRekor:
 			inspect = a.inspectRekorSBOMAttestation
+		case types.SBOMSourceRegistry:
+			inspect = a.inspectRegistrySBOMAttestation
 		default:
 			// Never reach here as the "--sbom-sources" values are validated beforehand
 			continue
@@ -133,6 +136,57 @@ func (a Artifact) inspectRekorSBOMAttestation(ctx context.Context) (ftypes.Artif
 		return ftypes.ArtifactReference{}, xerrors.Errorf("failed to retrieve SBOM attestation: %w", err)
 	}
 
+	res, err := a.inspectRawSBOM(ctx, raw)
+	if err != nil {
+		return res, xerrors.Errorf("SBOM error: %w", err)
+	}
+
+	// Found SBOM
+	log.Logger.Infof("Found SBOM (%s) in Rekor (%s)", res.Type, a.artifactOption.RekorURL)
+
+	return res, nil
+}
+
+// inspectRegistrySBOMAttestation uses the SBOM attestation attached to the image as an OCI referrer,
+// which is signed as the signature policy requires, instead of analyzing the layers.
+func (a Artifact) inspectRegistrySBOMAttestation(ctx context.Context) (ftypes.ArtifactReference, error) {
+	digest, err := repoDigest(a.image, a.artifactOption.Insecure)
+	if err != nil {
+		return ftypes.ArtifactReference{}, xerrors.Errorf("repo digest error: %w", err)
+	}
+
+	verifier, err := signature.NewCosignVerifier(a.artifactOption.SignaturePolicy)
+	if err != nil {
+		return ftypes.ArtifactReference{}, xerrors.Errorf("SBOM attestations must be verified with the signature key or the certificate identity: %w", err)
+	}
+	atts, err := verifier.ReferrerAttestations(ctx, digest, a.artifactOption.ImageOption.RegistryOptions)
+	if err != nil {
+		return ftypes.ArtifactReference{}, xerrors.Errorf("attestation error: %w", err)
+	}
+
+	for _, att := range atts {
+		raw, err := sbomatt.Predicate(att.Statement)
+		if errors.Is(err, sbomatt.ErrNoSBOMAttestation) {
+			continue
+		} else if err != nil {
+			log.Logger.Warnf("Invalid SBOM attestation: %s", err)
+			continue
+		}
+
+		res, err := a.inspectRawSBOM(ctx, raw)
+		if err != nil {
+			return res, xerrors.Errorf("SBOM error: %w", err)
+		}
+
+		// Found SBOM
+		log.Logger.Infof("Found the verified SBOM attestation (%s) in the OCI referrers", res.Type)
+		return res, nil
+	}
+	return ftypes.ArtifactReference{}, errNoSBOMFound
+}
+
+// inspectRawSBOM stores the SBOM document in a temporary file to be inspected
+func (a Artifact) inspectRawSBOM(ctx context.Context, raw []byte) (ftypes.ArtifactReference, error) {
 	f, err := os.CreateTemp("", "sbom-*")
 	if err != nil {
 		return ftypes.ArtifactReference{}, xerrors.Errorf("failed to create a temporary file: %w", err)
@@ -145,15 +199,7 @@ func (a Artifact) inspectRekorSBOMAttestation(ctx context.Context) (ftypes.Artif
 	if err = f.Close(); err != nil {
 		return ftypes.ArtifactReference{}, xerrors.Errorf("failed to close %s: %w", f.Name(), err)
 	}
-	res, err := a.inspectSBOMFile(ctx, f.Name())
-	if err != nil {
-		return res, xerrors.Errorf("SBOM error: %w", err)
-	}
-
-	// Found SBOM
-	log.Logger.Infof("Found SBOM (%s) in Rekor (%s)", res.Type, a.artifactOption.RekorURL)
-
-	return res, nil
+	return a.inspectSBOMFile(ctx, f.Name())
 }
 
 func (a Artifact) inspectSBOMFile(ctx context.Context, filePath string) (ftypes.ArtifactReference, error) {
@@ -2,15 +2,35 @@ package image_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"github.com/package-url/packageurl-go"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
 	fakei "github.com/google/go-containerregistry/pkg/v1/fake"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -20,6 +40,7 @@ import (
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/rekortest"
+	"github.com/aquasecurity/trivy/pkg/signature"
 )
 
 func TestMain(m *testing.M) {
@@ -46,6 +67,10 @@ func (f fakeImage) RepoDigests() []string {
 	return f.repoDigests
 }
 
+func (f fakeImage) RepoTags() []string {
+	return nil
+}
+
 func TestArtifact_InspectRekorAttestation(t *testing.T) {
 	type fields struct {
 		imageName   string
@@ -314,3 +339,148 @@ func TestArtifact_inspectOCIReferrerSBOM(t *testing.T) {
 		})
 	}
 }
+
+func TestArtifact_inspectRegistrySBOMAttestation(t *testing.T) {
+	ts := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "cosign.pub")
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	bom, err := os.ReadFile("testdata/cyclonedx.json")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		repo     string
+		signer   *ecdsa.PrivateKey
+		policy   signature.Policy
+		wantType types.ArtifactType
+		wantErr  string
+	}{
+		{
+			name:     "verified SBOM attestation",
+			repo:     "test/signed",
+			signer:   key,
+			policy:   signature.Policy{KeyPath: keyPath},
+			wantType: types.ArtifactCycloneDX,
+		},
+		{
+			name:     "signed with another key",
+			repo:     "test/forged",
+			signer:   otherKey,
+			policy:   signature.Policy{KeyPath: keyPath},
+			wantType: types.ArtifactContainerImage,
+		},
+		{
+			name:     "no attestation",
+			repo:     "test/unsigned",
+			policy:   signature.Policy{KeyPath: keyPath},
+			wantType: types.ArtifactContainerImage,
+		},
+		{
+			name:    "no signature policy",
+			repo:    "test/no-policy",
+			signer:  key,
+			wantErr: "SBOM attestations must be verified",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digest := pushImage(t, host+"/"+tt.repo)
+			if tt.signer != nil {
+				pushSBOMAttestation(t, digest, tt.signer, bom)
+			}
+
+			fi := &fakei.FakeImage{}
+			fi.ConfigFileReturns(&v1.ConfigFile{}, nil)
+			img := &fakeImage{
+				name:        host + "/" + tt.repo + ":latest",
+				repoDigests: []string{digest.String()},
+				FakeImage:   fi,
+			}
+
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			a, err := image2.NewArtifact(img, c, artifact.Option{
+				Insecure:        true,
+				SBOMSources:     []string{"registry"},
+				SignaturePolicy: tt.policy,
+				ImageOption: types.ImageOptions{
+					RegistryOptions: types.RegistryOptions{Insecure: true},
+				},
+			})
+			require.NoError(t, err)
+
+			got, err := a.Inspect(context.Background())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, got.Type)
+		})
+	}
+}
+
+func pushImage(t *testing.T, repo string) name.Digest {
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	ref, err := name.ParseReference(repo+":latest", name.Insecure)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+
+	d, err := img.Digest()
+	require.NoError(t, err)
+	return ref.Context().Digest(d.String())
+}
+
+// pushSBOMAttestation attaches the SBOM attestation as 'cosign attest --registry-referrers-mode oci-1-1' does
+func pushSBOMAttestation(t *testing.T, digest name.Digest, key *ecdsa.PrivateKey, bom []byte) {
+	statement, err := json.Marshal(map[string]any{
+		"_type":         in_toto.StatementInTotoV01,
+		"predicateType": in_toto.PredicateCycloneDX,
+		"subject": []in_toto.Subject{
+			{
+				Name:   digest.Context().Name(),
+				Digest: map[string]string{"sha256": strings.TrimPrefix(digest.DigestStr(), "sha256:")},
+			},
+		},
+		"predicate": json.RawMessage(bom),
+	})
+	require.NoError(t, err)
+
+	hash := sha256.Sum256(dsse.PAE(in_toto.PayloadType, statement))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	require.NoError(t, err)
+	envelope, err := json.Marshal(dsse.Envelope{
+		PayloadType: in_toto.PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(statement),
+		Signatures:  []dsse.Signature{{Sig: base64.StdEncoding.EncodeToString(sig)}},
+	})
+	require.NoError(t, err)
+
+	subject, err := remote.Get(digest)
+	require.NoError(t, err)
+
+	att, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer(envelope, signature.DSSEMediaType),
+	})
+	require.NoError(t, err)
+	att = mutate.MediaType(att, ggcrtypes.OCIManifestSchema1)
+	att = mutate.ConfigMediaType(att, signature.DSSEMediaType)
+	att = mutate.Subject(att, subject.Descriptor).(v1.Image)
+
+	attDigest, err := att.Digest()
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(digest.Context().Digest(attDigest.String()), att))
+}
@@ -0,0 +1,124 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
+	image2 "github.com/aquasecurity/trivy/pkg/fanal/artifact/image"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	"github.com/aquasecurity/trivy/pkg/fanal/image"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+
+	// Registering the analyzer changes the cache keys, so this test is kept apart from the other image tests
+	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/language/rust/binary"
+)
+
+type rawManifest []byte
+
+func (m rawManifest) RawManifest() ([]byte, error) {
+	return m, nil
+}
+
+// pushWasmModule pushes the WebAssembly module as wasm-to-oci does
+func pushWasmModule(t *testing.T, ref name.Reference, module []byte, annotations map[string]string) {
+	upload := func(content []byte, mediaType ggcrtypes.MediaType) v1.Descriptor {
+		layer := static.NewLayer(content, mediaType)
+		require.NoError(t, remote.WriteLayer(ref.Context(), layer))
+		digest, err := layer.Digest()
+		require.NoError(t, err)
+		return v1.Descriptor{
+			MediaType: mediaType,
+			Size:      int64(len(content)),
+			Digest:    digest,
+		}
+	}
+
+	layer := upload(module, "application/vnd.wasm.content.layer.v1+wasm")
+	layer.Annotations = annotations
+	b, err := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     ggcrtypes.OCIManifestSchema1,
+		"config":        upload([]byte(`{}`), "application/vnd.wasm.config.v0+json"),
+		"layers":        []v1.Descriptor{layer},
+	})
+	require.NoError(t, err)
+	require.NoError(t, remote.Put(ref, rawManifest(b)))
+}
+
+func TestArtifact_InspectWasmModule(t *testing.T) {
+	ts := httptest.NewServer(registry.New())
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	module, err := os.ReadFile("../../../analyzer/language/rust/binary/testdata/module.wasm")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		imageName    string
+		annotations  map[string]string
+		wantFilePath string
+	}{
+		{
+			name:         "without title",
+			imageName:    host + "/wasm/module:1.0",
+			wantFilePath: "module.wasm",
+		},
+		{
+			name:      "with title",
+			imageName: host + "/wasm/filter:1.0",
+			annotations: map[string]string{
+				"org.opencontainers.image.title": "filter.wasm",
+			},
+			wantFilePath: "filter.wasm",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := name.ParseReference(tt.imageName, name.Insecure)
+			require.NoError(t, err)
+			pushWasmModule(t, ref, module, tt.annotations)
+
+			opt := types.ImageOptions{
+				RegistryOptions: types.RegistryOptions{Insecure: true},
+				ImageSources:    types.ImageSources{types.RemoteImageSource},
+			}
+			img, cleanup, err := image.NewContainerImage(context.Background(), tt.imageName, opt)
+			require.NoError(t, err)
+			defer cleanup()
+
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer c.Close()
+
+			a, err := image2.NewArtifact(img, c, artifact.Option{ImageOption: opt})
+			require.NoError(t, err)
+
+			got, err := a.Inspect(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, types.ArtifactOCIArtifact, got.Type)
+
+			require.Len(t, got.BlobIDs, 1)
+			blob, err := c.GetBlob(got.BlobIDs[0])
+			require.NoError(t, err)
+			require.Len(t, blob.Applications, 1)
+			assert.Equal(t, types.RustBinary, blob.Applications[0].Type)
+			assert.Equal(t, tt.wantFilePath, blob.Applications[0].FilePath)
+			assert.Len(t, blob.Applications[0].Libraries, 2)
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package lambda
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/cloud/aws/config"
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact/local"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	functionDir = "function"
+	layersDir   = "layers"
+)
+
+// API is the subset of the Lambda API used to download functions
+type API interface {
+	GetFunction(ctx context.Context, params *lambda.GetFunctionInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error)
+	GetLayerVersionByArn(ctx context.Context, params *lambda.GetLayerVersionByArnInput, optFns ...func(*lambda.Options)) (*lambda.GetLayerVersionByArnOutput, error)
+}
+
+// component is the function code or one of the layers attached to the function.
+// Each component is stored as a blob, so that findings can be attributed to the layer they come from.
+type component struct {
+	dir   string // the directory where the package is extracted
+	layer types.Layer
+}
+
+// Artifact represents an AWS Lambda function with the layers attached to it
+type Artifact struct {
+	functionARN string
+	components  []component
+	cache       cache.ArtifactCache
+
+	artifactOption artifact.Option
+}
+
+func NewArtifact(ctx context.Context, target string, c cache.ArtifactCache, artifactOpt artifact.Option) (
+	artifact.Artifact, func(), error) {
+	region := artifactOpt.AWSRegion
+	if a, err := arn.Parse(target); err == nil && region == "" {
+		// The region can be omitted when the function is specified by ARN
+		region = a.Region
+	}
+
+	cfg, err := config.LoadDefaultAWSConfig(ctx, region, artifactOpt.AWSEndpoint)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	return newArtifact(ctx, target, lambda.NewFromConfig(cfg), http.DefaultClient, c, artifactOpt)
+}
+
+func newArtifact(ctx context.Context, target string, api API, httpClient *http.Client, c cache.ArtifactCache,
+	artifactOpt artifact.Option) (artifact.Artifact, func(), error) {
+	cleanup := func() {}
+
+	fn, err := api.GetFunction(ctx, &lambda.GetFunctionInput{FunctionName: aws.String(target)})
+	if err != nil {
+		return nil, cleanup, xerrors.Errorf("lambda.GetFunction: %w", err)
+	} else if fn.Code == nil || fn.Configuration == nil {
+		return nil, cleanup, xerrors.Errorf("%s not found", target)
+	}
+
+	if imageURI := aws.ToString(fn.Code.ImageUri); imageURI != "" {
+		return nil, cleanup, xerrors.Errorf("%s is deployed as a container image, use 'trivy image %s' instead",
+			target, imageURI)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "trivy-lambda")
+	if err != nil {
+		return nil, cleanup, xerrors.Errorf("failed to create a temp dir: %w", err)
+	}
+	cleanup = func() { _ = os.RemoveAll(tmpDir) }
+
+	functionARN := aws.ToString(fn.Configuration.FunctionArn)
+	log.Logger.Infof("Downloading the code of %s...", functionARN)
+	code := component{
+		dir: filepath.Join(tmpDir, "0"),
+		layer: types.Layer{
+			Digest:    codeDigest(aws.ToString(fn.Configuration.CodeSha256)),
+			CreatedBy: functionARN,
+		},
+	}
+	code.layer.DiffID = code.layer.Digest
+	if err = download(ctx, httpClient, aws.ToString(fn.Code.Location), filepath.Join(code.dir, functionDir)); err != nil {
+		return nil, cleanup, xerrors.Errorf("function code error: %w", err)
+	}
+	components := []component{code}
+
+	for i, l := range fn.Configuration.Layers {
+		layerARN := aws.ToString(l.Arn)
+		log.Logger.Infof("Downloading %s...", layerARN)
+		layer, err := api.GetLayerVersionByArn(ctx, &lambda.GetLayerVersionByArnInput{Arn: l.Arn})
+		if err != nil {
+			return nil, cleanup, xerrors.Errorf("lambda.GetLayerVersionByArn (%s): %w", layerARN, err)
+		} else if layer.Content == nil {
+			return nil, cleanup, xerrors.Errorf("%s has no content", layerARN)
+		}
+
+		comp := component{
+			dir: filepath.Join(tmpDir, fmt.Sprint(i+1)),
+			layer: types.Layer{
+				Digest:    codeDigest(aws.ToString(layer.Content.CodeSha256)),
+				CreatedBy: layerARN,
+			},
+		}
+		comp.layer.DiffID = comp.layer.Digest
+		if err = download(ctx, httpClient, aws.ToString(layer.Content.Location), filepath.Join(comp.dir, layerPath(layerARN))); err != nil {
+			return nil, cleanup, xerrors.Errorf("layer error (%s): %w", layerARN, err)
+		}
+		components = append(components, comp)
+	}
+
+	return Artifact{
+		functionARN:    functionARN,
+		components:     components,
+		cache:          c,
+		artifactOption: artifactOpt,
+	}, cleanup, nil
+}
+
+func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error) {
+	var blobIDs []string
+	for _, comp := range a.components {
+		art, err := local.NewArtifact(comp.dir, layerCache{ArtifactCache: a.cache, layer: comp.layer}, a.artifactOption)
+		if err != nil {
+			return types.ArtifactReference{}, xerrors.Errorf("fs artifact: %w", err)
+		}
+		ref, err := art.Inspect(ctx)
+		if err != nil {
+			return types.ArtifactReference{}, xerrors.Errorf("inspection error (%s): %w", comp.layer.CreatedBy, err)
+		}
+		blobIDs = append(blobIDs, ref.BlobIDs...)
+	}
+
+	// The blob IDs depend on the code of the function and its layers
+	h := sha256.New()
+	for _, blobID := range blobIDs {
+		h.Write([]byte(blobID))
+	}
+
+	return types.ArtifactReference{
+		Name:    a.functionARN,
+		Type:    types.ArtifactLambdaFunction,
+		ID:      "sha256:" + hex.EncodeToString(h.Sum(nil)), // pseudo artifact ID
+		BlobIDs: blobIDs,
+	}, nil
+}
+
+func (a Artifact) Clean(reference types.ArtifactReference) error {
+	return a.cache.DeleteBlobs(reference.BlobIDs)
+}
+
+// layerCache records the layer of the function in the analysis result of each component
+type layerCache struct {
+	cache.ArtifactCache
+	layer types.Layer
+}
+
+func (c layerCache) PutBlob(blobID string, blobInfo types.BlobInfo) error {
+	blobInfo.Digest = c.layer.Digest
+	blobInfo.DiffID = c.layer.DiffID
+	blobInfo.CreatedBy = c.layer.CreatedBy
+	return c.ArtifactCache.PutBlob(blobID, blobInfo)
+}
+
+// layerPath returns the path where the files of the layer are shown in the results,
+// e.g. "layers/my-layer/3" for "arn:aws:lambda:us-east-1:123456789012:layer:my-layer:3"
+func layerPath(layerARN string) string {
+	parts := strings.Split(layerARN, ":")
+	if len(parts) < 2 {
+		return path.Join(layersDir, layerARN)
+	}
+	return path.Join(layersDir, parts[len(parts)-2], parts[len(parts)-1])
+}
+
+// codeDigest converts the base64-encoded SHA-256 hash of a deployment package into a digest
+func codeDigest(codeSha256 string) string {
+	b, err := base64.StdEncoding.DecodeString(codeSha256)
+	if err != nil || len(b) != sha256.Size {
+		return ""
+	}
+	return "sha256:" + hex.EncodeToString(b)
+}
+
+// download fetches the deployment package from the pre-signed URL and extracts it into the directory
+func download(ctx context.Context, httpClient *http.Client, location, dir string) error {
+	if location == "" {
+		return xerrors.New("no download location")
+	}
+
+	f, err := os.CreateTemp("", "trivy-lambda-*.zip")
+	if err != nil {
+		return xerrors.Errorf("failed to create a temp file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, http.NoBody)
+	if err != nil {
+		return xerrors.Errorf("http request error: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("download error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("download error: unexpected status code %d", resp.StatusCode)
+	}
+
+	size, err := io.Copy(f, resp.Body)
+	if err != nil {
+		return xerrors.Errorf("download error: %w", err)
+	}
+
+	if err = unzip(f, size, dir); err != nil {
+		return xerrors.Errorf("unzip error: %w", err)
+	}
+	return nil
+}
+
+// unzip extracts the regular files of the archive into the directory.
+// Symbolic links are skipped, so that the files outside the package are not scanned.
+func unzip(r io.ReaderAt, size int64, dir string) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return xerrors.Errorf("zip error: %w", err)
+	}
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return xerrors.Errorf("mkdir error: %w", err)
+	}
+
+	for _, zf := range zr.File {
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+		// Prevent path traversal, e.g. "../../etc/passwd"
+		name := path.Clean("/" + strings.ReplaceAll(zf.Name, `\`, "/"))
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err = extractFile(zf, filePath); err != nil {
+			return xerrors.Errorf("unable to extract %s: %w", zf.Name, err)
+		}
+	}
+	return nil
+}
+
+func extractFile(zf *zip.File, filePath string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		return err
+	}
+
+	rc, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// Keep the executable bit for executables such as Go binaries
+	perm := os.FileMode(0o600)
+	if zf.Mode()&0o111 != 0 {
+		perm = 0o700
+	}
+
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err = io.Copy(f, rc); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package lambda
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+
+	_ "github.com/aquasecurity/trivy/pkg/fanal/analyzer/language/nodejs/pkg"
+)
+
+const (
+	functionARN = "arn:aws:lambda:us-east-1:123456789012:function:my-function"
+	layerARN    = "arn:aws:lambda:us-east-1:123456789012:layer:my-deps:3"
+)
+
+type mockAPI struct {
+	serverURL string
+}
+
+func (m mockAPI) GetFunction(_ context.Context, params *lambda.GetFunctionInput, _ ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	if aws.ToString(params.FunctionName) == "image-function" {
+		return &lambda.GetFunctionOutput{
+			Code: &lambdatypes.FunctionCodeLocation{
+				ImageUri:       aws.String("123456789012.dkr.ecr.us-east-1.amazonaws.com/app:latest"),
+				RepositoryType: aws.String("ECR"),
+			},
+			Configuration: &lambdatypes.FunctionConfiguration{},
+		}, nil
+	}
+	return &lambda.GetFunctionOutput{
+		Code: &lambdatypes.FunctionCodeLocation{
+			Location:       aws.String(m.serverURL + "/function.zip"),
+			RepositoryType: aws.String("S3"),
+		},
+		Configuration: &lambdatypes.FunctionConfiguration{
+			FunctionArn: aws.String(functionARN),
+			CodeSha256:  aws.String("47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="),
+			Layers: []lambdatypes.Layer{
+				{Arn: aws.String(layerARN)},
+			},
+		},
+	}, nil
+}
+
+func (m mockAPI) GetLayerVersionByArn(_ context.Context, params *lambda.GetLayerVersionByArnInput, _ ...func(*lambda.Options)) (*lambda.GetLayerVersionByArnOutput, error) {
+	return &lambda.GetLayerVersionByArnOutput{
+		LayerVersionArn: params.Arn,
+		Content: &lambdatypes.LayerVersionContentOutput{
+			Location:   aws.String(m.serverURL + "/layer.zip"),
+			CodeSha256: aws.String("ungWv48Bz+pBQUDeXa4iI7ADYaOWF3qctBD/YfIAFa0="),
+		},
+	}, nil
+}
+
+func newZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestArtifact_Inspect(t *testing.T) {
+	packages := map[string][]byte{
+		"/function.zip": newZip(t, map[string]string{
+			"index.js":                          "exports.handler = async () => {};",
+			"node_modules/lodash/package.json":  `{"name": "lodash", "version": "4.17.20", "license": "MIT"}`,
+			"../../node_modules/x/package.json": `{"name": "x", "version": "1.0.0"}`,
+		}),
+		"/layer.zip": newZip(t, map[string]string{
+			"nodejs/node_modules/axios/package.json": `{"name": "axios", "version": "0.21.0", "license": "MIT"}`,
+		}),
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, ok := packages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(b)
+	}))
+	defer ts.Close()
+
+	c, err := cache.NewFSCache(t.TempDir())
+	require.NoError(t, err)
+
+	opt := artifact.Option{Parallel: 1}
+	art, cleanup, err := newArtifact(context.Background(), "my-function", mockAPI{serverURL: ts.URL}, ts.Client(), c, opt)
+	require.NoError(t, err)
+	defer cleanup()
+
+	ref, err := art.Inspect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, functionARN, ref.Name)
+	assert.Equal(t, types.ArtifactLambdaFunction, ref.Type)
+	require.Len(t, ref.BlobIDs, 2)
+
+	type library struct {
+		FilePath string
+		Name     string
+		Version  string
+	}
+	tests := []struct {
+		layer types.Layer
+		want  []library
+	}{
+		{
+			layer: types.Layer{
+				Digest:    "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				DiffID:    "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				CreatedBy: functionARN,
+			},
+			// The entry outside the package is extracted into the package
+			want: []library{
+				{FilePath: "function/node_modules/lodash/package.json", Name: "lodash", Version: "4.17.20"},
+				{FilePath: "function/node_modules/x/package.json", Name: "x", Version: "1.0.0"},
+			},
+		},
+		{
+			layer: types.Layer{
+				Digest:    "sha256:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+				DiffID:    "sha256:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+				CreatedBy: layerARN,
+			},
+			want: []library{
+				{FilePath: "layers/my-deps/3/nodejs/node_modules/axios/package.json", Name: "axios", Version: "0.21.0"},
+			},
+		},
+	}
+	for i, tt := range tests {
+		blob, err := c.GetBlob(ref.BlobIDs[i])
+		require.NoError(t, err)
+		assert.Equal(t, tt.layer, types.Layer{
+			Digest:    blob.Digest,
+			DiffID:    blob.DiffID,
+			CreatedBy: blob.CreatedBy,
+		})
+
+		var got []library
+		for _, app := range blob.Applications {
+			for _, lib := range app.Libraries {
+				got = append(got, library{FilePath: lib.FilePath, Name: lib.Name, Version: lib.Version})
+			}
+		}
+		assert.ElementsMatch(t, tt.want, got)
+	}
+}
+
+func TestNewArtifact_ContainerImage(t *testing.T) {
+	_, cleanup, err := newArtifact(context.Background(), "image-function", mockAPI{}, http.DefaultClient, nil, artifact.Option{})
+	defer cleanup()
+	require.ErrorContains(t, err, "use 'trivy image 123456789012.dkr.ecr.us-east-1.amazonaws.com/app:latest' instead")
+}
@@ -0,0 +1,179 @@
+package local
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// relatedFiles are the files analyzed together, such as a manifest and its lock file.
+// When one of them is changed, the others in the same directory are analyzed as well,
+// e.g. package-lock.json requires package.json to identify direct dependencies.
+var relatedFiles = [][]string{
+	{"package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml"},
+	{"go.mod", "go.sum"},
+	{"Gemfile", "Gemfile.lock", "gems.rb", "gems.locked"},
+	{"Cargo.toml", "Cargo.lock"},
+	{"composer.json", "composer.lock"},
+	{"pyproject.toml", "poetry.lock"},
+	{"Pipfile", "Pipfile.lock"},
+	{"Package.swift", "Package.resolved"},
+	{"Podfile", "Podfile.lock"},
+	{"pubspec.yaml", "pubspec.lock"},
+	{"mix.exs", "mix.lock"},
+	{"conanfile.py", "conanfile.txt", "conan.lock"},
+	{"build.gradle", "build.gradle.kts", "gradle.lockfile"},
+}
+
+// changedFiles returns the files changed since the given revision, relative to the root path.
+// The changes are taken from the merge base of the revision and HEAD, so that changes made on the revision
+// after the branch was created are not included, as with "git diff <rev>...HEAD".
+// Uncommitted and untracked files are also included.
+func changedFiles(rootPath, rev string) (map[string]struct{}, error) {
+	repo, err := git.PlainOpenWithOptions(rootPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to open the git repository: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get HEAD: %w", err)
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get the HEAD commit: %w", err)
+	}
+	base, err := mergeBase(repo, rev, headCommit)
+	if err != nil {
+		return nil, xerrors.Errorf("merge base error: %w", err)
+	}
+
+	baseTree, err := base.Tree()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get the tree of %s: %w", rev, err)
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get the tree of HEAD: %w", err)
+	}
+	changes, err := object.DiffTree(baseTree, headTree)
+	if err != nil {
+		return nil, xerrors.Errorf("diff error: %w", err)
+	}
+
+	// Paths relative to the repository root
+	var paths []string
+	for _, change := range changes {
+		// Deleted files are not analyzed
+		if change.To.Name != "" {
+			paths = append(paths, change.To.Name)
+		}
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, xerrors.Errorf("worktree error: %w", err)
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, xerrors.Errorf("status error: %w", err)
+	}
+	for filePath, s := range status {
+		if s.Worktree == git.Deleted {
+			continue
+		}
+		if s.Staging != git.Unmodified || s.Worktree != git.Unmodified {
+			paths = append(paths, filePath)
+		}
+	}
+
+	prefix, err := relativeRoot(wt.Filesystem.Root(), rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]struct{})
+	for _, p := range paths {
+		if prefix != "" && !strings.HasPrefix(p, prefix+"/") {
+			continue
+		}
+		p = strings.TrimPrefix(p, prefix+"/")
+		files[p] = struct{}{}
+		for _, related := range relatedTo(p) {
+			files[related] = struct{}{}
+		}
+	}
+	log.Logger.Infof("%d files changed since %s", len(files), rev)
+	return files, nil
+}
+
+func mergeBase(repo *git.Repository, rev string, headCommit *object.Commit) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to resolve %q: %w", rev, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get the commit of %q: %w", rev, err)
+	}
+
+	bases, err := commit.MergeBase(headCommit)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to find the merge base of %q and HEAD: %w", rev, err)
+	} else if len(bases) == 0 {
+		// Unrelated histories
+		return commit, nil
+	}
+	return bases[0], nil
+}
+
+// relativeRoot returns the root path relative to the repository root, e.g. "services/api".
+// It returns an empty string when the root path is the repository root.
+func relativeRoot(repoRoot, rootPath string) (string, error) {
+	absRoot, err := filepath.Abs(rootPath)
+	if err != nil {
+		return "", xerrors.Errorf("failed to get an absolute path of %s: %w", rootPath, err)
+	}
+	if r, err := filepath.EvalSymlinks(absRoot); err == nil {
+		absRoot = r
+	}
+	if r, err := filepath.EvalSymlinks(repoRoot); err == nil {
+		repoRoot = r
+	}
+	rel, err := filepath.Rel(repoRoot, absRoot)
+	if err != nil {
+		return "", xerrors.Errorf("failed to get a relative path from %s to %s: %w", repoRoot, rootPath, err)
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		return "", nil
+	}
+	return rel, nil
+}
+
+// relatedTo returns the files analyzed together with the given file
+func relatedTo(filePath string) []string {
+	dir, file := path.Split(filePath)
+	for _, files := range relatedFiles {
+		for _, f := range files {
+			if f != file {
+				continue
+			}
+			var related []string
+			for _, r := range files {
+				if r != file {
+					related = append(related, dir+r)
+				}
+			}
+			return related
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+func Test_changedFiles(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(files map[string]string) {
+		for name, content := range files {
+			writeFile(t, dir, name, content)
+			_, err := wt.Add(name)
+			require.NoError(t, err)
+		}
+		_, err := wt.Commit("commit", &git.CommitOptions{
+			Author: &object.Signature{
+				Name:  "test",
+				Email: "test@example.com",
+				When:  time.Now(),
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	commit(map[string]string{
+		"README.md":                    "# test",
+		"app/package.json":             "{}",
+		"app/package-lock.json":        "{}",
+		"api/go.mod":                   "module example.com/api",
+		"api/go.sum":                   "",
+		"deploy/main.tf":               "",
+		"deploy/modules/vpc/variables": "",
+	})
+	head, err := repo.Head()
+	require.NoError(t, err)
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/base", head.Hash())))
+
+	// Committed changes
+	commit(map[string]string{
+		"app/package.json": `{"name": "app"}`,
+		"deploy/main.tf":   `resource "aws_s3_bucket" "this" {}`,
+	})
+
+	// Uncommitted changes
+	writeFile(t, dir, "api/go.sum", "example.com/foo v1.0.0 h1:xxx")
+	writeFile(t, dir, "api/.env", "AWS_SECRET_ACCESS_KEY=xxx")
+
+	tests := []struct {
+		name     string
+		rootPath string
+		rev      string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "repository root",
+			rootPath: dir,
+			rev:      "base",
+			want: []string{
+				"api/.env",
+				"api/go.mod",
+				"api/go.sum",
+				"app/package-lock.json",
+				"app/package.json",
+				"app/pnpm-lock.yaml",
+				"app/yarn.lock",
+				"deploy/main.tf",
+			},
+		},
+		{
+			name:     "sub directory",
+			rootPath: filepath.Join(dir, "deploy"),
+			rev:      "base",
+			want: []string{
+				"main.tf",
+			},
+		},
+		{
+			name:     "no committed changes",
+			rootPath: dir,
+			rev:      "HEAD",
+			want: []string{
+				"api/.env",
+				"api/go.mod",
+				"api/go.sum",
+			},
+		},
+		{
+			name:     "unknown revision",
+			rootPath: dir,
+			rev:      "unknown",
+			wantErr:  `failed to resolve "unknown"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := changedFiles(tt.rootPath, tt.rev)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			files := maps.Keys(got)
+			slices.Sort(files)
+			assert.Equal(t, tt.want, files)
+		})
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
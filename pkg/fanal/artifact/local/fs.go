@@ -13,6 +13,7 @@ import (
 	"github.com/opencontainers/go-digest"
 	"golang.org/x/xerrors"
 
+	"github.com/aquasecurity/trivy/pkg/debugreport"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
 	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
 	"github.com/aquasecurity/trivy/pkg/fanal/cache"
@@ -30,6 +31,14 @@ type Artifact struct {
 	analyzer       analyzer.AnalyzerGroup
 	handlerManager handler.Manager
 
+	// changedFiles are the files to be analyzed with --changed-from. All the files are analyzed if nil.
+	changedFiles map[string]struct{}
+
+	budget *artifact.BudgetTracker
+
+	// incrementalKey is the key of the manifest of the previous scan with --incremental
+	incrementalKey string
+
 	artifactOption artifact.Option
 }
 
@@ -44,6 +53,24 @@ func NewArtifact(rootPath string, c cache.ArtifactCache, opt artifact.Option) (a
 		return nil, xerrors.Errorf("analyzer group error: %w", err)
 	}
 
+	var changed map[string]struct{}
+	if opt.ChangedFrom != "" {
+		if fi, err := os.Stat(rootPath); err != nil {
+			return nil, xerrors.Errorf("stat error: %w", err)
+		} else if !fi.IsDir() {
+			log.Logger.Warnf("'--changed-from' is ignored as %s is not a directory", rootPath)
+		} else if changed, err = changedFiles(rootPath, opt.ChangedFrom); err != nil {
+			return nil, xerrors.Errorf("failed to get changed files: %w", err)
+		}
+	}
+
+	var incrementalKey string
+	if opt.IncrementalDir != "" {
+		if incrementalKey, err = calcIncrementalKey(rootPath, a, opt); err != nil {
+			return nil, xerrors.Errorf("incremental key error: %w", err)
+		}
+	}
+
 	return Artifact{
 		rootPath: filepath.ToSlash(filepath.Clean(rootPath)),
 		cache:    c,
@@ -51,6 +78,9 @@ func NewArtifact(rootPath string, c cache.ArtifactCache, opt artifact.Option) (a
 			opt.Parallel, opt.WalkOption.ErrorCallback),
 		analyzer:       a,
 		handlerManager: handlerManager,
+		changedFiles:   changed,
+		budget:         artifact.NewBudgetTracker(opt.Budget),
+		incrementalKey: incrementalKey,
 
 		artifactOption: opt,
 	}, nil
@@ -126,27 +156,83 @@ func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error)
 		return types.ArtifactReference{}, xerrors.Errorf("failed to prepare filesystem for post analysis: %w", err)
 	}
 
-	err = a.walker.Walk(a.rootPath, func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+	var inc *incremental
+	if a.incrementalKey != "" {
+		inc = newIncremental(a.artifactOption.IncrementalDir, a.incrementalKey)
+	}
+
+	collector := debugreport.FromContext(ctx)
+	a.budget.Start()
+	err = a.walker.Walk(ctx, a.rootPath, func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
 		dir := a.rootPath
 
+		// The files in archives are analyzed every time, along with the archive
+		archivePath, inArchive := walker.ArchivePath(info)
+
+		if a.changedFiles != nil {
+			changedPath := filePath
+			if inArchive {
+				changedPath = archivePath
+			}
+			if _, ok := a.changedFiles[changedPath]; !ok {
+				collector.AddSkip(filePath, debugreport.SkipReasonUnchanged)
+				return nil
+			}
+		}
+
+		if !a.budget.Consume(info.Size()) {
+			a.budget.Skip(filePath)
+			collector.AddSkip(filePath, debugreport.SkipReasonBudget)
+			return nil
+		}
+
 		// When the directory is the same as the filePath, a file was given
 		// instead of a directory, rewrite the file path and directory in this case.
 		if filePath == "." {
 			dir, filePath = path.Split(a.rootPath)
+		} else if archivePath == "." {
+			dir = path.Dir(a.rootPath)
 		}
 
-		if err := a.analyzer.AnalyzeFile(ctx, &wg, limit, result, dir, filePath, info, opener, nil, opts); err != nil {
-			return xerrors.Errorf("analyze file (%s): %w", filePath, err)
+		if inc == nil || inArchive {
+			if err := a.analyzer.AnalyzeFile(ctx, &wg, limit, result, dir, filePath, info, opener, nil, opts); err != nil {
+				return xerrors.Errorf("analyze file (%s): %w", filePath, err)
+			}
 		}
 
 		// Skip post analysis if the file is not required
 		analyzerTypes := a.analyzer.RequiredPostAnalyzers(filePath, info)
+
+		if inc != nil && inArchive {
+			if len(analyzerTypes) > 0 {
+				inc.addPostInput(filePath, true)
+			}
+		} else if inc != nil {
+			changed, err := a.analyzeFileIncrementally(ctx, inc, &wg, limit, result, dir, filePath, info, opener,
+				len(analyzerTypes) > 0, opts)
+			if err != nil {
+				return xerrors.Errorf("analyze file (%s): %w", filePath, err)
+			}
+			if len(analyzerTypes) > 0 {
+				inc.addPostInput(filePath, changed)
+			}
+		}
+
 		if len(analyzerTypes) == 0 {
 			return nil
 		}
 
 		// Build filesystem for post analysis
-		if err := composite.CreateLink(analyzerTypes, dir, filePath, filepath.Join(dir, filePath)); err != nil {
+		realPath := filepath.Join(dir, filePath)
+		if inArchive {
+			// The file doesn't exist on the filesystem
+			tmpFilePath, err := composite.CopyFileToTemp(opener, info)
+			if err != nil {
+				return xerrors.Errorf("failed to copy file to temp: %w", err)
+			}
+			realPath = tmpFilePath
+		}
+		if err := composite.CreateLink(analyzerTypes, dir, filePath, realPath); err != nil {
 			return xerrors.Errorf("failed to create link: %w", err)
 		}
 
@@ -160,7 +246,11 @@ func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error)
 	wg.Wait()
 
 	// Post-analysis
-	if err = a.analyzer.PostAnalyze(ctx, composite, result, opts); err != nil {
+	if inc == nil {
+		if err = a.analyzer.PostAnalyze(ctx, composite, result, opts); err != nil {
+			return types.ArtifactReference{}, xerrors.Errorf("post analysis error: %w", err)
+		}
+	} else if err = a.postAnalyzeIncrementally(ctx, inc, composite, result, opts); err != nil {
 		return types.ArtifactReference{}, xerrors.Errorf("post analysis error: %w", err)
 	}
 
@@ -176,6 +266,7 @@ func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error)
 		Misconfigurations: result.Misconfigurations,
 		Secrets:           result.Secrets,
 		Licenses:          result.Licenses,
+		Certificates:      result.Certificates,
 		CustomResources:   result.CustomResources,
 	}
 
@@ -203,10 +294,11 @@ func (a Artifact) Inspect(ctx context.Context) (types.ArtifactReference, error)
 	}
 
 	return types.ArtifactReference{
-		Name:    hostName,
-		Type:    types.ArtifactFilesystem,
-		ID:      cacheKey, // use a cache key as pseudo artifact ID
-		BlobIDs: []string{cacheKey},
+		Name:       hostName,
+		Type:       types.ArtifactFilesystem,
+		ID:         cacheKey, // use a cache key as pseudo artifact ID
+		BlobIDs:    []string{cacheKey},
+		Incomplete: a.budget.Incomplete(),
 	}, nil
 }
 
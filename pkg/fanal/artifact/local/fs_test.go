@@ -1,12 +1,16 @@
 package local
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"errors"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/slices"
@@ -47,7 +51,7 @@ func TestArtifact_Inspect(t *testing.T) {
 			},
 			putBlobExpectation: cache.ArtifactCachePutBlobExpectation{
 				Args: cache.ArtifactCachePutBlobArgs{
-					BlobID: "sha256:ff28bff7756fb32d0a060b3b474b31a781a2d365dcd2789f47b4ae556a34947e",
+					BlobID: "sha256:d92561d31c721a2e84441815425ac01c75fea4b85fe401651502063f9f6ffe28",
 					BlobInfo: types.BlobInfo{
 						SchemaVersion: types.BlobJSONSchemaVersion,
 						OS: types.OS{
@@ -71,6 +75,11 @@ func TestArtifact_Inspect(t *testing.T) {
 											"lib/libc.musl-x86_64.so.1",
 											"lib/ld-musl-x86_64.so.1",
 										},
+										Provenance: &types.Provenance{
+											Analyzer: "apk",
+											Version:  2,
+											FilePath: "lib/apk/db/installed",
+										},
 									},
 								},
 							},
@@ -82,9 +91,9 @@ func TestArtifact_Inspect(t *testing.T) {
 			want: types.ArtifactReference{
 				Name: "host",
 				Type: types.ArtifactFilesystem,
-				ID:   "sha256:ff28bff7756fb32d0a060b3b474b31a781a2d365dcd2789f47b4ae556a34947e",
+				ID:   "sha256:d92561d31c721a2e84441815425ac01c75fea4b85fe401651502063f9f6ffe28",
 				BlobIDs: []string{
-					"sha256:ff28bff7756fb32d0a060b3b474b31a781a2d365dcd2789f47b4ae556a34947e",
+					"sha256:d92561d31c721a2e84441815425ac01c75fea4b85fe401651502063f9f6ffe28",
 				},
 			},
 		},
@@ -102,7 +111,7 @@ func TestArtifact_Inspect(t *testing.T) {
 			},
 			putBlobExpectation: cache.ArtifactCachePutBlobExpectation{
 				Args: cache.ArtifactCachePutBlobArgs{
-					BlobID: "sha256:d5fa75cdac006582a8f6bc4e3fcc8bfb70bd9d0403c24d8c2e3230d3f38a7ff5",
+					BlobID: "sha256:b5c0b26a85881480d2d39d631640bdd31ed14e89e203988400135f2da233489e",
 					BlobInfo: types.BlobInfo{
 						SchemaVersion: types.BlobJSONSchemaVersion,
 					},
@@ -112,9 +121,9 @@ func TestArtifact_Inspect(t *testing.T) {
 			want: types.ArtifactReference{
 				Name: "host",
 				Type: types.ArtifactFilesystem,
-				ID:   "sha256:d5fa75cdac006582a8f6bc4e3fcc8bfb70bd9d0403c24d8c2e3230d3f38a7ff5",
+				ID:   "sha256:b5c0b26a85881480d2d39d631640bdd31ed14e89e203988400135f2da233489e",
 				BlobIDs: []string{
-					"sha256:d5fa75cdac006582a8f6bc4e3fcc8bfb70bd9d0403c24d8c2e3230d3f38a7ff5",
+					"sha256:b5c0b26a85881480d2d39d631640bdd31ed14e89e203988400135f2da233489e",
 				},
 			},
 		},
@@ -125,7 +134,7 @@ func TestArtifact_Inspect(t *testing.T) {
 			},
 			putBlobExpectation: cache.ArtifactCachePutBlobExpectation{
 				Args: cache.ArtifactCachePutBlobArgs{
-					BlobID: "sha256:ff28bff7756fb32d0a060b3b474b31a781a2d365dcd2789f47b4ae556a34947e",
+					BlobID: "sha256:d92561d31c721a2e84441815425ac01c75fea4b85fe401651502063f9f6ffe28",
 					BlobInfo: types.BlobInfo{
 						SchemaVersion: types.BlobJSONSchemaVersion,
 						OS: types.OS{
@@ -149,6 +158,11 @@ func TestArtifact_Inspect(t *testing.T) {
 											"lib/libc.musl-x86_64.so.1",
 											"lib/ld-musl-x86_64.so.1",
 										},
+										Provenance: &types.Provenance{
+											Analyzer: "apk",
+											Version:  2,
+											FilePath: "lib/apk/db/installed",
+										},
 									},
 								},
 							},
@@ -175,7 +189,7 @@ func TestArtifact_Inspect(t *testing.T) {
 			},
 			putBlobExpectation: cache.ArtifactCachePutBlobExpectation{
 				Args: cache.ArtifactCachePutBlobArgs{
-					BlobID: "sha256:09aa251b64e824d0ec71a8c469619e57c9bd91d885f26e4a840de94209acbe4f",
+					BlobID: "sha256:f9efcd733a4fe289b71c22f0310893d14c8b3e83bd5758cc2520736d4d7effe3",
 					BlobInfo: types.BlobInfo{
 						SchemaVersion: types.BlobJSONSchemaVersion,
 						Applications: []types.Application{
@@ -186,6 +200,11 @@ func TestArtifact_Inspect(t *testing.T) {
 									{
 										Name:    "Flask",
 										Version: "2.0.0",
+										Provenance: &types.Provenance{
+											Analyzer: "pip",
+											Version:  1,
+											FilePath: "requirements.txt",
+										},
 									},
 								},
 							},
@@ -197,9 +216,9 @@ func TestArtifact_Inspect(t *testing.T) {
 			want: types.ArtifactReference{
 				Name: "testdata/requirements.txt",
 				Type: types.ArtifactFilesystem,
-				ID:   "sha256:09aa251b64e824d0ec71a8c469619e57c9bd91d885f26e4a840de94209acbe4f",
+				ID:   "sha256:f9efcd733a4fe289b71c22f0310893d14c8b3e83bd5758cc2520736d4d7effe3",
 				BlobIDs: []string{
-					"sha256:09aa251b64e824d0ec71a8c469619e57c9bd91d885f26e4a840de94209acbe4f",
+					"sha256:f9efcd733a4fe289b71c22f0310893d14c8b3e83bd5758cc2520736d4d7effe3",
 				},
 			},
 		},
@@ -210,7 +229,7 @@ func TestArtifact_Inspect(t *testing.T) {
 			},
 			putBlobExpectation: cache.ArtifactCachePutBlobExpectation{
 				Args: cache.ArtifactCachePutBlobArgs{
-					BlobID: "sha256:09aa251b64e824d0ec71a8c469619e57c9bd91d885f26e4a840de94209acbe4f",
+					BlobID: "sha256:f9efcd733a4fe289b71c22f0310893d14c8b3e83bd5758cc2520736d4d7effe3",
 					BlobInfo: types.BlobInfo{
 						SchemaVersion: types.BlobJSONSchemaVersion,
 						Applications: []types.Application{
@@ -221,6 +240,11 @@ func TestArtifact_Inspect(t *testing.T) {
 									{
 										Name:    "Flask",
 										Version: "2.0.0",
+										Provenance: &types.Provenance{
+											Analyzer: "pip",
+											Version:  1,
+											FilePath: "requirements.txt",
+										},
 									},
 								},
 							},
@@ -232,9 +256,9 @@ func TestArtifact_Inspect(t *testing.T) {
 			want: types.ArtifactReference{
 				Name: "testdata/requirements.txt",
 				Type: types.ArtifactFilesystem,
-				ID:   "sha256:09aa251b64e824d0ec71a8c469619e57c9bd91d885f26e4a840de94209acbe4f",
+				ID:   "sha256:f9efcd733a4fe289b71c22f0310893d14c8b3e83bd5758cc2520736d4d7effe3",
 				BlobIDs: []string{
-					"sha256:09aa251b64e824d0ec71a8c469619e57c9bd91d885f26e4a840de94209acbe4f",
+					"sha256:f9efcd733a4fe289b71c22f0310893d14c8b3e83bd5758cc2520736d4d7effe3",
 				},
 			},
 		},
@@ -260,6 +284,81 @@ func TestArtifact_Inspect(t *testing.T) {
 	}
 }
 
+func TestArtifact_Inspect_Budget(t *testing.T) {
+	c, err := cache.NewFSCache(t.TempDir())
+	require.NoError(t, err)
+	defer c.Close()
+
+	// etc/alpine-release and etc/hostname fit in the budget, but lib/apk/db/installed doesn't
+	a, err := NewArtifact("./testdata/alpine", c, artifact.Option{
+		Budget: types.Budget{Bytes: 12},
+	})
+	require.NoError(t, err)
+
+	got, err := a.Inspect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &types.Incomplete{
+		Reason:         "the size budget (12 B) was exceeded",
+		SkippedTargets: []string{"lib/apk/db/installed"},
+	}, got.Incomplete)
+
+	blobInfo, err := c.GetBlob(got.BlobIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, types.OS{Family: "alpine", Name: "3.11.6"}, blobInfo.OS)
+	assert.Empty(t, blobInfo.PackageInfos)
+}
+
+func TestArtifact_Inspect_Archive(t *testing.T) {
+	// The files in the archive don't exist on the filesystem, including the input of the post-analyzers
+	var buf bytes.Buffer
+	zw, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+	tw := tar.NewWriter(zw)
+	for name, content := range map[string]string{
+		"app/requirements.txt": "Flask==2.0.0\n",
+		"app/Dockerfile":       "FROM ubuntu",
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Size:     int64(len(content)),
+			Mode:     0o644,
+		}))
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, zw.Close())
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "drop.tar.zst"), buf.Bytes(), 0o644))
+
+	c, err := cache.NewFSCache(t.TempDir())
+	require.NoError(t, err)
+	defer c.Close()
+
+	a, err := NewArtifact(dir, c, artifact.Option{
+		MisconfScannerOption: misconf.ScannerOption{
+			RegoOnly:                 true,
+			Namespaces:               []string{"user"},
+			PolicyPaths:              []string{"./testdata/misconfig/dockerfile/single-failure/rego"},
+			DisableEmbeddedPolicies:  true,
+			DisableEmbeddedLibraries: true,
+		},
+	})
+	require.NoError(t, err)
+
+	got, err := a.Inspect(context.Background())
+	require.NoError(t, err)
+
+	blobInfo, err := c.GetBlob(got.BlobIDs[0])
+	require.NoError(t, err)
+	require.Len(t, blobInfo.Applications, 1)
+	assert.Equal(t, "drop.tar.zst/app/requirements.txt", blobInfo.Applications[0].FilePath)
+	require.Len(t, blobInfo.Misconfigurations, 1)
+	assert.Equal(t, "drop.tar.zst/app/Dockerfile", blobInfo.Misconfigurations[0].FilePath)
+}
+
 func TestBuildPathsToSkip(t *testing.T) {
 	tests := []struct {
 		name  string
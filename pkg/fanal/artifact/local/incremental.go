@@ -0,0 +1,356 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	"github.com/aquasecurity/trivy/pkg/log"
+	xio "github.com/aquasecurity/trivy/pkg/x/io"
+)
+
+const manifestVersion = 1
+
+// manifest records the analysis results of the previous scan for '--incremental'
+type manifest struct {
+	Version int
+	Files   map[string]fileEntry
+
+	// PostAnalysis is the result of the post-analyzers, which analyze multiple files together
+	PostAnalysis postAnalysisEntry
+}
+
+type fileEntry struct {
+	ModTime time.Time
+	Size    int64
+	Digest  digest.Digest
+	Result  *analyzer.AnalysisResult `json:",omitempty"`
+}
+
+type postAnalysisEntry struct {
+	Files   []string                 // The files passed to the post-analyzers
+	Skipped []string                 // The files skipped by the post-analyzers as they were already analyzed, e.g. packages in SBOM
+	Result  *analyzer.AnalysisResult `json:",omitempty"`
+}
+
+// incremental reuses the analysis results of the files unchanged since the previous scan.
+// A file is unchanged when the modification time and the size are the same, or the content hash is the same.
+type incremental struct {
+	path string
+
+	mu       sync.Mutex
+	previous manifest
+	current  manifest
+
+	// analyzed are the files analyzed in this scan, which are recorded when the analysis completes
+	analyzed []analyzedFile
+	reused   int
+
+	// postInputs are the files passed to the post-analyzers and whether they have been changed
+	postInputs map[string]bool
+}
+
+type analyzedFile struct {
+	filePath string
+	realPath string
+	info     os.FileInfo
+	result   *analyzer.AnalysisResult
+}
+
+// newIncremental loads the manifest of the previous scan. The manifest is keyed by the root path and the analyzer options.
+func newIncremental(dir, key string) *incremental {
+	inc := &incremental{
+		path: filepath.Join(dir, strings.TrimPrefix(key, "sha256:")+".json"),
+		current: manifest{
+			Version: manifestVersion,
+			Files:   make(map[string]fileEntry),
+		},
+		postInputs: make(map[string]bool),
+	}
+
+	b, err := os.ReadFile(inc.path)
+	if err != nil {
+		log.Logger.Debug("No manifest of the previous scan, all the files are analyzed")
+		return inc
+	}
+	if err = json.Unmarshal(b, &inc.previous); err != nil || inc.previous.Version != manifestVersion {
+		log.Logger.Debugf("Invalid manifest (%s), all the files are analyzed", inc.path)
+		inc.previous = manifest{}
+	}
+	return inc
+}
+
+// reuse returns the result of the previous scan if the file is unchanged
+func (inc *incremental) reuse(filePath, realPath string, info os.FileInfo) (*analyzer.AnalysisResult, bool) {
+	inc.mu.Lock()
+	entry, ok := inc.previous.Files[filePath]
+	inc.mu.Unlock()
+	if !ok || entry.Size != info.Size() {
+		return nil, false
+	}
+
+	if !entry.ModTime.Equal(info.ModTime()) {
+		// The file may have been touched without modification, e.g. by "git checkout"
+		d, err := fileDigest(realPath)
+		if err != nil || d != entry.Digest {
+			return nil, false
+		}
+		entry.ModTime = info.ModTime()
+	}
+
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	inc.current.Files[filePath] = entry
+	inc.reused++
+	return entry.Result, true
+}
+
+// addAnalyzed adds the file analyzed in this scan
+func (inc *incremental) addAnalyzed(filePath, realPath string, info os.FileInfo, result *analyzer.AnalysisResult) {
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	inc.analyzed = append(inc.analyzed, analyzedFile{
+		filePath: filePath,
+		realPath: realPath,
+		info:     info,
+		result:   result,
+	})
+}
+
+// flush merges the results of the analyzed files and records them. It must be called after the analysis completes.
+func (inc *incremental) flush(result *analyzer.AnalysisResult) error {
+	for _, f := range inc.analyzed {
+		result.Merge(f.result)
+		if err := inc.record(f.filePath, f.realPath, f.info, f.result); err != nil {
+			return xerrors.Errorf("failed to record %s: %w", f.filePath, err)
+		}
+	}
+	log.Logger.Infof("Incremental scan: %d files unchanged, %d files analyzed", inc.reused, len(inc.analyzed))
+	inc.analyzed = nil
+	return nil
+}
+
+// record records the result of the file analyzed in this scan
+func (inc *incremental) record(filePath, realPath string, info os.FileInfo, result *analyzer.AnalysisResult) error {
+	d, err := fileDigest(realPath)
+	if err != nil {
+		return err
+	}
+	if result.Sort(); result.IsEmpty() {
+		result = nil
+	}
+
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	inc.current.Files[filePath] = fileEntry{
+		ModTime: info.ModTime(),
+		Size:    info.Size(),
+		Digest:  d,
+		Result:  result,
+	}
+	return nil
+}
+
+// addPostInput adds the file passed to the post-analyzers
+func (inc *incremental) addPostInput(filePath string, changed bool) {
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	inc.postInputs[filePath] = changed
+}
+
+// reusePostAnalysis returns the result of the post-analyzers in the previous scan
+// if the same files are passed to them and none of them has been changed.
+func (inc *incremental) reusePostAnalysis(result *analyzer.AnalysisResult) (*analyzer.AnalysisResult, bool) {
+	files := maps.Keys(inc.postInputs)
+	slices.Sort(files)
+	skipped := skippedFiles(result, inc.postInputs)
+
+	prev := inc.previous.PostAnalysis
+	if inc.previous.Files == nil || !slices.Equal(files, prev.Files) || !slices.Equal(skipped, prev.Skipped) {
+		return nil, false
+	}
+	for _, changed := range inc.postInputs {
+		if changed {
+			return nil, false
+		}
+	}
+	inc.current.PostAnalysis = prev
+	return prev.Result, true
+}
+
+// recordPostAnalysis records the result of the post-analyzers in this scan
+func (inc *incremental) recordPostAnalysis(result, postResult *analyzer.AnalysisResult) {
+	files := maps.Keys(inc.postInputs)
+	slices.Sort(files)
+	if postResult.Sort(); postResult.IsEmpty() {
+		postResult = nil
+	}
+	inc.current.PostAnalysis = postAnalysisEntry{
+		Files:   files,
+		Skipped: skippedFiles(result, inc.postInputs),
+		Result:  postResult,
+	}
+}
+
+// save writes the manifest of this scan for the next scan
+func (inc *incremental) save() error {
+	if err := os.MkdirAll(filepath.Dir(inc.path), 0700); err != nil {
+		return xerrors.Errorf("failed to create the manifest dir: %w", err)
+	}
+	b, err := json.Marshal(inc.current)
+	if err != nil {
+		return xerrors.Errorf("json marshal error: %w", err)
+	}
+
+	// Write to a temp file and rename it so that concurrent scans never read a partial manifest
+	f, err := os.CreateTemp(filepath.Dir(inc.path), "manifest-*")
+	if err != nil {
+		return xerrors.Errorf("failed to create a temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.Write(b); err != nil {
+		_ = f.Close()
+		return xerrors.Errorf("failed to write the manifest: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return xerrors.Errorf("failed to close the manifest: %w", err)
+	}
+	return os.Rename(f.Name(), inc.path)
+}
+
+// analyzeFileIncrementally analyzes the file unless it is unchanged since the previous scan.
+// It reports whether the file has been changed.
+func (a Artifact) analyzeFileIncrementally(ctx context.Context, inc *incremental, wg *sync.WaitGroup, limit *semaphore.Weighted,
+	result *analyzer.AnalysisResult, dir, filePath string, info os.FileInfo, opener analyzer.Opener, postInput bool,
+	opts analyzer.AnalysisOptions) (bool, error) {
+	realPath := filepath.Join(dir, filePath)
+	if res, ok := inc.reuse(filePath, realPath, info); ok {
+		result.Merge(res)
+		return false, nil
+	}
+
+	// The result of each file is recorded separately
+	var opened bool
+	fileResult := analyzer.NewAnalysisResult()
+	fileOpener := func() (xio.ReadSeekCloserAt, error) {
+		opened = true
+		return opener()
+	}
+	if err := a.analyzer.AnalyzeFile(ctx, wg, limit, fileResult, dir, filePath, info, fileOpener, nil, opts); err != nil {
+		return true, err
+	}
+
+	// Files not required by any analyzer are not recorded as checking them again is cheap
+	if opened || postInput {
+		inc.addAnalyzed(filePath, realPath, info, fileResult)
+	}
+	return true, nil
+}
+
+// postAnalyzeIncrementally runs the post-analyzers unless their input files are unchanged since the previous scan,
+// and saves the manifest for the next scan.
+func (a Artifact) postAnalyzeIncrementally(ctx context.Context, inc *incremental, composite *analyzer.CompositeFS,
+	result *analyzer.AnalysisResult, opts analyzer.AnalysisOptions) error {
+	if err := inc.flush(result); err != nil {
+		return xerrors.Errorf("manifest error: %w", err)
+	}
+
+	if postResult, ok := inc.reusePostAnalysis(result); ok {
+		log.Logger.Debug("The input files of post-analyzers are unchanged, reusing the previous result")
+		result.Merge(postResult)
+	} else {
+		// The post-analyzers refer to the result of the analyzers to skip the files already analyzed,
+		// and their own result is appended to it.
+		postResult := &analyzer.AnalysisResult{
+			Applications:         slices.Clip(result.Applications),
+			SystemInstalledFiles: slices.Clip(result.SystemInstalledFiles),
+		}
+		numApps, numFiles := len(result.Applications), len(result.SystemInstalledFiles)
+		if err := a.analyzer.PostAnalyze(ctx, composite, postResult, opts); err != nil {
+			return err
+		}
+		postResult.Applications = postResult.Applications[numApps:]
+		postResult.SystemInstalledFiles = postResult.SystemInstalledFiles[numFiles:]
+
+		inc.recordPostAnalysis(result, postResult)
+		result.Merge(postResult)
+	}
+
+	if err := inc.save(); err != nil {
+		log.Logger.Warnf("Unable to save the manifest for the incremental scan: %s", err)
+	}
+	return nil
+}
+
+// calcIncrementalKey returns the key of the manifest.
+// The previous results are reused only when the same path is scanned with the same analyzers and options.
+func calcIncrementalKey(rootPath string, ag analyzer.AnalyzerGroup, opt artifact.Option) (string, error) {
+	absPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		return "", xerrors.Errorf("absolute path error: %w", err)
+	}
+
+	id, err := json.Marshal(struct {
+		Root         string
+		Offline      bool
+		FileChecksum bool
+		License      analyzer.LicenseScannerOption
+	}{absPath, opt.Offline, opt.FileChecksum, opt.LicenseScannerOption})
+	if err != nil {
+		return "", xerrors.Errorf("json marshal error: %w", err)
+	}
+
+	key, err := cache.CalcKey(string(id), ag.AnalyzerVersions(), nil, opt)
+	if err != nil {
+		return "", xerrors.Errorf("cache key: %w", err)
+	}
+	return key, nil
+}
+
+// skippedFiles returns the input files of the post-analyzers that they skip according to the result of the analyzers
+func skippedFiles(result *analyzer.AnalysisResult, inputs map[string]bool) []string {
+	var skipped []string
+	add := func(filePath string) {
+		if _, ok := inputs[filePath]; ok {
+			skipped = append(skipped, filePath)
+		}
+	}
+	for _, filePath := range result.SystemInstalledFiles {
+		add(filePath)
+	}
+	for _, app := range result.Applications {
+		add(app.FilePath)
+		for _, lib := range app.Libraries {
+			add(lib.FilePath)
+		}
+	}
+	slices.Sort(skipped)
+	return slices.Compact(skipped)
+}
+
+func fileDigest(filePath string) (digest.Digest, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	d, err := digest.SHA256.FromReader(f)
+	if err != nil {
+		return "", xerrors.Errorf("digest error: %w", err)
+	}
+	return d, nil
+}
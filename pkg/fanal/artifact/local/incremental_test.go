@@ -0,0 +1,153 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func TestArtifact_Inspect_Incremental(t *testing.T) {
+	dir := t.TempDir()
+	incDir := t.TempDir()
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	write := func(name, content string, modTime time.Time) {
+		writeFile(t, dir, name, content)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, name), modTime, modTime))
+	}
+
+	inspect := func() map[string]string {
+		c, err := cache.NewFSCache(t.TempDir())
+		require.NoError(t, err)
+		defer c.Close()
+
+		a, err := NewArtifact(dir, c, artifact.Option{IncrementalDir: incDir})
+		require.NoError(t, err)
+
+		got, err := a.Inspect(context.Background())
+		require.NoError(t, err)
+
+		blobInfo, err := c.GetBlob(got.BlobIDs[0])
+		require.NoError(t, err)
+
+		// file path => package version
+		versions := make(map[string]string)
+		for _, app := range blobInfo.Applications {
+			require.Len(t, app.Libraries, 1)
+			versions[app.FilePath] = app.Libraries[0].Version
+		}
+		return versions
+	}
+
+	// The first scan analyzes the file
+	write("requirements.txt", "flask==2.0.0\n", modTime)
+	assert.Equal(t, map[string]string{
+		"requirements.txt": "2.0.0",
+	}, inspect())
+
+	entries, err := os.ReadDir(incDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// The same modification time and size are trusted without reading the file
+	write("requirements.txt", "flask==2.0.1\n", modTime)
+	assert.Equal(t, map[string]string{
+		"requirements.txt": "2.0.0",
+	}, inspect())
+
+	// The file is analyzed again as the content hash differs
+	write("requirements.txt", "flask==2.0.1\n", modTime.Add(time.Hour))
+	assert.Equal(t, map[string]string{
+		"requirements.txt": "2.0.1",
+	}, inspect())
+
+	// The file is analyzed again as the size differs
+	write("requirements.txt", "flask==2.0.10\n", modTime.Add(time.Hour))
+	assert.Equal(t, map[string]string{
+		"requirements.txt": "2.0.10",
+	}, inspect())
+}
+
+func Test_incremental_reusePostAnalysis(t *testing.T) {
+	prevResult := &analyzer.AnalysisResult{
+		Applications: []types.Application{
+			{
+				Type:     types.Npm,
+				FilePath: "package-lock.json",
+			},
+		},
+	}
+	previous := manifest{
+		Version: manifestVersion,
+		Files:   map[string]fileEntry{},
+		PostAnalysis: postAnalysisEntry{
+			Files:  []string{"package-lock.json", "sbom.cdx.json"},
+			Result: prevResult,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		postInputs map[string]bool
+		result     *analyzer.AnalysisResult
+		want       bool
+	}{
+		{
+			name: "unchanged",
+			postInputs: map[string]bool{
+				"package-lock.json": false,
+				"sbom.cdx.json":     false,
+			},
+			result: &analyzer.AnalysisResult{},
+			want:   true,
+		},
+		{
+			name: "changed",
+			postInputs: map[string]bool{
+				"package-lock.json": true,
+				"sbom.cdx.json":     false,
+			},
+			result: &analyzer.AnalysisResult{},
+		},
+		{
+			name: "removed",
+			postInputs: map[string]bool{
+				"package-lock.json": false,
+			},
+			result: &analyzer.AnalysisResult{},
+		},
+		{
+			name: "newly skipped",
+			postInputs: map[string]bool{
+				"package-lock.json": false,
+				"sbom.cdx.json":     false,
+			},
+			result: &analyzer.AnalysisResult{
+				SystemInstalledFiles: []string{"package-lock.json"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inc := &incremental{
+				previous:   previous,
+				postInputs: tt.postInputs,
+			}
+			got, ok := inc.reusePostAnalysis(tt.result)
+			assert.Equal(t, tt.want, ok)
+			if tt.want {
+				assert.Equal(t, prevResult, got)
+			}
+		})
+	}
+}
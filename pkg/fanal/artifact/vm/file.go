@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"io/fs"
 	"os"
 
 	lru "github.com/hashicorp/golang-lru/v2"
@@ -30,6 +31,7 @@ type ImageFile struct {
 	filePath string
 	file     *os.File
 	reader   *io.SectionReader
+	clean    func() // closes the parent disks of snapshots
 }
 
 func newFile(filePath string, storage Storage) (*ImageFile, error) {
@@ -43,9 +45,10 @@ func newFile(filePath string, storage Storage) (*ImageFile, error) {
 		return nil, xerrors.Errorf("failed to create new lru cache: %w", err)
 	}
 
-	reader, err := disk.New(f, c)
+	reader, clean, err := disk.New(f, c)
 	if err != nil {
-		if errors.Is(err, vm.ErrUnsupportedType) {
+		// The parent disk of the snapshot must not be silently ignored
+		if errors.Is(err, vm.ErrUnsupportedType) || errors.Is(err, fs.ErrNotExist) {
 			return nil, err
 		}
 
@@ -56,6 +59,7 @@ func newFile(filePath string, storage Storage) (*ImageFile, error) {
 			return nil, xerrors.Errorf("file stat error: %w", err)
 		}
 		reader = io.NewSectionReader(f, 0, fi.Size())
+		clean = func() {}
 	}
 
 	return &ImageFile{
@@ -64,6 +68,7 @@ func newFile(filePath string, storage Storage) (*ImageFile, error) {
 		filePath: filePath,
 		file:     f,
 		reader:   reader,
+		clean:    clean,
 	}, nil
 }
 
@@ -107,6 +112,7 @@ func (a *ImageFile) calcCacheKey(blobInfo types.BlobInfo) (string, error) {
 }
 
 func (a *ImageFile) Clean(reference types.ArtifactReference) error {
+	a.clean()
 	_ = a.file.Close()
 	return a.cache.DeleteBlobs(reference.BlobIDs)
 }
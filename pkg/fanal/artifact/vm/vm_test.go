@@ -36,7 +36,7 @@ type mockWalker struct {
 	root string
 }
 
-func (m *mockWalker) Walk(_ *io.SectionReader, _ string, fn walker.WalkFunc) error {
+func (m *mockWalker) Walk(_ context.Context, _ *io.SectionReader, _ string, fn walker.WalkFunc) error {
 	return filepath.WalkDir(m.root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -121,7 +121,7 @@ func TestArtifact_Inspect(t *testing.T) {
 			rootDir: "testdata/alpine",
 			putBlobExpectation: cache.ArtifactCachePutBlobExpectation{
 				Args: cache.ArtifactCachePutBlobArgs{
-					BlobID:   "sha256:84a726d23c36d0e1857101969b257c1199de5432489d44581750d54ea8eff8cd",
+					BlobID:   "sha256:0163b25e14e43018ab4e1d9523c7ce293c7ce516f53dfc16bfd7c91f248ecfee",
 					BlobInfo: expectedBlobInfo,
 				},
 				Returns: cache.ArtifactCachePutBlobReturns{},
@@ -129,7 +129,7 @@ func TestArtifact_Inspect(t *testing.T) {
 			putArtifactExpectations: []cache.ArtifactCachePutArtifactExpectation{
 				{
 					Args: cache.ArtifactCachePutArtifactArgs{
-						ArtifactID: "sha256:84a726d23c36d0e1857101969b257c1199de5432489d44581750d54ea8eff8cd",
+						ArtifactID: "sha256:0163b25e14e43018ab4e1d9523c7ce293c7ce516f53dfc16bfd7c91f248ecfee",
 						ArtifactInfo: types.ArtifactInfo{
 							SchemaVersion: types.ArtifactJSONSchemaVersion,
 						},
@@ -139,9 +139,9 @@ func TestArtifact_Inspect(t *testing.T) {
 			want: types.ArtifactReference{
 				Name: "rawdata.img",
 				Type: types.ArtifactVM,
-				ID:   "sha256:84a726d23c36d0e1857101969b257c1199de5432489d44581750d54ea8eff8cd",
+				ID:   "sha256:0163b25e14e43018ab4e1d9523c7ce293c7ce516f53dfc16bfd7c91f248ecfee",
 				BlobIDs: []string{
-					"sha256:84a726d23c36d0e1857101969b257c1199de5432489d44581750d54ea8eff8cd",
+					"sha256:0163b25e14e43018ab4e1d9523c7ce293c7ce516f53dfc16bfd7c91f248ecfee",
 				},
 			},
 		},
@@ -245,6 +245,11 @@ var expectedBlobInfo = types.BlobInfo{
 						"lib/ld-musl-aarch64.so.1",
 						"lib/libc.musl-aarch64.so.1",
 					},
+					Provenance: &types.Provenance{
+						Analyzer: "apk",
+						Version:  2,
+						FilePath: "lib/apk/db/installed",
+					},
 				},
 			},
 		},
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/version"
+)
+
+var _ Cache = &AzureBlobCache{}
+
+const (
+	// azureStorageScope is the scope of the Azure AD token for Blob Storage in all the clouds
+	azureStorageScope = "https://storage.azure.com/.default"
+
+	// azureStorageVersion is the version of the Blob Storage REST API
+	azureStorageVersion = "2021-08-06"
+)
+
+// AzureBlobCache is the cache stored in a container of Azure Blob Storage.
+// The Blob Storage REST API is called through the azcore pipeline, as the Blob Storage SDK is not a dependency.
+type AzureBlobCache struct {
+	objectCache
+}
+
+// NewAzureBlobCache returns the cache stored in the container of the storage account endpoint,
+// e.g. https://myaccount.blob.core.windows.net. The requests are authorized with the Azure AD token of the credential.
+func NewAzureBlobCache(endpoint, container, prefix string, cred azcore.TokenCredential, opts *policy.ClientOptions) AzureBlobCache {
+	pl := runtime.NewPipeline("trivy", version.AppVersion(), runtime.PipelineOptions{
+		PerRetry: []policy.Policy{runtime.NewBearerTokenPolicy(cred, []string{azureStorageScope}, nil)},
+	}, opts)
+	return AzureBlobCache{
+		objectCache: objectCache{
+			store: azureBlobStore{
+				pipeline:  pl,
+				container: strings.TrimSuffix(endpoint, "/") + "/" + url.PathEscape(container),
+			},
+			prefix: prefix,
+		},
+	}
+}
+
+type azureBlobStore struct {
+	pipeline  runtime.Pipeline
+	container string // The URL of the container
+}
+
+func (s azureBlobStore) blobURL(key string) string {
+	var escaped []string
+	for _, p := range strings.Split(key, "/") {
+		escaped = append(escaped, url.PathEscape(p))
+	}
+	return s.container + "/" + strings.Join(escaped, "/")
+}
+
+func (s azureBlobStore) do(ctx context.Context, method, rawURL string, body []byte, statusCodes ...int) (*http.Response, error) {
+	req, err := runtime.NewRequest(ctx, method, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	req.Raw().Header.Set("x-ms-version", azureStorageVersion)
+	if body != nil {
+		req.Raw().Header.Set("x-ms-blob-type", "BlockBlob")
+		if err = req.SetBody(streaming.NopCloser(bytes.NewReader(body)), "application/json"); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := s.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	} else if !runtime.HasStatusCode(resp, statusCodes...) {
+		defer resp.Body.Close()
+		return nil, runtime.NewResponseError(resp)
+	}
+	return resp, nil
+}
+
+func (s azureBlobStore) get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.blobURL(key), nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (s azureBlobStore) put(ctx context.Context, key string, b []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, s.blobURL(key), b, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s azureBlobStore) delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.blobURL(key), nil, http.StatusAccepted, http.StatusNotFound)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// azureBlobList is the response of List Blobs
+type azureBlobList struct {
+	Blobs []struct {
+		Name string `xml:"Name"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+func (s azureBlobStore) list(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	var marker string
+	for {
+		query := url.Values{
+			"restype": []string{"container"},
+			"comp":    []string{"list"},
+			"prefix":  []string{prefix},
+		}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, err := s.do(ctx, http.MethodGet, s.container+"?"+query.Encode(), nil, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+
+		var list azureBlobList
+		err = xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, xerrors.Errorf("XML decode error: %w", err)
+		}
+		for _, blob := range list.Blobs {
+			keys = append(keys, blob.Name)
+		}
+		if list.NextMarker == "" {
+			return keys, nil
+		}
+		marker = list.NextMarker
+	}
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"golang.org/x/xerrors"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+var _ Cache = &GCSCache{}
+
+// GCSCache is the cache stored in a bucket of Google Cloud Storage
+type GCSCache struct {
+	objectCache
+	client *storage.Client
+}
+
+// NewGCSCache returns the cache stored in the bucket.
+// The client uses the application default credentials unless the options are given.
+func NewGCSCache(ctx context.Context, bucketName, prefix string, opts ...option.ClientOption) (GCSCache, error) {
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return GCSCache{}, xerrors.Errorf("unable to create a GCS client: %w", err)
+	}
+	return GCSCache{
+		objectCache: objectCache{
+			store:  gcsStore{bucket: client.Bucket(bucketName)},
+			prefix: prefix,
+		},
+		client: client,
+	}, nil
+}
+
+func (c GCSCache) Close() error {
+	return c.client.Close()
+}
+
+type gcsStore struct {
+	bucket *storage.BucketHandle
+}
+
+func (s gcsStore) get(ctx context.Context, key string) ([]byte, error) {
+	r, err := s.bucket.Object(key).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func (s gcsStore) put(ctx context.Context, key string, b []byte) error {
+	w := s.bucket.Object(key).NewWriter(ctx)
+	w.ContentType = "application/json"
+	w.ChunkSize = 0 // Upload the small object in a single request without buffering
+	if _, err := w.Write(b); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (s gcsStore) delete(ctx context.Context, key string) error {
+	if err := s.bucket.Object(key).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s gcsStore) list(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	it := s.bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return keys, nil
+		} else if err != nil {
+			return nil, err
+		}
+		keys = append(keys, attrs.Name)
+	}
+}
@@ -22,22 +22,25 @@ func CalcKey(id string, analyzerVersions analyzer.Versions, hookVersions map[str
 
 	h := sha256.New()
 
-	// Write ID, analyzer/handler versions, skipped files/dirs and file patterns
+	// Write ID, analyzer/handler versions, skipped files/dirs, file patterns and the salt of secret fingerprints
 	keyBase := struct {
-		ID               string
-		AnalyzerVersions analyzer.Versions
-		HookVersions     map[string]int
-		SkipFiles        []string
-		SkipDirs         []string
-		FilePatterns     []string `json:",omitempty"`
-	}{id, analyzerVersions, hookVersions, artifactOpt.SkipFiles, artifactOpt.SkipDirs, artifactOpt.FilePatterns}
+		ID                    string
+		AnalyzerVersions      analyzer.Versions
+		HookVersions          map[string]int
+		SkipFiles             []string
+		SkipDirs              []string
+		FilePatterns          []string `json:",omitempty"`
+		SecretFingerprintSalt string   `json:",omitempty"`
+	}{id, analyzerVersions, hookVersions, artifactOpt.SkipFiles, artifactOpt.SkipDirs, artifactOpt.FilePatterns,
+		artifactOpt.SecretScannerOption.FingerprintSalt}
 
 	if err := json.NewEncoder(h).Encode(keyBase); err != nil {
 		return "", xerrors.Errorf("json encode error: %w", err)
 	}
 
-	// Write policy, data contents and secret config file
+	// Write policy, data, CRD schema contents and secret config file
 	paths := append(artifactOpt.MisconfScannerOption.PolicyPaths, artifactOpt.MisconfScannerOption.DataPaths...)
+	paths = append(paths, artifactOpt.MisconfScannerOption.K8sCRDSchemas...)
 
 	// Check if the secret config exists.
 	if _, err := os.Stat(artifactOpt.SecretScannerOption.ConfigPath); err == nil {
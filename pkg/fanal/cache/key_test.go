@@ -22,6 +22,7 @@ func TestCalcKey(t *testing.T) {
 		policy           []string
 		data             []string
 		secretConfigPath string
+		secretSalt       string
 	}
 	tests := []struct {
 		name    string
@@ -212,6 +213,23 @@ func TestCalcKey(t *testing.T) {
 			},
 			want: "sha256:c720b502991465ea11929cfefc71cf4b5aeaa9a8c0ae59fdaf597f957f5cdb18",
 		},
+		{
+			name: "secret fingerprint salt",
+			args: args{
+				key: "sha256:5c534be56eca62e756ef2ef51523feda0f19cd7c15bb0c015e3d6e3ae090bf6e",
+				analyzerVersions: analyzer.Versions{
+					Analyzers: map[string]int{
+						"alpine": 1,
+						"debian": 1,
+					},
+				},
+				hookVersions: map[string]int{
+					"python-pkg": 1,
+				},
+				secretSalt: "salt",
+			},
+			want: "sha256:5980921b905ca2d6cd57a4cf545dcfab595811bc8c001af5fc915291c0424173",
+		},
 		{
 			name: "with policy/non-existent dir",
 			args: args{
@@ -240,7 +258,8 @@ func TestCalcKey(t *testing.T) {
 				},
 
 				SecretScannerOption: analyzer.SecretScannerOption{
-					ConfigPath: tt.args.secretConfigPath,
+					ConfigPath:      tt.args.secretConfigPath,
+					FingerprintSalt: tt.args.secretSalt,
 				},
 			}
 			got, err := CalcKey(tt.args.key, tt.args.analyzerVersions, tt.args.hookVersions, artifactOpt)
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// objectStore is the object storage where the cache is stored, such as Google Cloud Storage and Azure Blob Storage.
+// Unlike S3, which needs the index files, the storage must be strongly consistent.
+type objectStore interface {
+	get(ctx context.Context, key string) ([]byte, error)
+	put(ctx context.Context, key string, b []byte) error
+	delete(ctx context.Context, key string) error // No error if the object doesn't exist
+	list(ctx context.Context, prefix string) ([]string, error)
+}
+
+// objectCache stores the JSON of artifacts and blobs as objects named "artifact/[PREFIX]/[ID]" and "blob/[PREFIX]/[ID]"
+type objectCache struct {
+	store  objectStore
+	prefix string
+}
+
+func (c objectCache) key(bucket, id string) string {
+	return path.Join(bucket, c.prefix, id)
+}
+
+func (c objectCache) PutArtifact(artifactID string, artifactInfo types.ArtifactInfo) error {
+	if err := c.put(c.key(artifactBucket, artifactID), artifactInfo); err != nil {
+		return xerrors.Errorf("unable to store artifact information in cache (%s): %w", artifactID, err)
+	}
+	return nil
+}
+
+func (c objectCache) PutBlob(blobID string, blobInfo types.BlobInfo) error {
+	if err := c.put(c.key(blobBucket, blobID), blobInfo); err != nil {
+		return xerrors.Errorf("unable to store blob information in cache (%s): %w", blobID, err)
+	}
+	return nil
+}
+
+func (c objectCache) put(key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return xerrors.Errorf("JSON marshal error: %w", err)
+	}
+	if err = c.store.put(context.TODO(), key, b); err != nil {
+		return xerrors.Errorf("unable to put object: %w", err)
+	}
+	return nil
+}
+
+func (c objectCache) DeleteBlobs(blobIDs []string) error {
+	var errs error
+	for _, blobID := range blobIDs {
+		key := c.key(blobBucket, blobID)
+		if err := c.store.delete(context.TODO(), key); err != nil {
+			errs = multierror.Append(errs, xerrors.Errorf("unable to delete object (%s): %w", key, err))
+		}
+	}
+	return errs
+}
+
+func (c objectCache) GetArtifact(artifactID string) (types.ArtifactInfo, error) {
+	var info types.ArtifactInfo
+	if err := c.get(c.key(artifactBucket, artifactID), &info); err != nil {
+		return types.ArtifactInfo{}, xerrors.Errorf("failed to get artifact from the cache: %w", err)
+	}
+	return info, nil
+}
+
+func (c objectCache) GetBlob(blobID string) (types.BlobInfo, error) {
+	var blobInfo types.BlobInfo
+	if err := c.get(c.key(blobBucket, blobID), &blobInfo); err != nil {
+		return types.BlobInfo{}, xerrors.Errorf("failed to get blob from the cache: %w", err)
+	}
+	return blobInfo, nil
+}
+
+func (c objectCache) get(key string, v interface{}) error {
+	b, err := c.store.get(context.TODO(), key)
+	if err != nil {
+		return xerrors.Errorf("unable to get object (%s): %w", key, err)
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return xerrors.Errorf("JSON unmarshal error: %w", err)
+	}
+	return nil
+}
+
+func (c objectCache) MissingBlobs(artifactID string, blobIDs []string) (bool, []string, error) {
+	var missingArtifact bool
+	var missingBlobIDs []string
+	for _, blobID := range blobIDs {
+		blobInfo, err := c.GetBlob(blobID)
+		if err != nil {
+			// error means cache missed blob info
+			missingBlobIDs = append(missingBlobIDs, blobID)
+			continue
+		}
+		if blobInfo.SchemaVersion != types.BlobJSONSchemaVersion {
+			missingBlobIDs = append(missingBlobIDs, blobID)
+		}
+	}
+	// get artifact info
+	artifactInfo, err := c.GetArtifact(artifactID)
+	// error means cache missed artifact info
+	if err != nil {
+		return true, missingBlobIDs, nil
+	}
+	if artifactInfo.SchemaVersion != types.ArtifactJSONSchemaVersion {
+		missingArtifact = true
+	}
+	return missingArtifact, missingBlobIDs, nil
+}
+
+func (c objectCache) Close() error {
+	return nil
+}
+
+// Clear deletes all the objects under the prefix
+func (c objectCache) Clear() error {
+	for _, bucket := range []string{artifactBucket, blobBucket} {
+		keys, err := c.store.list(context.TODO(), c.key(bucket, "")+"/")
+		if err != nil {
+			return xerrors.Errorf("unable to list objects: %w", err)
+		}
+		for _, key := range keys {
+			if err = c.store.delete(context.TODO(), key); err != nil {
+				return xerrors.Errorf("unable to delete object (%s): %w", key, err)
+			}
+		}
+	}
+	return nil
+}
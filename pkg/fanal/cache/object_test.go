@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// fakeObjects is the objects in the fake servers of GCS and Azure Blob Storage
+type fakeObjects struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (o *fakeObjects) get(key string) ([]byte, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	b, ok := o.objects[key]
+	return b, ok
+}
+
+func (o *fakeObjects) put(key string, b []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.objects[key] = b
+}
+
+func (o *fakeObjects) delete(key string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, ok := o.objects[key]
+	delete(o.objects, key)
+	return ok
+}
+
+// list returns the sorted keys with the prefix from the marker
+func (o *fakeObjects) list(prefix, marker string) []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	keys := lo.Filter(lo.Keys(o.objects), func(key string, _ int) bool {
+		return strings.HasPrefix(key, prefix) && key >= marker
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+func (o *fakeObjects) keys() []string {
+	return o.list("", "")
+}
+
+// newFakeGCSServer serves the JSON API for uploads, deletions and listings, and the XML API for downloads
+func newFakeGCSServer(t *testing.T, bucket string) (*httptest.Server, *fakeObjects) {
+	objects := &fakeObjects{objects: make(map[string][]byte)}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := r.URL.EscapedPath()
+		switch {
+		case r.Method == http.MethodPost && p == "/upload/storage/v1/b/"+bucket+"/o":
+			_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			require.NoError(t, err)
+			mr := multipart.NewReader(r.Body, params["boundary"])
+
+			var attrs struct {
+				Name string `json:"name"`
+			}
+			part, err := mr.NextPart()
+			require.NoError(t, err)
+			require.NoError(t, json.NewDecoder(part).Decode(&attrs))
+			part, err = mr.NextPart()
+			require.NoError(t, err)
+			b, err := io.ReadAll(part)
+			require.NoError(t, err)
+
+			objects.put(attrs.Name, b)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"bucket": bucket,
+				"name":   attrs.Name,
+			})
+		case r.Method == http.MethodDelete && strings.HasPrefix(p, "/storage/v1/b/"+bucket+"/o/"):
+			if !objects.delete(strings.TrimPrefix(r.URL.Path, "/storage/v1/b/"+bucket+"/o/")) {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && p == "/storage/v1/b/"+bucket+"/o":
+			var items []map[string]string
+			for _, key := range objects.list(r.URL.Query().Get("prefix"), "") {
+				items = append(items, map[string]string{"name": key})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": items})
+		case r.Method == http.MethodGet && strings.HasPrefix(p, "/"+bucket+"/"):
+			b, ok := objects.get(strings.TrimPrefix(r.URL.Path, "/"+bucket+"/"))
+			if !ok {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(b)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, objects
+}
+
+// newFakeAzureBlobServer serves the Blob Storage REST API, returning one blob per page of List Blobs
+func newFakeAzureBlobServer(t *testing.T, container string) (*httptest.Server, *fakeObjects) {
+	objects := &fakeObjects{objects: make(map[string][]byte)}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("x-ms-version") == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/"+container+"/")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/"+container && r.URL.Query().Get("comp") == "list":
+			var list azureBlobList
+			keys := objects.list(r.URL.Query().Get("prefix"), r.URL.Query().Get("marker"))
+			if len(keys) > 0 {
+				list.Blobs = append(list.Blobs, struct {
+					Name string `xml:"Name"`
+				}{Name: keys[0]})
+			}
+			if len(keys) > 1 {
+				list.NextMarker = keys[1]
+			}
+			_ = xml.NewEncoder(w).Encode(list)
+		case r.Method == http.MethodGet:
+			b, ok := objects.get(key)
+			if !ok {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(b)
+		case r.Method == http.MethodPut && r.Header.Get("x-ms-blob-type") == "BlockBlob":
+			b, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			objects.put(key, b)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete:
+			if !objects.delete(key) {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, objects
+}
+
+type fakeTokenCredential struct{}
+
+func (fakeTokenCredential) GetToken(_ context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{
+		Token:     "token",
+		ExpiresOn: time.Now().Add(time.Hour),
+	}, nil
+}
+
+func TestObjectCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		newCache func(t *testing.T) (Cache, *fakeObjects)
+	}{
+		{
+			name: "GCS",
+			newCache: func(t *testing.T) (Cache, *fakeObjects) {
+				ts, objects := newFakeGCSServer(t, "bucket")
+				c, err := NewGCSCache(context.Background(), "bucket", "prefix",
+					option.WithEndpoint(ts.URL+"/storage/v1/"), option.WithoutAuthentication())
+				require.NoError(t, err)
+				return c, objects
+			},
+		},
+		{
+			name: "Azure Blob Storage",
+			newCache: func(t *testing.T) (Cache, *fakeObjects) {
+				ts, objects := newFakeAzureBlobServer(t, "container")
+				c := NewAzureBlobCache(ts.URL, "container", "prefix", fakeTokenCredential{}, &policy.ClientOptions{
+					Transport: ts.Client(),
+					Retry:     policy.RetryOptions{MaxRetries: -1},
+				})
+				return c, objects
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, objects := tt.newCache(t)
+			defer c.Close()
+
+			// Nothing is cached yet
+			missingArtifact, missingBlobIDs, err := c.MissingBlobs("sha256:artifact", []string{correctHash})
+			require.NoError(t, err)
+			assert.True(t, missingArtifact)
+			assert.Equal(t, []string{correctHash}, missingBlobIDs)
+
+			blobInfo := types.BlobInfo{
+				SchemaVersion: types.BlobJSONSchemaVersion,
+				Digest:        "sha256:digest",
+				DiffID:        correctHash,
+			}
+			artifactInfo := types.ArtifactInfo{
+				SchemaVersion: types.ArtifactJSONSchemaVersion,
+				Architecture:  "amd64",
+			}
+			require.NoError(t, c.PutBlob(correctHash, blobInfo))
+			require.NoError(t, c.PutBlob("sha256:old", types.BlobInfo{SchemaVersion: 1}))
+			require.NoError(t, c.PutArtifact("sha256:artifact", artifactInfo))
+			assert.Equal(t, []string{
+				"artifact/prefix/sha256:artifact",
+				"blob/prefix/" + correctHash,
+				"blob/prefix/sha256:old",
+			}, objects.keys())
+
+			gotBlob, err := c.GetBlob(correctHash)
+			require.NoError(t, err)
+			assert.Equal(t, blobInfo, gotBlob)
+
+			gotArtifact, err := c.GetArtifact("sha256:artifact")
+			require.NoError(t, err)
+			assert.Equal(t, artifactInfo, gotArtifact)
+
+			_, err = c.GetBlob("sha256:unknown")
+			assert.ErrorContains(t, err, "failed to get blob from the cache")
+
+			// The blob with the old schema is missing
+			missingArtifact, missingBlobIDs, err = c.MissingBlobs("sha256:artifact", []string{correctHash, "sha256:old"})
+			require.NoError(t, err)
+			assert.False(t, missingArtifact)
+			assert.Equal(t, []string{"sha256:old"}, missingBlobIDs)
+
+			// Deleting unknown blobs doesn't fail
+			require.NoError(t, c.DeleteBlobs([]string{"sha256:old", "sha256:unknown"}))
+			assert.Equal(t, []string{
+				"artifact/prefix/sha256:artifact",
+				"blob/prefix/" + correctHash,
+			}, objects.keys())
+
+			// The objects out of the prefix are kept
+			objects.put("blob/other/"+correctHash, []byte("{}"))
+			objects.put("blob/prefix-other/"+correctHash, []byte("{}"))
+			require.NoError(t, c.Clear())
+			assert.Equal(t, []string{
+				"blob/other/" + correctHash,
+				"blob/prefix-other/" + correctHash,
+			}, objects.keys())
+		})
+	}
+}
@@ -20,7 +20,7 @@ const (
 )
 
 type RedisCache struct {
-	client     *redis.Client
+	client     redis.UniversalClient
 	expiration time.Duration
 }
 
@@ -31,6 +31,22 @@ func NewRedisCache(options *redis.Options, expiration time.Duration) RedisCache
 	}
 }
 
+// NewRedisClusterCache returns the cache stored in Redis Cluster
+func NewRedisClusterCache(options *redis.ClusterOptions, expiration time.Duration) RedisCache {
+	return RedisCache{
+		client:     redis.NewClusterClient(options),
+		expiration: expiration,
+	}
+}
+
+// NewRedisFailoverCache returns the cache stored in the Redis master monitored by Sentinel
+func NewRedisFailoverCache(options *redis.FailoverOptions, expiration time.Duration) RedisCache {
+	return RedisCache{
+		client:     redis.NewFailoverClient(options),
+		expiration: expiration,
+	}
+}
+
 func (c RedisCache) PutArtifact(artifactID string, artifactConfig types.ArtifactInfo) error {
 	key := fmt.Sprintf("%s::%s::%s", redisPrefix, artifactBucket, artifactID)
 	b, err := json.Marshal(artifactConfig)
@@ -131,12 +147,29 @@ func (c RedisCache) Close() error {
 func (c RedisCache) Clear() error {
 	ctx := context.Background()
 
+	// Keys are distributed across the master nodes in Redis Cluster
+	if cluster, ok := c.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return clearKeys(ctx, client)
+		})
+	}
+	return clearKeys(ctx, c.client)
+}
+
+func clearKeys(ctx context.Context, client redis.Cmdable) error {
 	for {
-		keys, cursor, err := c.client.Scan(ctx, 0, redisPrefix+"::*", 100).Result()
+		keys, cursor, err := client.Scan(ctx, 0, redisPrefix+"::*", 100).Result()
 		if err != nil {
 			return xerrors.Errorf("failed to perform prefix scanning: %w", err)
 		}
-		if err = c.client.Unlink(ctx, keys...).Err(); err != nil {
+		// Keys are unlinked one by one as Redis Cluster doesn't allow multi-key commands across hash slots
+		_, err = client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Unlink(ctx, key)
+			}
+			return nil
+		})
+		if err != nil {
 			return xerrors.Errorf("failed to unlink redis keys: %w", err)
 		}
 		if cursor == 0 { // We cleared all keys
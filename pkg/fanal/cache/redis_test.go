@@ -501,6 +501,40 @@ func TestRedisCache_Clear(t *testing.T) {
 		}
 		assert.True(t, s.Exists("foo"))
 	})
+
+	t.Run("cluster", func(t *testing.T) {
+		s.Set("fanal::key0", "value")
+
+		c := cache.NewRedisClusterCache(&redis.ClusterOptions{
+			Addrs: []string{s.Addr()},
+		}, 0)
+		require.NoError(t, c.Clear())
+		assert.False(t, s.Exists("fanal::key0"))
+		assert.True(t, s.Exists("foo"))
+	})
+}
+
+func TestRedisClusterCache(t *testing.T) {
+	s, err := miniredis.Run()
+	require.NoError(t, err)
+	defer s.Close()
+
+	c := cache.NewRedisClusterCache(&redis.ClusterOptions{
+		Addrs: []string{s.Addr()},
+	}, time.Hour)
+	defer c.Close()
+
+	artifactInfo := types.ArtifactInfo{
+		SchemaVersion: 2,
+		Architecture:  "amd64",
+		OS:            "linux",
+	}
+	require.NoError(t, c.PutArtifact("sha256:8652b9f0cb4c0599575e5a003f5906876e10c1ceb2ab9fe1786712dac14a50cf", artifactInfo))
+
+	got, err := c.GetArtifact("sha256:8652b9f0cb4c0599575e5a003f5906876e10c1ceb2ab9fe1786712dac14a50cf")
+	require.NoError(t, err)
+	assert.Equal(t, artifactInfo, got)
+	assert.Equal(t, time.Hour, s.TTL("fanal::artifact::sha256:8652b9f0cb4c0599575e5a003f5906876e10c1ceb2ab9fe1786712dac14a50cf"))
 }
 
 func TestRedisCache_DeleteBlobs(t *testing.T) {
@@ -20,7 +20,8 @@ var _ Cache = &S3Cache{}
 type s3API interface {
 	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
 	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
-	DeleteBucket(ctx context.Context, params *s3.DeleteBucketInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
 }
 
 type S3Cache struct {
@@ -51,14 +52,28 @@ func (c S3Cache) DeleteBlobs(blobIDs []string) error {
 	var errs error
 	for _, blobID := range blobIDs {
 		key := fmt.Sprintf("%s/%s/%s", blobBucket, c.prefix, blobID)
-		input := &s3.DeleteBucketInput{Bucket: aws.String(key)}
-		if _, err := c.s3Client.DeleteBucket(context.TODO(), input); err != nil {
+		if err := c.delete(key); err != nil {
 			errs = multierror.Append(errs, err)
 		}
 	}
 	return errs
 }
 
+// delete removes the object and its index file. The index file is removed first
+// so that the object is never treated as cached while it is being deleted.
+func (c S3Cache) delete(key string) error {
+	for _, k := range []string{fmt.Sprintf("%s.index", key), key} {
+		_, err := c.s3Client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+			Bucket: aws.String(c.bucketName),
+			Key:    aws.String(k),
+		})
+		if err != nil {
+			return xerrors.Errorf("unable to delete object (%s): %w", k, err)
+		}
+	}
+	return nil
+}
+
 func (c S3Cache) PutBlob(blobID string, blobInfo types.BlobInfo) error {
 	key := fmt.Sprintf("%s/%s/%s", blobBucket, c.prefix, blobID)
 	if err := c.put(key, blobInfo); err != nil {
@@ -175,6 +190,29 @@ func (c S3Cache) Close() error {
 	return nil
 }
 
+// Clear deletes all the objects under the prefix.
+// Objects are deleted one by one, as Google Cloud Storage doesn't support deleting multiple objects at once.
 func (c S3Cache) Clear() error {
+	for _, bucket := range []string{artifactBucket, blobBucket} {
+		paginator := s3.NewListObjectsV2Paginator(c.s3Client, &s3.ListObjectsV2Input{
+			Bucket: aws.String(c.bucketName),
+			Prefix: aws.String(fmt.Sprintf("%s/%s/", bucket, c.prefix)),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.TODO())
+			if err != nil {
+				return xerrors.Errorf("unable to list objects: %w", err)
+			}
+			for _, obj := range page.Contents {
+				_, err = c.s3Client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+					Bucket: aws.String(c.bucketName),
+					Key:    obj.Key,
+				})
+				if err != nil {
+					return xerrors.Errorf("unable to delete object (%s): %w", aws.ToString(obj.Key), err)
+				}
+			}
+		}
+	}
 	return nil
 }
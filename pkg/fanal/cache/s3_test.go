@@ -4,11 +4,17 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
@@ -30,11 +36,11 @@ func (m *mockS3Client) HeadObject(ctx context.Context, params *s3.HeadObjectInpu
 	return &s3.HeadObjectOutput{}, nil
 }
 
-func (m *mockS3Client) DeleteBucket(ctx context.Context, in *s3.DeleteBucketInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
-	if in != nil && *in.Bucket == blobBucket+"/prefix/"+correctHash {
-		return &s3.DeleteBucketOutput{}, nil
+func (m *mockS3Client) DeleteObject(ctx context.Context, in *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	if in != nil && strings.TrimSuffix(*in.Key, ".index") == blobBucket+"/prefix/"+correctHash {
+		return &s3.DeleteObjectOutput{}, nil
 	}
-	return nil, errors.New("unknown bucket")
+	return nil, errors.New("unknown object")
 }
 
 func TestS3Cache_PutBlob(t *testing.T) {
@@ -310,3 +316,43 @@ func TestS3Cache_DeleteBlobs(t *testing.T) {
 		})
 	}
 }
+
+type mockS3ClientObjects struct {
+	s3API
+	keys []string
+}
+
+func (m *mockS3ClientObjects) ListObjectsV2(ctx context.Context, in *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	var contents []s3types.Object
+	for _, key := range m.keys {
+		if strings.HasPrefix(key, aws.ToString(in.Prefix)) {
+			contents = append(contents, s3types.Object{Key: aws.String(key)})
+		}
+	}
+	return &s3.ListObjectsV2Output{Contents: contents}, nil
+}
+
+func (m *mockS3ClientObjects) DeleteObject(ctx context.Context, in *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	m.keys = lo.Without(m.keys, aws.ToString(in.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func TestS3Cache_Clear(t *testing.T) {
+	mockSvc := &mockS3ClientObjects{
+		keys: []string{
+			artifactBucket + "/prefix/" + correctHash,
+			artifactBucket + "/prefix/" + correctHash + ".index",
+			blobBucket + "/prefix/" + correctHash,
+			blobBucket + "/prefix/" + correctHash + ".index",
+			blobBucket + "/other/" + correctHash,
+			"unrelated",
+		},
+	}
+
+	c := NewS3Cache("test", "prefix", mockSvc, nil)
+	require.NoError(t, c.Clear())
+	assert.Equal(t, []string{
+		blobBucket + "/other/" + correctHash,
+		"unrelated",
+	}, mockSvc.keys)
+}
@@ -10,13 +10,16 @@ import (
 	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
 	"github.com/aquasecurity/trivy/pkg/fanal/handler"
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/purl"
+	trivyTypes "github.com/aquasecurity/trivy/pkg/types"
 )
 
 func init() {
 	handler.RegisterPostHandlerInit(types.SystemFileFilteringPostHandler, newSystemFileFilteringPostHandler)
 }
 
-const version = 1
+const version = 2
 
 var (
 	defaultSystemFiles = []string{
@@ -52,6 +55,8 @@ func newSystemFileFilteringPostHandler(artifact.Option) (handler.PostHandler, er
 }
 
 // Handle removes files installed by OS package manager such as yum.
+// The removed language-specific packages are recorded as aliases of the OS packages installing them
+// so that the same component is not reported twice.
 func (h systemFileFilteringPostHandler) Handle(_ context.Context, result *analyzer.AnalysisResult, blob *types.BlobInfo) error {
 	var systemFiles []string
 	for _, file := range append(result.SystemInstalledFiles, defaultSystemFiles...) {
@@ -64,11 +69,16 @@ func (h systemFileFilteringPostHandler) Handle(_ context.Context, result *analyz
 		}
 	}
 
+	owners := newFileOwners(blob.PackageInfos)
+
 	var apps []types.Application
 	for _, app := range blob.Applications {
 		// If the lang-specific package was installed by OS package manager, it should not be taken.
 		// Otherwise, the package version will be wrong, then it will lead to false positive.
 		if slices.Contains(systemFiles, app.FilePath) && slices.Contains(affectedTypes, app.Type) {
+			for _, lib := range app.Libraries {
+				owners.addAlias(app.FilePath, app.Type, lib)
+			}
 			continue
 		}
 
@@ -77,6 +87,7 @@ func (h systemFileFilteringPostHandler) Handle(_ context.Context, result *analyz
 			// If the lang-specific package was installed by OS package manager, it should not be taken.
 			// Otherwise, the package version will be wrong, then it will lead to false positive.
 			if slices.Contains(systemFiles, lib.FilePath) {
+				owners.addAlias(lib.FilePath, app.Type, lib)
 				continue
 			}
 			pkgs = append(pkgs, lib)
@@ -104,6 +115,47 @@ func (h systemFileFilteringPostHandler) Handle(_ context.Context, result *analyz
 	return nil
 }
 
+// fileOwners maps installed files to the OS packages installing them.
+type fileOwners map[string]*types.Package
+
+func newFileOwners(pkgInfos []types.PackageInfo) fileOwners {
+	owners := make(fileOwners)
+	for i := range pkgInfos {
+		for j := range pkgInfos[i].Packages {
+			pkg := &pkgInfos[i].Packages[j]
+			for _, file := range pkg.InstalledFiles {
+				owners[strings.TrimPrefix(file, "/")] = pkg
+			}
+		}
+	}
+	return owners
+}
+
+// addAlias records the lang-specific package as an alias of the OS package installing the file.
+func (o fileOwners) addAlias(filePath string, appType types.LangType, lib types.Package) {
+	owner, ok := o[filePath]
+	if !ok {
+		return
+	}
+
+	p, err := purl.New(appType, trivyTypes.Metadata{}, lib)
+	if err != nil {
+		log.Logger.Debugf("Unable to create PURL for %s: %s", lib.Name, err)
+		return
+	} else if p == nil {
+		return
+	}
+
+	alias := p.Unwrap()
+	if slices.ContainsFunc(owner.Aliases, func(id types.PkgIdentifier) bool {
+		return id.PURL.String() == alias.String()
+	}) {
+		return
+	}
+	log.Logger.Debugf("%s (%s) is installed by %s", lib.Name, appType, owner.Name)
+	owner.Aliases = append(owner.Aliases, types.PkgIdentifier{PURL: alias})
+}
+
 func (h systemFileFilteringPostHandler) Version() int {
 	return version
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/package-url/packageurl-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -220,6 +221,71 @@ func Test_systemFileFilterHook_Hook(t *testing.T) {
 			},
 			want: &types.BlobInfo{},
 		},
+		{
+			name: "packages installed by OS package manager",
+			result: &analyzer.AnalysisResult{
+				SystemInstalledFiles: []string{
+					"/usr/lib/python3/dist-packages/PyYAML-5.3.1.egg-info/PKG-INFO",
+					"/usr/lib/python3/dist-packages/yaml/__init__.py",
+				},
+			},
+			blob: &types.BlobInfo{
+				PackageInfos: []types.PackageInfo{
+					{
+						FilePath: "var/lib/dpkg/status",
+						Packages: types.Packages{
+							{
+								Name:    "python3-yaml",
+								Version: "5.3.1-5",
+								InstalledFiles: []string{
+									"/usr/lib/python3/dist-packages/PyYAML-5.3.1.egg-info/PKG-INFO",
+									"/usr/lib/python3/dist-packages/yaml/__init__.py",
+								},
+							},
+						},
+					},
+				},
+				Applications: []types.Application{
+					{
+						Type:     types.PythonPkg,
+						FilePath: "usr/lib/python3/dist-packages/PyYAML-5.3.1.egg-info/PKG-INFO",
+						Libraries: types.Packages{
+							{
+								Name:     "PyYAML",
+								Version:  "5.3.1",
+								FilePath: "usr/lib/python3/dist-packages/PyYAML-5.3.1.egg-info/PKG-INFO",
+							},
+						},
+					},
+				},
+			},
+			want: &types.BlobInfo{
+				PackageInfos: []types.PackageInfo{
+					{
+						FilePath: "var/lib/dpkg/status",
+						Packages: types.Packages{
+							{
+								Name:    "python3-yaml",
+								Version: "5.3.1-5",
+								InstalledFiles: []string{
+									"/usr/lib/python3/dist-packages/PyYAML-5.3.1.egg-info/PKG-INFO",
+									"/usr/lib/python3/dist-packages/yaml/__init__.py",
+								},
+								Aliases: []types.PkgIdentifier{
+									{
+										PURL: &packageurl.PackageURL{
+											Type:    packageurl.TypePyPi,
+											Name:    "pyyaml",
+											Version: "5.3.1",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "Rust will not be skipped",
 			result: &analyzer.AnalysisResult{
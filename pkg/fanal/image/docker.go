@@ -8,11 +8,16 @@ import (
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/klauspost/compress/zstd"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/fanal/utils"
 )
 
+// maxDecompressedSize limits the size of a decompressed zstd image tar file,
+// so that a small file can't expand without bound.
+var maxDecompressedSize int64 = 64 << 30 // 64GB
+
 func tryDockerArchive(fileName string) (v1.Image, error) {
 	img, err := tarball.Image(fileOpener(fileName), nil)
 	if err != nil {
@@ -32,12 +37,57 @@ func fileOpener(fileName string) func() (io.ReadCloser, error) {
 		br := bufio.NewReader(f)
 		r = br
 
-		if utils.IsGzip(br) {
+		switch {
+		case utils.IsGzip(br):
 			r, err = gzip.NewReader(br)
 			if err != nil {
 				return nil, xerrors.Errorf("failed to open gzip: %w", err)
 			}
+		case utils.IsZstd(br):
+			d, err := zstd.NewReader(br)
+			if err != nil {
+				_ = f.Close()
+				return nil, xerrors.Errorf("failed to open zstd: %w", err)
+			}
+			return &zstdReadCloser{
+				Reader:  &sizeLimitedReader{r: d, n: maxDecompressedSize},
+				decoder: d,
+				file:    f,
+			}, nil
 		}
 		return io.NopCloser(r), nil
 	}
 }
+
+type zstdReadCloser struct {
+	io.Reader
+	decoder *zstd.Decoder
+	file    *os.File
+}
+
+func (r *zstdReadCloser) Close() error {
+	r.decoder.Close()
+	return r.file.Close()
+}
+
+// sizeLimitedReader fails with an error, instead of io.EOF like io.LimitedReader, when more than n bytes are available.
+type sizeLimitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		// Check if there is anything left beyond the limit
+		if n, err := l.r.Read(make([]byte, 1)); n == 0 {
+			return 0, err
+		}
+		return 0, xerrors.Errorf("the decompressed size exceeds the limit of %d bytes", maxDecompressedSize)
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
@@ -0,0 +1,62 @@
+package image
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fileOpener(t *testing.T) {
+	content := bytes.Repeat([]byte("a"), 1024)
+
+	var buf bytes.Buffer
+	w, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+	_, err = w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	fileName := filepath.Join(t.TempDir(), "image.tar.zst")
+	require.NoError(t, os.WriteFile(fileName, buf.Bytes(), 0600))
+
+	tests := []struct {
+		name    string
+		limit   int64
+		wantErr string
+	}{
+		{
+			name:  "within the limit",
+			limit: int64(len(content)),
+		},
+		{
+			name:    "exceeding the limit",
+			limit:   int64(len(content)) - 1,
+			wantErr: "the decompressed size exceeds the limit of 1023 bytes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultSize := maxDecompressedSize
+			maxDecompressedSize = tt.limit
+			t.Cleanup(func() { maxDecompressedSize = defaultSize })
+
+			r, err := fileOpener(fileName)()
+			require.NoError(t, err)
+			defer r.Close()
+
+			got, err := io.ReadAll(r)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, content, got)
+		})
+	}
+}
@@ -424,6 +424,12 @@ func TestNewArchiveImage(t *testing.T) {
 				fileName: "../test/testdata/alpine-310.tar.gz",
 			},
 		},
+		{
+			name: "happy path with zstd",
+			args: args{
+				fileName: "../test/testdata/alpine-310.tar.zst",
+			},
+		},
 		{
 			name: "happy path with OCI Image Format",
 			args: args{
@@ -2,12 +2,13 @@ package azure
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/preview/preview/containerregistry/runtime/containerregistry"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"golang.org/x/xerrors"
@@ -17,28 +18,55 @@ import (
 
 type Registry struct {
 	domain string
+	cloud  azureCloud
 }
 
-const (
-	azureURL = "azurecr.io"
-	scope    = "https://management.azure.com/.default"
-	scheme   = "https"
-)
+// azureCloud is the Azure cloud of the registry domain
+type azureCloud struct {
+	config cloud.Configuration
+	scope  string
+}
+
+const scheme = "https"
+
+// clouds are the Azure clouds by registry domain, including the sovereign clouds
+var clouds = map[string]azureCloud{
+	"azurecr.io": {
+		config: cloud.AzurePublic,
+		scope:  "https://management.azure.com/.default",
+	},
+	"azurecr.cn": {
+		config: cloud.AzureChina,
+		scope:  "https://management.chinacloudapi.cn/.default",
+	},
+	"azurecr.us": {
+		config: cloud.AzureGovernment,
+		scope:  "https://management.usgovcloudapi.net/.default",
+	},
+}
 
 func (r *Registry) CheckOptions(domain string, _ types.RegistryOptions) error {
-	if !strings.HasSuffix(domain, azureURL) {
-		return xerrors.Errorf("Azure registry: %w", types.InvalidURLPattern)
+	for suffix, c := range clouds {
+		if strings.HasSuffix(domain, "."+suffix) {
+			r.domain = domain
+			r.cloud = c
+			return nil
+		}
 	}
-	r.domain = domain
-	return nil
+	return xerrors.Errorf("Azure registry: %w", types.InvalidURLPattern)
 }
 
+// GetCredential exchanges the Azure AD token for a refresh token of the registry.
+// The AD token is obtained with the default credential chain, i.e. the environment variables,
+// workload identity, managed identity or Azure CLI.
 func (r *Registry) GetCredential(ctx context.Context) (string, string, error) {
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	cred, err := azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+		ClientOptions: azcore.ClientOptions{Cloud: r.cloud.config},
+	})
 	if err != nil {
 		return "", "", xerrors.Errorf("unable to generate acr credential error: %w", err)
 	}
-	aadToken, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{scope}})
+	aadToken, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{r.cloud.scope}})
 	if err != nil {
 		return "", "", xerrors.Errorf("unable to get an access token: %w", err)
 	}
@@ -49,11 +77,10 @@ func (r *Registry) GetCredential(ctx context.Context) (string, string, error) {
 	return "00000000-0000-0000-0000-000000000000", *rt.RefreshToken, err
 }
 
+// refreshToken exchanges the access token for a refresh token of the registry.
+// The tenant is optional as the registry resolves it from the access token, e.g. with managed identities.
 func refreshToken(ctx context.Context, accessToken, domain string) (containerregistry.RefreshToken, error) {
 	tenantID := os.Getenv("AZURE_TENANT_ID")
-	if tenantID == "" {
-		return containerregistry.RefreshToken{}, errors.New("missing environment variable AZURE_TENANT_ID")
-	}
 	repoClient := containerregistry.NewRefreshTokensClient(fmt.Sprintf("%s://%s", scheme, domain))
 	return repoClient.GetFromExchange(ctx, "access_token", domain, tenantID, "", accessToken)
 }
@@ -19,6 +19,19 @@ func TestRegistry_CheckOptions(t *testing.T) {
 			name:   "happy path",
 			domain: "test.azurecr.io",
 		},
+		{
+			name:   "azure china",
+			domain: "test.azurecr.cn",
+		},
+		{
+			name:   "azure government",
+			domain: "test.azurecr.us",
+		},
+		{
+			name:    "not azure registry",
+			domain:  "azurecr.io.example.com",
+			wantErr: "Azure registry: invalid url pattern",
+		},
 		{
 			name:    "invalidURL",
 			domain:  "alpine:3.9",
@@ -3,6 +3,7 @@ package ecr
 import (
 	"context"
 	"encoding/base64"
+	"regexp"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -14,7 +15,10 @@ import (
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
 )
 
-const ecrURL = "amazonaws.com"
+// ecrPattern matches the private ECR registries, e.g. 123456789012.dkr.ecr.us-east-1.amazonaws.com,
+// including the FIPS endpoints and the China regions.
+// The authorization token is valid only in the region of the registry.
+var ecrPattern = regexp.MustCompile(`^\d{12}\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
 
 type ecrAPI interface {
 	GetAuthorizationToken(ctx context.Context, params *ecr.GetAuthorizationTokenInput, optFns ...func(*ecr.Options)) (*ecr.GetAuthorizationTokenOutput, error)
@@ -24,24 +28,34 @@ type ECR struct {
 	Client ecrAPI
 }
 
-func getSession(option types.RegistryOptions) (aws.Config, error) {
+func getSession(awsRegion string, option types.RegistryOptions) (aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(awsRegion),
+	}
 	// create custom credential information if option is valid
-	if option.AWSSecretKey != "" && option.AWSAccessKey != "" && option.AWSRegion != "" {
-		return config.LoadDefaultConfig(
-			context.TODO(),
-			config.WithRegion(option.AWSRegion),
-			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(option.AWSAccessKey, option.AWSSecretKey, option.AWSSessionToken)),
-		)
+	if option.AWSSecretKey != "" && option.AWSAccessKey != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(option.AWSAccessKey, option.AWSSecretKey, option.AWSSessionToken)))
+	}
+	return config.LoadDefaultConfig(context.TODO(), opts...)
+}
+
+// region returns the region of the ECR registry, or an empty string if the domain is not ECR
+func region(domain string) string {
+	m := ecrPattern.FindStringSubmatch(domain)
+	if m == nil {
+		return ""
 	}
-	return config.LoadDefaultConfig(context.TODO())
+	return m[1]
 }
 
 func (e *ECR) CheckOptions(domain string, option types.RegistryOptions) error {
-	if !strings.HasSuffix(domain, ecrURL) {
+	r := region(domain)
+	if r == "" {
 		return xerrors.Errorf("ECR : %w", types.InvalidURLPattern)
 	}
 
-	cfg, err := getSession(option)
+	cfg, err := getSession(r, option)
 	if err != nil {
 		return err
 	}
@@ -21,8 +21,12 @@ func TestCheckOptions(t *testing.T) {
 			domain:  "alpine:3.9",
 			wantErr: types.InvalidURLPattern,
 		},
+		"NotECR": {
+			domain:  "s3.ap-northeast-1.amazonaws.com",
+			wantErr: types.InvalidURLPattern,
+		},
 		"NoOption": {
-			domain: "xxx.ecr.ap-northeast-1.amazonaws.com",
+			domain: "123456789012.dkr.ecr.ap-northeast-1.amazonaws.com",
 		},
 	}
 
@@ -38,6 +42,21 @@ func TestCheckOptions(t *testing.T) {
 	}
 }
 
+func Test_region(t *testing.T) {
+	tests := map[string]string{
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com":          "us-east-1",
+		"123456789012.dkr.ecr-fips.us-gov-west-1.amazonaws.com": "us-gov-west-1",
+		"123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn":      "cn-north-1",
+		"public.ecr.aws": "",
+		"dkr.ecr.us-east-1.amazonaws.com.example.com": "",
+	}
+	for domain, want := range tests {
+		if got := region(domain); got != want {
+			t.Errorf("region(%q): expected %q, got %q", domain, want, got)
+		}
+	}
+}
+
 type mockedECR struct {
 	Resp ecr.GetAuthorizationTokenOutput
 }
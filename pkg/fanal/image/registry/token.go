@@ -2,6 +2,9 @@ package registry
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 
@@ -12,10 +15,24 @@ import (
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
 )
 
+// tokenTTL is how long the credentials of the cloud registries are reused.
+// The tokens are valid for an hour at least, e.g. 12 hours in ECR.
+const tokenTTL = 10 * time.Minute
+
 var (
 	registries []Registry
+
+	// The registries are stateful between CheckOptions and GetCredential,
+	// so that the credentials are obtained one at a time.
+	mu     sync.Mutex
+	tokens = make(map[string]cachedToken)
 )
 
+type cachedToken struct {
+	auth    authn.Basic
+	expires time.Time
+}
+
 func init() {
 	RegisterRegistry(&google.Registry{})
 	RegisterRegistry(&ecr.ECR{})
@@ -31,7 +48,18 @@ func RegisterRegistry(registry Registry) {
 	registries = append(registries, registry)
 }
 
+// GetToken returns the credentials of the cloud registry selected by the domain, i.e. ECR, GCR/Artifact Registry or ACR,
+// with the native authentication of the cloud, e.g. IAM roles for service accounts, application default credentials
+// or managed identities. The credentials are cached for each domain and the options.
 func GetToken(ctx context.Context, domain string, opt types.RegistryOptions) (auth authn.Basic) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	key := fmt.Sprintf("%s|%s|%s|%s", domain, opt.AWSAccessKey, opt.AWSRegion, opt.GCPCredPath)
+	if t, ok := tokens[key]; ok && time.Now().Before(t.expires) {
+		return t.auth
+	}
+
 	// check registry which particular to get credential
 	for _, registry := range registries {
 		err := registry.CheckOptions(domain, opt)
@@ -44,10 +72,15 @@ func GetToken(ctx context.Context, domain string, opt types.RegistryOptions) (au
 			log.Logger.Debug(err)
 			break
 		}
-		return authn.Basic{
+		auth = authn.Basic{
 			Username: username,
 			Password: password,
 		}
+		tokens[key] = cachedToken{
+			auth:    auth,
+			expires: time.Now().Add(tokenTTL),
+		}
+		return auth
 	}
 	return authn.Basic{}
 }
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/go-containerregistry/pkg/authn"
@@ -35,3 +36,36 @@ func TestGetToken(t *testing.T) {
 		})
 	}
 }
+
+type fakeRegistry struct {
+	calls int
+}
+
+func (r *fakeRegistry) CheckOptions(domain string, _ types.RegistryOptions) error {
+	if !strings.HasSuffix(domain, ".registry.test") {
+		return types.InvalidURLPattern
+	}
+	return nil
+}
+
+func (r *fakeRegistry) GetCredential(_ context.Context) (string, string, error) {
+	r.calls++
+	return "user", "token", nil
+}
+
+func TestGetToken_Cache(t *testing.T) {
+	r := &fakeRegistry{}
+	RegisterRegistry(r)
+	t.Cleanup(func() {
+		registries = registries[:len(registries)-1]
+	})
+
+	want := authn.Basic{Username: "user", Password: "token"}
+	assert.Equal(t, want, GetToken(context.Background(), "a.registry.test", types.RegistryOptions{}))
+	assert.Equal(t, want, GetToken(context.Background(), "a.registry.test", types.RegistryOptions{}))
+	assert.Equal(t, 1, r.calls)
+
+	// Another domain has its own credential
+	assert.Equal(t, want, GetToken(context.Background(), "b.registry.test", types.RegistryOptions{}))
+	assert.Equal(t, 2, r.calls)
+}
@@ -2,6 +2,7 @@ package image
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -63,6 +64,31 @@ func (img remoteImage) RepoDigests() []string {
 	return []string{repoDigest}
 }
 
+// ociManifest is the image manifest with the artifact type, which v1.Manifest doesn't have
+type ociManifest struct {
+	v1.Manifest
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+// ArtifactType returns the type of the OCI artifact such as Helm charts and WebAssembly modules.
+// It returns empty for container images. The manifest has already been fetched, so it doesn't send any request.
+func (img remoteImage) ArtifactType() string {
+	if img.descriptor.MediaType.IsIndex() {
+		return ""
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(img.descriptor.Manifest, &manifest); err != nil {
+		return ""
+	}
+	switch {
+	case manifest.Config.MediaType.IsConfig():
+		return ""
+	case manifest.ArtifactType != "":
+		return manifest.ArtifactType
+	}
+	return string(manifest.Config.MediaType)
+}
+
 type implicitReference struct {
 	ref name.Reference
 }
@@ -2,7 +2,10 @@ package secret
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
+	"fmt"
 	"os"
 	"regexp"
 	"sort"
@@ -22,6 +25,20 @@ var lineSep = []byte{'\n'}
 
 type Scanner struct {
 	*Global
+
+	// fingerprintSalt is the key of the HMAC of matched secrets.
+	// Findings have no fingerprint if it is empty.
+	fingerprintSalt string
+}
+
+type ScannerOption func(*Scanner)
+
+// WithFingerprintSalt enables fingerprints of findings, which are salted hashes of the matched secrets.
+// They allow deduplicating the same secret across scans without storing the secret itself.
+func WithFingerprintSalt(salt string) ScannerOption {
+	return func(s *Scanner) {
+		s.fingerprintSalt = salt
+	}
 }
 
 type Config struct {
@@ -38,6 +55,58 @@ type Config struct {
 	CustomRules      []Rule       `yaml:"rules"`
 	CustomAllowRules AllowRules   `yaml:"allow-rules"`
 	ExcludeBlock     ExcludeBlock `yaml:"exclude-block"`
+
+	// Override severities of rules. Overrides by rule ID take precedence over those by category.
+	SeverityOverrides []SeverityOverride `yaml:"severity-overrides"`
+}
+
+// SeverityOverride replaces the severity of the rule with the ID or the rules in the category.
+type SeverityOverride struct {
+	ID       string                   `yaml:"id"`
+	Category types.SecretRuleCategory `yaml:"category"`
+	Severity string                   `yaml:"severity"`
+}
+
+var severities = []string{
+	"UNKNOWN",
+	"LOW",
+	"MEDIUM",
+	"HIGH",
+	"CRITICAL",
+}
+
+// UnmarshalYAML validates the severity override
+func (o *SeverityOverride) UnmarshalYAML(value *yaml.Node) error {
+	// Define a shadow type to prevent infinite recursion
+	type plain SeverityOverride
+	if err := value.Decode((*plain)(o)); err != nil {
+		return err
+	}
+
+	switch {
+	case o.ID == "" && o.Category == "":
+		return xerrors.New("either id or category is required in severity-overrides")
+	case o.ID != "" && o.Category != "":
+		return xerrors.Errorf("id and category cannot be specified together in severity-overrides: %s", o.ID)
+	}
+
+	o.Severity = strings.ToUpper(o.Severity)
+	if !slices.Contains(severities, o.Severity) {
+		return xerrors.Errorf("invalid severity in severity-overrides: %q", o.Severity)
+	}
+	return nil
+}
+
+// overrideSeverity returns the severity of the rule after applying the overrides.
+// The first override with the rule ID is used, then the first one with the category.
+func overrideSeverity(rule Rule, overrides []SeverityOverride) string {
+	if o, ok := lo.Find(overrides, func(o SeverityOverride) bool { return o.ID != "" && o.ID == rule.ID }); ok {
+		return o.Severity
+	}
+	if o, ok := lo.Find(overrides, func(o SeverityOverride) bool { return o.Category != "" && o.Category == rule.Category }); ok {
+		return o.Severity
+	}
+	return rule.Severity
 }
 
 type Global struct {
@@ -290,13 +359,21 @@ func ParseConfig(configPath string) (*Config, error) {
 	return &config, nil
 }
 
-func NewScanner(config *Config) Scanner {
+func NewScanner(config *Config, opts ...ScannerOption) Scanner {
+	s := Scanner{Global: newGlobal(config)}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+func newGlobal(config *Config) *Global {
 	// Use the default rules
 	if config == nil {
-		return Scanner{Global: &Global{
+		return &Global{
 			Rules:      builtinRules,
 			AllowRules: builtinAllowRules,
-		}}
+		}
 	}
 
 	enabledRules := builtinRules
@@ -315,17 +392,22 @@ func NewScanner(config *Config) Scanner {
 		return !slices.Contains(config.DisableRuleIDs, v.ID)
 	})
 
+	// Rules are copied by lo.Filter, so the built-in rules are not modified
+	for i := range rules {
+		rules[i].Severity = overrideSeverity(rules[i], config.SeverityOverrides)
+	}
+
 	// Disable specified allow rules
 	allowRules := append(builtinAllowRules, config.CustomAllowRules...)
 	allowRules = lo.Filter(allowRules, func(v AllowRule, _ int) bool {
 		return !slices.Contains(config.DisableAllowRuleIDs, v.ID)
 	})
 
-	return Scanner{Global: &Global{
+	return &Global{
 		Rules:        rules,
 		AllowRules:   allowRules,
 		ExcludeBlock: config.ExcludeBlock,
-	}}
+	}
 }
 
 type ScanArgs struct {
@@ -398,7 +480,9 @@ func (s *Scanner) Scan(args ScanArgs) types.Secret {
 	}
 
 	for _, match := range matched {
-		findings = append(findings, toFinding(match.Rule, match.Location, censored))
+		finding := toFinding(match.Rule, match.Location, censored)
+		finding.Fingerprint = s.fingerprint(args.Content[match.Location.Start:match.Location.End])
+		findings = append(findings, finding)
 	}
 
 	if len(findings) == 0 {
@@ -418,6 +502,16 @@ func (s *Scanner) Scan(args ScanArgs) types.Secret {
 	}
 }
 
+// fingerprint returns the HMAC of the secret so that the secret cannot be recovered without the salt
+func (s *Scanner) fingerprint(secret []byte) string {
+	if s.fingerprintSalt == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(s.fingerprintSalt))
+	mac.Write(secret)
+	return fmt.Sprintf("hmac-sha256:%x", mac.Sum(nil))
+}
+
 func censorLocation(loc Location, input []byte) []byte {
 	return append(
 		input[:loc.Start],
@@ -2,12 +2,15 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/package-url/packageurl-go"
 	"github.com/samber/lo"
+	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/digest"
 )
@@ -90,6 +93,10 @@ type Package struct {
 
 	Layer Layer `json:",omitempty"`
 
+	// Identifiers of the same component known to other package managers,
+	// e.g. PyYAML installed by the python3-yaml Debian package.
+	Aliases []PkgIdentifier `json:",omitempty"`
+
 	// Each package metadata have the file path, while the package from lock files does not have.
 	FilePath string `json:",omitempty"`
 
@@ -101,6 +108,44 @@ type Package struct {
 
 	// Files installed by the package
 	InstalledFiles []string `json:",omitempty"`
+
+	// Which analyzer produced this package, to debug why an unexpected package appears.
+	// It doesn't affect the package identity such as SPDX IDs.
+	Provenance *Provenance `json:",omitempty" hash:"ignore"`
+}
+
+// Provenance represents the analyzer and the file a package is detected from
+type Provenance struct {
+	Analyzer string `json:",omitempty"` // e.g. npm, apk
+	Version  int    `json:",omitempty"` // version of the analyzer, bumped when its parser changes
+	FilePath string `json:",omitempty"` // the analyzed file, e.g. package-lock.json
+}
+
+// String returns the provenance in the format "<analyzer>/v<version>:<file path>", e.g. "npm/v1:package-lock.json"
+func (p Provenance) String() string {
+	s := fmt.Sprintf("%s/v%d", p.Analyzer, p.Version)
+	if p.FilePath != "" {
+		s += ":" + p.FilePath
+	}
+	return s
+}
+
+// ParseProvenance parses the string returned by Provenance.String
+func ParseProvenance(s string) (Provenance, error) {
+	analyzer, rest, ok := strings.Cut(s, "/v")
+	if !ok || analyzer == "" {
+		return Provenance{}, xerrors.Errorf("invalid provenance: %s", s)
+	}
+	ver, filePath, _ := strings.Cut(rest, ":")
+	version, err := strconv.Atoi(ver)
+	if err != nil {
+		return Provenance{}, xerrors.Errorf("invalid analyzer version (%s): %w", s, err)
+	}
+	return Provenance{
+		Analyzer: analyzer,
+		Version:  version,
+		FilePath: filePath,
+	}, nil
 }
 
 // PkgIdentifier represents a software identifiers in one of more of the supported formats.
@@ -266,6 +311,8 @@ const (
 	ArtifactSPDX           ArtifactType = "spdx"
 	ArtifactAWSAccount     ArtifactType = "aws_account"
 	ArtifactVM             ArtifactType = "vm"
+	ArtifactLambdaFunction ArtifactType = "lambda_function"
+	ArtifactOCIArtifact    ArtifactType = "oci_artifact"
 )
 
 // ArtifactReference represents a reference of container image, local filesystem and repository
@@ -278,6 +325,23 @@ type ArtifactReference struct {
 
 	// SBOM
 	CycloneDX *CycloneDX
+
+	// Incomplete is set when the analysis stopped early as the budget was exceeded
+	Incomplete *Incomplete
+}
+
+// Budget limits the time and the bytes spent on analyzing an artifact. Zero means no limit.
+type Budget struct {
+	Time  time.Duration
+	Bytes int64
+}
+
+// Incomplete represents the analysis that stopped early as the budget was exceeded
+type Incomplete struct {
+	Reason string
+
+	// SkippedTargets are the files, or the layers of container images, which were not fully analyzed
+	SkippedTargets []string `json:",omitempty"`
 }
 
 type ImageMetadata struct {
@@ -326,6 +390,10 @@ type BlobInfo struct {
 	Secrets           []Secret           `json:",omitempty"`
 	Licenses          []LicenseFile      `json:",omitempty"`
 
+	// Certificates hold the certificates and private keys found in files.
+	// The expiry is checked at scan time, as cached blobs may be reused for a long time.
+	Certificates []CertificateInventory `json:",omitempty"`
+
 	// Red Hat distributions have build info per layer.
 	// This information will be embedded into packages when applying layers.
 	// ref. https://redhat-connect.gitbook.io/partner-guide-for-adopting-red-hat-oval-v2/determining-common-platform-enumeration-cpe
@@ -338,13 +406,14 @@ type BlobInfo struct {
 
 // ArtifactDetail represents the analysis result.
 type ArtifactDetail struct {
-	OS                OS                 `json:",omitempty"`
-	Repository        *Repository        `json:",omitempty"`
-	Packages          Packages           `json:",omitempty"`
-	Applications      []Application      `json:",omitempty"`
-	Misconfigurations []Misconfiguration `json:",omitempty"`
-	Secrets           []Secret           `json:",omitempty"`
-	Licenses          []LicenseFile      `json:",omitempty"`
+	OS                OS                     `json:",omitempty"`
+	Repository        *Repository            `json:",omitempty"`
+	Packages          Packages               `json:",omitempty"`
+	Applications      []Application          `json:",omitempty"`
+	Misconfigurations []Misconfiguration     `json:",omitempty"`
+	Secrets           []Secret               `json:",omitempty"`
+	Licenses          []LicenseFile          `json:",omitempty"`
+	Certificates      []CertificateInventory `json:",omitempty"`
 
 	// ImageConfig has information from container image config
 	ImageConfig ImageConfigDetail
@@ -382,6 +451,7 @@ func (a *ArtifactDetail) ToBlobInfo() BlobInfo {
 		Misconfigurations: a.Misconfigurations,
 		Secrets:           a.Secrets,
 		Licenses:          a.Licenses,
+		Certificates:      a.Certificates,
 		CustomResources:   a.CustomResources,
 	}
 }
@@ -0,0 +1,45 @@
+package types
+
+import "time"
+
+// CertificateInventory holds the certificates and private keys found in a file
+type CertificateInventory struct {
+	FilePath     string
+	FileType     ConfigType
+	Certificates []Certificate `json:",omitempty"`
+	PrivateKeys  []PrivateKey  `json:",omitempty"`
+}
+
+// Certificate represents an X.509 certificate
+type Certificate struct {
+	Subject            string
+	CommonName         string `json:",omitempty"`
+	Issuer             string
+	SerialNumber       string
+	NotBefore          time.Time
+	NotAfter           time.Time
+	KeyAlgorithm       string
+	KeySize            int `json:",omitempty"`
+	SignatureAlgorithm string
+	SelfSigned         bool
+	IsCA               bool
+	StartLine          int `json:",omitempty"`
+	EndLine            int `json:",omitempty"`
+}
+
+// Name returns the common name, or the subject if the certificate has no common name
+func (c Certificate) Name() string {
+	if c.CommonName != "" {
+		return c.CommonName
+	}
+	return c.Subject
+}
+
+// PrivateKey represents a private key
+type PrivateKey struct {
+	Algorithm string
+	KeySize   int `json:",omitempty"`
+	Encrypted bool
+	StartLine int `json:",omitempty"`
+	EndLine   int `json:",omitempty"`
+}
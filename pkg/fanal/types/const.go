@@ -38,6 +38,7 @@ const (
 	Rocky              OSType = "rocky"
 	SLES               OSType = "suse linux enterprise server"
 	Ubuntu             OSType = "ubuntu"
+	Windows            OSType = "windows"
 	Wolfi              OSType = "wolfi"
 )
 
@@ -72,6 +73,9 @@ const (
 	Pub           LangType = "pub"
 	Hex           LangType = "hex"
 	Bitnami       LangType = "bitnami"
+	JRE           LangType = "jre"
+	Hackage       LangType = "hackage" // only from SBOM
+	CRAN          LangType = "cran"    // only from SBOM
 
 	K8sUpstream LangType = "kubernetes"
 	EKS         LangType = "eks" // Amazon Elastic Kubernetes Service
@@ -83,15 +87,20 @@ const (
 
 // Config files
 const (
-	JSON           ConfigType = "json"
-	Dockerfile     ConfigType = "dockerfile"
-	Terraform      ConfigType = "terraform"
-	TerraformPlan  ConfigType = "terraformplan"
-	CloudFormation ConfigType = "cloudformation"
-	Kubernetes     ConfigType = "kubernetes"
-	Helm           ConfigType = "helm"
-	Cloud          ConfigType = "cloud"
-	AzureARM       ConfigType = "azure-arm"
+	JSON            ConfigType = "json"
+	Dockerfile      ConfigType = "dockerfile"
+	Terraform       ConfigType = "terraform"
+	TerraformPlan   ConfigType = "terraformplan"
+	CloudFormation  ConfigType = "cloudformation"
+	Kubernetes      ConfigType = "kubernetes"
+	Helm            ConfigType = "helm"
+	Cloud           ConfigType = "cloud"
+	AzureARM        ConfigType = "azure-arm"
+	TrustStore      ConfigType = "trust-store"
+	CertificateFile ConfigType = "certificate"
+	SSH             ConfigType = "ssh"
+	CloudInit       ConfigType = "cloud-init"
+	BootConfig      ConfigType = "boot-config"
 )
 
 // Language-specific file names
@@ -53,6 +53,9 @@ type ImageOptions struct {
 	PodmanOptions     PodmanOptions
 	ContainerdOptions ContainerdOptions
 	ImageSources      ImageSources
+
+	// LayerMemoryLimit limits the total size of the file contents held in memory while analyzing each layer
+	LayerMemoryLimit int64
 }
 
 type DockerOptions struct {
@@ -17,4 +17,8 @@ type SecretFinding struct {
 	Code      Code
 	Match     string
 	Layer     Layer `json:",omitempty"`
+
+	// Fingerprint is the salted hash of the matched secret, which is the same across scans while the secret is present.
+	// It is empty unless a salt is configured.
+	Fingerprint string `json:",omitempty"`
 }
@@ -48,6 +48,14 @@ func IsGzip(f *bufio.Reader) bool {
 	return buf[0] == 0x1F && buf[1] == 0x8B && buf[2] == 0x8
 }
 
+func IsZstd(f *bufio.Reader) bool {
+	buf, err := f.Peek(4)
+	if err != nil {
+		return false
+	}
+	return buf[0] == 0x28 && buf[1] == 0xB5 && buf[2] == 0x2F && buf[3] == 0xFD
+}
+
 func Keys(m map[string]struct{}) []string {
 	var keys []string
 	for k := range m {
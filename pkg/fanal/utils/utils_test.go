@@ -7,6 +7,31 @@ import (
 	"testing"
 )
 
+func TestIsZstd(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want bool
+	}{
+		{filepath.Join("testdata", "test.txt.zst"), true},
+		{filepath.Join("testdata", "test.txt.gz"), false},
+		{filepath.Join("testdata", "test.txt"), false},
+		{filepath.Join("testdata", "aqua.png"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			f, err := os.Open(tt.in)
+			if err != nil {
+				t.Fatalf("unknown error: %s", err)
+			}
+
+			got := IsZstd(bufio.NewReader(f))
+			if got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsGzip(t *testing.T) {
 	var tests = []struct {
 		in   string
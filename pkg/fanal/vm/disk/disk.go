@@ -3,35 +3,249 @@ package disk
 import (
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
 
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+	"github.com/aquasecurity/trivy/pkg/log"
 )
 
+// maxParentDepth limits the chain of the parent disks, e.g. the backing files of qcow2 snapshots
+const maxParentDepth = 16
+
 var (
 	vmDisks = []Disk{
 		VMDK{},
+		QCOW2{},
+		VHDX{},
+		VHD{},
 	}
 )
 
 // Disk defines virtual machine disk images like VMDK, VDI and VHD.
+// The returned function closes the files opened by the disk, such as the backing files of qcow2.
 type Disk interface {
-	NewReader(io.ReadSeeker, vm.Cache[string, []byte]) (*io.SectionReader, error)
+	NewReader(io.ReadSeeker, vm.Cache[string, []byte]) (*io.SectionReader, func(), error)
 }
 
-func New(rs io.ReadSeeker, cache vm.Cache[string, []byte]) (*io.SectionReader, error) {
+func New(rs io.ReadSeeker, cache vm.Cache[string, []byte]) (*io.SectionReader, func(), error) {
 
 	for _, vmdisk := range vmDisks {
-		var vreader, err = vmdisk.NewReader(rs, cache)
+		var vreader, clean, err = vmdisk.NewReader(rs, cache)
 		if err != nil {
 			if errors.Is(err, vm.ErrInvalidSignature) {
 				continue
 			}
-			return nil, xerrors.Errorf("open virtual machine error: %w", err)
+			return nil, nil, xerrors.Errorf("open virtual machine error: %w", err)
+		}
+
+		return vreader, clean, nil
+	}
+	return nil, nil, xerrors.Errorf("virtual machine can not be detected: %w", vm.ErrInvalidSignature)
+}
+
+// parentFile is the parent disk with the chain of its descendants, which is passed to the parent's parent
+type parentFile struct {
+	*os.File
+	chain []string // The absolute paths of the descendants, starting from the disk given to New
+}
+
+// descendants returns the absolute paths of the child disk and its descendants
+func descendants(child io.ReadSeeker) []string {
+	switch f := child.(type) {
+	case *parentFile:
+		return append(slices.Clone(f.chain), f.Name())
+	case interface{ Name() string }:
+		return []string{absPath(f.Name())}
+	}
+	return nil
+}
+
+// absPath returns the absolute path with the symbolic links resolved, so that the same file has the same path
+func absPath(filePath string) string {
+	if p, err := filepath.Abs(filePath); err == nil {
+		filePath = p
+	}
+	if p, err := filepath.EvalSymlinks(filePath); err == nil {
+		filePath = p
+	}
+	return filePath
+}
+
+// openParent opens the parent disk of snapshots and differencing disks.
+// The relative path is resolved from the directory of the child disk.
+// The parent is read as a raw image if the format is not detected.
+// The chain of the parents must not be circular or deeper than maxParentDepth.
+func openParent(child io.ReadSeeker, parentPath string, cache vm.Cache[string, []byte]) (*io.SectionReader, func(), error) {
+	if !filepath.IsAbs(parentPath) {
+		f, ok := child.(interface{ Name() string })
+		if !ok {
+			return nil, nil, xerrors.Errorf("unable to resolve the relative path of the parent disk: %s", parentPath)
+		}
+		parentPath = filepath.Join(filepath.Dir(f.Name()), parentPath)
+	}
+	parentPath = absPath(parentPath)
+	log.Logger.Debugf("Opening the parent disk: %s", parentPath)
+
+	chain := descendants(child)
+	if len(chain) > maxParentDepth {
+		return nil, nil, xerrors.Errorf("the chain of the parent disks is deeper than %d", maxParentDepth)
+	} else if slices.Contains(chain, parentPath) {
+		return nil, nil, xerrors.Errorf("circular chain of the parent disks: %s", parentPath)
+	}
+
+	file, err := os.Open(parentPath)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("parent disk open error: %w", err)
+	}
+	f := &parentFile{
+		File:  file,
+		chain: chain,
+	}
+
+	r, clean, err := New(f, cache)
+	if err == nil {
+		return r, func() {
+			clean()
+			_ = f.Close()
+		}, nil
+	} else if !errors.Is(err, vm.ErrInvalidSignature) {
+		_ = f.Close()
+		return nil, nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, xerrors.Errorf("parent disk stat error: %w", err)
+	}
+	return io.NewSectionReader(f, 0, fi.Size()), func() { _ = f.Close() }, nil
+}
+
+// readZeroExtended reads the parent disk, which may be smaller than the child disk.
+// The area beyond the end of the parent is read as zeros.
+func readZeroExtended(parent *io.SectionReader, p []byte, off int64) error {
+	clear(p)
+	if parent == nil || off >= parent.Size() {
+		return nil
+	}
+	if _, err := parent.ReadAt(p, off); err != nil && !errors.Is(err, io.EOF) {
+		return xerrors.Errorf("parent disk read error: %w", err)
+	}
+	return nil
+}
+
+// readerAt reads the disk image at the offset, as the disk is given as io.ReadSeeker
+type readerAt struct {
+	mu sync.Mutex
+	rs io.ReadSeeker
+}
+
+func newReaderAt(rs io.ReadSeeker) io.ReaderAt {
+	if ra, ok := rs.(io.ReaderAt); ok {
+		return ra
+	}
+	return &readerAt{rs: rs}
+}
+
+func (r *readerAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r.rs, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// noCache is used when the cache is not given
+type noCache struct{}
+
+func (noCache) Add(string, []byte) bool {
+	return false
+}
+
+func (noCache) Get(string) ([]byte, bool) {
+	return nil, false
+}
+
+func cacheOrNoCache(cache vm.Cache[string, []byte]) vm.Cache[string, []byte] {
+	if cache == nil {
+		return noCache{}
+	}
+	return cache
+}
+
+func noClean() {}
+
+// readBlocks splits the read into the fixed-size blocks of the virtual disk, such as qcow2 clusters and VHDX blocks
+func readBlocks(p []byte, off, size, blockSize int64, readBlock func(p []byte, block, offInBlock int64) error) (int, error) {
+	if off < 0 {
+		return 0, xerrors.New("negative offset")
+	} else if off >= size {
+		return 0, io.EOF
+	}
+
+	var eof bool
+	if remaining := size - off; int64(len(p)) > remaining {
+		p = p[:remaining]
+		eof = true
+	}
+
+	var n int
+	for n < len(p) {
+		pos := off + int64(n)
+		block, offInBlock := pos/blockSize, pos%blockSize
+		chunk := min(int64(len(p)-n), blockSize-offInBlock)
+		if err := readBlock(p[n:n+int(chunk)], block, offInBlock); err != nil {
+			return n, err
+		}
+		n += int(chunk)
+	}
+
+	if eof {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// readSectorRuns reads the sectors in the block of differencing disks, which are in the child or the parent disk.
+// The run of the sectors in the same disk is read at once.
+func readSectorRuns(p []byte, offInBlock, sectorSize int64, present func(sector int64) bool,
+	readChild, readParent func(p []byte, offInBlock int64) error) error {
+	for len(p) > 0 {
+		inChild := present(offInBlock / sectorSize)
+		n := min(int64(len(p)), sectorSize-offInBlock%sectorSize)
+		for n < int64(len(p)) && present((offInBlock+n)/sectorSize) == inChild {
+			n += min(int64(len(p))-n, sectorSize)
+		}
+
+		read := readParent
+		if inChild {
+			read = readChild
 		}
+		if err := read(p[:n], offInBlock); err != nil {
+			return err
+		}
+		p, offInBlock = p[n:], offInBlock+n
+	}
+	return nil
+}
 
-		return vreader, nil
+// readFull reads the data in the disk image, which must not be truncated
+func readFull(r io.ReaderAt, p []byte, off int64) error {
+	if n, err := r.ReadAt(p, off); n != len(p) {
+		if err == nil || errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return xerrors.Errorf("read error at %d: %w", off, err)
 	}
-	return nil, xerrors.New("virtual machine can not be detected")
+	return nil
 }
@@ -33,7 +33,7 @@ func TestNew(t *testing.T) {
 			f, err := os.Open(tt.fileName)
 			require.NoError(t, err)
 
-			_, err = disk.New(f, nil)
+			_, _, err = disk.New(f, nil)
 			if err == nil {
 				assert.Fail(t, "required error test")
 			}
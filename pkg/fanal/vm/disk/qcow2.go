@@ -0,0 +1,249 @@
+package disk
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+)
+
+// cf. https://github.com/qemu/qemu/blob/master/docs/interop/qcow2.txt
+const (
+	qcow2Magic = "QFI\xfb"
+
+	qcow2OffsetMask     = 0x00fffffffffffe00
+	qcow2CompressedFlag = 1 << 62
+	qcow2ZeroFlag       = 1
+
+	// Incompatible features
+	qcow2Dirty           = 1 << 0
+	qcow2Corrupt         = 1 << 1
+	qcow2CompressionType = 1 << 3
+
+	qcow2CompressionZlib = 0
+	qcow2CompressionZstd = 1
+
+	// The L1 table of the 1 PB disk with 512-byte clusters has 32 MB
+	maxL1Size = 32 << 20
+
+	// QEMU refuses the longer backing file names
+	maxBackingFileSize = 1023
+)
+
+type QCOW2 struct{}
+
+type qcow2Header struct {
+	Magic                 [4]byte
+	Version               uint32
+	BackingFileOffset     uint64
+	BackingFileSize       uint32
+	ClusterBits           uint32
+	Size                  uint64
+	CryptMethod           uint32
+	L1Size                uint32
+	L1TableOffset         uint64
+	RefcountTableOffset   uint64
+	RefcountTableClusters uint32
+	NbSnapshots           uint32
+	SnapshotsOffset       uint64
+
+	// Version 3
+	IncompatibleFeatures uint64
+	CompatibleFeatures   uint64
+	AutoclearFeatures    uint64
+	RefcountOrder        uint32
+	HeaderLength         uint32
+	CompressionType      uint8
+}
+
+// qcow2Image reads the guest clusters through the L1 and L2 tables.
+// Unallocated clusters are read from the backing file, which is the base image of the snapshot created by "qemu-img create -b".
+// Internal snapshots are not read, as the L1 table in the header is the active one.
+type qcow2Image struct {
+	r           io.ReaderAt
+	header      qcow2Header
+	clusterSize int64
+	l2Entries   int64
+	l1          []uint64
+	backing     *io.SectionReader
+	cache       vm.Cache[string, []byte]
+}
+
+func (QCOW2) NewReader(rs io.ReadSeeker, cache vm.Cache[string, []byte]) (*io.SectionReader, func(), error) {
+	r := newReaderAt(rs)
+
+	var header qcow2Header
+	buf := make([]byte, binary.Size(header))
+	if n, err := r.ReadAt(buf, 0); n < 4 || string(buf[:4]) != qcow2Magic {
+		return nil, nil, vm.ErrInvalidSignature
+	} else if n < 72 {
+		return nil, nil, xerrors.Errorf("qcow2 header read error: %w", err)
+	}
+	if err := binary.Read(bytes.NewReader(buf), binary.BigEndian, &header); err != nil {
+		return nil, nil, xerrors.Errorf("qcow2 header decode error: %w", err)
+	}
+
+	if err := header.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	img := &qcow2Image{
+		r:           r,
+		header:      header,
+		clusterSize: 1 << header.ClusterBits,
+		l2Entries:   1 << (header.ClusterBits - 3),
+		cache:       cacheOrNoCache(cache),
+	}
+
+	l1 := make([]byte, int(header.L1Size)*8)
+	if err := readFull(r, l1, int64(header.L1TableOffset)); err != nil {
+		return nil, nil, xerrors.Errorf("qcow2 L1 table error: %w", err)
+	}
+	img.l1 = make([]uint64, header.L1Size)
+	for i := range img.l1 {
+		img.l1[i] = binary.BigEndian.Uint64(l1[i*8:])
+	}
+
+	clean := noClean
+	if header.BackingFileOffset != 0 {
+		name := make([]byte, header.BackingFileSize)
+		if err := readFull(r, name, int64(header.BackingFileOffset)); err != nil {
+			return nil, nil, xerrors.Errorf("qcow2 backing file error: %w", err)
+		}
+		backing, backingClean, err := openParent(rs, string(name), cache)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("qcow2 backing file (%s) error: %w", name, err)
+		}
+		img.backing, clean = backing, backingClean
+	}
+
+	return io.NewSectionReader(img, 0, int64(header.Size)), clean, nil
+}
+
+func (h *qcow2Header) validate() error {
+	switch h.Version {
+	case 2:
+		// Version 2 doesn't have the fields of version 3
+		h.IncompatibleFeatures, h.CompatibleFeatures, h.AutoclearFeatures = 0, 0, 0
+		h.RefcountOrder, h.HeaderLength, h.CompressionType = 0, 0, 0
+	case 3:
+		if h.HeaderLength <= 104 || h.IncompatibleFeatures&qcow2CompressionType == 0 {
+			h.CompressionType = qcow2CompressionZlib
+		}
+	default:
+		return xerrors.Errorf("qcow2 version %d: %w", h.Version, vm.ErrUnsupportedType)
+	}
+
+	switch {
+	case h.ClusterBits < 9 || h.ClusterBits > 21:
+		return xerrors.Errorf("invalid qcow2 cluster bits: %d", h.ClusterBits)
+	case h.CryptMethod != 0:
+		return xerrors.Errorf("encrypted qcow2: %w", vm.ErrUnsupportedType)
+	case h.IncompatibleFeatures&qcow2Corrupt != 0:
+		return xerrors.New("qcow2 image is marked as corrupt, run 'qemu-img check -r all'")
+	case h.IncompatibleFeatures&^(qcow2Dirty|qcow2CompressionType) != 0:
+		// e.g. external data files and extended L2 entries
+		return xerrors.Errorf("qcow2 incompatible features %#x: %w", h.IncompatibleFeatures, vm.ErrUnsupportedType)
+	case h.CompressionType != qcow2CompressionZlib && h.CompressionType != qcow2CompressionZstd:
+		return xerrors.Errorf("qcow2 compression type %d: %w", h.CompressionType, vm.ErrUnsupportedType)
+	case uint64(h.L1Size)*8 > maxL1Size:
+		return xerrors.Errorf("qcow2 L1 table too large: %d", h.L1Size)
+	case h.BackingFileOffset != 0 && h.BackingFileSize > maxBackingFileSize:
+		return xerrors.Errorf("qcow2 backing file name too long: %d", h.BackingFileSize)
+	}
+	return nil
+}
+
+func (img *qcow2Image) ReadAt(p []byte, off int64) (int, error) {
+	return readBlocks(p, off, int64(img.header.Size), img.clusterSize, img.readCluster)
+}
+
+func (img *qcow2Image) readCluster(p []byte, cluster, offInCluster int64) error {
+	l1Index, l2Index := cluster/img.l2Entries, cluster%img.l2Entries
+	if l1Index >= int64(len(img.l1)) || img.l1[l1Index]&qcow2OffsetMask == 0 {
+		return readZeroExtended(img.backing, p, cluster*img.clusterSize+offInCluster)
+	}
+
+	l2, err := img.l2Table(int64(img.l1[l1Index] & qcow2OffsetMask))
+	if err != nil {
+		return xerrors.Errorf("qcow2 L2 table error: %w", err)
+	}
+	entry := binary.BigEndian.Uint64(l2[l2Index*8:])
+
+	switch {
+	case entry&qcow2CompressedFlag != 0:
+		data, err := img.compressedCluster(entry)
+		if err != nil {
+			return xerrors.Errorf("qcow2 compressed cluster error: %w", err)
+		}
+		copy(p, data[offInCluster:])
+	case img.header.Version >= 3 && entry&qcow2ZeroFlag != 0:
+		clear(p)
+	case entry&qcow2OffsetMask == 0:
+		return readZeroExtended(img.backing, p, cluster*img.clusterSize+offInCluster)
+	default:
+		return readFull(img.r, p, int64(entry&qcow2OffsetMask)+offInCluster)
+	}
+	return nil
+}
+
+func (img *qcow2Image) l2Table(offset int64) ([]byte, error) {
+	key := fmt.Sprintf("qcow2:%p:l2:%d", img, offset)
+	if l2, ok := img.cache.Get(key); ok {
+		return l2, nil
+	}
+	l2 := make([]byte, img.clusterSize)
+	if err := readFull(img.r, l2, offset); err != nil {
+		return nil, err
+	}
+	img.cache.Add(key, l2)
+	return l2, nil
+}
+
+// compressedCluster decompresses the cluster, whose descriptor has the offset and the number of the 512-byte sectors
+func (img *qcow2Image) compressedCluster(entry uint64) ([]byte, error) {
+	offsetBits := 62 - (img.header.ClusterBits - 8)
+	offset := int64(entry & (1<<offsetBits - 1))
+	sectors := int64(entry>>offsetBits) & (1<<(img.header.ClusterBits-8) - 1)
+
+	key := fmt.Sprintf("qcow2:%p:compressed:%d", img, offset)
+	if data, ok := img.cache.Get(key); ok {
+		return data, nil
+	}
+
+	// The compressed data may end before the last sector at the end of the file
+	compressed := make([]byte, (sectors+1)*512-offset%512)
+	n, err := img.r.ReadAt(compressed, offset)
+	if n == 0 {
+		return nil, xerrors.Errorf("read error: %w", err)
+	}
+
+	var dec io.Reader
+	switch img.header.CompressionType {
+	case qcow2CompressionZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(compressed[:n]))
+		if err != nil {
+			return nil, xerrors.Errorf("zstd error: %w", err)
+		}
+		defer zr.Close()
+		dec = zr
+	default:
+		// Deflate without the zlib header
+		fr := flate.NewReader(bytes.NewReader(compressed[:n]))
+		defer fr.Close()
+		dec = fr
+	}
+
+	data := make([]byte, img.clusterSize)
+	if _, err = io.ReadFull(dec, data); err != nil {
+		return nil, xerrors.Errorf("decompression error: %w", err)
+	}
+	img.cache.Add(key, data)
+	return data, nil
+}
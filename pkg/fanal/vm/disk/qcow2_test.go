@@ -0,0 +1,401 @@
+package disk_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+	"github.com/aquasecurity/trivy/pkg/fanal/vm/disk"
+)
+
+const qcow2ClusterBits = 9 // 512-byte clusters to keep the images small
+
+type qcow2ClusterType int
+
+const (
+	qcow2Data qcow2ClusterType = iota
+	qcow2Zero
+	qcow2Deflate
+	qcow2Zstd
+)
+
+type qcow2Cluster struct {
+	typ  qcow2ClusterType
+	data []byte
+}
+
+type qcow2Image struct {
+	version     uint32
+	size        uint64
+	backingFile string
+	cryptMethod uint32
+	clusters    map[int64]qcow2Cluster // the allocated guest clusters
+}
+
+// write writes the image in the same layout as "qemu-img create -f qcow2 -o cluster_size=512",
+// i.e. the header, the L1 table, the L2 tables and the data clusters.
+func (img qcow2Image) write(t *testing.T, filePath string) {
+	clusterSize := int64(1) << qcow2ClusterBits
+	l2Entries := clusterSize / 8
+	l1Size := (int64(img.size)/clusterSize + l2Entries - 1) / l2Entries
+
+	var compressionType uint8
+	for _, c := range img.clusters {
+		if c.typ == qcow2Zstd {
+			compressionType = 1
+		}
+	}
+
+	file := make([]byte, clusterSize*(2+l1Size))
+	headerLength := 112
+	if img.version == 2 {
+		headerLength = 72
+	}
+	header := file[:clusterSize]
+	copy(header, "QFI\xfb")
+	binary.BigEndian.PutUint32(header[4:], img.version)
+	if img.backingFile != "" {
+		binary.BigEndian.PutUint64(header[8:], uint64(headerLength))
+		binary.BigEndian.PutUint32(header[16:], uint32(len(img.backingFile)))
+		copy(header[headerLength:], img.backingFile)
+	}
+	binary.BigEndian.PutUint32(header[20:], qcow2ClusterBits)
+	binary.BigEndian.PutUint64(header[24:], img.size)
+	binary.BigEndian.PutUint32(header[32:], img.cryptMethod)
+	binary.BigEndian.PutUint32(header[36:], uint32(l1Size))
+	binary.BigEndian.PutUint64(header[40:], uint64(clusterSize))
+	if img.version == 3 {
+		if compressionType != 0 {
+			binary.BigEndian.PutUint64(header[72:], 1<<3)
+		}
+		binary.BigEndian.PutUint32(header[96:], 4)
+		binary.BigEndian.PutUint32(header[100:], uint32(headerLength))
+		header[104] = compressionType
+	}
+
+	l1 := file[clusterSize : 2*clusterSize]
+	for i := int64(0); i < l1Size; i++ {
+		// The L2 tables follow the L1 table
+		binary.BigEndian.PutUint64(l1[i*8:], uint64((2+i)*clusterSize)|1<<63)
+	}
+
+	for cluster := int64(0); cluster < int64(img.size)/clusterSize; cluster++ {
+		c, ok := img.clusters[cluster]
+		if !ok {
+			continue
+		}
+		var entry uint64
+		switch c.typ {
+		case qcow2Data:
+			// Unlike compressed clusters, the clusters are aligned
+			file = append(file, make([]byte, (clusterSize-int64(len(file))%clusterSize)%clusterSize)...)
+			entry = uint64(len(file)) | 1<<63
+			file = append(file, c.data...)
+		case qcow2Zero:
+			entry = 1
+		case qcow2Deflate, qcow2Zstd:
+			compressed := compress(t, c.typ, c.data)
+			offset := uint64(len(file))
+			sectors := (offset%512 + uint64(len(compressed)) + 511) / 512
+			offsetBits := 62 - (qcow2ClusterBits - 8)
+			entry = 1<<62 | offset | (sectors-1)<<offsetBits
+			file = append(file, compressed...)
+		}
+		l2Offset := (2 + cluster/l2Entries) * clusterSize
+		binary.BigEndian.PutUint64(file[l2Offset+(cluster%l2Entries)*8:], entry)
+	}
+
+	require.NoError(t, os.WriteFile(filePath, file, 0o600))
+}
+
+func compress(t *testing.T, typ qcow2ClusterType, data []byte) []byte {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+	if typ == qcow2Zstd {
+		w, err = zstd.NewWriter(&buf)
+	} else {
+		w, err = flate.NewWriter(&buf, flate.BestCompression)
+	}
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func fill(b byte, n int) []byte {
+	return bytes.Repeat([]byte{b}, n)
+}
+
+func readAll(t *testing.T, sr *io.SectionReader) []byte {
+	b, err := io.ReadAll(sr)
+	require.NoError(t, err)
+	return b
+}
+
+func TestQCOW2_NewReader(t *testing.T) {
+	dir := t.TempDir()
+
+	// The base image of the snapshots
+	base := filepath.Join(dir, "base.qcow2")
+	qcow2Image{
+		version: 3,
+		size:    4 << 10,
+		clusters: map[int64]qcow2Cluster{
+			0: {data: fill('a', 512)},
+			1: {data: fill('b', 512)},
+			2: {data: fill('c', 512)},
+		},
+	}.write(t, base)
+
+	raw := filepath.Join(dir, "base.raw")
+	require.NoError(t, os.WriteFile(raw, fill('r', 1024), 0o600))
+
+	tests := []struct {
+		name    string
+		image   qcow2Image
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "clusters",
+			image: qcow2Image{
+				version: 3,
+				size:    64 << 10, // two L2 tables
+				clusters: map[int64]qcow2Cluster{
+					0:   {data: fill('x', 512)},
+					2:   {typ: qcow2Zero},
+					3:   {typ: qcow2Deflate, data: append(fill('d', 256), fill('e', 256)...)},
+					127: {data: fill('y', 512)},
+				},
+			},
+			want: bytes.Join([][]byte{
+				fill('x', 512),
+				make([]byte, 1024),
+				fill('d', 256),
+				fill('e', 256),
+				make([]byte, 123*512),
+				fill('y', 512),
+			}, nil),
+		},
+		{
+			name: "zstd",
+			image: qcow2Image{
+				version: 3,
+				size:    1024,
+				clusters: map[int64]qcow2Cluster{
+					1: {typ: qcow2Zstd, data: fill('z', 512)},
+				},
+			},
+			want: append(make([]byte, 512), fill('z', 512)...),
+		},
+		{
+			name: "snapshot",
+			image: qcow2Image{
+				version:     3,
+				size:        6 << 10,
+				backingFile: "base.qcow2",
+				clusters: map[int64]qcow2Cluster{
+					1: {data: fill('B', 512)},
+					2: {typ: qcow2Zero},
+				},
+			},
+			// The base image is smaller than the snapshot
+			want: bytes.Join([][]byte{
+				fill('a', 512),
+				fill('B', 512),
+				make([]byte, 6<<10-1024),
+			}, nil),
+		},
+		{
+			name: "version 2 with raw backing file",
+			image: qcow2Image{
+				version:     2,
+				size:        1024,
+				backingFile: raw,
+				clusters: map[int64]qcow2Cluster{
+					0: {data: fill('x', 512)},
+				},
+			},
+			want: append(fill('x', 512), fill('r', 512)...),
+		},
+		{
+			name: "backing file not found",
+			image: qcow2Image{
+				version:     3,
+				size:        1024,
+				backingFile: "missing.qcow2",
+			},
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name: "encrypted",
+			image: qcow2Image{
+				version:     3,
+				size:        1024,
+				cryptMethod: 2,
+			},
+			wantErr: vm.ErrUnsupportedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(dir, "disk.qcow2")
+			tt.image.write(t, filePath)
+			f, err := os.Open(filePath)
+			require.NoError(t, err)
+			defer f.Close()
+
+			sr, clean, err := disk.QCOW2{}.NewReader(f, nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			defer clean()
+
+			assert.Equal(t, tt.want, readAll(t, sr))
+		})
+	}
+}
+
+func TestQCOW2_NewReaderInvalidSignature(t *testing.T) {
+	f, err := os.Open("testdata/invalid.vmdk")
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, _, err = disk.QCOW2{}.NewReader(f, nil)
+	assert.ErrorIs(t, err, vm.ErrInvalidSignature)
+}
+
+func TestQCOW2_NewReaderBackingChain(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, dir string) string // returns the path of the disk
+		wantErr string
+	}{
+		{
+			name: "self-reference",
+			setup: func(t *testing.T, dir string) string {
+				filePath := filepath.Join(dir, "disk.qcow2")
+				qcow2Image{
+					version:     3,
+					size:        1024,
+					backingFile: "disk.qcow2",
+				}.write(t, filePath)
+				return filePath
+			},
+			wantErr: "circular chain of the parent disks",
+		},
+		{
+			name: "circular chain through a symlink",
+			setup: func(t *testing.T, dir string) string {
+				qcow2Image{
+					version:     3,
+					size:        1024,
+					backingFile: "b.qcow2",
+				}.write(t, filepath.Join(dir, "a.qcow2"))
+				qcow2Image{
+					version:     3,
+					size:        1024,
+					backingFile: "link.qcow2",
+				}.write(t, filepath.Join(dir, "b.qcow2"))
+				require.NoError(t, os.Symlink("a.qcow2", filepath.Join(dir, "link.qcow2")))
+				return filepath.Join(dir, "a.qcow2")
+			},
+			wantErr: "circular chain of the parent disks",
+		},
+		{
+			name: "too deep chain",
+			setup: func(t *testing.T, dir string) string {
+				qcow2Image{
+					version: 3,
+					size:    1024,
+				}.write(t, filepath.Join(dir, "0.qcow2"))
+				for i := 1; i <= 20; i++ {
+					qcow2Image{
+						version:     3,
+						size:        1024,
+						backingFile: fmt.Sprintf("%d.qcow2", i-1),
+					}.write(t, filepath.Join(dir, fmt.Sprintf("%d.qcow2", i)))
+				}
+				return filepath.Join(dir, "20.qcow2")
+			},
+			wantErr: "the chain of the parent disks is deeper than 16",
+		},
+		{
+			name: "too long backing file name",
+			setup: func(t *testing.T, dir string) string {
+				filePath := filepath.Join(dir, "disk.qcow2")
+				qcow2Image{
+					version:     3,
+					size:        1024,
+					backingFile: "base.qcow2",
+				}.write(t, filePath)
+
+				// The size of the name in the header
+				b, err := os.ReadFile(filePath)
+				require.NoError(t, err)
+				binary.BigEndian.PutUint32(b[16:], 0xffffffff)
+				require.NoError(t, os.WriteFile(filePath, b, 0o600))
+				return filePath
+			},
+			wantErr: "qcow2 backing file name too long",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.setup(t, t.TempDir()))
+			require.NoError(t, err)
+			defer f.Close()
+
+			_, _, err = disk.QCOW2{}.NewReader(f, nil)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestQCOW2_NewReaderLongChain(t *testing.T) {
+	dir := t.TempDir()
+	qcow2Image{
+		version: 3,
+		size:    1024,
+		clusters: map[int64]qcow2Cluster{
+			0: {data: fill('a', 512)},
+		},
+	}.write(t, filepath.Join(dir, "0.qcow2"))
+
+	// The chain up to the limit is read, with the same name in different directories
+	filePath := filepath.Join(dir, "0.qcow2")
+	for i := 1; i <= 16; i++ {
+		sub := filepath.Join(dir, fmt.Sprint(i))
+		require.NoError(t, os.Mkdir(sub, 0o700))
+		next := filepath.Join(sub, "0.qcow2")
+		qcow2Image{
+			version:     3,
+			size:        1024,
+			backingFile: filePath,
+		}.write(t, next)
+		filePath = next
+	}
+
+	f, err := os.Open(filePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	sr, clean, err := disk.QCOW2{}.NewReader(f, nil)
+	require.NoError(t, err)
+	defer clean()
+	assert.Equal(t, append(fill('a', 512), make([]byte, 512)...), readAll(t, sr))
+}
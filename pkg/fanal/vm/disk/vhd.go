@@ -0,0 +1,272 @@
+package disk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+)
+
+// cf. https://learn.microsoft.com/en-us/windows/win32/vstor/about-vhd
+const (
+	vhdFooterCookie        = "conectix"
+	vhdDynamicHeaderCookie = "cxsparse"
+	vhdFooterSize          = 512
+	vhdDynamicHeaderSize   = 1024
+	vhdSectorSize          = 512
+	vhdUnallocated         = 0xFFFFFFFF
+
+	vhdFixed        = 2
+	vhdDynamic      = 3
+	vhdDifferencing = 4
+
+	// Platform codes of the parent locators
+	vhdRelativePath = "W2ru"
+	vhdAbsolutePath = "W2ku"
+)
+
+type VHD struct{}
+
+type vhdFooter struct {
+	Cookie             [8]byte
+	Features           uint32
+	FileFormatVersion  uint32
+	DataOffset         uint64
+	TimeStamp          uint32
+	CreatorApplication [4]byte
+	CreatorVersion     uint32
+	CreatorHostOS      uint32
+	OriginalSize       uint64
+	CurrentSize        uint64
+	DiskGeometry       uint32
+	DiskType           uint32
+	Checksum           uint32
+	UniqueID           [16]byte
+	SavedState         uint8
+	Reserved           [427]byte
+}
+
+type vhdDynamicHeader struct {
+	Cookie            [8]byte
+	DataOffset        uint64
+	TableOffset       uint64
+	HeaderVersion     uint32
+	MaxTableEntries   uint32
+	BlockSize         uint32
+	Checksum          uint32
+	ParentUniqueID    [16]byte
+	ParentTimeStamp   uint32
+	Reserved          uint32
+	ParentUnicodeName [512]byte
+	ParentLocators    [8]vhdParentLocator
+	Reserved2         [256]byte
+}
+
+type vhdParentLocator struct {
+	PlatformCode       [4]byte
+	PlatformDataSpace  uint32
+	PlatformDataLength uint32
+	Reserved           uint32
+	PlatformDataOffset uint64
+}
+
+// vhdImage reads the blocks of dynamic and differencing disks through the block allocation table (BAT).
+type vhdImage struct {
+	r          io.ReaderAt
+	size       int64
+	blockSize  int64
+	bitmapSize int64
+	bat        []uint32
+	parent     *io.SectionReader
+	cache      vm.Cache[string, []byte]
+}
+
+func (VHD) NewReader(rs io.ReadSeeker, cache vm.Cache[string, []byte]) (*io.SectionReader, func(), error) {
+	fileSize, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("seek error: %w", err)
+	}
+	r := newReaderAt(rs)
+
+	footer, err := vhdReadFooter(r, fileSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch footer.DiskType {
+	case vhdFixed:
+		// The data is followed by the footer
+		if int64(footer.CurrentSize) > fileSize-vhdFooterSize {
+			return nil, nil, xerrors.Errorf("VHD size %d exceeds the file", footer.CurrentSize)
+		}
+		return io.NewSectionReader(r, 0, int64(footer.CurrentSize)), noClean, nil
+	case vhdDynamic, vhdDifferencing:
+	default:
+		return nil, nil, xerrors.Errorf("VHD disk type %d: %w", footer.DiskType, vm.ErrUnsupportedType)
+	}
+
+	buf := make([]byte, vhdDynamicHeaderSize)
+	if err = readFull(r, buf, int64(footer.DataOffset)); err != nil {
+		return nil, nil, xerrors.Errorf("VHD dynamic header error: %w", err)
+	}
+	if string(buf[:8]) != vhdDynamicHeaderCookie || !vhdChecksumValid(buf, 36) {
+		return nil, nil, xerrors.New("invalid VHD dynamic header")
+	}
+	var header vhdDynamicHeader
+	if err = binary.Read(bytes.NewReader(buf), binary.BigEndian, &header); err != nil {
+		return nil, nil, xerrors.Errorf("VHD dynamic header decode error: %w", err)
+	}
+
+	img := &vhdImage{
+		r:         r,
+		size:      int64(footer.CurrentSize),
+		blockSize: int64(header.BlockSize),
+		cache:     cacheOrNoCache(cache),
+	}
+	if img.blockSize < vhdSectorSize || img.blockSize&(img.blockSize-1) != 0 {
+		return nil, nil, xerrors.Errorf("invalid VHD block size: %d", img.blockSize)
+	}
+	// The sector bitmap precedes the data of the block, padded to the sector boundary
+	img.bitmapSize = (img.blockSize/vhdSectorSize/8 + vhdSectorSize - 1) / vhdSectorSize * vhdSectorSize
+
+	if int64(header.MaxTableEntries) < (img.size+img.blockSize-1)/img.blockSize {
+		return nil, nil, xerrors.Errorf("VHD BAT too small: %d entries", header.MaxTableEntries)
+	}
+	bat := make([]byte, int64(header.MaxTableEntries)*4)
+	if err = readFull(r, bat, int64(header.TableOffset)); err != nil {
+		return nil, nil, xerrors.Errorf("VHD BAT error: %w", err)
+	}
+	img.bat = make([]uint32, header.MaxTableEntries)
+	for i := range img.bat {
+		img.bat[i] = binary.BigEndian.Uint32(bat[i*4:])
+	}
+
+	clean := noClean
+	if footer.DiskType == vhdDifferencing {
+		parentPath, err := vhdParentPath(r, header)
+		if err != nil {
+			return nil, nil, err
+		}
+		parent, parentClean, err := openParent(rs, parentPath, cache)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("VHD parent disk (%s) error: %w", parentPath, err)
+		}
+		img.parent, clean = parent, parentClean
+	}
+
+	return io.NewSectionReader(img, 0, img.size), clean, nil
+}
+
+// vhdReadFooter reads the footer at the end of the file, or the copy at the beginning of dynamic disks
+func vhdReadFooter(r io.ReaderAt, fileSize int64) (vhdFooter, error) {
+	if fileSize < vhdFooterSize {
+		return vhdFooter{}, vm.ErrInvalidSignature
+	}
+	for _, offset := range []int64{fileSize - vhdFooterSize, 0} {
+		buf := make([]byte, vhdFooterSize)
+		if err := readFull(r, buf, offset); err != nil || string(buf[:8]) != vhdFooterCookie {
+			continue
+		}
+		if !vhdChecksumValid(buf, 64) {
+			return vhdFooter{}, xerrors.New("invalid VHD footer checksum")
+		}
+		var footer vhdFooter
+		if err := binary.Read(bytes.NewReader(buf), binary.BigEndian, &footer); err != nil {
+			return vhdFooter{}, xerrors.Errorf("VHD footer decode error: %w", err)
+		}
+		return footer, nil
+	}
+	return vhdFooter{}, vm.ErrInvalidSignature
+}
+
+// vhdParentPath returns the path of the parent disk from the parent locators.
+// The absolute path and the name are the ones on the Hyper-V host, so the parent is looked up next to the child.
+func vhdParentPath(r io.ReaderAt, header vhdDynamicHeader) (string, error) {
+	paths := make(map[string]string)
+	for _, locator := range header.ParentLocators {
+		code := string(locator.PlatformCode[:])
+		if code != vhdRelativePath && code != vhdAbsolutePath || locator.PlatformDataLength > 64<<10 {
+			continue
+		}
+		buf := make([]byte, locator.PlatformDataLength)
+		if err := readFull(r, buf, int64(locator.PlatformDataOffset)); err != nil {
+			return "", xerrors.Errorf("VHD parent locator error: %w", err)
+		}
+		paths[code] = strings.ReplaceAll(decodeUTF16(buf, binary.LittleEndian), `\`, "/")
+	}
+
+	if p := paths[vhdRelativePath]; p != "" {
+		return windowsPath(p), nil
+	} else if p = paths[vhdAbsolutePath]; p != "" {
+		return path.Base(p), nil
+	} else if name := decodeUTF16(header.ParentUnicodeName[:], binary.BigEndian); name != "" {
+		return path.Base(strings.ReplaceAll(name, `\`, "/")), nil
+	}
+	return "", xerrors.New("VHD parent path not found")
+}
+
+func (img *vhdImage) ReadAt(p []byte, off int64) (int, error) {
+	return readBlocks(p, off, img.size, img.blockSize, img.readBlock)
+}
+
+func (img *vhdImage) readBlock(p []byte, block, offInBlock int64) error {
+	entry := img.bat[block]
+	if entry == vhdUnallocated {
+		return readZeroExtended(img.parent, p, block*img.blockSize+offInBlock)
+	}
+	bitmapOffset := int64(entry) * vhdSectorSize
+	dataOffset := bitmapOffset + img.bitmapSize
+
+	// The sectors of dynamic disks without the bit are zeros in the block
+	if img.parent == nil {
+		return readFull(img.r, p, dataOffset+offInBlock)
+	}
+
+	bitmap, err := img.sectorBitmap(bitmapOffset)
+	if err != nil {
+		return xerrors.Errorf("VHD sector bitmap error: %w", err)
+	}
+	// The bit of the first sector is the MSB
+	present := func(sector int64) bool {
+		return bitmap[sector/8]&(0x80>>(sector%8)) != 0
+	}
+	return readSectorRuns(p, offInBlock, vhdSectorSize, present,
+		func(p []byte, off int64) error {
+			return readFull(img.r, p, dataOffset+off)
+		},
+		func(p []byte, off int64) error {
+			return readZeroExtended(img.parent, p, block*img.blockSize+off)
+		},
+	)
+}
+
+func (img *vhdImage) sectorBitmap(offset int64) ([]byte, error) {
+	key := fmt.Sprintf("vhd:%p:bitmap:%d", img, offset)
+	if bitmap, ok := img.cache.Get(key); ok {
+		return bitmap, nil
+	}
+	bitmap := make([]byte, img.bitmapSize)
+	if err := readFull(img.r, bitmap, offset); err != nil {
+		return nil, err
+	}
+	img.cache.Add(key, bitmap)
+	return bitmap, nil
+}
+
+// vhdChecksumValid verifies the one's complement of the sum of the bytes except the checksum field
+func vhdChecksumValid(buf []byte, checksumOffset int) bool {
+	var sum uint32
+	for i, b := range buf {
+		if i >= checksumOffset && i < checksumOffset+4 {
+			continue
+		}
+		sum += uint32(b)
+	}
+	return ^sum == binary.BigEndian.Uint32(buf[checksumOffset:])
+}
@@ -0,0 +1,195 @@
+package disk_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+	"github.com/aquasecurity/trivy/pkg/fanal/vm/disk"
+)
+
+const vhdBlockSize = 4096 // 8 sectors to keep the images small
+
+type vhdBlock struct {
+	data    []byte
+	sectors []int64 // the sectors in the differencing disk
+}
+
+type vhdImage struct {
+	diskType   uint32
+	size       int64
+	data       []byte // the data of the fixed disk
+	blocks     map[int64]vhdBlock
+	parentPath string // the relative path of the parent disk
+}
+
+func (img vhdImage) write(t *testing.T, filePath string) {
+	footer := make([]byte, 512)
+	copy(footer, "conectix")
+	binary.BigEndian.PutUint32(footer[12:], 0x00010000)
+	binary.BigEndian.PutUint64(footer[16:], 0xFFFFFFFFFFFFFFFF)
+	binary.BigEndian.PutUint64(footer[40:], uint64(img.size))
+	binary.BigEndian.PutUint64(footer[48:], uint64(img.size))
+	binary.BigEndian.PutUint32(footer[60:], img.diskType)
+
+	if img.diskType == 2 {
+		vhdChecksum(footer, 64)
+		require.NoError(t, os.WriteFile(filePath, append(img.data, footer...), 0o600))
+		return
+	}
+
+	// The copy of the footer, the dynamic header, the BAT and the parent locator
+	binary.BigEndian.PutUint64(footer[16:], 512)
+	vhdChecksum(footer, 64)
+	file := append(bytes.Clone(footer), make([]byte, 1024+512+512)...)
+
+	entries := (img.size + vhdBlockSize - 1) / vhdBlockSize
+	header := file[512:1536]
+	copy(header, "cxsparse")
+	binary.BigEndian.PutUint64(header[8:], 0xFFFFFFFFFFFFFFFF)
+	binary.BigEndian.PutUint64(header[16:], 1536)
+	binary.BigEndian.PutUint32(header[24:], 0x00010000)
+	binary.BigEndian.PutUint32(header[28:], uint32(entries))
+	binary.BigEndian.PutUint32(header[32:], vhdBlockSize)
+	if img.parentPath != "" {
+		locator := utf16LE(img.parentPath)
+		copy(header[576:], "W2ru")
+		binary.BigEndian.PutUint32(header[580:], 512)
+		binary.BigEndian.PutUint32(header[584:], uint32(len(locator)))
+		binary.BigEndian.PutUint64(header[592:], 2048)
+		copy(file[2048:], locator)
+	}
+	vhdChecksum(header, 36)
+
+	bat := file[1536 : 1536+512]
+	for i := int64(0); i < entries; i++ {
+		binary.BigEndian.PutUint32(bat[i*4:], 0xFFFFFFFF)
+	}
+	var blocks []byte
+	for block, b := range img.blocks {
+		binary.BigEndian.PutUint32(bat[block*4:], uint32((len(file)+len(blocks))/512))
+		// The sector bitmap padded to 512 bytes precedes the data
+		bitmap := make([]byte, 512)
+		for _, sector := range b.sectors {
+			bitmap[sector/8] |= 0x80 >> (sector % 8)
+		}
+		blocks = append(append(blocks, bitmap...), b.data...)
+	}
+
+	require.NoError(t, os.WriteFile(filePath, bytes.Join([][]byte{file, blocks, footer}, nil), 0o600))
+}
+
+func vhdChecksum(b []byte, offset int) {
+	var sum uint32
+	for _, c := range b {
+		sum += uint32(c)
+	}
+	binary.BigEndian.PutUint32(b[offset:], ^sum)
+}
+
+func TestVHD_NewReader(t *testing.T) {
+	dir := t.TempDir()
+
+	// The parent disk of the differencing disk
+	vhdImage{
+		diskType: 3,
+		size:     2 * vhdBlockSize,
+		blocks: map[int64]vhdBlock{
+			0: {data: fill('p', vhdBlockSize)},
+		},
+	}.write(t, filepath.Join(dir, "base.vhd"))
+
+	tests := []struct {
+		name    string
+		image   vhdImage
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "fixed disk",
+			image: vhdImage{
+				diskType: 2,
+				size:     1024,
+				data:     fill('f', 1024),
+			},
+			want: fill('f', 1024),
+		},
+		{
+			name: "dynamic disk",
+			image: vhdImage{
+				diskType: 3,
+				size:     3*vhdBlockSize + 512,
+				blocks: map[int64]vhdBlock{
+					1: {data: fill('a', vhdBlockSize)},
+					3: {data: fill('b', vhdBlockSize)},
+				},
+			},
+			want: bytes.Join([][]byte{
+				make([]byte, vhdBlockSize),
+				fill('a', vhdBlockSize),
+				make([]byte, vhdBlockSize),
+				fill('b', 512),
+			}, nil),
+		},
+		{
+			name: "differencing disk",
+			image: vhdImage{
+				diskType: 4,
+				size:     3 * vhdBlockSize,
+				blocks: map[int64]vhdBlock{
+					0: {data: fill('c', vhdBlockSize), sectors: []int64{1, 2, 7}},
+				},
+				parentPath: `.\base.vhd`,
+			},
+			want: bytes.Join([][]byte{
+				fill('p', 512),
+				fill('c', 1024),
+				fill('p', 2048),
+				fill('c', 512),
+				make([]byte, 2*vhdBlockSize),
+			}, nil),
+		},
+		{
+			name: "parent disk not found",
+			image: vhdImage{
+				diskType:   4,
+				size:       vhdBlockSize,
+				parentPath: `.\missing.vhd`,
+			},
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name: "unsupported disk type",
+			image: vhdImage{
+				diskType: 5,
+				size:     vhdBlockSize,
+			},
+			wantErr: vm.ErrUnsupportedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(dir, "disk.vhd")
+			tt.image.write(t, filePath)
+			f, err := os.Open(filePath)
+			require.NoError(t, err)
+			defer f.Close()
+
+			sr, clean, err := disk.VHD{}.NewReader(f, nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			defer clean()
+
+			assert.Equal(t, tt.want, readAll(t, sr))
+		})
+	}
+}
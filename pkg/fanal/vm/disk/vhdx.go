@@ -0,0 +1,446 @@
+package disk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+)
+
+// cf. https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-vhdx/83e061f8-f6e2-4de1-91bd-5d518a43d477
+const (
+	vhdxSignature = "vhdxfile"
+
+	vhdxHeader1Offset      = 64 << 10
+	vhdxHeader2Offset      = 128 << 10
+	vhdxRegionTable1Offset = 192 << 10
+	vhdxRegionTable2Offset = 256 << 10
+	vhdxStructureSize      = 64 << 10
+
+	// Payload block states
+	vhdxPayloadNotPresent       = 0
+	vhdxPayloadFullyPresent     = 6
+	vhdxPayloadPartiallyPresent = 7
+
+	vhdxSectorBitmapPresent = 6
+	vhdxSectorBitmapSize    = 1 << 20
+
+	vhdxHasParent = 1 << 1
+
+	// A chunk has the payload blocks described by a sector bitmap block
+	vhdxSectorsPerChunk = 1 << 23
+)
+
+var (
+	vhdxBATRegion       = mustGUID("2DC27766-F623-4200-9D64-115E9BFD4A08")
+	vhdxMetadataRegion  = mustGUID("8B7CA206-4790-4B9A-B8FE-575F050F886E")
+	vhdxFileParameters  = mustGUID("CAA16737-FA36-4D43-B3B6-33F0AA44E76B")
+	vhdxVirtualDiskSize = mustGUID("2FA54224-CD1B-4876-B211-5DBED83BF4B8")
+	vhdxLogicalSector   = mustGUID("8141BF1D-A96F-4709-BA47-F233A8FAAB5F")
+	vhdxParentLocator   = mustGUID("A8D35F2D-B30B-454D-ABF7-D3D84834AB0C")
+
+	castagnoli = crc32.MakeTable(crc32.Castagnoli)
+)
+
+type VHDX struct{}
+
+type vhdxHeader struct {
+	Signature      [4]byte
+	Checksum       uint32
+	SequenceNumber uint64
+	FileWriteGUID  [16]byte
+	DataWriteGUID  [16]byte
+	LogGUID        [16]byte
+	LogVersion     uint16
+	Version        uint16
+	LogLength      uint32
+	LogOffset      uint64
+}
+
+type vhdxRegionTableHeader struct {
+	Signature  [4]byte
+	Checksum   uint32
+	EntryCount uint32
+	Reserved   uint32
+}
+
+type vhdxRegionTableEntry struct {
+	GUID       [16]byte
+	FileOffset uint64
+	Length     uint32
+	Required   uint32
+}
+
+type vhdxMetadataTableHeader struct {
+	Signature  [8]byte
+	Reserved   uint16
+	EntryCount uint16
+	Reserved2  [20]byte
+}
+
+type vhdxMetadataTableEntry struct {
+	ItemID    [16]byte
+	Offset    uint32
+	Length    uint32
+	Flags     uint32
+	Reserved2 uint32
+}
+
+// vhdxImage reads the payload blocks through the block allocation table (BAT).
+// The blocks of differencing disks which are not present are read from the parent disk.
+type vhdxImage struct {
+	r                 io.ReaderAt
+	size              int64
+	blockSize         int64
+	logicalSectorSize int64
+	chunkRatio        int64
+	bat               []uint64
+	parent            *io.SectionReader
+	cache             vm.Cache[string, []byte]
+}
+
+func (VHDX) NewReader(rs io.ReadSeeker, cache vm.Cache[string, []byte]) (*io.SectionReader, func(), error) {
+	r := newReaderAt(rs)
+
+	signature := make([]byte, len(vhdxSignature))
+	if _, err := r.ReadAt(signature, 0); err != nil || string(signature) != vhdxSignature {
+		return nil, nil, vm.ErrInvalidSignature
+	}
+
+	header, err := vhdxCurrentHeader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.LogGUID != [16]byte{} {
+		// The log must be replayed, which is done by Hyper-V when the disk is attached
+		return nil, nil, xerrors.Errorf("VHDX with the log to be replayed: %w", vm.ErrUnsupportedType)
+	}
+
+	regions, err := vhdxRegions(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	batRegion, ok := regions[vhdxBATRegion]
+	if !ok {
+		return nil, nil, xerrors.New("VHDX BAT region not found")
+	}
+	metadataRegion, ok := regions[vhdxMetadataRegion]
+	if !ok {
+		return nil, nil, xerrors.New("VHDX metadata region not found")
+	}
+
+	metadata, err := vhdxMetadata(r, metadataRegion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	img := &vhdxImage{
+		r:     r,
+		cache: cacheOrNoCache(cache),
+	}
+	for _, item := range []struct {
+		guid [16]byte
+		size int
+	}{
+		{vhdxFileParameters, 8},
+		{vhdxVirtualDiskSize, 8},
+		{vhdxLogicalSector, 4},
+	} {
+		if len(metadata[item.guid]) < item.size {
+			return nil, nil, xerrors.Errorf("VHDX metadata %x not found", item.guid)
+		}
+	}
+	img.blockSize = int64(binary.LittleEndian.Uint32(metadata[vhdxFileParameters]))
+	hasParent := binary.LittleEndian.Uint32(metadata[vhdxFileParameters][4:])&vhdxHasParent != 0
+	img.size = int64(binary.LittleEndian.Uint64(metadata[vhdxVirtualDiskSize]))
+	img.logicalSectorSize = int64(binary.LittleEndian.Uint32(metadata[vhdxLogicalSector]))
+
+	switch {
+	case img.blockSize < 1<<20 || img.blockSize > 256<<20 || img.blockSize&(img.blockSize-1) != 0:
+		return nil, nil, xerrors.Errorf("invalid VHDX block size: %d", img.blockSize)
+	case img.logicalSectorSize != 512 && img.logicalSectorSize != 4096:
+		return nil, nil, xerrors.Errorf("invalid VHDX logical sector size: %d", img.logicalSectorSize)
+	case img.size < 0:
+		return nil, nil, xerrors.Errorf("invalid VHDX virtual disk size: %d", img.size)
+	}
+	img.chunkRatio = vhdxSectorsPerChunk * img.logicalSectorSize / img.blockSize
+
+	// The BAT has the payload block entries interleaved with the sector bitmap block entries
+	payloadBlocks := (img.size + img.blockSize - 1) / img.blockSize
+	batEntries := payloadBlocks + (payloadBlocks-1)/img.chunkRatio
+	if hasParent {
+		batEntries = (payloadBlocks + img.chunkRatio - 1) / img.chunkRatio * (img.chunkRatio + 1)
+	}
+	if batEntries*8 > int64(batRegion.Length) {
+		return nil, nil, xerrors.Errorf("VHDX BAT region too small: %d entries", batEntries)
+	}
+	bat := make([]byte, batEntries*8)
+	if err = readFull(r, bat, int64(batRegion.FileOffset)); err != nil {
+		return nil, nil, xerrors.Errorf("VHDX BAT error: %w", err)
+	}
+	img.bat = make([]uint64, batEntries)
+	for i := range img.bat {
+		img.bat[i] = binary.LittleEndian.Uint64(bat[i*8:])
+	}
+
+	clean := noClean
+	if hasParent {
+		parentPath, err := vhdxParentPath(metadata[vhdxParentLocator])
+		if err != nil {
+			return nil, nil, err
+		}
+		parent, parentClean, err := openParent(rs, parentPath, cache)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("VHDX parent disk (%s) error: %w", parentPath, err)
+		}
+		img.parent, clean = parent, parentClean
+	}
+
+	return io.NewSectionReader(img, 0, img.size), clean, nil
+}
+
+// vhdxCurrentHeader returns the valid header with the greater sequence number
+func vhdxCurrentHeader(r io.ReaderAt) (vhdxHeader, error) {
+	var current *vhdxHeader
+	for _, offset := range []int64{vhdxHeader1Offset, vhdxHeader2Offset} {
+		buf := make([]byte, 4<<10)
+		if err := readFull(r, buf, offset); err != nil || string(buf[:4]) != "head" || !vhdxChecksumValid(buf, 4) {
+			continue
+		}
+		var header vhdxHeader
+		if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &header); err != nil {
+			continue
+		}
+		if current == nil || header.SequenceNumber > current.SequenceNumber {
+			current = &header
+		}
+	}
+	if current == nil {
+		return vhdxHeader{}, xerrors.New("VHDX header not found")
+	}
+	if current.Version != 1 {
+		return vhdxHeader{}, xerrors.Errorf("VHDX version %d: %w", current.Version, vm.ErrUnsupportedType)
+	}
+	return *current, nil
+}
+
+// vhdxRegions returns the regions in the valid region table
+func vhdxRegions(r io.ReaderAt) (map[[16]byte]vhdxRegionTableEntry, error) {
+	for _, offset := range []int64{vhdxRegionTable1Offset, vhdxRegionTable2Offset} {
+		buf := make([]byte, vhdxStructureSize)
+		if err := readFull(r, buf, offset); err != nil || string(buf[:4]) != "regi" || !vhdxChecksumValid(buf, 4) {
+			continue
+		}
+
+		br := bytes.NewReader(buf)
+		var header vhdxRegionTableHeader
+		if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+			continue
+		}
+		// The region table has at most 2047 entries
+		if header.EntryCount > 2047 {
+			continue
+		}
+		entries := make([]vhdxRegionTableEntry, header.EntryCount)
+		if err := binary.Read(br, binary.LittleEndian, entries); err != nil {
+			continue
+		}
+
+		regions := make(map[[16]byte]vhdxRegionTableEntry)
+		for _, entry := range entries {
+			if entry.GUID != vhdxBATRegion && entry.GUID != vhdxMetadataRegion && entry.Required&1 != 0 {
+				return nil, xerrors.Errorf("VHDX unknown required region %x: %w", entry.GUID, vm.ErrUnsupportedType)
+			}
+			regions[entry.GUID] = entry
+		}
+		return regions, nil
+	}
+	return nil, xerrors.New("VHDX region table not found")
+}
+
+// vhdxMetadata returns the metadata items in the metadata region
+func vhdxMetadata(r io.ReaderAt, region vhdxRegionTableEntry) (map[[16]byte][]byte, error) {
+	buf := make([]byte, vhdxStructureSize)
+	if err := readFull(r, buf, int64(region.FileOffset)); err != nil {
+		return nil, xerrors.Errorf("VHDX metadata table error: %w", err)
+	}
+
+	br := bytes.NewReader(buf)
+	var header vhdxMetadataTableHeader
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return nil, xerrors.Errorf("VHDX metadata table error: %w", err)
+	}
+	if string(header.Signature[:]) != "metadata" || header.EntryCount > 2047 {
+		return nil, xerrors.New("invalid VHDX metadata table")
+	}
+	entries := make([]vhdxMetadataTableEntry, header.EntryCount)
+	if err := binary.Read(br, binary.LittleEndian, entries); err != nil {
+		return nil, xerrors.Errorf("VHDX metadata table error: %w", err)
+	}
+
+	metadata := make(map[[16]byte][]byte)
+	for _, entry := range entries {
+		if entry.Offset == 0 || entry.Length == 0 {
+			continue
+		}
+		if uint64(entry.Offset)+uint64(entry.Length) > uint64(region.Length) {
+			return nil, xerrors.Errorf("VHDX metadata item %x out of the region", entry.ItemID)
+		}
+		item := make([]byte, entry.Length)
+		if err := readFull(r, item, int64(region.FileOffset)+int64(entry.Offset)); err != nil {
+			return nil, xerrors.Errorf("VHDX metadata item error: %w", err)
+		}
+		metadata[entry.ItemID] = item
+	}
+	return metadata, nil
+}
+
+// vhdxParentPath returns the path of the parent disk from the parent locator.
+func vhdxParentPath(locator []byte) (string, error) {
+	// Locator type (16 bytes), reserved (2 bytes) and the number of the key-value pairs (2 bytes)
+	if len(locator) < 20 {
+		return "", xerrors.New("VHDX parent locator not found")
+	}
+	count := int(binary.LittleEndian.Uint16(locator[18:]))
+
+	entries := make(map[string]string)
+	for i := 0; i < count; i++ {
+		e := 20 + i*12
+		if e+12 > len(locator) {
+			return "", xerrors.New("invalid VHDX parent locator")
+		}
+		keyOffset, valueOffset := int(binary.LittleEndian.Uint32(locator[e:])), int(binary.LittleEndian.Uint32(locator[e+4:]))
+		keyLength, valueLength := int(binary.LittleEndian.Uint16(locator[e+8:])), int(binary.LittleEndian.Uint16(locator[e+10:]))
+		if keyOffset+keyLength > len(locator) || valueOffset+valueLength > len(locator) {
+			return "", xerrors.New("invalid VHDX parent locator")
+		}
+		key := decodeUTF16(locator[keyOffset:keyOffset+keyLength], binary.LittleEndian)
+		entries[key] = decodeUTF16(locator[valueOffset:valueOffset+valueLength], binary.LittleEndian)
+	}
+
+	if p := entries["relative_path"]; p != "" {
+		return windowsPath(p), nil
+	}
+	// The absolute path is the one on the Hyper-V host, so the parent is looked up next to the child
+	if p := entries["absolute_win32_path"]; p != "" {
+		return path.Base(strings.ReplaceAll(p, `\`, "/")), nil
+	}
+	return "", xerrors.New("VHDX parent path not found")
+}
+
+func (img *vhdxImage) ReadAt(p []byte, off int64) (int, error) {
+	return readBlocks(p, off, img.size, img.blockSize, img.readBlock)
+}
+
+func (img *vhdxImage) readBlock(p []byte, block, offInBlock int64) error {
+	entry := img.bat[block+block/img.chunkRatio]
+	fileOffset := int64(entry>>20) << 20
+
+	switch entry & 0x7 {
+	case vhdxPayloadFullyPresent:
+		return readFull(img.r, p, fileOffset+offInBlock)
+	case vhdxPayloadPartiallyPresent:
+		if img.parent == nil {
+			return xerrors.New("VHDX partially present block without parent")
+		}
+		return img.readPartialBlock(p, block, offInBlock, fileOffset)
+	case vhdxPayloadNotPresent:
+		if img.parent != nil {
+			return readZeroExtended(img.parent, p, block*img.blockSize+offInBlock)
+		}
+	}
+	// Zero, unmapped and undefined blocks
+	clear(p)
+	return nil
+}
+
+// readPartialBlock reads the sectors of the block in the differencing disk according to the sector bitmap.
+// The sectors without the bit are read from the parent disk.
+func (img *vhdxImage) readPartialBlock(p []byte, block, offInBlock, fileOffset int64) error {
+	bitmap, err := img.sectorBitmap(block / img.chunkRatio)
+	if err != nil {
+		return xerrors.Errorf("VHDX sector bitmap error: %w", err)
+	}
+
+	// The sector bitmap block describes the sectors of the chunk, where the bit of the first sector is the LSB.
+	firstSector := (block % img.chunkRatio) * (img.blockSize / img.logicalSectorSize)
+	present := func(sector int64) bool {
+		sector += firstSector
+		return bitmap[sector/8]&(1<<(sector%8)) != 0
+	}
+	return readSectorRuns(p, offInBlock, img.logicalSectorSize, present,
+		func(p []byte, off int64) error {
+			return readFull(img.r, p, fileOffset+off)
+		},
+		func(p []byte, off int64) error {
+			return readZeroExtended(img.parent, p, block*img.blockSize+off)
+		},
+	)
+}
+
+func (img *vhdxImage) sectorBitmap(chunk int64) ([]byte, error) {
+	key := fmt.Sprintf("vhdx:%p:bitmap:%d", img, chunk)
+	if bitmap, ok := img.cache.Get(key); ok {
+		return bitmap, nil
+	}
+
+	bitmap := make([]byte, vhdxSectorBitmapSize)
+	entry := img.bat[(chunk+1)*(img.chunkRatio+1)-1]
+	if entry&0x7 == vhdxSectorBitmapPresent {
+		if err := readFull(img.r, bitmap, int64(entry>>20)<<20); err != nil {
+			return nil, err
+		}
+	}
+	img.cache.Add(key, bitmap)
+	return bitmap, nil
+}
+
+// vhdxChecksumValid verifies CRC-32C of the structure, calculated with the checksum field as zero
+func vhdxChecksumValid(buf []byte, checksumOffset int) bool {
+	want := binary.LittleEndian.Uint32(buf[checksumOffset:])
+	b := bytes.Clone(buf)
+	binary.LittleEndian.PutUint32(b[checksumOffset:], 0)
+	return crc32.Checksum(b, castagnoli) == want
+}
+
+// mustGUID converts the GUID string to the bytes in the mixed-endian format of Windows
+func mustGUID(s string) [16]byte {
+	var b [16]byte
+	var d1 uint32
+	var d2, d3, d4 uint16
+	var d5 uint64
+	if _, err := fmt.Sscanf(s, "%08X-%04X-%04X-%04X-%012X", &d1, &d2, &d3, &d4, &d5); err != nil {
+		panic(err)
+	}
+	binary.LittleEndian.PutUint32(b[0:], d1)
+	binary.LittleEndian.PutUint16(b[4:], d2)
+	binary.LittleEndian.PutUint16(b[6:], d3)
+	binary.BigEndian.PutUint16(b[8:], d4)
+	for i := 0; i < 6; i++ {
+		b[10+i] = byte(d5 >> (8 * (5 - i)))
+	}
+	return b
+}
+
+func decodeUTF16(b []byte, order binary.ByteOrder) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = order.Uint16(b[i*2:])
+	}
+	// Trailing NULs are the padding
+	return strings.TrimRight(string(utf16.Decode(u)), "\x00")
+}
+
+// windowsPath converts the relative path on Windows, e.g. ".\\base.vhdx"
+func windowsPath(p string) string {
+	return filepath.FromSlash(strings.ReplaceAll(p, `\`, "/"))
+}
@@ -0,0 +1,272 @@
+package disk_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+	"github.com/aquasecurity/trivy/pkg/fanal/vm/disk"
+)
+
+const (
+	vhdxBlockSize  = 1 << 20 // the minimum block size
+	vhdxChunkRatio = 4096    // 2^23 sectors * 512 bytes / 1 MB
+)
+
+type vhdxBlock struct {
+	state   uint64
+	data    []byte
+	sectors []int64 // the present sectors of the partially present block
+}
+
+type vhdxImage struct {
+	size       int64
+	blocks     map[int64]vhdxBlock
+	parentKeys map[string]string // the key-value pairs of the parent locator
+	logGUID    bool
+}
+
+// write writes the image with the BAT region at 1 MB, the metadata region at 2 MB and the blocks after them.
+func (img vhdxImage) write(t *testing.T, filePath string) {
+	file := make([]byte, 3<<20)
+	copy(file, "vhdxfile")
+
+	for i, offset := range []int{64 << 10, 128 << 10} {
+		header := file[offset : offset+4<<10]
+		copy(header, "head")
+		binary.LittleEndian.PutUint64(header[8:], uint64(i+1))
+		if img.logGUID {
+			copy(header[40:], vhdxGUID("1E1DC3B1-1F4C-4C8B-9A8F-3F6A4B5A1D2E"))
+		}
+		binary.LittleEndian.PutUint16(header[66:], 1)
+		vhdxChecksum(header)
+	}
+
+	for _, offset := range []int{192 << 10, 256 << 10} {
+		table := file[offset : offset+64<<10]
+		copy(table, "regi")
+		binary.LittleEndian.PutUint32(table[8:], 2)
+		for i, region := range []struct {
+			guid   string
+			offset uint64
+		}{
+			{"2DC27766-F623-4200-9D64-115E9BFD4A08", 1 << 20}, // BAT
+			{"8B7CA206-4790-4B9A-B8FE-575F050F886E", 2 << 20}, // Metadata
+		} {
+			entry := table[16+i*32:]
+			copy(entry, vhdxGUID(region.guid))
+			binary.LittleEndian.PutUint64(entry[16:], region.offset)
+			binary.LittleEndian.PutUint32(entry[24:], 1<<20)
+			binary.LittleEndian.PutUint32(entry[28:], 1)
+		}
+		vhdxChecksum(table)
+	}
+
+	var flags uint32
+	if img.parentKeys != nil {
+		flags = 1 << 1
+	}
+	items := []struct {
+		guid string
+		data []byte
+	}{
+		{"CAA16737-FA36-4D43-B3B6-33F0AA44E76B", binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, vhdxBlockSize), flags)},
+		{"2FA54224-CD1B-4876-B211-5DBED83BF4B8", binary.LittleEndian.AppendUint64(nil, uint64(img.size))},
+		{"8141BF1D-A96F-4709-BA47-F233A8FAAB5F", binary.LittleEndian.AppendUint32(nil, 512)},
+	}
+	if img.parentKeys != nil {
+		items = append(items, struct {
+			guid string
+			data []byte
+		}{"A8D35F2D-B30B-454D-ABF7-D3D84834AB0C", vhdxParentLocator(img.parentKeys)})
+	}
+	metadata := file[2<<20 : 3<<20]
+	copy(metadata, "metadata")
+	binary.LittleEndian.PutUint16(metadata[10:], uint16(len(items)))
+	itemOffset := 64 << 10
+	for i, item := range items {
+		entry := metadata[32+i*32:]
+		copy(entry, vhdxGUID(item.guid))
+		binary.LittleEndian.PutUint32(entry[16:], uint32(itemOffset))
+		binary.LittleEndian.PutUint32(entry[20:], uint32(len(item.data)))
+		itemOffset += copy(metadata[itemOffset:], item.data)
+	}
+
+	// The BAT entries are written after appending the blocks, which reallocates the file
+	bat := make([]byte, 1<<20)
+	var bitmap []byte
+	for block, b := range img.blocks {
+		entry := b.state
+		if b.data != nil {
+			entry |= uint64(len(file))
+			file = append(file, b.data...)
+		}
+		binary.LittleEndian.PutUint64(bat[(block+block/vhdxChunkRatio)*8:], entry)
+
+		for _, sector := range b.sectors {
+			if bitmap == nil {
+				bitmap = make([]byte, 1<<20)
+			}
+			sector += (block % vhdxChunkRatio) * (vhdxBlockSize / 512)
+			bitmap[sector/8] |= 1 << (sector % 8)
+		}
+	}
+	if bitmap != nil {
+		// The sector bitmap block of the first chunk
+		binary.LittleEndian.PutUint64(bat[vhdxChunkRatio*8:], uint64(len(file))|6)
+		file = append(file, bitmap...)
+	}
+	copy(file[1<<20:], bat)
+
+	require.NoError(t, os.WriteFile(filePath, file, 0o600))
+}
+
+// vhdxGUID returns the GUID in the mixed-endian format of Windows
+func vhdxGUID(s string) []byte {
+	b := uuid.MustParse(s)
+	binary.LittleEndian.PutUint32(b[0:], binary.BigEndian.Uint32(b[0:]))
+	binary.LittleEndian.PutUint16(b[4:], binary.BigEndian.Uint16(b[4:]))
+	binary.LittleEndian.PutUint16(b[6:], binary.BigEndian.Uint16(b[6:]))
+	return b[:]
+}
+
+func vhdxChecksum(b []byte) {
+	binary.LittleEndian.PutUint32(b[4:], crc32.Checksum(b, crc32.MakeTable(crc32.Castagnoli)))
+}
+
+func vhdxParentLocator(keys map[string]string) []byte {
+	locator := make([]byte, 20+len(keys)*12)
+	copy(locator, vhdxGUID("B04AEFB7-D19E-4A81-B789-25B8E9445913"))
+	binary.LittleEndian.PutUint16(locator[18:], uint16(len(keys)))
+	var i int
+	for key, value := range keys {
+		entry := locator[20+i*12:]
+		k, v := utf16LE(key), utf16LE(value)
+		binary.LittleEndian.PutUint32(entry, uint32(len(locator)))
+		binary.LittleEndian.PutUint32(entry[4:], uint32(len(locator)+len(k)))
+		binary.LittleEndian.PutUint16(entry[8:], uint16(len(k)))
+		binary.LittleEndian.PutUint16(entry[10:], uint16(len(v)))
+		locator = append(append(locator, k...), v...)
+		i++
+	}
+	return locator
+}
+
+func utf16LE(s string) []byte {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, u)
+	}
+	return b
+}
+
+func TestVHDX_NewReader(t *testing.T) {
+	dir := t.TempDir()
+
+	// The parent disk of the differencing disks
+	vhdxImage{
+		size: 2 << 20,
+		blocks: map[int64]vhdxBlock{
+			0: {state: 6, data: fill('p', vhdxBlockSize)},
+			1: {state: 6, data: fill('q', vhdxBlockSize)},
+		},
+	}.write(t, filepath.Join(dir, "base.vhdx"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base.raw"), fill('r', 1<<20), 0o600))
+
+	partial := append(fill('c', 1024), make([]byte, vhdxBlockSize-1024)...)
+
+	tests := []struct {
+		name    string
+		image   vhdxImage
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "dynamic disk",
+			image: vhdxImage{
+				size: 3<<20 + 512,
+				blocks: map[int64]vhdxBlock{
+					0: {state: 6, data: fill('a', vhdxBlockSize)},
+					2: {state: 3}, // zero
+					3: {state: 6, data: fill('d', vhdxBlockSize)},
+				},
+			},
+			want: bytes.Join([][]byte{
+				fill('a', 1<<20),
+				make([]byte, 2<<20),
+				fill('d', 512),
+			}, nil),
+		},
+		{
+			name: "differencing disk",
+			image: vhdxImage{
+				size: 3 << 20,
+				blocks: map[int64]vhdxBlock{
+					0: {state: 7, data: partial, sectors: []int64{0, 1}},
+					1: {state: 7, data: partial, sectors: []int64{2047}},
+				},
+				parentKeys: map[string]string{
+					"parent_linkage": "{83ba3e74-3fd3-4bbc-9a4c-3a6a4a6f3d2e}",
+					"relative_path":  `.\base.vhdx`,
+				},
+			},
+			want: bytes.Join([][]byte{
+				fill('c', 1024),
+				fill('p', 1<<20-1024),
+				fill('q', 1<<20-512),
+				make([]byte, 512), // the sector in the child
+				make([]byte, 1<<20),
+			}, nil),
+		},
+		{
+			name: "raw parent disk on the host",
+			image: vhdxImage{
+				size: 2 << 20,
+				blocks: map[int64]vhdxBlock{
+					1: {state: 6, data: fill('b', vhdxBlockSize)},
+				},
+				parentKeys: map[string]string{
+					"absolute_win32_path": `C:\Hyper-V\base.raw`,
+				},
+			},
+			want: append(fill('r', 1<<20), fill('b', 1<<20)...),
+		},
+		{
+			name: "log to be replayed",
+			image: vhdxImage{
+				size:    1 << 20,
+				logGUID: true,
+			},
+			wantErr: vm.ErrUnsupportedType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(dir, "disk.vhdx")
+			tt.image.write(t, filePath)
+			f, err := os.Open(filePath)
+			require.NoError(t, err)
+			defer f.Close()
+
+			sr, clean, err := disk.VHDX{}.NewReader(f, nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			defer clean()
+
+			assert.True(t, bytes.Equal(tt.want, readAll(t, sr)))
+		})
+	}
+}
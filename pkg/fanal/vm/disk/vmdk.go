@@ -12,25 +12,25 @@ import (
 
 type VMDK struct{}
 
-func (VMDK) NewReader(rs io.ReadSeeker, cache vm.Cache[string, []byte]) (*io.SectionReader, error) {
+func (VMDK) NewReader(rs io.ReadSeeker, cache vm.Cache[string, []byte]) (*io.SectionReader, func(), error) {
 	if _, err := rs.Seek(0, io.SeekStart); err != nil {
-		return nil, xerrors.Errorf("seek error: %w", err)
+		return nil, nil, xerrors.Errorf("seek error: %w", err)
 	}
 
-	if _, err := vmdk.Check(rs); err != nil {
-		return nil, vm.ErrInvalidSignature
+	if ok, err := vmdk.Check(rs); err != nil || !ok {
+		return nil, nil, vm.ErrInvalidSignature
 	}
 
 	if _, err := rs.Seek(0, io.SeekStart); err != nil {
-		return nil, xerrors.Errorf("seek error: %w", err)
+		return nil, nil, xerrors.Errorf("seek error: %w", err)
 	}
 
 	reader, err := vmdk.Open(rs, cache)
 	if err != nil {
 		if errors.Is(err, vmdk.ErrUnSupportedType) {
-			return nil, xerrors.Errorf("%s: %w", err.Error(), vm.ErrUnsupportedType)
+			return nil, nil, xerrors.Errorf("%s: %w", err.Error(), vm.ErrUnsupportedType)
 		}
-		return nil, xerrors.Errorf("failed to open vmdk: %w", err)
+		return nil, nil, xerrors.Errorf("failed to open vmdk: %w", err)
 	}
-	return reader, nil
+	return reader, noClean, nil
 }
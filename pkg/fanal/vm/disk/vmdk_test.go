@@ -31,7 +31,7 @@ func TestVMDK_NewReader(t *testing.T) {
 			require.NoError(t, err)
 			defer f.Close()
 
-			_, err = v.NewReader(f, nil)
+			_, _, err = v.NewReader(f, nil)
 			if err == nil {
 				assert.Fail(t, "required error test")
 			}
@@ -36,7 +36,6 @@ func New(sr io.SectionReader) (fs.FS, func(), error) {
 	clean = lruCache.Purge
 
 	for _, filesystem := range filesystems {
-		// TODO: implement LVM handler
 		fsys, err := filesystem.New(sr, lruCache)
 		if err != nil {
 			if errors.Is(err, ErrInvalidHeader) {
@@ -0,0 +1,155 @@
+package luks
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1" // nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/xts"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+)
+
+var hashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+func hashFunc(name string) (func() hash.Hash, error) {
+	h, ok := hashes[strings.ToLower(name)]
+	if !ok {
+		return nil, xerrors.Errorf("hash %q: %w", name, vm.ErrUnsupportedType)
+	}
+	return h, nil
+}
+
+// sectorCipher decrypts the sectors encrypted by dm-crypt, where the IV is derived from the sector number
+type sectorCipher interface {
+	decrypt(p []byte, sector uint64)
+}
+
+// newSectorCipher returns the cipher of the encryption in the format of dm-crypt, e.g. "aes-xts-plain64".
+// AES in the XTS and CBC modes is supported, which covers the defaults of cryptsetup.
+func newSectorCipher(encryption string, key []byte) (sectorCipher, error) {
+	name, mode, _ := strings.Cut(encryption, "-")
+	chainMode, ivMode, _ := strings.Cut(mode, "-")
+	if name != "aes" {
+		return nil, xerrors.Errorf("cipher %q: %w", encryption, vm.ErrUnsupportedType)
+	}
+
+	var truncated bool
+	switch ivMode {
+	case "plain64":
+	case "plain":
+		// The sector number is truncated to 32 bits
+		truncated = true
+	default:
+		if chainMode != "cbc" || !strings.HasPrefix(ivMode, "essiv:") {
+			return nil, xerrors.Errorf("IV mode %q: %w", ivMode, vm.ErrUnsupportedType)
+		}
+	}
+
+	switch chainMode {
+	case "xts":
+		c, err := xts.NewCipher(aes.NewCipher, key)
+		if err != nil {
+			return nil, xerrors.Errorf("XTS cipher error: %w", err)
+		}
+		return &xtsCipher{
+			cipher:    c,
+			truncated: truncated,
+		}, nil
+	case "cbc":
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, xerrors.Errorf("AES cipher error: %w", err)
+		}
+		c := &cbcCipher{
+			block:     block,
+			truncated: truncated,
+		}
+		if hashName, ok := strings.CutPrefix(ivMode, "essiv:"); ok {
+			// ESSIV encrypts the sector number with the hash of the key
+			h, err := hashFunc(hashName)
+			if err != nil {
+				return nil, err
+			}
+			salt := h()
+			salt.Write(key)
+			if c.essiv, err = aes.NewCipher(salt.Sum(nil)); err != nil {
+				return nil, xerrors.Errorf("ESSIV cipher error: %w", err)
+			}
+		}
+		return c, nil
+	}
+	return nil, xerrors.Errorf("cipher mode %q: %w", chainMode, vm.ErrUnsupportedType)
+}
+
+type xtsCipher struct {
+	cipher    *xts.Cipher
+	truncated bool
+}
+
+func (c *xtsCipher) decrypt(p []byte, sector uint64) {
+	if c.truncated {
+		sector &= 0xffffffff
+	}
+	c.cipher.Decrypt(p, p, sector)
+}
+
+type cbcCipher struct {
+	block     cipher.Block
+	essiv     cipher.Block
+	truncated bool
+}
+
+func (c *cbcCipher) decrypt(p []byte, sector uint64) {
+	if c.truncated {
+		sector &= 0xffffffff
+	}
+	iv := make([]byte, aes.BlockSize)
+	binary.LittleEndian.PutUint64(iv, sector)
+	if c.essiv != nil {
+		c.essiv.Encrypt(iv, iv)
+	}
+	cipher.NewCBCDecrypter(c.block, iv).CryptBlocks(p, p)
+}
+
+// afMerge merges the stripes split by the anti-forensic splitter into the key
+func afMerge(stripes []byte, keySize, stripeCount int, h func() hash.Hash) []byte {
+	d := make([]byte, keySize)
+	for i := 0; i < stripeCount-1; i++ {
+		xorBytes(d, stripes[i*keySize:])
+		d = diffuse(d, h)
+	}
+	xorBytes(d, stripes[(stripeCount-1)*keySize:])
+	return d
+}
+
+// diffuse hashes each block of the digest size, with the block number as the prefix
+func diffuse(b []byte, h func() hash.Hash) []byte {
+	digest := h()
+	size := digest.Size()
+	out := make([]byte, 0, len(b))
+	for i := 0; i*size < len(b); i++ {
+		block := b[i*size : min((i+1)*size, len(b))]
+		digest.Reset()
+		_ = binary.Write(digest, binary.BigEndian, uint32(i))
+		digest.Write(block)
+		out = append(out, digest.Sum(nil)[:len(block)]...)
+	}
+	return out
+}
+
+func xorBytes(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
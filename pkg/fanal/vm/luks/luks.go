@@ -0,0 +1,139 @@
+package luks
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+)
+
+// cf. https://gitlab.com/cryptsetup/cryptsetup/-/wikis/Specification
+const (
+	magic      = "LUKS\xba\xbe"
+	sectorSize = 512
+	magicSize  = 6
+)
+
+// ErrInvalidKey is returned when none of the keys unlocks the volume
+var ErrInvalidKey = xerrors.New("no key slot is unlocked by the passphrase or the key file")
+
+// IsLUKS returns true if the volume has the LUKS header
+func IsLUKS(r io.ReaderAt) bool {
+	buf := make([]byte, magicSize)
+	if _, err := r.ReadAt(buf, 0); err != nil {
+		return false
+	}
+	return string(buf) == magic
+}
+
+// Open unlocks the LUKS volume with the keys, i.e. the passphrases or the contents of the key files,
+// and returns the decrypted data of the volume.
+func Open(sr *io.SectionReader, keys [][]byte) (*io.SectionReader, error) {
+	buf := make([]byte, magicSize+2)
+	if _, err := sr.ReadAt(buf, 0); err != nil || string(buf[:magicSize]) != magic {
+		return nil, vm.ErrInvalidSignature
+	}
+	if len(keys) == 0 {
+		return nil, xerrors.Errorf("passphrase required: %w", ErrInvalidKey)
+	}
+
+	switch version := binary.BigEndian.Uint16(buf[magicSize:]); version {
+	case 1:
+		return openLUKS1(sr, keys)
+	case 2:
+		return openLUKS2(sr, keys)
+	default:
+		return nil, xerrors.Errorf("LUKS version %d: %w", version, vm.ErrUnsupportedType)
+	}
+}
+
+// unlockKeySlot decrypts the key material in the key slot with the key derived from the passphrase,
+// and merges the anti-forensic stripes into the master key
+func unlockKeySlot(r io.ReaderAt, slot keySlot, derivedKey []byte) ([]byte, error) {
+	c, err := newSectorCipher(slot.encryption, derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	h, err := hashFunc(slot.afHash)
+	if err != nil {
+		return nil, err
+	}
+
+	size := slot.keySize * slot.stripes
+	material := make([]byte, (size+sectorSize-1)/sectorSize*sectorSize)
+	if n, err := r.ReadAt(material, slot.offset); n != len(material) {
+		if err == nil || errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, xerrors.Errorf("LUKS key material read error: %w", err)
+	}
+	for i := 0; i < len(material)/sectorSize; i++ {
+		c.decrypt(material[i*sectorSize:(i+1)*sectorSize], uint64(i))
+	}
+	return afMerge(material[:size], slot.keySize, slot.stripes, h), nil
+}
+
+// keySlot is the area of the key material encrypted with the key derived from the passphrase
+type keySlot struct {
+	offset     int64
+	keySize    int
+	stripes    int
+	afHash     string
+	encryption string
+}
+
+// verifyDigest verifies the master key with the PBKDF2 digest
+func verifyDigest(masterKey, salt, digest []byte, iterations int, h func() hash.Hash) bool {
+	d := pbkdf2.Key(masterKey, salt, iterations, len(digest), h)
+	return subtle.ConstantTimeCompare(d, digest) == 1
+}
+
+// volume decrypts the sectors of the segment
+type volume struct {
+	r          io.ReaderAt
+	offset     int64
+	size       int64
+	sectorSize int64
+	ivTweak    uint64
+	cipher     sectorCipher
+}
+
+func (v *volume) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, xerrors.New("negative offset")
+	} else if off >= v.size {
+		return 0, io.EOF
+	}
+
+	var eof bool
+	if remaining := v.size - off; int64(len(p)) > remaining {
+		p = p[:remaining]
+		eof = true
+	}
+
+	// Read the whole sectors including the range
+	first := off / v.sectorSize
+	last := (off + int64(len(p)) - 1) / v.sectorSize
+	buf := make([]byte, (last-first+1)*v.sectorSize)
+	if n, err := v.r.ReadAt(buf, v.offset+first*v.sectorSize); n != len(buf) {
+		if err == nil || errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, xerrors.Errorf("LUKS volume read error: %w", err)
+	}
+	for i := int64(0); i <= last-first; i++ {
+		v.cipher.decrypt(buf[i*v.sectorSize:(i+1)*v.sectorSize], v.ivTweak+uint64(first+i))
+	}
+
+	n := copy(p, buf[off-first*v.sectorSize:])
+	if eof {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,107 @@
+package luks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// cf. https://gitlab.com/cryptsetup/cryptsetup/-/wikis/LUKS-standard/on-disk-format.pdf
+const luks1KeyEnabled = 0x00AC71F3
+
+type luks1Header struct {
+	Magic              [6]byte
+	Version            uint16
+	CipherName         [32]byte
+	CipherMode         [32]byte
+	HashSpec           [32]byte
+	PayloadOffset      uint32
+	KeyBytes           uint32
+	MKDigest           [20]byte
+	MKDigestSalt       [32]byte
+	MKDigestIterations uint32
+	UUID               [40]byte
+	KeySlots           [8]luks1KeySlot
+}
+
+type luks1KeySlot struct {
+	Active            uint32
+	Iterations        uint32
+	Salt              [32]byte
+	KeyMaterialOffset uint32
+	Stripes           uint32
+}
+
+func openLUKS1(sr *io.SectionReader, keys [][]byte) (*io.SectionReader, error) {
+	var header luks1Header
+	if err := binary.Read(io.NewSectionReader(sr, 0, sr.Size()), binary.BigEndian, &header); err != nil {
+		return nil, xerrors.Errorf("LUKS1 header error: %w", err)
+	}
+	encryption := cString(header.CipherName[:]) + "-" + cString(header.CipherMode[:])
+	h, err := hashFunc(cString(header.HashSpec[:]))
+	if err != nil {
+		return nil, err
+	}
+	keySize := int(header.KeyBytes)
+	if keySize == 0 || keySize > 512 {
+		return nil, xerrors.Errorf("invalid LUKS1 key size: %d", keySize)
+	}
+
+	for _, key := range keys {
+		for i, slot := range header.KeySlots {
+			if slot.Active != luks1KeyEnabled {
+				continue
+			}
+			if slot.Stripes == 0 || slot.Stripes > 1<<20 {
+				return nil, xerrors.Errorf("invalid LUKS1 stripes: %d", slot.Stripes)
+			}
+
+			derivedKey := pbkdf2.Key(key, slot.Salt[:], int(slot.Iterations), keySize, h)
+			masterKey, err := unlockKeySlot(sr, keySlot{
+				offset:     int64(slot.KeyMaterialOffset) * sectorSize,
+				keySize:    keySize,
+				stripes:    int(slot.Stripes),
+				afHash:     cString(header.HashSpec[:]),
+				encryption: encryption,
+			}, derivedKey)
+			if err != nil {
+				return nil, xerrors.Errorf("LUKS1 key slot %d error: %w", i, err)
+			}
+			if !verifyDigest(masterKey, header.MKDigestSalt[:], header.MKDigest[:], int(header.MKDigestIterations), h) {
+				continue
+			}
+			log.Logger.Debugf("LUKS1 key slot %d unlocked", i)
+
+			c, err := newSectorCipher(encryption, masterKey)
+			if err != nil {
+				return nil, err
+			}
+			offset := int64(header.PayloadOffset) * sectorSize
+			if offset > sr.Size() {
+				return nil, xerrors.Errorf("invalid LUKS1 payload offset: %d", header.PayloadOffset)
+			}
+			v := &volume{
+				r:          sr,
+				offset:     offset,
+				size:       (sr.Size() - offset) / sectorSize * sectorSize,
+				sectorSize: sectorSize,
+				cipher:     c,
+			}
+			return io.NewSectionReader(v, 0, v.size), nil
+		}
+	}
+	return nil, ErrInvalidKey
+}
+
+// cString returns the string terminated by NUL
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
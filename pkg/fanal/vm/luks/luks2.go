@@ -0,0 +1,303 @@
+package luks
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// cf. https://gitlab.com/cryptsetup/LUKS2-docs/blob/main/luks2_doc_wip.pdf
+const (
+	luks2SecondaryMagic = "SKUL\xba\xbe"
+	luks2BinaryHeader   = 4096
+	luks2MaxHeaderSize  = 4 << 20
+	luks2ChecksumOffset = 448
+
+	// The maximum memory cost of Argon2 in cryptsetup, in KiB
+	luks2MaxArgon2Memory = 4 << 20
+)
+
+// The secondary header follows the primary header, whose size is one of them
+var luks2SecondaryOffsets = []int64{16 << 10, 32 << 10, 64 << 10, 128 << 10, 256 << 10, 512 << 10, 1 << 20, 2 << 20, 4 << 20}
+
+type luks2Header struct {
+	Magic             [6]byte
+	Version           uint16
+	HeaderSize        uint64
+	SequenceID        uint64
+	Label             [48]byte
+	ChecksumAlgorithm [32]byte
+}
+
+type luks2Metadata struct {
+	Keyslots map[string]luks2Keyslot `json:"keyslots"`
+	Segments map[string]luks2Segment `json:"segments"`
+	Digests  map[string]luks2Digest  `json:"digests"`
+}
+
+type luks2Keyslot struct {
+	Type    string `json:"type"`
+	KeySize int    `json:"key_size"`
+	AF      struct {
+		Type    string `json:"type"`
+		Stripes int    `json:"stripes"`
+		Hash    string `json:"hash"`
+	} `json:"af"`
+	Area struct {
+		Type       string `json:"type"`
+		Offset     string `json:"offset"`
+		Encryption string `json:"encryption"`
+		KeySize    int    `json:"key_size"`
+	} `json:"area"`
+	KDF luks2KDF `json:"kdf"`
+}
+
+type luks2KDF struct {
+	Type       string `json:"type"`
+	Salt       []byte `json:"salt"`
+	Hash       string `json:"hash"`       // pbkdf2
+	Iterations int    `json:"iterations"` // pbkdf2
+	Time       uint32 `json:"time"`       // argon2
+	Memory     uint32 `json:"memory"`     // argon2, in KiB
+	CPUs       uint8  `json:"cpus"`       // argon2
+}
+
+type luks2Segment struct {
+	Type       string `json:"type"`
+	Offset     string `json:"offset"`
+	Size       string `json:"size"`
+	IVTweak    string `json:"iv_tweak"`
+	Encryption string `json:"encryption"`
+	SectorSize int64  `json:"sector_size"`
+}
+
+type luks2Digest struct {
+	Type       string   `json:"type"`
+	Keyslots   []string `json:"keyslots"`
+	Segments   []string `json:"segments"`
+	Hash       string   `json:"hash"`
+	Iterations int      `json:"iterations"`
+	Salt       []byte   `json:"salt"`
+	Digest     []byte   `json:"digest"`
+}
+
+func openLUKS2(sr *io.SectionReader, keys [][]byte) (*io.SectionReader, error) {
+	metadata, err := readLUKS2Metadata(sr)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata.Segments) != 1 {
+		// e.g. the volume in the middle of the re-encryption
+		return nil, xerrors.Errorf("LUKS2 with %d segments: %w", len(metadata.Segments), vm.ErrUnsupportedType)
+	}
+
+	digestIDs := maps.Keys(metadata.Digests)
+	slices.Sort(digestIDs)
+	for _, digestID := range digestIDs {
+		digest := metadata.Digests[digestID]
+		if digest.Type != "pbkdf2" || len(digest.Segments) == 0 {
+			continue
+		}
+		h, err := hashFunc(digest.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			for _, keyslotID := range digest.Keyslots {
+				keyslot, ok := metadata.Keyslots[keyslotID]
+				if !ok || keyslot.Type != "luks2" || keyslot.AF.Type != "luks1" || keyslot.Area.Type != "raw" {
+					continue
+				}
+				masterKey, err := unlockLUKS2Keyslot(sr, keyslot, key)
+				if err != nil {
+					return nil, xerrors.Errorf("LUKS2 key slot %s error: %w", keyslotID, err)
+				}
+				if !verifyDigest(masterKey, digest.Salt, digest.Digest, digest.Iterations, h) {
+					continue
+				}
+				log.Logger.Debugf("LUKS2 key slot %s unlocked", keyslotID)
+
+				segment, ok := metadata.Segments[digest.Segments[0]]
+				if !ok {
+					return nil, xerrors.Errorf("LUKS2 segment %s not found", digest.Segments[0])
+				}
+				return newLUKS2Volume(sr, segment, masterKey)
+			}
+		}
+	}
+	return nil, ErrInvalidKey
+}
+
+// readLUKS2Metadata reads the JSON metadata of the valid header with the greater sequence ID
+func readLUKS2Metadata(sr *io.SectionReader) (luks2Metadata, error) {
+	var current *luks2Header
+	var metadata luks2Metadata
+	for _, offset := range append([]int64{0}, luks2SecondaryOffsets...) {
+		header, jsonArea, err := readLUKS2Header(sr, offset)
+		if err != nil {
+			log.Logger.Debugf("LUKS2 header at %d: %s", offset, err)
+			continue
+		} else if current != nil && header.SequenceID <= current.SequenceID {
+			continue
+		}
+
+		var m luks2Metadata
+		if err = json.Unmarshal(bytes.TrimRight(jsonArea, "\x00"), &m); err != nil {
+			log.Logger.Debugf("LUKS2 metadata at %d: %s", offset, err)
+			continue
+		}
+		current, metadata = &header, m
+	}
+	if current == nil {
+		return luks2Metadata{}, xerrors.New("LUKS2 header not found")
+	}
+	return metadata, nil
+}
+
+func readLUKS2Header(sr *io.SectionReader, offset int64) (luks2Header, []byte, error) {
+	buf := make([]byte, luks2BinaryHeader)
+	if _, err := sr.ReadAt(buf, offset); err != nil {
+		return luks2Header{}, nil, xerrors.Errorf("read error: %w", err)
+	}
+	var header luks2Header
+	if err := binary.Read(bytes.NewReader(buf), binary.BigEndian, &header); err != nil {
+		return luks2Header{}, nil, xerrors.Errorf("decode error: %w", err)
+	}
+
+	switch {
+	case offset == 0 && string(header.Magic[:]) != magic,
+		offset != 0 && string(header.Magic[:]) != luks2SecondaryMagic:
+		return luks2Header{}, nil, xerrors.New("invalid magic")
+	case header.Version != 2:
+		return luks2Header{}, nil, xerrors.Errorf("invalid version: %d", header.Version)
+	case header.HeaderSize <= luks2BinaryHeader || header.HeaderSize > luks2MaxHeaderSize:
+		return luks2Header{}, nil, xerrors.Errorf("invalid header size: %d", header.HeaderSize)
+	case cString(header.ChecksumAlgorithm[:]) != "sha256":
+		return luks2Header{}, nil, xerrors.Errorf("checksum algorithm %q: %w", header.ChecksumAlgorithm, vm.ErrUnsupportedType)
+	}
+
+	// The checksum is calculated over the binary header and the JSON area, with the checksum field as zeros
+	area := make([]byte, header.HeaderSize)
+	if _, err := sr.ReadAt(area, offset); err != nil {
+		return luks2Header{}, nil, xerrors.Errorf("read error: %w", err)
+	}
+	checksum := bytes.Clone(area[luks2ChecksumOffset : luks2ChecksumOffset+sha256.Size])
+	clear(area[luks2ChecksumOffset : luks2ChecksumOffset+64])
+	if sum := sha256.Sum256(area); !bytes.Equal(sum[:], checksum) {
+		return luks2Header{}, nil, xerrors.New("checksum mismatch")
+	}
+	return header, area[luks2BinaryHeader:], nil
+}
+
+func unlockLUKS2Keyslot(sr *io.SectionReader, keyslot luks2Keyslot, key []byte) ([]byte, error) {
+	offset, err := strconv.ParseInt(keyslot.Area.Offset, 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid key slot offset: %w", err)
+	}
+	if keyslot.KeySize <= 0 || keyslot.KeySize > 512 || keyslot.AF.Stripes <= 0 || keyslot.AF.Stripes > 1<<20 {
+		return nil, xerrors.Errorf("invalid key size %d or stripes %d", keyslot.KeySize, keyslot.AF.Stripes)
+	}
+	if keyslot.Area.KeySize <= 0 || keyslot.Area.KeySize > 512 {
+		return nil, xerrors.Errorf("invalid key slot area key size: %d", keyslot.Area.KeySize)
+	}
+
+	var derivedKey []byte
+	kdf := keyslot.KDF
+	switch kdf.Type {
+	case "pbkdf2":
+		h, err := hashFunc(kdf.Hash)
+		if err != nil {
+			return nil, err
+		}
+		derivedKey = pbkdf2.Key(key, kdf.Salt, kdf.Iterations, keyslot.Area.KeySize, h)
+	case "argon2i":
+		if err = validateArgon2(kdf); err != nil {
+			return nil, err
+		}
+		derivedKey = argon2.Key(key, kdf.Salt, kdf.Time, kdf.Memory, kdf.CPUs, uint32(keyslot.Area.KeySize))
+	case "argon2id":
+		if err = validateArgon2(kdf); err != nil {
+			return nil, err
+		}
+		derivedKey = argon2.IDKey(key, kdf.Salt, kdf.Time, kdf.Memory, kdf.CPUs, uint32(keyslot.Area.KeySize))
+	default:
+		return nil, xerrors.Errorf("KDF %q: %w", kdf.Type, vm.ErrUnsupportedType)
+	}
+
+	return unlockKeySlot(sr, keySlot{
+		offset:     offset,
+		keySize:    keyslot.KeySize,
+		stripes:    keyslot.AF.Stripes,
+		afHash:     keyslot.AF.Hash,
+		encryption: keyslot.Area.Encryption,
+	}, derivedKey)
+}
+
+// validateArgon2 checks the Argon2 parameters taken from the header, which anyone can rewrite with a valid checksum.
+// argon2.Key panics with zero time or threads, and a huge memory cost exhausts the memory.
+func validateArgon2(kdf luks2KDF) error {
+	switch {
+	case kdf.Time < 1:
+		return xerrors.Errorf("invalid Argon2 time cost: %d", kdf.Time)
+	case kdf.CPUs < 1:
+		return xerrors.Errorf("invalid Argon2 threads: %d", kdf.CPUs)
+	case kdf.Memory > luks2MaxArgon2Memory:
+		return xerrors.Errorf("invalid Argon2 memory cost: %d KiB", kdf.Memory)
+	}
+	return nil
+}
+
+func newLUKS2Volume(sr *io.SectionReader, segment luks2Segment, masterKey []byte) (*io.SectionReader, error) {
+	if segment.Type != "crypt" {
+		return nil, xerrors.Errorf("LUKS2 segment type %q: %w", segment.Type, vm.ErrUnsupportedType)
+	}
+	offset, err := strconv.ParseInt(segment.Offset, 10, 64)
+	if err != nil || offset > sr.Size() {
+		return nil, xerrors.Errorf("invalid LUKS2 segment offset: %s", segment.Offset)
+	}
+	if segment.SectorSize < sectorSize || segment.SectorSize > 4096 || segment.SectorSize&(segment.SectorSize-1) != 0 {
+		return nil, xerrors.Errorf("invalid LUKS2 sector size: %d", segment.SectorSize)
+	}
+
+	// The size of the segment is "dynamic" up to the end of the device
+	size := (sr.Size() - offset) / segment.SectorSize * segment.SectorSize
+	if segment.Size != "dynamic" {
+		if size, err = strconv.ParseInt(segment.Size, 10, 64); err != nil || offset+size > sr.Size() {
+			return nil, xerrors.Errorf("invalid LUKS2 segment size: %s", segment.Size)
+		}
+	}
+	var ivTweak uint64
+	if segment.IVTweak != "" {
+		if ivTweak, err = strconv.ParseUint(segment.IVTweak, 10, 64); err != nil {
+			return nil, xerrors.Errorf("invalid LUKS2 IV tweak: %w", err)
+		}
+	}
+
+	c, err := newSectorCipher(segment.Encryption, masterKey)
+	if err != nil {
+		return nil, err
+	}
+	// The IV is the sector number in the unit of the sector size
+	v := &volume{
+		r:          sr,
+		offset:     offset,
+		size:       size,
+		sectorSize: segment.SectorSize,
+		ivTweak:    ivTweak,
+		cipher:     c,
+	}
+	return io.NewSectionReader(v, 0, v.size), nil
+}
@@ -0,0 +1,410 @@
+package luks_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1" // nolint:gosec
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"hash"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/xts"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+	"github.com/aquasecurity/trivy/pkg/fanal/vm/luks"
+)
+
+const (
+	stripes    = 4000
+	iterations = 1000
+)
+
+var (
+	passphrase = []byte("correct horse battery staple")
+	keyFile    = []byte("key file\n\x00with binary data")
+)
+
+func randomBytes(t *testing.T, n int) []byte {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+	return b
+}
+
+// encryptSectors encrypts the data like dm-crypt, where the IV is the sector number from the beginning of the data
+func encryptSectors(t *testing.T, encryption string, key, data []byte, sectorSize int) []byte {
+	out := make([]byte, len(data))
+	for i := 0; i*sectorSize < len(data); i++ {
+		src, dst := data[i*sectorSize:(i+1)*sectorSize], out[i*sectorSize:(i+1)*sectorSize]
+		switch encryption {
+		case "aes-xts-plain64":
+			c, err := xts.NewCipher(aes.NewCipher, key)
+			require.NoError(t, err)
+			c.Encrypt(dst, src, uint64(i))
+		case "aes-cbc-essiv:sha256":
+			salt := sha256.Sum256(key)
+			essiv, err := aes.NewCipher(salt[:])
+			require.NoError(t, err)
+			iv := make([]byte, aes.BlockSize)
+			binary.LittleEndian.PutUint64(iv, uint64(i))
+			essiv.Encrypt(iv, iv)
+			block, err := aes.NewCipher(key)
+			require.NoError(t, err)
+			cipher.NewCBCEncrypter(block, iv).CryptBlocks(dst, src)
+		default:
+			require.Fail(t, "unknown encryption", encryption)
+		}
+	}
+	return out
+}
+
+// afSplit splits the key into the stripes with the anti-forensic splitter of cryptsetup
+func afSplit(t *testing.T, key []byte, h func() hash.Hash) []byte {
+	var material []byte
+	d := make([]byte, len(key))
+	for i := 0; i < stripes-1; i++ {
+		stripe := randomBytes(t, len(key))
+		material = append(material, stripe...)
+		for j := range d {
+			d[j] ^= stripe[j]
+		}
+
+		// Diffuse the blocks of the digest size with the big-endian block number
+		var diffused []byte
+		size := h().Size()
+		for j := 0; j*size < len(d); j++ {
+			block := d[j*size : min((j+1)*size, len(d))]
+			digest := h()
+			digest.Write(binary.BigEndian.AppendUint32(nil, uint32(j)))
+			digest.Write(block)
+			diffused = append(diffused, digest.Sum(nil)[:len(block)]...)
+		}
+		d = diffused
+	}
+	for j := range d {
+		d[j] ^= key[j]
+	}
+	return append(material, d...)
+}
+
+// padSectors pads the data to the sector boundary
+func padSectors(b []byte) []byte {
+	return append(b, make([]byte, (512-len(b)%512)%512)...)
+}
+
+// luks1Image returns the image formatted by "cryptsetup luksFormat --type luks1" with the key slots of the keys
+func luks1Image(t *testing.T, encryption, hashName string, keySize int, keys [][]byte, plaintext []byte) []byte {
+	h := map[string]func() hash.Hash{"sha1": sha1.New, "sha256": sha256.New}[hashName]
+	masterKey := randomBytes(t, keySize)
+	payloadOffset := 4096 // sectors
+
+	image := make([]byte, payloadOffset*512)
+	copy(image, "LUKS\xba\xbe")
+	binary.BigEndian.PutUint16(image[6:], 1)
+	cipherName, cipherMode, _ := bytes.Cut([]byte(encryption), []byte("-"))
+	copy(image[8:], cipherName)
+	copy(image[40:], cipherMode)
+	copy(image[72:], hashName)
+	binary.BigEndian.PutUint32(image[104:], uint32(payloadOffset))
+	binary.BigEndian.PutUint32(image[108:], uint32(keySize))
+	salt := randomBytes(t, 32)
+	copy(image[112:], pbkdf2.Key(masterKey, salt, iterations, 20, h))
+	copy(image[132:], salt)
+	binary.BigEndian.PutUint32(image[164:], iterations)
+
+	materialSectors := (keySize*stripes + 511) / 512
+	for i := 0; i < 8; i++ {
+		slot := image[208+i*48:]
+		offset := 8 + i*materialSectors
+		binary.BigEndian.PutUint32(slot[0:], 0x0000DEAD) // disabled
+		binary.BigEndian.PutUint32(slot[40:], uint32(offset))
+		binary.BigEndian.PutUint32(slot[44:], stripes)
+		if i >= len(keys) {
+			continue
+		}
+
+		salt = randomBytes(t, 32)
+		binary.BigEndian.PutUint32(slot[0:], 0x00AC71F3)
+		binary.BigEndian.PutUint32(slot[4:], iterations)
+		copy(slot[8:], salt)
+		derivedKey := pbkdf2.Key(keys[i], salt, iterations, keySize, h)
+		material := padSectors(afSplit(t, masterKey, h))
+		copy(image[offset*512:], encryptSectors(t, encryption, derivedKey, material, 512))
+	}
+
+	return append(image, encryptSectors(t, encryption, masterKey, plaintext, 512)...)
+}
+
+// luks2Image returns the image formatted by "cryptsetup luksFormat --type luks2" with the key slots of the keys
+func luks2Image(t *testing.T, kdf string, sectorSize int, keys [][]byte, plaintext []byte) []byte {
+	const (
+		headerSize    = 16 << 10
+		keyslotsStart = 2 * headerSize
+		segmentOffset = 1 << 20
+		keySize       = 64
+	)
+	masterKey := randomBytes(t, keySize)
+	image := make([]byte, segmentOffset)
+
+	keyslots := make(map[string]any)
+	var keyslotIDs []string
+	for i, key := range keys {
+		salt := randomBytes(t, 32)
+		var derivedKey []byte
+		kdfParams := map[string]any{"type": kdf, "salt": salt}
+		switch kdf {
+		case "pbkdf2":
+			kdfParams["hash"], kdfParams["iterations"] = "sha256", iterations
+			derivedKey = pbkdf2.Key(key, salt, iterations, keySize, sha256.New)
+		case "argon2id":
+			kdfParams["time"], kdfParams["memory"], kdfParams["cpus"] = 1, 64, 1
+			derivedKey = argon2.IDKey(key, salt, 1, 64, 1, keySize)
+		}
+
+		offset := keyslotsStart + i*(keySize*stripes+4095)/4096*4096
+		material := padSectors(afSplit(t, masterKey, sha256.New))
+		copy(image[offset:], encryptSectors(t, "aes-xts-plain64", derivedKey, material, 512))
+
+		id := strconv.Itoa(i)
+		keyslotIDs = append(keyslotIDs, id)
+		keyslots[id] = map[string]any{
+			"type":     "luks2",
+			"key_size": keySize,
+			"af":       map[string]any{"type": "luks1", "stripes": stripes, "hash": "sha256"},
+			"area": map[string]any{
+				"type":       "raw",
+				"offset":     strconv.Itoa(offset),
+				"size":       strconv.Itoa(len(material)),
+				"encryption": "aes-xts-plain64",
+				"key_size":   keySize,
+			},
+			"kdf": kdfParams,
+		}
+	}
+
+	digestSalt := randomBytes(t, 32)
+	metadata, err := json.Marshal(map[string]any{
+		"keyslots": keyslots,
+		"tokens":   map[string]any{},
+		"segments": map[string]any{
+			"0": map[string]any{
+				"type":        "crypt",
+				"offset":      strconv.Itoa(segmentOffset),
+				"size":        "dynamic",
+				"iv_tweak":    "0",
+				"encryption":  "aes-xts-plain64",
+				"sector_size": sectorSize,
+			},
+		},
+		"digests": map[string]any{
+			"0": map[string]any{
+				"type":       "pbkdf2",
+				"keyslots":   keyslotIDs,
+				"segments":   []string{"0"},
+				"hash":       "sha256",
+				"iterations": iterations,
+				"salt":       digestSalt,
+				"digest":     pbkdf2.Key(masterKey, digestSalt, iterations, 32, sha256.New),
+			},
+		},
+		"config": map[string]any{
+			"json_size":     strconv.Itoa(headerSize - 4096),
+			"keyslots_size": strconv.Itoa(segmentOffset - keyslotsStart),
+		},
+	})
+	require.NoError(t, err)
+
+	// The primary and the secondary headers
+	for i, magic := range []string{"LUKS\xba\xbe", "SKUL\xba\xbe"} {
+		header := image[i*headerSize : (i+1)*headerSize]
+		copy(header, magic)
+		binary.BigEndian.PutUint16(header[6:], 2)
+		binary.BigEndian.PutUint64(header[8:], headerSize)
+		binary.BigEndian.PutUint64(header[16:], 1)
+		copy(header[72:], "sha256")
+		binary.BigEndian.PutUint64(header[256:], uint64(i*headerSize))
+		copy(header[4096:], metadata)
+		sum := sha256.Sum256(header)
+		copy(header[448:], sum[:])
+	}
+
+	return append(image, encryptSectors(t, "aes-xts-plain64", masterKey, plaintext, sectorSize)...)
+}
+
+func TestOpen(t *testing.T) {
+	plaintext := randomBytes(t, 64<<10)
+
+	tests := []struct {
+		name    string
+		image   func(t *testing.T) []byte
+		keys    [][]byte
+		wantErr error
+	}{
+		{
+			name: "LUKS1 with the passphrase",
+			image: func(t *testing.T) []byte {
+				return luks1Image(t, "aes-xts-plain64", "sha256", 64, [][]byte{passphrase, keyFile}, plaintext)
+			},
+			keys: [][]byte{passphrase},
+		},
+		{
+			name: "LUKS1 with the key file in the second key slot",
+			image: func(t *testing.T) []byte {
+				return luks1Image(t, "aes-xts-plain64", "sha256", 64, [][]byte{passphrase, keyFile}, plaintext)
+			},
+			keys: [][]byte{[]byte("wrong"), keyFile},
+		},
+		{
+			name: "LUKS1 in CBC-ESSIV",
+			image: func(t *testing.T) []byte {
+				return luks1Image(t, "aes-cbc-essiv:sha256", "sha1", 32, [][]byte{passphrase}, plaintext)
+			},
+			keys: [][]byte{passphrase},
+		},
+		{
+			name: "LUKS2 with Argon2id",
+			image: func(t *testing.T) []byte {
+				return luks2Image(t, "argon2id", 512, [][]byte{passphrase}, plaintext)
+			},
+			keys: [][]byte{passphrase},
+		},
+		{
+			name: "LUKS2 with 4K sectors and PBKDF2",
+			image: func(t *testing.T) []byte {
+				return luks2Image(t, "pbkdf2", 4096, [][]byte{keyFile, passphrase}, plaintext)
+			},
+			keys: [][]byte{passphrase},
+		},
+		{
+			name: "LUKS2 with the broken primary header",
+			image: func(t *testing.T) []byte {
+				image := luks2Image(t, "pbkdf2", 512, [][]byte{passphrase}, plaintext)
+				image[4096] = '!'
+				return image
+			},
+			keys: [][]byte{passphrase},
+		},
+		{
+			name: "wrong passphrase",
+			image: func(t *testing.T) []byte {
+				return luks2Image(t, "pbkdf2", 512, [][]byte{passphrase}, plaintext)
+			},
+			keys:    [][]byte{[]byte("wrong")},
+			wantErr: luks.ErrInvalidKey,
+		},
+		{
+			name: "no passphrase",
+			image: func(t *testing.T) []byte {
+				return luks1Image(t, "aes-xts-plain64", "sha256", 64, [][]byte{passphrase}, plaintext)
+			},
+			wantErr: luks.ErrInvalidKey,
+		},
+		{
+			name: "not LUKS",
+			image: func(t *testing.T) []byte {
+				return make([]byte, 4096)
+			},
+			keys:    [][]byte{passphrase},
+			wantErr: vm.ErrInvalidSignature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image := tt.image(t)
+			assert.Equal(t, tt.wantErr != vm.ErrInvalidSignature, luks.IsLUKS(bytes.NewReader(image)))
+
+			sr, err := luks.Open(io.NewSectionReader(bytes.NewReader(image), 0, int64(len(image))), tt.keys)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := io.ReadAll(sr)
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(plaintext, got))
+
+			// Unaligned read in the middle of the sectors
+			buf := make([]byte, 5000)
+			_, err = sr.ReadAt(buf, 4100)
+			require.NoError(t, err)
+			assert.Equal(t, plaintext[4100:9100], buf)
+		})
+	}
+}
+
+// rewriteLUKS2Keyslot modifies the first key slot in both headers and recalculates the checksums
+func rewriteLUKS2Keyslot(t *testing.T, image []byte, fn func(keyslot map[string]any)) {
+	const headerSize = 16 << 10
+	for i := 0; i < 2; i++ {
+		header := image[i*headerSize : (i+1)*headerSize]
+
+		var metadata map[string]any
+		require.NoError(t, json.Unmarshal(bytes.TrimRight(header[4096:], "\x00"), &metadata))
+		fn(metadata["keyslots"].(map[string]any)["0"].(map[string]any))
+		b, err := json.Marshal(metadata)
+		require.NoError(t, err)
+
+		clear(header[4096:])
+		copy(header[4096:], b)
+		clear(header[448:512])
+		sum := sha256.Sum256(header)
+		copy(header[448:], sum[:])
+	}
+}
+
+func TestOpen_MalformedKeyslot(t *testing.T) {
+	tests := []struct {
+		name    string
+		rewrite func(keyslot map[string]any)
+		wantErr string
+	}{
+		{
+			name: "zero Argon2 time cost",
+			rewrite: func(keyslot map[string]any) {
+				keyslot["kdf"].(map[string]any)["time"] = 0
+			},
+			wantErr: "invalid Argon2 time cost: 0",
+		},
+		{
+			name: "zero Argon2 threads",
+			rewrite: func(keyslot map[string]any) {
+				keyslot["kdf"].(map[string]any)["cpus"] = 0
+			},
+			wantErr: "invalid Argon2 threads: 0",
+		},
+		{
+			name: "too large Argon2 memory cost",
+			rewrite: func(keyslot map[string]any) {
+				keyslot["kdf"].(map[string]any)["memory"] = 1 << 30
+			},
+			wantErr: "invalid Argon2 memory cost",
+		},
+		{
+			name: "negative area key size",
+			rewrite: func(keyslot map[string]any) {
+				keyslot["area"].(map[string]any)["key_size"] = -1
+			},
+			wantErr: "invalid key slot area key size: -1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image := luks2Image(t, "argon2id", 512, [][]byte{passphrase}, nil)
+			rewriteLUKS2Keyslot(t, image, tt.rewrite)
+
+			_, err := luks.Open(io.NewSectionReader(bytes.NewReader(image), 0, int64(len(image))), [][]byte{passphrase})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
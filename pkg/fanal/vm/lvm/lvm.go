@@ -0,0 +1,400 @@
+package lvm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// cf. https://github.com/lvmteam/lvm2/blob/main/lib/format_text/layout.h
+const (
+	sectorSize = 512
+
+	labelID          = "LABELONE"
+	labelType        = "LVM2 001"
+	labelScanSectors = 4
+
+	mdaMagic      = " LVM2 x[5A%r0N*>"
+	mdaHeaderSize = 512
+
+	// The metadata of a volume group is a few KB, even with hundreds of logical volumes
+	maxMetadataSize = 16 << 20
+
+	initialCRC = 0xf597a6cf
+)
+
+// PhysicalVolume is a physical volume with the metadata of its volume group
+type PhysicalVolume struct {
+	id       string
+	r        io.ReaderAt
+	metadata section
+}
+
+type labelHeader struct {
+	ID       [8]byte
+	SectorXL uint64
+	CRCXL    uint32
+	OffsetXL uint32
+	Type     [8]byte
+}
+
+type diskLocation struct {
+	Offset uint64
+	Size   uint64
+}
+
+type mdaHeader struct {
+	ChecksumXL uint32
+	Magic      [16]byte
+	Version    uint32
+	Start      uint64
+	Size       uint64
+}
+
+type rawLocation struct {
+	Offset   uint64
+	Size     uint64
+	Checksum uint32
+	Flags    uint32
+}
+
+// NewPhysicalVolume reads the label and the metadata of the physical volume.
+// vm.ErrInvalidSignature is returned if the volume is not a physical volume of LVM2.
+func NewPhysicalVolume(r io.ReaderAt) (*PhysicalVolume, error) {
+	sector := make([]byte, sectorSize)
+	for i := int64(0); i < labelScanSectors; i++ {
+		if _, err := r.ReadAt(sector, i*sectorSize); err != nil {
+			return nil, vm.ErrInvalidSignature
+		}
+		if string(sector[:len(labelID)]) != labelID {
+			continue
+		}
+
+		var label labelHeader
+		if err := binary.Read(bytes.NewReader(sector), binary.LittleEndian, &label); err != nil {
+			return nil, xerrors.Errorf("LVM label error: %w", err)
+		}
+		if string(label.Type[:]) != labelType {
+			return nil, xerrors.Errorf("LVM label type %q: %w", label.Type, vm.ErrUnsupportedType)
+		}
+		if label.CRCXL != calcCRC(sector[20:]) {
+			return nil, xerrors.New("LVM label checksum mismatch")
+		}
+		return readPVHeader(r, sector, label)
+	}
+	return nil, vm.ErrInvalidSignature
+}
+
+// readPVHeader reads the physical volume header following the label, and the metadata in the metadata area
+func readPVHeader(r io.ReaderAt, sector []byte, label labelHeader) (*PhysicalVolume, error) {
+	if label.OffsetXL < 32 || label.OffsetXL+32 > sectorSize {
+		return nil, xerrors.Errorf("invalid LVM PV header offset: %d", label.OffsetXL)
+	}
+	br := bytes.NewReader(sector[label.OffsetXL:])
+
+	var uuid [32]byte
+	var deviceSize uint64
+	if err := binary.Read(br, binary.LittleEndian, &uuid); err != nil {
+		return nil, xerrors.Errorf("LVM PV header error: %w", err)
+	}
+	if err := binary.Read(br, binary.LittleEndian, &deviceSize); err != nil {
+		return nil, xerrors.Errorf("LVM PV header error: %w", err)
+	}
+
+	// The data areas and the metadata areas are lists terminated by the zero entry
+	var areas [2][]diskLocation
+	for i := range areas {
+		for {
+			var loc diskLocation
+			if err := binary.Read(br, binary.LittleEndian, &loc); err != nil {
+				return nil, xerrors.Errorf("LVM PV header error: %w", err)
+			}
+			if loc.Offset == 0 {
+				break
+			}
+			areas[i] = append(areas[i], loc)
+		}
+	}
+
+	pv := &PhysicalVolume{
+		id: string(uuid[:]),
+		r:  r,
+	}
+	var errs []error
+	for _, mda := range areas[1] {
+		metadata, err := readMetadata(r, mda)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		pv.metadata = metadata
+		return pv, nil
+	}
+	if len(errs) > 0 {
+		return nil, xerrors.Errorf("LVM metadata error: %w", errors.Join(errs...))
+	}
+	// e.g. "pvcreate --metadatacopies 0", where the metadata is in the other physical volumes
+	return pv, nil
+}
+
+// readMetadata reads the current metadata in the circular buffer of the metadata area
+func readMetadata(r io.ReaderAt, mda diskLocation) (section, error) {
+	buf := make([]byte, mdaHeaderSize)
+	if err := readFull(r, buf, int64(mda.Offset)); err != nil {
+		return nil, xerrors.Errorf("LVM metadata area header error: %w", err)
+	}
+	br := bytes.NewReader(buf)
+	var header mdaHeader
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return nil, xerrors.Errorf("LVM metadata area header error: %w", err)
+	}
+	if string(header.Magic[:]) != mdaMagic || header.ChecksumXL != calcCRC(buf[4:]) {
+		return nil, xerrors.New("invalid LVM metadata area header")
+	}
+
+	var loc rawLocation
+	if err := binary.Read(br, binary.LittleEndian, &loc); err != nil {
+		return nil, xerrors.Errorf("LVM metadata location error: %w", err)
+	}
+	if loc.Offset == 0 || loc.Size > maxMetadataSize || loc.Offset >= header.Size {
+		return nil, xerrors.New("LVM metadata not found")
+	}
+
+	// The metadata wraps around to the beginning of the buffer following the header
+	text := make([]byte, loc.Size)
+	first := min(loc.Size, header.Size-loc.Offset)
+	if err := readFull(r, text[:first], int64(header.Start+loc.Offset)); err != nil {
+		return nil, xerrors.Errorf("LVM metadata read error: %w", err)
+	}
+	if err := readFull(r, text[first:], int64(header.Start+mdaHeaderSize)); err != nil {
+		return nil, xerrors.Errorf("LVM metadata read error: %w", err)
+	}
+	if loc.Checksum != calcCRC(text) {
+		return nil, xerrors.New("LVM metadata checksum mismatch")
+	}
+
+	return parseMetadata(string(bytes.TrimRight(text, "\x00")))
+}
+
+// LogicalVolume is a logical volume assembled from the physical volumes
+type LogicalVolume struct {
+	// Name is the name of the device mapper, e.g. "ubuntu--vg-ubuntu--lv"
+	Name string
+	*io.SectionReader
+}
+
+// Assemble assembles the visible logical volumes of the volume groups in the physical volumes.
+// The logical volumes which are not readable are skipped, such as the ones with missing physical volumes and thin volumes.
+func Assemble(pvs []*PhysicalVolume) []LogicalVolume {
+	// The volume group may have the different versions of the metadata in the physical volumes
+	vgs := make(map[string]*volumeGroup)
+	for _, pv := range pvs {
+		for name, v := range pv.metadata {
+			metadata, ok := v.(section)
+			if !ok {
+				continue
+			}
+			id := metadata.string("id")
+			if vg, ok := vgs[id]; !ok || metadata.int("seqno") > vg.metadata.int("seqno") {
+				vgs[id] = &volumeGroup{
+					name:     name,
+					metadata: metadata,
+				}
+			}
+		}
+	}
+
+	var lvs []LogicalVolume
+	for _, vg := range vgs {
+		vg.pvs = make(map[string]physicalExtents)
+		for pvName, v := range vg.metadata.section("physical_volumes") {
+			pvMetadata, ok := v.(section)
+			if !ok {
+				continue
+			}
+			id := strings.ReplaceAll(pvMetadata.string("id"), "-", "")
+			if i := slices.IndexFunc(pvs, func(pv *PhysicalVolume) bool { return pv.id == id }); i >= 0 {
+				vg.pvs[pvName] = physicalExtents{
+					r:     pvs[i].r,
+					start: pvMetadata.int("pe_start") * sectorSize,
+				}
+			}
+		}
+		lvs = append(lvs, vg.logicalVolumes()...)
+	}
+	sort.Slice(lvs, func(i, j int) bool { return lvs[i].Name < lvs[j].Name })
+	return lvs
+}
+
+type volumeGroup struct {
+	name     string
+	metadata section
+	pvs      map[string]physicalExtents
+}
+
+// physicalExtents is the area of the extents in the physical volume
+type physicalExtents struct {
+	r     io.ReaderAt
+	start int64
+}
+
+func (vg *volumeGroup) logicalVolumes() []LogicalVolume {
+	extentSize := vg.metadata.int("extent_size") * sectorSize
+	if extentSize <= 0 {
+		log.Logger.Warnf("Invalid extent size of the LVM volume group %q", vg.name)
+		return nil
+	}
+
+	var lvs []LogicalVolume
+	for lvName, v := range vg.metadata.section("logical_volumes") {
+		lvMetadata, ok := v.(section)
+		if !ok || !slices.Contains(lvMetadata.strings("status"), "VISIBLE") {
+			continue
+		}
+		name := deviceMapperName(vg.name, lvName)
+		lv, err := vg.logicalVolume(lvMetadata, extentSize)
+		if err != nil {
+			log.Logger.Warnf("Skip the LVM logical volume %s: %s", name, err)
+			continue
+		}
+		log.Logger.Debugf("Found LVM logical volume: %s", name)
+		lvs = append(lvs, LogicalVolume{
+			Name:          name,
+			SectionReader: io.NewSectionReader(lv, 0, lv.size),
+		})
+	}
+	return lvs
+}
+
+func (vg *volumeGroup) logicalVolume(metadata section, extentSize int64) (*logicalVolume, error) {
+	lv := &logicalVolume{}
+	for i := int64(1); i <= metadata.int("segment_count"); i++ {
+		s := metadata.section(fmt.Sprintf("segment%d", i))
+		if s == nil {
+			return nil, xerrors.Errorf("segment%d not found", i)
+		}
+		// Linear volumes are the striped ones with a stripe
+		if typ := s.string("type"); typ != "striped" {
+			return nil, xerrors.Errorf("segment type %q: %w", typ, vm.ErrUnsupportedType)
+		}
+
+		seg := segment{
+			start:      s.int("start_extent") * extentSize,
+			size:       s.int("extent_count") * extentSize,
+			stripeSize: s.int("stripe_size") * sectorSize,
+		}
+		stripes, _ := s["stripes"].([]any)
+		if len(stripes) == 0 || len(stripes)%2 != 0 {
+			return nil, xerrors.New("invalid stripes")
+		}
+		for j := 0; j < len(stripes); j += 2 {
+			pvName, _ := stripes[j].(string)
+			startExtent, _ := stripes[j+1].(int64)
+			pv, ok := vg.pvs[pvName]
+			if !ok {
+				return nil, xerrors.Errorf("physical volume %q not found", pvName)
+			}
+			seg.stripes = append(seg.stripes, stripe{
+				r:      pv.r,
+				offset: pv.start + startExtent*extentSize,
+			})
+		}
+		if len(seg.stripes) > 1 && seg.stripeSize <= 0 {
+			return nil, xerrors.Errorf("invalid stripe size: %d", seg.stripeSize)
+		}
+
+		if seg.start != lv.size {
+			return nil, xerrors.Errorf("segment%d not contiguous", i)
+		}
+		lv.segments = append(lv.segments, seg)
+		lv.size += seg.size
+	}
+	if len(lv.segments) == 0 {
+		return nil, xerrors.New("no segment")
+	}
+	return lv, nil
+}
+
+// deviceMapperName returns the name of the logical volume in /dev/mapper, where the hyphens are doubled
+func deviceMapperName(vgName, lvName string) string {
+	return strings.ReplaceAll(vgName, "-", "--") + "-" + strings.ReplaceAll(lvName, "-", "--")
+}
+
+type logicalVolume struct {
+	segments []segment
+	size     int64
+}
+
+type segment struct {
+	start      int64
+	size       int64
+	stripeSize int64
+	stripes    []stripe
+}
+
+// stripe is the area of the segment in the physical volume
+type stripe struct {
+	r      io.ReaderAt
+	offset int64
+}
+
+func (lv *logicalVolume) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, xerrors.New("negative offset")
+	}
+
+	var n int
+	for n < len(p) {
+		pos := off + int64(n)
+		i := sort.Search(len(lv.segments), func(i int) bool {
+			return lv.segments[i].start+lv.segments[i].size > pos
+		})
+		if i == len(lv.segments) {
+			return n, io.EOF
+		}
+		seg := lv.segments[i]
+
+		// The stripes are interleaved by the stripe size
+		offInSeg := pos - seg.start
+		st, offInStripe, chunk := seg.stripes[0], offInSeg, seg.size-offInSeg
+		if len(seg.stripes) > 1 {
+			stripeIndex := offInSeg / seg.stripeSize
+			st = seg.stripes[stripeIndex%int64(len(seg.stripes))]
+			offInStripe = stripeIndex/int64(len(seg.stripes))*seg.stripeSize + offInSeg%seg.stripeSize
+			chunk = seg.stripeSize - offInSeg%seg.stripeSize
+		}
+		chunk = min(chunk, int64(len(p)-n))
+
+		if err := readFull(st.r, p[n:n+int(chunk)], st.offset+offInStripe); err != nil {
+			return n, err
+		}
+		n += int(chunk)
+	}
+	return n, nil
+}
+
+func readFull(r io.ReaderAt, p []byte, off int64) error {
+	if n, err := r.ReadAt(p, off); n != len(p) {
+		if err == nil || errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return xerrors.Errorf("read error at %d: %w", off, err)
+	}
+	return nil
+}
+
+// calcCRC calculates the checksum of LVM2, i.e. CRC-32 without the final inversion
+func calcCRC(b []byte) uint32 {
+	return ^crc32.Update(^uint32(initialCRC), crc32.IEEETable, b)
+}
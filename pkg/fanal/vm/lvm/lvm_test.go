@@ -0,0 +1,268 @@
+package lvm_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
+	"github.com/aquasecurity/trivy/pkg/fanal/vm/lvm"
+)
+
+const (
+	mdaOffset  = 4096
+	mdaSize    = 64 << 10
+	peStart    = 1 << 20
+	extentSize = 4096
+	extents    = 4
+)
+
+const metadata = `# Generated by LVM2 version 2.03.11(2) (2021-01-08): Mon Jan  1 00:00:00 2024
+
+vg-data {
+id = "Qf1ZVz-3Ghn-qh8U-Nkd4-nUmM-xrZH-IaqW4u"
+seqno = 7
+format = "lvm2"			# informational
+status = ["RESIZEABLE", "READ", "WRITE"]
+flags = []
+extent_size = 8		# 4 Kilobytes
+max_lv = 0
+max_pv = 0
+metadata_copies = 0
+
+physical_volumes {
+
+pv0 {
+id = "5kT0Xy-AwQx-4GUz-Xv2j-lKBf-X3pd-sLyu9s"
+device = "/dev/sda2"	# Hint only
+
+status = ["ALLOCATABLE"]
+flags = []
+dev_size = 2112
+pe_start = 2048
+pe_count = 4	# 16 Kilobytes
+}
+
+pv1 {
+id = "0vSP5u-k7wh-FRRP-cx9v-uKr3-ftnZ-qkHZqx"
+device = "/dev/sdb"	# Hint only
+
+status = ["ALLOCATABLE"]
+flags = []
+dev_size = 2112
+pe_start = 2048
+pe_count = 4	# 16 Kilobytes
+}
+}
+
+logical_volumes {
+
+root {
+id = "v9ZRKS-L3zd-ryKf-2aTw-Yc2t-pY0a-AVdP1s"
+status = ["READ", "WRITE", "VISIBLE"]
+flags = []
+creation_time = 1704067200	# 2024-01-01 00:00:00 +0000
+creation_host = "ubuntu-server"
+segment_count = 2
+
+segment1 {
+start_extent = 0
+extent_count = 2	# 8 Kilobytes
+
+type = "striped"
+stripe_count = 1	# linear
+
+stripes = [
+"pv0", 0
+]
+}
+segment2 {
+start_extent = 2
+extent_count = 1	# 4 Kilobytes
+
+type = "striped"
+stripe_count = 1	# linear
+
+stripes = [
+"pv1", 0
+]
+}
+}
+
+striped-lv {
+id = "Uq0xTg-yS3U-3v2N-u8bO-4ZWl-mmM1-pW7vEo"
+status = ["READ", "WRITE", "VISIBLE"]
+flags = []
+segment_count = 1
+
+segment1 {
+start_extent = 0
+extent_count = 2	# 8 Kilobytes
+
+type = "striped"
+stripe_count = 2
+stripe_size = 2	# 1 Kilobytes
+
+stripes = [
+"pv0", 2,
+"pv1", 1
+]
+}
+}
+
+thin {
+id = "Ww3bLk-0cTA-ZmA6-Lzq0-hvHn-aUgV-5v4X2d"
+status = ["READ", "WRITE", "VISIBLE"]
+flags = []
+segment_count = 1
+
+segment1 {
+start_extent = 0
+extent_count = 1
+
+type = "thin"
+thin_pool = "pool"
+transaction_id = 0
+device_id = 1
+}
+}
+
+lvol0_pmspare {
+id = "Zf3rXy-0Aac-pB0c-Hs1o-8uJw-k8Vq-N2m1Ro"
+status = ["READ", "WRITE"]
+flags = []
+segment_count = 1
+
+segment1 {
+start_extent = 0
+extent_count = 1
+
+type = "striped"
+stripe_count = 1
+
+stripes = [
+"pv1", 3
+]
+}
+}
+}
+
+}
+# Generated by LVM2 version 2.03.11(2) (2021-01-08): Mon Jan  1 00:00:00 2024
+
+contents = "Text Format Volume Group"
+version = 1
+
+description = "Created *after* executing 'lvcreate -i 2 -I 1k -l 2 -n striped-lv vg-data'"
+
+creation_host = "ubuntu-server"	# Linux ubuntu-server 5.15.0-91-generic #101-Ubuntu SMP x86_64
+creation_time = 1704067200	# Mon Jan  1 00:00:00 2024
+`
+
+// physicalVolume returns the image of the physical volume created by "pvcreate", followed by the extents.
+// The metadata is written at the offset in the circular buffer of the metadata area.
+func physicalVolume(t *testing.T, id string, metadataOffset int, data []byte) []byte {
+	pv := make([]byte, peStart)
+
+	// The label in the second sector
+	label := pv[512:1024]
+	copy(label, "LABELONE")
+	binary.LittleEndian.PutUint64(label[8:], 1)
+	binary.LittleEndian.PutUint32(label[20:], 32)
+	copy(label[24:], "LVM2 001")
+	copy(label[32:], id)
+	binary.LittleEndian.PutUint64(label[64:], uint64(peStart+len(data)))
+	binary.LittleEndian.PutUint64(label[72:], peStart) // data area
+	binary.LittleEndian.PutUint64(label[104:], mdaOffset)
+	binary.LittleEndian.PutUint64(label[112:], mdaSize)
+	binary.LittleEndian.PutUint32(label[16:], lvmCRC(label[20:]))
+
+	// The metadata area header
+	mda := pv[mdaOffset : mdaOffset+mdaSize]
+	copy(mda[4:], " LVM2 x[5A%r0N*>")
+	binary.LittleEndian.PutUint32(mda[20:], 1)
+	binary.LittleEndian.PutUint64(mda[24:], mdaOffset)
+	binary.LittleEndian.PutUint64(mda[32:], mdaSize)
+	binary.LittleEndian.PutUint64(mda[40:], uint64(metadataOffset))
+	binary.LittleEndian.PutUint64(mda[48:], uint64(len(metadata)))
+	binary.LittleEndian.PutUint32(mda[56:], lvmCRC([]byte(metadata)))
+	binary.LittleEndian.PutUint32(mda[0:], lvmCRC(mda[4:512]))
+
+	n := copy(mda[metadataOffset:], metadata)
+	copy(mda[512:], metadata[n:])
+
+	require.Len(t, data, extents*extentSize)
+	return append(pv, data...)
+}
+
+func lvmCRC(b []byte) uint32 {
+	return ^crc32.Update(^uint32(0xf597a6cf), crc32.IEEETable, b)
+}
+
+// extentData returns the extents, where each kilobyte has the different byte
+func extentData(first byte) []byte {
+	var data []byte
+	for i := 0; i < extents*extentSize/1024; i++ {
+		data = append(data, bytes.Repeat([]byte{first + byte(i)}, 1024)...)
+	}
+	return data
+}
+
+func TestAssemble(t *testing.T) {
+	data0, data1 := extentData('a'), extentData('A')
+
+	var pvs []*lvm.PhysicalVolume
+	for _, image := range [][]byte{
+		physicalVolume(t, "5kT0XyAwQx4GUzXv2jlKBfX3pdsLyu9s", 512, data0),
+		// The metadata wrapping around in the circular buffer
+		physicalVolume(t, "0vSP5uk7whFRRPcx9vuKr3ftnZqkHZqx", mdaSize-1000, data1),
+	} {
+		pv, err := lvm.NewPhysicalVolume(bytes.NewReader(image))
+		require.NoError(t, err)
+		pvs = append(pvs, pv)
+	}
+
+	lvs := lvm.Assemble(pvs)
+	require.Len(t, lvs, 2)
+
+	// A linear volume over the physical volumes
+	assert.Equal(t, "vg--data-root", lvs[0].Name)
+	got, err := io.ReadAll(lvs[0])
+	require.NoError(t, err)
+	assert.Equal(t, append(bytes.Clone(data0[:2*extentSize]), data1[:extentSize]...), got)
+
+	// The 1 KB stripes of the 3rd extent of pv0 and the 2nd extent of pv1
+	assert.Equal(t, "vg--data-striped--lv", lvs[1].Name)
+	got, err = io.ReadAll(lvs[1])
+	require.NoError(t, err)
+	var want []byte
+	for row := 0; row < 4; row++ {
+		want = append(want, data0[2*extentSize+row*1024:2*extentSize+(row+1)*1024]...)
+		want = append(want, data1[extentSize+row*1024:extentSize+(row+1)*1024]...)
+	}
+	assert.Equal(t, want, got)
+
+	// The volume on the missing physical volume is skipped
+	assert.Empty(t, lvm.Assemble(pvs[1:]))
+}
+
+func TestNewPhysicalVolume(t *testing.T) {
+	image := physicalVolume(t, "5kT0XyAwQx4GUzXv2jlKBfX3pdsLyu9s", 512, extentData('a'))
+
+	t.Run("not physical volume", func(t *testing.T) {
+		_, err := lvm.NewPhysicalVolume(bytes.NewReader(make([]byte, 4096)))
+		assert.ErrorIs(t, err, vm.ErrInvalidSignature)
+	})
+
+	t.Run("broken metadata", func(t *testing.T) {
+		broken := bytes.Clone(image)
+		broken[mdaOffset+1024] ^= 0xff
+		_, err := lvm.NewPhysicalVolume(bytes.NewReader(broken))
+		assert.ErrorContains(t, err, "LVM metadata checksum mismatch")
+	})
+}
@@ -0,0 +1,208 @@
+package lvm
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/xerrors"
+)
+
+// section is a section of the LVM metadata, whose values are string, int64, []any and section.
+// cf. https://github.com/lvmteam/lvm2/blob/main/lib/format_text/export.c
+//
+//	vg0 {
+//		extent_size = 8192
+//		physical_volumes {
+//			pv0 {
+//				id = "ZBW5qW-dXF2-0bGw-ZCRo-Gvhh-qtcc-TdswpD"
+//			}
+//		}
+//	}
+type section map[string]any
+
+func (s section) section(key string) section {
+	v, _ := s[key].(section)
+	return v
+}
+
+func (s section) string(key string) string {
+	v, _ := s[key].(string)
+	return v
+}
+
+func (s section) int(key string) int64 {
+	v, _ := s[key].(int64)
+	return v
+}
+
+func (s section) strings(key string) []string {
+	values, _ := s[key].([]any)
+	var ss []string
+	for _, v := range values {
+		if str, ok := v.(string); ok {
+			ss = append(ss, str)
+		}
+	}
+	return ss
+}
+
+// parseMetadata parses the metadata in the text format of LVM2
+func parseMetadata(text string) (section, error) {
+	p := &metadataParser{text: text}
+	s, err := p.section()
+	if err != nil {
+		return nil, xerrors.Errorf("LVM metadata error at %d: %w", p.pos, err)
+	}
+	if !p.eof() {
+		return nil, xerrors.Errorf("LVM metadata error at %d: unexpected '}'", p.pos)
+	}
+	return s, nil
+}
+
+type metadataParser struct {
+	text string
+	pos  int
+}
+
+func (p *metadataParser) section() (section, error) {
+	s := make(section)
+	for {
+		p.skipSpaces()
+		if p.eof() || p.peek() == '}' {
+			return s, nil
+		}
+
+		key := p.identifier()
+		if key == "" {
+			return nil, xerrors.Errorf("unexpected character %q", p.peek())
+		}
+		p.skipSpaces()
+
+		switch {
+		case p.consume('{'):
+			child, err := p.section()
+			if err != nil {
+				return nil, err
+			}
+			if !p.consume('}') {
+				return nil, xerrors.Errorf("section %q not closed", key)
+			}
+			s[key] = child
+		case p.consume('='):
+			p.skipSpaces()
+			v, err := p.value()
+			if err != nil {
+				return nil, xerrors.Errorf("%q: %w", key, err)
+			}
+			s[key] = v
+		default:
+			return nil, xerrors.Errorf("'=' or '{' expected after %q", key)
+		}
+	}
+}
+
+func (p *metadataParser) value() (any, error) {
+	switch {
+	case p.eof():
+		return nil, xerrors.New("value expected")
+	case p.consume('['):
+		var values []any
+		for {
+			p.skipSpaces()
+			if p.consume(']') {
+				return values, nil
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+			p.skipSpaces()
+			if !p.consume(',') && (p.eof() || p.peek() != ']') {
+				return nil, xerrors.New("',' or ']' expected")
+			}
+		}
+	case p.peek() == '"':
+		return p.quoted()
+	}
+
+	start := p.pos
+	for !p.eof() && (p.peek() == '-' || p.peek() == '.' || unicode.IsDigit(rune(p.peek()))) {
+		p.pos++
+	}
+	token := p.text[start:p.pos]
+	if n, err := strconv.ParseInt(token, 10, 64); err == nil {
+		return n, nil
+	} else if f, err := strconv.ParseFloat(token, 64); err == nil {
+		return f, nil
+	}
+	return nil, xerrors.Errorf("invalid value %q", token)
+}
+
+func (p *metadataParser) quoted() (string, error) {
+	p.pos++ // opening quote
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.text[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", xerrors.New("string not closed")
+			}
+			c = p.text[p.pos]
+			p.pos++
+		}
+		sb.WriteByte(c)
+	}
+	return "", xerrors.New("string not closed")
+}
+
+func (p *metadataParser) identifier() string {
+	start := p.pos
+	for !p.eof() {
+		c := rune(p.peek())
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && !strings.ContainsRune("_.+-", c) {
+			break
+		}
+		p.pos++
+	}
+	return p.text[start:p.pos]
+}
+
+// skipSpaces skips the white spaces and the comments
+func (p *metadataParser) skipSpaces() {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == '#':
+			if i := strings.IndexByte(p.text[p.pos:], '\n'); i >= 0 {
+				p.pos += i
+			} else {
+				p.pos = len(p.text)
+			}
+		case unicode.IsSpace(rune(c)) || c == 0:
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *metadataParser) consume(c byte) bool {
+	if !p.eof() && p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *metadataParser) peek() byte {
+	return p.text[p.pos]
+}
+
+func (p *metadataParser) eof() bool {
+	return p.pos >= len(p.text)
+}
@@ -0,0 +1,185 @@
+package walker
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// The archives found in images and filesystems, e.g. vendor drops and firmware bundles, are walked like directories.
+// The files in an archive are passed to WalkFunc under the path of the archive, e.g. "opt/vendor/drop.tar.zst/lib/app.jar".
+const (
+	// maxArchiveDepth limits the nesting of archives, e.g. a 7z archive in a tar.zst archive is at the depth 2.
+	maxArchiveDepth = 3
+
+	// maxDictSize limits the zstd window and the LZMA dictionary, which are allocated on decompression.
+	maxDictSize = 128 << 20 // 128MB
+)
+
+// maxArchiveSize limits the total decompressed size of each archive, so that a small archive can't expand without bound.
+var maxArchiveSize int64 = 1 << 30 // 1GB
+
+type archiveWalkFunc func(a *archive, info os.FileInfo, opener analyzer.Opener) error
+
+var archiveFormats = map[string]archiveWalkFunc{
+	".tar.zst": walkTarZstd,
+	".tzst":    walkTarZstd,
+	".7z":      walkSevenZip,
+}
+
+func archiveFormat(filePath string) archiveWalkFunc {
+	filePath = strings.ToLower(filePath)
+	for ext, fn := range archiveFormats {
+		if strings.HasSuffix(filePath, ext) {
+			return fn
+		}
+	}
+	return nil
+}
+
+// ArchivePath returns the path of the archive where the file was found, e.g. "opt/vendor/drop.7z".
+// The files in archives don't exist on the filesystem. For nested archives, the outermost one is returned.
+func ArchivePath(info os.FileInfo) (string, bool) {
+	fi, ok := info.(archiveFileInfo)
+	return fi.archive, ok
+}
+
+type archiveFileInfo struct {
+	os.FileInfo
+	archive string
+}
+
+// archive walks the files in an archive
+type archive struct {
+	*walker
+	path      string
+	root      string // The outermost archive
+	threshold int64
+	memory    *memoryLimit
+	walkFn    WalkFunc
+}
+
+// walkFuncError distinguishes the errors of WalkFunc, which stop walking, from broken or unsupported archives.
+type walkFuncError struct {
+	err error
+}
+
+func (e *walkFuncError) Error() string {
+	return e.err.Error()
+}
+
+// walkArchives wraps WalkFunc so that the files in the archives are passed to it as well.
+func (w *walker) walkArchives(fn WalkFunc, threshold int64, memory *memoryLimit) WalkFunc {
+	return w.walkNested(fn, threshold, memory, 1)
+}
+
+func (w *walker) walkNested(fn WalkFunc, threshold int64, memory *memoryLimit, depth int) WalkFunc {
+	return func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+		if err := fn(filePath, info, opener); err != nil {
+			return err
+		}
+
+		// The file name is used as the root path is "." when a file is given
+		walkFn := archiveFormat(info.Name())
+		if walkFn == nil {
+			return nil
+		} else if depth > maxArchiveDepth {
+			log.Logger.Debugf("Skipping the archive nested more than %d levels: %s", maxArchiveDepth, filePath)
+			return nil
+		}
+
+		root, ok := ArchivePath(info)
+		if !ok {
+			root = filePath
+		}
+		a := &archive{
+			walker:    w,
+			path:      filePath,
+			root:      root,
+			threshold: threshold,
+			memory:    memory,
+			walkFn:    w.walkNested(fn, threshold, memory, depth+1),
+		}
+		err := walkFn(a, info, opener)
+
+		var fnErr *walkFuncError
+		if errors.As(err, &fnErr) {
+			return fnErr.err
+		} else if err != nil {
+			// Broken or unsupported archives don't fail the scan
+			log.Logger.Debugf("Unable to walk the archive %s: %s", filePath, err)
+		}
+		return nil
+	}
+}
+
+// walkFile passes a regular file in the archive to WalkFunc
+func (a *archive) walkFile(name string, info os.FileInfo, r io.Reader) error {
+	// The file must not escape from the archive, e.g. "../../etc/passwd"
+	name = strings.TrimLeft(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+	filePath := path.Join(a.path, name)
+	if a.shouldSkipFile(filePath) {
+		return nil
+	}
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if a.shouldSkipDir(path.Join(a.path, dir)) {
+			return nil
+		}
+	}
+
+	cf := newCachedFile(info.Size(), r, a.threshold, a.memory)
+	defer func() {
+		// nolint
+		_ = cf.Clean()
+	}()
+
+	if err := a.walkFn(filePath, archiveFileInfo{FileInfo: info, archive: a.root}, cf.Open); err != nil {
+		return &walkFuncError{err: err}
+	}
+	return nil
+}
+
+func walkTarZstd(a *archive, _ os.FileInfo, opener analyzer.Opener) error {
+	rc, err := opener()
+	if err != nil {
+		return xerrors.Errorf("failed to open: %w", err)
+	}
+	defer rc.Close()
+
+	d, err := zstd.NewReader(rc, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(maxDictSize))
+	if err != nil {
+		return xerrors.Errorf("failed to open zstd: %w", err)
+	}
+	defer d.Close()
+
+	var size int64
+	tr := tar.NewReader(d)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("failed to read the tar file: %w", err)
+		}
+
+		// The skipped files are decompressed as well
+		if size += hdr.Size; size > maxArchiveSize {
+			return xerrors.Errorf("the decompressed size exceeds the limit of %d bytes", maxArchiveSize)
+		} else if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if err = a.walkFile(hdr.Name, hdr.FileInfo(), tr); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,699 @@
+package walker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ulikunitz/xz/lzma"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+)
+
+type archiveFile struct {
+	name    string
+	content string
+	dir     bool
+	mode    uint32 // Unix file mode, e.g. 0o100755
+}
+
+func TestFS_WalkArchives(t *testing.T) {
+	files := []archiveFile{
+		{
+			name:    "lib/app.jar",
+			content: "jar",
+		},
+		{
+			name:    "bin/run.sh",
+			content: "#!/bin/sh",
+			mode:    0o100755,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		fileName  string
+		archive   []byte
+		skipFiles []string
+		skipDirs  []string
+		want      map[string]string
+	}{
+		{
+			name:     "tar.zst",
+			fileName: "drop.tar.zst",
+			archive:  newTarZstd(t, files),
+			want: map[string]string{
+				"vendor/drop.tar.zst":             "",
+				"vendor/drop.tar.zst/lib/app.jar": "jar",
+				"vendor/drop.tar.zst/bin/run.sh":  "#!/bin/sh",
+			},
+		},
+		{
+			name:     "tar.zst with the path escaping from the archive",
+			fileName: "drop.tzst",
+			archive: newTarZstd(t, []archiveFile{
+				{
+					name:    "../../etc/passwd",
+					content: "root",
+				},
+			}),
+			want: map[string]string{
+				"vendor/drop.tzst":            "",
+				"vendor/drop.tzst/etc/passwd": "root",
+			},
+		},
+		{
+			name:     "solid 7z with LZMA2 and the encoded header",
+			fileName: "firmware.7z",
+			archive: newSevenZip(t, true, []sevenZipTestFolder{
+				{
+					method: szLZMA2,
+					files:  files,
+				},
+			}, []archiveFile{
+				{name: "lib", dir: true},
+				{name: "empty.txt"},
+			}),
+			want: map[string]string{
+				"vendor/firmware.7z":             "",
+				"vendor/firmware.7z/lib/app.jar": "jar",
+				"vendor/firmware.7z/bin/run.sh":  "#!/bin/sh",
+				"vendor/firmware.7z/empty.txt":   "",
+			},
+		},
+		{
+			name:     "non-solid 7z with an unsupported method",
+			fileName: "firmware.7z",
+			archive: newSevenZip(t, false, []sevenZipTestFolder{
+				{
+					method: szCopy,
+					files:  files[:1],
+				},
+				{
+					method: 0x03030103, // BCJ
+					files:  []archiveFile{{name: "bin/app.exe", content: "MZ"}},
+				},
+				{
+					method: szLZMA2,
+					files:  files[1:],
+				},
+			}, nil),
+			want: map[string]string{
+				"vendor/firmware.7z":             "",
+				"vendor/firmware.7z/lib/app.jar": "jar",
+				"vendor/firmware.7z/bin/run.sh":  "#!/bin/sh",
+			},
+		},
+		{
+			name:     "7z created by bsdtar",
+			fileName: "firmware.7z",
+			archive:  readFile(t, "testdata/bsdtar-lzma.7z"),
+			want: map[string]string{
+				"vendor/firmware.7z":             "",
+				"vendor/firmware.7z/lib/app.jar": "jar",
+				"vendor/firmware.7z/run.sh":      "#!/bin/sh",
+			},
+		},
+		{
+			name:     "skip files and dirs in archives",
+			fileName: "drop.tar.zst",
+			archive:  newTarZstd(t, files),
+			skipFiles: []string{
+				"**/app.jar",
+			},
+			skipDirs: []string{
+				"vendor/drop.tar.zst/bin",
+			},
+			want: map[string]string{
+				"vendor/drop.tar.zst": "",
+			},
+		},
+		{
+			name:     "broken archive",
+			fileName: "broken.7z",
+			archive:  []byte("7z broken"),
+			want: map[string]string{
+				"vendor/broken.7z": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.Mkdir(filepath.Join(dir, "vendor"), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "vendor", tt.fileName), tt.archive, 0o644))
+
+			w := NewFS(tt.skipFiles, tt.skipDirs, 1, nil)
+			got := walkFiles(t, func(fn WalkFunc) error {
+				return w.Walk(context.Background(), dir, fn)
+			})
+
+			// The content of the archive itself is not compared
+			got["vendor/"+tt.fileName] = ""
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFS_WalkNestedArchives(t *testing.T) {
+	// firmware.7z => level2.tar.zst => level3.7z => level4.tar.zst
+	level4 := newTarZstd(t, []archiveFile{{name: "too-deep.txt", content: "deep"}})
+	level3 := newSevenZip(t, true, []sevenZipTestFolder{
+		{
+			method: szLZMA2,
+			files: []archiveFile{
+				{name: "level3.txt", content: "3"},
+				{name: "level4.tar.zst", content: string(level4)},
+			},
+		},
+	}, nil)
+	level2 := newTarZstd(t, []archiveFile{
+		{name: "level2.txt", content: "2"},
+		{name: "level3.7z", content: string(level3)},
+	})
+	level1 := newSevenZip(t, true, []sevenZipTestFolder{
+		{
+			method: szCopy,
+			files: []archiveFile{
+				{name: "level2.tar.zst", content: string(level2)},
+			},
+		},
+	}, nil)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "firmware.7z"), level1, 0o644))
+
+	w := NewFS(nil, nil, 1, nil)
+	archives := make(map[string]string)
+	got := walkFiles(t, func(fn WalkFunc) error {
+		return w.Walk(context.Background(), dir, func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+			if archivePath, ok := ArchivePath(info); ok {
+				archives[filePath] = archivePath
+			}
+			return fn(filePath, info, opener)
+		})
+	})
+
+	assert.Equal(t, "2", got["firmware.7z/level2.tar.zst/level2.txt"])
+	assert.Equal(t, "3", got["firmware.7z/level2.tar.zst/level3.7z/level3.txt"])
+	assert.Contains(t, got, "firmware.7z/level2.tar.zst/level3.7z/level4.tar.zst")
+	assert.NotContains(t, got, "firmware.7z/level2.tar.zst/level3.7z/level4.tar.zst/too-deep.txt")
+
+	// The outermost archive is the file on the filesystem
+	assert.Equal(t, "firmware.7z", archives["firmware.7z/level2.tar.zst/level3.7z/level3.txt"])
+	assert.NotContains(t, archives, "firmware.7z")
+}
+
+func TestFS_WalkArchiveFile(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "drop.tar.zst")
+	require.NoError(t, os.WriteFile(filePath, newTarZstd(t, []archiveFile{{name: "lib/app.jar", content: "jar"}}), 0o644))
+
+	// The root path is "." when a file is given
+	w := NewFS(nil, nil, 1, nil)
+	got := walkFiles(t, func(fn WalkFunc) error {
+		return w.Walk(context.Background(), filePath, fn)
+	})
+	assert.Equal(t, "jar", got["lib/app.jar"])
+}
+
+func TestLayerTar_WalkArchives(t *testing.T) {
+	archive := newSevenZip(t, true, []sevenZipTestFolder{
+		{
+			method: szLZMA2,
+			files:  []archiveFile{{name: "lib/app.jar", content: "jar"}},
+		},
+	}, nil)
+
+	var layer bytes.Buffer
+	tw := tar.NewWriter(&layer)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name:     "opt/firmware.7z",
+		Typeflag: tar.TypeReg,
+		Size:     int64(len(archive)),
+		Mode:     0o644,
+	}))
+	_, err := tw.Write(archive)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	w := NewLayerTar(nil, nil)
+	got := walkFiles(t, func(fn WalkFunc) error {
+		_, _, err := w.Walk(context.Background(), &layer, fn)
+		return err
+	})
+	assert.Equal(t, "jar", got["opt/firmware.7z/lib/app.jar"])
+}
+
+func TestWalkArchives_SizeLimit(t *testing.T) {
+	defaultSize := maxArchiveSize
+	maxArchiveSize = 10
+	defer func() { maxArchiveSize = defaultSize }()
+
+	files := []archiveFile{
+		{name: "first.txt", content: "12345"},
+		{name: "second.txt", content: "1234567890"},
+	}
+	tests := []struct {
+		name     string
+		fileName string
+		archive  []byte
+		want     map[string]string
+	}{
+		{
+			name:     "tar.zst",
+			fileName: "drop.tar.zst",
+			archive:  newTarZstd(t, files),
+			want: map[string]string{
+				"drop.tar.zst":           "",
+				"drop.tar.zst/first.txt": "12345",
+			},
+		},
+		{
+			name:     "7z",
+			fileName: "drop.7z",
+			archive: newSevenZip(t, true, []sevenZipTestFolder{
+				{
+					method: szLZMA2,
+					files:  files,
+				},
+			}, nil),
+			want: map[string]string{
+				"drop.7z": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, tt.fileName), tt.archive, 0o644))
+
+			w := NewFS(nil, nil, 1, nil)
+			got := walkFiles(t, func(fn WalkFunc) error {
+				return w.Walk(context.Background(), dir, fn)
+			})
+			got[tt.fileName] = ""
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWalkArchives_WalkFuncError(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "drop.tar.zst"),
+		newTarZstd(t, []archiveFile{{name: "app.jar", content: "jar"}}), 0o644))
+
+	w := NewFS(nil, nil, 1, nil)
+	err := w.Walk(context.Background(), dir, func(filePath string, _ os.FileInfo, _ analyzer.Opener) error {
+		if filePath == "drop.tar.zst/app.jar" {
+			return assert.AnError
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func Test_openSevenZip_Malformed(t *testing.T) {
+	valid := newSevenZip(t, false, []sevenZipTestFolder{
+		{
+			method: szCopy,
+			files:  []archiveFile{{name: "app.jar", content: "jar"}},
+		},
+	}, nil)
+
+	tests := []struct {
+		name    string
+		modify  func(b []byte) []byte
+		wantErr string
+	}{
+		{
+			name: "invalid signature",
+			modify: func(b []byte) []byte {
+				b[0] = 'Z'
+				return b
+			},
+			wantErr: "invalid signature",
+		},
+		{
+			name: "header out of the archive",
+			modify: func(b []byte) []byte {
+				binary.LittleEndian.PutUint64(b[12:20], 1<<40)
+				binary.LittleEndian.PutUint32(b[8:12], crc32.ChecksumIEEE(b[12:32]))
+				return b
+			},
+			wantErr: "the header is out of the archive",
+		},
+		{
+			name: "too large header",
+			modify: func(b []byte) []byte {
+				binary.LittleEndian.PutUint64(b[20:28], maxSevenZipHeaderSize+1)
+				binary.LittleEndian.PutUint32(b[8:12], crc32.ChecksumIEEE(b[12:32]))
+				return b
+			},
+			wantErr: "the header size exceeds the limit",
+		},
+		{
+			name: "header checksum mismatch",
+			modify: func(b []byte) []byte {
+				b[len(b)-1] ^= 0xFF
+				return b
+			},
+			wantErr: "header checksum mismatch",
+		},
+		{
+			name: "truncated header",
+			modify: func(b []byte) []byte {
+				// Cut the header in half
+				offset := sevenZipSignatureHeaderSize + binary.LittleEndian.Uint64(b[12:20])
+				size := binary.LittleEndian.Uint64(b[20:28]) / 2
+				b = b[:offset+size]
+				binary.LittleEndian.PutUint64(b[20:28], size)
+				binary.LittleEndian.PutUint32(b[28:32], crc32.ChecksumIEEE(b[offset:]))
+				binary.LittleEndian.PutUint32(b[8:12], crc32.ChecksumIEEE(b[12:32]))
+				return b
+			},
+			wantErr: "truncated 7z header",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.modify(bytes.Clone(valid))
+			_, err := openSevenZip(bytes.NewReader(b), int64(len(b)))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+// walkFiles returns the contents of the walked files
+func walkFiles(t *testing.T, walk func(fn WalkFunc) error) map[string]string {
+	got := make(map[string]string)
+	err := walk(func(filePath string, info os.FileInfo, opener analyzer.Opener) error {
+		if info.IsDir() {
+			return nil
+		}
+		f, err := opener()
+		require.NoError(t, err)
+		defer f.Close()
+
+		b, err := io.ReadAll(f)
+		require.NoError(t, err)
+		got[filePath] = string(b)
+		return nil
+	})
+	require.NoError(t, err)
+	return got
+}
+
+func readFile(t *testing.T, filePath string) []byte {
+	b, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	return b
+}
+
+func newTarZstd(t *testing.T, files []archiveFile) []byte {
+	var buf bytes.Buffer
+	zw, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+
+	tw := tar.NewWriter(zw)
+	for _, f := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     f.name,
+			Typeflag: tar.TypeReg,
+			Size:     int64(len(f.content)),
+			Mode:     0o644,
+		}))
+		_, err = tw.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+type sevenZipTestFolder struct {
+	method uint64
+	files  []archiveFile
+}
+
+// newSevenZip creates a 7z archive. The files in each folder are compressed into a stream,
+// and the empty files and directories have no stream.
+// The header is compressed with LZMA if the archive is solid, as 7-Zip does by default.
+func newSevenZip(t *testing.T, solid bool, folders []sevenZipTestFolder, empty []archiveFile) []byte {
+	var packed []byte
+	var streams szTestWriter
+	streams.byte(szPackInfo)
+	streams.number(0)
+	streams.number(uint64(len(folders)))
+	streams.byte(szSize)
+	var unpacked [][]byte
+	for _, folder := range folders {
+		var data []byte
+		for _, f := range folder.files {
+			data = append(data, f.content...)
+		}
+		unpacked = append(unpacked, data)
+
+		p := compressSevenZip(t, folder.method, data)
+		packed = append(packed, p...)
+		streams.number(uint64(len(p)))
+	}
+	streams.byte(szEnd)
+
+	streams.byte(szUnpackInfo)
+	streams.byte(szFolder)
+	streams.number(uint64(len(folders)))
+	streams.byte(0)
+	for _, folder := range folders {
+		streams.coder(folder.method)
+	}
+	streams.byte(szCodersUnpackSize)
+	for _, data := range unpacked {
+		streams.number(uint64(len(data)))
+	}
+	streams.byte(szEnd)
+
+	streams.byte(szSubStreamsInfo)
+	streams.byte(szNumUnpackStream)
+	for _, folder := range folders {
+		streams.number(uint64(len(folder.files)))
+	}
+	streams.byte(szSize)
+	for _, folder := range folders {
+		for _, f := range folder.files[:len(folder.files)-1] {
+			streams.number(uint64(len(f.content)))
+		}
+	}
+	streams.byte(szCRC)
+	streams.byte(1) // All are defined
+	for _, folder := range folders {
+		for _, f := range folder.files {
+			streams.uint32(crc32.ChecksumIEEE([]byte(f.content)))
+		}
+	}
+	streams.byte(szEnd)
+	streams.byte(szEnd)
+
+	// The files without streams come first
+	var files []archiveFile
+	files = append(files, empty...)
+	for _, folder := range folders {
+		files = append(files, folder.files...)
+	}
+
+	var header szTestWriter
+	header.byte(szHeader)
+	header.byte(szMainStreamsInfo)
+	header.Write(streams.Bytes())
+	header.byte(szFilesInfo)
+	header.number(uint64(len(files)))
+	if len(empty) > 0 {
+		var emptyStream, emptyFile []bool
+		for i, f := range files {
+			emptyStream = append(emptyStream, i < len(empty))
+			if i < len(empty) {
+				emptyFile = append(emptyFile, !f.dir)
+			}
+		}
+		header.property(szEmptyStream, bitField(emptyStream))
+		header.property(szEmptyFile, bitField(emptyFile))
+	}
+
+	var names szTestWriter
+	names.byte(0) // Not external
+	for _, f := range files {
+		for _, c := range utf16.Encode([]rune(f.name)) {
+			names.uint16(c)
+		}
+		names.uint16(0)
+	}
+	header.property(szName, names.Bytes())
+
+	var attribs szTestWriter
+	attribs.byte(1) // All are defined
+	attribs.byte(0) // Not external
+	for _, f := range files {
+		switch {
+		case f.dir:
+			attribs.uint32(winAttributeDirectory)
+		case f.mode != 0:
+			attribs.uint32(f.mode<<16 | winAttributeUnixExtension)
+		default:
+			attribs.uint32(0x20) // FILE_ATTRIBUTE_ARCHIVE
+		}
+	}
+	header.property(szWinAttributes, attribs.Bytes())
+	header.property(0x19, []byte{0, 0}) // Padding
+	header.byte(szEnd)
+	header.byte(szEnd)
+
+	nextHeader := header.Bytes()
+	if solid {
+		// The encoded header describes the compressed header
+		compressed := compressSevenZip(t, szLZMA, nextHeader)
+		var encoded szTestWriter
+		encoded.byte(szEncodedHeader)
+		encoded.byte(szPackInfo)
+		encoded.number(uint64(len(packed)))
+		encoded.number(1)
+		encoded.byte(szSize)
+		encoded.number(uint64(len(compressed)))
+		encoded.byte(szEnd)
+		encoded.byte(szUnpackInfo)
+		encoded.byte(szFolder)
+		encoded.number(1)
+		encoded.byte(0)
+		encoded.coder(szLZMA, compressed[:5]...)
+		encoded.byte(szCodersUnpackSize)
+		encoded.number(uint64(len(nextHeader)))
+		encoded.byte(szCRC)
+		encoded.byte(1)
+		encoded.uint32(crc32.ChecksumIEEE(nextHeader))
+		encoded.byte(szEnd)
+		encoded.byte(szEnd)
+
+		packed = append(packed, compressed[5:]...)
+		nextHeader = encoded.Bytes()
+	}
+
+	sh := make([]byte, sevenZipSignatureHeaderSize)
+	copy(sh, sevenZipSignature)
+	sh[7] = 4
+	binary.LittleEndian.PutUint64(sh[12:20], uint64(len(packed)))
+	binary.LittleEndian.PutUint64(sh[20:28], uint64(len(nextHeader)))
+	binary.LittleEndian.PutUint32(sh[28:32], crc32.ChecksumIEEE(nextHeader))
+	binary.LittleEndian.PutUint32(sh[8:12], crc32.ChecksumIEEE(sh[12:32]))
+
+	return append(append(sh, packed...), nextHeader...)
+}
+
+// compressSevenZip compresses the data. The LZMA properties are prepended to the LZMA stream.
+func compressSevenZip(t *testing.T, method uint64, data []byte) []byte {
+	var buf bytes.Buffer
+	switch method {
+	case szLZMA:
+		w, err := lzma.WriterConfig{
+			DictCap: 1 << 20,
+			Size:    int64(len(data)),
+		}.NewWriter(&buf)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		// The classic LZMA header has the uncompressed size after the properties
+		b := buf.Bytes()
+		return append(b[:5:5], b[lzma.HeaderLen:]...)
+	case szLZMA2:
+		w, err := lzma.Writer2Config{DictCap: 1 << 20}.NewWriter2(&buf)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	// Copy and the unsupported methods
+	return data
+}
+
+type szTestWriter struct {
+	bytes.Buffer
+}
+
+func (w *szTestWriter) byte(c byte) {
+	w.WriteByte(c)
+}
+
+func (w *szTestWriter) uint16(n uint16) {
+	w.Write(binary.LittleEndian.AppendUint16(nil, n))
+}
+
+func (w *szTestWriter) uint32(n uint32) {
+	w.Write(binary.LittleEndian.AppendUint32(nil, n))
+}
+
+// number writes a number in the shortest of the 1, 2 and 9 byte forms
+func (w *szTestWriter) number(n uint64) {
+	switch {
+	case n < 0x80:
+		w.byte(byte(n))
+	case n < 0x4000:
+		w.byte(0x80 | byte(n>>8))
+		w.byte(byte(n))
+	default:
+		w.byte(0xFF)
+		w.Write(binary.LittleEndian.AppendUint64(nil, n))
+	}
+}
+
+// coder writes a folder with a single coder
+func (w *szTestWriter) coder(method uint64, props ...byte) {
+	var id []byte
+	for m := method; m > 0; m >>= 8 {
+		id = append([]byte{byte(m)}, id...)
+	}
+	if len(id) == 0 {
+		id = []byte{0}
+	}
+	if method == szLZMA2 {
+		props = []byte{16} // 1MB dictionary
+	}
+
+	w.number(1)
+	flags := byte(len(id))
+	if len(props) > 0 {
+		flags |= 0x20
+	}
+	w.byte(flags)
+	w.Write(id)
+	if len(props) > 0 {
+		w.number(uint64(len(props)))
+		w.Write(props)
+	}
+}
+
+func (w *szTestWriter) property(id byte, data []byte) {
+	w.byte(id)
+	w.number(uint64(len(data)))
+	w.Write(data)
+}
+
+func bitField(bits []bool) []byte {
+	b := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			b[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return b
+}
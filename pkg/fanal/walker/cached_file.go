@@ -20,38 +20,51 @@ type cachedFile struct {
 	reader io.Reader
 
 	threshold int64 //　Files larger than this threshold are written to file without being read into memory.
+	memory    *memoryLimit
 
-	content  []byte // It will be populated if this file is small
-	filePath string // It will be populated if this file is large
+	content  []byte     // It will be populated if this file is small
+	filePath string     // It will be populated if this file is large
+	spill    *spillFile // It will be populated if this file is large
+
+	// The memory for the content is released when the file is cleaned and all the readers are closed
+	mu       sync.Mutex
+	closed   *sync.Cond
+	readers  int
+	cleaned  bool
+	reserved bool
 }
 
-func newCachedFile(size int64, r io.Reader, threshold int64) *cachedFile {
-	return &cachedFile{
+func newCachedFile(size int64, r io.Reader, threshold int64, memory *memoryLimit) *cachedFile {
+	cf := &cachedFile{
 		size:      size,
 		reader:    r,
 		threshold: threshold,
+		memory:    memory,
 	}
+	cf.closed = sync.NewCond(&cf.mu)
+	return cf
 }
 
 // Open opens a file and cache the file.
-// If the file size is greater than or equal to threshold, it copies the content to a temp file and opens it next time.
+// If the file size is greater than or equal to threshold, the content is written to a temp file as the readers read it,
+// so that the temp file only takes up the size actually read by analyzers, e.g. the header of a large binary.
 // If the file size is less than threshold, it opens the file once and the content will be shared so that others analyzers can use the same data.
+// The file is also written to a temp file when the memory limit of the layer is reached.
 func (o *cachedFile) Open() (xio.ReadSeekCloserAt, error) {
 	o.once.Do(func() {
 		// When the file is large, it will be written down to a temp file.
-		if o.size >= o.threshold {
+		if o.size >= o.threshold || !o.memory.reserve(o.size) {
 			f, err := os.CreateTemp("", "fanal-*")
 			if err != nil {
 				o.err = xerrors.Errorf("failed to create the temp file: %w", err)
 				return
 			}
-
-			if _, err = io.Copy(f, o.reader); err != nil {
-				o.err = xerrors.Errorf("failed to copy: %w", err)
-				return
-			}
-
 			o.filePath = f.Name()
+			o.spill = &spillFile{
+				file:   f,
+				source: o.reader,
+				size:   o.size,
+			}
 		} else {
 			b, err := io.ReadAll(o.reader)
 			if err != nil {
@@ -59,27 +72,193 @@ func (o *cachedFile) Open() (xio.ReadSeekCloserAt, error) {
 				return
 			}
 			o.content = b
+			o.reserved = true
 		}
 	})
 	if o.err != nil {
 		return nil, xerrors.Errorf("failed to open: %w", o.err)
 	}
 
-	return o.open()
+	return o.open(), nil
 }
 
-func (o *cachedFile) open() (xio.ReadSeekCloserAt, error) {
-	if o.filePath != "" {
-		f, err := os.Open(o.filePath)
-		if err != nil {
-			return nil, xerrors.Errorf("failed to open the temp file: %w", err)
+func (o *cachedFile) open() xio.ReadSeekCloserAt {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.readers++
+
+	if o.spill != nil {
+		return &spillReader{
+			spill: o.spill,
+			close: o.closeReader,
 		}
-		return f, nil
 	}
+	return &contentReader{
+		Reader: bytes.NewReader(o.content),
+		close:  o.closeReader,
+	}
+}
 
-	return xio.NopCloser(bytes.NewReader(o.content)), nil
+func (o *cachedFile) closeReader() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.readers--
+	o.release()
+	o.closed.Broadcast()
 }
 
+// Clean removes the cached content.
+// A large file is read from the underlying reader on demand, which is no longer available once the caller moves on,
+// so Clean waits for all the readers of a large file to be closed.
 func (o *cachedFile) Clean() error {
+	o.mu.Lock()
+	o.cleaned = true
+	o.release()
+	if o.spill != nil {
+		for o.readers > 0 {
+			o.closed.Wait()
+		}
+		_ = o.spill.file.Close()
+	}
+	o.mu.Unlock()
+
 	return os.Remove(o.filePath)
 }
+
+// release releases the memory once the content is no longer used. The caller must hold the lock.
+func (o *cachedFile) release() {
+	if o.reserved && o.cleaned && o.readers == 0 {
+		o.memory.release(o.size)
+		o.reserved = false
+		o.content = nil
+	}
+}
+
+// spillChunkSize is the minimum size copied to the temp file at once to avoid small writes
+const spillChunkSize = 1 << 20
+
+// spillFile copies the source to the temp file as far as it is read
+type spillFile struct {
+	mu      sync.Mutex
+	file    *os.File
+	source  io.Reader
+	size    int64
+	written int64
+	err     error
+}
+
+// fill copies the source to the temp file until the given offset
+func (s *spillFile) fill(offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.written >= min(offset, s.size) {
+		return nil
+	}
+	offset = min(max(offset, s.written+spillChunkSize), s.size)
+	if s.err != nil {
+		return s.err
+	}
+
+	n, err := io.CopyN(s.file, s.source, offset-s.written)
+	s.written += n
+	if err != nil {
+		s.err = xerrors.Errorf("failed to copy: %w", err)
+		return s.err
+	}
+	return nil
+}
+
+type spillReader struct {
+	spill     *spillFile
+	offset    int64
+	closeOnce sync.Once
+	close     func()
+}
+
+func (r *spillReader) Read(p []byte) (int, error) {
+	if r.offset >= r.spill.size {
+		return 0, io.EOF
+	}
+	n, err := r.ReadAt(p[:min(int64(len(p)), r.spill.size-r.offset)], r.offset)
+	r.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (r *spillReader) ReadAt(p []byte, off int64) (int, error) {
+	if err := r.spill.fill(off + int64(len(p))); err != nil {
+		return 0, err
+	}
+	return r.spill.file.ReadAt(p, off)
+}
+
+func (r *spillReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.spill.size
+	}
+	if offset < 0 {
+		return 0, xerrors.New("negative position")
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+func (r *spillReader) Close() error {
+	r.closeOnce.Do(r.close)
+	return nil
+}
+
+type contentReader struct {
+	*bytes.Reader
+	closeOnce sync.Once
+	close     func()
+}
+
+func (r *contentReader) Close() error {
+	r.closeOnce.Do(r.close)
+	return nil
+}
+
+// memoryLimit limits the total size of the file contents held in memory at the same time.
+// A nil memoryLimit has no limit.
+type memoryLimit struct {
+	mu    sync.Mutex
+	limit int64
+	used  int64
+}
+
+func newMemoryLimit(limit int64) *memoryLimit {
+	if limit <= 0 {
+		return nil
+	}
+	return &memoryLimit{limit: limit}
+}
+
+// reserve reports whether the file of the given size can be held in memory, and reserves the size if so
+func (m *memoryLimit) reserve(size int64) bool {
+	if m == nil {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.used+size > m.limit {
+		return false
+	}
+	m.used += size
+	return true
+}
+
+func (m *memoryLimit) release(size int64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.used -= size
+}
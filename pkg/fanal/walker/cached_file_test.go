@@ -0,0 +1,103 @@
+package walker
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_cachedFile_memoryLimit(t *testing.T) {
+	memory := newMemoryLimit(10)
+
+	// Held in memory
+	small := newCachedFile(8, strings.NewReader("12345678"), defaultSizeThreshold, memory)
+	r1, err := small.Open()
+	require.NoError(t, err)
+	r2, err := small.Open()
+	require.NoError(t, err)
+	assert.Empty(t, small.filePath)
+
+	// Written to a temp file as the memory limit is reached
+	large := newCachedFile(4, strings.NewReader("abcd"), defaultSizeThreshold, memory)
+	r3, err := large.Open()
+	require.NoError(t, err)
+	assert.NotEmpty(t, large.filePath)
+
+	b, err := io.ReadAll(r3)
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", string(b))
+	require.NoError(t, r3.Close())
+	require.NoError(t, large.Clean())
+	assert.NoFileExists(t, large.filePath)
+
+	// The memory is released once the file is cleaned and all the readers are closed
+	require.NoError(t, r1.Close())
+	require.ErrorIs(t, small.Clean(), os.ErrNotExist)
+	assert.Equal(t, int64(8), memory.used)
+
+	b, err = io.ReadAll(r2)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678", string(b))
+	require.NoError(t, r2.Close())
+	assert.Equal(t, int64(0), memory.used)
+
+	// No limit
+	var unlimited *memoryLimit
+	assert.True(t, unlimited.reserve(1<<40))
+	unlimited.release(1 << 40)
+}
+
+func Test_cachedFile_spill(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 4*spillChunkSize/16)
+	cf := newCachedFile(int64(len(content)), bytes.NewReader(content), 1, nil)
+
+	// Only the header is written to the temp file
+	r1, err := cf.Open()
+	require.NoError(t, err)
+	head := make([]byte, 300)
+	_, err = r1.Read(head)
+	require.NoError(t, err)
+	assert.Equal(t, content[:300], head)
+
+	fi, err := os.Stat(cf.filePath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(spillChunkSize), fi.Size())
+
+	// Readers share the temp file
+	r2, err := cf.Open()
+	require.NoError(t, err)
+	pos, err := r2.Seek(-16, io.SeekEnd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)-16), pos)
+	tail, err := io.ReadAll(r2)
+	require.NoError(t, err)
+	assert.Equal(t, content[len(content)-16:], tail)
+	require.NoError(t, r2.Close())
+
+	_, err = r1.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	got, err := io.ReadAll(r1)
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+
+	// Clean waits for the readers to be closed
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, cf.Clean())
+		close(done)
+	}()
+	select {
+	case <-done:
+		require.Fail(t, "Clean returned before the reader was closed")
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.NoError(t, r1.Close())
+	<-done
+	assert.NoFileExists(t, cf.filePath)
+}
@@ -1,6 +1,7 @@
 package walker
 
 import (
+	"context"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 	swalker "github.com/saracen/walker"
 	"golang.org/x/xerrors"
 
+	"github.com/aquasecurity/trivy/pkg/debugreport"
 	"github.com/aquasecurity/trivy/pkg/log"
 	xio "github.com/aquasecurity/trivy/pkg/x/io"
 )
@@ -41,7 +43,10 @@ func NewFS(skipFiles, skipDirs []string, parallel int, errCallback ErrorCallback
 
 // Walk walks the file tree rooted at root, calling WalkFunc for each file or
 // directory in the tree, including root, but a directory to be ignored will be skipped.
-func (w FS) Walk(root string, fn WalkFunc) error {
+func (w FS) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	collector := debugreport.FromContext(ctx)
+	fn = w.walkArchives(fn, defaultSizeThreshold, nil)
+
 	// walk function called for every path found
 	walkFn := func(pathname string, fi os.FileInfo) error {
 		pathname = filepath.Clean(pathname)
@@ -56,12 +61,14 @@ func (w FS) Walk(root string, fn WalkFunc) error {
 		switch {
 		case fi.IsDir():
 			if w.shouldSkipDir(relPath) {
+				collector.AddSkip(relPath, debugreport.SkipReasonPattern)
 				return filepath.SkipDir
 			}
 			return nil
 		case !fi.Mode().IsRegular():
 			return nil
 		case w.shouldSkipFile(relPath):
+			collector.AddSkip(relPath, debugreport.SkipReasonPattern)
 			return nil
 		}
 
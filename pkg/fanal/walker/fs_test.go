@@ -1,6 +1,7 @@
 package walker_test
 
 import (
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -95,7 +96,7 @@ func TestDir_Walk(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			w := walker.NewFS(tt.fields.skipFiles, tt.fields.skipDirs, 1, tt.fields.errCallback)
 
-			err := w.Walk(tt.rootDir, tt.analyzeFn)
+			err := w.Walk(context.Background(), tt.rootDir, tt.analyzeFn)
 			if tt.wantErr != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.wantErr)
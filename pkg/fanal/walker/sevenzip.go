@@ -0,0 +1,976 @@
+package walker
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"time"
+	"unicode/utf16"
+
+	"github.com/ulikunitz/xz/lzma"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// A minimal reader of 7z archives.
+// cf. https://github.com/ip7z/7zip/blob/main/DOC/7zFormat.txt
+//
+// Only the folders with a single coder of Copy, LZMA, LZMA2, Deflate or BZip2 are supported.
+// The folders with filters, e.g. BCJ, or encryption are skipped.
+
+var sevenZipSignature = []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}
+
+const (
+	sevenZipSignatureHeaderSize = 32
+
+	// maxSevenZipHeaderSize limits the size of the header, which is read into memory.
+	maxSevenZipHeaderSize = 64 << 20 // 64MB
+)
+
+// Property IDs
+const (
+	szEnd byte = iota
+	szHeader
+	szArchiveProperties
+	szAdditionalStreamsInfo
+	szMainStreamsInfo
+	szFilesInfo
+	szPackInfo
+	szUnpackInfo
+	szSubStreamsInfo
+	szSize
+	szCRC
+	szFolder
+	szCodersUnpackSize
+	szNumUnpackStream
+	szEmptyStream
+	szEmptyFile
+	szAnti
+	szName
+	szCTime
+	szATime
+	szMTime
+	szWinAttributes
+	szComment
+	szEncodedHeader
+)
+
+// Method IDs
+const (
+	szCopy    = 0x00
+	szLZMA2   = 0x21
+	szLZMA    = 0x030101
+	szDeflate = 0x040108
+	szBZip2   = 0x040202
+)
+
+const (
+	winAttributeDirectory     = 0x10
+	winAttributeUnixExtension = 0x8000 // The high 16 bits have the Unix file mode
+	unixTypeMask              = 0xF000
+	unixTypeRegular           = 0x8000
+)
+
+var errSevenZipTruncated = xerrors.New("truncated 7z header")
+
+type sevenZipFolder struct {
+	coders     int
+	method     uint64
+	props      []byte
+	packed     int      // The number of packed streams
+	unpackSize []uint64 // The size of each output stream, the last one is the output of the folder
+	crc        bool
+}
+
+func (f sevenZipFolder) size() uint64 {
+	return f.unpackSize[len(f.unpackSize)-1]
+}
+
+type sevenZipStreams struct {
+	packPos   uint64
+	packSizes []uint64
+	folders   []sevenZipFolder
+	files     [][]uint64 // The size of each file in each folder
+}
+
+type sevenZipFile struct {
+	name      string
+	hasStream bool
+	dir       bool
+	size      uint64
+	attrib    uint32
+	modTime   time.Time
+}
+
+type sevenZip struct {
+	r       io.ReaderAt
+	size    int64
+	streams *sevenZipStreams
+	files   []sevenZipFile
+}
+
+func walkSevenZip(a *archive, info os.FileInfo, opener analyzer.Opener) error {
+	rc, err := opener()
+	if err != nil {
+		return xerrors.Errorf("failed to open: %w", err)
+	}
+	defer rc.Close()
+
+	z, err := openSevenZip(rc, info.Size())
+	if err != nil {
+		return xerrors.Errorf("7z error: %w", err)
+	}
+
+	var files []sevenZipFile
+	for _, f := range z.files {
+		if f.hasStream {
+			files = append(files, f)
+		} else if !f.dir && f.regular() {
+			// Empty files
+			if err = a.walkFile(f.name, f, bytes.NewReader(nil)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, folder := range z.streams.folders {
+		// The number of files is validated on open
+		n := len(z.streams.files[i])
+		if err = z.walkFolder(a, i, files[:n]); err != nil {
+			var fnErr *walkFuncError
+			if errors.As(err, &fnErr) {
+				return err
+			}
+			log.Logger.Debugf("Unable to walk the 7z folder %d in %s (method %x): %s", i, a.path, folder.method, err)
+		}
+		files = files[n:]
+	}
+	return nil
+}
+
+// walkFolder walks the files in the folder, which are compressed into a stream, in order.
+func (z *sevenZip) walkFolder(a *archive, i int, files []sevenZipFile) error {
+	r, err := z.folderReader(z.streams, i)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		lr := &io.LimitedReader{
+			R: r,
+			N: int64(f.size),
+		}
+		if f.regular() {
+			if err = a.walkFile(f.name, f, lr); err != nil {
+				return err
+			}
+		}
+
+		// Skip the rest of the file
+		if _, err = io.Copy(io.Discard, lr); err != nil {
+			return xerrors.Errorf("decompression error: %w", err)
+		} else if lr.N > 0 {
+			return xerrors.Errorf("decompression error: %w", io.ErrUnexpectedEOF)
+		}
+	}
+	return nil
+}
+
+func openSevenZip(r io.ReaderAt, size int64) (*sevenZip, error) {
+	sh := make([]byte, sevenZipSignatureHeaderSize)
+	if _, err := r.ReadAt(sh, 0); err != nil {
+		return nil, xerrors.Errorf("unable to read the signature header: %w", err)
+	} else if !bytes.Equal(sh[:6], sevenZipSignature) {
+		return nil, xerrors.New("invalid signature")
+	} else if sh[6] != 0 {
+		return nil, xerrors.Errorf("unsupported version: %d.%d", sh[6], sh[7])
+	} else if crc32.ChecksumIEEE(sh[12:]) != binary.LittleEndian.Uint32(sh[8:12]) {
+		return nil, xerrors.New("signature header checksum mismatch")
+	}
+
+	z := &sevenZip{
+		r:       r,
+		size:    size,
+		streams: &sevenZipStreams{},
+	}
+	offset := binary.LittleEndian.Uint64(sh[12:20])
+	headerSize := binary.LittleEndian.Uint64(sh[20:28])
+	switch {
+	case headerSize == 0:
+		// Empty archive
+		return z, nil
+	case headerSize > maxSevenZipHeaderSize:
+		return nil, xerrors.Errorf("the header size exceeds the limit: %d", headerSize)
+	case offset > uint64(size) || sevenZipSignatureHeaderSize+offset+headerSize > uint64(size):
+		return nil, xerrors.New("the header is out of the archive")
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, int64(sevenZipSignatureHeaderSize+offset)); err != nil {
+		return nil, xerrors.Errorf("unable to read the header: %w", err)
+	} else if crc32.ChecksumIEEE(header) != binary.LittleEndian.Uint32(sh[28:32]) {
+		return nil, xerrors.New("header checksum mismatch")
+	}
+
+	// The header may be compressed, but not repeatedly
+	b := &szBuffer{b: header}
+	id, err := b.byte()
+	if err != nil {
+		return nil, err
+	}
+	if id == szEncodedHeader {
+		if header, err = z.decodeHeader(b); err != nil {
+			return nil, xerrors.Errorf("encoded header error: %w", err)
+		}
+		b = &szBuffer{b: header}
+		if id, err = b.byte(); err != nil {
+			return nil, err
+		}
+	}
+	if id != szHeader {
+		return nil, xerrors.Errorf("unexpected property: %d", id)
+	}
+	if err = z.readHeader(b); err != nil {
+		return nil, xerrors.Errorf("header error: %w", err)
+	}
+
+	var total uint64
+	for _, f := range z.streams.folders {
+		if total += f.size(); total > uint64(maxArchiveSize) {
+			return nil, xerrors.Errorf("the decompressed size exceeds the limit of %d bytes", maxArchiveSize)
+		}
+	}
+	return z, nil
+}
+
+// decodeHeader decompresses the header described by the streams info
+func (z *sevenZip) decodeHeader(b *szBuffer) ([]byte, error) {
+	streams, err := readStreamsInfo(b)
+	if err != nil {
+		return nil, err
+	} else if len(streams.folders) != 1 {
+		return nil, xerrors.Errorf("unexpected number of folders: %d", len(streams.folders))
+	} else if streams.folders[0].size() > maxSevenZipHeaderSize {
+		return nil, xerrors.Errorf("the header size exceeds the limit: %d", streams.folders[0].size())
+	}
+
+	r, err := z.folderReader(streams, 0)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, streams.folders[0].size())
+	if _, err = io.ReadFull(r, header); err != nil {
+		return nil, xerrors.Errorf("decompression error: %w", err)
+	}
+	return header, nil
+}
+
+func (z *sevenZip) readHeader(b *szBuffer) error {
+	for {
+		id, err := b.byte()
+		if err != nil {
+			return err
+		}
+		switch id {
+		case szEnd:
+			return z.validate()
+		case szArchiveProperties:
+			if err = skipArchiveProperties(b); err != nil {
+				return err
+			}
+		case szAdditionalStreamsInfo:
+			if _, err = readStreamsInfo(b); err != nil {
+				return err
+			}
+		case szMainStreamsInfo:
+			if z.streams, err = readStreamsInfo(b); err != nil {
+				return err
+			}
+		case szFilesInfo:
+			if z.files, err = readFilesInfo(b); err != nil {
+				return err
+			}
+		default:
+			return xerrors.Errorf("unexpected property: %d", id)
+		}
+	}
+}
+
+// validate checks the files correspond to the streams
+func (z *sevenZip) validate() error {
+	var streams, files int
+	for _, sizes := range z.streams.files {
+		streams += len(sizes)
+	}
+	for i, f := range z.files {
+		if f.hasStream {
+			files++
+		}
+		if f.name == "" {
+			return xerrors.Errorf("no name of the file %d", i)
+		}
+	}
+	if streams != files {
+		return xerrors.Errorf("the number of streams (%d) doesn't match the number of files (%d)", streams, files)
+	}
+
+	// Assign the sizes to the files
+	var sizes []uint64
+	for _, s := range z.streams.files {
+		sizes = append(sizes, s...)
+	}
+	for i := range z.files {
+		if z.files[i].hasStream {
+			z.files[i].size, sizes = sizes[0], sizes[1:]
+		}
+	}
+	return nil
+}
+
+// folderReader returns the decompressed stream of the folder
+func (z *sevenZip) folderReader(s *sevenZipStreams, i int) (io.Reader, error) {
+	folder := s.folders[i]
+	if folder.coders != 1 || folder.packed != 1 {
+		return nil, xerrors.Errorf("unsupported coders: %d", folder.coders)
+	}
+
+	// The packed streams of the folders are stored in order
+	var pack int
+	for _, f := range s.folders[:i] {
+		pack += f.packed
+	}
+	if pack >= len(s.packSizes) {
+		return nil, xerrors.New("no packed stream")
+	}
+	offset := sevenZipSignatureHeaderSize + s.packPos
+	for _, size := range s.packSizes[:pack] {
+		offset += size
+	}
+	size := s.packSizes[pack]
+	if offset > uint64(z.size) || size > uint64(z.size)-offset {
+		return nil, xerrors.New("the packed stream is out of the archive")
+	}
+	r := io.NewSectionReader(z.r, int64(offset), int64(size))
+
+	switch folder.method {
+	case szCopy:
+		return r, nil
+	case szLZMA:
+		if len(folder.props) != 5 {
+			return nil, xerrors.New("invalid LZMA properties")
+		} else if dictSize := binary.LittleEndian.Uint32(folder.props[1:]); dictSize > maxDictSize {
+			return nil, xerrors.Errorf("the LZMA dictionary size exceeds the limit: %d", dictSize)
+		}
+		// The classic LZMA header consists of the properties and the uncompressed size
+		header := binary.LittleEndian.AppendUint64(bytes.Clone(folder.props), folder.size())
+		lr, err := lzma.NewReader(io.MultiReader(bytes.NewReader(header), r))
+		if err != nil {
+			return nil, xerrors.Errorf("LZMA error: %w", err)
+		}
+		return lr, nil
+	case szLZMA2:
+		if len(folder.props) != 1 || folder.props[0] > 40 {
+			return nil, xerrors.New("invalid LZMA2 properties")
+		}
+		p := folder.props[0]
+		dictSize := uint64(0xFFFFFFFF)
+		if p < 40 {
+			dictSize = uint64(2|p&1) << (p/2 + 11)
+		}
+		if dictSize > maxDictSize {
+			return nil, xerrors.Errorf("the LZMA2 dictionary size exceeds the limit: %d", dictSize)
+		}
+		lr, err := lzma.Reader2Config{DictCap: max(int(dictSize), lzma.MinDictCap)}.NewReader2(r)
+		if err != nil {
+			return nil, xerrors.Errorf("LZMA2 error: %w", err)
+		}
+		return lr, nil
+	case szDeflate:
+		return flate.NewReader(r), nil
+	case szBZip2:
+		return bzip2.NewReader(r), nil
+	}
+	return nil, xerrors.Errorf("unsupported method: %x", folder.method)
+}
+
+func skipArchiveProperties(b *szBuffer) error {
+	for {
+		id, err := b.byte()
+		if err != nil || id == szEnd {
+			return err
+		}
+		if err = b.skipProperty(); err != nil {
+			return err
+		}
+	}
+}
+
+func readStreamsInfo(b *szBuffer) (*sevenZipStreams, error) {
+	s := &sevenZipStreams{}
+	for {
+		id, err := b.byte()
+		if err != nil {
+			return nil, err
+		}
+		switch id {
+		case szEnd:
+			if s.files == nil {
+				// A file per folder
+				for _, f := range s.folders {
+					s.files = append(s.files, []uint64{f.size()})
+				}
+			} else if len(s.files) != len(s.folders) {
+				return nil, xerrors.New("the substreams don't match the folders")
+			}
+			return s, nil
+		case szPackInfo:
+			err = s.readPackInfo(b)
+		case szUnpackInfo:
+			err = s.readUnpackInfo(b)
+		case szSubStreamsInfo:
+			err = s.readSubStreamsInfo(b)
+		default:
+			return nil, xerrors.Errorf("unexpected property: %d", id)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *sevenZipStreams) readPackInfo(b *szBuffer) error {
+	var err error
+	if s.packPos, err = b.number(); err != nil {
+		return err
+	}
+	n, err := b.count()
+	if err != nil {
+		return err
+	}
+	for {
+		id, err := b.byte()
+		if err != nil {
+			return err
+		}
+		switch id {
+		case szEnd:
+			if len(s.packSizes) != n {
+				return xerrors.New("no sizes of the packed streams")
+			}
+			return nil
+		case szSize:
+			s.packSizes = make([]uint64, n)
+			for i := range s.packSizes {
+				if s.packSizes[i], err = b.number(); err != nil {
+					return err
+				}
+			}
+		case szCRC:
+			if _, err = b.digests(n); err != nil {
+				return err
+			}
+		default:
+			return xerrors.Errorf("unexpected property: %d", id)
+		}
+	}
+}
+
+func (s *sevenZipStreams) readUnpackInfo(b *szBuffer) error {
+	if err := b.expect(szFolder); err != nil {
+		return err
+	}
+	n, err := b.count()
+	if err != nil {
+		return err
+	}
+	if external, err := b.byte(); err != nil {
+		return err
+	} else if external != 0 {
+		return xerrors.New("external folders are not supported")
+	}
+
+	s.folders = make([]sevenZipFolder, n)
+	for i := range s.folders {
+		if s.folders[i], err = readFolder(b); err != nil {
+			return err
+		}
+	}
+
+	if err = b.expect(szCodersUnpackSize); err != nil {
+		return err
+	}
+	for i := range s.folders {
+		for j := range s.folders[i].unpackSize {
+			if s.folders[i].unpackSize[j], err = b.number(); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		id, err := b.byte()
+		if err != nil {
+			return err
+		}
+		switch id {
+		case szEnd:
+			return nil
+		case szCRC:
+			defined, err := b.digests(n)
+			if err != nil {
+				return err
+			}
+			for i := range s.folders {
+				s.folders[i].crc = defined[i]
+			}
+		default:
+			return xerrors.Errorf("unexpected property: %d", id)
+		}
+	}
+}
+
+func readFolder(b *szBuffer) (sevenZipFolder, error) {
+	var f sevenZipFolder
+	var err error
+	if f.coders, err = b.count(); err != nil {
+		return f, err
+	} else if f.coders == 0 {
+		return f, xerrors.New("no coders")
+	}
+
+	var in, out uint64
+	for i := 0; i < f.coders; i++ {
+		flags, err := b.byte()
+		if err != nil {
+			return f, err
+		} else if flags&0x80 != 0 {
+			return f, xerrors.New("alternative methods are not supported")
+		}
+
+		id, err := b.bytes(uint64(flags & 0x0F))
+		if err != nil {
+			return f, err
+		}
+		var method uint64
+		for _, c := range id {
+			method = method<<8 | uint64(c)
+		}
+
+		numIn, numOut := uint64(1), uint64(1)
+		if flags&0x10 != 0 {
+			if numIn, err = b.number(); err != nil {
+				return f, err
+			} else if numOut, err = b.number(); err != nil {
+				return f, err
+			}
+		}
+
+		var props []byte
+		if flags&0x20 != 0 {
+			size, err := b.number()
+			if err != nil {
+				return f, err
+			} else if props, err = b.bytes(size); err != nil {
+				return f, err
+			}
+		}
+
+		in += numIn
+		out += numOut
+		if in > uint64(b.len()) || out > uint64(b.len()) {
+			return f, errSevenZipTruncated
+		}
+		if i == 0 {
+			f.method, f.props = method, props
+		}
+	}
+	if out == 0 || in < out-1 {
+		return f, xerrors.New("invalid number of streams")
+	}
+
+	// The output streams of the coders except the last one are bound to the input streams of the others
+	bindPairs := out - 1
+	for i := uint64(0); i < bindPairs*2; i++ {
+		if _, err = b.number(); err != nil {
+			return f, err
+		}
+	}
+	packed := in - bindPairs
+	if packed > 1 {
+		for i := uint64(0); i < packed; i++ {
+			if _, err = b.number(); err != nil {
+				return f, err
+			}
+		}
+	}
+
+	f.packed = int(packed)
+	f.unpackSize = make([]uint64, out)
+	return f, nil
+}
+
+func (s *sevenZipStreams) readSubStreamsInfo(b *szBuffer) error {
+	nums := make([]int, len(s.folders))
+	for i := range nums {
+		nums[i] = 1
+	}
+
+	id, err := b.byte()
+	if err != nil {
+		return err
+	}
+	if id == szNumUnpackStream {
+		for i := range nums {
+			if nums[i], err = b.count(); err != nil {
+				return err
+			}
+		}
+		if id, err = b.byte(); err != nil {
+			return err
+		}
+	}
+
+	// The size of the last file in each folder is the rest of the folder
+	s.files = make([][]uint64, len(s.folders))
+	for i, folder := range s.folders {
+		if nums[i] == 0 {
+			continue
+		} else if nums[i] > 1 && id != szSize {
+			return xerrors.New("no sizes of the files")
+		}
+
+		var sum uint64
+		for j := 0; j < nums[i]-1; j++ {
+			size, err := b.number()
+			if err != nil {
+				return err
+			}
+			if sum += size; sum < size || sum > folder.size() {
+				return xerrors.New("the file sizes exceed the folder size")
+			}
+			s.files[i] = append(s.files[i], size)
+		}
+		s.files[i] = append(s.files[i], folder.size()-sum)
+	}
+	if id == szSize {
+		if id, err = b.byte(); err != nil {
+			return err
+		}
+	}
+
+	for id != szEnd {
+		if id == szCRC {
+			// The digests of the folders with a single file are given in the unpack info
+			var n int
+			for i, folder := range s.folders {
+				if nums[i] != 1 || !folder.crc {
+					n += nums[i]
+				}
+			}
+			if _, err = b.digests(n); err != nil {
+				return err
+			}
+		} else if err = b.skipProperty(); err != nil {
+			return err
+		}
+		if id, err = b.byte(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readFilesInfo(b *szBuffer) ([]sevenZipFile, error) {
+	n, err := b.count()
+	if err != nil {
+		return nil, err
+	}
+	files := make([]sevenZipFile, n)
+	for i := range files {
+		files[i].hasStream = true
+	}
+
+	var emptyStreams []int
+	for {
+		id, err := b.byte()
+		if err != nil {
+			return nil, err
+		} else if id == szEnd {
+			return files, nil
+		}
+
+		size, err := b.number()
+		if err != nil {
+			return nil, err
+		}
+		data, err := b.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		p := &szBuffer{b: data}
+
+		switch id {
+		case szEmptyStream:
+			empty, err := p.bits(n)
+			if err != nil {
+				return nil, err
+			}
+			emptyStreams = nil
+			for i, e := range empty {
+				if e {
+					emptyStreams = append(emptyStreams, i)
+				}
+				// The empty streams are directories unless they are marked as empty files
+				files[i].hasStream = !e
+				files[i].dir = e
+			}
+		case szEmptyFile:
+			empty, err := p.bits(len(emptyStreams))
+			if err != nil {
+				return nil, err
+			}
+			for i, e := range empty {
+				if e {
+					files[emptyStreams[i]].dir = false
+				}
+			}
+		case szName:
+			if err = p.expect(0); err != nil {
+				return nil, xerrors.New("external names are not supported")
+			}
+			names, err := p.names(n)
+			if err != nil {
+				return nil, err
+			}
+			for i := range files {
+				files[i].name = names[i]
+			}
+		case szWinAttributes:
+			err = p.values(n, func(i int) error {
+				attrib, err := p.uint32()
+				files[i].attrib = attrib
+				return err
+			})
+		case szMTime:
+			err = p.values(n, func(i int) error {
+				ft, err := p.uint64()
+				files[i].modTime = fileTime(ft)
+				return err
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// fileTime converts Windows FILETIME, 100-nanosecond intervals since January 1, 1601
+func fileTime(ft uint64) time.Time {
+	const epochDiff = 11644473600 // seconds between 1601-01-01 and 1970-01-01
+	return time.Unix(int64(ft/1e7)-epochDiff, int64(ft%1e7)*100).UTC()
+}
+
+func (f sevenZipFile) regular() bool {
+	if f.attrib&winAttributeUnixExtension != 0 {
+		return (f.attrib>>16)&unixTypeMask == unixTypeRegular
+	}
+	return f.attrib&winAttributeDirectory == 0
+}
+
+// sevenZipFile implements fs.FileInfo
+func (f sevenZipFile) Name() string       { return path.Base(f.name) }
+func (f sevenZipFile) Size() int64        { return int64(f.size) }
+func (f sevenZipFile) ModTime() time.Time { return f.modTime }
+func (f sevenZipFile) IsDir() bool        { return f.dir }
+func (f sevenZipFile) Sys() any           { return nil }
+
+func (f sevenZipFile) Mode() fs.FileMode {
+	switch {
+	case f.dir:
+		return fs.ModeDir | 0o755
+	case f.attrib&winAttributeUnixExtension != 0:
+		return fs.FileMode(f.attrib>>16) & fs.ModePerm
+	}
+	return 0o644
+}
+
+// szBuffer reads the header
+type szBuffer struct {
+	b []byte
+}
+
+func (b *szBuffer) len() int {
+	return len(b.b)
+}
+
+func (b *szBuffer) byte() (byte, error) {
+	if len(b.b) == 0 {
+		return 0, errSevenZipTruncated
+	}
+	c := b.b[0]
+	b.b = b.b[1:]
+	return c, nil
+}
+
+func (b *szBuffer) expect(id byte) error {
+	if c, err := b.byte(); err != nil {
+		return err
+	} else if c != id {
+		return xerrors.Errorf("unexpected property: %d", c)
+	}
+	return nil
+}
+
+func (b *szBuffer) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(b.b)) {
+		return nil, errSevenZipTruncated
+	}
+	p := b.b[:n]
+	b.b = b.b[n:]
+	return p, nil
+}
+
+func (b *szBuffer) uint32() (uint32, error) {
+	p, err := b.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(p), nil
+}
+
+func (b *szBuffer) uint64() (uint64, error) {
+	p, err := b.bytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(p), nil
+}
+
+// number reads a variable-length number, where the leading 1 bits of the first byte are the number of extra bytes
+func (b *szBuffer) number() (uint64, error) {
+	first, err := b.byte()
+	if err != nil {
+		return 0, err
+	}
+	var value uint64
+	mask := byte(0x80)
+	for i := 0; i < 8; i++ {
+		if first&mask == 0 {
+			return value | uint64(first&(mask-1))<<(8*i), nil
+		}
+		c, err := b.byte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint64(c) << (8 * i)
+		mask >>= 1
+	}
+	return value, nil
+}
+
+// count reads the number of items, which can't exceed the remaining bytes as each item takes at least a byte or a bit
+func (b *szBuffer) count() (int, error) {
+	n, err := b.number()
+	if err != nil {
+		return 0, err
+	} else if n > uint64(len(b.b))*8 {
+		return 0, errSevenZipTruncated
+	}
+	return int(n), nil
+}
+
+func (b *szBuffer) bits(n int) ([]bool, error) {
+	p, err := b.bytes(uint64((n + 7) / 8))
+	if err != nil {
+		return nil, err
+	}
+	bits := make([]bool, n)
+	for i := range bits {
+		bits[i] = p[i/8]&(0x80>>(i%8)) != 0
+	}
+	return bits, nil
+}
+
+// defined reads the bits of the defined items, which may be all defined
+func (b *szBuffer) defined(n int) ([]bool, error) {
+	all, err := b.byte()
+	if err != nil {
+		return nil, err
+	} else if all == 0 {
+		return b.bits(n)
+	}
+	defined := make([]bool, n)
+	for i := range defined {
+		defined[i] = true
+	}
+	return defined, nil
+}
+
+func (b *szBuffer) digests(n int) ([]bool, error) {
+	defined, err := b.defined(n)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range defined {
+		if d {
+			if _, err = b.uint32(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return defined, nil
+}
+
+// values reads the values of the defined files, such as the attributes and the timestamps
+func (b *szBuffer) values(n int, read func(i int) error) error {
+	defined, err := b.defined(n)
+	if err != nil {
+		return err
+	} else if err = b.expect(0); err != nil {
+		return xerrors.New("external values are not supported")
+	}
+	for i, d := range defined {
+		if d {
+			if err = read(i); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// names reads the null-terminated UTF-16LE names
+func (b *szBuffer) names(n int) ([]string, error) {
+	names := make([]string, 0, n)
+	var name []uint16
+	for len(names) < n {
+		p, err := b.bytes(2)
+		if err != nil {
+			return nil, err
+		}
+		if c := binary.LittleEndian.Uint16(p); c != 0 {
+			name = append(name, c)
+			continue
+		}
+		names = append(names, string(utf16.Decode(name)))
+		name = name[:0]
+	}
+	return names, nil
+}
+
+func (b *szBuffer) skipProperty() error {
+	size, err := b.number()
+	if err != nil {
+		return err
+	}
+	_, err = b.bytes(size)
+	return err
+}
@@ -2,6 +2,7 @@ package walker
 
 import (
 	"archive/tar"
+	"context"
 	"io"
 	"io/fs"
 	"path"
@@ -10,19 +11,26 @@ import (
 
 	"golang.org/x/xerrors"
 
+	"github.com/aquasecurity/trivy/pkg/debugreport"
 	"github.com/aquasecurity/trivy/pkg/fanal/utils"
 )
 
 const (
 	opq string = ".wh..wh..opq"
 	wh  string = ".wh."
+
+	// Windows layers have the filesystem under this directory, next to the registry hives and the utility VM.
+	// cf. https://github.com/microsoft/hcsshim/blob/main/pkg/ociwclayer/export.go
+	windowsFilesDir = "Files/"
 )
 
 var parentDir = ".." + utils.PathSeparator
 
 type LayerTar struct {
 	walker
-	threshold int64
+	threshold   int64
+	memoryLimit int64
+	windows     bool
 }
 
 func NewLayerTar(skipFiles, skipDirs []string) LayerTar {
@@ -33,7 +41,23 @@ func NewLayerTar(skipFiles, skipDirs []string) LayerTar {
 	}
 }
 
-func (w LayerTar) Walk(layer io.Reader, analyzeFn WalkFunc) ([]string, []string, error) {
+// WithMemoryLimit limits the total size of the file contents held in memory while analyzing each layer.
+// Files exceeding the limit are written to temp files instead. Zero means no limit.
+func (w LayerTar) WithMemoryLimit(limit int64) LayerTar {
+	w.memoryLimit = limit
+	return w
+}
+
+// WithWindowsLayer walks the layers of Windows container images, which have only "Files/" as the filesystem.
+func (w LayerTar) WithWindowsLayer() LayerTar {
+	w.windows = true
+	return w
+}
+
+func (w LayerTar) Walk(ctx context.Context, layer io.Reader, analyzeFn WalkFunc) ([]string, []string, error) {
+	collector := debugreport.FromContext(ctx)
+	memory := newMemoryLimit(w.memoryLimit)
+	analyzeFn = w.walkArchives(analyzeFn, w.threshold, memory)
 	var opqDirs, whFiles, skipDirs []string
 	tr := tar.NewReader(layer)
 	for {
@@ -47,6 +71,13 @@ func (w LayerTar) Walk(layer io.Reader, analyzeFn WalkFunc) ([]string, []string,
 		// filepath.Clean cannot be used since tar file paths should be OS-agnostic.
 		filePath := path.Clean(hdr.Name)
 		filePath = strings.TrimLeft(filePath, "/")
+		if w.windows {
+			var found bool
+			if filePath, found = strings.CutPrefix(filePath, windowsFilesDir); !found {
+				// e.g. Hives/Software_Delta and UtilityVM/Files/...
+				continue
+			}
+		}
 		fileDir, fileName := path.Split(filePath)
 
 		// e.g. etc/.wh..wh..opq
@@ -65,11 +96,13 @@ func (w LayerTar) Walk(layer io.Reader, analyzeFn WalkFunc) ([]string, []string,
 		switch hdr.Typeflag {
 		case tar.TypeDir:
 			if w.shouldSkipDir(filePath) {
+				collector.AddSkip(filePath, debugreport.SkipReasonPattern)
 				skipDirs = append(skipDirs, filePath)
 				continue
 			}
 		case tar.TypeReg:
 			if w.shouldSkipFile(filePath) {
+				collector.AddSkip(filePath, debugreport.SkipReasonPattern)
 				continue
 			}
 		// symlinks and hardlinks have no content in reader, skip them
@@ -82,15 +115,15 @@ func (w LayerTar) Walk(layer io.Reader, analyzeFn WalkFunc) ([]string, []string,
 		}
 
 		// A regular file will reach here.
-		if err = w.processFile(filePath, tr, hdr.FileInfo(), analyzeFn); err != nil {
+		if err = w.processFile(filePath, tr, hdr.FileInfo(), memory, analyzeFn); err != nil {
 			return nil, nil, xerrors.Errorf("failed to process the file: %w", err)
 		}
 	}
 	return opqDirs, whFiles, nil
 }
 
-func (w LayerTar) processFile(filePath string, tr *tar.Reader, fi fs.FileInfo, analyzeFn WalkFunc) error {
-	cf := newCachedFile(fi.Size(), tr, w.threshold)
+func (w LayerTar) processFile(filePath string, tr *tar.Reader, fi fs.FileInfo, memory *memoryLimit, analyzeFn WalkFunc) error {
+	cf := newCachedFile(fi.Size(), tr, w.threshold, memory)
 	defer func() {
 		// nolint
 		_ = cf.Clean()
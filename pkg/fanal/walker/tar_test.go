@@ -1,6 +1,9 @@
 package walker_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
@@ -83,7 +86,7 @@ func TestLayerTar_Walk(t *testing.T) {
 
 			w := walker.NewLayerTar(tt.fields.skipFiles, tt.fields.skipDirs)
 
-			gotOpqDirs, gotWhFiles, err := w.Walk(f, tt.analyzeFn)
+			gotOpqDirs, gotWhFiles, err := w.Walk(context.Background(), f, tt.analyzeFn)
 			if tt.wantErr != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.wantErr)
@@ -95,3 +98,31 @@ func TestLayerTar_Walk(t *testing.T) {
 		})
 	}
 }
+
+func TestLayerTar_WalkWindowsLayer(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range []string{
+		"Files/Windows/System32/drivers/etc/hosts",
+		"Files/Program Files/app/.wh.config.ini",
+		"Hives/Software_Delta",
+		"UtilityVM/Files/EFI/Microsoft/Boot/bootmgfw.efi",
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+		}))
+	}
+	require.NoError(t, tw.Close())
+
+	var got []string
+	w := walker.NewLayerTar(nil, nil).WithWindowsLayer()
+	_, gotWhFiles, err := w.Walk(context.Background(), &buf, func(filePath string, _ os.FileInfo, _ analyzer.Opener) error {
+		got = append(got, filePath)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Windows/System32/drivers/etc/hosts"}, got)
+	assert.Equal(t, []string{"Program Files/app/config.ini"}, gotWhFiles)
+}
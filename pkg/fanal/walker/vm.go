@@ -2,6 +2,8 @@ package walker
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"io/fs"
 	"path/filepath"
@@ -14,7 +16,11 @@ import (
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
+	"github.com/aquasecurity/trivy/pkg/debugreport"
+	"github.com/aquasecurity/trivy/pkg/fanal/vm"
 	"github.com/aquasecurity/trivy/pkg/fanal/vm/filesystem"
+	"github.com/aquasecurity/trivy/pkg/fanal/vm/luks"
+	"github.com/aquasecurity/trivy/pkg/fanal/vm/lvm"
 	"github.com/aquasecurity/trivy/pkg/log"
 	xio "github.com/aquasecurity/trivy/pkg/x/io"
 )
@@ -37,6 +43,7 @@ type VM struct {
 	walker
 	threshold int64
 	analyzeFn WalkFunc
+	luksKeys  [][]byte
 }
 
 func NewVM(skipFiles, skipDirs []string) *VM {
@@ -47,15 +54,22 @@ func NewVM(skipFiles, skipDirs []string) *VM {
 	}
 }
 
-func (w *VM) Walk(vreader *io.SectionReader, root string, fn WalkFunc) error {
-	// This function will be called on each file.
-	w.analyzeFn = fn
+// WithLUKSKeys unlocks the LUKS-encrypted volumes with the keys, i.e. the passphrases or the contents of the key files.
+func (w *VM) WithLUKSKeys(keys ...[]byte) *VM {
+	w.luksKeys = keys
+	return w
+}
+
+func (w *VM) Walk(ctx context.Context, vreader *io.SectionReader, root string, fn WalkFunc) error {
+	// This function will be called on each file, including the files in archives.
+	w.analyzeFn = w.walkArchives(fn, w.threshold, nil)
 
 	driver, err := disk.NewDriver(vreader)
 	if err != nil {
 		return xerrors.Errorf("failed to new disk driver: %w", err)
 	}
 
+	var pvs []*lvm.PhysicalVolume
 	for {
 		partition, err := driver.Next()
 		if err != nil {
@@ -71,30 +85,56 @@ func (w *VM) Walk(vreader *io.SectionReader, root string, fn WalkFunc) error {
 		}
 
 		// Walk each partition
-		if err = w.diskWalk(root, partition); err != nil {
+		if err = w.diskWalk(ctx, root, partition, &pvs); err != nil {
 			log.Logger.Warnf("Partition error: %s", err.Error())
 		}
 	}
+
+	// The logical volumes can span the physical volumes in the partitions
+	for _, lv := range lvm.Assemble(pvs) {
+		if err = w.volumeWalk(ctx, root, lv.Name, lv.SectionReader, nil); err != nil {
+			log.Logger.Warnf("Logical volume error: %s", err.Error())
+		}
+	}
 	return nil
 }
 
 // Inject disk partitioning processes from externally with diskWalk.
-func (w *VM) diskWalk(root string, partition types.Partition) error {
+func (w *VM) diskWalk(ctx context.Context, root string, partition types.Partition, pvs *[]*lvm.PhysicalVolume) error {
 	log.Logger.Debugf("Found partition: %s", partition.Name())
 
 	sr := partition.GetSectionReader()
+	return w.volumeWalk(ctx, root, partition.Name(), &sr, pvs)
+}
 
-	// Trivy does not support LVM scanning. It is skipped at the moment.
-	foundLVM, err := w.detectLVM(sr)
-	if err != nil {
-		return xerrors.Errorf("LVM detection error: %w", err)
-	} else if foundLVM {
-		log.Logger.Errorf("LVM is not supported, skip %s.img", partition.Name())
+// volumeWalk walks the volume, which is a LUKS volume, an LVM physical volume or a filesystem.
+// The physical volumes are appended to pvs so that the logical volumes are assembled after all the partitions are found.
+func (w *VM) volumeWalk(ctx context.Context, root, name string, sr *io.SectionReader, pvs *[]*lvm.PhysicalVolume) error {
+	if luks.IsLUKS(sr) {
+		decrypted, err := luks.Open(sr, w.luksKeys)
+		if errors.Is(err, luks.ErrInvalidKey) {
+			return xerrors.Errorf("LUKS volume %s is not unlocked, specify --luks-passphrase or --luks-key-file: %w", name, err)
+		} else if err != nil {
+			return xerrors.Errorf("LUKS error: %w", err)
+		}
+		log.Logger.Debugf("Unlocked LUKS volume: %s", name)
+		return w.volumeWalk(ctx, root, name, decrypted, pvs)
+	}
+
+	pv, err := lvm.NewPhysicalVolume(sr)
+	switch {
+	case err == nil && pvs == nil:
+		log.Logger.Warnf("LVM physical volume in the logical volume is not supported, skip %s", name)
+		return nil
+	case err == nil:
+		*pvs = append(*pvs, pv)
 		return nil
+	case !errors.Is(err, vm.ErrInvalidSignature):
+		return xerrors.Errorf("LVM error: %w", err)
 	}
 
 	// Auto-detect filesystem such as ext4 and xfs
-	fsys, clean, err := filesystem.New(sr)
+	fsys, clean, err := filesystem.New(*sr)
 	if err != nil {
 		return xerrors.Errorf("filesystem error: %w", err)
 	}
@@ -102,7 +142,7 @@ func (w *VM) diskWalk(root string, partition types.Partition) error {
 
 	err = fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
 		// Walk filesystem
-		return w.fsWalk(fsys, path, d, err)
+		return w.fsWalk(ctx, fsys, path, d, err)
 	})
 	if err != nil {
 		return xerrors.Errorf("filesystem walk error: %w", err)
@@ -110,7 +150,7 @@ func (w *VM) diskWalk(root string, partition types.Partition) error {
 	return nil
 }
 
-func (w *VM) fsWalk(fsys fs.FS, path string, d fs.DirEntry, err error) error {
+func (w *VM) fsWalk(ctx context.Context, fsys fs.FS, path string, d fs.DirEntry, err error) error {
 	if err != nil {
 		return xerrors.Errorf("fs.Walk error: %w", err)
 	}
@@ -122,12 +162,14 @@ func (w *VM) fsWalk(fsys fs.FS, path string, d fs.DirEntry, err error) error {
 	switch {
 	case fi.IsDir():
 		if w.shouldSkipDir(pathName) {
+			debugreport.FromContext(ctx).AddSkip(pathName, debugreport.SkipReasonPattern)
 			return filepath.SkipDir
 		}
 		return nil
 	case !fi.Mode().IsRegular():
 		return nil
 	case w.shouldSkipFile(pathName):
+		debugreport.FromContext(ctx).AddSkip(pathName, debugreport.SkipReasonPattern)
 		return nil
 	case fi.Mode()&0x1000 == 0x1000 ||
 		fi.Mode()&0x2000 == 0x2000 ||
@@ -181,7 +223,7 @@ func (cvf *cachedVMFile) Open() (xio.ReadSeekCloserAt, error) {
 		return nil, xerrors.Errorf("file stat error: %w", err)
 	}
 
-	cvf.cf = newCachedFile(fi.Size(), f, cvf.threshold)
+	cvf.cf = newCachedFile(fi.Size(), f, cvf.threshold, nil)
 	return cvf.cf.Open()
 }
 
@@ -192,24 +234,6 @@ func (cvf *cachedVMFile) Clean() error {
 	return cvf.cf.Clean()
 }
 
-func (w *VM) detectLVM(sr io.SectionReader) (bool, error) {
-	buf := make([]byte, 512)
-	_, err := sr.ReadAt(buf, 512)
-	if err != nil {
-		return false, xerrors.Errorf("read header block error: %w", err)
-	}
-	_, err = sr.Seek(0, io.SeekStart)
-	if err != nil {
-		return false, xerrors.Errorf("seek error: %w", err)
-	}
-
-	// LABELONE is LVM signature
-	if string(buf[:8]) == "LABELONE" {
-		return true, nil
-	}
-	return false, nil
-}
-
 func shouldSkip(partition types.Partition) bool {
 	// skip empty partition
 	if bytes.Equal(partition.GetType(), []byte{0x00}) {
@@ -0,0 +1,65 @@
+package flag
+
+// e.g. config yaml
+// bundle:
+//   templates:
+//     - contrib/html.tpl
+//   compliance-specs:
+//     - ./my-spec.yaml
+//   install-dir: /opt/trivy
+
+var (
+	BundleTemplatesFlag = Flag[[]string]{
+		Name:       "templates",
+		ConfigName: "bundle.templates",
+		Usage:      "template files to be bundled",
+	}
+	BundleComplianceSpecsFlag = Flag[[]string]{
+		Name:       "compliance-specs",
+		ConfigName: "bundle.compliance-specs",
+		Usage:      "custom compliance spec files to be bundled",
+	}
+	BundleInstallDirFlag = Flag[string]{
+		Name:       "install-dir",
+		ConfigName: "bundle.install-dir",
+		Default:    ".",
+		Usage:      "directory to install the binary, templates and compliance specs in the bundle",
+	}
+)
+
+// BundleFlagGroup defines flags for air-gapped bundles
+type BundleFlagGroup struct {
+	Templates       *Flag[[]string]
+	ComplianceSpecs *Flag[[]string]
+	InstallDir      *Flag[string]
+}
+
+type BundleOptions struct {
+	BundleTemplates       []string
+	BundleComplianceSpecs []string
+	BundleInstallDir      string
+}
+
+func (f *BundleFlagGroup) Name() string {
+	return "Bundle"
+}
+
+func (f *BundleFlagGroup) Flags() []Flagger {
+	return []Flagger{
+		f.Templates,
+		f.ComplianceSpecs,
+		f.InstallDir,
+	}
+}
+
+func (f *BundleFlagGroup) ToOptions() (BundleOptions, error) {
+	if err := parseFlags(f); err != nil {
+		return BundleOptions{}, err
+	}
+
+	return BundleOptions{
+		BundleTemplates:       f.Templates.Value(),
+		BundleComplianceSpecs: f.ComplianceSpecs.Value(),
+		BundleInstallDir:      f.InstallDir.Value(),
+	}, nil
+}
@@ -15,6 +15,7 @@ import (
 //	  clear: true
 //	  backend: "redis://localhost:6379"
 //	redis:
+//	  cluster: true
 //	  ca: ca-cert.pem
 //	  cert: cert.pem
 //	  key: key.pem
@@ -28,18 +29,33 @@ var (
 		Name:       "cache-backend",
 		ConfigName: "cache.backend",
 		Default:    "fs",
-		Usage:      "cache backend (e.g. redis://localhost:6379)",
+		Usage:      "cache backend (e.g. redis://localhost:6379, s3://bucket/prefix, gs://bucket/prefix, azblob://container/prefix)",
 	}
 	CacheTTLFlag = Flag[time.Duration]{
 		Name:       "cache-ttl",
 		ConfigName: "cache.ttl",
-		Usage:      "cache TTL when using redis as cache backend",
+		Usage:      "cache TTL when using redis as cache backend or the result cache",
+	}
+	ResultCacheFlag = Flag[bool]{
+		Name:       "result-cache",
+		ConfigName: "cache.result",
+		Usage:      "[EXPERIMENTAL] reuse the scan results of unchanged images until the TTL expires or the vulnerability DB is updated",
 	}
 	RedisTLSFlag = Flag[bool]{
 		Name:       "redis-tls",
 		ConfigName: "cache.redis.tls",
 		Usage:      "enable redis TLS with public certificates, if using redis as cache backend",
 	}
+	RedisClusterFlag = Flag[bool]{
+		Name:       "redis-cluster",
+		ConfigName: "cache.redis.cluster",
+		Usage:      "[EXPERIMENTAL] use Redis Cluster as cache backend, with the nodes in '--cache-backend' (e.g. redis://node1:6379,node2:6379)",
+	}
+	RedisSentinelMasterFlag = Flag[string]{
+		Name:       "redis-sentinel-master",
+		ConfigName: "cache.redis.sentinel.master",
+		Usage:      "[EXPERIMENTAL] name of the master monitored by Redis Sentinel, with the sentinels in '--cache-backend' (e.g. redis://sentinel1:26379,sentinel2:26379)",
+	}
 	RedisCACertFlag = Flag[string]{
 		Name:       "redis-ca",
 		ConfigName: "cache.redis.ca",
@@ -62,19 +78,27 @@ type CacheFlagGroup struct {
 	ClearCache   *Flag[bool]
 	CacheBackend *Flag[string]
 	CacheTTL     *Flag[time.Duration]
-
-	RedisTLS    *Flag[bool]
-	RedisCACert *Flag[string]
-	RedisCert   *Flag[string]
-	RedisKey    *Flag[string]
+	ResultCache  *Flag[bool]
+
+	RedisTLS            *Flag[bool]
+	RedisCluster        *Flag[bool]
+	RedisSentinelMaster *Flag[string]
+	RedisCACert         *Flag[string]
+	RedisCert           *Flag[string]
+	RedisKey            *Flag[string]
 }
 
 type CacheOptions struct {
 	ClearCache   bool
 	CacheBackend string
 	CacheTTL     time.Duration
+	ResultCache  bool
 	RedisTLS     bool
 	RedisOptions
+
+	// For Redis Cluster and Sentinel
+	RedisCluster        bool
+	RedisSentinelMaster string
 }
 
 // RedisOptions holds the options for redis cache
@@ -87,13 +111,15 @@ type RedisOptions struct {
 // NewCacheFlagGroup returns a default CacheFlagGroup
 func NewCacheFlagGroup() *CacheFlagGroup {
 	return &CacheFlagGroup{
-		ClearCache:   ClearCacheFlag.Clone(),
-		CacheBackend: CacheBackendFlag.Clone(),
-		CacheTTL:     CacheTTLFlag.Clone(),
-		RedisTLS:     RedisTLSFlag.Clone(),
-		RedisCACert:  RedisCACertFlag.Clone(),
-		RedisCert:    RedisCertFlag.Clone(),
-		RedisKey:     RedisKeyFlag.Clone(),
+		ClearCache:          ClearCacheFlag.Clone(),
+		CacheBackend:        CacheBackendFlag.Clone(),
+		CacheTTL:            CacheTTLFlag.Clone(),
+		RedisTLS:            RedisTLSFlag.Clone(),
+		RedisCluster:        RedisClusterFlag.Clone(),
+		RedisSentinelMaster: RedisSentinelMasterFlag.Clone(),
+		RedisCACert:         RedisCACertFlag.Clone(),
+		RedisCert:           RedisCertFlag.Clone(),
+		RedisKey:            RedisKeyFlag.Clone(),
 	}
 }
 
@@ -106,7 +132,10 @@ func (fg *CacheFlagGroup) Flags() []Flagger {
 		fg.ClearCache,
 		fg.CacheBackend,
 		fg.CacheTTL,
+		fg.ResultCache,
 		fg.RedisTLS,
+		fg.RedisCluster,
+		fg.RedisSentinelMaster,
 		fg.RedisCACert,
 		fg.RedisCert,
 		fg.RedisKey,
@@ -125,10 +154,11 @@ func (fg *CacheFlagGroup) ToOptions() (CacheOptions, error) {
 		RedisKey:    fg.RedisKey.Value(),
 	}
 
-	// "redis://" or "fs" are allowed for now
+	// "redis://", "s3://", "gs://", "azblob://" or "fs" are allowed for now
 	// An empty value is also allowed for testability
-	if !strings.HasPrefix(cacheBackend, "redis://") &&
-		cacheBackend != "fs" && cacheBackend != "" {
+	if !lo.SomeBy([]string{"redis://", "s3://", "gs://", "azblob://"}, func(scheme string) bool {
+		return strings.HasPrefix(cacheBackend, scheme)
+	}) && cacheBackend != "fs" && cacheBackend != "" {
 		return CacheOptions{}, xerrors.Errorf("unsupported cache backend: %s", cacheBackend)
 	}
 	// if one of redis option not nil, make sure CA, cert, and key provided
@@ -138,12 +168,23 @@ func (fg *CacheFlagGroup) ToOptions() (CacheOptions, error) {
 		}
 	}
 
+	redisCluster, sentinelMaster := fg.RedisCluster.Value(), fg.RedisSentinelMaster.Value()
+	if redisCluster && sentinelMaster != "" {
+		return CacheOptions{}, xerrors.New("'--redis-cluster' and '--redis-sentinel-master' cannot be specified together")
+	} else if (redisCluster || sentinelMaster != "") && !strings.HasPrefix(cacheBackend, "redis://") {
+		return CacheOptions{}, xerrors.New("Redis Cluster and Sentinel require the Redis cache backend")
+	}
+
 	return CacheOptions{
 		ClearCache:   fg.ClearCache.Value(),
 		CacheBackend: cacheBackend,
 		CacheTTL:     fg.CacheTTL.Value(),
+		ResultCache:  fg.ResultCache.Value(),
 		RedisTLS:     fg.RedisTLS.Value(),
 		RedisOptions: redisOptions,
+
+		RedisCluster:        redisCluster,
+		RedisSentinelMaster: sentinelMaster,
 	}, nil
 }
 
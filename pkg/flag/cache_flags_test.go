@@ -20,6 +20,9 @@ func TestCacheFlagGroup_ToOptions(t *testing.T) {
 		RedisCACert  string
 		RedisCert    string
 		RedisKey     string
+
+		RedisCluster        bool
+		RedisSentinelMaster string
 	}
 	tests := []struct {
 		name      string
@@ -77,6 +80,36 @@ func TestCacheFlagGroup_ToOptions(t *testing.T) {
 			},
 			assertion: require.NoError,
 		},
+		{
+			name: "s3",
+			fields: fields{
+				CacheBackend: "s3://bucket/prefix",
+			},
+			want: flag.CacheOptions{
+				CacheBackend: "s3://bucket/prefix",
+			},
+			assertion: require.NoError,
+		},
+		{
+			name: "gcs",
+			fields: fields{
+				CacheBackend: "gs://bucket/prefix",
+			},
+			want: flag.CacheOptions{
+				CacheBackend: "gs://bucket/prefix",
+			},
+			assertion: require.NoError,
+		},
+		{
+			name: "azure blob storage",
+			fields: fields{
+				CacheBackend: "azblob://container/prefix?account=myaccount",
+			},
+			want: flag.CacheOptions{
+				CacheBackend: "azblob://container/prefix?account=myaccount",
+			},
+			assertion: require.NoError,
+		},
 		{
 			name: "unknown backend",
 			fields: fields{
@@ -86,6 +119,51 @@ func TestCacheFlagGroup_ToOptions(t *testing.T) {
 				require.ErrorContains(t, err, "unsupported cache backend")
 			},
 		},
+		{
+			name: "redis cluster",
+			fields: fields{
+				CacheBackend: "redis://node1:6379,node2:6379",
+				RedisCluster: true,
+			},
+			want: flag.CacheOptions{
+				CacheBackend: "redis://node1:6379,node2:6379",
+				RedisCluster: true,
+			},
+			assertion: require.NoError,
+		},
+		{
+			name: "redis sentinel",
+			fields: fields{
+				CacheBackend:        "redis://sentinel1:26379,sentinel2:26379",
+				RedisSentinelMaster: "mymaster",
+			},
+			want: flag.CacheOptions{
+				CacheBackend:        "redis://sentinel1:26379,sentinel2:26379",
+				RedisSentinelMaster: "mymaster",
+			},
+			assertion: require.NoError,
+		},
+		{
+			name: "sad redis cluster and sentinel",
+			fields: fields{
+				CacheBackend:        "redis://node1:6379,node2:6379",
+				RedisCluster:        true,
+				RedisSentinelMaster: "mymaster",
+			},
+			assertion: func(t require.TestingT, err error, msgs ...interface{}) {
+				require.ErrorContains(t, err, "cannot be specified together")
+			},
+		},
+		{
+			name: "sad redis cluster with fs",
+			fields: fields{
+				CacheBackend: "fs",
+				RedisCluster: true,
+			},
+			assertion: func(t require.TestingT, err error, msgs ...interface{}) {
+				require.ErrorContains(t, err, "require the Redis cache backend")
+			},
+		},
 		{
 			name: "sad redis tls",
 			fields: fields{
@@ -106,6 +184,8 @@ func TestCacheFlagGroup_ToOptions(t *testing.T) {
 			viper.Set(flag.RedisCACertFlag.ConfigName, tt.fields.RedisCACert)
 			viper.Set(flag.RedisCertFlag.ConfigName, tt.fields.RedisCert)
 			viper.Set(flag.RedisKeyFlag.ConfigName, tt.fields.RedisKey)
+			viper.Set(flag.RedisClusterFlag.ConfigName, tt.fields.RedisCluster)
+			viper.Set(flag.RedisSentinelMasterFlag.ConfigName, tt.fields.RedisSentinelMaster)
 
 			f := &flag.CacheFlagGroup{
 				ClearCache:   flag.ClearCacheFlag.Clone(),
@@ -115,6 +195,9 @@ func TestCacheFlagGroup_ToOptions(t *testing.T) {
 				RedisCACert:  flag.RedisCACertFlag.Clone(),
 				RedisCert:    flag.RedisCertFlag.Clone(),
 				RedisKey:     flag.RedisKeyFlag.Clone(),
+
+				RedisCluster:        flag.RedisClusterFlag.Clone(),
+				RedisSentinelMaster: flag.RedisSentinelMasterFlag.Clone(),
 			}
 
 			got, err := f.ToOptions()
@@ -0,0 +1,106 @@
+package flag
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+var (
+	CatalogRepositoriesFlag = Flag[[]string]{
+		Name:       "repositories",
+		ConfigName: "catalog.repositories",
+		Usage:      "glob patterns of the repositories to be scanned. All the repositories are scanned if not specified (example: team-a/*)",
+	}
+	CatalogExcludeRepositoriesFlag = Flag[[]string]{
+		Name:       "exclude-repositories",
+		ConfigName: "catalog.exclude-repositories",
+		Usage:      "glob patterns of the repositories not to be scanned (example: */cache)",
+	}
+	CatalogTagsFlag = Flag[[]string]{
+		Name:       "tags",
+		ConfigName: "catalog.tags",
+		Usage:      "glob patterns of the tags to be scanned. All the tags are scanned if not specified (example: v*)",
+	}
+	CatalogExcludeTagsFlag = Flag[[]string]{
+		Name:       "exclude-tags",
+		ConfigName: "catalog.exclude-tags",
+		Usage:      "glob patterns of the tags not to be scanned (example: *-dev)",
+	}
+	CatalogMaxTagAgeFlag = Flag[time.Duration]{
+		Name:       "max-tag-age",
+		ConfigName: "catalog.max-tag-age",
+		Usage:      "scan only the images created within the duration. All the images are scanned if 0 (example: 720h)",
+	}
+)
+
+// CatalogFlagGroup composes the flags selecting the images in the catalog of a registry
+type CatalogFlagGroup struct {
+	Repositories        *Flag[[]string]
+	ExcludeRepositories *Flag[[]string]
+	Tags                *Flag[[]string]
+	ExcludeTags         *Flag[[]string]
+	MaxTagAge           *Flag[time.Duration]
+}
+
+type CatalogOptions struct {
+	Repositories        []string
+	ExcludeRepositories []string
+	Tags                []string
+	ExcludeTags         []string
+	MaxTagAge           time.Duration
+}
+
+func NewCatalogFlagGroup() *CatalogFlagGroup {
+	return &CatalogFlagGroup{
+		Repositories:        CatalogRepositoriesFlag.Clone(),
+		ExcludeRepositories: CatalogExcludeRepositoriesFlag.Clone(),
+		Tags:                CatalogTagsFlag.Clone(),
+		ExcludeTags:         CatalogExcludeTagsFlag.Clone(),
+		MaxTagAge:           CatalogMaxTagAgeFlag.Clone(),
+	}
+}
+
+func (f *CatalogFlagGroup) Name() string {
+	return "Catalog"
+}
+
+func (f *CatalogFlagGroup) Flags() []Flagger {
+	return []Flagger{
+		f.Repositories,
+		f.ExcludeRepositories,
+		f.Tags,
+		f.ExcludeTags,
+		f.MaxTagAge,
+	}
+}
+
+func (f *CatalogFlagGroup) ToOptions() (CatalogOptions, error) {
+	if err := parseFlags(f); err != nil {
+		return CatalogOptions{}, err
+	}
+
+	for _, patterns := range [][]string{
+		f.Repositories.Value(),
+		f.ExcludeRepositories.Value(),
+		f.Tags.Value(),
+		f.ExcludeTags.Value(),
+	} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return CatalogOptions{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	if f.MaxTagAge.Value() < 0 {
+		return CatalogOptions{}, fmt.Errorf("max tag age must be positive")
+	}
+
+	return CatalogOptions{
+		Repositories:        f.Repositories.Value(),
+		ExcludeRepositories: f.ExcludeRepositories.Value(),
+		Tags:                f.Tags.Value(),
+		ExcludeTags:         f.ExcludeTags.Value(),
+		MaxTagAge:           f.MaxTagAge.Value(),
+	}, nil
+}
@@ -8,6 +8,7 @@ import (
 
 const defaultDBRepository = "ghcr.io/aquasecurity/trivy-db"
 const defaultJavaDBRepository = "ghcr.io/aquasecurity/trivy-java-db"
+const defaultOSVAPIURL = "https://api.osv.dev"
 
 var (
 	ResetFlag = Flag[bool]{
@@ -41,6 +42,16 @@ var (
 		ConfigName: "db.java-skip-update",
 		Usage:      "skip updating Java index database",
 	}
+	JavaDBAllowStaleFlag = Flag[bool]{
+		Name:       "java-db-allow-stale",
+		ConfigName: "db.java-allow-stale",
+		Usage:      "use the outdated Java index database in the cache when it cannot be downloaded",
+	}
+	JavaDBShardsFlag = Flag[bool]{
+		Name:       "java-db-shards",
+		ConfigName: "db.java-shards",
+		Usage:      "[EXPERIMENTAL] download only the shards of Java index database needed for the scanned JAR files",
+	}
 	NoProgressFlag = Flag[bool]{
 		Name:       "no-progress",
 		ConfigName: "db.no-progress",
@@ -58,6 +69,22 @@ var (
 		Default:    defaultJavaDBRepository,
 		Usage:      "OCI repository to retrieve trivy-java-db from",
 	}
+	AdvisorySourceFlag = Flag[[]string]{
+		Name:       "advisory-source",
+		ConfigName: "db.advisory-sources",
+		Usage:      "additional advisory source merged with trivy-db (osv:<dir>, csaf:<dir>)",
+	}
+	OSVOnlineFlag = Flag[bool]{
+		Name:       "osv-online",
+		ConfigName: "db.osv-online",
+		Usage:      "[EXPERIMENTAL] query OSV.dev for packages of ecosystems not covered by trivy-db (e.g. Hackage, CRAN)",
+	}
+	OSVAPIURLFlag = Flag[string]{
+		Name:       "osv-api-url",
+		ConfigName: "db.osv-api-url",
+		Default:    defaultOSVAPIURL,
+		Usage:      "[EXPERIMENTAL] base URL of the OSV API used with '--osv-online'",
+	}
 	LightFlag = Flag[bool]{
 		Name:       "light",
 		ConfigName: "db.light",
@@ -73,9 +100,14 @@ type DBFlagGroup struct {
 	SkipDBUpdate       *Flag[bool]
 	DownloadJavaDBOnly *Flag[bool]
 	SkipJavaDBUpdate   *Flag[bool]
+	JavaDBAllowStale   *Flag[bool]
+	JavaDBShards       *Flag[bool]
 	NoProgress         *Flag[bool]
 	DBRepository       *Flag[string]
 	JavaDBRepository   *Flag[string]
+	AdvisorySources    *Flag[[]string]
+	OSVOnline          *Flag[bool]
+	OSVAPIURL          *Flag[string]
 	Light              *Flag[bool] // deprecated
 }
 
@@ -85,9 +117,14 @@ type DBOptions struct {
 	SkipDBUpdate       bool
 	DownloadJavaDBOnly bool
 	SkipJavaDBUpdate   bool
+	JavaDBAllowStale   bool
+	JavaDBShards       bool
 	NoProgress         bool
 	DBRepository       string
 	JavaDBRepository   string
+	AdvisorySources    []string
+	OSVOnline          bool
+	OSVAPIURL          string
 	Light              bool // deprecated
 }
 
@@ -99,10 +136,15 @@ func NewDBFlagGroup() *DBFlagGroup {
 		SkipDBUpdate:       SkipDBUpdateFlag.Clone(),
 		DownloadJavaDBOnly: DownloadJavaDBOnlyFlag.Clone(),
 		SkipJavaDBUpdate:   SkipJavaDBUpdateFlag.Clone(),
+		JavaDBAllowStale:   JavaDBAllowStaleFlag.Clone(),
+		JavaDBShards:       JavaDBShardsFlag.Clone(),
 		Light:              LightFlag.Clone(),
 		NoProgress:         NoProgressFlag.Clone(),
 		DBRepository:       DBRepositoryFlag.Clone(),
 		JavaDBRepository:   JavaDBRepositoryFlag.Clone(),
+		AdvisorySources:    AdvisorySourceFlag.Clone(),
+		OSVOnline:          OSVOnlineFlag.Clone(),
+		OSVAPIURL:          OSVAPIURLFlag.Clone(),
 	}
 }
 
@@ -117,9 +159,14 @@ func (f *DBFlagGroup) Flags() []Flagger {
 		f.SkipDBUpdate,
 		f.DownloadJavaDBOnly,
 		f.SkipJavaDBUpdate,
+		f.JavaDBAllowStale,
+		f.JavaDBShards,
 		f.NoProgress,
 		f.DBRepository,
 		f.JavaDBRepository,
+		f.AdvisorySources,
+		f.OSVOnline,
+		f.OSVAPIURL,
 		f.Light,
 	}
 }
@@ -151,9 +198,14 @@ func (f *DBFlagGroup) ToOptions() (DBOptions, error) {
 		SkipDBUpdate:       skipDBUpdate,
 		DownloadJavaDBOnly: downloadJavaDBOnly,
 		SkipJavaDBUpdate:   skipJavaDBUpdate,
+		JavaDBAllowStale:   f.JavaDBAllowStale.Value(),
+		JavaDBShards:       f.JavaDBShards.Value(),
 		Light:              light,
 		NoProgress:         f.NoProgress.Value(),
 		DBRepository:       f.DBRepository.Value(),
 		JavaDBRepository:   f.JavaDBRepository.Value(),
+		AdvisorySources:    f.AdvisorySources.Value(),
+		OSVOnline:          f.OSVOnline.Value(),
+		OSVAPIURL:          f.OSVAPIURL.Value(),
 	}, nil
 }
@@ -1,6 +1,7 @@
 package flag
 
 import (
+	"github.com/dustin/go-humanize"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"golang.org/x/xerrors"
 
@@ -45,6 +46,21 @@ var (
 		Default:    "",
 		Usage:      "unix domain socket path to use for docker scanning",
 	}
+	CompareFlag = Flag[string]{
+		Name:       "compare",
+		ConfigName: "image.compare",
+		Usage:      "[EXPERIMENTAL] base image to compare with, reporting only vulnerabilities and packages introduced on top of it",
+	}
+	LayerMemoryLimitFlag = Flag[string]{
+		Name:       "layer-memory-limit",
+		ConfigName: "image.layer-memory-limit",
+		Usage:      "[EXPERIMENTAL] maximum size of file contents held in memory while analyzing each layer (e.g. 512MB). Files over the limit are buffered in temporary files",
+	}
+	ScanPolicyKeyFlag = Flag[string]{
+		Name:       "scan-policy-key",
+		ConfigName: "image.scan-policy-key",
+		Usage:      "[EXPERIMENTAL] public key verifying the scan policy bundles (ignore rules, VEX and severity overrides) attached to the image as OCI referrers. The bundles are discovered only when specified",
+	}
 	SourceFlag = Flag[[]string]{
 		Name:       "image-src",
 		ConfigName: "image.source",
@@ -61,6 +77,9 @@ type ImageFlagGroup struct {
 	Platform            *Flag[string]
 	DockerHost          *Flag[string]
 	ImageSources        *Flag[[]string]
+	Compare             *Flag[string]
+	LayerMemoryLimit    *Flag[string]
+	ScanPolicyKey       *Flag[string]
 }
 
 type ImageOptions struct {
@@ -70,6 +89,9 @@ type ImageOptions struct {
 	Platform            ftypes.Platform
 	DockerHost          string
 	ImageSources        ftypes.ImageSources
+	CompareBase         string
+	LayerMemoryLimit    int64
+	ScanPolicyKey       string
 }
 
 func NewImageFlagGroup() *ImageFlagGroup {
@@ -80,6 +102,9 @@ func NewImageFlagGroup() *ImageFlagGroup {
 		Platform:            PlatformFlag.Clone(),
 		DockerHost:          DockerHostFlag.Clone(),
 		ImageSources:        SourceFlag.Clone(),
+		Compare:             CompareFlag.Clone(),
+		LayerMemoryLimit:    LayerMemoryLimitFlag.Clone(),
+		ScanPolicyKey:       ScanPolicyKeyFlag.Clone(),
 	}
 }
 
@@ -95,6 +120,9 @@ func (f *ImageFlagGroup) Flags() []Flagger {
 		f.Platform,
 		f.DockerHost,
 		f.ImageSources,
+		f.Compare,
+		f.LayerMemoryLimit,
+		f.ScanPolicyKey,
 	}
 }
 
@@ -115,6 +143,15 @@ func (f *ImageFlagGroup) ToOptions() (ImageOptions, error) {
 		platform = ftypes.Platform{Platform: pl}
 	}
 
+	var layerMemoryLimit int64
+	if l := f.LayerMemoryLimit.Value(); l != "" {
+		b, err := humanize.ParseBytes(l)
+		if err != nil {
+			return ImageOptions{}, xerrors.Errorf("unable to parse the layer memory limit: %w", err)
+		}
+		layerMemoryLimit = int64(b)
+	}
+
 	return ImageOptions{
 		Input:               f.Input.Value(),
 		ImageConfigScanners: xstrings.ToTSlice[types.Scanner](f.ImageConfigScanners.Value()),
@@ -122,5 +159,8 @@ func (f *ImageFlagGroup) ToOptions() (ImageOptions, error) {
 		Platform:            platform,
 		DockerHost:          f.DockerHost.Value(),
 		ImageSources:        xstrings.ToTSlice[ftypes.ImageSource](f.ImageSources.Value()),
+		CompareBase:         f.Compare.Value(),
+		LayerMemoryLimit:    layerMemoryLimit,
+		ScanPolicyKey:       f.ScanPolicyKey.Value(),
 	}, nil
 }
@@ -7,13 +7,14 @@ import (
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 var (
-	ClusterContextFlag = Flag[string]{
+	ClusterContextFlag = Flag[[]string]{
 		Name:       "context",
 		ConfigName: "kubernetes.context",
-		Usage:      "specify a context to scan",
+		Usage:      "specify contexts to scan. Glob patterns are allowed to scan multiple clusters (e.g. prod-*)",
 		Aliases: []Alias{
 			{Name: "ctx"},
 		},
@@ -80,6 +81,62 @@ var (
 		Default:    "ghcr.io/aquasecurity/node-collector:0.0.9",
 		Usage:      "indicate the image reference for the node-collector scan job",
 	}
+	CriticalityLabel = Flag[string]{
+		Name:       "criticality-label",
+		ConfigName: "kubernetes.criticality.label",
+		Default:    "trivy.aquasec.com/criticality",
+		Usage:      "namespace label with the criticality (critical, high, medium or low) weighing the priority of workloads",
+	}
+	CustomResourcesFlag = Flag[[]string]{
+		Name:       "custom-resources",
+		ConfigName: "kubernetes.custom-resources",
+		Usage:      "[EXPERIMENTAL] include CRDs and the custom resources of the given group/kind in the report (example: cert-manager.io/Certificate,networking.istio.io/*)",
+	}
+	KBOMNodeComponentsFlag = Flag[bool]{
+		Name:       "kbom-node-components",
+		ConfigName: "kubernetes.kbom.node-components",
+		Usage:      "[EXPERIMENTAL] run the node-collector on nodes to add node-level components, e.g. CNI plugins, to KBOM",
+	}
+	IncrementalStateFlag = Flag[string]{
+		Name:       "incremental-state",
+		ConfigName: "kubernetes.incremental.state",
+		Usage:      "[EXPERIMENTAL] path to the state file of incremental scanning. Only the workloads changed since the last run are scanned if specified",
+	}
+	IncludeNamespacesFlag = Flag[[]string]{
+		Name:       "include-namespaces",
+		ConfigName: "kubernetes.include-namespaces",
+		Usage:      "include only the resources in the given namespaces in the report. Glob patterns are allowed (example: team-*)",
+	}
+	ExcludeNamespacesFlag = Flag[[]string]{
+		Name:       "exclude-namespaces",
+		ConfigName: "kubernetes.exclude-namespaces",
+		Usage:      "exclude the resources in the given namespaces from the report. Glob patterns are allowed (example: kube-*)",
+	}
+	LabelSelectorFlag = Flag[string]{
+		Name:       "label-selector",
+		ConfigName: "kubernetes.label-selector",
+		Usage:      "include only the resources matching the label selector in the report (example: app.kubernetes.io/part-of=shop,tier!=test)",
+	}
+	OwnerAnnotationFlag = Flag[string]{
+		Name:       "owner-annotation",
+		ConfigName: "kubernetes.owner-annotation",
+		Usage:      "annotation of resources, or of their namespaces, with the owner team. The summary report is grouped by the owners if specified",
+	}
+	OwnersFlag = Flag[[]string]{
+		Name:       "owners",
+		ConfigName: "kubernetes.owners",
+		Usage:      "include only the resources of the given owners in the report (requires --owner-annotation)",
+	}
+	WatchFlag = Flag[bool]{
+		Name:       "watch",
+		ConfigName: "kubernetes.watch",
+		Usage:      "[EXPERIMENTAL] keep watching the workloads and scan them as they are created or updated, publishing the results to --webhook-url or as VulnerabilityReport resources",
+	}
+	WatchCRsFlag = Flag[bool]{
+		Name:       "watch-crs",
+		ConfigName: "kubernetes.watch-crs",
+		Usage:      "[EXPERIMENTAL] write the results of --watch as VulnerabilityReport custom resources of trivy-operator (requires the CRDs)",
+	}
 	QPS = Flag[float64]{
 		Name:       "qps",
 		ConfigName: "kubernetes.qps",
@@ -95,7 +152,7 @@ var (
 )
 
 type K8sFlagGroup struct {
-	ClusterContext         *Flag[string]
+	ClusterContext         *Flag[[]string]
 	Namespace              *Flag[string]
 	KubeConfig             *Flag[string]
 	Components             *Flag[[]string]
@@ -106,12 +163,24 @@ type K8sFlagGroup struct {
 	NodeCollectorNamespace *Flag[string]
 	ExcludeOwned           *Flag[bool]
 	ExcludeNodes           *Flag[[]string]
+	CriticalityLabel       *Flag[string]
+	CustomResources        *Flag[[]string]
+	KBOMNodeComponents     *Flag[bool]
+	IncrementalState       *Flag[string]
+	IncludeNamespaces      *Flag[[]string]
+	ExcludeNamespaces      *Flag[[]string]
+	LabelSelector          *Flag[string]
+	OwnerAnnotation        *Flag[string]
+	Owners                 *Flag[[]string]
+	Watch                  *Flag[bool]
+	WatchCRs               *Flag[bool]
 	QPS                    *Flag[float64]
 	Burst                  *Flag[int]
 }
 
 type K8sOptions struct {
-	ClusterContext         string
+	ClusterContext         string   // The context of the cluster being scanned
+	ClusterContexts        []string // The contexts or the glob patterns specified by '--context'
 	Namespace              string
 	KubeConfig             string
 	Components             []string
@@ -122,6 +191,17 @@ type K8sOptions struct {
 	NodeCollectorNamespace string
 	ExcludeOwned           bool
 	ExcludeNodes           map[string]string
+	CriticalityLabel       string
+	CustomResources        []string
+	KBOMNodeComponents     bool
+	IncrementalState       string
+	IncludeNamespaces      []string
+	ExcludeNamespaces      []string
+	LabelSelector          string
+	OwnerAnnotation        string
+	Owners                 []string
+	Watch                  bool
+	WatchCRs               bool
 	QPS                    float32
 	Burst                  int
 }
@@ -139,6 +219,17 @@ func NewK8sFlagGroup() *K8sFlagGroup {
 		ExcludeOwned:           ExcludeOwned.Clone(),
 		ExcludeNodes:           ExcludeNodes.Clone(),
 		NodeCollectorImageRef:  NodeCollectorImageRef.Clone(),
+		CriticalityLabel:       CriticalityLabel.Clone(),
+		CustomResources:        CustomResourcesFlag.Clone(),
+		KBOMNodeComponents:     KBOMNodeComponentsFlag.Clone(),
+		IncrementalState:       IncrementalStateFlag.Clone(),
+		IncludeNamespaces:      IncludeNamespacesFlag.Clone(),
+		ExcludeNamespaces:      ExcludeNamespacesFlag.Clone(),
+		LabelSelector:          LabelSelectorFlag.Clone(),
+		OwnerAnnotation:        OwnerAnnotationFlag.Clone(),
+		Owners:                 OwnersFlag.Clone(),
+		Watch:                  WatchFlag.Clone(),
+		WatchCRs:               WatchCRsFlag.Clone(),
 		QPS:                    QPS.Clone(),
 		Burst:                  Burst.Clone(),
 	}
@@ -161,6 +252,17 @@ func (f *K8sFlagGroup) Flags() []Flagger {
 		f.ExcludeOwned,
 		f.ExcludeNodes,
 		f.NodeCollectorImageRef,
+		f.CriticalityLabel,
+		f.CustomResources,
+		f.KBOMNodeComponents,
+		f.IncrementalState,
+		f.IncludeNamespaces,
+		f.ExcludeNamespaces,
+		f.LabelSelector,
+		f.OwnerAnnotation,
+		f.Owners,
+		f.Watch,
+		f.WatchCRs,
 		f.QPS,
 		f.Burst,
 	}
@@ -186,8 +288,32 @@ func (f *K8sFlagGroup) ToOptions() (K8sOptions, error) {
 		exludeNodeLabels[excludeNodeParts[0]] = excludeNodeParts[1]
 	}
 
+	for _, cr := range f.CustomResources.Value() {
+		if group, kind, ok := strings.Cut(cr, "/"); !ok || group == "" || kind == "" {
+			return K8sOptions{}, fmt.Errorf("custom resource %s must be a group/kind", cr)
+		}
+	}
+
+	if _, err = labels.Parse(f.LabelSelector.Value()); err != nil {
+		return K8sOptions{}, fmt.Errorf("invalid label selector: %w", err)
+	}
+	if len(f.Owners.Value()) > 0 && f.OwnerAnnotation.Value() == "" {
+		return K8sOptions{}, fmt.Errorf("owners require the owner annotation")
+	}
+	if f.WatchCRs.Value() && !f.Watch.Value() {
+		return K8sOptions{}, fmt.Errorf("--watch-crs requires --watch")
+	}
+
+	// The current context is used if not specified
+	var clusterContext string
+	contexts := f.ClusterContext.Value()
+	if len(contexts) == 1 {
+		clusterContext = contexts[0]
+	}
+
 	return K8sOptions{
-		ClusterContext:         f.ClusterContext.Value(),
+		ClusterContext:         clusterContext,
+		ClusterContexts:        contexts,
 		Namespace:              f.Namespace.Value(),
 		KubeConfig:             f.KubeConfig.Value(),
 		Components:             f.Components.Value(),
@@ -198,6 +324,17 @@ func (f *K8sFlagGroup) ToOptions() (K8sOptions, error) {
 		ExcludeOwned:           f.ExcludeOwned.Value(),
 		ExcludeNodes:           exludeNodeLabels,
 		NodeCollectorImageRef:  f.NodeCollectorImageRef.Value(),
+		CriticalityLabel:       f.CriticalityLabel.Value(),
+		CustomResources:        f.CustomResources.Value(),
+		KBOMNodeComponents:     f.KBOMNodeComponents.Value(),
+		IncrementalState:       f.IncrementalState.Value(),
+		IncludeNamespaces:      f.IncludeNamespaces.Value(),
+		ExcludeNamespaces:      f.ExcludeNamespaces.Value(),
+		LabelSelector:          f.LabelSelector.Value(),
+		OwnerAnnotation:        f.OwnerAnnotation.Value(),
+		Owners:                 f.Owners.Value(),
+		Watch:                  f.Watch.Value(),
+		WatchCRs:               f.WatchCRs.Value(),
 		QPS:                    float32(f.QPS.Value()),
 		Burst:                  f.Burst.Value(),
 	}, nil
@@ -45,6 +45,11 @@ var (
 		ConfigName: "misconfiguration.helm.set-string",
 		Usage:      "specify Helm string values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)",
 	}
+	HelmDownloadDepsFlag = Flag[bool]{
+		Name:       "helm-download-dependencies",
+		ConfigName: "misconfiguration.helm.download-dependencies",
+		Usage:      "download the subcharts locked in Chart.lock from their repositories when they are not cached (disabled with '--offline-scan')",
+	}
 	TfVarsFlag = Flag[[]string]{
 		Name:       "tf-vars",
 		ConfigName: "misconfiguration.terraform.vars",
@@ -61,6 +66,16 @@ var (
 		ConfigName: "misconfiguration.terraform.exclude-downloaded-modules",
 		Usage:      "exclude misconfigurations for downloaded terraform modules",
 	}
+	K8sCRDSchemasFlag = Flag[[]string]{
+		Name:       "k8s-crd-schemas",
+		ConfigName: "misconfiguration.kubernetes.crd-schemas",
+		Usage:      "specify paths to CustomResourceDefinition manifests to validate custom resources against",
+	}
+	K8sCRDSchemasFromClusterFlag = Flag[bool]{
+		Name:       "k8s-crd-schemas-from-cluster",
+		ConfigName: "misconfiguration.kubernetes.crd-schemas-from-cluster",
+		Usage:      "validate custom resources against the CustomResourceDefinitions of the cluster in the current kubeconfig context",
+	}
 	PolicyBundleRepositoryFlag = Flag[string]{
 		Name:       "policy-bundle-repository",
 		ConfigName: "misconfiguration.policy-bundle-repository",
@@ -73,6 +88,11 @@ var (
 		Default:    xstrings.ToStringSlice(analyzer.TypeConfigFiles),
 		Usage:      "comma-separated list of misconfig scanners to use for misconfiguration scanning",
 	}
+	FixDryRunFlag = Flag[bool]{
+		Name:       "fix-dry-run",
+		ConfigName: "misconfiguration.fix-dry-run",
+		Usage:      "[EXPERIMENTAL] output unified diffs fixing supported misconfigurations instead of a report",
+	}
 )
 
 // MisconfFlagGroup composes common printer flag structs used for commands providing misconfiguration scanning.
@@ -86,10 +106,18 @@ type MisconfFlagGroup struct {
 	HelmValueFiles             *Flag[[]string]
 	HelmFileValues             *Flag[[]string]
 	HelmStringValues           *Flag[[]string]
+	HelmDownloadDeps           *Flag[bool]
 	TerraformTFVars            *Flag[[]string]
 	CloudformationParamVars    *Flag[[]string]
 	TerraformExcludeDownloaded *Flag[bool]
 	MisconfigScanners          *Flag[[]string]
+
+	// Only available in 'trivy config', 'trivy filesystem', 'trivy repository' and 'trivy kubernetes'
+	K8sCRDSchemas            *Flag[[]string]
+	K8sCRDSchemasFromCluster *Flag[bool]
+
+	// Only available in 'trivy config'
+	FixDryRun *Flag[bool]
 }
 
 type MisconfOptions struct {
@@ -98,14 +126,18 @@ type MisconfOptions struct {
 	PolicyBundleRepository string
 
 	// Values Files
-	HelmValues              []string
-	HelmValueFiles          []string
-	HelmFileValues          []string
-	HelmStringValues        []string
-	TerraformTFVars         []string
-	CloudFormationParamVars []string
-	TfExcludeDownloaded     bool
-	MisconfigScanners       []analyzer.Type
+	HelmValues               []string
+	HelmValueFiles           []string
+	HelmFileValues           []string
+	HelmStringValues         []string
+	HelmDownloadDeps         bool
+	TerraformTFVars          []string
+	CloudFormationParamVars  []string
+	TfExcludeDownloaded      bool
+	K8sCRDSchemas            []string
+	K8sCRDSchemasFromCluster bool
+	MisconfigScanners        []analyzer.Type
+	FixDryRun                bool
 }
 
 func NewMisconfFlagGroup() *MisconfFlagGroup {
@@ -118,6 +150,7 @@ func NewMisconfFlagGroup() *MisconfFlagGroup {
 		HelmFileValues:             HelmSetFileFlag.Clone(),
 		HelmStringValues:           HelmSetStringFlag.Clone(),
 		HelmValueFiles:             HelmValuesFileFlag.Clone(),
+		HelmDownloadDeps:           HelmDownloadDepsFlag.Clone(),
 		TerraformTFVars:            TfVarsFlag.Clone(),
 		CloudformationParamVars:    CfParamsFlag.Clone(),
 		TerraformExcludeDownloaded: TerraformExcludeDownloaded.Clone(),
@@ -138,10 +171,14 @@ func (f *MisconfFlagGroup) Flags() []Flagger {
 		f.HelmValueFiles,
 		f.HelmFileValues,
 		f.HelmStringValues,
+		f.HelmDownloadDeps,
 		f.TerraformTFVars,
 		f.TerraformExcludeDownloaded,
 		f.CloudformationParamVars,
+		f.K8sCRDSchemas,
+		f.K8sCRDSchemasFromCluster,
 		f.MisconfigScanners,
+		f.FixDryRun,
 	}
 }
 
@@ -151,16 +188,20 @@ func (f *MisconfFlagGroup) ToOptions() (MisconfOptions, error) {
 	}
 
 	return MisconfOptions{
-		IncludeNonFailures:      f.IncludeNonFailures.Value(),
-		ResetPolicyBundle:       f.ResetPolicyBundle.Value(),
-		PolicyBundleRepository:  f.PolicyBundleRepository.Value(),
-		HelmValues:              f.HelmValues.Value(),
-		HelmValueFiles:          f.HelmValueFiles.Value(),
-		HelmFileValues:          f.HelmFileValues.Value(),
-		HelmStringValues:        f.HelmStringValues.Value(),
-		TerraformTFVars:         f.TerraformTFVars.Value(),
-		CloudFormationParamVars: f.CloudformationParamVars.Value(),
-		TfExcludeDownloaded:     f.TerraformExcludeDownloaded.Value(),
-		MisconfigScanners:       xstrings.ToTSlice[analyzer.Type](f.MisconfigScanners.Value()),
+		IncludeNonFailures:       f.IncludeNonFailures.Value(),
+		ResetPolicyBundle:        f.ResetPolicyBundle.Value(),
+		PolicyBundleRepository:   f.PolicyBundleRepository.Value(),
+		HelmValues:               f.HelmValues.Value(),
+		HelmValueFiles:           f.HelmValueFiles.Value(),
+		HelmFileValues:           f.HelmFileValues.Value(),
+		HelmStringValues:         f.HelmStringValues.Value(),
+		HelmDownloadDeps:         f.HelmDownloadDeps.Value(),
+		TerraformTFVars:          f.TerraformTFVars.Value(),
+		CloudFormationParamVars:  f.CloudformationParamVars.Value(),
+		TfExcludeDownloaded:      f.TerraformExcludeDownloaded.Value(),
+		K8sCRDSchemas:            f.K8sCRDSchemas.Value(),
+		K8sCRDSchemasFromCluster: f.K8sCRDSchemasFromCluster.Value(),
+		MisconfigScanners:        xstrings.ToTSlice[analyzer.Type](f.MisconfigScanners.Value()),
+		FixDryRun:                f.FixDryRun.Value(),
 	}, nil
 }
@@ -21,7 +21,10 @@ import (
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/plugin"
+	"github.com/aquasecurity/trivy/pkg/report/publish"
+	"github.com/aquasecurity/trivy/pkg/report/webhook"
 	"github.com/aquasecurity/trivy/pkg/result"
+	"github.com/aquasecurity/trivy/pkg/signature"
 	"github.com/aquasecurity/trivy/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/version"
 )
@@ -300,7 +303,9 @@ type Flagger interface {
 type Flags struct {
 	GlobalFlagGroup        *GlobalFlagGroup
 	AWSFlagGroup           *AWSFlagGroup
+	BundleFlagGroup        *BundleFlagGroup
 	CacheFlagGroup         *CacheFlagGroup
+	CatalogFlagGroup       *CatalogFlagGroup
 	CloudFlagGroup         *CloudFlagGroup
 	DBFlagGroup            *DBFlagGroup
 	ImageFlagGroup         *ImageFlagGroup
@@ -308,6 +313,8 @@ type Flags struct {
 	LicenseFlagGroup       *LicenseFlagGroup
 	MisconfFlagGroup       *MisconfFlagGroup
 	ModuleFlagGroup        *ModuleFlagGroup
+	PluginFlagGroup        *PluginFlagGroup
+	PublishFlagGroup       *PublishFlagGroup
 	RemoteFlagGroup        *RemoteFlagGroup
 	RegistryFlagGroup      *RegistryFlagGroup
 	RegoFlagGroup          *RegoFlagGroup
@@ -316,6 +323,8 @@ type Flags struct {
 	SBOMFlagGroup          *SBOMFlagGroup
 	ScanFlagGroup          *ScanFlagGroup
 	SecretFlagGroup        *SecretFlagGroup
+	SignatureFlagGroup     *SignatureFlagGroup
+	VMFlagGroup            *VMFlagGroup
 	VulnerabilityFlagGroup *VulnerabilityFlagGroup
 }
 
@@ -323,7 +332,9 @@ type Flags struct {
 type Options struct {
 	GlobalOptions
 	AWSOptions
+	BundleOptions
 	CacheOptions
+	CatalogOptions
 	CloudOptions
 	DBOptions
 	ImageOptions
@@ -331,6 +342,8 @@ type Options struct {
 	LicenseOptions
 	MisconfOptions
 	ModuleOptions
+	PluginOptions
+	PublishOptions
 	RegistryOptions
 	RegoOptions
 	RemoteOptions
@@ -339,6 +352,8 @@ type Options struct {
 	SBOMOptions
 	ScanOptions
 	SecretOptions
+	SignatureOptions
+	VMOptions
 	VulnerabilityOptions
 
 	// Trivy's version, not populated via CLI flags
@@ -369,6 +384,12 @@ func (o *Options) Align() {
 		log.Logger.Info(`"k8s with --format cyclonedx" disable security scanning`)
 		o.Scanners = nil
 	}
+
+	// Dependency-Track analyzes all the components in the SBOM, not only vulnerable ones
+	if o.DependencyTrackURL != "" && !o.ListAllPkgs {
+		log.Logger.Debug("'--dtrack-url' enables '--list-all-pkgs'.")
+		o.ListAllPkgs = true
+	}
 }
 
 // RegistryOpts returns options for OCI registries
@@ -382,16 +403,74 @@ func (o *Options) RegistryOpts() ftypes.RegistryOptions {
 	}
 }
 
+// SignaturePolicy returns the signers trusted in verifying the signatures of images
+func (o *Options) SignaturePolicy() signature.Policy {
+	return signature.Policy{
+		KeyPath:               o.SignatureKey,
+		CertificateIdentity:   o.CertificateIdentity,
+		CertificateOIDCIssuer: o.CertificateOIDCIssuer,
+		FulcioRoot:            o.FulcioRoot,
+		RekorPublicKey:        o.RekorPublicKey,
+	}
+}
+
+// PluginOpts returns options for installing and updating plugins
+func (o *Options) PluginOpts() plugin.Options {
+	return plugin.Options{
+		IndexURL:        o.PluginIndex,
+		Lockfile:        o.PluginLockfile,
+		PublicKeys:      o.PluginPublicKeys,
+		UpdatePolicy:    o.PluginUpdatePolicy,
+		RegistryOptions: o.RegistryOpts(),
+	}
+}
+
 // FilterOpts returns options for filtering
 func (o *Options) FilterOpts() result.FilterOption {
 	return result.FilterOption{
-		Severities:         o.Severities,
-		IgnoreStatuses:     o.IgnoreStatuses,
-		IncludeNonFailures: o.IncludeNonFailures,
-		IgnoreFile:         o.IgnoreFile,
-		PolicyFile:         o.IgnorePolicy,
-		IgnoreLicenses:     o.IgnoredLicenses,
-		VEXPath:            o.VEXPath,
+		Severities:           o.Severities,
+		IgnoreStatuses:       o.IgnoreStatuses,
+		IgnoreStatusScopes:   o.IgnoreStatusScopes,
+		IncludeNonFailures:   o.IncludeNonFailures,
+		IgnoreFile:           o.IgnoreFile,
+		RequireStatement:     o.RequireIgnoreStatement,
+		PolicyFile:           o.IgnorePolicy,
+		IgnoreLicenses:       o.IgnoredLicenses,
+		VEXPath:              o.VEXPath,
+		KEVOnly:              o.KEVOnly,
+		SkipUnreachable:      o.SkipUnreachable,
+		DedupeAliases:        !o.NoDedupeAliases,
+		SeverityOverrideFile: o.SeverityOverrideFile,
+	}
+}
+
+// WebhookOpts returns options for the webhook notification
+func (o *Options) WebhookOpts() webhook.Options {
+	return webhook.Options{
+		URL:        o.WebhookURL,
+		Template:   o.WebhookTemplate,
+		Threshold:  o.WebhookThreshold,
+		Severities: o.Severities,
+	}
+}
+
+// PublishOpts returns options for uploading the results to vulnerability management platforms
+func (o *Options) PublishOpts() publish.Options {
+	return publish.Options{
+		AppVersion: o.AppVersion,
+		DependencyTrack: publish.DependencyTrackOptions{
+			URL:            o.DependencyTrackURL,
+			APIKey:         o.DependencyTrackAPIKey,
+			Project:        o.DependencyTrackProject,
+			ProjectVersion: o.DependencyTrackProjectVersion,
+		},
+		DefectDojo: publish.DefectDojoOptions{
+			URL:         o.DefectDojoURL,
+			APIKey:      o.DefectDojoAPIKey,
+			Product:     o.DefectDojoProduct,
+			ProductType: o.DefectDojoProductType,
+			Engagement:  o.DefectDojoEngagement,
+		},
 	}
 }
 
@@ -466,6 +545,15 @@ func (f *Flags) groups() []FlagGroup {
 	if f.ImageFlagGroup != nil {
 		groups = append(groups, f.ImageFlagGroup)
 	}
+	if f.CatalogFlagGroup != nil {
+		groups = append(groups, f.CatalogFlagGroup)
+	}
+	if f.SignatureFlagGroup != nil {
+		groups = append(groups, f.SignatureFlagGroup)
+	}
+	if f.VMFlagGroup != nil {
+		groups = append(groups, f.VMFlagGroup)
+	}
 	if f.SBOMFlagGroup != nil {
 		groups = append(groups, f.SBOMFlagGroup)
 	}
@@ -478,6 +566,9 @@ func (f *Flags) groups() []FlagGroup {
 	if f.ModuleFlagGroup != nil {
 		groups = append(groups, f.ModuleFlagGroup)
 	}
+	if f.PluginFlagGroup != nil {
+		groups = append(groups, f.PluginFlagGroup)
+	}
 	if f.SecretFlagGroup != nil {
 		groups = append(groups, f.SecretFlagGroup)
 	}
@@ -502,6 +593,12 @@ func (f *Flags) groups() []FlagGroup {
 	if f.RepoFlagGroup != nil {
 		groups = append(groups, f.RepoFlagGroup)
 	}
+	if f.BundleFlagGroup != nil {
+		groups = append(groups, f.BundleFlagGroup)
+	}
+	if f.PublishFlagGroup != nil {
+		groups = append(groups, f.PublishFlagGroup)
+	}
 	return groups
 }
 
@@ -586,6 +683,13 @@ func (f *Flags) ToOptions(args []string) (Options, error) {
 		}
 	}
 
+	if f.BundleFlagGroup != nil {
+		opts.BundleOptions, err = f.BundleFlagGroup.ToOptions()
+		if err != nil {
+			return Options{}, xerrors.Errorf("bundle flag error: %w", err)
+		}
+	}
+
 	if f.CacheFlagGroup != nil {
 		opts.CacheOptions, err = f.CacheFlagGroup.ToOptions()
 		if err != nil {
@@ -593,6 +697,13 @@ func (f *Flags) ToOptions(args []string) (Options, error) {
 		}
 	}
 
+	if f.CatalogFlagGroup != nil {
+		opts.CatalogOptions, err = f.CatalogFlagGroup.ToOptions()
+		if err != nil {
+			return Options{}, xerrors.Errorf("catalog flag error: %w", err)
+		}
+	}
+
 	if f.DBFlagGroup != nil {
 		opts.DBOptions, err = f.DBFlagGroup.ToOptions()
 		if err != nil {
@@ -635,6 +746,20 @@ func (f *Flags) ToOptions(args []string) (Options, error) {
 		}
 	}
 
+	if f.PluginFlagGroup != nil {
+		opts.PluginOptions, err = f.PluginFlagGroup.ToOptions()
+		if err != nil {
+			return Options{}, xerrors.Errorf("plugin flag error: %w", err)
+		}
+	}
+
+	if f.PublishFlagGroup != nil {
+		opts.PublishOptions, err = f.PublishFlagGroup.ToOptions()
+		if err != nil {
+			return Options{}, xerrors.Errorf("publish flag error: %w", err)
+		}
+	}
+
 	if f.RegoFlagGroup != nil {
 		opts.RegoOptions, err = f.RegoFlagGroup.ToOptions()
 		if err != nil {
@@ -691,6 +816,20 @@ func (f *Flags) ToOptions(args []string) (Options, error) {
 		}
 	}
 
+	if f.SignatureFlagGroup != nil {
+		opts.SignatureOptions, err = f.SignatureFlagGroup.ToOptions()
+		if err != nil {
+			return Options{}, xerrors.Errorf("signature flag error: %w", err)
+		}
+	}
+
+	if f.VMFlagGroup != nil {
+		opts.VMOptions, err = f.VMFlagGroup.ToOptions()
+		if err != nil {
+			return Options{}, xerrors.Errorf("vm flag error: %w", err)
+		}
+	}
+
 	if f.VulnerabilityFlagGroup != nil {
 		opts.VulnerabilityOptions, err = f.VulnerabilityFlagGroup.ToOptions()
 		if err != nil {
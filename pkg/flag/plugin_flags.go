@@ -0,0 +1,94 @@
+package flag
+
+import (
+	"github.com/aquasecurity/trivy/pkg/plugin"
+)
+
+// e.g. config yaml
+// plugin:
+//   index: "https://aquasecurity.github.io/trivy-plugin-index/v1/index.yaml"
+//   lockfile: "trivy-plugins.lock.yaml"
+//   public-keys:
+//     - "/path/to/key.pub"
+//   update-policy: minor
+
+var (
+	PluginIndexFlag = Flag[string]{
+		Name:       "plugin-index",
+		ConfigName: "plugin.index",
+		Default:    plugin.DefaultIndexURL,
+		Usage:      "[EXPERIMENTAL] HTTP(S) URL or OCI repository of the plugin index to resolve plugin names",
+	}
+	PluginLockfileFlag = Flag[string]{
+		Name:       "plugin-lockfile",
+		ConfigName: "plugin.lockfile",
+		Usage:      "[EXPERIMENTAL] path to the lockfile pinning the sources and digests of the installed plugins",
+	}
+	PluginPublicKeyFlag = Flag[[]string]{
+		Name:       "plugin-public-key",
+		ConfigName: "plugin.public-keys",
+		Usage:      "[EXPERIMENTAL] path to the Ed25519 public key in PEM to verify the signature of plugins. Plugins without a valid signature are rejected if specified",
+	}
+	PluginUpdatePolicyFlag = Flag[string]{
+		Name:       "plugin-update-policy",
+		ConfigName: "plugin.update-policy",
+		Default:    string(plugin.UpdatePolicyMinor),
+		Values: []string{
+			string(plugin.UpdatePolicyNone),
+			string(plugin.UpdatePolicyPatch),
+			string(plugin.UpdatePolicyMinor),
+			string(plugin.UpdatePolicyMajor),
+		},
+		Usage: "[EXPERIMENTAL] version bumps allowed when updating plugins in the index",
+	}
+)
+
+// PluginFlagGroup defines flags for installing and updating plugins
+type PluginFlagGroup struct {
+	Index        *Flag[string]
+	Lockfile     *Flag[string]
+	PublicKeys   *Flag[[]string]
+	UpdatePolicy *Flag[string]
+}
+
+type PluginOptions struct {
+	PluginIndex        string
+	PluginLockfile     string
+	PluginPublicKeys   []string
+	PluginUpdatePolicy plugin.UpdatePolicy
+}
+
+func NewPluginFlagGroup() *PluginFlagGroup {
+	return &PluginFlagGroup{
+		Index:        PluginIndexFlag.Clone(),
+		Lockfile:     PluginLockfileFlag.Clone(),
+		PublicKeys:   PluginPublicKeyFlag.Clone(),
+		UpdatePolicy: PluginUpdatePolicyFlag.Clone(),
+	}
+}
+
+func (f *PluginFlagGroup) Name() string {
+	return "Plugin"
+}
+
+func (f *PluginFlagGroup) Flags() []Flagger {
+	return []Flagger{
+		f.Index,
+		f.Lockfile,
+		f.PublicKeys,
+		f.UpdatePolicy,
+	}
+}
+
+func (f *PluginFlagGroup) ToOptions() (PluginOptions, error) {
+	if err := parseFlags(f); err != nil {
+		return PluginOptions{}, err
+	}
+
+	return PluginOptions{
+		PluginIndex:        f.Index.Value(),
+		PluginLockfile:     f.Lockfile.Value(),
+		PluginPublicKeys:   f.PublicKeys.Value(),
+		PluginUpdatePolicy: plugin.UpdatePolicy(f.UpdatePolicy.Value()),
+	}, nil
+}
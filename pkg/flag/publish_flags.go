@@ -0,0 +1,148 @@
+package flag
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// e.g. config yaml:
+//
+//	dependency-track:
+//	  url: https://dtrack.example.com
+//	  project: my-app
+//	defectdojo:
+//	  url: https://defectdojo.example.com
+//	  product: my-app
+var (
+	DependencyTrackURLFlag = Flag[string]{
+		Name:       "dtrack-url",
+		ConfigName: "dependency-track.url",
+		Usage:      "[EXPERIMENTAL] URL of Dependency-Track to upload the CycloneDX SBOM of the scan to",
+	}
+	DependencyTrackAPIKeyFlag = Flag[string]{
+		Name:       "dtrack-api-key",
+		ConfigName: "dependency-track.api-key",
+		Usage:      "API key of Dependency-Track with the BOM_UPLOAD and PROJECT_CREATION_UPLOAD permissions",
+	}
+	DependencyTrackProjectFlag = Flag[string]{
+		Name:       "dtrack-project",
+		ConfigName: "dependency-track.project",
+		Usage:      "name of the Dependency-Track project, which is created if it doesn't exist (default: the artifact name)",
+	}
+	DependencyTrackProjectVersionFlag = Flag[string]{
+		Name:       "dtrack-project-version",
+		ConfigName: "dependency-track.project-version",
+		Usage:      "version of the Dependency-Track project",
+	}
+	DefectDojoURLFlag = Flag[string]{
+		Name:       "defectdojo-url",
+		ConfigName: "defectdojo.url",
+		Usage:      "[EXPERIMENTAL] URL of DefectDojo to reimport the JSON report of the scan to",
+	}
+	DefectDojoAPIKeyFlag = Flag[string]{
+		Name:       "defectdojo-api-key",
+		ConfigName: "defectdojo.api-key",
+		Usage:      "API v2 key of DefectDojo",
+	}
+	DefectDojoProductFlag = Flag[string]{
+		Name:       "defectdojo-product",
+		ConfigName: "defectdojo.product",
+		Usage:      "name of the DefectDojo product, which is created if it doesn't exist (default: the artifact name)",
+	}
+	DefectDojoProductTypeFlag = Flag[string]{
+		Name:       "defectdojo-product-type",
+		ConfigName: "defectdojo.product-type",
+		Usage:      "name of the DefectDojo product type, required to create the product",
+	}
+	DefectDojoEngagementFlag = Flag[string]{
+		Name:       "defectdojo-engagement",
+		ConfigName: "defectdojo.engagement",
+		Default:    "Trivy",
+		Usage:      "name of the DefectDojo engagement, which is created if it doesn't exist",
+	}
+)
+
+// PublishFlagGroup composes flags for uploading the results to vulnerability management platforms
+type PublishFlagGroup struct {
+	DependencyTrackURL            *Flag[string]
+	DependencyTrackAPIKey         *Flag[string]
+	DependencyTrackProject        *Flag[string]
+	DependencyTrackProjectVersion *Flag[string]
+
+	DefectDojoURL         *Flag[string]
+	DefectDojoAPIKey      *Flag[string]
+	DefectDojoProduct     *Flag[string]
+	DefectDojoProductType *Flag[string]
+	DefectDojoEngagement  *Flag[string]
+}
+
+type PublishOptions struct {
+	DependencyTrackURL            string
+	DependencyTrackAPIKey         string
+	DependencyTrackProject        string
+	DependencyTrackProjectVersion string
+
+	DefectDojoURL         string
+	DefectDojoAPIKey      string
+	DefectDojoProduct     string
+	DefectDojoProductType string
+	DefectDojoEngagement  string
+}
+
+// NewPublishFlagGroup returns a default PublishFlagGroup
+func NewPublishFlagGroup() *PublishFlagGroup {
+	return &PublishFlagGroup{
+		DependencyTrackURL:            DependencyTrackURLFlag.Clone(),
+		DependencyTrackAPIKey:         DependencyTrackAPIKeyFlag.Clone(),
+		DependencyTrackProject:        DependencyTrackProjectFlag.Clone(),
+		DependencyTrackProjectVersion: DependencyTrackProjectVersionFlag.Clone(),
+		DefectDojoURL:                 DefectDojoURLFlag.Clone(),
+		DefectDojoAPIKey:              DefectDojoAPIKeyFlag.Clone(),
+		DefectDojoProduct:             DefectDojoProductFlag.Clone(),
+		DefectDojoProductType:         DefectDojoProductTypeFlag.Clone(),
+		DefectDojoEngagement:          DefectDojoEngagementFlag.Clone(),
+	}
+}
+
+func (f *PublishFlagGroup) Name() string {
+	return "Publish"
+}
+
+func (f *PublishFlagGroup) Flags() []Flagger {
+	return []Flagger{
+		f.DependencyTrackURL,
+		f.DependencyTrackAPIKey,
+		f.DependencyTrackProject,
+		f.DependencyTrackProjectVersion,
+		f.DefectDojoURL,
+		f.DefectDojoAPIKey,
+		f.DefectDojoProduct,
+		f.DefectDojoProductType,
+		f.DefectDojoEngagement,
+	}
+}
+
+func (f *PublishFlagGroup) ToOptions() (PublishOptions, error) {
+	if err := parseFlags(f); err != nil {
+		return PublishOptions{}, err
+	}
+
+	// The API keys are usually given by TRIVY_DTRACK_API_KEY and TRIVY_DEFECTDOJO_API_KEY
+	if f.DependencyTrackURL.Value() != "" && f.DependencyTrackAPIKey.Value() == "" {
+		return PublishOptions{}, xerrors.New("'--dtrack-api-key' must be specified with '--dtrack-url'")
+	}
+	if f.DefectDojoURL.Value() != "" && f.DefectDojoAPIKey.Value() == "" {
+		return PublishOptions{}, xerrors.New("'--defectdojo-api-key' must be specified with '--defectdojo-url'")
+	}
+
+	return PublishOptions{
+		DependencyTrackURL:            f.DependencyTrackURL.Value(),
+		DependencyTrackAPIKey:         f.DependencyTrackAPIKey.Value(),
+		DependencyTrackProject:        f.DependencyTrackProject.Value(),
+		DependencyTrackProjectVersion: f.DependencyTrackProjectVersion.Value(),
+		DefectDojoURL:                 f.DefectDojoURL.Value(),
+		DefectDojoAPIKey:              f.DefectDojoAPIKey.Value(),
+		DefectDojoProduct:             f.DefectDojoProduct.Value(),
+		DefectDojoProductType:         f.DefectDojoProductType.Value(),
+		DefectDojoEngagement:          f.DefectDojoEngagement.Value(),
+	}, nil
+}
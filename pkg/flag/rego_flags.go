@@ -33,6 +33,11 @@ var (
 			{Name: "data"},
 		},
 	}
+	TestFilterFlag = Flag[string]{
+		Name:       "run",
+		ConfigName: "rego.test.run",
+		Usage:      "run only the tests whose names match the regular expression",
+	}
 	PolicyNamespaceFlag = Flag[[]string]{
 		Name:       "policy-namespaces",
 		ConfigName: "rego.namespaces",
@@ -50,6 +55,7 @@ type RegoFlagGroup struct {
 	PolicyPaths      *Flag[[]string]
 	DataPaths        *Flag[[]string]
 	PolicyNamespaces *Flag[[]string]
+	TestFilter       *Flag[string] // only for "trivy check test"
 }
 
 type RegoOptions struct {
@@ -58,6 +64,7 @@ type RegoOptions struct {
 	PolicyPaths      []string
 	DataPaths        []string
 	PolicyNamespaces []string
+	TestFilter       string
 }
 
 func NewRegoFlagGroup() *RegoFlagGroup {
@@ -81,6 +88,7 @@ func (f *RegoFlagGroup) Flags() []Flagger {
 		f.PolicyPaths,
 		f.DataPaths,
 		f.PolicyNamespaces,
+		f.TestFilter,
 	}
 }
 
@@ -95,5 +103,6 @@ func (f *RegoFlagGroup) ToOptions() (RegoOptions, error) {
 		PolicyPaths:      f.PolicyPaths.Value(),
 		DataPaths:        f.DataPaths.Value(),
 		PolicyNamespaces: f.PolicyNamespaces.Value(),
+		TestFilter:       f.TestFilter.Value(),
 	}, nil
 }
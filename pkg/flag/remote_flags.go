@@ -3,12 +3,18 @@ package flag
 import (
 	"net/http"
 	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/log"
 )
 
 const (
 	DefaultTokenHeader = "Trivy-Token"
+
+	// ScanPriorityHeader must be the same as the one in the rpc/server package
+	ScanPriorityHeader = "Trivy-Scan-Priority"
 )
 
 var (
@@ -33,52 +39,178 @@ var (
 		ConfigName: "server.custom-headers",
 		Usage:      "custom headers in client mode",
 	}
+	ServerTLSCACertFlag = Flag[string]{
+		Name:       "tls-ca-cert",
+		ConfigName: "server.tls.ca-cert",
+		Usage:      "[EXPERIMENTAL] CA certificate to verify the server in client mode, or to require client certificates in server mode",
+	}
+	ServerTLSCertFlag = Flag[string]{
+		Name:       "tls-cert",
+		ConfigName: "server.tls.cert",
+		Usage:      "[EXPERIMENTAL] certificate presented to the peer in client/server mode. The server listens over TLS with it",
+	}
+	ServerTLSKeyFlag = Flag[string]{
+		Name:       "tls-key",
+		ConfigName: "server.tls.key",
+		Usage:      "[EXPERIMENTAL] private key of '--tls-cert'",
+	}
+	ServerTLSSPIFFEIDsFlag = Flag[[]string]{
+		Name:       "tls-spiffe-id",
+		ConfigName: "server.tls.spiffe-ids",
+		Usage:      "[EXPERIMENTAL] SPIFFE IDs allowed in the peer certificate in client/server mode (e.g. spiffe://example.org/trivy)",
+	}
 	ServerListenFlag = Flag[string]{
 		Name:       "listen",
 		ConfigName: "server.listen",
 		Default:    "localhost:4954",
 		Usage:      "listen address in server mode",
 	}
+	ServerScanPriorityFlag = Flag[string]{
+		Name:       "scan-priority",
+		ConfigName: "server.scan-priority",
+		Default:    "high",
+		Values: []string{
+			"high",
+			"low",
+		},
+		Usage: "[EXPERIMENTAL] priority class of the scan in client mode. 'high' scans preempt 'low' scans when the server is busy",
+	}
+	ServerMaxScansFlag = Flag[int]{
+		Name:       "max-scans",
+		ConfigName: "server.max-scans",
+		Usage:      "[EXPERIMENTAL] maximum number of concurrent scans in server mode (0 = unlimited)",
+	}
+	ServerMaxHighPriorityScansFlag = Flag[int]{
+		Name:       "max-high-priority-scans",
+		ConfigName: "server.max-high-priority-scans",
+		Usage:      "[EXPERIMENTAL] maximum number of concurrent 'high' priority scans in server mode (0 = unlimited)",
+	}
+	ServerMaxLowPriorityScansFlag = Flag[int]{
+		Name:       "max-low-priority-scans",
+		ConfigName: "server.max-low-priority-scans",
+		Usage:      "[EXPERIMENTAL] maximum number of concurrent 'low' priority scans in server mode (0 = unlimited)",
+	}
+	ServerVerdictPolicyDirFlag = Flag[string]{
+		Name:       "verdict-policy-dir",
+		ConfigName: "server.verdict.policy-dir",
+		Usage:      "[EXPERIMENTAL] directory of Rego policies for the admission verdict and review endpoints in server mode. The endpoints are enabled when specified",
+	}
+	ServerVerdictTimeoutFlag = Flag[time.Duration]{
+		Name:       "verdict-timeout",
+		ConfigName: "server.verdict.timeout",
+		Default:    3 * time.Second,
+		Usage:      "[EXPERIMENTAL] latency budget of an admission verdict for images that have not been scanned",
+	}
+	ServerVerdictFailOpenFlag = Flag[bool]{
+		Name:       "verdict-fail-open",
+		ConfigName: "server.verdict.fail-open",
+		Usage:      "[EXPERIMENTAL] allow images when the verdict cannot be decided within the budget or the scan fails",
+	}
+	ServerJobWorkersFlag = Flag[int]{
+		Name:       "job-workers",
+		ConfigName: "server.jobs.workers",
+		Usage:      "[EXPERIMENTAL] number of images scanned concurrently by the asynchronous scan job API in server mode. The API is enabled when positive",
+	}
+	ServerAuthConfigFlag = Flag[string]{
+		Name:       "auth-config",
+		ConfigName: "server.auth-config",
+		Usage:      "[EXPERIMENTAL] YAML file of tenants authenticated by static tokens or OIDC with per-tenant rate limits of scan requests in server mode",
+	}
+	ServerJobQueueSizeFlag = Flag[int]{
+		Name:       "job-queue-size",
+		ConfigName: "server.jobs.queue-size",
+		Default:    100,
+		Usage:      "[EXPERIMENTAL] maximum number of scan jobs waiting for a worker. Submissions are rejected when the queue is full",
+	}
 )
 
 // RemoteFlagGroup composes common printer flag structs
 // used for commands requiring reporting logic.
 type RemoteFlagGroup struct {
 	// for client/server
-	Token       *Flag[string]
-	TokenHeader *Flag[string]
+	Token        *Flag[string]
+	TokenHeader  *Flag[string]
+	TLSCACert    *Flag[string]
+	TLSCert      *Flag[string]
+	TLSKey       *Flag[string]
+	TLSSPIFFEIDs *Flag[[]string]
 
 	// for client
 	ServerAddr    *Flag[string]
 	CustomHeaders *Flag[[]string]
+	ScanPriority  *Flag[string]
 
 	// for server
-	Listen *Flag[string]
+	Listen               *Flag[string]
+	MaxScans             *Flag[int]
+	MaxHighPriorityScans *Flag[int]
+	MaxLowPriorityScans  *Flag[int]
+	VerdictPolicyDir     *Flag[string]
+	VerdictTimeout       *Flag[time.Duration]
+	VerdictFailOpen      *Flag[bool]
+	JobWorkers           *Flag[int]
+	JobQueueSize         *Flag[int]
+	AuthConfig           *Flag[string]
 }
 
 type RemoteOptions struct {
-	Token       string
-	TokenHeader string
+	Token        string
+	TokenHeader  string
+	TLSCACert    string
+	TLSCert      string
+	TLSKey       string
+	TLSSPIFFEIDs []string
 
 	ServerAddr    string
 	Listen        string
 	CustomHeaders http.Header
+
+	MaxScans             int
+	MaxHighPriorityScans int
+	MaxLowPriorityScans  int
+
+	VerdictPolicyDir string
+	VerdictTimeout   time.Duration
+	VerdictFailOpen  bool
+
+	JobWorkers   int
+	JobQueueSize int
+
+	AuthConfig string
 }
 
 func NewClientFlags() *RemoteFlagGroup {
 	return &RemoteFlagGroup{
 		Token:         ServerTokenFlag.Clone(),
 		TokenHeader:   ServerTokenHeaderFlag.Clone(),
+		TLSCACert:     ServerTLSCACertFlag.Clone(),
+		TLSCert:       ServerTLSCertFlag.Clone(),
+		TLSKey:        ServerTLSKeyFlag.Clone(),
+		TLSSPIFFEIDs:  ServerTLSSPIFFEIDsFlag.Clone(),
 		ServerAddr:    ServerAddrFlag.Clone(),
 		CustomHeaders: ServerCustomHeadersFlag.Clone(),
+		ScanPriority:  ServerScanPriorityFlag.Clone(),
 	}
 }
 
 func NewServerFlags() *RemoteFlagGroup {
 	return &RemoteFlagGroup{
-		Token:       &ServerTokenFlag,
-		TokenHeader: &ServerTokenHeaderFlag,
-		Listen:      &ServerListenFlag,
+		Token:                &ServerTokenFlag,
+		TokenHeader:          &ServerTokenHeaderFlag,
+		TLSCACert:            &ServerTLSCACertFlag,
+		TLSCert:              &ServerTLSCertFlag,
+		TLSKey:               &ServerTLSKeyFlag,
+		TLSSPIFFEIDs:         &ServerTLSSPIFFEIDsFlag,
+		Listen:               &ServerListenFlag,
+		MaxScans:             &ServerMaxScansFlag,
+		MaxHighPriorityScans: &ServerMaxHighPriorityScansFlag,
+		MaxLowPriorityScans:  &ServerMaxLowPriorityScansFlag,
+		VerdictPolicyDir:     &ServerVerdictPolicyDirFlag,
+		VerdictTimeout:       &ServerVerdictTimeoutFlag,
+		VerdictFailOpen:      &ServerVerdictFailOpenFlag,
+		JobWorkers:           &ServerJobWorkersFlag,
+		JobQueueSize:         &ServerJobQueueSizeFlag,
+		AuthConfig:           &ServerAuthConfigFlag,
 	}
 }
 
@@ -90,9 +222,23 @@ func (f *RemoteFlagGroup) Flags() []Flagger {
 	return []Flagger{
 		f.Token,
 		f.TokenHeader,
+		f.TLSCACert,
+		f.TLSCert,
+		f.TLSKey,
+		f.TLSSPIFFEIDs,
 		f.ServerAddr,
 		f.CustomHeaders,
+		f.ScanPriority,
 		f.Listen,
+		f.MaxScans,
+		f.MaxHighPriorityScans,
+		f.MaxLowPriorityScans,
+		f.VerdictPolicyDir,
+		f.VerdictTimeout,
+		f.VerdictFailOpen,
+		f.JobWorkers,
+		f.JobQueueSize,
+		f.AuthConfig,
 	}
 }
 
@@ -126,12 +272,39 @@ func (f *RemoteFlagGroup) ToOptions() (RemoteOptions, error) {
 		customHeaders.Set(tokenHeader, token)
 	}
 
+	tlsCert, tlsKey := f.TLSCert.Value(), f.TLSKey.Value()
+	if (tlsCert == "") != (tlsKey == "") {
+		return RemoteOptions{}, xerrors.New("'--tls-cert' and '--tls-key' must be specified together")
+	}
+
+	// The server treats requests without the header as 'high' priority
+	if priority := f.ScanPriority.Value(); serverAddr != "" && priority != "" {
+		customHeaders.Set(ScanPriorityHeader, priority)
+	}
+
 	return RemoteOptions{
 		Token:         token,
 		TokenHeader:   tokenHeader,
+		TLSCACert:     f.TLSCACert.Value(),
+		TLSCert:       tlsCert,
+		TLSKey:        tlsKey,
+		TLSSPIFFEIDs:  f.TLSSPIFFEIDs.Value(),
 		ServerAddr:    serverAddr,
 		CustomHeaders: customHeaders,
 		Listen:        listen,
+
+		MaxScans:             f.MaxScans.Value(),
+		MaxHighPriorityScans: f.MaxHighPriorityScans.Value(),
+		MaxLowPriorityScans:  f.MaxLowPriorityScans.Value(),
+
+		VerdictPolicyDir: f.VerdictPolicyDir.Value(),
+		VerdictTimeout:   f.VerdictTimeout.Value(),
+		VerdictFailOpen:  f.VerdictFailOpen.Value(),
+
+		JobWorkers:   f.JobWorkers.Value(),
+		JobQueueSize: f.JobQueueSize.Value(),
+
+		AuthConfig: f.AuthConfig.Value(),
 	}, nil
 }
 
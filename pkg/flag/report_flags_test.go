@@ -237,3 +237,71 @@ func TestReportFlagGroup_ToOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestReportFlagGroup_ToOptions_MultipleOutputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		outputs []string
+		want    flag.ReportOptions
+		wantErr string
+	}{
+		{
+			name:    "formats paired with outputs in order",
+			formats: []string{"json", "cyclonedx", "table"},
+			outputs: []string{"result.json", "sbom.cdx.json"},
+			want: flag.ReportOptions{
+				Format:      types.FormatJSON,
+				Output:      "result.json",
+				ListAllPkgs: true,
+				ExtraOutputs: []flag.ReportOutput{
+					{
+						Format: types.FormatCycloneDX,
+						Output: "sbom.cdx.json",
+					},
+					{
+						Format: types.FormatTable,
+					},
+				},
+			},
+		},
+		{
+			name:    "more outputs than formats",
+			formats: []string{"json"},
+			outputs: []string{"result.json", "result.sarif"},
+			wantErr: "'--output' is specified 2 times, but '--format' is specified only 1 times",
+		},
+		{
+			name:    "multiple formats to stdout",
+			formats: []string{"json", "table", "sarif"},
+			outputs: []string{"result.json"},
+			wantErr: `only one format can be written to stdout, but ["table" "sarif"] are`,
+		},
+		{
+			name:    "same output",
+			formats: []string{"json", "sarif"},
+			outputs: []string{"result", "result"},
+			wantErr: "'--output result' is specified more than once",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(viper.Reset)
+			setSliceValue(flag.FormatFlag.ConfigName, tt.formats)
+			setSliceValue(flag.OutputFlag.ConfigName, tt.outputs)
+
+			f := &flag.ReportFlagGroup{
+				Format:      flag.FormatFlag.Clone(),
+				ListAllPkgs: flag.ListAllPkgsFlag.Clone(),
+				Output:      flag.OutputFlag.Clone(),
+			}
+			got, err := f.ToOptions()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,9 +2,14 @@ package flag
 
 import (
 	"runtime"
+	"strings"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/samber/lo"
+	"golang.org/x/xerrors"
 
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/types"
 	xstrings "github.com/aquasecurity/trivy/pkg/x/strings"
@@ -87,6 +92,7 @@ var (
 		Values: []string{
 			"oci",
 			"rekor",
+			"registry",
 		},
 		Usage: "[EXPERIMENTAL] try to retrieve SBOM from the specified sources",
 	}
@@ -101,6 +107,31 @@ var (
 		ConfigName: "include-dev-deps",
 		Usage:      "include development dependencies in the report (supported: npm, yarn)",
 	}
+	DebugReportFlag = Flag[string]{
+		Name:       "debug-report",
+		ConfigName: "scan.debug-report",
+		Usage:      "write analyzer timings, skipped files and layer cache status to the file as JSON",
+	}
+	ChangedFromFlag = Flag[string]{
+		Name:       "changed-from",
+		ConfigName: "scan.changed-from",
+		Usage:      "[EXPERIMENTAL] analyze only files changed since the git revision, plus their related lock files and manifests",
+	}
+	IncrementalFlag = Flag[bool]{
+		Name:       "incremental",
+		ConfigName: "scan.incremental",
+		Usage:      "[EXPERIMENTAL] reuse the analysis results of files unchanged since the previous scan of the same path",
+	}
+	BudgetFlag = Flag[string]{
+		Name:       "budget",
+		ConfigName: "scan.budget",
+		Usage:      "[EXPERIMENTAL] stop analyzing more files and report partial results when the time or size budget is exceeded (e.g. time=5m,bytes=2GB)",
+	}
+	MaxMemoryFlag = Flag[string]{
+		Name:       "max-memory",
+		ConfigName: "scan.max-memory",
+		Usage:      "[EXPERIMENTAL] keep the memory usage under the limit (e.g. 512MB) by collecting garbage more often and writing file buffers to disk",
+	}
 )
 
 type ScanFlagGroup struct {
@@ -114,6 +145,11 @@ type ScanFlagGroup struct {
 	SBOMSources    *Flag[[]string]
 	RekorURL       *Flag[string]
 	IncludeDevDeps *Flag[bool]
+	DebugReport    *Flag[string]
+	ChangedFrom    *Flag[string] // only for filesystem
+	Incremental    *Flag[bool]   // only for filesystem
+	Budget         *Flag[string] // only for container images, filesystem, rootfs and repositories
+	MaxMemory      *Flag[string]
 }
 
 type ScanOptions struct {
@@ -127,6 +163,11 @@ type ScanOptions struct {
 	SBOMSources    []string
 	RekorURL       string
 	IncludeDevDeps bool
+	DebugReport    string
+	ChangedFrom    string
+	Incremental    bool
+	Budget         ftypes.Budget
+	MaxMemory      int64
 }
 
 func NewScanFlagGroup() *ScanFlagGroup {
@@ -141,6 +182,8 @@ func NewScanFlagGroup() *ScanFlagGroup {
 		RekorURL:       RekorURLFlag.Clone(),
 		IncludeDevDeps: IncludeDevDepsFlag.Clone(),
 		Slow:           SlowFlag.Clone(),
+		DebugReport:    DebugReportFlag.Clone(),
+		MaxMemory:      MaxMemoryFlag.Clone(),
 	}
 }
 
@@ -160,6 +203,11 @@ func (f *ScanFlagGroup) Flags() []Flagger {
 		f.SBOMSources,
 		f.RekorURL,
 		f.IncludeDevDeps,
+		f.DebugReport,
+		f.ChangedFrom,
+		f.Incremental,
+		f.Budget,
+		f.MaxMemory,
 	}
 }
 
@@ -179,6 +227,20 @@ func (f *ScanFlagGroup) ToOptions(args []string) (ScanOptions, error) {
 		parallel = runtime.NumCPU()
 	}
 
+	budget, err := parseBudget(f.Budget.Value())
+	if err != nil {
+		return ScanOptions{}, xerrors.Errorf("invalid budget: %w", err)
+	}
+
+	var maxMemory int64
+	if m := f.MaxMemory.Value(); m != "" {
+		b, err := humanize.ParseBytes(m)
+		if err != nil || b == 0 {
+			return ScanOptions{}, xerrors.Errorf("the max memory must be a positive size such as 512MB: %q", m)
+		}
+		maxMemory = int64(b)
+	}
+
 	return ScanOptions{
 		Target:         target,
 		SkipDirs:       f.SkipDirs.Value(),
@@ -190,5 +252,41 @@ func (f *ScanFlagGroup) ToOptions(args []string) (ScanOptions, error) {
 		SBOMSources:    f.SBOMSources.Value(),
 		RekorURL:       f.RekorURL.Value(),
 		IncludeDevDeps: f.IncludeDevDeps.Value(),
+		DebugReport:    f.DebugReport.Value(),
+		ChangedFrom:    f.ChangedFrom.Value(),
+		Incremental:    f.Incremental.Value(),
+		Budget:         budget,
+		MaxMemory:      maxMemory,
 	}, nil
 }
+
+// parseBudget parses the budget such as "time=5m,bytes=2GB"
+func parseBudget(s string) (ftypes.Budget, error) {
+	var budget ftypes.Budget
+	if s == "" {
+		return budget, nil
+	}
+	for _, kv := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(kv), "=")
+		if !ok {
+			return ftypes.Budget{}, xerrors.Errorf("%q must be in the form of key=value", kv)
+		}
+		switch key {
+		case "time":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return ftypes.Budget{}, xerrors.Errorf("time must be a positive duration such as 5m: %q", value)
+			}
+			budget.Time = d
+		case "bytes":
+			b, err := humanize.ParseBytes(value)
+			if err != nil || b == 0 {
+				return ftypes.Budget{}, xerrors.Errorf("bytes must be a positive size such as 2GB: %q", value)
+			}
+			budget.Bytes = int64(b)
+		default:
+			return ftypes.Budget{}, xerrors.Errorf("unknown key %q, supported: time, bytes", key)
+		}
+	}
+	return budget, nil
+}
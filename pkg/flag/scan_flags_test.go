@@ -3,10 +3,12 @@ package flag_test
 import (
 	"github.com/spf13/viper"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/flag"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
@@ -17,6 +19,8 @@ func TestScanFlagGroup_ToOptions(t *testing.T) {
 		skipFiles   []string
 		offlineScan bool
 		scanners    string
+		budget      string
+		maxMemory   string
 	}
 	tests := []struct {
 		name      string
@@ -95,6 +99,68 @@ func TestScanFlagGroup_ToOptions(t *testing.T) {
 			},
 			assertion: require.NoError,
 		},
+		{
+			name: "budget",
+			fields: fields{
+				budget: "time=5m, bytes=2GB",
+			},
+			want: flag.ScanOptions{
+				Budget: ftypes.Budget{
+					Time:  5 * time.Minute,
+					Bytes: 2_000_000_000,
+				},
+			},
+			assertion: require.NoError,
+		},
+		{
+			name: "budget in GiB",
+			fields: fields{
+				budget: "bytes=1GiB",
+			},
+			want: flag.ScanOptions{
+				Budget: ftypes.Budget{
+					Bytes: 1 << 30,
+				},
+			},
+			assertion: require.NoError,
+		},
+		{
+			name: "invalid budget",
+			fields: fields{
+				budget: "time=5m,files=100",
+			},
+			assertion: func(t require.TestingT, err error, msgs ...interface{}) {
+				require.ErrorContains(t, err, `unknown key "files"`)
+			},
+		},
+		{
+			name: "invalid budget time",
+			fields: fields{
+				budget: "time=5",
+			},
+			assertion: func(t require.TestingT, err error, msgs ...interface{}) {
+				require.ErrorContains(t, err, "time must be a positive duration")
+			},
+		},
+		{
+			name: "max memory",
+			fields: fields{
+				maxMemory: "512MiB",
+			},
+			want: flag.ScanOptions{
+				MaxMemory: 512 << 20,
+			},
+			assertion: require.NoError,
+		},
+		{
+			name: "invalid max memory",
+			fields: fields{
+				maxMemory: "half",
+			},
+			assertion: func(t require.TestingT, err error, msgs ...interface{}) {
+				require.ErrorContains(t, err, "the max memory must be a positive size")
+			},
+		},
 		{
 			name: "offline scan",
 			fields: fields{
@@ -114,6 +180,8 @@ func TestScanFlagGroup_ToOptions(t *testing.T) {
 			setSliceValue(flag.SkipFilesFlag.ConfigName, tt.fields.skipFiles)
 			setValue(flag.OfflineScanFlag.ConfigName, tt.fields.offlineScan)
 			setValue(flag.ScannersFlag.ConfigName, tt.fields.scanners)
+			setValue(flag.BudgetFlag.ConfigName, tt.fields.budget)
+			setValue(flag.MaxMemoryFlag.ConfigName, tt.fields.maxMemory)
 
 			// Assert options
 			f := &flag.ScanFlagGroup{
@@ -121,6 +189,8 @@ func TestScanFlagGroup_ToOptions(t *testing.T) {
 				SkipFiles:   flag.SkipFilesFlag.Clone(),
 				OfflineScan: flag.OfflineScanFlag.Clone(),
 				Scanners:    flag.ScannersFlag.Clone(),
+				Budget:      flag.BudgetFlag.Clone(),
+				MaxMemory:   flag.MaxMemoryFlag.Clone(),
 			}
 
 			got, err := f.ToOptions(tt.args)
@@ -7,19 +7,27 @@ var (
 		Default:    "trivy-secret.yaml",
 		Usage:      "specify a path to config file for secret scanning",
 	}
+	SecretFingerprintSaltFlag = Flag[string]{
+		Name:       "secret-fingerprint-salt",
+		ConfigName: "secret.fingerprint-salt",
+		Usage:      "salt for fingerprints of detected secrets, which are salted hashes to deduplicate the same secret across scans",
+	}
 )
 
 type SecretFlagGroup struct {
-	SecretConfig *Flag[string]
+	SecretConfig          *Flag[string]
+	SecretFingerprintSalt *Flag[string]
 }
 
 type SecretOptions struct {
-	SecretConfigPath string
+	SecretConfigPath      string
+	SecretFingerprintSalt string
 }
 
 func NewSecretFlagGroup() *SecretFlagGroup {
 	return &SecretFlagGroup{
-		SecretConfig: SecretConfigFlag.Clone(),
+		SecretConfig:          SecretConfigFlag.Clone(),
+		SecretFingerprintSalt: SecretFingerprintSaltFlag.Clone(),
 	}
 }
 
@@ -28,7 +36,10 @@ func (f *SecretFlagGroup) Name() string {
 }
 
 func (f *SecretFlagGroup) Flags() []Flagger {
-	return []Flagger{f.SecretConfig}
+	return []Flagger{
+		f.SecretConfig,
+		f.SecretFingerprintSalt,
+	}
 }
 
 func (f *SecretFlagGroup) ToOptions() (SecretOptions, error) {
@@ -37,6 +48,7 @@ func (f *SecretFlagGroup) ToOptions() (SecretOptions, error) {
 	}
 
 	return SecretOptions{
-		SecretConfigPath: f.SecretConfig.Value(),
+		SecretConfigPath:      f.SecretConfig.Value(),
+		SecretFingerprintSalt: f.SecretFingerprintSalt.Value(),
 	}, nil
 }
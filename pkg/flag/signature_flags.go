@@ -0,0 +1,118 @@
+package flag
+
+import "fmt"
+
+var (
+	VerifySignatureFlag = Flag[bool]{
+		Name:       "verify-signature",
+		ConfigName: "signature.verify",
+		Usage:      "[EXPERIMENTAL] verify the cosign signatures and attestations of the image and record the result in the report",
+	}
+	SignatureKeyFlag = Flag[string]{
+		Name:       "signature-key",
+		ConfigName: "signature.key",
+		Usage:      "path to the public key of the image signer",
+	}
+	CertificateIdentityFlag = Flag[string]{
+		Name:       "certificate-identity",
+		ConfigName: "signature.certificate-identity",
+		Usage:      "identity in the Fulcio certificate of keyless signatures (example: release@example.com)",
+	}
+	CertificateOIDCIssuerFlag = Flag[string]{
+		Name:       "certificate-oidc-issuer",
+		ConfigName: "signature.certificate-oidc-issuer",
+		Usage:      "OIDC issuer in the Fulcio certificate of keyless signatures (example: https://token.actions.githubusercontent.com)",
+	}
+	FulcioRootFlag = Flag[string]{
+		Name:       "fulcio-root",
+		ConfigName: "signature.fulcio-root",
+		Usage:      "path to the PEM file with the Fulcio root and intermediate certificates for keyless signatures",
+	}
+	RekorPublicKeyFlag = Flag[string]{
+		Name:       "rekor-public-key",
+		ConfigName: "signature.rekor-public-key",
+		Usage:      "path to the public key of Rekor to verify the signing time in the Rekor bundle of keyless signatures",
+	}
+	RequireSignatureFlag = Flag[bool]{
+		Name:       "require-signature",
+		ConfigName: "signature.require",
+		Usage:      "fail the scan if no signature is verified with '--verify-signature'",
+	}
+)
+
+// SignatureFlagGroup composes the flags verifying the cosign signatures of images
+type SignatureFlagGroup struct {
+	VerifySignature       *Flag[bool]
+	SignatureKey          *Flag[string]
+	CertificateIdentity   *Flag[string]
+	CertificateOIDCIssuer *Flag[string]
+	FulcioRoot            *Flag[string]
+	RekorPublicKey        *Flag[string]
+	RequireSignature      *Flag[bool]
+}
+
+type SignatureOptions struct {
+	VerifySignature       bool
+	SignatureKey          string
+	CertificateIdentity   string
+	CertificateOIDCIssuer string
+	FulcioRoot            string
+	RekorPublicKey        string
+	RequireSignature      bool
+}
+
+func NewSignatureFlagGroup() *SignatureFlagGroup {
+	return &SignatureFlagGroup{
+		VerifySignature:       VerifySignatureFlag.Clone(),
+		SignatureKey:          SignatureKeyFlag.Clone(),
+		CertificateIdentity:   CertificateIdentityFlag.Clone(),
+		CertificateOIDCIssuer: CertificateOIDCIssuerFlag.Clone(),
+		FulcioRoot:            FulcioRootFlag.Clone(),
+		RekorPublicKey:        RekorPublicKeyFlag.Clone(),
+		RequireSignature:      RequireSignatureFlag.Clone(),
+	}
+}
+
+func (f *SignatureFlagGroup) Name() string {
+	return "Signature"
+}
+
+func (f *SignatureFlagGroup) Flags() []Flagger {
+	return []Flagger{
+		f.VerifySignature,
+		f.SignatureKey,
+		f.CertificateIdentity,
+		f.CertificateOIDCIssuer,
+		f.FulcioRoot,
+		f.RekorPublicKey,
+		f.RequireSignature,
+	}
+}
+
+func (f *SignatureFlagGroup) ToOptions() (SignatureOptions, error) {
+	if err := parseFlags(f); err != nil {
+		return SignatureOptions{}, err
+	}
+
+	verify := f.VerifySignature.Value()
+	keyless := f.CertificateIdentity.Value() != "" || f.CertificateOIDCIssuer.Value() != "" || f.FulcioRoot.Value() != ""
+	switch {
+	case f.RequireSignature.Value() && !verify:
+		return SignatureOptions{}, fmt.Errorf("--require-signature requires --verify-signature")
+	case !verify:
+	case f.SignatureKey.Value() != "" && (keyless || f.RekorPublicKey.Value() != ""):
+		return SignatureOptions{}, fmt.Errorf("--signature-key cannot be used with the keyless signature flags")
+	case f.SignatureKey.Value() == "" && (f.CertificateIdentity.Value() == "" || f.CertificateOIDCIssuer.Value() == "" || f.FulcioRoot.Value() == ""):
+		return SignatureOptions{}, fmt.Errorf("--verify-signature requires --signature-key, or --certificate-identity, --certificate-oidc-issuer and --fulcio-root")
+	}
+
+	return SignatureOptions{
+		VerifySignature:       verify,
+		SignatureKey:          f.SignatureKey.Value(),
+		CertificateIdentity:   f.CertificateIdentity.Value(),
+		CertificateOIDCIssuer: f.CertificateOIDCIssuer.Value(),
+		FulcioRoot:            f.FulcioRoot.Value(),
+		RekorPublicKey:        f.RekorPublicKey.Value(),
+		RequireSignature:      f.RequireSignature.Value(),
+	}, nil
+}
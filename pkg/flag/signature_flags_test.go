@@ -0,0 +1,106 @@
+package flag_test
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/flag"
+)
+
+func TestSignatureFlagGroup_ToOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  flag.SignatureOptions
+		want    flag.SignatureOptions
+		wantErr string
+	}{
+		{
+			name: "key",
+			fields: flag.SignatureOptions{
+				VerifySignature:  true,
+				SignatureKey:     "cosign.pub",
+				RequireSignature: true,
+			},
+			want: flag.SignatureOptions{
+				VerifySignature:  true,
+				SignatureKey:     "cosign.pub",
+				RequireSignature: true,
+			},
+		},
+		{
+			name: "keyless",
+			fields: flag.SignatureOptions{
+				VerifySignature:       true,
+				CertificateIdentity:   "release@example.com",
+				CertificateOIDCIssuer: "https://accounts.example.com",
+				FulcioRoot:            "fulcio.pem",
+				RekorPublicKey:        "rekor.pub",
+			},
+			want: flag.SignatureOptions{
+				VerifySignature:       true,
+				CertificateIdentity:   "release@example.com",
+				CertificateOIDCIssuer: "https://accounts.example.com",
+				FulcioRoot:            "fulcio.pem",
+				RekorPublicKey:        "rekor.pub",
+			},
+		},
+		{
+			name: "require without verify",
+			fields: flag.SignatureOptions{
+				SignatureKey:     "cosign.pub",
+				RequireSignature: true,
+			},
+			wantErr: "--require-signature requires --verify-signature",
+		},
+		{
+			name: "both key and identity",
+			fields: flag.SignatureOptions{
+				VerifySignature:     true,
+				SignatureKey:        "cosign.pub",
+				CertificateIdentity: "release@example.com",
+			},
+			wantErr: "--signature-key cannot be used with the keyless signature flags",
+		},
+		{
+			name: "both key and Rekor public key",
+			fields: flag.SignatureOptions{
+				VerifySignature: true,
+				SignatureKey:    "cosign.pub",
+				RekorPublicKey:  "rekor.pub",
+			},
+			wantErr: "--signature-key cannot be used with the keyless signature flags",
+		},
+		{
+			name: "no issuer",
+			fields: flag.SignatureOptions{
+				VerifySignature:     true,
+				CertificateIdentity: "release@example.com",
+				FulcioRoot:          "fulcio.pem",
+			},
+			wantErr: "--verify-signature requires --signature-key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(viper.Reset)
+			viper.Set(flag.VerifySignatureFlag.ConfigName, tt.fields.VerifySignature)
+			viper.Set(flag.SignatureKeyFlag.ConfigName, tt.fields.SignatureKey)
+			viper.Set(flag.CertificateIdentityFlag.ConfigName, tt.fields.CertificateIdentity)
+			viper.Set(flag.CertificateOIDCIssuerFlag.ConfigName, tt.fields.CertificateOIDCIssuer)
+			viper.Set(flag.FulcioRootFlag.ConfigName, tt.fields.FulcioRoot)
+			viper.Set(flag.RekorPublicKeyFlag.ConfigName, tt.fields.RekorPublicKey)
+			viper.Set(flag.RequireSignatureFlag.ConfigName, tt.fields.RequireSignature)
+
+			got, err := flag.NewSignatureFlagGroup().ToOptions()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
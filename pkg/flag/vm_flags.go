@@ -0,0 +1,77 @@
+package flag
+
+import (
+	"os"
+
+	"golang.org/x/xerrors"
+)
+
+var (
+	LUKSPassphraseFlag = Flag[string]{
+		Name:       "luks-passphrase",
+		ConfigName: "vm.luks.passphrase",
+		Usage:      "passphrase to unlock the LUKS-encrypted volumes (prefer the TRIVY_LUKS_PASSPHRASE environment variable)",
+	}
+	LUKSKeyFileFlag = Flag[string]{
+		Name:       "luks-key-file",
+		ConfigName: "vm.luks.key-file",
+		Usage:      "path to the key file to unlock the LUKS-encrypted volumes",
+	}
+)
+
+// VMFlagGroup composes the flags specific to virtual machine images
+type VMFlagGroup struct {
+	LUKSPassphrase *Flag[string]
+	LUKSKeyFile    *Flag[string]
+}
+
+type VMOptions struct {
+	LUKSPassphrase string
+	LUKSKeyFile    string
+}
+
+func NewVMFlagGroup() *VMFlagGroup {
+	return &VMFlagGroup{
+		LUKSPassphrase: LUKSPassphraseFlag.Clone(),
+		LUKSKeyFile:    LUKSKeyFileFlag.Clone(),
+	}
+}
+
+func (f *VMFlagGroup) Name() string {
+	return "VM"
+}
+
+func (f *VMFlagGroup) Flags() []Flagger {
+	return []Flagger{
+		f.LUKSPassphrase,
+		f.LUKSKeyFile,
+	}
+}
+
+func (f *VMFlagGroup) ToOptions() (VMOptions, error) {
+	if err := parseFlags(f); err != nil {
+		return VMOptions{}, err
+	}
+
+	return VMOptions{
+		LUKSPassphrase: f.LUKSPassphrase.Value(),
+		LUKSKeyFile:    f.LUKSKeyFile.Value(),
+	}, nil
+}
+
+// LUKSKeys returns the passphrase and the contents of the key file, which are tried in order to unlock the LUKS volumes.
+// The key file is used as is, including the trailing newline, as with "cryptsetup --key-file".
+func (o *VMOptions) LUKSKeys() ([][]byte, error) {
+	var keys [][]byte
+	if o.LUKSPassphrase != "" {
+		keys = append(keys, []byte(o.LUKSPassphrase))
+	}
+	if o.LUKSKeyFile != "" {
+		key, err := os.ReadFile(o.LUKSKeyFile)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to read the LUKS key file: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,66 @@
+package flag_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/flag"
+)
+
+func TestVMOptions_LUKSKeys(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "luks.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("secret\n"), 0600))
+
+	tests := []struct {
+		name    string
+		fields  flag.VMOptions
+		want    [][]byte
+		wantErr string
+	}{
+		{
+			name: "passphrase and key file",
+			fields: flag.VMOptions{
+				LUKSPassphrase: "passphrase",
+				LUKSKeyFile:    keyFile,
+			},
+			want: [][]byte{
+				[]byte("passphrase"),
+				[]byte("secret\n"),
+			},
+		},
+		{
+			name:   "no keys",
+			fields: flag.VMOptions{},
+		},
+		{
+			name: "missing key file",
+			fields: flag.VMOptions{
+				LUKSKeyFile: filepath.Join(t.TempDir(), "missing.key"),
+			},
+			wantErr: "unable to read the LUKS key file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(viper.Reset)
+			viper.Set(flag.LUKSPassphraseFlag.ConfigName, tt.fields.LUKSPassphrase)
+			viper.Set(flag.LUKSKeyFileFlag.ConfigName, tt.fields.LUKSKeyFile)
+
+			opts, err := flag.NewVMFlagGroup().ToOptions()
+			require.NoError(t, err)
+
+			got, err := opts.LUKSKeys()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,10 +1,15 @@
 package flag
 
 import (
+	"strings"
+
 	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
 
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/result"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -27,6 +32,11 @@ var (
 		ConfigName: "vulnerability.ignore-unfixed",
 		Usage:      "display only fixed vulnerabilities",
 	}
+	IgnoreUnfixedScopeFlag = Flag[[]string]{
+		Name:       "ignore-unfixed-scope",
+		ConfigName: "vulnerability.ignore-unfixed-scope",
+		Usage:      "comma-separated list of vulnerability types and severities where unfixed vulnerabilities are ignored (e.g. os:LOW,os:MEDIUM,library)",
+	}
 	IgnoreStatusFlag = Flag[[]string]{
 		Name:       "ignore-status",
 		ConfigName: "vulnerability.ignore-status",
@@ -39,27 +49,93 @@ var (
 		Default:    "",
 		Usage:      "[EXPERIMENTAL] file path to VEX",
 	}
+	SeveritySourceFlag = Flag[string]{
+		Name:       "severity-source",
+		ConfigName: "vulnerability.severity-source",
+		Default:    SeveritySourceVendor,
+		Values: []string{
+			SeveritySourceVendor,
+			SeveritySourceEPSS,
+		},
+		Usage: "[EXPERIMENTAL] source of severity. 'epss' derives severity from EPSS and CISA KEV",
+	}
+	KEVOnlyFlag = Flag[bool]{
+		Name:       "kev-only",
+		ConfigName: "vulnerability.kev-only",
+		Usage:      "[EXPERIMENTAL] display only vulnerabilities in the CISA KEV catalog",
+	}
+	SeverityOverrideFileFlag = Flag[string]{
+		Name:       "severity-override-file",
+		ConfigName: "vulnerability.severity-override-file",
+		Usage:      "specify a YAML file overriding the severity of vulnerabilities",
+	}
+	ReachabilitySymbolsFlag = Flag[string]{
+		Name:       "reachability-symbols",
+		ConfigName: "vulnerability.reachability-symbols",
+		Usage:      "[EXPERIMENTAL] specify a YAML file with affected symbols to analyze the reachability of Go and Java vulnerabilities",
+	}
+	SkipUnreachableFlag = Flag[bool]{
+		Name:       "skip-unreachable",
+		ConfigName: "vulnerability.skip-unreachable",
+		Usage:      "[EXPERIMENTAL] hide vulnerabilities whose affected symbols are not reachable",
+	}
+	NoDedupeAliasesFlag = Flag[bool]{
+		Name:       "no-dedupe-aliases",
+		ConfigName: "vulnerability.no-dedupe-aliases",
+		Usage:      "report the same vulnerability from different advisory sources (e.g. CVE and GHSA) separately instead of merging them",
+	}
+)
+
+const (
+	SeveritySourceVendor = "vendor"
+	SeveritySourceEPSS   = "epss"
 )
 
 type VulnerabilityFlagGroup struct {
-	VulnType      *Flag[[]string]
-	IgnoreUnfixed *Flag[bool]
-	IgnoreStatus  *Flag[[]string]
-	VEXPath       *Flag[string]
+	VulnType             *Flag[[]string]
+	IgnoreUnfixed        *Flag[bool]
+	IgnoreUnfixedScope   *Flag[[]string]
+	IgnoreStatus         *Flag[[]string]
+	VEXPath              *Flag[string]
+	SeveritySource       *Flag[string]
+	KEVOnly              *Flag[bool]
+	SeverityOverrideFile *Flag[string]
+	ReachabilitySymbols  *Flag[string]
+	SkipUnreachable      *Flag[bool]
+	NoDedupeAliases      *Flag[bool]
 }
 
 type VulnerabilityOptions struct {
-	VulnType       []string
-	IgnoreStatuses []dbTypes.Status
-	VEXPath        string
+	VulnType             []string
+	IgnoreStatuses       []dbTypes.Status
+	IgnoreStatusScopes   []result.StatusScope
+	VEXPath              string
+	SeveritySource       string
+	KEVOnly              bool
+	SeverityOverrideFile string
+	ReachabilitySymbols  string
+	SkipUnreachable      bool
+	NoDedupeAliases      bool
+}
+
+// ExploitEnabled returns true if the exploit data, EPSS and CISA KEV, is required
+func (o VulnerabilityOptions) ExploitEnabled() bool {
+	return o.SeveritySource == SeveritySourceEPSS || o.KEVOnly
 }
 
 func NewVulnerabilityFlagGroup() *VulnerabilityFlagGroup {
 	return &VulnerabilityFlagGroup{
-		VulnType:      VulnTypeFlag.Clone(),
-		IgnoreUnfixed: IgnoreUnfixedFlag.Clone(),
-		IgnoreStatus:  IgnoreStatusFlag.Clone(),
-		VEXPath:       VEXFlag.Clone(),
+		VulnType:             VulnTypeFlag.Clone(),
+		IgnoreUnfixed:        IgnoreUnfixedFlag.Clone(),
+		IgnoreUnfixedScope:   IgnoreUnfixedScopeFlag.Clone(),
+		IgnoreStatus:         IgnoreStatusFlag.Clone(),
+		VEXPath:              VEXFlag.Clone(),
+		SeveritySource:       SeveritySourceFlag.Clone(),
+		KEVOnly:              KEVOnlyFlag.Clone(),
+		SeverityOverrideFile: SeverityOverrideFileFlag.Clone(),
+		ReachabilitySymbols:  ReachabilitySymbolsFlag.Clone(),
+		SkipUnreachable:      SkipUnreachableFlag.Clone(),
+		NoDedupeAliases:      NoDedupeAliasesFlag.Clone(),
 	}
 }
 
@@ -71,8 +147,15 @@ func (f *VulnerabilityFlagGroup) Flags() []Flagger {
 	return []Flagger{
 		f.VulnType,
 		f.IgnoreUnfixed,
+		f.IgnoreUnfixedScope,
 		f.IgnoreStatus,
 		f.VEXPath,
+		f.SeveritySource,
+		f.KEVOnly,
+		f.SeverityOverrideFile,
+		f.ReachabilitySymbols,
+		f.SkipUnreachable,
+		f.NoDedupeAliases,
 	}
 }
 
@@ -87,9 +170,19 @@ func (f *VulnerabilityFlagGroup) ToOptions() (VulnerabilityOptions, error) {
 	})
 	ignoreUnfixed := f.IgnoreUnfixed.Value()
 
+	statusScopes, err := parseStatusScopes(f.IgnoreUnfixedScope.Value())
+	if err != nil {
+		return VulnerabilityOptions{}, xerrors.Errorf("unable to parse '--ignore-unfixed-scope': %w", err)
+	}
+	// '--ignore-unfixed-scope' implies '--ignore-unfixed'
+	if len(statusScopes) > 0 && !ignoreUnfixed {
+		ignoreUnfixed = true
+	}
+
 	switch {
 	case ignoreUnfixed && len(ignoreStatuses) > 0:
 		log.Logger.Warn("'--ignore-unfixed' is ignored because '--ignore-status' is specified")
+		statusScopes = nil
 	case ignoreUnfixed:
 		// '--ignore-unfixed' is a shorthand of '--ignore-status'.
 		ignoreStatuses = lo.FilterMap(dbTypes.Statuses, func(s string, _ int) (dbTypes.Status, bool) {
@@ -104,9 +197,49 @@ func (f *VulnerabilityFlagGroup) ToOptions() (VulnerabilityOptions, error) {
 	}
 	log.Logger.Debugw("Ignore statuses", "statuses", ignoreStatuses)
 
+	if f.SkipUnreachable.Value() && f.ReachabilitySymbols.Value() == "" {
+		log.Logger.Warn("'--skip-unreachable' is ignored because '--reachability-symbols' is not specified")
+	}
+
 	return VulnerabilityOptions{
-		VulnType:       f.VulnType.Value(),
-		IgnoreStatuses: ignoreStatuses,
-		VEXPath:        f.VEXPath.Value(),
+		VulnType:             f.VulnType.Value(),
+		IgnoreStatuses:       ignoreStatuses,
+		IgnoreStatusScopes:   statusScopes,
+		VEXPath:              f.VEXPath.Value(),
+		SeveritySource:       f.SeveritySource.Value(),
+		KEVOnly:              f.KEVOnly.Value(),
+		SeverityOverrideFile: f.SeverityOverrideFile.Value(),
+		ReachabilitySymbols:  f.ReachabilitySymbols.Value(),
+		SkipUnreachable:      f.SkipUnreachable.Value(),
+		NoDedupeAliases:      f.NoDedupeAliases.Value(),
 	}, nil
 }
+
+// parseStatusScopes parses scopes in the form of "<vuln-type>[:<severity>]", e.g. "os:LOW" and "library"
+func parseStatusScopes(scopes []string) ([]result.StatusScope, error) {
+	var statusScopes []result.StatusScope
+	for _, scope := range scopes {
+		vulnType, severity, _ := strings.Cut(scope, ":")
+
+		var class types.ResultClass
+		switch vulnType {
+		case types.VulnTypeOS:
+			class = types.ClassOSPkg
+		case types.VulnTypeLibrary:
+			class = types.ClassLangPkg
+		default:
+			return nil, xerrors.Errorf("unknown vulnerability type %q in %q, allowed values: %s, %s",
+				vulnType, scope, types.VulnTypeOS, types.VulnTypeLibrary)
+		}
+
+		severity = strings.ToUpper(severity)
+		if severity != "" && !slices.Contains(dbTypes.SeverityNames, severity) {
+			return nil, xerrors.Errorf("unknown severity %q in %q", severity, scope)
+		}
+		statusScopes = append(statusScopes, result.StatusScope{
+			Class:    class,
+			Severity: severity,
+		})
+	}
+	return statusScopes, nil
+}
@@ -9,14 +9,17 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/flag"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/result"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
 func TestVulnerabilityFlagGroup_ToOptions(t *testing.T) {
 	type fields struct {
-		vulnType string
+		vulnType           string
+		ignoreUnfixedScope []string
 	}
 	tests := []struct {
 		name     string
@@ -24,6 +27,7 @@ func TestVulnerabilityFlagGroup_ToOptions(t *testing.T) {
 		fields   fields
 		want     flag.VulnerabilityOptions
 		wantLogs []string
+		wantErr  string
 	}{
 		{
 			name: "happy path for OS vulnerabilities",
@@ -45,6 +49,55 @@ func TestVulnerabilityFlagGroup_ToOptions(t *testing.T) {
 				VulnType: []string{types.VulnTypeLibrary},
 			},
 		},
+		{
+			name: "ignore unfixed scope",
+			args: []string{"alpine:latest"},
+			fields: fields{
+				vulnType: "os,library",
+				ignoreUnfixedScope: []string{
+					"os:low",
+					"os:MEDIUM",
+					"library",
+				},
+			},
+			want: flag.VulnerabilityOptions{
+				VulnType: []string{
+					types.VulnTypeOS,
+					types.VulnTypeLibrary,
+				},
+				IgnoreStatuses: []dbTypes.Status{
+					dbTypes.StatusUnknown,
+					dbTypes.StatusNotAffected,
+					dbTypes.StatusAffected,
+					dbTypes.StatusUnderInvestigation,
+					dbTypes.StatusWillNotFix,
+					dbTypes.StatusFixDeferred,
+					dbTypes.StatusEndOfLife,
+				},
+				IgnoreStatusScopes: []result.StatusScope{
+					{
+						Class:    types.ClassOSPkg,
+						Severity: "LOW",
+					},
+					{
+						Class:    types.ClassOSPkg,
+						Severity: "MEDIUM",
+					},
+					{
+						Class: types.ClassLangPkg,
+					},
+				},
+			},
+		},
+		{
+			name: "invalid ignore unfixed scope",
+			args: []string{"alpine:latest"},
+			fields: fields{
+				vulnType:           "os",
+				ignoreUnfixedScope: []string{"os:HIGHEST"},
+			},
+			wantErr: `unknown severity "HIGHEST" in "os:HIGHEST"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -55,13 +108,20 @@ func TestVulnerabilityFlagGroup_ToOptions(t *testing.T) {
 			log.Logger = zap.New(core).Sugar()
 
 			viper.Set(flag.VulnTypeFlag.ConfigName, tt.fields.vulnType)
+			viper.Set(flag.IgnoreUnfixedScopeFlag.ConfigName, tt.fields.ignoreUnfixedScope)
+			t.Cleanup(viper.Reset)
 
 			// Assert options
 			f := &flag.VulnerabilityFlagGroup{
-				VulnType: flag.VulnTypeFlag.Clone(),
+				VulnType:           flag.VulnTypeFlag.Clone(),
+				IgnoreUnfixedScope: flag.IgnoreUnfixedScopeFlag.Clone(),
 			}
 
 			got, err := f.ToOptions()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
 			require.NoError(t, err)
 			assert.Equalf(t, tt.want, got, "ToOptions()")
 
@@ -0,0 +1,134 @@
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// DefaultLanguage is the language of the messages in the source code
+const DefaultLanguage = "en"
+
+//go:embed catalogs/*.yaml
+var catalogFS embed.FS
+
+// Catalog holds the translations of a language.
+// A nil catalog returns the messages as they are.
+type Catalog struct {
+	// Messages are the translations of the messages in the table output, keyed by the English format string.
+	// Translations must keep the verbs of the format string in the same order.
+	Messages map[string]string `yaml:"messages"`
+
+	// Findings are the translations of findings keyed by the vulnerability ID or the check ID, e.g. AVD-DS-0002.
+	Findings map[string]Finding `yaml:"findings"`
+}
+
+// Finding represents the translation of a finding
+type Finding struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Resolution  string `yaml:"resolution"`
+}
+
+// Languages returns the supported languages
+func Languages() []string {
+	langs := []string{DefaultLanguage}
+	entries, err := catalogFS.ReadDir("catalogs")
+	if err != nil {
+		return langs
+	}
+	for _, entry := range entries {
+		langs = append(langs, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	slices.Sort(langs[1:])
+	return langs
+}
+
+// Load returns the catalog of the language. It returns nil for the default language.
+func Load(lang string) (*Catalog, error) {
+	if lang == "" || lang == DefaultLanguage {
+		return nil, nil
+	}
+	b, err := catalogFS.ReadFile(path.Join("catalogs", lang+".yaml"))
+	if err != nil {
+		return nil, xerrors.Errorf("unsupported language %q, supported: %q", lang, Languages())
+	}
+	var c Catalog
+	if err = yaml.Unmarshal(b, &c); err != nil {
+		return nil, xerrors.Errorf("failed to parse the catalog of %q: %w", lang, err)
+	}
+	return &c, nil
+}
+
+// T returns the translation of the message, or the message itself if it is not translated
+func (c *Catalog) T(msg string) string {
+	if c == nil {
+		return msg
+	}
+	if s, ok := c.Messages[msg]; ok && s != "" {
+		return s
+	}
+	return msg
+}
+
+// Sprintf formats the translation of the format string
+func (c *Catalog) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(c.T(format), args...)
+}
+
+// Localize replaces the titles, descriptions and resolutions of findings with the translations.
+// Findings with no translation are left in English.
+func (c *Catalog) Localize(report types.Report) types.Report {
+	if c == nil || len(c.Findings) == 0 {
+		return report
+	}
+	// Not to modify the original report
+	report.Results = slices.Clone(report.Results)
+	for i, result := range report.Results {
+		vulns := slices.Clone(result.Vulnerabilities)
+		for j, vuln := range vulns {
+			if f, ok := c.Findings[vuln.VulnerabilityID]; ok {
+				vulns[j].Title = replace(vuln.Title, f.Title)
+				vulns[j].Description = replace(vuln.Description, f.Description)
+			}
+		}
+		report.Results[i].Vulnerabilities = vulns
+
+		misconfs := slices.Clone(result.Misconfigurations)
+		for j, misconf := range misconfs {
+			f, ok := c.Findings[misconf.AVDID]
+			if !ok {
+				f, ok = c.Findings[misconf.ID]
+			}
+			if ok {
+				misconfs[j].Title = replace(misconf.Title, f.Title)
+				misconfs[j].Description = replace(misconf.Description, f.Description)
+				misconfs[j].Resolution = replace(misconf.Resolution, f.Resolution)
+			}
+		}
+		report.Results[i].Misconfigurations = misconfs
+
+		secrets := slices.Clone(result.Secrets)
+		for j, secret := range secrets {
+			if f, ok := c.Findings[secret.RuleID]; ok {
+				secrets[j].Title = replace(secret.Title, f.Title)
+			}
+		}
+		report.Results[i].Secrets = secrets
+	}
+	return report
+}
+
+func replace(s, translation string) string {
+	if translation == "" {
+		return s
+	}
+	return translation
+}
@@ -0,0 +1,101 @@
+package i18n_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+
+	"github.com/aquasecurity/trivy/pkg/i18n"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestLoad(t *testing.T) {
+	assert.Equal(t, []string{"en", "de", "fr", "ja"}, i18n.Languages())
+
+	c, err := i18n.Load("en")
+	require.NoError(t, err)
+	assert.Nil(t, c)
+	assert.Equal(t, "Total: 1 (HIGH: 1)", c.Sprintf("Total: %d (%s)", 1, "HIGH: 1"))
+
+	c, err = i18n.Load("ja")
+	require.NoError(t, err)
+	assert.Equal(t, "合計: 1 (HIGH: 1)", c.Sprintf("Total: %d (%s)", 1, "HIGH: 1"))
+	assert.Equal(t, "Unknown message", c.T("Unknown message"))
+
+	_, err = i18n.Load("es")
+	require.ErrorContains(t, err, `unsupported language "es"`)
+}
+
+// All the catalogs must translate the same messages with the same verbs
+func TestCatalogs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+
+	ja, err := i18n.Load("ja")
+	require.NoError(t, err)
+
+	for _, lang := range i18n.Languages()[1:] {
+		t.Run(lang, func(t *testing.T) {
+			c, err := i18n.Load(lang)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, maps.Keys(ja.Messages), maps.Keys(c.Messages))
+			assert.ElementsMatch(t, maps.Keys(ja.Findings), maps.Keys(c.Findings))
+
+			for msg, translation := range c.Messages {
+				assert.Equal(t, verbs.FindAllString(msg, -1), verbs.FindAllString(translation, -1), msg)
+			}
+		})
+	}
+}
+
+func TestCatalog_Localize(t *testing.T) {
+	c, err := i18n.Load("de")
+	require.NoError(t, err)
+
+	report := types.Report{
+		Results: types.Results{
+			{
+				Target: "Dockerfile",
+				Class:  types.ClassConfig,
+				Misconfigurations: []types.DetectedMisconfiguration{
+					{
+						ID:          "DS002",
+						AVDID:       "AVD-DS-0002",
+						Title:       "Image user should not be 'root'",
+						Description: "Running containers with 'root' user can lead to a container escape situation.",
+						Message:     "Specify at least 1 USER command in Dockerfile with non-root user as argument",
+						Resolution:  "Add 'USER <non root user name>' line to the Dockerfile",
+					},
+					{
+						ID:    "DS999",
+						AVDID: "AVD-DS-0999",
+						Title: "Custom check",
+					},
+				},
+			},
+		},
+	}
+
+	got := c.Localize(report)
+	want := []types.DetectedMisconfiguration{
+		{
+			ID:          "DS002",
+			AVDID:       "AVD-DS-0002",
+			Title:       "Der Benutzer des Images sollte nicht 'root' sein",
+			Description: "Das Ausführen von Containern mit dem Benutzer 'root' kann zum Ausbruch aus dem Container führen. Es ist bewährte Praxis, Container mit Benutzern ohne Root-Rechte auszuführen, was durch eine 'USER'-Anweisung im Dockerfile erreicht werden kann.",
+			Message:     "Specify at least 1 USER command in Dockerfile with non-root user as argument",
+			Resolution:  "Fügen Sie dem Dockerfile die Zeile 'USER <Benutzername ohne Root-Rechte>' hinzu",
+		},
+		{
+			ID:    "DS999",
+			AVDID: "AVD-DS-0999",
+			Title: "Custom check",
+		},
+	}
+	assert.Equal(t, want, got.Results[0].Misconfigurations)
+
+	// The original report is not modified
+	assert.Equal(t, "Image user should not be 'root'", report.Results[0].Misconfigurations[0].Title)
+}
@@ -123,6 +123,10 @@ func init() {
 	}
 
 	matchers[FileTypeAzureARM] = func(name string, r io.ReadSeeker) bool {
+		// Bicep files are lowered into ARM templates
+		if filepath.Ext(name) == ".bicep" {
+			return true
+		}
 
 		if resetReader(r) == nil {
 			return false
@@ -158,7 +162,7 @@ func init() {
 	}
 
 	matchers[FileTypeHelm] = func(name string, r io.ReadSeeker) bool {
-		helmFiles := []string{"Chart.yaml", ".helmignore", "values.schema.json", "NOTES.txt"}
+		helmFiles := []string{"Chart.yaml", "Chart.lock", ".helmignore", "values.schema.json", "NOTES.txt"}
 		for _, expected := range helmFiles {
 			if strings.HasSuffix(name, expected) {
 				return true
@@ -238,7 +242,13 @@ func init() {
 }
 
 func IsTerraformFile(path string) bool {
-	for _, ext := range []string{".tf", ".tf.json", ".tfvars"} {
+	for _, ext := range []string{
+		".tf", ".tf.json", ".tfvars",
+		// OpenTofu
+		".tofu", ".tofu.json",
+		// Terraform Stacks
+		".tfstack.hcl", ".tfdeploy.hcl",
+	} {
 		if strings.HasSuffix(path, ext) {
 			return true
 		}
@@ -0,0 +1,21 @@
+# METADATA
+# title: "Root user"
+# description: "Running containers as root is not recommended."
+# scope: package
+# schemas:
+#   - input: schema["dockerfile"]
+# custom:
+#   id: ID001
+#   severity: HIGH
+#   input:
+#     selector:
+#     - type: dockerfile
+package user.dockerfile.ID001
+
+import data.lib.docker
+
+deny[msg] {
+	user := docker.user[_]
+	user.Value[0] == "root"
+	msg := "Specify a non-root user"
+}
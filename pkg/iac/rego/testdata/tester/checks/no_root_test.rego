@@ -0,0 +1,13 @@
+package user.dockerfile.ID001
+
+test_root_denied {
+	count(deny) == 1 with input as data.fixtures.dockerfile.root
+}
+
+test_non_root_allowed {
+	count(deny) == 0 with input as data.fixtures.dockerfile.non_root
+}
+
+test_no_user_denied {
+	count(deny) == 1 with input as data.fixtures.dockerfile.non_root
+}
@@ -0,0 +1,13 @@
+# METADATA
+# title: "Typo"
+# scope: package
+# schemas:
+#   - input: schema["dockerfile"]
+# custom:
+#   id: ID002
+package user.dockerfile.ID002
+
+deny[msg] {
+	input.Stage[_].Name == "alpine:3.19"
+	msg := "typo"
+}
@@ -0,0 +1,5 @@
+package user.dockerfile.ID002
+
+test_denied {
+	count(deny) == 1 with input as {"Stages": [{"Name": "alpine:3.19"}]}
+}
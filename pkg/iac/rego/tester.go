@@ -0,0 +1,124 @@
+package rego
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/bundle"
+	"github.com/open-policy-agent/opa/storage"
+	"github.com/open-policy-agent/opa/tester"
+)
+
+// TestOptions holds the options for running the tests of checks
+type TestOptions struct {
+	// Filter is a regular expression to select the tests to run
+	Filter string
+	// Trace enables the trace output of failed tests
+	Trace bool
+}
+
+// RunTests runs the tests ("test_" rules) of the Rego checks in the paths, including "_test.rego" files.
+// The checks are compiled with the embedded libraries and type-checked against the input schemas
+// referenced in their metadata, as when scanning, so that the tests fail on inputs Trivy never produces.
+// Data files (JSON and YAML) in dataPaths, such as fixture inputs, are available under "data".
+func RunTests(ctx context.Context, policyFS fs.FS, policyPaths []string, dataFS fs.FS, dataPaths []string,
+	opts TestOptions) ([]*tester.Result, error) {
+	modules, err := loadModulesWithTests(policyFS, policyPaths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checks: %w", err)
+	}
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no checks found in %s", strings.Join(policyPaths, ", "))
+	}
+
+	libs, err := LoadEmbeddedLibraries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded rego libraries: %w", err)
+	}
+	for name, lib := range libs {
+		// Libraries in the paths take precedence over the embedded ones
+		if !hasPackage(modules, lib.Package.Path) {
+			modules["embedded/"+name] = lib
+		}
+	}
+
+	schemaSet, _, err := BuildSchemaSetFromPolicies(modules, policyPaths, policyFS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build schemas: %w", err)
+	}
+
+	compiler := ast.NewCompiler().
+		WithUseTypeCheckAnnotations(true).
+		WithCapabilities(ast.CapabilitiesForThisVersion()).
+		WithEnablePrintStatements(true).
+		WithSchemas(schemaSet)
+
+	var store storage.Store
+	if dataFS != nil {
+		if store, err = initStore(dataFS, dataPaths, nil); err != nil {
+			return nil, fmt.Errorf("failed to load data: %w", err)
+		}
+	}
+
+	runner := tester.NewRunner().
+		SetCompiler(compiler).
+		SetStore(store).
+		SetModules(modules).
+		CapturePrintOutput(true).
+		EnableTracing(opts.Trace).
+		Filter(opts.Filter)
+
+	ch, err := runner.RunTests(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run tests: %w", err)
+	}
+
+	var results []*tester.Result
+	for result := range ch {
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// loadModulesWithTests loads the Rego files in the paths as LoadPoliciesFromDirs does, but with tests
+func loadModulesWithTests(target fs.FS, paths []string) (map[string]*ast.Module, error) {
+	modules := make(map[string]*ast.Module)
+	for _, path := range paths {
+		if err := fs.WalkDir(target, sanitisePath(path), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || IsDotFile(d.Name()) || !strings.HasSuffix(d.Name(), bundle.RegoExt) {
+				return nil
+			}
+			data, err := fs.ReadFile(target, filepath.ToSlash(path))
+			if err != nil {
+				return err
+			}
+			module, err := ast.ParseModuleWithOpts(path, string(data), ast.ParserOptions{
+				ProcessAnnotation: true,
+			})
+			if err != nil {
+				return err
+			}
+			modules[path] = module
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return modules, nil
+}
+
+func hasPackage(modules map[string]*ast.Module, path ast.Ref) bool {
+	for _, module := range modules {
+		if module.Package.Path.Equal(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package rego
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTests(t *testing.T) {
+	type result struct {
+		Name string
+		Pass bool
+	}
+	tests := []struct {
+		name        string
+		policyPaths []string
+		opts        TestOptions
+		want        []result
+		wantErr     string
+	}{
+		{
+			name:        "checks with fixtures",
+			policyPaths: []string{"checks"},
+			want: []result{
+				{Name: "data.user.dockerfile.ID001.test_root_denied", Pass: true},
+				{Name: "data.user.dockerfile.ID001.test_non_root_allowed", Pass: true},
+				{Name: "data.user.dockerfile.ID001.test_no_user_denied", Pass: false},
+			},
+		},
+		{
+			name:        "filter",
+			policyPaths: []string{"checks"},
+			opts:        TestOptions{Filter: "root_denied"},
+			want: []result{
+				{Name: "data.user.dockerfile.ID001.test_root_denied", Pass: true},
+			},
+		},
+		{
+			name:        "input not matching the schema",
+			policyPaths: []string{"schema_error"},
+			wantErr:     "undefined ref: input.Stage",
+		},
+		{
+			name:        "no checks",
+			policyPaths: []string{"fixtures"},
+			wantErr:     "no checks found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := os.DirFS("testdata/tester")
+			got, err := RunTests(context.Background(), fsys, tt.policyPaths, fsys, []string{"fixtures"}, tt.opts)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var results []result
+			for _, r := range got {
+				require.NoError(t, r.Error)
+				results = append(results, result{
+					Name: r.Package + "." + r.Name,
+					Pass: r.Pass(),
+				})
+			}
+			assert.ElementsMatch(t, tt.want, results)
+		})
+	}
+}
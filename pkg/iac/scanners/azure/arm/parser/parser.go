@@ -11,6 +11,7 @@ import (
 	"github.com/aquasecurity/trivy/pkg/iac/debug"
 	azure2 "github.com/aquasecurity/trivy/pkg/iac/scanners/azure"
 	"github.com/aquasecurity/trivy/pkg/iac/scanners/azure/arm/parser/armjson"
+	"github.com/aquasecurity/trivy/pkg/iac/scanners/azure/bicep"
 	"github.com/aquasecurity/trivy/pkg/iac/scanners/azure/resolver"
 	"github.com/aquasecurity/trivy/pkg/iac/scanners/options"
 	"github.com/aquasecurity/trivy/pkg/iac/types"
@@ -43,6 +44,7 @@ func New(targetFS fs.FS, opts ...options.ParserOption) *Parser {
 func (p *Parser) ParseFS(ctx context.Context, dir string) ([]azure2.Deployment, error) {
 
 	var deployments []azure2.Deployment
+	var bicepFiles []string
 
 	if err := fs.WalkDir(p.targetFS, dir, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
@@ -59,6 +61,11 @@ func (p *Parser) ParseFS(ctx context.Context, dir string) ([]azure2.Deployment,
 		if !p.Required(path) {
 			return nil
 		}
+		// Bicep files are lowered together, as they may use each other as modules
+		if bicep.IsBicepFile(path) {
+			bicepFiles = append(bicepFiles, path)
+			return nil
+		}
 		f, err := p.targetFS.Open(path)
 		if err != nil {
 			return err
@@ -74,6 +81,14 @@ func (p *Parser) ParseFS(ctx context.Context, dir string) ([]azure2.Deployment,
 		return nil, err
 	}
 
+	if len(bicepFiles) > 0 {
+		lowered, err := bicep.Parse(ctx, p.targetFS, bicepFiles, p.debug)
+		if err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, lowered...)
+	}
+
 	return deployments, nil
 }
 
@@ -81,6 +96,9 @@ func (p *Parser) Required(path string) bool {
 	if p.skipRequired {
 		return true
 	}
+	if bicep.IsBicepFile(path) {
+		return true
+	}
 	if !strings.HasSuffix(path, ".json") {
 		return false
 	}
@@ -0,0 +1,157 @@
+package bicep
+
+// span is the range of lines of a node in the file
+type span struct {
+	start, end int
+}
+
+func (s span) pos() span {
+	return s
+}
+
+type node interface {
+	pos() span
+}
+
+type expr = node
+
+type literal struct {
+	span
+	// value is a string, an int64, a bool or nil
+	value any
+}
+
+// interpolation is a string with interpolated expressions, whose parts are string or expr
+type interpolation struct {
+	span
+	parts []any
+}
+
+type identifier struct {
+	span
+	name string
+}
+
+type arrayExpr struct {
+	span
+	items []expr
+}
+
+type property struct {
+	span
+	key   expr
+	value expr
+}
+
+type objectExpr struct {
+	span
+	properties []property
+	// resources are the nested resources declared in the object
+	resources []*resourceDecl
+}
+
+type memberExpr struct {
+	span
+	object expr
+	name   string
+}
+
+type indexExpr struct {
+	span
+	object expr
+	index  expr
+}
+
+type callExpr struct {
+	span
+	// receiver is the namespace or the object of the function, e.g. "az" in "az.resourceGroup()"
+	receiver expr
+	name     string
+	args     []expr
+}
+
+type unaryExpr struct {
+	span
+	op string
+	x  expr
+}
+
+type binaryExpr struct {
+	span
+	op          string
+	left, right expr
+}
+
+type ternaryExpr struct {
+	span
+	cond, then, otherwise expr
+}
+
+type lambdaExpr struct {
+	span
+}
+
+// forExpr is a loop, e.g. "[for (item, i) in items: if (cond) body]"
+type forExpr struct {
+	span
+	item       string
+	index      string
+	collection expr
+	body       expr
+}
+
+// ifExpr is a condition of a resource or a module, e.g. "if (cond) { ... }"
+type ifExpr struct {
+	span
+	cond expr
+	body expr
+}
+
+type decorator struct {
+	name string
+	args []expr
+}
+
+type paramDecl struct {
+	span
+	name       string
+	typ        string
+	value      expr
+	decorators []decorator
+}
+
+type varDecl struct {
+	span
+	name  string
+	value expr
+}
+
+type resourceDecl struct {
+	span
+	name     string
+	typ      string
+	existing bool
+	body     expr
+}
+
+type moduleDecl struct {
+	span
+	name string
+	path string
+	body expr
+}
+
+type outputDecl struct {
+	span
+	name  string
+	value expr
+}
+
+type file struct {
+	targetScope string
+	params      []*paramDecl
+	vars        []*varDecl
+	resources   []*resourceDecl
+	modules     []*moduleDecl
+	outputs     []*outputDecl
+}
@@ -0,0 +1,54 @@
+package bicep
+
+import (
+	"context"
+	"io/fs"
+	"path"
+
+	"github.com/aquasecurity/trivy/pkg/iac/debug"
+	"github.com/aquasecurity/trivy/pkg/iac/scanners/azure"
+)
+
+// IsBicepFile checks if the file is a Bicep file
+func IsBicepFile(name string) bool {
+	return path.Ext(name) == ".bicep"
+}
+
+// Parse lowers the Bicep files into ARM deployments, so that they are scanned as ARM templates.
+// The files used as modules by other files are only scanned through them, with the parameters passed by them.
+func Parse(ctx context.Context, fsys fs.FS, paths []string, logger debug.Logger) ([]azure.Deployment, error) {
+	l := &loader{
+		fsys:    fsys,
+		debug:   logger,
+		modules: make(map[string]struct{}),
+	}
+
+	type loaded struct {
+		path       string
+		deployment *azure.Deployment
+	}
+	var files []loaded
+	for _, p := range paths {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		deployment, err := l.load(p, nil)
+		if err != nil {
+			logger.Log("Failed to lower %s: %s", p, err)
+			continue
+		}
+		files = append(files, loaded{path: p, deployment: deployment})
+	}
+
+	var deployments []azure.Deployment
+	for _, f := range files {
+		if _, ok := l.modules[f.path]; ok {
+			logger.Log("Skipping %s used as a module", f.path)
+			continue
+		}
+		deployments = append(deployments, *f.deployment)
+	}
+	return deployments, nil
+}
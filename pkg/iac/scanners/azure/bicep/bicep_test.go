@@ -0,0 +1,222 @@
+package bicep
+
+import (
+	"context"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/iac/adapters/arm"
+	"github.com/aquasecurity/trivy/pkg/iac/debug"
+	"github.com/aquasecurity/trivy/pkg/iac/scanners/azure"
+)
+
+func TestParse(t *testing.T) {
+	deployments, err := Parse(context.TODO(), os.DirFS("testdata"), []string{"main.bicep", "modules/vault.bicep"}, debug.Logger{})
+	require.NoError(t, err)
+	// The module is only lowered as part of main.bicep
+	require.Len(t, deployments, 1)
+	deployment := deployments[0]
+
+	type resource struct {
+		typ        string
+		apiVersion string
+		name       string
+		file       string
+		startLine  int
+		endLine    int
+	}
+	var got []resource
+	for _, r := range deployment.Resources {
+		got = append(got, resource{
+			typ:        r.Type.AsString(),
+			apiVersion: r.APIVersion.AsString(),
+			name:       r.Name.AsString(),
+			file:       r.Metadata.Range().GetFilename(),
+			startLine:  r.Metadata.Range().GetStartLine(),
+			endLine:    r.Metadata.Range().GetEndLine(),
+		})
+	}
+	assert.Equal(t, []resource{
+		{"Microsoft.Storage/storageAccounts", "2023-01-01", "appdevsa", "main.bicep", 21, 39},
+		{"Microsoft.Storage/storageAccounts/blobServices", "2023-01-01", "appdevsa/default", "main.bicep", 36, 38},
+		{"Microsoft.Storage/storageAccounts/blobServices/containers", "2023-01-01", "appdevsa/default/logs", "main.bicep", 41, 47},
+		{"Microsoft.Storage/storageAccounts/blobServices/containers", "2023-01-01", "appdevsa/default/data", "main.bicep", 41, 47},
+		{"Microsoft.KeyVault/vaults", "2023-02-01", "app-kv", "modules/vault.bicep", 4, 16},
+	}, got)
+
+	storage := deployment.Resources[0]
+	assert.Equal(t, "Standard_LRS", storage.Sku.GetMapValue("name").AsString())
+	assert.Equal(t, azure.KindExpression, storage.Location.Kind)
+	assert.Equal(t, "resourceGroup().location", storage.Location.Raw())
+
+	tls := storage.Properties.GetMapValue("minimumTlsVersion")
+	assert.Equal(t, "TLS1_0", tls.AsString())
+	assert.Equal(t, 29, tls.Range().GetStartLine())
+
+	assert.Equal(t, "None", deployment.Resources[2].Properties.GetMapValue("publicAccess").AsString())
+	assert.Equal(t, "Blob", deployment.Resources[3].Properties.GetMapValue("publicAccess").AsString())
+
+	// The parameters passed to the module override its defaults
+	assert.False(t, deployment.Resources[4].Properties.GetMapValue("enablePurgeProtection").AsBool())
+
+	require.Len(t, deployment.Outputs, 2)
+	assert.Equal(t, "https://app-kv.vault.azure.net", deployment.Outputs[1].Value.AsString())
+
+	require.Len(t, deployment.Parameters, 4)
+	assert.Equal(t, "prefix", deployment.Parameters[0].Name)
+	assert.Len(t, deployment.Parameters[0].Decorators, 2)
+}
+
+func TestParse_Adapt(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.bicep": {Data: []byte(`param httpsOnly bool = false
+
+resource storage 'Microsoft.Storage/storageAccounts@2023-01-01' = {
+  name: 'example'
+  properties: {
+    supportsHttpsTrafficOnly: httpsOnly
+    minimumTlsVersion: 'TLS1_2'
+  }
+}
+`)},
+	}
+	deployments, err := Parse(context.TODO(), fsys, []string{"main.bicep"}, debug.Logger{})
+	require.NoError(t, err)
+	require.Len(t, deployments, 1)
+
+	state := arm.Adapt(context.TODO(), deployments[0])
+	require.Len(t, state.Azure.Storage.Accounts, 1)
+	account := state.Azure.Storage.Accounts[0]
+	assert.False(t, account.EnforceHTTPS.Value())
+	assert.Equal(t, 6, account.EnforceHTTPS.GetMetadata().Range().GetStartLine())
+	assert.Equal(t, "TLS1_2", account.MinimumTLSVersion.Value())
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   any
+		// unresolved is the ARM expression of a value which can't be evaluated
+		unresolved string
+	}{
+		{
+			name:   "interpolation",
+			source: "var a = 'x'\nvar b = 2\nvar out = '${a}-${b}-${true}'",
+			want:   "x-2-true",
+		},
+		{
+			name:   "escapes",
+			source: `var out = 'it\'s \${literal}'`,
+			want:   "it's ${literal}",
+		},
+		{
+			name:   "multi-line string",
+			source: "var out = '''\nline 1\nline 2'''",
+			want:   "line 1\nline 2",
+		},
+		{
+			name:   "operators",
+			source: "var out = (1 + 2) * 3 - 4 / 2 % 3",
+			want:   int64(7),
+		},
+		{
+			name:   "comparison",
+			source: "var out = 'Abc' =~ 'aBC' && !(2 > 3) || false",
+			want:   true,
+		},
+		{
+			name:   "ternary",
+			source: "param env string = 'prod'\nvar out = env == 'prod' ? 'Standard_GRS' : 'Standard_LRS'",
+			want:   "Standard_GRS",
+		},
+		{
+			name:   "coalesce",
+			source: "var settings = {\n  a: null\n}\nvar out = settings.a ?? 'default'",
+			want:   "default",
+		},
+		{
+			name:   "functions",
+			source: "var out = toUpper(concat('a', sys.string(length([1, 2]))))",
+			want:   "A2",
+		},
+		{
+			name:   "member and index",
+			source: "var obj = {\n  'key-1': [\n    {\n      name: 'first'\n    }\n  ]\n}\nvar out = obj['key-1'][0].name",
+			want:   "first",
+		},
+		{
+			name:   "loop",
+			source: "var out = [for i in range(0, 3): i * 2]",
+			want:   []any{int64(0), int64(2), int64(4)},
+		},
+		{
+			name:   "loop with condition",
+			source: "var items = [\n  'a'\n  'b'\n]\nvar out = [for (item, i) in items: if (i > 0) '${item}${i}']",
+			want:   []any{"b1"},
+		},
+		{
+			name:   "reference to a resource",
+			source: "resource sa 'Microsoft.Storage/storageAccounts@2023-01-01' = {\n  name: 'example'\n  properties: {\n    minimumTlsVersion: 'TLS1_2'\n  }\n}\nvar out = sa.properties.minimumTlsVersion",
+			want:   "TLS1_2",
+		},
+		{
+			name:   "skipped declarations",
+			source: "metadata info = 'example'\n\ntype sku = 'a' | 'b'\n\nfunc greet(name string) string => 'Hi ${name}'\n\n// comment\n/* block\ncomment */\nvar out = 'ok'",
+			want:   "ok",
+		},
+		{
+			name:       "deployment function",
+			source:     "param location string = resourceGroup().location\nvar out = '${location}-vnet'",
+			unresolved: "format('{0}-vnet', parameters('location'))",
+		},
+		{
+			name:       "lambda",
+			source:     "var out = filter([1, 2], x => x > 1)",
+			unresolved: "filter(createArray(1, 2), null())",
+		},
+		{
+			name:       "parameter without default",
+			source:     "param name string\nvar out = toLower(name)",
+			unresolved: "toLower(parameters('name'))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"main.bicep": {Data: []byte(tt.source)},
+			}
+			deployments, err := Parse(context.TODO(), fsys, []string{"main.bicep"}, debug.Logger{})
+			require.NoError(t, err)
+			require.Len(t, deployments, 1)
+
+			var out azure.Value
+			for _, v := range deployments[0].Variables {
+				if v.Name == "out" {
+					out = v.Value
+				}
+			}
+			if tt.unresolved != "" {
+				assert.Equal(t, azure.KindExpression, out.Kind)
+				assert.Equal(t, tt.unresolved, out.Raw())
+				return
+			}
+			assert.Equal(t, tt.want, toRaw(out))
+		})
+	}
+}
+
+func TestParse_InvalidFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"invalid.bicep": {Data: []byte("resource sa = {\n")},
+		"valid.bicep":   {Data: []byte("var a = 'a'\n")},
+	}
+	deployments, err := Parse(context.TODO(), fsys, []string{"invalid.bicep", "valid.bicep"}, debug.Logger{})
+	require.NoError(t, err)
+	require.Len(t, deployments, 1)
+	assert.Equal(t, "valid.bicep", deployments[0].Metadata.Range().GetFilename())
+}
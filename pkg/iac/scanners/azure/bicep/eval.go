@@ -0,0 +1,429 @@
+package bicep
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/iac/scanners/azure"
+	"github.com/aquasecurity/trivy/pkg/iac/scanners/azure/functions"
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+// scope holds the variables of the loops
+type scope map[string]azure.Value
+
+func (s scope) with(name string, value azure.Value) scope {
+	child := make(scope, len(s)+1)
+	for k, v := range s {
+		child[k] = v
+	}
+	child[name] = value
+	return child
+}
+
+// eval evaluates the expression statically.
+// Values depending on the deployment, e.g. "resourceGroup().location", are lowered to unresolved ARM expressions.
+func (e *evaluator) eval(x expr, s scope) azure.Value {
+	switch x := x.(type) {
+	case *literal:
+		return azure.NewValue(x.value, e.metadata(x.span))
+	case *interpolation:
+		return e.evalInterpolation(x, s)
+	case *identifier:
+		return e.evalIdentifier(x, s)
+	case *arrayExpr:
+		items := make([]azure.Value, 0, len(x.items))
+		for _, item := range x.items {
+			items = append(items, e.eval(item, s))
+		}
+		return azure.NewValue(items, e.metadata(x.span))
+	case *objectExpr:
+		return e.evalObject(x, s)
+	case *memberExpr:
+		object := e.eval(x.object, s)
+		if object.Kind != azure.KindObject || !object.HasKey(x.name) {
+			return e.unresolved(x, s)
+		}
+		return object.GetMapValue(x.name)
+	case *indexExpr:
+		return e.evalIndex(x, s)
+	case *callExpr:
+		return e.evalCall(x, s)
+	case *unaryExpr:
+		return e.evalUnary(x, s)
+	case *binaryExpr:
+		return e.evalBinary(x, s)
+	case *ternaryExpr:
+		cond := e.eval(x.cond, s)
+		if cond.Kind != azure.KindBoolean {
+			return e.unresolved(x, s)
+		}
+		if cond.AsBool() {
+			return e.eval(x.then, s)
+		}
+		return e.eval(x.otherwise, s)
+	case *forExpr:
+		items, ok := e.iterate(x, s)
+		if !ok {
+			return e.unresolved(x, s)
+		}
+		values := make([]azure.Value, 0, len(items))
+		for _, item := range items {
+			values = append(values, e.eval(item.body, item.scope))
+		}
+		return azure.NewValue(values, e.metadata(x.span))
+	case *ifExpr:
+		return e.eval(x.body, s)
+	}
+	return e.unresolved(x, s)
+}
+
+type iteration struct {
+	body  expr
+	scope scope
+}
+
+// iterate expands the loop, skipping the items whose condition is false.
+// It returns false if the collection can't be resolved.
+func (e *evaluator) iterate(loop *forExpr, s scope) ([]iteration, bool) {
+	collection := e.eval(loop.collection, s)
+	if collection.Kind != azure.KindArray {
+		return nil, false
+	}
+	var iterations []iteration
+	for i, item := range collection.AsList() {
+		itemScope := s.with(loop.item, item)
+		if loop.index != "" {
+			itemScope = itemScope.with(loop.index, azure.NewValue(int64(i), e.metadata(loop.span)))
+		}
+		body := loop.body
+		if cond, ok := body.(*ifExpr); ok {
+			if c := e.eval(cond.cond, itemScope); c.Kind == azure.KindBoolean && !c.AsBool() {
+				continue
+			}
+			body = cond.body
+		}
+		iterations = append(iterations, iteration{body: body, scope: itemScope})
+	}
+	return iterations, true
+}
+
+func (e *evaluator) evalInterpolation(x *interpolation, s scope) azure.Value {
+	var sb strings.Builder
+	for _, part := range x.parts {
+		switch part := part.(type) {
+		case string:
+			sb.WriteString(part)
+		case expr:
+			v := e.eval(part, s)
+			switch v.Kind {
+			case azure.KindString, azure.KindNumber, azure.KindBoolean:
+				sb.WriteString(fmt.Sprint(v.Raw()))
+			default:
+				return e.unresolved(x, s)
+			}
+		}
+	}
+	return azure.NewValue(sb.String(), e.metadata(x.span))
+}
+
+func (e *evaluator) evalIdentifier(x *identifier, s scope) azure.Value {
+	var v azure.Value
+	switch {
+	case hasKey(s, x.name):
+		v = s[x.name]
+	case hasKey(e.paramDecls, x.name):
+		v = e.param(x.name)
+	case hasKey(e.varDecls, x.name):
+		v = e.variable(x.name)
+	case hasKey(e.resourceDecls, x.name):
+		return e.resource(x.name)
+	case hasKey(e.moduleDecls, x.name):
+		return e.module(x.name)
+	default:
+		return e.unresolved(x, s)
+	}
+	// Scalar values are reported where they are used
+	switch v.Kind {
+	case azure.KindString, azure.KindNumber, azure.KindBoolean, azure.KindNull, azure.KindExpression:
+		v.Metadata = e.metadata(x.span)
+	}
+	return v
+}
+
+func (e *evaluator) evalObject(x *objectExpr, s scope) azure.Value {
+	properties := make(map[string]azure.Value, len(x.properties))
+	for _, prop := range x.properties {
+		key := e.eval(prop.key, s)
+		if key.Kind != azure.KindString {
+			continue
+		}
+		properties[key.AsString()] = e.eval(prop.value, s)
+	}
+	return azure.NewValue(properties, e.metadata(x.span))
+}
+
+func (e *evaluator) evalIndex(x *indexExpr, s scope) azure.Value {
+	object := e.eval(x.object, s)
+	index := e.eval(x.index, s)
+	switch {
+	case object.Kind == azure.KindArray && index.Kind == azure.KindNumber:
+		items := object.AsList()
+		if i := index.AsInt(); i >= 0 && i < len(items) {
+			return items[i]
+		}
+	case object.Kind == azure.KindObject && index.Kind == azure.KindString:
+		if object.HasKey(index.AsString()) {
+			return object.GetMapValue(index.AsString())
+		}
+	}
+	return e.unresolved(x, s)
+}
+
+func (e *evaluator) evalCall(x *callExpr, s scope) azure.Value {
+	// Methods of resources, e.g. "storage.listKeys()", depend on the deployment
+	if x.receiver != nil {
+		if ns, ok := x.receiver.(*identifier); !ok || (ns.name != "az" && ns.name != "sys") {
+			return e.unresolved(x, s)
+		}
+	}
+	if x.name == "any" && len(x.args) == 1 {
+		return e.eval(x.args[0], s)
+	}
+
+	args := make([]any, 0, len(x.args))
+	for _, arg := range x.args {
+		v := e.eval(arg, s)
+		if !isResolved(v) {
+			return e.unresolved(x, s)
+		}
+		args = append(args, toArg(toRaw(v)))
+	}
+	result := e.call(x.name, args)
+	if result == nil {
+		return e.unresolved(x, s)
+	}
+	return fromRaw(result, e.metadata(x.span))
+}
+
+// call calls the ARM function, which may panic on unexpected arguments
+func (e *evaluator) call(name string, args []any) (result any) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+		}
+	}()
+	return functions.Evaluate(e.deployment, name, args...)
+}
+
+func (e *evaluator) evalUnary(x *unaryExpr, s scope) azure.Value {
+	v := e.eval(x.x, s)
+	switch {
+	case x.op == "!" && v.Kind == azure.KindBoolean:
+		return azure.NewValue(!v.AsBool(), e.metadata(x.span))
+	case x.op == "-" && v.Kind == azure.KindNumber:
+		return azure.NewValue(-toRaw(v).(int64), e.metadata(x.span))
+	}
+	return e.unresolved(x, s)
+}
+
+func (e *evaluator) evalBinary(x *binaryExpr, s scope) azure.Value {
+	left := e.eval(x.left, s)
+	meta := e.metadata(x.span)
+
+	// Short-circuits
+	switch {
+	case x.op == "&&" && left.Kind == azure.KindBoolean && !left.AsBool():
+		return azure.NewValue(false, meta)
+	case x.op == "||" && left.Kind == azure.KindBoolean && left.AsBool():
+		return azure.NewValue(true, meta)
+	case x.op == "??" && isResolved(left) && left.Kind != azure.KindNull:
+		return left
+	}
+
+	right := e.eval(x.right, s)
+	if !isResolved(left) || !isResolved(right) {
+		return e.unresolved(x, s)
+	}
+
+	l, r := toRaw(left), toRaw(right)
+	switch x.op {
+	case "??":
+		return right
+	case "&&", "||":
+		if right.Kind == azure.KindBoolean {
+			return azure.NewValue(right.AsBool(), meta)
+		}
+	case "==":
+		return azure.NewValue(reflect.DeepEqual(l, r), meta)
+	case "!=":
+		return azure.NewValue(!reflect.DeepEqual(l, r), meta)
+	case "=~", "!~":
+		if ls, ok := l.(string); ok {
+			if rs, ok := r.(string); ok {
+				return azure.NewValue(strings.EqualFold(ls, rs) == (x.op == "=~"), meta)
+			}
+		}
+	default:
+		if result, ok := arithmetic(x.op, l, r); ok {
+			return azure.NewValue(result, meta)
+		}
+	}
+	return e.unresolved(x, s)
+}
+
+func arithmetic(op string, l, r any) (any, bool) {
+	if ls, ok := l.(string); ok {
+		rs, ok := r.(string)
+		if !ok {
+			return nil, false
+		}
+		switch op {
+		case "<":
+			return ls < rs, true
+		case "<=":
+			return ls <= rs, true
+		case ">":
+			return ls > rs, true
+		case ">=":
+			return ls >= rs, true
+		}
+		return nil, false
+	}
+
+	li, ok := l.(int64)
+	if !ok {
+		return nil, false
+	}
+	ri, ok := r.(int64)
+	if !ok {
+		return nil, false
+	}
+	switch op {
+	case "<":
+		return li < ri, true
+	case "<=":
+		return li <= ri, true
+	case ">":
+		return li > ri, true
+	case ">=":
+		return li >= ri, true
+	case "+":
+		return li + ri, true
+	case "-":
+		return li - ri, true
+	case "*":
+		return li * ri, true
+	case "/":
+		if ri != 0 {
+			return li / ri, true
+		}
+	case "%":
+		if ri != 0 {
+			return li % ri, true
+		}
+	}
+	return nil, false
+}
+
+// unresolved returns the ARM expression of a value which can't be evaluated statically
+func (e *evaluator) unresolved(x expr, s scope) azure.Value {
+	v := azure.NewValue(e.armExpression(x, s), e.metadata(x.pos()))
+	v.Kind = azure.KindExpression
+	return v
+}
+
+func isResolved(v azure.Value) bool {
+	return v.Kind != azure.KindExpression && v.Kind != azure.KindUnresolvable
+}
+
+func hasKey[T any](m map[string]T, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// toRaw converts the value to the arguments expected by the ARM functions
+func toRaw(v azure.Value) any {
+	switch v.Kind {
+	case azure.KindArray:
+		var items []any
+		for _, item := range v.AsList() {
+			items = append(items, toRaw(item))
+		}
+		return items
+	case azure.KindObject:
+		m := make(map[string]any)
+		for k, item := range v.AsMap() {
+			m[k] = toRaw(item)
+		}
+		return m
+	}
+	return v.Raw()
+}
+
+// toArg converts the numbers to int, as expected by the ARM functions
+func toArg(raw any) any {
+	switch raw := raw.(type) {
+	case int64:
+		return int(raw)
+	case []any:
+		args := make([]any, 0, len(raw))
+		for _, item := range raw {
+			args = append(args, toArg(item))
+		}
+		return args
+	case map[string]any:
+		m := make(map[string]any, len(raw))
+		for k, item := range raw {
+			m[k] = toArg(item)
+		}
+		return m
+	}
+	// Typed slices, e.g. []int or []string
+	if v := reflect.ValueOf(raw); v.Kind() == reflect.Slice {
+		items := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, normalize(v.Index(i).Interface()))
+		}
+		return items
+	}
+	return raw
+}
+
+// fromRaw converts the result of an ARM function, whose numbers are stored as int64 like in ARM templates
+func fromRaw(raw any, meta types.Metadata) azure.Value {
+	return azure.NewValue(normalize(raw), meta)
+}
+
+func normalize(raw any) any {
+	switch raw := raw.(type) {
+	case int:
+		return int64(raw)
+	case int32:
+		return int64(raw)
+	case float64:
+		return int64(raw)
+	case []any:
+		items := make([]any, 0, len(raw))
+		for _, item := range raw {
+			items = append(items, normalize(item))
+		}
+		return items
+	case map[string]any:
+		m := make(map[string]any, len(raw))
+		for k, item := range raw {
+			m[k] = normalize(item)
+		}
+		return m
+	}
+	// Typed slices, e.g. []int or []string
+	if v := reflect.ValueOf(raw); v.Kind() == reflect.Slice {
+		items := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, normalize(v.Index(i).Interface()))
+		}
+		return items
+	}
+	return raw
+}
@@ -0,0 +1,245 @@
+package bicep
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNewline
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	// parts holds the literal text and the interpolated expressions of a string,
+	// as string and []token respectively
+	parts []any
+	line  int
+	// endLine is the last line of multi-line strings
+	endLine int
+}
+
+func (t token) is(kind tokenKind, value string) bool {
+	return t.kind == kind && t.value == value
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of file"
+	case tokenNewline:
+		return "new line"
+	case tokenString:
+		return "string"
+	}
+	return fmt.Sprintf("'%s'", t.value)
+}
+
+// punctuations are sorted so that the longest ones are matched first
+var punctuations = []string{
+	"=>", "::", "==", "!=", "=~", "!~", "<=", ">=", "&&", "||", "??", "?.", "?[",
+	"{", "}", "[", "]", "(", ")", ":", ",", ".", "?", "!", "=", "<", ">", "+", "-", "*", "/", "%", "@", "|",
+}
+
+type lexer struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func lex(src string) ([]token, error) {
+	l := &lexer{
+		src:  []rune(strings.ReplaceAll(src, "\r\n", "\n")),
+		line: 1,
+	}
+	tokens, err := l.lex(false)
+	if err != nil {
+		return nil, err
+	}
+	return append(tokens, token{kind: tokenEOF, line: l.line}), nil
+}
+
+// lex returns the tokens until the end of the source, or until the closing brace of an interpolation
+func (l *lexer) lex(interpolation bool) ([]token, error) {
+	var tokens []token
+	depth := 0
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			if len(tokens) == 0 || tokens[len(tokens)-1].kind != tokenNewline {
+				tokens = append(tokens, token{kind: tokenNewline, line: l.line})
+			}
+			l.line++
+			l.pos++
+		case unicode.IsSpace(c):
+			l.pos++
+		case l.hasPrefix("//"):
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case l.hasPrefix("/*"):
+			if err := l.skipBlockComment(); err != nil {
+				return nil, err
+			}
+		case l.hasPrefix("'''"):
+			tok, err := l.lexMultilineString()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+		case c == '\'':
+			tok, err := l.lexString()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+		case unicode.IsDigit(c):
+			start := l.pos
+			for l.pos < len(l.src) && (unicode.IsDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+				l.pos++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(l.src[start:l.pos]), line: l.line})
+		case c == '_' || unicode.IsLetter(c):
+			start := l.pos
+			for l.pos < len(l.src) && (l.src[l.pos] == '_' || unicode.IsLetter(l.src[l.pos]) || unicode.IsDigit(l.src[l.pos])) {
+				l.pos++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(l.src[start:l.pos]), line: l.line})
+		default:
+			punct := l.lexPunct()
+			if punct == "" {
+				return nil, fmt.Errorf("unexpected character '%c' at line %d", c, l.line)
+			}
+			if interpolation {
+				switch punct {
+				case "{":
+					depth++
+				case "}":
+					if depth == 0 {
+						return tokens, nil
+					}
+					depth--
+				}
+			}
+			tokens = append(tokens, token{kind: tokenPunct, value: punct, line: l.line})
+		}
+	}
+	if interpolation {
+		return nil, fmt.Errorf("unterminated string interpolation at line %d", l.line)
+	}
+	return tokens, nil
+}
+
+func (l *lexer) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(string(l.src[l.pos:min(l.pos+len(prefix), len(l.src))]), prefix)
+}
+
+func (l *lexer) lexPunct() string {
+	for _, punct := range punctuations {
+		if l.hasPrefix(punct) {
+			l.pos += len(punct)
+			return punct
+		}
+	}
+	return ""
+}
+
+func (l *lexer) skipBlockComment() error {
+	start := l.line
+	l.pos += 2
+	for l.pos < len(l.src) {
+		if l.hasPrefix("*/") {
+			l.pos += 2
+			return nil
+		}
+		if l.src[l.pos] == '\n' {
+			l.line++
+		}
+		l.pos++
+	}
+	return fmt.Errorf("unterminated comment at line %d", start)
+}
+
+// lexMultilineString lexes a string delimited by triple quotes, which supports neither escapes nor interpolation
+func (l *lexer) lexMultilineString() (token, error) {
+	tok := token{kind: tokenString, line: l.line}
+	l.pos += 3
+	// The first line break is not part of the string
+	if l.pos < len(l.src) && l.src[l.pos] == '\n' {
+		l.line++
+		l.pos++
+	}
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		if l.hasPrefix("'''") {
+			l.pos += 3
+			tok.parts = []any{sb.String()}
+			tok.endLine = l.line
+			return tok, nil
+		}
+		if l.src[l.pos] == '\n' {
+			l.line++
+		}
+		sb.WriteRune(l.src[l.pos])
+		l.pos++
+	}
+	return tok, fmt.Errorf("unterminated string at line %d", tok.line)
+}
+
+func (l *lexer) lexString() (token, error) {
+	tok := token{kind: tokenString, line: l.line}
+	l.pos++
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\'':
+			l.pos++
+			if sb.Len() > 0 || len(tok.parts) == 0 {
+				tok.parts = append(tok.parts, sb.String())
+			}
+			return tok, nil
+		case c == '\n':
+			return tok, fmt.Errorf("unterminated string at line %d", tok.line)
+		case c == '\\' && l.pos+1 < len(l.src):
+			l.pos++
+			switch e := l.src[l.pos]; e {
+			case 'n':
+				sb.WriteRune('\n')
+			case 'r':
+				sb.WriteRune('\r')
+			case 't':
+				sb.WriteRune('\t')
+			default:
+				// \\, \' and \$
+				sb.WriteRune(e)
+			}
+			l.pos++
+		case l.hasPrefix("${"):
+			l.pos += 2
+			if sb.Len() > 0 {
+				tok.parts = append(tok.parts, sb.String())
+				sb.Reset()
+			}
+			tokens, err := l.lex(true)
+			if err != nil {
+				return tok, err
+			}
+			tok.parts = append(tok.parts, append(tokens, token{kind: tokenEOF, line: l.line}))
+		default:
+			sb.WriteRune(c)
+			l.pos++
+		}
+	}
+	return tok, fmt.Errorf("unterminated string at line %d", tok.line)
+}
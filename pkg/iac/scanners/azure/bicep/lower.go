@@ -0,0 +1,590 @@
+package bicep
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/iac/debug"
+	"github.com/aquasecurity/trivy/pkg/iac/scanners/azure"
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+// evaluator lowers a Bicep file into an ARM deployment
+type evaluator struct {
+	loader     *loader
+	path       string
+	file       *file
+	deployment *azure.Deployment
+
+	paramDecls    map[string]*paramDecl
+	varDecls      map[string]*varDecl
+	resourceDecls map[string]*resourceDecl
+	moduleDecls   map[string]*moduleDecl
+	// nestedParents holds the parents of the nested resources
+	nestedParents map[string]*resourceDecl
+
+	// params holds the values passed by the parent file of a module
+	params  map[string]azure.Value
+	values  map[string]azure.Value
+	pending map[string]struct{}
+}
+
+func newEvaluator(l *loader, filePath string, f *file, params map[string]azure.Value) *evaluator {
+	e := &evaluator{
+		loader:        l,
+		path:          filePath,
+		file:          f,
+		deployment:    &azure.Deployment{},
+		paramDecls:    make(map[string]*paramDecl),
+		varDecls:      make(map[string]*varDecl),
+		resourceDecls: make(map[string]*resourceDecl),
+		moduleDecls:   make(map[string]*moduleDecl),
+		nestedParents: make(map[string]*resourceDecl),
+		params:        params,
+		values:        make(map[string]azure.Value),
+		pending:       make(map[string]struct{}),
+	}
+	for _, p := range f.params {
+		e.paramDecls[p.name] = p
+	}
+	for _, v := range f.vars {
+		e.varDecls[v.name] = v
+	}
+	for _, r := range f.resources {
+		e.addResourceDecls(r)
+	}
+	for _, m := range f.modules {
+		e.moduleDecls[m.name] = m
+	}
+	return e
+}
+
+// addResourceDecls registers the resource along with its nested resources, which share the same namespace
+func (e *evaluator) addResourceDecls(r *resourceDecl) {
+	e.resourceDecls[r.name] = r
+	if obj := resourceObject(r.body); obj != nil {
+		for _, child := range obj.resources {
+			e.nestedParents[child.name] = r
+			e.addResourceDecls(child)
+		}
+	}
+}
+
+func (e *evaluator) metadata(s span) types.Metadata {
+	return types.NewMetadata(types.NewRange(e.path, s.start, s.end, "", e.loader.fsys), "")
+}
+
+// lazy evaluates a declaration once, as declarations may refer to each other in any order
+func (e *evaluator) lazy(key string, fallback expr, evaluate func() azure.Value) azure.Value {
+	if v, ok := e.values[key]; ok {
+		return v
+	}
+	if _, ok := e.pending[key]; ok {
+		// cycle
+		return e.unresolved(fallback, nil)
+	}
+	e.pending[key] = struct{}{}
+	v := evaluate()
+	delete(e.pending, key)
+	e.values[key] = v
+	return v
+}
+
+func (e *evaluator) param(name string) azure.Value {
+	decl := e.paramDecls[name]
+	return e.lazy("param."+name, &identifier{span: decl.span, name: name}, func() azure.Value {
+		if v, ok := e.params[name]; ok {
+			return v
+		}
+		if decl.value != nil {
+			return e.eval(decl.value, nil)
+		}
+		return e.unresolved(&identifier{span: decl.span, name: name}, nil)
+	})
+}
+
+func (e *evaluator) variable(name string) azure.Value {
+	decl := e.varDecls[name]
+	return e.lazy("var."+name, &identifier{span: decl.span, name: name}, func() azure.Value {
+		return e.eval(decl.value, nil)
+	})
+}
+
+// resource returns the symbolic reference of a resource, e.g. for "storage.properties.minimumTlsVersion"
+func (e *evaluator) resource(name string) azure.Value {
+	decl := e.resourceDecls[name]
+	ref := &identifier{span: decl.span, name: name}
+	return e.lazy("resource."+name, ref, func() azure.Value {
+		if loop, ok := decl.body.(*forExpr); ok {
+			iterations, ok := e.iterate(loop, nil)
+			if !ok {
+				return e.unresolved(ref, nil)
+			}
+			var instances []azure.Value
+			for _, it := range iterations {
+				instances = append(instances, e.resourceValue(decl, it.body, it.scope))
+			}
+			return azure.NewValue(instances, e.metadata(decl.span))
+		}
+		return e.resourceValue(decl, decl.body, nil)
+	})
+}
+
+func (e *evaluator) resourceValue(decl *resourceDecl, body expr, s scope) azure.Value {
+	if cond, ok := body.(*ifExpr); ok {
+		body = cond.body
+	}
+	v := e.eval(body, s)
+	if v.Kind != azure.KindObject {
+		return e.unresolved(&identifier{span: decl.span, name: decl.name}, s)
+	}
+	props := v.AsMap()
+	typ, apiVersion := e.fullType(decl, 0)
+	props["type"] = azure.NewValue(typ, e.metadata(decl.span))
+	props["apiVersion"] = azure.NewValue(apiVersion, e.metadata(decl.span))
+	props["id"] = e.unresolved(&memberExpr{span: decl.span, object: &identifier{span: decl.span, name: decl.name}, name: "id"}, s)
+	return azure.NewValue(props, v.Metadata)
+}
+
+// module returns the symbolic reference of a module, e.g. for "network.outputs.subnetId"
+func (e *evaluator) module(name string) azure.Value {
+	decl := e.moduleDecls[name]
+	ref := &identifier{span: decl.span, name: name}
+	return e.lazy("module."+name, ref, func() azure.Value {
+		if _, ok := decl.body.(*forExpr); ok {
+			return e.unresolved(ref, nil)
+		}
+		body := decl.body
+		if cond, ok := body.(*ifExpr); ok {
+			body = cond.body
+		}
+		v := e.eval(body, nil)
+		if v.Kind != azure.KindObject {
+			return e.unresolved(ref, nil)
+		}
+		props := v.AsMap()
+		deployment, ok := e.loadModule(decl, v)
+		if !ok {
+			return e.unresolved(ref, nil)
+		}
+		outputs := make(map[string]azure.Value)
+		for _, output := range deployment.Outputs {
+			outputs[output.Name] = output.Value
+		}
+		props["outputs"] = azure.NewValue(outputs, e.metadata(decl.span))
+		return azure.NewValue(props, v.Metadata)
+	})
+}
+
+// lower builds the deployment.
+// The resources of the modules are added to the deployment, as if they were deployed by the file.
+func (e *evaluator) lower() *azure.Deployment {
+	d := e.deployment
+	d.Metadata = types.NewMetadata(types.NewRange(e.path, 0, 0, "", e.loader.fsys), "")
+	d.TargetScope = azure.ScopeResourceGroup
+	if e.file.targetScope != "" {
+		d.TargetScope = azure.Scope(e.file.targetScope)
+	}
+
+	for _, decl := range e.file.params {
+		param := azure.Parameter{
+			Variable: azure.Variable{
+				Name:  decl.name,
+				Value: e.param(decl.name),
+			},
+		}
+		if decl.value != nil {
+			param.Default = e.eval(decl.value, nil)
+		}
+		for _, d := range decl.decorators {
+			decorator := azure.Decorator{Name: d.name}
+			for _, arg := range d.args {
+				decorator.Args = append(decorator.Args, e.eval(arg, nil))
+			}
+			param.Decorators = append(param.Decorators, decorator)
+		}
+		d.Parameters = append(d.Parameters, param)
+	}
+
+	for _, decl := range e.file.vars {
+		d.Variables = append(d.Variables, azure.Variable{
+			Name:  decl.name,
+			Value: e.variable(decl.name),
+		})
+	}
+
+	for _, decl := range e.file.resources {
+		d.Resources = append(d.Resources, e.lowerResource(decl, nil)...)
+	}
+
+	for _, decl := range e.file.modules {
+		d.Resources = append(d.Resources, e.lowerModule(decl)...)
+	}
+
+	for _, decl := range e.file.outputs {
+		d.Outputs = append(d.Outputs, azure.Output{
+			Name:  decl.name,
+			Value: e.eval(decl.value, nil),
+		})
+	}
+	return d
+}
+
+// lowerResource returns the instances of the resource and of its nested resources.
+// Existing resources are not deployed, but their nested resources are.
+func (e *evaluator) lowerResource(decl *resourceDecl, s scope) []azure.Resource {
+	var instances []iteration
+	switch body := decl.body.(type) {
+	case *forExpr:
+		iterations, ok := e.iterate(body, s)
+		if !ok {
+			// The resource is scanned once with the unresolved values of the loop
+			iterations = []iteration{{body: body.body, scope: s.with(body.item, e.unresolved(body.collection, s))}}
+			if cond, ok := body.body.(*ifExpr); ok {
+				iterations[0].body = cond.body
+			}
+		}
+		instances = iterations
+	case *ifExpr:
+		if cond := e.eval(body.cond, s); cond.Kind == azure.KindBoolean && !cond.AsBool() {
+			return nil
+		}
+		instances = []iteration{{body: body.body, scope: s}}
+	default:
+		instances = []iteration{{body: body, scope: s}}
+	}
+
+	var resources []azure.Resource
+	for _, instance := range instances {
+		obj, ok := instance.body.(*objectExpr)
+		if !ok {
+			continue
+		}
+		if !decl.existing {
+			resources = append(resources, e.buildResource(decl, e.eval(obj, instance.scope), instance.scope))
+		}
+		for _, child := range obj.resources {
+			resources = append(resources, e.lowerResource(child, instance.scope)...)
+		}
+	}
+	return resources
+}
+
+func (e *evaluator) buildResource(decl *resourceDecl, body azure.Value, s scope) azure.Resource {
+	meta := types.NewMetadata(types.NewRange(e.path, decl.start, decl.end, "", e.loader.fsys), decl.name)
+	typ, apiVersion := e.fullType(decl, 0)
+
+	properties := body.GetMapValue("properties")
+	if properties.Kind == "" {
+		// Missing properties are reported on the resource
+		properties = azure.NewValue(map[string]azure.Value{}, meta)
+	}
+
+	return azure.Resource{
+		Metadata:   meta,
+		APIVersion: azure.NewValue(apiVersion, meta),
+		Type:       azure.NewValue(typ, meta),
+		Kind:       body.GetMapValue("kind"),
+		Name:       e.fullName(decl, s, 0),
+		Location:   body.GetMapValue("location"),
+		Tags:       body.GetMapValue("tags"),
+		Sku:        body.GetMapValue("sku"),
+		Properties: properties,
+	}
+}
+
+// maxParents limits the depth of the parents of a resource, in case of invalid cyclic parents
+const maxParents = 10
+
+// parent returns the parent of the resource, which is either declared with the "parent" property or nested
+func (e *evaluator) parent(decl *resourceDecl) (*resourceDecl, bool) {
+	if p, ok := e.nestedParents[decl.name]; ok {
+		return p, true
+	}
+	obj := resourceObject(decl.body)
+	if obj == nil {
+		return nil, false
+	}
+	for _, prop := range obj.properties {
+		key, ok := prop.key.(*literal)
+		if !ok || key.value != "parent" {
+			continue
+		}
+		if ref, ok := prop.value.(*identifier); ok {
+			p, ok := e.resourceDecls[ref.name]
+			return p, ok
+		}
+	}
+	return nil, false
+}
+
+// fullType returns the type of the resource, whose type is relative to its parent when it is nested
+func (e *evaluator) fullType(decl *resourceDecl, depth int) (string, string) {
+	typ, apiVersion := splitType(decl.typ)
+	p, ok := e.nestedParents[decl.name]
+	if !ok || depth > maxParents {
+		return typ, apiVersion
+	}
+	parentType, parentVersion := e.fullType(p, depth+1)
+	if !strings.Contains(strings.Split(typ, "/")[0], ".") {
+		typ = parentType + "/" + typ
+	}
+	if apiVersion == "" {
+		apiVersion = parentVersion
+	}
+	return typ, apiVersion
+}
+
+// fullName returns the name of the resource including the names of its parents, e.g. "account/default/container"
+func (e *evaluator) fullName(decl *resourceDecl, s scope, depth int) azure.Value {
+	obj := resourceObject(decl.body)
+	if obj == nil {
+		return e.unresolved(&identifier{span: decl.span, name: decl.name}, s)
+	}
+	name := e.unresolved(&memberExpr{span: decl.span, object: &identifier{span: decl.span, name: decl.name}, name: "name"}, s)
+	for _, prop := range obj.properties {
+		if key, ok := prop.key.(*literal); ok && key.value == "name" {
+			name = e.eval(prop.value, s)
+		}
+	}
+
+	p, ok := e.parent(decl)
+	if !ok || depth > maxParents {
+		return name
+	}
+	parentName := e.fullName(p, s, depth+1)
+	if parentName.Kind == azure.KindString && name.Kind == azure.KindString {
+		return azure.NewValue(parentName.AsString()+"/"+name.AsString(), name.Metadata)
+	}
+	v := azure.NewValue(fmt.Sprintf("format('{0}/{1}', %s, %s)", rawExpression(parentName), rawExpression(name)), name.Metadata)
+	v.Kind = azure.KindExpression
+	return v
+}
+
+// lowerModule returns the resources deployed by the instances of the module
+func (e *evaluator) lowerModule(decl *moduleDecl) []azure.Resource {
+	var instances []iteration
+	switch body := decl.body.(type) {
+	case *forExpr:
+		iterations, ok := e.iterate(body, nil)
+		if !ok {
+			iterations = []iteration{{body: body.body, scope: scope{body.item: e.unresolved(body.collection, nil)}}}
+			if cond, ok := body.body.(*ifExpr); ok {
+				iterations[0].body = cond.body
+			}
+		}
+		instances = iterations
+	case *ifExpr:
+		if cond := e.eval(body.cond, nil); cond.Kind == azure.KindBoolean && !cond.AsBool() {
+			return nil
+		}
+		instances = []iteration{{body: body.body}}
+	default:
+		instances = []iteration{{body: body}}
+	}
+
+	var resources []azure.Resource
+	for _, instance := range instances {
+		if deployment, ok := e.loadModule(decl, e.eval(instance.body, instance.scope)); ok {
+			resources = append(resources, deployment.Resources...)
+		}
+	}
+	return resources
+}
+
+// loadModule lowers the local module with the parameters of the module body
+func (e *evaluator) loadModule(decl *moduleDecl, body azure.Value) (*azure.Deployment, bool) {
+	// Modules from registries and template specs can't be resolved
+	if strings.Contains(decl.path, ":") || path.Ext(decl.path) != ".bicep" {
+		return nil, false
+	}
+	modulePath := path.Join(path.Dir(e.path), decl.path)
+	deployment, err := e.loader.load(modulePath, body.GetMapValue("params").AsMap())
+	if err != nil {
+		e.loader.debug.Log("Failed to load module '%s' of %s: %s", decl.path, e.path, err)
+		return nil, false
+	}
+	return deployment, true
+}
+
+// resourceObject returns the object of the resource body
+func resourceObject(body expr) *objectExpr {
+	switch b := body.(type) {
+	case *objectExpr:
+		return b
+	case *ifExpr:
+		return resourceObject(b.body)
+	case *forExpr:
+		return resourceObject(b.body)
+	}
+	return nil
+}
+
+// splitType splits e.g. "Microsoft.Storage/storageAccounts@2023-01-01"
+func splitType(typ string) (string, string) {
+	t, apiVersion, _ := strings.Cut(typ, "@")
+	return t, apiVersion
+}
+
+// armExpression renders the expression in the ARM template language
+func (e *evaluator) armExpression(x expr, s scope) string {
+	render := func(x expr) string {
+		return e.armExpression(x, s)
+	}
+	switch x := x.(type) {
+	case *literal:
+		switch v := x.value.(type) {
+		case string:
+			return quote(v)
+		case bool:
+			return fmt.Sprintf("%t()", v)
+		case nil:
+			return "null()"
+		default:
+			return fmt.Sprint(v)
+		}
+	case *interpolation:
+		var format strings.Builder
+		var args []string
+		for _, part := range x.parts {
+			switch part := part.(type) {
+			case string:
+				format.WriteString(part)
+			case expr:
+				fmt.Fprintf(&format, "{%d}", len(args))
+				args = append(args, render(part))
+			}
+		}
+		return fmt.Sprintf("format(%s)", strings.Join(append([]string{quote(format.String())}, args...), ", "))
+	case *identifier:
+		switch {
+		case hasKey(s, x.name):
+			return rawExpression(s[x.name])
+		case hasKey(e.paramDecls, x.name):
+			return fmt.Sprintf("parameters('%s')", x.name)
+		case hasKey(e.varDecls, x.name):
+			return fmt.Sprintf("variables('%s')", x.name)
+		case hasKey(e.resourceDecls, x.name):
+			typ, apiVersion := splitType(e.resourceDecls[x.name].typ)
+			return fmt.Sprintf("reference(resourceId('%s', '%s'), '%s', 'full')", typ, x.name, apiVersion)
+		case hasKey(e.moduleDecls, x.name):
+			return fmt.Sprintf("reference(resourceId('Microsoft.Resources/deployments', '%s'), '2022-09-01')", x.name)
+		}
+		return x.name
+	case *arrayExpr:
+		var items []string
+		for _, item := range x.items {
+			items = append(items, render(item))
+		}
+		return fmt.Sprintf("createArray(%s)", strings.Join(items, ", "))
+	case *objectExpr:
+		var items []string
+		for _, prop := range x.properties {
+			items = append(items, render(prop.key), render(prop.value))
+		}
+		return fmt.Sprintf("createObject(%s)", strings.Join(items, ", "))
+	case *memberExpr:
+		return fmt.Sprintf("%s.%s", render(x.object), x.name)
+	case *indexExpr:
+		return fmt.Sprintf("%s[%s]", render(x.object), render(x.index))
+	case *callExpr:
+		var args []string
+		for _, arg := range x.args {
+			args = append(args, render(arg))
+		}
+		call := fmt.Sprintf("%s(%s)", x.name, strings.Join(args, ", "))
+		if ns, ok := x.receiver.(*identifier); x.receiver != nil && (!ok || (ns.name != "az" && ns.name != "sys")) {
+			return render(x.receiver) + "." + call
+		}
+		return call
+	case *unaryExpr:
+		if x.op == "!" {
+			return fmt.Sprintf("not(%s)", render(x.x))
+		}
+		return fmt.Sprintf("sub(0, %s)", render(x.x))
+	case *binaryExpr:
+		l, r := render(x.left), render(x.right)
+		switch x.op {
+		case "!=":
+			return fmt.Sprintf("not(equals(%s, %s))", l, r)
+		case "=~":
+			return fmt.Sprintf("equals(toLower(%s), toLower(%s))", l, r)
+		case "!~":
+			return fmt.Sprintf("not(equals(toLower(%s), toLower(%s)))", l, r)
+		}
+		return fmt.Sprintf("%s(%s, %s)", binaryFunctions[x.op], l, r)
+	case *ternaryExpr:
+		return fmt.Sprintf("if(%s, %s, %s)", render(x.cond), render(x.then), render(x.otherwise))
+	}
+	return "null()"
+}
+
+var binaryFunctions = map[string]string{
+	"??": "coalesce",
+	"||": "or",
+	"&&": "and",
+	"==": "equals",
+	"<":  "less",
+	"<=": "lessOrEquals",
+	">":  "greater",
+	">=": "greaterOrEquals",
+	"+":  "add",
+	"-":  "sub",
+	"*":  "mul",
+	"/":  "div",
+	"%":  "mod",
+}
+
+// rawExpression renders the value in the ARM template language
+func rawExpression(v azure.Value) string {
+	switch v.Kind {
+	case azure.KindExpression:
+		return fmt.Sprint(v.Raw())
+	case azure.KindString:
+		return quote(v.AsString())
+	case azure.KindBoolean:
+		return fmt.Sprintf("%t()", v.AsBool())
+	case azure.KindNumber:
+		return fmt.Sprint(v.Raw())
+	}
+	return "null()"
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// loader loads the Bicep files and their modules
+type loader struct {
+	fsys  fs.FS
+	debug debug.Logger
+	// modules holds the files used as modules by other files
+	modules map[string]struct{}
+	// stack holds the files being loaded, to detect cyclic modules
+	stack []string
+}
+
+func (l *loader) load(filePath string, params map[string]azure.Value) (*azure.Deployment, error) {
+	for _, p := range l.stack {
+		if p == filePath {
+			return nil, fmt.Errorf("cyclic module %s", filePath)
+		}
+	}
+	if len(l.stack) > 0 {
+		l.modules[filePath] = struct{}{}
+	}
+	l.stack = append(l.stack, filePath)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	content, err := fs.ReadFile(l.fsys, filePath)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return newEvaluator(l, filePath, f, params).lower(), nil
+}
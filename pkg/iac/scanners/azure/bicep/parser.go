@@ -0,0 +1,762 @@
+package bicep
+
+import (
+	"fmt"
+	"strconv"
+
+	"golang.org/x/exp/slices"
+)
+
+// binaryOperators are sorted by increasing precedence
+var binaryOperators = [][]string{
+	{"??"},
+	{"||"},
+	{"&&"},
+	{"==", "!=", "=~", "!~"},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse parses the declarations of a Bicep file.
+// Declarations which don't affect the deployed resources, such as user-defined types and functions, are skipped.
+func parse(src string) (*file, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	return p.parseFile()
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// lastLine returns the last line of the previous token
+func (p *parser) lastLine() int {
+	if p.pos == 0 {
+		return 1
+	}
+	t := p.tokens[p.pos-1]
+	return max(t.line, t.endLine)
+}
+
+func (p *parser) skipNewlines() {
+	for p.peek().kind == tokenNewline {
+		p.next()
+	}
+}
+
+func (p *parser) expect(kind tokenKind, value string) (token, error) {
+	t := p.next()
+	if t.kind != kind || (value != "" && t.value != value) {
+		expected := value
+		if expected == "" {
+			expected = token{kind: kind}.String()
+		}
+		return t, fmt.Errorf("expected %s but found %s at line %d", expected, t, t.line)
+	}
+	return t, nil
+}
+
+func (p *parser) expectPunct(value string) error {
+	_, err := p.expect(tokenPunct, value)
+	return err
+}
+
+func (p *parser) parseFile() (*file, error) {
+	f := &file{}
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		if t.kind == tokenEOF {
+			return f, nil
+		}
+
+		decorators, err := p.parseDecorators()
+		if err != nil {
+			return nil, err
+		}
+		t = p.peek()
+		if t.kind != tokenIdent {
+			return nil, fmt.Errorf("unexpected %s at line %d", t, t.line)
+		}
+
+		switch t.value {
+		case "targetScope":
+			p.next()
+			if err := p.expectPunct("="); err != nil {
+				return nil, err
+			}
+			value, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if l, ok := value.(*literal); ok {
+				f.targetScope, _ = l.value.(string)
+			}
+		case "param":
+			param, err := p.parseParam()
+			if err != nil {
+				return nil, err
+			}
+			param.decorators = decorators
+			f.params = append(f.params, param)
+		case "var":
+			v, err := p.parseVar()
+			if err != nil {
+				return nil, err
+			}
+			f.vars = append(f.vars, v)
+		case "resource":
+			resource, err := p.parseResource()
+			if err != nil {
+				return nil, err
+			}
+			f.resources = append(f.resources, resource)
+		case "module":
+			module, err := p.parseModule()
+			if err != nil {
+				return nil, err
+			}
+			f.modules = append(f.modules, module)
+		case "output":
+			output, err := p.parseOutput()
+			if err != nil {
+				return nil, err
+			}
+			f.outputs = append(f.outputs, output)
+		default:
+			// e.g. metadata, type, func, import, extension
+			p.skipStatement()
+		}
+
+		if t := p.peek(); t.kind != tokenNewline && t.kind != tokenEOF {
+			return nil, fmt.Errorf("unexpected %s at line %d", t, t.line)
+		}
+	}
+}
+
+// skipStatement skips the tokens until the end of the statement
+func (p *parser) skipStatement() {
+	depth := 0
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokenEOF:
+			return
+		case t.kind == tokenNewline && depth == 0:
+			return
+		case t.kind == tokenPunct && slices.Contains([]string{"(", "[", "{"}, t.value):
+			depth++
+		case t.kind == tokenPunct && slices.Contains([]string{")", "]", "}"}, t.value):
+			depth--
+		}
+		p.next()
+	}
+}
+
+// skipType skips a type, e.g. "string[]" or "'a' | 'b'", and returns its first token
+func (p *parser) skipType() string {
+	first := p.peek().value
+	depth := 0
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokenEOF:
+			return first
+		case (t.kind == tokenNewline || t.is(tokenPunct, "=")) && depth == 0:
+			return first
+		case t.kind == tokenPunct && slices.Contains([]string{"(", "[", "{"}, t.value):
+			depth++
+		case t.kind == tokenPunct && slices.Contains([]string{")", "]", "}"}, t.value):
+			depth--
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseDecorators() ([]decorator, error) {
+	var decorators []decorator
+	for p.peek().is(tokenPunct, "@") {
+		p.next()
+		name, err := p.expect(tokenIdent, "")
+		if err != nil {
+			return nil, err
+		}
+		// e.g. @sys.description()
+		if p.peek().is(tokenPunct, ".") {
+			p.next()
+			if name, err = p.expect(tokenIdent, ""); err != nil {
+				return nil, err
+			}
+		}
+		d := decorator{name: name.value}
+		if p.peek().is(tokenPunct, "(") {
+			p.next()
+			if d.args, err = p.parseArgs(); err != nil {
+				return nil, err
+			}
+		}
+		decorators = append(decorators, d)
+		p.skipNewlines()
+	}
+	return decorators, nil
+}
+
+func (p *parser) parseParam() (*paramDecl, error) {
+	start := p.next().line
+	name, err := p.expect(tokenIdent, "")
+	if err != nil {
+		return nil, err
+	}
+	param := &paramDecl{name: name.value, typ: p.skipType()}
+	if p.peek().is(tokenPunct, "=") {
+		p.next()
+		if param.value, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	param.span = span{start, p.lastLine()}
+	return param, nil
+}
+
+func (p *parser) parseVar() (*varDecl, error) {
+	start := p.next().line
+	name, err := p.expect(tokenIdent, "")
+	if err != nil {
+		return nil, err
+	}
+	// Variables may be typed
+	p.skipType()
+	if err = p.expectPunct("="); err != nil {
+		return nil, err
+	}
+	value, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &varDecl{span: span{start, p.lastLine()}, name: name.value, value: value}, nil
+}
+
+func (p *parser) parseResource() (*resourceDecl, error) {
+	start := p.next().line
+	name, err := p.expect(tokenIdent, "")
+	if err != nil {
+		return nil, err
+	}
+	typ, err := p.parseStringLiteral()
+	if err != nil {
+		return nil, err
+	}
+	resource := &resourceDecl{name: name.value, typ: typ}
+	if p.peek().is(tokenIdent, "existing") {
+		p.next()
+		resource.existing = true
+	}
+	if err = p.expectPunct("="); err != nil {
+		return nil, err
+	}
+	if resource.body, err = p.parseBody(); err != nil {
+		return nil, err
+	}
+	resource.span = span{start, p.lastLine()}
+	return resource, nil
+}
+
+func (p *parser) parseModule() (*moduleDecl, error) {
+	start := p.next().line
+	name, err := p.expect(tokenIdent, "")
+	if err != nil {
+		return nil, err
+	}
+	path, err := p.parseStringLiteral()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expectPunct("="); err != nil {
+		return nil, err
+	}
+	body, err := p.parseBody()
+	if err != nil {
+		return nil, err
+	}
+	return &moduleDecl{span: span{start, p.lastLine()}, name: name.value, path: path, body: body}, nil
+}
+
+func (p *parser) parseOutput() (*outputDecl, error) {
+	start := p.next().line
+	name, err := p.expect(tokenIdent, "")
+	if err != nil {
+		return nil, err
+	}
+	p.skipType()
+	if err = p.expectPunct("="); err != nil {
+		return nil, err
+	}
+	value, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &outputDecl{span: span{start, p.lastLine()}, name: name.value, value: value}, nil
+}
+
+func (p *parser) parseStringLiteral() (string, error) {
+	t, err := p.expect(tokenString, "")
+	if err != nil {
+		return "", err
+	}
+	if len(t.parts) != 1 {
+		return "", fmt.Errorf("string interpolation is not allowed at line %d", t.line)
+	}
+	s, ok := t.parts[0].(string)
+	if !ok {
+		return "", fmt.Errorf("string interpolation is not allowed at line %d", t.line)
+	}
+	return s, nil
+}
+
+// parseBody parses the body of a resource or a module, which may be conditional or a loop
+func (p *parser) parseBody() (expr, error) {
+	if p.peek().is(tokenIdent, "if") {
+		return p.parseIf()
+	}
+	return p.parseExpr()
+}
+
+func (p *parser) parseIf() (expr, error) {
+	start := p.next().line
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	p.skipNewlines()
+	cond, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipNewlines()
+	if err = p.expectPunct(")"); err != nil {
+		return nil, err
+	}
+	body, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &ifExpr{span: span{start, p.lastLine()}, cond: cond, body: body}, nil
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	start := p.peek().line
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.peek().is(tokenPunct, "?") {
+		return cond, nil
+	}
+	p.next()
+	p.skipNewlines()
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipNewlines()
+	if err = p.expectPunct(":"); err != nil {
+		return nil, err
+	}
+	p.skipNewlines()
+	otherwise, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &ternaryExpr{span: span{start, p.lastLine()}, cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+func (p *parser) parseBinary(level int) (expr, error) {
+	if level == len(binaryOperators) {
+		return p.parseUnary()
+	}
+	start := p.peek().line
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenPunct || !slices.Contains(binaryOperators[level], t.value) {
+			return left, nil
+		}
+		p.next()
+		p.skipNewlines()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{span: span{start, p.lastLine()}, op: t.value, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	t := p.peek()
+	if t.is(tokenPunct, "!") || t.is(tokenPunct, "-") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{span: span{t.line, p.lastLine()}, op: t.value, x: x}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (expr, error) {
+	start := p.peek().line
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch {
+		case t.is(tokenPunct, ".") || t.is(tokenPunct, "?."):
+			p.next()
+			name, err := p.expect(tokenIdent, "")
+			if err != nil {
+				return nil, err
+			}
+			if p.peek().is(tokenPunct, "(") {
+				p.next()
+				args, err := p.parseArgs()
+				if err != nil {
+					return nil, err
+				}
+				x = &callExpr{span: span{start, p.lastLine()}, receiver: x, name: name.value, args: args}
+				continue
+			}
+			x = &memberExpr{span: span{start, p.lastLine()}, object: x, name: name.value}
+		case t.is(tokenPunct, "::"):
+			// nested resource accessor, e.g. "storage::blobService", whose symbols share the same namespace here
+			p.next()
+			name, err := p.expect(tokenIdent, "")
+			if err != nil {
+				return nil, err
+			}
+			x = &identifier{span: span{start, p.lastLine()}, name: name.value}
+		case t.is(tokenPunct, "[") || t.is(tokenPunct, "?["):
+			p.next()
+			p.skipNewlines()
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			p.skipNewlines()
+			if err = p.expectPunct("]"); err != nil {
+				return nil, err
+			}
+			x = &indexExpr{span: span{start, p.lastLine()}, object: x, index: index}
+		case t.is(tokenPunct, "!") && !p.peekAt(1).is(tokenPunct, "="):
+			// non-null assertion
+			p.next()
+		default:
+			return x, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+		if i, err := strconv.ParseInt(t.value, 10, 64); err == nil {
+			return &literal{span: span{t.line, t.line}, value: i}, nil
+		}
+		return nil, fmt.Errorf("invalid number %s at line %d", t.value, t.line)
+	case tokenString:
+		p.next()
+		return p.parseString(t)
+	case tokenIdent:
+		return p.parseIdentifier()
+	case tokenPunct:
+		switch t.value {
+		case "(":
+			if p.isLambda() {
+				return p.parseLambda()
+			}
+			p.next()
+			p.skipNewlines()
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			p.skipNewlines()
+			if err = p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			return p.parseArray()
+		case "{":
+			return p.parseObject()
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at line %d", t, t.line)
+}
+
+func (p *parser) parseString(t token) (expr, error) {
+	s := span{t.line, max(t.line, t.endLine)}
+	if len(t.parts) == 1 {
+		if str, ok := t.parts[0].(string); ok {
+			return &literal{span: s, value: str}, nil
+		}
+	}
+	interp := &interpolation{span: s}
+	for _, part := range t.parts {
+		switch part := part.(type) {
+		case string:
+			interp.parts = append(interp.parts, part)
+		case []token:
+			sub := &parser{tokens: part}
+			sub.skipNewlines()
+			x, err := sub.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			sub.skipNewlines()
+			if t := sub.peek(); t.kind != tokenEOF {
+				return nil, fmt.Errorf("unexpected %s at line %d", t, t.line)
+			}
+			interp.parts = append(interp.parts, x)
+		}
+	}
+	return interp, nil
+}
+
+func (p *parser) parseIdentifier() (expr, error) {
+	t := p.next()
+	s := span{t.line, t.line}
+	switch t.value {
+	case "true", "false":
+		return &literal{span: s, value: t.value == "true"}, nil
+	case "null":
+		return &literal{span: s}, nil
+	}
+	switch {
+	case p.peek().is(tokenPunct, "=>"):
+		p.pos--
+		return p.parseLambda()
+	case p.peek().is(tokenPunct, "("):
+		p.next()
+		args, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+		return &callExpr{span: span{t.line, p.lastLine()}, name: t.value, args: args}, nil
+	}
+	return &identifier{span: s, name: t.value}, nil
+}
+
+// isLambda checks if the parenthesis starts the parameters of a lambda, e.g. "(a, b) => a + b"
+func (p *parser) isLambda() bool {
+	for i := 1; ; i++ {
+		t := p.peekAt(i)
+		switch {
+		case t.kind == tokenIdent, t.kind == tokenNewline, t.is(tokenPunct, ","):
+		case t.is(tokenPunct, ")"):
+			return p.peekAt(i+1).is(tokenPunct, "=>")
+		default:
+			return false
+		}
+	}
+}
+
+// parseLambda parses a lambda, which can't be evaluated statically
+func (p *parser) parseLambda() (expr, error) {
+	start := p.peek().line
+	for !p.peek().is(tokenPunct, "=>") {
+		if p.peek().kind == tokenEOF {
+			return nil, fmt.Errorf("unterminated lambda at line %d", start)
+		}
+		p.next()
+	}
+	p.next()
+	p.skipNewlines()
+	if _, err := p.parseExpr(); err != nil {
+		return nil, err
+	}
+	return &lambdaExpr{span: span{start, p.lastLine()}}, nil
+}
+
+// parseArgs parses the arguments of a function after the opening parenthesis
+func (p *parser) parseArgs() ([]expr, error) {
+	var args []expr
+	for {
+		p.skipNewlines()
+		if p.peek().is(tokenPunct, ")") {
+			p.next()
+			return args, nil
+		}
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		p.skipNewlines()
+		if t := p.peek(); t.is(tokenPunct, ",") {
+			p.next()
+		} else if !t.is(tokenPunct, ")") {
+			return nil, fmt.Errorf("unexpected %s at line %d", t, t.line)
+		}
+	}
+}
+
+func (p *parser) parseArray() (expr, error) {
+	start := p.next().line
+	p.skipNewlines()
+	if p.peek().is(tokenIdent, "for") {
+		return p.parseFor(start)
+	}
+	arr := &arrayExpr{}
+	for {
+		p.skipNewlines()
+		if p.peek().is(tokenPunct, "]") {
+			p.next()
+			arr.span = span{start, p.lastLine()}
+			return arr, nil
+		}
+		item, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		arr.items = append(arr.items, item)
+		if p.peek().is(tokenPunct, ",") {
+			p.next()
+		}
+	}
+}
+
+// parseFor parses a loop after the opening bracket
+func (p *parser) parseFor(start int) (expr, error) {
+	p.next()
+	loop := &forExpr{}
+	if p.peek().is(tokenPunct, "(") {
+		p.next()
+		item, err := p.expect(tokenIdent, "")
+		if err != nil {
+			return nil, err
+		}
+		loop.item = item.value
+		if p.peek().is(tokenPunct, ",") {
+			p.next()
+			index, err := p.expect(tokenIdent, "")
+			if err != nil {
+				return nil, err
+			}
+			loop.index = index.value
+		}
+		if err = p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+	} else {
+		item, err := p.expect(tokenIdent, "")
+		if err != nil {
+			return nil, err
+		}
+		loop.item = item.value
+	}
+	if _, err := p.expect(tokenIdent, "in"); err != nil {
+		return nil, err
+	}
+	collection, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	loop.collection = collection
+	if err = p.expectPunct(":"); err != nil {
+		return nil, err
+	}
+	p.skipNewlines()
+	if loop.body, err = p.parseBody(); err != nil {
+		return nil, err
+	}
+	p.skipNewlines()
+	if err = p.expectPunct("]"); err != nil {
+		return nil, err
+	}
+	loop.span = span{start, p.lastLine()}
+	return loop, nil
+}
+
+func (p *parser) parseObject() (expr, error) {
+	start := p.next().line
+	obj := &objectExpr{}
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		switch {
+		case t.is(tokenPunct, "}"):
+			p.next()
+			obj.span = span{start, p.lastLine()}
+			return obj, nil
+		case t.is(tokenPunct, ","):
+			p.next()
+			continue
+		case t.is(tokenPunct, "@"), t.is(tokenIdent, "resource") && p.peekAt(1).kind == tokenIdent:
+			// nested resource
+			if _, err := p.parseDecorators(); err != nil {
+				return nil, err
+			}
+			resource, err := p.parseResource()
+			if err != nil {
+				return nil, err
+			}
+			obj.resources = append(obj.resources, resource)
+			continue
+		}
+
+		var key expr
+		switch t.kind {
+		case tokenIdent:
+			p.next()
+			key = &literal{span: span{t.line, t.line}, value: t.value}
+		case tokenString:
+			p.next()
+			var err error
+			if key, err = p.parseString(t); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %s at line %d", t, t.line)
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		p.skipNewlines()
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		obj.properties = append(obj.properties, property{span: span{t.line, p.lastLine()}, key: key, value: value})
+	}
+}
@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"encoding/json"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	iacTypes "github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+// CDK apps synthesize their stacks into a cloud assembly (cdk.out), whose manifest records
+// the construct path of each resource and, in debug mode, the stack trace of its creation.
+
+const (
+	cdkManifestFile     = "manifest.json"
+	cdkStackArtifact    = "aws:cloudformation:stack"
+	cdkLogicalIDEntry   = "aws:cdk:logicalId"
+	cdkNestedAssemblies = "assembly-"
+)
+
+// stackFrameRegexp matches the location at the end of a stack frame,
+// e.g. "new AppStack (/home/user/app/lib/app-stack.ts:12:5)"
+var stackFrameRegexp = regexp.MustCompile(`(?:^|\()((?:[A-Za-z]:)?[^():]+):(\d+):\d+\)?$`)
+
+type cdkManifest struct {
+	Version   string                 `json:"version"`
+	Artifacts map[string]cdkArtifact `json:"artifacts"`
+}
+
+type cdkArtifact struct {
+	Type       string `json:"type"`
+	Properties struct {
+		TemplateFile string `json:"templateFile"`
+	} `json:"properties"`
+	Metadata map[string][]cdkMetadataEntry `json:"metadata"`
+}
+
+type cdkMetadataEntry struct {
+	Type  string   `json:"type"`
+	Data  any      `json:"data"`
+	Trace []string `json:"trace"`
+}
+
+// IsCDKManifest returns true if the file is the manifest of a CDK cloud assembly
+func IsCDKManifest(filePath string, r io.Reader) bool {
+	if path.Base(filePath) != cdkManifestFile {
+		return false
+	}
+	var manifest cdkManifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return false
+	}
+	return manifest.Version != "" && manifest.Artifacts != nil
+}
+
+// loadCDKConstructs returns the metadata of the CDK constructs which created the resources of the template,
+// keyed by logical ID. Only the constructs whose source location is known are returned.
+func loadCDKConstructs(fsys fs.FS, templatePath string) map[string]iacTypes.Metadata {
+	dir := path.Dir(templatePath)
+	content, err := fs.ReadFile(fsys, path.Join(dir, cdkManifestFile))
+	if err != nil {
+		return nil
+	}
+	var manifest cdkManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil
+	}
+
+	// The app is usually the parent directory of the cloud assembly
+	appDir := path.Join(dir, "..")
+	if strings.HasPrefix(path.Base(dir), cdkNestedAssemblies) {
+		appDir = path.Join(dir, "..", "..")
+	}
+
+	constructs := make(map[string]iacTypes.Metadata)
+	for _, artifact := range manifest.Artifacts {
+		if artifact.Type != cdkStackArtifact || artifact.Properties.TemplateFile != path.Base(templatePath) {
+			continue
+		}
+		for constructPath, entries := range artifact.Metadata {
+			for _, entry := range entries {
+				logicalID, ok := entry.Data.(string)
+				if entry.Type != cdkLogicalIDEntry || !ok {
+					continue
+				}
+				filename, line, ok := sourceLocation(fsys, appDir, entry.Trace)
+				if !ok {
+					continue
+				}
+				rng := iacTypes.NewRange(filename, line, line, "", fsys)
+				constructs[logicalID] = iacTypes.NewMetadata(rng, constructName(constructPath))
+			}
+		}
+	}
+	return constructs
+}
+
+// constructName returns the path of the construct written by the user,
+// e.g. "AppStack/Bucket" for the "AppStack/Bucket/Resource" L1 construct.
+func constructName(constructPath string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(constructPath, "/"), "/Resource")
+}
+
+// sourceLocation returns the first location of the stack trace in the app, skipping the CDK libraries
+func sourceLocation(fsys fs.FS, appDir string, trace []string) (string, int, bool) {
+	var appRoot string
+	for _, frame := range trace {
+		m := stackFrameRegexp.FindStringSubmatch(strings.TrimSpace(frame))
+		if m == nil {
+			continue
+		}
+		filename := strings.ReplaceAll(m[1], `\`, "/")
+		// The libraries are installed in the app, which tells where the app is on the machine running the synthesis
+		if root, _, found := strings.Cut(filename, "/node_modules/"); found {
+			if appRoot == "" {
+				appRoot = root
+			}
+			continue
+		}
+		line, err := strconv.Atoi(m[2])
+		if err != nil {
+			continue
+		}
+		return resolveSourcePath(fsys, appDir, appRoot, filename), line, true
+	}
+	return "", 0, false
+}
+
+// resolveSourcePath converts the absolute path of a source file on the machine running the synthesis
+// into a path in the scanned filesystem when possible.
+func resolveSourcePath(fsys fs.FS, appDir, appRoot, filename string) string {
+	if appRoot != "" {
+		if rel, ok := strings.CutPrefix(filename, appRoot+"/"); ok {
+			return path.Join(appDir, rel)
+		}
+	}
+	// Look for the longest suffix of the path which exists in the filesystem
+	parts := strings.Split(strings.TrimPrefix(filename, "/"), "/")
+	for i := range parts {
+		candidate := path.Join(parts[i:]...)
+		if _, err := fs.Stat(fsys, candidate); err == nil {
+			return candidate
+		}
+	}
+	return filename
+}
+
+// withConstruct sets the CDK construct which created the resource as the parent of the metadata
+func (t *FileContext) withConstruct(m iacTypes.Metadata, logicalID string) iacTypes.Metadata {
+	if t == nil {
+		return m
+	}
+	if construct, ok := t.constructs[logicalID]; ok {
+		return m.WithParent(construct)
+	}
+	return m
+}
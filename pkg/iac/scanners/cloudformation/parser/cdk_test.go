@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCDKTemplate = `{
+  "Resources": {
+    "LogsBucket9C4D8843": {
+      "Type": "AWS::S3::Bucket",
+      "Properties": {
+        "VersioningConfiguration": {
+          "Status": "Suspended"
+        }
+      },
+      "Metadata": {
+        "aws:cdk:path": "AppStack/LogsBucket/Resource"
+      }
+    },
+    "CDKMetadata": {
+      "Type": "AWS::CDK::Metadata",
+      "Properties": {
+        "Analytics": "v2:deflate64:H4sIAAAAAAAA"
+      }
+    }
+  }
+}`
+
+const testCDKManifest = `{
+  "version": "36.0.0",
+  "artifacts": {
+    "AppStack.assets": {
+      "type": "cdk:asset-manifest",
+      "properties": {
+        "file": "AppStack.assets.json"
+      }
+    },
+    "AppStack": {
+      "type": "aws:cloudformation:stack",
+      "environment": "aws://unknown-account/unknown-region",
+      "properties": {
+        "templateFile": "AppStack.template.json"
+      },
+      "metadata": {
+        "/AppStack/LogsBucket/Resource": [
+          {
+            "type": "aws:cdk:logicalId",
+            "data": "LogsBucket9C4D8843",
+            "trace": [
+              "new Bucket (/home/user/app/node_modules/aws-cdk-lib/aws-s3/lib/bucket.js:1:2)",
+              "new AppStack (/home/user/app/lib/app-stack.ts:12:24)",
+              "Object.<anonymous> (/home/user/app/bin/app.ts:7:1)",
+              "Module._compile (node:internal/modules/cjs/loader:1256:14)"
+            ]
+          }
+        ],
+        "/AppStack/CDKMetadata/Default": [
+          {
+            "type": "aws:cdk:logicalId",
+            "data": "CDKMetadata"
+          }
+        ]
+      },
+      "displayName": "AppStack"
+    }
+  }
+}`
+
+func TestParse_CDKConstructs(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		templatePath string
+		wantFile     string
+		wantLine     int
+	}{
+		{
+			name: "app root from the libraries",
+			files: fstest.MapFS{
+				"app/cdk.out/manifest.json":          {Data: []byte(testCDKManifest)},
+				"app/cdk.out/AppStack.template.json": {Data: []byte(testCDKTemplate)},
+			},
+			templatePath: "app/cdk.out/AppStack.template.json",
+			wantFile:     "app/lib/app-stack.ts",
+			wantLine:     12,
+		},
+		{
+			name: "nested assembly",
+			files: fstest.MapFS{
+				"cdk.out/assembly-Prod/manifest.json":          {Data: []byte(testCDKManifest)},
+				"cdk.out/assembly-Prod/AppStack.template.json": {Data: []byte(testCDKTemplate)},
+			},
+			templatePath: "cdk.out/assembly-Prod/AppStack.template.json",
+			wantFile:     "lib/app-stack.ts",
+			wantLine:     12,
+		},
+		{
+			name: "globally installed libraries",
+			files: fstest.MapFS{
+				"cdk.out/manifest.json": {Data: []byte(strings.ReplaceAll(testCDKManifest,
+					"/home/user/app/node_modules/aws-cdk-lib", "/usr/local/lib/node_modules/aws-cdk-lib"))},
+				"cdk.out/AppStack.template.json": {Data: []byte(testCDKTemplate)},
+				"lib/app-stack.ts":               {Data: []byte("export class AppStack {}")},
+			},
+			templatePath: "cdk.out/AppStack.template.json",
+			wantFile:     "lib/app-stack.ts",
+			wantLine:     12,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fctx, err := New().ParseFile(context.TODO(), tt.files, tt.templatePath)
+			require.NoError(t, err)
+
+			bucket := fctx.GetResourceByLogicalID("LogsBucket9C4D8843")
+			require.NotNil(t, bucket)
+
+			parent := bucket.Metadata().Parent()
+			require.NotNil(t, parent)
+			assert.Equal(t, "AppStack/LogsBucket", parent.Reference())
+			assert.Equal(t, tt.wantFile, parent.Range().GetFilename())
+			assert.Equal(t, tt.wantLine, parent.Range().GetStartLine())
+
+			// Properties are mapped to the construct as well
+			status := bucket.GetProperty("VersioningConfiguration.Status")
+			require.NotNil(t, status.Metadata().Parent())
+			assert.Equal(t, "AppStack/LogsBucket", status.Metadata().Parent().Reference())
+
+			// There is no stack trace for the metadata of the stack
+			assert.Nil(t, fctx.GetResourceByLogicalID("CDKMetadata").Metadata().Parent())
+		})
+	}
+}
+
+func TestParse_WithoutCDKManifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"AppStack.template.json": {Data: []byte(testCDKTemplate)},
+	}
+	fctx, err := New().ParseFile(context.TODO(), fsys, "AppStack.template.json")
+	require.NoError(t, err)
+	assert.Nil(t, fctx.GetResourceByLogicalID("LogsBucket9C4D8843").Metadata().Parent())
+}
+
+func TestIsCDKManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		content  string
+		want     bool
+	}{
+		{
+			name:     "cloud assembly manifest",
+			filePath: "cdk.out/manifest.json",
+			content:  testCDKManifest,
+			want:     true,
+		},
+		{
+			name:     "web app manifest",
+			filePath: "public/manifest.json",
+			content:  `{"name": "app", "icons": []}`,
+			want:     false,
+		},
+		{
+			name:     "another file name",
+			filePath: "cdk.out/tree.json",
+			content:  testCDKManifest,
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsCDKManifest(tt.filePath, strings.NewReader(tt.content)))
+		})
+	}
+}
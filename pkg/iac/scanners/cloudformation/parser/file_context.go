@@ -22,6 +22,9 @@ type FileContext struct {
 	Globals      map[string]*Resource   `json:"Globals" yaml:"Globals"`
 	Mappings     map[string]interface{} `json:"Mappings,omitempty" yaml:"Mappings"`
 	Conditions   map[string]Property    `json:"Conditions,omitempty" yaml:"Conditions"`
+
+	// constructs are the CDK constructs which created the resources, keyed by logical ID
+	constructs map[string]iacTypes.Metadata
 }
 
 func (t *FileContext) GetResourceByLogicalID(name string) *Resource {
@@ -180,6 +180,7 @@ func (p *Parser) ParseFile(ctx context.Context, fsys fs.FS, path string) (fctx *
 	fctx.lines = lines
 	fctx.SourceFormat = sourceFmt
 	fctx.filepath = path
+	fctx.constructs = loadCDKConstructs(fsys, path)
 
 	p.debug.Log("Context loaded from source %s", path)
 
@@ -120,12 +120,12 @@ func (p *Property) Metadata() iacTypes.Metadata {
 		}
 	}
 	ref := NewCFReferenceWithValue(p.parentRange, *base, p.logicalId)
-	return iacTypes.NewMetadata(p.Range(), ref.String())
+	return p.ctx.withConstruct(iacTypes.NewMetadata(p.Range(), ref.String()), p.logicalId)
 }
 
 func (p *Property) MetadataWithValue(resolvedValue *Property) iacTypes.Metadata {
 	ref := NewCFReferenceWithValue(p.parentRange, *resolvedValue, p.logicalId)
-	return iacTypes.NewMetadata(p.Range(), ref.String())
+	return p.ctx.withConstruct(iacTypes.NewMetadata(p.Range(), ref.String()), p.logicalId)
 }
 
 func (p *Property) isFunction() bool {
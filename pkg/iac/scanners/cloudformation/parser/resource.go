@@ -82,7 +82,8 @@ func (r *Resource) SourceFormat() SourceFormat {
 }
 
 func (r *Resource) Metadata() iacTypes.Metadata {
-	return iacTypes.NewMetadata(r.Range(), NewCFReference(r.id, r.rng).String())
+	m := iacTypes.NewMetadata(r.Range(), NewCFReference(r.id, r.rng).String())
+	return r.ctx.withConstruct(m, r.id)
 }
 
 func (r *Resource) properties() map[string]*Property {
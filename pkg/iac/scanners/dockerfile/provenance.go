@@ -0,0 +1,458 @@
+package dockerfile
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/trivy/pkg/iac/providers/dockerfile"
+	"github.com/aquasecurity/trivy/pkg/iac/scan"
+	"github.com/aquasecurity/trivy/pkg/iac/severity"
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+const provenanceNamespace = "dockerfile.provenance"
+
+var (
+	secretCopyRule = scan.Rule{
+		AVDID:       "DSP001",
+		ShortCode:   "no-secret-from-build-stage",
+		Summary:     "Secret copied from a build stage into the final image",
+		Explanation: "A secret added to a build stage ends up in the final image when it is copied by 'COPY --from', directly or through other stages, and can be extracted from the image layers.",
+		Resolution:  "Use build secrets ('RUN --mount=type=secret') or copy only the build outputs from the build stage",
+		Provider:    "dockerfile",
+		Service:     "general",
+		Severity:    severity.High,
+	}
+	cacheCopyRule = scan.Rule{
+		AVDID:       "DSP002",
+		ShortCode:   "no-package-cache-from-build-stage",
+		Summary:     "Package cache copied from a build stage into the final image",
+		Explanation: "A package manager cache left in a build stage ends up in the final image when it is copied by 'COPY --from', which bloats the image and may expose private packages or registry credentials.",
+		Resolution:  "Clean the package cache or use a cache mount ('RUN --mount=type=cache') in the build stage, or copy only the build outputs",
+		Provider:    "dockerfile",
+		Service:     "general",
+		Severity:    severity.Medium,
+	}
+)
+
+var (
+	// secretFileNames are the names of files holding credentials
+	secretFileNames = []string{
+		".env", ".npmrc", ".pypirc", ".netrc", ".git-credentials", ".dockercfg", ".htpasswd",
+		"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519", "credentials.json", "service-account.json",
+	}
+	// secretFilePaths are the paths, relative to a home directory, of files holding credentials
+	secretFilePaths = []string{
+		".aws/credentials", ".docker/config.json", ".kube/config", ".config/gcloud/credentials.db",
+	}
+	// secretDirNames are the names of directories holding credentials
+	secretDirNames = []string{".ssh", ".aws", ".docker", ".gnupg", ".kube"}
+	// secretFileExtensions are the extensions of private keys and keystores
+	secretFileExtensions = []string{".key", ".p12", ".pfx", ".jks", ".keystore"}
+)
+
+// packageCache describes the cache left by a package manager
+type packageCache struct {
+	name    string
+	command *regexp.Regexp
+	// noCache matches the options disabling the cache
+	noCache *regexp.Regexp
+	paths   []string
+	// clean matches the commands removing the cache
+	clean *regexp.Regexp
+}
+
+var packageCaches = []packageCache{
+	{
+		name:    "apt",
+		command: regexp.MustCompile(`\bapt(-get)?\s+(\S+\s+)*install\b`),
+		paths:   []string{"/var/lib/apt/lists", "/var/cache/apt"},
+		clean:   regexp.MustCompile(`rm\s+-[a-zA-Z]*\s+/var/lib/apt/lists`),
+	},
+	{
+		name:    "apk",
+		command: regexp.MustCompile(`\bapk\s+(\S+\s+)*add\b`),
+		noCache: regexp.MustCompile(`--no-cache\b`),
+		paths:   []string{"/var/cache/apk"},
+		clean:   regexp.MustCompile(`rm\s+-[a-zA-Z]*\s+/var/cache/apk|\bapk\s+cache\s+clean\b`),
+	},
+	{
+		name:    "yum",
+		command: regexp.MustCompile(`\byum\s+(\S+\s+)*install\b`),
+		paths:   []string{"/var/cache/yum"},
+		clean:   regexp.MustCompile(`\byum\s+clean\s+all\b|rm\s+-[a-zA-Z]*\s+/var/cache/yum`),
+	},
+	{
+		name:    "dnf",
+		command: regexp.MustCompile(`\b(micro)?dnf\s+(\S+\s+)*install\b`),
+		paths:   []string{"/var/cache/dnf"},
+		clean:   regexp.MustCompile(`\bdnf\s+clean\s+all\b|rm\s+-[a-zA-Z]*\s+/var/cache/dnf`),
+	},
+	{
+		name:    "pip",
+		command: regexp.MustCompile(`\bpip[0-9.]*\s+(\S+\s+)*install\b`),
+		noCache: regexp.MustCompile(`--no-cache-dir\b`),
+		paths:   []string{"/root/.cache/pip"},
+		clean:   regexp.MustCompile(`\bpip[0-9.]*\s+cache\s+purge\b|rm\s+-[a-zA-Z]*\s+/root/.cache`),
+	},
+	{
+		name:    "npm",
+		command: regexp.MustCompile(`\bnpm\s+(\S+\s+)*(install|ci|i)\b`),
+		paths:   []string{"/root/.npm"},
+		clean:   regexp.MustCompile(`\bnpm\s+cache\s+clean\b|rm\s+-[a-zA-Z]*\s+/root/.npm`),
+	},
+	{
+		name:    "yarn",
+		command: regexp.MustCompile(`\byarn(\s+install)?\b`),
+		paths:   []string{"/usr/local/share/.cache/yarn"},
+		clean:   regexp.MustCompile(`\byarn\s+cache\s+clean\b`),
+	},
+	{
+		name:    "Go module",
+		command: regexp.MustCompile(`\bgo\s+(build|install|get|mod\s+download)\b`),
+		paths:   []string{"/go/pkg/mod", "/root/.cache/go-build"},
+		clean:   regexp.MustCompile(`\bgo\s+clean\s+(-\S+\s+)*-modcache\b`),
+	},
+	{
+		name:    "Maven",
+		command: regexp.MustCompile(`\bmvnw?\s`),
+		paths:   []string{"/root/.m2"},
+		clean:   regexp.MustCompile(`rm\s+-[a-zA-Z]*\s+/root/.m2`),
+	},
+	{
+		name:    "Gradle",
+		command: regexp.MustCompile(`\bgradlew?\s`),
+		paths:   []string{"/root/.gradle"},
+		clean:   regexp.MustCompile(`rm\s+-[a-zA-Z]*\s+/root/.gradle`),
+	},
+	{
+		name:    "Cargo",
+		command: regexp.MustCompile(`\bcargo\s+(build|install|fetch)\b`),
+		paths:   []string{"/usr/local/cargo/registry"},
+		clean:   regexp.MustCompile(`rm\s+-[a-zA-Z]*\s+/usr/local/cargo/registry`),
+	},
+}
+
+// artifact is a secret or a package cache in the filesystem of a stage
+type artifact struct {
+	path string
+	// description is e.g. "secret file" or "npm cache"
+	description string
+	secret      bool
+	// stages are the names of the stages the artifact went through, starting with the one it was added in
+	stages []string
+	line   int
+}
+
+// stageState tracks the filesystem of a build stage
+type stageState struct {
+	name      string
+	alias     string
+	workdir   string
+	artifacts []artifact
+}
+
+// checkProvenance follows the artifacts through the stages of a multi-stage build,
+// and reports the secrets and package caches copied from other stages into the final one.
+func checkProvenance(fsys fs.FS, filePath string, file *dockerfile.Dockerfile) scan.Results {
+	var stages []dockerfile.Stage
+	for _, stage := range file.Stages {
+		// The arguments declared before the first FROM are parsed as a separate stage
+		if len(stage.Commands) > 0 && stage.Commands[0].Cmd == "from" {
+			stages = append(stages, stage)
+		}
+	}
+	if len(stages) < 2 {
+		return nil
+	}
+
+	rng := func(cmd dockerfile.Command) types.Metadata {
+		return types.NewMetadata(types.NewRange(filePath, cmd.StartLine, cmd.EndLine, "", fsys), "")
+	}
+
+	var states []*stageState
+	var secretResults, cacheResults scan.Results
+	for i, stage := range stages {
+		state := newStageState(stage, states)
+		final := i == len(stages)-1
+		for _, cmd := range stage.Commands {
+			switch cmd.Cmd {
+			case "workdir":
+				if len(cmd.Value) > 0 {
+					state.workdir = resolvePath(state.workdir, cmd.Value[0])
+				}
+			case "run":
+				state.run(cmd)
+			case "copy", "add":
+				from := flagValue(cmd.Flags, "from")
+				if from == "" {
+					state.addSecrets(cmd)
+					continue
+				}
+				source := findStage(states, from)
+				if source == nil {
+					continue
+				}
+				copied := state.copyFrom(source, cmd)
+				if !final {
+					continue
+				}
+				for _, a := range copied {
+					msg := fmt.Sprintf("'COPY --from=%s' copies the %s '%s' into the final image (%s)",
+						from, a.description, a.path, a.origin())
+					if a.secret {
+						secretResults.AddRego(msg, provenanceNamespace, "secret", nil, rng(cmd))
+					} else {
+						cacheResults.AddRego(msg, provenanceNamespace, "cache", nil, rng(cmd))
+					}
+				}
+			}
+		}
+		states = append(states, state)
+	}
+
+	fromCmd := stages[len(stages)-1].Commands[0]
+	if len(secretResults) == 0 {
+		secretResults.AddPassedRego(provenanceNamespace, "secret", nil, rng(fromCmd))
+	}
+	if len(cacheResults) == 0 {
+		cacheResults.AddPassedRego(provenanceNamespace, "cache", nil, rng(fromCmd))
+	}
+	secretResults.SetRule(secretCopyRule)
+	cacheResults.SetRule(cacheCopyRule)
+	return append(secretResults, cacheResults...)
+}
+
+func newStageState(stage dockerfile.Stage, previous []*stageState) *stageState {
+	state := &stageState{
+		name:    stage.Name,
+		workdir: "/",
+	}
+	from := stage.Commands[0].Value
+	if len(from) == 3 && strings.EqualFold(from[1], "as") {
+		state.alias = strings.ToLower(from[2])
+	}
+	// A stage based on a previous one starts with its filesystem
+	if len(from) > 0 {
+		if base := findStage(previous, from[0]); base != nil {
+			state.workdir = base.workdir
+			for _, a := range base.artifacts {
+				state.artifacts = append(state.artifacts, a.through(state))
+			}
+		}
+	}
+	return state
+}
+
+// findStage looks up a previous stage by alias or index
+func findStage(stages []*stageState, ref string) *stageState {
+	if i, err := strconv.Atoi(ref); err == nil {
+		if i >= 0 && i < len(stages) {
+			return stages[i]
+		}
+		return nil
+	}
+	ref = strings.ToLower(ref)
+	for i := len(stages) - 1; i >= 0; i-- {
+		if stages[i].alias != "" && stages[i].alias == ref {
+			return stages[i]
+		}
+	}
+	return nil
+}
+
+func (s *stageState) displayName() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+// run records the package caches left by the command
+func (s *stageState) run(cmd dockerfile.Command) {
+	script := strings.Join(cmd.Value, " ")
+	var cacheMounts []string
+	for _, flag := range cmd.Flags {
+		if mount, ok := strings.CutPrefix(flag, "--mount="); ok && strings.Contains(mount, "type=cache") {
+			if target := mountTarget(mount); target != "" {
+				cacheMounts = append(cacheMounts, resolvePath(s.workdir, target))
+			}
+		}
+	}
+
+	for _, cache := range packageCaches {
+		if !cache.command.MatchString(script) || cache.clean.MatchString(script) {
+			continue
+		}
+		if cache.noCache != nil && cache.noCache.MatchString(script) {
+			continue
+		}
+		for _, p := range cache.paths {
+			// Cache mounts are not persisted in the image
+			if slices.ContainsFunc(cacheMounts, func(mount string) bool { return contains(mount, p) }) {
+				continue
+			}
+			if slices.ContainsFunc(s.artifacts, func(a artifact) bool { return a.path == p }) {
+				continue
+			}
+			s.artifacts = append(s.artifacts, artifact{
+				path:        p,
+				description: cache.name + " cache",
+				stages:      []string{s.displayName()},
+				line:        cmd.StartLine,
+			})
+		}
+	}
+}
+
+// addSecrets records the secrets copied from the build context
+func (s *stageState) addSecrets(cmd dockerfile.Command) {
+	if len(cmd.Value) < 2 {
+		return
+	}
+	sources, dest := cmd.Value[:len(cmd.Value)-1], cmd.Value[len(cmd.Value)-1]
+	dest = resolvePath(s.workdir, dest)
+	for _, src := range sources {
+		if strings.Contains(src, "://") || !isSecret(src) {
+			continue
+		}
+		target := dest
+		if len(sources) > 1 || strings.HasSuffix(cmd.Value[len(cmd.Value)-1], "/") {
+			target = path.Join(dest, path.Base(src))
+		}
+		s.artifacts = append(s.artifacts, artifact{
+			path:        target,
+			description: "secret file",
+			secret:      true,
+			stages:      []string{s.displayName()},
+			line:        cmd.StartLine,
+		})
+	}
+}
+
+// copyFrom copies the artifacts of the source stage matching the sources of the command, and returns them
+func (s *stageState) copyFrom(source *stageState, cmd dockerfile.Command) []artifact {
+	if len(cmd.Value) < 2 {
+		return nil
+	}
+	sources, rawDest := cmd.Value[:len(cmd.Value)-1], cmd.Value[len(cmd.Value)-1]
+	dest := resolvePath(s.workdir, rawDest)
+	intoDir := len(sources) > 1 || strings.HasSuffix(rawDest, "/")
+
+	var copied []artifact
+	for _, src := range sources {
+		// The sources are relative to the root of the source stage
+		src = resolvePath("/", src)
+		for _, a := range source.artifacts {
+			target, ok := copyTarget(src, dest, intoDir, a.path)
+			if !ok {
+				continue
+			}
+			c := a.through(s)
+			c.path = target
+			copied = append(copied, c)
+		}
+	}
+	s.artifacts = append(s.artifacts, copied...)
+	return copied
+}
+
+// copyTarget returns where the artifact at the given path ends up when the source is copied to the destination
+func copyTarget(src, dest string, intoDir bool, p string) (string, bool) {
+	switch {
+	case src == p:
+		if intoDir {
+			return path.Join(dest, path.Base(p)), true
+		}
+		return dest, true
+	case contains(src, p):
+		// The content of a directory is copied, not the directory itself
+		return path.Join(dest, strings.TrimPrefix(p, src)), true
+	case contains(p, src):
+		// Part of the artifact is copied, e.g. a package of the cache
+		return dest, true
+	}
+
+	// Wildcards match the artifact or one of its parent directories
+	if strings.ContainsAny(src, "*?[") {
+		for dir := p; dir != "/"; dir = path.Dir(dir) {
+			if ok, _ := path.Match(src, dir); ok {
+				return path.Join(dest, path.Base(dir), strings.TrimPrefix(p, dir)), true
+			}
+		}
+	}
+	return "", false
+}
+
+// through returns a copy of the artifact which went through the stage
+func (a artifact) through(s *stageState) artifact {
+	a.stages = append(slices.Clone(a.stages), s.displayName())
+	return a
+}
+
+// origin describes where the artifact comes from, e.g. "added in stage 'builder' at line 3"
+func (a artifact) origin() string {
+	verb := "left"
+	if a.secret {
+		verb = "added"
+	}
+	origin := fmt.Sprintf("%s in stage '%s' at line %d", verb, a.stages[0], a.line)
+	// The last stage is the final one
+	if via := a.stages[1 : len(a.stages)-1]; len(via) > 0 {
+		origin += fmt.Sprintf(" and copied through '%s'", strings.Join(via, "', '"))
+	}
+	return origin
+}
+
+func isSecret(p string) bool {
+	p = strings.TrimSuffix(p, "/")
+	base := path.Base(p)
+	switch {
+	case slices.Contains(secretFileNames, base), strings.HasPrefix(base, ".env."):
+		return true
+	case slices.Contains(secretDirNames, base):
+		return true
+	case slices.Contains(secretFileExtensions, path.Ext(base)):
+		return true
+	}
+	return slices.ContainsFunc(secretFilePaths, func(s string) bool {
+		return p == s || strings.HasSuffix(p, "/"+s)
+	})
+}
+
+// contains checks if the directory contains the path
+func contains(dir, p string) bool {
+	return dir == "/" || strings.HasPrefix(p, strings.TrimSuffix(dir, "/")+"/")
+}
+
+func resolvePath(workdir, p string) string {
+	if path.IsAbs(p) {
+		return path.Clean(p)
+	}
+	return path.Join(workdir, p)
+}
+
+func flagValue(flags []string, name string) string {
+	for _, flag := range flags {
+		if v, ok := strings.CutPrefix(flag, "--"+name+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+func mountTarget(mount string) string {
+	for _, opt := range strings.Split(mount, ",") {
+		key, value, _ := strings.Cut(opt, "=")
+		if key == "target" || key == "dst" || key == "destination" {
+			return value
+		}
+	}
+	return ""
+}
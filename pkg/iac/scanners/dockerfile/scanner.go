@@ -136,6 +136,12 @@ func (s *Scanner) ScanFS(ctx context.Context, fsys fs.FS, path string) (scan.Res
 	if err != nil {
 		return nil, err
 	}
+	// The provenance checks are built in like the embedded policies
+	if s.loadEmbeddedPolicies {
+		for path, dfile := range files {
+			results = append(results, checkProvenance(fsys, path, dfile)...)
+		}
+	}
 	return results, nil
 }
 
@@ -145,10 +151,17 @@ func (s *Scanner) ScanFile(ctx context.Context, fsys fs.FS, path string) (scan.R
 		return nil, err
 	}
 	s.debug.Log("Scanning %s...", path)
-	return s.scanRego(ctx, fsys, rego.Input{
+	results, err := s.scanRego(ctx, fsys, rego.Input{
 		Path:     path,
 		Contents: dockerfile.ToRego(),
 	})
+	if err != nil {
+		return nil, err
+	}
+	if s.loadEmbeddedPolicies {
+		results = append(results, checkProvenance(fsys, path, dockerfile)...)
+	}
+	return results, nil
 }
 
 func (s *Scanner) initRegoScanner(srcFS fs.FS) (*rego.Scanner, error) {
@@ -3,8 +3,12 @@ package dockerfile
 import (
 	"bytes"
 	"context"
+	"io/fs"
+	"strings"
 	"testing"
 
+	"github.com/samber/lo"
+
 	"github.com/aquasecurity/trivy/internal/testutil"
 	"github.com/aquasecurity/trivy/pkg/iac/framework"
 	"github.com/aquasecurity/trivy/pkg/iac/rego"
@@ -635,3 +639,103 @@ COPY --from=dep /binary /`
 	}
 
 }
+
+func Test_ScanProvenance(t *testing.T) {
+	fs := testutil.CreateFS(t, map[string]string{
+		"/code/Dockerfile": `FROM node:20 AS deps
+WORKDIR /app
+COPY .npmrc package.json ./
+RUN npm ci
+
+FROM deps AS build
+COPY . .
+RUN npm run build
+
+FROM python:3.12 AS tools
+RUN --mount=type=cache,target=/root/.cache/pip pip install awscli
+
+FROM node:20-slim
+COPY --from=build /app/dist /app/dist
+COPY --from=build /app /srv
+COPY --from=build /root/.npm/_cacache /root/.npm/_cacache
+COPY --from=tools /usr/local /usr/local
+`,
+	})
+
+	failed, passed := scanProvenance(t, fs)
+
+	type finding struct {
+		id      string
+		message string
+		line    int
+	}
+	var got []finding
+	for _, result := range failed {
+		got = append(got, finding{
+			id:      result.Rule().AVDID,
+			message: result.Description(),
+			line:    result.Range().GetStartLine(),
+		})
+	}
+	assert.ElementsMatch(t, []finding{
+		{
+			id:      "DSP001",
+			message: "'COPY --from=build' copies the secret file '/srv/.npmrc' into the final image (added in stage 'deps' at line 3 and copied through 'build')",
+			line:    15,
+		},
+		{
+			id:      "DSP002",
+			message: "'COPY --from=build' copies the npm cache '/root/.npm/_cacache' into the final image (left in stage 'deps' at line 4 and copied through 'build')",
+			line:    16,
+		},
+	}, got)
+	require.Len(t, passed, 0)
+
+	t.Run("single stage", func(t *testing.T) {
+		fs := testutil.CreateFS(t, map[string]string{
+			"/code/Dockerfile": `FROM node:20
+COPY .npmrc .
+RUN npm ci
+`,
+		})
+		failed, passed := scanProvenance(t, fs)
+		assert.Empty(t, failed)
+		assert.Empty(t, passed)
+	})
+
+	t.Run("clean build", func(t *testing.T) {
+		fs := testutil.CreateFS(t, map[string]string{
+			"/code/Dockerfile": `FROM golang:1.22 AS builder
+RUN go build -o /out/app .
+
+FROM alpine:3.19
+RUN apk add --no-cache ca-certificates
+COPY --from=builder /out/app /usr/local/bin/app
+`,
+		})
+		failed, passed := scanProvenance(t, fs)
+		assert.Empty(t, failed)
+		assert.Len(t, passed, 2)
+	})
+
+	t.Run("embedded checks disabled", func(t *testing.T) {
+		results, err := NewScanner().ScanFS(context.TODO(), fs, "code")
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+// scanProvenance returns the results of the provenance checks, which are run with the embedded checks
+func scanProvenance(t *testing.T, fsys fs.FS) (scan.Results, scan.Results) {
+	scanner := NewScanner(
+		options.ScannerWithEmbeddedPolicies(true),
+		options.ScannerWithEmbeddedLibraries(true),
+	)
+	results, err := scanner.ScanFS(context.TODO(), fsys, "code")
+	require.NoError(t, err)
+
+	isProvenance := func(result scan.Result, _ int) bool {
+		return strings.HasPrefix(result.Rule().AVDID, "DSP")
+	}
+	return lo.Filter(results.GetFailed(), isProvenance), lo.Filter(results.GetPassed(), isProvenance)
+}
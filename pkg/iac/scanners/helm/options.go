@@ -49,3 +49,19 @@ func ScannerWithAPIVersions(values ...string) options.ScannerOption {
 		}
 	}
 }
+
+func ScannerWithDependencyCacheDir(dir string) options.ScannerOption {
+	return func(s options.ConfigurableScanner) {
+		if helmScanner, ok := s.(ConfigurableHelmScanner); ok {
+			helmScanner.AddParserOptions(parser.OptionWithDependencyCacheDir(dir))
+		}
+	}
+}
+
+func ScannerWithDependencyDownload(enabled bool) options.ScannerOption {
+	return func(s options.ConfigurableScanner) {
+		if helmScanner, ok := s.(ConfigurableHelmScanner); ok {
+			helmScanner.AddParserOptions(parser.OptionWithDependencyDownload(enabled))
+		}
+	}
+}
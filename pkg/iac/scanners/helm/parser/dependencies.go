@@ -0,0 +1,231 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// lockedDependency is a subchart pinned in Chart.lock
+type lockedDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+}
+
+type repoIndex struct {
+	Entries map[string][]struct {
+		Version string   `yaml:"version"`
+		URLs    []string `yaml:"urls"`
+	} `yaml:"entries"`
+}
+
+// resolveDependencies puts the subcharts pinned in Chart.lock into the "charts" directory of the chart,
+// so that they are rendered along with the chart.
+// Archives are taken from the dependency cache, and downloaded only when they are not cached and downloads are enabled.
+func (p *Parser) resolveDependencies(chartDir string) error {
+	// Chart.lock is not collected with the chart files, so it is read from the original filesystem
+	content, err := fs.ReadFile(p.workingFS, path.Join(filepath.ToSlash(p.rootPath), "Chart.lock"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var lock struct {
+		Dependencies []lockedDependency `yaml:"dependencies"`
+	}
+	if err := yaml.Unmarshal(content, &lock); err != nil {
+		return fmt.Errorf("failed to parse Chart.lock: %w", err)
+	}
+
+	chartsDir := filepath.Join(chartDir, "charts")
+	for _, dep := range lock.Dependencies {
+		// Chart.lock comes from the scanned files and must not lead to writing files outside the chart
+		if err := validateDependency(dep); err != nil {
+			p.debug.Log("Skip the dependency %q: %s", dep.Name, err)
+			continue
+		}
+		if subchartExists(chartsDir, dep) {
+			continue
+		}
+		if err := os.MkdirAll(chartsDir, os.ModePerm); err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(dep.Repository, "file://") {
+			if err := p.copyLocalDependency(chartsDir, dep); err != nil {
+				p.debug.Log("Failed to copy the local dependency %q: %s", dep.Name, err)
+			}
+			continue
+		}
+
+		archive, err := p.dependencyArchive(dep)
+		if err != nil {
+			p.debug.Log("Failed to get the dependency %q: %s", dep.Name, err)
+			continue
+		}
+		archivePath, err := securePath(chartsDir, fmt.Sprintf("%s-%s.tgz", dep.Name, dep.Version))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(archivePath, archive, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDependency rejects the name and the version which are not a single path element
+func validateDependency(dep lockedDependency) error {
+	for _, v := range []string{dep.Name, dep.Version} {
+		if v == "" || v == "." || strings.Contains(v, "..") || strings.ContainsAny(v, `/\`) {
+			return fmt.Errorf("invalid name or version: %q", v)
+		}
+	}
+	return nil
+}
+
+// securePath joins the elements to the base directory, and returns an error if the path is outside the directory
+func securePath(baseDir string, elem ...string) (string, error) {
+	joined := filepath.Join(append([]string{baseDir}, elem...)...)
+	rel, err := filepath.Rel(baseDir, joined)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside %q", joined, baseDir)
+	}
+	return joined, nil
+}
+
+func subchartExists(chartsDir string, dep lockedDependency) bool {
+	for _, name := range []string{dep.Name, fmt.Sprintf("%s-%s.tgz", dep.Name, dep.Version)} {
+		if _, err := os.Stat(filepath.Join(chartsDir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// copyLocalDependency copies a subchart referenced by a relative path, e.g. "file://../common"
+func (p *Parser) copyLocalDependency(chartsDir string, dep lockedDependency) error {
+	depPath := path.Join(filepath.ToSlash(p.rootPath), strings.TrimPrefix(dep.Repository, "file://"))
+	return fs.WalkDir(p.workingFS, depPath, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		content, err := fs.ReadFile(p.workingFS, filePath)
+		if err != nil {
+			return err
+		}
+		workingPath, err := securePath(chartsDir, dep.Name, strings.TrimPrefix(filePath, depPath))
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(workingPath), os.ModePerm); err != nil {
+			return err
+		}
+		return os.WriteFile(workingPath, content, os.ModePerm)
+	})
+}
+
+// dependencyArchive returns the chart archive of the dependency from the cache or the repository
+func (p *Parser) dependencyArchive(dep lockedDependency) ([]byte, error) {
+	var cachePath string
+	if p.dependencyCacheDir != "" {
+		repoKey := fmt.Sprintf("%x", sha256.Sum256([]byte(dep.Repository)))
+		var err error
+		cachePath, err = securePath(p.dependencyCacheDir, repoKey, fmt.Sprintf("%s-%s.tgz", dep.Name, dep.Version))
+		if err != nil {
+			return nil, err
+		}
+		if archive, err := os.ReadFile(cachePath); err == nil {
+			p.debug.Log("Dependency %q resolved via cache", dep.Name)
+			return archive, nil
+		}
+	}
+
+	if !p.downloadDependencies {
+		return nil, fmt.Errorf("the dependency is not cached and downloads are disabled")
+	}
+
+	archive, err := downloadDependency(dep)
+	if err != nil {
+		return nil, err
+	}
+	p.debug.Log("Downloaded the dependency %q from %s", dep.Name, dep.Repository)
+
+	if cachePath != "" {
+		if err := os.MkdirAll(filepath.Dir(cachePath), os.ModePerm); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(cachePath, archive, 0o600); err != nil {
+			return nil, err
+		}
+	}
+	return archive, nil
+}
+
+func downloadDependency(dep lockedDependency) ([]byte, error) {
+	providers := getter.All(cli.New())
+
+	repoURL, err := url.Parse(dep.Repository)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository %q: %w", dep.Repository, err)
+	}
+
+	// Repositories added by "helm repo add" are referenced by name, e.g. "@bitnami"
+	if repoURL.Scheme == "" {
+		return nil, fmt.Errorf("repository %q is not a URL", dep.Repository)
+	}
+
+	g, err := providers.ByScheme(repoURL.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	chartURL := fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(dep.Repository, "/"), dep.Name, dep.Version)
+	if repoURL.Scheme != "oci" {
+		if chartURL, err = chartURLFromIndex(g, dep); err != nil {
+			return nil, err
+		}
+	}
+
+	archive, err := g.Get(chartURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", chartURL, err)
+	}
+	return archive.Bytes(), nil
+}
+
+func chartURLFromIndex(g getter.Getter, dep lockedDependency) (string, error) {
+	indexURL := strings.TrimSuffix(dep.Repository, "/") + "/index.yaml"
+	content, err := g.Get(indexURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", indexURL, err)
+	}
+
+	var index repoIndex
+	if err := yaml.Unmarshal(content.Bytes(), &index); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", indexURL, err)
+	}
+
+	for _, entry := range index.Entries[dep.Name] {
+		if entry.Version == dep.Version && len(entry.URLs) > 0 {
+			return repo.ResolveReferenceURL(dep.Repository, entry.URLs[0])
+		}
+	}
+	return "", fmt.Errorf("%s %s is not found in %s", dep.Name, dep.Version, indexURL)
+}
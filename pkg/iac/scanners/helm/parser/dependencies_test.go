@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_resolveDependencies(t *testing.T) {
+	fsys := fstest.MapFS{
+		"chart/Chart.yaml": &fstest.MapFile{Data: []byte("apiVersion: v2\nname: chart\nversion: 1.0.0\n")},
+		"chart/Chart.lock": &fstest.MapFile{Data: []byte(`dependencies:
+- name: ../../../escaped
+  repository: file://../common
+  version: 1.0.0
+- name: escaped
+  repository: https://charts.example.com/stable
+  version: ../../../../1.0.0
+- name: ..
+  repository: file://../common
+  version: 1.0.0
+- name: common
+  repository: file://../common
+  version: 1.0.0
+- name: remote
+  repository: https://charts.example.com/stable
+  version: 2.3.4
+`)},
+		"common/Chart.yaml":         &fstest.MapFile{Data: []byte("apiVersion: v2\nname: common\nversion: 1.0.0\n")},
+		"common/templates/pod.yaml": &fstest.MapFile{Data: []byte("kind: Pod\n")},
+	}
+
+	dir := t.TempDir()
+	p := New("chart", OptionWithDependencyCacheDir(filepath.Join(dir, "cache")))
+	p.workingFS = fsys
+	p.rootPath = "chart"
+	require.NoError(t, p.resolveDependencies(filepath.Join(dir, "work", "chart")))
+
+	// Only the valid local dependency is copied, and the remote one is not downloaded by default
+	var got []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		got = append(got, filepath.ToSlash(rel))
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"work/chart/charts/common/Chart.yaml",
+		"work/chart/charts/common/templates/pod.yaml",
+	}, got)
+
+	_, err = os.Stat(filepath.Join(dir, "escaped"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func Test_securePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		elem    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "in the directory",
+			elem: []string{"common", "templates/pod.yaml"},
+			want: filepath.Join("base", "common", "templates", "pod.yaml"),
+		},
+		{
+			name:    "outside the directory",
+			elem:    []string{"common", "../../escaped"},
+			wantErr: true,
+		},
+		{
+			name:    "parent directory",
+			elem:    []string{".."},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := securePath("base", tt.elem...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
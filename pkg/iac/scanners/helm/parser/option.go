@@ -9,6 +9,8 @@ type ConfigurableHelmParser interface {
 	SetFileValues(...string)
 	SetStringValues(...string)
 	SetAPIVersions(...string)
+	SetDependencyCacheDir(string)
+	SetDependencyDownload(bool)
 }
 
 func OptionWithValuesFile(paths ...string) options.ParserOption {
@@ -50,3 +52,19 @@ func OptionWithAPIVersions(values ...string) options.ParserOption {
 		}
 	}
 }
+
+func OptionWithDependencyCacheDir(dir string) options.ParserOption {
+	return func(p options.ConfigurableParser) {
+		if helmParser, ok := p.(ConfigurableHelmParser); ok {
+			helmParser.SetDependencyCacheDir(dir)
+		}
+	}
+}
+
+func OptionWithDependencyDownload(enabled bool) options.ParserOption {
+	return func(p options.ConfigurableParser) {
+		if helmParser, ok := p.(ConfigurableHelmParser); ok {
+			helmParser.SetDependencyDownload(enabled)
+		}
+	}
+}
@@ -41,6 +41,9 @@ type Parser struct {
 	fileValues   []string
 	stringValues []string
 	apiVersions  []string
+
+	dependencyCacheDir   string
+	downloadDependencies bool
 }
 
 type ChartFile struct {
@@ -76,6 +79,14 @@ func (p *Parser) SetAPIVersions(values ...string) {
 	p.apiVersions = values
 }
 
+func (p *Parser) SetDependencyCacheDir(dir string) {
+	p.dependencyCacheDir = dir
+}
+
+func (p *Parser) SetDependencyDownload(enabled bool) {
+	p.downloadDependencies = enabled
+}
+
 func New(path string, opts ...options.ParserOption) *Parser {
 
 	client := action.NewInstall(&action.Configuration{})
@@ -202,6 +213,10 @@ func (p *Parser) RenderedChartFiles() ([]ChartFile, error) {
 		return nil, err
 	}
 
+	if err := p.resolveDependencies(tempDir); err != nil {
+		return nil, err
+	}
+
 	workingChart, err := loadChart(tempDir)
 	if err != nil {
 		return nil, err
@@ -1,7 +1,12 @@
 package test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -197,3 +202,59 @@ func Test_helm_tarball_parser(t *testing.T) {
 		}
 	}
 }
+
+func Test_helm_parser_with_locked_dependencies(t *testing.T) {
+	// The remote dependency is resolved from the cache, as downloads are disabled
+	repoKey := fmt.Sprintf("%x", sha256.Sum256([]byte("https://charts.example.com/stable")))
+	cacheDir := t.TempDir()
+	archivePath := filepath.Join(cacheDir, repoKey, "remote-2.3.4.tgz")
+	require.NoError(t, os.MkdirAll(filepath.Dir(archivePath), os.ModePerm))
+	require.NoError(t, os.WriteFile(archivePath, createChartArchive(t, map[string]string{
+		"remote/Chart.yaml": "apiVersion: v2\nname: remote\nversion: 2.3.4\n",
+		"remote/templates/service.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: remote
+spec:
+  ports:
+    - port: 80
+`,
+	}), os.ModePerm))
+
+	helmParser := parser.New("chart",
+		parser.OptionWithDependencyCacheDir(cacheDir),
+		parser.OptionWithDependencyDownload(false),
+	)
+	err := helmParser.ParseFS(context.TODO(), os.DirFS(filepath.Join("testdata", "with-locked-deps")), "chart")
+	require.NoError(t, err)
+	manifests, err := helmParser.RenderedChartFiles()
+	require.NoError(t, err)
+
+	var paths []string
+	for _, manifest := range manifests {
+		paths = append(paths, manifest.TemplateFilePath)
+	}
+	assert.ElementsMatch(t, []string{
+		"templates/pod.yaml",
+		"charts/common/templates/pod.yaml",
+		"charts/remote/templates/service.yaml",
+	}, paths)
+}
+
+func createChartArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,318 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/iac/providers"
+	"github.com/aquasecurity/trivy/pkg/iac/rego"
+	"github.com/aquasecurity/trivy/pkg/iac/scan"
+	"github.com/aquasecurity/trivy/pkg/iac/severity"
+	"github.com/aquasecurity/trivy/pkg/iac/types"
+)
+
+const (
+	crdAPIVersion = "apiextensions.k8s.io/v1"
+	crdKind       = "CustomResourceDefinition"
+
+	crdNamespace = "kubernetes.crd"
+	crdRule      = "schema"
+)
+
+var crdSchemaRule = scan.Rule{
+	AVDID:       "CRD001",
+	ShortCode:   "custom-resource-schema",
+	Summary:     "Custom resource violates the schema of its CRD",
+	Explanation: "The custom resource doesn't match the OpenAPI schema of its CustomResourceDefinition, so the API server will reject it or drop the unknown fields.",
+	Resolution:  "Fix the custom resource according to the schema of its CustomResourceDefinition",
+	Provider:    providers.KubernetesProvider,
+	Service:     "general",
+	Severity:    severity.Medium,
+}
+
+// crdSchemas holds the schemas of the custom resources, keyed by apiVersion and kind
+type crdSchemas map[string]*gojsonschema.Schema
+
+func crdKey(apiVersion, kind string) string {
+	return apiVersion + "/" + kind
+}
+
+// loadCRDSchemas loads the CustomResourceDefinitions from the given files or directories.
+// Files may contain several YAML documents, or lists such as the output of 'kubectl get crd -o yaml'.
+func loadCRDSchemas(paths []string) (crdSchemas, error) {
+	schemas := make(crdSchemas)
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			// Only the extensions of the root are not checked, as it is passed explicitly
+			if path != root && !slices.Contains([]string{".yaml", ".yml", ".json"}, filepath.Ext(path)) {
+				return nil
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err = schemas.load(b); err != nil {
+				return fmt.Errorf("failed to load CRDs from %s: %w", path, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return schemas, nil
+}
+
+func (c crdSchemas) load(content []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc map[string]any
+		if err := decoder.Decode(&doc); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := c.add(doc); err != nil {
+			return err
+		}
+	}
+}
+
+func (c crdSchemas) add(doc map[string]any) error {
+	if items, ok := doc["items"].([]any); ok {
+		for _, item := range items {
+			if m, ok := item.(map[string]any); ok {
+				if err := c.add(m); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if doc["apiVersion"] != crdAPIVersion || doc["kind"] != crdKind {
+		return nil
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var crd struct {
+		Spec struct {
+			Group string `json:"group"`
+			Names struct {
+				Kind string `json:"kind"`
+			} `json:"names"`
+			Versions []struct {
+				Name   string `json:"name"`
+				Schema struct {
+					OpenAPIV3Schema map[string]any `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}
+	if err = json.Unmarshal(b, &crd); err != nil {
+		return err
+	}
+
+	for _, version := range crd.Spec.Versions {
+		if version.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(toJSONSchema(version.Schema.OpenAPIV3Schema, true)))
+		if err != nil {
+			return fmt.Errorf("invalid schema of %s/%s %s: %w", crd.Spec.Group, version.Name, crd.Spec.Names.Kind, err)
+		}
+		c[crdKey(crd.Spec.Group+"/"+version.Name, crd.Spec.Names.Kind)] = schema
+	}
+	return nil
+}
+
+// toJSONSchema converts a structural OpenAPI v3 schema of a CRD into a JSON schema.
+// Unknown fields are rejected as with the strict field validation of kubectl,
+// unless the schema preserves them.
+func toJSONSchema(schema map[string]any, root bool) map[string]any {
+	converted := make(map[string]any, len(schema))
+	for k, v := range schema {
+		converted[k] = v
+	}
+
+	if props, ok := schema["properties"].(map[string]any); ok {
+		convertedProps := make(map[string]any, len(props))
+		for name, prop := range props {
+			if m, ok := prop.(map[string]any); ok {
+				convertedProps[name] = toJSONSchema(m, false)
+			}
+		}
+		converted["properties"] = convertedProps
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		converted["items"] = toJSONSchema(items, false)
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+		converted["additionalProperties"] = toJSONSchema(additional, false)
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if subSchemas, ok := schema[key].([]any); ok {
+			var convertedSubSchemas []any
+			for _, subSchema := range subSchemas {
+				if m, ok := subSchema.(map[string]any); ok {
+					convertedSubSchemas = append(convertedSubSchemas, toJSONSchema(m, false))
+				}
+			}
+			converted[key] = convertedSubSchemas
+		}
+	}
+
+	if root {
+		// The object metadata is validated by the API server, not by the CRD schema
+		props, _ := converted["properties"].(map[string]any)
+		if props == nil {
+			props = make(map[string]any)
+		}
+		for _, name := range []string{"apiVersion", "kind", "metadata"} {
+			if _, ok := props[name]; !ok {
+				props[name] = map[string]any{}
+			}
+		}
+		converted["properties"] = props
+	}
+
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		if typ, ok := schema["type"].(string); ok {
+			converted["type"] = []any{typ, "null"}
+		}
+	}
+
+	_, hasProps := converted["properties"]
+	_, hasAdditional := converted["additionalProperties"]
+	preserve, _ := schema["x-kubernetes-preserve-unknown-fields"].(bool)
+	embedded, _ := schema["x-kubernetes-embedded-resource"].(bool)
+	if hasProps && !hasAdditional && !preserve && !embedded {
+		converted["additionalProperties"] = false
+	}
+	return converted
+}
+
+// validateCustomResources validates the custom resources among the inputs against the schemas of their CRDs
+func (s *Scanner) validateCustomResources(inputs []rego.Input) scan.Results {
+	var results scan.Results
+	for _, input := range inputs {
+		manifest, ok := input.Contents.(map[string]any)
+		if !ok {
+			continue
+		}
+		apiVersion, _ := manifest["apiVersion"].(string)
+		kind, _ := manifest["kind"].(string)
+		schema, ok := s.crdSchemas[crdKey(apiVersion, kind)]
+		if !ok {
+			continue
+		}
+
+		offset := metadataInt(manifest, "offset")
+		resource := resourceName(manifest)
+		rng := func(start, end int) types.Metadata {
+			return types.NewMetadata(types.NewRange(input.Path, start+offset, end+offset, "", input.FS), resource)
+		}
+
+		result, err := schema.Validate(gojsonschema.NewGoLoader(stripMetadata(manifest)))
+		if err != nil {
+			s.debug.Log("Failed to validate %s '%s': %s", kind, resource, err)
+			continue
+		}
+		if result.Valid() {
+			results.AddPassedRego(crdNamespace, crdRule, nil,
+				rng(metadataInt(manifest, "startline"), metadataInt(manifest, "endline")))
+			continue
+		}
+		for _, resultErr := range result.Errors() {
+			start, end := locate(manifest, resultErr.Field())
+			results.AddRego(
+				fmt.Sprintf("%s '%s' violates the schema of its CRD: %s", kind, resource, resultErr),
+				crdNamespace, crdRule, nil, rng(start, end),
+			)
+		}
+	}
+	results.SetRule(crdSchemaRule)
+	return results
+}
+
+// locate returns the lines of the closest object to the field, e.g. "spec.containers.0.name"
+func locate(manifest map[string]any, field string) (int, int) {
+	start, end := metadataInt(manifest, "startline"), metadataInt(manifest, "endline")
+	if field == gojsonschema.STRING_CONTEXT_ROOT {
+		return start, end
+	}
+
+	var current any = manifest
+	for _, key := range strings.Split(field, ".") {
+		switch v := current.(type) {
+		case map[string]any:
+			current = v[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return start, end
+			}
+			current = v[i]
+		default:
+			return start, end
+		}
+		if m, ok := current.(map[string]any); ok {
+			start, end = metadataInt(m, "startline"), metadataInt(m, "endline")
+		}
+	}
+	return start, end
+}
+
+func metadataInt(manifest map[string]any, key string) int {
+	metadata, _ := manifest["__defsec_metadata"].(map[string]any)
+	v, _ := metadata[key].(int)
+	return v
+}
+
+func resourceName(manifest map[string]any) string {
+	metadata, _ := manifest["metadata"].(map[string]any)
+	name, _ := metadata["name"].(string)
+	return name
+}
+
+// stripMetadata removes the metadata added by the parser
+func stripMetadata(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		stripped := make(map[string]any, len(v))
+		for key, value := range v {
+			if key == "__defsec_metadata" {
+				continue
+			}
+			stripped[key] = stripMetadata(value)
+		}
+		return stripped
+	case []any:
+		stripped := make([]any, 0, len(v))
+		for _, value := range v {
+			stripped = append(stripped, stripMetadata(value))
+		}
+		return stripped
+	}
+	return v
+}
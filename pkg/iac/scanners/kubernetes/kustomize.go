@@ -0,0 +1,177 @@
+package kubernetes
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	ktypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// IsKustomizationFile checks if the file is a Kustomize kustomization file
+func IsKustomizationFile(name string) bool {
+	return slices.Contains(konfig.RecognizedKustomizationFileNames(), path.Base(name))
+}
+
+// renderedKustomization holds the final manifests of a kustomization
+type renderedKustomization struct {
+	// path of the kustomization file
+	path    string
+	content []byte
+}
+
+// kustomizations renders the kustomizations found in the filesystem.
+// Only the kustomizations which are not used as a base by another one are returned,
+// along with every file consumed while rendering them.
+func (s *Scanner) kustomizations(target fs.FS, dir string) ([]renderedKustomization, map[string]struct{}) {
+	var roots []string
+	if err := fs.WalkDir(target, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && IsKustomizationFile(p) {
+			roots = append(roots, p)
+		}
+		return nil
+	}); err != nil {
+		s.debug.Log("Failed to find kustomizations: %s", err)
+		return nil, nil
+	}
+	if len(roots) == 0 {
+		return nil, nil
+	}
+
+	memFS, err := copyToMemFS(target, dir)
+	if err != nil {
+		s.debug.Log("Failed to prepare the filesystem for kustomize: %s", err)
+		return nil, nil
+	}
+
+	opts := krusty.MakeDefaultOptions()
+	// the whole filesystem is in memory, so bases and patches may be anywhere in it
+	opts.LoadRestrictions = ktypes.LoadRestrictionsNone
+
+	type result struct {
+		rendered renderedKustomization
+		read     map[string]struct{}
+	}
+	var results []result
+	usedAsBase := make(map[string]struct{})
+	for _, root := range roots {
+		kustomizationDir := path.Dir(root)
+		if !isLocal(memFS, kustomizationDir, make(map[string]struct{})) {
+			s.debug.Log("Skipping kustomization '%s' referring to remote resources", root)
+			continue
+		}
+
+		recording := &recordingFS{FileSystem: memFS, read: make(map[string]struct{})}
+		resMap, err := krusty.MakeKustomizer(opts).Run(recording, path.Join("/", kustomizationDir))
+		if err != nil {
+			s.debug.Log("Failed to render kustomization '%s': %s", root, err)
+			continue
+		}
+		content, err := resMap.AsYaml()
+		if err != nil {
+			s.debug.Log("Failed to render kustomization '%s': %s", root, err)
+			continue
+		}
+
+		for p := range recording.read {
+			if IsKustomizationFile(p) && p != root {
+				usedAsBase[p] = struct{}{}
+			}
+		}
+		results = append(results, result{
+			rendered: renderedKustomization{path: root, content: content},
+			read:     recording.read,
+		})
+	}
+
+	var rendered []renderedKustomization
+	consumed := make(map[string]struct{})
+	for _, r := range results {
+		for p := range r.read {
+			consumed[p] = struct{}{}
+		}
+		if _, ok := usedAsBase[r.rendered.path]; ok {
+			continue
+		}
+		s.debug.Log("Rendered kustomization '%s'", r.rendered.path)
+		rendered = append(rendered, r.rendered)
+	}
+	return rendered, consumed
+}
+
+// isLocal checks that all the resources of the kustomization and its bases exist in the filesystem,
+// so that kustomize never attempts to fetch remote resources.
+func isLocal(fsys filesys.FileSystem, dir string, visited map[string]struct{}) bool {
+	if _, ok := visited[dir]; ok {
+		return true
+	}
+	visited[dir] = struct{}{}
+
+	var kustomization struct {
+		Resources  []string `yaml:"resources"`
+		Bases      []string `yaml:"bases"`
+		Components []string `yaml:"components"`
+	}
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		b, err := fsys.ReadFile(path.Join("/", dir, name))
+		if err != nil {
+			continue
+		}
+		if err = yaml.Unmarshal(b, &kustomization); err != nil {
+			return false
+		}
+		break
+	}
+
+	var refs []string
+	refs = append(refs, kustomization.Resources...)
+	refs = append(refs, kustomization.Bases...)
+	refs = append(refs, kustomization.Components...)
+	for _, ref := range refs {
+		p := path.Join("/", dir, ref)
+		if !fsys.Exists(p) {
+			return false
+		}
+		if fsys.IsDir(p) && !isLocal(fsys, strings.TrimPrefix(p, "/"), visited) {
+			return false
+		}
+	}
+	return true
+}
+
+func copyToMemFS(target fs.FS, dir string) (filesys.FileSystem, error) {
+	memFS := filesys.MakeFsInMemory()
+	err := fs.WalkDir(target, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return memFS.MkdirAll(path.Join("/", p))
+		}
+		b, err := fs.ReadFile(target, p)
+		if err != nil {
+			return err
+		}
+		return memFS.WriteFile(path.Join("/", p), b)
+	})
+	return memFS, err
+}
+
+// recordingFS records the files read by kustomize
+type recordingFS struct {
+	filesys.FileSystem
+	read map[string]struct{}
+}
+
+func (r *recordingFS) ReadFile(name string) ([]byte, error) {
+	r.read[strings.TrimPrefix(name, "/")] = struct{}{}
+	return r.FileSystem.ReadFile(name)
+}
@@ -0,0 +1,14 @@
+package kubernetes
+
+import (
+	"github.com/aquasecurity/trivy/pkg/iac/scanners/options"
+)
+
+// ScannerWithCRDSchemas validates custom resources against the CustomResourceDefinitions in the given files or directories
+func ScannerWithCRDSchemas(paths ...string) options.ScannerOption {
+	return func(s options.ConfigurableScanner) {
+		if k8sScanner, ok := s.(*Scanner); ok {
+			k8sScanner.crdSchemaPaths = append(k8sScanner.crdSchemaPaths, paths...)
+		}
+	}
+}
@@ -33,7 +33,7 @@ func (r *ManifestNode) ToRego() interface{} {
 		return nil
 	}
 	switch r.Type {
-	case TagBool, TagInt, TagString, TagStr:
+	case TagBool, TagInt, TagFloat, TagString, TagStr:
 		return r.Value
 	case TagSlice:
 		var output []interface{}
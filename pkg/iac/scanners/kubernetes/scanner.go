@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"io/fs"
@@ -36,6 +37,8 @@ type Scanner struct { // nolint: gocritic
 	frameworks            []framework.Framework
 	spec                  string
 	loadEmbeddedLibraries bool
+	crdSchemaPaths        []string
+	crdSchemas            crdSchemas
 }
 
 func (s *Scanner) SetSpec(spec string) {
@@ -121,6 +124,21 @@ func (s *Scanner) initRegoScanner(srcFS fs.FS) (*rego.Scanner, error) {
 	return regoScanner, nil
 }
 
+func (s *Scanner) initCRDSchemas() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.crdSchemas != nil || len(s.crdSchemaPaths) == 0 {
+		return nil
+	}
+	schemas, err := loadCRDSchemas(s.crdSchemaPaths)
+	if err != nil {
+		return err
+	}
+	s.debug.Log("Loaded %d CRD schemas", len(schemas))
+	s.crdSchemas = schemas
+	return nil
+}
+
 func (s *Scanner) ScanReader(ctx context.Context, filename string, reader io.Reader) (scan.Results, error) {
 	memfs := memoryfs.New()
 	if err := memfs.MkdirAll(filepath.Base(filename), 0o700); err != nil {
@@ -138,17 +156,23 @@ func (s *Scanner) ScanReader(ctx context.Context, filename string, reader io.Rea
 
 func (s *Scanner) ScanFS(ctx context.Context, target fs.FS, dir string) (scan.Results, error) {
 
+	// Kustomizations are scanned as their final manifests instead of the files they consist of
+	kustomizations, consumed := s.kustomizations(target, dir)
+
 	k8sFilesets, err := s.parser.ParseFS(ctx, target, dir)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(k8sFilesets) == 0 {
+	if len(k8sFilesets) == 0 && len(kustomizations) == 0 {
 		return nil, nil
 	}
 
 	var inputs []rego.Input
 	for path, k8sFiles := range k8sFilesets {
+		if _, ok := consumed[path]; ok {
+			continue
+		}
 		for _, content := range k8sFiles {
 			inputs = append(inputs, rego.Input{
 				Path:     path,
@@ -163,15 +187,62 @@ func (s *Scanner) ScanFS(ctx context.Context, target fs.FS, dir string) (scan.Re
 		return nil, err
 	}
 
+	if err = s.initCRDSchemas(); err != nil {
+		return nil, err
+	}
+
 	s.debug.Log("Scanning %d files...", len(inputs))
 	results, err := regoScanner.ScanInput(ctx, inputs...)
 	if err != nil {
 		return nil, err
 	}
+	results = append(results, s.validateCustomResources(inputs)...)
 	results.SetSourceAndFilesystem("", target, false)
 
+	for _, kustomization := range kustomizations {
+		kustomizationResults, err := s.scanKustomization(ctx, regoScanner, kustomization)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, kustomizationResults...)
+	}
+
 	sort.Slice(results, func(i, j int) bool {
 		return results[i].Rule().AVDID < results[j].Rule().AVDID
 	})
 	return results, nil
 }
+
+func (s *Scanner) scanKustomization(ctx context.Context, regoScanner *rego.Scanner, kustomization renderedKustomization) (scan.Results, error) {
+	manifests, err := s.parser.Parse(bytes.NewReader(kustomization.content), kustomization.path)
+	if err != nil {
+		s.debug.Log("Parse error in rendered kustomization '%s': %s", kustomization.path, err)
+		return nil, nil
+	}
+
+	// The rendered manifests are kept in a separate filesystem, so that the code of the results can be shown
+	renderedFS := memoryfs.New()
+	if err := renderedFS.MkdirAll(filepath.Dir(kustomization.path), fs.ModePerm); err != nil {
+		return nil, err
+	}
+	if err := renderedFS.WriteFile(kustomization.path, kustomization.content, fs.ModePerm); err != nil {
+		return nil, err
+	}
+
+	var inputs []rego.Input
+	for _, manifest := range manifests {
+		inputs = append(inputs, rego.Input{
+			Path:     kustomization.path,
+			FS:       renderedFS,
+			Contents: manifest,
+		})
+	}
+
+	results, err := regoScanner.ScanInput(ctx, inputs...)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, s.validateCustomResources(inputs)...)
+	results.SetSourceAndFilesystem("", renderedFS, false)
+	return results, nil
+}
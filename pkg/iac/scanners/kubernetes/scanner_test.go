@@ -3,6 +3,7 @@ package kubernetes
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -731,3 +732,219 @@ spec:
 	failure := results.GetFailed()[0].Rule()
 	assert.Equal(t, "Process can elevate its own privileges", failure.Summary)
 }
+
+func Test_ScanKustomization(t *testing.T) {
+	srcFS := testutil.CreateFS(t, map[string]string{
+		"policies/privileged.rego": `# METADATA
+# title: "Privileged container"
+# schemas:
+# - input: schema["kubernetes"]
+# custom:
+#   id: KSV017
+#   avd_id: AVD-KSV-9017
+#   severity: HIGH
+#   input:
+#     selector:
+#     - type: kubernetes
+package builtin.kubernetes.KSV9017
+
+import data.lib.kubernetes
+
+deny[res] {
+	container := kubernetes.containers[_]
+	container.securityContext.privileged == true
+	msg := sprintf("Container '%s' of %s '%s' should not be privileged", [container.name, kubernetes.kind, kubernetes.name])
+	res := result.new(msg, container)
+}
+`,
+		"code/base/kustomization.yaml": `resources:
+  - deployment.yaml
+`,
+		"code/base/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: nginx
+          securityContext:
+            privileged: true
+`,
+		"code/overlay/kustomization.yaml": `namePrefix: prod-
+resources:
+  - ../base
+patches:
+  - path: patch.yaml
+`,
+		"code/overlay/patch.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          securityContext:
+            privileged: false
+        - name: sidecar
+          image: busybox
+          securityContext:
+            privileged: true
+`,
+		"code/pod.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: standalone
+spec:
+  containers:
+    - name: standalone
+      image: busybox
+      securityContext:
+        privileged: true
+`,
+	})
+
+	scanner := NewScanner(
+		options.ScannerWithEmbeddedLibraries(true),
+		options.ScannerWithPolicyDirs("policies/"),
+		options.ScannerWithPolicyFilesystem(srcFS),
+	)
+	results, err := scanner.ScanFS(context.TODO(), srcFS, "code")
+	require.NoError(t, err)
+
+	failed := results.GetFailed()
+	require.Len(t, failed, 2)
+
+	var messages []string
+	for _, result := range failed {
+		messages = append(messages, result.Range().GetFilename()+": "+result.Description())
+	}
+	assert.ElementsMatch(t, []string{
+		"code/overlay/kustomization.yaml: Container 'sidecar' of Deployment 'prod-app' should not be privileged",
+		"code/pod.yaml: Container 'standalone' of Pod 'standalone' should not be privileged",
+	}, messages)
+}
+
+func Test_ScanCustomResources(t *testing.T) {
+	crdDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(crdDir, "crd.yaml"), []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: databases.example.com
+spec:
+  group: example.com
+  names:
+    kind: Database
+    plural: databases
+  scope: Namespaced
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required:
+                - engine
+              properties:
+                engine:
+                  type: string
+                  enum:
+                    - postgres
+                    - mysql
+                replicas:
+                  type: integer
+                  minimum: 1
+                storage:
+                  type: object
+                  properties:
+                    size:
+                      type: string
+                labels:
+                  type: object
+                  x-kubernetes-preserve-unknown-fields: true
+`), 0o600))
+
+	srcFS := testutil.CreateFS(t, map[string]string{
+		"code/valid.yaml": `apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: valid
+spec:
+  engine: postgres
+  replicas: 3
+  labels:
+    team: backend
+`,
+		"code/invalid.yaml": `apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: invalid
+spec:
+  engine: oracle
+  replicas: 0
+  storage:
+    size: 10Gi
+    class: fast
+`,
+		"code/unknown.yaml": `apiVersion: example.com/v2
+kind: Database
+metadata:
+  name: unknown
+spec:
+  engine: oracle
+`,
+	})
+
+	scanner := NewScanner(
+		options.ScannerWithEmbeddedLibraries(true),
+		options.ScannerWithEmbeddedPolicies(false),
+		ScannerWithCRDSchemas(crdDir),
+	)
+	results, err := scanner.ScanFS(context.TODO(), srcFS, "code")
+	require.NoError(t, err)
+
+	passed := results.GetPassed()
+	require.Len(t, passed, 1)
+	assert.Equal(t, "code/valid.yaml", passed[0].Range().GetFilename())
+
+	type failure struct {
+		message   string
+		startLine int
+		endLine   int
+	}
+	var failures []failure
+	for _, result := range results.GetFailed() {
+		assert.Equal(t, "CRD001", result.Rule().AVDID)
+		assert.Equal(t, "code/invalid.yaml", result.Range().GetFilename())
+		failures = append(failures, failure{
+			message:   result.Description(),
+			startLine: result.Range().GetStartLine(),
+			endLine:   result.Range().GetEndLine(),
+		})
+	}
+	assert.ElementsMatch(t, []failure{
+		{
+			message:   `Database 'invalid' violates the schema of its CRD: spec.engine: spec.engine must be one of the following: "postgres", "mysql"`,
+			startLine: 6,
+			endLine:   10,
+		},
+		{
+			message:   "Database 'invalid' violates the schema of its CRD: spec.replicas: Must be greater than or equal to 1",
+			startLine: 6,
+			endLine:   10,
+		},
+		{
+			message:   "Database 'invalid' violates the schema of its CRD: spec.storage: Additional property class is not allowed",
+			startLine: 9,
+			endLine:   10,
+		},
+	}, failures)
+}
@@ -162,8 +162,8 @@ func (e *evaluator) EvaluateAll(ctx context.Context) (terraform.Modules, map[str
 			e.debug.Log("Failed to evaluate submodule '%s': %s.", definition.Name, err)
 			continue
 		}
-		// export module outputs
-		e.ctx.Set(outputs, "module", definition.Name)
+		// export module outputs, referenced as component.<name> for stack components
+		e.ctx.Set(outputs, definition.Definition.Type(), definition.Name)
 		modules = append(modules, submodules...)
 		for key, val := range definition.Parser.GetFilesystemMap() {
 			fsMap[key] = val
@@ -227,7 +227,7 @@ func (e *evaluator) expandDynamicBlock(b *terraform.Block) {
 }
 
 func isBlockSupportsForEachMetaArgument(block *terraform.Block) bool {
-	return slices.Contains([]string{"module", "resource", "data", "dynamic"}, block.Type())
+	return slices.Contains([]string{"module", "component", "resource", "data", "dynamic"}, block.Type())
 }
 
 func (e *evaluator) expandBlockForEaches(blocks terraform.Blocks, isDynamic bool) terraform.Blocks {
@@ -355,7 +355,7 @@ func (e *evaluator) copyVariables(from, to *terraform.Block) {
 		fromBase = from.TypeLabel()
 		fromRel = from.NameLabel()
 		toRel = to.NameLabel()
-	case "module":
+	case "module", "component":
 		fromBase = from.Type()
 		fromRel = from.TypeLabel()
 		toRel = to.TypeLabel()
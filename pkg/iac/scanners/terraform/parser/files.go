@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/trivy/pkg/iac/terraform"
+)
+
+const (
+	stackExtension      = ".tfstack.hcl"
+	deploymentExtension = ".tfdeploy.hcl"
+)
+
+var (
+	// Terraform, OpenTofu and Terraform Stacks files in native syntax
+	hclExtensions = []string{".tf", ".tofu", stackExtension, deploymentExtension}
+	// Terraform and OpenTofu files in JSON syntax
+	jsonExtensions = []string{".tf.json", ".tofu.json"}
+)
+
+// IsConfigFile checks if the file is a Terraform, OpenTofu or Terraform Stacks configuration file
+func IsConfigFile(name string) bool {
+	return isHCLFile(name) || isJSONFile(name)
+}
+
+func isHCLFile(name string) bool {
+	return hasSuffix(name, hclExtensions)
+}
+
+func isJSONFile(name string) bool {
+	return hasSuffix(name, jsonExtensions)
+}
+
+func hasSuffix(name string, suffixes []string) bool {
+	return slices.ContainsFunc(suffixes, func(suffix string) bool {
+		return strings.HasSuffix(name, suffix)
+	})
+}
+
+// skipOverriddenFiles removes the Terraform files which have an OpenTofu counterpart.
+// OpenTofu ignores "main.tf" when "main.tofu" exists in the same directory.
+func skipOverriddenFiles(paths []string) []string {
+	return lo.Filter(paths, func(p string, _ int) bool {
+		switch {
+		case strings.HasSuffix(p, ".tf"):
+			return !slices.Contains(paths, strings.TrimSuffix(p, ".tf")+".tofu")
+		case strings.HasSuffix(p, ".tf.json"):
+			return !slices.Contains(paths, strings.TrimSuffix(p, ".tf.json")+".tofu.json")
+		}
+		return true
+	})
+}
+
+// fileContent returns the blocks of the file according to its schema.
+// The Stacks formats are still evolving, so unknown blocks are skipped there instead of failing the whole file.
+func fileContent(file sourceFile) (*hcl.BodyContent, hcl.Diagnostics) {
+	switch {
+	case strings.HasSuffix(file.path, stackExtension):
+		content, _, diagnostics := file.file.Body.PartialContent(terraform.StackSchema)
+		return content, diagnostics
+	case strings.HasSuffix(file.path, deploymentExtension):
+		content, _, diagnostics := file.file.Body.PartialContent(terraform.DeploymentSchema)
+		return content, diagnostics
+	default:
+		return file.file.Body.Content(terraform.Schema)
+	}
+}
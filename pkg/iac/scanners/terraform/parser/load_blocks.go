@@ -14,7 +14,7 @@ import (
 
 func loadBlocksFromFile(file sourceFile, moduleSource string) (hcl.Blocks, []terraform.Ignore, error) {
 	ignores := parseIgnores(file.file.Bytes, file.path, moduleSource)
-	contents, diagnostics := file.file.Body.Content(terraform.Schema)
+	contents, diagnostics := fileContent(file)
 	if diagnostics != nil && diagnostics.HasErrors() {
 		return nil, nil, diagnostics
 	}
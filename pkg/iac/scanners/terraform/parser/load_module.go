@@ -39,7 +39,8 @@ func (e *evaluator) loadModules(ctx context.Context) []*ModuleDefinition {
 
 	var moduleDefinitions []*ModuleDefinition
 
-	expanded := e.expandBlocks(blocks.OfType("module"))
+	// stack components are loaded the same way as modules
+	expanded := e.expandBlocks(append(blocks.OfType("module"), blocks.OfType("component")...))
 
 	var loadErrors []*moduleLoadError
 
@@ -8,7 +8,6 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
@@ -147,8 +146,8 @@ func (p *Parser) Metrics() Metrics {
 func (p *Parser) ParseFile(_ context.Context, fullPath string) error {
 	diskStart := time.Now()
 
-	isJSON := strings.HasSuffix(fullPath, ".tf.json")
-	isHCL := strings.HasSuffix(fullPath, ".tf")
+	isJSON := isJSONFile(fullPath)
+	isHCL := isHCLFile(fullPath)
 	if !isJSON && !isHCL {
 		return nil
 	}
@@ -241,7 +240,7 @@ func (p *Parser) ParseFS(ctx context.Context, dir string) error {
 		paths = append(paths, realPath)
 	}
 	sort.Strings(paths)
-	for _, path := range paths {
+	for _, path := range skipOverriddenFiles(paths) {
 		if err := p.ParseFile(ctx, path); err != nil {
 			if p.stopOnHCLError {
 				return err
@@ -272,10 +271,14 @@ func (p *Parser) EvaluateAll(ctx context.Context) (terraform.Modules, cty.Value,
 	p.metrics.Counts.Blocks = len(blocks)
 
 	var inputVars map[string]cty.Value
-	if p.moduleBlock != nil {
-		inputVars = p.moduleBlock.Values().AsValueMap()
+	switch {
+	case p.moduleBlock != nil:
+		inputVars = moduleInputs(p.moduleBlock)
 		p.debug.Log("Added %d input variables from module definition.", len(inputVars))
-	} else {
+	case len(p.tfvarsPaths) == 0 && len(blocks.OfType("deployment")) > 0:
+		inputVars = deploymentInputs(blocks.OfType("deployment"))
+		p.debug.Log("Added %d variables from stack deployment.", len(inputVars))
+	default:
 		inputVars, err = loadTFVars(p.configsFS, p.tfvarsPaths)
 		if err != nil {
 			return nil, cty.NilVal, err
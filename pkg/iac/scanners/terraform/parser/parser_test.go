@@ -1497,6 +1497,120 @@ resource "test_block" "this" {
 	})
 }
 
+func TestOpenTofuFiles(t *testing.T) {
+	modules := parse(t, map[string]string{
+		"main.tf": `
+resource "aws_s3_bucket" "this" {
+  bucket = "terraform"
+}
+`,
+		"main.tofu": `
+resource "aws_s3_bucket" "this" {
+  bucket = "tofu"
+}
+`,
+		"other.tofu.json": `{
+  "resource": {
+    "aws_s3_bucket": {
+      "other": {
+        "bucket": "other"
+      }
+    }
+  }
+}`,
+	})
+	require.Len(t, modules, 1)
+
+	buckets := modules.GetResourcesByType("aws_s3_bucket")
+	require.Len(t, buckets, 2)
+
+	var names []string
+	for _, bucket := range buckets {
+		names = append(names, bucket.GetAttribute("bucket").Value().AsString())
+	}
+	assert.ElementsMatch(t, []string{"tofu", "other"}, names)
+}
+
+func TestStackComponents(t *testing.T) {
+	fs := testutil.CreateFS(t, map[string]string{
+		"stack/components.tfstack.hcl": `
+required_providers {
+  aws = {
+    source  = "hashicorp/aws"
+    version = "~> 5.0"
+  }
+}
+
+provider "aws" "this" {
+  config {
+    region = var.region
+  }
+}
+
+variable "region" {
+  type = string
+}
+
+variable "bucket_name" {
+  type = string
+}
+
+component "s3" {
+  source = "../modules/s3"
+  inputs = {
+    name = var.bucket_name
+  }
+  providers = {
+    aws = provider.aws.this
+  }
+}
+
+output "bucket" {
+  value = component.s3.bucket
+}
+`,
+		"stack/deployments.tfdeploy.hcl": `
+identity_token "aws" {
+  audience = ["aws.workload.identity"]
+}
+
+deployment "production" {
+  inputs = {
+    region      = "us-east-1"
+    bucket_name = "production-bucket"
+  }
+}
+`,
+		"modules/s3/main.tf": `
+variable "name" {}
+
+resource "aws_s3_bucket" "this" {
+  bucket = var.name
+}
+
+output "bucket" {
+  value = aws_s3_bucket.this.bucket
+}
+`,
+	})
+
+	parser := New(fs, "", OptionStopOnHCLError(true))
+	require.NoError(t, parser.ParseFS(context.TODO(), "stack"))
+
+	modules, outputs, err := parser.EvaluateAll(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, modules, 2)
+
+	components := modules[0].GetBlocks().OfType("component")
+	require.Len(t, components, 1)
+
+	buckets := modules.GetResourcesByType("aws_s3_bucket")
+	require.Len(t, buckets, 1)
+	assert.Equal(t, "production-bucket", buckets[0].GetAttribute("bucket").Value().AsString())
+
+	assert.Equal(t, "production-bucket", outputs.GetAttr("bucket").AsString())
+}
+
 func parse(t *testing.T, files map[string]string) terraform.Modules {
 	fs := testutil.CreateFS(t, files)
 	parser := New(fs, "", OptionStopOnHCLError(true))
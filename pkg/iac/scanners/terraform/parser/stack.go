@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/aquasecurity/trivy/pkg/iac/terraform"
+)
+
+// moduleInputs returns the input variables passed to a module or a stack component
+func moduleInputs(b *terraform.Block) map[string]cty.Value {
+	if b.Type() == terraform.TypeComponent.Name() {
+		return objectValues(b.GetAttribute("inputs"))
+	}
+	return b.Values().AsValueMap()
+}
+
+// deploymentInputs returns the stack variables set by a deployment.
+// Every deployment is an instance of the same stack, so the first one is used as representative.
+func deploymentInputs(deployments terraform.Blocks) map[string]cty.Value {
+	sort.Slice(deployments, func(i, j int) bool {
+		return deployments[i].Label() < deployments[j].Label()
+	})
+	return objectValues(deployments[0].GetAttribute("inputs"))
+}
+
+func objectValues(attr *terraform.Attribute) map[string]cty.Value {
+	val := attr.Value()
+	if !val.Type().IsObjectType() && !val.Type().IsMapType() {
+		return nil
+	}
+	return val.AsValueMap()
+}
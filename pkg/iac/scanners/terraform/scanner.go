@@ -357,7 +357,7 @@ func (s *Scanner) isRootModule(target fs.FS, dir string) bool {
 		return false
 	}
 	for _, file := range files {
-		if strings.HasSuffix(file.Name(), ".tf") || strings.HasSuffix(file.Name(), ".tf.json") {
+		if parser.IsConfigFile(file.Name()) {
 			return true
 		}
 	}
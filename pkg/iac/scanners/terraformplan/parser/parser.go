@@ -67,7 +67,7 @@ func (p *PlanFile) ToFS() (*memoryfs.FS, error) {
 
 	var fileResources []string
 
-	resources, err := getResources(p.PlannedValues.RootModule, p.ResourceChanges, p.Configuration)
+	resources, err := getResources(p.PlannedValues.RootModule, p.ResourceChanges, p.Configuration, p.Variables)
 	if err != nil {
 		return nil, err
 	}
@@ -84,46 +84,86 @@ func (p *PlanFile) ToFS() (*memoryfs.FS, error) {
 
 }
 
-func getResources(module Module, resourceChanges []ResourceChange, configuration Configuration) ([]terraform.PlanBlock, error) {
-	var resources []terraform.PlanBlock
-	for _, r := range module.Resources {
-		resourceName := r.Name
-		if strings.HasPrefix(r.Address, "module.") {
-			hashable := strings.TrimSuffix(strings.Split(r.Address, fmt.Sprintf(".%s.", r.Type))[0], ".data")
-			/* #nosec */
-			hash := fmt.Sprintf("%x", md5.Sum([]byte(hashable)))
-			resourceName = fmt.Sprintf("%s_%s", r.Name, hash)
-		}
+// moduleCall is a single step in the module call tree leading to a resource.
+type moduleCall struct {
+	// name of the module call, without an instance key
+	name string
+	// address of the module instance, e.g. module.a.module.b["x"]
+	address string
+	// configuration of the call, if it could be found
+	config *CallModule
+}
 
-		res := terraform.NewPlanBlock(r.Mode, r.Type, resourceName)
+// moduleScope describes where in the module call tree a configuration expression is evaluated.
+type moduleScope struct {
+	calls     []moduleCall
+	variables map[string]Variable
+}
 
-		changes := getValues(r.Address, resourceChanges)
-		// process the changes to get the after state
-		for k, v := range changes.After {
-			switch t := v.(type) {
-			case []interface{}:
-				if len(t) == 0 {
-					continue
-				}
-				val := t[0]
-				switch v := val.(type) {
-				// is it a HCL block?
-				case map[string]interface{}:
-					res.Blocks[k] = v
-				// just a normal attribute then
+func (s moduleScope) isRoot() bool {
+	return len(s.calls) == 0
+}
+
+func (s moduleScope) current() moduleCall {
+	return s.calls[len(s.calls)-1]
+}
+
+func (s moduleScope) parent() moduleScope {
+	return moduleScope{
+		calls:     s.calls[:len(s.calls)-1],
+		variables: s.variables,
+	}
+}
+
+// blockName returns the name under which a block declared in this scope is rendered.
+// Blocks from child modules get a suffix derived from the module instance address
+// so that identically named resources from different modules do not clash.
+func (s moduleScope) blockName(name string) string {
+	if s.isRoot() {
+		return name
+	}
+	/* #nosec */
+	hash := fmt.Sprintf("%x", md5.Sum([]byte(s.current().address)))
+	return fmt.Sprintf("%s_%s", name, hash)
+}
+
+func getResources(module Module, resourceChanges []ResourceChange, configuration Configuration,
+	variables map[string]Variable) ([]terraform.PlanBlock, error) {
+	var resources []terraform.PlanBlock
+	for _, r := range module.Resources {
+		resourceConfig, scope := getConfiguration(r.Address, configuration.RootModule)
+		scope.variables = variables
+
+		res := terraform.NewPlanBlock(r.Mode, r.Type, scope.blockName(r.Name))
+
+		if changes := getValues(r.Address, resourceChanges); changes != nil {
+			// process the changes to get the after state
+			for k, v := range changes.After {
+				switch t := v.(type) {
+				case []interface{}:
+					if len(t) == 0 {
+						continue
+					}
+					val := t[0]
+					switch v := val.(type) {
+					// is it a HCL block?
+					case map[string]interface{}:
+						res.Blocks[k] = v
+					// just a normal attribute then
+					default:
+						res.Attributes[k] = v
+					}
 				default:
 					res.Attributes[k] = v
 				}
-			default:
-				res.Attributes[k] = v
 			}
+			markUnknownValues(res, changes.AfterUnknown)
 		}
 
-		resourceConfig := getConfiguration(r.Address, configuration.RootModule)
 		if resourceConfig != nil {
-
 			for attr, val := range resourceConfig.Expressions {
-				if value, shouldReplace := unpackConfigurationValue(val, r); shouldReplace || !res.HasAttribute(attr) {
+				value, shouldReplace := unpackConfigurationValue(val, scope)
+				if shouldReplace || !res.HasAttribute(attr) || (isUnknown(res.Attributes[attr]) && value != nil) {
 					res.Attributes[attr] = value
 				}
 			}
@@ -132,7 +172,7 @@ func getResources(module Module, resourceChanges []ResourceChange, configuration
 	}
 
 	for _, m := range module.ChildModules {
-		cr, err := getResources(m.Module, resourceChanges, configuration)
+		cr, err := getResources(m.Module, resourceChanges, configuration, variables)
 		if err != nil {
 			return nil, err
 		}
@@ -142,36 +182,46 @@ func getResources(module Module, resourceChanges []ResourceChange, configuration
 	return resources, nil
 }
 
-func unpackConfigurationValue(val any, r Resource) (any, bool) {
+// markUnknownValues replaces attributes that are unknown until apply with a taint marker,
+// so that they are not mistaken for missing or empty values.
+func markUnknownValues(res *terraform.PlanBlock, afterUnknown map[string]any) {
+	markUnknown(res.Attributes, afterUnknown)
+	for name, block := range res.Blocks {
+		if nested, ok := afterUnknown[name].([]any); ok && len(nested) > 0 {
+			if unknown, ok := nested[0].(map[string]any); ok {
+				markUnknown(block, unknown)
+			}
+		}
+	}
+}
+
+func markUnknown(values, afterUnknown map[string]any) {
+	for k, v := range afterUnknown {
+		if unknown, ok := v.(bool); ok && unknown && values[k] == nil {
+			values[k] = terraform.PlanUnknown{}
+		}
+	}
+}
+
+func isUnknown(val any) bool {
+	_, ok := val.(terraform.PlanUnknown)
+	return ok
+}
+
+func unpackConfigurationValue(val any, scope moduleScope) (any, bool) {
 	if t, ok := val.(map[string]any); ok {
 		for k, v := range t {
 			switch k {
 			case "references":
-				reference := v.([]any)[0].(string)
-				if strings.HasPrefix(r.Address, "module.") {
-					hashable := strings.TrimSuffix(strings.Split(r.Address, fmt.Sprintf(".%s.", r.Type))[0], ".data")
-					/* #nosec */
-					hash := fmt.Sprintf("%x", md5.Sum([]byte(hashable)))
-
-					parts := strings.Split(reference, ".")
-					var rejoin []string
-
-					name := parts[1]
-					remainder := parts[2:]
-					if parts[0] == "data" {
-						rejoin = append(rejoin, parts[:2]...)
-						name = parts[2]
-						remainder = parts[3:]
-					} else {
-						rejoin = append(rejoin, parts[:1]...)
-					}
-
-					rejoin = append(rejoin, fmt.Sprintf("%s_%s", name, hash))
-					rejoin = append(rejoin, remainder...)
-
-					reference = strings.Join(rejoin, ".")
+				references, ok := v.([]any)
+				if !ok || len(references) == 0 {
+					continue
+				}
+				reference, ok := references[0].(string)
+				if !ok {
+					continue
 				}
-				return terraform.PlanReference{Value: reference}, false
+				return resolveReference(reference, scope), false
 			case "constant_value":
 				return v, false
 			}
@@ -181,30 +231,132 @@ func unpackConfigurationValue(val any, r Resource) (any, bool) {
 	return nil, false
 }
 
-func getConfiguration(address string, configuration ConfigurationModule) *ConfigurationResource {
+// resolveReference converts a reference made from within the given scope into a value
+// that can be rendered into the flattened plan configuration.
+func resolveReference(reference string, scope moduleScope) any {
+	parts := splitAddress(reference)
+	if len(parts) < 2 {
+		return terraform.PlanUnknown{}
+	}
 
-	workingAddress := address
-	var moduleParts []string
-	for strings.HasPrefix(workingAddress, "module.") {
-		workingAddressParts := strings.Split(workingAddress, ".")
-		moduleParts = append(moduleParts, workingAddressParts[1])
-		workingAddress = strings.Join(workingAddressParts[2:], ".")
+	switch parts[0] {
+	case "var":
+		return resolveVariable(parts[1], scope)
+	case "local", "module", "each", "count", "path", "terraform", "self":
+		// these cannot be resolved from the plan and are only known after apply
+		return terraform.PlanUnknown{}
+	case "data":
+		if len(parts) < 3 {
+			return terraform.PlanUnknown{}
+		}
+		parts[2] = scope.blockName(trimInstanceKey(parts[2]))
+	default:
+		parts[1] = scope.blockName(trimInstanceKey(parts[1]))
 	}
 
-	workingModule := configuration
-	for _, moduleName := range moduleParts {
-		if module, ok := workingModule.ModuleCalls[moduleName]; ok {
-			workingModule = module.Module
+	return terraform.PlanReference{Value: strings.Join(parts, ".")}
+}
+
+// resolveVariable walks up the module call tree to find the value passed to a module variable.
+func resolveVariable(name string, scope moduleScope) any {
+	if scope.isRoot() {
+		if v, ok := scope.variables[name]; ok && v.Value != nil {
+			return v.Value
 		}
+		return terraform.PlanReference{Value: "var." + name}
 	}
 
+	call := scope.current().config
+	if call == nil {
+		return terraform.PlanUnknown{}
+	}
+
+	expr, ok := call.Expressions[name]
+	if !ok {
+		// the variable default is used, which is not recorded in the plan
+		return nil
+	}
+
+	// expressions passed to a module call are evaluated in the calling module
+	value, _ := unpackConfigurationValue(expr, scope.parent())
+	return value
+}
+
+func getConfiguration(address string, configuration ConfigurationModule) (*ConfigurationResource, moduleScope) {
+
+	var scope moduleScope
+	parts := splitAddress(address)
+
+	workingModule := &configuration
+	var prefix []string
+	for len(parts) > 2 && parts[0] == "module" {
+		prefix = append(prefix, parts[0], parts[1])
+		call := moduleCall{
+			name:    trimInstanceKey(parts[1]),
+			address: strings.Join(prefix, "."),
+		}
+		if workingModule != nil {
+			if module, ok := workingModule.ModuleCalls[call.name]; ok {
+				call.config = &module
+				workingModule = &module.Module
+			} else {
+				workingModule = nil
+			}
+		}
+		scope.calls = append(scope.calls, call)
+		parts = parts[2:]
+	}
+
+	if workingModule == nil {
+		return nil, scope
+	}
+
+	parts[len(parts)-1] = trimInstanceKey(parts[len(parts)-1])
+	workingAddress := strings.Join(parts, ".")
+
 	for _, resource := range workingModule.Resources {
 		if resource.Address == workingAddress {
-			return &resource
+			return &resource, scope
 		}
 	}
 
-	return nil
+	return nil, scope
+}
+
+// splitAddress splits a resource address into its dot-separated parts,
+// keeping instance keys such as ["a.b"] intact.
+func splitAddress(address string) []string {
+	var (
+		parts   []string
+		current strings.Builder
+		depth   int
+		quoted  bool
+	)
+	for i := 0; i < len(address); i++ {
+		c := address[i]
+		switch {
+		case c == '"' && (i == 0 || address[i-1] != '\\'):
+			quoted = !quoted
+		case quoted:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '.' && depth == 0:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteByte(c)
+	}
+	return append(parts, current.String())
+}
+
+func trimInstanceKey(name string) string {
+	if idx := strings.IndexByte(name, '['); idx >= 0 {
+		return name[:idx]
+	}
+	return name
 }
 
 func getValues(address string, resourceChange []ResourceChange) *ResourceChange {
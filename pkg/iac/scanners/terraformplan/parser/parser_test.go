@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const modulePlan = `{
+  "format_version": "1.2",
+  "variables": {
+    "env": { "value": "prod" }
+  },
+  "planned_values": {
+    "root_module": {
+      "child_modules": [
+        {
+          "address": "module.storage[\"logs.v1\"]",
+          "resources": [
+            {
+              "address": "module.storage[\"logs.v1\"].aws_s3_bucket.this[0]",
+              "mode": "managed",
+              "type": "aws_s3_bucket",
+              "name": "this"
+            }
+          ]
+        }
+      ]
+    }
+  },
+  "resource_changes": [
+    {
+      "address": "module.storage[\"logs.v1\"].aws_s3_bucket.this[0]",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "this",
+      "change": {
+        "after": {
+          "force_destroy": false
+        },
+        "after_unknown": {
+          "acl": true,
+          "bucket": true
+        }
+      }
+    }
+  ],
+  "configuration": {
+    "root_module": {
+      "module_calls": {
+        "storage": {
+          "source": "./storage",
+          "expressions": {
+            "name": { "references": ["var.env"] }
+          },
+          "module": {
+            "resources": [
+              {
+                "address": "aws_s3_bucket.this",
+                "mode": "managed",
+                "type": "aws_s3_bucket",
+                "name": "this",
+                "expressions": {
+                  "bucket": { "references": ["var.name"] }
+                }
+              }
+            ]
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestPlanFile_ToFS_ModulesAndUnknownValues(t *testing.T) {
+	planFile, err := New().Parse(strings.NewReader(modulePlan))
+	require.NoError(t, err)
+
+	fsys, err := planFile.ToFS()
+	require.NoError(t, err)
+
+	b, err := fsys.ReadFile("main.tf")
+	require.NoError(t, err)
+	content := string(b)
+
+	// the module variable is resolved through the module call to the root variable
+	assert.Contains(t, content, `bucket = "prod"`)
+	// values only known after apply are rendered as unresolvable references
+	assert.Contains(t, content, "acl = unknown_after_apply")
+}
+
+func Test_splitAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected []string
+	}{
+		{
+			address:  "aws_s3_bucket.this",
+			expected: []string{"aws_s3_bucket", "this"},
+		},
+		{
+			address:  `module.a["x.y"].module.b[0].aws_s3_bucket.this[1]`,
+			expected: []string{"module", `a["x.y"]`, "module", "b[0]", "aws_s3_bucket", "this[1]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			assert.Equal(t, tt.expected, splitAddress(tt.address))
+		})
+	}
+}
+
+func Test_getConfiguration(t *testing.T) {
+	planFile, err := New().Parse(strings.NewReader(modulePlan))
+	require.NoError(t, err)
+
+	resource, scope := getConfiguration(`module.storage["logs.v1"].aws_s3_bucket.this[0]`, planFile.Configuration.RootModule)
+	require.NotNil(t, resource)
+	assert.Equal(t, "aws_s3_bucket.this", resource.Address)
+	require.Len(t, scope.calls, 1)
+	assert.Equal(t, "storage", scope.calls[0].name)
+	assert.Equal(t, `module.storage["logs.v1"]`, scope.calls[0].address)
+}
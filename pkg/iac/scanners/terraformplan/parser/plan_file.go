@@ -21,8 +21,9 @@ type ConfigurationResource struct {
 }
 
 type Change struct {
-	Before map[string]interface{} `json:"before"`
-	After  map[string]interface{} `json:"after"`
+	Before       map[string]interface{} `json:"before"`
+	After        map[string]interface{} `json:"after"`
+	AfterUnknown map[string]interface{} `json:"after_unknown"`
 }
 
 type Module struct {
@@ -41,8 +42,9 @@ type ConfigurationModule struct {
 }
 
 type CallModule struct {
-	Source string              `json:"source"`
-	Module ConfigurationModule `json:"module"`
+	Source      string                 `json:"source"`
+	Expressions map[string]interface{} `json:"expressions"`
+	Module      ConfigurationModule    `json:"module"`
 }
 
 type ConfigurationChildModule struct {
@@ -58,10 +60,15 @@ type Configuration struct {
 	RootModule ConfigurationModule `json:"root_module"`
 }
 
+type Variable struct {
+	Value interface{} `json:"value"`
+}
+
 type PlanFile struct {
-	FormatVersion    string           `json:"format_version"`
-	TerraformVersion string           `json:"terraform_version"`
-	PlannedValues    PlannedValues    `json:"planned_values"`
-	ResourceChanges  []ResourceChange `json:"resource_changes"`
-	Configuration    Configuration    `json:"configuration"`
+	FormatVersion    string              `json:"format_version"`
+	TerraformVersion string              `json:"terraform_version"`
+	Variables        map[string]Variable `json:"variables"`
+	PlannedValues    PlannedValues       `json:"planned_values"`
+	ResourceChanges  []ResourceChange    `json:"resource_changes"`
+	Configuration    Configuration       `json:"configuration"`
 }
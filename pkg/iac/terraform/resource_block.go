@@ -11,6 +11,13 @@ type PlanReference struct {
 	Value interface{}
 }
 
+// PlanUnknown marks a value that will only be known after apply. It is rendered
+// as a reference that can never be resolved, so checks see an unknown value
+// instead of a missing or empty one.
+type PlanUnknown struct{}
+
+const planUnknownReference = "unknown_after_apply"
+
 type PlanBlock struct {
 	Type       string
 	Name       string
@@ -90,6 +97,8 @@ func renderPrimitive(val interface{}) string {
 	switch t := val.(type) {
 	case PlanReference:
 		return fmt.Sprintf("%v", t.Value)
+	case PlanUnknown:
+		return planUnknownReference
 	case string:
 		if strings.Contains(t, "\n") {
 			return fmt.Sprintf(`<<EOF
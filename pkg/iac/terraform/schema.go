@@ -50,3 +50,59 @@ var Schema = &hcl.BodySchema{
 		},
 	},
 }
+
+// StackSchema describes the blocks of a Terraform Stacks configuration file (.tfstack.hcl)
+var StackSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "required_providers",
+		},
+		{
+			Type:       "provider",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type:       "variable",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type: "locals",
+		},
+		{
+			Type:       "output",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type:       "component",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type: "removed",
+		},
+	},
+}
+
+// DeploymentSchema describes the blocks of a Terraform Stacks deployment file (.tfdeploy.hcl)
+var DeploymentSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "deployment",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type:       "identity_token",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type:       "orchestrate",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type:       "store",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type: "locals",
+		},
+	},
+}
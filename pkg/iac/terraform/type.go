@@ -66,8 +66,13 @@ var TypeTerraform = Type{
 	name: "terraform",
 }
 
+var TypeComponent = Type{
+	name: "component",
+}
+
 var ValidTypes = []Type{
 	TypeCheck,
+	TypeComponent,
 	TypeData,
 	TypeImport,
 	TypeLocal,
@@ -30,6 +30,8 @@ type Updater struct {
 	repo           string
 	dbDir          string
 	skip           bool
+	allowStale     bool
+	sharded        bool
 	quiet          bool
 	registryOption ftypes.RegistryOptions
 	once           sync.Once // we need to update java-db once per run
@@ -60,6 +62,12 @@ func (u *Updater) Update() error {
 			return xerrors.Errorf("oci error: %w", err)
 		}
 		if err = a.Download(context.Background(), dbDir, oci.DownloadOption{MediaType: mediaType}); err != nil {
+			// Fall back to the outdated DB in the cache, e.g. from the offline bundle, only if it is explicitly allowed
+			if u.allowStale && meta.Version == db.SchemaVersion {
+				log.Logger.Warnf("Unable to download the Java DB, using the DB downloaded at %s instead: %s",
+					meta.DownloadedAt.Format(time.RFC3339), err)
+				return nil
+			}
 			return xerrors.Errorf("DB download error: %w", err)
 		}
 
@@ -81,11 +89,13 @@ func (u *Updater) Update() error {
 	return nil
 }
 
-func Init(cacheDir, javaDBRepository string, skip, quiet bool, registryOption ftypes.RegistryOptions) {
+func Init(cacheDir, javaDBRepository string, skip, allowStale, sharded, quiet bool, registryOption ftypes.RegistryOptions) {
 	updater = &Updater{
 		repo:           fmt.Sprintf("%s:%d", javaDBRepository, db.SchemaVersion),
 		dbDir:          filepath.Join(cacheDir, "java-db"),
 		skip:           skip,
+		allowStale:     allowStale,
+		sharded:        sharded,
 		quiet:          quiet,
 		registryOption: registryOption,
 	}
@@ -103,18 +113,34 @@ func Update() error {
 	return err
 }
 
+type driver interface {
+	SelectIndexBySha1(sha1 string) (types.Index, error)
+	SelectIndexByArtifactIDAndGroupID(artifactID, groupID string) (types.Index, error)
+	SelectIndexesByArtifactIDAndFileType(artifactID, version string, fileType types.ArchiveType) ([]types.Index, error)
+	Close() error
+}
+
 type DB struct {
-	driver db.DB
+	driver driver
 }
 
 func NewClient() (*DB, error) {
+	if updater == nil {
+		return nil, xerrors.New("Java DB client not initialized")
+	}
+
+	// Only the shards for the JAR files are downloaded on demand
+	if updater.sharded {
+		return &DB{driver: newShardedDriver(updater)}, nil
+	}
+
 	if err := Update(); err != nil {
 		return nil, xerrors.Errorf("Java DB update failed: %s", err)
 	}
 
-	dbc, err := db.New(updater.dbDir)
+	dbc, err := openDB(updater.dbDir)
 	if err != nil {
-		return nil, xerrors.Errorf("Java DB open error: %w", err)
+		return nil, err
 	}
 
 	return &DB{driver: dbc}, nil
@@ -0,0 +1,76 @@
+package javadb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-java-db/pkg/db"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+func TestUpdater_Update(t *testing.T) {
+	stale := &db.Metadata{
+		Version:      db.SchemaVersion,
+		NextUpdate:   time.Now().Add(-time.Hour),
+		DownloadedAt: time.Now().Add(-72 * time.Hour),
+	}
+	tests := []struct {
+		name       string
+		meta       *db.Metadata
+		allowStale bool
+		wantErr    string
+	}{
+		{
+			name:       "fall back to the outdated DB",
+			meta:       stale,
+			allowStale: true,
+		},
+		{
+			name:    "outdated DB not allowed",
+			meta:    stale,
+			wantErr: "DB download error",
+		},
+		{
+			name: "old schema",
+			meta: &db.Metadata{
+				Version:    db.SchemaVersion - 1,
+				NextUpdate: time.Now().Add(-time.Hour),
+			},
+			allowStale: true,
+			wantErr:    "DB download error",
+		},
+		{
+			name:       "no DB",
+			allowStale: true,
+			wantErr:    "DB download error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbDir := filepath.Join(t.TempDir(), "java-db")
+			if tt.meta != nil {
+				metac := db.NewMetadata(dbDir)
+				require.NoError(t, metac.Update(*tt.meta))
+			}
+
+			// The registry is unreachable
+			u := &Updater{
+				repo:           "localhost:1/trivy-java-db:1",
+				dbDir:          dbDir,
+				allowStale:     tt.allowStale,
+				quiet:          true,
+				registryOption: ftypes.RegistryOptions{Insecure: true},
+			}
+			err := u.Update()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
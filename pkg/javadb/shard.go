@@ -0,0 +1,247 @@
+package javadb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-java-db/pkg/db"
+	"github.com/aquasecurity/trivy-java-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/oci"
+)
+
+// Each shard of the Java DB is an OCI artifact tagged "<schema version>-<kind>-<key>" in the Java DB repository,
+// e.g. "ghcr.io/aquasecurity/trivy-java-db:1-group-org.apache", with the same layout as the full DB.
+// The indexes are split by the key of each lookup, as the groupId is not known yet
+// when JAR files are looked up by the SHA-1 digest or the artifactId.
+const (
+	shardGroup    = "group"    // The first two components of the groupId, e.g. "org.apache"
+	shardSHA1     = "sha1"     // The first two hex digits of the SHA-1 digest
+	shardArtifact = "artifact" // The first character of the artifactId
+
+	shardDir = "shards"
+)
+
+var invalidTagChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+func groupShard(groupID string) string {
+	parts := strings.SplitN(groupID, ".", 3)
+	return shardName(shardGroup, strings.Join(parts[:min(len(parts), 2)], "."))
+}
+
+func sha1Shard(sha1 string) string {
+	return shardName(shardSHA1, strings.ToLower(sha1[:min(len(sha1), 2)]))
+}
+
+func artifactShard(artifactID string) string {
+	return shardName(shardArtifact, strings.ToLower(artifactID[:min(len(artifactID), 1)]))
+}
+
+func shardName(kind, key string) string {
+	// Tags are up to 128 characters
+	key = invalidTagChars.ReplaceAllString(key, "_")
+	return fmt.Sprintf("%s-%s", kind, key[:min(len(key), 100)])
+}
+
+type shard struct {
+	once sync.Once
+	db   *db.DB // nil if the shard doesn't exist
+	err  error
+}
+
+// shardedDriver looks up the shard of each query, which is downloaded on the first lookup.
+// When the shard is not available, e.g. in air-gapped environments, the full DB in the cache is used instead,
+// which can be downloaded with '--download-java-db-only' or carried in the offline bundle.
+type shardedDriver struct {
+	updater *Updater
+
+	mu     sync.Mutex
+	shards map[string]*shard
+
+	fullOnce sync.Once
+	full     *db.DB
+	fullErr  error
+}
+
+func newShardedDriver(u *Updater) *shardedDriver {
+	return &shardedDriver{
+		updater: u,
+		shards:  make(map[string]*shard),
+	}
+}
+
+func (d *shardedDriver) SelectIndexBySha1(sha1 string) (types.Index, error) {
+	dbc, err := d.open(sha1Shard(sha1))
+	if err != nil || dbc == nil {
+		return types.Index{}, err
+	}
+	return dbc.SelectIndexBySha1(sha1)
+}
+
+func (d *shardedDriver) SelectIndexByArtifactIDAndGroupID(artifactID, groupID string) (types.Index, error) {
+	dbc, err := d.open(groupShard(groupID))
+	if err != nil || dbc == nil {
+		return types.Index{}, err
+	}
+	return dbc.SelectIndexByArtifactIDAndGroupID(artifactID, groupID)
+}
+
+func (d *shardedDriver) SelectIndexesByArtifactIDAndFileType(artifactID, version string, fileType types.ArchiveType) ([]types.Index, error) {
+	dbc, err := d.open(artifactShard(artifactID))
+	if err != nil || dbc == nil {
+		return nil, err
+	}
+	return dbc.SelectIndexesByArtifactIDAndFileType(artifactID, version, fileType)
+}
+
+func (d *shardedDriver) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var errs error
+	for _, s := range d.shards {
+		if s.db != nil {
+			if err := s.db.Close(); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+	}
+	if d.full != nil {
+		if err := d.full.Close(); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+// open returns the DB of the shard, or nil if the shard doesn't exist in the repository
+func (d *shardedDriver) open(name string) (*db.DB, error) {
+	d.mu.Lock()
+	s, ok := d.shards[name]
+	if !ok {
+		s = &shard{}
+		d.shards[name] = s
+	}
+	d.mu.Unlock()
+
+	s.once.Do(func() {
+		s.db, s.err = d.openShard(name)
+	})
+	return s.db, s.err
+}
+
+func (d *shardedDriver) openShard(name string) (*db.DB, error) {
+	dir := filepath.Join(d.updater.dbDir, shardDir, name)
+	metac := db.NewMetadata(dir)
+	meta, err := metac.Get()
+	cached := err == nil && meta.Version == db.SchemaVersion
+
+	if cached && (d.updater.skip || meta.NextUpdate.After(time.Now().UTC())) {
+		return openDB(dir)
+	} else if d.updater.skip {
+		log.Logger.Debugf("Java DB shard %s is not in the cache", name)
+		return d.fallback(name, xerrors.New("'--skip-java-db-update' is specified"))
+	}
+
+	err = d.download(name, dir)
+	switch {
+	case err == nil:
+		return openDB(dir)
+	case isNotFound(err):
+		// No groupId, SHA-1 digest or artifactId of the shard exists
+		log.Logger.Debugf("Java DB shard %s not found", name)
+		return nil, nil
+	case cached && d.updater.allowStale:
+		log.Logger.Warnf("Unable to download the Java DB shard %s, using the shard downloaded at %s instead: %s",
+			name, meta.DownloadedAt.Format(time.RFC3339), err)
+		return openDB(dir)
+	}
+	return d.fallback(name, err)
+}
+
+func (d *shardedDriver) download(name, dir string) error {
+	log.Logger.Debugf("Downloading the Java DB shard %s...", name)
+	a, err := oci.NewArtifact(fmt.Sprintf("%s-%s", d.updater.repo, name), true, d.updater.registryOption)
+	if err != nil {
+		return xerrors.Errorf("oci error: %w", err)
+	}
+
+	// Download the shard into the temporary directory to keep the old one in case of failure
+	tmpDir := dir + ".tmp"
+	if err = os.RemoveAll(tmpDir); err != nil {
+		return xerrors.Errorf("unable to remove the temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err = a.Download(context.Background(), tmpDir, oci.DownloadOption{MediaType: mediaType}); err != nil {
+		return xerrors.Errorf("DB download error: %w", err)
+	}
+
+	metac := db.NewMetadata(tmpDir)
+	meta, err := metac.Get()
+	if err != nil {
+		return xerrors.Errorf("Java DB metadata error: %w", err)
+	}
+	meta.DownloadedAt = time.Now().UTC()
+	if err = metac.Update(meta); err != nil {
+		return xerrors.Errorf("Java DB metadata update error: %w", err)
+	}
+
+	if err = os.RemoveAll(dir); err != nil {
+		return xerrors.Errorf("unable to remove the old shard: %w", err)
+	}
+	if err = os.Rename(tmpDir, dir); err != nil {
+		return xerrors.Errorf("unable to move the shard: %w", err)
+	}
+	return nil
+}
+
+// fallback opens the full DB in the cache if the shard is not available
+func (d *shardedDriver) fallback(name string, shardErr error) (*db.DB, error) {
+	d.fullOnce.Do(func() {
+		metac := db.NewMetadata(d.updater.dbDir)
+		meta, err := metac.Get()
+		switch {
+		case err != nil:
+			d.fullErr = xerrors.Errorf("no full Java DB in the cache: %w", err)
+			return
+		case meta.Version != db.SchemaVersion:
+			d.fullErr = xerrors.Errorf("the full Java DB in the cache has the old schema version %d", meta.Version)
+			return
+		case !d.updater.skip && !d.updater.allowStale && meta.NextUpdate.Before(time.Now().UTC()):
+			d.fullErr = xerrors.Errorf("the full Java DB in the cache is outdated")
+			return
+		}
+		log.Logger.Infof("Using the full Java DB downloaded at %s", meta.DownloadedAt.Format(time.RFC3339))
+		d.full, d.fullErr = openDB(d.updater.dbDir)
+	})
+	if d.fullErr != nil {
+		return nil, xerrors.Errorf("Java DB shard %s error: %w (fallback error: %s)", name, shardErr, d.fullErr)
+	}
+	return d.full, nil
+}
+
+func openDB(dir string) (*db.DB, error) {
+	dbc, err := db.New(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("Java DB open error: %w", err)
+	}
+	return &dbc, nil
+}
+
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
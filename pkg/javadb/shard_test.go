@@ -0,0 +1,222 @@
+package javadb
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/aquasecurity/trivy-java-db/pkg/db"
+	"github.com/aquasecurity/trivy-java-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+var (
+	commonsIndex = types.Index{
+		GroupID:     "org.apache.commons",
+		ArtifactID:  "commons-lang3",
+		Version:     "3.14.0",
+		SHA1:        []byte{0x1e, 0xd4, 0x71, 0x19, 0x47, 0x13, 0xd9, 0x3d, 0x2f, 0x8b, 0x4a, 0xe0, 0x12, 0x04, 0xa6, 0x58, 0x7e, 0x98, 0x17, 0x2b},
+		ArchiveType: types.JarType,
+	}
+	junitIndex = types.Index{
+		GroupID:     "junit",
+		ArtifactID:  "junit",
+		Version:     "4.13.2",
+		SHA1:        []byte{0x8a, 0xc9, 0xe1, 0x6d, 0x93, 0x3b, 0x6f, 0xb4, 0x3b, 0xc7, 0xf5, 0x76, 0x33, 0x6b, 0x8f, 0x4d, 0x62, 0xee, 0xb6, 0x26},
+		ArchiveType: types.JarType,
+	}
+)
+
+func TestShardName(t *testing.T) {
+	assert.Equal(t, "group-org.apache", groupShard("org.apache.commons"))
+	assert.Equal(t, "group-junit", groupShard("junit"))
+	assert.Equal(t, "group-com.example_app", groupShard("com.example+app.core"))
+	assert.Equal(t, "sha1-1e", sha1Shard("1ED471194713D93D2F8B4AE01204A6587E98172B"))
+	assert.Equal(t, "artifact-c", artifactShard("Commons-lang3"))
+	assert.Equal(t, "artifact-", artifactShard(""))
+}
+
+func TestShardedDriver(t *testing.T) {
+	ts := httptest.NewServer(registry.New())
+	defer ts.Close()
+	repo := strings.TrimPrefix(ts.URL, "http://") + "/trivy-java-db"
+
+	pushShard(t, repo, "group-org.apache", commonsIndex)
+	pushShard(t, repo, "group-junit", junitIndex)
+	pushShard(t, repo, "sha1-1e", commonsIndex)
+	pushShard(t, repo, "artifact-c", commonsIndex)
+
+	t.Run("shards downloaded on demand", func(t *testing.T) {
+		dbDir := filepath.Join(t.TempDir(), "java-db")
+		client := &DB{driver: newShardedDriver(newTestUpdater(repo, dbDir))}
+		defer client.Close()
+
+		ok, err := client.Exists("org.apache.commons", "commons-lang3")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		props, err := client.SearchBySHA1("1ed471194713d93d2f8b4ae01204a6587e98172b")
+		require.NoError(t, err)
+		assert.Equal(t, "org.apache.commons", props.GroupID)
+
+		groupID, err := client.SearchByArtifactID("commons-lang3", "3.14.0")
+		require.NoError(t, err)
+		assert.Equal(t, "org.apache.commons", groupID)
+
+		// The shard doesn't exist in the repository
+		ok, err = client.Exists("com.example", "app")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		// Only the shards for the lookups are cached
+		entries, err := os.ReadDir(filepath.Join(dbDir, shardDir))
+		require.NoError(t, err)
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.Name())
+		}
+		assert.Equal(t, []string{"artifact-c", "group-org.apache", "sha1-1e"}, got)
+	})
+
+	t.Run("cached shard", func(t *testing.T) {
+		dbDir := filepath.Join(t.TempDir(), "java-db")
+		client := &DB{driver: newShardedDriver(newTestUpdater(repo, dbDir))}
+		_, err := client.Exists("junit", "junit")
+		require.NoError(t, err)
+		require.NoError(t, client.Close())
+
+		// The registry is not accessed with the fresh shard in the cache
+		client = &DB{driver: newShardedDriver(newTestUpdater("localhost:1/trivy-java-db:1", dbDir))}
+		defer client.Close()
+		ok, err := client.Exists("junit", "junit")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("outdated shard", func(t *testing.T) {
+		dbDir := filepath.Join(t.TempDir(), "java-db")
+		writeDB(t, filepath.Join(dbDir, shardDir, "group-junit"), time.Now().Add(-time.Hour), junitIndex)
+
+		// The registry is unreachable
+		u := newTestUpdater("localhost:1/trivy-java-db:1", dbDir)
+		client := &DB{driver: newShardedDriver(u)}
+		_, err := client.Exists("junit", "junit")
+		assert.ErrorContains(t, err, "Java DB shard group-junit error")
+		require.NoError(t, client.Close())
+
+		u.allowStale = true
+		client = &DB{driver: newShardedDriver(u)}
+		defer client.Close()
+		ok, err := client.Exists("junit", "junit")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("fall back to the full DB", func(t *testing.T) {
+		dbDir := filepath.Join(t.TempDir(), "java-db")
+		writeDB(t, dbDir, time.Now().Add(time.Hour), junitIndex, commonsIndex)
+
+		// The registry is unreachable, e.g. in air-gapped environments with the offline bundle
+		client := &DB{driver: newShardedDriver(newTestUpdater("localhost:1/trivy-java-db:1", dbDir))}
+		defer client.Close()
+		ok, err := client.Exists("junit", "junit")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		props, err := client.SearchBySHA1("1ed471194713d93d2f8b4ae01204a6587e98172b")
+		require.NoError(t, err)
+		assert.Equal(t, "commons-lang3", props.ArtifactID)
+	})
+
+	t.Run("skip update without the shard in the cache", func(t *testing.T) {
+		u := newTestUpdater(repo, filepath.Join(t.TempDir(), "java-db"))
+		u.skip = true
+		client := &DB{driver: newShardedDriver(u)}
+		defer client.Close()
+
+		_, err := client.Exists("junit", "junit")
+		assert.ErrorContains(t, err, "no full Java DB in the cache")
+	})
+}
+
+func newTestUpdater(repo, dbDir string) *Updater {
+	if !strings.Contains(repo, ":1") {
+		repo += ":1"
+	}
+	return &Updater{
+		repo:           repo,
+		dbDir:          dbDir,
+		sharded:        true,
+		quiet:          true,
+		registryOption: ftypes.RegistryOptions{Insecure: true},
+	}
+}
+
+// writeDB creates the Java DB with the indexes in the directory
+func writeDB(t *testing.T, dir string, nextUpdate time.Time, indexes ...types.Index) {
+	dbc, err := db.New(dir)
+	require.NoError(t, err)
+	require.NoError(t, dbc.Init())
+	require.NoError(t, dbc.InsertIndexes(indexes))
+	require.NoError(t, dbc.Close())
+
+	metac := db.NewMetadata(dir)
+	require.NoError(t, metac.Update(db.Metadata{
+		Version:    db.SchemaVersion,
+		NextUpdate: nextUpdate,
+		UpdatedAt:  time.Now(),
+	}))
+}
+
+// pushShard pushes the shard in the same layout as the full Java DB
+func pushShard(t *testing.T, repo, shard string, indexes ...types.Index) {
+	dir := t.TempDir()
+	writeDB(t, dir, time.Now().Add(time.Hour), indexes...)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, fileName := range []string{"trivy-java.db", "metadata.json"} {
+		b, err := os.ReadFile(filepath.Join(dir, fileName))
+		require.NoError(t, err)
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: fileName,
+			Mode: 0o644,
+			Size: int64(len(b)),
+		}))
+		_, err = tw.Write(b)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer(buf.Bytes(), mediaType),
+		Annotations: map[string]string{
+			"org.opencontainers.image.title": "javadb.tar.gz",
+		},
+	})
+	require.NoError(t, err)
+	img = mutate.MediaType(img, ggcrtypes.OCIManifestSchema1)
+
+	tag, err := name.NewTag(repo + ":1-" + shard)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(tag, img))
+}
@@ -2,11 +2,14 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	k8sArtifacts "github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+	"github.com/aquasecurity/trivy-kubernetes/pkg/jobs"
 	"github.com/aquasecurity/trivy-kubernetes/pkg/k8s"
 	"github.com/aquasecurity/trivy-kubernetes/pkg/trivyk8s"
 	"github.com/aquasecurity/trivy/pkg/flag"
@@ -14,10 +17,10 @@ import (
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
-// clusterRun runs scan on kubernetes cluster
-func clusterRun(ctx context.Context, opts flag.Options, cluster k8s.Cluster) error {
+// clusterArtifacts lists the artifacts to be scanned in the kubernetes cluster
+func clusterArtifacts(ctx context.Context, opts flag.Options, cluster k8s.Cluster) ([]*k8sArtifacts.Artifact, error) {
 	if err := validateReportArguments(opts); err != nil {
-		return err
+		return nil, err
 	}
 	var artifacts []*k8sArtifacts.Artifact
 	var err error
@@ -25,9 +28,19 @@ func clusterRun(ctx context.Context, opts flag.Options, cluster k8s.Cluster) err
 	case types.FormatCycloneDX:
 		artifacts, err = trivyk8s.New(cluster, log.Logger).ListClusterBomInfo(ctx)
 		if err != nil {
-			return xerrors.Errorf("get k8s artifacts with node info error: %w", err)
+			return nil, xerrors.Errorf("get k8s artifacts with node info error: %w", err)
+		}
+		if opts.KBOMNodeComponents {
+			nodeInfo, err := collectNodeComponents(ctx, opts, cluster, artifacts)
+			if err != nil {
+				return nil, xerrors.Errorf("node components collection error: %w", err)
+			}
+			artifacts = append(artifacts, nodeInfo...)
+		}
+	case types.FormatJSON, types.FormatTable, types.FormatHTML:
+		if opts.Format == types.FormatHTML && opts.Compliance.Spec.ID == "" {
+			return nil, xerrors.New(`"--format html" can be used only with "--compliance" for Kubernetes`)
 		}
-	case types.FormatJSON, types.FormatTable:
 		if opts.Scanners.AnyEnabled(types.MisconfigScanner) && slices.Contains(opts.Components, "infra") {
 			artifacts, err = trivyk8s.New(cluster, log.Logger, trivyk8s.WithExcludeOwned(opts.ExcludeOwned)).ListArtifactAndNodeInfo(ctx,
 				trivyk8s.WithScanJobNamespace(opts.NodeCollectorNamespace),
@@ -35,18 +48,66 @@ func clusterRun(ctx context.Context, opts flag.Options, cluster k8s.Cluster) err
 				trivyk8s.WithScanJobImageRef(opts.NodeCollectorImageRef),
 				trivyk8s.WithTolerations(opts.Tolerations))
 			if err != nil {
-				return xerrors.Errorf("get k8s artifacts with node info error: %w", err)
+				return nil, xerrors.Errorf("get k8s artifacts with node info error: %w", err)
 			}
 		} else {
 			artifacts, err = trivyk8s.New(cluster, log.Logger).ListArtifacts(ctx)
 			if err != nil {
-				return xerrors.Errorf("get k8s artifacts error: %w", err)
+				return nil, xerrors.Errorf("get k8s artifacts error: %w", err)
 			}
 		}
 	default:
-		return xerrors.Errorf(`unknown format %q. Use "json" or "table" or "cyclonedx"`, opts.Format)
+		return nil, xerrors.Errorf(`unknown format %q. Use "json" or "table" or "cyclonedx"`, opts.Format)
 	}
 
-	runner := newRunner(opts, cluster.GetCurrentContext())
-	return runner.run(ctx, artifacts)
+	customResources, err := listCustomResources(ctx, opts, cluster, "")
+	if err != nil {
+		return nil, err
+	}
+	return append(artifacts, customResources...), nil
+}
+
+// collectNodeComponents runs the node-collector on the nodes in KBOM to collect node-level components,
+// such as the kernel and CNI plugins, which are not available in the node status.
+func collectNodeComponents(ctx context.Context, opts flag.Options, cluster k8s.Cluster, bomArtifacts []*k8sArtifacts.Artifact) ([]*k8sArtifacts.Artifact, error) {
+	jc := jobs.NewCollector(
+		cluster,
+		jobs.WithTimetout(5*time.Minute),
+		jobs.WithJobTemplateName(jobs.NodeCollectorName),
+		jobs.WithJobNamespace(opts.NodeCollectorNamespace),
+		jobs.WithJobLabels(map[string]string{
+			jobs.TrivyCollectorName: jobs.NodeCollectorName,
+			jobs.TrivyAutoCreated:   "true",
+		}),
+		jobs.WithImageRef(opts.NodeCollectorImageRef),
+		jobs.WithJobTolerations(opts.Tolerations),
+	)
+	defer jc.Cleanup(ctx)
+
+	var nodeInfo []*k8sArtifacts.Artifact
+	for _, artifact := range bomArtifacts {
+		if artifact.Kind != "NodeComponents" {
+			continue
+		}
+		jc.AppendLabels(jobs.WithJobLabels(map[string]string{
+			jobs.TrivyResourceName: artifact.Name,
+			jobs.TrivyResourceKind: "Node",
+		}))
+		output, err := jc.ApplyAndCollect(ctx, artifact.Name)
+		if err != nil {
+			// The KBOM is still generated from the node status
+			log.Logger.Warnf("Unable to collect the node components of %s: %s", artifact.Name, err)
+			continue
+		}
+		var raw map[string]any
+		if err = json.Unmarshal([]byte(output), &raw); err != nil {
+			return nil, xerrors.Errorf("node-collector output decode error: %w", err)
+		}
+		nodeInfo = append(nodeInfo, &k8sArtifacts.Artifact{
+			Kind:        "NodeInfo",
+			Name:        artifact.Name,
+			RawResource: raw,
+		})
+	}
+	return nodeInfo, nil
 }
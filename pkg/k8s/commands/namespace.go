@@ -5,31 +5,37 @@ import (
 
 	"golang.org/x/xerrors"
 
+	k8sArtifacts "github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
 	"github.com/aquasecurity/trivy-kubernetes/pkg/k8s"
 	"github.com/aquasecurity/trivy-kubernetes/pkg/trivyk8s"
 	"github.com/aquasecurity/trivy/pkg/flag"
 	"github.com/aquasecurity/trivy/pkg/log"
 )
 
-// namespaceRun runs scan on kubernetes cluster
-func namespaceRun(ctx context.Context, opts flag.Options, cluster k8s.Cluster) error {
+// namespaceArtifacts lists the artifacts to be scanned in the namespace, or all the namespaces
+func namespaceArtifacts(ctx context.Context, opts flag.Options, cluster k8s.Cluster) ([]*k8sArtifacts.Artifact, error) {
 	if err := validateReportArguments(opts); err != nil {
-		return err
+		return nil, err
 	}
 	var trivyk trivyk8s.TrivyK8S
+	var namespace string
 	if opts.AllNamespaces {
 		trivyk = trivyk8s.New(cluster, log.Logger).AllNamespaces()
 	} else {
-		trivyk = trivyk8s.New(cluster, log.Logger).Namespace(getNamespace(opts, cluster.GetCurrentNamespace()))
+		namespace = getNamespace(opts, cluster.GetCurrentNamespace())
+		trivyk = trivyk8s.New(cluster, log.Logger).Namespace(namespace)
 	}
 
 	artifacts, err := trivyk.ListArtifacts(ctx)
 	if err != nil {
-		return xerrors.Errorf("get k8s artifacts error: %w", err)
+		return nil, xerrors.Errorf("get k8s artifacts error: %w", err)
 	}
 
-	runner := newRunner(opts, cluster.GetCurrentContext())
-	return runner.run(ctx, artifacts)
+	customResources, err := listCustomResources(ctx, opts, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return append(artifacts, customResources...), nil
 }
 
 func getNamespace(opts flag.Options, currentNamespace string) string {
@@ -13,15 +13,13 @@ import (
 	"github.com/aquasecurity/trivy/pkg/log"
 )
 
-// resourceRun runs scan on kubernetes cluster
-func resourceRun(ctx context.Context, args []string, opts flag.Options, cluster k8s.Cluster) error {
+// resourceArtifacts lists the artifacts of the resources to be scanned in the kubernetes cluster
+func resourceArtifacts(ctx context.Context, args []string, opts flag.Options, cluster k8s.Cluster) ([]*artifacts.Artifact, error) {
 	kind, name, err := extractKindAndName(args)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	runner := newRunner(opts, cluster.GetCurrentContext())
-
 	var trivyk trivyk8s.TrivyK8S
 
 	trivyk = trivyk8s.New(cluster, log.Logger, trivyk8s.WithExcludeOwned(opts.ExcludeOwned))
@@ -34,24 +32,19 @@ func resourceRun(ctx context.Context, args []string, opts flag.Options, cluster
 
 	if name == "" { // pods or configmaps etc
 		if err = validateReportArguments(opts); err != nil {
-			return err
-		}
-
-		targets, err := trivyk.Resources(kind).ListArtifacts(ctx)
-		if err != nil {
-			return err
+			return nil, err
 		}
 
-		return runner.run(ctx, targets)
+		return trivyk.Resources(kind).ListArtifacts(ctx)
 	}
 
 	// pod/NAME or pod NAME etc
 	artifact, err := trivyk.GetArtifact(ctx, kind, name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return runner.run(ctx, []*artifacts.Artifact{artifact})
+	return []*artifacts.Artifact{artifact}, nil
 }
 
 func extractKindAndName(args []string) (string, string, error) {
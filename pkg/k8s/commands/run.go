@@ -3,9 +3,19 @@ package commands
 import (
 	"context"
 	"errors"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
 
+	"github.com/samber/lo"
 	"github.com/spf13/viper"
+	"golang.org/x/exp/maps"
 	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd"
 
 	k8sArtifacts "github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
 	"github.com/aquasecurity/trivy-kubernetes/pkg/k8s"
@@ -14,9 +24,12 @@ import (
 	cr "github.com/aquasecurity/trivy/pkg/compliance/report"
 	"github.com/aquasecurity/trivy/pkg/flag"
 	k8sRep "github.com/aquasecurity/trivy/pkg/k8s"
+	"github.com/aquasecurity/trivy/pkg/k8s/incremental"
 	"github.com/aquasecurity/trivy/pkg/k8s/report"
 	"github.com/aquasecurity/trivy/pkg/k8s/scanner"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/parallel"
+	pkgReport "github.com/aquasecurity/trivy/pkg/report"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -27,15 +40,28 @@ const (
 
 // Run runs a k8s scan
 func Run(ctx context.Context, args []string, opts flag.Options) error {
-	cluster, err := k8s.GetCluster(
-		k8s.WithContext(opts.K8sOptions.ClusterContext),
-		k8s.WithKubeConfig(opts.K8sOptions.KubeConfig),
-		k8s.WithBurst(opts.K8sOptions.Burst),
-		k8s.WithQPS(opts.K8sOptions.QPS),
-	)
+	contexts, err := clusterContexts(opts.K8sOptions)
 	if err != nil {
-		return xerrors.Errorf("failed getting k8s cluster: %w", err)
+		return xerrors.Errorf("kube context error: %w", err)
+	}
+	if len(contexts) > 1 {
+		if opts.Format == types.FormatCycloneDX {
+			return xerrors.New("KBOM with CycloneDX format is not supported for multiple clusters")
+		} else if opts.Compliance.Spec.ID != "" {
+			return xerrors.New("compliance reports are not supported for multiple clusters")
+		}
+	}
+	if opts.Format == types.FormatCycloneDX && filtered(opts.K8sOptions) {
+		return xerrors.New("KBOM with CycloneDX format is not supported with namespace, label or owner filters")
+	}
+	if opts.Watch {
+		if len(contexts) > 1 {
+			return xerrors.New(`"--watch" is not supported for multiple clusters`)
+		}
+		// The timeout is applied to each scan as the watch mode keeps running
+		return watch(ctx, args, opts, contexts[0])
 	}
+
 	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
@@ -44,36 +70,210 @@ func Run(ctx context.Context, args []string, opts flag.Options) error {
 			log.Logger.Warn("Increase --timeout value")
 		}
 	}()
+
+	// The artifacts of the clusters are listed in parallel, while the scans share the same cache
+	var targets []target
+	p := parallel.NewPipeline(opts.Parallel, false, contexts, func(ctx context.Context, clusterContext string) (target, error) {
+		return listTarget(ctx, args, opts, clusterContext)
+	}, func(t target) error {
+		targets = append(targets, t)
+		return nil
+	})
+	if err = p.Do(ctx); err != nil {
+		return err
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].cluster < targets[j].cluster
+	})
+
+	err = newRunner(opts).run(ctx, targets)
+	return err
+}
+
+// target represents the artifacts to be scanned in a cluster.
+type target struct {
+	opts        flag.Options // The options with the context and the version of the cluster
+	cluster     string
+	artifacts   []*k8sArtifacts.Artifact
+	criticality map[string]string // The criticality label by namespace
+	filter      report.Filter
+}
+
+func listTarget(ctx context.Context, args []string, opts flag.Options, clusterContext string) (target, error) {
+	cluster, err := getCluster(opts, clusterContext)
+	if err != nil {
+		return target{}, err
+	}
+	opts.K8sOptions.ClusterContext = clusterContext
 	opts.K8sVersion = cluster.GetClusterVersion()
+
+	var artifacts []*k8sArtifacts.Artifact
 	switch args[0] {
 	case clusterArtifact:
-		return clusterRun(ctx, opts, cluster)
+		artifacts, err = clusterArtifacts(ctx, opts, cluster)
 	case allArtifact:
 		if opts.Format == types.FormatCycloneDX {
-			return xerrors.Errorf("KBOM with CycloneDX format is not supported for all namespace scans")
+			return target{}, xerrors.Errorf("KBOM with CycloneDX format is not supported for all namespace scans")
 		}
-		return namespaceRun(ctx, opts, cluster)
+		artifacts, err = namespaceArtifacts(ctx, opts, cluster)
 	default: // resourceArtifact
 		if opts.Format == types.FormatCycloneDX {
-			return xerrors.Errorf("KBOM with CycloneDX format is not supported for resource scans")
+			return target{}, xerrors.Errorf("KBOM with CycloneDX format is not supported for resource scans")
+		}
+		artifacts, err = resourceArtifacts(ctx, args, opts, cluster)
+	}
+	if err != nil {
+		return target{}, err
+	}
+
+	// The label selector is validated with the flag
+	selector, _ := labels.Parse(opts.LabelSelector)
+	return target{
+		opts:        opts,
+		cluster:     cluster.GetCurrentContext(),
+		artifacts:   artifacts,
+		criticality: namespaceCriticality(ctx, cluster, opts.CriticalityLabel),
+		filter: report.Filter{
+			IncludeNamespaces: opts.IncludeNamespaces,
+			ExcludeNamespaces: opts.ExcludeNamespaces,
+			LabelSelector:     selector,
+			Owners:            opts.Owners,
+			Ownership: report.Ownership{
+				Annotation: opts.OwnerAnnotation,
+				Namespaces: namespaceOwners(ctx, cluster, opts.OwnerAnnotation),
+			},
+		},
+	}, nil
+}
+
+func getCluster(opts flag.Options, clusterContext string) (k8s.Cluster, error) {
+	cluster, err := k8s.GetCluster(
+		k8s.WithContext(clusterContext),
+		k8s.WithKubeConfig(opts.K8sOptions.KubeConfig),
+		k8s.WithBurst(opts.K8sOptions.Burst),
+		k8s.WithQPS(opts.K8sOptions.QPS),
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("failed getting k8s cluster: %w", err)
+	}
+	return cluster, nil
+}
+
+// filtered returns whether the resources in the report are filtered by namespace, labels or owner
+func filtered(opts flag.K8sOptions) bool {
+	return len(opts.IncludeNamespaces) > 0 || len(opts.ExcludeNamespaces) > 0 || opts.LabelSelector != "" || len(opts.Owners) > 0
+}
+
+// clusterContexts returns the contexts to be scanned.
+// The glob patterns are expanded with the contexts in the kubeconfig.
+// An empty context is returned if '--context' is not specified so that the current context is used.
+func clusterContexts(opts flag.K8sOptions) ([]string, error) {
+	if len(opts.ClusterContexts) == 0 {
+		return []string{opts.ClusterContext}, nil
+	}
+
+	var kubeContexts []string
+	var contexts []string
+	for _, c := range opts.ClusterContexts {
+		if !strings.ContainsAny(c, "*?[") {
+			contexts = append(contexts, c)
+			continue
+		}
+
+		if kubeContexts == nil {
+			rules := clientcmd.NewDefaultClientConfigLoadingRules()
+			rules.ExplicitPath = opts.KubeConfig
+			config, err := rules.Load()
+			if err != nil {
+				return nil, xerrors.Errorf("failed to load kubeconfig: %w", err)
+			}
+			kubeContexts = maps.Keys(config.Contexts)
+		}
+
+		var matched bool
+		for _, kc := range kubeContexts {
+			if ok, err := path.Match(c, kc); err != nil {
+				return nil, xerrors.Errorf("invalid context pattern %q: %w", c, err)
+			} else if ok {
+				contexts = append(contexts, kc)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, xerrors.Errorf("no context matches %q", c)
 		}
-		return resourceRun(ctx, args, opts, cluster)
 	}
+
+	contexts = lo.Uniq(contexts)
+	sort.Strings(contexts)
+	return contexts, nil
 }
 
 type runner struct {
 	flagOpts flag.Options
-	cluster  string
 }
 
-func newRunner(flagOpts flag.Options, cluster string) *runner {
+func newRunner(flagOpts flag.Options) *runner {
 	return &runner{
 		flagOpts,
-		cluster,
 	}
 }
 
-func (r *runner) run(ctx context.Context, artifacts []*k8sArtifacts.Artifact) error {
+// namespaceCriticality returns the values of the criticality label by namespace.
+// The priorities don't take the criticality into account when namespaces can't be listed, e.g. without the permission.
+func namespaceCriticality(ctx context.Context, cluster k8s.Cluster, label string) map[string]string {
+	if label == "" {
+		return nil
+	}
+	namespaces, err := cluster.GetK8sClientSet().CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: label,
+	})
+	if err != nil {
+		log.Logger.Debugf("Unable to list namespaces for the criticality label: %s", err)
+		return nil
+	}
+
+	criticality := make(map[string]string)
+	for _, ns := range namespaces.Items {
+		criticality[ns.Name] = strings.ToLower(ns.Labels[label])
+	}
+	return criticality
+}
+
+// namespaceOwners returns the values of the owner annotation by namespace.
+// The owners are resolved only from the annotations of the resources when namespaces can't be listed.
+func namespaceOwners(ctx context.Context, cluster k8s.Cluster, annotation string) map[string]string {
+	if annotation == "" {
+		return nil
+	}
+	namespaces, err := cluster.GetK8sClientSet().CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Logger.Debugf("Unable to list namespaces for the owner annotation: %s", err)
+		return nil
+	}
+
+	owners := make(map[string]string)
+	for _, ns := range namespaces.Items {
+		if owner := ns.Annotations[annotation]; owner != "" {
+			owners[ns.Name] = owner
+		}
+	}
+	return owners
+}
+
+// listCustomResources returns the CRDs and the custom resources allowed by '--custom-resources'.
+func listCustomResources(ctx context.Context, opts flag.Options, cluster k8s.Cluster, namespace string) ([]*k8sArtifacts.Artifact, error) {
+	if len(opts.CustomResources) == 0 {
+		return nil, nil
+	}
+	customResources, err := k8sRep.ListCustomResources(ctx, cluster.GetDynamicClient(), opts.CustomResources, namespace)
+	if err != nil {
+		return nil, xerrors.Errorf("get custom resources error: %w", err)
+	}
+	return customResources, nil
+}
+
+func (r *runner) run(ctx context.Context, targets []target) error {
 	runner, err := cmd.NewRunner(ctx, r.flagOpts)
 	if err != nil {
 		if errors.Is(err, cmd.SkipScan) {
@@ -87,8 +287,6 @@ func (r *runner) run(ctx context.Context, artifacts []*k8sArtifacts.Artifact) er
 		}
 	}()
 
-	s := scanner.NewScanner(r.cluster, runner, r.flagOpts)
-
 	// set scanners types by spec
 	if r.flagOpts.Compliance.Spec.ID != "" {
 		scanners, err := r.flagOpts.Compliance.Scanners()
@@ -97,18 +295,153 @@ func (r *runner) run(ctx context.Context, artifacts []*k8sArtifacts.Artifact) er
 		}
 		r.flagOpts.ScanOptions.Scanners = scanners
 	}
-	var rpt report.Report
-	rpt, err = s.Scan(ctx, artifacts)
+
+	var rpts []report.Report
+	for _, t := range targets {
+		t.opts.ScanOptions.Scanners = r.flagOpts.ScanOptions.Scanners
+		if len(targets) > 1 {
+			log.Logger.Infof("Scanning the cluster %q...", t.cluster)
+			t.opts.IncrementalState = clusterStatePath(t.opts.IncrementalState, t.cluster)
+		}
+		rpt, err := r.scan(ctx, runner, t)
+		if err != nil {
+			return xerrors.Errorf("cluster %q: %w", t.cluster, err)
+		}
+		rpts = append(rpts, rpt)
+	}
+
+	// With '--summary', the full report goes only to '--output' and stdout gets the summary
+	if !r.flagOpts.Summary || r.flagOpts.Output != "" {
+		if err = r.writeReport(ctx, rpts); err != nil {
+			return err
+		}
+	}
+	if r.flagOpts.Summary {
+		var results types.Results
+		for _, rpt := range rpts {
+			for _, resource := range rpt.Resources {
+				results = append(results, resource.Results...)
+			}
+		}
+		if err = pkgReport.WriteSummary(ctx, results, r.flagOpts); err != nil {
+			return xerrors.Errorf("unable to write the summary: %w", err)
+		}
+	}
+
+	// The compliance report doesn't fail with '--exit-code'
+	if r.flagOpts.Compliance.Spec.ID != "" {
+		return nil
+	}
+	operation.Exit(r.flagOpts, report.MultiClusterReport{Clusters: rpts}.Failed())
+
+	return nil
+}
+
+// scan scans the artifacts in the cluster.
+func (r *runner) scan(ctx context.Context, runner cmd.Runner, t target) (report.Report, error) {
+	s := scanner.NewScanner(t.cluster, runner, t.opts)
+
+	// Only the changed workloads are scanned in incremental scanning
+	artifacts := t.filter.Apply(t.artifacts)
+	var state *incremental.State
+	var cached []report.Resource
+	if t.opts.IncrementalState != "" && t.opts.Format != types.FormatCycloneDX {
+		var err error
+		if state, err = loadState(t); err != nil {
+			return report.Report{}, xerrors.Errorf("incremental scanning state error: %w", err)
+		}
+		resolveImages := t.opts.Scanners.AnyEnabled(types.VulnerabilityScanner, types.SecretScanner)
+		artifacts, cached = state.Plan(ctx, artifacts, resolveImages)
+	}
+
+	rpt, err := s.Scan(ctx, artifacts)
 	if err != nil {
-		return xerrors.Errorf("k8s scan error: %w", err)
+		return report.Report{}, xerrors.Errorf("k8s scan error: %w", err)
 	}
+	rpt.Resources = append(rpt.Resources, cached...)
+	if state != nil {
+		if err = state.Save(rpt.Resources); err != nil {
+			return report.Report{}, xerrors.Errorf("incremental scanning state error: %w", err)
+		}
+	}
+	report.AssignOwners(rpt.Resources, t.artifacts, t.filter.Ownership)
+	rpt.Priorities = report.Prioritize(rpt.Resources, t.artifacts, t.criticality)
+	return rpt, nil
+}
+
+// clusterStatePath returns the path of the incremental scanning state for the cluster,
+// e.g. "state.json" => "state.prod.json", so that each cluster has its own state.
+func clusterStatePath(statePath, cluster string) string {
+	if statePath == "" {
+		return ""
+	}
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, cluster)
+	ext := filepath.Ext(statePath)
+	return strings.TrimSuffix(statePath, ext) + "." + name + ext
+}
 
+// loadState loads the state of incremental scanning.
+// The state is discarded when the options affecting the results are changed.
+func loadState(t target) (*incremental.State, error) {
+	fingerprint, err := incremental.Fingerprint(
+		t.opts.AppVersion,
+		t.cluster,
+		t.opts.Scanners,
+		t.opts.Severities,
+		t.opts.VulnerabilityOptions,
+		t.opts.Components,
+		t.opts.Compliance.Spec.ID,
+		t.opts.ListAllPkgs,
+		t.opts.IgnoreFile,
+		t.opts.IgnorePolicy,
+		t.opts.PolicyPaths,
+		t.opts.IncludeNamespaces,
+		t.opts.ExcludeNamespaces,
+		t.opts.LabelSelector,
+		t.opts.Owners,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("fingerprint error: %w", err)
+	}
+	return incremental.Load(t.opts.IncrementalState, fingerprint, incremental.RemoteDigestResolver(t.opts.RegistryOpts()))
+}
+
+func (r *runner) writeReport(ctx context.Context, rpts []report.Report) error {
+	if len(r.flagOpts.ExtraOutputs) > 0 {
+		log.Logger.Warnf("Only '--format %s' is written as multiple formats are not supported in Kubernetes scanning", r.flagOpts.Format)
+	}
+	if len(r.flagOpts.ReportHooks) > 0 {
+		log.Logger.Warn("'--report-hook' is ignored as report hooks are not supported in Kubernetes scanning")
+	}
 	output, cleanup, err := r.flagOpts.OutputWriter(ctx)
 	if err != nil {
 		return xerrors.Errorf("failed to create output file: %w", err)
 	}
 	defer cleanup()
 
+	option := report.Option{
+		Format:       r.flagOpts.Format,
+		Report:       r.flagOpts.ReportFormat,
+		Output:       output,
+		Severities:   r.flagOpts.Severities,
+		Components:   r.flagOpts.Components,
+		Scanners:     r.flagOpts.ScanOptions.Scanners,
+		APIVersion:   r.flagOpts.AppVersion,
+		GroupByOwner: r.flagOpts.OwnerAnnotation != "",
+	}
+	if len(rpts) > 1 {
+		if err = k8sRep.WriteClusters(ctx, report.MultiClusterReport{Clusters: rpts}, option); err != nil {
+			return xerrors.Errorf("unable to write results: %w", err)
+		}
+		return nil
+	}
+
+	rpt := rpts[0]
 	if r.flagOpts.Compliance.Spec.ID != "" {
 		var scanResults []types.Results
 		for _, rss := range rpt.Resources {
@@ -125,20 +458,9 @@ func (r *runner) run(ctx context.Context, artifacts []*k8sArtifacts.Artifact) er
 		})
 	}
 
-	if err := k8sRep.Write(ctx, rpt, report.Option{
-		Format:     r.flagOpts.Format,
-		Report:     r.flagOpts.ReportFormat,
-		Output:     output,
-		Severities: r.flagOpts.Severities,
-		Components: r.flagOpts.Components,
-		Scanners:   r.flagOpts.ScanOptions.Scanners,
-		APIVersion: r.flagOpts.AppVersion,
-	}); err != nil {
+	if err = k8sRep.Write(ctx, rpt, option); err != nil {
 		return xerrors.Errorf("unable to write results: %w", err)
 	}
-
-	operation.Exit(r.flagOpts, rpt.Failed())
-
 	return nil
 }
 
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/flag"
+)
+
+const kubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: prod-us
+  context:
+    cluster: prod
+- name: prod-eu
+  context:
+    cluster: prod
+- name: staging
+  context:
+    cluster: prod
+current-context: staging
+`
+
+func Test_clusterContexts(t *testing.T) {
+	kubeConfigPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(kubeConfigPath, []byte(kubeConfig), 0o600))
+
+	tests := []struct {
+		name     string
+		contexts []string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "current context",
+			contexts: nil,
+			want:     []string{""},
+		},
+		{
+			name:     "context",
+			contexts: []string{"staging"},
+			want:     []string{"staging"},
+		},
+		{
+			name:     "glob pattern",
+			contexts: []string{"prod-*"},
+			want: []string{
+				"prod-eu",
+				"prod-us",
+			},
+		},
+		{
+			name: "duplicated contexts",
+			contexts: []string{
+				"prod-*",
+				"staging",
+				"prod-us",
+			},
+			want: []string{
+				"prod-eu",
+				"prod-us",
+				"staging",
+			},
+		},
+		{
+			name:     "no match",
+			contexts: []string{"dev-*"},
+			wantErr:  `no context matches "dev-*"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := clusterContexts(flag.K8sOptions{
+				ClusterContexts: tt.contexts,
+				KubeConfig:      kubeConfigPath,
+			})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_clusterStatePath(t *testing.T) {
+	tests := []struct {
+		name      string
+		statePath string
+		cluster   string
+		want      string
+	}{
+		{
+			name:      "happy path",
+			statePath: "/tmp/state.json",
+			cluster:   "prod",
+			want:      "/tmp/state.prod.json",
+		},
+		{
+			name:      "EKS context",
+			statePath: "/tmp/state.json",
+			cluster:   "arn:aws:eks:us-east-1:123456789012:cluster/prod",
+			want:      "/tmp/state.arn_aws_eks_us-east-1_123456789012_cluster_prod.json",
+		},
+		{
+			name:      "no state",
+			statePath: "",
+			cluster:   "prod",
+			want:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, clusterStatePath(tt.statePath, tt.cluster))
+		})
+	}
+}
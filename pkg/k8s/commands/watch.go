@@ -0,0 +1,383 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	k8sArtifacts "github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+	"github.com/aquasecurity/trivy-kubernetes/pkg/k8s/docker"
+	cmd "github.com/aquasecurity/trivy/pkg/commands/artifact"
+	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/k8s/report"
+	"github.com/aquasecurity/trivy/pkg/k8s/scanner"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/report/webhook"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// maxWatchRetries is the number of times a failed scan of a workload is retried with backoff
+const maxWatchRetries = 5
+
+// watchResources are the workloads scanned in the watch mode
+var watchResources = []schema.GroupVersionResource{
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+	{Group: "apps", Version: "v1", Resource: "daemonsets"},
+	{Group: "apps", Version: "v1", Resource: "replicasets"},
+	{Group: "batch", Version: "v1", Resource: "cronjobs"},
+	{Group: "batch", Version: "v1", Resource: "jobs"},
+	{Version: "v1", Resource: "pods"},
+	{Version: "v1", Resource: "replicationcontrollers"},
+}
+
+// watchOwnerKinds are the kinds of the workloads whose owned resources, e.g. the pods of a ReplicaSet,
+// are scanned through the owners and skipped in the watch mode.
+var watchOwnerKinds = []string{
+	"Deployment",
+	"StatefulSet",
+	"DaemonSet",
+	"ReplicaSet",
+	"CronJob",
+	"Job",
+	"ReplicationController",
+}
+
+// watch keeps scanning the workloads in the cluster as they are created or updated until the command is interrupted.
+func watch(ctx context.Context, args []string, opts flag.Options, clusterContext string) error {
+	if args[0] != clusterArtifact && args[0] != allArtifact {
+		return xerrors.New(`"--watch" is supported only with "cluster" and "all"`)
+	} else if opts.Format == types.FormatCycloneDX || opts.Compliance.Spec.ID != "" {
+		return xerrors.New(`"--watch" doesn't support KBOM and compliance reports`)
+	} else if opts.WebhookURL == "" && !opts.WatchCRs {
+		return xerrors.New(`"--watch" requires "--webhook-url" or "--watch-crs" to publish the results`)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cluster, err := getCluster(opts, clusterContext)
+	if err != nil {
+		return err
+	}
+	opts.K8sOptions.ClusterContext = clusterContext
+	opts.K8sVersion = cluster.GetClusterVersion()
+	opts.Quiet = true // no progress bars for each workload
+
+	runner, err := cmd.NewRunner(ctx, opts)
+	if err != nil {
+		if errors.Is(err, cmd.SkipScan) {
+			return nil
+		}
+		return xerrors.Errorf("init error: %w", err)
+	}
+	defer func() {
+		if err := runner.Close(ctx); err != nil {
+			log.Logger.Errorf("failed to close runner: %s", err)
+		}
+	}()
+
+	var publishers []publisher
+	notifier, err := webhook.NewNotifier(opts.WebhookOpts())
+	if err != nil {
+		return xerrors.Errorf("webhook error: %w", err)
+	} else if notifier != nil {
+		publishers = append(publishers, webhookPublisher{notifier: notifier})
+	}
+	if opts.WatchCRs {
+		publishers = append(publishers, crPublisher{
+			client:  cluster.GetDynamicClient(),
+			version: opts.AppVersion,
+		})
+	}
+
+	var namespace string // All the namespaces
+	if args[0] == allArtifact && !opts.AllNamespaces {
+		namespace = getNamespace(opts, cluster.GetCurrentNamespace())
+	}
+
+	// The label selector is validated with the flag
+	selector, _ := labels.Parse(opts.LabelSelector)
+	filter := report.Filter{
+		IncludeNamespaces: opts.IncludeNamespaces,
+		ExcludeNamespaces: opts.ExcludeNamespaces,
+		LabelSelector:     selector,
+		Owners:            opts.Owners,
+		Ownership: report.Ownership{
+			Annotation: opts.OwnerAnnotation,
+			Namespaces: namespaceOwners(ctx, cluster, opts.OwnerAnnotation),
+		},
+	}
+	s := scanner.NewScanner(cluster.GetCurrentContext(), runner, opts)
+	w := newWatcher(cluster.GetDynamicClient(), namespace, filter, cluster.AuthByResource, func(ctx context.Context, artifact *k8sArtifacts.Artifact) ([]report.Resource, error) {
+		ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+		rpt, err := s.Scan(ctx, []*k8sArtifacts.Artifact{artifact})
+		if err != nil {
+			return nil, err
+		}
+		return rpt.Resources, nil
+	}, publishers)
+
+	log.Logger.Infof("Watching the workloads in the cluster %q...", cluster.GetCurrentContext())
+	return w.run(ctx)
+}
+
+// scanFunc scans the workload and returns the resources of the results
+type scanFunc func(ctx context.Context, artifact *k8sArtifacts.Artifact) ([]report.Resource, error)
+
+// authFunc returns the registry credentials of the workload, e.g. from imagePullSecrets
+type authFunc func(resource unstructured.Unstructured) (map[string]docker.Auth, error)
+
+// watcher scans the workloads through a work queue fed by informers.
+// The events of a workload are coalesced in the queue, and a workload is rescanned only when its images
+// or its spec, i.e. the generation, change so that status updates don't trigger scans.
+type watcher struct {
+	client     dynamic.Interface
+	namespace  string // All the namespaces if empty
+	filter     report.Filter
+	auth       authFunc
+	scan       scanFunc
+	publishers []publisher
+
+	queue   workqueue.RateLimitingInterface
+	listers map[string]cache.GenericLister // by resource
+	scanned map[string]string              // the revision scanned last by workload key
+}
+
+func newWatcher(client dynamic.Interface, namespace string, filter report.Filter, auth authFunc, scan scanFunc, publishers []publisher) *watcher {
+	return &watcher{
+		client:     client,
+		namespace:  namespace,
+		filter:     filter,
+		auth:       auth,
+		scan:       scan,
+		publishers: publishers,
+		queue:      workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		listers:    make(map[string]cache.GenericLister),
+		scanned:    make(map[string]string),
+	}
+}
+
+// run processes the events until the context is canceled.
+// The workloads are scanned one by one as the scanner silences the global logger during scans.
+func (w *watcher) run(ctx context.Context) error {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(w.client, 0, w.namespace, nil)
+	for _, gvr := range watchResources {
+		// Resources which can't be listed, e.g. without the permission, would block the cache sync forever
+		if _, err := w.client.Resource(gvr).Namespace(w.namespace).List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+			log.Logger.Warnf("Unable to watch %s: %s", gvr.Resource, err)
+			continue
+		}
+
+		resource := gvr.Resource
+		informer := factory.ForResource(gvr)
+		if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj any) { w.enqueue(resource, obj) },
+			UpdateFunc: func(_, obj any) { w.enqueue(resource, obj) },
+			DeleteFunc: func(obj any) { w.enqueue(resource, obj) },
+		}); err != nil {
+			return xerrors.Errorf("event handler error: %w", err)
+		}
+		w.listers[resource] = informer.Lister()
+	}
+	if len(w.listers) == 0 {
+		return xerrors.New("no workloads can be watched")
+	}
+
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+	for gvr, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced && ctx.Err() == nil {
+			return xerrors.Errorf("unable to sync %s", gvr.Resource)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		w.queue.ShutDown()
+	}()
+	for w.next(ctx) {
+	}
+	return nil
+}
+
+// enqueue adds the key of the workload, e.g. "deployments/default/web", to the queue
+func (w *watcher) enqueue(resource string, obj any) {
+	if u, ok := obj.(*unstructured.Unstructured); ok && w.owned(u) {
+		return
+	}
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.Logger.Debugf("Unable to get the key of %s: %s", resource, err)
+		return
+	}
+	w.queue.Add(resource + "/" + key)
+}
+
+// owned returns whether the workload is scanned through its owner
+func (w *watcher) owned(obj *unstructured.Unstructured) bool {
+	for _, owner := range obj.GetOwnerReferences() {
+		if slices.Contains(watchOwnerKinds, owner.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *watcher) next(ctx context.Context) bool {
+	item, shutdown := w.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer w.queue.Done(item)
+
+	key := item.(string)
+	if err := w.sync(ctx, key); err != nil && ctx.Err() == nil {
+		if w.queue.NumRequeues(item) < maxWatchRetries {
+			log.Logger.Warnf("Unable to scan %s, retrying: %s", key, err)
+			w.queue.AddRateLimited(item)
+			return true
+		}
+		log.Logger.Errorf("Unable to scan %s: %s", key, err)
+	}
+	w.queue.Forget(item)
+	return true
+}
+
+// sync scans the workload if it's new or changed, and publishes the results
+func (w *watcher) sync(ctx context.Context, key string) error {
+	resource, nsName, _ := strings.Cut(key, "/")
+	namespace, name, err := cache.SplitMetaNamespaceKey(nsName)
+	if err != nil {
+		return xerrors.Errorf("invalid key: %w", err)
+	}
+	obj, err := w.listers[resource].ByNamespace(namespace).Get(name)
+	if k8sErrors.IsNotFound(err) {
+		// The reports are garbage-collected with the workload
+		delete(w.scanned, key)
+		return nil
+	} else if err != nil {
+		return xerrors.Errorf("lister error: %w", err)
+	}
+
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return xerrors.Errorf("unexpected object: %T", obj)
+	}
+	u = u.DeepCopy()
+	auths, err := w.auth(*u)
+	if err != nil {
+		return xerrors.Errorf("registry credentials error: %w", err)
+	}
+	artifact, err := k8sArtifacts.FromResource(*u, auths)
+	if err != nil {
+		return xerrors.Errorf("artifact error: %w", err)
+	}
+	if len(artifact.Images) == 0 || len(w.filter.Apply([]*k8sArtifacts.Artifact{artifact})) == 0 {
+		return nil
+	}
+
+	revision := fmt.Sprintf("%d/%s", u.GetGeneration(), strings.Join(artifact.Images, ","))
+	if w.scanned[key] == revision {
+		return nil
+	}
+
+	log.Logger.Infof("Scanning %s/%s/%s...", artifact.Namespace, artifact.Kind, artifact.Name)
+	resources, err := w.scan(ctx, artifact)
+	if err != nil {
+		return xerrors.Errorf("scan error: %w", err)
+	}
+	report.AssignOwners(resources, []*k8sArtifacts.Artifact{artifact}, w.filter.Ownership)
+
+	for _, p := range w.publishers {
+		if err = p.publish(ctx, artifact, resources); err != nil {
+			return xerrors.Errorf("publish error: %w", err)
+		}
+	}
+	w.scanned[key] = revision
+	return nil
+}
+
+// publisher publishes the results of a workload scanned in the watch mode
+type publisher interface {
+	publish(ctx context.Context, artifact *k8sArtifacts.Artifact, resources []report.Resource) error
+}
+
+// webhookPublisher posts the results of the workload to '--webhook-url'
+type webhookPublisher struct {
+	notifier *webhook.Notifier
+}
+
+func (p webhookPublisher) publish(ctx context.Context, artifact *k8sArtifacts.Artifact, resources []report.Resource) error {
+	rpt := types.Report{
+		ArtifactName: fmt.Sprintf("%s/%s/%s", artifact.Namespace, artifact.Kind, artifact.Name),
+	}
+	for _, r := range resources {
+		rpt.Results = append(rpt.Results, r.Results...)
+	}
+	return p.notifier.Notify(ctx, rpt)
+}
+
+// crPublisher applies the VulnerabilityReport resources of the workload.
+// The reports of the containers removed from the workload are deleted.
+type crPublisher struct {
+	client  dynamic.Interface
+	version string
+}
+
+func (p crPublisher) publish(ctx context.Context, artifact *k8sArtifacts.Artifact, resources []report.Resource) error {
+	client := p.client.Resource(report.VulnerabilityReportGVR).Namespace(artifact.Namespace)
+
+	applied := make(map[string]bool)
+	for _, vr := range report.VulnerabilityReports(artifact, resources, p.version, time.Now()) {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&vr)
+		if err != nil {
+			return xerrors.Errorf("VulnerabilityReport conversion error: %w", err)
+		}
+		if _, err = client.Apply(ctx, vr.Name, &unstructured.Unstructured{Object: obj}, metav1.ApplyOptions{
+			FieldManager: "trivy",
+			Force:        true,
+		}); err != nil {
+			return xerrors.Errorf("unable to apply the VulnerabilityReport %q: %w", vr.Name, err)
+		}
+		applied[vr.Name] = true
+	}
+
+	existing, err := client.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{
+			report.LabelResourceKind: artifact.Kind,
+			report.LabelResourceName: artifact.Name,
+		}).String(),
+	})
+	if err != nil {
+		return xerrors.Errorf("unable to list the VulnerabilityReports: %w", err)
+	}
+	for _, item := range existing.Items {
+		if applied[item.GetName()] {
+			continue
+		}
+		if err = client.Delete(ctx, item.GetName(), metav1.DeleteOptions{}); err != nil && !k8sErrors.IsNotFound(err) {
+			return xerrors.Errorf("unable to delete the VulnerabilityReport %q: %w", item.GetName(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	k8sArtifacts "github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+	"github.com/aquasecurity/trivy-kubernetes/pkg/k8s/docker"
+	"github.com/aquasecurity/trivy/pkg/k8s/report"
+)
+
+type recordingPublisher struct {
+	mu        sync.Mutex
+	published []string
+}
+
+func (p *recordingPublisher) publish(_ context.Context, artifact *k8sArtifacts.Artifact, _ []report.Resource) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published = append(p.published, artifact.Namespace+"/"+artifact.Kind+"/"+artifact.Name+"@"+artifact.Images[0])
+	return nil
+}
+
+func (p *recordingPublisher) get() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string{}, p.published...)
+}
+
+func workload(kind, name, image string, generation int64, owner string) *unstructured.Unstructured {
+	containers := []any{
+		map[string]any{"name": "app", "image": image},
+	}
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "default",
+		},
+	}}
+	switch kind {
+	case "Pod":
+		u.SetAPIVersion("v1")
+		_ = unstructured.SetNestedSlice(u.Object, containers, "spec", "containers")
+	default:
+		u.SetAPIVersion("apps/v1")
+		_ = unstructured.SetNestedSlice(u.Object, containers, "spec", "template", "spec", "containers")
+	}
+	u.SetKind(kind)
+	u.SetGeneration(generation)
+	if owner != "" {
+		u.SetOwnerReferences([]metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: owner, Name: "web-5d4f8"},
+		})
+	}
+	return u
+}
+
+func Test_watcher(t *testing.T) {
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, gvr := range watchResources {
+		listKinds[gvr] = "List"
+	}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		workload("Deployment", "web", "nginx:1.25", 1, ""),
+		workload("Pod", "web-5d4f8-x7k2p", "nginx:1.25", 0, "ReplicaSet"),
+	)
+
+	var mu sync.Mutex
+	var scanned []string
+	scan := func(ctx context.Context, artifact *k8sArtifacts.Artifact) ([]report.Resource, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		scanned = append(scanned, artifact.Kind+"/"+artifact.Name)
+		return nil, nil
+	}
+	auth := func(unstructured.Unstructured) (map[string]docker.Auth, error) {
+		return nil, nil
+	}
+	p := &recordingPublisher{}
+	w := newWatcher(client, "", report.Filter{}, auth, scan, []publisher{p})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- w.run(ctx)
+	}()
+
+	// The owned pod is scanned through its owner
+	require.Eventually(t, func() bool {
+		return len(p.get()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"default/Deployment/web@nginx:1.25"}, p.get())
+
+	// Status updates don't trigger scans
+	status := workload("Deployment", "web", "nginx:1.25", 1, "")
+	_ = unstructured.SetNestedField(status.Object, int64(3), "status", "replicas")
+	_, err := client.Resource(deployments).Namespace("default").Update(ctx, status, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// A new image is scanned
+	_, err = client.Resource(deployments).Namespace("default").Update(ctx,
+		workload("Deployment", "web", "nginx:1.26", 2, ""), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(p.get()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "default/Deployment/web@nginx:1.26", p.get()[1])
+
+	// A bare pod is scanned
+	_, err = client.Resource(pods).Namespace("default").Create(ctx,
+		workload("Pod", "debug", "busybox:1.36", 0, ""), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(p.get()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"Deployment/web", "Deployment/web", "Pod/debug"}, scanned)
+}
@@ -0,0 +1,42 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/aquasecurity/trivy-kubernetes/pkg/k8s"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+var crdResource = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// SaveCRDs fetches the CustomResourceDefinitions of the cluster and saves them to the file,
+// so that custom resources can be validated against their schemas.
+func SaveCRDs(ctx context.Context, cluster k8s.Cluster, path string) error {
+	list, err := cluster.GetDynamicClient().Resource(crdResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return xerrors.Errorf("failed to list CRDs: %w", err)
+	}
+	log.Logger.Debugf("%d CRDs found in the cluster", len(list.Items))
+
+	b, err := list.MarshalJSON()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal CRDs: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return xerrors.Errorf("failed to create the directory: %w", err)
+	}
+	if err = os.WriteFile(path, b, 0o600); err != nil {
+		return xerrors.Errorf("failed to write CRDs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// CRDKind is the kind of the artifacts representing CustomResourceDefinitions.
+const CRDKind = "CustomResourceDefinition"
+
+// ListCustomResources returns the CRDs of the cluster and the custom resources allowed by "group/kind" entries,
+// e.g. "cert-manager.io/Certificate" or "networking.istio.io/*".
+// Custom resources are listed in the namespace, or all the namespaces if the namespace is empty.
+// The CRDs are not listed if the allowlist is empty.
+func ListCustomResources(ctx context.Context, client dynamic.Interface, allowlist []string, namespace string) ([]*artifacts.Artifact, error) {
+	if len(allowlist) == 0 {
+		return nil, nil
+	}
+
+	crds, err := client.Resource(crdResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to list CRDs: %w", err)
+	}
+	log.Logger.Debugf("%d CRDs found in the cluster", len(crds.Items))
+
+	var arts []*artifacts.Artifact
+	for _, crd := range crds.Items {
+		arts = append(arts, toArtifact(crd))
+
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		if !MatchCustomResource(allowlist, group, kind) {
+			continue
+		}
+
+		gvr, namespaced, ok := storageResource(crd)
+		if !ok {
+			log.Logger.Debugf("No storage version found in the CRD %s", crd.GetName())
+			continue
+		}
+
+		var ri dynamic.ResourceInterface = client.Resource(gvr)
+		if namespaced && namespace != "" {
+			ri = client.Resource(gvr).Namespace(namespace)
+		}
+		list, err := ri.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			// e.g. no permission to list the custom resources
+			log.Logger.Warnf("Unable to list %s/%s: %s", group, kind, err)
+			continue
+		}
+		for _, res := range list.Items {
+			arts = append(arts, toArtifact(res))
+		}
+	}
+	return arts, nil
+}
+
+// MatchCustomResource returns whether the "group/kind" entries in the allowlist include the custom resource.
+// The kind "*" matches all the kinds in the group.
+func MatchCustomResource(allowlist []string, group, kind string) bool {
+	for _, entry := range allowlist {
+		g, k, ok := strings.Cut(entry, "/")
+		if !ok || g != group {
+			continue
+		}
+		if k == "*" || strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCustomResource returns whether the artifact is a CRD or a custom resource allowed by the allowlist.
+func IsCustomResource(artifact *artifacts.Artifact, allowlist []string) bool {
+	if len(allowlist) == 0 {
+		return false
+	} else if artifact.Kind == CRDKind {
+		return true
+	}
+	apiVersion, _, _ := unstructured.NestedString(artifact.RawResource, "apiVersion")
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil || gv.Group == "" {
+		return false
+	}
+	return MatchCustomResource(allowlist, gv.Group, artifact.Kind)
+}
+
+// storageResource returns the resource of the CRD in the storage version.
+func storageResource(crd unstructured.Unstructured) (schema.GroupVersionResource, bool, bool) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		ver, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		if storage, _, _ := unstructured.NestedBool(ver, "storage"); !storage {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(ver, "name")
+		return schema.GroupVersionResource{
+			Group:    group,
+			Version:  name,
+			Resource: plural,
+		}, scope == "Namespaced", true
+	}
+	return schema.GroupVersionResource{}, false, false
+}
+
+func toArtifact(res unstructured.Unstructured) *artifacts.Artifact {
+	return &artifacts.Artifact{
+		Namespace:   res.GetNamespace(),
+		Kind:        res.GetKind(),
+		Labels:      res.GetLabels(),
+		Name:        res.GetName(),
+		RawResource: res.Object,
+	}
+}
@@ -0,0 +1,235 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+)
+
+func newCRD(group, kind, plural, scope string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       CRDKind,
+			"metadata": map[string]any{
+				"name": plural + "." + group,
+			},
+			"spec": map[string]any{
+				"group": group,
+				"scope": scope,
+				"names": map[string]any{
+					"kind":   kind,
+					"plural": plural,
+				},
+				"versions": []any{
+					map[string]any{
+						"name":    "v1alpha1",
+						"storage": false,
+					},
+					map[string]any{
+						"name":    "v1",
+						"storage": true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newCustomResource(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]any{
+				"namespace": namespace,
+				"name":      name,
+			},
+		},
+	}
+}
+
+func TestListCustomResources(t *testing.T) {
+	certificates := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	issuers := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "clusterissuers"}
+	gateways := schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1", Resource: "gateways"}
+
+	objects := []runtime.Object{
+		newCRD("cert-manager.io", "Certificate", "certificates", "Namespaced"),
+		newCRD("cert-manager.io", "ClusterIssuer", "clusterissuers", "Cluster"),
+		newCRD("networking.istio.io", "Gateway", "gateways", "Namespaced"),
+		newCustomResource("cert-manager.io/v1", "Certificate", "default", "web"),
+		newCustomResource("cert-manager.io/v1", "Certificate", "prod", "api"),
+		newCustomResource("cert-manager.io/v1", "ClusterIssuer", "", "letsencrypt"),
+		newCustomResource("networking.istio.io/v1", "Gateway", "istio-system", "ingress"),
+	}
+
+	tests := []struct {
+		name      string
+		allowlist []string
+		namespace string
+		want      []string // kind/namespace/name
+	}{
+		{
+			name:      "no allowlist",
+			allowlist: nil,
+			want:      nil,
+		},
+		{
+			name:      "kind",
+			allowlist: []string{"cert-manager.io/Certificate"},
+			want: []string{
+				"CustomResourceDefinition//certificates.cert-manager.io",
+				"Certificate/default/web",
+				"Certificate/prod/api",
+				"CustomResourceDefinition//clusterissuers.cert-manager.io",
+				"CustomResourceDefinition//gateways.networking.istio.io",
+			},
+		},
+		{
+			name:      "wildcard in the namespace",
+			allowlist: []string{"cert-manager.io/*"},
+			namespace: "prod",
+			want: []string{
+				"CustomResourceDefinition//certificates.cert-manager.io",
+				"Certificate/prod/api",
+				"CustomResourceDefinition//clusterissuers.cert-manager.io",
+				"ClusterIssuer//letsencrypt",
+				"CustomResourceDefinition//gateways.networking.istio.io",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				crdResource:  "CustomResourceDefinitionList",
+				certificates: "CertificateList",
+				issuers:      "ClusterIssuerList",
+				gateways:     "GatewayList",
+			}, objects...)
+
+			got, err := ListCustomResources(context.Background(), client, tt.allowlist, tt.namespace)
+			require.NoError(t, err)
+
+			var names []string
+			for _, a := range got {
+				names = append(names, a.Kind+"/"+a.Namespace+"/"+a.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestMatchCustomResource(t *testing.T) {
+	allowlist := []string{
+		"cert-manager.io/Certificate",
+		"networking.istio.io/*",
+	}
+	tests := []struct {
+		name  string
+		group string
+		kind  string
+		want  bool
+	}{
+		{
+			name:  "kind",
+			group: "cert-manager.io",
+			kind:  "Certificate",
+			want:  true,
+		},
+		{
+			name:  "case-insensitive kind",
+			group: "cert-manager.io",
+			kind:  "certificate",
+			want:  true,
+		},
+		{
+			name:  "another kind",
+			group: "cert-manager.io",
+			kind:  "Issuer",
+			want:  false,
+		},
+		{
+			name:  "wildcard",
+			group: "networking.istio.io",
+			kind:  "VirtualService",
+			want:  true,
+		},
+		{
+			name:  "another group",
+			group: "security.istio.io",
+			kind:  "AuthorizationPolicy",
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchCustomResource(allowlist, tt.group, tt.kind))
+		})
+	}
+}
+
+func TestIsCustomResource(t *testing.T) {
+	allowlist := []string{"cert-manager.io/Certificate"}
+	tests := []struct {
+		name      string
+		artifact  *artifacts.Artifact
+		allowlist []string
+		want      bool
+	}{
+		{
+			name: "CRD",
+			artifact: &artifacts.Artifact{
+				Kind: CRDKind,
+				Name: "certificates.cert-manager.io",
+			},
+			allowlist: allowlist,
+			want:      true,
+		},
+		{
+			name: "custom resource",
+			artifact: &artifacts.Artifact{
+				Kind: "Certificate",
+				Name: "web",
+				RawResource: map[string]any{
+					"apiVersion": "cert-manager.io/v1",
+				},
+			},
+			allowlist: allowlist,
+			want:      true,
+		},
+		{
+			name: "core resource",
+			artifact: &artifacts.Artifact{
+				Kind: "Pod",
+				Name: "web",
+				RawResource: map[string]any{
+					"apiVersion": "v1",
+				},
+			},
+			allowlist: allowlist,
+			want:      false,
+		},
+		{
+			name: "no allowlist",
+			artifact: &artifacts.Artifact{
+				Kind: CRDKind,
+				Name: "certificates.cert-manager.io",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsCustomResource(tt.artifact, tt.allowlist))
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package incremental
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/k8s/report"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/remote"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const stateVersion = 1
+
+// DigestResolver returns the current digest of the image used by the artifact.
+type DigestResolver func(ctx context.Context, artifact *artifacts.Artifact, image string) (string, error)
+
+// State records the workloads scanned in the last run so that unchanged workloads are not rescanned.
+//
+// A workload is rescanned when
+//   - it didn't exist in the last run
+//   - its resource version changed, i.e. the manifest was updated
+//   - any of its images points to another digest, e.g. a mutable tag was pushed again
+//   - the scan failed in the last run
+type State struct {
+	Version     int
+	Fingerprint string // The hash of the scan options. The state is discarded if the options change.
+	Workloads   map[string]Workload
+
+	path     string
+	resolver DigestResolver
+	current  map[string]Workload // The workloads in this run without the scan results
+}
+
+// Workload represents a scanned workload and its results.
+type Workload struct {
+	ResourceVersion string
+	Images          map[string]string // digest by image reference
+	Resources       []Resource
+}
+
+// Resource is report.Resource with the original report, which is not marshaled in report.Resource.
+type Resource struct {
+	report.Resource
+	Report types.Report
+}
+
+// Load loads the state from the file. An empty state is returned if the file doesn't exist or the fingerprint differs.
+func Load(path, fingerprint string, resolver DigestResolver) (*State, error) {
+	s := &State{
+		Version:     stateVersion,
+		Fingerprint: fingerprint,
+		Workloads:   make(map[string]Workload),
+		path:        path,
+		resolver:    resolver,
+		current:     make(map[string]Workload),
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Logger.Infof("No incremental scanning state found in %s, all the workloads will be scanned", path)
+		return s, nil
+	} else if err != nil {
+		return nil, xerrors.Errorf("failed to read the state: %w", err)
+	}
+
+	var last State
+	if err = json.Unmarshal(b, &last); err != nil {
+		return nil, xerrors.Errorf("state decode error: %w", err)
+	}
+	if last.Version != stateVersion || last.Fingerprint != fingerprint {
+		log.Logger.Infof("The scan options changed since the last run, all the workloads will be scanned")
+		return s, nil
+	}
+	s.Workloads = last.Workloads
+	return s, nil
+}
+
+// Plan returns the artifacts to be scanned and the results of the unchanged workloads in the last run.
+// Artifacts without the resource version, e.g. node components, are always scanned.
+func (s *State) Plan(ctx context.Context, arts []*artifacts.Artifact, resolveImages bool) ([]*artifacts.Artifact, []report.Resource) {
+	var changed []*artifacts.Artifact
+	var cached []report.Resource
+	for _, artifact := range arts {
+		resourceVersion, _, _ := unstructured.NestedString(artifact.RawResource, "metadata", "resourceVersion")
+		if resourceVersion == "" {
+			changed = append(changed, artifact)
+			continue
+		}
+
+		key := workloadKey(artifact.Namespace, artifact.Kind, artifact.Name)
+		current := Workload{
+			ResourceVersion: resourceVersion,
+			Images:          make(map[string]string),
+		}
+		if resolveImages {
+			for _, image := range artifact.Images {
+				d, err := s.resolveDigest(ctx, artifact, image)
+				if err != nil {
+					// The workload is rescanned as the image may be changed
+					log.Logger.Debugf("Unable to resolve the digest of %s: %s", image, err)
+					continue
+				}
+				current.Images[image] = d
+			}
+		}
+		s.current[key] = current
+
+		if last, ok := s.Workloads[key]; ok && last.unchanged(current, artifact.Images, resolveImages) {
+			log.Logger.Debugf("Skipping the unchanged workload: %s", key)
+			for _, r := range last.Resources {
+				r.Resource.Report = r.Report
+				cached = append(cached, r.Resource)
+			}
+			continue
+		}
+		changed = append(changed, artifact)
+	}
+	log.Logger.Infof("%d unchanged workloads are skipped by incremental scanning", len(arts)-len(changed))
+	return changed, cached
+}
+
+// Save records the workloads in this run with their results and writes the state to the file.
+// The workloads deleted from the cluster are dropped.
+func (s *State) Save(resources []report.Resource) error {
+	workloads := make(map[string]Workload, len(s.current))
+	for key, w := range s.current {
+		w.Resources = []Resource{}
+		workloads[key] = w
+	}
+	for _, r := range resources {
+		key := workloadKey(r.Namespace, r.Kind, r.Name)
+		w, ok := workloads[key]
+		if !ok {
+			continue
+		}
+		w.Resources = append(w.Resources, Resource{
+			Resource: r,
+			Report:   r.Report,
+		})
+		workloads[key] = w
+	}
+	s.Workloads = workloads
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return xerrors.Errorf("state encode error: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return xerrors.Errorf("failed to create the state directory: %w", err)
+	}
+	if err = os.WriteFile(s.path, b, 0o600); err != nil {
+		return xerrors.Errorf("failed to write the state: %w", err)
+	}
+	return nil
+}
+
+func (s *State) resolveDigest(ctx context.Context, artifact *artifacts.Artifact, image string) (string, error) {
+	// The image pinned by the digest never changes
+	if _, d, ok := strings.Cut(image, "@"); ok {
+		return d, nil
+	}
+	return s.resolver(ctx, artifact, image)
+}
+
+func (w Workload) unchanged(current Workload, images []string, resolveImages bool) bool {
+	if w.ResourceVersion != current.ResourceVersion {
+		return false
+	}
+	// Retry the failed scans
+	for _, r := range w.Resources {
+		if r.Error != "" {
+			return false
+		}
+	}
+	if !resolveImages {
+		return true
+	}
+	for _, image := range images {
+		d, ok := current.Images[image]
+		if !ok || d != w.Images[image] {
+			return false
+		}
+	}
+	return true
+}
+
+func workloadKey(namespace, kind, name string) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s/%s", namespace, kind, name))
+}
+
+// Fingerprint returns the hash of the values affecting the scan results, e.g. scanners and severities.
+func Fingerprint(values ...any) (string, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", xerrors.Errorf("json marshal error: %w", err)
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(b)), nil
+}
+
+// RemoteDigestResolver returns the resolver to get the image digests from the registries
+// with the credentials of the workload, e.g. imagePullSecrets.
+func RemoteDigestResolver(opts ftypes.RegistryOptions) DigestResolver {
+	return func(ctx context.Context, artifact *artifacts.Artifact, image string) (string, error) {
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			return "", xerrors.Errorf("image reference parse error: %w", err)
+		}
+		o := opts
+		o.Credentials = append([]ftypes.Credential{}, opts.Credentials...)
+		for _, cred := range artifact.Credentials {
+			o.Credentials = append(o.Credentials, ftypes.Credential{
+				Username: cred.Username,
+				Password: cred.Password,
+			})
+		}
+		desc, err := remote.Get(ctx, ref, o)
+		if err != nil {
+			return "", xerrors.Errorf("failed to get the image descriptor: %w", err)
+		}
+		return desc.Digest.String(), nil
+	}
+}
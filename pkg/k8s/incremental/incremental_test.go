@@ -0,0 +1,145 @@
+package incremental_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+	"github.com/aquasecurity/trivy/pkg/k8s/incremental"
+	"github.com/aquasecurity/trivy/pkg/k8s/report"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func newArtifact(kind, name, resourceVersion string, images ...string) *artifacts.Artifact {
+	return &artifacts.Artifact{
+		Namespace: "default",
+		Kind:      kind,
+		Name:      name,
+		Images:    images,
+		RawResource: map[string]any{
+			"metadata": map[string]any{
+				"name":            name,
+				"resourceVersion": resourceVersion,
+			},
+		},
+	}
+}
+
+func newResource(kind, name, target, scanErr string) report.Resource {
+	results := types.Results{
+		{
+			Target: target,
+		},
+	}
+	return report.Resource{
+		Namespace: "default",
+		Kind:      kind,
+		Name:      name,
+		Results:   results,
+		Error:     scanErr,
+		Report: types.Report{
+			ArtifactName: target,
+			Results:      results,
+		},
+	}
+}
+
+func names(arts []*artifacts.Artifact) []string {
+	var n []string
+	for _, a := range arts {
+		n = append(n, a.Name)
+	}
+	return n
+}
+
+func TestState(t *testing.T) {
+	digests := map[string]string{
+		"nginx:1.25": "sha256:aaa",
+		"redis:7":    "sha256:bbb",
+	}
+	resolver := func(_ context.Context, _ *artifacts.Artifact, image string) (string, error) {
+		d, ok := digests[image]
+		if !ok {
+			return "", xerrors.New("not found")
+		}
+		return d, nil
+	}
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	ctx := context.Background()
+
+	// The first run scans all the workloads
+	arts := []*artifacts.Artifact{
+		newArtifact("Deployment", "web", "100", "nginx:1.25"),
+		newArtifact("Deployment", "cache", "200", "redis:7"),
+		newArtifact("Deployment", "api", "300", "api@sha256:ccc"),
+		newArtifact("Deployment", "private", "400", "private:latest"),
+		newArtifact("Deployment", "failed", "500"),
+		{Kind: "NodeComponents", Name: "node"},
+	}
+	state, err := incremental.Load(path, "fingerprint", resolver)
+	require.NoError(t, err)
+
+	targets, cached := state.Plan(ctx, arts, true)
+	assert.Equal(t, names(arts), names(targets))
+	assert.Empty(t, cached)
+
+	resources := []report.Resource{
+		newResource("Deployment", "web", "nginx:1.25", ""),
+		newResource("Deployment", "cache", "redis:7", ""),
+		newResource("Deployment", "api", "api@sha256:ccc", ""),
+		newResource("Deployment", "private", "private:latest", ""),
+		newResource("Deployment", "failed", "Deployment/failed", "timeout"),
+	}
+	require.NoError(t, state.Save(resources))
+
+	// The second run skips the unchanged workloads
+	digests["redis:7"] = "sha256:ddd"
+	arts = []*artifacts.Artifact{
+		newArtifact("Deployment", "web", "100", "nginx:1.25"),         // unchanged
+		newArtifact("Deployment", "cache", "200", "redis:7"),          // the image is pushed again
+		newArtifact("Deployment", "api", "301", "api@sha256:ccc"),     // the manifest is updated
+		newArtifact("Deployment", "private", "400", "private:latest"), // unable to resolve the digest
+		newArtifact("Deployment", "failed", "500"),                    // failed in the last run
+		newArtifact("Deployment", "new", "600", "nginx:1.25"),         // new workload
+		{Kind: "NodeComponents", Name: "node"},
+	}
+	state, err = incremental.Load(path, "fingerprint", resolver)
+	require.NoError(t, err)
+
+	targets, cached = state.Plan(ctx, arts, true)
+	assert.Equal(t, []string{"cache", "api", "private", "failed", "new", "node"}, names(targets))
+	assert.Equal(t, []report.Resource{newResource("Deployment", "web", "nginx:1.25", "")}, cached)
+
+	// Without the vulnerability scanning, the image digests are not checked
+	state, err = incremental.Load(path, "fingerprint", resolver)
+	require.NoError(t, err)
+
+	targets, _ = state.Plan(ctx, arts, false)
+	assert.Equal(t, []string{"api", "failed", "new", "node"}, names(targets))
+
+	// The state is discarded if the scan options change
+	state, err = incremental.Load(path, "another", resolver)
+	require.NoError(t, err)
+
+	targets, cached = state.Plan(ctx, arts, true)
+	assert.Equal(t, names(arts), names(targets))
+	assert.Empty(t, cached)
+}
+
+func TestFingerprint(t *testing.T) {
+	a, err := incremental.Fingerprint("0.50.0", []string{"vuln"})
+	require.NoError(t, err)
+	b, err := incremental.Fingerprint("0.50.0", []string{"vuln"})
+	require.NoError(t, err)
+	c, err := incremental.Fingerprint("0.50.0", []string{"vuln", "misconfig"})
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
@@ -6,10 +6,11 @@ package k8s
 import (
 	"github.com/google/wire"
 
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/fanal/cache"
 )
 
-func initializeScanK8s(localArtifactCache cache.LocalArtifactCache) *ScanKubernetes {
+func initializeScanK8s(localArtifactCache cache.LocalArtifactCache, online *advisory.OnlineSource) *ScanKubernetes {
 	wire.Build(ScanSuperSet)
 	return &ScanKubernetes{}
 }
@@ -30,7 +30,8 @@ func NewScanKubernetes(s local.Scanner) *ScanKubernetes {
 
 // NewKubenetesScanner is the factory method for scanner
 func NewKubenetesScanner() *ScanKubernetes {
-	return initializeScanK8s(nil)
+	// The core components are not looked up online
+	return initializeScanK8s(nil, nil)
 }
 
 // // Scan scans k8s core components and return it findings
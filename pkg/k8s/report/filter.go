@@ -0,0 +1,137 @@
+package report
+
+import (
+	"path"
+	"sort"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+)
+
+// Ownership resolves the owner teams of resources from an annotation of the resources,
+// which is inherited from their namespaces if not set.
+type Ownership struct {
+	Annotation string
+	Namespaces map[string]string // The owner by namespace
+}
+
+// Owner returns the owner of the artifact, or an empty string if not owned
+func (o Ownership) Owner(artifact *artifacts.Artifact) string {
+	if o.Annotation == "" {
+		return ""
+	}
+	annotations, _, _ := unstructured.NestedStringMap(artifact.RawResource, "metadata", "annotations")
+	if owner := annotations[o.Annotation]; owner != "" {
+		return owner
+	}
+	return o.Namespaces[artifact.Namespace]
+}
+
+// Filter selects the resources to be reported by namespace, labels and owner.
+// Cluster-scoped resources don't match when the namespaces to be included are specified.
+type Filter struct {
+	IncludeNamespaces []string // Glob patterns, e.g. team-*
+	ExcludeNamespaces []string // Glob patterns, e.g. kube-*
+	LabelSelector     labels.Selector
+	Owners            []string
+	Ownership         Ownership
+}
+
+// Apply returns the artifacts matching the filter
+func (f Filter) Apply(allArtifacts []*artifacts.Artifact) []*artifacts.Artifact {
+	return lo.Filter(allArtifacts, func(artifact *artifacts.Artifact, _ int) bool {
+		return f.match(artifact)
+	})
+}
+
+func (f Filter) match(artifact *artifacts.Artifact) bool {
+	if len(f.IncludeNamespaces) > 0 && !matchNamespace(f.IncludeNamespaces, artifact.Namespace) {
+		return false
+	}
+	if matchNamespace(f.ExcludeNamespaces, artifact.Namespace) {
+		return false
+	}
+	if f.LabelSelector != nil && !f.LabelSelector.Matches(artifactLabels(artifact)) {
+		return false
+	}
+	if len(f.Owners) > 0 && !lo.Contains(f.Owners, f.Ownership.Owner(artifact)) {
+		return false
+	}
+	return true
+}
+
+// artifactLabels returns the labels of the artifact.
+// trivy-kubernetes fills in the labels only for nodes, so they are read from the resource otherwise.
+func artifactLabels(artifact *artifacts.Artifact) labels.Set {
+	if artifact.Labels != nil {
+		return artifact.Labels
+	}
+	l, _, _ := unstructured.NestedStringMap(artifact.RawResource, "metadata", "labels")
+	return l
+}
+
+func matchNamespace(patterns []string, namespace string) bool {
+	if namespace == "" {
+		return false
+	}
+	return lo.ContainsBy(patterns, func(pattern string) bool {
+		ok, _ := path.Match(pattern, namespace)
+		return ok
+	})
+}
+
+// AssignOwners sets the owners of the resources, which are resolved from the artifacts
+func AssignOwners(resources []Resource, allArtifacts []*artifacts.Artifact, ownership Ownership) {
+	if ownership.Annotation == "" {
+		return
+	}
+	owners := make(map[string]string)
+	for _, artifact := range allArtifacts {
+		r := Resource{
+			Namespace: artifact.Namespace,
+			Kind:      artifact.Kind,
+			Name:      artifact.Name,
+		}
+		owners[r.fullname()] = ownership.Owner(artifact)
+	}
+	for i := range resources {
+		resources[i].Owner = owners[resources[i].fullname()]
+	}
+}
+
+// OwnerReport represents the resources of an owner in the report
+type OwnerReport struct {
+	Owner  string // Empty for the resources without owners
+	Report Report
+}
+
+// GroupByOwner splits the report by the owners of the resources.
+// The reports are sorted by owner, and the resources without owners come last.
+func (r Report) GroupByOwner() []OwnerReport {
+	groups := lo.GroupBy(r.Resources, func(resource Resource) string {
+		return resource.Owner
+	})
+	owners := lo.Keys(groups)
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i] == "" || owners[j] == "" {
+			return owners[j] == ""
+		}
+		return owners[i] < owners[j]
+	})
+
+	var reports []OwnerReport
+	for _, owner := range owners {
+		reports = append(reports, OwnerReport{
+			Owner: owner,
+			Report: Report{
+				SchemaVersion: r.SchemaVersion,
+				ClusterName:   r.ClusterName,
+				Resources:     groups[owner],
+			},
+		})
+	}
+	return reports
+}
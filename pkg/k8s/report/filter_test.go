@@ -0,0 +1,173 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+)
+
+var filterArtifacts = []*artifacts.Artifact{
+	{
+		Namespace: "team-a",
+		Kind:      "Deployment",
+		Name:      "web",
+		Labels:    map[string]string{"app": "web", "tier": "frontend"},
+	},
+	{
+		Namespace: "team-a",
+		Kind:      "Deployment",
+		Name:      "batch",
+		Labels:    map[string]string{"app": "batch"},
+		RawResource: map[string]any{
+			"metadata": map[string]any{
+				"annotations": map[string]any{
+					"example.com/owner": "data",
+				},
+			},
+		},
+	},
+	{
+		Namespace: "team-b",
+		Kind:      "StatefulSet",
+		Name:      "db",
+		RawResource: map[string]any{
+			"metadata": map[string]any{
+				"labels": map[string]any{
+					"app": "db",
+				},
+			},
+		},
+	},
+	{
+		Namespace: "kube-system",
+		Kind:      "DaemonSet",
+		Name:      "kube-proxy",
+	},
+	{
+		Kind: "ClusterRole",
+		Name: "admin",
+	},
+}
+
+var filterOwnership = Ownership{
+	Annotation: "example.com/owner",
+	Namespaces: map[string]string{
+		"team-a": "shop",
+		"team-b": "payment",
+	},
+}
+
+func TestFilter_Apply(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{
+			name:   "no filter",
+			filter: Filter{},
+			want: []string{
+				"team-a/Deployment/web",
+				"team-a/Deployment/batch",
+				"team-b/StatefulSet/db",
+				"kube-system/DaemonSet/kube-proxy",
+				"/ClusterRole/admin",
+			},
+		},
+		{
+			name: "include namespaces",
+			filter: Filter{
+				IncludeNamespaces: []string{"team-*"},
+			},
+			want: []string{
+				"team-a/Deployment/web",
+				"team-a/Deployment/batch",
+				"team-b/StatefulSet/db",
+			},
+		},
+		{
+			name: "exclude namespaces",
+			filter: Filter{
+				ExcludeNamespaces: []string{"kube-*", "team-b"},
+			},
+			want: []string{
+				"team-a/Deployment/web",
+				"team-a/Deployment/batch",
+				"/ClusterRole/admin",
+			},
+		},
+		{
+			name: "label selector",
+			filter: Filter{
+				LabelSelector: lo.Must(labels.Parse("app in (web,db),tier!=backend")),
+			},
+			want: []string{
+				"team-a/Deployment/web",
+				"team-b/StatefulSet/db",
+			},
+		},
+		{
+			name: "owners",
+			filter: Filter{
+				Owners:    []string{"shop", "payment"},
+				Ownership: filterOwnership,
+			},
+			want: []string{
+				"team-a/Deployment/web",
+				"team-b/StatefulSet/db",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lo.Map(tt.filter.Apply(filterArtifacts), func(a *artifacts.Artifact, _ int) string {
+				return a.Namespace + "/" + a.Kind + "/" + a.Name
+			})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAssignOwners(t *testing.T) {
+	resources := []Resource{
+		vulnResource("team-a", "Deployment", "web", nil, "HIGH"),
+		vulnResource("team-a", "Deployment", "batch", nil, "LOW"),
+		vulnResource("team-b", "StatefulSet", "db", nil, "CRITICAL"),
+		vulnResource("kube-system", "DaemonSet", "kube-proxy", nil, "MEDIUM"),
+	}
+	AssignOwners(resources, filterArtifacts, filterOwnership)
+
+	got := lo.Map(resources, func(r Resource, _ int) string {
+		return r.Owner
+	})
+	assert.Equal(t, []string{"shop", "data", "payment", ""}, got)
+}
+
+func TestReport_GroupByOwner(t *testing.T) {
+	rpt := Report{
+		ClusterName: "test",
+		Resources: []Resource{
+			{Namespace: "kube-system", Kind: "DaemonSet", Name: "kube-proxy"},
+			{Namespace: "team-b", Kind: "StatefulSet", Name: "db", Owner: "payment"},
+			{Namespace: "team-a", Kind: "Deployment", Name: "web", Owner: "shop"},
+			{Namespace: "team-a", Kind: "Deployment", Name: "batch", Owner: "data"},
+			{Namespace: "team-b", Kind: "Deployment", Name: "api", Owner: "payment"},
+		},
+	}
+
+	got := rpt.GroupByOwner()
+	require.Len(t, got, 4)
+	assert.Equal(t, []string{"data", "payment", "shop", ""}, lo.Map(got, func(r OwnerReport, _ int) string {
+		return r.Owner
+	}))
+	assert.Equal(t, "test", got[1].Report.ClusterName)
+	assert.Equal(t, []Resource{
+		{Namespace: "team-b", Kind: "StatefulSet", Name: "db", Owner: "payment"},
+		{Namespace: "team-b", Kind: "Deployment", Name: "api", Owner: "payment"},
+	}, got[1].Report.Resources)
+}
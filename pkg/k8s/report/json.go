@@ -15,23 +15,33 @@ type JSONWriter struct {
 
 // Write writes the results in JSON format
 func (jw JSONWriter) Write(report Report) error {
-	var output []byte
-	var err error
+	switch jw.Report {
+	case AllReport:
+		return jw.write(report)
+	case SummaryReport:
+		return jw.write(report.consolidate())
+	default:
+		return xerrors.Errorf(`report %q not supported. Use "summary" or "all"`, jw.Report)
+	}
+}
 
+// WriteClusters writes the results of multiple clusters in JSON format
+func (jw JSONWriter) WriteClusters(report MultiClusterReport) error {
 	switch jw.Report {
 	case AllReport:
-		output, err = json.MarshalIndent(report, "", "  ")
-		if err != nil {
-			return xerrors.Errorf("failed to write json: %w", err)
-		}
+		return jw.write(report)
 	case SummaryReport:
-		output, err = json.MarshalIndent(report.consolidate(), "", "  ")
-		if err != nil {
-			return xerrors.Errorf("failed to write json: %w", err)
-		}
+		return jw.write(report.consolidate())
 	default:
 		return xerrors.Errorf(`report %q not supported. Use "summary" or "all"`, jw.Report)
 	}
+}
+
+func (jw JSONWriter) write(v any) error {
+	output, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("failed to write json: %w", err)
+	}
 	if _, err = fmt.Fprintln(jw.Output, string(output)); err != nil {
 		return xerrors.Errorf("failed to write json: %w", err)
 	}
@@ -0,0 +1,297 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/aquasecurity/table"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+	pkgReport "github.com/aquasecurity/trivy/pkg/report/table"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Exposure represents how the workload is reachable through Services and Ingresses
+type Exposure string
+
+const (
+	ExposureInternet Exposure = "internet" // LoadBalancer, NodePort or external IPs, or the backend of an Ingress
+	ExposureCluster  Exposure = "cluster"  // Only through ClusterIP Services
+)
+
+// The weights of the priority score, which is the weight of the highest severity multiplied by the factors
+var (
+	severityWeights = map[dbTypes.Severity]float64{
+		dbTypes.SeverityCritical: 10,
+		dbTypes.SeverityHigh:     7,
+		dbTypes.SeverityMedium:   4,
+		dbTypes.SeverityLow:      1,
+	}
+	exposureFactors = map[Exposure]float64{
+		ExposureInternet: 2,
+		ExposureCluster:  1.2,
+	}
+	privilegedFactor   = 1.5
+	criticalityFactors = map[string]float64{
+		"critical": 2,
+		"high":     1.5,
+		"medium":   1,
+		"low":      0.5,
+	}
+)
+
+// Priority represents how urgently the vulnerabilities of a workload should be fixed
+type Priority struct {
+	Namespace       string `json:",omitempty"`
+	Kind            string
+	Name            string
+	Score           float64
+	Vulnerabilities map[string]int // The number of vulnerabilities by severity
+	Exposure        Exposure       `json:",omitempty"`
+	Privileged      bool           `json:",omitempty"` // Privileged containers or host paths
+	Criticality     string         `json:",omitempty"` // The criticality label of the namespace
+	Owner           string         `json:",omitempty"`
+}
+
+// Prioritize ranks the workloads with vulnerabilities by combining the severities with the exposure and privileges
+// of the workloads and the criticality of the namespaces. The artifacts are used to find the Services and Ingresses
+// exposing the workloads, and namespaceCriticality is the criticality label by namespace.
+func Prioritize(resources []Resource, allArtifacts []*artifacts.Artifact, namespaceCriticality map[string]string) []Priority {
+	exposures := newExposureIndex(allArtifacts)
+
+	var priorities []Priority
+	index := make(map[string]int)
+	for _, r := range resources {
+		counts := lo.CountValuesBy(lo.FlatMap(r.Results, func(result types.Result, _ int) []types.DetectedVulnerability {
+			return result.Vulnerabilities
+		}), func(v types.DetectedVulnerability) string {
+			return v.Severity
+		})
+		if len(counts) == 0 {
+			continue
+		}
+
+		// A workload has a resource per image
+		key := r.fullname()
+		i, ok := index[key]
+		if !ok {
+			priorities = append(priorities, Priority{
+				Namespace:       r.Namespace,
+				Kind:            r.Kind,
+				Name:            r.Name,
+				Vulnerabilities: make(map[string]int),
+				Exposure:        exposures.of(r),
+				Criticality:     namespaceCriticality[r.Namespace],
+				Owner:           r.Owner,
+			})
+			i = len(priorities) - 1
+			index[key] = i
+		}
+		p := &priorities[i]
+		for severity, n := range counts {
+			p.Vulnerabilities[severity] += n
+		}
+		if r.Workload != nil && (r.Workload.Privileged || len(r.Workload.HostPaths) > 0) {
+			p.Privileged = true
+		}
+	}
+
+	for i := range priorities {
+		priorities[i].Score = priorities[i].score()
+	}
+	// Ties are broken by the number of vulnerabilities from the highest severity
+	sort.SliceStable(priorities, func(i, j int) bool {
+		pi, pj := priorities[i], priorities[j]
+		if pi.Score != pj.Score {
+			return pi.Score > pj.Score
+		}
+		for _, severity := range []dbTypes.Severity{
+			dbTypes.SeverityCritical,
+			dbTypes.SeverityHigh,
+			dbTypes.SeverityMedium,
+			dbTypes.SeverityLow,
+		} {
+			if ni, nj := pi.Vulnerabilities[severity.String()], pj.Vulnerabilities[severity.String()]; ni != nj {
+				return ni > nj
+			}
+		}
+		return false
+	})
+	return priorities
+}
+
+func (p Priority) score() float64 {
+	var score float64
+	for severity, n := range p.Vulnerabilities {
+		s, err := dbTypes.NewSeverity(severity)
+		if err != nil || n == 0 {
+			continue
+		}
+		score = math.Max(score, severityWeights[s])
+	}
+	if f, ok := exposureFactors[p.Exposure]; ok {
+		score *= f
+	}
+	if p.Privileged {
+		score *= privilegedFactor
+	}
+	if f, ok := criticalityFactors[p.Criticality]; ok {
+		score *= f
+	}
+	return math.Round(score*10) / 10
+}
+
+type exposedService struct {
+	namespace string
+	selector  map[string]string
+	exposure  Exposure
+}
+
+type exposureIndex struct {
+	services  []exposedService
+	podLabels map[string]map[string]string // by the full name of workloads
+}
+
+func newExposureIndex(allArtifacts []*artifacts.Artifact) exposureIndex {
+	// Services routed by Ingresses by namespace
+	ingressBackends := make(map[string]map[string]bool)
+	for _, a := range allArtifacts {
+		if a.Kind != "Ingress" {
+			continue
+		}
+		if ingressBackends[a.Namespace] == nil {
+			ingressBackends[a.Namespace] = make(map[string]bool)
+		}
+		for _, name := range ingressServices(a.RawResource) {
+			ingressBackends[a.Namespace][name] = true
+		}
+	}
+
+	index := exposureIndex{podLabels: make(map[string]map[string]string)}
+	for _, a := range allArtifacts {
+		if labels := podLabels(a.Kind, a.RawResource); labels != nil {
+			index.podLabels[Resource{Namespace: a.Namespace, Kind: a.Kind, Name: a.Name}.fullname()] = labels
+		}
+		if a.Kind != "Service" {
+			continue
+		}
+		selector, _, _ := unstructured.NestedStringMap(a.RawResource, "spec", "selector")
+		if len(selector) == 0 {
+			continue
+		}
+		serviceType, _, _ := unstructured.NestedString(a.RawResource, "spec", "type")
+		externalIPs, _, _ := unstructured.NestedStringSlice(a.RawResource, "spec", "externalIPs")
+
+		exposure := ExposureCluster
+		if serviceType == "LoadBalancer" || serviceType == "NodePort" || len(externalIPs) > 0 ||
+			ingressBackends[a.Namespace][a.Name] {
+			exposure = ExposureInternet
+		}
+		index.services = append(index.services, exposedService{
+			namespace: a.Namespace,
+			selector:  selector,
+			exposure:  exposure,
+		})
+	}
+	return index
+}
+
+// of returns the highest exposure of the Services selecting the pods of the workload
+func (index exposureIndex) of(r Resource) Exposure {
+	labels, ok := index.podLabels[r.fullname()]
+	if !ok {
+		return ""
+	}
+	var exposure Exposure
+	for _, svc := range index.services {
+		if svc.namespace != r.Namespace || !matchLabels(svc.selector, labels) {
+			continue
+		}
+		if svc.exposure == ExposureInternet {
+			return ExposureInternet
+		}
+		exposure = svc.exposure
+	}
+	return exposure
+}
+
+// ingressServices returns the backend Services of the Ingress
+func ingressServices(raw map[string]any) []string {
+	var names []string
+	if name, found, _ := unstructured.NestedString(raw, "spec", "defaultBackend", "service", "name"); found {
+		names = append(names, name)
+	}
+	rules, _, _ := unstructured.NestedSlice(raw, "spec", "rules")
+	for _, r := range rules {
+		rule, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		paths, _, _ := unstructured.NestedSlice(rule, "http", "paths")
+		for _, p := range paths {
+			path, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			if name, found, _ := unstructured.NestedString(path, "backend", "service", "name"); found {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func matchLabels(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// PriorityWriter writes the priorities as a ranked table
+type PriorityWriter struct {
+	Output io.Writer
+}
+
+func (w PriorityWriter) Write(priorities []Priority) error {
+	if len(priorities) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w.Output, "\nFix Priority"); err != nil {
+		return xerrors.Errorf("failed to write priority title: %w", err)
+	}
+
+	t := table.New(w.Output)
+	t.SetRowLines(false)
+	t.SetHeaders("Rank", NamespaceColumn, ResourceColumn, "Score", VulnerabilitiesColumn, "Exposure", "Privileged", "Criticality")
+	for i, p := range priorities {
+		var counts []string
+		for _, severity := range []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"} {
+			if n := p.Vulnerabilities[severity]; n > 0 {
+				counts = append(counts, pkgReport.ColorizeSeverity(fmt.Sprintf("%s:%d", severity[:1], n), severity))
+			}
+		}
+		t.AddRow(
+			strconv.Itoa(i+1),
+			p.Namespace,
+			fmt.Sprintf("%s/%s", p.Kind, p.Name),
+			strconv.FormatFloat(p.Score, 'f', -1, 64),
+			strings.Join(counts, " "),
+			string(p.Exposure),
+			lo.Ternary(p.Privileged, "yes", ""),
+			p.Criticality,
+		)
+	}
+	t.Render()
+	return nil
+}
@@ -0,0 +1,186 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func vulnResource(namespace, kind, name string, workload *Workload, severities ...string) Resource {
+	var vulns []types.DetectedVulnerability
+	for _, s := range severities {
+		vulns = append(vulns, types.DetectedVulnerability{
+			VulnerabilityID: "CVE-2024-" + s,
+			Vulnerability: dbTypes.Vulnerability{
+				Severity: s,
+			},
+		})
+	}
+	return Resource{
+		Namespace: namespace,
+		Kind:      kind,
+		Name:      name,
+		Workload:  workload,
+		Results: types.Results{
+			{
+				Target:          "image",
+				Class:           types.ClassOSPkg,
+				Vulnerabilities: vulns,
+			},
+		},
+	}
+}
+
+func TestPrioritize(t *testing.T) {
+	podTemplate := func(labels map[string]any) map[string]any {
+		return map[string]any{
+			"spec": map[string]any{
+				"template": map[string]any{
+					"metadata": map[string]any{
+						"labels": labels,
+					},
+				},
+			},
+		}
+	}
+	allArtifacts := []*artifacts.Artifact{
+		{
+			Namespace:   "prod",
+			Kind:        "Deployment",
+			Name:        "web",
+			RawResource: podTemplate(map[string]any{"app": "web"}),
+		},
+		{
+			Namespace:   "prod",
+			Kind:        "Deployment",
+			Name:        "worker",
+			RawResource: podTemplate(map[string]any{"app": "worker"}),
+		},
+		{
+			Namespace:   "dev",
+			Kind:        "Deployment",
+			Name:        "web",
+			RawResource: podTemplate(map[string]any{"app": "web"}),
+		},
+		{
+			Namespace: "prod",
+			Kind:      "Service",
+			Name:      "web",
+			RawResource: map[string]any{
+				"spec": map[string]any{
+					"type":     "ClusterIP",
+					"selector": map[string]any{"app": "web"},
+				},
+			},
+		},
+		{
+			Namespace: "prod",
+			Kind:      "Service",
+			Name:      "worker",
+			RawResource: map[string]any{
+				"spec": map[string]any{
+					"selector": map[string]any{"app": "worker"},
+				},
+			},
+		},
+		{
+			Namespace: "prod",
+			Kind:      "Ingress",
+			Name:      "web",
+			RawResource: map[string]any{
+				"spec": map[string]any{
+					"rules": []any{
+						map[string]any{
+							"http": map[string]any{
+								"paths": []any{
+									map[string]any{
+										"backend": map[string]any{
+											"service": map[string]any{"name": "web"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resources := []Resource{
+		vulnResource("dev", "Deployment", "web", nil, "HIGH"),
+		vulnResource("prod", "Deployment", "worker", &Workload{}, "MEDIUM"),
+		vulnResource("prod", "Deployment", "web", &Workload{HostPaths: []string{"/"}}, "CRITICAL"),
+		vulnResource("prod", "Deployment", "web", &Workload{}, "HIGH", "HIGH"),
+		// Misconfigurations
+		{
+			Namespace: "prod",
+			Kind:      "Service",
+			Name:      "web",
+		},
+	}
+
+	got := Prioritize(resources, allArtifacts, map[string]string{"prod": "critical"})
+	want := []Priority{
+		{
+			Namespace: "prod",
+			Kind:      "Deployment",
+			Name:      "web",
+			Score:     60, // 10 (CRITICAL) * 2 (internet) * 1.5 (privileged) * 2 (critical)
+			Vulnerabilities: map[string]int{
+				"CRITICAL": 1,
+				"HIGH":     2,
+			},
+			Exposure:    ExposureInternet,
+			Privileged:  true,
+			Criticality: "critical",
+		},
+		{
+			Namespace: "prod",
+			Kind:      "Deployment",
+			Name:      "worker",
+			Score:     9.6, // 4 (MEDIUM) * 1.2 (cluster) * 2 (critical)
+			Vulnerabilities: map[string]int{
+				"MEDIUM": 1,
+			},
+			Exposure:    ExposureCluster,
+			Criticality: "critical",
+		},
+		{
+			Namespace: "dev",
+			Kind:      "Deployment",
+			Name:      "web",
+			Score:     7,
+			Vulnerabilities: map[string]int{
+				"HIGH": 1,
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestPriorityWriter_Write(t *testing.T) {
+	var buf bytes.Buffer
+	err := PriorityWriter{Output: &buf}.Write([]Priority{
+		{
+			Namespace:       "prod",
+			Kind:            "Deployment",
+			Name:            "web",
+			Score:           60,
+			Vulnerabilities: map[string]int{"CRITICAL": 1},
+			Exposure:        ExposureInternet,
+			Privileged:      true,
+			Criticality:     "critical",
+		},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "Fix Priority")
+	assert.Contains(t, buf.String(), "Deployment/web")
+	assert.Contains(t, buf.String(), "internet")
+}
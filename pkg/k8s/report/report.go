@@ -34,6 +34,7 @@ type Option struct {
 	Scanners      types.Scanners
 	Components    []string
 	APIVersion    string
+	GroupByOwner  bool // Whether the summary report is grouped by the owners of the resources
 }
 
 // Report represents a kubernetes scan report
@@ -41,6 +42,7 @@ type Report struct {
 	SchemaVersion int `json:",omitempty"`
 	ClusterName   string
 	Resources     []Resource      `json:",omitempty"`
+	Priorities    []Priority      `json:",omitempty"` // The workloads with vulnerabilities in the order to be fixed
 	RootComponent *core.Component `json:"-"`
 	name          string
 }
@@ -50,6 +52,19 @@ type ConsolidatedReport struct {
 	SchemaVersion int `json:",omitempty"`
 	ClusterName   string
 	Findings      []Resource `json:",omitempty"`
+	Priorities    []Priority `json:",omitempty"`
+}
+
+// MultiClusterReport represents the reports of multiple clusters scanned at once
+type MultiClusterReport struct {
+	SchemaVersion int `json:",omitempty"`
+	Clusters      []Report
+}
+
+// MultiClusterConsolidatedReport represents the consolidated reports of multiple clusters
+type MultiClusterConsolidatedReport struct {
+	SchemaVersion int `json:",omitempty"`
+	Clusters      []ConsolidatedReport
 }
 
 // Resource represents a kubernetes resource report
@@ -57,7 +72,9 @@ type Resource struct {
 	Namespace string `json:",omitempty"`
 	Kind      string
 	Name      string
+	Owner     string         `json:",omitempty"` // The owner team resolved from '--owner-annotation'
 	Metadata  types.Metadata `json:",omitempty"`
+	Workload  *Workload      `json:",omitempty"`
 	Results   types.Results  `json:",omitempty"`
 	Error     string         `json:",omitempty"`
 
@@ -79,10 +96,31 @@ func (r Report) Failed() bool {
 	return false
 }
 
+// Failed returns whether any of the cluster reports includes vulnerabilities or misconfigurations
+func (r MultiClusterReport) Failed() bool {
+	for _, c := range r.Clusters {
+		if c.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r MultiClusterReport) consolidate() MultiClusterConsolidatedReport {
+	consolidated := MultiClusterConsolidatedReport{
+		SchemaVersion: r.SchemaVersion,
+	}
+	for _, c := range r.Clusters {
+		consolidated.Clusters = append(consolidated.Clusters, c.consolidate())
+	}
+	return consolidated
+}
+
 func (r Report) consolidate() ConsolidatedReport {
 	consolidated := ConsolidatedReport{
 		SchemaVersion: r.SchemaVersion,
 		ClusterName:   r.ClusterName,
+		Priorities:    r.Priorities,
 	}
 
 	index := make(map[string]Resource)
@@ -103,7 +141,9 @@ func (r Report) consolidate() ConsolidatedReport {
 				Namespace: res.Namespace,
 				Kind:      res.Kind,
 				Name:      res.Name,
+				Owner:     res.Owner,
 				Metadata:  res.Metadata,
+				Workload:  res.Workload,
 				Results:   append(res.Results, v.Results...),
 				Error:     res.Error,
 			}
@@ -242,6 +282,7 @@ func createK8sResource(artifact *artifacts.Artifact, scanResults types.Results)
 		Kind:      artifact.Kind,
 		Name:      artifact.Name,
 		Metadata:  types.Metadata{},
+		Workload:  newWorkload(artifact.Kind, artifact.RawResource),
 		Results:   results,
 		Report: types.Report{
 			Results:      results,
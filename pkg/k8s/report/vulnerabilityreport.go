@@ -0,0 +1,237 @@
+package report
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// The labels of VulnerabilityReport resources pointing to the workloads and the containers, as in trivy-operator
+const (
+	LabelResourceKind      = "trivy-operator.resource.kind"
+	LabelResourceName      = "trivy-operator.resource.name"
+	LabelResourceNamespace = "trivy-operator.resource.namespace"
+	LabelContainerName     = "trivy-operator.container.name"
+)
+
+// VulnerabilityReportGVR is the resource of the VulnerabilityReport CRD of trivy-operator
+var VulnerabilityReportGVR = schema.GroupVersionResource{
+	Group:    "aquasecurity.github.io",
+	Version:  "v1alpha1",
+	Resource: "vulnerabilityreports",
+}
+
+// VulnerabilityReport is the custom resource of trivy-operator with the vulnerabilities of a container image,
+// so that the dashboards and the tools built for trivy-operator can consume the results.
+type VulnerabilityReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Report            VulnerabilityReportData `json:"report"`
+}
+
+type VulnerabilityReportData struct {
+	UpdateTimestamp metav1.Time                 `json:"updateTimestamp"`
+	Scanner         VulnerabilityReportScanner  `json:"scanner"`
+	Registry        VulnerabilityReportRegistry `json:"registry"`
+	Artifact        VulnerabilityReportArtifact `json:"artifact"`
+	OS              VulnerabilityReportOS       `json:"os,omitempty"`
+	Summary         VulnerabilitySummary        `json:"summary"`
+	Vulnerabilities []Vulnerability             `json:"vulnerabilities"`
+}
+
+type VulnerabilityReportScanner struct {
+	Name    string `json:"name"`
+	Vendor  string `json:"vendor"`
+	Version string `json:"version"`
+}
+
+type VulnerabilityReportRegistry struct {
+	Server string `json:"server"`
+}
+
+type VulnerabilityReportArtifact struct {
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+}
+
+type VulnerabilityReportOS struct {
+	Family string `json:"family,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+type VulnerabilitySummary struct {
+	CriticalCount int `json:"criticalCount"`
+	HighCount     int `json:"highCount"`
+	MediumCount   int `json:"mediumCount"`
+	LowCount      int `json:"lowCount"`
+	UnknownCount  int `json:"unknownCount"`
+}
+
+type Vulnerability struct {
+	VulnerabilityID  string   `json:"vulnerabilityID"`
+	Resource         string   `json:"resource"`
+	InstalledVersion string   `json:"installedVersion"`
+	FixedVersion     string   `json:"fixedVersion"`
+	Severity         string   `json:"severity"`
+	Title            string   `json:"title"`
+	PrimaryLink      string   `json:"primaryLink,omitempty"`
+	Links            []string `json:"links"`
+	Score            *float64 `json:"score,omitempty"`
+	Target           string   `json:"target"`
+}
+
+// VulnerabilityReports converts the image scan results of the workload into VulnerabilityReport resources,
+// one per container as trivy-operator does. The resources are owned by the workload to be garbage-collected with it.
+func VulnerabilityReports(artifact *artifacts.Artifact, resources []Resource, version string, now time.Time) []VulnerabilityReport {
+	workload := unstructured.Unstructured{Object: artifact.RawResource}
+	containers := containerNames(artifact)
+
+	var reports []VulnerabilityReport
+	for _, resource := range resources {
+		if resource.Report.ArtifactType != ftypes.ArtifactContainerImage {
+			continue
+		}
+		image := resource.Report.ArtifactName
+		data := vulnerabilityReportData(resource.Report, version, now)
+		for _, container := range containers[image] {
+			reports = append(reports, VulnerabilityReport{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: VulnerabilityReportGVR.GroupVersion().String(),
+					Kind:       "VulnerabilityReport",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      vulnerabilityReportName(artifact.Kind, artifact.Name, container),
+					Namespace: artifact.Namespace,
+					Labels: map[string]string{
+						LabelResourceKind:      artifact.Kind,
+						LabelResourceName:      artifact.Name,
+						LabelResourceNamespace: artifact.Namespace,
+						LabelContainerName:     container,
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: workload.GetAPIVersion(),
+							Kind:       artifact.Kind,
+							Name:       artifact.Name,
+							UID:        workload.GetUID(),
+						},
+					},
+				},
+				Report: data,
+			})
+		}
+	}
+	return reports
+}
+
+func vulnerabilityReportData(r types.Report, version string, now time.Time) VulnerabilityReportData {
+	data := VulnerabilityReportData{
+		UpdateTimestamp: metav1.NewTime(now),
+		Scanner: VulnerabilityReportScanner{
+			Name:    "Trivy",
+			Vendor:  "Aqua Security",
+			Version: version,
+		},
+		Vulnerabilities: []Vulnerability{},
+	}
+	if ref, err := name.ParseReference(r.ArtifactName); err == nil {
+		data.Registry.Server = ref.Context().RegistryStr()
+		data.Artifact.Repository = ref.Context().RepositoryStr()
+		if tag, ok := ref.(name.Tag); ok {
+			data.Artifact.Tag = tag.TagStr()
+		}
+	}
+	if len(r.Metadata.RepoDigests) > 0 {
+		_, data.Artifact.Digest, _ = strings.Cut(r.Metadata.RepoDigests[0], "@")
+	}
+	if r.Metadata.OS != nil {
+		data.OS = VulnerabilityReportOS{
+			Family: string(r.Metadata.OS.Family),
+			Name:   r.Metadata.OS.Name,
+		}
+	}
+
+	for _, result := range r.Results {
+		for _, v := range result.Vulnerabilities {
+			vuln := Vulnerability{
+				VulnerabilityID:  v.VulnerabilityID,
+				Resource:         v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         v.Severity,
+				Title:            v.Title,
+				PrimaryLink:      v.PrimaryURL,
+				Links:            append([]string{}, v.References...),
+				Target:           result.Target,
+			}
+			if cvss, ok := v.CVSS[v.SeveritySource]; ok && cvss.V3Score > 0 {
+				vuln.Score = &cvss.V3Score
+			}
+			data.Vulnerabilities = append(data.Vulnerabilities, vuln)
+
+			switch v.Severity {
+			case dbTypes.SeverityCritical.String():
+				data.Summary.CriticalCount++
+			case dbTypes.SeverityHigh.String():
+				data.Summary.HighCount++
+			case dbTypes.SeverityMedium.String():
+				data.Summary.MediumCount++
+			case dbTypes.SeverityLow.String():
+				data.Summary.LowCount++
+			default:
+				data.Summary.UnknownCount++
+			}
+		}
+	}
+	return data
+}
+
+// vulnerabilityReportName returns the name of the report, e.g. "deployment-web-app".
+// Long names are shortened with the hash so that they are valid as label values as well.
+func vulnerabilityReportName(kind, resourceName, container string) string {
+	n := strings.ToLower(fmt.Sprintf("%s-%s-%s", kind, resourceName, container))
+	if len(n) <= 63 {
+		return n
+	}
+	return fmt.Sprintf("%s-%x", strings.ToLower(kind), sha256.Sum256([]byte(n)))[:63]
+}
+
+// containerNames returns the names of the containers by image in the workload
+func containerNames(artifact *artifacts.Artifact) map[string][]string {
+	var keys []string
+	switch artifact.Kind {
+	case "Pod":
+		keys = []string{"spec"}
+	case "CronJob":
+		keys = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		keys = []string{"spec", "template", "spec"}
+	}
+
+	names := make(map[string][]string)
+	for _, containerType := range []string{"initContainers", "containers", "ephemeralContainers"} {
+		containers, _, _ := unstructured.NestedSlice(artifact.RawResource, append(keys, containerType)...)
+		for _, c := range containers {
+			container, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			image, _, _ := unstructured.NestedString(container, "image")
+			containerName, _, _ := unstructured.NestedString(container, "name")
+			names[image] = append(names[image], containerName)
+		}
+	}
+	return names
+}
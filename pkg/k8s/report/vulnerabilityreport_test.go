@@ -0,0 +1,185 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestVulnerabilityReports(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	artifact := &artifacts.Artifact{
+		Namespace: "default",
+		Kind:      "Deployment",
+		Name:      "web",
+		Images:    []string{"nginx:1.25", "ghcr.io/org/sidecar:2.0"},
+		RawResource: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":      "web",
+				"namespace": "default",
+				"uid":       "0b7d1c9a-4b0e-4c5a-9a57-2f7f3d6e1f00",
+			},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"initContainers": []any{
+							map[string]any{"name": "init", "image": "nginx:1.25"},
+						},
+						"containers": []any{
+							map[string]any{"name": "app", "image": "nginx:1.25"},
+							map[string]any{"name": "sidecar", "image": "ghcr.io/org/sidecar:2.0"},
+						},
+					},
+				},
+			},
+		},
+	}
+	resources := []Resource{
+		{
+			Namespace: "default",
+			Kind:      "Deployment",
+			Name:      "web",
+			Report: types.Report{
+				ArtifactName: "nginx:1.25",
+				ArtifactType: ftypes.ArtifactContainerImage,
+				Metadata: types.Metadata{
+					OS: &ftypes.OS{
+						Family: ftypes.Debian,
+						Name:   "12.5",
+					},
+					RepoDigests: []string{"nginx@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31"},
+				},
+				Results: types.Results{
+					{
+						Target: "nginx:1.25 (debian 12.5)",
+						Vulnerabilities: []types.DetectedVulnerability{
+							{
+								VulnerabilityID:  "CVE-2024-0001",
+								PkgName:          "libssl3",
+								InstalledVersion: "3.0.11-1",
+								FixedVersion:     "3.0.13-1",
+								PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2024-0001",
+								SeveritySource:   "nvd",
+								Vulnerability: dbTypes.Vulnerability{
+									Title:      "openssl: excessive time spent",
+									Severity:   "HIGH",
+									References: []string{"https://www.openssl.org/news/secadv/20240115.txt"},
+									CVSS: dbTypes.VendorCVSS{
+										"nvd": {V3Score: 7.5},
+									},
+								},
+							},
+							{
+								VulnerabilityID:  "CVE-2024-0002",
+								PkgName:          "zlib1g",
+								InstalledVersion: "1:1.2.13.dfsg-1",
+								Vulnerability: dbTypes.Vulnerability{
+									Severity: "UNKNOWN",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			// Misconfigurations are not in VulnerabilityReports
+			Namespace: "default",
+			Kind:      "Deployment",
+			Name:      "web",
+			Report: types.Report{
+				ArtifactName: "web",
+			},
+		},
+	}
+
+	got := VulnerabilityReports(artifact, resources, "0.50.0", now)
+	assert.Len(t, got, 2)
+
+	assert.Equal(t, metav1.ObjectMeta{
+		Name:      "deployment-web-init",
+		Namespace: "default",
+		Labels: map[string]string{
+			LabelResourceKind:      "Deployment",
+			LabelResourceName:      "web",
+			LabelResourceNamespace: "default",
+			LabelContainerName:     "init",
+		},
+		OwnerReferences: []metav1.OwnerReference{
+			{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "web",
+				UID:        "0b7d1c9a-4b0e-4c5a-9a57-2f7f3d6e1f00",
+			},
+		},
+	}, got[0].ObjectMeta)
+	assert.Equal(t, "deployment-web-app", got[1].Name)
+	assert.Equal(t, "aquasecurity.github.io/v1alpha1", got[1].APIVersion)
+	assert.Equal(t, "VulnerabilityReport", got[1].Kind)
+
+	score := 7.5
+	assert.Equal(t, VulnerabilityReportData{
+		UpdateTimestamp: metav1.NewTime(now),
+		Scanner: VulnerabilityReportScanner{
+			Name:    "Trivy",
+			Vendor:  "Aqua Security",
+			Version: "0.50.0",
+		},
+		Registry: VulnerabilityReportRegistry{
+			Server: "index.docker.io",
+		},
+		Artifact: VulnerabilityReportArtifact{
+			Repository: "library/nginx",
+			Tag:        "1.25",
+			Digest:     "sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+		},
+		OS: VulnerabilityReportOS{
+			Family: "debian",
+			Name:   "12.5",
+		},
+		Summary: VulnerabilitySummary{
+			HighCount:    1,
+			UnknownCount: 1,
+		},
+		Vulnerabilities: []Vulnerability{
+			{
+				VulnerabilityID:  "CVE-2024-0001",
+				Resource:         "libssl3",
+				InstalledVersion: "3.0.11-1",
+				FixedVersion:     "3.0.13-1",
+				Severity:         "HIGH",
+				Title:            "openssl: excessive time spent",
+				PrimaryLink:      "https://avd.aquasec.com/nvd/cve-2024-0001",
+				Links:            []string{"https://www.openssl.org/news/secadv/20240115.txt"},
+				Score:            &score,
+				Target:           "nginx:1.25 (debian 12.5)",
+			},
+			{
+				VulnerabilityID:  "CVE-2024-0002",
+				Resource:         "zlib1g",
+				InstalledVersion: "1:1.2.13.dfsg-1",
+				Severity:         "UNKNOWN",
+				Links:            []string{},
+				Target:           "nginx:1.25 (debian 12.5)",
+			},
+		},
+	}, got[1].Report)
+}
+
+func Test_vulnerabilityReportName(t *testing.T) {
+	assert.Equal(t, "statefulset-db-postgres", vulnerabilityReportName("StatefulSet", "db", "postgres"))
+
+	long := vulnerabilityReportName("Deployment", "a-very-long-deployment-name-generated-by-some-tool", "application")
+	assert.Len(t, long, 63)
+	assert.Regexp(t, "^deployment-[0-9a-f]+$", long)
+}
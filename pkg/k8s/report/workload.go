@@ -0,0 +1,157 @@
+package report
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	seccompPodAnnotation             = "seccomp.security.alpha.kubernetes.io/pod"
+	seccompContainerAnnotationPrefix = "container.seccomp.security.alpha.kubernetes.io/"
+	appArmorAnnotationPrefix         = "container.apparmor.security.beta.kubernetes.io/"
+)
+
+var workloadKinds = []string{
+	"Pod",
+	"Deployment",
+	"ReplicaSet",
+	"ReplicationController",
+	"StatefulSet",
+	"DaemonSet",
+	"Job",
+	"CronJob",
+}
+
+// Workload represents the exposure of a workload so that the findings can be weighed by it,
+// e.g. a vulnerable image in a privileged container mounting the host filesystem.
+type Workload struct {
+	RuntimeClassName string              `json:",omitempty"` // e.g. gvisor and kata
+	Privileged       bool                // Whether any container runs privileged
+	HostPaths        []string            `json:",omitempty"` // The host paths mounted as volumes
+	Containers       []WorkloadContainer `json:",omitempty"`
+}
+
+// WorkloadContainer represents the sandbox of a container.
+// The profiles are the effective ones, which are inherited from the pod unless set for the container.
+type WorkloadContainer struct {
+	Name            string
+	Privileged      bool   `json:",omitempty"`
+	SeccompProfile  string `json:",omitempty"` // e.g. RuntimeDefault, Unconfined and Localhost/profiles/audit.json
+	AppArmorProfile string `json:",omitempty"` // e.g. RuntimeDefault, Unconfined and Localhost/k8s-apparmor-example
+}
+
+// newWorkload returns the exposure of the workload resource, or nil for other resources
+func newWorkload(kind string, raw map[string]any) *Workload {
+	if !slices.Contains(workloadKinds, kind) || raw == nil {
+		return nil
+	}
+
+	podKeys := podTemplateKeys(kind)
+	spec, _, _ := unstructured.NestedMap(raw, append(podKeys, "spec")...)
+	annotations, _, _ := unstructured.NestedStringMap(raw, append(podKeys, "metadata", "annotations")...)
+
+	w := &Workload{}
+	w.RuntimeClassName, _, _ = unstructured.NestedString(spec, "runtimeClassName")
+
+	volumes, _, _ := unstructured.NestedSlice(spec, "volumes")
+	for _, v := range volumes {
+		volume, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		if p, found, _ := unstructured.NestedString(volume, "hostPath", "path"); found {
+			w.HostPaths = append(w.HostPaths, p)
+		}
+	}
+
+	podSeccomp := securityContextProfile(spec, "securityContext", "seccompProfile")
+	if podSeccomp == "" {
+		podSeccomp = annotationProfile(annotations[seccompPodAnnotation])
+	}
+	podAppArmor := securityContextProfile(spec, "securityContext", "appArmorProfile")
+
+	for _, containerType := range []string{"initContainers", "containers", "ephemeralContainers"} {
+		containers, _, _ := unstructured.NestedSlice(spec, containerType)
+		for _, c := range containers {
+			container, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(container, "name")
+			privileged, _, _ := unstructured.NestedBool(container, "securityContext", "privileged")
+			w.Privileged = w.Privileged || privileged
+
+			w.Containers = append(w.Containers, WorkloadContainer{
+				Name:       name,
+				Privileged: privileged,
+				SeccompProfile: firstProfile(
+					securityContextProfile(container, "securityContext", "seccompProfile"),
+					annotationProfile(annotations[seccompContainerAnnotationPrefix+name]),
+					podSeccomp,
+				),
+				AppArmorProfile: firstProfile(
+					securityContextProfile(container, "securityContext", "appArmorProfile"),
+					annotationProfile(annotations[appArmorAnnotationPrefix+name]),
+					podAppArmor,
+				),
+			})
+		}
+	}
+	return w
+}
+
+// podTemplateKeys returns the keys of the pod template of controllers, or nil for pods
+func podTemplateKeys(kind string) []string {
+	switch kind {
+	case "Pod":
+		return nil
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template"}
+	}
+	return []string{"spec", "template"}
+}
+
+// podLabels returns the labels of the pods of the workload resource
+func podLabels(kind string, raw map[string]any) map[string]string {
+	if !slices.Contains(workloadKinds, kind) {
+		return nil
+	}
+	labels, _, _ := unstructured.NestedStringMap(raw, append(podTemplateKeys(kind), "metadata", "labels")...)
+	return labels
+}
+
+// securityContextProfile returns the seccomp or AppArmor profile in the security context,
+// e.g. {type: Localhost, localhostProfile: audit.json} => Localhost/audit.json
+func securityContextProfile(obj map[string]any, keys ...string) string {
+	profileType, _, _ := unstructured.NestedString(obj, append(keys, "type")...)
+	if profileType != "Localhost" {
+		return profileType
+	}
+	localhostProfile, _, _ := unstructured.NestedString(obj, append(keys, "localhostProfile")...)
+	return profileType + "/" + localhostProfile
+}
+
+// annotationProfile converts the profile in the deprecated annotations into the security context style,
+// e.g. runtime/default => RuntimeDefault
+func annotationProfile(annotation string) string {
+	switch {
+	case annotation == "runtime/default", annotation == "docker/default":
+		return "RuntimeDefault"
+	case annotation == "unconfined":
+		return "Unconfined"
+	case strings.HasPrefix(annotation, "localhost/"):
+		return "Localhost/" + strings.TrimPrefix(annotation, "localhost/")
+	}
+	return annotation
+}
+
+func firstProfile(profiles ...string) string {
+	for _, p := range profiles {
+		if p != "" {
+			return p
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newWorkload(t *testing.T) {
+	tests := []struct {
+		name string
+		kind string
+		raw  map[string]any
+		want *Workload
+	}{
+		{
+			name: "privileged pod with host path",
+			kind: "Pod",
+			raw: map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]any{
+						"seccomp.security.alpha.kubernetes.io/pod":                "runtime/default",
+						"container.apparmor.security.beta.kubernetes.io/exporter": "localhost/k8s-apparmor-example",
+					},
+				},
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{
+							"name": "exporter",
+							"securityContext": map[string]any{
+								"privileged": true,
+							},
+						},
+						map[string]any{
+							"name": "sidecar",
+							"securityContext": map[string]any{
+								"seccompProfile": map[string]any{
+									"type":             "Localhost",
+									"localhostProfile": "profiles/audit.json",
+								},
+							},
+						},
+					},
+					"volumes": []any{
+						map[string]any{
+							"name": "root",
+							"hostPath": map[string]any{
+								"path": "/",
+							},
+						},
+						map[string]any{
+							"name":     "tmp",
+							"emptyDir": map[string]any{},
+						},
+					},
+				},
+			},
+			want: &Workload{
+				Privileged: true,
+				HostPaths:  []string{"/"},
+				Containers: []WorkloadContainer{
+					{
+						Name:            "exporter",
+						Privileged:      true,
+						SeccompProfile:  "RuntimeDefault",
+						AppArmorProfile: "Localhost/k8s-apparmor-example",
+					},
+					{
+						Name:           "sidecar",
+						SeccompProfile: "Localhost/profiles/audit.json",
+					},
+				},
+			},
+		},
+		{
+			name: "cronjob in sandbox",
+			kind: "CronJob",
+			raw: map[string]any{
+				"spec": map[string]any{
+					"jobTemplate": map[string]any{
+						"spec": map[string]any{
+							"template": map[string]any{
+								"spec": map[string]any{
+									"runtimeClassName": "gvisor",
+									"securityContext": map[string]any{
+										"seccompProfile": map[string]any{
+											"type": "RuntimeDefault",
+										},
+										"appArmorProfile": map[string]any{
+											"type": "RuntimeDefault",
+										},
+									},
+									"initContainers": []any{
+										map[string]any{
+											"name": "init",
+										},
+									},
+									"containers": []any{
+										map[string]any{
+											"name": "backup",
+											"securityContext": map[string]any{
+												"seccompProfile": map[string]any{
+													"type": "Unconfined",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &Workload{
+				RuntimeClassName: "gvisor",
+				Containers: []WorkloadContainer{
+					{
+						Name:            "init",
+						SeccompProfile:  "RuntimeDefault",
+						AppArmorProfile: "RuntimeDefault",
+					},
+					{
+						Name:            "backup",
+						SeccompProfile:  "Unconfined",
+						AppArmorProfile: "RuntimeDefault",
+					},
+				},
+			},
+		},
+		{
+			name: "not workload",
+			kind: "Role",
+			raw: map[string]any{
+				"rules": []any{},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newWorkload(tt.kind, tt.raw)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
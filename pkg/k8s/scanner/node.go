@@ -0,0 +1,177 @@
+package scanner
+
+import (
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/package-url/packageurl-go"
+	"github.com/samber/lo"
+
+	"github.com/aquasecurity/trivy-kubernetes/pkg/bom"
+	"github.com/aquasecurity/trivy/pkg/purl"
+	"github.com/aquasecurity/trivy/pkg/sbom/cyclonedx/core"
+)
+
+const (
+	nodeInfo     = "NodeInfo"
+	linuxKernel  = "linux-kernel"
+	cniReference = "github.com/containernetworking/plugins"
+)
+
+// nodeCollectorInfo represents the node-level components collected by the node-collector.
+//
+// e.g.
+//
+//	{
+//	  "kind": "NodeInfo",
+//	  "info": {
+//	    "kernelVersion": {"values": ["6.2.15-300.fc38.aarch64"]},
+//	    "kubeletVersion": {"values": ["v1.29.1"]},
+//	    "containerRuntimeVersion": {"values": ["containerd://1.7.11"]},
+//	    "cniPlugins": {"values": ["bridge:1.4.0", "calico:3.27.0"]}
+//	  }
+//	}
+type nodeCollectorInfo struct {
+	KernelVersion           string
+	KubeletVersion          string
+	ContainerRuntimeVersion string
+	CNIPlugins              []cniPlugin
+}
+
+type cniPlugin struct {
+	Name    string
+	Version string
+}
+
+func parseNodeCollectorInfo(raw map[string]any) nodeCollectorInfo {
+	info, _ := raw["info"].(map[string]any)
+	values := func(key string) []string {
+		v, ok := info[key].(map[string]any)
+		if !ok {
+			return nil
+		}
+		vals, _ := v["values"].([]any)
+		return lo.FilterMap(vals, func(v any, _ int) (string, bool) {
+			s, ok := v.(string)
+			return s, ok && s != ""
+		})
+	}
+	first := func(key string) string {
+		vals := values(key)
+		if len(vals) == 0 {
+			return ""
+		}
+		return vals[0]
+	}
+
+	var plugins []cniPlugin
+	for _, p := range values("cniPlugins") {
+		name, ver, _ := strings.Cut(p, ":")
+		plugins = append(plugins, cniPlugin{
+			Name:    name,
+			Version: sanitizedVersion(ver),
+		})
+	}
+
+	return nodeCollectorInfo{
+		KernelVersion:           first("kernelVersion"),
+		KubeletVersion:          first("kubeletVersion"),
+		ContainerRuntimeVersion: first("containerRuntimeVersion"),
+		CNIPlugins:              plugins,
+	}
+}
+
+// merge overrides the versions reported by the node status with the ones collected on the node.
+func (i nodeCollectorInfo) merge(nf bom.NodeInfo) bom.NodeInfo {
+	if i.KernelVersion != "" {
+		props := make(map[string]string, len(nf.Properties)+1)
+		for k, v := range nf.Properties {
+			props[k] = v
+		}
+		props["KernelVersion"] = i.KernelVersion
+		nf.Properties = props
+	}
+	if i.KubeletVersion != "" {
+		nf.KubeletVersion = i.KubeletVersion
+	}
+	if i.ContainerRuntimeVersion != "" {
+		nf.ContainerRuntimeVersion = i.ContainerRuntimeVersion
+	}
+	return nf
+}
+
+// cniPluginModule returns the Go module of the CNI plugin binary so that the vulnerabilities can be detected.
+func cniPluginModule(name string) string {
+	switch name {
+	case "bandwidth", "bridge", "dhcp", "dummy", "firewall", "host-device", "host-local", "ipvlan", "loopback",
+		"macvlan", "portmap", "ptp", "sbr", "static", "tap", "tuning", "vlan", "vrf":
+		return cniReference
+	case "calico", "calico-ipam":
+		return "github.com/projectcalico/calico"
+	case "flannel":
+		return "github.com/flannel-io/cni-plugin"
+	case "cilium-cni":
+		return "github.com/cilium/cilium"
+	case "aws-cni":
+		return "github.com/aws/amazon-vpc-cni-k8s"
+	default:
+		return name
+	}
+}
+
+func cniPluginComponents(plugins []cniPlugin) []*core.Component {
+	// Reference plugins, e.g. "bridge" and "portmap", are in the same module
+	plugins = lo.UniqBy(plugins, func(p cniPlugin) string {
+		return cniPluginModule(p.Name) + "@" + p.Version
+	})
+	return lo.Map(plugins, func(p cniPlugin, _ int) *core.Component {
+		module := cniPluginModule(p.Name)
+		c := &core.Component{
+			Type:    cdx.ComponentTypeApplication,
+			Name:    module,
+			Version: p.Version,
+			Properties: []core.Property{
+				{
+					Name:      k8sComponentType,
+					Value:     k8sComponentNode,
+					Namespace: k8sCoreComponentNamespace,
+				},
+				{
+					Name:      k8sComponentName,
+					Value:     p.Name,
+					Namespace: k8sCoreComponentNamespace,
+				},
+			},
+		}
+		if p.Version != "" {
+			c.PackageURL = &purl.PackageURL{
+				PackageURL: *packageurl.NewPackageURL(golang, "", module, p.Version, packageurl.Qualifiers{}, ""),
+			}
+		}
+		return c
+	})
+}
+
+func kernelComponent(nf bom.NodeInfo) *core.Component {
+	ver := nf.Properties["KernelVersion"]
+	if ver == "" {
+		return nil
+	}
+	return &core.Component{
+		Type:    cdx.ComponentTypeApplication,
+		Name:    linuxKernel,
+		Version: ver,
+		Properties: []core.Property{
+			{
+				Name:      k8sComponentType,
+				Value:     k8sComponentNode,
+				Namespace: k8sCoreComponentNamespace,
+			},
+			{
+				Name:      k8sComponentName,
+				Value:     linuxKernel,
+				Namespace: k8sCoreComponentNamespace,
+			},
+		},
+	}
+}
@@ -0,0 +1,182 @@
+package scanner
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aquasecurity/trivy-kubernetes/pkg/bom"
+	"github.com/aquasecurity/trivy/pkg/purl"
+	"github.com/aquasecurity/trivy/pkg/sbom/cyclonedx/core"
+)
+
+func TestParseNodeCollectorInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  map[string]any
+		want nodeCollectorInfo
+	}{
+		{
+			name: "all components",
+			raw: map[string]any{
+				"kind": "NodeInfo",
+				"info": map[string]any{
+					"kernelVersion": map[string]any{
+						"values": []any{"6.5.0-1015-aws"},
+					},
+					"kubeletVersion": map[string]any{
+						"values": []any{"v1.29.1"},
+					},
+					"containerRuntimeVersion": map[string]any{
+						"values": []any{"containerd://1.7.11"},
+					},
+					"cniPlugins": map[string]any{
+						"values": []any{
+							"bridge:v1.4.0",
+							"calico:3.27.0",
+							"unknown",
+						},
+					},
+					"kubeletConfFileOwnership": map[string]any{
+						"values": []any{"root:root"},
+					},
+				},
+			},
+			want: nodeCollectorInfo{
+				KernelVersion:           "6.5.0-1015-aws",
+				KubeletVersion:          "v1.29.1",
+				ContainerRuntimeVersion: "containerd://1.7.11",
+				CNIPlugins: []cniPlugin{
+					{
+						Name:    "bridge",
+						Version: "1.4.0",
+					},
+					{
+						Name:    "calico",
+						Version: "3.27.0",
+					},
+					{
+						Name: "unknown",
+					},
+				},
+			},
+		},
+		{
+			name: "no components",
+			raw: map[string]any{
+				"kind": "NodeInfo",
+				"info": map[string]any{
+					"kubeletConfFileOwnership": map[string]any{
+						"values": []any{"root:root"},
+					},
+				},
+			},
+			want: nodeCollectorInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseNodeCollectorInfo(tt.raw)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNodeCollectorInfo_Merge(t *testing.T) {
+	nf := bom.NodeInfo{
+		NodeName:                "kind-control-plane",
+		KubeletVersion:          "v1.29.0",
+		ContainerRuntimeVersion: "containerd://1.7.1",
+		Properties: map[string]string{
+			"Architecture":  "arm64",
+			"KernelVersion": "6.2.15-300.fc38.aarch64",
+		},
+	}
+
+	t.Run("override", func(t *testing.T) {
+		info := nodeCollectorInfo{
+			KernelVersion:  "6.5.0-1015-aws",
+			KubeletVersion: "v1.29.1",
+		}
+		got := info.merge(nf)
+		assert.Equal(t, bom.NodeInfo{
+			NodeName:                "kind-control-plane",
+			KubeletVersion:          "v1.29.1",
+			ContainerRuntimeVersion: "containerd://1.7.1",
+			Properties: map[string]string{
+				"Architecture":  "arm64",
+				"KernelVersion": "6.5.0-1015-aws",
+			},
+		}, got)
+
+		// The original properties must not be modified
+		assert.Equal(t, "6.2.15-300.fc38.aarch64", nf.Properties["KernelVersion"])
+	})
+
+	t.Run("nothing collected", func(t *testing.T) {
+		got := nodeCollectorInfo{}.merge(nf)
+		assert.Equal(t, nf, got)
+	})
+}
+
+func TestCNIPluginComponents(t *testing.T) {
+	plugins := []cniPlugin{
+		{
+			Name:    "bridge",
+			Version: "1.4.0",
+		},
+		{
+			Name:    "portmap",
+			Version: "1.4.0",
+		},
+		{
+			Name: "custom",
+		},
+	}
+	want := []*core.Component{
+		{
+			Type:    cdx.ComponentTypeApplication,
+			Name:    "github.com/containernetworking/plugins",
+			Version: "1.4.0",
+			Properties: []core.Property{
+				{
+					Name:      k8sComponentType,
+					Value:     "node",
+					Namespace: k8sCoreComponentNamespace,
+				},
+				{
+					Name:      k8sComponentName,
+					Value:     "bridge",
+					Namespace: k8sCoreComponentNamespace,
+				},
+			},
+			PackageURL: &purl.PackageURL{
+				PackageURL: packageurl.PackageURL{
+					Type:       "golang",
+					Name:       "github.com/containernetworking/plugins",
+					Version:    "1.4.0",
+					Qualifiers: packageurl.Qualifiers{},
+				},
+			},
+		},
+		{
+			Type: cdx.ComponentTypeApplication,
+			Name: "custom",
+			Properties: []core.Property{
+				{
+					Name:      k8sComponentType,
+					Value:     "node",
+					Namespace: k8sCoreComponentNamespace,
+				},
+				{
+					Name:      k8sComponentName,
+					Value:     "custom",
+					Namespace: k8sCoreComponentNamespace,
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, cniPluginComponents(plugins))
+}
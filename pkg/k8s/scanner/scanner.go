@@ -11,7 +11,10 @@ import (
 	ms "github.com/mitchellh/mapstructure"
 	"github.com/package-url/packageurl-go"
 	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/aquasecurity/go-version/pkg/version"
 	"github.com/aquasecurity/trivy-kubernetes/pkg/artifacts"
@@ -36,6 +39,9 @@ const (
 	k8sComponentType          = "Type"
 	k8sComponentName          = "Name"
 	k8sComponentNode          = "node"
+	k8sComponentKind          = "Kind"
+	k8sComponentScope         = "Scope"
+	k8sComponentNamespace     = "Namespace"
 )
 
 type Scanner struct {
@@ -71,10 +77,15 @@ func (s *Scanner) Scan(ctx context.Context, artifactsData []*artifacts.Artifact)
 	}()
 
 	if s.opts.Format == types.FormatCycloneDX {
-		rootComponent, err := clusterInfoToReportResources(artifactsData)
+		rootComponent, err := clusterInfoToReportResources(artifactsData, s.opts.CustomResources)
 		if err != nil {
 			return report.Report{}, err
 		}
+		if s.opts.Scanners.Enabled(types.VulnerabilityScanner) {
+			if err = s.kbomVulnerabilities(ctx, rootComponent); err != nil {
+				return report.Report{}, xerrors.Errorf("KBOM vulnerability detection error: %w", err)
+			}
+		}
 		return report.Report{
 			SchemaVersion: 0,
 			RootComponent: rootComponent,
@@ -83,7 +94,8 @@ func (s *Scanner) Scan(ctx context.Context, artifactsData []*artifacts.Artifact)
 	var resourceArtifacts []*artifacts.Artifact
 	var k8sCoreArtifacts []*artifacts.Artifact
 	for _, artifact := range artifactsData {
-		if strings.HasSuffix(artifact.Kind, "Components") || strings.HasSuffix(artifact.Kind, "Cluster") {
+		if (strings.HasSuffix(artifact.Kind, "Components") || strings.HasSuffix(artifact.Kind, "Cluster")) &&
+			!k8s.IsCustomResource(artifact, s.opts.CustomResources) {
 			k8sCoreArtifacts = append(k8sCoreArtifacts, artifact)
 			continue
 		}
@@ -95,6 +107,7 @@ func (s *Scanner) Scan(ctx context.Context, artifactsData []*artifacts.Artifact)
 	type scanResult struct {
 		vulns     []report.Resource
 		misconfig report.Resource
+		inventory *report.Resource
 	}
 
 	onItem := func(ctx context.Context, artifact *artifacts.Artifact) (scanResult, error) {
@@ -127,6 +140,10 @@ func (s *Scanner) Scan(ctx context.Context, artifactsData []*artifacts.Artifact)
 			}
 			scanResults.misconfig = misconfig
 		}
+		// Keep CRDs and custom resources in the inventory even without any findings
+		if len(scanResults.vulns) == 0 && scanResults.misconfig.Results == nil && k8s.IsCustomResource(artifact, s.opts.CustomResources) {
+			scanResults.inventory = lo.ToPtr(report.CreateResource(artifact, types.Report{}, nil))
+		}
 		return scanResults, nil
 	}
 
@@ -136,6 +153,9 @@ func (s *Scanner) Scan(ctx context.Context, artifactsData []*artifacts.Artifact)
 		if result.misconfig.Results != nil {
 			resources = append(resources, result.misconfig)
 		}
+		if result.inventory != nil {
+			resources = append(resources, *result.inventory)
+		}
 		return nil
 	}
 
@@ -367,9 +387,18 @@ func findNodeName(allArtifact []*artifacts.Artifact) string {
 	return ""
 }
 
-func clusterInfoToReportResources(allArtifact []*artifacts.Artifact) (*core.Component, error) {
+func clusterInfoToReportResources(allArtifact []*artifacts.Artifact, customResources []string) (*core.Component, error) {
 	var coreComponents []*core.Component
 	var cInfo *core.Component
+	var customArtifacts []*artifacts.Artifact
+
+	// The node-level components collected by the node-collector
+	collected := make(map[string]nodeCollectorInfo)
+	for _, artifact := range allArtifact {
+		if artifact.Kind == nodeInfo {
+			collected[artifact.Name] = parseNodeCollectorInfo(artifact.RawResource)
+		}
+	}
 
 	// Find the first node name to identify AKS cluster
 	var nodeName string
@@ -378,6 +407,11 @@ func clusterInfoToReportResources(allArtifact []*artifacts.Artifact) (*core.Comp
 	}
 
 	for _, artifact := range allArtifact {
+		// Custom resources may have the same kind as the cluster info, e.g. "Cluster" of Cluster API
+		if k8s.IsCustomResource(artifact, customResources) {
+			customArtifacts = append(customArtifacts, artifact)
+			continue
+		}
 		switch artifact.Kind {
 		case controlPlaneComponents:
 			var comp bom.Component
@@ -435,7 +469,10 @@ func clusterInfoToReportResources(allArtifact []*artifacts.Artifact) (*core.Comp
 			if err != nil {
 				return nil, err
 			}
-			coreComponents = append(coreComponents, nodeComponent(nf))
+			info := collected[nf.NodeName]
+			coreComponents = append(coreComponents, nodeComponent(info.merge(nf), info.CNIPlugins))
+		case nodeInfo:
+			continue
 		case clusterInfo:
 			var cf bom.ClusterInfo
 			err := ms.Decode(artifact.RawResource, &cf)
@@ -451,6 +488,8 @@ func clusterInfoToReportResources(allArtifact []*artifacts.Artifact) (*core.Comp
 			return nil, fmt.Errorf("resource kind %s is not supported", artifact.Kind)
 		}
 	}
+	coreComponents = append(coreComponents, customResourceComponents(customArtifacts)...)
+
 	rootComponent := &core.Component{
 		Name:       cInfo.Name,
 		Version:    cInfo.Version,
@@ -462,6 +501,65 @@ func clusterInfoToReportResources(allArtifact []*artifacts.Artifact) (*core.Comp
 	return rootComponent, nil
 }
 
+// customResourceComponents returns the components of CRDs with the custom resources defined by them as the children.
+func customResourceComponents(allArtifact []*artifacts.Artifact) []*core.Component {
+	var components []*core.Component
+	crds := make(map[string]*core.Component) // by "group/kind"
+	for _, artifact := range allArtifact {
+		if artifact.Kind != k8s.CRDKind {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(artifact.RawResource, "spec", "group")
+		kind, _, _ := unstructured.NestedString(artifact.RawResource, "spec", "names", "kind")
+		scope, _, _ := unstructured.NestedString(artifact.RawResource, "spec", "scope")
+		c := &core.Component{
+			Type:  cdx.ComponentTypeData,
+			Name:  artifact.Name,
+			Group: group,
+			Properties: toProperties(map[string]string{
+				k8sComponentType:  artifact.Kind,
+				k8sComponentName:  artifact.Name,
+				k8sComponentKind:  kind,
+				k8sComponentScope: scope,
+			}, k8sCoreComponentNamespace),
+		}
+		crds[group+"/"+kind] = c
+		components = append(components, c)
+	}
+
+	for _, artifact := range allArtifact {
+		if artifact.Kind == k8s.CRDKind {
+			continue
+		}
+		apiVersion, _, _ := unstructured.NestedString(artifact.RawResource, "apiVersion")
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			log.Logger.Debugf("Invalid apiVersion of %s/%s: %s", artifact.Kind, artifact.Name, err)
+			continue
+		}
+		props := map[string]string{
+			k8sComponentType: artifact.Kind,
+			k8sComponentName: artifact.Name,
+		}
+		if artifact.Namespace != "" {
+			props[k8sComponentNamespace] = artifact.Namespace
+		}
+		c := &core.Component{
+			Type:       cdx.ComponentTypeData,
+			Name:       artifact.Name,
+			Group:      gv.Group,
+			Version:    gv.Version,
+			Properties: toProperties(props, k8sCoreComponentNamespace),
+		}
+		if crd, ok := crds[gv.Group+"/"+artifact.Kind]; ok {
+			crd.Components = append(crd.Components, c)
+		} else {
+			components = append(components, c)
+		}
+	}
+	return components
+}
+
 func sanitizedVersion(ver string) string {
 	return strings.TrimPrefix(ver, "v")
 }
@@ -500,7 +598,7 @@ func runtimeNameVersion(name string) (string, string) {
 	return name, ver
 }
 
-func nodeComponent(nf bom.NodeInfo) *core.Component {
+func nodeComponent(nf bom.NodeInfo, cniPlugins []cniPlugin) *core.Component {
 	osName, osVersion := osNameVersion(nf.OsImage)
 	runtimeName, runtimeVersion := runtimeNameVersion(nf.ContainerRuntimeVersion)
 	kubeletVersion := sanitizedVersion(nf.KubeletVersion)
@@ -509,7 +607,7 @@ func nodeComponent(nf bom.NodeInfo) *core.Component {
 		k8sComponentType: k8sComponentNode,
 		k8sComponentName: nf.NodeName,
 	}, k8sCoreComponentNamespace)...)
-	return &core.Component{
+	node := &core.Component{
 		Type:       cdx.ComponentTypePlatform,
 		Name:       nf.NodeName,
 		Properties: properties,
@@ -585,6 +683,66 @@ func nodeComponent(nf bom.NodeInfo) *core.Component {
 			},
 		},
 	}
+
+	// The CNI plugins are Go binaries as well as kubelet and the container runtime
+	coreComponents := node.Components[len(node.Components)-1]
+	coreComponents.Components = append(coreComponents.Components, cniPluginComponents(cniPlugins)...)
+	if kernel := kernelComponent(nf); kernel != nil {
+		// Between the OS and the core components
+		node.Components = slices.Insert(node.Components, 1, kernel)
+	}
+	return node
+}
+
+// kbomVulnerabilities detects the vulnerabilities of the Kubernetes and Go components in KBOM.
+func (s *Scanner) kbomVulnerabilities(ctx context.Context, component *core.Component) error {
+	if component.PackageURL != nil && component.Version != "" {
+		var appType ftypes.LangType
+		switch component.PackageURL.Type {
+		case purl.TypeK8s:
+			// An empty namespace means upstream
+			appType = ftypes.LangType(lo.Ternary(component.PackageURL.Namespace == "", "kubernetes", component.PackageURL.Namespace))
+		case golang:
+			appType = ftypes.GoBinary
+		}
+
+		if appType != "" {
+			results, _, err := k8s.NewKubenetesScanner().Scan(ctx, types.ScanTarget{
+				Applications: []ftypes.Application{
+					{
+						Type:     appType,
+						FilePath: component.Name,
+						Libraries: []ftypes.Package{
+							{
+								Name:    component.Name,
+								Version: component.Version,
+							},
+						},
+					},
+				},
+			}, types.ScanOptions{
+				Scanners: s.opts.Scanners,
+				VulnType: s.opts.VulnType,
+			})
+			if err != nil {
+				return xerrors.Errorf("scan error (%s): %w", component.Name, err)
+			}
+			r, err := s.runner.Filter(ctx, s.opts, types.Report{Results: results})
+			if err != nil {
+				return xerrors.Errorf("filter error: %w", err)
+			}
+			for _, result := range r.Results {
+				component.Vulnerabilities = append(component.Vulnerabilities, result.Vulnerabilities...)
+			}
+		}
+	}
+
+	for _, child := range component.Components {
+		if err := s.kbomVulnerabilities(ctx, child); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func toProperties(props map[string]string, namespace string) []core.Property {
@@ -201,6 +201,23 @@ func TestScanner_Scan(t *testing.T) {
 									},
 								},
 							},
+							{
+								Type:    cdx.ComponentTypeApplication,
+								Name:    "linux-kernel",
+								Version: "6.2.15-300.fc38.aarch64",
+								Properties: []core.Property{
+									{
+										Name:      k8sComponentType,
+										Value:     "node",
+										Namespace: k8sCoreComponentNamespace,
+									},
+									{
+										Name:      k8sComponentName,
+										Value:     "linux-kernel",
+										Namespace: k8sCoreComponentNamespace,
+									},
+								},
+							},
 							{
 								Type: cdx.ComponentTypeApplication,
 								Name: "node-core-components",
@@ -551,3 +568,130 @@ func TestFindNodeName(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomResourceComponents(t *testing.T) {
+	tests := []struct {
+		name      string
+		artifacts []*artifacts.Artifact
+		want      []*core.Component
+	}{
+		{
+			name: "custom resources under the CRD",
+			artifacts: []*artifacts.Artifact{
+				{
+					Kind: "CustomResourceDefinition",
+					Name: "certificates.cert-manager.io",
+					RawResource: map[string]interface{}{
+						"spec": map[string]interface{}{
+							"group": "cert-manager.io",
+							"scope": "Namespaced",
+							"names": map[string]interface{}{
+								"kind": "Certificate",
+							},
+						},
+					},
+				},
+				{
+					Namespace: "default",
+					Kind:      "Certificate",
+					Name:      "web",
+					RawResource: map[string]interface{}{
+						"apiVersion": "cert-manager.io/v1",
+					},
+				},
+			},
+			want: []*core.Component{
+				{
+					Type:  cdx.ComponentTypeData,
+					Name:  "certificates.cert-manager.io",
+					Group: "cert-manager.io",
+					Properties: []core.Property{
+						{
+							Name:      "Kind",
+							Value:     "Certificate",
+							Namespace: k8sCoreComponentNamespace,
+						},
+						{
+							Name:      "Name",
+							Value:     "certificates.cert-manager.io",
+							Namespace: k8sCoreComponentNamespace,
+						},
+						{
+							Name:      "Scope",
+							Value:     "Namespaced",
+							Namespace: k8sCoreComponentNamespace,
+						},
+						{
+							Name:      "Type",
+							Value:     "CustomResourceDefinition",
+							Namespace: k8sCoreComponentNamespace,
+						},
+					},
+					Components: []*core.Component{
+						{
+							Type:    cdx.ComponentTypeData,
+							Name:    "web",
+							Group:   "cert-manager.io",
+							Version: "v1",
+							Properties: []core.Property{
+								{
+									Name:      "Name",
+									Value:     "web",
+									Namespace: k8sCoreComponentNamespace,
+								},
+								{
+									Name:      "Namespace",
+									Value:     "default",
+									Namespace: k8sCoreComponentNamespace,
+								},
+								{
+									Name:      "Type",
+									Value:     "Certificate",
+									Namespace: k8sCoreComponentNamespace,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "custom resource without the CRD",
+			artifacts: []*artifacts.Artifact{
+				{
+					Kind: "Gateway",
+					Name: "ingress",
+					RawResource: map[string]interface{}{
+						"apiVersion": "networking.istio.io/v1",
+					},
+				},
+			},
+			want: []*core.Component{
+				{
+					Type:    cdx.ComponentTypeData,
+					Name:    "ingress",
+					Group:   "networking.istio.io",
+					Version: "v1",
+					Properties: []core.Property{
+						{
+							Name:      "Name",
+							Value:     "ingress",
+							Namespace: k8sCoreComponentNamespace,
+						},
+						{
+							Name:      "Type",
+							Value:     "Gateway",
+							Namespace: k8sCoreComponentNamespace,
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := customResourceComponents(tt.artifacts)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -5,13 +5,15 @@ import (
 	"fmt"
 
 	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/k8s/report"
 	"github.com/aquasecurity/trivy/pkg/report/table"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
-// Write writes the results in the give format
+// Write writes the results in the given format
 func Write(ctx context.Context, k8sreport report.Report, option report.Option) error {
 	k8sreport.PrintErrors()
 
@@ -23,30 +25,76 @@ func Write(ctx context.Context, k8sreport report.Report, option report.Option) e
 		}
 		return jwriter.Write(k8sreport)
 	case types.FormatTable:
-		separatedReports := report.SeparateMisconfigReports(k8sreport, option.Scanners, option.Components)
-
-		if option.Report == report.SummaryReport {
-			target := fmt.Sprintf("Summary Report for %s", k8sreport.ClusterName)
-			table.RenderTarget(option.Output, target, table.IsOutputToTerminal(option.Output))
-		}
-
-		for _, r := range separatedReports {
-			writer := &report.TableWriter{
-				Output:        option.Output,
-				Report:        option.Report,
-				Severities:    option.Severities,
-				ColumnHeading: report.ColumnHeading(option.Scanners, option.Components, r.Columns),
+		title := fmt.Sprintf("Summary Report for %s", k8sreport.ClusterName)
+		if option.Report != report.SummaryReport || !option.GroupByOwner {
+			if err := writeTable(ctx, k8sreport, option, title); err != nil {
+				return err
 			}
+			return report.PriorityWriter{Output: option.Output}.Write(k8sreport.Priorities)
+		}
 
-			if err := writer.Write(ctx, r.Report); err != nil {
+		// Each team gets its own summary
+		for _, owned := range k8sreport.GroupByOwner() {
+			owner := lo.Ternary(owned.Owner != "", owned.Owner, "none")
+			if err := writeTable(ctx, owned.Report, option, fmt.Sprintf("%s (owner: %s)", title, owner)); err != nil {
 				return err
 			}
 		}
-
-		return nil
+		return report.PriorityWriter{Output: option.Output}.Write(k8sreport.Priorities)
 	case types.FormatCycloneDX:
 		w := report.NewCycloneDXWriter(option.Output, cdx.BOMFileFormatJSON, option.APIVersion)
 		return w.Write(ctx, k8sreport.RootComponent)
 	}
 	return nil
 }
+
+// writeTable writes the results in the table format with the title of the summary report
+func writeTable(ctx context.Context, k8sreport report.Report, option report.Option, title string) error {
+	separatedReports := report.SeparateMisconfigReports(k8sreport, option.Scanners, option.Components)
+
+	if option.Report == report.SummaryReport {
+		table.RenderTarget(option.Output, title, table.IsOutputToTerminal(option.Output))
+	}
+
+	for _, r := range separatedReports {
+		writer := &report.TableWriter{
+			Output:        option.Output,
+			Report:        option.Report,
+			Severities:    option.Severities,
+			ColumnHeading: report.ColumnHeading(option.Scanners, option.Components, r.Columns),
+		}
+
+		if err := writer.Write(ctx, r.Report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteClusters writes the results of multiple clusters in the given format
+func WriteClusters(ctx context.Context, k8sreport report.MultiClusterReport, option report.Option) error {
+	switch option.Format {
+	case types.FormatJSON:
+		for _, r := range k8sreport.Clusters {
+			r.PrintErrors()
+		}
+		jwriter := report.JSONWriter{
+			Output: option.Output,
+			Report: option.Report,
+		}
+		return jwriter.WriteClusters(k8sreport)
+	case types.FormatTable:
+		for _, r := range k8sreport.Clusters {
+			// The summary report has its own header with the cluster name
+			if option.Report != report.SummaryReport {
+				target := fmt.Sprintf("Report for %s", r.ClusterName)
+				table.RenderTarget(option.Output, target, table.IsOutputToTerminal(option.Output))
+			}
+			if err := Write(ctx, r, option); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return xerrors.Errorf("%q format is not supported for multiple clusters", option.Format)
+}
@@ -3,6 +3,7 @@ package k8s
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"regexp"
 	"strings"
 	"testing"
@@ -400,6 +401,100 @@ Severities: C=CRITICAL H=HIGH M=MEDIUM L=LOW U=UNKNOWN`,
 	}
 }
 
+func TestWriteClusters(t *testing.T) {
+	rpt := report.MultiClusterReport{
+		Clusters: []report.Report{
+			{
+				ClusterName: "prod-eu",
+				Resources:   []report.Resource{deployOrionWithVulns},
+			},
+			{
+				ClusterName: "prod-us",
+				Resources:   []report.Resource{deployOrionWithVulns},
+			},
+		},
+	}
+	scanners := types.Scanners{types.VulnerabilityScanner}
+	components := []string{workloadComponent}
+
+	t.Run("json", func(t *testing.T) {
+		for _, r := range []string{AllReport, SummaryReport} {
+			output := bytes.Buffer{}
+			err := WriteClusters(context.Background(), rpt, report.Option{
+				Format:     jsonFormat,
+				Report:     r,
+				Output:     &output,
+				Scanners:   scanners,
+				Components: components,
+			})
+			require.NoError(t, err)
+
+			var got struct {
+				Clusters []struct {
+					ClusterName string
+				}
+			}
+			require.NoError(t, json.Unmarshal(output.Bytes(), &got))
+			require.Len(t, got.Clusters, 2, r)
+			assert.Equal(t, "prod-eu", got.Clusters[0].ClusterName, r)
+			assert.Equal(t, "prod-us", got.Clusters[1].ClusterName, r)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		output := bytes.Buffer{}
+		err := WriteClusters(context.Background(), rpt, report.Option{
+			Format:     tableFormat,
+			Report:     SummaryReport,
+			Output:     &output,
+			Scanners:   scanners,
+			Severities: []dbTypes.Severity{dbTypes.SeverityCritical},
+			Components: components,
+		})
+		require.NoError(t, err)
+
+		got := stripAnsi(output.String())
+		assert.Contains(t, got, "Summary Report for prod-eu")
+		assert.Contains(t, got, "Summary Report for prod-us")
+		assert.Less(t, strings.Index(got, "prod-eu"), strings.Index(got, "prod-us"))
+	})
+
+	t.Run("cyclonedx", func(t *testing.T) {
+		err := WriteClusters(context.Background(), rpt, report.Option{
+			Format: cycloneDXFormat,
+			Output: &bytes.Buffer{},
+		})
+		require.ErrorContains(t, err, "not supported for multiple clusters")
+	})
+}
+
+func TestWrite_GroupByOwner(t *testing.T) {
+	shop := deployOrionWithVulns
+	shop.Owner = "shop"
+	unowned := deployOrionWithVulns
+	unowned.Namespace = "kube-public"
+
+	output := bytes.Buffer{}
+	err := Write(context.Background(), report.Report{
+		ClusterName: "test",
+		Resources:   []report.Resource{unowned, shop},
+	}, report.Option{
+		Format:       tableFormat,
+		Report:       SummaryReport,
+		Output:       &output,
+		Scanners:     types.Scanners{types.VulnerabilityScanner},
+		Severities:   []dbTypes.Severity{dbTypes.SeverityCritical},
+		Components:   []string{workloadComponent},
+		GroupByOwner: true,
+	})
+	require.NoError(t, err)
+
+	got := stripAnsi(output.String())
+	assert.Contains(t, got, "Summary Report for test (owner: shop)")
+	assert.Contains(t, got, "Summary Report for test (owner: none)")
+	assert.Less(t, strings.Index(got, "owner: shop"), strings.Index(got, "owner: none"))
+}
+
 const ansi = "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
 
 var ansiRegexp = regexp.MustCompile(ansi)
@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	namespace = "trivy"
+
+	// pushJob is the job label of the metrics pushed to Pushgateway
+	pushJob = "trivy"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	scanDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scan_duration_seconds",
+		Help:      "Duration of scans in seconds.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+	})
+	scans = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scans_total",
+		Help:      "Number of scans by status.",
+	}, []string{"status"})
+	findings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "findings_total",
+		Help:      "Number of findings by type and severity.",
+	}, []string{"type", "severity"})
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Number of artifact and blob lookups in the cache by result.",
+	}, []string{"result"})
+
+	// The cache directory of the vulnerability DB, which is set after the flags are parsed
+	dbCacheDir   string
+	dbCacheDirMu sync.RWMutex
+)
+
+func init() {
+	registry.MustRegister(
+		scanDuration,
+		scans,
+		findings,
+		cacheLookups,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_age_seconds",
+			Help:      "Seconds since the vulnerability DB was built, or NaN if the DB is not downloaded.",
+		}, dbAge),
+	)
+}
+
+// SetCacheDir sets the cache directory where the metadata of the vulnerability DB is read for the DB age
+func SetCacheDir(dir string) {
+	dbCacheDirMu.Lock()
+	defer dbCacheDirMu.Unlock()
+	dbCacheDir = dir
+}
+
+func dbAge() float64 {
+	dbCacheDirMu.RLock()
+	dir := dbCacheDir
+	dbCacheDirMu.RUnlock()
+	if dir == "" {
+		return math.NaN()
+	}
+	meta, err := metadata.NewClient(dir).Get()
+	if err != nil || meta.UpdatedAt.IsZero() {
+		return math.NaN()
+	}
+	return time.Since(meta.UpdatedAt).Seconds()
+}
+
+// ObserveScan records the duration and the status of a scan started at start
+func ObserveScan(start time.Time, err error) {
+	scanDuration.Observe(time.Since(start).Seconds())
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	scans.WithLabelValues(status).Inc()
+}
+
+// RecordFindings counts the vulnerabilities, the failed misconfigurations, the secrets and the licenses by severity
+func RecordFindings(results types.Results) {
+	for _, result := range results {
+		for _, vuln := range result.Vulnerabilities {
+			findings.WithLabelValues("vulnerability", vuln.Severity).Inc()
+		}
+		for _, misconf := range result.Misconfigurations {
+			if misconf.Status == types.MisconfStatusFailure {
+				findings.WithLabelValues("misconfiguration", misconf.Severity).Inc()
+			}
+		}
+		for _, secret := range result.Secrets {
+			findings.WithLabelValues("secret", secret.Severity).Inc()
+		}
+		for _, license := range result.Licenses {
+			findings.WithLabelValues("license", license.Severity).Inc()
+		}
+	}
+}
+
+// Handler returns the handler exposing the metrics in the Prometheus format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Push pushes the metrics to the Pushgateway at url
+func Push(url string) error {
+	if err := push.New(url, pushJob).Gatherer(registry).Push(); err != nil {
+		return xerrors.Errorf("failed to push metrics to %s: %w", url, err)
+	}
+	return nil
+}
+
+// Cache counts the hits and misses of the artifact and blob lookups
+type Cache struct {
+	cache.Cache
+}
+
+// NewCache wraps the cache to count the hits and misses
+func NewCache(c cache.Cache) Cache {
+	return Cache{Cache: c}
+}
+
+func (c Cache) MissingBlobs(artifactID string, blobIDs []string) (bool, []string, error) {
+	missingArtifact, missingBlobIDs, err := c.Cache.MissingBlobs(artifactID, blobIDs)
+	if err != nil {
+		return missingArtifact, missingBlobIDs, err
+	}
+
+	misses := len(missingBlobIDs)
+	if missingArtifact {
+		misses++
+	}
+	hits := len(blobIDs) + 1 - misses
+	cacheLookups.WithLabelValues("hit").Add(float64(hits))
+	cacheLookups.WithLabelValues("miss").Add(float64(misses))
+	return missingArtifact, missingBlobIDs, nil
+}
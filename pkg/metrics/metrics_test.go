@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestObserveScan(t *testing.T) {
+	scans.Reset()
+
+	ObserveScan(time.Now(), nil)
+	ObserveScan(time.Now(), nil)
+	ObserveScan(time.Now(), errors.New("error"))
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(scans.WithLabelValues("success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(scans.WithLabelValues("failure")))
+}
+
+func TestRecordFindings(t *testing.T) {
+	findings.Reset()
+
+	RecordFindings(types.Results{
+		{
+			Vulnerabilities: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2024-0001", Vulnerability: dbTypes.Vulnerability{Severity: "CRITICAL"}},
+				{VulnerabilityID: "CVE-2024-0002", Vulnerability: dbTypes.Vulnerability{Severity: "CRITICAL"}},
+				{VulnerabilityID: "CVE-2024-0003", Vulnerability: dbTypes.Vulnerability{Severity: "LOW"}},
+			},
+			Misconfigurations: []types.DetectedMisconfiguration{
+				{ID: "AVD-KSV-0001", Severity: "HIGH", Status: types.MisconfStatusFailure},
+				{ID: "AVD-KSV-0002", Severity: "HIGH", Status: types.MisconfStatusPassed},
+			},
+		},
+		{
+			Secrets: []types.DetectedSecret{
+				{RuleID: "aws-access-key-id", Severity: "CRITICAL"},
+			},
+			Licenses: []types.DetectedLicense{
+				{Name: "GPL-3.0", Severity: "HIGH"},
+			},
+		},
+	})
+
+	tests := []struct {
+		findingType string
+		severity    string
+		want        float64
+	}{
+		{"vulnerability", "CRITICAL", 2},
+		{"vulnerability", "LOW", 1},
+		{"misconfiguration", "HIGH", 1},
+		{"secret", "CRITICAL", 1},
+		{"license", "HIGH", 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, testutil.ToFloat64(findings.WithLabelValues(tt.findingType, tt.severity)),
+			tt.findingType+"/"+tt.severity)
+	}
+	assert.Equal(t, 5, testutil.CollectAndCount(findings))
+}
+
+func TestCache_MissingBlobs(t *testing.T) {
+	cacheLookups.Reset()
+
+	c, err := cache.NewFSCache(t.TempDir())
+	require.NoError(t, err)
+	defer func() { _ = c.Close() }()
+	require.NoError(t, c.PutBlob("sha256:cached", ftypes.BlobInfo{SchemaVersion: ftypes.BlobJSONSchemaVersion}))
+
+	missingArtifact, missingBlobIDs, err := NewCache(c).MissingBlobs("sha256:artifact",
+		[]string{"sha256:cached", "sha256:missing"})
+	require.NoError(t, err)
+	assert.True(t, missingArtifact)
+	assert.Equal(t, []string{"sha256:missing"}, missingBlobIDs)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(cacheLookups.WithLabelValues("hit")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(cacheLookups.WithLabelValues("miss")))
+}
+
+func Test_dbAge(t *testing.T) {
+	SetCacheDir("")
+	assert.True(t, math.IsNaN(dbAge()))
+
+	dir := t.TempDir()
+	SetCacheDir(dir)
+	defer SetCacheDir("")
+	assert.True(t, math.IsNaN(dbAge()))
+
+	require.NoError(t, metadata.NewClient(dir).Update(metadata.Metadata{
+		Version:   2,
+		UpdatedAt: time.Now().Add(-2 * time.Hour),
+	}))
+	assert.InDelta(t, (2 * time.Hour).Seconds(), dbAge(), 60)
+}
+
+func TestHandler(t *testing.T) {
+	ObserveScan(time.Now(), nil)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := rec.Body.String()
+	for _, name := range []string{
+		"trivy_scan_duration_seconds",
+		"trivy_scans_total",
+		"trivy_db_age_seconds",
+	} {
+		assert.True(t, strings.Contains(body, name), name)
+	}
+}
+
+func TestPush(t *testing.T) {
+	var gotPath string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	require.NoError(t, Push(ts.URL))
+	assert.Equal(t, "/metrics/job/trivy", gotPath)
+}
@@ -0,0 +1,122 @@
+package fix
+
+import (
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Patch holds the fixed content of a single file
+type Patch struct {
+	FilePath string
+	// IDs of the misconfigurations fixed by this patch
+	IDs      []string
+	Original []byte
+	Fixed    []byte
+}
+
+// Diff returns the patch as a unified diff
+func (p Patch) Diff() (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(p.Original),
+		B:        splitLines(p.Fixed),
+		FromFile: "a/" + p.FilePath,
+		ToFile:   "b/" + p.FilePath,
+		Context:  3,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("unable to generate diff for %s: %w", p.FilePath, err)
+	}
+	return diff, nil
+}
+
+// splitLines splits the content into lines, keeping line endings
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// fixer applies a fix for a misconfiguration to the given file content.
+// It returns false if the misconfiguration couldn't be fixed.
+type fixer interface {
+	fix(content []byte, misconf types.DetectedMisconfiguration) ([]byte, bool)
+}
+
+// Generate builds patches for the failed misconfigurations which have a known fix.
+// The targets of the results are resolved against fsys.
+func Generate(fsys fs.FS, results types.Results) ([]Patch, error) {
+	var patches []Patch
+	for _, result := range results {
+		if result.Class != types.ClassConfig || len(result.Misconfigurations) == 0 {
+			continue
+		}
+
+		var misconfs []types.DetectedMisconfiguration
+		for _, misconf := range result.Misconfigurations {
+			if misconf.Status != types.MisconfStatusFailure {
+				continue
+			}
+			if _, ok := fixers[misconf.AVDID]; ok {
+				misconfs = append(misconfs, misconf)
+			}
+		}
+		if len(misconfs) == 0 {
+			continue
+		}
+
+		original, err := fs.ReadFile(fsys, result.Target)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to read %s: %w", result.Target, err)
+		}
+
+		// Fix from the bottom of the file so that the line numbers of the remaining causes stay valid
+		sort.SliceStable(misconfs, func(i, j int) bool {
+			return misconfs[i].CauseMetadata.StartLine > misconfs[j].CauseMetadata.StartLine
+		})
+
+		patch := Patch{
+			FilePath: result.Target,
+			Original: original,
+			Fixed:    original,
+		}
+		for _, misconf := range misconfs {
+			fixed, ok := fixers[misconf.AVDID].fix(patch.Fixed, misconf)
+			if !ok {
+				log.Logger.Debugf("Unable to fix %s in %s", misconf.AVDID, result.Target)
+				continue
+			}
+			patch.Fixed = fixed
+			patch.IDs = append(patch.IDs, misconf.AVDID)
+		}
+
+		if len(patch.IDs) > 0 {
+			patches = append(patches, patch)
+		}
+	}
+	return patches, nil
+}
+
+// Write writes the patches to the output as a single unified diff
+func Write(output io.Writer, patches []Patch) error {
+	for _, patch := range patches {
+		log.Logger.Infof("Fixes for %s: %s", patch.FilePath, strings.Join(patch.IDs, ", "))
+		diff, err := patch.Diff()
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(output, diff); err != nil {
+			return xerrors.Errorf("failed to write the patch: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package fix_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/misconf/fix"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const terraformSource = `resource "aws_s3_bucket_public_access_block" "example" {
+  bucket            = "example"
+  block_public_acls = false
+}
+
+resource "aws_s3_bucket" "example" {
+  bucket = "example"
+}
+`
+
+// The bucket has no public access block, so the bucket is the cause of the public access checks
+const terraformBucketSource = `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket_server_side_encryption_configuration" "logs" {
+  bucket = aws_s3_bucket.logs.id
+}
+`
+
+const kubernetesSource = `apiVersion: v1
+kind: Pod
+metadata:
+  name: example
+spec:
+  containers:
+    - name: app
+      image: nginx
+      securityContext:
+        privileged: true
+    - name: sidecar
+      image: busybox
+`
+
+func failure(id, message string, start, end int) types.DetectedMisconfiguration {
+	return types.DetectedMisconfiguration{
+		AVDID:   id,
+		Message: message,
+		Status:  types.MisconfStatusFailure,
+		CauseMetadata: ftypes.CauseMetadata{
+			StartLine: start,
+			EndLine:   end,
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.tf":   {Data: []byte(terraformSource)},
+		"bucket.tf": {Data: []byte(terraformBucketSource)},
+		"pod.yaml":  {Data: []byte(kubernetesSource)},
+	}
+
+	tests := []struct {
+		name    string
+		result  types.Result
+		wantIDs []string
+		want    string
+	}{
+		{
+			name: "terraform",
+			result: types.Result{
+				Target: "main.tf",
+				Class:  types.ClassConfig,
+				Misconfigurations: []types.DetectedMisconfiguration{
+					failure("AVD-AWS-0086", "", 3, 3),
+					failure("AVD-AWS-0087", "", 1, 4),
+					failure("AVD-AWS-0088", "", 6, 8),
+					failure("AVD-AWS-9999", "", 6, 8),
+				},
+			},
+			wantIDs: []string{"AVD-AWS-0088", "AVD-AWS-0086", "AVD-AWS-0087"},
+			want: `resource "aws_s3_bucket_public_access_block" "example" {
+  bucket            = "example"
+  block_public_acls = true
+  block_public_policy = true
+}
+
+resource "aws_s3_bucket" "example" {
+  bucket = "example"
+  server_side_encryption_configuration {
+    rule {
+      apply_server_side_encryption_by_default {
+        sse_algorithm = "aws:kms"
+      }
+    }
+  }
+}
+`,
+		},
+		{
+			name: "terraform without public access block",
+			result: types.Result{
+				Target: "bucket.tf",
+				Class:  types.ClassConfig,
+				Misconfigurations: []types.DetectedMisconfiguration{
+					failure("AVD-AWS-0086", "", 1, 3),
+					failure("AVD-AWS-0087", "", 1, 3),
+					failure("AVD-AWS-0093", "", 1, 3),
+					// Not fixable, as the nested blocks differ from the ones of the bucket
+					failure("AVD-AWS-0088", "", 5, 7),
+				},
+			},
+			wantIDs: []string{"AVD-AWS-0086", "AVD-AWS-0087", "AVD-AWS-0093"},
+			want: `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket_public_access_block" "logs" {
+  bucket = aws_s3_bucket.logs.id
+  block_public_acls = true
+  block_public_policy = true
+  restrict_public_buckets = true
+}
+
+resource "aws_s3_bucket_server_side_encryption_configuration" "logs" {
+  bucket = aws_s3_bucket.logs.id
+}
+`,
+		},
+		{
+			name: "kubernetes",
+			result: types.Result{
+				Target: "pod.yaml",
+				Class:  types.ClassConfig,
+				Misconfigurations: []types.DetectedMisconfiguration{
+					failure("AVD-KSV-0017", "Container 'app' of Pod 'example' should set 'securityContext.privileged' to false", 7, 10),
+					failure("AVD-KSV-0012", "Container 'app' of Pod 'example' should set 'securityContext.runAsNonRoot' to true", 7, 10),
+					failure("AVD-KSV-0012", "Container 'sidecar' of Pod 'example' should set 'securityContext.runAsNonRoot' to true", 11, 12),
+				},
+			},
+			wantIDs: []string{"AVD-KSV-0012", "AVD-KSV-0017", "AVD-KSV-0012"},
+			want: `apiVersion: v1
+kind: Pod
+metadata:
+  name: example
+spec:
+  containers:
+    - name: app
+      image: nginx
+      securityContext:
+        privileged: false
+        runAsNonRoot: true
+    - name: sidecar
+      image: busybox
+      securityContext:
+        runAsNonRoot: true
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patches, err := fix.Generate(fsys, types.Results{tt.result})
+			require.NoError(t, err)
+			require.Len(t, patches, 1)
+
+			assert.Equal(t, tt.wantIDs, patches[0].IDs)
+			assert.Equal(t, tt.want, string(patches[0].Fixed))
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	patch := fix.Patch{
+		FilePath: "main.tf",
+		IDs:      []string{"AVD-AWS-0086"},
+		Original: []byte("a\nb = false\nc\n"),
+		Fixed:    []byte("a\nb = true\nc\n"),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, fix.Write(&buf, []fix.Patch{patch}))
+
+	want := `--- a/main.tf
++++ b/main.tf
+@@ -1,3 +1,3 @@
+ a
+-b = false
++b = true
+ c
+`
+	assert.Equal(t, want, buf.String())
+}
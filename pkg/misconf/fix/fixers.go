@@ -0,0 +1,33 @@
+package fix
+
+// fixers contains the curated set of checks with an automated fix, keyed by AVD ID
+var fixers = map[string]fixer{
+	// S3 buckets
+	"AVD-AWS-0086": publicAccessBlockFix{attribute: "block_public_acls"},
+	"AVD-AWS-0087": publicAccessBlockFix{attribute: "block_public_policy"},
+	"AVD-AWS-0088": terraformFix{
+		resourceType: "aws_s3_bucket",
+		blocks: []string{
+			"server_side_encryption_configuration",
+			"rule",
+			"apply_server_side_encryption_by_default",
+		},
+		attribute: "sse_algorithm",
+		value:     `"aws:kms"`,
+	},
+	"AVD-AWS-0091": publicAccessBlockFix{attribute: "ignore_public_acls"},
+	"AVD-AWS-0093": publicAccessBlockFix{attribute: "restrict_public_buckets"},
+
+	// EC2 instances
+	"AVD-AWS-0028": terraformFix{
+		blocks:    []string{"metadata_options"},
+		attribute: "http_tokens",
+		value:     `"required"`,
+	},
+
+	// Kubernetes workloads
+	"AVD-KSV-0001": securityContextFix{field: "allowPrivilegeEscalation", value: "false"},
+	"AVD-KSV-0012": securityContextFix{field: "runAsNonRoot", value: "true"},
+	"AVD-KSV-0014": securityContextFix{field: "readOnlyRootFilesystem", value: "true"},
+	"AVD-KSV-0017": securityContextFix{field: "privileged", value: "false"},
+}
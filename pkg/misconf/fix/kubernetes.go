@@ -0,0 +1,173 @@
+package fix
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var containerNameRegex = regexp.MustCompile(`Container '([^']+)'`)
+
+// securityContextFix sets a field in the security context of a Kubernetes container
+type securityContextFix struct {
+	field string
+	value string
+}
+
+func (f securityContextFix) fix(content []byte, misconf types.DetectedMisconfiguration) ([]byte, bool) {
+	containers, err := findContainers(content)
+	if err != nil {
+		return nil, false
+	}
+
+	container := selectContainer(containers, misconf)
+	if container == nil {
+		return nil, false
+	}
+
+	lines := strings.Split(string(content), "\n")
+	securityContext := mappingValue(container, "securityContext")
+	switch {
+	case securityContext == nil:
+		last, ok := lastLine(container)
+		if !ok {
+			return nil, false
+		}
+		indent := strings.Repeat(" ", container.Content[0].Column-1)
+		lines = slices.Insert(lines, last,
+			indent+"securityContext:",
+			indent+"  "+f.field+": "+f.value,
+		)
+	case securityContext.Kind != yaml.MappingNode || securityContext.Style&yaml.FlowStyle != 0 || len(securityContext.Content) == 0:
+		// flow style and non-mapping values can't be patched without reformatting the document
+		return nil, false
+	default:
+		if value := mappingValue(securityContext, f.field); value != nil {
+			if value.Kind != yaml.ScalarNode || value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+				return nil, false
+			}
+			line := lines[value.Line-1]
+			lines[value.Line-1] = line[:value.Column-1] + f.value
+			break
+		}
+		last, ok := lastLine(securityContext)
+		if !ok {
+			return nil, false
+		}
+		indent := strings.Repeat(" ", securityContext.Content[0].Column-1)
+		lines = slices.Insert(lines, last, indent+f.field+": "+f.value)
+	}
+
+	return []byte(strings.Join(lines, "\n")), true
+}
+
+// findContainers returns the container mappings of all the documents in the content
+func findContainers(content []byte) ([]*yaml.Node, error) {
+	var containers []*yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		containers = append(containers, walkContainers(&doc)...)
+	}
+	return containers, nil
+}
+
+func walkContainers(node *yaml.Node) []*yaml.Node {
+	var containers []*yaml.Node
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			switch key.Value {
+			case "containers", "initContainers", "ephemeralContainers":
+				if value.Kind != yaml.SequenceNode {
+					continue
+				}
+				for _, c := range value.Content {
+					if c.Kind == yaml.MappingNode && c.Style&yaml.FlowStyle == 0 && len(c.Content) > 0 {
+						containers = append(containers, c)
+					}
+				}
+				continue
+			}
+			containers = append(containers, walkContainers(value)...)
+		}
+		return containers
+	}
+	for _, child := range node.Content {
+		containers = append(containers, walkContainers(child)...)
+	}
+	return containers
+}
+
+// selectContainer picks the container the misconfiguration is about,
+// using the container name from the message and the location of the cause.
+func selectContainer(containers []*yaml.Node, misconf types.DetectedMisconfiguration) *yaml.Node {
+	var name string
+	if m := containerNameRegex.FindStringSubmatch(misconf.Message); len(m) == 2 {
+		name = m[1]
+	}
+
+	var candidates []*yaml.Node
+	for _, c := range containers {
+		if name != "" {
+			if n := mappingValue(c, "name"); n == nil || n.Value != name {
+				continue
+			}
+		}
+		candidates = append(candidates, c)
+	}
+
+	cause := misconf.CauseMetadata
+	for _, c := range candidates {
+		if cause.StartLine > 0 && c.Line >= cause.StartLine && c.Line <= cause.EndLine {
+			return c
+		}
+	}
+
+	// Fall back to the only candidate when the location doesn't help
+	if name != "" && len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// lastLine returns the last line occupied by the node.
+// Multi-line scalars are not supported as their end can't be derived from the node.
+func lastLine(node *yaml.Node) (int, bool) {
+	if node.Kind == yaml.ScalarNode && (node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 || strings.Contains(node.Value, "\n")) {
+		return 0, false
+	}
+	last := node.Line
+	for _, child := range node.Content {
+		l, ok := lastLine(child)
+		if !ok {
+			return 0, false
+		}
+		last = max(last, l)
+	}
+	return last, true
+}
@@ -0,0 +1,179 @@
+package fix
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var resourceHeader = regexp.MustCompile(`^\s*resource\s+"([^"]+)"\s+"([^"]+)"`)
+
+// terraformFix sets an attribute of a Terraform resource,
+// creating the enclosing nested blocks when they are missing.
+type terraformFix struct {
+	// resourceType limits the fix to the resource type if set
+	resourceType string
+	blocks       []string
+	attribute    string
+	value        string
+}
+
+func (f terraformFix) fix(content []byte, misconf types.DetectedMisconfiguration) ([]byte, bool) {
+	lines := strings.Split(string(content), "\n")
+	start := misconf.CauseMetadata.StartLine - 1
+	if start < 0 || start >= len(lines) {
+		return nil, false
+	}
+
+	attr := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(f.attribute) + `\s*=`)
+
+	// The cause points to the attribute with a wrong value
+	if attr.MatchString(lines[start]) {
+		lines[start] = f.setAttribute(lines[start])
+		return []byte(strings.Join(lines, "\n")), true
+	}
+
+	// Otherwise the cause points to the resource or to one of the nested blocks
+	header := strings.TrimSpace(lines[start])
+	missing := f.blocks
+	if m := resourceHeader.FindStringSubmatch(header); m != nil && f.resourceType != "" && m[1] != f.resourceType {
+		return nil, false
+	} else if !strings.HasPrefix(header, "resource ") {
+		idx := slices.IndexFunc(f.blocks, func(block string) bool {
+			return strings.HasPrefix(header, block+" ") || strings.HasPrefix(header, block+"{")
+		})
+		if idx == -1 {
+			return nil, false
+		}
+		missing = f.blocks[idx+1:]
+	}
+
+	end, ok := closingBrace(lines, start)
+	if !ok {
+		return nil, false
+	}
+
+	if len(missing) == 0 {
+		depth := 1
+		for i := start + 1; i < end; i++ {
+			if depth == 1 && attr.MatchString(lines[i]) {
+				lines[i] = f.setAttribute(lines[i])
+				return []byte(strings.Join(lines, "\n")), true
+			}
+			depth += braceDelta(lines[i])
+		}
+	}
+
+	indent := leadingWhitespace(lines[start]) + "  "
+	var inserted []string
+	for i, block := range missing {
+		inserted = append(inserted, indent+strings.Repeat("  ", i)+block+" {")
+	}
+	inserted = append(inserted, indent+strings.Repeat("  ", len(missing))+f.attribute+" = "+f.value)
+	for i := len(missing) - 1; i >= 0; i-- {
+		inserted = append(inserted, indent+strings.Repeat("  ", i)+"}")
+	}
+
+	lines = slices.Insert(lines, end, inserted...)
+	return []byte(strings.Join(lines, "\n")), true
+}
+
+// publicAccessBlockFix sets an attribute of aws_s3_bucket_public_access_block.
+// When the bucket has no public access block, the cause is the bucket itself,
+// and a public access block referring to the bucket is added after it.
+type publicAccessBlockFix struct {
+	attribute string
+}
+
+func (f publicAccessBlockFix) fix(content []byte, misconf types.DetectedMisconfiguration) ([]byte, bool) {
+	tf := terraformFix{
+		resourceType: "aws_s3_bucket_public_access_block",
+		attribute:    f.attribute,
+		value:        "true",
+	}
+
+	lines := strings.Split(string(content), "\n")
+	start := misconf.CauseMetadata.StartLine - 1
+	if start < 0 || start >= len(lines) {
+		return nil, false
+	}
+	m := resourceHeader.FindStringSubmatch(lines[start])
+	if m == nil || m[1] != "aws_s3_bucket" {
+		return tf.fix(content, misconf)
+	}
+
+	// The public access block may have been added by the fix of another attribute
+	header := fmt.Sprintf(`resource "aws_s3_bucket_public_access_block" %q {`, m[2])
+	if i := slices.Index(lines, header); i >= 0 {
+		misconf.CauseMetadata.StartLine = i + 1
+		return tf.fix(content, misconf)
+	}
+
+	end, ok := closingBrace(lines, start)
+	if !ok {
+		return nil, false
+	}
+	lines = slices.Insert(lines, end+1,
+		"",
+		header,
+		fmt.Sprintf("  bucket = aws_s3_bucket.%s.id", m[2]),
+		fmt.Sprintf("  %s = true", f.attribute),
+		"}",
+	)
+	return []byte(strings.Join(lines, "\n")), true
+}
+
+// setAttribute replaces the value of the attribute, keeping the alignment of "="
+func (f terraformFix) setAttribute(line string) string {
+	idx := strings.Index(line, "=")
+	return line[:idx] + "= " + f.value
+}
+
+// closingBrace returns the index of the line closing the block opened at the given line
+func closingBrace(lines []string, start int) (int, bool) {
+	var depth int
+	for i := start; i < len(lines); i++ {
+		depth += braceDelta(lines[i])
+		if depth <= 0 {
+			if i == start {
+				// the block is opened and closed on the same line
+				return 0, false
+			}
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// braceDelta returns the difference between opening and closing braces in the line,
+// ignoring braces inside string literals and comments.
+func braceDelta(line string) int {
+	var (
+		delta  int
+		quoted bool
+	)
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && quoted:
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '#' || (c == '/' && i+1 < len(line) && line[i+1] == '/'):
+			return delta
+		case c == '{':
+			delta++
+		case c == '}':
+			delta--
+		}
+	}
+	return delta
+}
+
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
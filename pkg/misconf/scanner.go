@@ -57,16 +57,21 @@ type ScannerOption struct {
 	HelmValueFiles          []string
 	HelmFileValues          []string
 	HelmStringValues        []string
+	HelmDependencyCacheDir  string
+	HelmDownloadDeps        bool
 	TerraformTFVars         []string
 	CloudFormationParamVars []string
 	TfExcludeDownloaded     bool
 	K8sVersion              string
+	K8sCRDSchemas           []string
+	OfflineScan             bool
 }
 
 func (o *ScannerOption) Sort() {
 	sort.Strings(o.Namespaces)
 	sort.Strings(o.PolicyPaths)
 	sort.Strings(o.DataPaths)
+	sort.Strings(o.K8sCRDSchemas)
 }
 
 type Scanner struct {
@@ -186,7 +191,11 @@ func (s *Scanner) filterFS(fsys fs.FS) (fs.FS, error) {
 		}
 		defer file.Close()
 
-		if !s.hasFilePattern && !detection.IsType(path, rs, s.fileType) {
+		// Kustomization files are not Kubernetes manifests, but they are needed to render the final manifests
+		isKustomization := s.fileType == detection.FileTypeKubernetes && k8sscanner.IsKustomizationFile(path)
+		// CDK manifests are not CloudFormation templates, but they map the resources back to the CDK app
+		isCDKManifest := s.fileType == detection.FileTypeCloudFormation && cfparser.IsCDKManifest(path, rs)
+		if !s.hasFilePattern && !isKustomization && !isCDKManifest && !detection.IsType(path, rs, s.fileType) {
 			return true, nil
 		}
 		foundRelevantFile = true
@@ -257,6 +266,8 @@ func scannerOptions(t detection.FileType, opt ScannerOption) ([]options.ScannerO
 		return addTFOpts(opts, opt)
 	case detection.FileTypeCloudFormation:
 		return addCFOpts(opts, opt)
+	case detection.FileTypeKubernetes:
+		return addK8sOpts(opts, opt), nil
 	default:
 		return opts, nil
 	}
@@ -324,6 +335,19 @@ func addHelmOpts(opts []options.ScannerOption, scannerOption ScannerOption) []op
 		opts = append(opts, helm2.ScannerWithStringValues(scannerOption.HelmStringValues...))
 	}
 
+	if scannerOption.HelmDependencyCacheDir != "" {
+		opts = append(opts, helm2.ScannerWithDependencyCacheDir(scannerOption.HelmDependencyCacheDir))
+	}
+
+	opts = append(opts, helm2.ScannerWithDependencyDownload(scannerOption.HelmDownloadDeps))
+
+	return opts
+}
+
+func addK8sOpts(opts []options.ScannerOption, scannerOption ScannerOption) []options.ScannerOption {
+	if len(scannerOption.K8sCRDSchemas) > 0 {
+		opts = append(opts, k8sscanner.ScannerWithCRDSchemas(scannerOption.K8sCRDSchemas...))
+	}
 	return opts
 }
 
@@ -500,6 +524,37 @@ func NewCauseWithCode(underlying scan.Result) types.CauseMetadata {
 				}
 			}),
 		}
+		// The suppression comment of the block is included in the code so that it can be applied after scanning
+		if line, ok := suppressionComment(underlying, cause.Code.Lines); ok {
+			cause.Code.Lines = append([]types.Line{line}, cause.Code.Lines...)
+		}
 	}
 	return cause
 }
+
+// suppressionComment returns the line just above the code if it is a suppression comment,
+// e.g. "# trivy:ignore:AVD-AWS-0086 reason=..."
+func suppressionComment(underlying scan.Result, lines []types.Line) (types.Line, bool) {
+	if len(lines) == 0 || lines[0].Number <= 1 {
+		return types.Line{}, false
+	}
+	srcFS := underlying.Metadata().Range().GetFS()
+	if srcFS == nil {
+		return types.Line{}, false
+	}
+	content, err := fs.ReadFile(srcFS, strings.TrimPrefix(filepath.ToSlash(underlying.Range().GetLocalFilename()), "/"))
+	if err != nil {
+		return types.Line{}, false
+	}
+
+	rawLines := strings.Split(string(content), "\n")
+	number := lines[0].Number - 1
+	if number > len(rawLines) || !strings.Contains(rawLines[number-1], "trivy:ignore") {
+		return types.Line{}, false
+	}
+	return types.Line{
+		Number:      number,
+		Content:     rawLines[number-1],
+		Highlighted: rawLines[number-1],
+	}, true
+}
@@ -2,11 +2,13 @@ package misconf
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -198,3 +200,30 @@ func assertFS(t *testing.T, tmpDir string, f fs.FS, got []string, err error) {
 	require.NoError(t, err)
 	assert.True(t, stat.IsDir())
 }
+
+func TestScanner_Scan_SuppressionComment(t *testing.T) {
+	fsys := mapfs.New()
+	err := fsys.WriteVirtualFile("Dockerfile", []byte(`# trivy:ignore:AVD-DS-0001 reason=The tag is pinned by the build system
+FROM alpine:latest
+`), 0666)
+	require.NoError(t, err)
+
+	s, err := NewDockerfileScanner(nil, ScannerOption{})
+	require.NoError(t, err)
+
+	misconfs, err := s.Scan(context.Background(), fsys)
+	require.NoError(t, err)
+	require.Len(t, misconfs, 1)
+
+	failure, ok := lo.Find(misconfs[0].Failures, func(r types.MisconfResult) bool {
+		return r.AVDID == "AVD-DS-0001"
+	})
+	require.True(t, ok)
+	got := lo.Map(failure.Code.Lines, func(l types.Line, _ int) string {
+		return fmt.Sprintf("%d: %s", l.Number, l.Content)
+	})
+	assert.Equal(t, []string{
+		"1: # trivy:ignore:AVD-DS-0001 reason=The tag is pinned by the build system",
+		"2: FROM alpine:latest",
+	}, got)
+}
@@ -3,10 +3,12 @@ package module
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"sync"
 
 	"github.com/mailru/easyjson"
@@ -189,6 +191,50 @@ func (m *Manager) Close(ctx context.Context) error {
 	return m.cache.Close(ctx)
 }
 
+// AnalyzerModule represents a WASM module loaded as an analyzer.
+type AnalyzerModule interface {
+	Name() string
+	Version() int
+	Required(filePath string, info os.FileInfo) bool
+	Analyze(ctx context.Context, input analyzer.AnalysisInput) (*analyzer.AnalysisResult, error)
+	Close(ctx context.Context) error
+}
+
+type analyzerModule struct {
+	*wasmModule
+	cache wazero.CompilationCache
+}
+
+func (m analyzerModule) Close(ctx context.Context) error {
+	return errors.Join(m.wasmModule.Close(ctx), m.cache.Close(ctx))
+}
+
+// LoadAnalyzer loads the WASM module as an analyzer without registering it, e.g. to test the module with fixtures.
+// The module must be closed after use.
+func LoadAnalyzer(ctx context.Context, path string) (AnalyzerModule, error) {
+	wasmCode, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("file read error: %w", err)
+	}
+
+	cache := wazero.NewCompilationCache()
+	p, err := newWASMPlugin(ctx, cache, wasmCode)
+	if err != nil {
+		_ = cache.Close(ctx)
+		return nil, xerrors.Errorf("WASM module init error %s: %w", path, err)
+	} else if p == nil {
+		_ = cache.Close(ctx)
+		return nil, xerrors.Errorf("%s is not compatible with the module API version %d", path, tapi.Version)
+	} else if !p.isAnalyzer {
+		_ = errors.Join(p.Close(ctx), cache.Close(ctx))
+		return nil, xerrors.Errorf("%s is not an analyzer", path)
+	}
+	return analyzerModule{
+		wasmModule: p,
+		cache:      cache,
+	}, nil
+}
+
 func splitPtrSize(u uint64) (uint32, uint32) {
 	ptr := uint32(u >> 32)
 	size := uint32(u)
@@ -471,6 +517,11 @@ func (m *wasmModule) Analyze(ctx context.Context, input analyzer.AnalysisInput)
 		return nil, xerrors.Errorf("invalid return value: %w", err)
 	}
 
+	// Modules see the absolute path, while the file paths are relative to the artifact in Trivy
+	for i, app := range result.Applications {
+		result.Applications[i].FilePath = lo.Ternary(app.FilePath == "", input.FilePath, strings.TrimPrefix(app.FilePath, "/"))
+	}
+
 	return &result, nil
 }
 
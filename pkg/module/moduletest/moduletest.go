@@ -0,0 +1,117 @@
+package moduletest
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/module"
+)
+
+const (
+	inputDir = "input"
+	wantFile = "want.json"
+)
+
+// moduleResult is the part of the analysis result that modules can return
+type moduleResult struct {
+	Applications    []types.Application    `json:",omitempty"`
+	CustomResources []types.CustomResource `json:",omitempty"`
+}
+
+// Run tests the analyzer of the WASM module with fixtures.
+// Each directory under "dir" is a test case, which has the following files.
+//
+//	<test case>/input:     the files to analyze as if they were in the scanned artifact
+//	<test case>/want.json: the expected applications and custom resources, e.g. {"Applications": [...]}
+//
+// The files required by the module are analyzed in the same way as Trivy does,
+// then the merged result is compared with "want.json".
+func Run(t *testing.T, wasmFile, dir string) {
+	ctx := context.Background()
+	mod, err := module.LoadAnalyzer(ctx, wasmFile)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, mod.Close(ctx))
+	})
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		t.Run(entry.Name(), func(t *testing.T) {
+			got, err := Analyze(ctx, mod, filepath.Join(dir, entry.Name(), inputDir))
+			require.NoError(t, err)
+
+			want, err := os.ReadFile(filepath.Join(dir, entry.Name(), wantFile))
+			require.NoError(t, err)
+
+			b, err := json.Marshal(moduleResult{
+				Applications:    got.Applications,
+				CustomResources: got.CustomResources,
+			})
+			require.NoError(t, err)
+			assert.JSONEq(t, string(want), string(b))
+		})
+	}
+}
+
+// Analyze analyzes the files under "dir" required by the module and returns the merged result.
+func Analyze(ctx context.Context, mod module.AnalyzerModule, dir string) (*analyzer.AnalysisResult, error) {
+	result := analyzer.NewAnalysisResult()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !mod.Required(rel, info) {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		res, err := mod.Analyze(ctx, analyzer.AnalysisInput{
+			Dir:      dir,
+			FilePath: rel,
+			Info:     info,
+			Content:  f,
+		})
+		if err != nil {
+			return xerrors.Errorf("analyze error %s: %w", rel, err)
+		}
+		result.Merge(res)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("walk error: %w", err)
+	}
+	result.Sort()
+	return result, nil
+}
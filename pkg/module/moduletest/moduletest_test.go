@@ -0,0 +1,22 @@
+package moduletest_test
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/aquasecurity/trivy/pkg/module/moduletest"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		// WASM tests difficult on Windows
+		t.Skip("Test satisfied adequately by Linux tests")
+	}
+
+	wasmFile := "testdata/lockfile/lockfile.wasm"
+	if _, err := os.Stat(wasmFile); err != nil {
+		t.Skip("missing WASM modules, try 'mage test:unit' or 'mage test:generateModules'")
+	}
+	moduletest.Run(t, wasmFile, "testdata/fixtures")
+}
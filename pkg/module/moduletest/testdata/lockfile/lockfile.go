@@ -0,0 +1,79 @@
+//go:generate tinygo build -o lockfile.wasm -scheduler=none -target=wasi --no-debug lockfile.go
+//go:build tinygo.wasm
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/module/serialize"
+	"github.com/aquasecurity/trivy/pkg/module/wasm"
+)
+
+const (
+	moduleVersion = 1
+	moduleName    = "lockfile"
+)
+
+func main() {
+	wasm.RegisterModule(LockfileModule{})
+}
+
+// LockfileModule parses "acme.lock" of an internal package manager, which has "<name>@<version>" per line.
+type LockfileModule struct{}
+
+func (LockfileModule) Version() int {
+	return moduleVersion
+}
+
+func (LockfileModule) Name() string {
+	return moduleName
+}
+
+func (LockfileModule) RequiredFiles() []string {
+	return []string{
+		`acme\.lock$`,
+	}
+}
+
+func (LockfileModule) Analyze(filePath string) (*serialize.AnalysisResult, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pkgs types.Packages
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, version, ok := strings.Cut(line, "@")
+		if !ok {
+			return nil, fmt.Errorf("invalid line: %s", line)
+		}
+		pkgs = append(pkgs, types.Package{
+			ID:      line,
+			Name:    name,
+			Version: version,
+		})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &serialize.AnalysisResult{
+		Applications: []types.Application{
+			{
+				Type:      "acme",
+				Libraries: pkgs,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,31 @@
+package serialize
+
+import (
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+)
+
+// AnalysisResult is separated from types.go as easyjson doesn't support the interfaces in types.Result.
+//
+// $ easyjson ./pkg/module/serialize/analysis.go
+
+//easyjson:json
+type AnalysisResult struct {
+	// TODO: support other fields as well
+	// OS                   *types.OS
+	// Repository           *types.Repository
+	// PackageInfos         []types.PackageInfo
+	// Secrets              []types.Secret
+	// SystemInstalledFiles []string // A list of files installed by OS package manager
+
+	// Applications are the packages parsed from lock files and manifests, e.g. of internal package managers.
+	// FilePath of the application defaults to the analyzed file.
+	Applications []ftypes.Application
+
+	CustomResources []CustomResource
+}
+
+type CustomResource struct {
+	Type     string
+	FilePath string
+	Data     interface{}
+}
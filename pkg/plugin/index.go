@@ -0,0 +1,173 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/go-version/pkg/semver"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/oci"
+)
+
+const (
+	DefaultIndexURL = "https://aquasecurity.github.io/trivy-plugin-index/v1/index.yaml"
+
+	indexFile    = "index.yaml"
+	indexVersion = 1
+)
+
+// Index represents the plugin index, which maps plugin names to their repositories.
+//
+// e.g.
+//
+//	version: 1
+//	plugins:
+//	  - name: count
+//	    version: 0.2.0
+//	    maintainer: aquasecurity
+//	    summary: A Trivy output plugin that counts the number of vulnerabilities
+//	    repository: github.com/aquasecurity/trivy-output-plugin-count
+type Index struct {
+	Version int          `yaml:"version"`
+	Plugins []IndexEntry `yaml:"plugins"`
+}
+
+// IndexEntry represents a plugin in the index.
+type IndexEntry struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"` // The latest version. The plugin repository must have the tag "v<version>".
+	Maintainer string `yaml:"maintainer"`
+	Summary    string `yaml:"summary"`
+	Repository string `yaml:"repository"`
+}
+
+// source returns the URL to download the plugin in the version, or the default branch if the version is empty.
+func (e IndexEntry) source(ver string) string {
+	if ver == "" {
+		return e.Repository
+	}
+	return fmt.Sprintf("%s?ref=v%s", e.Repository, strings.TrimPrefix(ver, "v"))
+}
+
+func (i Index) lookup(name string) (IndexEntry, bool) {
+	for _, e := range i.Plugins {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return IndexEntry{}, false
+}
+
+// fetchIndex downloads the index from the HTTP(S) URL or the OCI repository.
+func fetchIndex(ctx context.Context, opts Options) (Index, error) {
+	log.Logger.Debugf("Fetching the plugin index from %s...", opts.IndexURL)
+
+	var b []byte
+	var err error
+	if strings.HasPrefix(opts.IndexURL, "https://") || strings.HasPrefix(opts.IndexURL, "http://") {
+		b, err = fetchHTTPIndex(ctx, opts.IndexURL)
+	} else {
+		b, err = fetchOCIIndex(ctx, opts)
+	}
+	if err != nil {
+		return Index{}, err
+	}
+
+	var index Index
+	if err = yaml.Unmarshal(b, &index); err != nil {
+		return Index{}, xerrors.Errorf("yaml decode error: %w", err)
+	} else if index.Version != indexVersion {
+		return Index{}, xerrors.Errorf("unsupported index version: %d", index.Version)
+	}
+	return index, nil
+}
+
+func fetchHTTPIndex(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create a request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to fetch the plugin index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("failed to fetch the plugin index: %s", resp.Status)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read the plugin index: %w", err)
+	}
+	return b, nil
+}
+
+func fetchOCIIndex(ctx context.Context, opts Options) ([]byte, error) {
+	art, err := oci.NewArtifact(opts.IndexURL, true, opts.RegistryOptions)
+	if err != nil {
+		return nil, xerrors.Errorf("OCI artifact error: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "trivy-plugin-index")
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create a temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err = art.Download(ctx, tempDir, oci.DownloadOption{Filename: indexFile}); err != nil {
+		return nil, xerrors.Errorf("failed to download the plugin index: %w", err)
+	}
+	b, err := os.ReadFile(filepath.Join(tempDir, indexFile))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read the plugin index: %w", err)
+	}
+	return b, nil
+}
+
+// UpdatePolicy represents which version bumps "trivy plugin update" accepts.
+type UpdatePolicy string
+
+const (
+	UpdatePolicyNone  UpdatePolicy = "none"
+	UpdatePolicyPatch UpdatePolicy = "patch"
+	UpdatePolicyMinor UpdatePolicy = "minor"
+	UpdatePolicyMajor UpdatePolicy = "major"
+)
+
+// Allows reports whether the policy accepts updating the plugin from the current version to the latest one.
+func (p UpdatePolicy) Allows(current, latest string) (bool, error) {
+	cv, err := semver.Parse(current)
+	if err != nil {
+		return false, xerrors.Errorf("invalid version %q: %w", current, err)
+	}
+	lv, err := semver.Parse(latest)
+	if err != nil {
+		return false, xerrors.Errorf("invalid version %q: %w", latest, err)
+	}
+
+	if !lv.GreaterThan(cv) {
+		return false, nil
+	}
+
+	switch p {
+	case UpdatePolicyNone:
+		return false, nil
+	case UpdatePolicyPatch:
+		return lv.Major().Compare(cv.Major()) == 0 && lv.Minor().Compare(cv.Minor()) == 0, nil
+	case UpdatePolicyMinor:
+		return lv.Major().Compare(cv.Major()) == 0, nil
+	case UpdatePolicyMajor, "":
+		return true, nil
+	default:
+		return false, xerrors.Errorf("unknown update policy: %s", p)
+	}
+}
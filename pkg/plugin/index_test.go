@@ -0,0 +1,89 @@
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/plugin"
+)
+
+func TestUpdatePolicy_Allows(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  plugin.UpdatePolicy
+		current string
+		latest  string
+		want    bool
+		wantErr string
+	}{
+		{
+			name:    "patch policy with patch update",
+			policy:  plugin.UpdatePolicyPatch,
+			current: "0.1.0",
+			latest:  "0.1.2",
+			want:    true,
+		},
+		{
+			name:    "patch policy with minor update",
+			policy:  plugin.UpdatePolicyPatch,
+			current: "0.1.0",
+			latest:  "0.2.0",
+			want:    false,
+		},
+		{
+			name:    "minor policy with minor update",
+			policy:  plugin.UpdatePolicyMinor,
+			current: "1.1.0",
+			latest:  "1.3.0",
+			want:    true,
+		},
+		{
+			name:    "minor policy with major update",
+			policy:  plugin.UpdatePolicyMinor,
+			current: "1.1.0",
+			latest:  "2.0.0",
+			want:    false,
+		},
+		{
+			name:    "major policy with major update",
+			policy:  plugin.UpdatePolicyMajor,
+			current: "1.1.0",
+			latest:  "2.0.0",
+			want:    true,
+		},
+		{
+			name:    "none policy",
+			policy:  plugin.UpdatePolicyNone,
+			current: "1.1.0",
+			latest:  "1.1.1",
+			want:    false,
+		},
+		{
+			name:    "downgrade",
+			policy:  plugin.UpdatePolicyMajor,
+			current: "1.1.0",
+			latest:  "1.0.0",
+			want:    false,
+		},
+		{
+			name:    "invalid version",
+			policy:  plugin.UpdatePolicyMajor,
+			current: "1.1.0",
+			latest:  "latest",
+			wantErr: `invalid version "latest"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.Allows(tt.current, tt.latest)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package plugin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// Lockfile pins the installed plugins so that the same plugins are installed everywhere.
+//
+// e.g.
+//
+//	plugins:
+//	  - name: count
+//	    version: 0.2.0
+//	    source: github.com/aquasecurity/trivy-output-plugin-count?ref=v0.2.0
+//	    digest: sha256:9b4e...
+//	    executables:
+//	      linux/amd64: sha256:2c26...
+type Lockfile struct {
+	Plugins []LockedPlugin `yaml:"plugins"`
+
+	path string
+}
+
+// LockedPlugin represents a plugin pinned in the lockfile.
+type LockedPlugin struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	Source  string `yaml:"source"`
+
+	// Digest is the digest of plugin.yaml
+	Digest string `yaml:"digest"`
+
+	// Executables are the digests of the executable per platform, e.g. "linux/amd64".
+	// The digest for a platform is recorded when the plugin is installed on the platform for the first time.
+	Executables map[string]string `yaml:"executables,omitempty"`
+}
+
+// loadLockfile loads the lockfile. The returned lockfile is nil if the path is empty, i.e. plugins are not pinned.
+func loadLockfile(path string) (*Lockfile, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	lock := &Lockfile{path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lock, nil
+	} else if err != nil {
+		return nil, xerrors.Errorf("failed to read the lockfile: %w", err)
+	}
+
+	if err = yaml.Unmarshal(b, lock); err != nil {
+		return nil, xerrors.Errorf("yaml decode error: %w", err)
+	}
+	return lock, nil
+}
+
+// find returns the plugin locked by the name or the source.
+func (l *Lockfile) find(nameOrSource string) (LockedPlugin, bool) {
+	if l == nil {
+		return LockedPlugin{}, false
+	}
+	for _, p := range l.Plugins {
+		if p.Name == nameOrSource || p.Source == nameOrSource {
+			return p, true
+		}
+	}
+	return LockedPlugin{}, false
+}
+
+// verify checks the digests of plugin.yaml and the executable downloaded from the source match the locked ones.
+func (l *Lockfile) verify(source, digest, platform, execDigest string) error {
+	locked, ok := l.find(source)
+	if !ok || locked.Source != source {
+		return nil
+	}
+	if locked.Digest != digest {
+		return xerrors.Errorf("digest mismatch of plugin.yaml in %s, locked: %s, got: %s", source, locked.Digest, digest)
+	}
+	if d, ok := locked.Executables[platform]; ok && d != execDigest {
+		return xerrors.Errorf("digest mismatch of the %s executable in %s, locked: %s, got: %s", platform, source, d, execDigest)
+	}
+	return nil
+}
+
+// lock adds or replaces the plugin in the lockfile and saves it.
+func (l *Lockfile) lock(p LockedPlugin, platform, execDigest string) error {
+	if l == nil {
+		return nil
+	}
+
+	idx := slices.IndexFunc(l.Plugins, func(lp LockedPlugin) bool {
+		return lp.Name == p.Name
+	})
+	if idx >= 0 && l.Plugins[idx].Source == p.Source && l.Plugins[idx].Digest == p.Digest {
+		// Keep the digests of the executables for the other platforms
+		p.Executables = l.Plugins[idx].Executables
+	}
+	if p.Executables == nil {
+		p.Executables = make(map[string]string)
+	}
+	p.Executables[platform] = execDigest
+
+	if idx >= 0 {
+		l.Plugins[idx] = p
+	} else {
+		l.Plugins = append(l.Plugins, p)
+	}
+	slices.SortFunc(l.Plugins, func(a, b LockedPlugin) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	b, err := yaml.Marshal(l)
+	if err != nil {
+		return xerrors.Errorf("yaml encode error: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(l.path), 0o750); err != nil {
+		return xerrors.Errorf("failed to create the lockfile directory: %w", err)
+	}
+	if err = os.WriteFile(l.path, b, 0o644); err != nil {
+		return xerrors.Errorf("failed to write the lockfile: %w", err)
+	}
+	return nil
+}
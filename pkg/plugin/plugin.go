@@ -10,10 +10,12 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/samber/lo"
 	"golang.org/x/xerrors"
 	"gopkg.in/yaml.v3"
 
 	"github.com/aquasecurity/trivy/pkg/downloader"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/utils/fsutils"
 )
@@ -50,6 +52,10 @@ type Platform struct {
 	Selector *Selector
 	URI      string
 	Bin      string
+
+	// Digest is the digest of the execution file, e.g. "sha256:2c26...".
+	// It is verified after downloading if specified.
+	Digest string
 }
 
 // Selector represents the environment.
@@ -58,9 +64,26 @@ type Selector struct {
 	Arch string
 }
 
+// Options represents how plugins are resolved, pinned and verified on installation and update.
+type Options struct {
+	// IndexURL is the HTTP(S) URL or the OCI repository of the plugin index
+	IndexURL string
+
+	// Lockfile is the path to the lockfile pinning the installed plugins. Plugins are not pinned if empty.
+	Lockfile string
+
+	// PublicKeys are the paths to the Ed25519 public keys in PEM to verify the signature of plugin.yaml.
+	// The signature is not verified if empty.
+	PublicKeys []string
+
+	UpdatePolicy    UpdatePolicy
+	RegistryOptions ftypes.RegistryOptions
+}
+
 type RunOptions struct {
-	Args  []string
-	Stdin io.Reader
+	Args   []string
+	Stdin  io.Reader
+	Stdout io.Writer
 }
 
 func (p Plugin) Cmd(ctx context.Context, opts RunOptions) (*exec.Cmd, error) {
@@ -77,6 +100,9 @@ func (p Plugin) Cmd(ctx context.Context, opts RunOptions) (*exec.Cmd, error) {
 		cmd.Stdin = opts.Stdin
 	}
 	cmd.Stdout = os.Stdout
+	if opts.Stdout != nil {
+		cmd.Stdout = opts.Stdout
+	}
 	cmd.Stderr = os.Stderr
 	cmd.Env = os.Environ()
 
@@ -143,18 +169,32 @@ func (p Plugin) selectPlatform() (Platform, error) {
 	return Platform{}, xerrors.New("platform not found")
 }
 
-func (p Plugin) install(ctx context.Context, dst, pwd string) error {
+// install downloads the execution file and returns its digest
+func (p Plugin) install(ctx context.Context, dst, pwd string) (string, error) {
 	log.Logger.Debugf("Installing the plugin to %s...", dst)
 	platform, err := p.selectPlatform()
 	if err != nil {
-		return xerrors.Errorf("platform selection error: %w", err)
+		return "", xerrors.Errorf("platform selection error: %w", err)
 	}
 
 	log.Logger.Debugf("Downloading the execution file from %s...", platform.URI)
 	if err = downloader.Download(ctx, platform.URI, dst, pwd); err != nil {
-		return xerrors.Errorf("unable to download the execution file (%s): %w", platform.URI, err)
+		return "", xerrors.Errorf("unable to download the execution file (%s): %w", platform.URI, err)
 	}
-	return nil
+
+	d, err := fileDigest(filepath.Join(dst, platform.Bin))
+	if err != nil {
+		return "", xerrors.Errorf("unable to calculate the digest of the execution file: %w", err)
+	}
+	if platform.Digest != "" && platform.Digest != d {
+		return "", xerrors.Errorf("digest mismatch of the execution file (%s), want: %s, got: %s", platform.URI, platform.Digest, d)
+	}
+	return d, nil
+}
+
+// platform returns the platform of the plugin runtime, e.g. "linux/amd64"
+func (p Plugin) platform() string {
+	return lo.Ternary(p.GOOS != "", p.GOOS, runtime.GOOS) + "/" + lo.Ternary(p.GOARCH != "", p.GOARCH, runtime.GOARCH)
 }
 
 func (p Plugin) dir() (string, error) {
@@ -167,11 +207,23 @@ func (p Plugin) dir() (string, error) {
 }
 
 // Install installs a plugin
-func Install(ctx context.Context, url string, force bool) (Plugin, error) {
-	// Replace short names with full qualified names
-	// e.g. kubectl => github.com/aquasecurity/trivy-plugin-kubectl
-	if v, ok := officialPlugins[url]; ok {
-		url = v
+//
+// The plugin can be specified by the URL, the file path or the name in the index with an optional version, e.g. "count@0.2.0".
+// With the lockfile, the plugin is installed from the locked source and the digests are verified.
+func Install(ctx context.Context, name string, force bool, opts Options) (Plugin, error) {
+	return install(ctx, name, force, false, opts)
+}
+
+// install installs the plugin. If relock is true, the digests are not verified with the lockfile but updated.
+func install(ctx context.Context, name string, force, relock bool, opts Options) (Plugin, error) {
+	lock, err := loadLockfile(opts.Lockfile)
+	if err != nil {
+		return Plugin{}, xerrors.Errorf("lockfile error: %w", err)
+	}
+
+	url, err := resolve(ctx, name, lock, relock, opts)
+	if err != nil {
+		return Plugin{}, xerrors.Errorf("failed to resolve %s: %w", name, err)
 	}
 
 	if !force {
@@ -194,12 +246,27 @@ func Install(ctx context.Context, url string, force bool) (Plugin, error) {
 		return Plugin{}, xerrors.Errorf("failed to load the plugin metadata: %w", err)
 	}
 
+	metadataPath := filepath.Join(tempDir, configFile)
+	if err = verifySignature(plugin, metadataPath, filepath.Join(tempDir, signatureFile), opts.PublicKeys); err != nil {
+		return Plugin{}, xerrors.Errorf("signature verification error: %w", err)
+	}
+	metadataDigest, err := fileDigest(metadataPath)
+	if err != nil {
+		return Plugin{}, xerrors.Errorf("unable to calculate the digest of plugin.yaml: %w", err)
+	}
+
 	pluginDir, err := plugin.dir()
 	if err != nil {
 		return Plugin{}, xerrors.Errorf("failed to determine the plugin dir: %w", err)
 	}
 
-	if err = plugin.install(ctx, pluginDir, tempDir); err != nil {
+	execDigest, err := plugin.install(ctx, pluginDir, tempDir)
+	if err == nil && !relock {
+		err = lock.verify(url, metadataDigest, plugin.platform(), execDigest)
+	}
+	if err != nil {
+		// Remove the execution file not to leave the unverified plugin
+		_ = os.RemoveAll(pluginDir)
 		return Plugin{}, xerrors.Errorf("failed to install the plugin: %w", err)
 	}
 
@@ -208,9 +275,54 @@ func Install(ctx context.Context, url string, force bool) (Plugin, error) {
 		return Plugin{}, xerrors.Errorf("failed to copy plugin.yaml: %w", err)
 	}
 
+	err = lock.lock(LockedPlugin{
+		Name:    plugin.Name,
+		Version: plugin.Version,
+		Source:  url,
+		Digest:  metadataDigest,
+	}, plugin.platform(), execDigest)
+	if err != nil {
+		return Plugin{}, xerrors.Errorf("lockfile error: %w", err)
+	}
+
 	return plugin, nil
 }
 
+// resolve returns the URL to download the plugin from.
+// File paths and URLs are returned as is, while plugin names are resolved with the lockfile and the index.
+func resolve(ctx context.Context, name string, lock *Lockfile, relock bool, opts Options) (string, error) {
+	if _, err := os.Stat(name); err == nil || strings.ContainsAny(name, `/\:`) {
+		return name, nil
+	}
+
+	pluginName, ver, _ := strings.Cut(name, "@")
+
+	// The locked source is used unless the version is specified
+	if locked, ok := lock.find(pluginName); ok && ver == "" && !relock {
+		log.Logger.Debugf("Installing the locked version %s of %s", locked.Version, pluginName)
+		return locked.Source, nil
+	}
+
+	// Replace short names with full qualified names
+	// e.g. kubectl => github.com/aquasecurity/trivy-plugin-kubectl
+	if v, ok := officialPlugins[pluginName]; ok {
+		return IndexEntry{Repository: v}.source(ver), nil
+	}
+
+	if opts.IndexURL == "" {
+		return name, nil
+	}
+	index, err := fetchIndex(ctx, opts)
+	if err != nil {
+		return "", xerrors.Errorf("plugin index error: %w", err)
+	}
+	entry, ok := index.lookup(pluginName)
+	if !ok {
+		return "", xerrors.Errorf("%s is not found in the plugin index", pluginName)
+	}
+	return entry.source(lo.Ternary(ver != "", ver, entry.Version)), nil
+}
+
 // Uninstall installs the plugin
 func Uninstall(name string) error {
 	pluginDir := filepath.Join(dir(), name)
@@ -252,15 +364,44 @@ func List() (string, error) {
 	return strings.Join(pluginList, "\n"), nil
 }
 
-// Update updates an existing plugin
-func Update(name string) error {
+// Update updates an existing plugin.
+// If the plugin is in the index, it is updated to the latest version only when the update policy allows.
+func Update(ctx context.Context, name string, opts Options) error {
 	plugin, err := load(name)
 	if err != nil {
 		return xerrors.Errorf("plugin load error: %w", err)
 	}
 
+	if opts.UpdatePolicy == UpdatePolicyNone {
+		log.Logger.Infof("The %s plugin is not updated by the update policy %q", name, opts.UpdatePolicy)
+		return nil
+	}
+
 	log.Logger.Infof("Updating plugin '%s'", name)
-	updated, err := Install(nil, plugin.Repository, true)
+	target := plugin.Repository
+	if opts.IndexURL != "" && !IsPredefined(name) {
+		index, err := fetchIndex(ctx, opts)
+		if err != nil {
+			return xerrors.Errorf("plugin index error: %w", err)
+		}
+		if entry, ok := index.lookup(name); ok && entry.Version != "" {
+			if strings.TrimPrefix(entry.Version, "v") == strings.TrimPrefix(plugin.Version, "v") {
+				log.Logger.Infof("The %s plugin is the latest version. [%s]", name, plugin.Version)
+				return nil
+			}
+			allowed, err := opts.UpdatePolicy.Allows(plugin.Version, entry.Version)
+			if err != nil {
+				return xerrors.Errorf("update policy error: %w", err)
+			} else if !allowed {
+				log.Logger.Infof("The %s plugin is kept at %s by the update policy %q. [latest: %s]",
+					name, plugin.Version, opts.UpdatePolicy, entry.Version)
+				return nil
+			}
+			target = name + "@" + entry.Version
+		}
+	}
+
+	updated, err := install(ctx, target, true, true, opts)
 	if err != nil {
 		return xerrors.Errorf("unable to perform an update installation: %w", err)
 	}
@@ -273,6 +414,20 @@ func Update(name string) error {
 	return nil
 }
 
+// UpdateAll updates all the installed plugins
+func UpdateAll(ctx context.Context, opts Options) error {
+	plugins, err := LoadAll()
+	if err != nil {
+		return xerrors.Errorf("unable to load plugins: %w", err)
+	}
+	for _, p := range plugins {
+		if err = Update(ctx, p.Name, opts); err != nil {
+			return xerrors.Errorf("unable to update %s: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
 // LoadAll loads all plugins
 func LoadAll() ([]Plugin, error) {
 	pluginsDir := dir()
@@ -312,7 +467,7 @@ func Start(ctx context.Context, name string, opts RunOptions) (Wait, error) {
 
 // RunWithURL runs the plugin with URL
 func RunWithURL(ctx context.Context, url string, opts RunOptions) error {
-	plugin, err := Install(ctx, url, false)
+	plugin, err := Install(ctx, url, false, Options{})
 	if err != nil {
 		return xerrors.Errorf("plugin install error: %w", err)
 	}
@@ -2,6 +2,15 @@ package plugin_test
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -263,7 +272,7 @@ func TestInstall(t *testing.T) {
 			dst := t.TempDir()
 			os.Setenv("XDG_DATA_HOME", dst)
 
-			got, err := plugin.Install(context.Background(), tt.url, false)
+			got, err := plugin.Install(context.Background(), tt.url, false, plugin.Options{})
 			if tt.wantErr != "" {
 				require.NotNil(t, err)
 				assert.Contains(t, err.Error(), tt.wantErr)
@@ -427,7 +436,7 @@ description: A simple test plugin`
 	verifyVersion(t, pluginName, "0.0.5")
 
 	// Update the existing plugin
-	err = plugin.Update(pluginName)
+	err = plugin.Update(context.Background(), pluginName, plugin.Options{})
 	require.NoError(t, err)
 
 	// verify plugin updated
@@ -443,3 +452,202 @@ func verifyVersion(t *testing.T, pluginName, expectedVersion string) {
 		}
 	}
 }
+
+// writePlugin writes a plugin with the digest of the execution file into a temp dir and returns the dir
+func writePlugin(t *testing.T, script, digest string) string {
+	dir := t.TempDir()
+	metadata := `name: "test_plugin"
+repository: ` + dir + `
+version: "0.1.0"
+usage: test
+description: test
+platforms:
+  - selector:
+      os: linux
+      arch: amd64
+    uri: ./test.sh
+    bin: ./test.sh
+    digest: ` + digest + `
+_goos: linux
+_goarch: amd64
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plugin.yaml"), []byte(metadata), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test.sh"), []byte(script), 0o755))
+	return dir
+}
+
+func TestInstall_Index(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		// the test.sh script can't be run on windows so skipping
+		t.Skip("Test satisfied adequately by Linux tests")
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `version: 1
+plugins:
+  - name: test_plugin
+    maintainer: aquasecurity
+    summary: test
+    repository: testdata/test_plugin
+`)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		plugin  string
+		wantErr string
+	}{
+		{
+			name:   "happy path",
+			plugin: "test_plugin",
+		},
+		{
+			name:    "not in the index",
+			plugin:  "unknown",
+			wantErr: "unknown is not found in the plugin index",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+			got, err := plugin.Install(context.Background(), tt.plugin, false, plugin.Options{
+				IndexURL: ts.URL,
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "test_plugin", got.Name)
+		})
+	}
+}
+
+func TestInstall_Lockfile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		// the test.sh script can't be run on windows so skipping
+		t.Skip("Test satisfied adequately by Linux tests")
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	src := writePlugin(t, "#!/bin/sh\necho foo\n", "")
+	opts := plugin.Options{
+		Lockfile: filepath.Join(t.TempDir(), "plugins.lock.yaml"),
+	}
+
+	// The first installation locks the plugin
+	_, err := plugin.Install(context.Background(), src, true, opts)
+	require.NoError(t, err)
+
+	b, err := os.ReadFile(opts.Lockfile)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "name: test_plugin")
+	assert.Contains(t, string(b), "source: "+src)
+	assert.Contains(t, string(b), "linux/amd64: sha256:")
+
+	// The same plugin can be installed again
+	_, err = plugin.Install(context.Background(), src, true, opts)
+	require.NoError(t, err)
+
+	// Updating the plugin updates the lockfile
+	require.NoError(t, os.WriteFile(filepath.Join(src, "test.sh"), []byte("#!/bin/sh\necho bar\n"), 0o755))
+	require.NoError(t, plugin.Update(context.Background(), "test_plugin", opts))
+	_, err = plugin.Install(context.Background(), src, true, opts)
+	require.NoError(t, err)
+
+	// The tampered execution file is rejected and removed
+	require.NoError(t, os.WriteFile(filepath.Join(src, "test.sh"), []byte("#!/bin/sh\necho baz\n"), 0o755))
+	_, err = plugin.Install(context.Background(), src, true, opts)
+	assert.ErrorContains(t, err, "digest mismatch of the linux/amd64 executable")
+	assert.NoDirExists(t, filepath.Join(os.Getenv("XDG_DATA_HOME"), ".trivy", "plugins", "test_plugin"))
+}
+
+func TestInstall_Signature(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		// the test.sh script can't be run on windows so skipping
+		t.Skip("Test satisfied adequately by Linux tests")
+	}
+
+	script := "#!/bin/sh\necho foo\n"
+	scriptDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(script)))
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	writeKey := func(key ed25519.PublicKey) string {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "key.pub")
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644))
+		return path
+	}
+
+	tests := []struct {
+		name       string
+		digest     string
+		publicKeys []string
+		sign       bool
+		wantErr    string
+	}{
+		{
+			name:       "happy path",
+			digest:     scriptDigest,
+			publicKeys: []string{writeKey(otherPub), writeKey(pub)},
+			sign:       true,
+		},
+		{
+			name:   "no public keys",
+			digest: "",
+		},
+		{
+			name:       "unknown key",
+			digest:     scriptDigest,
+			publicKeys: []string{writeKey(otherPub)},
+			sign:       true,
+			wantErr:    "not verified with any of the public keys",
+		},
+		{
+			name:       "no signature",
+			digest:     scriptDigest,
+			publicKeys: []string{writeKey(pub)},
+			wantErr:    "failed to read the signature",
+		},
+		{
+			name:       "no digest",
+			publicKeys: []string{writeKey(pub)},
+			sign:       true,
+			wantErr:    "the digest of the executable for ./test.sh must be in plugin.yaml",
+		},
+		{
+			name:    "digest mismatch",
+			digest:  "sha256:0000",
+			wantErr: "digest mismatch of the execution file (./test.sh)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+			src := writePlugin(t, script, tt.digest)
+			if tt.sign {
+				metadata, err := os.ReadFile(filepath.Join(src, "plugin.yaml"))
+				require.NoError(t, err)
+				sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, metadata))
+				require.NoError(t, os.WriteFile(filepath.Join(src, "plugin.yaml.sig"), []byte(sig), 0o644))
+			}
+
+			_, err := plugin.Install(context.Background(), src, true, plugin.Options{
+				PublicKeys: tt.publicKeys,
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/digest"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// signatureFile is the base64-encoded Ed25519 signature of plugin.yaml, placed next to plugin.yaml.
+const signatureFile = configFile + ".sig"
+
+// fileDigest returns the SHA-256 digest of the file, e.g. "sha256:2c26..."
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	d, err := digest.CalcSHA256(f)
+	if err != nil {
+		return "", xerrors.Errorf("digest error: %w", err)
+	}
+	return d.String(), nil
+}
+
+// verifySignature verifies the signature of plugin.yaml with the public keys.
+// The signature is verified only when any public key is specified.
+// As the signature covers only plugin.yaml, the digests of the executables must be in plugin.yaml.
+func verifySignature(p Plugin, metadataPath, sigPath string, publicKeys []string) error {
+	if len(publicKeys) == 0 {
+		return nil
+	}
+	log.Logger.Debugf("Verifying the signature of %s...", metadataPath)
+
+	for _, platform := range p.Platforms {
+		if platform.Digest == "" {
+			return xerrors.Errorf("the digest of the executable for %s must be in %s to verify the signature", platform.URI, configFile)
+		}
+	}
+
+	metadata, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return xerrors.Errorf("failed to read %s: %w", configFile, err)
+	}
+	b, err := os.ReadFile(sigPath)
+	if err != nil {
+		return xerrors.Errorf("failed to read the signature: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return xerrors.Errorf("invalid signature: %w", err)
+	}
+
+	for _, keyPath := range publicKeys {
+		key, err := loadPublicKey(keyPath)
+		if err != nil {
+			return xerrors.Errorf("failed to load the public key %s: %w", keyPath, err)
+		}
+		if ed25519.Verify(key, metadata, sig) {
+			log.Logger.Debugf("The signature is verified with %s", keyPath)
+			return nil
+		}
+	}
+	return xerrors.Errorf("the signature of %s is not verified with any of the public keys", configFile)
+}
+
+// loadPublicKey loads the Ed25519 public key in PEM, e.g. generated by "openssl pkey -pubout".
+func loadPublicKey(path string) (ed25519.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("file read error: %w", err)
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, xerrors.New("no PEM block found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, xerrors.Errorf("public key parse error: %w", err)
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, xerrors.Errorf("unsupported public key type %T, only Ed25519 is supported", key)
+	}
+	return edKey, nil
+}
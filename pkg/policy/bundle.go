@@ -0,0 +1,249 @@
+package policy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/bundle"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/iac/rego"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// Check represents a check in the policy bundle
+type Check struct {
+	ID       string
+	AVDID    string
+	Title    string
+	Severity string
+	Path     string // The path relative to the bundle root
+}
+
+// Pin pins the policy bundle to the digest so that it is not updated implicitly.
+// The current bundle is pinned if the digest is empty, otherwise the bundle with the digest is downloaded.
+func (c *Client) Pin(ctx context.Context, digest string) error {
+	meta, err := c.GetMetadata()
+	if digest == "" {
+		if err != nil {
+			return xerrors.New("no policy bundle to pin, run 'trivy policy download' first")
+		}
+		digest = meta.Digest
+	} else if err != nil || meta.Digest != digest {
+		ref, err := name.ParseReference(c.policyBundleRepo)
+		if err != nil {
+			return xerrors.Errorf("repository parse error (%s): %w", c.policyBundleRepo, err)
+		}
+		c.policyBundleRepo = fmt.Sprintf("%s@%s", ref.Context().Name(), digest)
+		c.artifact = nil
+
+		log.Logger.Infof("Downloading the policy bundle %s...", c.policyBundleRepo)
+		if err = c.DownloadBuiltinPolicies(ctx); err != nil {
+			return xerrors.Errorf("failed to download the policy bundle: %w", err)
+		}
+		if meta, err = c.GetMetadata(); err != nil {
+			return xerrors.Errorf("policy metadata error: %w", err)
+		}
+	}
+
+	meta.Pinned = true
+	if err = c.writeMetadata(*meta); err != nil {
+		return xerrors.Errorf("unable to update the policy metadata: %w", err)
+	}
+	log.Logger.Infof("The policy bundle is pinned to %s", digest)
+	return nil
+}
+
+// Unpin lets the policy bundle be updated when scanning again
+func (c *Client) Unpin() error {
+	meta, err := c.GetMetadata()
+	if err != nil {
+		return xerrors.New("no policy bundle to unpin")
+	}
+	meta.Pinned = false
+	if err = c.writeMetadata(*meta); err != nil {
+		return xerrors.Errorf("unable to update the policy metadata: %w", err)
+	}
+	return nil
+}
+
+// Verify checks that the files of the policy bundle have not been modified since the download
+// and that the bundle can be loaded.
+func (c *Client) Verify() error {
+	meta, err := c.GetMetadata()
+	if err != nil {
+		return xerrors.New("no policy bundle to verify, run 'trivy policy download' first")
+	}
+
+	if meta.ContentDigest == "" {
+		return xerrors.New("the policy bundle has no content digest, run 'trivy policy download' again")
+	}
+	contentDigest, err := c.calcContentDigest()
+	if err != nil {
+		return xerrors.Errorf("content digest error: %w", err)
+	}
+	if contentDigest != meta.ContentDigest {
+		return xerrors.Errorf("the policy bundle has been modified: expected %s, actual %s", meta.ContentDigest,
+			contentDigest)
+	}
+
+	if _, err = bundle.NewCustomReader(bundle.NewDirectoryLoader(c.contentDir())).Read(); err != nil {
+		return xerrors.Errorf("bundle load error: %w", err)
+	}
+	return nil
+}
+
+// ListChecks returns the checks in the policy bundle, sorted by ID
+func (c *Client) ListChecks() ([]Check, error) {
+	var checks []Check
+	err := filepath.WalkDir(c.contentDir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if d.IsDir() || filepath.Ext(path) != ".rego" || strings.HasSuffix(path, "_test.rego") {
+			return nil
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		module, err := ast.ParseModuleWithOpts(path, string(b), ast.ParserOptions{ProcessAnnotation: true})
+		if err != nil {
+			return xerrors.Errorf("rego parse error (%s): %w", path, err)
+		}
+
+		for _, annotations := range module.Annotations {
+			if annotations.Scope != "package" {
+				continue
+			}
+			meta := rego.NewStaticMetadata(module.Package.Path.String(), rego.InputOptions{})
+			if err = meta.FromAnnotations(annotations); err != nil {
+				return xerrors.Errorf("metadata error (%s): %w", path, err)
+			}
+			// Libraries have no ID
+			if meta.Library || meta.ID == "N/A" {
+				continue
+			}
+
+			rel, err := filepath.Rel(c.contentDir(), path)
+			if err != nil {
+				return err
+			}
+			checks = append(checks, Check{
+				ID:       meta.ID,
+				AVDID:    meta.AVDID,
+				Title:    meta.Title,
+				Severity: meta.Severity,
+				Path:     filepath.ToSlash(rel),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("walk error: %w", err)
+	}
+
+	slices.SortFunc(checks, func(a, b Check) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return checks, nil
+}
+
+// Vendor copies the policy bundle into the directory so that it can be used in other environments,
+// e.g. air-gapped ones, by placing it as "policy" in the cache directory.
+// The vendored bundle is pinned as it cannot be updated there.
+func (c *Client) Vendor(dir string) error {
+	meta, err := c.GetMetadata()
+	if err != nil {
+		return xerrors.New("no policy bundle to vendor, run 'trivy policy download' first")
+	}
+	if err = c.Verify(); err != nil {
+		return xerrors.Errorf("verification error: %w", err)
+	}
+
+	dst := &Client{options: c.options, policyDir: dir}
+	err = filepath.WalkDir(c.contentDir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(c.contentDir(), path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst.contentDir(), rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		return copyFile(path, target)
+	})
+	if err != nil {
+		return xerrors.Errorf("copy error: %w", err)
+	}
+
+	meta.Pinned = true
+	if err = dst.writeMetadata(*meta); err != nil {
+		return xerrors.Errorf("unable to write the policy metadata: %w", err)
+	}
+	return nil
+}
+
+// calcContentDigest calculates the digest of the paths and contents of the extracted files
+func (c *Client) calcContentDigest() (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(c.contentDir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(c.contentDir(), path)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		fh := sha256.New()
+		if _, err = io.Copy(fh, f); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00%x\n", filepath.ToSlash(rel), fh.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,170 @@
+package policy_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	fakei "github.com/google/go-containerregistry/pkg/v1/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	fake "k8s.io/utils/clock/testing"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/oci"
+	"github.com/aquasecurity/trivy/pkg/policy"
+)
+
+// newDownloadedClient returns a client with the test bundle downloaded into the cache directory
+func newDownloadedClient(t *testing.T, cacheDir string) *policy.Client {
+	img := new(fakei.FakeImage)
+	img.LayersReturns([]v1.Layer{newFakeLayer(t)}, nil)
+	img.DigestReturns(v1.Hash{
+		Algorithm: "sha256",
+		Hex:       "01e033e78bd8a59fa4f4577215e7da06c05e1152526094d8d79d2aa06e98cb9d",
+	}, nil)
+	img.ManifestReturns(&v1.Manifest{
+		Layers: []v1.Descriptor{
+			{
+				MediaType: "application/vnd.cncf.openpolicyagent.layer.v1.tar+gzip",
+				Size:      100,
+				Digest: v1.Hash{
+					Algorithm: "sha256",
+					Hex:       "cba33656188782852f58993f45b68bfb8577f64cdcf02a604e3fc2afbeb5f2d8",
+				},
+				Annotations: map[string]string{
+					"org.opencontainers.image.title": "bundle.tar.gz",
+				},
+			},
+		},
+	}, nil)
+
+	art, err := oci.NewArtifact("repo", true, ftypes.RegistryOptions{}, oci.WithImage(img))
+	require.NoError(t, err)
+
+	c, err := policy.NewClient(cacheDir, true, "", policy.WithOCIArtifact(art),
+		policy.WithClock(fake.NewFakeClock(time.Date(2021, 1, 1, 1, 0, 0, 0, time.UTC))))
+	require.NoError(t, err)
+	require.NoError(t, c.DownloadBuiltinPolicies(context.Background()))
+	return c
+}
+
+func TestClient_Pin(t *testing.T) {
+	t.Run("current bundle", func(t *testing.T) {
+		c := newDownloadedClient(t, t.TempDir())
+		require.NoError(t, c.Pin(context.Background(), ""))
+
+		meta, err := c.GetMetadata()
+		require.NoError(t, err)
+		assert.True(t, meta.Pinned)
+		assert.Equal(t, "sha256:01e033e78bd8a59fa4f4577215e7da06c05e1152526094d8d79d2aa06e98cb9d", meta.Digest)
+
+		needsUpdate, err := c.NeedsUpdate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, needsUpdate)
+
+		require.NoError(t, c.Unpin())
+		meta, err = c.GetMetadata()
+		require.NoError(t, err)
+		assert.False(t, meta.Pinned)
+	})
+
+	t.Run("no bundle", func(t *testing.T) {
+		c, err := policy.NewClient(t.TempDir(), true, "")
+		require.NoError(t, err)
+		require.ErrorContains(t, c.Pin(context.Background(), ""), "no policy bundle to pin")
+	})
+}
+
+func TestClient_Verify(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(t *testing.T, contentDir string)
+		wantErr string
+	}{
+		{
+			name: "happy path",
+		},
+		{
+			name: "modified file",
+			modify: func(t *testing.T, contentDir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(contentDir, "port22.rego"), []byte("package appshield.DS004"), 0o600))
+			},
+			wantErr: "the policy bundle has been modified",
+		},
+		{
+			name: "added file",
+			modify: func(t *testing.T, contentDir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(contentDir, "evil.rego"), []byte("package evil"), 0o600))
+			},
+			wantErr: "the policy bundle has been modified",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			c := newDownloadedClient(t, cacheDir)
+			if tt.modify != nil {
+				tt.modify(t, filepath.Join(cacheDir, "policy", "content"))
+			}
+
+			err := c.Verify()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_ListChecks(t *testing.T) {
+	c, err := policy.NewClient("testdata/checks", true, "")
+	require.NoError(t, err)
+
+	got, err := c.ListChecks()
+	require.NoError(t, err)
+
+	want := []policy.Check{
+		{
+			ID:       "KSV008",
+			AVDID:    "AVD-KSV-0008",
+			Title:    "Access to host IPC namespace",
+			Severity: "HIGH",
+			Path:     "kubernetes/policies/host_ipc.rego",
+		},
+		{
+			ID:       "KSV017",
+			AVDID:    "AVD-KSV-0017",
+			Title:    "Privileged",
+			Severity: "HIGH",
+			Path:     "kubernetes/policies/privileged.rego",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestClient_Vendor(t *testing.T) {
+	c := newDownloadedClient(t, t.TempDir())
+
+	vendorDir := filepath.Join(t.TempDir(), "policy")
+	require.NoError(t, c.Vendor(vendorDir))
+
+	// The vendored bundle can be used as the policy cache of another environment
+	cacheDir := filepath.Dir(vendorDir)
+	vendored, err := policy.NewClient(cacheDir, true, "")
+	require.NoError(t, err)
+	require.NoError(t, vendored.Verify())
+
+	meta, err := vendored.GetMetadata()
+	require.NoError(t, err)
+	assert.True(t, meta.Pinned)
+
+	needsUpdate, err := vendored.NeedsUpdate(context.Background())
+	require.NoError(t, err)
+	assert.False(t, needsUpdate)
+	assert.FileExists(t, filepath.Join(vendorDir, "content", "port22.rego"))
+}
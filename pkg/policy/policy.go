@@ -25,8 +25,9 @@ const (
 )
 
 type options struct {
-	artifact *oci.Artifact
-	clock    clock.Clock
+	artifact    *oci.Artifact
+	clock       clock.Clock
+	registryOpt types.RegistryOptions
 }
 
 // WithOCIArtifact takes an OCI artifact
@@ -43,6 +44,13 @@ func WithClock(c clock.Clock) Option {
 	}
 }
 
+// WithRegistryOptions takes options for the registry hosting the bundle, e.g. credentials for private registries
+func WithRegistryOptions(opt types.RegistryOptions) Option {
+	return func(opts *options) {
+		opts.registryOpt = opt
+	}
+}
+
 // Option is a functional option
 type Option func(*options)
 
@@ -56,8 +64,11 @@ type Client struct {
 
 // Metadata holds default policy metadata
 type Metadata struct {
-	Digest       string
-	DownloadedAt time.Time
+	Repository    string `json:",omitempty"`
+	Digest        string
+	ContentDigest string `json:",omitempty"` // The digest of the extracted files for verification
+	Pinned        bool   `json:",omitempty"` // Pinned bundles are never updated implicitly
+	DownloadedAt  time.Time
 }
 
 func (m Metadata) String() string {
@@ -92,7 +103,7 @@ func NewClient(cacheDir string, quiet bool, policyBundleRepo string, opts ...Opt
 func (c *Client) populateOCIArtifact() error {
 	if c.artifact == nil {
 		log.Logger.Debugf("Using URL: %s to load policy bundle", c.policyBundleRepo)
-		art, err := oci.NewArtifact(c.policyBundleRepo, c.quiet, types.RegistryOptions{})
+		art, err := oci.NewArtifact(c.policyBundleRepo, c.quiet, c.registryOpt)
 		if err != nil {
 			return xerrors.Errorf("OCI artifact error: %w", err)
 		}
@@ -118,8 +129,19 @@ func (c *Client) DownloadBuiltinPolicies(ctx context.Context) error {
 	}
 	log.Logger.Debugf("Digest of the built-in policies: %s", digest)
 
+	contentDigest, err := c.calcContentDigest()
+	if err != nil {
+		return xerrors.Errorf("content digest error: %w", err)
+	}
+
 	// Update metadata.json with the new digest and the current date
-	if err = c.updateMetadata(digest, c.clock.Now()); err != nil {
+	meta := Metadata{
+		Repository:    c.policyBundleRepo,
+		Digest:        digest,
+		ContentDigest: contentDigest,
+		DownloadedAt:  c.clock.Now(),
+	}
+	if err = c.writeMetadata(meta); err != nil {
 		return xerrors.Errorf("unable to update the policy metadata: %w", err)
 	}
 
@@ -160,6 +182,12 @@ func (c *Client) NeedsUpdate(ctx context.Context) (bool, error) {
 		return true, nil
 	}
 
+	// Pinned bundles are updated only by "trivy policy pin" and "trivy policy download"
+	if meta.Pinned {
+		log.Logger.Debugf("The policy bundle is pinned to %s", meta.Digest)
+		return false, nil
+	}
+
 	// No need to update if it's been within a day since the last update.
 	if c.clock.Now().Before(meta.DownloadedAt.Add(updateInterval)) {
 		return false, nil
@@ -181,7 +209,8 @@ func (c *Client) NeedsUpdate(ctx context.Context) (bool, error) {
 	// Update DownloadedAt with the current time.
 	// Otherwise, if there are no updates in the remote registry,
 	// the digest will be fetched every time even after this.
-	if err = c.updateMetadata(meta.Digest, time.Now()); err != nil {
+	meta.DownloadedAt = time.Now()
+	if err = c.writeMetadata(*meta); err != nil {
 		return false, xerrors.Errorf("unable to update the policy metadata: %w", err)
 	}
 
@@ -200,18 +229,13 @@ func (c *Client) manifestPath() string {
 	return filepath.Join(c.contentDir(), bundle.ManifestExt)
 }
 
-func (c *Client) updateMetadata(digest string, now time.Time) error {
+func (c *Client) writeMetadata(meta Metadata) error {
 	f, err := os.Create(c.metadataPath())
 	if err != nil {
 		return xerrors.Errorf("failed to open a policy manifest: %w", err)
 	}
 	defer f.Close()
 
-	meta := Metadata{
-		Digest:       digest,
-		DownloadedAt: now,
-	}
-
 	if err = json.NewEncoder(f).Encode(meta); err != nil {
 		return xerrors.Errorf("json encode error: %w", err)
 	}
@@ -192,6 +192,22 @@ func TestClient_NeedsUpdate(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name:  "pinned",
+			clock: fake.NewFakeClock(time.Date(2021, 1, 2, 1, 0, 0, 0, time.UTC)),
+			digestReturns: digestReturns{
+				h: v1.Hash{
+					Algorithm: "sha256",
+					Hex:       "01e033e78bd8a59fa4f4577215e7da06c05e1152526094d8d79d2aa06e98cb9d",
+				},
+			},
+			metadata: policy.Metadata{
+				Digest:       `sha256:922e50f14ab484f11ae65540c3d2d76009020213f1027d4331d31141575e5414`,
+				Pinned:       true,
+				DownloadedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want: false,
+		},
 		{
 			name:  "sad: Digest returns  an error",
 			clock: fake.NewFakeClock(time.Date(2021, 1, 2, 1, 0, 0, 0, time.UTC)),
@@ -301,8 +317,10 @@ func TestClient_DownloadBuiltinPolicies(t *testing.T) {
 				},
 			},
 			want: &policy.Metadata{
-				Digest:       "sha256:01e033e78bd8a59fa4f4577215e7da06c05e1152526094d8d79d2aa06e98cb9d",
-				DownloadedAt: time.Date(2021, 1, 1, 1, 0, 0, 0, time.UTC),
+				Repository:    "ghcr.io/aquasecurity/trivy-policies:0",
+				Digest:        "sha256:01e033e78bd8a59fa4f4577215e7da06c05e1152526094d8d79d2aa06e98cb9d",
+				ContentDigest: "sha256:c51b0a6568cfad398fd754f834b3deabdbadc2625e6bfa94aa02c164991722e8",
+				DownloadedAt:  time.Date(2021, 1, 1, 1, 0, 0, 0, time.UTC),
 			},
 		},
 		{
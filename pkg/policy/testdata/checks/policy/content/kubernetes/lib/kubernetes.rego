@@ -0,0 +1,8 @@
+# METADATA
+# custom:
+#   library: true
+package lib.kubernetes
+
+containers[container] {
+	container := input.spec.containers[_]
+}
@@ -0,0 +1,14 @@
+# METADATA
+# title: "Access to host IPC namespace"
+# description: "Sharing the host’s IPC namespace allows container processes to communicate with processes on the host."
+# scope: package
+# custom:
+#   id: KSV008
+#   avd_id: AVD-KSV-0008
+#   severity: HIGH
+package builtin.kubernetes.KSV008
+
+deny[res] {
+	input.spec.hostIPC
+	res := "Pod should not set 'spec.hostIPC' to true"
+}
@@ -0,0 +1,5 @@
+package builtin.kubernetes.KSV008
+
+test_host_ipc_denied {
+	count(deny) > 0 with input as {"spec": {"hostIPC": true}}
+}
@@ -0,0 +1,14 @@
+# METADATA
+# title: "Privileged"
+# description: "Privileged containers share namespaces with the host system and do not offer any security."
+# scope: package
+# custom:
+#   id: KSV017
+#   avd_id: AVD-KSV-0017
+#   severity: HIGH
+package builtin.kubernetes.KSV017
+
+deny[res] {
+	input.spec.containers[_].securityContext.privileged
+	res := "Container should not be privileged"
+}
@@ -202,6 +202,10 @@ func (p *PackageURL) LangType() ftypes.LangType {
 		return ftypes.Pub
 	case packageurl.TypeBitnami:
 		return ftypes.Bitnami
+	case packageurl.TypeHackage:
+		return ftypes.Hackage
+	case packageurl.TypeCran:
+		return ftypes.CRAN
 	case TypeK8s:
 		switch p.Namespace {
 		case NamespaceEKS:
@@ -493,6 +497,8 @@ func purlType(t ftypes.TargetType) string {
 		return packageurl.TypePub
 	case ftypes.RustBinary, ftypes.Cargo:
 		return packageurl.TypeCargo
+	case ftypes.JRE:
+		return packageurl.TypeGeneric
 	case ftypes.Alpine:
 		return packageurl.TypeApk
 	case ftypes.Debian, ftypes.Ubuntu:
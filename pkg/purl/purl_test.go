@@ -804,6 +804,15 @@ func TestPackageURL_LangType(t *testing.T) {
 			},
 			want: ftypes.EKS,
 		},
+		{
+			name: "hackage",
+			purl: packageurl.PackageURL{
+				Type:    packageurl.TypeHackage,
+				Name:    "aeson",
+				Version: "2.2.1.0",
+			},
+			want: ftypes.Hackage,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,288 @@
+package reachability
+
+import (
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+var (
+	// e.g. golang.org/x/net/http2.(*Server).ServeConn => golang.org/x/net/http2.Server.ServeConn
+	goFuncNameReplacer = strings.NewReplacer("(*", "", ")", "", "[...]", "")
+
+	// e.g. gopkg.in/yaml.v3 and github.com/go-chi/chi/v5
+	goVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// symbolSet is the set of symbols which certainly exist, such as functions in a Go binary.
+type symbolSet map[string]struct{}
+
+func (s symbolSet) Reachable(symbol string) (bool, bool) {
+	_, ok := s[symbol]
+	return ok, true
+}
+
+// goBinaryReferences returns the functions in the Go binary.
+// The Go linker removes unreachable functions from binaries, so the functions in the symbol table are reachable from main.
+func goBinaryReferences(filePath string) (referenceSet, error) {
+	data, textStart, err := goPCLNTab(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, textStart))
+	if err != nil {
+		return nil, xerrors.Errorf("symbol table error: %w", err)
+	}
+
+	symbols := make(symbolSet)
+	for _, fn := range table.Funcs {
+		symbols[goFuncNameReplacer.Replace(fn.Name)] = struct{}{}
+	}
+	return symbols, nil
+}
+
+// goPCLNTab returns the function table and the start address of the text section.
+// The function table is kept even in stripped binaries.
+func goPCLNTab(filePath string) ([]byte, uint64, error) {
+	if f, err := elf.Open(filePath); err == nil {
+		defer f.Close()
+		tab, text := f.Section(".gopclntab"), f.Section(".text")
+		if tab == nil || text == nil {
+			return nil, 0, xerrors.New("no function table")
+		}
+		data, err := tab.Data()
+		return data, text.Addr, err
+	}
+	if f, err := macho.Open(filePath); err == nil {
+		defer f.Close()
+		tab, text := f.Section("__gopclntab"), f.Section("__text")
+		if tab == nil || text == nil {
+			return nil, 0, xerrors.New("no function table")
+		}
+		data, err := tab.Data()
+		return data, text.Addr, err
+	}
+	return nil, 0, xerrors.New("unsupported binary format")
+}
+
+// goSourceRefs holds the references to package-level identifiers in Go source code.
+// Method calls cannot be resolved without type checking, so a method is considered to be referenced
+// when a file importing the package calls a method with the same name.
+type goSourceRefs struct {
+	// e.g. golang.org/x/net/http2.ConfigureServer
+	identifiers map[string]struct{}
+
+	// import path => selectors in files importing the package
+	selectors map[string]map[string]struct{}
+
+	// modules whose source code is not available
+	missing []string
+}
+
+func (r *goSourceRefs) Reachable(symbol string) (bool, bool) {
+	if _, ok := r.identifiers[symbol]; ok {
+		return true, true
+	}
+
+	// e.g. golang.org/x/net/http2.Server.ServeConn => golang.org/x/net/http2 and Server.ServeConn
+	// The last element of the import path may contain dots, such as gopkg.in/yaml.v3.
+	for i := strings.LastIndex(symbol, "/") + 1; i < len(symbol); i++ {
+		if symbol[i] != '.' {
+			continue
+		}
+		selectors, ok := r.selectors[symbol[:i]]
+		if !ok {
+			continue
+		}
+		if _, method, ok := strings.Cut(symbol[i+1:], "."); ok {
+			if _, ok = selectors[method]; ok {
+				return true, true
+			}
+		}
+	}
+
+	// The code calling the symbol might be in dependencies which could not be analyzed
+	return false, len(r.missing) == 0
+}
+
+// goSourceReferences analyzes the Go module in the directory and its dependencies.
+// Dependencies are read from the vendor directory or the module cache.
+func goSourceReferences(dir string, pkgs []ftypes.Package) (referenceSet, error) {
+	refs := &goSourceRefs{
+		identifiers: make(map[string]struct{}),
+		selectors:   make(map[string]map[string]struct{}),
+	}
+
+	if err := refs.walk(dir, true); err != nil {
+		return nil, xerrors.Errorf("walk error: %w", err)
+	}
+
+	vendorDir := filepath.Join(dir, "vendor")
+	if _, err := os.Stat(vendorDir); err == nil {
+		if err = refs.walk(vendorDir, false); err != nil {
+			return nil, xerrors.Errorf("vendor walk error: %w", err)
+		}
+		return refs, nil
+	}
+
+	modCache := goModCache()
+	for _, pkg := range pkgs {
+		modDir, ok := goModuleDir(modCache, pkg)
+		if !ok {
+			refs.missing = append(refs.missing, pkg.Name)
+			continue
+		}
+		if err := refs.walk(modDir, false); err != nil {
+			return nil, xerrors.Errorf("module walk error: %w", err)
+		}
+	}
+	if len(refs.missing) > 0 {
+		log.Logger.Debugf("The source code of %d Go modules is not available, run 'go mod download' to analyze them", len(refs.missing))
+	}
+	return refs, nil
+}
+
+func (r *goSourceRefs) walk(root string, skipVendor bool) error {
+	return filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if filePath != root && (name == "testdata" || (skipVendor && name == "vendor") ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// Test code is not linked into binaries
+		if filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+		if err = r.parseFile(filePath); err != nil {
+			log.Logger.Debugf("Unable to parse %s: %s", filePath, err)
+		}
+		return nil
+	})
+}
+
+func (r *goSourceRefs) parseFile(filePath string) error {
+	f, err := parser.ParseFile(token.NewFileSet(), filePath, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+
+	// local name => import path
+	imports := make(map[string]string)
+	for _, spec := range f.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := goPackageName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		// Blank imports are not called
+		if name != "_" {
+			imports[name] = importPath
+		}
+	}
+	if len(imports) == 0 {
+		return nil
+	}
+
+	selectors := make(map[string]struct{})
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		selectors[sel.Sel.Name] = struct{}{}
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			if importPath, ok := imports[ident.Name]; ok {
+				r.identifiers[importPath+"."+sel.Sel.Name] = struct{}{}
+			}
+		}
+		return true
+	})
+
+	for _, importPath := range imports {
+		if r.selectors[importPath] == nil {
+			r.selectors[importPath] = make(map[string]struct{})
+		}
+		for s := range selectors {
+			r.selectors[importPath][s] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// goPackageName guesses the package name from the import path, as most packages follow the convention.
+func goPackageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if goVersionSuffix.MatchString(name) && len(elems) > 1 {
+		name = elems[len(elems)-2]
+	}
+	// e.g. gopkg.in/yaml.v3 => yaml
+	if base, suffix, ok := strings.Cut(name, "."); ok && goVersionSuffix.MatchString(suffix) {
+		name = base
+	}
+	// e.g. github.com/hashicorp/go-version => version
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "go-"), "-go")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+func goModCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		gopath = filepath.Join(home, "go")
+	}
+	// GOPATH can be a list of directories, and the module cache is in the first one
+	gopath = filepath.SplitList(gopath)[0]
+	return filepath.Join(gopath, "pkg", "mod")
+}
+
+// goModuleDir returns the directory of the module in the module cache
+func goModuleDir(modCache string, pkg ftypes.Package) (string, bool) {
+	if modCache == "" || pkg.Version == "" {
+		return "", false
+	}
+	escapedPath, err := module.EscapePath(pkg.Name)
+	if err != nil {
+		return "", false
+	}
+	escapedVersion, err := module.EscapeVersion("v" + pkg.Version)
+	if err != nil {
+		return "", false
+	}
+	dir := filepath.Join(modCache, filepath.FromSlash(escapedPath+"@"+escapedVersion))
+	if _, err = os.Stat(dir); err != nil {
+		return "", false
+	}
+	return dir, true
+}
@@ -0,0 +1,322 @@
+package reachability
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const (
+	javaClassMagic = 0xCAFEBABE
+
+	// Constant pool tags
+	// cf. https://docs.oracle.com/javase/specs/jvms/se21/html/jvms-4.html#jvms-4.4
+	javaConstantUtf8               = 1
+	javaConstantInteger            = 3
+	javaConstantFloat              = 4
+	javaConstantLong               = 5
+	javaConstantDouble             = 6
+	javaConstantClass              = 7
+	javaConstantString             = 8
+	javaConstantFieldref           = 9
+	javaConstantMethodref          = 10
+	javaConstantInterfaceMethodref = 11
+	javaConstantNameAndType        = 12
+	javaConstantMethodHandle       = 15
+	javaConstantMethodType         = 16
+	javaConstantDynamic            = 17
+	javaConstantInvokeDynamic      = 18
+	javaConstantModule             = 19
+	javaConstantPackage            = 20
+
+	// Fat JARs such as Spring Boot applications contain JARs, which are analyzed as well
+	maxJavaArchiveDepth = 2
+)
+
+var javaArchiveExtensions = []string{
+	".jar",
+	".war",
+	".ear",
+	".par",
+}
+
+// javaRefs holds the methods called from Java classes.
+// Classes are grouped by their origin, the archive or the directory containing them,
+// so that calls inside the library declaring the vulnerable method are not taken into account.
+type javaRefs struct {
+	// method => origins of the classes calling the method
+	callers map[string][]string
+
+	// class => origins declaring the class
+	declared map[string][]string
+}
+
+func (r *javaRefs) Reachable(symbol string) (bool, bool) {
+	idx := strings.LastIndex(symbol, ".")
+	if idx < 0 {
+		return false, false
+	}
+
+	// The reachability cannot be determined if the class is not found, e.g. only pom.xml is scanned
+	declared, ok := r.declared[symbol[:idx]]
+	if !ok {
+		return false, false
+	}
+
+	for _, origin := range r.callers[symbol] {
+		if !slices.Contains(declared, origin) {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// javaReferences analyzes Java archives and class files under the root directory.
+func javaReferences(root string) (referenceSet, error) {
+	refs := &javaRefs{
+		callers:  make(map[string][]string),
+		declared: make(map[string][]string),
+	}
+
+	err := filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if d.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, filePath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		switch ext := strings.ToLower(filepath.Ext(filePath)); {
+		case ext == ".class":
+			// Loose class files, such as target/classes, are considered to be the application
+			if err = refs.parseClassFile(filePath, "."); err != nil {
+				log.Logger.Debugf("Unable to parse %s: %s", relPath, err)
+			}
+		case slices.Contains(javaArchiveExtensions, ext):
+			if err = refs.parseArchiveFile(filePath, relPath); err != nil {
+				log.Logger.Debugf("Unable to parse %s: %s", relPath, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("walk error: %w", err)
+	}
+	return refs, nil
+}
+
+func (r *javaRefs) parseClassFile(filePath, origin string) error {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	return r.addClass(b, origin)
+}
+
+func (r *javaRefs) parseArchiveFile(filePath, origin string) error {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return xerrors.Errorf("zip open error: %w", err)
+	}
+	defer zr.Close()
+	return r.parseArchive(&zr.Reader, origin, 1)
+}
+
+func (r *javaRefs) parseArchive(zr *zip.Reader, origin string, depth int) error {
+	for _, f := range zr.File {
+		ext := strings.ToLower(filepath.Ext(f.Name))
+		isArchive := slices.Contains(javaArchiveExtensions, ext)
+		if ext != ".class" && (!isArchive || depth >= maxJavaArchiveDepth) {
+			continue
+		}
+
+		b, err := readZipFile(f)
+		if err != nil {
+			return xerrors.Errorf("unable to read %s: %w", f.Name, err)
+		}
+
+		if isArchive {
+			inner, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+			if err != nil {
+				log.Logger.Debugf("Unable to open %s in %s: %s", f.Name, origin, err)
+				continue
+			}
+			if err = r.parseArchive(inner, origin+"!/"+f.Name, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err = r.addClass(b, origin); err != nil {
+			log.Logger.Debugf("Unable to parse %s in %s: %s", f.Name, origin, err)
+		}
+	}
+	return nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func (r *javaRefs) addClass(b []byte, origin string) error {
+	class, methods, err := parseJavaClass(b)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(r.declared[class], origin) {
+		r.declared[class] = append(r.declared[class], origin)
+	}
+	for _, method := range methods {
+		if !slices.Contains(r.callers[method], origin) {
+			r.callers[method] = append(r.callers[method], origin)
+		}
+	}
+	return nil
+}
+
+// javaConstant is an entry of the constant pool
+type javaConstant struct {
+	tag    byte
+	utf8   string
+	index1 uint16 // e.g. the class of Methodref, the name of Class and NameAndType
+	index2 uint16 // e.g. the NameAndType of Methodref
+}
+
+// parseJavaClass returns the binary name of the class, e.g. com.example.Foo$Bar,
+// and the methods referenced in the constant pool, e.g. com.example.Baz.run.
+func parseJavaClass(b []byte) (string, []string, error) {
+	r := bytes.NewReader(b)
+
+	var header struct {
+		Magic        uint32
+		MinorVersion uint16
+		MajorVersion uint16
+		PoolCount    uint16
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return "", nil, xerrors.Errorf("header error: %w", err)
+	} else if header.Magic != javaClassMagic {
+		return "", nil, xerrors.New("not a class file")
+	}
+
+	// The constant pool is indexed from 1
+	pool := make([]javaConstant, header.PoolCount)
+	for i := 1; i < int(header.PoolCount); i++ {
+		tag, err := r.ReadByte()
+		if err != nil {
+			return "", nil, xerrors.Errorf("constant pool error: %w", err)
+		}
+		c := javaConstant{tag: tag}
+
+		var size int64
+		switch tag {
+		case javaConstantUtf8:
+			var length uint16
+			if err = binary.Read(r, binary.BigEndian, &length); err != nil {
+				return "", nil, xerrors.Errorf("constant pool error: %w", err)
+			}
+			s := make([]byte, length)
+			if _, err = io.ReadFull(r, s); err != nil {
+				return "", nil, xerrors.Errorf("constant pool error: %w", err)
+			}
+			c.utf8 = string(s)
+		case javaConstantClass, javaConstantMethodref, javaConstantInterfaceMethodref, javaConstantNameAndType:
+			if err = binary.Read(r, binary.BigEndian, &c.index1); err != nil {
+				return "", nil, xerrors.Errorf("constant pool error: %w", err)
+			}
+			if tag != javaConstantClass {
+				if err = binary.Read(r, binary.BigEndian, &c.index2); err != nil {
+					return "", nil, xerrors.Errorf("constant pool error: %w", err)
+				}
+			}
+		case javaConstantString, javaConstantMethodType, javaConstantModule, javaConstantPackage:
+			size = 2
+		case javaConstantMethodHandle:
+			size = 3
+		case javaConstantInteger, javaConstantFloat, javaConstantFieldref, javaConstantDynamic, javaConstantInvokeDynamic:
+			size = 4
+		case javaConstantLong, javaConstantDouble:
+			size = 8
+		default:
+			return "", nil, xerrors.Errorf("unknown constant pool tag: %d", tag)
+		}
+		if _, err = r.Seek(size, io.SeekCurrent); err != nil {
+			return "", nil, xerrors.Errorf("constant pool error: %w", err)
+		}
+		pool[i] = c
+
+		// Long and Double take two entries
+		if tag == javaConstantLong || tag == javaConstantDouble {
+			i++
+		}
+	}
+
+	var access struct {
+		AccessFlags uint16
+		ThisClass   uint16
+	}
+	if err := binary.Read(r, binary.BigEndian, &access); err != nil {
+		return "", nil, xerrors.Errorf("class info error: %w", err)
+	}
+
+	constant := func(index uint16, tag byte) (javaConstant, bool) {
+		if int(index) >= len(pool) || pool[index].tag != tag {
+			return javaConstant{}, false
+		}
+		return pool[index], true
+	}
+	className := func(index uint16) (string, bool) {
+		class, ok := constant(index, javaConstantClass)
+		if !ok {
+			return "", false
+		}
+		name, ok := constant(class.index1, javaConstantUtf8)
+		return strings.ReplaceAll(name.utf8, "/", "."), ok
+	}
+
+	class, ok := className(access.ThisClass)
+	if !ok {
+		return "", nil, xerrors.New("invalid class name")
+	}
+
+	var methods []string
+	for _, c := range pool {
+		if c.tag != javaConstantMethodref && c.tag != javaConstantInterfaceMethodref {
+			continue
+		}
+		owner, ok := className(c.index1)
+		if !ok {
+			continue
+		}
+		nameAndType, ok := constant(c.index2, javaConstantNameAndType)
+		if !ok {
+			continue
+		}
+		name, ok := constant(nameAndType.index1, javaConstantUtf8)
+		if !ok {
+			continue
+		}
+		methods = append(methods, owner+"."+name.utf8)
+	}
+	return class, methods, nil
+}
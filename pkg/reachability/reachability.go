@@ -0,0 +1,134 @@
+package reachability
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// AffectedSymbols represents the functions and methods where a vulnerability resides.
+type AffectedSymbols struct {
+	// ID is the identifier of the vulnerability, e.g. CVE-2023-39325 or GO-2023-2102
+	ID string `yaml:"id"`
+
+	// Symbols are fully qualified names of the affected functions and methods, e.g.
+	//   - Go: golang.org/x/net/http2.Server.ServeConn
+	//   - Java: com.fasterxml.jackson.databind.ObjectMapper.readValue
+	Symbols []string `yaml:"symbols"`
+}
+
+// Metadata represents the structure of the affected-symbol metadata file.
+type Metadata struct {
+	Vulnerabilities []AffectedSymbols `yaml:"vulnerabilities"`
+}
+
+// symbols returns the affected symbols of the vulnerability, matching the vendor IDs as well
+func (m Metadata) symbols(vuln types.DetectedVulnerability) []string {
+	for _, v := range m.Vulnerabilities {
+		if v.ID == vuln.VulnerabilityID {
+			return v.Symbols
+		}
+		for _, id := range vuln.VendorIDs {
+			if v.ID == id {
+				return v.Symbols
+			}
+		}
+	}
+	return nil
+}
+
+// referenceSet tells if the symbol is referenced.
+// It returns false in the second value when the reachability cannot be determined,
+// e.g. the source code of dependencies is not available.
+type referenceSet interface {
+	Reachable(symbol string) (reachable, determined bool)
+}
+
+func parseMetadata(filePath string) (Metadata, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return Metadata{}, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	var metadata Metadata
+	if err = yaml.NewDecoder(f).Decode(&metadata); err != nil {
+		return Metadata{}, xerrors.Errorf("yaml decode error: %w", err)
+	}
+	for _, v := range metadata.Vulnerabilities {
+		if v.ID == "" {
+			return Metadata{}, xerrors.New("id is required in the affected-symbol metadata")
+		} else if len(v.Symbols) == 0 {
+			return Metadata{}, xerrors.Errorf("no symbols for %s in the affected-symbol metadata", v.ID)
+		}
+	}
+	return metadata, nil
+}
+
+// Fill marks vulnerabilities in Go and Java packages as reachable or unreachable
+// by checking if the affected symbols are referenced from the files under the root directory.
+// Vulnerabilities without affected-symbol metadata are left as they are.
+func Fill(root, metadataFile string, results types.Results) error {
+	metadata, err := parseMetadata(metadataFile)
+	if err != nil {
+		return xerrors.Errorf("%s parse error: %w", metadataFile, err)
+	}
+
+	// Java classes can call any other classes in the classpath, so all the archives and classes are analyzed together.
+	var javaRefs referenceSet
+	for i, result := range results {
+		if len(result.Vulnerabilities) == 0 {
+			continue
+		}
+
+		var refs referenceSet
+		switch result.Type {
+		case ftypes.GoBinary:
+			refs, err = goBinaryReferences(filepath.Join(root, filepath.FromSlash(result.Target)))
+		case ftypes.GoModule:
+			refs, err = goSourceReferences(filepath.Join(root, filepath.FromSlash(path.Dir(result.Target))), result.Packages)
+		case ftypes.Jar, ftypes.Pom, ftypes.Gradle:
+			if javaRefs == nil {
+				javaRefs, err = javaReferences(root)
+			}
+			refs = javaRefs
+		default:
+			continue
+		}
+		if err != nil {
+			log.Logger.Debugf("Unable to analyze the reachability of %s: %s", result.Target, err)
+			continue
+		}
+
+		for j, vuln := range result.Vulnerabilities {
+			symbols := metadata.symbols(vuln)
+			if len(symbols) == 0 {
+				continue
+			}
+			results[i].Vulnerabilities[j].Reachability = reachability(refs, symbols)
+		}
+	}
+	return nil
+}
+
+// reachability returns Reachable if any symbol is referenced, and Unreachable if all symbols are not referenced.
+// It returns an empty value when the reachability of some symbols cannot be determined.
+func reachability(refs referenceSet, symbols []string) types.Reachability {
+	result := types.Unreachable
+	for _, symbol := range symbols {
+		reachable, determined := refs.Reachable(symbol)
+		if reachable {
+			return types.Reachable
+		} else if !determined {
+			result = ""
+		}
+	}
+	return result
+}
@@ -0,0 +1,198 @@
+package reachability_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/reachability"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestFill(t *testing.T) {
+	tests := []struct {
+		name         string
+		root         string
+		metadataFile string
+		result       types.Result
+		want         []types.Reachability
+		wantErr      string
+	}{
+		{
+			name:         "Go binary",
+			root:         "../dependency/parser/golang/binary/testdata",
+			metadataFile: "testdata/symbols.yaml",
+			result: types.Result{
+				Target: "test.elf",
+				Type:   ftypes.GoBinary,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{VulnerabilityID: "CVE-2024-0001"},
+					{VulnerabilityID: "CVE-2024-0002"},
+					{VulnerabilityID: "CVE-2024-9999"},
+				},
+			},
+			want: []types.Reachability{
+				types.Reachable,
+				types.Unreachable,
+				"",
+			},
+		},
+		{
+			name:         "Go source with vendor",
+			root:         "testdata",
+			metadataFile: "testdata/symbols.yaml",
+			result: types.Result{
+				Target: "gomod/go.mod",
+				Type:   ftypes.GoModule,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID: "CVE-2024-0003",
+						VendorIDs:       []string{"GO-2024-0003"},
+					},
+					{VulnerabilityID: "GO-2024-0004"},
+				},
+			},
+			want: []types.Reachability{
+				types.Reachable,
+				types.Unreachable,
+			},
+		},
+		{
+			name:         "not Go or Java",
+			root:         "testdata",
+			metadataFile: "testdata/symbols.yaml",
+			result: types.Result{
+				Target: "package-lock.json",
+				Type:   ftypes.Npm,
+				Vulnerabilities: []types.DetectedVulnerability{
+					{VulnerabilityID: "CVE-2024-0001"},
+				},
+			},
+			want: []types.Reachability{""},
+		},
+		{
+			name:         "invalid metadata",
+			root:         "testdata",
+			metadataFile: "testdata/invalid.yaml",
+			wantErr:      "no symbols for CVE-2024-0001",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := types.Results{tt.result}
+			err := reachability.Fill(tt.root, tt.metadataFile, results)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []types.Reachability
+			for _, vuln := range results[0].Vulnerabilities {
+				got = append(got, vuln.Reachability)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFill_Java(t *testing.T) {
+	root := t.TempDir()
+
+	// The library calls its own vulnerable method, which must not make it reachable
+	writeJar(t, filepath.Join(root, "lib", "parser.jar"), map[string][]byte{
+		"com/example/lib/Parser.class": javaClass("com/example/lib/Parser", "com/example/lib/Parser.eval"),
+	})
+	// The application is packaged as a fat JAR
+	app := writeJar(t, filepath.Join(t.TempDir(), "app.jar"), map[string][]byte{
+		"com/example/app/Main.class": javaClass("com/example/app/Main", "com/example/lib/Parser.parse"),
+	})
+	writeJar(t, filepath.Join(root, "app", "boot.jar"), map[string][]byte{
+		"BOOT-INF/lib/app.jar": app,
+	})
+
+	results := types.Results{
+		{
+			Target: "Java",
+			Type:   ftypes.Jar,
+			Vulnerabilities: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2024-0005"},
+				{VulnerabilityID: "CVE-2024-0006"},
+				{VulnerabilityID: "CVE-2024-0007"},
+			},
+		},
+	}
+	err := reachability.Fill(root, "testdata/symbols.yaml", results)
+	require.NoError(t, err)
+
+	var got []types.Reachability
+	for _, vuln := range results[0].Vulnerabilities {
+		got = append(got, vuln.Reachability)
+	}
+	assert.Equal(t, []types.Reachability{
+		types.Reachable,
+		types.Unreachable,
+		"", // the class is not found
+	}, got)
+}
+
+// javaClass returns a minimal class file calling the given methods, e.g. com/example/Foo.bar
+func javaClass(name string, methods ...string) []byte {
+	var pool bytes.Buffer
+	count := uint16(1)
+	add := func(tag byte, data ...any) uint16 {
+		pool.WriteByte(tag)
+		for _, d := range data {
+			_ = binary.Write(&pool, binary.BigEndian, d)
+		}
+		count++
+		return count - 1
+	}
+	utf8 := func(s string) uint16 {
+		return add(1, uint16(len(s)), []byte(s))
+	}
+
+	// Long takes two entries
+	add(5, int64(42))
+	count++
+
+	thisClass := add(7, utf8(name))
+	for _, method := range methods {
+		owner, methodName, _ := strings.Cut(method, ".")
+		class := add(7, utf8(owner))
+		nameAndType := add(12, utf8(methodName), utf8("()V"))
+		add(10, class, nameAndType)
+	}
+
+	var b bytes.Buffer
+	_ = binary.Write(&b, binary.BigEndian, uint32(0xCAFEBABE))
+	_ = binary.Write(&b, binary.BigEndian, []uint16{0, 65, count})
+	b.Write(pool.Bytes())
+	// access flags, this class, super class, interfaces, fields, methods and attributes
+	_ = binary.Write(&b, binary.BigEndian, []uint16{0x21, thisClass, 0, 0, 0, 0, 0})
+	return b.Bytes()
+}
+
+func writeJar(t *testing.T, filePath string, files map[string][]byte) []byte {
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+	require.NoError(t, os.WriteFile(filePath, b.Bytes(), 0644))
+	return b.Bytes()
+}
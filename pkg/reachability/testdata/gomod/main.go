@@ -0,0 +1,8 @@
+package main
+
+import "example.com/lib"
+
+func main() {
+	c := lib.Client{}
+	c.Do()
+}
@@ -33,7 +33,7 @@ func Get(ctx context.Context, ref name.Reference, option types.RegistryOptions)
 
 	var errs error
 	// Try each authentication method until it succeeds
-	for _, authOpt := range authOptions(ctx, ref, option) {
+	for _, authOpt := range authOptions(ctx, ref.Context().RegistryStr(), option) {
 		remoteOpts := []remote.Option{
 			remote.WithTransport(transport),
 			authOpt,
@@ -78,7 +78,7 @@ func Image(ctx context.Context, ref name.Reference, option types.RegistryOptions
 
 	var errs error
 	// Try each authentication method until it succeeds
-	for _, authOpt := range authOptions(ctx, ref, option) {
+	for _, authOpt := range authOptions(ctx, ref.Context().RegistryStr(), option) {
 		remoteOpts := []remote.Option{
 			remote.WithTransport(transport),
 			authOpt,
@@ -105,7 +105,7 @@ func Referrers(ctx context.Context, d name.Digest, option types.RegistryOptions)
 
 	var errs error
 	// Try each authentication method until it succeeds
-	for _, authOpt := range authOptions(ctx, d, option) {
+	for _, authOpt := range authOptions(ctx, d.Context().RegistryStr(), option) {
 		remoteOpts := []remote.Option{
 			remote.WithTransport(transport),
 			authOpt,
@@ -122,6 +122,52 @@ func Referrers(ctx context.Context, d name.Digest, option types.RegistryOptions)
 	return nil, errs
 }
 
+// Catalog is a wrapper of google/go-containerregistry/pkg/v1/remote.Catalog
+// so that it can try multiple authentication methods.
+func Catalog(ctx context.Context, reg name.Registry, option types.RegistryOptions) ([]string, error) {
+	transport, err := httpTransport(option)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create http transport: %w", err)
+	}
+
+	var errs error
+	// Try each authentication method until it succeeds
+	for _, authOpt := range authOptions(ctx, reg.RegistryStr(), option) {
+		repos, err := remote.Catalog(ctx, reg, remote.WithTransport(transport), authOpt)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		return repos, nil
+	}
+
+	// No authentication succeeded
+	return nil, errs
+}
+
+// List is a wrapper of google/go-containerregistry/pkg/v1/remote.List
+// so that it can try multiple authentication methods.
+func List(ctx context.Context, repo name.Repository, option types.RegistryOptions) ([]string, error) {
+	transport, err := httpTransport(option)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create http transport: %w", err)
+	}
+
+	var errs error
+	// Try each authentication method until it succeeds
+	for _, authOpt := range authOptions(ctx, repo.RegistryStr(), option) {
+		tags, err := remote.List(repo, remote.WithContext(ctx), remote.WithTransport(transport), authOpt)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		return tags, nil
+	}
+
+	// No authentication succeeded
+	return nil, errs
+}
+
 func httpTransport(option types.RegistryOptions) (*http.Transport, error) {
 	d := &net.Dialer{
 		Timeout: 10 * time.Minute,
@@ -141,7 +187,7 @@ func httpTransport(option types.RegistryOptions) (*http.Transport, error) {
 	return tr, nil
 }
 
-func authOptions(ctx context.Context, ref name.Reference, option types.RegistryOptions) []remote.Option {
+func authOptions(ctx context.Context, domain string, option types.RegistryOptions) []remote.Option {
 	var opts []remote.Option
 	for _, cred := range option.Credentials {
 		opts = append(opts, remote.WithAuth(&authn.Basic{
@@ -150,7 +196,6 @@ func authOptions(ctx context.Context, ref name.Reference, option types.RegistryO
 		}))
 	}
 
-	domain := ref.Context().RegistryStr()
 	token := registry.GetToken(ctx, domain, option)
 	if !lo.IsEmpty(token) {
 		opts = append(opts, remote.WithAuth(&token))
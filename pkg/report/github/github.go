@@ -70,6 +70,9 @@ type DependencySnapshot struct {
 type Writer struct {
 	Output  io.Writer
 	Version string
+
+	// Submit posts the snapshot to the dependency submission API as well
+	Submit bool
 }
 
 func (w Writer) Write(ctx context.Context, report types.Report) error {
@@ -127,24 +130,38 @@ func (w Writer) Write(ctx context.Context, report types.Report) error {
 			}
 		}
 
+		packageUrls := make([]string, len(result.Packages))
+		purls := make(map[string]string) // package ID => package URL
+		for i, pkg := range result.Packages {
+			packageUrl, err := buildPurl(result.Type, report.Metadata, pkg)
+			if err != nil {
+				return xerrors.Errorf("unable to build purl for %s: %w", pkg.Name, err)
+			}
+			packageUrls[i] = packageUrl
+			if pkg.ID != "" {
+				purls[pkg.ID] = packageUrl
+			}
+		}
+
 		resolved := make(map[string]Package)
 
-		for _, pkg := range result.Packages {
-			var err error
+		for i, pkg := range result.Packages {
 			githubPkg := Package{}
-			githubPkg.Scope = RuntimeScope
+			githubPkg.Scope = getPkgScope(pkg)
 			githubPkg.Relationship = getPkgRelationshipType(pkg)
-			githubPkg.Dependencies = pkg.DependsOn // TODO: replace with PURL
-			githubPkg.PackageUrl, err = buildPurl(result.Type, report.Metadata, pkg)
-			if err != nil {
-				return xerrors.Errorf("unable to build purl for %s: %w", pkg.Name, err)
-			}
+			githubPkg.Dependencies = getPkgDependencies(pkg, purls) // Dependencies are referenced by package URLs
+			githubPkg.PackageUrl = packageUrls[i]
 
 			if pkg.FilePath != "" {
 				githubPkg.Metadata = Metadata{"source_location": pkg.FilePath}
 			}
 
-			resolved[pkg.Name] = githubPkg
+			// Multiple versions of the same package can be resolved, e.g. in package-lock.json
+			key := pkg.Name
+			if _, ok := resolved[key]; ok {
+				key = fmt.Sprintf("%s@%s", pkg.Name, pkg.Version)
+			}
+			resolved[key] = githubPkg
 		}
 
 		manifest.Resolved = resolved
@@ -161,6 +178,12 @@ func (w Writer) Write(ctx context.Context, report types.Report) error {
 	if _, err = fmt.Fprint(w.Output, string(output)); err != nil {
 		return xerrors.Errorf("failed to write github dependency snapshots: %w", err)
 	}
+
+	if w.Submit {
+		if err = submit(ctx, snapshot, output); err != nil {
+			return xerrors.Errorf("failed to submit github dependency snapshots: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -175,6 +198,24 @@ func getMetadata(report types.Report) Metadata {
 	return metadata
 }
 
+func getPkgScope(pkg ftypes.Package) string {
+	if pkg.Dev {
+		return DevelopmentScope
+	}
+	return RuntimeScope
+}
+
+func getPkgDependencies(pkg ftypes.Package, purls map[string]string) []string {
+	var dependencies []string
+	for _, dependsOn := range pkg.DependsOn {
+		// Dependencies without package URLs cannot be referenced in the snapshot
+		if packageUrl := purls[dependsOn]; packageUrl != "" {
+			dependencies = append(dependencies, packageUrl)
+		}
+	}
+	return dependencies
+}
+
 func getPkgRelationshipType(pkg ftypes.Package) string {
 	if pkg.Indirect {
 		return IndirectRelationship
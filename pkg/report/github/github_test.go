@@ -2,11 +2,17 @@ package github_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
@@ -231,6 +237,70 @@ func TestWriter_Write(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "npm with dependencies",
+			report: types.Report{
+				SchemaVersion: 2,
+				ArtifactName:  "my-node-app",
+				Results: types.Results{
+					{
+						Target: "package-lock.json",
+						Class:  "lang-pkgs",
+						Type:   "npm",
+						Packages: []ftypes.Package{
+							{
+								ID:      "debug@2.6.9",
+								Name:    "debug",
+								Version: "2.6.9",
+								DependsOn: []string{
+									"ms@2.0.0",
+								},
+							},
+							{
+								ID:       "ms@2.0.0",
+								Name:     "ms",
+								Version:  "2.0.0",
+								Indirect: true,
+							},
+							{
+								ID:      "ms@2.1.3",
+								Name:    "ms",
+								Version: "2.1.3",
+								Dev:     true,
+							},
+						},
+					},
+				},
+			},
+			want: map[string]github.Manifest{
+				"package-lock.json": {
+					Name: "npm",
+					File: &github.File{
+						SrcLocation: "package-lock.json",
+					},
+					Resolved: map[string]github.Package{
+						"debug": {
+							PackageUrl:   "pkg:npm/debug@2.6.9",
+							Relationship: "direct",
+							Dependencies: []string{
+								"pkg:npm/ms@2.0.0",
+							},
+							Scope: "runtime",
+						},
+						"ms": {
+							PackageUrl:   "pkg:npm/ms@2.0.0",
+							Relationship: "indirect",
+							Scope:        "runtime",
+						},
+						"ms@2.1.3": {
+							PackageUrl:   "pkg:npm/ms@2.1.3",
+							Relationship: "direct",
+							Scope:        "development",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -252,3 +322,139 @@ func TestWriter_Write(t *testing.T) {
 		})
 	}
 }
+
+func TestWriter_Write_Submit(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository string
+		status     int
+		response   string
+		wantErr    string
+	}{
+		{
+			name:       "happy path",
+			repository: "octo-org/octo-repo",
+			status:     http.StatusCreated,
+			response:   `{"id": 1, "created_at": "2024-01-01T00:00:00Z", "result": "SUCCESS", "message": "Dependency results for the repo have been successfully updated."}`,
+		},
+		{
+			name:       "bad credentials",
+			repository: "octo-org/octo-repo",
+			status:     http.StatusUnauthorized,
+			response:   `{"message": "Bad credentials"}`,
+			wantErr:    "401 Unauthorized: Bad credentials",
+		},
+		{
+			name:    "no repository",
+			wantErr: "GITHUB_REPOSITORY must be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got github.DependencySnapshot
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/repos/octo-org/octo-repo/dependency-graph/snapshots", r.URL.Path)
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer ts.Close()
+
+			t.Setenv("GITHUB_API_URL", ts.URL)
+			t.Setenv("GITHUB_REPOSITORY", tt.repository)
+			t.Setenv("GITHUB_TOKEN", "secret")
+			t.Setenv("GITHUB_REF", "refs/heads/main")
+			t.Setenv("GITHUB_SHA", "39da54a1ff04120a31df8cbc94ce9ede251d21a3")
+
+			w := github.Writer{
+				Output:  io.Discard,
+				Version: "dev",
+				Submit:  true,
+			}
+			err := w.Write(context.Background(), types.Report{
+				SchemaVersion: 2,
+				ArtifactName:  "my-java-app",
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "refs/heads/main", got.Ref)
+			assert.Equal(t, "39da54a1ff04120a31df8cbc94ce9ede251d21a3", got.Sha)
+		})
+	}
+}
+
+func TestUploadSARIF(t *testing.T) {
+	sarif := []byte(`{"version": "2.1.0", "runs": []}`)
+	tests := []struct {
+		name     string
+		ref      string
+		status   int
+		response string
+		wantErr  string
+	}{
+		{
+			name:     "happy path",
+			ref:      "refs/heads/main",
+			status:   http.StatusAccepted,
+			response: `{"id": "47177e22-5596-11eb-80a1-c1e54ef945c6", "url": "https://api.github.com/repos/octo-org/octo-repo/code-scanning/sarifs/47177e22-5596-11eb-80a1-c1e54ef945c6"}`,
+		},
+		{
+			name:     "no permission",
+			ref:      "refs/heads/main",
+			status:   http.StatusForbidden,
+			response: `{"message": "Resource not accessible by integration"}`,
+			wantErr:  "403 Forbidden: Resource not accessible by integration",
+		},
+		{
+			name:    "no ref",
+			wantErr: "GITHUB_REF and GITHUB_SHA must be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/repos/octo-org/octo-repo/code-scanning/sarifs", r.URL.Path)
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+				var req struct {
+					CommitSha string `json:"commit_sha"`
+					Ref       string `json:"ref"`
+					Sarif     string `json:"sarif"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, "39da54a1ff04120a31df8cbc94ce9ede251d21a3", req.CommitSha)
+				assert.Equal(t, tt.ref, req.Ref)
+
+				b, err := base64.StdEncoding.DecodeString(req.Sarif)
+				require.NoError(t, err)
+				zr, err := gzip.NewReader(bytes.NewReader(b))
+				require.NoError(t, err)
+				got, err := io.ReadAll(zr)
+				require.NoError(t, err)
+				assert.Equal(t, sarif, got)
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer ts.Close()
+
+			t.Setenv("GITHUB_API_URL", ts.URL)
+			t.Setenv("GITHUB_REPOSITORY", "octo-org/octo-repo")
+			t.Setenv("GITHUB_TOKEN", "secret")
+			t.Setenv("GITHUB_REF", tt.ref)
+			t.Setenv("GITHUB_SHA", "39da54a1ff04120a31df8cbc94ce9ede251d21a3")
+
+			err := github.UploadSARIF(context.Background(), sarif)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
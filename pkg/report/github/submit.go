@@ -0,0 +1,150 @@
+package github
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+const defaultAPIURL = "https://api.github.com"
+
+type submissionResponse struct {
+	ID      int    `json:"id"`
+	Result  string `json:"result"`
+	Message string `json:"message"`
+}
+
+type sarifUploadRequest struct {
+	CommitSha string `json:"commit_sha"`
+	Ref       string `json:"ref"`
+	Sarif     string `json:"sarif"`
+	ToolName  string `json:"tool_name"`
+}
+
+type sarifUploadResponse struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// submit posts the snapshot to the dependency submission API.
+// The repository and the token are taken from the environment variables of GitHub Actions.
+// cf. https://docs.github.com/en/rest/dependency-graph/dependency-submission
+func submit(ctx context.Context, snapshot *DependencySnapshot, body []byte) error {
+	if snapshot.Ref == "" || snapshot.Sha == "" {
+		return xerrors.New("GITHUB_REF and GITHUB_SHA must be set to the commit the snapshot is associated with")
+	}
+
+	var res submissionResponse
+	repository, err := post(ctx, "dependency-graph/snapshots", body, http.StatusCreated, &res)
+	if err != nil {
+		return err
+	}
+
+	log.Logger.Infof("Submitted the dependency snapshot to %s (id: %d, result: %s): %s", repository, res.ID, res.Result, res.Message)
+	return nil
+}
+
+// UploadSARIF uploads the SARIF report to the code scanning API so that the results are shown as code scanning alerts.
+// The report is associated with the commit of GITHUB_SHA and GITHUB_REF, which is set by GitHub Actions.
+// cf. https://docs.github.com/en/rest/code-scanning/code-scanning#upload-an-analysis-as-sarif-data
+func UploadSARIF(ctx context.Context, sarif []byte) error {
+	ref, sha := os.Getenv("GITHUB_REF"), os.Getenv("GITHUB_SHA")
+	if ref == "" || sha == "" {
+		return xerrors.New("GITHUB_REF and GITHUB_SHA must be set to the commit the analysis is associated with")
+	}
+
+	// The API requires the SARIF compressed with gzip and encoded with Base64
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(sarif); err != nil {
+		return xerrors.Errorf("unable to compress the SARIF: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return xerrors.Errorf("unable to compress the SARIF: %w", err)
+	}
+
+	body, err := json.Marshal(sarifUploadRequest{
+		CommitSha: sha,
+		Ref:       ref,
+		Sarif:     base64.StdEncoding.EncodeToString(buf.Bytes()),
+		ToolName:  "Trivy",
+	})
+	if err != nil {
+		return xerrors.Errorf("unable to marshal the request: %w", err)
+	}
+
+	var res sarifUploadResponse
+	repository, err := post(ctx, "code-scanning/sarifs", body, http.StatusAccepted, &res)
+	if err != nil {
+		return err
+	}
+
+	// The analysis is processed asynchronously, and the URL shows the processing status
+	log.Logger.Infof("Uploaded the SARIF to %s (id: %s): %s", repository, res.ID, res.URL)
+	return nil
+}
+
+// post sends the body to the API of the repository of GITHUB_REPOSITORY with GITHUB_TOKEN, and decodes the response into v.
+// It returns the repository for logging.
+func post(ctx context.Context, path string, body []byte, wantStatus int, v any) (string, error) {
+	repository := os.Getenv("GITHUB_REPOSITORY")
+	if repository == "" {
+		return "", xerrors.New("GITHUB_REPOSITORY must be set to the repository, e.g. 'octo-org/octo-repo'")
+	}
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return "", xerrors.New("GITHUB_TOKEN must be set to the token with 'contents: write' or 'security-events: write' permission")
+	}
+
+	apiURL := os.Getenv("GITHUB_API_URL") // for GitHub Enterprise Server
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s", strings.TrimSuffix(apiURL, "/"), repository, path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", xerrors.Errorf("unable to create a request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", xerrors.Errorf("unable to read the response: %w", err)
+	}
+
+	if resp.StatusCode != wantStatus {
+		// Errors of the API have "message"
+		var res struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(b, &res) == nil && res.Message != "" {
+			return "", xerrors.Errorf("%s: %s", resp.Status, res.Message)
+		}
+		return "", xerrors.Errorf("%s: %s", resp.Status, string(b))
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return "", xerrors.Errorf("unable to decode the response: %w", err)
+	}
+	return repository, nil
+}
@@ -0,0 +1,261 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// SchemaVersion is the version of the GitLab security report schemas
+// cf. https://gitlab.com/gitlab-org/security-products/security-report-schemas
+const SchemaVersion = "15.0.7"
+
+// timeFormat is the format of "start_time" and "end_time" required by the schemas
+const timeFormat = "2006-01-02T15:04:05"
+
+// ReportType is the type of the GitLab security report
+type ReportType string
+
+const (
+	DependencyScanning ReportType = "dependency_scanning"
+	ContainerScanning  ReportType = "container_scanning"
+)
+
+type Report struct {
+	Version         string          `json:"version"`
+	Scan            Scan            `json:"scan"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+	Remediations    []any           `json:"remediations"`
+
+	// Only for dependency_scanning
+	DependencyFiles []DependencyFile `json:"dependency_files,omitempty"`
+}
+
+type Scan struct {
+	Analyzer  Tool       `json:"analyzer"`
+	Scanner   Tool       `json:"scanner"`
+	Type      ReportType `json:"type"`
+	StartTime string     `json:"start_time"`
+	EndTime   string     `json:"end_time"`
+	Status    string     `json:"status"`
+}
+
+type Tool struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	URL     string `json:"url,omitempty"`
+	Vendor  Vendor `json:"vendor"`
+	Version string `json:"version"`
+}
+
+type Vendor struct {
+	Name string `json:"name"`
+}
+
+type Vulnerability struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Severity    string       `json:"severity"`
+	Solution    string       `json:"solution,omitempty"`
+	Identifiers []Identifier `json:"identifiers"`
+	Links       []Link       `json:"links,omitempty"`
+	Location    Location     `json:"location"`
+}
+
+type Identifier struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+type Link struct {
+	URL string `json:"url"`
+}
+
+// Location has "file" for dependency_scanning, and "image" and "operating_system" for container_scanning
+type Location struct {
+	File            string     `json:"file,omitempty"`
+	Image           string     `json:"image,omitempty"`
+	OperatingSystem string     `json:"operating_system,omitempty"`
+	Dependency      Dependency `json:"dependency"`
+}
+
+type Dependency struct {
+	Package Package `json:"package"`
+	Version string  `json:"version"`
+}
+
+type Package struct {
+	Name string `json:"name"`
+}
+
+type DependencyFile struct {
+	Path           string       `json:"path"`
+	PackageManager string       `json:"package_manager"`
+	Dependencies   []Dependency `json:"dependencies"`
+}
+
+// Writer writes the report in the GitLab security report schema of the type,
+// so that the findings are shown in the security widget of merge requests.
+type Writer struct {
+	Output  io.Writer
+	Version string
+	Type    ReportType
+}
+
+func (w Writer) Write(ctx context.Context, report types.Report) error {
+	tool := Tool{
+		ID:      "trivy",
+		Name:    "Trivy",
+		URL:     "https://github.com/aquasecurity/trivy",
+		Vendor:  Vendor{Name: "Aqua Security"},
+		Version: w.Version,
+	}
+	glReport := Report{
+		Version: SchemaVersion,
+		Scan: Scan{
+			Analyzer:  tool,
+			Scanner:   tool,
+			Type:      w.Type,
+			StartTime: report.CreatedAt.UTC().Format(timeFormat),
+			EndTime:   clock.Now(ctx).UTC().Format(timeFormat),
+			Status:    "success",
+		},
+		Vulnerabilities: []Vulnerability{},
+		Remediations:    []any{},
+	}
+
+	switch w.Type {
+	case DependencyScanning:
+		glReport.DependencyFiles = []DependencyFile{}
+		for _, result := range report.Results {
+			// Only lock files and manifests have the path the widget can link to
+			if result.Class != types.ClassLangPkg {
+				continue
+			}
+			for _, vuln := range result.Vulnerabilities {
+				glReport.Vulnerabilities = append(glReport.Vulnerabilities, w.vulnerability(result, vuln, Location{
+					File: result.Target,
+				}))
+			}
+			glReport.DependencyFiles = append(glReport.DependencyFiles, dependencyFile(result))
+		}
+	case ContainerScanning:
+		osName := "Unknown"
+		if report.Metadata.OS != nil {
+			osName = fmt.Sprintf("%s %s", report.Metadata.OS.Family, report.Metadata.OS.Name)
+		}
+		for _, result := range report.Results {
+			for _, vuln := range result.Vulnerabilities {
+				glReport.Vulnerabilities = append(glReport.Vulnerabilities, w.vulnerability(result, vuln, Location{
+					Image:           report.ArtifactName,
+					OperatingSystem: osName,
+				}))
+			}
+		}
+	default:
+		return xerrors.Errorf("unknown GitLab report type: %s", w.Type)
+	}
+
+	b, err := json.MarshalIndent(glReport, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("failed to marshal the GitLab %s report: %w", w.Type, err)
+	}
+	if _, err = fmt.Fprintln(w.Output, string(b)); err != nil {
+		return xerrors.Errorf("failed to write the GitLab %s report: %w", w.Type, err)
+	}
+	return nil
+}
+
+func (w Writer) vulnerability(result types.Result, vuln types.DetectedVulnerability, location Location) Vulnerability {
+	location.Dependency = Dependency{
+		Package: Package{Name: vuln.PkgName},
+		Version: vuln.InstalledVersion,
+	}
+
+	var solution string
+	if vuln.FixedVersion != "" {
+		solution = fmt.Sprintf("Upgrade %s to %s", vuln.PkgName, vuln.FixedVersion)
+	}
+
+	var links []Link
+	for _, ref := range vuln.References {
+		links = append(links, Link{URL: ref})
+	}
+
+	name := vuln.Title
+	if name == "" {
+		name = vuln.VulnerabilityID
+	}
+
+	return Vulnerability{
+		// GitLab tracks the vulnerabilities across pipelines by the ID, so it must be stable
+		ID: uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.Join([]string{
+			string(w.Type), result.Target, vuln.PkgName, vuln.InstalledVersion, vuln.VulnerabilityID,
+		}, "/"))).String(),
+		Name:        name,
+		Description: vuln.Description,
+		Severity:    severity(vuln.Severity),
+		Solution:    solution,
+		Identifiers: []Identifier{
+			{
+				Type:  identifierType(vuln.VulnerabilityID),
+				Name:  vuln.VulnerabilityID,
+				Value: vuln.VulnerabilityID,
+				URL:   vuln.PrimaryURL,
+			},
+		},
+		Links:    links,
+		Location: location,
+	}
+}
+
+func dependencyFile(result types.Result) DependencyFile {
+	deps := []Dependency{}
+	for _, pkg := range result.Packages {
+		deps = append(deps, Dependency{
+			Package: Package{Name: pkg.Name},
+			Version: pkg.Version,
+		})
+	}
+	return DependencyFile{
+		Path:           result.Target,
+		PackageManager: string(result.Type),
+		Dependencies:   deps,
+	}
+}
+
+// severity converts the severity to the one of the schemas, e.g. "CRITICAL" => "Critical"
+func severity(s string) string {
+	switch s {
+	case "CRITICAL":
+		return "Critical"
+	case "HIGH":
+		return "High"
+	case "MEDIUM":
+		return "Medium"
+	case "LOW":
+		return "Low"
+	default:
+		return "Unknown"
+	}
+}
+
+// identifierType returns the lowercased prefix of the ID, e.g. "CVE-2021-44228" => "cve", "GHSA-jfh8-c2jp-5v3q" => "ghsa"
+func identifierType(id string) string {
+	prefix, _, found := strings.Cut(id, "-")
+	if !found {
+		return "trivy"
+	}
+	return strings.ToLower(prefix)
+}
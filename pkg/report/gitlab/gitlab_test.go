@@ -0,0 +1,215 @@
+package gitlab_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/gitlab"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var report = types.Report{
+	ArtifactName: "alpine:3.19",
+	ArtifactType: ftypes.ArtifactContainerImage,
+	CreatedAt:    time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC),
+	Metadata: types.Metadata{
+		OS: &ftypes.OS{
+			Family: ftypes.Alpine,
+			Name:   "3.19.0",
+		},
+	},
+	Results: types.Results{
+		{
+			Target: "alpine:3.19 (alpine 3.19.0)",
+			Class:  types.ClassOSPkg,
+			Type:   ftypes.Alpine,
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2024-0727",
+					PkgName:          "openssl",
+					InstalledVersion: "3.1.4-r2",
+					FixedVersion:     "3.1.4-r5",
+					PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2024-0727",
+					Vulnerability: dbTypes.Vulnerability{
+						Title:      "openssl: denial of service via null dereference",
+						Severity:   "MEDIUM",
+						References: []string{"https://www.openssl.org/news/secadv/20240125.txt"},
+					},
+				},
+			},
+		},
+		{
+			Target: "app/package-lock.json",
+			Class:  types.ClassLangPkg,
+			Type:   ftypes.Npm,
+			Packages: []ftypes.Package{
+				{
+					Name:    "lodash",
+					Version: "4.17.4",
+				},
+			},
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "GHSA-35jh-r3h4-6jhm",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.4",
+					Vulnerability: dbTypes.Vulnerability{
+						Severity: "HIGH",
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      gitlab.ReportType
+		wantVuln []gitlab.Vulnerability
+		wantDeps []gitlab.DependencyFile
+	}{
+		{
+			name: "container scanning",
+			typ:  gitlab.ContainerScanning,
+			wantVuln: []gitlab.Vulnerability{
+				{
+					Name:     "openssl: denial of service via null dereference",
+					Severity: "Medium",
+					Solution: "Upgrade openssl to 3.1.4-r5",
+					Identifiers: []gitlab.Identifier{
+						{
+							Type:  "cve",
+							Name:  "CVE-2024-0727",
+							Value: "CVE-2024-0727",
+							URL:   "https://avd.aquasec.com/nvd/cve-2024-0727",
+						},
+					},
+					Links: []gitlab.Link{
+						{URL: "https://www.openssl.org/news/secadv/20240125.txt"},
+					},
+					Location: gitlab.Location{
+						Image:           "alpine:3.19",
+						OperatingSystem: "alpine 3.19.0",
+						Dependency: gitlab.Dependency{
+							Package: gitlab.Package{Name: "openssl"},
+							Version: "3.1.4-r2",
+						},
+					},
+				},
+				{
+					Name:     "GHSA-35jh-r3h4-6jhm",
+					Severity: "High",
+					Identifiers: []gitlab.Identifier{
+						{
+							Type:  "ghsa",
+							Name:  "GHSA-35jh-r3h4-6jhm",
+							Value: "GHSA-35jh-r3h4-6jhm",
+						},
+					},
+					Location: gitlab.Location{
+						Image:           "alpine:3.19",
+						OperatingSystem: "alpine 3.19.0",
+						Dependency: gitlab.Dependency{
+							Package: gitlab.Package{Name: "lodash"},
+							Version: "4.17.4",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "dependency scanning",
+			typ:  gitlab.DependencyScanning,
+			wantVuln: []gitlab.Vulnerability{
+				{
+					Name:     "GHSA-35jh-r3h4-6jhm",
+					Severity: "High",
+					Identifiers: []gitlab.Identifier{
+						{
+							Type:  "ghsa",
+							Name:  "GHSA-35jh-r3h4-6jhm",
+							Value: "GHSA-35jh-r3h4-6jhm",
+						},
+					},
+					Location: gitlab.Location{
+						File: "app/package-lock.json",
+						Dependency: gitlab.Dependency{
+							Package: gitlab.Package{Name: "lodash"},
+							Version: "4.17.4",
+						},
+					},
+				},
+			},
+			wantDeps: []gitlab.DependencyFile{
+				{
+					Path:           "app/package-lock.json",
+					PackageManager: "npm",
+					Dependencies: []gitlab.Dependency{
+						{
+							Package: gitlab.Package{Name: "lodash"},
+							Version: "4.17.4",
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := clock.With(context.Background(), time.Date(2024, 2, 1, 10, 1, 0, 0, time.UTC))
+			out := bytes.NewBuffer(nil)
+			w := gitlab.Writer{
+				Output:  out,
+				Version: "dev",
+				Type:    tt.typ,
+			}
+			require.NoError(t, w.Write(ctx, report))
+
+			var got gitlab.Report
+			require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+			assert.Equal(t, gitlab.SchemaVersion, got.Version)
+			assert.Equal(t, tt.typ, got.Scan.Type)
+			assert.Equal(t, "2024-02-01T10:00:00", got.Scan.StartTime)
+			assert.Equal(t, "2024-02-01T10:01:00", got.Scan.EndTime)
+			assert.Equal(t, "success", got.Scan.Status)
+
+			// IDs are stable but opaque
+			for i := range got.Vulnerabilities {
+				assert.Len(t, got.Vulnerabilities[i].ID, 36)
+				got.Vulnerabilities[i].ID = ""
+			}
+			assert.Equal(t, tt.wantVuln, got.Vulnerabilities)
+			assert.Equal(t, tt.wantDeps, got.DependencyFiles)
+		})
+	}
+}
+
+func TestWriter_Write_StableID(t *testing.T) {
+	var ids [2][]string
+	for i := range ids {
+		out := bytes.NewBuffer(nil)
+		w := gitlab.Writer{
+			Output: out,
+			Type:   gitlab.ContainerScanning,
+		}
+		require.NoError(t, w.Write(context.Background(), report))
+
+		var got gitlab.Report
+		require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		for _, v := range got.Vulnerabilities {
+			ids[i] = append(ids[i], v.ID)
+		}
+	}
+	assert.Equal(t, ids[0], ids[1])
+	assert.NotEqual(t, ids[0][0], ids[0][1])
+}
@@ -0,0 +1,127 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	wasi "github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/plugin"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const pluginPrefix = "plugin="
+
+// Run passes the report to the hooks in order and returns the report modified by them.
+// A hook is an executable, a WASI module with the ".wasm" extension or an installed plugin with the "plugin=" prefix.
+// It reads the JSON report from stdin and prints the modified report to stdout.
+// The report is kept as is if the hook prints nothing, e.g. when it only sends the report somewhere.
+func Run(ctx context.Context, report types.Report, hooks []string) (types.Report, error) {
+	for _, hook := range hooks {
+		log.Logger.Debugf("Running the report hook: %s", hook)
+
+		in, err := json.Marshal(report)
+		if err != nil {
+			return types.Report{}, xerrors.Errorf("failed to marshal the report: %w", err)
+		}
+
+		out := new(bytes.Buffer)
+		if err = run(ctx, hook, bytes.NewReader(in), out); err != nil {
+			return types.Report{}, xerrors.Errorf("report hook %q error: %w", hook, err)
+		}
+
+		if len(bytes.TrimSpace(out.Bytes())) == 0 {
+			continue
+		}
+
+		var modified types.Report
+		if err = json.Unmarshal(out.Bytes(), &modified); err != nil {
+			return types.Report{}, xerrors.Errorf("report hook %q returned an invalid report: %w", hook, err)
+		}
+		report = modified
+	}
+	return report, nil
+}
+
+func run(ctx context.Context, hook string, stdin io.Reader, stdout io.Writer) error {
+	switch {
+	case strings.HasPrefix(hook, pluginPrefix):
+		return runPlugin(ctx, strings.TrimPrefix(hook, pluginPrefix), stdin, stdout)
+	case filepath.Ext(hook) == ".wasm":
+		return runWASM(ctx, hook, stdin, stdout)
+	default:
+		return runExecutable(ctx, hook, stdin, stdout)
+	}
+}
+
+func runExecutable(ctx context.Context, path string, stdin io.Reader, stdout io.Writer) error {
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return xerrors.Errorf("exec error: %w", err)
+	}
+	return nil
+}
+
+func runPlugin(ctx context.Context, name string, stdin io.Reader, stdout io.Writer) error {
+	wait, err := plugin.Start(ctx, name, plugin.RunOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+	})
+	if err != nil {
+		return xerrors.Errorf("plugin start: %w", err)
+	}
+	if err = wait(); err != nil {
+		return xerrors.Errorf("plugin exec: %w", err)
+	}
+	return nil
+}
+
+// runWASM runs the "_start" function of the WASI module, i.e. "main", with the report as stdin.
+// Unlike the modules in "~/.trivy/modules", it doesn't need the Trivy module SDK.
+func runWASM(ctx context.Context, path string, stdin io.Reader, stdout io.Writer) error {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return xerrors.Errorf("failed to read the WASM module: %w", err)
+	}
+
+	r := wazero.NewRuntime(ctx)
+	defer r.Close(ctx)
+
+	if _, err = wasi.NewBuilder(r).Instantiate(ctx); err != nil {
+		return xerrors.Errorf("WASI init error: %w", err)
+	}
+
+	compiled, err := r.CompileModule(ctx, code)
+	if err != nil {
+		return xerrors.Errorf("module compile error: %w", err)
+	}
+
+	config := wazero.NewModuleConfig().
+		WithName(filepath.Base(path)).
+		WithArgs(filepath.Base(path)).
+		WithStdin(stdin).
+		WithStdout(stdout).
+		WithStderr(os.Stderr)
+	if _, err = r.InstantiateModule(ctx, compiled, config); err != nil {
+		var exitErr *sys.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 0 {
+			return nil
+		}
+		return xerrors.Errorf("module exec error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package hook_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/hook"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var report = types.Report{
+	SchemaVersion: 2,
+	ArtifactName:  "alpine:3.19",
+	ArtifactType:  ftypes.ArtifactContainerImage,
+	Results: types.Results{
+		{
+			Target: "alpine:3.19 (alpine 3.19.1)",
+			Class:  types.ClassOSPkg,
+			Type:   ftypes.Alpine,
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2024-0727",
+					PkgName:          "libcrypto3",
+					InstalledVersion: "3.1.4-r2",
+					Vulnerability: dbTypes.Vulnerability{
+						Severity: "HIGH",
+					},
+				},
+				{
+					VulnerabilityID:  "CVE-2023-6237",
+					PkgName:          "libcrypto3",
+					InstalledVersion: "3.1.4-r2",
+					Vulnerability: dbTypes.Vulnerability{
+						Severity: "LOW",
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   []string
+		want    func(r types.Report) types.Report
+		wantErr string
+	}{
+		{
+			name:  "no hooks",
+			hooks: nil,
+			want: func(r types.Report) types.Report {
+				return r
+			},
+		},
+		{
+			name: "modify the report",
+			hooks: []string{
+				"testdata/enrich.sh",
+			},
+			want: func(r types.Report) types.Report {
+				r.ArtifactName = "enriched"
+				return r
+			},
+		},
+		{
+			name: "hook printing nothing",
+			hooks: []string{
+				"testdata/silent.sh",
+				"testdata/enrich.sh",
+			},
+			want: func(r types.Report) types.Report {
+				r.ArtifactName = "enriched"
+				return r
+			},
+		},
+		{
+			name: "hook failure",
+			hooks: []string{
+				"testdata/fail.sh",
+			},
+			wantErr: `report hook "testdata/fail.sh" error`,
+		},
+		{
+			name: "invalid report",
+			hooks: []string{
+				"testdata/invalid.sh",
+			},
+			wantErr: `report hook "testdata/invalid.sh" returned an invalid report`,
+		},
+		{
+			name: "missing executable",
+			hooks: []string{
+				"testdata/missing.sh",
+			},
+			wantErr: "exec error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hook.Run(context.Background(), report, tt.hooks)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want(report), got)
+		})
+	}
+}
+
+func TestRun_WASM(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping building the WASM module in short mode")
+	}
+
+	wasmFile := filepath.Join(t.TempDir(), "filter.wasm")
+	cmd := exec.Command("go", "build", "-o", wasmFile, "main.go")
+	cmd.Dir = filepath.Join("testdata", "wasm")
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("unable to build the WASM module: %s", out)
+	}
+
+	got, err := hook.Run(context.Background(), report, []string{wasmFile})
+	require.NoError(t, err)
+
+	want := report
+	want.Results = types.Results{report.Results[0]}
+	want.Results[0].Vulnerabilities = report.Results[0].Vulnerabilities[:1]
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,2 @@
+#!/bin/sh
+sed 's/"ArtifactName":"[^"]*"/"ArtifactName":"enriched"/'
@@ -0,0 +1,3 @@
+#!/bin/sh
+echo "no owner found" >&2
+exit 1
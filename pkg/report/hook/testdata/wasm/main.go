@@ -0,0 +1,30 @@
+// This is a report hook removing the vulnerabilities with LOW severity.
+// GOOS=wasip1 GOARCH=wasm go build -o filter.wasm main.go
+package main
+
+import (
+	"encoding/json"
+	"os"
+)
+
+func main() {
+	var report map[string]any
+	if err := json.NewDecoder(os.Stdin).Decode(&report); err != nil {
+		os.Exit(1)
+	}
+	results, _ := report["Results"].([]any)
+	for _, r := range results {
+		result := r.(map[string]any)
+		vulns, _ := result["Vulnerabilities"].([]any)
+		var filtered []any
+		for _, v := range vulns {
+			if v.(map[string]any)["Severity"] != "LOW" {
+				filtered = append(filtered, v)
+			}
+		}
+		result["Vulnerabilities"] = filtered
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,198 @@
+package html
+
+import (
+	"context"
+	_ "embed"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/report/layers"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// The template has the styles and scripts inline so that the report can be opened offline and shared as a single file
+//
+//go:embed report.html.tpl
+var reportTemplate string
+
+var tmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+}).Parse(reportTemplate))
+
+// Writer writes the report as a self-contained HTML page with filters by severity and status, and search by package.
+// The page is meant to be shared with people who don't run Trivy.
+type Writer struct {
+	Output  io.Writer
+	Version string
+}
+
+type page struct {
+	ArtifactName string
+	ArtifactType string
+	CreatedAt    string
+	Version      string
+
+	// Severities are the most severe first, with the number of the findings
+	Severities []severityCount
+	Statuses   []string
+
+	// HasLayers is true if the vulnerabilities can be attributed to the image layers
+	HasLayers bool
+
+	Vulnerabilities   []vulnerability
+	Misconfigurations []misconfiguration
+	Secrets           []secret
+	Licenses          []license
+}
+
+type severityCount struct {
+	Severity string
+	Count    int
+}
+
+type vulnerability struct {
+	Target           string
+	ID               string
+	URL              string
+	Severity         string
+	Status           string
+	PkgName          string
+	InstalledVersion string
+	FixedVersion     string
+	Title            string
+	Layer            string
+}
+
+type misconfiguration struct {
+	Target     string
+	ID         string
+	URL        string
+	Severity   string
+	Status     string
+	Title      string
+	Message    string
+	Resolution string
+}
+
+type secret struct {
+	Target   string
+	RuleID   string
+	Severity string
+	Category string
+	Title    string
+	Line     int
+}
+
+type license struct {
+	Target   string
+	Name     string
+	Severity string
+	Category string
+	PkgName  string
+	FilePath string
+}
+
+func (w Writer) Write(_ context.Context, report types.Report) error {
+	p := page{
+		ArtifactName: report.ArtifactName,
+		ArtifactType: string(report.ArtifactType),
+		Version:      w.Version,
+		HasLayers:    len(report.Metadata.DiffIDs) > 0,
+	}
+	if !report.CreatedAt.IsZero() {
+		p.CreatedAt = report.CreatedAt.UTC().Format(time.RFC3339)
+	}
+
+	layerTitles := make(map[string]string)
+	if p.HasLayers {
+		for _, l := range layers.NewLayers(report) {
+			layerTitles[l.DiffID] = l.Title()
+		}
+	}
+
+	counts := make(map[string]int)
+	statuses := make(map[string]struct{})
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			counts[v.Severity]++
+			statuses[v.Status.String()] = struct{}{}
+			p.Vulnerabilities = append(p.Vulnerabilities, vulnerability{
+				Target:           result.Target,
+				ID:               v.VulnerabilityID,
+				URL:              v.PrimaryURL,
+				Severity:         v.Severity,
+				Status:           v.Status.String(),
+				PkgName:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Title:            v.Title,
+				Layer:            layerTitles[v.Layer.DiffID],
+			})
+		}
+		for _, m := range result.Misconfigurations {
+			counts[m.Severity]++
+			p.Misconfigurations = append(p.Misconfigurations, misconfiguration{
+				Target:     result.Target,
+				ID:         m.AVDID,
+				URL:        m.PrimaryURL,
+				Severity:   m.Severity,
+				Status:     string(m.Status),
+				Title:      m.Title,
+				Message:    m.Message,
+				Resolution: m.Resolution,
+			})
+		}
+		for _, s := range result.Secrets {
+			counts[s.Severity]++
+			p.Secrets = append(p.Secrets, secret{
+				Target:   result.Target,
+				RuleID:   s.RuleID,
+				Severity: s.Severity,
+				Category: string(s.Category),
+				Title:    s.Title,
+				Line:     s.StartLine,
+			})
+		}
+		for _, l := range result.Licenses {
+			counts[l.Severity]++
+			p.Licenses = append(p.Licenses, license{
+				Target:   result.Target,
+				Name:     l.Name,
+				Severity: l.Severity,
+				Category: string(l.Category),
+				PkgName:  l.PkgName,
+				FilePath: l.FilePath,
+			})
+		}
+	}
+
+	for i := len(dbTypes.SeverityNames) - 1; i >= 0; i-- {
+		severity := dbTypes.SeverityNames[i]
+		p.Severities = append(p.Severities, severityCount{
+			Severity: severity,
+			Count:    counts[severity],
+		})
+	}
+	p.Statuses = lo.Keys(statuses)
+	sort.Strings(p.Statuses)
+
+	// The most severe first
+	sort.SliceStable(p.Vulnerabilities, func(i, j int) bool {
+		return dbTypes.CompareSeverityString(p.Vulnerabilities[j].Severity, p.Vulnerabilities[i].Severity) > 0
+	})
+	sort.SliceStable(p.Misconfigurations, func(i, j int) bool {
+		return dbTypes.CompareSeverityString(p.Misconfigurations[j].Severity, p.Misconfigurations[i].Severity) > 0
+	})
+
+	if err := tmpl.Execute(w.Output, p); err != nil {
+		return xerrors.Errorf("failed to write HTML: %w", err)
+	}
+	return nil
+}
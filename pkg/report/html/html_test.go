@@ -0,0 +1,153 @@
+package html_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/html"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  types.Report
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "image with layers",
+			report: types.Report{
+				ArtifactName: "alpine:3.19",
+				ArtifactType: ftypes.ArtifactContainerImage,
+				CreatedAt:    time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC),
+				Metadata: types.Metadata{
+					DiffIDs: []string{"sha256:aaa", "sha256:bbb"},
+					ImageConfig: v1.ConfigFile{
+						History: []v1.History{
+							{CreatedBy: "/bin/sh -c #(nop) ADD file:37a76ec18f98 in / "},
+							{CreatedBy: "/bin/sh -c apk add --no-cache curl"},
+						},
+					},
+				},
+				Results: types.Results{
+					{
+						Target: "alpine:3.19 (alpine 3.19.0)",
+						Class:  types.ClassOSPkg,
+						Type:   ftypes.Alpine,
+						Vulnerabilities: []types.DetectedVulnerability{
+							{
+								VulnerabilityID:  "CVE-2023-42363",
+								PkgName:          "busybox",
+								InstalledVersion: "1.36.1-r15",
+								Status:           dbTypes.StatusAffected,
+								Layer:            ftypes.Layer{DiffID: "sha256:aaa"},
+								Vulnerability: dbTypes.Vulnerability{
+									Severity: "LOW",
+								},
+							},
+							{
+								VulnerabilityID:  "CVE-2023-38545",
+								PkgName:          "curl",
+								InstalledVersion: "8.2.1-r0",
+								FixedVersion:     "8.4.0-r0",
+								Status:           dbTypes.StatusFixed,
+								PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2023-38545",
+								Layer:            ftypes.Layer{DiffID: "sha256:bbb"},
+								Vulnerability: dbTypes.Vulnerability{
+									Title:    "curl: heap based buffer overflow in the <SOCKS5> proxy handshake",
+									Severity: "CRITICAL",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				"<title>Trivy Report - alpine:3.19</title>",
+				"scanned at 2024-02-01T10:00:00Z",
+				`<div class="CRITICAL"><strong>1</strong>CRITICAL</div>`,
+				`<div class="LOW"><strong>1</strong>LOW</div>`,
+				`<option value="affected">affected</option>`,
+				`<option value="fixed">fixed</option>`,
+				`<tr data-severity="CRITICAL" data-status="fixed" data-package="curl">`,
+				`<a href="https://avd.aquasec.com/nvd/cve-2023-38545">CVE-2023-38545</a>`,
+				// The title is escaped
+				"curl: heap based buffer overflow in the &lt;SOCKS5&gt; proxy handshake",
+				"<th>Layer</th>",
+				"<td>Layer 1: ADD file:37a76ec18f98 in /</td>",
+				"<td>Layer 2: RUN apk add --no-cache curl</td>",
+			},
+		},
+		{
+			name: "filesystem with misconfigurations",
+			report: types.Report{
+				ArtifactName: "./app",
+				ArtifactType: ftypes.ArtifactFilesystem,
+				Results: types.Results{
+					{
+						Target: "Dockerfile",
+						Class:  types.ClassConfig,
+						Misconfigurations: []types.DetectedMisconfiguration{
+							{
+								AVDID:    "AVD-DS-0002",
+								Title:    "Image user should not be 'root'",
+								Severity: "HIGH",
+								Status:   types.MisconfStatusFailure,
+							},
+						},
+					},
+				},
+			},
+			want: []string{
+				"<h2>Misconfigurations",
+				`<tr data-severity="HIGH">`,
+				"<td>AVD-DS-0002</td>",
+			},
+			notWant: []string{
+				"<th>Layer</th>",
+				`id="status-filter"`,
+				"<h2>Vulnerabilities",
+			},
+		},
+		{
+			name: "no issues",
+			report: types.Report{
+				ArtifactName: "./app",
+				ArtifactType: ftypes.ArtifactFilesystem,
+			},
+			want: []string{
+				"No issues detected",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			w := html.Writer{
+				Output:  out,
+				Version: "dev",
+			}
+			require.NoError(t, w.Write(context.Background(), tt.report))
+
+			got := out.String()
+			for _, want := range tt.want {
+				assert.Contains(t, got, want)
+			}
+			for _, notWant := range tt.notWant {
+				assert.NotContains(t, got, notWant)
+			}
+			// Self-contained
+			assert.NotContains(t, got, `src="http`)
+			assert.NotContains(t, got, `<link`)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="generator" content="Trivy {{ .Version }}">
+<title>Trivy Report - {{ .ArtifactName }}</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+  h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+  h2 { font-size: 1.2em; margin-top: 2em; }
+  .meta { color: #656d76; margin-bottom: 1.5em; }
+  .summary { display: flex; gap: 0.8em; flex-wrap: wrap; margin-bottom: 1.5em; }
+  .summary div { padding: 0.6em 1em; border-radius: 6px; color: #fff; min-width: 6em; text-align: center; }
+  .summary strong { display: block; font-size: 1.6em; }
+  .CRITICAL { background: #8b0000; }
+  .HIGH { background: #d1242f; }
+  .MEDIUM { background: #bf8700; }
+  .LOW { background: #0969da; }
+  .UNKNOWN { background: #6e7781; }
+  .filters { display: flex; gap: 1.5em; flex-wrap: wrap; align-items: center; padding: 0.8em; background: #f6f8fa; border-radius: 6px; }
+  .filters label { margin-right: 0.6em; white-space: nowrap; }
+  table { border-collapse: collapse; width: 100%; margin-top: 0.5em; font-size: 0.9em; }
+  th, td { border: 1px solid #d0d7de; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+  th { background: #f6f8fa; }
+  td.severity { color: #fff; font-weight: bold; }
+  tr.hidden { display: none; }
+  .count { color: #656d76; font-weight: normal; font-size: 0.8em; }
+  .none { color: #656d76; }
+  @media print {
+    .filters { display: none; }
+    tr.hidden { display: table-row; }
+  }
+</style>
+</head>
+<body>
+<h1>{{ .ArtifactName }}</h1>
+<div class="meta">
+  {{- if .ArtifactType }}{{ .ArtifactType }}{{ end }}
+  {{- if .CreatedAt }} &middot; scanned at {{ .CreatedAt }}{{ end }}
+  {{- if .Version }} &middot; Trivy {{ .Version }}{{ end }}
+</div>
+
+<div class="summary">
+{{- range .Severities }}
+  <div class="{{ .Severity }}"><strong>{{ .Count }}</strong>{{ .Severity }}</div>
+{{- end }}
+</div>
+
+<div class="filters">
+  <div>
+  {{- range .Severities }}
+    <label><input type="checkbox" class="severity-filter" value="{{ .Severity }}" checked> {{ .Severity }}</label>
+  {{- end }}
+  </div>
+  {{- if .Statuses }}
+  <label>Status
+    <select id="status-filter">
+      <option value="">all</option>
+      {{- range .Statuses }}
+      <option value="{{ . }}">{{ . }}</option>
+      {{- end }}
+    </select>
+  </label>
+  {{- end }}
+  <label>Package <input type="search" id="package-filter" placeholder="Search packages"></label>
+</div>
+
+{{- if .Vulnerabilities }}
+<h2>Vulnerabilities <span class="count" data-count-for="vulnerabilities"></span></h2>
+<table id="vulnerabilities">
+  <thead>
+    <tr>
+      <th>Severity</th><th>ID</th><th>Package</th><th>Installed</th><th>Fixed</th><th>Status</th><th>Title</th><th>Target</th>
+      {{- if .HasLayers }}<th>Layer</th>{{ end }}
+    </tr>
+  </thead>
+  <tbody>
+  {{- range .Vulnerabilities }}
+    <tr data-severity="{{ .Severity }}" data-status="{{ .Status }}" data-package="{{ lower .PkgName }}">
+      <td class="severity {{ .Severity }}">{{ .Severity }}</td>
+      <td>{{ if .URL }}<a href="{{ .URL }}">{{ .ID }}</a>{{ else }}{{ .ID }}{{ end }}</td>
+      <td>{{ .PkgName }}</td>
+      <td>{{ .InstalledVersion }}</td>
+      <td>{{ .FixedVersion }}</td>
+      <td>{{ .Status }}</td>
+      <td>{{ .Title }}</td>
+      <td>{{ .Target }}</td>
+      {{- if $.HasLayers }}<td>{{ .Layer }}</td>{{ end }}
+    </tr>
+  {{- end }}
+  </tbody>
+</table>
+{{- end }}
+
+{{- if .Misconfigurations }}
+<h2>Misconfigurations <span class="count" data-count-for="misconfigurations"></span></h2>
+<table id="misconfigurations">
+  <thead>
+    <tr><th>Severity</th><th>ID</th><th>Status</th><th>Title</th><th>Message</th><th>Resolution</th><th>Target</th></tr>
+  </thead>
+  <tbody>
+  {{- range .Misconfigurations }}
+    <tr data-severity="{{ .Severity }}">
+      <td class="severity {{ .Severity }}">{{ .Severity }}</td>
+      <td>{{ if .URL }}<a href="{{ .URL }}">{{ .ID }}</a>{{ else }}{{ .ID }}{{ end }}</td>
+      <td>{{ .Status }}</td>
+      <td>{{ .Title }}</td>
+      <td>{{ .Message }}</td>
+      <td>{{ .Resolution }}</td>
+      <td>{{ .Target }}</td>
+    </tr>
+  {{- end }}
+  </tbody>
+</table>
+{{- end }}
+
+{{- if .Secrets }}
+<h2>Secrets <span class="count" data-count-for="secrets"></span></h2>
+<table id="secrets">
+  <thead>
+    <tr><th>Severity</th><th>Rule</th><th>Category</th><th>Title</th><th>Target</th><th>Line</th></tr>
+  </thead>
+  <tbody>
+  {{- range .Secrets }}
+    <tr data-severity="{{ .Severity }}">
+      <td class="severity {{ .Severity }}">{{ .Severity }}</td>
+      <td>{{ .RuleID }}</td>
+      <td>{{ .Category }}</td>
+      <td>{{ .Title }}</td>
+      <td>{{ .Target }}</td>
+      <td>{{ .Line }}</td>
+    </tr>
+  {{- end }}
+  </tbody>
+</table>
+{{- end }}
+
+{{- if .Licenses }}
+<h2>Licenses <span class="count" data-count-for="licenses"></span></h2>
+<table id="licenses">
+  <thead>
+    <tr><th>Severity</th><th>License</th><th>Category</th><th>Package</th><th>Path</th><th>Target</th></tr>
+  </thead>
+  <tbody>
+  {{- range .Licenses }}
+    <tr data-severity="{{ .Severity }}" data-package="{{ lower .PkgName }}">
+      <td class="severity {{ .Severity }}">{{ .Severity }}</td>
+      <td>{{ .Name }}</td>
+      <td>{{ .Category }}</td>
+      <td>{{ .PkgName }}</td>
+      <td>{{ .FilePath }}</td>
+      <td>{{ .Target }}</td>
+    </tr>
+  {{- end }}
+  </tbody>
+</table>
+{{- end }}
+
+{{- if not (or .Vulnerabilities .Misconfigurations .Secrets .Licenses) }}
+<p class="none">No issues detected</p>
+{{- end }}
+
+<script>
+(function () {
+  var severities = document.querySelectorAll(".severity-filter");
+  var status = document.getElementById("status-filter");
+  var search = document.getElementById("package-filter");
+
+  function apply() {
+    var checked = {};
+    severities.forEach(function (s) { checked[s.value] = s.checked; });
+    var wantStatus = status ? status.value : "";
+    var query = search.value.trim().toLowerCase();
+
+    document.querySelectorAll("table").forEach(function (table) {
+      var rows = table.querySelectorAll("tbody tr");
+      var shown = 0;
+      rows.forEach(function (row) {
+        var visible = checked[row.dataset.severity] !== false;
+        // Rows without status or package, e.g. misconfigurations, are filtered only by severity
+        if (visible && wantStatus && row.dataset.status !== undefined) {
+          visible = row.dataset.status === wantStatus;
+        }
+        if (visible && query && row.dataset.package !== undefined) {
+          visible = row.dataset.package.indexOf(query) !== -1;
+        }
+        row.classList.toggle("hidden", !visible);
+        if (visible) shown++;
+      });
+      var count = document.querySelector('[data-count-for="' + table.id + '"]');
+      if (count) count.textContent = "(" + shown + " of " + rows.length + ")";
+    });
+  }
+
+  severities.forEach(function (s) { s.addEventListener("change", apply); });
+  if (status) status.addEventListener("change", apply);
+  search.addEventListener("input", apply);
+  apply();
+})();
+</script>
+</body>
+</html>
@@ -0,0 +1,184 @@
+package installed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// ecosystem defines how the installed packages of an ecosystem are written as a manifest
+type ecosystem struct {
+	name string
+	// installedType is the type of results detected from installed packages, e.g. site-packages
+	installedType ftypes.LangType
+	// lockfileTypes are the types of results detected from lockfiles.
+	// Manifests are not generated if the artifact has one of them.
+	lockfileTypes []ftypes.LangType
+	fileName      string
+	marshal       func(pkgs []ftypes.Package) ([]byte, error)
+}
+
+var ecosystems = []ecosystem{
+	{
+		name:          "Python",
+		installedType: ftypes.PythonPkg,
+		lockfileTypes: []ftypes.LangType{ftypes.Pip, ftypes.Pipenv, ftypes.Poetry},
+		fileName:      "requirements.txt",
+		marshal:       marshalRequirements,
+	},
+	{
+		name:          "Node.js",
+		installedType: ftypes.NodePkg,
+		lockfileTypes: []ftypes.LangType{ftypes.Npm, ftypes.Yarn, ftypes.Pnpm},
+		fileName:      "package.json",
+		marshal:       marshalPackageJSON,
+	},
+	{
+		name:          "Ruby",
+		installedType: ftypes.GemSpec,
+		lockfileTypes: []ftypes.LangType{ftypes.Bundler},
+		fileName:      "Gemfile",
+		marshal:       marshalGemfile,
+	},
+}
+
+// Write writes the manifests of the packages installed without lockfiles into the directory,
+// so that the installed state can be rebuilt or verified with the package managers.
+// It returns the paths of the written manifests.
+func Write(dir string, report types.Report) ([]string, error) {
+	var written []string
+	for _, eco := range ecosystems {
+		var installed []ftypes.Package
+		var hasLockfile bool
+		for _, result := range report.Results {
+			if result.Class != types.ClassLangPkg {
+				continue
+			}
+			switch {
+			case result.Type == eco.installedType:
+				installed = append(installed, result.Packages...)
+			case slices.Contains(eco.lockfileTypes, result.Type):
+				hasLockfile = true
+			}
+		}
+
+		if len(installed) == 0 {
+			continue
+		} else if hasLockfile {
+			log.Logger.Debugf("Skip generating %s as %s lockfiles are found", eco.fileName, eco.name)
+			continue
+		}
+
+		b, err := eco.marshal(uniquePackages(installed))
+		if err != nil {
+			return nil, xerrors.Errorf("%s marshal error: %w", eco.fileName, err)
+		}
+
+		if err = os.MkdirAll(dir, 0o755); err != nil {
+			return nil, xerrors.Errorf("mkdir error: %w", err)
+		}
+		filePath := filepath.Join(dir, eco.fileName)
+		if err = os.WriteFile(filePath, b, 0o644); err != nil {
+			return nil, xerrors.Errorf("write error: %w", err)
+		}
+		log.Logger.Infof("%s packages installed without lockfiles have been written to %s", eco.name, filePath)
+		written = append(written, filePath)
+	}
+	return written, nil
+}
+
+// uniquePackages returns one version for each package, sorted by name.
+// If different versions of a package are installed, e.g. in nested node_modules or multiple virtualenvs,
+// the version in the shallowest path is used as it is the one resolved from the top level.
+func uniquePackages(pkgs []ftypes.Package) []ftypes.Package {
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		return depth(pkgs[i].FilePath) < depth(pkgs[j].FilePath)
+	})
+
+	seen := make(map[string]ftypes.Package)
+	var uniq []ftypes.Package
+	for _, pkg := range pkgs {
+		if s, ok := seen[pkg.Name]; ok {
+			if s.Version != pkg.Version {
+				log.Logger.Warnf("Multiple versions of %s are installed, %s is used instead of %s (%s)",
+					pkg.Name, s.Version, pkg.Version, pkg.FilePath)
+			}
+			continue
+		}
+		seen[pkg.Name] = pkg
+		uniq = append(uniq, pkg)
+	}
+
+	sort.Slice(uniq, func(i, j int) bool {
+		return uniq[i].Name < uniq[j].Name
+	})
+	return uniq
+}
+
+func depth(filePath string) int {
+	return strings.Count(filepath.ToSlash(filePath), "/")
+}
+
+const header = "Generated by Trivy from the installed packages"
+
+var pythonNameReplacer = regexp.MustCompile(`[-_.]+`)
+
+// marshalRequirements writes requirements.txt with the normalized names
+// cf. https://packaging.python.org/en/latest/specifications/name-normalization/
+func marshalRequirements(pkgs []ftypes.Package) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n", header)
+	for _, pkg := range pkgs {
+		name := pythonNameReplacer.ReplaceAllString(strings.ToLower(pkg.Name), "-")
+		fmt.Fprintf(&b, "%s==%s\n", name, pkg.Version)
+	}
+	return b.Bytes(), nil
+}
+
+type packageJSON struct {
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Private      bool              `json:"private"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// marshalPackageJSON writes package.json with the exact versions
+func marshalPackageJSON(pkgs []ftypes.Package) ([]byte, error) {
+	p := packageJSON{
+		Name:         "installed-packages",
+		Description:  header,
+		Private:      true,
+		Dependencies: make(map[string]string),
+	}
+	for _, pkg := range pkgs {
+		p.Dependencies[pkg.Name] = pkg.Version
+	}
+
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// marshalGemfile writes Gemfile with the exact versions
+func marshalGemfile(pkgs []ftypes.Package) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\nsource \"https://rubygems.org\"\n\n", header)
+	for _, pkg := range pkgs {
+		fmt.Fprintf(&b, "gem %q, %q\n", pkg.Name, "= "+pkg.Version)
+	}
+	return b.Bytes(), nil
+}
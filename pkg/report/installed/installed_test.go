@@ -0,0 +1,166 @@
+package installed_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/installed"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		report types.Report
+		want   map[string]string
+	}{
+		{
+			name: "installed packages",
+			report: types.Report{
+				Results: types.Results{
+					{
+						Target: "Python",
+						Class:  types.ClassLangPkg,
+						Type:   ftypes.PythonPkg,
+						Packages: []ftypes.Package{
+							{
+								Name:     "requests",
+								Version:  "2.31.0",
+								FilePath: "usr/lib/python3.11/site-packages/requests-2.31.0.dist-info/METADATA",
+							},
+							{
+								Name:     "Flask_SQLAlchemy",
+								Version:  "3.1.1",
+								FilePath: "usr/lib/python3.11/site-packages/Flask_SQLAlchemy-3.1.1.dist-info/METADATA",
+							},
+						},
+					},
+					{
+						Target: "Node.js",
+						Class:  types.ClassLangPkg,
+						Type:   ftypes.NodePkg,
+						Packages: []ftypes.Package{
+							{
+								Name:     "lodash",
+								Version:  "4.17.20",
+								FilePath: "app/node_modules/express/node_modules/lodash/package.json",
+							},
+							{
+								Name:     "lodash",
+								Version:  "4.17.21",
+								FilePath: "app/node_modules/lodash/package.json",
+							},
+							{
+								Name:     "express",
+								Version:  "4.18.2",
+								FilePath: "app/node_modules/express/package.json",
+							},
+						},
+					},
+					{
+						Target: "Ruby",
+						Class:  types.ClassLangPkg,
+						Type:   ftypes.GemSpec,
+						Packages: []ftypes.Package{
+							{
+								Name:     "rake",
+								Version:  "13.0.6",
+								FilePath: "usr/lib/ruby/gems/3.1.0/specifications/rake-13.0.6.gemspec",
+							},
+						},
+					},
+				},
+			},
+			want: map[string]string{
+				"requirements.txt": `# Generated by Trivy from the installed packages
+flask-sqlalchemy==3.1.1
+requests==2.31.0
+`,
+				"package.json": `{
+  "name": "installed-packages",
+  "description": "Generated by Trivy from the installed packages",
+  "private": true,
+  "dependencies": {
+    "express": "4.18.2",
+    "lodash": "4.17.21"
+  }
+}
+`,
+				"Gemfile": `# Generated by Trivy from the installed packages
+source "https://rubygems.org"
+
+gem "rake", "= 13.0.6"
+`,
+			},
+		},
+		{
+			name: "lockfile exists",
+			report: types.Report{
+				Results: types.Results{
+					{
+						Target: "app/package-lock.json",
+						Class:  types.ClassLangPkg,
+						Type:   ftypes.Npm,
+						Packages: []ftypes.Package{
+							{
+								Name:    "lodash",
+								Version: "4.17.21",
+							},
+						},
+					},
+					{
+						Target: "Node.js",
+						Class:  types.ClassLangPkg,
+						Type:   ftypes.NodePkg,
+						Packages: []ftypes.Package{
+							{
+								Name:     "lodash",
+								Version:  "4.17.21",
+								FilePath: "app/node_modules/lodash/package.json",
+							},
+						},
+					},
+				},
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "OS packages only",
+			report: types.Report{
+				Results: types.Results{
+					{
+						Target: "alpine:3.19 (alpine 3.19.1)",
+						Class:  types.ClassOSPkg,
+						Type:   ftypes.Alpine,
+						Packages: []ftypes.Package{
+							{
+								Name:    "musl",
+								Version: "1.2.4-r2",
+							},
+						},
+					},
+				},
+			},
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "manifests")
+			got, err := installed.Write(dir, tt.report)
+			require.NoError(t, err)
+			assert.Len(t, got, len(tt.want))
+
+			for fileName, want := range tt.want {
+				b, err := os.ReadFile(filepath.Join(dir, fileName))
+				require.NoError(t, err)
+				assert.Equal(t, want, string(b), fileName)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/samber/lo"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/types"
@@ -13,11 +14,20 @@ import (
 
 // JSONWriter implements result Writer
 type JSONWriter struct {
-	Output io.Writer
+	Output         io.Writer
+	ShowSuppressed bool
 }
 
 // Write writes the results in JSON format
 func (jw JSONWriter) Write(ctx context.Context, report types.Report) error {
+	if !jw.ShowSuppressed {
+		// Copy results so that the suppressed findings are still available for other writers
+		report.Results = lo.Map(report.Results, func(r types.Result, _ int) types.Result {
+			r.ModifiedFindings = nil
+			return r
+		})
+	}
+
 	output, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
 		return xerrors.Errorf("failed to marshal json: %w", err)
@@ -5,8 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
@@ -97,3 +99,81 @@ func TestReportWriter_JSON(t *testing.T) {
 		})
 	}
 }
+
+func TestReportWriter_JSONShowSuppressed(t *testing.T) {
+	expiredAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	modified := []types.ModifiedFinding{
+		{
+			Type:      types.FindingTypeVulnerability,
+			Status:    types.FindingStatusIgnored,
+			Statement: "Not exploitable",
+			Source:    ".trivyignore.yaml",
+			Owner:     "security-team",
+			ExpiredAt: &expiredAt,
+			Finding: types.DetectedVulnerability{
+				VulnerabilityID:  "CVE-2020-0001",
+				PkgName:          "foo",
+				InstalledVersion: "1.2.3",
+			},
+		},
+		{
+			Type:      types.FindingTypeSecret,
+			Status:    types.FindingStatusIgnored,
+			Statement: "Test fixture",
+			Source:    ".trivyignore.yaml",
+			Finding: types.DetectedSecret{
+				RuleID:   "aws-access-key-id",
+				Category: "AWS",
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		showSuppressed bool
+		want           []types.ModifiedFinding
+	}{
+		{
+			name:           "show suppressed",
+			showSuppressed: true,
+			want:           modified,
+		},
+		{
+			name:           "hide suppressed",
+			showSuppressed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := types.Report{
+				SchemaVersion: 2,
+				ArtifactName:  "alpine:3.14",
+				Results: types.Results{
+					{
+						Target:           "foojson",
+						ModifiedFindings: modified,
+					},
+				},
+			}
+
+			output := bytes.NewBuffer(nil)
+			jw := report.JSONWriter{
+				Output:         output,
+				ShowSuppressed: tt.showSuppressed,
+			}
+			err := jw.Write(context.Background(), input)
+			require.NoError(t, err)
+
+			var got types.Report
+			err = json.Unmarshal(output.Bytes(), &got)
+			require.NoError(t, err)
+
+			require.Len(t, got.Results, 1)
+			assert.Equal(t, tt.want, got.Results[0].ModifiedFindings)
+
+			// The input report must not be modified
+			assert.Equal(t, modified, input.Results[0].ModifiedFindings)
+		})
+	}
+}
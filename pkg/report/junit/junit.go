@@ -0,0 +1,279 @@
+package junit
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// TestCase is the unit of the JUnit test cases
+type TestCase string
+
+const (
+	// TestCaseFinding makes each finding a failed test case, and each target a test suite
+	TestCaseFinding TestCase = "finding"
+	// TestCaseTarget makes each target a test case, which fails if the target has findings
+	TestCaseTarget TestCase = "target"
+)
+
+type testSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Suites   []testSuite `xml:"testsuite"`
+}
+
+type testSuite struct {
+	Name       string      `xml:"name,attr"`
+	Tests      int         `xml:"tests,attr"`
+	Failures   int         `xml:"failures,attr"`
+	Errors     int         `xml:"errors,attr"`
+	Skipped    int         `xml:"skipped,attr"`
+	Properties *properties `xml:"properties,omitempty"`
+	Cases      []testCase  `xml:"testcase"`
+}
+
+type properties struct {
+	Properties []property `xml:"property"`
+}
+
+type property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type testCase struct {
+	ClassName string   `xml:"classname,attr"`
+	Name      string   `xml:"name,attr"`
+	Failure   *failure `xml:"failure,omitempty"`
+	Skipped   *skipped `xml:"skipped,omitempty"`
+}
+
+type failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"` // CDATA keeps the line breaks readable
+}
+
+type skipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// Writer writes the report as JUnit XML so that CI systems only understanding test results can fail builds on findings
+type Writer struct {
+	Output   io.Writer
+	TestCase TestCase
+}
+
+func (w Writer) Write(_ context.Context, report types.Report) error {
+	suites := testSuites{Name: "trivy"}
+	switch w.TestCase {
+	case TestCaseTarget:
+		suite := testSuite{Name: report.ArtifactName}
+		for _, result := range report.Results {
+			suite.Cases = append(suite.Cases, targetCase(result))
+		}
+		suites.Suites = append(suites.Suites, suite)
+	case TestCaseFinding, "":
+		for _, result := range report.Results {
+			suites.Suites = append(suites.Suites, findingSuite(result))
+		}
+	default:
+		return xerrors.Errorf("unknown JUnit test case: %s", w.TestCase)
+	}
+
+	for i := range suites.Suites {
+		suite := &suites.Suites[i]
+		for _, c := range suite.Cases {
+			suite.Tests++
+			if c.Failure != nil {
+				suite.Failures++
+			} else if c.Skipped != nil {
+				suite.Skipped++
+			}
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+	}
+
+	b, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("failed to marshal JUnit XML: %w", err)
+	}
+	if _, err = fmt.Fprintf(w.Output, "%s%s\n", xml.Header, b); err != nil {
+		return xerrors.Errorf("failed to write JUnit XML: %w", err)
+	}
+	return nil
+}
+
+// findingSuite returns the test suite of the target with a test case per finding.
+// Passed misconfigurations are passed test cases and exceptions are skipped ones.
+func findingSuite(result types.Result) testSuite {
+	suite := testSuite{
+		Name: result.Target,
+		Properties: &properties{
+			Properties: []property{
+				{Name: "type", Value: string(result.Type)},
+				{Name: "class", Value: string(result.Class)},
+			},
+		},
+	}
+
+	for _, vuln := range result.Vulnerabilities {
+		suite.Cases = append(suite.Cases, testCase{
+			ClassName: result.Target,
+			Name:      fmt.Sprintf("[%s] %s: %s@%s", vuln.Severity, vuln.VulnerabilityID, vuln.PkgName, vuln.InstalledVersion),
+			Failure: &failure{
+				Message: vulnerabilityMessage(vuln),
+				Type:    vuln.Severity,
+				Text:    vulnerabilityDetails(vuln),
+			},
+		})
+	}
+	for _, misconf := range result.Misconfigurations {
+		c := testCase{
+			ClassName: result.Target,
+			Name:      fmt.Sprintf("[%s] %s: %s", misconf.Severity, misconf.AVDID, misconf.Title),
+		}
+		switch misconf.Status {
+		case types.MisconfStatusFailure:
+			c.Failure = &failure{
+				Message: misconf.Message,
+				Type:    misconf.Severity,
+				Text:    misconfigurationDetails(misconf),
+			}
+		case types.MisconfStatusException:
+			c.Skipped = &skipped{Message: "exception"}
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	for _, secret := range result.Secrets {
+		suite.Cases = append(suite.Cases, testCase{
+			ClassName: result.Target,
+			Name:      fmt.Sprintf("[%s] %s: line %d", secret.Severity, secret.RuleID, secret.StartLine),
+			Failure: &failure{
+				Message: secret.Title,
+				Type:    secret.Severity,
+				Text:    details("Match", secret.Match),
+			},
+		})
+	}
+	for _, license := range result.Licenses {
+		suite.Cases = append(suite.Cases, testCase{
+			ClassName: result.Target,
+			Name:      fmt.Sprintf("[%s] %s: %s", license.Severity, license.Name, license.PkgName),
+			Failure: &failure{
+				Message: fmt.Sprintf("%s is %s", license.Name, license.Category),
+				Type:    license.Severity,
+				Text:    details("Package", license.PkgName, "Path", license.FilePath, "Link", license.Link),
+			},
+		})
+	}
+	return suite
+}
+
+// targetCase returns the test case of the target, which fails with the list of findings
+func targetCase(result types.Result) testCase {
+	c := testCase{
+		ClassName: string(result.Class),
+		Name:      result.Target,
+	}
+
+	var lines []string
+	var counts []string
+	count := func(n int, kind string) {
+		if n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, kind))
+		}
+	}
+	for _, vuln := range result.Vulnerabilities {
+		lines = append(lines, fmt.Sprintf("[%s] %s: %s", vuln.Severity, vuln.VulnerabilityID, vulnerabilityMessage(vuln)))
+	}
+	count(len(result.Vulnerabilities), "vulnerabilities")
+
+	var failed int
+	for _, misconf := range result.Misconfigurations {
+		if misconf.Status != types.MisconfStatusFailure {
+			continue
+		}
+		failed++
+		lines = append(lines, fmt.Sprintf("[%s] %s: %s", misconf.Severity, misconf.AVDID, misconf.Message))
+	}
+	count(failed, "misconfigurations")
+
+	for _, secret := range result.Secrets {
+		lines = append(lines, fmt.Sprintf("[%s] %s: line %d", secret.Severity, secret.RuleID, secret.StartLine))
+	}
+	count(len(result.Secrets), "secrets")
+
+	for _, license := range result.Licenses {
+		lines = append(lines, fmt.Sprintf("[%s] %s: %s", license.Severity, license.Name, license.PkgName))
+	}
+	count(len(result.Licenses), "licenses")
+
+	if len(lines) > 0 {
+		c.Failure = &failure{
+			Message: strings.Join(counts, ", "),
+			Type:    "findings",
+			Text:    strings.Join(lines, "\n"),
+		}
+	}
+	return c
+}
+
+func vulnerabilityMessage(vuln types.DetectedVulnerability) string {
+	msg := fmt.Sprintf("%s %s", vuln.PkgName, vuln.InstalledVersion)
+	if vuln.FixedVersion != "" {
+		msg += fmt.Sprintf(" (fixed in %s)", vuln.FixedVersion)
+	}
+	if vuln.Title != "" {
+		msg += ": " + vuln.Title
+	}
+	return msg
+}
+
+func vulnerabilityDetails(vuln types.DetectedVulnerability) string {
+	return details(
+		"Package", vuln.PkgName,
+		"Installed version", vuln.InstalledVersion,
+		"Fixed version", vuln.FixedVersion,
+		"Status", vuln.Status.String(),
+		"Link", vuln.PrimaryURL,
+		"Description", vuln.Description,
+	)
+}
+
+func misconfigurationDetails(misconf types.DetectedMisconfiguration) string {
+	var lines string
+	if misconf.CauseMetadata.StartLine > 0 {
+		lines = fmt.Sprintf("%d-%d", misconf.CauseMetadata.StartLine, misconf.CauseMetadata.EndLine)
+	}
+	return details(
+		"Resource", misconf.CauseMetadata.Resource,
+		"Lines", lines,
+		"Resolution", misconf.Resolution,
+		"Link", misconf.PrimaryURL,
+		"Description", misconf.Description,
+	)
+}
+
+// details returns the key/value pairs as lines, skipping empty values
+func details(kvs ...string) string {
+	var lines []string
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if kvs[i+1] == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", kvs[i], kvs[i+1]))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,159 @@
+package junit_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/junit"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var report = types.Report{
+	ArtifactName: "./app",
+	ArtifactType: ftypes.ArtifactFilesystem,
+	Results: types.Results{
+		{
+			Target: "package-lock.json",
+			Class:  types.ClassLangPkg,
+			Type:   ftypes.Npm,
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2021-23337",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.4",
+					FixedVersion:     "4.17.21",
+					Status:           dbTypes.StatusFixed,
+					PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2021-23337",
+					Vulnerability: dbTypes.Vulnerability{
+						Title:       "nodejs-lodash: command injection via template",
+						Description: "Lodash versions prior to 4.17.21 are vulnerable to <Command Injection>",
+						Severity:    "HIGH",
+					},
+				},
+			},
+		},
+		{
+			Target: "Dockerfile",
+			Class:  types.ClassConfig,
+			Type:   "dockerfile",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				{
+					AVDID:    "AVD-DS-0002",
+					Title:    "Image user should not be 'root'",
+					Message:  "Specify at least 1 USER command in Dockerfile with non-root user as argument",
+					Severity: "HIGH",
+					Status:   types.MisconfStatusFailure,
+				},
+				{
+					AVDID:    "AVD-DS-0001",
+					Title:    "':latest' tag used",
+					Severity: "MEDIUM",
+					Status:   types.MisconfStatusPassed,
+				},
+				{
+					AVDID:    "AVD-DS-0026",
+					Title:    "No HEALTHCHECK defined",
+					Severity: "LOW",
+					Status:   types.MisconfStatusException,
+				},
+			},
+		},
+		{
+			Target: "go.mod",
+			Class:  types.ClassLangPkg,
+			Type:   ftypes.GoModule,
+		},
+	},
+}
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name     string
+		testCase junit.TestCase
+		want     string
+	}{
+		{
+			name:     "test case per finding",
+			testCase: junit.TestCaseFinding,
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="trivy" tests="4" failures="2" skipped="1">
+  <testsuite name="package-lock.json" tests="1" failures="1" errors="0" skipped="0">
+    <properties>
+      <property name="type" value="npm"></property>
+      <property name="class" value="lang-pkgs"></property>
+    </properties>
+    <testcase classname="package-lock.json" name="[HIGH] CVE-2021-23337: lodash@4.17.4">
+      <failure message="lodash 4.17.4 (fixed in 4.17.21): nodejs-lodash: command injection via template" type="HIGH"><![CDATA[Package: lodash
+Installed version: 4.17.4
+Fixed version: 4.17.21
+Status: fixed
+Link: https://avd.aquasec.com/nvd/cve-2021-23337
+Description: Lodash versions prior to 4.17.21 are vulnerable to <Command Injection>]]></failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="Dockerfile" tests="3" failures="1" errors="0" skipped="1">
+    <properties>
+      <property name="type" value="dockerfile"></property>
+      <property name="class" value="config"></property>
+    </properties>
+    <testcase classname="Dockerfile" name="[HIGH] AVD-DS-0002: Image user should not be &#39;root&#39;">
+      <failure message="Specify at least 1 USER command in Dockerfile with non-root user as argument" type="HIGH"></failure>
+    </testcase>
+    <testcase classname="Dockerfile" name="[MEDIUM] AVD-DS-0001: &#39;:latest&#39; tag used"></testcase>
+    <testcase classname="Dockerfile" name="[LOW] AVD-DS-0026: No HEALTHCHECK defined">
+      <skipped message="exception"></skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="go.mod" tests="0" failures="0" errors="0" skipped="0">
+    <properties>
+      <property name="type" value="gomod"></property>
+      <property name="class" value="lang-pkgs"></property>
+    </properties>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name:     "test case per target",
+			testCase: junit.TestCaseTarget,
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="trivy" tests="3" failures="2" skipped="0">
+  <testsuite name="./app" tests="3" failures="2" errors="0" skipped="0">
+    <testcase classname="lang-pkgs" name="package-lock.json">
+      <failure message="1 vulnerabilities" type="findings"><![CDATA[[HIGH] CVE-2021-23337: lodash 4.17.4 (fixed in 4.17.21): nodejs-lodash: command injection via template]]></failure>
+    </testcase>
+    <testcase classname="config" name="Dockerfile">
+      <failure message="1 misconfigurations" type="findings"><![CDATA[[HIGH] AVD-DS-0002: Specify at least 1 USER command in Dockerfile with non-root user as argument]]></failure>
+    </testcase>
+    <testcase classname="lang-pkgs" name="go.mod"></testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			w := junit.Writer{
+				Output:   out,
+				TestCase: tt.testCase,
+			}
+			require.NoError(t, w.Write(context.Background(), report))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestWriter_Write_UnknownTestCase(t *testing.T) {
+	w := junit.Writer{
+		Output:   bytes.NewBuffer(nil),
+		TestCase: "package",
+	}
+	assert.ErrorContains(t, w.Write(context.Background(), report), "unknown JUnit test case: package")
+}
@@ -0,0 +1,195 @@
+package layers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/fanal/image"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Layer represents the vulnerabilities introduced by an image layer
+type Layer struct {
+	// Index is the position of the layer in the image, starting from 1.
+	// It is 0 if the layer is unknown, e.g. the vulnerable package is not in a container image.
+	Index  int
+	DiffID string
+
+	// CreatedBy is the Dockerfile instruction creating the layer, recovered from the image history
+	CreatedBy string
+
+	// BaseImage is true if the layer is guessed to belong to the base image,
+	// which is usually maintained by a platform team rather than the application team.
+	BaseImage bool
+
+	Vulnerabilities []Vulnerability
+}
+
+// Vulnerability represents a vulnerability with the target it was detected in
+type Vulnerability struct {
+	Target string
+	types.DetectedVulnerability
+}
+
+// NewLayers groups the detected vulnerabilities by the layer introducing the vulnerable package.
+// Layers are sorted in the order of the image, and vulnerabilities with no layer come last.
+func NewLayers(report types.Report) []Layer {
+	layers := lo.Map(report.Metadata.DiffIDs, func(diffID string, i int) *Layer {
+		return &Layer{
+			Index:  i + 1,
+			DiffID: diffID,
+		}
+	})
+	fillHistory(layers, report.Metadata.ImageConfig.History)
+
+	byDiffID := lo.SliceToMap(layers, func(l *Layer) (string, *Layer) {
+		return l.DiffID, l
+	})
+	unknown := &Layer{}
+	for _, result := range report.Results {
+		for _, vuln := range result.Vulnerabilities {
+			l, ok := byDiffID[vuln.Layer.DiffID]
+			if !ok {
+				l = unknown
+			}
+			l.Vulnerabilities = append(l.Vulnerabilities, Vulnerability{
+				Target:                result.Target,
+				DetectedVulnerability: vuln,
+			})
+		}
+	}
+
+	var grouped []Layer
+	for _, l := range append(layers, unknown) {
+		if len(l.Vulnerabilities) > 0 {
+			grouped = append(grouped, *l)
+		}
+	}
+	return grouped
+}
+
+// fillHistory sets the instructions to the layers.
+// Histories of empty layers, such as ENV and CMD, have no corresponding layers and are skipped.
+func fillHistory(layers []*Layer, histories []v1.History) {
+	baseImageIndex := image.GuessBaseImageIndex(histories)
+	var n int
+	for i, h := range histories {
+		if h.EmptyLayer {
+			continue
+		}
+		if n >= len(layers) {
+			return
+		}
+		layers[n].CreatedBy = instruction(h.CreatedBy)
+		layers[n].BaseImage = i < baseImageIndex
+		n++
+	}
+}
+
+// instruction converts the history into the Dockerfile instruction, e.g.
+//
+//	/bin/sh -c apk add --no-cache curl => RUN apk add --no-cache curl
+//	/bin/sh -c #(nop) ADD file:37a76ec18f98 in / => ADD file:37a76ec18f98 in /
+//	COPY app.jar /app/ # buildkit => COPY app.jar /app/
+func instruction(createdBy string) string {
+	switch {
+	case strings.HasPrefix(createdBy, "/bin/sh -c #(nop)"):
+		createdBy = strings.TrimPrefix(createdBy, "/bin/sh -c #(nop)")
+	case strings.HasPrefix(createdBy, "/bin/sh -c"):
+		createdBy = "RUN" + strings.TrimPrefix(createdBy, "/bin/sh -c")
+	case strings.HasSuffix(createdBy, "# buildkit"):
+		createdBy = strings.TrimSuffix(createdBy, "# buildkit")
+		createdBy = strings.Replace(createdBy, "RUN /bin/sh -c", "RUN", 1)
+	}
+	return strings.TrimSpace(createdBy)
+}
+
+// Writer writes the vulnerabilities grouped by the layer introducing the vulnerable package, e.g.
+//
+//	Layer 2: RUN apk add --no-cache curl
+//	====================================
+//	DiffID: sha256:1f9a...
+//	Total: 1 (HIGH: 1)
+//	- CVE-2023-38545: curl 8.2.1-r0 (HIGH, fixed in 8.4.0-r0)
+type Writer struct {
+	Output io.Writer
+}
+
+func (w Writer) Write(_ context.Context, report types.Report) error {
+	layers := NewLayers(report)
+	if len(layers) == 0 {
+		if _, err := fmt.Fprintln(w.Output, "No vulnerabilities detected"); err != nil {
+			return xerrors.Errorf("failed to write layers: %w", err)
+		}
+		return nil
+	}
+
+	var b strings.Builder
+	for i, l := range layers {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		header := l.Title()
+		fmt.Fprintf(&b, "%s\n%s\n", header, strings.Repeat("=", len(header)))
+		if l.DiffID != "" {
+			fmt.Fprintf(&b, "DiffID: %s\n", l.DiffID)
+		}
+		fmt.Fprintf(&b, "Total: %d (%s)\n", len(l.Vulnerabilities), countSeverities(l.Vulnerabilities))
+
+		for _, vuln := range l.Vulnerabilities {
+			b.WriteString(formatVulnerability(vuln))
+		}
+	}
+
+	if _, err := io.WriteString(w.Output, b.String()); err != nil {
+		return xerrors.Errorf("failed to write layers: %w", err)
+	}
+	return nil
+}
+
+// Title returns the heading of the layer, e.g. "Layer 2: RUN apk add --no-cache curl"
+func (l Layer) Title() string {
+	switch {
+	case l.Index == 0:
+		return "Unknown layer"
+	case l.CreatedBy == "":
+		return fmt.Sprintf("Layer %d", l.Index)
+	case l.BaseImage:
+		return fmt.Sprintf("Layer %d (base image): %s", l.Index, l.CreatedBy)
+	}
+	return fmt.Sprintf("Layer %d: %s", l.Index, l.CreatedBy)
+}
+
+func formatVulnerability(vuln Vulnerability) string {
+	pkg := fmt.Sprintf("%s %s", vuln.PkgName, vuln.InstalledVersion)
+	if vuln.PkgPath != "" {
+		pkg = fmt.Sprintf("%s %s (%s)", vuln.PkgName, vuln.InstalledVersion, vuln.PkgPath)
+	}
+	fixed := "no fix"
+	if vuln.FixedVersion != "" {
+		fixed = "fixed in " + vuln.FixedVersion
+	}
+	return fmt.Sprintf("- %s: %s (%s, %s)\n", vuln.VulnerabilityID, pkg, vuln.Severity, fixed)
+}
+
+// countSeverities returns the number of vulnerabilities by severity, the most severe first
+func countSeverities(vulns []Vulnerability) string {
+	counts := lo.CountValuesBy(vulns, func(vuln Vulnerability) string {
+		return vuln.Severity
+	})
+	var summaries []string
+	for i := len(dbTypes.SeverityNames) - 1; i >= 0; i-- {
+		severity := dbTypes.SeverityNames[i]
+		if count := counts[severity]; count > 0 {
+			summaries = append(summaries, fmt.Sprintf("%s: %d", severity, count))
+		}
+	}
+	return strings.Join(summaries, ", ")
+}
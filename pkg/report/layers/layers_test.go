@@ -0,0 +1,162 @@
+package layers_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/layers"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name   string
+		report types.Report
+		want   string
+	}{
+		{
+			name: "container image",
+			report: types.Report{
+				ArtifactName: "app:1.0",
+				Metadata: types.Metadata{
+					DiffIDs: []string{"sha256:base", "sha256:curl", "sha256:app"},
+					ImageConfig: v1.ConfigFile{
+						History: []v1.History{
+							{CreatedBy: "/bin/sh -c #(nop) ADD file:37a76ec18f98 in / "},
+							{CreatedBy: `/bin/sh -c #(nop)  CMD ["/bin/sh"]`, EmptyLayer: true},
+							{CreatedBy: "RUN /bin/sh -c apk add --no-cache curl # buildkit"},
+							{CreatedBy: "ENV APP_HOME=/app", EmptyLayer: true},
+							{CreatedBy: "COPY app/ /app/ # buildkit"},
+							{CreatedBy: `CMD ["node", "/app/index.js"]`, EmptyLayer: true},
+						},
+					},
+				},
+				Results: types.Results{
+					{
+						Target: "app:1.0 (alpine 3.19.1)",
+						Class:  types.ClassOSPkg,
+						Type:   ftypes.Alpine,
+						Vulnerabilities: []types.DetectedVulnerability{
+							{
+								VulnerabilityID:  "CVE-2024-0727",
+								PkgName:          "libcrypto3",
+								InstalledVersion: "3.1.4-r4",
+								FixedVersion:     "3.1.4-r5",
+								Layer:            ftypes.Layer{DiffID: "sha256:base"},
+								Vulnerability:    vuln("MEDIUM"),
+							},
+							{
+								VulnerabilityID:  "CVE-2023-38545",
+								PkgName:          "curl",
+								InstalledVersion: "8.2.1-r0",
+								FixedVersion:     "8.4.0-r0",
+								Layer:            ftypes.Layer{DiffID: "sha256:curl"},
+								Vulnerability:    vuln("CRITICAL"),
+							},
+						},
+					},
+					{
+						Target: "Node.js",
+						Class:  types.ClassLangPkg,
+						Type:   ftypes.NodePkg,
+						Vulnerabilities: []types.DetectedVulnerability{
+							{
+								VulnerabilityID:  "CVE-2021-23337",
+								PkgName:          "lodash",
+								PkgPath:          "app/node_modules/lodash/package.json",
+								InstalledVersion: "4.17.4",
+								FixedVersion:     "4.17.21",
+								Layer:            ftypes.Layer{DiffID: "sha256:app"},
+								Vulnerability:    vuln("HIGH"),
+							},
+							{
+								VulnerabilityID:  "CVE-2023-42282",
+								PkgName:          "ip",
+								PkgPath:          "app/node_modules/ip/package.json",
+								InstalledVersion: "2.0.0",
+								Layer:            ftypes.Layer{DiffID: "sha256:app"},
+								Vulnerability:    vuln("LOW"),
+							},
+						},
+					},
+				},
+			},
+			want: `Layer 1 (base image): ADD file:37a76ec18f98 in /
+================================================
+DiffID: sha256:base
+Total: 1 (MEDIUM: 1)
+- CVE-2024-0727: libcrypto3 3.1.4-r4 (MEDIUM, fixed in 3.1.4-r5)
+
+Layer 2: RUN apk add --no-cache curl
+====================================
+DiffID: sha256:curl
+Total: 1 (CRITICAL: 1)
+- CVE-2023-38545: curl 8.2.1-r0 (CRITICAL, fixed in 8.4.0-r0)
+
+Layer 3: COPY app/ /app/
+========================
+DiffID: sha256:app
+Total: 2 (HIGH: 1, LOW: 1)
+- CVE-2021-23337: lodash 4.17.4 (app/node_modules/lodash/package.json) (HIGH, fixed in 4.17.21)
+- CVE-2023-42282: ip 2.0.0 (app/node_modules/ip/package.json) (LOW, no fix)
+`,
+		},
+		{
+			name: "no layer",
+			report: types.Report{
+				ArtifactName: "./app",
+				Results: types.Results{
+					{
+						Target: "go.mod",
+						Class:  types.ClassLangPkg,
+						Type:   ftypes.GoModule,
+						Vulnerabilities: []types.DetectedVulnerability{
+							{
+								VulnerabilityID:  "CVE-2023-39325",
+								PkgName:          "golang.org/x/net",
+								InstalledVersion: "0.15.0",
+								FixedVersion:     "0.17.0",
+								Vulnerability:    vuln("HIGH"),
+							},
+						},
+					},
+				},
+			},
+			want: `Unknown layer
+=============
+Total: 1 (HIGH: 1)
+- CVE-2023-39325: golang.org/x/net 0.15.0 (HIGH, fixed in 0.17.0)
+`,
+		},
+		{
+			name: "no vulnerabilities",
+			report: types.Report{
+				ArtifactName: "alpine:3.19",
+				Metadata: types.Metadata{
+					DiffIDs: []string{"sha256:base"},
+				},
+			},
+			want: "No vulnerabilities detected\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			w := layers.Writer{Output: buf}
+			err := w.Write(context.Background(), tt.report)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func vuln(severity string) dbTypes.Vulnerability {
+	return dbTypes.Vulnerability{Severity: severity}
+}
@@ -0,0 +1,212 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// TopPackages is the number of packages reported in "trivy_package_vulnerabilities"
+const TopPackages = 10
+
+const (
+	metricVulnerabilities        = "trivy_vulnerabilities"
+	metricMisconfigurations      = "trivy_misconfigurations"
+	metricSecrets                = "trivy_secrets"
+	metricPackageVulnerabilities = "trivy_package_vulnerabilities"
+	metricScanTimestamp          = "trivy_scan_timestamp_seconds"
+	metricDBUpdatedTimestamp     = "trivy_db_updated_timestamp_seconds"
+	metricDBAge                  = "trivy_db_age_seconds"
+)
+
+var help = map[string]string{
+	metricVulnerabilities:        "Number of vulnerabilities by severity",
+	metricMisconfigurations:      "Number of failed misconfiguration checks by severity",
+	metricSecrets:                "Number of secrets by severity",
+	metricPackageVulnerabilities: "Number of vulnerabilities of the most vulnerable packages",
+	metricScanTimestamp:          "Time when the scan finished",
+	metricDBUpdatedTimestamp:     "Time when the vulnerability database was updated",
+	metricDBAge:                  "Age of the vulnerability database at the scan",
+}
+
+// Metric is a flattened sample, which can be queried with JSONPath, e.g. $.Metrics[?(@.Name == 'trivy_vulnerabilities')]
+type Metric struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Snapshot represents the metrics of a scan
+type Snapshot struct {
+	ArtifactName string
+	ArtifactType string
+	Timestamp    time.Time
+	Metrics      []Metric
+}
+
+// Writer writes the metrics of the report for dashboards.
+// The metrics are written in JSON for the Grafana JSON datasource
+// or in the Prometheus text format, which can be pushed to Pushgateway.
+type Writer struct {
+	Output io.Writer
+	Format types.Format
+
+	// DBUpdatedAt is the time when the vulnerability database was updated, which is unknown if zero
+	DBUpdatedAt time.Time
+}
+
+func (w Writer) Write(ctx context.Context, report types.Report) error {
+	snapshot := NewSnapshot(report, clock.Now(ctx), w.DBUpdatedAt)
+
+	switch w.Format {
+	case types.FormatPrometheus:
+		if err := writePrometheus(w.Output, snapshot); err != nil {
+			return xerrors.Errorf("failed to write metrics: %w", err)
+		}
+	default:
+		b, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return xerrors.Errorf("failed to marshal metrics: %w", err)
+		}
+		if _, err = fmt.Fprintln(w.Output, string(b)); err != nil {
+			return xerrors.Errorf("failed to write metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+// NewSnapshot flattens the report into metrics
+func NewSnapshot(report types.Report, now, dbUpdatedAt time.Time) Snapshot {
+	artifactLabels := map[string]string{
+		"artifact_name": report.ArtifactName,
+		"artifact_type": string(report.ArtifactType),
+	}
+	withLabels := func(kv ...string) map[string]string {
+		labels := lo.Assign(artifactLabels)
+		for i := 0; i+1 < len(kv); i += 2 {
+			labels[kv[i]] = kv[i+1]
+		}
+		return labels
+	}
+
+	vulns := make(map[string]int)
+	misconfs := make(map[string]int)
+	secrets := make(map[string]int)
+	type pkg struct {
+		name, version string
+	}
+	pkgVulns := make(map[pkg]int)
+	for _, result := range report.Results {
+		for _, vuln := range result.Vulnerabilities {
+			vulns[vuln.Severity]++
+			pkgVulns[pkg{name: vuln.PkgName, version: vuln.InstalledVersion}]++
+		}
+		for _, misconf := range result.Misconfigurations {
+			if misconf.Status == types.MisconfStatusFailure {
+				misconfs[misconf.Severity]++
+			}
+		}
+		for _, secret := range result.Secrets {
+			secrets[secret.Severity]++
+		}
+	}
+
+	var metrics []Metric
+	// All severities are reported so that the series don't disappear when the issues are fixed
+	for _, counts := range []struct {
+		name   string
+		counts map[string]int
+	}{
+		{name: metricVulnerabilities, counts: vulns},
+		{name: metricMisconfigurations, counts: misconfs},
+		{name: metricSecrets, counts: secrets},
+	} {
+		for _, severity := range dbTypes.SeverityNames {
+			metrics = append(metrics, Metric{
+				Name:   counts.name,
+				Labels: withLabels("severity", severity),
+				Value:  float64(counts.counts[severity]),
+			})
+		}
+	}
+
+	pkgs := lo.Keys(pkgVulns)
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgVulns[pkgs[i]] != pkgVulns[pkgs[j]] {
+			return pkgVulns[pkgs[i]] > pkgVulns[pkgs[j]]
+		}
+		if pkgs[i].name != pkgs[j].name {
+			return pkgs[i].name < pkgs[j].name
+		}
+		return pkgs[i].version < pkgs[j].version
+	})
+	for _, p := range lo.Slice(pkgs, 0, TopPackages) {
+		metrics = append(metrics, Metric{
+			Name:   metricPackageVulnerabilities,
+			Labels: withLabels("package", p.name, "version", p.version),
+			Value:  float64(pkgVulns[p]),
+		})
+	}
+
+	metrics = append(metrics, Metric{
+		Name:   metricScanTimestamp,
+		Labels: withLabels(),
+		Value:  float64(now.Unix()),
+	})
+	if !dbUpdatedAt.IsZero() {
+		metrics = append(metrics, Metric{
+			Name:   metricDBUpdatedTimestamp,
+			Labels: withLabels(),
+			Value:  float64(dbUpdatedAt.Unix()),
+		}, Metric{
+			Name:   metricDBAge,
+			Labels: withLabels(),
+			Value:  now.Sub(dbUpdatedAt).Seconds(),
+		})
+	}
+
+	return Snapshot{
+		ArtifactName: report.ArtifactName,
+		ArtifactType: string(report.ArtifactType),
+		Timestamp:    now,
+		Metrics:      metrics,
+	}
+}
+
+// writePrometheus writes the metrics in the Prometheus text exposition format.
+// Timestamps are omitted as Pushgateway rejects them.
+func writePrometheus(w io.Writer, snapshot Snapshot) error {
+	var b strings.Builder
+	var last string
+	for _, m := range snapshot.Metrics {
+		if m.Name != last {
+			fmt.Fprintf(&b, "# HELP %s %s\n", m.Name, help[m.Name])
+			fmt.Fprintf(&b, "# TYPE %s gauge\n", m.Name)
+			last = m.Name
+		}
+
+		keys := lo.Keys(m.Labels)
+		sort.Strings(keys)
+		labels := lo.Map(keys, func(k string, _ int) string {
+			return fmt.Sprintf(`%s="%s"`, k, labelValueEscaper.Replace(m.Labels[k]))
+		})
+		fmt.Fprintf(&b, "%s{%s} %s\n", m.Name, strings.Join(labels, ","), strconv.FormatFloat(m.Value, 'f', -1, 64))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// labelValueEscaper escapes label values as the text format requires
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,173 @@
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/metrics"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var report = types.Report{
+	ArtifactName: "alpine:3.19",
+	ArtifactType: ftypes.ArtifactContainerImage,
+	Results: types.Results{
+		{
+			Target: "alpine:3.19 (alpine 3.19.0)",
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2023-0001",
+					PkgName:          "openssl",
+					InstalledVersion: "3.1.4-r1",
+					Vulnerability:    dbTypes.Vulnerability{Severity: "HIGH"},
+				},
+				{
+					VulnerabilityID:  "CVE-2023-0002",
+					PkgName:          "openssl",
+					InstalledVersion: "3.1.4-r1",
+					Vulnerability:    dbTypes.Vulnerability{Severity: "CRITICAL"},
+				},
+				{
+					VulnerabilityID:  "CVE-2023-0003",
+					PkgName:          "busybox",
+					InstalledVersion: "1.36.1-r15",
+					Vulnerability:    dbTypes.Vulnerability{Severity: "HIGH"},
+				},
+			},
+		},
+		{
+			Target: "Dockerfile",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				{
+					ID:       "DS002",
+					Severity: "HIGH",
+					Status:   types.MisconfStatusFailure,
+				},
+				{
+					ID:       "DS001",
+					Severity: "MEDIUM",
+					Status:   types.MisconfStatusPassed,
+				},
+			},
+		},
+	},
+}
+
+func TestWriter_Write_Prometheus(t *testing.T) {
+	ctx := clock.With(context.Background(), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	out := bytes.NewBuffer(nil)
+	w := metrics.Writer{
+		Output:      out,
+		Format:      types.FormatPrometheus,
+		DBUpdatedAt: time.Date(2024, 2, 29, 18, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, w.Write(ctx, report))
+
+	want := `# HELP trivy_vulnerabilities Number of vulnerabilities by severity
+# TYPE trivy_vulnerabilities gauge
+trivy_vulnerabilities{artifact_name="alpine:3.19",artifact_type="container_image",severity="UNKNOWN"} 0
+trivy_vulnerabilities{artifact_name="alpine:3.19",artifact_type="container_image",severity="LOW"} 0
+trivy_vulnerabilities{artifact_name="alpine:3.19",artifact_type="container_image",severity="MEDIUM"} 0
+trivy_vulnerabilities{artifact_name="alpine:3.19",artifact_type="container_image",severity="HIGH"} 2
+trivy_vulnerabilities{artifact_name="alpine:3.19",artifact_type="container_image",severity="CRITICAL"} 1
+# HELP trivy_misconfigurations Number of failed misconfiguration checks by severity
+# TYPE trivy_misconfigurations gauge
+trivy_misconfigurations{artifact_name="alpine:3.19",artifact_type="container_image",severity="UNKNOWN"} 0
+trivy_misconfigurations{artifact_name="alpine:3.19",artifact_type="container_image",severity="LOW"} 0
+trivy_misconfigurations{artifact_name="alpine:3.19",artifact_type="container_image",severity="MEDIUM"} 0
+trivy_misconfigurations{artifact_name="alpine:3.19",artifact_type="container_image",severity="HIGH"} 1
+trivy_misconfigurations{artifact_name="alpine:3.19",artifact_type="container_image",severity="CRITICAL"} 0
+# HELP trivy_secrets Number of secrets by severity
+# TYPE trivy_secrets gauge
+trivy_secrets{artifact_name="alpine:3.19",artifact_type="container_image",severity="UNKNOWN"} 0
+trivy_secrets{artifact_name="alpine:3.19",artifact_type="container_image",severity="LOW"} 0
+trivy_secrets{artifact_name="alpine:3.19",artifact_type="container_image",severity="MEDIUM"} 0
+trivy_secrets{artifact_name="alpine:3.19",artifact_type="container_image",severity="HIGH"} 0
+trivy_secrets{artifact_name="alpine:3.19",artifact_type="container_image",severity="CRITICAL"} 0
+# HELP trivy_package_vulnerabilities Number of vulnerabilities of the most vulnerable packages
+# TYPE trivy_package_vulnerabilities gauge
+trivy_package_vulnerabilities{artifact_name="alpine:3.19",artifact_type="container_image",package="openssl",version="3.1.4-r1"} 2
+trivy_package_vulnerabilities{artifact_name="alpine:3.19",artifact_type="container_image",package="busybox",version="1.36.1-r15"} 1
+# HELP trivy_scan_timestamp_seconds Time when the scan finished
+# TYPE trivy_scan_timestamp_seconds gauge
+trivy_scan_timestamp_seconds{artifact_name="alpine:3.19",artifact_type="container_image"} 1709251200
+# HELP trivy_db_updated_timestamp_seconds Time when the vulnerability database was updated
+# TYPE trivy_db_updated_timestamp_seconds gauge
+trivy_db_updated_timestamp_seconds{artifact_name="alpine:3.19",artifact_type="container_image"} 1709229600
+# HELP trivy_db_age_seconds Age of the vulnerability database at the scan
+# TYPE trivy_db_age_seconds gauge
+trivy_db_age_seconds{artifact_name="alpine:3.19",artifact_type="container_image"} 21600
+`
+	assert.Equal(t, want, out.String())
+}
+
+func TestWriter_Write_JSON(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	ctx := clock.With(context.Background(), now)
+	out := bytes.NewBuffer(nil)
+	w := metrics.Writer{
+		Output: out,
+		Format: types.FormatMetrics,
+	}
+	require.NoError(t, w.Write(ctx, report))
+
+	var got metrics.Snapshot
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, "alpine:3.19", got.ArtifactName)
+	assert.Equal(t, "container_image", got.ArtifactType)
+	assert.True(t, now.Equal(got.Timestamp))
+
+	// The DB metrics are omitted when the DB is unknown
+	assert.Len(t, got.Metrics, 3*5+2+1)
+	assert.Contains(t, got.Metrics, metrics.Metric{
+		Name: "trivy_vulnerabilities",
+		Labels: map[string]string{
+			"artifact_name": "alpine:3.19",
+			"artifact_type": "container_image",
+			"severity":      "CRITICAL",
+		},
+		Value: 1,
+	})
+}
+
+func TestNewSnapshot_TopPackages(t *testing.T) {
+	var vulns []types.DetectedVulnerability
+	for i := 0; i < metrics.TopPackages+5; i++ {
+		vulns = append(vulns, types.DetectedVulnerability{
+			VulnerabilityID:  "CVE-2023-0001",
+			PkgName:          string(rune('a' + i)),
+			InstalledVersion: "1.0.0",
+			Vulnerability:    dbTypes.Vulnerability{Severity: "LOW"},
+		})
+	}
+	snapshot := metrics.NewSnapshot(types.Report{
+		Results: types.Results{{Vulnerabilities: vulns}},
+	}, time.Now(), time.Time{})
+
+	var pkgs []string
+	for _, m := range snapshot.Metrics {
+		if m.Name == "trivy_package_vulnerabilities" {
+			pkgs = append(pkgs, m.Labels["package"])
+		}
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}, pkgs)
+}
+
+func TestWriter_Write_Escape(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	w := metrics.Writer{
+		Output: out,
+		Format: types.FormatPrometheus,
+	}
+	require.NoError(t, w.Write(context.Background(), types.Report{ArtifactName: "C:\\path\\\"app\"\n"}))
+	assert.Contains(t, out.String(), `artifact_name="C:\\path\\\"app\"\n"`)
+}
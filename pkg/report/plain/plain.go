@@ -0,0 +1,231 @@
+package plain
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Writer writes the report as plain text for screen readers and narrow terminals.
+// It uses no box drawing or colors, writes one key/value per line, and sorts findings deterministically, e.g.
+//
+//	Target: package-lock.json
+//	Type: npm
+//	Vulnerabilities: 1 (CRITICAL: 0, HIGH: 1, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+//
+//	Vulnerability 1 of 1
+//	ID: CVE-2021-23337
+//	Severity: HIGH
+//	Package: lodash
+//	...
+type Writer struct {
+	Output io.Writer
+
+	// For misconfigurations
+	IncludeNonFailures bool
+}
+
+type field struct {
+	key   string
+	value string
+}
+
+// record is a list of key/value pairs. Pairs with no value are not written.
+type record []field
+
+func (r record) String() string {
+	var b strings.Builder
+	for _, f := range r {
+		if f.value == "" {
+			continue
+		}
+		// Keep one key/value per line
+		value := strings.Join(strings.Fields(f.value), " ")
+		fmt.Fprintf(&b, "%s: %s\n", f.key, value)
+	}
+	return b.String()
+}
+
+func (w Writer) Write(_ context.Context, report types.Report) error {
+	var b strings.Builder
+	b.WriteString(record{
+		{"Artifact", report.ArtifactName},
+		{"Artifact type", string(report.ArtifactType)},
+	}.String())
+
+	var written bool
+	for _, result := range report.Results {
+		misconfs := lo.Filter(result.Misconfigurations, func(m types.DetectedMisconfiguration, _ int) bool {
+			return w.IncludeNonFailures || m.Status == types.MisconfStatusFailure
+		})
+		if len(result.Vulnerabilities) == 0 && len(misconfs) == 0 && len(result.Secrets) == 0 && len(result.Licenses) == 0 {
+			continue
+		}
+		written = true
+
+		b.WriteString("\n")
+		b.WriteString(record{
+			{"Target", result.Target},
+			{"Type", string(result.Type)},
+		}.String())
+
+		writeFindings(&b, "Vulnerability", "Vulnerabilities", vulnerabilityRecords(result.Vulnerabilities))
+		writeFindings(&b, "Misconfiguration", "Misconfigurations", misconfigurationRecords(misconfs))
+		writeFindings(&b, "Secret", "Secrets", secretRecords(result.Secrets))
+		writeFindings(&b, "License", "Licenses", licenseRecords(result.Licenses))
+	}
+	if !written {
+		b.WriteString("\nNo issues detected\n")
+	}
+	if report.Incomplete != nil {
+		b.WriteString("\n")
+		b.WriteString(record{
+			{"Incomplete results", "the analysis stopped as " + report.Incomplete.Reason},
+			{"Not fully analyzed", strings.Join(report.Incomplete.SkippedTargets, ", ")},
+		}.String())
+	}
+
+	if _, err := io.WriteString(w.Output, b.String()); err != nil {
+		return xerrors.Errorf("failed to write plain text: %w", err)
+	}
+	return nil
+}
+
+// severityRecord is a record with the severity for counting and the key for sorting
+type severityRecord struct {
+	severity string
+	key      string
+	record   record
+}
+
+func writeFindings(b *strings.Builder, singular, plural string, records []severityRecord) {
+	if len(records) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s: %d (%s)\n", plural, len(records), summarize(records))
+	for i, r := range records {
+		fmt.Fprintf(b, "\n%s %d of %d\n", singular, i+1, len(records))
+		b.WriteString(r.record.String())
+	}
+}
+
+// summarize returns the number of findings by severity, the most severe first.
+// All the severities are listed so that the summary has the same shape every time.
+func summarize(records []severityRecord) string {
+	counts := lo.CountValuesBy(records, func(r severityRecord) string {
+		return r.severity
+	})
+	var summaries []string
+	for i := len(dbTypes.SeverityNames) - 1; i >= 0; i-- {
+		severity := dbTypes.SeverityNames[i]
+		summaries = append(summaries, fmt.Sprintf("%s: %d", severity, counts[severity]))
+	}
+	return strings.Join(summaries, ", ")
+}
+
+// sortRecords sorts the records by severity, the most severe first, and then by the keys
+func sortRecords(records []severityRecord) []severityRecord {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].severity != records[j].severity {
+			return dbTypes.CompareSeverityString(records[j].severity, records[i].severity) > 0
+		}
+		return records[i].key < records[j].key
+	})
+	return records
+}
+
+// location returns the lines of the finding, e.g. "lines 3 to 5"
+func location(startLine, endLine int) string {
+	switch {
+	case startLine <= 0:
+		return ""
+	case startLine >= endLine:
+		return fmt.Sprintf("line %d", startLine)
+	}
+	return fmt.Sprintf("lines %d to %d", startLine, endLine)
+}
+
+func vulnerabilityRecords(vulns []types.DetectedVulnerability) []severityRecord {
+	records := lo.Map(vulns, func(v types.DetectedVulnerability, _ int) severityRecord {
+		return severityRecord{
+			severity: v.Severity,
+			key:      fmt.Sprintf("%s/%s/%s", v.VulnerabilityID, v.PkgName, v.PkgPath),
+			record: record{
+				{"ID", v.VulnerabilityID},
+				{"Aliases", strings.Join(v.Aliases, ", ")},
+				{"Severity", v.Severity},
+				{"Package", v.PkgName},
+				{"Package path", v.PkgPath},
+				{"Installed version", v.InstalledVersion},
+				{"Fixed version", v.FixedVersion},
+				{"Status", v.Status.String()},
+				{"Title", v.Title},
+				{"URL", v.PrimaryURL},
+			},
+		}
+	})
+	return sortRecords(records)
+}
+
+func misconfigurationRecords(misconfs []types.DetectedMisconfiguration) []severityRecord {
+	records := lo.Map(misconfs, func(m types.DetectedMisconfiguration, _ int) severityRecord {
+		return severityRecord{
+			severity: m.Severity,
+			key:      fmt.Sprintf("%s/%s/%08d", m.AVDID, m.CauseMetadata.Resource, m.CauseMetadata.StartLine),
+			record: record{
+				{"ID", m.AVDID},
+				{"Severity", m.Severity},
+				{"Status", string(m.Status)},
+				{"Title", m.Title},
+				{"Message", m.Message},
+				{"Resource", m.CauseMetadata.Resource},
+				{"Location", location(m.CauseMetadata.StartLine, m.CauseMetadata.EndLine)},
+				{"Resolution", m.Resolution},
+				{"URL", m.PrimaryURL},
+			},
+		}
+	})
+	return sortRecords(records)
+}
+
+func secretRecords(secrets []types.DetectedSecret) []severityRecord {
+	records := lo.Map(secrets, func(s types.DetectedSecret, _ int) severityRecord {
+		return severityRecord{
+			severity: s.Severity,
+			key:      fmt.Sprintf("%08d/%s", s.StartLine, s.RuleID),
+			record: record{
+				{"ID", s.RuleID},
+				{"Severity", s.Severity},
+				{"Category", string(s.Category)},
+				{"Title", s.Title},
+				{"Location", location(s.StartLine, s.EndLine)},
+			},
+		}
+	})
+	return sortRecords(records)
+}
+
+func licenseRecords(licenses []types.DetectedLicense) []severityRecord {
+	records := lo.Map(licenses, func(l types.DetectedLicense, _ int) severityRecord {
+		return severityRecord{
+			severity: l.Severity,
+			key:      fmt.Sprintf("%s/%s/%s", l.Name, l.PkgName, l.FilePath),
+			record: record{
+				{"Name", l.Name},
+				{"Severity", l.Severity},
+				{"Classification", string(l.Category)},
+				{"Package", l.PkgName},
+				{"File", l.FilePath},
+			},
+		}
+	})
+	return sortRecords(records)
+}
@@ -0,0 +1,201 @@
+package plain_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/plain"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		results types.Results
+		want    string
+	}{
+		{
+			name: "vulnerabilities sorted by severity and ID",
+			results: types.Results{
+				{
+					Target: "package-lock.json",
+					Class:  types.ClassLangPkg,
+					Type:   ftypes.Npm,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2023-42282",
+							PkgName:          "ip",
+							InstalledVersion: "2.0.0",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:    "nodejs-ip: arbitrary code execution",
+								Severity: "LOW",
+							},
+						},
+						{
+							VulnerabilityID:  "CVE-2021-23337",
+							PkgName:          "lodash",
+							InstalledVersion: "4.17.4",
+							FixedVersion:     "4.17.21",
+							Status:           dbTypes.StatusFixed,
+							PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2021-23337",
+							Vulnerability: dbTypes.Vulnerability{
+								Title:    "nodejs-lodash: command injection via template",
+								Severity: "HIGH",
+							},
+						},
+						{
+							VulnerabilityID:  "CVE-2019-10744",
+							PkgName:          "lodash",
+							InstalledVersion: "4.17.4",
+							FixedVersion:     "4.17.12",
+							Status:           dbTypes.StatusFixed,
+							Vulnerability: dbTypes.Vulnerability{
+								// Multiple lines are joined
+								Title:    "nodejs-lodash: prototype pollution\nin defaultsDeep function",
+								Severity: "HIGH",
+							},
+						},
+					},
+				},
+			},
+			want: `Artifact: test
+Artifact type: filesystem
+
+Target: package-lock.json
+Type: npm
+Vulnerabilities: 3 (CRITICAL: 0, HIGH: 2, MEDIUM: 0, LOW: 1, UNKNOWN: 0)
+
+Vulnerability 1 of 3
+ID: CVE-2019-10744
+Severity: HIGH
+Package: lodash
+Installed version: 4.17.4
+Fixed version: 4.17.12
+Status: fixed
+Title: nodejs-lodash: prototype pollution in defaultsDeep function
+
+Vulnerability 2 of 3
+ID: CVE-2021-23337
+Severity: HIGH
+Package: lodash
+Installed version: 4.17.4
+Fixed version: 4.17.21
+Status: fixed
+Title: nodejs-lodash: command injection via template
+URL: https://avd.aquasec.com/nvd/cve-2021-23337
+
+Vulnerability 3 of 3
+ID: CVE-2023-42282
+Severity: LOW
+Package: ip
+Installed version: 2.0.0
+Status: unknown
+Title: nodejs-ip: arbitrary code execution
+`,
+		},
+		{
+			name: "misconfigurations and secrets",
+			results: types.Results{
+				{
+					Target: "Dockerfile",
+					Class:  types.ClassConfig,
+					Type:   ftypes.Dockerfile,
+					Misconfigurations: []types.DetectedMisconfiguration{
+						{
+							AVDID:    "AVD-DS-0026",
+							Title:    "No HEALTHCHECK defined",
+							Severity: "LOW",
+							Status:   types.MisconfStatusPassed,
+						},
+						{
+							AVDID:      "AVD-DS-0002",
+							Title:      "Image user should not be 'root'",
+							Message:    "Specify at least 1 USER command in Dockerfile with non-root user as argument",
+							Resolution: "Add 'USER <non root user name>' line to the Dockerfile",
+							Severity:   "HIGH",
+							Status:     types.MisconfStatusFailure,
+							CauseMetadata: ftypes.CauseMetadata{
+								StartLine: 1,
+								EndLine:   3,
+							},
+						},
+					},
+				},
+				{
+					Target: "config.yaml",
+					Class:  types.ClassSecret,
+					Secrets: []types.DetectedSecret{
+						{
+							RuleID:    "aws-access-key-id",
+							Category:  "AWS",
+							Severity:  "CRITICAL",
+							Title:     "AWS Access Key ID",
+							StartLine: 5,
+							EndLine:   5,
+						},
+					},
+				},
+			},
+			want: `Artifact: test
+Artifact type: filesystem
+
+Target: Dockerfile
+Type: dockerfile
+Misconfigurations: 1 (CRITICAL: 0, HIGH: 1, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+
+Misconfiguration 1 of 1
+ID: AVD-DS-0002
+Severity: HIGH
+Status: FAIL
+Title: Image user should not be 'root'
+Message: Specify at least 1 USER command in Dockerfile with non-root user as argument
+Location: lines 1 to 3
+Resolution: Add 'USER <non root user name>' line to the Dockerfile
+
+Target: config.yaml
+Secrets: 1 (CRITICAL: 1, HIGH: 0, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+
+Secret 1 of 1
+ID: aws-access-key-id
+Severity: CRITICAL
+Category: AWS
+Title: AWS Access Key ID
+Location: line 5
+`,
+		},
+		{
+			name: "no issues",
+			results: types.Results{
+				{
+					Target: "go.mod",
+					Class:  types.ClassLangPkg,
+					Type:   ftypes.GoModule,
+				},
+			},
+			want: `Artifact: test
+Artifact type: filesystem
+
+No issues detected
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			w := plain.Writer{Output: buf}
+			err := w.Write(context.Background(), types.Report{
+				ArtifactName: "test",
+				ArtifactType: ftypes.ArtifactFilesystem,
+				Results:      tt.results,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// defectDojoScanType is the parser of DefectDojo for the JSON report
+const defectDojoScanType = "Trivy Scan"
+
+// DefectDojoOptions configures the reimport to DefectDojo
+type DefectDojoOptions struct {
+	URL         string
+	APIKey      string
+	Product     string // The artifact name if empty
+	ProductType string // Required only when the product is created
+	Engagement  string
+}
+
+type reimportResponse struct {
+	Test       int `json:"test"`
+	Statistics struct {
+		After struct {
+			Total struct {
+				Active int `json:"active"`
+			} `json:"total"`
+		} `json:"after"`
+	} `json:"statistics"`
+}
+
+// reimportScan reimports the JSON report into the test of the engagement, so that the findings missing in the report
+// are closed. The product, the engagement and the test are created if they don't exist.
+// cf. https://documentation.defectdojo.com/integrations/importing/#reimport
+func reimportScan(ctx context.Context, client *http.Client, report types.Report, opts DefectDojoOptions) error {
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return xerrors.Errorf("json encode error: %w", err)
+	}
+
+	product := opts.Product
+	if product == "" {
+		product = report.ArtifactName
+	}
+	fields := map[string]string{
+		"scan_type":           defectDojoScanType,
+		"product_name":        product,
+		"engagement_name":     opts.Engagement,
+		"auto_create_context": "true",
+		"close_old_findings":  "true",
+	}
+	if opts.ProductType != "" {
+		fields["product_type_name"] = opts.ProductType
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for k, v := range fields {
+		if err = w.WriteField(k, v); err != nil {
+			return xerrors.Errorf("unable to write the form: %w", err)
+		}
+	}
+	fw, err := w.CreateFormFile("file", "trivy.json")
+	if err != nil {
+		return xerrors.Errorf("unable to write the form: %w", err)
+	}
+	if _, err = fw.Write(reportJSON); err != nil {
+		return xerrors.Errorf("unable to write the form: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return xerrors.Errorf("unable to write the form: %w", err)
+	}
+
+	url := strings.TrimSuffix(opts.URL, "/") + "/api/v2/reimport-scan/"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return xerrors.Errorf("unable to create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", "Token "+opts.APIKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return responseError(resp)
+	}
+	var res reimportResponse
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return xerrors.Errorf("unable to decode the response: %w", err)
+	}
+
+	log.Logger.Infof("Reimported the report to the DefectDojo product %q (test: %d, active findings: %d)",
+		product, res.Test, res.Statistics.After.Total.Active)
+	return nil
+}
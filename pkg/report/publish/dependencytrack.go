@@ -0,0 +1,85 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/report/cyclonedx"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// DependencyTrackOptions configures the BOM upload to Dependency-Track
+type DependencyTrackOptions struct {
+	URL            string
+	APIKey         string
+	Project        string // The artifact name if empty
+	ProjectVersion string
+}
+
+type bomUploadRequest struct {
+	ProjectName    string `json:"projectName"`
+	ProjectVersion string `json:"projectVersion,omitempty"`
+	AutoCreate     bool   `json:"autoCreate"`
+	BOM            string `json:"bom"`
+}
+
+type bomUploadResponse struct {
+	Token string `json:"token"`
+}
+
+// uploadBOM uploads the CycloneDX SBOM of the report, and the project is created if it doesn't exist.
+// Dependency-Track analyzes the SBOM asynchronously, and the token identifies the processing.
+// cf. https://docs.dependencytrack.org/usage/cicd/
+func uploadBOM(ctx context.Context, client *http.Client, report types.Report, appVersion string,
+	opts DependencyTrackOptions) error {
+	var bom bytes.Buffer
+	if err := cyclonedx.NewWriter(&bom, appVersion).Write(ctx, report); err != nil {
+		return xerrors.Errorf("CycloneDX error: %w", err)
+	}
+
+	project := opts.Project
+	if project == "" {
+		project = report.ArtifactName
+	}
+	body, err := json.Marshal(bomUploadRequest{
+		ProjectName:    project,
+		ProjectVersion: opts.ProjectVersion,
+		AutoCreate:     true,
+		BOM:            base64.StdEncoding.EncodeToString(bom.Bytes()),
+	})
+	if err != nil {
+		return xerrors.Errorf("json encode error: %w", err)
+	}
+
+	url := strings.TrimSuffix(opts.URL, "/") + "/api/v1/bom"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("unable to create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", opts.APIKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	var res bomUploadResponse
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return xerrors.Errorf("unable to decode the response: %w", err)
+	}
+
+	log.Logger.Infof("Uploaded the SBOM to the Dependency-Track project %q (token: %s)", project, res.Token)
+	return nil
+}
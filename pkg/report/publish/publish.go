@@ -0,0 +1,43 @@
+package publish
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const requestTimeout = 5 * time.Minute
+
+// Options holds the platforms the results are uploaded to. Each platform is skipped if its URL is empty.
+type Options struct {
+	AppVersion      string
+	DependencyTrack DependencyTrackOptions
+	DefectDojo      DefectDojoOptions
+}
+
+// Publish uploads the report to the configured vulnerability management platforms
+func Publish(ctx context.Context, report types.Report, opts Options) error {
+	client := &http.Client{Timeout: requestTimeout}
+	if opts.DependencyTrack.URL != "" {
+		if err := uploadBOM(ctx, client, report, opts.AppVersion, opts.DependencyTrack); err != nil {
+			return xerrors.Errorf("Dependency-Track error: %w", err)
+		}
+	}
+	if opts.DefectDojo.URL != "" {
+		if err := reimportScan(ctx, client, report, opts.DefectDojo); err != nil {
+			return xerrors.Errorf("DefectDojo error: %w", err)
+		}
+	}
+	return nil
+}
+
+// responseError returns the error with the status and the beginning of the body
+func responseError(resp *http.Response) error {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return xerrors.Errorf("%s: %s", resp.Status, string(b))
+}
@@ -0,0 +1,232 @@
+package publish_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/publish"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var report = types.Report{
+	SchemaVersion: 2,
+	ArtifactName:  "alpine:3.19",
+	ArtifactType:  ftypes.ArtifactContainerImage,
+	Metadata: types.Metadata{
+		OS: &ftypes.OS{
+			Family: ftypes.Alpine,
+			Name:   "3.19.0",
+		},
+	},
+	Results: types.Results{
+		{
+			Target: "alpine:3.19 (alpine 3.19.0)",
+			Class:  types.ClassOSPkg,
+			Type:   ftypes.Alpine,
+			Packages: []ftypes.Package{
+				{
+					ID:      "openssl@3.1.4-r2",
+					Name:    "openssl",
+					Version: "3.1.4-r2",
+				},
+			},
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2024-0727",
+					PkgID:            "openssl@3.1.4-r2",
+					PkgName:          "openssl",
+					InstalledVersion: "3.1.4-r2",
+					FixedVersion:     "3.1.4-r5",
+					Vulnerability: dbTypes.Vulnerability{
+						Severity: "MEDIUM",
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestPublish_DependencyTrack(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        publish.DependencyTrackOptions
+		status      int
+		wantProject string
+		wantErr     string
+	}{
+		{
+			name: "happy path",
+			opts: publish.DependencyTrackOptions{
+				APIKey:         "secret",
+				Project:        "my-app",
+				ProjectVersion: "1.0.0",
+			},
+			status:      http.StatusOK,
+			wantProject: "my-app",
+		},
+		{
+			name: "default project",
+			opts: publish.DependencyTrackOptions{
+				APIKey: "secret",
+			},
+			status:      http.StatusOK,
+			wantProject: "alpine:3.19",
+		},
+		{
+			name: "sad path: unauthorized",
+			opts: publish.DependencyTrackOptions{
+				APIKey: "invalid",
+			},
+			status:  http.StatusUnauthorized,
+			wantErr: "Dependency-Track error: 401 Unauthorized",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "/api/v1/bom", r.URL.Path)
+				assert.Equal(t, tt.opts.APIKey, r.Header.Get("X-Api-Key"))
+				if tt.status != http.StatusOK {
+					w.WriteHeader(tt.status)
+					return
+				}
+
+				var req struct {
+					ProjectName    string `json:"projectName"`
+					ProjectVersion string `json:"projectVersion"`
+					AutoCreate     bool   `json:"autoCreate"`
+					BOM            string `json:"bom"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, tt.wantProject, req.ProjectName)
+				assert.Equal(t, tt.opts.ProjectVersion, req.ProjectVersion)
+				assert.True(t, req.AutoCreate)
+
+				bom, err := base64.StdEncoding.DecodeString(req.BOM)
+				require.NoError(t, err)
+				assert.Contains(t, string(bom), `"bomFormat": "CycloneDX"`)
+				assert.Contains(t, string(bom), `"name": "openssl"`)
+
+				_, _ = w.Write([]byte(`{"token": "d8ee8cba-3b6c-4fd4-b2e4-0f6e2a2b8b2c"}`))
+			}))
+			defer ts.Close()
+
+			opts := tt.opts
+			opts.URL = ts.URL + "/"
+			err := publish.Publish(context.Background(), report, publish.Options{
+				AppVersion:      "dev",
+				DependencyTrack: opts,
+			})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPublish_DefectDojo(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       publish.DefectDojoOptions
+		status     int
+		wantFields map[string]string
+		wantErr    string
+	}{
+		{
+			name: "happy path",
+			opts: publish.DefectDojoOptions{
+				APIKey:      "secret",
+				Product:     "my-app",
+				ProductType: "Web",
+				Engagement:  "Trivy",
+			},
+			status: http.StatusCreated,
+			wantFields: map[string]string{
+				"scan_type":           "Trivy Scan",
+				"product_name":        "my-app",
+				"product_type_name":   "Web",
+				"engagement_name":     "Trivy",
+				"auto_create_context": "true",
+				"close_old_findings":  "true",
+			},
+		},
+		{
+			name: "default product",
+			opts: publish.DefectDojoOptions{
+				APIKey:     "secret",
+				Engagement: "Trivy",
+			},
+			status: http.StatusCreated,
+			wantFields: map[string]string{
+				"scan_type":           "Trivy Scan",
+				"product_name":        "alpine:3.19",
+				"product_type_name":   "",
+				"engagement_name":     "Trivy",
+				"auto_create_context": "true",
+				"close_old_findings":  "true",
+			},
+		},
+		{
+			name: "sad path: bad request",
+			opts: publish.DefectDojoOptions{
+				APIKey: "secret",
+			},
+			status:  http.StatusBadRequest,
+			wantErr: `DefectDojo error: 400 Bad Request: {"engagement_name": ["This field is required."]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/api/v2/reimport-scan/", r.URL.Path)
+				assert.Equal(t, "Token "+tt.opts.APIKey, r.Header.Get("Authorization"))
+				if tt.status != http.StatusCreated {
+					w.WriteHeader(tt.status)
+					_, _ = w.Write([]byte(`{"engagement_name": ["This field is required."]}`))
+					return
+				}
+
+				for k, v := range tt.wantFields {
+					assert.Equal(t, v, r.FormValue(k), k)
+				}
+				f, _, err := r.FormFile("file")
+				require.NoError(t, err)
+				b, err := io.ReadAll(f)
+				require.NoError(t, err)
+
+				var got types.Report
+				require.NoError(t, json.Unmarshal(b, &got))
+				assert.Equal(t, "CVE-2024-0727", got.Results[0].Vulnerabilities[0].VulnerabilityID)
+
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"test": 12, "statistics": {"after": {"total": {"active": 1}}}}`))
+			}))
+			defer ts.Close()
+
+			opts := tt.opts
+			opts.URL = ts.URL
+			err := publish.Publish(context.Background(), report, publish.Options{
+				DefectDojo: opts,
+			})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
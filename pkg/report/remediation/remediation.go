@@ -0,0 +1,216 @@
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Action represents an upgrade of a package fixing its vulnerabilities
+type Action struct {
+	PkgID            string
+	PkgName          string
+	PkgPath          string
+	InstalledVersion string
+
+	// FixedVersion is the lowest version fixing all the vulnerabilities.
+	// It is empty if some vulnerabilities are not fixed yet.
+	FixedVersion string
+
+	VulnerabilityIDs []string
+	Severities       map[string]int
+
+	// Indirect is true if the package is pulled in by other packages in the lock file.
+	Indirect bool
+
+	// DirectDependencies are the direct dependencies pulling in the indirect package,
+	// which need to be upgraded unless the version is overridden in the lock file.
+	DirectDependencies []string
+}
+
+// Plan represents the upgrade actions of a target
+type Plan struct {
+	Target  string
+	Actions []Action
+}
+
+// NewPlans aggregates the detected vulnerabilities into the minimal set of package upgrades.
+// Actions fixing more vulnerabilities come first, and packages with no fix come last.
+func NewPlans(report types.Report) []Plan {
+	var plans []Plan
+	for _, result := range report.Results {
+		actions := newActions(result)
+		if len(actions) == 0 {
+			continue
+		}
+		target := result.Target
+		if result.Class == types.ClassLangPkg {
+			target += fmt.Sprintf(" (%s)", result.Type)
+		}
+		plans = append(plans, Plan{
+			Target:  target,
+			Actions: actions,
+		})
+	}
+	return plans
+}
+
+func newActions(result types.Result) []Action {
+	var actions []*Action
+	pkgActions := make(map[string]*Action)
+	for _, vuln := range result.Vulnerabilities {
+		key := fmt.Sprintf("%s/%s/%s/%s", vuln.PkgID, vuln.PkgName, vuln.InstalledVersion, vuln.PkgPath)
+		action, ok := pkgActions[key]
+		if !ok {
+			action = &Action{
+				PkgID:            vuln.PkgID,
+				PkgName:          vuln.PkgName,
+				PkgPath:          vuln.PkgPath,
+				InstalledVersion: vuln.InstalledVersion,
+				FixedVersion:     vuln.PkgFixedVersion,
+				Severities:       make(map[string]int),
+			}
+			pkgActions[key] = action
+			actions = append(actions, action)
+		}
+		if slices.Contains(action.VulnerabilityIDs, vuln.VulnerabilityID) {
+			continue
+		}
+		action.VulnerabilityIDs = append(action.VulnerabilityIDs, vuln.VulnerabilityID)
+		action.Severities[vuln.Severity]++
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+
+	// The dependency graph is available when packages are listed
+	parents := ftypes.Packages(result.Packages).ParentDeps()
+	for _, action := range actions {
+		pkg, ok := lo.Find(result.Packages, func(pkg ftypes.Package) bool {
+			return action.PkgID != "" && pkg.ID == action.PkgID
+		})
+		if !ok || !pkg.Indirect {
+			continue
+		}
+		action.Indirect = true
+		action.DirectDependencies = directDependencies(pkg.ID, parents, make(map[string]struct{}))
+		sort.Strings(action.DirectDependencies)
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		fixed1, fixed2 := actions[i].FixedVersion != "", actions[j].FixedVersion != ""
+		if fixed1 != fixed2 {
+			return fixed1
+		}
+		return len(actions[i].VulnerabilityIDs) > len(actions[j].VulnerabilityIDs)
+	})
+	return lo.Map(actions, func(action *Action, _ int) Action {
+		return *action
+	})
+}
+
+// directDependencies returns the direct dependencies pulling in the package.
+// As with the dependency tree, a package with no parents is considered to be a direct dependency.
+func directDependencies(pkgID string, parents map[string]ftypes.Packages, seen map[string]struct{}) []string {
+	deps := make(map[string]struct{})
+	seen[pkgID] = struct{}{}
+	for _, parent := range parents[pkgID] {
+		if _, ok := seen[parent.ID]; ok {
+			continue
+		}
+		if !parent.Indirect || len(parents[parent.ID]) == 0 {
+			deps[parent.ID] = struct{}{}
+			continue
+		}
+		for _, dep := range directDependencies(parent.ID, parents, seen) {
+			deps[dep] = struct{}{}
+		}
+	}
+	return maps.Keys(deps)
+}
+
+// Writer writes the upgrade actions fixing the detected vulnerabilities, e.g.
+//
+//	bump lodash from 4.17.4 to 4.17.21, fixing 7 vulnerabilities (CRITICAL: 1, HIGH: 6)
+type Writer struct {
+	Output io.Writer
+}
+
+func (w Writer) Write(_ context.Context, report types.Report) error {
+	plans := NewPlans(report)
+	if len(plans) == 0 {
+		if _, err := fmt.Fprintln(w.Output, "No vulnerabilities to remediate"); err != nil {
+			return xerrors.Errorf("failed to write remediation plan: %w", err)
+		}
+		return nil
+	}
+
+	var b strings.Builder
+	for i, plan := range plans {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s\n%s\n", plan.Target, strings.Repeat("=", len(plan.Target)))
+
+		for _, action := range plan.Actions {
+			b.WriteString(formatAction(action))
+		}
+	}
+
+	if _, err := io.WriteString(w.Output, b.String()); err != nil {
+		return xerrors.Errorf("failed to write remediation plan: %w", err)
+	}
+	return nil
+}
+
+func formatAction(action Action) string {
+	name := action.PkgName
+	if action.PkgPath != "" {
+		name = fmt.Sprintf("%s (%s)", action.PkgName, action.PkgPath)
+	}
+	vulns := fmt.Sprintf("%d %s (%s)", len(action.VulnerabilityIDs),
+		lo.Ternary(len(action.VulnerabilityIDs) == 1, "vulnerability", "vulnerabilities"),
+		summarize(action.Severities))
+
+	var s string
+	if action.FixedVersion == "" {
+		s = fmt.Sprintf("- no fix for %s %s yet: %s\n", name, action.InstalledVersion, vulns)
+	} else {
+		s = fmt.Sprintf("- bump %s from %s to %s, fixing %s\n", name, action.InstalledVersion, action.FixedVersion, vulns)
+	}
+	s += fmt.Sprintf("    %s\n", strings.Join(action.VulnerabilityIDs, ", "))
+
+	if action.Indirect && action.FixedVersion != "" {
+		if len(action.DirectDependencies) > 0 {
+			s += fmt.Sprintf("    indirect dependency: upgrade %s or override the version in the lock file\n",
+				strings.Join(action.DirectDependencies, ", "))
+		} else {
+			s += "    indirect dependency: override the version in the lock file\n"
+		}
+	}
+	return s
+}
+
+// summarize returns the number of vulnerabilities by severity, e.g. "CRITICAL: 1, HIGH: 2"
+func summarize(severities map[string]int) string {
+	var summaries []string
+	// The most severe first
+	for i := len(dbTypes.SeverityNames) - 1; i >= 0; i-- {
+		severity := dbTypes.SeverityNames[i]
+		if count := severities[severity]; count > 0 {
+			summaries = append(summaries, fmt.Sprintf("%s: %d", severity, count))
+		}
+	}
+	return strings.Join(summaries, ", ")
+}
@@ -0,0 +1,189 @@
+package remediation_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/remediation"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		results types.Results
+		want    string
+	}{
+		{
+			name: "lock file",
+			results: types.Results{
+				{
+					Target: "package-lock.json",
+					Class:  types.ClassLangPkg,
+					Type:   ftypes.Npm,
+					Packages: []ftypes.Package{
+						{
+							ID:        "app@1.0.0",
+							Name:      "app",
+							Version:   "1.0.0",
+							DependsOn: []string{"express@4.17.1", "lodash@4.17.4"},
+						},
+						{
+							ID:        "express@4.17.1",
+							Name:      "express",
+							Version:   "4.17.1",
+							DependsOn: []string{"qs@6.7.0"},
+						},
+						{
+							ID:       "qs@6.7.0",
+							Name:     "qs",
+							Version:  "6.7.0",
+							Indirect: true,
+						},
+						{
+							ID:      "lodash@4.17.4",
+							Name:    "lodash",
+							Version: "4.17.4",
+						},
+						{
+							ID:      "ip@2.0.0",
+							Name:    "ip",
+							Version: "2.0.0",
+						},
+					},
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2022-24999",
+							PkgID:            "qs@6.7.0",
+							PkgName:          "qs",
+							InstalledVersion: "6.7.0",
+							FixedVersion:     "6.7.3",
+							PkgFixedVersion:  "6.7.3",
+							Vulnerability:    vuln("HIGH"),
+						},
+						{
+							VulnerabilityID:  "CVE-2018-16487",
+							PkgID:            "lodash@4.17.4",
+							PkgName:          "lodash",
+							InstalledVersion: "4.17.4",
+							FixedVersion:     "4.17.11",
+							PkgFixedVersion:  "4.17.21",
+							Vulnerability:    vuln("MEDIUM"),
+						},
+						{
+							VulnerabilityID:  "CVE-2019-10744",
+							PkgID:            "lodash@4.17.4",
+							PkgName:          "lodash",
+							InstalledVersion: "4.17.4",
+							FixedVersion:     "4.17.12",
+							PkgFixedVersion:  "4.17.21",
+							Vulnerability:    vuln("CRITICAL"),
+						},
+						{
+							VulnerabilityID:  "CVE-2021-23337",
+							PkgID:            "lodash@4.17.4",
+							PkgName:          "lodash",
+							InstalledVersion: "4.17.4",
+							FixedVersion:     "4.17.21",
+							PkgFixedVersion:  "4.17.21",
+							Vulnerability:    vuln("HIGH"),
+						},
+						{
+							VulnerabilityID:  "CVE-2023-42282",
+							PkgID:            "ip@2.0.0",
+							PkgName:          "ip",
+							InstalledVersion: "2.0.0",
+							Vulnerability:    vuln("LOW"),
+						},
+					},
+				},
+			},
+			want: `package-lock.json (npm)
+=======================
+- bump lodash from 4.17.4 to 4.17.21, fixing 3 vulnerabilities (CRITICAL: 1, HIGH: 1, MEDIUM: 1)
+    CVE-2018-16487, CVE-2019-10744, CVE-2021-23337
+- bump qs from 6.7.0 to 6.7.3, fixing 1 vulnerability (HIGH: 1)
+    CVE-2022-24999
+    indirect dependency: upgrade express@4.17.1 or override the version in the lock file
+- no fix for ip 2.0.0 yet: 1 vulnerability (LOW: 1)
+    CVE-2023-42282
+`,
+		},
+		{
+			name: "OS packages and JAR files",
+			results: types.Results{
+				{
+					Target: "alpine:3.19 (alpine 3.19.1)",
+					Class:  types.ClassOSPkg,
+					Type:   ftypes.Alpine,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2024-0727",
+							PkgName:          "libcrypto3",
+							InstalledVersion: "3.1.4-r4",
+							FixedVersion:     "3.1.4-r5",
+							PkgFixedVersion:  "3.1.4-r5",
+							Vulnerability:    vuln("MEDIUM"),
+						},
+					},
+				},
+				{
+					Target: "Java",
+					Class:  types.ClassLangPkg,
+					Type:   ftypes.Jar,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2021-44228",
+							PkgName:          "org.apache.logging.log4j:log4j-core",
+							PkgPath:          "app/log4j-core-2.14.1.jar",
+							InstalledVersion: "2.14.1",
+							FixedVersion:     "2.15.0",
+							PkgFixedVersion:  "2.15.0",
+							Vulnerability:    vuln("CRITICAL"),
+						},
+					},
+				},
+			},
+			want: `alpine:3.19 (alpine 3.19.1)
+===========================
+- bump libcrypto3 from 3.1.4-r4 to 3.1.4-r5, fixing 1 vulnerability (MEDIUM: 1)
+    CVE-2024-0727
+
+Java (jar)
+==========
+- bump org.apache.logging.log4j:log4j-core (app/log4j-core-2.14.1.jar) from 2.14.1 to 2.15.0, fixing 1 vulnerability (CRITICAL: 1)
+    CVE-2021-44228
+`,
+		},
+		{
+			name: "no vulnerabilities",
+			results: types.Results{
+				{
+					Target: "go.mod",
+					Class:  types.ClassLangPkg,
+					Type:   ftypes.GoModule,
+				},
+			},
+			want: "No vulnerabilities to remediate\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			w := remediation.Writer{Output: buf}
+			err := w.Write(context.Background(), types.Report{Results: tt.results})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func vuln(severity string) dbTypes.Vulnerability {
+	return dbTypes.Vulnerability{Severity: severity}
+}
@@ -1,7 +1,9 @@
 package report
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"html"
 	"io"
@@ -14,6 +16,7 @@ import (
 	"golang.org/x/xerrors"
 
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/github"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -32,6 +35,13 @@ const (
 
 	columnKind = "utf16CodeUnits"
 
+	// sarifFingerprintKey is the key of "partialFingerprints".
+	// The version must be bumped when the fingerprint is changed, as the results are no longer matched with the previous ones.
+	sarifFingerprintKey = "trivyFingerprint/v1"
+
+	sarifBaselineNew       = "new"
+	sarifBaselineUnchanged = "unchanged"
+
 	builtinRulesUrl = "https://github.com/aquasecurity/trivy/blob/main/pkg/fanal/secret/builtin-rules.go" // list all secrets
 )
 
@@ -49,6 +59,16 @@ type SarifWriter struct {
 	run           *sarif.Run
 	locationCache map[string][]location
 	Target        string
+
+	// Upload uploads the SARIF to the GitHub code scanning API as well
+	Upload bool
+
+	// ShowSuppressed writes the findings suppressed by the ignore file and VEX with "suppressions"
+	ShowSuppressed bool
+
+	// Baseline is the path to the SARIF of a previous scan.
+	// The results are marked "new" or "unchanged" in "baselineState" by comparing the fingerprints.
+	Baseline string
 }
 
 type sarifData struct {
@@ -67,6 +87,8 @@ type sarifData struct {
 	message          string
 	cvssScore        string
 	locations        []location
+	fingerprint      string
+	suppression      *sarif.Suppression
 }
 
 type location struct {
@@ -108,7 +130,13 @@ func (sw *SarifWriter) addSarifResult(data *sarifData) {
 		WithRuleIndex(data.resultIndex).
 		WithMessage(sarif.NewTextMessage(data.message)).
 		WithLevel(toSarifErrorLevel(data.severity)).
-		WithLocations(toSarifLocations(data.locations, data.artifactLocation, data.locationMessage))
+		WithLocations(toSarifLocations(data.locations, data.artifactLocation, data.locationMessage)).
+		WithPartialFingerPrints(map[string]interface{}{
+			sarifFingerprintKey: data.fingerprint,
+		})
+	if data.suppression != nil {
+		result.WithSuppression([]*sarif.Suppression{data.suppression})
+	}
 	sw.run.AddResult(result)
 }
 
@@ -145,122 +173,230 @@ func (sw *SarifWriter) Write(ctx context.Context, report types.Report) error {
 
 	ruleIndexes := make(map[string]int)
 	for _, res := range report.Results {
-		target := ToPathUri(res.Target, res.Class)
-
-		for _, vuln := range res.Vulnerabilities {
-			fullDescription := vuln.Description
-			if fullDescription == "" {
-				fullDescription = vuln.Title
-			}
-			path := target
-			if vuln.PkgPath != "" {
-				path = ToPathUri(vuln.PkgPath, res.Class)
-			}
-			sw.addSarifResult(&sarifData{
-				title:            "vulnerability",
-				vulnerabilityId:  vuln.VulnerabilityID,
-				severity:         vuln.Severity,
-				cvssScore:        getCVSSScore(vuln),
-				url:              vuln.PrimaryURL,
-				resourceClass:    res.Class,
-				artifactLocation: path,
-				locationMessage:  fmt.Sprintf("%v: %v@%v", path, vuln.PkgName, vuln.InstalledVersion),
-				locations:        sw.getLocations(vuln.PkgName, vuln.InstalledVersion, path, res.Packages),
-				resultIndex:      getRuleIndex(vuln.VulnerabilityID, ruleIndexes),
-				shortDescription: html.EscapeString(vuln.Title),
-				fullDescription:  html.EscapeString(fullDescription),
-				helpText: fmt.Sprintf("Vulnerability %v\nSeverity: %v\nPackage: %v\nFixed Version: %v\nLink: [%v](%v)\n%v",
-					vuln.VulnerabilityID, vuln.Severity, vuln.PkgName, vuln.FixedVersion, vuln.VulnerabilityID, vuln.PrimaryURL, vuln.Description),
-				helpMarkdown: fmt.Sprintf("**Vulnerability %v**\n| Severity | Package | Fixed Version | Link |\n| --- | --- | --- | --- |\n|%v|%v|%v|[%v](%v)|\n\n%v",
-					vuln.VulnerabilityID, vuln.Severity, vuln.PkgName, vuln.FixedVersion, vuln.VulnerabilityID, vuln.PrimaryURL, vuln.Description),
-				message: fmt.Sprintf("Package: %v\nInstalled Version: %v\nVulnerability %v\nSeverity: %v\nFixed Version: %v\nLink: [%v](%v)",
-					vuln.PkgName, vuln.InstalledVersion, vuln.VulnerabilityID, vuln.Severity, vuln.FixedVersion, vuln.VulnerabilityID, vuln.PrimaryURL),
-			})
-		}
-		for _, misconf := range res.Misconfigurations {
-			sw.addSarifResult(&sarifData{
-				title:            "misconfiguration",
-				vulnerabilityId:  misconf.ID,
-				severity:         misconf.Severity,
-				cvssScore:        severityToScore(misconf.Severity),
-				url:              misconf.PrimaryURL,
-				resourceClass:    res.Class,
-				artifactLocation: target,
-				locationMessage:  target,
-				locations: []location{
-					{
-						startLine: misconf.CauseMetadata.StartLine,
-						endLine:   misconf.CauseMetadata.EndLine,
-					},
-				},
-				resultIndex:      getRuleIndex(misconf.ID, ruleIndexes),
-				shortDescription: html.EscapeString(misconf.Title),
-				fullDescription:  html.EscapeString(misconf.Description),
-				helpText: fmt.Sprintf("Misconfiguration %v\nType: %s\nSeverity: %v\nCheck: %v\nMessage: %v\nLink: [%v](%v)\n%s",
-					misconf.ID, misconf.Type, misconf.Severity, misconf.Title, misconf.Message, misconf.ID, misconf.PrimaryURL, misconf.Description),
-				helpMarkdown: fmt.Sprintf("**Misconfiguration %v**\n| Type | Severity | Check | Message | Link |\n| --- | --- | --- | --- | --- |\n|%v|%v|%v|%s|[%v](%v)|\n\n%v",
-					misconf.ID, misconf.Type, misconf.Severity, misconf.Title, misconf.Message, misconf.ID, misconf.PrimaryURL, misconf.Description),
-				message: fmt.Sprintf("Artifact: %v\nType: %v\nVulnerability %v\nSeverity: %v\nMessage: %v\nLink: [%v](%v)",
-					res.Target, res.Type, misconf.ID, misconf.Severity, misconf.Message, misconf.ID, misconf.PrimaryURL),
-			})
+		sw.addResults(res, ruleIndexes, nil)
+		if !sw.ShowSuppressed {
+			continue
 		}
-		for _, secret := range res.Secrets {
-			sw.addSarifResult(&sarifData{
-				title:            "secret",
-				vulnerabilityId:  secret.RuleID,
-				severity:         secret.Severity,
-				cvssScore:        severityToScore(secret.Severity),
-				url:              builtinRulesUrl,
-				resourceClass:    res.Class,
-				artifactLocation: target,
-				locationMessage:  target,
-				locations: []location{
-					{
-						startLine: secret.StartLine,
-						endLine:   secret.EndLine,
-					},
-				},
-				resultIndex:      getRuleIndex(secret.RuleID, ruleIndexes),
-				shortDescription: html.EscapeString(secret.Title),
-				fullDescription:  html.EscapeString(secret.Match),
-				helpText: fmt.Sprintf("Secret %v\nSeverity: %v\nMatch: %s",
-					secret.Title, secret.Severity, secret.Match),
-				helpMarkdown: fmt.Sprintf("**Secret %v**\n| Severity | Match |\n| --- | --- |\n|%v|%v|",
-					secret.Title, secret.Severity, secret.Match),
-				message: fmt.Sprintf("Artifact: %v\nType: %v\nSecret %v\nSeverity: %v\nMatch: %v",
-					res.Target, res.Type, secret.Title, secret.Severity, secret.Match),
-			})
-		}
-		for _, license := range res.Licenses {
-			id := fmt.Sprintf("%s:%s", license.PkgName, license.Name)
-			desc := fmt.Sprintf("%s in %s", license.Name, license.PkgName)
-			sw.addSarifResult(&sarifData{
-				title:            "license",
-				vulnerabilityId:  id,
-				severity:         license.Severity,
-				cvssScore:        severityToScore(license.Severity),
-				url:              license.Link,
-				resourceClass:    res.Class,
-				artifactLocation: target,
-				resultIndex:      getRuleIndex(id, ruleIndexes),
-				shortDescription: desc,
-				fullDescription:  desc,
-				helpText: fmt.Sprintf("License %s\nClassification: %s\nPkgName: %s\nPath: %s",
-					license.Name, license.Category, license.PkgName, license.FilePath),
-				helpMarkdown: fmt.Sprintf("**License %s**\n| PkgName | Classification | Path |\n| --- | --- | --- |\n|%s|%s|%s|",
-					license.Name, license.PkgName, license.Category, license.FilePath),
-				message: fmt.Sprintf("Artifact: %s\nLicense %s\nPkgName: %s\n Classification: %s\n Path: %s",
-					res.Target, license.Name, license.Category, license.PkgName, license.FilePath),
-			})
+		// Suppressed findings are written with "suppressions" so that code scanning platforms can close them
+		for _, modified := range res.ModifiedFindings {
+			sw.addResults(suppressedResult(res, modified), ruleIndexes, toSarifSuppression(res.Target, modified))
 		}
-
+	}
+	if err = sw.markBaseline(); err != nil {
+		return err
 	}
 	sw.run.ColumnKind = columnKind
 	sw.run.OriginalUriBaseIDs = map[string]*sarif.ArtifactLocation{
 		"ROOTPATH": {URI: &rootPath},
 	}
 	sarifReport.AddRun(sw.run)
-	return sarifReport.PrettyWrite(sw.Output)
+	if !sw.Upload {
+		return sarifReport.PrettyWrite(sw.Output)
+	}
+
+	var buf bytes.Buffer
+	if err = sarifReport.PrettyWrite(io.MultiWriter(sw.Output, &buf)); err != nil {
+		return err
+	}
+	if err = github.UploadSARIF(ctx, buf.Bytes()); err != nil {
+		return xerrors.Errorf("failed to upload the SARIF to GitHub code scanning: %w", err)
+	}
+	return nil
+}
+
+// addResults adds the findings of the result. "suppression" is set if they are suppressed.
+func (sw *SarifWriter) addResults(res types.Result, ruleIndexes map[string]int, suppression *sarif.Suppression) {
+	target := ToPathUri(res.Target, res.Class)
+
+	for _, vuln := range res.Vulnerabilities {
+		fullDescription := vuln.Description
+		if fullDescription == "" {
+			fullDescription = vuln.Title
+		}
+		path := target
+		if vuln.PkgPath != "" {
+			path = ToPathUri(vuln.PkgPath, res.Class)
+		}
+		sw.addSarifResult(&sarifData{
+			title:            "vulnerability",
+			vulnerabilityId:  vuln.VulnerabilityID,
+			fingerprint:      fingerprint(path, vuln.PkgName, vuln.VulnerabilityID),
+			suppression:      suppression,
+			severity:         vuln.Severity,
+			cvssScore:        getCVSSScore(vuln),
+			url:              vuln.PrimaryURL,
+			resourceClass:    res.Class,
+			artifactLocation: path,
+			locationMessage:  fmt.Sprintf("%v: %v@%v", path, vuln.PkgName, vuln.InstalledVersion),
+			locations:        sw.getLocations(vuln.PkgName, vuln.InstalledVersion, path, res.Packages),
+			resultIndex:      getRuleIndex(vuln.VulnerabilityID, ruleIndexes),
+			shortDescription: html.EscapeString(vuln.Title),
+			fullDescription:  html.EscapeString(fullDescription),
+			helpText: fmt.Sprintf("Vulnerability %v\nSeverity: %v\nPackage: %v\nFixed Version: %v\nLink: [%v](%v)\n%v",
+				vuln.VulnerabilityID, vuln.Severity, vuln.PkgName, vuln.FixedVersion, vuln.VulnerabilityID, vuln.PrimaryURL, vuln.Description),
+			helpMarkdown: fmt.Sprintf("**Vulnerability %v**\n| Severity | Package | Fixed Version | Link |\n| --- | --- | --- | --- |\n|%v|%v|%v|[%v](%v)|\n\n%v",
+				vuln.VulnerabilityID, vuln.Severity, vuln.PkgName, vuln.FixedVersion, vuln.VulnerabilityID, vuln.PrimaryURL, vuln.Description),
+			message: fmt.Sprintf("Package: %v\nInstalled Version: %v\nVulnerability %v\nSeverity: %v\nFixed Version: %v\nLink: [%v](%v)",
+				vuln.PkgName, vuln.InstalledVersion, vuln.VulnerabilityID, vuln.Severity, vuln.FixedVersion, vuln.VulnerabilityID, vuln.PrimaryURL),
+		})
+	}
+	for _, misconf := range res.Misconfigurations {
+		sw.addSarifResult(&sarifData{
+			title:            "misconfiguration",
+			vulnerabilityId:  misconf.ID,
+			fingerprint:      fingerprint(target, misconf.ID, misconf.CauseMetadata.Resource, misconf.Message),
+			suppression:      suppression,
+			severity:         misconf.Severity,
+			cvssScore:        severityToScore(misconf.Severity),
+			url:              misconf.PrimaryURL,
+			resourceClass:    res.Class,
+			artifactLocation: target,
+			locationMessage:  target,
+			locations: []location{
+				{
+					startLine: misconf.CauseMetadata.StartLine,
+					endLine:   misconf.CauseMetadata.EndLine,
+				},
+			},
+			resultIndex:      getRuleIndex(misconf.ID, ruleIndexes),
+			shortDescription: html.EscapeString(misconf.Title),
+			fullDescription:  html.EscapeString(misconf.Description),
+			helpText: fmt.Sprintf("Misconfiguration %v\nType: %s\nSeverity: %v\nCheck: %v\nMessage: %v\nLink: [%v](%v)\n%s",
+				misconf.ID, misconf.Type, misconf.Severity, misconf.Title, misconf.Message, misconf.ID, misconf.PrimaryURL, misconf.Description),
+			helpMarkdown: fmt.Sprintf("**Misconfiguration %v**\n| Type | Severity | Check | Message | Link |\n| --- | --- | --- | --- | --- |\n|%v|%v|%v|%s|[%v](%v)|\n\n%v",
+				misconf.ID, misconf.Type, misconf.Severity, misconf.Title, misconf.Message, misconf.ID, misconf.PrimaryURL, misconf.Description),
+			message: fmt.Sprintf("Artifact: %v\nType: %v\nVulnerability %v\nSeverity: %v\nMessage: %v\nLink: [%v](%v)",
+				res.Target, res.Type, misconf.ID, misconf.Severity, misconf.Message, misconf.ID, misconf.PrimaryURL),
+		})
+	}
+	for _, secret := range res.Secrets {
+		sw.addSarifResult(&sarifData{
+			title:            "secret",
+			vulnerabilityId:  secret.RuleID,
+			fingerprint:      fingerprint(target, secret.RuleID, secret.Match),
+			suppression:      suppression,
+			severity:         secret.Severity,
+			cvssScore:        severityToScore(secret.Severity),
+			url:              builtinRulesUrl,
+			resourceClass:    res.Class,
+			artifactLocation: target,
+			locationMessage:  target,
+			locations: []location{
+				{
+					startLine: secret.StartLine,
+					endLine:   secret.EndLine,
+				},
+			},
+			resultIndex:      getRuleIndex(secret.RuleID, ruleIndexes),
+			shortDescription: html.EscapeString(secret.Title),
+			fullDescription:  html.EscapeString(secret.Match),
+			helpText: fmt.Sprintf("Secret %v\nSeverity: %v\nMatch: %s",
+				secret.Title, secret.Severity, secret.Match),
+			helpMarkdown: fmt.Sprintf("**Secret %v**\n| Severity | Match |\n| --- | --- |\n|%v|%v|",
+				secret.Title, secret.Severity, secret.Match),
+			message: fmt.Sprintf("Artifact: %v\nType: %v\nSecret %v\nSeverity: %v\nMatch: %v",
+				res.Target, res.Type, secret.Title, secret.Severity, secret.Match),
+		})
+	}
+	for _, license := range res.Licenses {
+		id := fmt.Sprintf("%s:%s", license.PkgName, license.Name)
+		desc := fmt.Sprintf("%s in %s", license.Name, license.PkgName)
+		sw.addSarifResult(&sarifData{
+			title:            "license",
+			vulnerabilityId:  id,
+			fingerprint:      fingerprint(target, id),
+			suppression:      suppression,
+			severity:         license.Severity,
+			cvssScore:        severityToScore(license.Severity),
+			url:              license.Link,
+			resourceClass:    res.Class,
+			artifactLocation: target,
+			resultIndex:      getRuleIndex(id, ruleIndexes),
+			shortDescription: desc,
+			fullDescription:  desc,
+			helpText: fmt.Sprintf("License %s\nClassification: %s\nPkgName: %s\nPath: %s",
+				license.Name, license.Category, license.PkgName, license.FilePath),
+			helpMarkdown: fmt.Sprintf("**License %s**\n| PkgName | Classification | Path |\n| --- | --- | --- |\n|%s|%s|%s|",
+				license.Name, license.PkgName, license.Category, license.FilePath),
+			message: fmt.Sprintf("Artifact: %s\nLicense %s\nPkgName: %s\n Classification: %s\n Path: %s",
+				res.Target, license.Name, license.Category, license.PkgName, license.FilePath),
+		})
+	}
+}
+
+// fingerprint returns the hash identifying a finding across scans.
+// It must not contain line numbers and versions, which change without changing the finding.
+func fingerprint(parts ...string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(parts, "\x00"))))
+}
+
+// suppressedResult returns the result only with the suppressed finding
+func suppressedResult(res types.Result, modified types.ModifiedFinding) types.Result {
+	suppressed := types.Result{
+		Target:   res.Target,
+		Class:    res.Class,
+		Type:     res.Type,
+		Packages: res.Packages,
+	}
+	switch f := modified.Finding.(type) {
+	case types.DetectedVulnerability:
+		suppressed.Vulnerabilities = []types.DetectedVulnerability{f}
+	case types.DetectedMisconfiguration:
+		suppressed.Misconfigurations = []types.DetectedMisconfiguration{f}
+	case types.DetectedSecret:
+		suppressed.Secrets = []types.DetectedSecret{f}
+	case types.DetectedLicense:
+		suppressed.Licenses = []types.DetectedLicense{f}
+	}
+	return suppressed
+}
+
+// toSarifSuppression converts the modified finding into the suppression.
+// The ignore file and VEX documents are outside the scanned code, so the kind is "external",
+// while suppression comments have "<target>:<line>" as the source and the kind is "inSource".
+func toSarifSuppression(target string, modified types.ModifiedFinding) *sarif.Suppression {
+	kind := "external"
+	if strings.HasPrefix(modified.Source, target+":") {
+		kind = "inSource"
+	}
+	suppression := sarif.NewSuppression(kind).WithStatus("accepted")
+	justification := modified.Statement
+	if modified.Source != "" {
+		justification = strings.TrimSpace(fmt.Sprintf("%s (%s)", justification, modified.Source))
+	}
+	if justification != "" {
+		suppression.WithJustifcation(justification)
+	}
+	return suppression
+}
+
+// markBaseline sets "baselineState" of the results by comparing the fingerprints with the baseline
+func (sw *SarifWriter) markBaseline() error {
+	if sw.Baseline == "" {
+		return nil
+	}
+	baseline, err := sarif.Open(sw.Baseline)
+	if err != nil {
+		return xerrors.Errorf("unable to open the SARIF baseline %s: %w", sw.Baseline, err)
+	}
+
+	known := make(map[string]struct{})
+	for _, run := range baseline.Runs {
+		for _, result := range run.Results {
+			if fp, ok := result.PartialFingerprints[sarifFingerprintKey].(string); ok {
+				known[fp] = struct{}{}
+			}
+		}
+	}
+
+	for _, result := range sw.run.Results {
+		state := sarifBaselineNew
+		if _, ok := known[result.PartialFingerprints[sarifFingerprintKey].(string)]; ok {
+			state = sarifBaselineUnchanged
+		}
+		result.WithBaselineState(state)
+	}
+	return nil
 }
 
 func toSarifLocations(locations []location, artifactLocation, locationMessage string) []*sarif.Location {
@@ -364,9 +500,12 @@ func (sw *SarifWriter) getLocations(name, version, path string, pkgs []ftypes.Pa
 
 func getCVSSScore(vuln types.DetectedVulnerability) string {
 	// Take the vendor score
-	if cvss, ok := vuln.CVSS[vuln.SeveritySource]; ok {
+	if cvss, ok := vuln.CVSS[vuln.SeveritySource]; ok && cvss.V3Score != 0 {
 		return fmt.Sprintf("%.1f", cvss.V3Score)
 	}
+	if cvss, ok := vuln.CVSSv40[vuln.SeveritySource]; ok {
+		return fmt.Sprintf("%.1f", cvss.V40Score)
+	}
 
 	// Converts severity to score
 	return severityToScore(vuln.Severity)
@@ -3,11 +3,14 @@ package report_test
 import (
 	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/owenrumney/go-sarif/v2/sarif"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
@@ -131,6 +134,9 @@ func TestReportWriter_Sarif(t *testing.T) {
 								RuleIndex: lo.ToPtr[uint](0),
 								Level:     lo.ToPtr("error"),
 								Message:   sarif.Message{Text: lo.ToPtr("Package: foo\nInstalled Version: 1.2.3\nVulnerability CVE-2020-0001\nSeverity: HIGH\nFixed Version: 3.4.5\nLink: [CVE-2020-0001](https://avd.aquasec.com/nvd/cve-2020-0001)")},
+								PartialFingerprints: map[string]interface{}{
+									"trivyFingerprint/v1": "8c13d30ae9747ee49f09cfc0b2b3d788ede0f06cc300c5d159e0cdb6b952a988",
+								},
 								Locations: []*sarif.Location{
 									{
 										Message: &sarif.Message{Text: lo.ToPtr("library/test: foo@1.2.3")},
@@ -279,6 +285,9 @@ func TestReportWriter_Sarif(t *testing.T) {
 								RuleIndex: lo.ToPtr[uint](0),
 								Level:     lo.ToPtr("error"),
 								Message:   sarif.Message{Text: lo.ToPtr("Artifact: library/test\nType: \nVulnerability KSV001\nSeverity: HIGH\nMessage: Message\nLink: [KSV001](https://avd.aquasec.com/appshield/ksv001)")},
+								PartialFingerprints: map[string]interface{}{
+									"trivyFingerprint/v1": "4917c1210c27d2fbae876d7d3fc7941cc84e62c2a0b25526cc9271285beaeb8a",
+								},
 								Locations: []*sarif.Location{
 									{
 										Message: &sarif.Message{Text: lo.ToPtr("library/test")},
@@ -302,6 +311,9 @@ func TestReportWriter_Sarif(t *testing.T) {
 								RuleIndex: lo.ToPtr[uint](1),
 								Level:     lo.ToPtr("error"),
 								Message:   sarif.Message{Text: lo.ToPtr("Artifact: library/test\nType: \nVulnerability KSV002\nSeverity: CRITICAL\nMessage: Message\nLink: [KSV002](https://avd.aquasec.com/appshield/ksv002)")},
+								PartialFingerprints: map[string]interface{}{
+									"trivyFingerprint/v1": "7b8b4a3225a6c14315e0e3264e541bf3d05d96e897b67e75b734d30d0541bf46",
+								},
 								Locations: []*sarif.Location{
 									{
 										Message: &sarif.Message{Text: lo.ToPtr("library/test")},
@@ -396,6 +408,9 @@ func TestReportWriter_Sarif(t *testing.T) {
 								RuleIndex: lo.ToPtr[uint](0),
 								Level:     lo.ToPtr("error"),
 								Message:   sarif.Message{Text: lo.ToPtr("Artifact: library/test\nType: \nSecret AWS Secret Access Key\nSeverity: CRITICAL\nMatch: 'AWS_secret_KEY'=\"****************************************\"")},
+								PartialFingerprints: map[string]interface{}{
+									"trivyFingerprint/v1": "a1a661fd0c95279ab97564e88d1766e36ff71a06bcbc039c89ba41460d5a5ee4",
+								},
 								Locations: []*sarif.Location{
 									{
 										Message: &sarif.Message{Text: lo.ToPtr("library/test")},
@@ -485,6 +500,9 @@ func TestReportWriter_Sarif(t *testing.T) {
 								RuleIndex: lo.ToPtr(uint(0)),
 								Level:     lo.ToPtr("error"),
 								Message:   sarif.Message{Text: lo.ToPtr("Artifact: OS Packages\nLicense GPL-3.0\nPkgName: restricted\n Classification: alpine-base\n Path: ")},
+								PartialFingerprints: map[string]interface{}{
+									"trivyFingerprint/v1": "141cf1108c6b1d1b5e5e1f066b3cc9b43e0f3065700cd40140c8ae32abbf21e3",
+								},
 								Locations: []*sarif.Location{
 									{
 										Message: sarif.NewTextMessage(""),
@@ -600,3 +618,143 @@ func TestToPathUri(t *testing.T) {
 		}
 	}
 }
+
+func TestReportWriter_Sarif_Suppressions(t *testing.T) {
+	vuln := types.DetectedVulnerability{
+		VulnerabilityID:  "CVE-2020-0001",
+		PkgName:          "foo",
+		InstalledVersion: "1.2.3",
+		Vulnerability: dbTypes.Vulnerability{
+			Severity: "HIGH",
+		},
+	}
+	secret := types.DetectedSecret{
+		RuleID:    "aws-access-key-id",
+		Title:     "AWS Access Key ID",
+		Severity:  "CRITICAL",
+		StartLine: 3,
+		EndLine:   3,
+	}
+	input := types.Report{
+		Results: types.Results{
+			{
+				Target:          "package-lock.json",
+				Class:           types.ClassLangPkg,
+				Type:            ftypes.Npm,
+				Vulnerabilities: []types.DetectedVulnerability{vuln},
+				ModifiedFindings: []types.ModifiedFinding{
+					types.NewModifiedFinding(vuln, types.FindingStatusNotAffected, "vulnerable_code_not_in_execute_path", "OpenVEX"),
+				},
+			},
+			{
+				Target: "config.yaml",
+				Class:  types.ClassSecret,
+				ModifiedFindings: []types.ModifiedFinding{
+					types.NewModifiedFinding(secret, types.FindingStatusIgnored, "Documentation example", "config.yaml:2"),
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		showSuppressed bool
+		want           [][]*sarif.Suppression
+	}{
+		{
+			name: "suppressed findings are not written",
+			want: [][]*sarif.Suppression{nil},
+		},
+		{
+			name:           "show suppressed findings",
+			showSuppressed: true,
+			want: [][]*sarif.Suppression{
+				nil,
+				{
+					{
+						Kind:          "external",
+						Status:        lo.ToPtr("accepted"),
+						Justification: lo.ToPtr("vulnerable_code_not_in_execute_path (OpenVEX)"),
+					},
+				},
+				{
+					{
+						Kind:          "inSource",
+						Status:        lo.ToPtr("accepted"),
+						Justification: lo.ToPtr("Documentation example (config.yaml:2)"),
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sarifWritten := bytes.NewBuffer(nil)
+			w := report.SarifWriter{
+				Output:         sarifWritten,
+				ShowSuppressed: tt.showSuppressed,
+			}
+			require.NoError(t, w.Write(nil, input))
+
+			got, err := sarif.FromBytes(sarifWritten.Bytes())
+			require.NoError(t, err)
+			require.Len(t, got.Runs[0].Results, len(tt.want))
+			for i, want := range tt.want {
+				assert.Equal(t, want, got.Runs[0].Results[i].Suppressions, i)
+			}
+			if tt.showSuppressed {
+				// The suppressed vulnerability has the same fingerprint as the unsuppressed one would have
+				assert.Equal(t, got.Runs[0].Results[0].PartialFingerprints, got.Runs[0].Results[1].PartialFingerprints)
+			}
+		})
+	}
+}
+
+func TestReportWriter_Sarif_Baseline(t *testing.T) {
+	newReport := func(vulnIDs ...string) types.Report {
+		return types.Report{
+			Results: types.Results{
+				{
+					Target: "package-lock.json",
+					Class:  types.ClassLangPkg,
+					Type:   ftypes.Npm,
+					Vulnerabilities: lo.Map(vulnIDs, func(id string, _ int) types.DetectedVulnerability {
+						return types.DetectedVulnerability{
+							VulnerabilityID:  id,
+							PkgName:          "lodash",
+							InstalledVersion: "4.17.4",
+							Vulnerability: dbTypes.Vulnerability{
+								Severity: "HIGH",
+							},
+						}
+					}),
+				},
+			},
+		}
+	}
+
+	// The previous scan with the old version of lodash
+	baseline := filepath.Join(t.TempDir(), "baseline.sarif")
+	f, err := os.Create(baseline)
+	require.NoError(t, err)
+	w := report.SarifWriter{Output: f}
+	require.NoError(t, w.Write(nil, newReport("CVE-2019-10744")))
+	require.NoError(t, f.Close())
+
+	sarifWritten := bytes.NewBuffer(nil)
+	w = report.SarifWriter{
+		Output:   sarifWritten,
+		Baseline: baseline,
+	}
+	require.NoError(t, w.Write(nil, newReport("CVE-2019-10744", "CVE-2021-23337")))
+
+	got, err := sarif.FromBytes(sarifWritten.Bytes())
+	require.NoError(t, err)
+	states := lo.Map(got.Runs[0].Results, func(r *sarif.Result, _ int) string {
+		return lo.FromPtr(r.RuleID) + ":" + lo.FromPtr(r.BaselineState)
+	})
+	assert.Equal(t, []string{"CVE-2019-10744:unchanged", "CVE-2021-23337:new"}, states)
+
+	w.Baseline = filepath.Join(t.TempDir(), "missing.sarif")
+	assert.ErrorContains(t, w.Write(nil, newReport("CVE-2019-10744")), "unable to open the SARIF baseline")
+}
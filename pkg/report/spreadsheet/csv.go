@@ -0,0 +1,40 @@
+package spreadsheet
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// CSVWriter writes the vulnerabilities, licenses and secrets as CSV with a header row, one finding per row.
+// Use the "kind" column to tell the findings apart.
+type CSVWriter struct {
+	Output  io.Writer
+	Columns []Column
+}
+
+func (w CSVWriter) Write(_ context.Context, report types.Report) error {
+	columns, err := columnsOrDefault(w.Columns)
+	if err != nil {
+		return xerrors.Errorf("invalid CSV columns: %w", err)
+	}
+
+	cw := csv.NewWriter(w.Output)
+	if err = cw.Write(header(columns)); err != nil {
+		return xerrors.Errorf("failed to write CSV: %w", err)
+	}
+	for _, r := range rows(report) {
+		if err = cw.Write(r.cells(columns)); err != nil {
+			return xerrors.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	if err = cw.Error(); err != nil {
+		return xerrors.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
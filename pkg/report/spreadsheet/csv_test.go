@@ -0,0 +1,122 @@
+package spreadsheet_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/spreadsheet"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var report = types.Report{
+	ArtifactName: "./app",
+	ArtifactType: ftypes.ArtifactFilesystem,
+	Results: types.Results{
+		{
+			Target: "package-lock.json",
+			Class:  types.ClassLangPkg,
+			Type:   ftypes.Npm,
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2021-23337",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.4",
+					FixedVersion:     "4.17.21",
+					Status:           dbTypes.StatusFixed,
+					PrimaryURL:       "https://avd.aquasec.com/nvd/cve-2021-23337",
+					Vulnerability: dbTypes.Vulnerability{
+						Title:    "nodejs-lodash: command injection via template",
+						Severity: "HIGH",
+					},
+				},
+			},
+		},
+		{
+			Target: "Node.js",
+			Class:  types.ClassLicense,
+			Licenses: []types.DetectedLicense{
+				{
+					Severity: "HIGH",
+					Category: "restricted",
+					PkgName:  "gpl-lib",
+					Name:     "GPL-3.0",
+					FilePath: "node_modules/gpl-lib/package.json",
+				},
+			},
+		},
+		{
+			Target: "config.yaml",
+			Class:  types.ClassSecret,
+			Secrets: []types.DetectedSecret{
+				{
+					RuleID:    "aws-access-key-id",
+					Category:  "AWS",
+					Severity:  "CRITICAL",
+					Title:     "AWS Access Key ID",
+					StartLine: 3,
+				},
+			},
+		},
+	},
+}
+
+func TestCSVWriter_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []spreadsheet.Column
+		want    string
+		wantErr string
+	}{
+		{
+			name: "default columns",
+			want: `target,kind,id,severity,package,installed-version,fixed-version,status,title
+package-lock.json,vulnerability,CVE-2021-23337,HIGH,lodash,4.17.4,4.17.21,fixed,nodejs-lodash: command injection via template
+Node.js,license,GPL-3.0,HIGH,gpl-lib,,,,
+config.yaml,secret,aws-access-key-id,CRITICAL,,,,,AWS Access Key ID
+`,
+		},
+		{
+			name: "custom columns",
+			columns: []spreadsheet.Column{
+				spreadsheet.ColumnKind,
+				spreadsheet.ColumnID,
+				spreadsheet.ColumnCategory,
+				spreadsheet.ColumnPath,
+				spreadsheet.ColumnLine,
+				spreadsheet.ColumnURL,
+			},
+			want: `kind,id,category,path,line,url
+vulnerability,CVE-2021-23337,,,,https://avd.aquasec.com/nvd/cve-2021-23337
+license,GPL-3.0,restricted,node_modules/gpl-lib/package.json,,
+secret,aws-access-key-id,AWS,config.yaml,3,
+`,
+		},
+		{
+			name:    "unknown column",
+			columns: []spreadsheet.Column{"cvss"},
+			wantErr: "unknown column: cvss",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			w := spreadsheet.CSVWriter{
+				Output:  out,
+				Columns: tt.columns,
+			}
+			err := w.Write(context.Background(), report)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
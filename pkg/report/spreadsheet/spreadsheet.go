@@ -0,0 +1,164 @@
+package spreadsheet
+
+import (
+	"strconv"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Column is a column of the spreadsheets.
+// Columns which don't apply to a kind of findings are left empty, e.g. "fixed-version" of secrets.
+type Column string
+
+const (
+	ColumnTarget           Column = "target"
+	ColumnClass            Column = "class"
+	ColumnKind             Column = "kind"
+	ColumnID               Column = "id"
+	ColumnSeverity         Column = "severity"
+	ColumnPackage          Column = "package"
+	ColumnInstalledVersion Column = "installed-version"
+	ColumnFixedVersion     Column = "fixed-version"
+	ColumnStatus           Column = "status"
+	ColumnTitle            Column = "title"
+	ColumnCategory         Column = "category"
+	ColumnPath             Column = "path"
+	ColumnLine             Column = "line"
+	ColumnURL              Column = "url"
+)
+
+// Kind is the kind of findings, and the name of the sheet in XLSX
+type Kind string
+
+const (
+	KindVulnerability Kind = "vulnerability"
+	KindLicense       Kind = "license"
+	KindSecret        Kind = "secret"
+)
+
+var (
+	Columns = []Column{
+		ColumnTarget,
+		ColumnClass,
+		ColumnKind,
+		ColumnID,
+		ColumnSeverity,
+		ColumnPackage,
+		ColumnInstalledVersion,
+		ColumnFixedVersion,
+		ColumnStatus,
+		ColumnTitle,
+		ColumnCategory,
+		ColumnPath,
+		ColumnLine,
+		ColumnURL,
+	}
+	DefaultColumns = []Column{
+		ColumnTarget,
+		ColumnKind,
+		ColumnID,
+		ColumnSeverity,
+		ColumnPackage,
+		ColumnInstalledVersion,
+		ColumnFixedVersion,
+		ColumnStatus,
+		ColumnTitle,
+	}
+	kinds = []Kind{
+		KindVulnerability,
+		KindLicense,
+		KindSecret,
+	}
+)
+
+// row is a finding with the values by column
+type row struct {
+	kind   Kind
+	values map[Column]string
+}
+
+// rows flattens the vulnerabilities, licenses and secrets of the report
+func rows(report types.Report) []row {
+	var rs []row
+	for _, result := range report.Results {
+		common := func(kind Kind) map[Column]string {
+			return map[Column]string{
+				ColumnTarget: result.Target,
+				ColumnClass:  string(result.Class),
+				ColumnKind:   string(kind),
+			}
+		}
+		for _, v := range result.Vulnerabilities {
+			values := common(KindVulnerability)
+			values[ColumnID] = v.VulnerabilityID
+			values[ColumnSeverity] = v.Severity
+			values[ColumnPackage] = v.PkgName
+			values[ColumnInstalledVersion] = v.InstalledVersion
+			values[ColumnFixedVersion] = v.FixedVersion
+			values[ColumnStatus] = v.Status.String()
+			values[ColumnTitle] = v.Title
+			values[ColumnPath] = v.PkgPath
+			values[ColumnURL] = v.PrimaryURL
+			rs = append(rs, row{
+				kind:   KindVulnerability,
+				values: values,
+			})
+		}
+		for _, l := range result.Licenses {
+			values := common(KindLicense)
+			values[ColumnID] = l.Name
+			values[ColumnSeverity] = l.Severity
+			values[ColumnPackage] = l.PkgName
+			values[ColumnCategory] = string(l.Category)
+			values[ColumnPath] = l.FilePath
+			values[ColumnURL] = l.Link
+			rs = append(rs, row{
+				kind:   KindLicense,
+				values: values,
+			})
+		}
+		for _, s := range result.Secrets {
+			values := common(KindSecret)
+			values[ColumnID] = s.RuleID
+			values[ColumnSeverity] = s.Severity
+			values[ColumnTitle] = s.Title
+			values[ColumnCategory] = string(s.Category)
+			values[ColumnPath] = result.Target
+			values[ColumnLine] = strconv.Itoa(s.StartLine)
+			rs = append(rs, row{
+				kind:   KindSecret,
+				values: values,
+			})
+		}
+	}
+	return rs
+}
+
+// columnsOrDefault validates the columns, and returns the default columns if none is specified
+func columnsOrDefault(columns []Column) ([]Column, error) {
+	if len(columns) == 0 {
+		return DefaultColumns, nil
+	}
+	for _, c := range columns {
+		if !slices.Contains(Columns, c) {
+			return nil, xerrors.Errorf("unknown column: %s", c)
+		}
+	}
+	return columns, nil
+}
+
+func header(columns []Column) []string {
+	return lo.Map(columns, func(c Column, _ int) string {
+		return string(c)
+	})
+}
+
+func (r row) cells(columns []Column) []string {
+	return lo.Map(columns, func(c Column, _ int) string {
+		return r.values[c]
+	})
+}
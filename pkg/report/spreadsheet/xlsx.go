@@ -0,0 +1,202 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// XLSXWriter writes the vulnerabilities, licenses and secrets as an Excel workbook with a sheet per kind of findings.
+// The workbook is written by hand with the minimum parts of SpreadsheetML so as not to depend on an Excel library.
+type XLSXWriter struct {
+	Output  io.Writer
+	Columns []Column
+}
+
+var sheetNames = map[Kind]string{
+	KindVulnerability: "Vulnerabilities",
+	KindLicense:       "Licenses",
+	KindSecret:        "Secrets",
+}
+
+const (
+	contentTypesPart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`%s</Types>`
+	contentTypeSheet = `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`
+
+	rootRelsPart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	workbookPart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets>%s</sheets></workbook>`
+	workbookSheet = `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`
+
+	// The styles are referenced by the relationship with the ID next to the sheets
+	workbookRelsPart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`%s<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+	workbookRelsSheet = `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`
+
+	// The second cell format makes the header bold
+	stylesPart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font/><font><b/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border/></borders>` +
+		`<cellStyleXfs count="1"><xf/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf/><xf fontId="1" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`
+)
+
+type worksheet struct {
+	XMLName    xml.Name    `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main worksheet"`
+	SheetViews sheetViews  `xml:"sheetViews"`
+	Rows       []sheetRow  `xml:"sheetData>row"`
+	AutoFilter *autoFilter `xml:"autoFilter"`
+}
+
+// sheetViews freezes the header row
+type sheetViews struct {
+	SheetView struct {
+		WorkbookViewID int `xml:"workbookViewId,attr"`
+		Pane           struct {
+			YSplit      int    `xml:"ySplit,attr"`
+			TopLeftCell string `xml:"topLeftCell,attr"`
+			State       string `xml:"state,attr"`
+		} `xml:"pane"`
+	} `xml:"sheetView"`
+}
+
+type autoFilter struct {
+	Ref string `xml:"ref,attr"`
+}
+
+type sheetRow struct {
+	R     int    `xml:"r,attr"`
+	Cells []cell `xml:"c"`
+}
+
+type cell struct {
+	R     string `xml:"r,attr"`
+	S     int    `xml:"s,attr,omitempty"`
+	T     string `xml:"t,attr"`
+	Value string `xml:"is>t"`
+}
+
+func (w XLSXWriter) Write(_ context.Context, report types.Report) error {
+	columns, err := columnsOrDefault(w.Columns)
+	if err != nil {
+		return xerrors.Errorf("invalid XLSX columns: %w", err)
+	}
+
+	byKind := make(map[Kind][]row)
+	for _, r := range rows(report) {
+		byKind[r.kind] = append(byKind[r.kind], r)
+	}
+
+	var contentTypes, sheets, sheetRels strings.Builder
+	for i, kind := range kinds {
+		n := i + 1
+		fmt.Fprintf(&contentTypes, contentTypeSheet, n)
+		fmt.Fprintf(&sheets, workbookSheet, sheetNames[kind], n, n)
+		fmt.Fprintf(&sheetRels, workbookRelsSheet, n, n)
+	}
+
+	zw := zip.NewWriter(w.Output)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(contentTypesPart, contentTypes.String())},
+		{"_rels/.rels", rootRelsPart},
+		{"xl/workbook.xml", fmt.Sprintf(workbookPart, sheets.String())},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(workbookRelsPart, sheetRels.String(), len(kinds)+1)},
+		{"xl/styles.xml", stylesPart},
+	}
+	for _, part := range parts {
+		if err = writePart(zw, part.name, part.content); err != nil {
+			return err
+		}
+	}
+
+	for i, kind := range kinds {
+		b, err := xml.Marshal(newWorksheet(columns, byKind[kind]))
+		if err != nil {
+			return xerrors.Errorf("failed to marshal the worksheet: %w", err)
+		}
+		if err = writePart(zw, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xml.Header+string(b)); err != nil {
+			return err
+		}
+	}
+
+	if err = zw.Close(); err != nil {
+		return xerrors.Errorf("failed to write XLSX: %w", err)
+	}
+	return nil
+}
+
+func newWorksheet(columns []Column, rows []row) worksheet {
+	var ws worksheet
+	ws.SheetViews.SheetView.Pane.YSplit = 1
+	ws.SheetViews.SheetView.Pane.TopLeftCell = "A2"
+	ws.SheetViews.SheetView.Pane.State = "frozen"
+
+	ws.Rows = append(ws.Rows, newSheetRow(1, header(columns), 1))
+	for i, r := range rows {
+		ws.Rows = append(ws.Rows, newSheetRow(i+2, r.cells(columns), 0))
+	}
+	ws.AutoFilter = &autoFilter{
+		Ref: fmt.Sprintf("A1:%s%d", columnName(len(columns)-1), len(ws.Rows)),
+	}
+	return ws
+}
+
+func newSheetRow(n int, values []string, style int) sheetRow {
+	r := sheetRow{R: n}
+	for i, v := range values {
+		r.Cells = append(r.Cells, cell{
+			R:     fmt.Sprintf("%s%d", columnName(i), n),
+			S:     style,
+			T:     "inlineStr",
+			Value: v,
+		})
+	}
+	return r
+}
+
+// columnName converts the zero-based index into the column name, e.g. 0 => A, 26 => AA
+func columnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
+
+func writePart(zw *zip.Writer, name, content string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return xerrors.Errorf("failed to create %s: %w", name, err)
+	}
+	if _, err = io.WriteString(f, content); err != nil {
+		return xerrors.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
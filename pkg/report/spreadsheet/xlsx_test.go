@@ -0,0 +1,68 @@
+package spreadsheet_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/report/spreadsheet"
+)
+
+func TestXLSXWriter_Write(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	w := spreadsheet.XLSXWriter{
+		Output: out,
+		Columns: []spreadsheet.Column{
+			spreadsheet.ColumnID,
+			spreadsheet.ColumnSeverity,
+			spreadsheet.ColumnTitle,
+		},
+	}
+	require.NoError(t, w.Write(context.Background(), report))
+
+	zr, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts[f.Name] = string(b)
+	}
+
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts, "_rels/.rels")
+	assert.Contains(t, parts, "xl/styles.xml")
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheets>`+
+		`<sheet name="Vulnerabilities" sheetId="1" r:id="rId1"/>`+
+		`<sheet name="Licenses" sheetId="2" r:id="rId2"/>`+
+		`<sheet name="Secrets" sheetId="3" r:id="rId3"/>`+
+		`</sheets>`)
+	assert.Contains(t, parts["xl/_rels/workbook.xml.rels"], `<Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" state="frozen"></pane></sheetView></sheetViews>`+
+		`<sheetData>`+
+		`<row r="1">`+
+		`<c r="A1" s="1" t="inlineStr"><is><t>id</t></is></c>`+
+		`<c r="B1" s="1" t="inlineStr"><is><t>severity</t></is></c>`+
+		`<c r="C1" s="1" t="inlineStr"><is><t>title</t></is></c>`+
+		`</row>`+
+		`<row r="2">`+
+		`<c r="A2" t="inlineStr"><is><t>CVE-2021-23337</t></is></c>`+
+		`<c r="B2" t="inlineStr"><is><t>HIGH</t></is></c>`+
+		`<c r="C2" t="inlineStr"><is><t>nodejs-lodash: command injection via template</t></is></c>`+
+		`</row>`+
+		`</sheetData>`+
+		`<autoFilter ref="A1:C2"></autoFilter>`+
+		`</worksheet>`, parts["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, parts["xl/worksheets/sheet3.xml"], `<c r="A2" t="inlineStr"><is><t>aws-access-key-id</t></is></c>`)
+}
@@ -0,0 +1,180 @@
+package sqlexport_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/sqlexport"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func newReport(name string, createdAt time.Time, vulns ...types.DetectedVulnerability) types.Report {
+	return types.Report{
+		ArtifactName: name,
+		ArtifactType: ftypes.ArtifactContainerImage,
+		CreatedAt:    createdAt,
+		Metadata: types.Metadata{
+			ImageID: "sha256:" + name,
+			OS: &ftypes.OS{
+				Family: ftypes.Alpine,
+				Name:   "3.19.0",
+			},
+		},
+		Results: types.Results{
+			{
+				Target:          name + " (alpine 3.19.0)",
+				Class:           types.ClassOSPkg,
+				Type:            ftypes.Alpine,
+				Vulnerabilities: vulns,
+			},
+		},
+	}
+}
+
+var (
+	published = time.Date(2024, 1, 26, 9, 15, 0, 0, time.UTC)
+
+	opensslVuln = types.DetectedVulnerability{
+		VulnerabilityID:  "CVE-2024-0727",
+		PkgName:          "openssl",
+		InstalledVersion: "3.1.4-r2",
+		FixedVersion:     "3.1.4-r5",
+		Status:           dbTypes.StatusFixed,
+		PkgIdentifier: ftypes.PkgIdentifier{
+			PURL: &packageurl.PackageURL{
+				Type:      packageurl.TypeApk,
+				Namespace: "alpine",
+				Name:      "openssl",
+				Version:   "3.1.4-r2",
+			},
+		},
+		PrimaryURL: "https://avd.aquasec.com/nvd/cve-2024-0727",
+		Vulnerability: dbTypes.Vulnerability{
+			Title:         "openssl: denial of service via null dereference",
+			Severity:      "MEDIUM",
+			PublishedDate: &published,
+		},
+	}
+	busyboxVuln = types.DetectedVulnerability{
+		VulnerabilityID:  "CVE-2023-42363",
+		PkgName:          "busybox",
+		InstalledVersion: "1.36.1-r15",
+		Status:           dbTypes.StatusAffected,
+		Vulnerability: dbTypes.Vulnerability{
+			Severity: "LOW",
+		},
+	}
+)
+
+type finding struct {
+	Artifact      string
+	Package       string
+	Vulnerability string
+	Severity      string
+	Status        string
+	FixedVersion  string
+}
+
+func TestWriter_Write(t *testing.T) {
+	ctx := context.Background()
+	url := "sqlite://" + filepath.Join(t.TempDir(), "trivy.db")
+	scannedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// The second scan of alpine:3.19 replaces the findings of the first scan
+	require.NoError(t, sqlexport.Export(ctx, url, newReport("alpine:3.19", scannedAt, opensslVuln, busyboxVuln)))
+	require.NoError(t, sqlexport.Export(ctx, url, newReport("nginx:1.25", scannedAt, opensslVuln)))
+	require.NoError(t, sqlexport.Export(ctx, url, newReport("alpine:3.19", scannedAt.Add(time.Hour), busyboxVuln)))
+
+	db, _, err := sqlexport.Open(url)
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT a.name, p.name || '@' || p.version, v.id, f.severity, f.status, f.fixed_version
+		FROM findings f
+		JOIN artifacts a ON a.id = f.artifact_id
+		JOIN packages p ON p.id = f.package_id
+		JOIN vulnerabilities v ON v.id = f.vulnerability_id
+		ORDER BY a.name, v.id`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var got []finding
+	for rows.Next() {
+		var f finding
+		require.NoError(t, rows.Scan(&f.Artifact, &f.Package, &f.Vulnerability, &f.Severity, &f.Status, &f.FixedVersion))
+		got = append(got, f)
+	}
+	require.NoError(t, rows.Err())
+
+	assert.Equal(t, []finding{
+		{
+			Artifact:      "alpine:3.19",
+			Package:       "busybox@1.36.1-r15",
+			Vulnerability: "CVE-2023-42363",
+			Severity:      "LOW",
+			Status:        "affected",
+		},
+		{
+			Artifact:      "nginx:1.25",
+			Package:       "openssl@3.1.4-r2",
+			Vulnerability: "CVE-2024-0727",
+			Severity:      "MEDIUM",
+			Status:        "fixed",
+			FixedVersion:  "3.1.4-r5",
+		},
+	}, got)
+
+	var artifacts, packages int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM artifacts`).Scan(&artifacts))
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM packages`).Scan(&packages))
+	assert.Equal(t, 2, artifacts)
+	assert.Equal(t, 2, packages)
+
+	var purl, title string
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT purl FROM packages WHERE name = 'openssl'`).Scan(&purl))
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT title FROM vulnerabilities WHERE id = 'CVE-2024-0727'`).Scan(&title))
+	assert.Equal(t, "pkg:apk/alpine/openssl@3.1.4-r2", purl)
+	assert.Equal(t, "openssl: denial of service via null dereference", title)
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    sqlexport.Dialect
+		wantErr string
+	}{
+		{
+			name: "sqlite",
+			url:  "sqlite://" + filepath.Join(t.TempDir(), "trivy.db"),
+			want: sqlexport.DialectSQLite,
+		},
+		{
+			name:    "sad path: unsupported scheme",
+			url:     "mysql://localhost:3306/trivy",
+			wantErr: "unsupported database URL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, got, err := sqlexport.Open(tt.url)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			defer db.Close()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package summary
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Writer writes the number of findings per scanner and severity across all the targets
+// with the pass/fail verdict, for humans skimming CI logs, e.g.
+//
+//	Summary: FAIL (3 targets)
+//	Vulnerabilities: 6 (CRITICAL: 1, HIGH: 5, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+//	Secrets: 0 (CRITICAL: 0, HIGH: 0, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+//
+// The verdict fails when any finding is detected, which is the same condition as '--exit-code'.
+type Writer struct {
+	Output io.Writer
+
+	// Scanners are the scanners listed in the summary. All are listed if empty.
+	Scanners types.Scanners
+
+	// Severities are the severities listed in the summary. All are listed if empty.
+	Severities []dbTypes.Severity
+}
+
+func (w Writer) Write(results types.Results) error {
+	var severities []string
+	for _, s := range lo.Reverse(slices.Clone(dbTypes.SeverityNames)) {
+		sev, _ := dbTypes.NewSeverity(s)
+		if len(w.Severities) == 0 || lo.Contains(w.Severities, sev) {
+			severities = append(severities, s)
+		}
+	}
+
+	var vulns, misconfs, secrets, licenses []string
+	for _, result := range results {
+		for _, v := range result.Vulnerabilities {
+			vulns = append(vulns, v.Severity)
+		}
+		for _, m := range result.Misconfigurations {
+			if m.Status == types.MisconfStatusFailure {
+				misconfs = append(misconfs, m.Severity)
+			}
+		}
+		for _, s := range result.Secrets {
+			secrets = append(secrets, s.Severity)
+		}
+		for _, l := range result.Licenses {
+			licenses = append(licenses, l.Severity)
+		}
+	}
+
+	verdict := lo.Ternary(results.Failed(), "FAIL", "PASS")
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summary: %s (%d %s)\n", verdict, len(results), lo.Ternary(len(results) == 1, "target", "targets"))
+	if w.enabled(types.VulnerabilityScanner) {
+		writeCounts(&b, "Vulnerabilities", vulns, severities)
+	}
+	if w.enabled(types.MisconfigScanner, types.RBACScanner) {
+		writeCounts(&b, "Misconfigurations", misconfs, severities)
+	}
+	if w.enabled(types.SecretScanner) {
+		writeCounts(&b, "Secrets", secrets, severities)
+	}
+	if w.enabled(types.LicenseScanner) {
+		writeCounts(&b, "Licenses", licenses, severities)
+	}
+
+	if _, err := io.WriteString(w.Output, b.String()); err != nil {
+		return xerrors.Errorf("failed to write the summary: %w", err)
+	}
+	return nil
+}
+
+func (w Writer) enabled(scanners ...types.Scanner) bool {
+	return len(w.Scanners) == 0 || w.Scanners.AnyEnabled(scanners...)
+}
+
+// writeCounts writes the number of findings by severity, the most severe first.
+// All the severities are listed so that the summary has the same shape every time.
+func writeCounts(b *strings.Builder, name string, findings, severities []string) {
+	counts := lo.CountValues(findings)
+	summaries := lo.Map(severities, func(s string, _ int) string {
+		return fmt.Sprintf("%s: %d", s, counts[s])
+	})
+	fmt.Fprintf(b, "%s: %d (%s)\n", name, len(findings), strings.Join(summaries, ", "))
+}
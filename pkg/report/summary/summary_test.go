@@ -0,0 +1,124 @@
+package summary_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/report/summary"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestWriter_Write(t *testing.T) {
+	results := types.Results{
+		{
+			Target: "alpine:3.19 (alpine 3.19.1)",
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "CVE-2024-0001",
+					Vulnerability:   dbTypes.Vulnerability{Severity: "CRITICAL"},
+				},
+				{
+					VulnerabilityID: "CVE-2024-0002",
+					Vulnerability:   dbTypes.Vulnerability{Severity: "HIGH"},
+				},
+			},
+		},
+		{
+			Target: "Dockerfile",
+			Misconfigurations: []types.DetectedMisconfiguration{
+				{
+					ID:       "DS002",
+					Severity: "HIGH",
+					Status:   types.MisconfStatusFailure,
+				},
+				{
+					ID:       "DS001",
+					Severity: "MEDIUM",
+					Status:   types.MisconfStatusPassed,
+				},
+			},
+		},
+		{
+			Target: "package-lock.json",
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "CVE-2024-0003",
+					Vulnerability:   dbTypes.Vulnerability{Severity: "HIGH"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		results    types.Results
+		scanners   types.Scanners
+		severities []dbTypes.Severity
+		want       string
+	}{
+		{
+			name:    "findings across targets",
+			results: results,
+			scanners: types.Scanners{
+				types.VulnerabilityScanner,
+				types.MisconfigScanner,
+				types.SecretScanner,
+			},
+			want: `Summary: FAIL (3 targets)
+Vulnerabilities: 3 (CRITICAL: 1, HIGH: 2, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+Misconfigurations: 1 (CRITICAL: 0, HIGH: 1, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+Secrets: 0 (CRITICAL: 0, HIGH: 0, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+`,
+		},
+		{
+			name:     "selected severities",
+			results:  results,
+			scanners: types.Scanners{types.VulnerabilityScanner},
+			severities: []dbTypes.Severity{
+				dbTypes.SeverityHigh,
+				dbTypes.SeverityCritical,
+			},
+			want: `Summary: FAIL (3 targets)
+Vulnerabilities: 3 (CRITICAL: 1, HIGH: 2)
+`,
+		},
+		{
+			name: "no findings",
+			results: types.Results{
+				{
+					Target: "go.mod",
+				},
+			},
+			scanners: types.Scanners{types.VulnerabilityScanner},
+			want: `Summary: PASS (1 target)
+Vulnerabilities: 0 (CRITICAL: 0, HIGH: 0, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+`,
+		},
+		{
+			name: "all scanners without scanners",
+			want: `Summary: PASS (0 targets)
+Vulnerabilities: 0 (CRITICAL: 0, HIGH: 0, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+Misconfigurations: 0 (CRITICAL: 0, HIGH: 0, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+Secrets: 0 (CRITICAL: 0, HIGH: 0, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+Licenses: 0 (CRITICAL: 0, HIGH: 0, MEDIUM: 0, LOW: 0, UNKNOWN: 0)
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			w := summary.Writer{
+				Output:     buf,
+				Scanners:   tt.scanners,
+				Severities: tt.severities,
+			}
+			err := w.Write(tt.results)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
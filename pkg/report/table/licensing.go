@@ -14,6 +14,7 @@ import (
 	"github.com/aquasecurity/tml"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/i18n"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -24,6 +25,7 @@ type pkgLicenseRenderer struct {
 	isTerminal  bool
 	severities  []dbTypes.Severity
 	once        *sync.Once
+	catalog     *i18n.Catalog
 }
 
 func NewPkgLicenseRenderer(result types.Result, isTerminal bool, severities []dbTypes.Severity) pkgLicenseRenderer {
@@ -46,7 +48,7 @@ func (r pkgLicenseRenderer) Render() string {
 
 	target := r.result.Target + " (license)"
 	RenderTarget(r.w, target, r.isTerminal)
-	r.printf("Total: %d (%s)\n\n", total, strings.Join(summaries, ", "))
+	r.printf(r.catalog.T("Total: %d (%s)")+"\n\n", total, strings.Join(summaries, ", "))
 
 	r.tableWriter.Render()
 
@@ -54,7 +56,7 @@ func (r pkgLicenseRenderer) Render() string {
 }
 
 func (r pkgLicenseRenderer) setHeaders() {
-	header := []string{"Package", "License", "Classification", "Severity"}
+	header := translateHeaders(r.catalog, "Package", "License", "Classification", "Severity")
 	r.tableWriter.SetHeaders(header...)
 }
 
@@ -94,6 +96,7 @@ type fileLicenseRenderer struct {
 	isTerminal  bool
 	severities  []dbTypes.Severity
 	once        *sync.Once
+	catalog     *i18n.Catalog
 }
 
 func NewFileLicenseRenderer(result types.Result, isTerminal bool, severities []dbTypes.Severity) fileLicenseRenderer {
@@ -116,7 +119,7 @@ func (r fileLicenseRenderer) Render() string {
 
 	target := r.result.Target + " (license)"
 	RenderTarget(r.w, target, r.isTerminal)
-	r.printf("Total: %d (%s)\n\n", total, strings.Join(summaries, ", "))
+	r.printf(r.catalog.T("Total: %d (%s)")+"\n\n", total, strings.Join(summaries, ", "))
 
 	r.tableWriter.Render()
 
@@ -124,7 +127,7 @@ func (r fileLicenseRenderer) Render() string {
 }
 
 func (r fileLicenseRenderer) setHeaders() {
-	header := []string{"Classification", "Severity", "License", "File Location"}
+	header := translateHeaders(r.catalog, "Classification", "Severity", "License", "File Location")
 	r.tableWriter.SetHeaders(header...)
 }
 
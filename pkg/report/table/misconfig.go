@@ -10,6 +10,7 @@ import (
 
 	"github.com/aquasecurity/tml"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/i18n"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -28,6 +29,7 @@ type misconfigRenderer struct {
 	includeNonFailures bool
 	width              int
 	ansi               bool
+	catalog            *i18n.Catalog
 }
 
 func NewMisconfigRenderer(result types.Result, severities []dbTypes.Severity, trace, includeNonFailures, ansi bool) *misconfigRenderer {
@@ -56,9 +58,9 @@ func (r *misconfigRenderer) Render() string {
 	total, summaries := summarize(r.severities, r.countSeverities())
 
 	summary := r.result.MisconfSummary
-	r.printf("Tests: %d (SUCCESSES: %d, FAILURES: %d, EXCEPTIONS: %d)\n",
+	r.printf(r.catalog.T("Tests: %d (SUCCESSES: %d, FAILURES: %d, EXCEPTIONS: %d)")+"\n",
 		summary.Successes+summary.Failures+summary.Exceptions, summary.Successes, summary.Failures, summary.Exceptions)
-	r.printf("Failures: %d (%s)\n\n", total, strings.Join(summaries, ", "))
+	r.printf(r.catalog.T("Failures: %d (%s)")+"\n\n", total, strings.Join(summaries, ", "))
 
 	for _, m := range r.result.Misconfigurations {
 		r.renderSingle(m)
@@ -142,7 +144,7 @@ func (r *misconfigRenderer) renderSummary(misconf types.DetectedMisconfiguration
 
 	// show link if we have one
 	if misconf.PrimaryURL != "" {
-		r.printf("\r\n<dim>See %s\r\n", misconf.PrimaryURL)
+		r.printf("\r\n<dim>"+r.catalog.T("See %s")+"\r\n", misconf.PrimaryURL)
 	}
 
 	r.printSingleDivider()
@@ -9,6 +9,7 @@ import (
 
 	"github.com/aquasecurity/tml"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/i18n"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -19,6 +20,7 @@ type secretRenderer struct {
 	severities []dbTypes.Severity
 	width      int
 	ansi       bool
+	catalog    *i18n.Catalog
 }
 
 func NewSecretRenderer(target string, secrets []types.DetectedSecret, ansi bool, severities []dbTypes.Severity) *secretRenderer {
@@ -46,7 +48,7 @@ func (r *secretRenderer) Render() string {
 	severityCount := r.countSeverities()
 	total, summaries := summarize(r.severities, severityCount)
 
-	r.printf("Total: %d (%s)\n\n", total, strings.Join(summaries, ", "))
+	r.printf(r.catalog.T("Total: %d (%s)")+"\n\n", total, strings.Join(summaries, ", "))
 
 	for _, m := range r.secrets {
 		r.renderSingle(m)
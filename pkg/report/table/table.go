@@ -9,11 +9,14 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/samber/lo"
 	"golang.org/x/exp/slices"
 
 	"github.com/aquasecurity/table"
 	"github.com/aquasecurity/tml"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/i18n"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -45,6 +48,9 @@ type Writer struct {
 	// For licenses
 	LicenseRiskThreshold int
 	IgnoredLicenses      []string
+
+	// Catalog translates the table output. It is nil for English.
+	Catalog *i18n.Catalog
 }
 
 type Renderer interface {
@@ -61,9 +67,37 @@ func (tw Writer) Write(_ context.Context, report types.Report) error {
 		}
 		tw.write(result)
 	}
+	tw.writeIncomplete(report.Incomplete)
 	return nil
 }
 
+// maxSkippedTargets is the number of skipped targets listed in the table output.
+// All of them are available in the JSON output.
+const maxSkippedTargets = 20
+
+// writeIncomplete writes a notice that the results are partial as the budget was exceeded
+func (tw Writer) writeIncomplete(incomplete *ftypes.Incomplete) {
+	if incomplete == nil {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nINCOMPLETE RESULTS: the analysis stopped as %s.\n", incomplete.Reason)
+	if n := len(incomplete.SkippedTargets); n > 0 {
+		fmt.Fprintf(&b, "Not fully analyzed (%d):\n", n)
+		for _, target := range lo.Slice(incomplete.SkippedTargets, 0, maxSkippedTargets) {
+			fmt.Fprintf(&b, "  - %s\n", target)
+		}
+		if n > maxSkippedTargets {
+			fmt.Fprintf(&b, "  ... and %d more\n", n-maxSkippedTargets)
+		}
+	}
+	if tw.isOutputToTerminal() {
+		_, _ = color.New(color.FgYellow).Fprint(tw.Output, b.String())
+		return
+	}
+	_, _ = fmt.Fprint(tw.Output, b.String())
+}
+
 func (tw Writer) write(result types.Result) {
 	if result.IsEmpty() && result.Class != types.ClassOSPkg {
 		return
@@ -73,19 +107,29 @@ func (tw Writer) write(result types.Result) {
 	switch {
 	// vulnerability
 	case result.Class == types.ClassOSPkg || result.Class == types.ClassLangPkg:
-		renderer = NewVulnerabilityRenderer(result, tw.isOutputToTerminal(), tw.Tree, tw.ShowSuppressed, tw.Severities)
+		r := NewVulnerabilityRenderer(result, tw.isOutputToTerminal(), tw.Tree, tw.ShowSuppressed, tw.Severities)
+		r.catalog = tw.Catalog
+		renderer = r
 	// misconfiguration
 	case result.Class == types.ClassConfig:
-		renderer = NewMisconfigRenderer(result, tw.Severities, tw.Trace, tw.IncludeNonFailures, tw.isOutputToTerminal())
+		r := NewMisconfigRenderer(result, tw.Severities, tw.Trace, tw.IncludeNonFailures, tw.isOutputToTerminal())
+		r.catalog = tw.Catalog
+		renderer = r
 	// secret
 	case result.Class == types.ClassSecret:
-		renderer = NewSecretRenderer(result.Target, result.Secrets, tw.isOutputToTerminal(), tw.Severities)
+		r := NewSecretRenderer(result.Target, result.Secrets, tw.isOutputToTerminal(), tw.Severities)
+		r.catalog = tw.Catalog
+		renderer = r
 	// package license
 	case result.Class == types.ClassLicense:
-		renderer = NewPkgLicenseRenderer(result, tw.isOutputToTerminal(), tw.Severities)
+		r := NewPkgLicenseRenderer(result, tw.isOutputToTerminal(), tw.Severities)
+		r.catalog = tw.Catalog
+		renderer = r
 	// file license
 	case result.Class == types.ClassLicenseFile:
-		renderer = NewFileLicenseRenderer(result, tw.isOutputToTerminal(), tw.Severities)
+		r := NewFileLicenseRenderer(result, tw.isOutputToTerminal(), tw.Severities)
+		r.catalog = tw.Catalog
+		renderer = r
 	default:
 		return
 	}
@@ -110,6 +154,13 @@ func newTableWriter(output io.Writer, isTerminal bool) *table.Table {
 	return tableWriter
 }
 
+// translateHeaders returns the headers of the table in the language of the catalog
+func translateHeaders(catalog *i18n.Catalog, headers ...string) []string {
+	return lo.Map(headers, func(header string, _ int) string {
+		return catalog.T(header)
+	})
+}
+
 func summarize(specifiedSeverities []dbTypes.Severity, severityCount map[string]int) (int, []string) {
 	var total int
 	var severities []string
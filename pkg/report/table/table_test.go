@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/i18n"
 	"github.com/aquasecurity/trivy/pkg/report/table"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
@@ -18,6 +20,8 @@ func TestWriter_Write(t *testing.T) {
 		results            types.Results
 		expectedOutput     string
 		includeNonFailures bool
+		lang               string
+		incomplete         *ftypes.Incomplete
 	}{
 		{
 			name: "vulnerability and custom resource",
@@ -58,6 +62,40 @@ Total: 1 (MEDIUM: 0, HIGH: 1)
 │ foo     │ CVE-2020-0001 │ HIGH     │ will_not_fix │ 1.2.3             │               │ foobar                                    │
 │         │               │          │              │                   │               │ https://avd.aquasec.com/nvd/cve-2020-0001 │
 └─────────┴───────────────┴──────────┴──────────────┴───────────────────┴───────────────┴───────────────────────────────────────────┘
+`,
+		},
+		{
+			name: "french",
+			lang: "fr",
+			results: types.Results{
+				{
+					Target: "test",
+					Class:  types.ClassLangPkg,
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2020-0001",
+							PkgName:          "foo",
+							InstalledVersion: "1.2.3",
+							FixedVersion:     "1.2.4",
+							Status:           dbTypes.StatusFixed,
+							Vulnerability: dbTypes.Vulnerability{
+								Title:    "foobar",
+								Severity: "HIGH",
+							},
+						},
+					},
+				},
+			},
+			expectedOutput: `
+test ()
+=======
+Total : 1 (MEDIUM: 0, HIGH: 1)
+
+┌──────────────┬───────────────┬─────────┬────────┬───────────────────┬──────────────────┬────────┐
+│ Bibliothèque │ Vulnérabilité │ Gravité │ Statut │ Version installée │ Version corrigée │ Titre  │
+├──────────────┼───────────────┼─────────┼────────┼───────────────────┼──────────────────┼────────┤
+│ foo          │ CVE-2020-0001 │ HIGH    │ fixed  │ 1.2.3             │ 1.2.4            │ foobar │
+└──────────────┴───────────────┴─────────┴────────┴───────────────────┴──────────────────┴────────┘
 `,
 		},
 		{
@@ -70,12 +108,38 @@ Total: 1 (MEDIUM: 0, HIGH: 1)
 			},
 			expectedOutput: ``,
 		},
+		{
+			name: "incomplete",
+			results: types.Results{
+				{
+					Target: "test",
+					Class:  types.ClassLangPkg,
+				},
+			},
+			incomplete: &ftypes.Incomplete{
+				Reason: "the time budget (5m0s) was exceeded",
+				SkippedTargets: []string{
+					"sha256:1f0b5fe6bb5e8e7ed8fb94c38e3a1b22fa2b7b4cf3a4e6b3ec4cba36b5d63a42",
+					"sha256:d9cd1b4a7f2b0d6b9e1a21ba14ac0e1cf3c5f7a1b5f0e87a1e13f0c07ed5e5b1",
+				},
+			},
+			expectedOutput: `
+INCOMPLETE RESULTS: the analysis stopped as the time budget (5m0s) was exceeded.
+Not fully analyzed (2):
+  - sha256:1f0b5fe6bb5e8e7ed8fb94c38e3a1b22fa2b7b4cf3a4e6b3ec4cba36b5d63a42
+  - sha256:d9cd1b4a7f2b0d6b9e1a21ba14ac0e1cf3c5f7a1b5f0e87a1e13f0c07ed5e5b1
+`,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			catalog, err := i18n.Load(tc.lang)
+			require.NoError(t, err)
+
 			tableWritten := bytes.Buffer{}
 			writer := table.Writer{
+				Catalog:            catalog,
 				Output:             &tableWritten,
 				Tree:               true,
 				IncludeNonFailures: tc.includeNonFailures,
@@ -84,7 +148,10 @@ Total: 1 (MEDIUM: 0, HIGH: 1)
 					dbTypes.SeverityMedium,
 				},
 			}
-			err := writer.Write(nil, types.Report{Results: tc.results})
+			err = writer.Write(nil, types.Report{
+				Results:    tc.results,
+				Incomplete: tc.incomplete,
+			})
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedOutput, tableWritten.String(), tc.name)
 		})
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/mattn/go-runewidth"
 	"github.com/samber/lo"
 	"github.com/xlab/treeprint"
 	"golang.org/x/exp/maps"
@@ -17,6 +18,7 @@ import (
 	"github.com/aquasecurity/tml"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/i18n"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
@@ -33,6 +35,7 @@ type vulnerabilityRenderer struct {
 	showSuppressed bool // Show suppressed vulnerabilities
 	severities     []dbTypes.Severity
 	once           *sync.Once
+	catalog        *i18n.Catalog
 }
 
 func NewVulnerabilityRenderer(result types.Result, isTerminal, tree, suppressed bool, severities []dbTypes.Severity) *vulnerabilityRenderer {
@@ -80,7 +83,7 @@ func (r *vulnerabilityRenderer) renderDetectedVulnerabilities() {
 		target += fmt.Sprintf(" (%s)", r.result.Type)
 	}
 	RenderTarget(r.w, target, r.isTerminal)
-	r.printf("Total: %d (%s)\n\n", total, strings.Join(summaries, ", "))
+	r.printf(r.catalog.T("Total: %d (%s)")+"\n\n", total, strings.Join(summaries, ", "))
 
 	tw.Render()
 }
@@ -89,7 +92,7 @@ func (r *vulnerabilityRenderer) setHeaders(tw *table.Table) {
 	if len(r.result.Vulnerabilities) == 0 {
 		return
 	}
-	header := []string{
+	header := translateHeaders(r.catalog,
 		"Library",
 		"Vulnerability",
 		"Severity",
@@ -97,7 +100,7 @@ func (r *vulnerabilityRenderer) setHeaders(tw *table.Table) {
 		"Installed Version",
 		"Fixed Version",
 		"Title",
-	}
+	)
 	tw.SetHeaders(header...)
 }
 
@@ -132,11 +135,14 @@ func (r *vulnerabilityRenderer) setVulnerabilityRows(tw *table.Table, vulns []ty
 			}
 		}
 
+		// Aliases from other advisory sources are listed under the ID
+		vulnID := strings.Join(append([]string{v.VulnerabilityID}, v.Aliases...), "\n")
+
 		var row []string
 		if r.isTerminal {
 			row = []string{
 				lib,
-				v.VulnerabilityID,
+				vulnID,
 				ColorizeSeverity(v.Severity, v.Severity),
 				v.Status.String(),
 				v.InstalledVersion,
@@ -146,7 +152,7 @@ func (r *vulnerabilityRenderer) setVulnerabilityRows(tw *table.Table, vulns []ty
 		} else {
 			row = []string{
 				lib,
-				v.VulnerabilityID,
+				vulnID,
 				v.Severity,
 				v.Status.String(),
 				v.InstalledVersion,
@@ -169,14 +175,14 @@ func (r *vulnerabilityRenderer) countSeverities(vulns []types.DetectedVulnerabil
 
 func (r *vulnerabilityRenderer) renderModifiedVulnerabilities() {
 	tw := newTableWriter(r.w, r.isTerminal)
-	header := []string{
+	header := translateHeaders(r.catalog,
 		"Library",
 		"Vulnerability",
 		"Severity",
 		"Status",
 		"Statement",
 		"Source",
-	}
+	)
 	tw.SetHeaders(header...)
 
 	var total int
@@ -195,13 +201,13 @@ func (r *vulnerabilityRenderer) renderModifiedVulnerabilities() {
 		return
 	}
 
-	title := fmt.Sprintf("Suppressed Vulnerabilities (Total: %d)", total)
+	title := r.catalog.Sprintf("Suppressed Vulnerabilities (Total: %d)", total)
 	if r.isTerminal {
 		// nolint
 		_ = tml.Fprintf(r.w, "\n<underline>%s</underline>\n\n", title)
 	} else {
 		_, _ = fmt.Fprintf(r.w, "\n%s\n", title)
-		_, _ = fmt.Fprintf(r.w, "%s\n", strings.Repeat("=", len(title)))
+		_, _ = fmt.Fprintf(r.w, "%s\n", strings.Repeat("=", runewidth.StringWidth(title)))
 	}
 
 	tw.Render()
@@ -215,10 +221,11 @@ func (r *vulnerabilityRenderer) renderDependencyTree() {
 	}
 	ancestors := traverseAncestors(r.result.Packages, parents)
 
+	title := r.catalog.T("Dependency Origin Tree (Reversed)")
 	root := treeprint.NewWithRoot(fmt.Sprintf(`
-Dependency Origin Tree (Reversed)
-=================================
-%s`, r.result.Target))
+%s
+%s
+%s`, title, strings.Repeat("=", runewidth.StringWidth(title)), r.result.Target))
 
 	// This count is next to the package ID.
 	// e.g. node-fetch@1.7.3 (MEDIUM: 2, HIGH: 1, CRITICAL: 3)
@@ -6,7 +6,9 @@ import (
 	"encoding/xml"
 	"html"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 	"text/template"
 
@@ -21,21 +23,28 @@ import (
 // CustomTemplateFuncMap is used to overwrite existing functions for testing.
 var CustomTemplateFuncMap = make(map[string]interface{})
 
+// mainTemplate is the entry point of a template directory
+const mainTemplate = "main.tpl"
+
 // TemplateWriter write result in custom format defined by user's template
 type TemplateWriter struct {
 	Output   io.Writer
 	Template *template.Template
+
+	// OutputDir is the directory where "writeFile" writes files to.
+	// It is the current directory if empty.
+	OutputDir string
 }
 
-// NewTemplateWriter is the factory method to return TemplateWriter object
+// NewTemplateWriter is the factory method to return TemplateWriter object.
+// The template can be a string, a file with the "@" prefix, or a directory with the "@" prefix.
+// All the "*.tpl" files in the directory are parsed as templates named by the relative paths, e.g. "partials/vuln.tpl",
+// and "main.tpl" is executed.
 func NewTemplateWriter(output io.Writer, outputTemplate, appVersion string) (*TemplateWriter, error) {
-	if strings.HasPrefix(outputTemplate, "@") {
-		buf, err := os.ReadFile(strings.TrimPrefix(outputTemplate, "@"))
-		if err != nil {
-			return nil, xerrors.Errorf("error retrieving template from path: %w", err)
-		}
-		outputTemplate = string(buf)
+	tw := &TemplateWriter{
+		Output: output,
 	}
+
 	var templateFuncMap template.FuncMap = sprig.GenericFuncMap()
 	templateFuncMap["escapeXML"] = func(input string) string {
 		escaped := &bytes.Buffer{}
@@ -59,19 +68,93 @@ func NewTemplateWriter(output io.Writer, outputTemplate, appVersion string) (*Te
 		return appVersion
 	}
 
+	// e.g. {{ include "partials/vuln.tpl" . | indent 2 }}
+	templateFuncMap["include"] = func(name string, data any) (string, error) {
+		var buf bytes.Buffer
+		if err := tw.Template.ExecuteTemplate(&buf, name, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	// e.g. {{ range . }}{{ writeFile (printf "%s.html" .Target) (include "target.tpl" .) }}{{ end }}
+	templateFuncMap["writeFile"] = tw.writeFile
+
 	// Overwrite functions
 	for k, v := range CustomTemplateFuncMap {
 		templateFuncMap[k] = v
 	}
 
-	tmpl, err := template.New("output template").Funcs(templateFuncMap).Parse(outputTemplate)
+	tmpl, err := parseTemplate(template.New("output template").Funcs(templateFuncMap), outputTemplate)
 	if err != nil {
 		return nil, xerrors.Errorf("error parsing template: %w", err)
 	}
-	return &TemplateWriter{
-		Output:   output,
-		Template: tmpl,
-	}, nil
+	tw.Template = tmpl
+	return tw, nil
+}
+
+func parseTemplate(tmpl *template.Template, outputTemplate string) (*template.Template, error) {
+	if !strings.HasPrefix(outputTemplate, "@") {
+		return tmpl.Parse(outputTemplate)
+	}
+
+	path := strings.TrimPrefix(outputTemplate, "@")
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, xerrors.Errorf("error retrieving template from path: %w", err)
+	}
+	if !fi.IsDir() {
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return nil, xerrors.Errorf("error retrieving template from path: %w", err)
+		}
+		return tmpl.Parse(string(buf))
+	}
+
+	err = filepath.WalkDir(path, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if d.IsDir() || filepath.Ext(filePath) != ".tpl" {
+			return nil
+		}
+		rel, err := filepath.Rel(path, filePath)
+		if err != nil {
+			return err
+		}
+		buf, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		if _, err = tmpl.New(filepath.ToSlash(rel)).Parse(string(buf)); err != nil {
+			return xerrors.Errorf("%s: %w", rel, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("error retrieving templates from directory: %w", err)
+	}
+
+	main := tmpl.Lookup(mainTemplate)
+	if main == nil {
+		return nil, xerrors.Errorf("%q not found in the template directory %s", mainTemplate, path)
+	}
+	return main, nil
+}
+
+// writeFile writes the content to the path relative to the output directory, so that a scan can produce multiple files.
+// It returns an empty string not to print anything in the output.
+func (tw *TemplateWriter) writeFile(path, content string) (string, error) {
+	if !filepath.IsLocal(path) {
+		return "", xerrors.Errorf("writeFile: %q must be a relative path within the output directory", path)
+	}
+	path = filepath.Join(tw.OutputDir, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", xerrors.Errorf("writeFile: failed to create the directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", xerrors.Errorf("writeFile: %w", err)
+	}
+	log.Logger.Debugf("Written %s", path)
+	return "", nil
 }
 
 // Write writes result
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -188,3 +189,99 @@ func TestReportWriter_Template(t *testing.T) {
 		})
 	}
 }
+
+func TestReportWriter_TemplateDir(t *testing.T) {
+	inputReport := types.Report{
+		Results: types.Results{
+			{
+				Target: "alpine:3.19 (alpine 3.19.0)",
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID: "CVE-2023-38545",
+						Vulnerability: dbTypes.Vulnerability{
+							Severity: dbTypes.SeverityCritical.String(),
+						},
+					},
+				},
+			},
+			{
+				Target: "package-lock.json",
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID: "CVE-2021-23337",
+						Vulnerability: dbTypes.Vulnerability{
+							Severity: dbTypes.SeverityHigh.String(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		template  string
+		want      string
+		wantFiles map[string]string
+		wantErr   string
+	}{
+		{
+			name:     "sprig functions",
+			template: `{{ range . }}{{ .Target | upper | quote }} {{ len .Vulnerabilities | add 1 }}{{ "\n" }}{{ end }}`,
+			want:     "\"ALPINE:3.19 (ALPINE 3.19.0)\" 2\n\"PACKAGE-LOCK.JSON\" 2\n",
+		},
+		{
+			name:     "directory with partials",
+			template: "@testdata/template-dir",
+			want: `
+alpine:3.19 (alpine 3.19.0):
+  CVE-2023-38545 (critical)
+package-lock.json:
+  CVE-2021-23337 (high)
+`,
+		},
+		{
+			name: "multiple files",
+			template: `{{ define "target" }}{{ range .Vulnerabilities }}{{ .VulnerabilityID }}{{ end }}{{ end }}` +
+				`{{ range $i, $r := . }}{{ writeFile (printf "targets/%d.txt" $i) (include "target" $r) }}{{ end }}done`,
+			want: "done",
+			wantFiles: map[string]string{
+				"targets/0.txt": "CVE-2023-38545",
+				"targets/1.txt": "CVE-2021-23337",
+			},
+		},
+		{
+			name:     "file outside the output directory",
+			template: `{{ writeFile "../escape.txt" "foo" }}`,
+			wantErr:  `"../escape.txt" must be a relative path within the output directory`,
+		},
+		{
+			name:     "directory without main.tpl",
+			template: "@testdata/template-dir-no-main",
+			wantErr:  `"main.tpl" not found in the template directory`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := t.TempDir()
+			got := bytes.Buffer{}
+			w, err := report.NewTemplateWriter(&got, tt.template, "dev")
+			if err == nil {
+				w.OutputDir = outputDir
+				err = w.Write(context.Background(), inputReport)
+			}
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+
+			for name, want := range tt.wantFiles {
+				b, err := os.ReadFile(filepath.Join(outputDir, name))
+				require.NoError(t, err)
+				assert.Equal(t, want, string(b))
+			}
+		})
+	}
+}
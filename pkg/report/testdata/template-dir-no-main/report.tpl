@@ -0,0 +1 @@
+{{ range . }}{{ .Target }}{{ end }}
\ No newline at end of file
@@ -0,0 +1,6 @@
+{{- range . }}
+{{ .Target }}:
+{{- range .Vulnerabilities }}
+{{ include "partials/vuln.tpl" . | indent 2 }}
+{{- end }}
+{{- end }}
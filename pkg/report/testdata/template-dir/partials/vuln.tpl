@@ -0,0 +1 @@
+{{- .VulnerabilityID }} ({{ .Severity | lower }})
\ No newline at end of file
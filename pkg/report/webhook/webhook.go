@@ -0,0 +1,237 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// The built-in templates selected by name with '--webhook-template'
+const (
+	TemplateJSON  = "json"
+	TemplateSlack = "slack"
+	TemplateTeams = "teams"
+)
+
+const requestTimeout = 30 * time.Second
+
+var builtinTemplates = map[string]string{
+	TemplateSlack: `{"text": {{ printf "*Trivy %s*: %s (%d findings)\n%s" .Verdict .ArtifactName .Total (include "counts" .) | toJson }}}`,
+	TemplateTeams: `{
+  "@type": "MessageCard",
+  "@context": "https://schema.org/extensions",
+  "themeColor": "{{ if eq .Verdict "FAIL" }}d9534f{{ else }}5cb85c{{ end }}",
+  "summary": {{ printf "Trivy %s: %s" .Verdict .ArtifactName | toJson }},
+  "title": {{ printf "Trivy %s: %s (%d findings)" .Verdict .ArtifactName .Total | toJson }},
+  "text": {{ include "counts" . | replace "\n" "<br>" | toJson }}
+}`,
+}
+
+// countsTemplate lists the findings by severity per scanner, and is available as "counts" in the templates
+const countsTemplate = `{{ define "counts" }}{{ range $name, $counts := .Findings }}{{ $name }}: {{ $counts.Total }}` +
+	`{{ range $severity, $n := $counts.Severities }} {{ $severity }}:{{ $n }}{{ end }}
+{{ end }}{{ end }}`
+
+// Options configures the notification of completed scans
+type Options struct {
+	// URL is the endpoint where the payload is posted. Notifications are disabled if empty.
+	URL string
+
+	// Template is the name of a built-in template, the path of a template file prefixed with "@",
+	// or a Go template of the payload. The payload is posted as JSON if empty.
+	Template string
+
+	// Threshold is the number of findings at Severities required to notify. Every scan is notified if zero.
+	Threshold  int
+	Severities []dbTypes.Severity
+}
+
+// Payload summarizes the results of a scan
+type Payload struct {
+	ArtifactName string
+	ArtifactType string `json:",omitempty"`
+	Verdict      string // "PASS" or "FAIL" as '--exit-code'
+	Targets      int
+	Total        int
+	Findings     map[string]Counts // by "Vulnerabilities", "Misconfigurations", "Secrets" and "Licenses"
+}
+
+// Counts is the number of findings by severity
+type Counts struct {
+	Total      int
+	Severities map[string]int
+}
+
+// Notifier posts the payload of completed scans to the webhook
+type Notifier struct {
+	opts     Options
+	template *template.Template
+	client   *http.Client
+}
+
+// NewNotifier parses the template and returns the notifier, or nil if the URL is empty
+func NewNotifier(opts Options) (*Notifier, error) {
+	if opts.URL == "" {
+		return nil, nil
+	}
+
+	var tmpl *template.Template
+	if opts.Template != "" && opts.Template != TemplateJSON {
+		text, ok := builtinTemplates[opts.Template]
+		if !ok {
+			text = opts.Template
+			if strings.HasPrefix(text, "@") {
+				b, err := os.ReadFile(strings.TrimPrefix(text, "@"))
+				if err != nil {
+					return nil, xerrors.Errorf("unable to read the webhook template: %w", err)
+				}
+				text = string(b)
+			}
+		}
+
+		tmpl = template.New("webhook")
+		funcs := sprig.TxtFuncMap()
+		funcs["include"] = func(name string, data any) (string, error) {
+			var b strings.Builder
+			err := tmpl.ExecuteTemplate(&b, name, data)
+			return b.String(), err
+		}
+		var err error
+		if tmpl, err = tmpl.Funcs(funcs).Parse(countsTemplate + text); err != nil {
+			return nil, xerrors.Errorf("unable to parse the webhook template: %w", err)
+		}
+	}
+
+	return &Notifier{
+		opts:     opts,
+		template: tmpl,
+		client:   &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// Notify posts the payload of the report if the findings reach the threshold
+func (n *Notifier) Notify(ctx context.Context, report types.Report) error {
+	if n == nil {
+		return nil
+	}
+	if n.opts.Threshold > 0 {
+		if count := n.count(report.Results); count < n.opts.Threshold {
+			log.Logger.Debugf("Skipping the webhook notification as %d findings are below the threshold (%d)",
+				count, n.opts.Threshold)
+			return nil
+		}
+	}
+
+	body, err := n.render(NewPayload(report))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("unable to create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("webhook request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return xerrors.Errorf("webhook error: %s: %s", resp.Status, string(b))
+	}
+	log.Logger.Debugf("Sent the webhook notification of %s", report.ArtifactName)
+	return nil
+}
+
+func (n *Notifier) render(payload Payload) ([]byte, error) {
+	if n.template == nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, xerrors.Errorf("json encode error: %w", err)
+		}
+		return b, nil
+	}
+	var b bytes.Buffer
+	if err := n.template.Execute(&b, payload); err != nil {
+		return nil, xerrors.Errorf("unable to execute the webhook template: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// count returns the number of findings at the severities of the threshold
+func (n *Notifier) count(results types.Results) int {
+	var count int
+	for _, counts := range NewPayload(types.Report{Results: results}).Findings {
+		for severity, c := range counts.Severities {
+			s, _ := dbTypes.NewSeverity(severity)
+			if len(n.opts.Severities) == 0 || slices.Contains(n.opts.Severities, s) {
+				count += c
+			}
+		}
+	}
+	return count
+}
+
+// NewPayload counts the vulnerabilities, the failed misconfigurations, the secrets and the licenses in the report
+func NewPayload(report types.Report) Payload {
+	var vulns, misconfs, secrets, licenses []string
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			vulns = append(vulns, v.Severity)
+		}
+		for _, m := range result.Misconfigurations {
+			if m.Status == types.MisconfStatusFailure {
+				misconfs = append(misconfs, m.Severity)
+			}
+		}
+		for _, s := range result.Secrets {
+			secrets = append(secrets, s.Severity)
+		}
+		for _, l := range result.Licenses {
+			licenses = append(licenses, l.Severity)
+		}
+	}
+
+	findings := make(map[string]Counts)
+	for name, severities := range map[string][]string{
+		"Vulnerabilities":   vulns,
+		"Misconfigurations": misconfs,
+		"Secrets":           secrets,
+		"Licenses":          licenses,
+	} {
+		if len(severities) > 0 {
+			findings[name] = Counts{
+				Total:      len(severities),
+				Severities: lo.CountValues(severities),
+			}
+		}
+	}
+
+	return Payload{
+		ArtifactName: report.ArtifactName,
+		ArtifactType: string(report.ArtifactType),
+		Verdict:      lo.Ternary(report.Results.Failed(), "FAIL", "PASS"),
+		Targets:      len(report.Results),
+		Total:        len(vulns) + len(misconfs) + len(secrets) + len(licenses),
+		Findings:     findings,
+	}
+}
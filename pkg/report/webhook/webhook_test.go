@@ -0,0 +1,176 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/report/webhook"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+var report = types.Report{
+	ArtifactName: "alpine:3.19",
+	ArtifactType: ftypes.ArtifactContainerImage,
+	Results: types.Results{
+		{
+			Target: "alpine:3.19 (alpine 3.19.0)",
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "CVE-2024-0727",
+					Vulnerability:   dbTypes.Vulnerability{Severity: "MEDIUM"},
+				},
+				{
+					VulnerabilityID: "CVE-2023-5363",
+					Vulnerability:   dbTypes.Vulnerability{Severity: "HIGH"},
+				},
+			},
+		},
+		{
+			Target: "app/.env",
+			Secrets: []types.DetectedSecret{
+				{
+					RuleID:   "aws-access-key-id",
+					Severity: "CRITICAL",
+				},
+			},
+		},
+	},
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         webhook.Options
+		templateFile string
+		want         string
+		wantSkip     bool
+	}{
+		{
+			name: "json",
+			want: `{
+  "ArtifactName": "alpine:3.19",
+  "ArtifactType": "container_image",
+  "Verdict": "FAIL",
+  "Targets": 2,
+  "Total": 3,
+  "Findings": {
+    "Vulnerabilities": {"Total": 2, "Severities": {"HIGH": 1, "MEDIUM": 1}},
+    "Secrets": {"Total": 1, "Severities": {"CRITICAL": 1}}
+  }
+}`,
+		},
+		{
+			name: "slack",
+			opts: webhook.Options{
+				Template: webhook.TemplateSlack,
+			},
+			want: `{"text": "*Trivy FAIL*: alpine:3.19 (3 findings)\nSecrets: 1 CRITICAL:1\nVulnerabilities: 2 HIGH:1 MEDIUM:1\n"}`,
+		},
+		{
+			name: "teams",
+			opts: webhook.Options{
+				Template: webhook.TemplateTeams,
+			},
+			want: `{
+  "@type": "MessageCard",
+  "@context": "https://schema.org/extensions",
+  "themeColor": "d9534f",
+  "summary": "Trivy FAIL: alpine:3.19",
+  "title": "Trivy FAIL: alpine:3.19 (3 findings)",
+  "text": "Secrets: 1 CRITICAL:1<br>Vulnerabilities: 2 HIGH:1 MEDIUM:1<br>"
+}`,
+		},
+		{
+			name:         "template file",
+			templateFile: `{"artifact": "{{ .ArtifactName }}", "critical": {{ .Findings.Secrets.Severities.CRITICAL }}}`,
+			want:         `{"artifact": "alpine:3.19", "critical": 1}`,
+		},
+		{
+			name: "above the threshold",
+			opts: webhook.Options{
+				Template:   `{"total": {{ .Total }}}`,
+				Threshold:  2,
+				Severities: []dbTypes.Severity{dbTypes.SeverityCritical, dbTypes.SeverityHigh},
+			},
+			want: `{"total": 3}`,
+		},
+		{
+			name: "below the threshold",
+			opts: webhook.Options{
+				Threshold:  2,
+				Severities: []dbTypes.Severity{dbTypes.SeverityCritical},
+			},
+			wantSkip: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []byte
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				var err error
+				got, err = io.ReadAll(r.Body)
+				assert.NoError(t, err)
+			}))
+			defer ts.Close()
+
+			opts := tt.opts
+			opts.URL = ts.URL
+			if tt.templateFile != "" {
+				path := filepath.Join(t.TempDir(), "webhook.tpl")
+				require.NoError(t, os.WriteFile(path, []byte(tt.templateFile), 0600))
+				opts.Template = "@" + path
+			}
+
+			notifier, err := webhook.NewNotifier(opts)
+			require.NoError(t, err)
+			require.NoError(t, notifier.Notify(context.Background(), report))
+
+			if tt.wantSkip {
+				assert.Nil(t, got)
+				return
+			}
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestNotifier_Notify_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	notifier, err := webhook.NewNotifier(webhook.Options{URL: ts.URL})
+	require.NoError(t, err)
+	err = notifier.Notify(context.Background(), report)
+	require.ErrorContains(t, err, "400 Bad Request: invalid_payload")
+}
+
+func TestNewNotifier(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		notifier, err := webhook.NewNotifier(webhook.Options{})
+		require.NoError(t, err)
+		assert.Nil(t, notifier)
+		assert.NoError(t, notifier.Notify(context.Background(), report))
+	})
+
+	t.Run("sad path: invalid template", func(t *testing.T) {
+		_, err := webhook.NewNotifier(webhook.Options{
+			URL:      "http://localhost",
+			Template: "{{ .Total ",
+		})
+		require.ErrorContains(t, err, "unable to parse the webhook template")
+	})
+}
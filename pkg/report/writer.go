@@ -4,18 +4,32 @@ import (
 	"context"
 	"errors"
 	"io"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/samber/lo"
 	"golang.org/x/xerrors"
 
+	"github.com/aquasecurity/trivy-db/pkg/metadata"
 	cr "github.com/aquasecurity/trivy/pkg/compliance/report"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/i18n"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/report/cyclonedx"
 	"github.com/aquasecurity/trivy/pkg/report/github"
+	"github.com/aquasecurity/trivy/pkg/report/gitlab"
+	"github.com/aquasecurity/trivy/pkg/report/html"
+	"github.com/aquasecurity/trivy/pkg/report/junit"
+	"github.com/aquasecurity/trivy/pkg/report/layers"
+	"github.com/aquasecurity/trivy/pkg/report/metrics"
+	"github.com/aquasecurity/trivy/pkg/report/plain"
 	"github.com/aquasecurity/trivy/pkg/report/predicate"
+	"github.com/aquasecurity/trivy/pkg/report/remediation"
 	"github.com/aquasecurity/trivy/pkg/report/spdx"
+	"github.com/aquasecurity/trivy/pkg/report/spreadsheet"
+	"github.com/aquasecurity/trivy/pkg/report/summary"
 	"github.com/aquasecurity/trivy/pkg/report/table"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
@@ -26,6 +40,21 @@ const (
 
 // Write writes the result to output, format as passed in argument
 func Write(ctx context.Context, report types.Report, option flag.Options) (err error) {
+	if len(option.ExtraOutputs) > 0 {
+		return writeOutputs(ctx, report, option)
+	}
+
+	// With '--summary', the full report goes only to '--output' and stdout gets the summary
+	if option.Summary {
+		if option.Output != "" {
+			option.Summary = false
+			if err = Write(ctx, report, option); err != nil {
+				return err
+			}
+		}
+		return WriteSummary(ctx, report.Results, option)
+	}
+
 	output, cleanup, err := option.OutputWriter(ctx)
 	if err != nil {
 		return xerrors.Errorf("failed to create a file: %w", err)
@@ -41,6 +70,12 @@ func Write(ctx context.Context, report types.Report, option flag.Options) (err e
 		return complianceWrite(ctx, report, option, output)
 	}
 
+	catalog, err := loadCatalog(option)
+	if err != nil {
+		return xerrors.Errorf("failed to load the message catalog: %w", err)
+	}
+	report = catalog.Localize(report)
+
 	var writer Writer
 	switch option.Format {
 	case types.FormatTable:
@@ -53,13 +88,18 @@ func Write(ctx context.Context, report types.Report, option flag.Options) (err e
 			Trace:                option.Trace,
 			LicenseRiskThreshold: option.LicenseRiskThreshold,
 			IgnoredLicenses:      option.IgnoredLicenses,
+			Catalog:              catalog,
 		}
 	case types.FormatJSON:
-		writer = &JSONWriter{Output: output}
+		writer = &JSONWriter{
+			Output:         output,
+			ShowSuppressed: option.ShowSuppressed,
+		}
 	case types.FormatGitHub:
 		writer = &github.Writer{
 			Output:  output,
 			Version: option.AppVersion,
+			Submit:  option.GitHubSubmit,
 		}
 	case types.FormatCycloneDX:
 		// TODO: support xml format option with cyclonedx writer
@@ -76,22 +116,77 @@ func Write(ctx context.Context, report types.Report, option flag.Options) (err e
 			}
 			break
 		}
-		var err error
-		if writer, err = NewTemplateWriter(output, option.Template, option.AppVersion); err != nil {
+		tw, err := NewTemplateWriter(output, option.Template, option.AppVersion)
+		if err != nil {
 			return xerrors.Errorf("failed to initialize template writer: %w", err)
 		}
+		// Files written by the template go next to the output file
+		if option.Output != "" && !strings.HasPrefix(option.Output, "plugin=") {
+			tw.OutputDir = filepath.Dir(option.Output)
+		}
+		writer = tw
 	case types.FormatSarif:
 		target := ""
 		if report.ArtifactType == ftypes.ArtifactFilesystem {
 			target = option.Target
 		}
 		writer = &SarifWriter{
-			Output:  output,
-			Version: option.AppVersion,
-			Target:  target,
+			Output:         output,
+			Version:        option.AppVersion,
+			Target:         target,
+			Upload:         option.GitHubUpload,
+			ShowSuppressed: option.ShowSuppressed,
+			Baseline:       option.SARIFBaseline,
 		}
 	case types.FormatCosignVuln:
 		writer = predicate.NewVulnWriter(output, option.AppVersion)
+	case types.FormatMetrics, types.FormatPrometheus:
+		writer = metrics.Writer{
+			Output:      output,
+			Format:      option.Format,
+			DBUpdatedAt: dbUpdatedAt(option.CacheDir),
+		}
+	case types.FormatRemediation:
+		writer = remediation.Writer{Output: output}
+	case types.FormatLayers:
+		writer = layers.Writer{Output: output}
+	case types.FormatJUnit:
+		writer = junit.Writer{
+			Output:   output,
+			TestCase: junit.TestCase(option.JUnitTestCase),
+		}
+	case types.FormatHTML:
+		writer = html.Writer{
+			Output:  output,
+			Version: option.AppVersion,
+		}
+	case types.FormatCSV:
+		writer = spreadsheet.CSVWriter{
+			Output:  output,
+			Columns: toColumns(option.SpreadsheetColumns),
+		}
+	case types.FormatXLSX:
+		writer = spreadsheet.XLSXWriter{
+			Output:  output,
+			Columns: toColumns(option.SpreadsheetColumns),
+		}
+	case types.FormatGitLabDependencyScanning:
+		writer = gitlab.Writer{
+			Output:  output,
+			Version: option.AppVersion,
+			Type:    gitlab.DependencyScanning,
+		}
+	case types.FormatGitLabContainerScanning:
+		writer = gitlab.Writer{
+			Output:  output,
+			Version: option.AppVersion,
+			Type:    gitlab.ContainerScanning,
+		}
+	case types.FormatPlain:
+		writer = plain.Writer{
+			Output:             output,
+			IncludeNonFailures: option.IncludeNonFailures,
+		}
 	default:
 		return xerrors.Errorf("unknown format: %v", option.Format)
 	}
@@ -103,6 +198,63 @@ func Write(ctx context.Context, report types.Report, option flag.Options) (err e
 	return nil
 }
 
+// writeOutputs writes the report in each pair of '--format' and '--output' specified multiple times
+func writeOutputs(ctx context.Context, report types.Report, option flag.Options) error {
+	outputs := append([]flag.ReportOutput{
+		{
+			Format: option.Format,
+			Output: option.Output,
+		},
+	}, option.ExtraOutputs...)
+	for _, o := range outputs {
+		// With '--summary', stdout gets only the summary
+		if option.Summary && o.Output == "" {
+			continue
+		}
+		opt := option
+		opt.Format, opt.Output = o.Format, o.Output
+		opt.ExtraOutputs = nil
+		opt.Summary = false
+		if err := Write(ctx, report, opt); err != nil {
+			return xerrors.Errorf("failed to write %s: %w", o.Format, err)
+		}
+	}
+	if option.Summary {
+		return WriteSummary(ctx, report.Results, option)
+	}
+	return nil
+}
+
+// WriteSummary writes the number of findings in the results with the pass/fail verdict to stdout
+func WriteSummary(ctx context.Context, results types.Results, option flag.Options) (err error) {
+	option.Output = ""
+	output, cleanup, err := option.OutputWriter(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to open the summary output: %w", err)
+	}
+	defer func() {
+		if cerr := cleanup(); cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+	}()
+
+	writer := summary.Writer{
+		Output:     output,
+		Scanners:   option.Scanners,
+		Severities: option.Severities,
+	}
+	if err = writer.Write(results); err != nil {
+		return xerrors.Errorf("failed to write the summary: %w", err)
+	}
+	return nil
+}
+
+func toColumns(columns []string) []spreadsheet.Column {
+	return lo.Map(columns, func(c string, _ int) spreadsheet.Column {
+		return spreadsheet.Column(c)
+	})
+}
+
 func complianceWrite(ctx context.Context, report types.Report, opt flag.Options, output io.Writer) error {
 	complianceReport, err := cr.BuildComplianceReport([]types.Results{report.Results}, opt.Compliance)
 	if err != nil {
@@ -116,6 +268,25 @@ func complianceWrite(ctx context.Context, report types.Report, opt flag.Options,
 	})
 }
 
+// dbUpdatedAt returns the time when the vulnerability database in the cache was updated
+func dbUpdatedAt(cacheDir string) time.Time {
+	meta, err := metadata.NewClient(cacheDir).Get()
+	if err != nil {
+		log.Logger.Debugf("Unable to get the DB metadata: %s", err)
+		return time.Time{}
+	}
+	return meta.UpdatedAt
+}
+
+// loadCatalog returns the message catalog of '--lang'.
+// It returns nil for English and machine-readable formats, which are not translated.
+func loadCatalog(opt flag.Options) (*i18n.Catalog, error) {
+	if opt.Format != types.FormatTable && opt.Format != types.FormatTemplate {
+		return nil, nil
+	}
+	return i18n.Load(opt.Lang)
+}
+
 // Writer defines the result write operation
 type Writer interface {
 	Write(context.Context, types.Report) error
@@ -1,10 +1,18 @@
 package report_test
 
 import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/flag"
+	"github.com/aquasecurity/trivy/pkg/report"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
 
@@ -82,3 +90,49 @@ func TestResults_Failed(t *testing.T) {
 		})
 	}
 }
+
+func TestWrite_MultipleOutputs(t *testing.T) {
+	dir := t.TempDir()
+	r := types.Report{
+		SchemaVersion: 2,
+		ArtifactName:  "test",
+		Results: types.Results{
+			{
+				Target: "test",
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID: "CVE-2021-0001",
+						PkgName:         "test",
+						Vulnerability: dbTypes.Vulnerability{
+							Severity: dbTypes.SeverityHigh.String(),
+						},
+					},
+				},
+			},
+		},
+	}
+	opts := flag.Options{
+		AppVersion: "dev",
+		ReportOptions: flag.ReportOptions{
+			Format: types.FormatJSON,
+			Output: filepath.Join(dir, "result.json"),
+			ExtraOutputs: []flag.ReportOutput{
+				{
+					Format: types.FormatSarif,
+					Output: filepath.Join(dir, "result.sarif"),
+				},
+			},
+		},
+	}
+	require.NoError(t, report.Write(context.Background(), r, opts))
+
+	b, err := os.ReadFile(filepath.Join(dir, "result.json"))
+	require.NoError(t, err)
+	var got types.Report
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, "CVE-2021-0001", got.Results[0].Vulnerabilities[0].VulnerabilityID)
+
+	b, err = os.ReadFile(filepath.Join(dir, "result.sarif"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"ruleId": "CVE-2021-0001"`)
+}
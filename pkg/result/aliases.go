@@ -0,0 +1,142 @@
+package result
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// advisoryURLRegexp extracts the vulnerability ID from the URL of a well-known advisory page
+var advisoryURLRegexp = regexp.MustCompile(`^https?://(?:github\.com/advisories|nvd\.nist\.gov/vuln/detail)/((?:GHSA|CVE)-[\w-]+)/?$`)
+
+// dedupeAliases merges the vulnerabilities of the same package that are aliases of each other,
+// e.g. the same issue reported as a CVE by one advisory source and as a GHSA by another.
+// Vulnerabilities are aliases when one refers to the other in the vendor IDs or the references from the DB.
+// The merged vulnerability takes the details of the CVE and lists the other IDs in Aliases.
+func dedupeAliases(vulns []types.DetectedVulnerability) []types.DetectedVulnerability {
+	groups := make(map[string][]int)
+	var keys []string
+	for i, v := range vulns {
+		key := fmt.Sprintf("%s/%s/%s", v.PkgName, v.InstalledVersion, v.PkgPath)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	merged := make(map[int][]int) // primary index => alias indices
+	removed := make(map[int]bool)
+	for _, key := range keys {
+		for primary, aliases := range aliasComponents(vulns, groups[key]) {
+			merged[primary] = aliases
+			for _, i := range aliases {
+				removed[i] = true
+			}
+		}
+	}
+	if len(removed) == 0 {
+		return vulns
+	}
+
+	var deduped []types.DetectedVulnerability
+	for i, v := range vulns {
+		if removed[i] {
+			continue
+		}
+		for _, j := range merged[i] {
+			v.Aliases = append(v.Aliases, vulns[j].VulnerabilityID)
+			v.Aliases = append(v.Aliases, vulns[j].Aliases...)
+		}
+		if len(v.Aliases) > 0 {
+			slices.Sort(v.Aliases)
+			v.Aliases = slices.Compact(v.Aliases)
+		}
+		deduped = append(deduped, v)
+	}
+	return deduped
+}
+
+// aliasComponents returns the primary vulnerability and its aliases among the vulnerabilities of the same package
+func aliasComponents(vulns []types.DetectedVulnerability, indices []int) map[int][]int {
+	if len(indices) < 2 {
+		return nil
+	}
+
+	byID := make(map[string]int)
+	for _, i := range indices {
+		byID[vulns[i].VulnerabilityID] = i
+	}
+
+	// Union-find with the number of CVEs in each component
+	parent := make(map[int]int)
+	cves := make(map[int]int)
+	for _, i := range indices {
+		parent[i] = i
+		if isCVE(vulns[i].VulnerabilityID) {
+			cves[i] = 1
+		}
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for _, i := range indices {
+		for _, id := range aliasIDs(vulns[i]) {
+			j, ok := byID[id]
+			if !ok {
+				continue
+			}
+			ri, rj := find(i), find(j)
+			// Different CVEs are never merged, as a vendor advisory such as DSA may cover several CVEs
+			if ri == rj || cves[ri]+cves[rj] > 1 {
+				continue
+			}
+			parent[rj] = ri
+			cves[ri] += cves[rj]
+		}
+	}
+
+	members := make(map[int][]int)
+	for _, i := range indices {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+
+	components := make(map[int][]int)
+	for _, m := range members {
+		if len(m) < 2 {
+			continue
+		}
+		primary := m[0]
+		for _, i := range m[1:] {
+			if isCVE(vulns[i].VulnerabilityID) && !isCVE(vulns[primary].VulnerabilityID) {
+				primary = i
+			}
+		}
+		components[primary] = slices.DeleteFunc(slices.Clone(m), func(i int) bool { return i == primary })
+	}
+	return components
+}
+
+// aliasIDs returns the IDs the vulnerability refers to as the same issue
+func aliasIDs(v types.DetectedVulnerability) []string {
+	ids := slices.Clone(v.VendorIDs)
+	for _, ref := range v.References {
+		if m := advisoryURLRegexp.FindStringSubmatch(ref); m != nil {
+			ids = append(ids, m[1])
+		}
+	}
+	return ids
+}
+
+func isCVE(id string) bool {
+	return strings.HasPrefix(id, "CVE-")
+}
@@ -0,0 +1,157 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func Test_dedupeAliases(t *testing.T) {
+	tests := []struct {
+		name  string
+		vulns []types.DetectedVulnerability
+		want  []types.DetectedVulnerability
+	}{
+		{
+			name: "GHSA referring to CVE",
+			vulns: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "GHSA-xxxx-yyyy-zzzz",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.4",
+					Vulnerability: dbTypes.Vulnerability{
+						References: []string{"https://nvd.nist.gov/vuln/detail/CVE-2019-10744"},
+					},
+				},
+				{
+					VulnerabilityID:  "CVE-2019-10744",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.4",
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2019-10744",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.4",
+					Aliases:          []string{"GHSA-xxxx-yyyy-zzzz"},
+				},
+			},
+		},
+		{
+			name: "vendor IDs",
+			vulns: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2023-0001",
+					PkgName:          "openssl",
+					InstalledVersion: "1.1.1k-1",
+					VendorIDs:        []string{"DLA-1234-1"},
+				},
+				{
+					VulnerabilityID:  "DLA-1234-1",
+					PkgName:          "openssl",
+					InstalledVersion: "1.1.1k-1",
+				},
+				{
+					VulnerabilityID:  "GHSA-aaaa-bbbb-cccc",
+					PkgName:          "openssl",
+					InstalledVersion: "1.1.1k-1",
+					Vulnerability: dbTypes.Vulnerability{
+						References: []string{"https://github.com/advisories/GHSA-aaaa-bbbb-cccc"},
+					},
+					VendorIDs: []string{"DLA-1234-1"},
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2023-0001",
+					PkgName:          "openssl",
+					InstalledVersion: "1.1.1k-1",
+					VendorIDs:        []string{"DLA-1234-1"},
+					Aliases:          []string{"DLA-1234-1", "GHSA-aaaa-bbbb-cccc"},
+				},
+			},
+		},
+		{
+			name: "vendor advisory covering several CVEs",
+			vulns: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2023-0001",
+					PkgName:          "openssl",
+					InstalledVersion: "1.1.1k-1",
+					VendorIDs:        []string{"DSA-5555-1"},
+				},
+				{
+					VulnerabilityID:  "CVE-2023-0002",
+					PkgName:          "openssl",
+					InstalledVersion: "1.1.1k-1",
+					VendorIDs:        []string{"DSA-5555-1"},
+				},
+				{
+					VulnerabilityID:  "DSA-5555-1",
+					PkgName:          "openssl",
+					InstalledVersion: "1.1.1k-1",
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2023-0001",
+					PkgName:          "openssl",
+					InstalledVersion: "1.1.1k-1",
+					VendorIDs:        []string{"DSA-5555-1"},
+					Aliases:          []string{"DSA-5555-1"},
+				},
+				{
+					VulnerabilityID:  "CVE-2023-0002",
+					PkgName:          "openssl",
+					InstalledVersion: "1.1.1k-1",
+					VendorIDs:        []string{"DSA-5555-1"},
+				},
+			},
+		},
+		{
+			name: "different packages",
+			vulns: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "GHSA-xxxx-yyyy-zzzz",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.4",
+					Vulnerability: dbTypes.Vulnerability{
+						References: []string{"https://nvd.nist.gov/vuln/detail/CVE-2019-10744"},
+					},
+				},
+				{
+					VulnerabilityID:  "CVE-2019-10744",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.4",
+					PkgPath:          "node_modules/lodash/package.json",
+				},
+			},
+			want: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "GHSA-xxxx-yyyy-zzzz",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.4",
+					Vulnerability: dbTypes.Vulnerability{
+						References: []string{"https://nvd.nist.gov/vuln/detail/CVE-2019-10744"},
+					},
+				},
+				{
+					VulnerabilityID:  "CVE-2019-10744",
+					PkgName:          "lodash",
+					InstalledVersion: "4.17.4",
+					PkgPath:          "node_modules/lodash/package.json",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dedupeAliases(tt.vulns)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/open-policy-agent/opa/rego"
 	"github.com/samber/lo"
@@ -14,6 +15,8 @@ import (
 	"golang.org/x/xerrors"
 
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/scanpolicy"
 	"github.com/aquasecurity/trivy/pkg/types"
 	"github.com/aquasecurity/trivy/pkg/vex"
 )
@@ -21,24 +24,69 @@ import (
 const (
 	// DefaultIgnoreFile is the file name to be evaluated
 	DefaultIgnoreFile = ".trivyignore"
+
+	// DefaultIgnoreYAMLFile is the file name to be evaluated when DefaultIgnoreFile doesn't exist
+	DefaultIgnoreYAMLFile = ".trivyignore.yaml"
 )
 
 type FilterOption struct {
-	Severities         []dbTypes.Severity
-	IgnoreStatuses     []dbTypes.Status
-	IncludeNonFailures bool
-	IgnoreFile         string
-	PolicyFile         string
-	IgnoreLicenses     []string
-	VEXPath            string
+	Severities           []dbTypes.Severity
+	IgnoreStatuses       []dbTypes.Status
+	IgnoreStatusScopes   []StatusScope
+	IncludeNonFailures   bool
+	IgnoreFile           string
+	RequireStatement     bool
+	PolicyFile           string
+	IgnoreLicenses       []string
+	VEXPath              string
+	KEVOnly              bool
+	SeverityOverrideFile string
+	SkipUnreachable      bool
+	DedupeAliases        bool
+
+	// ScanPolicyDirs are the directories of the scan policy bundles published by the image producer.
+	// Their ignore rules, VEX documents and severity overrides are applied in addition to the local ones.
+	ScanPolicyDirs []string
+}
+
+// StatusScope limits ignoring vulnerability statuses to a result class and a severity,
+// e.g. unfixed vulnerabilities are ignored only in OS packages with LOW severity.
+type StatusScope struct {
+	// Class is ClassOSPkg or ClassLangPkg
+	Class types.ResultClass
+
+	// Severity is empty for all severities
+	Severity string
+}
+
+func (s StatusScope) match(class types.ResultClass, severity string) bool {
+	return s.Class == class && (s.Severity == "" || s.Severity == severity)
 }
 
 // Filter filters out the report
 func Filter(ctx context.Context, report types.Report, opt FilterOption) error {
-	ignoreConf, err := parseIgnoreFile(ctx, opt.IgnoreFile)
+	// The severity must be overridden before filtering by severity.
+	// The local overrides are applied last so that they take precedence over the producer's.
+	for _, dir := range opt.ScanPolicyDirs {
+		if err := overrideSeverities(report, bundleFile(dir, scanpolicy.SeverityOverrideFile)); err != nil {
+			return xerrors.Errorf("scan policy severity override error: %w", err)
+		}
+	}
+	if err := overrideSeverities(report, opt.SeverityOverrideFile); err != nil {
+		return xerrors.Errorf("severity override error: %w", err)
+	}
+
+	ignoreConf, err := parseIgnoreFile(ctx, opt.IgnoreFile, opt.RequireStatement)
 	if err != nil {
 		return xerrors.Errorf("%s error: %w", opt.IgnoreFile, err)
 	}
+	for _, dir := range opt.ScanPolicyDirs {
+		bundleConf, err := parseIgnoreFile(ctx, bundleFile(dir, scanpolicy.IgnoreFile), opt.RequireStatement)
+		if err != nil {
+			return xerrors.Errorf("scan policy ignore error: %w", err)
+		}
+		ignoreConf.merge(bundleConf)
+	}
 
 	for i := range report.Results {
 		if err = FilterResult(ctx, &report.Results[i], ignoreConf, opt); err != nil {
@@ -47,9 +95,19 @@ func Filter(ctx context.Context, report types.Report, opt FilterOption) error {
 	}
 
 	// Filter out vulnerabilities based on the given VEX document.
-	if err = filterByVEX(report, opt); err != nil {
+	if err = filterByVEX(report, opt.VEXPath); err != nil {
 		return xerrors.Errorf("VEX error: %w", err)
 	}
+	for _, dir := range opt.ScanPolicyDirs {
+		if err = filterByVEX(report, bundleFile(dir, scanpolicy.VEXFile)); err != nil {
+			return xerrors.Errorf("scan policy VEX error: %w", err)
+		}
+	}
+
+	// The fixed versions of packages only take the remaining vulnerabilities into account
+	for i := range report.Results {
+		fillPkgFixedVersions(&report.Results[i])
+	}
 
 	return nil
 }
@@ -61,9 +119,13 @@ func FilterResult(ctx context.Context, result *types.Result, ignoreConf IgnoreCo
 		return s.String()
 	})
 
-	filterVulnerabilities(result, severities, opt.IgnoreStatuses, ignoreConf)
-	filterMisconfigurations(result, severities, opt.IncludeNonFailures, ignoreConf)
-	filterSecrets(result, severities, ignoreConf)
+	filterVulnerabilities(result, severities, opt.IgnoreStatuses, opt.IgnoreStatusScopes, opt.KEVOnly, opt.SkipUnreachable, ignoreConf)
+	if opt.DedupeAliases {
+		result.Vulnerabilities = dedupeAliases(result.Vulnerabilities)
+	}
+	now := clock.Now(ctx)
+	filterMisconfigurations(result, severities, opt.IncludeNonFailures, ignoreConf, now)
+	filterSecrets(result, severities, ignoreConf, now)
 	filterLicenses(result, severities, opt.IgnoreLicenses, ignoreConf)
 
 	if opt.PolicyFile != "" {
@@ -79,8 +141,8 @@ func FilterResult(ctx context.Context, result *types.Result, ignoreConf IgnoreCo
 // filterByVEX determines whether a detected vulnerability should be filtered out based on the provided VEX document.
 // If the VEX document is not nil and the vulnerability is either not affected or fixed according to the VEX statement,
 // the vulnerability is filtered out.
-func filterByVEX(report types.Report, opt FilterOption) error {
-	vexDoc, err := vex.New(opt.VEXPath, report)
+func filterByVEX(report types.Report, vexPath string) error {
+	vexDoc, err := vex.New(vexPath, report)
 	if err != nil {
 		return err
 	} else if vexDoc == nil {
@@ -96,7 +158,17 @@ func filterByVEX(report types.Report, opt FilterOption) error {
 	return nil
 }
 
-func filterVulnerabilities(result *types.Result, severities []string, ignoreStatuses []dbTypes.Status, ignoreConfig IgnoreConfig) {
+// bundleFile returns the path of the file in the scan policy bundle, or empty if the bundle doesn't have it
+func bundleFile(dir, fileName string) string {
+	filePath := filepath.Join(dir, fileName)
+	if _, err := os.Stat(filePath); err != nil {
+		return ""
+	}
+	return filePath
+}
+
+func filterVulnerabilities(result *types.Result, severities []string, ignoreStatuses []dbTypes.Status, statusScopes []StatusScope,
+	kevOnly, skipUnreachable bool, ignoreConfig IgnoreConfig) {
 	uniqVulns := make(map[string]types.DetectedVulnerability)
 	for _, vuln := range result.Vulnerabilities {
 		if vuln.Severity == "" {
@@ -108,14 +180,20 @@ func filterVulnerabilities(result *types.Result, severities []string, ignoreStat
 		case !slices.Contains(severities, vuln.Severity):
 			continue
 		// Filter by status
-		case slices.Contains(ignoreStatuses, vuln.Status):
+		case slices.Contains(ignoreStatuses, vuln.Status) && inStatusScopes(statusScopes, result.Class, vuln.Severity):
+			continue
+		// Filter by CISA KEV
+		case kevOnly && vuln.KEV == nil:
+			continue
+		// Filter by reachability
+		case skipUnreachable && vuln.Reachability == types.Unreachable:
 			continue
 		}
 
 		// Filter by ignore file
 		if f := ignoreConfig.MatchVulnerability(vuln.VulnerabilityID, result.Target, vuln.PkgPath, vuln.PkgIdentifier.PURL); f != nil {
 			result.ModifiedFindings = append(result.ModifiedFindings,
-				types.NewModifiedFinding(vuln, types.FindingStatusIgnored, f.Statement, ignoreConfig.FilePath))
+				f.modify(types.NewModifiedFinding(vuln, types.FindingStatusIgnored, f.Statement, ignoreConfig.sourceOf(f))))
 			continue
 		}
 
@@ -134,8 +212,19 @@ func filterVulnerabilities(result *types.Result, severities []string, ignoreStat
 	}
 }
 
+// inStatusScopes returns true if statuses are ignored in the class and the severity.
+// All vulnerabilities are in the scope if no scopes are given.
+func inStatusScopes(scopes []StatusScope, class types.ResultClass, severity string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	return lo.ContainsBy(scopes, func(s StatusScope) bool {
+		return s.match(class, severity)
+	})
+}
+
 func filterMisconfigurations(result *types.Result, severities []string, includeNonFailures bool,
-	ignoreConfig IgnoreConfig) {
+	ignoreConfig IgnoreConfig, now time.Time) {
 	var filtered []types.DetectedMisconfiguration
 	result.MisconfSummary = new(types.MisconfSummary)
 
@@ -149,10 +238,20 @@ func filterMisconfigurations(result *types.Result, severities []string, includeN
 		if f := ignoreConfig.MatchMisconfiguration(misconf.ID, misconf.AVDID, result.Target); f != nil {
 			result.MisconfSummary.Exceptions++
 			result.ModifiedFindings = append(result.ModifiedFindings,
-				types.NewModifiedFinding(misconf, types.FindingStatusIgnored, f.Statement, ignoreConfig.FilePath))
+				f.modify(types.NewModifiedFinding(misconf, types.FindingStatusIgnored, f.Statement, ignoreConfig.sourceOf(f))))
 			continue
 		}
 
+		// Filter by suppression comments, including the ones already applied by the Terraform scanner
+		if misconf.Status != types.MisconfStatusPassed {
+			if s := matchInlineMisconfiguration(result.Target, misconf, now); s != nil {
+				result.MisconfSummary.Exceptions++
+				result.ModifiedFindings = append(result.ModifiedFindings,
+					s.modify(result.Target, types.NewModifiedFinding(misconf, types.FindingStatusIgnored, "", "")))
+				continue
+			}
+		}
+
 		// Count successes, failures, and exceptions
 		summarize(misconf.Status, result.MisconfSummary)
 
@@ -169,7 +268,7 @@ func filterMisconfigurations(result *types.Result, severities []string, includeN
 	}
 }
 
-func filterSecrets(result *types.Result, severities []string, ignoreConfig IgnoreConfig) {
+func filterSecrets(result *types.Result, severities []string, ignoreConfig IgnoreConfig, now time.Time) {
 	var filtered []types.DetectedSecret
 	for _, secret := range result.Secrets {
 		if !slices.Contains(severities, secret.Severity) {
@@ -178,7 +277,12 @@ func filterSecrets(result *types.Result, severities []string, ignoreConfig Ignor
 		} else if f := ignoreConfig.MatchSecret(secret.RuleID, result.Target); f != nil {
 			// Filter by ignore file
 			result.ModifiedFindings = append(result.ModifiedFindings,
-				types.NewModifiedFinding(secret, types.FindingStatusIgnored, f.Statement, ignoreConfig.FilePath))
+				f.modify(types.NewModifiedFinding(secret, types.FindingStatusIgnored, f.Statement, ignoreConfig.sourceOf(f))))
+			continue
+		} else if s := matchInlineSecret(result.Target, secret, now); s != nil {
+			// Filter by suppression comments
+			result.ModifiedFindings = append(result.ModifiedFindings,
+				s.modify(result.Target, types.NewModifiedFinding(secret, types.FindingStatusIgnored, "", "")))
 			continue
 		}
 		filtered = append(filtered, secret)
@@ -212,7 +316,7 @@ func filterLicenses(result *types.Result, severities, ignoreLicenseNames []strin
 		// Filter by ignore file
 		if f := ignoreConfig.MatchLicense(l.Name, l.FilePath); f != nil {
 			result.ModifiedFindings = append(result.ModifiedFindings,
-				types.NewModifiedFinding(l, types.FindingStatusIgnored, f.Statement, ignoreConfig.FilePath))
+				f.modify(types.NewModifiedFinding(l, types.FindingStatusIgnored, f.Statement, ignoreConfig.sourceOf(f))))
 			continue
 		}
 
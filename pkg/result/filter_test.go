@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -180,12 +181,15 @@ func TestFilter(t *testing.T) {
 		}
 	)
 	type args struct {
-		report         types.Report
-		severities     []dbTypes.Severity
-		ignoreStatuses []dbTypes.Status
-		ignoreFile     string
-		policyFile     string
-		vexPath        string
+		report          types.Report
+		severities      []dbTypes.Severity
+		ignoreStatuses  []dbTypes.Status
+		statusScopes    []result.StatusScope
+		ignoreFile      string
+		policyFile      string
+		vexPath         string
+		kevOnly         bool
+		skipUnreachable bool
 	}
 	tests := []struct {
 		name string
@@ -222,7 +226,7 @@ func TestFilter(t *testing.T) {
 				Results: []types.Result{
 					{
 						Vulnerabilities: []types.DetectedVulnerability{
-							vuln2,
+							withPkgFixedVersion(vuln2, "1.2.4"),
 						},
 						MisconfSummary: &types.MisconfSummary{
 							Successes:  0,
@@ -265,7 +269,7 @@ func TestFilter(t *testing.T) {
 				Results: types.Results{
 					types.Result{
 						Vulnerabilities: []types.DetectedVulnerability{
-							vuln2,
+							withPkgFixedVersion(vuln2, "1.2.4"),
 						},
 						ModifiedFindings: []types.ModifiedFinding{
 							{
@@ -308,6 +312,126 @@ func TestFilter(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ignore unfixed in scopes",
+			args: args{
+				report: types.Report{
+					Results: types.Results{
+						{
+							Target: "debian:11 (debian 11)",
+							Class:  types.ClassOSPkg,
+							Vulnerabilities: []types.DetectedVulnerability{
+								withStatus(vuln1, dbTypes.StatusAffected),
+								withStatus(vuln2, dbTypes.StatusAffected),
+							},
+						},
+						{
+							Target: "go.mod",
+							Class:  types.ClassLangPkg,
+							Vulnerabilities: []types.DetectedVulnerability{
+								withStatus(vuln1, dbTypes.StatusAffected),
+								withStatus(vuln2, dbTypes.StatusAffected),
+							},
+						},
+					},
+				},
+				severities: []dbTypes.Severity{
+					dbTypes.SeverityLow,
+					dbTypes.SeverityCritical,
+				},
+				ignoreStatuses: []dbTypes.Status{
+					dbTypes.StatusAffected,
+				},
+				statusScopes: []result.StatusScope{
+					{
+						Class: types.ClassOSPkg,
+					},
+					{
+						Class:    types.ClassLangPkg,
+						Severity: "LOW",
+					},
+				},
+			},
+			want: types.Report{
+				Results: types.Results{
+					{
+						Target: "debian:11 (debian 11)",
+						Class:  types.ClassOSPkg,
+					},
+					{
+						Target: "go.mod",
+						Class:  types.ClassLangPkg,
+						Vulnerabilities: []types.DetectedVulnerability{
+							withPkgFixedVersion(withStatus(vuln2, dbTypes.StatusAffected), "1.2.4"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "kev only",
+			args: args{
+				report: types.Report{
+					Results: types.Results{
+						types.Result{
+							Target: "debian:11 (debian 11)",
+							Vulnerabilities: []types.DetectedVulnerability{
+								vuln1,
+								withKEV(vuln2),
+							},
+						},
+					},
+				},
+				severities: []dbTypes.Severity{
+					dbTypes.SeverityLow,
+					dbTypes.SeverityCritical,
+				},
+				kevOnly: true,
+			},
+			want: types.Report{
+				Results: types.Results{
+					{
+						Target: "debian:11 (debian 11)",
+						Vulnerabilities: []types.DetectedVulnerability{
+							withPkgFixedVersion(withKEV(vuln2), "1.2.4"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "skip unreachable",
+			args: args{
+				report: types.Report{
+					Results: types.Results{
+						types.Result{
+							Target: "go.mod",
+							Vulnerabilities: []types.DetectedVulnerability{
+								withReachability(vuln1, types.Unreachable),
+								withReachability(vuln2, types.Reachable),
+								vuln3,
+							},
+						},
+					},
+				},
+				severities: []dbTypes.Severity{
+					dbTypes.SeverityLow,
+					dbTypes.SeverityCritical,
+				},
+				skipUnreachable: true,
+			},
+			want: types.Report{
+				Results: types.Results{
+					{
+						Target: "go.mod",
+						Vulnerabilities: []types.DetectedVulnerability{
+							withPkgFixedVersion(withReachability(vuln2, types.Reachable), "1.2.4"),
+							withPkgFixedVersion(vuln3, "1.2.4"),
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "ignore file",
 			args: args{
@@ -355,8 +479,8 @@ func TestFilter(t *testing.T) {
 						Target: "package-lock.json",
 						Class:  types.ClassLangPkg,
 						Vulnerabilities: []types.DetectedVulnerability{
-							vuln3,
-							vuln4,
+							withPkgFixedVersion(vuln3, "1.2.4"),
+							withPkgFixedVersion(vuln4, "1.2.4"),
 						},
 						ModifiedFindings: []types.ModifiedFinding{
 							{
@@ -366,16 +490,18 @@ func TestFilter(t *testing.T) {
 								Finding: vuln1,
 							},
 							{
-								Type:    types.FindingTypeVulnerability,
-								Status:  types.FindingStatusIgnored,
-								Source:  "testdata/.trivyignore",
-								Finding: vuln5,
+								Type:      types.FindingTypeVulnerability,
+								Status:    types.FindingStatusIgnored,
+								Source:    "testdata/.trivyignore",
+								ExpiredAt: lo.ToPtr(time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)),
+								Finding:   vuln5,
 							},
 							{
-								Type:    types.FindingTypeVulnerability,
-								Status:  types.FindingStatusIgnored,
-								Source:  "testdata/.trivyignore",
-								Finding: vuln6,
+								Type:      types.FindingTypeVulnerability,
+								Status:    types.FindingStatusIgnored,
+								Source:    "testdata/.trivyignore",
+								ExpiredAt: lo.ToPtr(time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)),
+								Finding:   vuln6,
 							},
 						},
 					},
@@ -469,8 +595,8 @@ func TestFilter(t *testing.T) {
 					{
 						Target: "foo/package-lock.json",
 						Vulnerabilities: []types.DetectedVulnerability{
-							vuln4,
-							vuln6,
+							withPkgFixedVersion(vuln4, "1.2.4"),
+							withPkgFixedVersion(vuln6, "1.2.4"),
 						},
 						ModifiedFindings: []types.ModifiedFinding{
 							{
@@ -486,10 +612,13 @@ func TestFilter(t *testing.T) {
 								Finding: vuln3,
 							},
 							{
-								Type:    types.FindingTypeVulnerability,
-								Status:  types.FindingStatusIgnored,
-								Source:  "testdata/.trivyignore.yaml",
-								Finding: vuln5,
+								Type:      types.FindingTypeVulnerability,
+								Status:    types.FindingStatusIgnored,
+								Statement: "Not exploitable in this configuration",
+								Source:    "testdata/.trivyignore.yaml",
+								Owner:     "security-team",
+								ExpiredAt: lo.ToPtr(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+								Finding:   vuln5,
 							},
 							{
 								Type:    types.FindingTypeVulnerability,
@@ -582,7 +711,7 @@ func TestFilter(t *testing.T) {
 				Results: types.Results{
 					{
 						Vulnerabilities: []types.DetectedVulnerability{
-							vuln1,
+							withPkgFixedVersion(vuln1, "1.2.4"),
 						},
 						ModifiedFindings: []types.ModifiedFinding{
 							{
@@ -953,6 +1082,7 @@ func TestFilter(t *testing.T) {
 								PkgName:          "bar",
 								InstalledVersion: "1.2.3",
 								FixedVersion:     "1.2.4",
+								PkgFixedVersion:  "1.2.4",
 								Vulnerability: dbTypes.Vulnerability{
 									Severity: dbTypes.SeverityCritical.String(),
 								},
@@ -963,6 +1093,7 @@ func TestFilter(t *testing.T) {
 								PkgName:          "bar",
 								InstalledVersion: "1.2.3",
 								FixedVersion:     "1.2.4",
+								PkgFixedVersion:  "1.2.4",
 								Vulnerability: dbTypes.Vulnerability{
 									Severity: dbTypes.SeverityCritical.String(),
 								},
@@ -983,6 +1114,7 @@ func TestFilter(t *testing.T) {
 								PkgPath:          "some/path/c.jar",
 								InstalledVersion: "1.2.3",
 								FixedVersion:     "1.2.4",
+								PkgFixedVersion:  "1.2.4",
 								Vulnerability: dbTypes.Vulnerability{
 									Severity: dbTypes.SeverityUnknown.String(),
 								},
@@ -993,6 +1125,7 @@ func TestFilter(t *testing.T) {
 								PkgName:          "baz",
 								InstalledVersion: "1.2.3",
 								FixedVersion:     "1.2.4",
+								PkgFixedVersion:  "1.2.4",
 								Vulnerability: dbTypes.Vulnerability{
 									Severity: dbTypes.SeverityHigh.String(),
 								},
@@ -1009,14 +1142,248 @@ func TestFilter(t *testing.T) {
 			ctx := clock.With(context.Background(), fakeTime)
 
 			err := result.Filter(ctx, tt.args.report, result.FilterOption{
-				Severities:     tt.args.severities,
-				VEXPath:        tt.args.vexPath,
-				IgnoreStatuses: tt.args.ignoreStatuses,
-				IgnoreFile:     tt.args.ignoreFile,
-				PolicyFile:     tt.args.policyFile,
+				Severities:         tt.args.severities,
+				VEXPath:            tt.args.vexPath,
+				IgnoreStatuses:     tt.args.ignoreStatuses,
+				IgnoreStatusScopes: tt.args.statusScopes,
+				IgnoreFile:         tt.args.ignoreFile,
+				PolicyFile:         tt.args.policyFile,
+				KEVOnly:            tt.args.kevOnly,
+				SkipUnreachable:    tt.args.skipUnreachable,
 			})
 			require.NoError(t, err)
 			assert.Equal(t, tt.want, tt.args.report)
 		})
 	}
 }
+
+func withStatus(vuln types.DetectedVulnerability, status dbTypes.Status) types.DetectedVulnerability {
+	vuln.Status = status
+	return vuln
+}
+
+func withKEV(vuln types.DetectedVulnerability) types.DetectedVulnerability {
+	vuln.KEV = &types.KEV{DateAdded: "2021-12-10"}
+	return vuln
+}
+
+func withReachability(vuln types.DetectedVulnerability, reachability types.Reachability) types.DetectedVulnerability {
+	vuln.Reachability = reachability
+	return vuln
+}
+
+func withPkgFixedVersion(vuln types.DetectedVulnerability, pkgFixedVersion string) types.DetectedVulnerability {
+	vuln.PkgFixedVersion = pkgFixedVersion
+	return vuln
+}
+
+func TestFilter_RequireStatement(t *testing.T) {
+	tests := []struct {
+		name       string
+		ignoreFile string
+		wantErr    string
+	}{
+		{
+			name:       "all entries have statements",
+			ignoreFile: "testdata/.trivyignore-statement.yaml",
+		},
+		{
+			name:       "entry without statement",
+			ignoreFile: "testdata/.trivyignore.yaml",
+			wantErr:    "the statement is required to ignore CVE-2019-0001",
+		},
+		{
+			name:       "flat ignore file",
+			ignoreFile: "testdata/.trivyignore",
+			wantErr:    "cannot have statements",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := result.Filter(context.Background(), types.Report{}, result.FilterOption{
+				IgnoreFile:       tt.ignoreFile,
+				RequireStatement: true,
+			})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFilter_ScanPolicyDirs(t *testing.T) {
+	vuln := func(id, severity string) types.DetectedVulnerability {
+		return types.DetectedVulnerability{
+			VulnerabilityID:  id,
+			PkgName:          "foo",
+			InstalledVersion: "1.2.3",
+			Vulnerability: dbTypes.Vulnerability{
+				Severity: severity,
+			},
+		}
+	}
+	report := types.Report{
+		Results: types.Results{
+			{
+				Target: "package-lock.json",
+				Vulnerabilities: []types.DetectedVulnerability{
+					vuln("CVE-2019-0001", dbTypes.SeverityMedium.String()),
+					vuln("CVE-2019-0002", dbTypes.SeverityHigh.String()),
+					vuln("CVE-2019-0003", dbTypes.SeverityLow.String()),
+				},
+			},
+		},
+	}
+
+	err := result.Filter(context.Background(), report, result.FilterOption{
+		Severities: []dbTypes.Severity{
+			dbTypes.SeverityLow,
+			dbTypes.SeverityMedium,
+			dbTypes.SeverityHigh,
+			dbTypes.SeverityCritical,
+		},
+		SeverityOverrideFile: "testdata/severity-override.yaml",
+		ScanPolicyDirs:       []string{"testdata/scan-policy"},
+	})
+	require.NoError(t, err)
+
+	got := lo.Map(report.Results[0].Vulnerabilities, func(v types.DetectedVulnerability, _ int) string {
+		return v.VulnerabilityID + ":" + v.Severity
+	})
+	// The local override takes precedence over the bundle, and the bundle ignores CVE-2019-0002
+	assert.ElementsMatch(t, []string{
+		"CVE-2019-0001:LOW",
+		"CVE-2019-0003:HIGH",
+	}, got)
+	require.Len(t, report.Results[0].ModifiedFindings, 1)
+	assert.Equal(t, "testdata/scan-policy/trivyignore.yaml", report.Results[0].ModifiedFindings[0].Source)
+}
+
+func TestFilter_InlineSuppression(t *testing.T) {
+	misconf := func(avdID string, status types.MisconfStatus, comment string) types.DetectedMisconfiguration {
+		return types.DetectedMisconfiguration{
+			ID:       avdID,
+			AVDID:    avdID,
+			Severity: dbTypes.SeverityHigh.String(),
+			Status:   status,
+			CauseMetadata: ftypes.CauseMetadata{
+				StartLine: 3,
+				EndLine:   3,
+				Code: ftypes.Code{
+					Lines: []ftypes.Line{
+						{
+							Number:  1,
+							Content: comment,
+						},
+						{
+							Number:  2,
+							Content: `resource "aws_s3_bucket" "website" {`,
+						},
+						{
+							Number:  3,
+							Content: `  acl = "public-read"`,
+						},
+					},
+				},
+			},
+		}
+	}
+	secret := func(startLine int, lines ...string) types.DetectedSecret {
+		s := types.DetectedSecret{
+			RuleID:    "aws-access-key-id",
+			Severity:  dbTypes.SeverityHigh.String(),
+			StartLine: startLine,
+			EndLine:   startLine,
+		}
+		for i, l := range lines {
+			s.Code.Lines = append(s.Code.Lines, ftypes.Line{
+				Number:  i + 1,
+				Content: l,
+			})
+		}
+		return s
+	}
+
+	var (
+		suppressedMisconf = misconf("AVD-AWS-0086", types.MisconfStatusFailure,
+			"# trivy:ignore:AVD-AWS-0086:exp:2025-01-01 reason=The bucket hosts a public website")
+		noReasonMisconf = misconf("AVD-AWS-0087", types.MisconfStatusFailure, "# trivy:ignore:AVD-AWS-0087")
+		expiredMisconf  = misconf("AVD-AWS-0088", types.MisconfStatusFailure,
+			"# trivy:ignore:AVD-AWS-0088:exp:2020-01-01 reason=Waiting for the migration")
+		otherIDMisconf    = misconf("AVD-AWS-0089", types.MisconfStatusFailure, "# trivy:ignore:AVD-AWS-0001 reason=Unrelated")
+		terraformMisconf  = misconf("AVD-AWS-0090", types.MisconfStatusException, "# trivy:ignore:AVD-AWS-0090")
+		suppressedSecret  = secret(2, "aws:", "  access_key_id: **************** # trivy:ignore-secret reason=Test credentials")
+		tooFarAboveSecret = secret(3, "# trivy:ignore-secret reason=Test credentials", "", "access_key_id: ****************")
+	)
+
+	report := types.Report{
+		Results: types.Results{
+			{
+				Target: "main.tf",
+				Misconfigurations: []types.DetectedMisconfiguration{
+					suppressedMisconf,
+					noReasonMisconf,
+					expiredMisconf,
+					otherIDMisconf,
+					terraformMisconf,
+				},
+			},
+			{
+				Target: "config.yaml",
+				Secrets: []types.DetectedSecret{
+					suppressedSecret,
+					tooFarAboveSecret,
+				},
+			},
+		},
+	}
+
+	ctx := clock.With(context.Background(), time.Date(2020, 8, 10, 7, 28, 17, 958601, time.UTC))
+	err := result.Filter(ctx, report, result.FilterOption{
+		Severities: []dbTypes.Severity{dbTypes.SeverityHigh},
+	})
+	require.NoError(t, err)
+
+	want := types.Results{
+		{
+			Target: "main.tf",
+			MisconfSummary: &types.MisconfSummary{
+				Failures:   3,
+				Exceptions: 2,
+			},
+			Misconfigurations: []types.DetectedMisconfiguration{
+				noReasonMisconf,
+				expiredMisconf,
+				otherIDMisconf,
+			},
+			ModifiedFindings: []types.ModifiedFinding{
+				{
+					Type:      types.FindingTypeMisconfiguration,
+					Status:    types.FindingStatusIgnored,
+					Statement: "The bucket hosts a public website",
+					Source:    "main.tf:1",
+					ExpiredAt: lo.ToPtr(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+					Finding:   suppressedMisconf,
+				},
+			},
+		},
+		{
+			Target: "config.yaml",
+			Secrets: []types.DetectedSecret{
+				tooFarAboveSecret,
+			},
+			ModifiedFindings: []types.ModifiedFinding{
+				{
+					Type:      types.FindingTypeSecret,
+					Status:    types.FindingStatusIgnored,
+					Statement: "Test credentials",
+					Source:    "config.yaml:2",
+					Finding:   suppressedSecret,
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, report.Results)
+}
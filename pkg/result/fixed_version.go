@@ -0,0 +1,198 @@
+package result
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	gem "github.com/aquasecurity/go-gem-version"
+	npm "github.com/aquasecurity/go-npm-version/pkg"
+	pep440 "github.com/aquasecurity/go-pep440-version"
+	"github.com/aquasecurity/go-version/pkg/version"
+	apkver "github.com/knqyf263/go-apk-version"
+	debver "github.com/knqyf263/go-deb-version"
+	rpmver "github.com/knqyf263/go-rpm-version"
+	mvn "github.com/masahiro331/go-mvn-version"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+type versionComparer func(v1, v2 string) (int, error)
+
+// fillPkgFixedVersions sets the lowest version of each package fixing all its vulnerabilities.
+// Advisories may list several fixed versions for different release lines, e.g. "1.1.9, 1.2.8",
+// so the highest fixed version is not always the right one.
+func fillPkgFixedVersions(result *types.Result) {
+	if len(result.Vulnerabilities) == 0 {
+		return
+	}
+	compare := newVersionComparer(result.Type)
+
+	pkgVulns := make(map[string][]int)
+	var keys []string
+	for i, vuln := range result.Vulnerabilities {
+		key := fmt.Sprintf("%s/%s/%s/%s", vuln.PkgID, vuln.PkgName, vuln.InstalledVersion, vuln.PkgPath)
+		if _, ok := pkgVulns[key]; !ok {
+			keys = append(keys, key)
+		}
+		pkgVulns[key] = append(pkgVulns[key], i)
+	}
+
+	for _, key := range keys {
+		indices := pkgVulns[key]
+		var fixes [][]string
+		for _, i := range indices {
+			fixes = append(fixes, splitFixedVersions(result.Vulnerabilities[i].FixedVersion))
+		}
+		installed := result.Vulnerabilities[indices[0]].InstalledVersion
+		fixedVersion, err := pkgFixedVersion(installed, fixes, compare)
+		if err != nil {
+			log.Logger.Debugf("Unable to compute the fixed version of %s: %s", key, err)
+			continue
+		}
+		for _, i := range indices {
+			result.Vulnerabilities[i].PkgFixedVersion = fixedVersion
+		}
+	}
+}
+
+// pkgFixedVersion returns the lowest of the fixed versions which fixes all the vulnerabilities.
+// It returns an empty string if one of the vulnerabilities is not fixed yet.
+func pkgFixedVersion(installed string, fixes [][]string, compare versionComparer) (string, error) {
+	var candidates []string
+	for _, fixedVersions := range fixes {
+		if len(fixedVersions) == 0 {
+			return "", nil
+		}
+		for _, fixed := range fixedVersions {
+			// Skip the fixed versions of older release lines
+			if c, err := compare(fixed, installed); err == nil && c <= 0 {
+				continue
+			}
+			if !slices.Contains(candidates, fixed) {
+				candidates = append(candidates, fixed)
+			}
+		}
+	}
+
+	var sortErr error
+	slices.SortFunc(candidates, func(a, b string) int {
+		c, err := compare(a, b)
+		if err != nil {
+			sortErr = err
+		}
+		return c
+	})
+	if sortErr != nil {
+		return "", sortErr
+	}
+
+	for _, candidate := range candidates {
+		fixesAll := true
+		for _, fixedVersions := range fixes {
+			fixed, err := isFixed(candidate, fixedVersions, compare)
+			if err != nil {
+				return "", err
+			}
+			if !fixed {
+				fixesAll = false
+				break
+			}
+		}
+		if fixesAll {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+// isFixed checks if the version includes the fix of a vulnerability.
+// Fixed versions other than the highest one are backports, which only fix their own release line.
+func isFixed(ver string, fixedVersions []string, compare versionComparer) (bool, error) {
+	fixedInAll := true
+	for _, fixedVersion := range fixedVersions {
+		c, err := compare(ver, fixedVersion)
+		if err != nil {
+			return false, err
+		}
+		if releaseLine(fixedVersion) == releaseLine(ver) {
+			return c >= 0, nil
+		}
+		fixedInAll = fixedInAll && c >= 0
+	}
+	return fixedInAll, nil
+}
+
+// releaseLine returns the version without the last component, e.g. "1.2" for "1.2.3"
+func releaseLine(ver string) string {
+	if i := strings.LastIndex(ver, "."); i >= 0 {
+		return ver[:i]
+	}
+	return ""
+}
+
+// splitFixedVersions splits the fixed versions of an advisory, e.g. "1.1.9, >=1.2.8"
+func splitFixedVersions(fixedVersion string) []string {
+	var versions []string
+	for _, v := range strings.Split(fixedVersion, ",") {
+		v = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(v), "=>^~ "))
+		if v != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// newVersionComparer returns the version comparison of the package manager or the OS
+func newVersionComparer(targetType ftypes.TargetType) versionComparer {
+	switch targetType {
+	case ftypes.Alpine, ftypes.Wolfi, ftypes.Chainguard:
+		return compareWith(apkver.NewVersion)
+	case ftypes.Debian, ftypes.Ubuntu:
+		return func(v1, v2 string) (int, error) {
+			ver1, err := debver.NewVersion(v1)
+			if err != nil {
+				return 0, err
+			}
+			ver2, err := debver.NewVersion(v2)
+			if err != nil {
+				return 0, err
+			}
+			return ver1.Compare(ver2), nil
+		}
+	case ftypes.Alma, ftypes.Amazon, ftypes.CBLMariner, ftypes.CentOS, ftypes.Fedora, ftypes.OpenSUSE,
+		ftypes.OpenSUSELeap, ftypes.OpenSUSETumbleweed, ftypes.Oracle, ftypes.Photon, ftypes.RedHat, ftypes.Rocky,
+		ftypes.SLES:
+		return func(v1, v2 string) (int, error) {
+			return rpmver.NewVersion(v1).Compare(rpmver.NewVersion(v2)), nil
+		}
+	case ftypes.Npm, ftypes.Yarn, ftypes.Pnpm, ftypes.NodePkg, ftypes.JavaScript:
+		return compareWith(npm.NewVersion)
+	case ftypes.Pipenv, ftypes.Poetry, ftypes.Pip, ftypes.PythonPkg:
+		return compareWith(pep440.Parse)
+	case ftypes.Jar, ftypes.Pom, ftypes.Gradle:
+		return compareWith(mvn.NewVersion)
+	case ftypes.Bundler, ftypes.GemSpec, ftypes.Cocoapods:
+		return compareWith(gem.NewVersion)
+	default:
+		// Most of the other ecosystems use semver-like versions
+		return compareWith(version.Parse)
+	}
+}
+
+func compareWith[T interface{ Compare(T) int }](parse func(string) (T, error)) versionComparer {
+	return func(v1, v2 string) (int, error) {
+		ver1, err := parse(v1)
+		if err != nil {
+			return 0, err
+		}
+		ver2, err := parse(v2)
+		if err != nil {
+			return 0, err
+		}
+		return ver1.Compare(ver2), nil
+	}
+}
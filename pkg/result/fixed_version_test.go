@@ -0,0 +1,92 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func Test_fillPkgFixedVersions(t *testing.T) {
+	tests := []struct {
+		name       string
+		targetType ftypes.TargetType
+		vulns      []types.DetectedVulnerability
+		want       []string
+	}{
+		{
+			name:       "highest fixed version",
+			targetType: ftypes.Alpine,
+			vulns: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2023-0001", PkgName: "libcurl", InstalledVersion: "8.1.2-r0", FixedVersion: "8.4.0-r0"},
+				{VulnerabilityID: "CVE-2023-0002", PkgName: "libcurl", InstalledVersion: "8.1.2-r0", FixedVersion: "8.2.0-r0"},
+				{VulnerabilityID: "CVE-2023-0003", PkgName: "zlib", InstalledVersion: "1.2.13-r0", FixedVersion: "1.2.13-r1"},
+			},
+			want: []string{"8.4.0-r0", "8.4.0-r0", "1.2.13-r1"},
+		},
+		{
+			name:       "fixed in several release lines",
+			targetType: ftypes.Npm,
+			vulns: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2023-0001", PkgName: "lodash", InstalledVersion: "4.1.3", FixedVersion: "4.1.9, 4.2.8"},
+				{VulnerabilityID: "CVE-2023-0002", PkgName: "lodash", InstalledVersion: "4.1.3", FixedVersion: "4.2.6"},
+			},
+			// 4.2.6 is still affected by CVE-2023-0001
+			want: []string{"4.2.8", "4.2.8"},
+		},
+		{
+			name:       "fixed in the release line of the installed version",
+			targetType: ftypes.Pip,
+			vulns: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2023-0001", PkgName: "django", InstalledVersion: "3.2.1", FixedVersion: "3.2.19, 4.1.9"},
+				{VulnerabilityID: "CVE-2023-0002", PkgName: "django", InstalledVersion: "3.2.1", FixedVersion: ">=3.2.20, >=4.1.10"},
+			},
+			want: []string{"3.2.20", "3.2.20"},
+		},
+		{
+			name:       "not fixed yet",
+			targetType: ftypes.Debian,
+			vulns: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2023-0001", PkgName: "openssl", InstalledVersion: "3.0.9-1", FixedVersion: "3.0.11-1~deb12u1"},
+				{VulnerabilityID: "CVE-2023-0002", PkgName: "openssl", InstalledVersion: "3.0.9-1"},
+			},
+			want: []string{"", ""},
+		},
+		{
+			name:       "different paths",
+			targetType: ftypes.Jar,
+			vulns: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2021-44228", PkgName: "org.apache.logging.log4j:log4j-core", PkgPath: "app/a.jar", InstalledVersion: "2.14.1", FixedVersion: "2.15.0"},
+				{VulnerabilityID: "CVE-2021-45046", PkgName: "org.apache.logging.log4j:log4j-core", PkgPath: "app/a.jar", InstalledVersion: "2.14.1", FixedVersion: "2.16.0, 2.12.2"},
+				{VulnerabilityID: "CVE-2021-44228", PkgName: "org.apache.logging.log4j:log4j-core", PkgPath: "app/b.jar", InstalledVersion: "2.14.1", FixedVersion: "2.15.0"},
+			},
+			want: []string{"2.16.0", "2.16.0", "2.15.0"},
+		},
+		{
+			name:       "invalid version",
+			targetType: ftypes.GoModule,
+			vulns: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2023-0001", PkgName: "example.com/foo", InstalledVersion: "v1.0.0", FixedVersion: "v1.0.1"},
+				{VulnerabilityID: "CVE-2023-0002", PkgName: "example.com/foo", InstalledVersion: "v1.0.0", FixedVersion: "unknown"},
+			},
+			want: []string{"", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &types.Result{
+				Type:            tt.targetType,
+				Vulnerabilities: tt.vulns,
+			}
+			fillPkgFixedVersions(result)
+
+			var got []string
+			for _, vuln := range result.Vulnerabilities {
+				got = append(got, vuln.PkgFixedVersion)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package result
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/open-policy-agent/opa/rego"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Gate evaluates the result policy against the whole report and returns the messages of "deny" rules.
+// The report fails the gate when one or more messages are returned.
+//
+// e.g.
+//
+//	package trivy
+//
+//	deny[msg] {
+//		some result in input.Results
+//		some vuln in result.Vulnerabilities
+//		vuln.Severity == "CRITICAL"
+//		vuln.FixedVersion != ""
+//		msg := sprintf("%s in %s is fixable", [vuln.VulnerabilityID, vuln.PkgName])
+//	}
+func Gate(ctx context.Context, report types.Report, policyFile string) ([]string, error) {
+	policy, err := os.ReadFile(policyFile)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read the policy file: %w", err)
+	}
+
+	query, err := rego.New(
+		rego.Query("data.trivy.deny"),
+		rego.Module("lib.rego", module),
+		rego.Module("trivy.rego", string(policy)),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to prepare for eval: %w", err)
+	}
+
+	results, err := query.Eval(ctx, rego.EvalInput(report))
+	if err != nil {
+		return nil, xerrors.Errorf("unable to evaluate the policy: %w", err)
+	} else if len(results) == 0 {
+		// "deny" is undefined
+		return nil, nil
+	}
+
+	values, ok := results[0].Expressions[0].Value.([]interface{})
+	if !ok {
+		return nil, xerrors.New(`"deny" must be a set of strings`)
+	}
+
+	var messages []string
+	for _, v := range values {
+		msg, ok := v.(string)
+		if !ok {
+			return nil, xerrors.Errorf(`"deny" must be a set of strings, but got %v`, v)
+		}
+		messages = append(messages, msg)
+	}
+	sort.Strings(messages)
+
+	return messages, nil
+}
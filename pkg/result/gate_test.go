@@ -0,0 +1,118 @@
+package result_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/result"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func TestGate(t *testing.T) {
+	fixable := types.DetectedVulnerability{
+		VulnerabilityID:  "CVE-2019-0001",
+		PkgName:          "foo",
+		InstalledVersion: "1.2.3",
+		FixedVersion:     "1.2.4",
+		Vulnerability: dbTypes.Vulnerability{
+			Severity: dbTypes.SeverityCritical.String(),
+		},
+	}
+	unfixed := types.DetectedVulnerability{
+		VulnerabilityID:  "CVE-2019-0002",
+		PkgName:          "bar",
+		InstalledVersion: "1.2.3",
+		Vulnerability: dbTypes.Vulnerability{
+			Severity: dbTypes.SeverityCritical.String(),
+		},
+	}
+	remote := types.DetectedVulnerability{
+		VulnerabilityID:  "CVE-2019-0003",
+		PkgName:          "baz",
+		InstalledVersion: "1.2.3",
+		Vulnerability: dbTypes.Vulnerability{
+			Severity: dbTypes.SeverityCritical.String(),
+			CVSS: dbTypes.VendorCVSS{
+				vulnerability.NVD: dbTypes.CVSS{
+					V3Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+					V3Score:  9.8,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		policyFile string
+		report     types.Report
+		want       []string
+		wantErr    string
+	}{
+		{
+			name:       "fixable critical in production",
+			policyFile: "testdata/result-policy.rego",
+			report: types.Report{
+				Metadata: types.Metadata{
+					RepoTags: []string{"app:prod"},
+				},
+				Results: types.Results{
+					{
+						Target:          "app:prod (alpine 3.19.1)",
+						Vulnerabilities: []types.DetectedVulnerability{fixable, unfixed, remote},
+					},
+				},
+			},
+			want: []string{
+				"CVE-2019-0001 in foo is fixable in 1.2.4",
+				"CVE-2019-0003 is remotely exploitable",
+			},
+		},
+		{
+			name:       "fixable critical outside production",
+			policyFile: "testdata/result-policy.rego",
+			report: types.Report{
+				Metadata: types.Metadata{
+					RepoTags: []string{"app:dev"},
+				},
+				Results: types.Results{
+					{
+						Target:          "app:dev (alpine 3.19.1)",
+						Vulnerabilities: []types.DetectedVulnerability{fixable, unfixed},
+					},
+				},
+			},
+		},
+		{
+			name:       "no results",
+			policyFile: "testdata/result-policy.rego",
+			report:     types.Report{},
+		},
+		{
+			name:       "non-string message",
+			policyFile: "testdata/result-policy-invalid.rego",
+			report:     types.Report{},
+			wantErr:    `"deny" must be a set of strings`,
+		},
+		{
+			name:       "policy not found",
+			policyFile: "testdata/missing.rego",
+			wantErr:    "unable to read the policy file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := result.Gate(context.Background(), tt.report, tt.policyFile)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -12,12 +12,14 @@ import (
 
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/package-url/packageurl-go"
+	"github.com/samber/lo"
 	"golang.org/x/xerrors"
 	"gopkg.in/yaml.v3"
 
 	"github.com/aquasecurity/trivy/pkg/clock"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/purl"
+	"github.com/aquasecurity/trivy/pkg/types"
 )
 
 // IgnoreFinding represents an item to be ignored.
@@ -44,8 +46,15 @@ type IgnoreFinding struct {
 	ExpiredAt time.Time `yaml:"expired_at"`
 
 	// Statement describes the reason for ignoring the finding.
-	// required: false
+	// required: false (true with "--require-ignore-statement")
 	Statement string `yaml:"statement"`
+
+	// Owner is the person or team accountable for the exception.
+	// required: false
+	Owner string `yaml:"owner"`
+
+	// source is the file the finding comes from when it is merged from another ignore file
+	source string
 }
 
 // UnmarshalYAML is a custom unmarshaler for IgnoreFinding that handles
@@ -126,11 +135,22 @@ func matchPURL(target *packageurl.PackageURL, purls []*purl.PackageURL) bool {
 	return false
 }
 
+// modify fills in the exception details of the ignored finding
+func (i *IgnoreFinding) modify(m types.ModifiedFinding) types.ModifiedFinding {
+	m.Owner = i.Owner
+	if !i.ExpiredAt.IsZero() {
+		m.ExpiredAt = lo.ToPtr(i.ExpiredAt)
+	}
+	return m
+}
+
 func (f *IgnoreFindings) Prune(ctx context.Context) {
 	var findings IgnoreFindings
 	for _, finding := range *f {
-		// Filter out expired ignore findings
+		// Filter out expired ignore findings so that they are reported again
 		if !finding.ExpiredAt.IsZero() && finding.ExpiredAt.Before(clock.Now(ctx)) {
+			log.Logger.Warnw("The ignore rule has expired", log.String("id", finding.ID),
+				log.String("expired_at", finding.ExpiredAt.Format(time.DateOnly)), log.String("owner", finding.Owner))
 			continue
 		}
 		findings = append(findings, finding)
@@ -181,9 +201,58 @@ func (c *IgnoreConfig) MatchLicense(licenseID, filePath string) *IgnoreFinding {
 	return c.Licenses.Match(licenseID, filePath, nil)
 }
 
-func parseIgnoreFile(ctx context.Context, ignoreFile string) (IgnoreConfig, error) {
+// merge appends the findings of the other ignore file, keeping the file as their source
+func (c *IgnoreConfig) merge(other IgnoreConfig) {
+	for _, findings := range []struct {
+		dst *IgnoreFindings
+		src IgnoreFindings
+	}{
+		{&c.Vulnerabilities, other.Vulnerabilities},
+		{&c.Misconfigurations, other.Misconfigurations},
+		{&c.Secrets, other.Secrets},
+		{&c.Licenses, other.Licenses},
+	} {
+		for _, f := range findings.src {
+			f.source = other.FilePath
+			*findings.dst = append(*findings.dst, f)
+		}
+	}
+}
+
+// sourceOf returns the file the finding comes from
+func (c *IgnoreConfig) sourceOf(f *IgnoreFinding) string {
+	if f.source != "" {
+		return f.source
+	}
+	return c.FilePath
+}
+
+// validate checks if all the ignore findings have the statement
+func (c *IgnoreConfig) validate() error {
+	for _, findings := range []IgnoreFindings{
+		c.Vulnerabilities,
+		c.Misconfigurations,
+		c.Secrets,
+		c.Licenses,
+	} {
+		for _, finding := range findings {
+			if strings.TrimSpace(finding.Statement) == "" {
+				return xerrors.Errorf("the statement is required to ignore %s", finding.ID)
+			}
+		}
+	}
+	return nil
+}
+
+func parseIgnoreFile(ctx context.Context, ignoreFile string, requireStatement bool) (IgnoreConfig, error) {
 	var conf IgnoreConfig
 	if _, err := os.Stat(ignoreFile); errors.Is(err, fs.ErrNotExist) {
+		// Look for .trivyignore.yaml when the default .trivyignore doesn't exist
+		if ignoreFile == DefaultIgnoreFile {
+			if _, err = os.Stat(DefaultIgnoreYAMLFile); err == nil {
+				return parseIgnoreFile(ctx, DefaultIgnoreYAMLFile, requireStatement)
+			}
+		}
 		// .trivyignore doesn't necessarily exist
 		return IgnoreConfig{}, nil
 	} else if filepath.Ext(ignoreFile) == ".yml" || filepath.Ext(ignoreFile) == ".yaml" {
@@ -192,6 +261,9 @@ func parseIgnoreFile(ctx context.Context, ignoreFile string) (IgnoreConfig, erro
 			return IgnoreConfig{}, xerrors.Errorf("%s parse error: %w", ignoreFile, err)
 		}
 	} else {
+		if requireStatement {
+			return IgnoreConfig{}, xerrors.Errorf("%s cannot have statements, use .trivyignore.yaml instead", ignoreFile)
+		}
 		ignoredFindings, err := parseIgnore(ignoreFile)
 		if err != nil {
 			return IgnoreConfig{}, xerrors.Errorf("%s parse error: %w", ignoreFile, err)
@@ -207,6 +279,12 @@ func parseIgnoreFile(ctx context.Context, ignoreFile string) (IgnoreConfig, erro
 		}
 	}
 
+	if requireStatement {
+		if err := conf.validate(); err != nil {
+			return IgnoreConfig{}, xerrors.Errorf("%s validation error: %w", ignoreFile, err)
+		}
+	}
+
 	conf.Vulnerabilities.Prune(ctx)
 	conf.Misconfigurations.Prune(ctx)
 	conf.Secrets.Prune(ctx)
@@ -0,0 +1,148 @@
+package result
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	inlineIgnore       = "ignore"
+	inlineIgnoreSecret = "ignore-secret"
+)
+
+// e.g.
+//   - # trivy:ignore:AVD-AWS-0086 reason=The bucket hosts a public website
+//   - // trivy:ignore:AVD-AWS-0086:exp:2025-01-01 reason=Waiting for the migration
+//   - # trivy:ignore-secret reason=Test credentials
+var inlineSuppressionPattern = regexp.MustCompile(`trivy:(ignore-secret|ignore)((?::[^\s:]+)*)(?:\s+reason=(.*))?`)
+
+// inlineSuppression represents a suppression comment in the code next to a finding
+type inlineSuppression struct {
+	kind      string
+	id        string // rule ID, which is optional for secrets
+	reason    string
+	expiredAt time.Time
+	line      int
+}
+
+// parseInlineSuppressions returns the suppression comments of the given kind in the lines of the code
+func parseInlineSuppressions(kind string, code ftypes.Code, lines ...int) []inlineSuppression {
+	var suppressions []inlineSuppression
+	for _, l := range code.Lines {
+		if !slices.Contains(lines, l.Number) {
+			continue
+		}
+		s, ok := parseInlineSuppression(l.Content)
+		if !ok || s.kind != kind {
+			continue
+		}
+		s.line = l.Number
+		suppressions = append(suppressions, s)
+	}
+	return suppressions
+}
+
+func parseInlineSuppression(line string) (inlineSuppression, bool) {
+	m := inlineSuppressionPattern.FindStringSubmatch(line)
+	if m == nil {
+		return inlineSuppression{}, false
+	}
+
+	s := inlineSuppression{
+		kind:   m[1],
+		reason: strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(m[3]), "*/")),
+	}
+
+	// e.g. ":AVD-AWS-0086:exp:2025-01-01"
+	segments := strings.Split(strings.TrimPrefix(m[2], ":"), ":")
+	for i := 0; i < len(segments); i++ {
+		switch {
+		case segments[i] == "":
+		case segments[i] == "exp" && i+1 < len(segments):
+			exp, err := time.Parse(time.DateOnly, segments[i+1])
+			if err != nil {
+				log.Logger.Warnf("Invalid expiry date in the suppression comment %q: %s", strings.TrimSpace(line), err)
+				return inlineSuppression{}, false
+			}
+			s.expiredAt = exp
+			i++
+		case s.id == "":
+			s.id = segments[i]
+		}
+	}
+	return s, true
+}
+
+// valid returns false if the suppression has no reason or has expired
+func (s inlineSuppression) valid(target string, now time.Time) bool {
+	switch {
+	case s.reason == "":
+		log.Logger.Warnw("The suppression comment is ignored as it has no reason", log.String("source", s.source(target)))
+		return false
+	case !s.expiredAt.IsZero() && s.expiredAt.Before(now):
+		log.Logger.Warnw("The suppression comment has expired", log.String("source", s.source(target)),
+			log.String("expired_at", s.expiredAt.Format(time.DateOnly)))
+		return false
+	}
+	return true
+}
+
+// source returns the location of the comment, e.g. main.tf:10
+func (s inlineSuppression) source(target string) string {
+	return fmt.Sprintf("%s:%d", target, s.line)
+}
+
+func (s inlineSuppression) modify(target string, f types.ModifiedFinding) types.ModifiedFinding {
+	f.Statement = s.reason
+	f.Source = s.source(target)
+	if !s.expiredAt.IsZero() {
+		f.ExpiredAt = lo.ToPtr(s.expiredAt)
+	}
+	return f
+}
+
+// matchInlineMisconfiguration returns the suppression comment of the misconfiguration.
+// The comment can be on the start line of the misconfiguration or its enclosing block, or on the line just above them.
+func matchInlineMisconfiguration(target string, misconf types.DetectedMisconfiguration, now time.Time) *inlineSuppression {
+	cause := misconf.CauseMetadata
+	if len(cause.Code.Lines) == 0 {
+		return nil
+	}
+	blockStart := cause.Code.Lines[0].Number
+	for _, s := range parseInlineSuppressions(inlineIgnore, cause.Code, cause.StartLine-1, cause.StartLine, blockStart) {
+		switch {
+		case s.id != "*" && s.id != misconf.ID && s.id != misconf.AVDID:
+			continue
+		case misconf.Status == types.MisconfStatusException && s.reason == "":
+			// The Terraform scanner has already applied the comment, which was allowed without a reason
+			continue
+		}
+		if s.valid(target, now) {
+			return &s
+		}
+	}
+	return nil
+}
+
+// matchInlineSecret returns the suppression comment on the line of the secret or the line just above it.
+// The rule ID is optional, and the comment suppresses all the secrets on the line without it.
+func matchInlineSecret(target string, secret types.DetectedSecret, now time.Time) *inlineSuppression {
+	for _, s := range parseInlineSuppressions(inlineIgnoreSecret, secret.Code, secret.StartLine-1, secret.StartLine) {
+		if s.id != "" && s.id != secret.RuleID {
+			continue
+		}
+		if s.valid(target, now) {
+			return &s
+		}
+	}
+	return nil
+}
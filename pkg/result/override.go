@@ -0,0 +1,170 @@
+package result
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/purl"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// SeveritySourceOverride is the severity source of vulnerabilities overridden by the severity override file
+const SeveritySourceOverride dbTypes.SourceID = "override"
+
+// SeverityOverride represents the severity of a vulnerability decided by the organization.
+type SeverityOverride struct {
+	// ID is the identifier of the vulnerability, e.g. CVE-2023-0464
+	// required: true
+	ID string `yaml:"id"`
+
+	// Severity is the severity replacing the one from the vulnerability DB
+	// required: true
+	Severity string `yaml:"severity"`
+
+	// Score is the CVSS score decided by the organization
+	// required: false
+	Score float64 `yaml:"score"`
+
+	// PURLs is the list of packages the override is applied to.
+	// If PURLs is not set, the override is applied to all packages.
+	// required: false
+	PURLs []*purl.PackageURL `yaml:"-"` // Filled in UnmarshalYAML
+
+	// ImageLabels is the set of labels the scanned image must have.
+	// If ImageLabels is not set, the override is applied to all artifacts.
+	// required: false
+	ImageLabels map[string]string `yaml:"image_labels"`
+
+	// Statement describes the reason for the override.
+	// required: false
+	Statement string `yaml:"statement"`
+}
+
+// UnmarshalYAML is a custom unmarshaler for SeverityOverride that validates the severity and the score,
+// and converts PURLs from strings to purl.PackageURL objects.
+func (o *SeverityOverride) UnmarshalYAML(value *yaml.Node) error {
+	// Define a shadow type to prevent infinite recursion
+	type plain SeverityOverride
+	var tmp struct {
+		plain `yaml:",inline"`
+		PURLs []string `yaml:"purls"`
+	}
+	if err := value.Decode(&tmp); err != nil {
+		return err
+	}
+	*o = SeverityOverride(tmp.plain)
+
+	if o.ID == "" {
+		return xerrors.New("id is required in the severity override file")
+	}
+	severity, err := dbTypes.NewSeverity(strings.ToUpper(o.Severity))
+	if err != nil {
+		return xerrors.Errorf("invalid severity in the severity override file, id: %s, severity: %s", o.ID, o.Severity)
+	}
+	o.Severity = severity.String()
+	if o.Score < 0 || o.Score > 10 {
+		return xerrors.Errorf("invalid score in the severity override file, id: %s, score: %.1f", o.ID, o.Score)
+	}
+
+	for _, purlStr := range tmp.PURLs {
+		parsedPURL, err := purl.FromString(purlStr)
+		if err != nil {
+			return xerrors.Errorf("purl error in the severity override file: %w", err)
+		}
+		o.PURLs = append(o.PURLs, parsedPURL)
+	}
+	return nil
+}
+
+// SeverityOverrides represents the structure of the severity override file.
+type SeverityOverrides struct {
+	Vulnerabilities []SeverityOverride `yaml:"vulnerabilities"`
+}
+
+// Match returns the first override applicable to the vulnerability
+func (s *SeverityOverrides) Match(vuln types.DetectedVulnerability, imageLabels map[string]string) *SeverityOverride {
+	for _, o := range s.Vulnerabilities {
+		if o.ID != vuln.VulnerabilityID && !slices.Contains(vuln.VendorIDs, o.ID) {
+			continue
+		}
+		if !matchPURL(vuln.PkgIdentifier.PURL, o.PURLs) || !matchLabels(imageLabels, o.ImageLabels) {
+			continue
+		}
+		return &o
+	}
+	return nil
+}
+
+func matchLabels(labels, want map[string]string) bool {
+	for k, v := range want {
+		if got, ok := labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// apply overrides the severity, keeping the original one for auditing
+func (o *SeverityOverride) apply(vuln *types.DetectedVulnerability) {
+	log.Logger.Debugw("Severity overridden", log.String("id", vuln.VulnerabilityID),
+		log.String("from", vuln.Severity), log.String("to", o.Severity))
+
+	vuln.OriginalSeverity = &types.OriginalSeverity{
+		Severity:       vuln.Severity,
+		SeveritySource: vuln.SeveritySource,
+		Statement:      o.Statement,
+	}
+	vuln.Severity = o.Severity
+	vuln.SeveritySource = SeveritySourceOverride
+
+	if o.Score > 0 {
+		// Copy the map so that the vulnerability details shared with other packages are not modified
+		cvss := make(dbTypes.VendorCVSS)
+		maps.Copy(cvss, vuln.CVSS)
+		cvss[SeveritySourceOverride] = dbTypes.CVSS{V3Score: o.Score}
+		vuln.CVSS = cvss
+	}
+}
+
+func parseSeverityOverrideFile(overrideFile string) (SeverityOverrides, error) {
+	f, err := os.Open(overrideFile)
+	if err != nil {
+		return SeverityOverrides{}, xerrors.Errorf("file open error: %w", err)
+	}
+	defer f.Close()
+
+	var overrides SeverityOverrides
+	if err = yaml.NewDecoder(f).Decode(&overrides); err != nil {
+		return SeverityOverrides{}, xerrors.Errorf("yaml decode error: %w", err)
+	}
+	return overrides, nil
+}
+
+// overrideSeverities applies the severity override file to the vulnerabilities in the report
+func overrideSeverities(report types.Report, overrideFile string) error {
+	if overrideFile == "" {
+		return nil
+	}
+	overrides, err := parseSeverityOverrideFile(overrideFile)
+	if err != nil {
+		return xerrors.Errorf("%s parse error: %w", overrideFile, err)
+	}
+
+	labels := report.Metadata.ImageConfig.Config.Labels
+	for i := range report.Results {
+		vulns := report.Results[i].Vulnerabilities
+		for j := range vulns {
+			if o := overrides.Match(vulns[j], labels); o != nil {
+				o.apply(&vulns[j])
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package result_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/package-url/packageurl-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/result"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func newVuln(id, pkgName string, severity dbTypes.Severity) types.DetectedVulnerability {
+	return types.DetectedVulnerability{
+		VulnerabilityID:  id,
+		PkgName:          pkgName,
+		InstalledVersion: "1.2.3",
+		PkgIdentifier: ftypes.PkgIdentifier{
+			PURL: &packageurl.PackageURL{
+				Type:      packageurl.TypeGolang,
+				Namespace: "github.com/aquasecurity",
+				Name:      pkgName,
+				Version:   "1.2.3",
+			},
+		},
+		SeveritySource: "nvd",
+		Vulnerability: dbTypes.Vulnerability{
+			Severity: severity.String(),
+		},
+	}
+}
+
+func TestFilter_SeverityOverride(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   []types.DetectedVulnerability
+	}{
+		{
+			name: "without image labels",
+			want: []types.DetectedVulnerability{
+				func() types.DetectedVulnerability {
+					v := newVuln("CVE-2019-0002", "bar", dbTypes.SeverityLow)
+					v.Severity = dbTypes.SeverityCritical.String()
+					v.SeveritySource = result.SeveritySourceOverride
+					v.OriginalSeverity = &types.OriginalSeverity{
+						Severity:       dbTypes.SeverityLow.String(),
+						SeveritySource: "nvd",
+					}
+					return v
+				}(),
+				newVuln("CVE-2019-0003", "foo", dbTypes.SeverityMedium),
+				func() types.DetectedVulnerability {
+					v := newVuln("CVE-2019-0001", "foo", dbTypes.SeverityHigh)
+					v.Severity = dbTypes.SeverityLow.String()
+					v.SeveritySource = result.SeveritySourceOverride
+					v.CVSS = dbTypes.VendorCVSS{
+						result.SeveritySourceOverride: {V3Score: 3.1},
+					}
+					v.OriginalSeverity = &types.OriginalSeverity{
+						Severity:       dbTypes.SeverityHigh.String(),
+						SeveritySource: "nvd",
+						Statement:      "Not exploitable in our environment",
+					}
+					return v
+				}(),
+				newVuln("CVE-2019-0002", "foo", dbTypes.SeverityLow),
+			},
+		},
+		{
+			name: "with image labels",
+			labels: map[string]string{
+				"team": "payments",
+			},
+			want: []types.DetectedVulnerability{
+				func() types.DetectedVulnerability {
+					v := newVuln("CVE-2019-0002", "bar", dbTypes.SeverityLow)
+					v.Severity = dbTypes.SeverityCritical.String()
+					v.SeveritySource = result.SeveritySourceOverride
+					v.OriginalSeverity = &types.OriginalSeverity{
+						Severity:       dbTypes.SeverityLow.String(),
+						SeveritySource: "nvd",
+					}
+					return v
+				}(),
+				func() types.DetectedVulnerability {
+					v := newVuln("CVE-2019-0003", "foo", dbTypes.SeverityMedium)
+					v.Severity = dbTypes.SeverityCritical.String()
+					v.SeveritySource = result.SeveritySourceOverride
+					v.OriginalSeverity = &types.OriginalSeverity{
+						Severity:       dbTypes.SeverityMedium.String(),
+						SeveritySource: "nvd",
+					}
+					return v
+				}(),
+				func() types.DetectedVulnerability {
+					v := newVuln("CVE-2019-0001", "foo", dbTypes.SeverityHigh)
+					v.Severity = dbTypes.SeverityLow.String()
+					v.SeveritySource = result.SeveritySourceOverride
+					v.CVSS = dbTypes.VendorCVSS{
+						result.SeveritySourceOverride: {V3Score: 3.1},
+					}
+					v.OriginalSeverity = &types.OriginalSeverity{
+						Severity:       dbTypes.SeverityHigh.String(),
+						SeveritySource: "nvd",
+						Statement:      "Not exploitable in our environment",
+					}
+					return v
+				}(),
+				newVuln("CVE-2019-0002", "foo", dbTypes.SeverityLow),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := types.Report{
+				Metadata: types.Metadata{
+					ImageConfig: v1.ConfigFile{
+						Config: v1.Config{
+							Labels: tt.labels,
+						},
+					},
+				},
+				Results: types.Results{
+					{
+						Target: "go.mod",
+						Vulnerabilities: []types.DetectedVulnerability{
+							newVuln("CVE-2019-0001", "foo", dbTypes.SeverityHigh),
+							newVuln("CVE-2019-0002", "foo", dbTypes.SeverityLow),
+							newVuln("CVE-2019-0002", "bar", dbTypes.SeverityLow),
+							newVuln("CVE-2019-0003", "foo", dbTypes.SeverityMedium),
+						},
+					},
+				},
+			}
+			err := result.Filter(context.Background(), report, result.FilterOption{
+				Severities: []dbTypes.Severity{
+					dbTypes.SeverityLow,
+					dbTypes.SeverityMedium,
+					dbTypes.SeverityCritical,
+				},
+				SeverityOverrideFile: "testdata/severity-override.yaml",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, report.Results[0].Vulnerabilities)
+		})
+	}
+
+	t.Run("invalid severity", func(t *testing.T) {
+		err := result.Filter(context.Background(), types.Report{}, result.FilterOption{
+			SeverityOverrideFile: "testdata/severity-override-invalid.yaml",
+		})
+		require.ErrorContains(t, err, "invalid severity in the severity override file")
+	})
+}
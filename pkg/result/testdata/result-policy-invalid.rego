@@ -0,0 +1,5 @@
+package trivy
+
+deny[1] {
+	true
+}
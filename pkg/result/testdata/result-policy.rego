@@ -0,0 +1,29 @@
+package trivy
+
+import future.keywords.if
+import future.keywords.in
+
+import data.lib.trivy
+
+production if {
+	some tag in input.Metadata.RepoTags
+	endswith(tag, ":prod")
+}
+
+deny[msg] {
+	production
+	some result in input.Results
+	some vuln in result.Vulnerabilities
+	vuln.Severity == "CRITICAL"
+	vuln.FixedVersion != ""
+	msg := sprintf("%s in %s is fixable in %s", [vuln.VulnerabilityID, vuln.PkgName, vuln.FixedVersion])
+}
+
+deny[msg] {
+	some result in input.Results
+	some vuln in result.Vulnerabilities
+	vector := trivy.parse_cvss_vector_v3(vuln.CVSS.nvd.V3Vector)
+	vector.AttackVector == "Network"
+	vuln.CVSS.nvd.V3Score >= 9.8
+	msg := sprintf("%s is remotely exploitable", [vuln.VulnerabilityID])
+}
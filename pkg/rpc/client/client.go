@@ -3,11 +3,13 @@ package client
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"net/http"
 
 	"golang.org/x/xerrors"
 
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
 	r "github.com/aquasecurity/trivy/pkg/rpc"
 	"github.com/aquasecurity/trivy/pkg/types"
 	xstrings "github.com/aquasecurity/trivy/pkg/x/strings"
@@ -16,14 +18,17 @@ import (
 
 type options struct {
 	rpcClient rpc.Scanner
+	stream    bool
 }
 
 type Option func(*options)
 
-// WithRPCClient takes rpc client for testability
+// WithRPCClient takes rpc client for testability.
+// The results are not streamed with the client.
 func WithRPCClient(c rpc.Scanner) Option {
 	return func(opts *options) {
 		opts.rpcClient = c
+		opts.stream = false
 	}
 }
 
@@ -32,35 +37,54 @@ type ScannerOption struct {
 	RemoteURL     string
 	Insecure      bool
 	CustomHeaders http.Header
+
+	// TLSConfig overrides Insecure if specified
+	TLSConfig *tls.Config
 }
 
 // Scanner implements the RPC scanner
 type Scanner struct {
 	customHeaders http.Header
 	client        rpc.Scanner
+
+	// stream is the client of the scan stream, which is nil when the results are not streamed
+	stream *streamClient
 }
 
 // NewScanner is the factory method to return RPC Scanner
 func NewScanner(scannerOptions ScannerOption, opts ...Option) Scanner {
+	tlsConfig := scannerOptions.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{
+			InsecureSkipVerify: scannerOptions.Insecure,
+		}
+	}
 	httpClient := &http.Client{
 		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: scannerOptions.Insecure,
-			},
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
 		},
 	}
 
 	c := rpc.NewScannerProtobufClient(scannerOptions.RemoteURL, httpClient)
 
-	o := &options{rpcClient: c}
+	o := &options{
+		rpcClient: c,
+		stream:    true,
+	}
 	for _, opt := range opts {
 		opt(o)
 	}
 
+	var stream *streamClient
+	if o.stream {
+		stream = newStreamClient(scannerOptions.RemoteURL, httpClient)
+	}
+
 	return Scanner{
 		customHeaders: scannerOptions.CustomHeaders,
 		client:        o.rpcClient,
+		stream:        stream,
 	}
 }
 
@@ -74,21 +98,31 @@ func (s Scanner) Scan(ctx context.Context, target, artifactKey string, blobKeys
 		licenseCategories[string(category)] = &rpc.Licenses{Names: names}
 	}
 
+	req := &rpc.ScanRequest{
+		Target:     target,
+		ArtifactId: artifactKey,
+		BlobIds:    blobKeys,
+		Options: &rpc.ScanOptions{
+			VulnType:          opts.VulnType,
+			Scanners:          xstrings.ToStringSlice(opts.Scanners),
+			ListAllPackages:   opts.ListAllPackages,
+			LicenseCategories: licenseCategories,
+			IncludeDevDeps:    opts.IncludeDevDeps,
+		},
+	}
+
 	var res *rpc.ScanResponse
 	err := r.Retry(func() error {
 		var err error
-		res, err = s.client.Scan(ctx, &rpc.ScanRequest{
-			Target:     target,
-			ArtifactId: artifactKey,
-			BlobIds:    blobKeys,
-			Options: &rpc.ScanOptions{
-				VulnType:          opts.VulnType,
-				Scanners:          xstrings.ToStringSlice(opts.Scanners),
-				ListAllPackages:   opts.ListAllPackages,
-				LicenseCategories: licenseCategories,
-				IncludeDevDeps:    opts.IncludeDevDeps,
-			},
-		})
+		if s.stream != nil {
+			res, err = s.stream.Scan(ctx, s.customHeaders, req)
+			if !errors.Is(err, errStreamUnsupported) {
+				return err
+			}
+			// Servers before the scan stream return the results in a single message
+			log.Logger.Debug("The server doesn't support the scan stream")
+		}
+		res, err = s.client.Scan(ctx, req)
 		return err
 	})
 	if err != nil {
@@ -9,15 +9,18 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/NYTimes/gziphandler"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/twitchtv/twirp"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/utils"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	rpcutil "github.com/aquasecurity/trivy/pkg/rpc"
 	"github.com/aquasecurity/trivy/pkg/types"
 	"github.com/aquasecurity/trivy/rpc/common"
 	rpc "github.com/aquasecurity/trivy/rpc/scanner"
@@ -253,3 +256,118 @@ func TestScanner_ScanServerInsecure(t *testing.T) {
 		})
 	}
 }
+
+type fakeScanner struct {
+	res *rpc.ScanResponse
+}
+
+func (s fakeScanner) Scan(_ context.Context, _ *rpc.ScanRequest) (*rpc.ScanResponse, error) {
+	return s.res, nil
+}
+
+func TestScanner_ScanStream(t *testing.T) {
+	res := &rpc.ScanResponse{
+		Os: &common.OS{
+			Family: "alpine",
+			Name:   "3.19.1",
+		},
+		Results: []*rpc.Result{
+			{
+				Target: "alpine:3.19 (alpine 3.19.1)",
+				Class:  "os-pkgs",
+				Type:   "alpine",
+				Vulnerabilities: []*common.Vulnerability{
+					{
+						VulnerabilityId:  "CVE-2024-0727",
+						PkgName:          "openssl",
+						InstalledVersion: "3.1.4-r4",
+						FixedVersion:     "3.1.4-r5",
+						Severity:         common.Severity_MEDIUM,
+					},
+				},
+			},
+		},
+	}
+	wantResults := types.Results{
+		{
+			Target: "alpine:3.19 (alpine 3.19.1)",
+			Class:  types.ClassOSPkg,
+			Type:   ftypes.Alpine,
+			Vulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID:  "CVE-2024-0727",
+					PkgName:          "openssl",
+					InstalledVersion: "3.1.4-r4",
+					FixedVersion:     "3.1.4-r5",
+					Vulnerability: dbTypes.Vulnerability{
+						Severity:       "MEDIUM",
+						VendorSeverity: dbTypes.VendorSeverity{},
+						CVSS:           dbTypes.VendorCVSS{},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		streamFunc http.HandlerFunc // The server doesn't support the scan stream when nil
+		wantStream bool
+		wantErr    string
+	}{
+		{
+			name: "happy path",
+			streamFunc: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Trivy-Token") != "token" {
+					rpc.WriteError(w, twirp.NewError(twirp.Unauthenticated, "invalid token"))
+					return
+				}
+				w.Header().Set("Content-Type", rpcutil.ScanStreamContentType)
+				require.NoError(t, rpcutil.WriteScanStream(w, res))
+			},
+			wantStream: true,
+		},
+		{
+			name: "fall back to the RPC",
+		},
+		{
+			name: "sad path: stream error",
+			streamFunc: func(w http.ResponseWriter, r *http.Request) {
+				rpc.WriteError(w, twirp.NewError(twirp.Unauthenticated, "invalid token"))
+			},
+			wantErr: "twirp error unauthenticated: invalid token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var streamed bool
+			mux := http.NewServeMux()
+			mux.Handle(rpc.ScannerPathPrefix, rpc.NewScannerServer(fakeScanner{res: res}))
+			if tt.streamFunc != nil {
+				mux.Handle(rpcutil.ScanStreamPath, gziphandler.GzipHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					streamed = true
+					tt.streamFunc(w, r)
+				})))
+			}
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+
+			s := NewScanner(ScannerOption{
+				RemoteURL:     ts.URL,
+				CustomHeaders: http.Header{"Trivy-Token": []string{"token"}},
+			})
+			gotResults, gotOS, err := s.Scan(context.Background(), "alpine:3.19", "sha256:artifact", nil, types.ScanOptions{})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStream, streamed)
+			assert.Equal(t, wantResults, gotResults)
+			assert.Equal(t, ftypes.OS{
+				Family: ftypes.Alpine,
+				Name:   "3.19.1",
+			}, gotOS)
+		})
+	}
+}
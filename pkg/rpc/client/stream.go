@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/twitchtv/twirp"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/proto"
+
+	r "github.com/aquasecurity/trivy/pkg/rpc"
+	rpc "github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+// errStreamUnsupported is returned when the server doesn't have the scan stream endpoint
+var errStreamUnsupported = errors.New("scan stream unsupported")
+
+// streamClient requests the scan stream, which returns the results in chunks
+// so that large reports are not limited by the message size.
+type streamClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+func newStreamClient(remoteURL string, httpClient *http.Client) *streamClient {
+	return &streamClient{
+		url:        strings.TrimSuffix(remoteURL, "/") + r.ScanStreamPath,
+		httpClient: httpClient,
+	}
+}
+
+// Scan scans in the same way as the Scan RPC.
+// The errors are returned as twirp errors in the same way as the RPC client so that they are retried alike.
+func (c *streamClient) Scan(ctx context.Context, customHeaders http.Header, in *rpc.ScanRequest) (*rpc.ScanResponse, error) {
+	b, err := proto.Marshal(in)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal the scan request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(b))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create a scan stream request: %w", err)
+	}
+	for key, values := range customHeaders {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/protobuf")
+
+	// The response is decompressed by the transport
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, twirp.InternalErrorWith(xerrors.Errorf("failed to do request: %w", err))
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && resp.Header.Get("Content-Type") != "application/json":
+		return nil, errStreamUnsupported
+	case resp.StatusCode != http.StatusOK:
+		return nil, responseError(resp)
+	}
+
+	res, err := r.ReadScanStream(resp.Body)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read the scan stream: %w", err)
+	}
+	return res, nil
+}
+
+// responseError converts the twirp error in the response body
+func responseError(resp *http.Response) error {
+	var twerr struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(b, &twerr); err != nil || twerr.Code == "" {
+		return twirp.NewError(twirp.Unknown, http.StatusText(resp.StatusCode))
+	}
+	return twirp.NewError(twirp.ErrorCode(twerr.Code), twerr.Msg)
+}
@@ -144,15 +144,16 @@ func ConvertToRPCSecretFindings(findings []ftypes.SecretFinding) []*common.Secre
 	var rpcFindings []*common.SecretFinding
 	for _, f := range findings {
 		rpcFindings = append(rpcFindings, &common.SecretFinding{
-			RuleId:    f.RuleID,
-			Category:  string(f.Category),
-			Severity:  f.Severity,
-			Title:     f.Title,
-			EndLine:   int32(f.EndLine),
-			StartLine: int32(f.StartLine),
-			Code:      ConvertToRPCCode(f.Code),
-			Match:     f.Match,
-			Layer:     ConvertToRPCLayer(f.Layer),
+			RuleId:      f.RuleID,
+			Category:    string(f.Category),
+			Severity:    f.Severity,
+			Title:       f.Title,
+			EndLine:     int32(f.EndLine),
+			StartLine:   int32(f.StartLine),
+			Code:        ConvertToRPCCode(f.Code),
+			Match:       f.Match,
+			Layer:       ConvertToRPCLayer(f.Layer),
+			Fingerprint: f.Fingerprint,
 		})
 	}
 	return rpcFindings
@@ -174,6 +175,55 @@ func ConvertToRPCLicenseFiles(licenses []ftypes.LicenseFile) []*common.LicenseFi
 	return rpcLicenses
 }
 
+func ConvertToRPCCertificateInventories(inventories []ftypes.CertificateInventory) []*common.CertificateInventory {
+	var rpcInventories []*common.CertificateInventory
+	for _, inventory := range inventories {
+		rpcInventories = append(rpcInventories, &common.CertificateInventory{
+			FilePath:     inventory.FilePath,
+			FileType:     string(inventory.FileType),
+			Certificates: ConvertToRPCCertificates(inventory.Certificates),
+			PrivateKeys:  ConvertToRPCPrivateKeys(inventory.PrivateKeys),
+		})
+	}
+	return rpcInventories
+}
+
+func ConvertToRPCCertificates(certs []ftypes.Certificate) []*common.Certificate {
+	var rpcCerts []*common.Certificate
+	for _, cert := range certs {
+		rpcCerts = append(rpcCerts, &common.Certificate{
+			Subject:            cert.Subject,
+			CommonName:         cert.CommonName,
+			Issuer:             cert.Issuer,
+			SerialNumber:       cert.SerialNumber,
+			NotBefore:          timestamppb.New(cert.NotBefore),
+			NotAfter:           timestamppb.New(cert.NotAfter),
+			KeyAlgorithm:       cert.KeyAlgorithm,
+			KeySize:            int32(cert.KeySize),
+			SignatureAlgorithm: cert.SignatureAlgorithm,
+			SelfSigned:         cert.SelfSigned,
+			IsCa:               cert.IsCA,
+			StartLine:          int32(cert.StartLine),
+			EndLine:            int32(cert.EndLine),
+		})
+	}
+	return rpcCerts
+}
+
+func ConvertToRPCPrivateKeys(keys []ftypes.PrivateKey) []*common.PrivateKey {
+	var rpcKeys []*common.PrivateKey
+	for _, key := range keys {
+		rpcKeys = append(rpcKeys, &common.PrivateKey{
+			Algorithm: key.Algorithm,
+			KeySize:   int32(key.KeySize),
+			Encrypted: key.Encrypted,
+			StartLine: int32(key.StartLine),
+			EndLine:   int32(key.EndLine),
+		})
+	}
+	return rpcKeys
+}
+
 func ConvertToRPCLicenseFindings(findings ftypes.LicenseFindings) []*common.LicenseFinding {
 	var rpcFindings []*common.LicenseFinding
 
@@ -395,6 +445,8 @@ func ConvertFromRPCResults(rpcResults []*scanner.Result) []types.Result {
 			CustomResources:   ConvertFromRPCCustomResources(result.CustomResources),
 			Secrets:           ConvertFromRPCDetectedSecrets(result.Secrets),
 			Licenses:          ConvertFromRPCDetectedLicenses(result.Licenses),
+			Certificates:      ConvertFromRPCCertificates(result.Certificates),
+			PrivateKeys:       ConvertFromRPCPrivateKeys(result.PrivateKeys),
 		})
 	}
 	return results
@@ -487,6 +539,7 @@ func ConvertFromRPCSecretFindings(rpcFindings []*common.SecretFinding) []ftypes.
 				DiffID:    finding.Layer.DiffId,
 				CreatedBy: finding.Layer.CreatedBy,
 			},
+			Fingerprint: finding.Fingerprint,
 		})
 	}
 	return findings
@@ -519,6 +572,55 @@ func ConvertFromRPCLicenseFiles(rpcLicenses []*common.LicenseFile) []ftypes.Lice
 	return licenses
 }
 
+func ConvertFromRPCCertificateInventories(rpcInventories []*common.CertificateInventory) []ftypes.CertificateInventory {
+	var inventories []ftypes.CertificateInventory
+	for _, inventory := range rpcInventories {
+		inventories = append(inventories, ftypes.CertificateInventory{
+			FilePath:     inventory.FilePath,
+			FileType:     ftypes.ConfigType(inventory.FileType),
+			Certificates: ConvertFromRPCCertificates(inventory.Certificates),
+			PrivateKeys:  ConvertFromRPCPrivateKeys(inventory.PrivateKeys),
+		})
+	}
+	return inventories
+}
+
+func ConvertFromRPCCertificates(rpcCerts []*common.Certificate) []ftypes.Certificate {
+	var certs []ftypes.Certificate
+	for _, cert := range rpcCerts {
+		certs = append(certs, ftypes.Certificate{
+			Subject:            cert.Subject,
+			CommonName:         cert.CommonName,
+			Issuer:             cert.Issuer,
+			SerialNumber:       cert.SerialNumber,
+			NotBefore:          cert.NotBefore.AsTime(),
+			NotAfter:           cert.NotAfter.AsTime(),
+			KeyAlgorithm:       cert.KeyAlgorithm,
+			KeySize:            int(cert.KeySize),
+			SignatureAlgorithm: cert.SignatureAlgorithm,
+			SelfSigned:         cert.SelfSigned,
+			IsCA:               cert.IsCa,
+			StartLine:          int(cert.StartLine),
+			EndLine:            int(cert.EndLine),
+		})
+	}
+	return certs
+}
+
+func ConvertFromRPCPrivateKeys(rpcKeys []*common.PrivateKey) []ftypes.PrivateKey {
+	var keys []ftypes.PrivateKey
+	for _, key := range rpcKeys {
+		keys = append(keys, ftypes.PrivateKey{
+			Algorithm: key.Algorithm,
+			KeySize:   int(key.KeySize),
+			Encrypted: key.Encrypted,
+			StartLine: int(key.StartLine),
+			EndLine:   int(key.EndLine),
+		})
+	}
+	return keys
+}
+
 func ConvertFromRPCLicenseFindings(rpcFindings []*common.LicenseFinding) ftypes.LicenseFindings {
 	var findings ftypes.LicenseFindings
 
@@ -781,6 +883,7 @@ func ConvertFromRPCPutBlobRequest(req *cache.PutBlobRequest) ftypes.BlobInfo {
 		CustomResources:   ConvertFromRPCCustomResources(req.BlobInfo.CustomResources),
 		Secrets:           ConvertFromRPCSecrets(req.BlobInfo.Secrets),
 		Licenses:          ConvertFromRPCLicenseFiles(req.BlobInfo.Licenses),
+		Certificates:      ConvertFromRPCCertificateInventories(req.BlobInfo.Certificates),
 	}
 }
 
@@ -892,6 +995,7 @@ func ConvertToRPCPutBlobRequest(diffID string, blobInfo ftypes.BlobInfo) *cache.
 			CustomResources:   customResources,
 			Secrets:           ConvertToRPCSecrets(blobInfo.Secrets),
 			Licenses:          ConvertToRPCLicenseFiles(blobInfo.Licenses),
+			Certificates:      ConvertToRPCCertificateInventories(blobInfo.Certificates),
 		},
 	}
 }
@@ -934,6 +1038,8 @@ func ConvertToRPCScanResponse(results types.Results, fos ftypes.OS) *scanner.Sca
 			Misconfigurations: ConvertToRPCMisconfs(result.Misconfigurations),
 			Secrets:           ConvertToRPCSecretFindings(secretFindings),
 			Licenses:          ConvertToRPCLicenses(result.Licenses),
+			Certificates:      ConvertToRPCCertificates(result.Certificates),
+			PrivateKeys:       ConvertToRPCPrivateKeys(result.PrivateKeys),
 			CustomResources:   ConvertToRPCCustomResources(result.CustomResources),
 		})
 	}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/result"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// AdmissionPathPrefix is the path prefix of the endpoint reviewing the objects of validating admission webhooks.
+// The policy is the rest of the path, e.g. /admission/no-critical, as webhook URLs can't have query parameters.
+const AdmissionPathPrefix = "/admission/"
+
+// admissionHandler reviews the workloads of AdmissionReview requests.
+// The misconfigurations of the manifest and the vulnerabilities of the images are evaluated together
+// by the verdict policy, and the images share the scan results and the latency budget with the verdict endpoint.
+type admissionHandler struct {
+	verdicts *verdictHandler
+}
+
+func newAdmissionHandler(verdicts *verdictHandler) *admissionHandler {
+	return &admissionHandler{verdicts: verdicts}
+}
+
+func (h *admissionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	policyFile, err := h.verdicts.policyFile(strings.TrimPrefix(r.URL.Path, AdmissionPathPrefix))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var review admissionv1.AdmissionReview
+	if err = json.NewDecoder(r.Body).Decode(&review); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	} else if review.Request == nil {
+		http.Error(w, "invalid request: no admission request", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.review(r.Context(), review.Request, policyFile)
+	if err != nil {
+		log.Logger.Errorf("Admission review error (%s): %s", review.Request.UID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(admissionv1.AdmissionReview{
+		TypeMeta: review.TypeMeta,
+		Response: res,
+	}); err != nil {
+		log.Logger.Errorf("Admission review response error: %s", err)
+	}
+}
+
+// review evaluates the policy against the scan results of the object in the request.
+// The request is allowed with warnings when the results are incomplete and the verdicts fail open.
+func (h *admissionHandler) review(ctx context.Context, req *admissionv1.AdmissionRequest, policyFile string) (*admissionv1.AdmissionResponse, error) {
+	res := &admissionv1.AdmissionResponse{
+		UID:     req.UID,
+		Allowed: true,
+	}
+	// Deleted objects have nothing to be reviewed
+	if len(req.Object.Raw) == 0 {
+		return res, nil
+	}
+
+	var obj unstructured.Unstructured
+	if err := obj.UnmarshalJSON(req.Object.Raw); err != nil {
+		return nil, xerrors.Errorf("object decode error: %w", err)
+	}
+
+	budget, cancel := context.WithTimeout(context.Background(), h.verdicts.opts.Timeout)
+	defer cancel()
+
+	report := types.Report{
+		ArtifactName: fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName()),
+	}
+	var undecided []string
+	if h.verdicts.opts.ScanManifest != nil {
+		// JSON is valid YAML for the Kubernetes scanner
+		manifest, err := h.verdicts.opts.ScanManifest(ctx, req.Object.Raw)
+		if err != nil {
+			undecided = append(undecided, fmt.Sprintf("misconfiguration scan error: %s", err))
+		}
+		report.Results = append(report.Results, manifest.Results...)
+	}
+
+	for _, image := range workloadImages(obj) {
+		ref, err := h.resolve(ctx, image)
+		if err != nil {
+			undecided = append(undecided, fmt.Sprintf("unable to resolve %s: %s", image, err))
+			continue
+		}
+		imageReport, _, err := h.verdicts.imageReport(ctx, ref, budget.Done())
+		var u *undecidedError
+		if errors.As(err, &u) {
+			undecided = append(undecided, fmt.Sprintf("%s: %s", image, u.reason))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		report.Results = append(report.Results, imageReport.Results...)
+	}
+
+	messages, err := result.Gate(ctx, report, policyFile)
+	if err != nil {
+		return nil, xerrors.Errorf("policy error: %w", err)
+	}
+	if len(undecided) > 0 && !h.verdicts.opts.FailOpen {
+		messages = append(messages, undecided...)
+	}
+	if len(messages) > 0 {
+		res.Allowed = false
+		res.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusForbidden,
+			Reason:  metav1.StatusReasonForbidden,
+			Message: "Trivy: " + strings.Join(messages, "; "),
+		}
+		return res, nil
+	}
+	res.Warnings = undecided
+	return res, nil
+}
+
+// resolve returns the digest of the image, which is looked up in the registry unless the image is referenced by digest
+func (h *admissionHandler) resolve(ctx context.Context, image string) (name.Digest, error) {
+	if ref, err := name.NewDigest(image); err == nil {
+		return ref, nil
+	} else if h.verdicts.opts.Resolve == nil {
+		return name.Digest{}, xerrors.New("the image must be referenced by digest")
+	}
+	return h.verdicts.opts.Resolve(ctx, image)
+}
+
+// workloadImages returns the images of the containers in the workload object
+func workloadImages(obj unstructured.Unstructured) []string {
+	var keys []string
+	switch obj.GetKind() {
+	case "Pod":
+		keys = []string{"spec"}
+	case "CronJob":
+		keys = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	case "Deployment", "ReplicaSet", "ReplicationController", "StatefulSet", "DaemonSet", "Job":
+		keys = []string{"spec", "template", "spec"}
+	default:
+		return nil
+	}
+
+	var images []string
+	for _, containerType := range []string{"initContainers", "containers", "ephemeralContainers"} {
+		containers, _, _ := unstructured.NestedSlice(obj.Object, append(keys, containerType)...)
+		for _, c := range containers {
+			container, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			if image, _, _ := unstructured.NestedString(container, "image"); image != "" {
+				images = append(images, image)
+			}
+		}
+	}
+	return lo.Uniq(images)
+}
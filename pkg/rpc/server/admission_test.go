@@ -0,0 +1,244 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func admissionReview(object string) string {
+	return `{
+  "apiVersion": "admission.k8s.io/v1",
+  "kind": "AdmissionReview",
+  "request": {
+    "uid": "705ab4f5-6393-11e8-b7cc-42010a800002",
+    "operation": "CREATE",
+    "object": ` + object + `
+  }
+}`
+}
+
+const deployment = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {"name": "web", "namespace": "default"},
+  "spec": {
+    "template": {
+      "spec": {
+        "initContainers": [{"name": "init", "image": "ghcr.io/org/app:1.0"}],
+        "containers": [
+          {"name": "app", "image": "ghcr.io/org/app:1.0"},
+          {"name": "sidecar", "image": "ghcr.io/org/sidecar@` + testImageDigest + `"}
+        ]
+      }
+    }
+  }
+}`
+
+func Test_admissionHandler(t *testing.T) {
+	criticalReport := types.Report{
+		Results: types.Results{
+			{
+				Target: "ghcr.io/org/app:1.0",
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID: "CVE-2024-0001",
+						PkgName:         "musl",
+						Vulnerability: dbTypes.Vulnerability{
+							Severity: "CRITICAL",
+						},
+					},
+				},
+			},
+		},
+	}
+	privilegedReport := types.Report{
+		Results: types.Results{
+			{
+				Target: "manifest.yaml",
+				Misconfigurations: []types.DetectedMisconfiguration{
+					{
+						ID:       "KSV017",
+						Message:  "Container 'app' should set 'securityContext.privileged' to false",
+						Severity: "HIGH",
+						Status:   types.MisconfStatusFailure,
+					},
+				},
+			},
+		},
+	}
+	resolve := func(ctx context.Context, image string) (name.Digest, error) {
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			return name.Digest{}, err
+		}
+		return ref.Context().Digest(testImageDigest), nil
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		body         string
+		failOpen     bool
+		scan         ImageScanFunc
+		scanManifest ManifestScanFunc
+		resolve      DigestResolveFunc
+		wantStatus   int
+		want         *admissionv1.AdmissionResponse
+	}{
+		{
+			name: "allowed",
+			path: "no-critical-workload",
+			body: admissionReview(deployment),
+			scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+				return types.Report{ArtifactName: imageRef}, nil
+			},
+			scanManifest: func(ctx context.Context, manifest []byte) (types.Report, error) {
+				return types.Report{}, nil
+			},
+			resolve:    resolve,
+			wantStatus: http.StatusOK,
+			want: &admissionv1.AdmissionResponse{
+				UID:     "705ab4f5-6393-11e8-b7cc-42010a800002",
+				Allowed: true,
+			},
+		},
+		{
+			name: "denied by vulnerabilities and misconfigurations",
+			path: "no-critical-workload",
+			body: admissionReview(deployment),
+			scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+				return criticalReport, nil
+			},
+			scanManifest: func(ctx context.Context, manifest []byte) (types.Report, error) {
+				return privilegedReport, nil
+			},
+			resolve:    resolve,
+			wantStatus: http.StatusOK,
+			want: &admissionv1.AdmissionResponse{
+				UID:     "705ab4f5-6393-11e8-b7cc-42010a800002",
+				Allowed: false,
+				Result: &metav1.Status{
+					Status:  metav1.StatusFailure,
+					Code:    http.StatusForbidden,
+					Reason:  metav1.StatusReasonForbidden,
+					Message: "Trivy: CVE-2024-0001 in musl is critical; KSV017: Container 'app' should set 'securityContext.privileged' to false",
+				},
+			},
+		},
+		{
+			name:     "pending with fail-open",
+			path:     "no-critical",
+			body:     admissionReview(deployment),
+			failOpen: true,
+			scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+				<-ctx.Done()
+				return types.Report{}, ctx.Err()
+			},
+			resolve:    resolve,
+			wantStatus: http.StatusOK,
+			want: &admissionv1.AdmissionResponse{
+				UID:     "705ab4f5-6393-11e8-b7cc-42010a800002",
+				Allowed: true,
+				Warnings: []string{
+					"ghcr.io/org/app:1.0: the scan did not complete within the latency budget",
+					"ghcr.io/org/sidecar@" + testImageDigest + ": the scan did not complete within the latency budget",
+				},
+			},
+		},
+		{
+			name: "unresolved tag with fail-closed",
+			path: "no-critical",
+			body: admissionReview(deployment),
+			scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+				return types.Report{}, nil
+			},
+			resolve: func(ctx context.Context, image string) (name.Digest, error) {
+				return name.Digest{}, xerrors.New("unauthorized")
+			},
+			wantStatus: http.StatusOK,
+			want: &admissionv1.AdmissionResponse{
+				UID:     "705ab4f5-6393-11e8-b7cc-42010a800002",
+				Allowed: false,
+				Result: &metav1.Status{
+					Status:  metav1.StatusFailure,
+					Code:    http.StatusForbidden,
+					Reason:  metav1.StatusReasonForbidden,
+					Message: "Trivy: unable to resolve ghcr.io/org/app:1.0: unauthorized",
+				},
+			},
+		},
+		{
+			name:       "deleted object",
+			path:       "no-critical",
+			body:       admissionReview("null"),
+			wantStatus: http.StatusOK,
+			want: &admissionv1.AdmissionResponse{
+				UID:     "705ab4f5-6393-11e8-b7cc-42010a800002",
+				Allowed: true,
+			},
+		},
+		{
+			name:       "no request",
+			path:       "no-critical",
+			body:       `{"apiVersion": "admission.k8s.io/v1", "kind": "AdmissionReview"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown policy",
+			path:       "unknown",
+			body:       admissionReview(deployment),
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			verdicts := newVerdictHandler(ctx, VerdictOptions{
+				PolicyDir:    "testdata/verdict",
+				Timeout:      100 * time.Millisecond,
+				FailOpen:     tt.failOpen,
+				Scan:         tt.scan,
+				ScanManifest: tt.scanManifest,
+				Resolve:      tt.resolve,
+			}, &sync.WaitGroup{}, &sync.WaitGroup{})
+
+			mux := http.NewServeMux()
+			mux.Handle(AdmissionPathPrefix, newAdmissionHandler(verdicts))
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+
+			resp, err := http.Post(ts.URL+AdmissionPathPrefix+tt.path, "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got admissionv1.AdmissionReview
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+			assert.Equal(t, "admission.k8s.io/v1", got.APIVersion)
+			assert.Equal(t, "AdmissionReview", got.Kind)
+			assert.Equal(t, tt.want, got.Response)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/twitchtv/twirp"
+	"golang.org/x/time/rate"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/rpc"
+	rpcScanner "github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+// defaultTenant is the tenant of the token shared by all clients
+const defaultTenant = "default"
+
+// AuthOptions configures the authentication of requests.
+// Requests are not authenticated when neither Token nor Config is set.
+type AuthOptions struct {
+	Token       string     // The token shared by all clients
+	TokenHeader string     // The header carrying static tokens
+	Config      AuthConfig // The tenants authenticated separately
+}
+
+// AuthConfig is the file of '--auth-config'
+type AuthConfig struct {
+	Tenants []TenantConfig `yaml:"tenants"`
+	OIDC    *OIDCConfig    `yaml:"oidc"`
+}
+
+// TenantConfig is a team sharing the rate limit of scan requests
+type TenantConfig struct {
+	Name      string   `yaml:"name"`
+	Tokens    []string `yaml:"tokens"`     // Static tokens sent in the token header
+	RateLimit int      `yaml:"rate-limit"` // Scan requests per minute (0 = unlimited)
+}
+
+// OIDCConfig authenticates ID tokens sent as "Authorization: Bearer <token>".
+// The tenant is the value of TenantClaim, which must be one of the configured tenants.
+type OIDCConfig struct {
+	Issuer      string `yaml:"issuer"`
+	Audience    string `yaml:"audience"`
+	TenantClaim string `yaml:"tenant-claim"` // Default is "sub"
+}
+
+// LoadAuthConfig loads the tenants from the YAML file
+func LoadAuthConfig(path string) (AuthConfig, error) {
+	var config AuthConfig
+	if path == "" {
+		return config, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return AuthConfig{}, xerrors.Errorf("failed to read the auth config: %w", err)
+	}
+	if err = yaml.Unmarshal(b, &config); err != nil {
+		return AuthConfig{}, xerrors.Errorf("failed to parse the auth config: %w", err)
+	}
+
+	names := make(map[string]bool)
+	tokens := make(map[string]bool)
+	for _, t := range config.Tenants {
+		switch {
+		case t.Name == "":
+			return AuthConfig{}, xerrors.New("tenant name is required")
+		case names[t.Name]:
+			return AuthConfig{}, xerrors.Errorf("duplicate tenant: %s", t.Name)
+		case t.RateLimit < 0:
+			return AuthConfig{}, xerrors.Errorf("negative rate limit of the tenant %s", t.Name)
+		}
+		names[t.Name] = true
+
+		for _, token := range t.Tokens {
+			if token == "" || tokens[token] {
+				return AuthConfig{}, xerrors.Errorf("empty or duplicate token of the tenant %s", t.Name)
+			}
+			tokens[token] = true
+		}
+	}
+
+	if config.OIDC != nil {
+		if config.OIDC.Issuer == "" || config.OIDC.Audience == "" {
+			return AuthConfig{}, xerrors.New("OIDC issuer and audience are required")
+		}
+		if config.OIDC.TenantClaim == "" {
+			config.OIDC.TenantClaim = "sub"
+		}
+	}
+	return config, nil
+}
+
+type tenant struct {
+	name    string
+	limiter *rate.Limiter // nil if unlimited
+}
+
+type tenantKey struct{}
+
+// tenantName returns the name of the tenant authenticated for the request context.
+// It is empty when authentication is disabled.
+func tenantName(ctx context.Context) string {
+	name, _ := ctx.Value(tenantKey{}).(string)
+	return name
+}
+
+// authenticator identifies the tenant of requests.
+// Scan requests are rate-limited per tenant and written to the audit log.
+type authenticator struct {
+	tokenHeader string
+	tokens      map[string]*tenant // by static token
+	tenants     map[string]*tenant // by name
+	oidc        *oidcVerifier
+}
+
+// newAuthenticator returns nil when authentication is disabled
+func newAuthenticator(opts AuthOptions) *authenticator {
+	if opts.Token == "" && len(opts.Config.Tenants) == 0 && opts.Config.OIDC == nil {
+		return nil
+	}
+
+	a := &authenticator{
+		tokenHeader: opts.TokenHeader,
+		tokens:      make(map[string]*tenant),
+		tenants:     make(map[string]*tenant),
+	}
+	if opts.Token != "" {
+		a.tokens[opts.Token] = &tenant{name: defaultTenant}
+	}
+	for _, tc := range opts.Config.Tenants {
+		t := &tenant{name: tc.Name}
+		if tc.RateLimit > 0 {
+			t.limiter = rate.NewLimiter(rate.Limit(float64(tc.RateLimit)/60), tc.RateLimit)
+		}
+		a.tenants[tc.Name] = t
+		for _, token := range tc.Tokens {
+			a.tokens[token] = t
+		}
+	}
+	if opts.Config.OIDC != nil {
+		a.oidc = newOIDCVerifier(*opts.Config.OIDC)
+	}
+	return a
+}
+
+func (a *authenticator) authenticate(r *http.Request) (*tenant, error) {
+	if token := r.Header.Get(a.tokenHeader); token != "" {
+		if t, ok := a.tokens[token]; ok {
+			return t, nil
+		}
+	}
+
+	if a.oidc != nil {
+		if raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			name, err := a.oidc.verify(r.Context(), raw)
+			if err != nil {
+				return nil, xerrors.Errorf("OIDC error: %w", err)
+			}
+			if t, ok := a.tenants[name]; ok {
+				return t, nil
+			}
+			return nil, xerrors.Errorf("unknown tenant: %s", name)
+		}
+	}
+	return nil, xerrors.New("invalid token")
+}
+
+// handler authenticates requests to base. Requests are passed through when a is nil.
+func (a *authenticator) handler(base http.Handler) http.Handler {
+	if a == nil {
+		return base
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t, err := a.authenticate(r)
+		if err != nil {
+			log.Logger.Warnw("Unauthenticated request", "path", r.URL.Path, "remote", r.RemoteAddr, "err", err)
+			rpcScanner.WriteError(w, twirp.NewError(twirp.Unauthenticated, "invalid token"))
+			return
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), tenantKey{}, t.name))
+		if !isScanRequest(r) {
+			base.ServeHTTP(w, r)
+			return
+		}
+
+		if t.limiter != nil && !t.limiter.Allow() {
+			audit(r, t, http.StatusTooManyRequests)
+			rpcScanner.WriteError(w, twirp.NewError(twirp.ResourceExhausted, "rate limit exceeded"))
+			return
+		}
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		base.ServeHTTP(sw, r)
+		audit(r, t, sw.status)
+	})
+}
+
+// isScanRequest returns whether the request starts a scan.
+// Cache requests are a part of scans and job polling doesn't scan, so they are not counted.
+func isScanRequest(r *http.Request) bool {
+	switch {
+	case strings.HasPrefix(r.URL.Path, rpcScanner.ScannerPathPrefix), r.URL.Path == rpc.ScanStreamPath,
+		r.URL.Path == RESTScanPath, r.URL.Path == VerdictPath:
+		return true
+	case r.URL.Path == JobsPath:
+		return r.Method == http.MethodPost
+	}
+	return false
+}
+
+func audit(r *http.Request, t *tenant, status int) {
+	log.Logger.Infow("Scan request", "tenant", t.name, "path", r.URL.Path, "remote", r.RemoteAddr, "status", status)
+}
+
+// statusWriter records the status code for the audit log
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,310 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rpcCache "github.com/aquasecurity/trivy/rpc/cache"
+	rpcScanner "github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+func TestLoadAuthConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    AuthConfig
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			content: `
+tenants:
+  - name: team-a
+    tokens: [token-a1, token-a2]
+    rate-limit: 60
+  - name: team-b
+oidc:
+  issuer: https://token.actions.githubusercontent.com
+  audience: trivy
+`,
+			want: AuthConfig{
+				Tenants: []TenantConfig{
+					{
+						Name:      "team-a",
+						Tokens:    []string{"token-a1", "token-a2"},
+						RateLimit: 60,
+					},
+					{
+						Name: "team-b",
+					},
+				},
+				OIDC: &OIDCConfig{
+					Issuer:      "https://token.actions.githubusercontent.com",
+					Audience:    "trivy",
+					TenantClaim: "sub",
+				},
+			},
+		},
+		{
+			name: "sad path: duplicate token",
+			content: `
+tenants:
+  - name: team-a
+    tokens: [token]
+  - name: team-b
+    tokens: [token]
+`,
+			wantErr: "empty or duplicate token of the tenant team-b",
+		},
+		{
+			name: "sad path: duplicate tenant",
+			content: `
+tenants:
+  - name: team-a
+  - name: team-a
+`,
+			wantErr: "duplicate tenant: team-a",
+		},
+		{
+			name: "sad path: no audience",
+			content: `
+oidc:
+  issuer: https://token.actions.githubusercontent.com
+`,
+			wantErr: "OIDC issuer and audience are required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "auth.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			got, err := LoadAuthConfig(path)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_authenticator_handler(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := newTestIssuer(t, &key.PublicKey)
+
+	signToken := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key1"
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	opts := AuthOptions{
+		Token:       "shared",
+		TokenHeader: "Trivy-Token",
+		Config: AuthConfig{
+			Tenants: []TenantConfig{
+				{
+					Name:      "team-a",
+					Tokens:    []string{"token-a"},
+					RateLimit: 1,
+				},
+				{
+					Name: "repo:org/app:ref:refs/heads/main",
+				},
+			},
+			OIDC: &OIDCConfig{
+				Issuer:      issuer.URL,
+				Audience:    "trivy",
+				TenantClaim: "sub",
+			},
+		},
+	}
+
+	type request struct {
+		path   string
+		header http.Header
+	}
+	tests := []struct {
+		name     string
+		requests []request
+		want     []int
+	}{
+		{
+			name: "shared token",
+			requests: []request{
+				{
+					path:   rpcScanner.ScannerPathPrefix,
+					header: http.Header{"Trivy-Token": []string{"shared"}},
+				},
+			},
+			want: []int{http.StatusOK},
+		},
+		{
+			name: "rate-limited scans",
+			requests: []request{
+				{
+					path:   rpcScanner.ScannerPathPrefix,
+					header: http.Header{"Trivy-Token": []string{"token-a"}},
+				},
+				{
+					path:   rpcScanner.ScannerPathPrefix,
+					header: http.Header{"Trivy-Token": []string{"token-a"}},
+				},
+			},
+			want: []int{
+				http.StatusOK,
+				http.StatusTooManyRequests,
+			},
+		},
+		{
+			name: "cache requests are not rate-limited",
+			requests: []request{
+				{
+					path:   rpcCache.CachePathPrefix,
+					header: http.Header{"Trivy-Token": []string{"token-a"}},
+				},
+				{
+					path:   rpcCache.CachePathPrefix,
+					header: http.Header{"Trivy-Token": []string{"token-a"}},
+				},
+			},
+			want: []int{
+				http.StatusOK,
+				http.StatusOK,
+			},
+		},
+		{
+			name: "OIDC token",
+			requests: []request{
+				{
+					path: rpcScanner.ScannerPathPrefix,
+					header: http.Header{"Authorization": []string{"Bearer " + signToken(jwt.MapClaims{
+						"iss": issuer.URL,
+						"aud": "trivy",
+						"sub": "repo:org/app:ref:refs/heads/main",
+						"exp": exp,
+					})}},
+				},
+			},
+			want: []int{http.StatusOK},
+		},
+		{
+			name: "sad path: invalid token",
+			requests: []request{
+				{
+					path:   rpcScanner.ScannerPathPrefix,
+					header: http.Header{"Trivy-Token": []string{"invalid"}},
+				},
+			},
+			want: []int{http.StatusUnauthorized},
+		},
+		{
+			name: "sad path: OIDC token for another audience",
+			requests: []request{
+				{
+					path: rpcScanner.ScannerPathPrefix,
+					header: http.Header{"Authorization": []string{"Bearer " + signToken(jwt.MapClaims{
+						"iss": issuer.URL,
+						"aud": "another",
+						"sub": "repo:org/app:ref:refs/heads/main",
+						"exp": exp,
+					})}},
+				},
+			},
+			want: []int{http.StatusUnauthorized},
+		},
+		{
+			name: "sad path: OIDC token of unknown tenant",
+			requests: []request{
+				{
+					path: rpcScanner.ScannerPathPrefix,
+					header: http.Header{"Authorization": []string{"Bearer " + signToken(jwt.MapClaims{
+						"iss": issuer.URL,
+						"aud": "trivy",
+						"sub": "repo:org/unknown:ref:refs/heads/main",
+						"exp": exp,
+					})}},
+				},
+			},
+			want: []int{http.StatusUnauthorized},
+		},
+		{
+			name: "sad path: expired OIDC token",
+			requests: []request{
+				{
+					path: rpcScanner.ScannerPathPrefix,
+					header: http.Header{"Authorization": []string{"Bearer " + signToken(jwt.MapClaims{
+						"iss": issuer.URL,
+						"aud": "trivy",
+						"sub": "repo:org/app:ref:refs/heads/main",
+						"exp": time.Now().Add(-time.Hour).Unix(),
+					})}},
+				},
+			},
+			want: []int{http.StatusUnauthorized},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newAuthenticator(opts).handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			var got []int
+			for _, req := range tt.requests {
+				r := httptest.NewRequest(http.MethodPost, req.path, http.NoBody)
+				r.Header = req.header
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+				got = append(got, w.Code)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// newTestIssuer serves the OpenID Connect discovery document and the signing key
+func newTestIssuer(t *testing.T, key *rsa.PublicKey) *httptest.Server {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   ts.URL,
+			"jwks_uri": ts.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": "key1",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		})
+	})
+	return ts
+}
@@ -6,10 +6,11 @@ package server
 import (
 	"github.com/google/wire"
 
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/fanal/cache"
 )
 
-func initializeScanServer(localArtifactCache cache.LocalArtifactCache) *ScanServer {
+func initializeScanServer(localArtifactCache cache.LocalArtifactCache, online *advisory.OnlineSource) *ScanServer {
 	wire.Build(ScanSuperSet)
 	return &ScanServer{}
 }
@@ -0,0 +1,467 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// JobsPath is the path of the asynchronous scan job API
+const JobsPath = "/jobs"
+
+const (
+	jobDirName      = "server"
+	jobStoreName    = "jobs.db"
+	jobBucket       = "jobs"
+	jobReportBucket = "reports"
+
+	// jobRetention is how long finished jobs and their reports are kept
+	jobRetention = 24 * time.Hour
+
+	jobScanTimeout = 1 * time.Hour
+)
+
+// JobStatus represents the status of a scan job
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+var errQueueFull = xerrors.New("the job queue is full")
+
+// JobOptions configures the asynchronous scan job API.
+// The API is enabled when Workers is positive.
+type JobOptions struct {
+	Workers   int // The number of images scanned concurrently
+	QueueSize int // The maximum number of jobs waiting for a worker
+	Scan      ImageScanFunc
+}
+
+// JobRequest is the request body to submit a scan job
+type JobRequest struct {
+	Image string // The image reference, e.g. ghcr.io/org/app:1.0
+}
+
+// Job represents a scan job. The report is fetched separately once the job succeeds.
+// Only the tenant that submitted the job can see it.
+type Job struct {
+	ID         string
+	Image      string
+	Tenant     string `json:",omitempty"`
+	Status     JobStatus
+	Error      string `json:",omitempty"`
+	CreatedAt  time.Time
+	StartedAt  *time.Time `json:",omitempty"`
+	FinishedAt *time.Time `json:",omitempty"`
+}
+
+func (j Job) finished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
+
+// jobManager runs scan jobs with a bounded worker pool.
+// Jobs are persisted in the cache directory, so that unfinished jobs are resumed after restart.
+type jobManager struct {
+	ctx        context.Context
+	opts       JobOptions
+	dbUpdateWg *sync.WaitGroup
+	requestWg  *sync.WaitGroup
+
+	db    *bolt.DB
+	queue chan string
+	wg    sync.WaitGroup
+}
+
+func newJobManager(ctx context.Context, cacheDir string, opts JobOptions, dbUpdateWg, requestWg *sync.WaitGroup) (*jobManager, error) {
+	dir := filepath.Join(cacheDir, jobDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, xerrors.Errorf("failed to create the job dir: %w", err)
+	}
+
+	// The timeout avoids blocking forever when another server uses the same cache directory
+	db, err := bolt.Open(filepath.Join(dir, jobStoreName), 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		return nil, xerrors.Errorf("unable to open the job store: %w", err)
+	}
+
+	m := &jobManager{
+		ctx:        ctx,
+		opts:       opts,
+		dbUpdateWg: dbUpdateWg,
+		requestWg:  requestWg,
+		db:         db,
+	}
+
+	pending, err := m.load()
+	if err != nil {
+		_ = db.Close()
+		return nil, xerrors.Errorf("job store error: %w", err)
+	}
+
+	// Resumed jobs don't count against the queue size
+	m.queue = make(chan string, opts.QueueSize+len(pending))
+	for _, id := range pending {
+		m.queue <- id
+	}
+	if len(pending) > 0 {
+		log.Logger.Infof("Resuming %d scan jobs...", len(pending))
+	}
+
+	for i := 0; i < opts.Workers; i++ {
+		m.wg.Add(1)
+		go m.work()
+	}
+	return m, nil
+}
+
+// load prepares the buckets and returns unfinished jobs in creation order
+func (m *jobManager) load() ([]string, error) {
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range []string{jobBucket, jobReportBucket} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(bucket)); err != nil {
+				return xerrors.Errorf("unable to create %s bucket: %w", bucket, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = m.prune(); err != nil {
+		return nil, err
+	}
+
+	var pending []Job
+	err = m.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(jobBucket)).ForEach(func(_, v []byte) error {
+			var job Job
+			if err := json.Unmarshal(v, &job); err != nil {
+				return xerrors.Errorf("JSON unmarshal error: %w", err)
+			}
+			if !job.finished() {
+				pending = append(pending, job)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(pending, func(a, b Job) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return lo.Map(pending, func(job Job, _ int) string {
+		return job.ID
+	}), nil
+}
+
+// prune removes the jobs finished before the retention period and their reports
+func (m *jobManager) prune() error {
+	return m.db.Update(func(tx *bolt.Tx) error {
+		jobs := tx.Bucket([]byte(jobBucket))
+		reports := tx.Bucket([]byte(jobReportBucket))
+
+		var expired [][]byte
+		err := jobs.ForEach(func(k, v []byte) error {
+			var job Job
+			if err := json.Unmarshal(v, &job); err != nil {
+				return xerrors.Errorf("JSON unmarshal error: %w", err)
+			}
+			if job.finished() && time.Since(*job.FinishedAt) > jobRetention {
+				expired = append(expired, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range expired {
+			if err = jobs.Delete(k); err != nil {
+				return xerrors.Errorf("unable to delete the job: %w", err)
+			}
+			if err = reports.Delete(k); err != nil {
+				return xerrors.Errorf("unable to delete the report: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// close waits for the running scans and closes the job store.
+// The context passed to newJobManager must be canceled beforehand.
+func (m *jobManager) close() error {
+	m.wg.Wait()
+	return m.db.Close()
+}
+
+// submit queues a scan job of the image for the tenant
+func (m *jobManager) submit(image, tenant string) (Job, error) {
+	job := Job{
+		ID:        uuid.New().String(),
+		Image:     image,
+		Tenant:    tenant,
+		Status:    JobQueued,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := m.put(job, nil); err != nil {
+		return Job{}, err
+	}
+
+	select {
+	case m.queue <- job.ID:
+		return job, nil
+	default:
+		if err := m.delete(job.ID); err != nil {
+			log.Logger.Errorf("Unable to delete the job %s: %s", job.ID, err)
+		}
+		return Job{}, errQueueFull
+	}
+}
+
+func (m *jobManager) work() {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.ctx.Done():
+			// Unfinished jobs are resumed after restart
+			return
+		case id := <-m.queue:
+			if err := m.run(id); err != nil {
+				log.Logger.Errorf("Scan job error (%s): %s", id, err)
+			}
+			if err := m.prune(); err != nil {
+				log.Logger.Errorf("Unable to prune expired jobs: %s", err)
+			}
+		}
+	}
+}
+
+func (m *jobManager) run(id string) error {
+	job, ok, err := m.get(id)
+	if err != nil {
+		return err
+	} else if !ok {
+		return xerrors.Errorf("no such job")
+	}
+
+	job.Status = JobRunning
+	job.StartedAt = now()
+	if err = m.put(job, nil); err != nil {
+		return err
+	}
+
+	log.Logger.Infof("Scanning %s for the job %s...", job.Image, job.ID)
+	report, err := m.scan(job.Image)
+	if m.ctx.Err() != nil {
+		// Interrupted by shutdown. The job stays running and is resumed after restart.
+		return nil
+	}
+
+	job.FinishedAt = now()
+	if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+		return m.put(job, nil)
+	}
+	job.Status = JobSucceeded
+	return m.put(job, &report)
+}
+
+func (m *jobManager) scan(image string) (types.Report, error) {
+	ctx, cancel := context.WithTimeout(m.ctx, jobScanTimeout)
+	defer cancel()
+
+	// Stop scanning during DB update, and block DB update during the scan
+	m.dbUpdateWg.Wait()
+	m.requestWg.Add(1)
+	defer m.requestWg.Done()
+
+	return m.opts.Scan(ctx, image)
+}
+
+// put stores the job, and the report if given
+func (m *jobManager) put(job Job, report *types.Report) error {
+	b, err := json.Marshal(job)
+	if err != nil {
+		return xerrors.Errorf("JSON marshal error: %w", err)
+	}
+	var r []byte
+	if report != nil {
+		if r, err = json.Marshal(report); err != nil {
+			return xerrors.Errorf("JSON marshal error: %w", err)
+		}
+	}
+
+	err = m.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket([]byte(jobBucket)).Put([]byte(job.ID), b); err != nil {
+			return err
+		}
+		if r != nil {
+			return tx.Bucket([]byte(jobReportBucket)).Put([]byte(job.ID), r)
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("job store error: %w", err)
+	}
+	return nil
+}
+
+func (m *jobManager) delete(id string) error {
+	err := m.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(jobBucket)).Delete([]byte(id))
+	})
+	if err != nil {
+		return xerrors.Errorf("job store error: %w", err)
+	}
+	return nil
+}
+
+func (m *jobManager) get(id string) (Job, bool, error) {
+	var job Job
+	var found bool
+	err := m.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(jobBucket)).Get([]byte(id))
+		if b == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(b, &job)
+	})
+	if err != nil {
+		return Job{}, false, xerrors.Errorf("job store error: %w", err)
+	}
+	return job, found, nil
+}
+
+// report returns the JSON report of the succeeded job as stored
+func (m *jobManager) report(id string) ([]byte, error) {
+	var report []byte
+	err := m.db.View(func(tx *bolt.Tx) error {
+		// The value is valid only during the transaction
+		report = append(report, tx.Bucket([]byte(jobReportBucket)).Get([]byte(id))...)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("job store error: %w", err)
+	}
+	return report, nil
+}
+
+// ServeHTTP handles the following requests:
+//
+//	POST /jobs                submits a scan job
+//	GET  /jobs/{id}           returns the status of the job
+//	GET  /jobs/{id}/report    returns the JSON report of the succeeded job
+func (m *jobManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, sub, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, JobsPath), "/"), "/")
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		m.handleSubmit(w, r)
+	case id != "" && sub == "" && r.Method == http.MethodGet:
+		m.handleStatus(w, r, id)
+	case id != "" && sub == "report" && r.Method == http.MethodGet:
+		m.handleReport(w, r, id)
+	case id == "" || sub == "" || sub == "report":
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (m *jobManager) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+	if _, err := name.ParseReference(req.Image); err != nil {
+		http.Error(w, fmt.Sprintf("invalid image reference: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	job, err := m.submit(req.Image, tenantName(r.Context()))
+	if errors.Is(err, errQueueFull) {
+		// Clients should retry later
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	} else if err != nil {
+		log.Logger.Errorf("Job submission error: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (m *jobManager) handleStatus(w http.ResponseWriter, r *http.Request, id string) {
+	job, ok, err := m.get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	} else if !ok || job.Tenant != tenantName(r.Context()) {
+		// Jobs of other tenants are not disclosed
+		http.Error(w, "no such job", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (m *jobManager) handleReport(w http.ResponseWriter, r *http.Request, id string) {
+	job, ok, err := m.get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	} else if !ok || job.Tenant != tenantName(r.Context()) {
+		http.Error(w, "no such job", http.StatusNotFound)
+		return
+	} else if job.Status != JobSucceeded {
+		http.Error(w, fmt.Sprintf("the job is %s", job.Status), http.StatusConflict)
+		return
+	}
+
+	report, err := m.report(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(report); err != nil {
+		log.Logger.Errorf("Job report response error: %s", err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Logger.Errorf("Response write error: %s", err)
+	}
+}
+
+func now() *time.Time {
+	t := time.Now().UTC()
+	return &t
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func Test_jobManager(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		workers    int
+		scan       ImageScanFunc
+		wantStatus int
+		wantJob    Job
+		wantReport *types.Report
+	}{
+		{
+			name:    "succeeded",
+			body:    `{"Image": "ghcr.io/org/app:1.0"}`,
+			workers: 1,
+			scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+				return types.Report{ArtifactName: imageRef}, nil
+			},
+			wantStatus: http.StatusAccepted,
+			wantJob: Job{
+				Image:  "ghcr.io/org/app:1.0",
+				Status: JobSucceeded,
+			},
+			wantReport: &types.Report{ArtifactName: "ghcr.io/org/app:1.0"},
+		},
+		{
+			name:    "failed",
+			body:    `{"Image": "ghcr.io/org/app:1.0"}`,
+			workers: 1,
+			scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+				return types.Report{}, xerrors.New("unauthorized")
+			},
+			wantStatus: http.StatusAccepted,
+			wantJob: Job{
+				Image:  "ghcr.io/org/app:1.0",
+				Status: JobFailed,
+				Error:  "unauthorized",
+			},
+		},
+		{
+			name:       "queue full",
+			body:       `{"Image": "ghcr.io/org/app:1.0"}`,
+			workers:    0,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "invalid image",
+			body:       `{"Image": "ghcr.io/org/APP"}`,
+			workers:    1,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			m, err := newJobManager(ctx, t.TempDir(), JobOptions{
+				Workers: tt.workers,
+				Scan:    tt.scan,
+			}, &sync.WaitGroup{}, &sync.WaitGroup{})
+			require.NoError(t, err)
+			defer func() {
+				cancel()
+				require.NoError(t, m.close())
+			}()
+
+			ts := httptest.NewServer(m)
+			defer ts.Close()
+
+			resp, err := http.Post(ts.URL+JobsPath, "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			if resp.StatusCode != http.StatusAccepted {
+				return
+			}
+
+			var submitted Job
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&submitted))
+			assert.Equal(t, JobQueued, submitted.Status)
+
+			var got Job
+			require.Eventually(t, func() bool {
+				getJSON(t, ts.URL+JobsPath+"/"+submitted.ID, http.StatusOK, &got)
+				return got.FinishedAt != nil
+			}, 5*time.Second, 10*time.Millisecond)
+
+			assert.Equal(t, submitted.ID, got.ID)
+			assert.NotNil(t, got.StartedAt)
+			got.ID, got.CreatedAt, got.StartedAt, got.FinishedAt = "", time.Time{}, nil, nil
+			assert.Equal(t, tt.wantJob, got)
+
+			if tt.wantReport == nil {
+				getJSON(t, ts.URL+JobsPath+"/"+submitted.ID+"/report", http.StatusConflict, nil)
+				return
+			}
+			var report types.Report
+			getJSON(t, ts.URL+JobsPath+"/"+submitted.ID+"/report", http.StatusOK, &report)
+			assert.Equal(t, *tt.wantReport, report)
+		})
+	}
+}
+
+func Test_jobManager_resume(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	// No workers, so that the job is left queued
+	ctx, cancel := context.WithCancel(context.Background())
+	m, err := newJobManager(ctx, cacheDir, JobOptions{QueueSize: 1}, &sync.WaitGroup{}, &sync.WaitGroup{})
+	require.NoError(t, err)
+	job, err := m.submit("ghcr.io/org/app:1.0", "")
+	require.NoError(t, err)
+	cancel()
+	require.NoError(t, m.close())
+
+	// The job is resumed after restart
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	m, err = newJobManager(ctx, cacheDir, JobOptions{
+		Workers: 1,
+		Scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+			return types.Report{ArtifactName: imageRef}, nil
+		},
+	}, &sync.WaitGroup{}, &sync.WaitGroup{})
+	require.NoError(t, err)
+	defer func() {
+		cancel()
+		require.NoError(t, m.close())
+	}()
+
+	require.Eventually(t, func() bool {
+		got, ok, err := m.get(job.ID)
+		require.NoError(t, err)
+		require.True(t, ok)
+		return got.Status == JobSucceeded
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_jobManager_notFound(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m, err := newJobManager(ctx, t.TempDir(), JobOptions{}, &sync.WaitGroup{}, &sync.WaitGroup{})
+	require.NoError(t, err)
+	defer func() {
+		cancel()
+		require.NoError(t, m.close())
+	}()
+
+	ts := httptest.NewServer(m)
+	defer ts.Close()
+
+	getJSON(t, ts.URL+JobsPath+"/unknown", http.StatusNotFound, nil)
+	getJSON(t, ts.URL+JobsPath+"/unknown/report", http.StatusNotFound, nil)
+	getJSON(t, ts.URL+JobsPath+"/unknown/logs", http.StatusNotFound, nil)
+}
+
+func Test_jobManager_tenant(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m, err := newJobManager(ctx, t.TempDir(), JobOptions{
+		Workers: 1,
+		Scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+			return types.Report{ArtifactName: imageRef}, nil
+		},
+	}, &sync.WaitGroup{}, &sync.WaitGroup{})
+	require.NoError(t, err)
+	defer func() {
+		cancel()
+		require.NoError(t, m.close())
+	}()
+
+	auth := newAuthenticator(AuthOptions{
+		TokenHeader: "Trivy-Token",
+		Config: AuthConfig{
+			Tenants: []TenantConfig{
+				{
+					Name:   "team-a",
+					Tokens: []string{"token-a"},
+				},
+				{
+					Name:   "team-b",
+					Tokens: []string{"token-b"},
+				},
+			},
+		},
+	})
+	ts := httptest.NewServer(auth.handler(m))
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+JobsPath, strings.NewReader(`{"Image": "ghcr.io/org/app:1.0"}`))
+	require.NoError(t, err)
+	req.Header.Set("Trivy-Token", "token-a")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	var submitted Job
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&submitted))
+	assert.Equal(t, "team-a", submitted.Tenant)
+
+	jobURL := ts.URL + JobsPath + "/" + submitted.ID
+	header := http.Header{"Trivy-Token": []string{"token-a"}}
+	require.Eventually(t, func() bool {
+		var got Job
+		getJSONWithHeader(t, jobURL, header, http.StatusOK, &got)
+		return got.Status == JobSucceeded
+	}, 5*time.Second, 10*time.Millisecond)
+	getJSONWithHeader(t, jobURL+"/report", header, http.StatusOK, nil)
+
+	// The job of team-a looks missing to team-b
+	header = http.Header{"Trivy-Token": []string{"token-b"}}
+	getJSONWithHeader(t, jobURL, header, http.StatusNotFound, nil)
+	getJSONWithHeader(t, jobURL+"/report", header, http.StatusNotFound, nil)
+}
+
+func getJSON(t *testing.T, url string, wantStatus int, v any) {
+	getJSONWithHeader(t, url, nil, wantStatus, v)
+}
+
+func getJSONWithHeader(t *testing.T, url string, header http.Header, wantStatus int, v any) {
+	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	require.NoError(t, err)
+	req.Header = header
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, wantStatus, resp.StatusCode)
+	if v != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"net/http"
 	"os"
@@ -9,45 +10,66 @@ import (
 	"time"
 
 	"github.com/NYTimes/gziphandler"
-	"github.com/twitchtv/twirp"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	"github.com/aquasecurity/trivy-db/pkg/metadata"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	dbc "github.com/aquasecurity/trivy/pkg/db"
 	"github.com/aquasecurity/trivy/pkg/fanal/cache"
 	"github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/metrics"
+	"github.com/aquasecurity/trivy/pkg/report/webhook"
+	"github.com/aquasecurity/trivy/pkg/rpc"
 	"github.com/aquasecurity/trivy/pkg/utils/fsutils"
 	"github.com/aquasecurity/trivy/pkg/version"
 	rpcCache "github.com/aquasecurity/trivy/rpc/cache"
 	rpcScanner "github.com/aquasecurity/trivy/rpc/scanner"
 )
 
-const updateInterval = 1 * time.Hour
+const (
+	updateInterval = 1 * time.Hour
+
+	// MetricsPath is the path of the metrics in the Prometheus format
+	MetricsPath = "/metrics"
+)
 
 // Server represents Trivy server
 type Server struct {
 	appVersion   string
 	addr         string
 	cacheDir     string
-	token        string
-	tokenHeader  string
 	dbRepository string
+	queueOpts    QueueOptions
+	verdictOpts  VerdictOptions
+	jobOpts      JobOptions
+	authOpts     AuthOptions
+	webhookOpts  webhook.Options
+	tlsConfig    *tls.Config
+	online       *advisory.OnlineSource
 
 	// For OCI registries
 	types.RegistryOptions
 }
 
 // NewServer returns an instance of Server
-func NewServer(appVersion, addr, cacheDir, token, tokenHeader, dbRepository string, opt types.RegistryOptions) Server {
+// The server listens over TLS if tlsConfig is not nil, and looks up OSV.dev if online is not nil.
+func NewServer(appVersion, addr, cacheDir, dbRepository string, queueOpts QueueOptions, verdictOpts VerdictOptions,
+	jobOpts JobOptions, authOpts AuthOptions, webhookOpts webhook.Options, tlsConfig *tls.Config,
+	online *advisory.OnlineSource, opt types.RegistryOptions) Server {
 	return Server{
 		appVersion:      appVersion,
 		addr:            addr,
 		cacheDir:        cacheDir,
-		token:           token,
-		tokenHeader:     tokenHeader,
 		dbRepository:    dbRepository,
+		queueOpts:       queueOpts,
+		verdictOpts:     verdictOpts,
+		jobOpts:         jobOpts,
+		authOpts:        authOpts,
+		webhookOpts:     webhookOpts,
+		tlsConfig:       tlsConfig,
+		online:          online,
 		RegistryOptions: opt,
 	}
 }
@@ -67,14 +89,40 @@ func (s Server) ListenAndServe(ctx context.Context, serverCache cache.Cache, ski
 		}
 	}()
 
-	mux := newServeMux(ctx, serverCache, dbUpdateWg, requestWg, s.token, s.tokenHeader, s.cacheDir)
+	// The asynchronous scan job API is for images too large to be scanned within an RPC timeout
+	var jobs *jobManager
+	if s.jobOpts.Workers > 0 {
+		var err error
+		if jobs, err = newJobManager(ctx, s.cacheDir, s.jobOpts, dbUpdateWg, requestWg); err != nil {
+			return xerrors.Errorf("scan job error: %w", err)
+		}
+	}
+
+	notifier, err := webhook.NewNotifier(s.webhookOpts)
+	if err != nil {
+		return xerrors.Errorf("webhook error: %w", err)
+	}
+
+	mux := newServeMux(ctx, serverCache, dbUpdateWg, requestWg, newAuthenticator(s.authOpts), s.cacheDir, s.queueOpts,
+		s.verdictOpts, jobs, notifier, s.online)
 	log.Logger.Infof("Listening %s...", s.addr)
 
-	return http.ListenAndServe(s.addr, mux)
+	if s.tlsConfig == nil {
+		return http.ListenAndServe(s.addr, mux)
+	}
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           mux,
+		TLSConfig:         s.tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// The certificate is provided by TLSConfig so that it can be rotated
+	return server.ListenAndServeTLS("", "")
 }
 
 func newServeMux(ctx context.Context, serverCache cache.Cache, dbUpdateWg, requestWg *sync.WaitGroup,
-	token, tokenHeader, cacheDir string) *http.ServeMux {
+	auth *authenticator, cacheDir string, queueOpts QueueOptions, verdictOpts VerdictOptions, jobs *jobManager,
+	notifier *webhook.Notifier, online *advisory.OnlineSource) *http.ServeMux {
 	withWaitGroup := func(base http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Stop processing requests during DB update
@@ -91,20 +139,58 @@ func newServeMux(ctx context.Context, serverCache cache.Cache, dbUpdateWg, reque
 
 	mux := http.NewServeMux()
 
-	scanServer := rpcScanner.NewScannerServer(initializeScanServer(serverCache), nil)
-	scanHandler := withToken(withWaitGroup(scanServer), token, tokenHeader)
+	// Count the cache hits and misses of the blobs uploaded by clients
+	serverCache = metrics.NewCache(serverCache)
+	metrics.SetCacheDir(cacheDir)
+
+	// The scan RPC and the scan stream share the queue
+	scanQueue := newScanQueue(queueOpts)
+	scanServer := withWebhook(initializeScanServer(serverCache, online), notifier)
+
+	scanHandler := auth.handler(withWaitGroup(withScanQueue(rpcScanner.NewScannerServer(scanServer, nil), scanQueue)))
 	mux.Handle(rpcScanner.ScannerPathPrefix, gziphandler.GzipHandler(scanHandler))
 
-	layerServer := rpcCache.NewCacheServer(NewCacheServer(serverCache), nil)
-	layerHandler := withToken(withWaitGroup(layerServer), token, tokenHeader)
+	streamHandler := auth.handler(withWaitGroup(withScanQueue(newStreamHandler(scanServer), scanQueue)))
+	mux.Handle(rpc.ScanStreamPath, gziphandler.GzipHandler(streamHandler))
+
+	cacheServer := NewCacheServer(serverCache)
+	layerHandler := auth.handler(withWaitGroup(rpcCache.NewCacheServer(cacheServer, nil)))
 	mux.Handle(rpcCache.CachePathPrefix, gziphandler.GzipHandler(layerHandler))
 
+	// The REST API is the JSON counterpart of the Twirp services for clients without generated stubs
+	rest := newRESTHandler(scanServer, cacheServer)
+	restScanHandler := auth.handler(withWaitGroup(withScanQueue(http.HandlerFunc(rest.scan), scanQueue)))
+	mux.Handle(RESTScanPath, gziphandler.GzipHandler(restScanHandler))
+	mux.Handle(RESTPathPrefix, gziphandler.GzipHandler(auth.handler(withWaitGroup(rest))))
+
+	var tokenHeader string
+	if auth != nil {
+		tokenHeader = auth.tokenHeader
+	}
+	mux.Handle(OpenAPIPath, newOpenAPIHandler(tokenHeader))
+
+	// The verdict and admission review endpoints for admission webhooks scan images by themselves
+	if verdictOpts.PolicyDir != "" {
+		verdicts := newVerdictHandler(ctx, verdictOpts, dbUpdateWg, requestWg)
+		mux.Handle(VerdictPath, auth.handler(verdicts))
+		mux.Handle(AdmissionPathPrefix, auth.handler(newAdmissionHandler(verdicts)))
+	}
+
+	if jobs != nil {
+		jobHandler := auth.handler(jobs)
+		mux.Handle(JobsPath, jobHandler)
+		mux.Handle(JobsPath+"/", jobHandler)
+	}
+
 	mux.HandleFunc("/healthz", func(rw http.ResponseWriter, r *http.Request) {
 		if _, err := rw.Write([]byte("ok")); err != nil {
 			log.Logger.Errorf("health check error: %s", err)
 		}
 	})
 
+	// The metrics are not authenticated as with the health check so that Prometheus can scrape them
+	mux.Handle(MetricsPath, metrics.Handler())
+
 	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Content-Type", "application/json")
 
@@ -116,16 +202,6 @@ func newServeMux(ctx context.Context, serverCache cache.Cache, dbUpdateWg, reque
 	return mux
 }
 
-func withToken(base http.Handler, token, tokenHeader string) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if token != "" && token != r.Header.Get(tokenHeader) {
-			rpcScanner.WriteError(w, twirp.NewError(twirp.Unauthenticated, "invalid token"))
-			return
-		}
-		base.ServeHTTP(w, r)
-	})
-}
-
 type dbWorker struct {
 	dbClient dbc.Operation
 }
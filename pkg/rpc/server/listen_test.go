@@ -22,6 +22,7 @@ import (
 	"github.com/aquasecurity/trivy/pkg/fanal/cache"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/policy"
+	"github.com/aquasecurity/trivy/pkg/rpc"
 	"github.com/aquasecurity/trivy/pkg/utils/fsutils"
 	"github.com/aquasecurity/trivy/pkg/version"
 	rpcCache "github.com/aquasecurity/trivy/rpc/cache"
@@ -203,6 +204,24 @@ func Test_newServeMux(t *testing.T) {
 			path: "/healthz",
 			want: http.StatusOK,
 		},
+		{
+			name: "OpenAPI definition",
+			args: args{
+				token:       "test",
+				tokenHeader: "Authorization",
+			},
+			path: OpenAPIPath,
+			want: http.StatusOK,
+		},
+		{
+			name: "metrics",
+			args: args{
+				token:       "test",
+				tokenHeader: "Authorization",
+			},
+			path: MetricsPath,
+			want: http.StatusOK,
+		},
 		{
 			name: "cache endpoint",
 			path: path.Join(rpcCache.CachePathPrefix, "MissingBlobs"),
@@ -244,6 +263,19 @@ func Test_newServeMux(t *testing.T) {
 			},
 			want: http.StatusUnauthorized,
 		},
+		{
+			name: "sad path: scan stream with invalid token",
+			args: args{
+				token:       "test",
+				tokenHeader: "Authorization",
+			},
+			path: rpc.ScanStreamPath,
+			header: http.Header{
+				"Authorization": []string{"invalid"},
+				"Content-Type":  []string{"application/protobuf"},
+			},
+			want: http.StatusUnauthorized,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -253,8 +285,10 @@ func Test_newServeMux(t *testing.T) {
 			require.NoError(t, err)
 			defer func() { _ = c.Close() }()
 
-			ts := httptest.NewServer(newServeMux(context.Background(), c, dbUpdateWg, requestWg, tt.args.token,
-				tt.args.tokenHeader, ""),
+			ts := httptest.NewServer(newServeMux(context.Background(), c, dbUpdateWg, requestWg, newAuthenticator(AuthOptions{
+				Token:       tt.args.token,
+				TokenHeader: tt.args.tokenHeader,
+			}), "", QueueOptions{}, VerdictOptions{}, nil, nil, nil),
 			)
 			defer ts.Close()
 
@@ -284,8 +318,8 @@ func Test_VersionEndpoint(t *testing.T) {
 	require.NoError(t, err)
 	defer func() { _ = c.Close() }()
 
-	ts := httptest.NewServer(newServeMux(context.Background(), c, dbUpdateWg, requestWg, "", "",
-		"testdata/testcache"),
+	ts := httptest.NewServer(newServeMux(context.Background(), c, dbUpdateWg, requestWg, nil,
+		"testdata/testcache", QueueOptions{}, VerdictOptions{}, nil, nil, nil),
 	)
 	defer ts.Close()
 
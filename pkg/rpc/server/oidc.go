@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"golang.org/x/xerrors"
+)
+
+// jwksRefreshInterval is the minimum interval of fetching the signing keys again for unknown key IDs.
+// Issuers rotate their keys, but tokens with random key IDs must not make the server hammer the issuer.
+const jwksRefreshInterval = time.Minute
+
+// oidcVerifier verifies ID tokens signed by the keys of the issuer
+type oidcVerifier struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey // by key ID
+	fetchedAt time.Time
+}
+
+func newOIDCVerifier(config OIDCConfig) *oidcVerifier {
+	return &oidcVerifier{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// verify returns the tenant of the ID token
+func (v *oidcVerifier) verify(ctx context.Context, raw string) (string, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, xerrors.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return v.key(ctx, kid)
+	})
+	if err != nil {
+		return "", xerrors.Errorf("token verification error: %w", err)
+	}
+
+	// The expiry is verified by ParseWithClaims only if present
+	if _, ok := claims["exp"]; !ok {
+		return "", xerrors.New("no expiry")
+	} else if !claims.VerifyIssuer(v.config.Issuer, true) {
+		return "", xerrors.Errorf("unexpected issuer: %v", claims["iss"])
+	} else if !claims.VerifyAudience(v.config.Audience, true) {
+		return "", xerrors.Errorf("unexpected audience: %v", claims["aud"])
+	}
+
+	tenant, _ := claims[v.config.TenantClaim].(string)
+	if tenant == "" {
+		return "", xerrors.Errorf("no %q claim", v.config.TenantClaim)
+	}
+	return tenant, nil
+}
+
+func (v *oidcVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	} else if time.Since(v.fetchedAt) < jwksRefreshInterval {
+		return nil, xerrors.Errorf("unknown key ID: %s", kid)
+	}
+
+	keys, err := v.fetchKeys(ctx)
+	v.fetchedAt = time.Now()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to fetch the signing keys: %w", err)
+	}
+	v.keys = keys
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, xerrors.Errorf("unknown key ID: %s", kid)
+}
+
+// fetchKeys fetches the RSA keys of the issuer via OpenID Connect Discovery
+func (v *oidcVerifier) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	discoveryURL := strings.TrimSuffix(v.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := v.get(ctx, discoveryURL, &discovery); err != nil {
+		return nil, xerrors.Errorf("discovery error: %w", err)
+	} else if discovery.JWKSURI == "" {
+		return nil, xerrors.New("no jwks_uri in the discovery document")
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := v.get(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return nil, xerrors.Errorf("JWKS error: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid modulus of the key %s: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid exponent of the key %s: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func (v *oidcVerifier) get(ctx context.Context, url string, body any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return xerrors.Errorf("request error: %w", err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("HTTP error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("unexpected status code from %s: %d", url, resp.StatusCode)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(body); err != nil {
+		return xerrors.Errorf("JSON decode error: %w", err)
+	}
+	return nil
+}
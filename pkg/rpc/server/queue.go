@@ -0,0 +1,215 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/samber/lo"
+	"github.com/twitchtv/twirp"
+	"golang.org/x/exp/slices"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	rpcScanner "github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+const (
+	// ScanPriorityHeader is the request header carrying the priority class of the scan
+	ScanPriorityHeader = "Trivy-Scan-Priority"
+
+	// PriorityHigh is for scans blocking users, e.g. CI pipelines
+	PriorityHigh = "high"
+	// PriorityLow is for background scans, e.g. scheduled rescans
+	PriorityLow = "low"
+)
+
+// QueueOptions holds the concurrency limits of scans. Zero means unlimited.
+type QueueOptions struct {
+	MaxScans             int
+	MaxHighPriorityScans int
+	MaxLowPriorityScans  int
+}
+
+type scanJob struct {
+	priority  string
+	ctx       context.Context
+	cancel    context.CancelFunc
+	started   chan struct{}
+	preempted bool
+}
+
+// scanQueue limits the number of concurrent scans per priority class.
+// High priority scans are dispatched first, and preempt the newest low priority scan
+// when only the total limit prevents them from starting.
+type scanQueue struct {
+	mu      sync.Mutex
+	opts    QueueOptions
+	running []*scanJob
+	waiting []*scanJob // in arrival order
+}
+
+func newScanQueue(opts QueueOptions) *scanQueue {
+	return &scanQueue{opts: opts}
+}
+
+// acquire blocks until the scan can start or ctx is done.
+func (q *scanQueue) acquire(ctx context.Context, priority string) (*scanJob, error) {
+	job := q.push(ctx, priority)
+	select {
+	case <-job.started:
+		return job, nil
+	case <-ctx.Done():
+		q.release(job)
+		return nil, ctx.Err()
+	}
+}
+
+// push queues a job and starts it if the limits allow.
+// Requests without a valid priority class are treated as 'high' priority.
+func (q *scanQueue) push(ctx context.Context, priority string) *scanJob {
+	if priority != PriorityLow {
+		priority = PriorityHigh
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	job := &scanJob{
+		priority: priority,
+		ctx:      jobCtx,
+		cancel:   cancel,
+		started:  make(chan struct{}),
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.waiting = append(q.waiting, job)
+	q.dispatch()
+	return job
+}
+
+// release removes the job from the queue and starts the waiting jobs.
+func (q *scanQueue) release(job *scanJob) {
+	job.cancel()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.running = lo.Without(q.running, job)
+	q.waiting = lo.Without(q.waiting, job)
+	q.dispatch()
+}
+
+// isPreempted returns true if the job was preempted by a high priority scan.
+func (q *scanQueue) isPreempted(job *scanJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return job.preempted
+}
+
+// dispatch starts the waiting jobs as long as the limits allow. It must be called with the lock held.
+func (q *scanQueue) dispatch() {
+	for _, priority := range []string{PriorityHigh, PriorityLow} {
+		for _, job := range slices.Clone(q.waiting) {
+			if job.priority != priority || q.classFull(priority) {
+				continue
+			}
+			if q.opts.MaxScans > 0 && len(q.running) >= q.opts.MaxScans && !q.preempt(job) {
+				return
+			}
+			q.waiting = lo.Without(q.waiting, job)
+			q.running = append(q.running, job)
+			close(job.started)
+		}
+	}
+}
+
+// preempt cancels the newest low priority scan in favor of the given high priority job.
+func (q *scanQueue) preempt(job *scanJob) bool {
+	if job.priority != PriorityHigh {
+		return false
+	}
+	for i := len(q.running) - 1; i >= 0; i-- {
+		victim := q.running[i]
+		if victim.priority != PriorityLow {
+			continue
+		}
+		log.Logger.Info("Preempting a low priority scan in favor of a high priority scan")
+		victim.preempted = true
+		victim.cancel()
+		q.running = lo.Without(q.running, victim)
+		return true
+	}
+	return false
+}
+
+func (q *scanQueue) classFull(priority string) bool {
+	limit := q.opts.MaxLowPriorityScans
+	if priority == PriorityHigh {
+		limit = q.opts.MaxHighPriorityScans
+	}
+	if limit <= 0 {
+		return false
+	}
+	running := lo.CountBy(q.running, func(job *scanJob) bool {
+		return job.priority == priority
+	})
+	return running >= limit
+}
+
+// withScanQueue runs scans through the queue.
+// The response of a preempted scan is discarded and replaced with an "unavailable" error
+// so that the client retries it later.
+func withScanQueue(base http.Handler, queue *scanQueue) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		job, err := queue.acquire(r.Context(), r.Header.Get(ScanPriorityHeader))
+		if err != nil {
+			rpcScanner.WriteError(w, twirp.NewError(twirp.Unavailable, err.Error()))
+			return
+		}
+		defer queue.release(job)
+
+		buf := &bufferedResponse{header: make(http.Header)}
+		base.ServeHTTP(buf, r.WithContext(job.ctx))
+
+		if queue.isPreempted(job) {
+			rpcScanner.WriteError(w, twirp.NewError(twirp.Unavailable, "preempted by a high priority scan"))
+			return
+		}
+		buf.flush(w)
+	})
+}
+
+// bufferedResponse holds the response until the scan turns out not to be preempted.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) flush(w http.ResponseWriter) {
+	for k, v := range b.header {
+		w.Header()[k] = v
+	}
+	if b.status != 0 {
+		w.WriteHeader(b.status)
+	}
+	if _, err := w.Write(b.body.Bytes()); err != nil {
+		log.Logger.Errorf("Response write error: %s", err)
+	}
+}
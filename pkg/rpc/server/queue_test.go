@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func started(job *scanJob) bool {
+	select {
+	case <-job.started:
+		return true
+	default:
+		return false
+	}
+}
+
+func Test_scanQueue(t *testing.T) {
+	t.Run("per-class limit", func(t *testing.T) {
+		q := newScanQueue(QueueOptions{MaxLowPriorityScans: 1})
+		low1 := q.push(context.Background(), PriorityLow)
+		low2 := q.push(context.Background(), PriorityLow)
+		high := q.push(context.Background(), PriorityHigh)
+
+		assert.True(t, started(low1))
+		assert.False(t, started(low2))
+		assert.True(t, started(high))
+
+		q.release(low1)
+		assert.True(t, started(low2))
+	})
+
+	t.Run("high priority first", func(t *testing.T) {
+		q := newScanQueue(QueueOptions{MaxScans: 1})
+		high1 := q.push(context.Background(), PriorityHigh)
+		low := q.push(context.Background(), PriorityLow)
+		high2 := q.push(context.Background(), PriorityHigh)
+
+		assert.True(t, started(high1))
+		assert.False(t, started(low))
+		assert.False(t, started(high2))
+
+		q.release(high1)
+		assert.True(t, started(high2))
+		assert.False(t, started(low))
+
+		q.release(high2)
+		assert.True(t, started(low))
+	})
+
+	t.Run("preemption", func(t *testing.T) {
+		q := newScanQueue(QueueOptions{MaxScans: 2})
+		low1 := q.push(context.Background(), PriorityLow)
+		low2 := q.push(context.Background(), PriorityLow)
+		high := q.push(context.Background(), PriorityHigh)
+
+		assert.True(t, started(high))
+		assert.False(t, q.isPreempted(low1))
+		assert.True(t, q.isPreempted(low2))
+		assert.ErrorIs(t, low2.ctx.Err(), context.Canceled)
+		assert.NoError(t, low1.ctx.Err())
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		q := newScanQueue(QueueOptions{MaxScans: 1})
+		_ = q.push(context.Background(), PriorityHigh)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := q.acquire(ctx, PriorityHigh)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Empty(t, q.waiting)
+	})
+}
+
+func Test_withScanQueue(t *testing.T) {
+	q := newScanQueue(QueueOptions{MaxScans: 1})
+
+	scanning := make(chan struct{})
+	finish := make(chan struct{})
+	base := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(ScanPriorityHeader) == PriorityLow {
+			close(scanning)
+			<-finish
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+	ts := httptest.NewServer(withScanQueue(base, q))
+	defer ts.Close()
+
+	lowResp := make(chan *http.Response)
+	go func() {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL, http.NoBody)
+		req.Header.Set(ScanPriorityHeader, PriorityLow)
+		resp, _ := http.DefaultClient.Do(req)
+		lowResp <- resp
+	}()
+	<-scanning
+
+	// The high priority scan preempts the running low priority scan
+	resp, err := http.Post(ts.URL, "application/json", http.NoBody)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	close(finish)
+	resp = <-lowResp
+	require.NotNil(t, resp)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
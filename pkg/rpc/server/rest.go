@@ -0,0 +1,206 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/twitchtv/twirp"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	rpcCache "github.com/aquasecurity/trivy/rpc/cache"
+	rpcScanner "github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+const (
+	// RESTPathPrefix is the path prefix of the REST API, which has the same functionality as the Twirp services
+	RESTPathPrefix = "/api/v1/"
+
+	// RESTScanPath is the path of the REST API scanning the cached artifact and blobs
+	RESTScanPath = RESTPathPrefix + "scan"
+
+	// OpenAPIPath is the path of the OpenAPI definition of the REST API and the other endpoints
+	OpenAPIPath = "/openapi.json"
+
+	// maxRESTRequestSize is the maximum size of a request body, which can be a large blob
+	maxRESTRequestSize = 512 << 20
+)
+
+//go:embed openapi.json
+var openAPIDefinition []byte
+
+// The field names are the same as the JSON of the Twirp services
+var (
+	restMarshalOptions   = protojson.MarshalOptions{UseProtoNames: true}
+	restUnmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// restHandler serves the REST API by calling the implementations of the Twirp services.
+// The errors are written in the same JSON as Twirp errors.
+type restHandler struct {
+	scanner rpcScanner.Scanner
+	cache   rpcCache.Cache
+}
+
+func newRESTHandler(s rpcScanner.Scanner, c rpcCache.Cache) *restHandler {
+	return &restHandler{
+		scanner: s,
+		cache:   c,
+	}
+}
+
+// ServeHTTP serves the cache routes. The scan route is served by scan so that it can go through the scan queue.
+func (h *restHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resource, id, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, RESTPathPrefix), "/")
+	switch {
+	case resource == "artifacts" && id != "" && r.Method == http.MethodPut:
+		h.putArtifact(w, r, id)
+	case resource == "blobs" && id == "missing" && r.Method == http.MethodPost:
+		h.missingBlobs(w, r)
+	case resource == "blobs" && id != "" && r.Method == http.MethodPut:
+		h.putBlob(w, r, id)
+	case resource == "blobs" && id != "" && r.Method == http.MethodDelete:
+		h.deleteBlob(w, r, id)
+	default:
+		rpcScanner.WriteError(w, twirp.NewError(twirp.BadRoute, "no route for "+r.Method+" "+r.URL.Path))
+	}
+}
+
+func (h *restHandler) scan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rpcScanner.WriteError(w, twirp.NewError(twirp.BadRoute, "the scan must be requested with POST"))
+		return
+	}
+	var req rpcScanner.ScanRequest
+	if !readRESTRequest(w, r, &req) {
+		return
+	}
+	res, err := h.scanner.Scan(r.Context(), &req)
+	if err != nil {
+		rpcScanner.WriteError(w, twirp.InternalErrorWith(err))
+		return
+	}
+	writeRESTResponse(w, res)
+}
+
+func (h *restHandler) putArtifact(w http.ResponseWriter, r *http.Request, id string) {
+	var info rpcCache.ArtifactInfo
+	if !readRESTRequest(w, r, &info) {
+		return
+	}
+	if _, err := h.cache.PutArtifact(r.Context(), &rpcCache.PutArtifactRequest{
+		ArtifactId:   id,
+		ArtifactInfo: &info,
+	}); err != nil {
+		rpcScanner.WriteError(w, twirp.InternalErrorWith(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *restHandler) putBlob(w http.ResponseWriter, r *http.Request, id string) {
+	var info rpcCache.BlobInfo
+	if !readRESTRequest(w, r, &info) {
+		return
+	}
+	if _, err := h.cache.PutBlob(r.Context(), &rpcCache.PutBlobRequest{
+		DiffId:   id,
+		BlobInfo: &info,
+	}); err != nil {
+		rpcScanner.WriteError(w, twirp.InternalErrorWith(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *restHandler) missingBlobs(w http.ResponseWriter, r *http.Request) {
+	var req rpcCache.MissingBlobsRequest
+	if !readRESTRequest(w, r, &req) {
+		return
+	}
+	res, err := h.cache.MissingBlobs(r.Context(), &req)
+	if err != nil {
+		rpcScanner.WriteError(w, twirp.InternalErrorWith(err))
+		return
+	}
+	writeRESTResponse(w, res)
+}
+
+func (h *restHandler) deleteBlob(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := h.cache.DeleteBlobs(r.Context(), &rpcCache.DeleteBlobsRequest{
+		BlobIds: []string{id},
+	}); err != nil {
+		rpcScanner.WriteError(w, twirp.InternalErrorWith(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readRESTRequest decodes the JSON body into m, or writes the error and returns false
+func readRESTRequest(w http.ResponseWriter, r *http.Request, m proto.Message) bool {
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxRESTRequestSize+1))
+	if err != nil {
+		rpcScanner.WriteError(w, twirp.NewError(twirp.Malformed, err.Error()))
+		return false
+	} else if len(b) > maxRESTRequestSize {
+		rpcScanner.WriteError(w, twirp.NewError(twirp.Malformed, "the request body is too large"))
+		return false
+	}
+	if err = restUnmarshalOptions.Unmarshal(b, m); err != nil {
+		rpcScanner.WriteError(w, twirp.NewError(twirp.Malformed, "invalid JSON: "+err.Error()))
+		return false
+	}
+	return true
+}
+
+func writeRESTResponse(w http.ResponseWriter, m proto.Message) {
+	b, err := restMarshalOptions.Marshal(m)
+	if err != nil {
+		rpcScanner.WriteError(w, twirp.InternalErrorWith(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(b); err != nil {
+		log.Logger.Errorf("Response write error: %s", err)
+	}
+}
+
+// newOpenAPIHandler serves the OpenAPI definition with the header of static tokens configured for the server
+func newOpenAPIHandler(tokenHeader string) http.Handler {
+	b, err := openAPIWithTokenHeader(tokenHeader)
+	if err != nil {
+		log.Logger.Errorf("OpenAPI definition error: %s", err)
+		b = openAPIDefinition
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(b); err != nil {
+			log.Logger.Errorf("Response write error: %s", err)
+		}
+	})
+}
+
+func openAPIWithTokenHeader(tokenHeader string) ([]byte, error) {
+	if tokenHeader == "" {
+		return openAPIDefinition, nil
+	}
+	var def map[string]any
+	if err := json.Unmarshal(openAPIDefinition, &def); err != nil {
+		return nil, xerrors.Errorf("json decode error: %w", err)
+	}
+	components, _ := def["components"].(map[string]any)
+	schemes, _ := components["securitySchemes"].(map[string]any)
+	if token, ok := schemes["token"].(map[string]any); ok {
+		token["name"] = tokenHeader
+	}
+	b, err := json.MarshalIndent(def, "", "  ")
+	if err != nil {
+		return nil, xerrors.Errorf("json encode error: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/cache"
+	"github.com/aquasecurity/trivy/rpc/common"
+	rpcScanner "github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+type fakeScanner struct{}
+
+func (fakeScanner) Scan(_ context.Context, in *rpcScanner.ScanRequest) (*rpcScanner.ScanResponse, error) {
+	return &rpcScanner.ScanResponse{
+		Os: &common.OS{
+			Family: "alpine",
+			Name:   "3.19.1",
+		},
+		Results: []*rpcScanner.Result{
+			{
+				Target: in.Target,
+				Class:  "os-pkgs",
+				Type:   "alpine",
+				Vulnerabilities: []*common.Vulnerability{
+					{
+						VulnerabilityId: "CVE-2024-0727",
+						PkgName:         "openssl",
+						Severity:        common.Severity_MEDIUM,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func Test_restHandler(t *testing.T) {
+	type request struct {
+		method string
+		path   string
+		body   string
+	}
+	type response struct {
+		status int
+		body   string
+	}
+	tests := []struct {
+		name     string
+		requests []request
+		want     []response
+	}{
+		{
+			name: "upload blobs",
+			requests: []request{
+				{
+					method: http.MethodPost,
+					path:   "/api/v1/blobs/missing",
+					body:   `{"artifact_id": "sha256:artifact", "blob_ids": ["sha256:blob"]}`,
+				},
+				{
+					method: http.MethodPut,
+					path:   "/api/v1/artifacts/sha256:artifact",
+					body:   `{"schema_version": 1, "architecture": "amd64", "os": "linux"}`,
+				},
+				{
+					method: http.MethodPut,
+					path:   "/api/v1/blobs/sha256:blob",
+					body:   `{"schema_version": 2, "os": {"family": "alpine", "name": "3.19.1"}}`,
+				},
+				{
+					method: http.MethodPost,
+					path:   "/api/v1/blobs/missing",
+					body:   `{"artifact_id": "sha256:artifact", "blob_ids": ["sha256:blob"]}`,
+				},
+				{
+					method: http.MethodDelete,
+					path:   "/api/v1/blobs/sha256:blob",
+				},
+				{
+					method: http.MethodPost,
+					path:   "/api/v1/blobs/missing",
+					body:   `{"artifact_id": "sha256:artifact", "blob_ids": ["sha256:blob"]}`,
+				},
+			},
+			want: []response{
+				{
+					status: http.StatusOK,
+					body:   `{"missing_artifact": true, "missing_blob_ids": ["sha256:blob"]}`,
+				},
+				{
+					status: http.StatusNoContent,
+				},
+				{
+					status: http.StatusNoContent,
+				},
+				{
+					status: http.StatusOK,
+					body:   `{}`,
+				},
+				{
+					status: http.StatusNoContent,
+				},
+				{
+					status: http.StatusOK,
+					body:   `{"missing_blob_ids": ["sha256:blob"]}`,
+				},
+			},
+		},
+		{
+			name: "scan",
+			requests: []request{
+				{
+					method: http.MethodPost,
+					path:   "/api/v1/scan",
+					body:   `{"target": "alpine:3.19", "artifact_id": "sha256:artifact", "options": {"scanners": ["vuln"]}}`,
+				},
+			},
+			want: []response{
+				{
+					status: http.StatusOK,
+					body: `{
+  "os": {"family": "alpine", "name": "3.19.1"},
+  "results": [
+    {
+      "target": "alpine:3.19",
+      "class": "os-pkgs",
+      "type": "alpine",
+      "vulnerabilities": [{"vulnerability_id": "CVE-2024-0727", "pkg_name": "openssl", "severity": "MEDIUM"}]
+    }
+  ]
+}`,
+				},
+			},
+		},
+		{
+			name: "sad path: invalid JSON",
+			requests: []request{
+				{
+					method: http.MethodPut,
+					path:   "/api/v1/blobs/sha256:blob",
+					body:   `{"schema_version": "two"}`,
+				},
+			},
+			want: []response{
+				{
+					status: http.StatusBadRequest,
+				},
+			},
+		},
+		{
+			name: "sad path: unknown route",
+			requests: []request{
+				{
+					method: http.MethodGet,
+					path:   "/api/v1/blobs/sha256:blob",
+				},
+				{
+					method: http.MethodGet,
+					path:   "/api/v1/scan",
+				},
+			},
+			want: []response{
+				{
+					status: http.StatusNotFound,
+				},
+				{
+					status: http.StatusNotFound,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := cache.NewFSCache(t.TempDir())
+			require.NoError(t, err)
+			defer func() { _ = c.Close() }()
+
+			rest := newRESTHandler(fakeScanner{}, NewCacheServer(c))
+			mux := http.NewServeMux()
+			mux.HandleFunc(RESTScanPath, rest.scan)
+			mux.Handle(RESTPathPrefix, rest)
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+
+			for i, r := range tt.requests {
+				req, err := http.NewRequest(r.method, ts.URL+r.path, strings.NewReader(r.body))
+				require.NoError(t, err)
+				resp, err := http.DefaultClient.Do(req)
+				require.NoError(t, err)
+
+				b, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				_ = resp.Body.Close()
+
+				want := tt.want[i]
+				assert.Equal(t, want.status, resp.StatusCode, r.path)
+				if want.body != "" {
+					assert.JSONEq(t, want.body, string(b), r.path)
+				}
+			}
+		})
+	}
+}
+
+func Test_openAPIWithTokenHeader(t *testing.T) {
+	b, err := openAPIWithTokenHeader("X-Trivy-Token")
+	require.NoError(t, err)
+
+	var def struct {
+		Paths      map[string]any
+		Components struct {
+			SecuritySchemes map[string]struct {
+				Name string
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(b, &def))
+	assert.Equal(t, "X-Trivy-Token", def.Components.SecuritySchemes["token"].Name)
+	for _, path := range []string{
+		RESTScanPath,
+		RESTPathPrefix + "artifacts/{id}",
+		RESTPathPrefix + "blobs/{id}",
+		RESTPathPrefix + "blobs/missing",
+		VerdictPath,
+		JobsPath,
+		MetricsPath,
+	} {
+		assert.Contains(t, def.Paths, path)
+	}
+}
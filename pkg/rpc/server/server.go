@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"time"
 
 	google_protobuf "github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/wire"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/aquasecurity/trivy/pkg/fanal/cache"
 	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/metrics"
 	"github.com/aquasecurity/trivy/pkg/rpc"
 	"github.com/aquasecurity/trivy/pkg/scanner"
 	"github.com/aquasecurity/trivy/pkg/scanner/local"
@@ -52,11 +54,15 @@ func (s *ScanServer) Scan(ctx context.Context, in *rpcScanner.ScanRequest) (*rpc
 		ListAllPackages: in.Options.ListAllPackages,
 		IncludeDevDeps:  in.Options.IncludeDevDeps,
 	}
+	start := time.Now()
 	results, os, err := s.localScanner.Scan(ctx, in.Target, in.ArtifactId, in.BlobIds, options)
+	metrics.ObserveScan(start, err)
 	if err != nil {
 		return nil, teeError(xerrors.Errorf("failed scan, %s: %w", in.Target, err))
 	}
 
+	metrics.RecordFindings(results)
+
 	return rpc.ConvertToRPCScanResponse(results, os), nil
 }
 
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/twitchtv/twirp"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/rpc"
+	rpcScanner "github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+// maxScanRequestSize is the maximum size of a scan request, which has only the keys of the cached artifact and blobs
+const maxScanRequestSize = 10 << 20
+
+// streamHandler scans in the same way as the Scan RPC, and streams the results in chunks
+// so that the response size is not limited by a single message.
+type streamHandler struct {
+	scanner rpcScanner.Scanner
+}
+
+func newStreamHandler(s rpcScanner.Scanner) *streamHandler {
+	return &streamHandler{scanner: s}
+}
+
+func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rpcScanner.WriteError(w, twirp.NewError(twirp.BadRoute, "the scan stream must be requested with POST"))
+		return
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxScanRequestSize+1))
+	if err != nil {
+		rpcScanner.WriteError(w, twirp.NewError(twirp.Malformed, err.Error()))
+		return
+	} else if len(b) > maxScanRequestSize {
+		rpcScanner.WriteError(w, twirp.NewError(twirp.Malformed, "the scan request is too large"))
+		return
+	}
+
+	var req rpcScanner.ScanRequest
+	if err = proto.Unmarshal(b, &req); err != nil {
+		rpcScanner.WriteError(w, twirp.NewError(twirp.Malformed, err.Error()))
+		return
+	}
+
+	res, err := h.scanner.Scan(r.Context(), &req)
+	if err != nil {
+		rpcScanner.WriteError(w, twirp.InternalErrorWith(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", rpc.ScanStreamContentType)
+	w.WriteHeader(http.StatusOK)
+	// The status has already been sent, so the client detects the failure by the truncated stream
+	if err = rpc.WriteScanStream(w, res); err != nil {
+		log.Logger.Errorf("Scan stream error: %s", err)
+	}
+}
@@ -0,0 +1,18 @@
+package trivy
+
+import future.keywords.in
+
+deny[msg] {
+	some result in input.Results
+	some vuln in result.Vulnerabilities
+	vuln.Severity == "CRITICAL"
+	msg := sprintf("%s in %s is critical", [vuln.VulnerabilityID, vuln.PkgName])
+}
+
+deny[msg] {
+	some result in input.Results
+	some misconf in result.Misconfigurations
+	misconf.Status == "FAIL"
+	misconf.Severity in {"HIGH", "CRITICAL"}
+	msg := sprintf("%s: %s", [misconf.ID, misconf.Message])
+}
@@ -0,0 +1,10 @@
+package trivy
+
+import future.keywords.in
+
+deny[msg] {
+	some result in input.Results
+	some vuln in result.Vulnerabilities
+	vuln.Severity == "CRITICAL"
+	msg := sprintf("%s in %s is critical", [vuln.VulnerabilityID, vuln.PkgName])
+}
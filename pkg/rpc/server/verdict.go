@@ -0,0 +1,266 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/result"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// VerdictPath is the path of the endpoint returning the admission verdict of a container image
+const VerdictPath = "/verdict"
+
+const (
+	// verdictCacheSize is the maximum number of images whose scan results are kept for verdicts.
+	// The results expire with the DB update interval so that new vulnerabilities are taken into account.
+	verdictCacheSize = 1000
+
+	// maxVerdictScans is the maximum number of images scanned concurrently for verdicts
+	maxVerdictScans = 5
+
+	verdictScanTimeout = 10 * time.Minute
+)
+
+var policyNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ImageScanFunc scans the container image in the registry
+type ImageScanFunc func(ctx context.Context, imageRef string) (types.Report, error)
+
+// ManifestScanFunc scans misconfigurations of the Kubernetes manifest
+type ManifestScanFunc func(ctx context.Context, manifest []byte) (types.Report, error)
+
+// DigestResolveFunc resolves the image reference, e.g. with a tag, into the digest in the registry
+type DigestResolveFunc func(ctx context.Context, image string) (name.Digest, error)
+
+// VerdictOptions configures the verdict and admission review endpoints for admission webhooks.
+// The endpoints are enabled when PolicyDir is set.
+type VerdictOptions struct {
+	PolicyDir    string        // The policy reference of a request is the file name without ".rego" in the directory
+	Timeout      time.Duration // The latency budget of a verdict
+	FailOpen     bool          // Allow images when the scan fails or doesn't complete within the budget
+	Scan         ImageScanFunc
+	ScanManifest ManifestScanFunc  // For the workload manifests of admission reviews
+	Resolve      DigestResolveFunc // For the images of admission reviews, which are usually referenced by tags
+}
+
+// VerdictRequest is the request body of the verdict endpoint
+type VerdictRequest struct {
+	Image  string // The image reference with the digest, e.g. ghcr.io/org/app@sha256:...
+	Policy string
+}
+
+// VerdictResponse is the response body of the verdict endpoint
+type VerdictResponse struct {
+	Allowed bool
+	Reasons []string `json:",omitempty"` // The messages of the denying rules, or why the verdict is undecided
+	Cached  bool     // Whether the cached scan result was used
+	Pending bool     `json:",omitempty"` // Whether the scan is still running
+}
+
+type verdictHandler struct {
+	ctx        context.Context
+	opts       VerdictOptions
+	dbUpdateWg *sync.WaitGroup
+	requestWg  *sync.WaitGroup
+	limit      *semaphore.Weighted
+
+	// reports are the scan results by image digest
+	reports *expirable.LRU[string, types.Report]
+
+	mu    sync.Mutex
+	scans map[string]*verdictScan // in-flight scans by image digest
+}
+
+type verdictScan struct {
+	done   chan struct{}
+	report types.Report
+	err    error
+}
+
+func newVerdictHandler(ctx context.Context, opts VerdictOptions, dbUpdateWg, requestWg *sync.WaitGroup) *verdictHandler {
+	return &verdictHandler{
+		ctx:        ctx,
+		opts:       opts,
+		dbUpdateWg: dbUpdateWg,
+		requestWg:  requestWg,
+		limit:      semaphore.NewWeighted(maxVerdictScans),
+		reports:    expirable.NewLRU[string, types.Report](verdictCacheSize, nil, updateInterval),
+		scans:      make(map[string]*verdictScan),
+	}
+}
+
+func (h *verdictHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req VerdictRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+	ref, err := name.NewDigest(req.Image)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("the image must be referenced by digest: %s", err), http.StatusBadRequest)
+		return
+	}
+	policyFile, err := h.policyFile(req.Policy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	res, err := h.verdict(r.Context(), ref, policyFile)
+	if err != nil {
+		log.Logger.Errorf("Verdict error (%s): %s", ref, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		log.Logger.Errorf("Verdict response error: %s", err)
+	}
+}
+
+func (h *verdictHandler) policyFile(policy string) (string, error) {
+	if !policyNameRegexp.MatchString(policy) {
+		return "", xerrors.Errorf("invalid policy: %q", policy)
+	}
+	policyFile := filepath.Join(h.opts.PolicyDir, policy+".rego")
+	if _, err := os.Stat(policyFile); err != nil {
+		return "", xerrors.Errorf("unknown policy: %q", policy)
+	}
+	return policyFile, nil
+}
+
+// verdict evaluates the policy against the cached scan result of the image.
+// If the image has not been scanned, it waits for the scan until the latency budget runs out.
+func (h *verdictHandler) verdict(ctx context.Context, ref name.Digest, policyFile string) (VerdictResponse, error) {
+	budget, cancel := context.WithTimeout(context.Background(), h.opts.Timeout)
+	defer cancel()
+
+	report, cached, err := h.imageReport(ctx, ref, budget.Done())
+	var u *undecidedError
+	if errors.As(err, &u) {
+		return h.undecided(u.reason, u.pending), nil
+	} else if err != nil {
+		return VerdictResponse{}, err
+	}
+
+	messages, err := result.Gate(ctx, report, policyFile)
+	if err != nil {
+		return VerdictResponse{}, xerrors.Errorf("policy error: %w", err)
+	}
+	return VerdictResponse{
+		Allowed: len(messages) == 0,
+		Reasons: messages,
+		Cached:  cached,
+	}, nil
+}
+
+// undecidedError represents why the verdict cannot be decided
+type undecidedError struct {
+	reason  string
+	pending bool // Whether the scan is still running
+}
+
+func (e *undecidedError) Error() string {
+	return e.reason
+}
+
+// imageReport returns the cached scan result of the image, or waits for the scan until the budget is done.
+// An undecidedError is returned when the scan doesn't complete within the budget or fails.
+func (h *verdictHandler) imageReport(ctx context.Context, ref name.Digest, budget <-chan struct{}) (types.Report, bool, error) {
+	if report, cached := h.reports.Get(ref.DigestStr()); cached {
+		return report, true, nil
+	}
+
+	s := h.scan(ref)
+	select {
+	case <-s.done:
+	case <-budget:
+		// The scan continues in the background for the next request
+		return types.Report{}, false, &undecidedError{
+			reason:  "the scan did not complete within the latency budget",
+			pending: true,
+		}
+	case <-ctx.Done():
+		return types.Report{}, false, ctx.Err()
+	}
+	if s.err != nil {
+		return types.Report{}, false, &undecidedError{reason: fmt.Sprintf("scan error: %s", s.err)}
+	}
+	return s.report, false, nil
+}
+
+func (h *verdictHandler) undecided(reason string, pending bool) VerdictResponse {
+	return VerdictResponse{
+		Allowed: h.opts.FailOpen,
+		Reasons: []string{reason},
+		Pending: pending,
+	}
+}
+
+// scan starts scanning the image unless it is being scanned
+func (h *verdictHandler) scan(ref name.Digest) *verdictScan {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := ref.DigestStr()
+	if s, ok := h.scans[key]; ok {
+		return s
+	}
+
+	s := &verdictScan{done: make(chan struct{})}
+	h.scans[key] = s
+	go func() {
+		defer func() {
+			h.mu.Lock()
+			delete(h.scans, key)
+			h.mu.Unlock()
+			close(s.done)
+		}()
+
+		if s.report, s.err = h.runScan(ref); s.err != nil {
+			log.Logger.Errorf("Unable to scan %s for the verdict: %s", ref, s.err)
+			return
+		}
+		h.reports.Add(key, s.report)
+	}()
+	return s
+}
+
+func (h *verdictHandler) runScan(ref name.Digest) (types.Report, error) {
+	ctx, cancel := context.WithTimeout(h.ctx, verdictScanTimeout)
+	defer cancel()
+
+	if err := h.limit.Acquire(ctx, 1); err != nil {
+		return types.Report{}, xerrors.Errorf("semaphore acquire: %w", err)
+	}
+	defer h.limit.Release(1)
+
+	// Stop scanning during DB update, and block DB update during the scan
+	h.dbUpdateWg.Wait()
+	h.requestWg.Add(1)
+	defer h.requestWg.Done()
+
+	log.Logger.Infof("Scanning %s for the verdict...", ref)
+	return h.opts.Scan(ctx, ref.String())
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const testImageDigest = "sha256:5f8ac8a3e1e3a9b7c1b5f9ac1d2f6b6d1e1a3b7c5e2f4a6b8c0d2e4f6a8b0c2d"
+
+func Test_verdictHandler(t *testing.T) {
+	criticalReport := types.Report{
+		Results: types.Results{
+			{
+				Target: "alpine:3.19",
+				Vulnerabilities: []types.DetectedVulnerability{
+					{
+						VulnerabilityID: "CVE-2024-0001",
+						PkgName:         "musl",
+						Vulnerability: dbTypes.Vulnerability{
+							Severity: "CRITICAL",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		cached     *types.Report
+		failOpen   bool
+		scan       ImageScanFunc
+		wantStatus int
+		want       VerdictResponse
+	}{
+		{
+			name: "allowed",
+			body: `{"Image": "ghcr.io/org/app@` + testImageDigest + `", "Policy": "no-critical"}`,
+			scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+				return types.Report{ArtifactName: imageRef}, nil
+			},
+			wantStatus: http.StatusOK,
+			want: VerdictResponse{
+				Allowed: true,
+			},
+		},
+		{
+			name: "denied",
+			body: `{"Image": "ghcr.io/org/app@` + testImageDigest + `", "Policy": "no-critical"}`,
+			scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+				return criticalReport, nil
+			},
+			wantStatus: http.StatusOK,
+			want: VerdictResponse{
+				Allowed: false,
+				Reasons: []string{"CVE-2024-0001 in musl is critical"},
+			},
+		},
+		{
+			name:       "cached",
+			body:       `{"Image": "ghcr.io/org/app@` + testImageDigest + `", "Policy": "no-critical"}`,
+			cached:     &criticalReport,
+			wantStatus: http.StatusOK,
+			want: VerdictResponse{
+				Allowed: false,
+				Reasons: []string{"CVE-2024-0001 in musl is critical"},
+				Cached:  true,
+			},
+		},
+		{
+			name:     "pending with fail-open",
+			body:     `{"Image": "ghcr.io/org/app@` + testImageDigest + `", "Policy": "no-critical"}`,
+			failOpen: true,
+			scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+				<-ctx.Done()
+				return types.Report{}, ctx.Err()
+			},
+			wantStatus: http.StatusOK,
+			want: VerdictResponse{
+				Allowed: true,
+				Reasons: []string{"the scan did not complete within the latency budget"},
+				Pending: true,
+			},
+		},
+		{
+			name: "scan error with fail-closed",
+			body: `{"Image": "ghcr.io/org/app@` + testImageDigest + `", "Policy": "no-critical"}`,
+			scan: func(ctx context.Context, imageRef string) (types.Report, error) {
+				return types.Report{}, xerrors.New("unauthorized")
+			},
+			wantStatus: http.StatusOK,
+			want: VerdictResponse{
+				Allowed: false,
+				Reasons: []string{"scan error: unauthorized"},
+			},
+		},
+		{
+			name:       "tag without digest",
+			body:       `{"Image": "ghcr.io/org/app:latest", "Policy": "no-critical"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown policy",
+			body:       `{"Image": "ghcr.io/org/app@` + testImageDigest + `", "Policy": "unknown"}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "policy outside the directory",
+			body:       `{"Image": "ghcr.io/org/app@` + testImageDigest + `", "Policy": "../verdict/no-critical"}`,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			h := newVerdictHandler(ctx, VerdictOptions{
+				PolicyDir: "testdata/verdict",
+				Timeout:   100 * time.Millisecond,
+				FailOpen:  tt.failOpen,
+				Scan:      tt.scan,
+			}, &sync.WaitGroup{}, &sync.WaitGroup{})
+			if tt.cached != nil {
+				h.reports.Add(testImageDigest, *tt.cached)
+			}
+
+			ts := httptest.NewServer(h)
+			defer ts.Close()
+
+			resp, err := http.Post(ts.URL+VerdictPath, "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got VerdictResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/report/webhook"
+	"github.com/aquasecurity/trivy/pkg/rpc"
+	"github.com/aquasecurity/trivy/pkg/types"
+	rpcScanner "github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+// notifyingScanner posts the summary of completed scans to the webhook.
+// It wraps the scanner shared by the Twirp service, the scan stream and the REST API.
+type notifyingScanner struct {
+	rpcScanner.Scanner
+	notifier *webhook.Notifier
+}
+
+func withWebhook(s rpcScanner.Scanner, notifier *webhook.Notifier) rpcScanner.Scanner {
+	if notifier == nil {
+		return s
+	}
+	return notifyingScanner{
+		Scanner:  s,
+		notifier: notifier,
+	}
+}
+
+func (s notifyingScanner) Scan(ctx context.Context, in *rpcScanner.ScanRequest) (*rpcScanner.ScanResponse, error) {
+	res, err := s.Scanner.Scan(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	report := types.Report{
+		ArtifactName: in.Target,
+		Results:      rpc.ConvertFromRPCResults(res.Results),
+	}
+	// The response doesn't wait for the webhook
+	go func() {
+		if err := s.notifier.Notify(context.WithoutCancel(ctx), report); err != nil {
+			log.Logger.Warnf("Unable to send the webhook notification of %s: %s", in.Target, err)
+		}
+	}()
+	return res, nil
+}
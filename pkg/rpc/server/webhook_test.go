@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/report/webhook"
+	rpcScanner "github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+func Test_notifyingScanner_Scan(t *testing.T) {
+	payloads := make(chan webhook.Payload, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhook.Payload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		payloads <- payload
+	}))
+	defer ts.Close()
+
+	notifier, err := webhook.NewNotifier(webhook.Options{URL: ts.URL})
+	require.NoError(t, err)
+
+	s := withWebhook(fakeScanner{}, notifier)
+	res, err := s.Scan(context.Background(), &rpcScanner.ScanRequest{Target: "alpine:3.19"})
+	require.NoError(t, err)
+	assert.Len(t, res.Results, 1)
+
+	select {
+	case payload := <-payloads:
+		assert.Equal(t, webhook.Payload{
+			ArtifactName: "alpine:3.19",
+			Verdict:      "FAIL",
+			Targets:      1,
+			Total:        1,
+			Findings: map[string]webhook.Counts{
+				"Vulnerabilities": {
+					Total:      1,
+					Severities: map[string]int{"MEDIUM": 1},
+				},
+			},
+		}, payload)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the webhook was not notified")
+	}
+}
@@ -8,6 +8,7 @@ package server
 
 import (
 	"github.com/aquasecurity/trivy-db/pkg/db"
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/fanal/applier"
 	"github.com/aquasecurity/trivy/pkg/fanal/cache"
 	"github.com/aquasecurity/trivy/pkg/scanner/langpkg"
@@ -18,10 +19,10 @@ import (
 
 // Injectors from inject.go:
 
-func initializeScanServer(localArtifactCache cache.LocalArtifactCache) *ScanServer {
+func initializeScanServer(localArtifactCache cache.LocalArtifactCache, online *advisory.OnlineSource) *ScanServer {
 	applierApplier := applier.NewApplier(localArtifactCache)
 	scanner := ospkg.NewScanner()
-	langpkgScanner := langpkg.NewScanner()
+	langpkgScanner := langpkg.NewScanner(online)
 	config := db.Config{}
 	client := vulnerability.NewClient(config)
 	localScanner := local.NewScanner(applierApplier, scanner, langpkgScanner, client)
@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"github.com/samber/lo"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"github.com/aquasecurity/trivy/rpc/common"
+	"github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+const (
+	// ScanStreamPath is the path of the endpoint streaming scan results in chunks,
+	// so that large reports, e.g. of big SBOMs, are not transferred as a single message.
+	ScanStreamPath = "/scan/stream"
+
+	// ScanStreamContentType is the content type of the stream.
+	//
+	// The stream consists of length-delimited ScanResponse messages:
+	//   - a chunk with a single result holding a part of its findings and packages
+	//   - an empty message ending the result whose chunks precede it
+	//   - a message with the OS ending the stream
+	ScanStreamContentType = "application/vnd.aquasec.trivy.scan-stream+protobuf"
+
+	// maxChunkItems is the maximum number of findings and packages in a chunk
+	maxChunkItems = 500
+
+	// maxChunkSize is the maximum size of a chunk to be read
+	maxChunkSize = 64 << 20
+)
+
+// WriteScanStream writes the scan response to w in chunks
+func WriteScanStream(w io.Writer, res *scanner.ScanResponse) error {
+	flusher, _ := w.(interface{ Flush() })
+	for _, result := range res.Results {
+		for _, chunk := range splitResult(result, maxChunkItems) {
+			if _, err := protodelim.MarshalTo(w, &scanner.ScanResponse{Results: []*scanner.Result{chunk}}); err != nil {
+				return xerrors.Errorf("chunk write error: %w", err)
+			}
+		}
+		if _, err := protodelim.MarshalTo(w, &scanner.ScanResponse{}); err != nil {
+			return xerrors.Errorf("chunk write error: %w", err)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	os := res.Os
+	if os == nil {
+		os = &common.OS{}
+	}
+	if _, err := protodelim.MarshalTo(w, &scanner.ScanResponse{Os: os}); err != nil {
+		return xerrors.Errorf("chunk write error: %w", err)
+	}
+	return nil
+}
+
+// ReadScanStream reads the chunks written by WriteScanStream and assembles the scan response
+func ReadScanStream(r io.Reader) (*scanner.ScanResponse, error) {
+	br := bufio.NewReader(r)
+	opts := protodelim.UnmarshalOptions{MaxSize: maxChunkSize}
+
+	res := &scanner.ScanResponse{}
+	var current *scanner.Result
+	for {
+		var chunk scanner.ScanResponse
+		if err := opts.UnmarshalFrom(br, &chunk); errors.Is(err, io.EOF) {
+			return nil, xerrors.New("the scan stream ended unexpectedly")
+		} else if err != nil {
+			return nil, xerrors.Errorf("chunk read error: %w", err)
+		}
+
+		switch {
+		case chunk.Os != nil:
+			if current != nil {
+				return nil, xerrors.New("the scan stream ended in the middle of a result")
+			}
+			res.Os = chunk.Os
+			return res, nil
+		case len(chunk.Results) == 0:
+			if current == nil {
+				return nil, xerrors.New("the scan stream has an empty result")
+			}
+			res.Results = append(res.Results, current)
+			current = nil
+		case len(chunk.Results) != 1:
+			return nil, xerrors.Errorf("a chunk must have a single result, got %d", len(chunk.Results))
+		case current == nil:
+			current = chunk.Results[0]
+		default:
+			mergeResult(current, chunk.Results[0])
+		}
+	}
+}
+
+// splitResult splits the result into chunks of up to size findings and packages.
+// Every chunk has the target, class and type of the result.
+func splitResult(result *scanner.Result, size int) []*scanner.Result {
+	newChunk := func() *scanner.Result {
+		return &scanner.Result{
+			Target: result.Target,
+			Class:  result.Class,
+			Type:   result.Type,
+		}
+	}
+
+	// The first chunk is sent even if the result has nothing else
+	chunks := []*scanner.Result{newChunk()}
+	for _, vulns := range lo.Chunk(result.Vulnerabilities, size) {
+		chunk := newChunk()
+		chunk.Vulnerabilities = vulns
+		chunks = append(chunks, chunk)
+	}
+	for _, misconfs := range lo.Chunk(result.Misconfigurations, size) {
+		chunk := newChunk()
+		chunk.Misconfigurations = misconfs
+		chunks = append(chunks, chunk)
+	}
+	for _, pkgs := range lo.Chunk(result.Packages, size) {
+		chunk := newChunk()
+		chunk.Packages = pkgs
+		chunks = append(chunks, chunk)
+	}
+	for _, resources := range lo.Chunk(result.CustomResources, size) {
+		chunk := newChunk()
+		chunk.CustomResources = resources
+		chunks = append(chunks, chunk)
+	}
+	for _, secrets := range lo.Chunk(result.Secrets, size) {
+		chunk := newChunk()
+		chunk.Secrets = secrets
+		chunks = append(chunks, chunk)
+	}
+	for _, licenses := range lo.Chunk(result.Licenses, size) {
+		chunk := newChunk()
+		chunk.Licenses = licenses
+		chunks = append(chunks, chunk)
+	}
+	for _, certs := range lo.Chunk(result.Certificates, size) {
+		chunk := newChunk()
+		chunk.Certificates = certs
+		chunks = append(chunks, chunk)
+	}
+	for _, keys := range lo.Chunk(result.PrivateKeys, size) {
+		chunk := newChunk()
+		chunk.PrivateKeys = keys
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func mergeResult(dst, src *scanner.Result) {
+	dst.Vulnerabilities = append(dst.Vulnerabilities, src.Vulnerabilities...)
+	dst.Misconfigurations = append(dst.Misconfigurations, src.Misconfigurations...)
+	dst.Packages = append(dst.Packages, src.Packages...)
+	dst.CustomResources = append(dst.CustomResources, src.CustomResources...)
+	dst.Secrets = append(dst.Secrets, src.Secrets...)
+	dst.Licenses = append(dst.Licenses, src.Licenses...)
+	dst.Certificates = append(dst.Certificates, src.Certificates...)
+	dst.PrivateKeys = append(dst.PrivateKeys, src.PrivateKeys...)
+}
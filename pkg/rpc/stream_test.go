@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/aquasecurity/trivy/rpc/common"
+	"github.com/aquasecurity/trivy/rpc/scanner"
+)
+
+func TestScanStream(t *testing.T) {
+	var pkgs []*common.Package
+	for i := 0; i < maxChunkItems*2+1; i++ {
+		pkgs = append(pkgs, &common.Package{
+			Name:    fmt.Sprintf("pkg-%d", i),
+			Version: "1.0.0",
+		})
+	}
+
+	tests := []struct {
+		name  string
+		input *scanner.ScanResponse
+		want  *scanner.ScanResponse
+	}{
+		{
+			name: "large results",
+			input: &scanner.ScanResponse{
+				Os: &common.OS{
+					Family: "alpine",
+					Name:   "3.19.1",
+				},
+				Results: []*scanner.Result{
+					{
+						Target: "alpine:3.19 (alpine 3.19.1)",
+						Class:  "os-pkgs",
+						Type:   "alpine",
+						Vulnerabilities: []*common.Vulnerability{
+							{
+								VulnerabilityId: "CVE-2024-0727",
+								PkgName:         "pkg-0",
+							},
+						},
+						Packages: pkgs,
+					},
+					{
+						Target: "sbom.cdx.json",
+						Class:  "lang-pkgs",
+						Type:   "jar",
+					},
+					{
+						Target: "sbom.cdx.json",
+						Class:  "lang-pkgs",
+						Type:   "jar",
+						Licenses: []*common.DetectedLicense{
+							{
+								Name: "MIT",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "no OS and results",
+			input: &scanner.ScanResponse{},
+			want: &scanner.ScanResponse{
+				Os: &common.OS{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteScanStream(&buf, tt.input))
+
+			got, err := ReadScanStream(&buf)
+			require.NoError(t, err)
+
+			want := tt.want
+			if want == nil {
+				want = tt.input
+			}
+			assert.True(t, proto.Equal(want, got), "got: %v", got)
+		})
+	}
+}
+
+func TestReadScanStream_Truncated(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteScanStream(&buf, &scanner.ScanResponse{
+		Results: []*scanner.Result{
+			{
+				Target: "go.mod",
+				Class:  "lang-pkgs",
+				Type:   "gomod",
+			},
+		},
+	}))
+
+	// Cut off the end of the stream
+	b := buf.Bytes()
+	_, err := ReadScanStream(bytes.NewReader(b[:len(b)-3]))
+	require.ErrorContains(t, err, "the scan stream ended unexpectedly")
+}
@@ -0,0 +1,194 @@
+package rpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/log"
+)
+
+// TLSOptions holds the TLS settings of client/server mode
+type TLSOptions struct {
+	// CACert is the CA certificate to verify the peer.
+	// The server requires client certificates signed by it.
+	CACert string
+
+	// Cert and Key are the certificate presented to the peer
+	Cert string
+	Key  string
+
+	// SPIFFEIDs are the SPIFFE IDs allowed in the URI SAN of the peer certificate
+	SPIFFEIDs []string
+
+	// Insecure skips verifying the server certificate in client mode
+	Insecure bool
+}
+
+// NewServerTLSConfig returns the TLS config of the server, or nil if TLS is disabled.
+// The certificate and the CA certificate are reloaded when the files are updated, so that they can be rotated without restart.
+func NewServerTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	if opts.Cert == "" {
+		if opts.CACert != "" || len(opts.SPIFFEIDs) > 0 {
+			return nil, xerrors.New("the server certificate is required for client certificate authentication")
+		}
+		return nil, nil
+	}
+	if len(opts.SPIFFEIDs) > 0 && opts.CACert == "" {
+		return nil, xerrors.New("the CA certificate is required to verify SPIFFE IDs of clients")
+	}
+
+	cert := &reloader[tls.Certificate]{paths: []string{opts.Cert, opts.Key}, load: loadKeyPair}
+	if _, err := cert.get(); err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return cert.get()
+		},
+	}
+	if opts.CACert == "" {
+		return config, nil
+	}
+
+	caCert := &reloader[x509.CertPool]{paths: []string{opts.CACert}, load: loadCertPool}
+	if _, err := caCert.get(); err != nil {
+		return nil, err
+	}
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		pool, err := caCert.get()
+		if err != nil {
+			return nil, err
+		}
+		c := config.Clone()
+		c.GetConfigForClient = nil
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+		c.ClientCAs = pool
+		c.VerifyConnection = verifySPIFFEID(opts.SPIFFEIDs)
+		return c, nil
+	}
+	return config, nil
+}
+
+// NewClientTLSConfig returns the TLS config of the client
+func NewClientTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	if opts.Insecure && len(opts.SPIFFEIDs) > 0 {
+		// The SPIFFE ID of the unverified certificate could be forged by anyone
+		return nil, xerrors.New("SPIFFE IDs of the server can't be verified with '--insecure'")
+	}
+
+	config := &tls.Config{
+		InsecureSkipVerify: opts.Insecure,
+		VerifyConnection:   verifySPIFFEID(opts.SPIFFEIDs),
+	}
+	if opts.CACert != "" {
+		pool, err := loadCertPool(opts.CACert)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	if opts.Cert != "" {
+		cert := &reloader[tls.Certificate]{paths: []string{opts.Cert, opts.Key}, load: loadKeyPair}
+		if _, err := cert.get(); err != nil {
+			return nil, err
+		}
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return cert.get()
+		}
+	}
+	return config, nil
+}
+
+// verifySPIFFEID verifies the SPIFFE ID of the peer certificate, which has been verified against the CA
+func verifySPIFFEID(ids []string) func(tls.ConnectionState) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return xerrors.New("no peer certificate for SPIFFE ID verification")
+		}
+		for _, uri := range cs.PeerCertificates[0].URIs {
+			if uri.Scheme == "spiffe" && slices.Contains(ids, uri.String()) {
+				return nil
+			}
+		}
+		return xerrors.New("the SPIFFE ID of the peer certificate is not allowed")
+	}
+}
+
+// reloader loads the value from the files and reloads it when any of them is modified
+type reloader[T any] struct {
+	paths []string
+	load  func(paths ...string) (*T, error)
+
+	mu      sync.Mutex
+	value   *T
+	modTime time.Time
+}
+
+func (r *reloader[T]) get() (*T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var modTime time.Time
+	for _, path := range r.paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			if r.value != nil {
+				// Keep using the current value, e.g. while the files are being replaced
+				log.Logger.Debugf("Unable to stat %s: %s", path, err)
+				return r.value, nil
+			}
+			return nil, xerrors.Errorf("unable to stat %s: %w", path, err)
+		}
+		if fi.ModTime().After(modTime) {
+			modTime = fi.ModTime()
+		}
+	}
+	if r.value != nil && modTime.Equal(r.modTime) {
+		return r.value, nil
+	}
+
+	value, err := r.load(r.paths...)
+	if err != nil {
+		if r.value != nil {
+			log.Logger.Warnf("Unable to reload %v, keeping the current one: %s", r.paths, err)
+			return r.value, nil
+		}
+		return nil, err
+	}
+	if r.value != nil {
+		log.Logger.Infof("Reloaded %v", r.paths)
+	}
+	r.value, r.modTime = value, modTime
+	return r.value, nil
+}
+
+func loadKeyPair(paths ...string) (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(paths[0], paths[1])
+	if err != nil {
+		return nil, xerrors.Errorf("unable to load the certificate: %w", err)
+	}
+	return &cert, nil
+}
+
+func loadCertPool(paths ...string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(paths[0])
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read the CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, xerrors.Errorf("no valid certificate in %s", paths[0])
+	}
+	return pool, nil
+}
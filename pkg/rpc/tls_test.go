@@ -0,0 +1,295 @@
+package rpc_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/rpc"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	dir  string
+}
+
+func newTestCA(t *testing.T) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	ca := testCA{cert: cert, key: key, dir: t.TempDir()}
+	writePEM(t, ca.path("ca.crt"), "CERTIFICATE", der)
+	return ca
+}
+
+func (ca testCA) path(name string) string {
+	return filepath.Join(ca.dir, name)
+}
+
+// issue writes the certificate and the key signed by the CA and returns their paths
+func (ca testCA) issue(t *testing.T, name, spiffeID string, serial int64) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if spiffeID != "" {
+		u, err := url.Parse(spiffeID)
+		require.NoError(t, err)
+		tmpl.URIs = []*url.URL{u}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath, keyPath := ca.path(name+".crt"), ca.path(name+".key")
+	writePEM(t, certPath, "CERTIFICATE", der)
+	writePEM(t, keyPath, "EC PRIVATE KEY", keyDER)
+	return certPath, keyPath
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	b := pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+	require.NoError(t, os.WriteFile(path, b, 0600))
+}
+
+func TestTLSConfig(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "server", "spiffe://example.org/trivy-server", 2)
+	clientCert, clientKey := ca.issue(t, "client", "spiffe://example.org/ci", 3)
+	otherCert, otherKey := ca.issue(t, "other", "spiffe://example.org/other", 4)
+
+	tests := []struct {
+		name       string
+		serverOpts rpc.TLSOptions
+		clientOpts rpc.TLSOptions
+		wantErr    bool
+	}{
+		{
+			name: "server TLS",
+			serverOpts: rpc.TLSOptions{
+				Cert: serverCert,
+				Key:  serverKey,
+			},
+			clientOpts: rpc.TLSOptions{
+				CACert: ca.path("ca.crt"),
+			},
+		},
+		{
+			name: "mutual TLS with SPIFFE IDs",
+			serverOpts: rpc.TLSOptions{
+				CACert:    ca.path("ca.crt"),
+				Cert:      serverCert,
+				Key:       serverKey,
+				SPIFFEIDs: []string{"spiffe://example.org/ci"},
+			},
+			clientOpts: rpc.TLSOptions{
+				CACert:    ca.path("ca.crt"),
+				Cert:      clientCert,
+				Key:       clientKey,
+				SPIFFEIDs: []string{"spiffe://example.org/trivy-server"},
+			},
+		},
+		{
+			name: "no client certificate",
+			serverOpts: rpc.TLSOptions{
+				CACert: ca.path("ca.crt"),
+				Cert:   serverCert,
+				Key:    serverKey,
+			},
+			clientOpts: rpc.TLSOptions{
+				CACert: ca.path("ca.crt"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "client SPIFFE ID not allowed",
+			serverOpts: rpc.TLSOptions{
+				CACert:    ca.path("ca.crt"),
+				Cert:      serverCert,
+				Key:       serverKey,
+				SPIFFEIDs: []string{"spiffe://example.org/ci"},
+			},
+			clientOpts: rpc.TLSOptions{
+				CACert: ca.path("ca.crt"),
+				Cert:   otherCert,
+				Key:    otherKey,
+			},
+			wantErr: true,
+		},
+		{
+			name: "server SPIFFE ID not allowed",
+			serverOpts: rpc.TLSOptions{
+				Cert: serverCert,
+				Key:  serverKey,
+			},
+			clientOpts: rpc.TLSOptions{
+				CACert:    ca.path("ca.crt"),
+				SPIFFEIDs: []string{"spiffe://example.org/other"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown CA",
+			serverOpts: rpc.TLSOptions{
+				Cert: serverCert,
+				Key:  serverKey,
+			},
+			clientOpts: rpc.TLSOptions{
+				CACert: newTestCA(t).path("ca.crt"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverConfig, err := rpc.NewServerTLSConfig(tt.serverOpts)
+			require.NoError(t, err)
+			serverURL := newTLSServer(t, serverConfig)
+
+			clientConfig, err := rpc.NewClientTLSConfig(tt.clientOpts)
+			require.NoError(t, err)
+			_, err = get(serverURL, clientConfig)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewClientTLSConfig_InsecureSPIFFEIDs(t *testing.T) {
+	_, err := rpc.NewClientTLSConfig(rpc.TLSOptions{
+		SPIFFEIDs: []string{"spiffe://example.org/trivy-server"},
+		Insecure:  true,
+	})
+	assert.ErrorContains(t, err, "SPIFFE IDs of the server can't be verified with '--insecure'")
+}
+
+func TestNewServerTLSConfig_Rotation(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "server", "", 2)
+
+	serverConfig, err := rpc.NewServerTLSConfig(rpc.TLSOptions{
+		Cert: serverCert,
+		Key:  serverKey,
+	})
+	require.NoError(t, err)
+	serverURL := newTLSServer(t, serverConfig)
+
+	clientConfig, err := rpc.NewClientTLSConfig(rpc.TLSOptions{CACert: ca.path("ca.crt")})
+	require.NoError(t, err)
+
+	serial, err := get(serverURL, clientConfig)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), serial)
+
+	// Renew the certificate without restart
+	ca.issue(t, "server", "", 5)
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(serverCert, future, future))
+
+	serial, err = get(serverURL, clientConfig)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), serial)
+}
+
+func TestNewServerTLSConfig_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    rpc.TLSOptions
+		wantErr string
+	}{
+		{
+			name:    "client CA without server certificate",
+			opts:    rpc.TLSOptions{CACert: "ca.crt"},
+			wantErr: "the server certificate is required",
+		},
+		{
+			name: "SPIFFE IDs without client CA",
+			opts: rpc.TLSOptions{
+				Cert:      "server.crt",
+				Key:       "server.key",
+				SPIFFEIDs: []string{"spiffe://example.org/ci"},
+			},
+			wantErr: "the CA certificate is required",
+		},
+		{
+			name: "missing certificate",
+			opts: rpc.TLSOptions{
+				Cert: "missing.crt",
+				Key:  "missing.key",
+			},
+			wantErr: "unable to stat",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := rpc.NewServerTLSConfig(tt.opts)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+// newTLSServer returns the URL of the server. httptest.Server.StartTLS is not used as it overrides the certificate.
+func newTLSServer(t *testing.T, config *tls.Config) string {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Listener = tls.NewListener(ts.Listener, config)
+	ts.Start()
+	t.Cleanup(ts.Close)
+	return "https://" + ts.Listener.Addr().String()
+}
+
+// get returns the serial number of the server certificate
+func get(url string, config *tls.Config) (int64, error) {
+	c := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   config,
+			DisableKeepAlives: true,
+		},
+	}
+	resp, err := c.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.TLS.PeerCertificates[0].SerialNumber.Int64(), nil
+}
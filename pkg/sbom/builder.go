@@ -0,0 +1,193 @@
+package sbom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/spdx/tools-golang/tagvalue"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/purl"
+	"github.com/aquasecurity/trivy/pkg/sbom/cyclonedx"
+	"github.com/aquasecurity/trivy/pkg/sbom/spdx"
+	"github.com/aquasecurity/trivy/pkg/scanner/utils"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// schemaVersion is the schema version of the report built by Builder, which must be the same as report.SchemaVersion
+const schemaVersion = 2
+
+// Component represents a package to be added to the SBOM.
+type Component struct {
+	// Type is the OS family for OS packages, e.g. "alpine", or the language-specific package type, e.g. "npm".
+	// Packages are treated as OS packages when Type is the same as the OS family of the root.
+	Type ftypes.TargetType
+
+	// FilePath is the file declaring the package, e.g. "app/package-lock.json". It is not used for OS packages.
+	FilePath string
+
+	ftypes.Package
+}
+
+// Builder constructs SBOMs with the same conventions as SBOMs generated by Trivy,
+// such as the component hierarchy, package URLs and properties, so that other tools can produce SBOMs Trivy understands.
+// Builder is not safe for concurrent use.
+type Builder struct {
+	appVersion   string
+	artifactName string
+	artifactType ftypes.ArtifactType
+	metadata     types.Metadata
+	components   []Component
+}
+
+// NewBuilder returns a builder. The version is recorded as the version of the tool generating the SBOM.
+func NewBuilder(appVersion string) *Builder {
+	return &Builder{appVersion: appVersion}
+}
+
+// SetRoot sets the artifact the SBOM describes. metadata.OS must be set for OS packages.
+func (b *Builder) SetRoot(name string, artifactType ftypes.ArtifactType, metadata types.Metadata) *Builder {
+	b.artifactName = name
+	b.artifactType = artifactType
+	b.metadata = metadata
+	return b
+}
+
+// AddComponent adds the package to the SBOM and returns the package ID used for AddDependency.
+// The package ID and the package URL are filled in the same way as Trivy if they are empty.
+func (b *Builder) AddComponent(c Component) (string, error) {
+	if c.Name == "" {
+		return "", xerrors.New("component name is required")
+	} else if c.Type == "" {
+		return "", xerrors.Errorf("component type is required: %s", c.Name)
+	}
+
+	if c.ID == "" {
+		c.ID = fmt.Sprintf("%s@%s", c.Name, utils.FormatVersion(c.Package))
+	}
+	if b.index(c.ID) >= 0 {
+		return "", xerrors.Errorf("duplicate component: %s", c.ID)
+	}
+
+	// DependsOn must be set through AddDependency so that it refers to the existing components
+	c.DependsOn = slices.Clone(c.DependsOn)
+	b.components = append(b.components, c)
+	return c.ID, nil
+}
+
+// AddDependency records that the parent component depends on the child component.
+func (b *Builder) AddDependency(parentID, childID string) error {
+	parent := b.index(parentID)
+	if parent < 0 {
+		return xerrors.Errorf("component not found: %s", parentID)
+	} else if b.index(childID) < 0 {
+		return xerrors.Errorf("component not found: %s", childID)
+	}
+
+	if !slices.Contains(b.components[parent].DependsOn, childID) {
+		b.components[parent].DependsOn = append(b.components[parent].DependsOn, childID)
+	}
+	return nil
+}
+
+func (b *Builder) index(id string) int {
+	return slices.IndexFunc(b.components, func(c Component) bool {
+		return c.ID == id
+	})
+}
+
+// Report returns the components as a Trivy report, which the CycloneDX and SPDX marshalers take.
+func (b *Builder) Report() (types.Report, error) {
+	if b.artifactName == "" {
+		return types.Report{}, xerrors.New("root is not set")
+	}
+
+	report := types.Report{
+		SchemaVersion: schemaVersion,
+		ArtifactName:  b.artifactName,
+		ArtifactType:  b.artifactType,
+		Metadata:      b.metadata,
+	}
+
+	for _, c := range b.components {
+		result := types.Result{
+			Target: c.FilePath,
+			Class:  types.ClassLangPkg,
+			Type:   c.Type,
+		}
+		purlMetadata := types.Metadata{}
+		if os := b.metadata.OS; os != nil && os.Family == c.Type {
+			result.Target = fmt.Sprintf("%s (%s %s)", b.artifactName, os.Family, os.Name)
+			result.Class = types.ClassOSPkg
+			purlMetadata.OS = os
+		}
+
+		pkg := c.Package
+		if pkg.Identifier.PURL == nil {
+			p, err := purl.New(c.Type, purlMetadata, pkg)
+			if err != nil {
+				return types.Report{}, xerrors.Errorf("failed to create the package URL of %s: %w", pkg.ID, err)
+			}
+			pkg.Identifier.PURL = p.Unwrap()
+		}
+
+		idx := slices.IndexFunc(report.Results, func(r types.Result) bool {
+			return r.Target == result.Target && r.Class == result.Class && r.Type == result.Type
+		})
+		if idx < 0 {
+			report.Results = append(report.Results, result)
+			idx = len(report.Results) - 1
+		}
+		report.Results[idx].Packages = append(report.Results[idx].Packages, pkg)
+	}
+	return report, nil
+}
+
+// Marshal encodes the SBOM in the given format.
+// Supported formats are "cyclonedx-json", "cyclonedx-xml", "spdx-json" and "spdx-tv".
+func (b *Builder) Marshal(ctx context.Context, w io.Writer, format Format) error {
+	report, err := b.Report()
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatCycloneDXJSON, FormatCycloneDXXML:
+		bom, err := cyclonedx.NewMarshaler(b.appVersion).Marshal(ctx, report)
+		if err != nil {
+			return xerrors.Errorf("CycloneDX marshal error: %w", err)
+		}
+		encoder := cdx.NewBOMEncoder(w, cdx.BOMFileFormatJSON)
+		if format == FormatCycloneDXXML {
+			encoder = cdx.NewBOMEncoder(w, cdx.BOMFileFormatXML)
+		}
+		encoder.SetPretty(true)
+		if err = encoder.Encode(bom); err != nil {
+			return xerrors.Errorf("failed to encode bom: %w", err)
+		}
+	case FormatSPDXJSON, FormatSPDXTV:
+		doc, err := spdx.NewMarshaler(b.appVersion).Marshal(ctx, report)
+		if err != nil {
+			return xerrors.Errorf("SPDX marshal error: %w", err)
+		}
+		if format == FormatSPDXTV {
+			if err = tagvalue.Write(doc, w); err != nil {
+				return xerrors.Errorf("failed to encode spdx tag-value: %w", err)
+			}
+			return nil
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(doc); err != nil {
+			return xerrors.Errorf("failed to encode spdx json: %w", err)
+		}
+	default:
+		return xerrors.Errorf("%s: %w", format, ErrUnknownFormat)
+	}
+	return nil
+}
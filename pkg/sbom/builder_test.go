@@ -0,0 +1,126 @@
+package sbom_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/sbom"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+func newBuilder(t *testing.T) *sbom.Builder {
+	b := sbom.NewBuilder("dev").SetRoot("my-app:1.0", ftypes.ArtifactContainerImage, types.Metadata{
+		OS: &ftypes.OS{
+			Family: ftypes.Alpine,
+			Name:   "3.19.1",
+		},
+	})
+
+	_, err := b.AddComponent(sbom.Component{
+		Type: ftypes.Alpine,
+		Package: ftypes.Package{
+			Name:       "musl",
+			Version:    "1.2.4_git20230717",
+			Release:    "r4",
+			SrcName:    "musl",
+			SrcVersion: "1.2.4_git20230717",
+			SrcRelease: "r4",
+			Licenses:   []string{"MIT"},
+		},
+	})
+	require.NoError(t, err)
+
+	express, err := b.AddComponent(sbom.Component{
+		Type:     ftypes.Npm,
+		FilePath: "app/package-lock.json",
+		Package: ftypes.Package{
+			Name:    "express",
+			Version: "4.18.2",
+		},
+	})
+	require.NoError(t, err)
+	body, err := b.AddComponent(sbom.Component{
+		Type:     ftypes.Npm,
+		FilePath: "app/package-lock.json",
+		Package: ftypes.Package{
+			Name:     "body-parser",
+			Version:  "1.20.1",
+			Indirect: true,
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, b.AddDependency(express, body))
+	return b
+}
+
+func TestBuilder_Report(t *testing.T) {
+	report, err := newBuilder(t).Report()
+	require.NoError(t, err)
+
+	require.Len(t, report.Results, 2)
+	assert.Equal(t, "my-app:1.0 (alpine 3.19.1)", report.Results[0].Target)
+	assert.Equal(t, types.ClassOSPkg, report.Results[0].Class)
+	assert.Equal(t, "pkg:apk/alpine/musl@1.2.4_git20230717-r4?distro=3.19.1",
+		report.Results[0].Packages[0].Identifier.PURL.String())
+
+	assert.Equal(t, "app/package-lock.json", report.Results[1].Target)
+	assert.Equal(t, types.ClassLangPkg, report.Results[1].Class)
+	assert.Equal(t, []string{"body-parser@1.20.1"}, report.Results[1].Packages[0].DependsOn)
+	assert.Equal(t, "pkg:npm/body-parser@1.20.1", report.Results[1].Packages[1].Identifier.PURL.String())
+}
+
+func TestBuilder_Marshal(t *testing.T) {
+	tests := []struct {
+		format sbom.Format
+	}{
+		{format: sbom.FormatCycloneDXJSON},
+		{format: sbom.FormatSPDXJSON},
+		{format: sbom.FormatSPDXTV},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, newBuilder(t).Marshal(context.Background(), &buf, tt.format))
+
+			// The SBOM must be readable by Trivy
+			got, err := sbom.Decode(&buf, tt.format)
+			require.NoError(t, err)
+
+			assert.Equal(t, ftypes.Alpine, got.OS.Family)
+			pkgs := lo.FlatMap(got.Packages, func(p ftypes.PackageInfo, _ int) []ftypes.Package {
+				return p.Packages
+			})
+			assert.Equal(t, []string{"musl"}, lo.Map(pkgs, func(p ftypes.Package, _ int) string {
+				return p.Name
+			}))
+			require.Len(t, got.Applications, 1)
+			assert.Equal(t, ftypes.Npm, got.Applications[0].Type)
+			assert.ElementsMatch(t, []string{"express", "body-parser"},
+				lo.Map(got.Applications[0].Libraries, func(p ftypes.Package, _ int) string {
+					return p.Name
+				}))
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		err := newBuilder(t).Marshal(context.Background(), &bytes.Buffer{}, sbom.FormatUnknown)
+		require.ErrorIs(t, err, sbom.ErrUnknownFormat)
+	})
+}
+
+func TestBuilder_AddDependency(t *testing.T) {
+	b := newBuilder(t)
+	require.ErrorContains(t, b.AddDependency("express@4.18.2", "lodash@4.17.21"), "component not found")
+
+	_, err := b.AddComponent(sbom.Component{
+		Type:    ftypes.Npm,
+		Package: ftypes.Package{Name: "express", Version: "4.18.2"},
+	})
+	require.ErrorContains(t, err, "duplicate component")
+}
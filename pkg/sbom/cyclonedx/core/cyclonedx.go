@@ -14,6 +14,7 @@ import (
 	dtypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
 	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/cvss"
 	"github.com/aquasecurity/trivy/pkg/digest"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/purl"
@@ -398,7 +399,7 @@ func cdxRatings(vuln types.DetectedVulnerability) *[]cdx.VulnerabilityRating {
 			if cvss.V3Score != 0 || cvss.V3Vector != "" {
 				rates = append(rates, cdxRatingV3(sourceID, severity, cvss))
 			}
-		} else { // When the vendor provides only severity
+		} else if _, ok = vuln.CVSSv40[sourceID]; !ok { // When the vendor provides only severity
 			rate := cdx.VulnerabilityRating{
 				Source: &cdx.Source{
 					Name: string(sourceID),
@@ -408,6 +409,9 @@ func cdxRatings(vuln types.DetectedVulnerability) *[]cdx.VulnerabilityRating {
 			rates = append(rates, rate)
 		}
 	}
+	for sourceID, v40 := range vuln.CVSSv40 {
+		rates = append(rates, cdxRatingV40(sourceID, vuln.VendorSeverity, v40))
+	}
 
 	// For consistency
 	sort.Slice(rates, func(i, j int) bool {
@@ -460,6 +464,23 @@ func cdxRatingV3(sourceID dtypes.SourceID, severity dtypes.Severity, cvss dtypes
 	return rate
 }
 
+func cdxRatingV40(sourceID dtypes.SourceID, vendorSeverity dtypes.VendorSeverity, v40 types.CVSSv40) cdx.VulnerabilityRating {
+	// The vendor might provide CVSS v4.0 without severity
+	severity, ok := vendorSeverity[sourceID]
+	if !ok {
+		severity = cvss.V40Severity(v40.V40Score)
+	}
+	return cdx.VulnerabilityRating{
+		Source: &cdx.Source{
+			Name: string(sourceID),
+		},
+		Score:    &v40.V40Score,
+		Method:   cdx.ScoringMethodCVSSv4,
+		Severity: toCDXSeverity(severity),
+		Vector:   v40.V40Vector,
+	}
+}
+
 func nvdSeverityV2(score float64) cdx.Severity {
 	// cf. https://nvd.nist.gov/vuln-metrics/cvss
 	switch {
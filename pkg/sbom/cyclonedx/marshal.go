@@ -42,6 +42,8 @@ const (
 	PropertyFilePath        = "FilePath"
 	PropertyLayerDigest     = "LayerDigest"
 	PropertyLayerDiffID     = "LayerDiffID"
+	PropertyPkgAlias        = "PkgAlias"
+	PropertyProvenance      = "Provenance"
 )
 
 var (
@@ -248,7 +250,7 @@ func (e *Marshaler) rootComponent(r types.Report) (*core.Component, error) {
 
 	case ftypes.ArtifactVM:
 		root.Type = cdx.ComponentTypeContainer
-	case ftypes.ArtifactFilesystem, ftypes.ArtifactRepository:
+	case ftypes.ArtifactFilesystem, ftypes.ArtifactRepository, ftypes.ArtifactLambdaFunction, ftypes.ArtifactOCIArtifact:
 		root.Type = cdx.ComponentTypeApplication
 	case ftypes.ArtifactCycloneDX:
 		return toCoreComponent(r.CycloneDX.Metadata.Component)
@@ -379,6 +381,24 @@ func pkgComponent(pkg Package) (*core.Component, error) {
 		},
 	}
 
+	// Which analyzer produced the component, e.g. "npm/v1:package-lock.json"
+	if pkg.Provenance != nil {
+		properties = append(properties, core.Property{
+			Name:  PropertyProvenance,
+			Value: pkg.Provenance.String(),
+		})
+	}
+
+	// The same component known to other package managers, e.g. PyYAML installed by python3-yaml
+	for _, alias := range pkg.Aliases {
+		if alias.PURL != nil {
+			properties = append(properties, core.Property{
+				Name:  PropertyPkgAlias,
+				Value: alias.PURL.String(),
+			})
+		}
+	}
+
 	return &core.Component{
 		Type:            cdx.ComponentTypeLibrary,
 		Name:            name,
@@ -2,6 +2,7 @@ package cyclonedx_test
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/package-url/packageurl-go"
 	"testing"
 	"time"
@@ -150,6 +151,11 @@ func TestMarshaler_Marshal(t *testing.T) {
 									},
 								},
 								Indirect: false,
+								Provenance: &ftypes.Provenance{
+									Analyzer: "bundler",
+									Version:  1,
+									FilePath: "app/subproject/Gemfile.lock",
+								},
 							},
 							{
 								ID:      "actioncontroller@7.0.0",
@@ -411,6 +417,10 @@ func TestMarshaler_Marshal(t *testing.T) {
 								Name:  "aquasecurity:trivy:PkgType",
 								Value: "bundler",
 							},
+							{
+								Name:  "aquasecurity:trivy:Provenance",
+								Value: "bundler/v1:app/subproject/Gemfile.lock",
+							},
 						},
 					},
 					{
@@ -813,6 +823,12 @@ func TestMarshaler_Marshal(t *testing.T) {
 									PublishedDate:    lo.ToPtr(time.Date(2022, 2, 11, 21, 15, 0, 0, time.UTC)),
 									LastModifiedDate: lo.ToPtr(time.Date(2022, 2, 22, 21, 47, 0, 0, time.UTC)),
 								},
+								CVSSv40: types.VendorCVSSv40{
+									vulnerability.GHSA: types.CVSSv40{
+										V40Vector: "CVSS:4.0/AV:N/AC:H/AT:N/PR:N/UI:N/VC:H/VI:N/VA:N/SC:N/SI:N/SA:N",
+										V40Score:  8.2,
+									},
+								},
 							},
 							{
 								VulnerabilityID: "CVE-2022-23633",
@@ -863,6 +879,12 @@ func TestMarshaler_Marshal(t *testing.T) {
 									PublishedDate:    lo.ToPtr(time.Date(2022, 2, 11, 21, 15, 0, 0, time.UTC)),
 									LastModifiedDate: lo.ToPtr(time.Date(2022, 2, 22, 21, 47, 0, 0, time.UTC)),
 								},
+								CVSSv40: types.VendorCVSSv40{
+									vulnerability.GHSA: types.CVSSv40{
+										V40Vector: "CVSS:4.0/AV:N/AC:H/AT:N/PR:N/UI:N/VC:H/VI:N/VA:N/SC:N/SI:N/SA:N",
+										V40Score:  8.2,
+									},
+								},
 							},
 						},
 					},
@@ -1113,6 +1135,15 @@ func TestMarshaler_Marshal(t *testing.T) {
 						},
 						Recommendation: "Upgrade actionpack to version ~> 5.2.6, >= 5.2.6.2, ~> 6.0.4, >= 6.0.4.6, ~> 6.1.4, >= 6.1.4.6, >= 7.0.2.2",
 						Ratings: &[]cdx.VulnerabilityRating{
+							{
+								Source: &cdx.Source{
+									Name: string(vulnerability.GHSA),
+								},
+								Score:    lo.ToPtr(8.2),
+								Severity: cdx.SeverityHigh,
+								Method:   cdx.ScoringMethodCVSSv4,
+								Vector:   "CVSS:4.0/AV:N/AC:H/AT:N/PR:N/UI:N/VC:H/VI:N/VA:N/SC:N/SI:N/SA:N",
+							},
 							{
 								Source: &cdx.Source{
 									Name: string(vulnerability.NVD),
@@ -2077,3 +2108,70 @@ func TestMarshaler_Marshal(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshaler_MarshalAliases(t *testing.T) {
+	alias := &packageurl.PackageURL{
+		Type:    packageurl.TypePyPi,
+		Name:    "pyyaml",
+		Version: "5.3.1",
+	}
+	input := types.Report{
+		SchemaVersion: report.SchemaVersion,
+		ArtifactName:  "debian:11",
+		ArtifactType:  ftypes.ArtifactContainerImage,
+		Metadata: types.Metadata{
+			OS: &ftypes.OS{
+				Family: ftypes.Debian,
+				Name:   "11.8",
+			},
+		},
+		Results: types.Results{
+			{
+				Target: "debian:11 (debian 11.8)",
+				Class:  types.ClassOSPkg,
+				Type:   ftypes.Debian,
+				Packages: []ftypes.Package{
+					{
+						ID:      "python3-yaml@5.3.1-5",
+						Name:    "python3-yaml",
+						Version: "5.3.1-5",
+						Identifier: ftypes.PkgIdentifier{
+							PURL: &packageurl.PackageURL{
+								Type:      packageurl.TypeDebian,
+								Namespace: "debian",
+								Name:      "python3-yaml",
+								Version:   "5.3.1-5",
+							},
+						},
+						Aliases: []ftypes.PkgIdentifier{
+							{PURL: alias},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	marshaler := cyclonedx.NewMarshaler("dev")
+	bom, err := marshaler.Marshal(context.Background(), input)
+	require.NoError(t, err)
+
+	component, found := lo.Find(lo.FromPtr(bom.Components), func(c cdx.Component) bool {
+		return c.Name == "python3-yaml"
+	})
+	require.True(t, found)
+	assert.Contains(t, lo.FromPtr(component.Properties), cdx.Property{
+		Name:  "aquasecurity:trivy:PkgAlias",
+		Value: "pkg:pypi/pyyaml@5.3.1",
+	})
+
+	// The aliases should be restored from the SBOM
+	b, err := json.Marshal(bom)
+	require.NoError(t, err)
+
+	var got cyclonedx.BOM
+	require.NoError(t, json.Unmarshal(b, &got))
+	require.Len(t, got.SBOM.Packages, 1)
+	require.Len(t, got.SBOM.Packages[0].Packages, 1)
+	assert.Equal(t, []ftypes.PkgIdentifier{{PURL: alias}}, got.SBOM.Packages[0].Packages[0].Aliases)
+}
@@ -376,9 +376,19 @@ func toPackage(component cdx.Component) (*purl.PackageURL, *ftypes.Package, erro
 			pkg.Layer.Digest = value
 		case PropertyFilePath:
 			pkg.FilePath = value
+		case PropertyProvenance:
+			provenance, err := ftypes.ParseProvenance(value)
+			if err != nil {
+				return nil, nil, xerrors.Errorf("failed to parse provenance: %w", err)
+			}
+			pkg.Provenance = &provenance
 		}
 	}
 
+	if pkg.Aliases, err = parseAliases(component.Properties); err != nil {
+		return nil, nil, xerrors.Errorf("failed to parse aliases: %w", err)
+	}
+
 	if pkg.FilePath != "" {
 		p.FilePath = pkg.FilePath
 	}
@@ -391,6 +401,22 @@ func toPackage(component cdx.Component) (*purl.PackageURL, *ftypes.Package, erro
 	return p, pkg, nil
 }
 
+// parseAliases parses all the "PkgAlias" properties as a property can appear multiple times
+func parseAliases(properties *[]cdx.Property) ([]ftypes.PkgIdentifier, error) {
+	var aliases []ftypes.PkgIdentifier
+	for _, prop := range lo.FromPtr(properties) {
+		if prop.Name != core.Namespace+PropertyPkgAlias {
+			continue
+		}
+		p, err := purl.FromString(prop.Value)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse purl: %w", err)
+		}
+		aliases = append(aliases, ftypes.PkgIdentifier{PURL: p.Unwrap()})
+	}
+	return aliases, nil
+}
+
 func fillSrcPkg(pkg *ftypes.Package) {
 	// Fill source package information for components in third-party SBOMs .
 	if pkg.SrcName == "" {
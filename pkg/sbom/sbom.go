@@ -216,7 +216,7 @@ func Decode(f io.Reader, format Format) (types.SBOM, error) {
 		decoder = spdx.NewTVDecoder(f)
 
 	default:
-		return types.SBOM{}, xerrors.Errorf("%s scanning is not yet supported", format)
+		return types.SBOM{}, xerrors.Errorf("%s scanning is not yet supported: %w", format, ErrUnknownFormat)
 
 	}
 
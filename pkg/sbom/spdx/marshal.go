@@ -337,6 +337,13 @@ func (m *Marshaler) pkgToSpdxPackage(t ftypes.TargetType, pkgDownloadLocation st
 	var pkgExtRefs []*spdx.PackageExternalReference
 	if pkg.Identifier.PURL != nil {
 		pkgExtRefs = []*spdx.PackageExternalReference{purlExternalReference(pkg.Identifier.PURL.String())}
+
+		// The same component known to other package managers, e.g. PyYAML installed by python3-yaml
+		for _, alias := range pkg.Aliases {
+			if alias.PURL != nil {
+				pkgExtRefs = append(pkgExtRefs, purlExternalReference(alias.PURL.String()))
+			}
+		}
 	}
 
 	var attrTexts []string
@@ -272,6 +272,10 @@ func parsePkg(spdxPkg spdx.Package, packageFilePaths map[string]string) (*ftypes
 		pkg.FilePath = spdxPkg.Files[0].FileName
 	}
 
+	if pkg.Aliases, err = parseAliases(spdxPkg.PackageExternalReferences); err != nil {
+		return nil, nil, xerrors.Errorf("alias error: %w", err)
+	}
+
 	pkg.ID = lookupAttributionTexts(spdxPkg.PackageAttributionTexts, PropertyPkgID)
 	pkg.Layer.Digest = lookupAttributionTexts(spdxPkg.PackageAttributionTexts, PropertyLayerDigest)
 	pkg.Layer.DiffID = lookupAttributionTexts(spdxPkg.PackageAttributionTexts, PropertyLayerDiffID)
@@ -295,6 +299,22 @@ func parseExternalReferences(refs []*spdx.PackageExternalReference) (*purl.Packa
 	return nil, errUnknownPackageFormat
 }
 
+// parseAliases returns PURLs following the first one, which identify the same component.
+func parseAliases(refs []*spdx.PackageExternalReference) ([]ftypes.PkgIdentifier, error) {
+	var aliases []ftypes.PkgIdentifier
+	purlRefs := lo.Filter(refs, func(ref *spdx.PackageExternalReference, _ int) bool {
+		return ref.RefType == RefTypePurl && ref.Category == CategoryPackageManager
+	})
+	for _, ref := range lo.Drop(purlRefs, 1) {
+		p, err := purl.FromString(ref.Locator)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse purl from string: %w", err)
+		}
+		aliases = append(aliases, ftypes.PkgIdentifier{PURL: p.Unwrap()})
+	}
+	return aliases, nil
+}
+
 func lookupAttributionTexts(attributionTexts []string, key string) string {
 	for _, text := range attributionTexts {
 		if strings.HasPrefix(text, key) {
@@ -1,10 +1,12 @@
 package langpkg
 
 import (
+	"context"
 	"sort"
 
 	"golang.org/x/xerrors"
 
+	"github.com/aquasecurity/trivy/pkg/advisory"
 	"github.com/aquasecurity/trivy/pkg/detector/library"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/log"
@@ -24,13 +26,17 @@ var (
 
 type Scanner interface {
 	Packages(target types.ScanTarget, options types.ScanOptions) types.Results
-	Scan(target types.ScanTarget, options types.ScanOptions) (types.Results, error)
+	Scan(ctx context.Context, target types.ScanTarget, options types.ScanOptions) (types.Results, error)
 }
 
-type scanner struct{}
+type scanner struct {
+	online *advisory.OnlineSource
+}
 
-func NewScanner() Scanner {
-	return &scanner{}
+// NewScanner returns the scanner of language-specific packages.
+// The online source is nil unless the online lookup is enabled.
+func NewScanner(online *advisory.OnlineSource) Scanner {
+	return &scanner{online: online}
 }
 
 func (s *scanner) Packages(target types.ScanTarget, _ types.ScanOptions) types.Results {
@@ -50,7 +56,7 @@ func (s *scanner) Packages(target types.ScanTarget, _ types.ScanOptions) types.R
 	return results
 }
 
-func (s *scanner) Scan(target types.ScanTarget, _ types.ScanOptions) (types.Results, error) {
+func (s *scanner) Scan(ctx context.Context, target types.ScanTarget, _ types.ScanOptions) (types.Results, error) {
 	apps := target.Applications
 	log.Logger.Infof("Number of language-specific files: %d", len(apps))
 	if len(apps) == 0 {
@@ -71,7 +77,7 @@ func (s *scanner) Scan(target types.ScanTarget, _ types.ScanOptions) (types.Resu
 		}
 
 		log.Logger.Debugf("Detecting library vulnerabilities, type: %s, path: %s", app.Type, app.FilePath)
-		vulns, err := library.Detect(app.Type, app.Libraries)
+		vulns, err := library.Detect(ctx, app.Type, app.Libraries, s.online)
 		if err != nil {
 			return nil, xerrors.Errorf("failed vulnerability detection of libraries: %w", err)
 		} else if len(vulns) == 0 {
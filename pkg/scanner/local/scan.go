@@ -13,7 +13,9 @@ import (
 	"golang.org/x/xerrors"
 
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
+	"github.com/aquasecurity/trivy/pkg/clock"
 	"github.com/aquasecurity/trivy/pkg/fanal/analyzer"
+	"github.com/aquasecurity/trivy/pkg/fanal/analyzer/certificate"
 	"github.com/aquasecurity/trivy/pkg/fanal/applier"
 	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
 	"github.com/aquasecurity/trivy/pkg/licensing"
@@ -85,15 +87,19 @@ func (s Scanner) Scan(ctx context.Context, targetName, artifactKey string, blobK
 		return nil, ftypes.OS{}, xerrors.Errorf("failed to apply layers: %w", err)
 	}
 
+	// Certificates may expire after the analysis results are cached
+	misconfs := certificate.CheckExpiry(mergeMisconfigurations(targetName, detail), detail.Certificates, clock.Now(ctx))
+
 	target := types.ScanTarget{
 		Name:              targetName,
 		OS:                detail.OS,
 		Repository:        detail.Repository,
 		Packages:          mergePkgs(detail.Packages, detail.ImageConfig.Packages, options),
 		Applications:      detail.Applications,
-		Misconfigurations: mergeMisconfigurations(targetName, detail),
+		Misconfigurations: misconfs,
 		Secrets:           mergeSecrets(targetName, detail),
 		Licenses:          detail.Licenses,
+		Certificates:      detail.Certificates,
 		CustomResources:   detail.CustomResources,
 	}
 
@@ -126,6 +132,11 @@ func (s Scanner) ScanTarget(ctx context.Context, target types.ScanTarget, option
 		}
 		target.OS.Eosl = eosl
 
+		// The scan may be canceled, e.g. preempted in server mode, during the detection
+		if err = ctx.Err(); err != nil {
+			return nil, ftypes.OS{}, xerrors.Errorf("scan canceled: %w", err)
+		}
+
 		// Merge package results into vulnerability results
 		mergedResults := s.fillPkgsInVulns(pkgResults, vulnResults)
 
@@ -136,7 +147,7 @@ func (s Scanner) ScanTarget(ctx context.Context, target types.ScanTarget, option
 	}
 
 	// Store misconfigurations
-	results = append(results, s.misconfsToResults(target.Misconfigurations, options)...)
+	results = append(results, s.misconfsToResults(target.Misconfigurations, target.Certificates, options)...)
 
 	// Store secrets
 	results = append(results, s.secretsToResults(target.Secrets, options)...)
@@ -182,7 +193,7 @@ func (s Scanner) scanVulnerabilities(ctx context.Context, target types.ScanTarge
 	}
 
 	if slices.Contains(options.VulnType, types.VulnTypeLibrary) {
-		vulns, err := s.langPkgScanner.Scan(target, options)
+		vulns, err := s.langPkgScanner.Scan(ctx, target, options)
 		if err != nil {
 			return nil, false, xerrors.Errorf("failed to scan application libraries: %w", err)
 		}
@@ -210,13 +221,25 @@ func (s Scanner) fillPkgsInVulns(pkgResults, vulnResults types.Results) types.Re
 	return results
 }
 
-func (s Scanner) misconfsToResults(misconfs []ftypes.Misconfiguration, options types.ScanOptions) types.Results {
+func (s Scanner) misconfsToResults(misconfs []ftypes.Misconfiguration, certs []ftypes.CertificateInventory,
+	options types.ScanOptions) types.Results {
 	if !ShouldScanMisconfigOrRbac(options.Scanners) &&
 		!options.ImageConfigScanners.Enabled(types.MisconfigScanner) {
 		return nil
 	}
 
-	return s.MisconfsToResults(misconfs)
+	results := s.MisconfsToResults(misconfs)
+
+	// Fill the certificate inventory
+	for i := range results {
+		if inventory, ok := lo.Find(certs, func(c ftypes.CertificateInventory) bool {
+			return c.FilePath == results[i].Target && c.FileType == results[i].Type
+		}); ok {
+			results[i].Certificates = inventory.Certificates
+			results[i].PrivateKeys = inventory.PrivateKeys
+		}
+	}
+	return results
 }
 
 // MisconfsToResults is exported for trivy-plugin-aqua purposes only
@@ -2,13 +2,19 @@ package scanner
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/google/wire"
 	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy/pkg/clock"
+	"github.com/aquasecurity/trivy/pkg/debugreport"
 	"github.com/aquasecurity/trivy/pkg/fanal/artifact"
 	aimage "github.com/aquasecurity/trivy/pkg/fanal/artifact/image"
+	"github.com/aquasecurity/trivy/pkg/fanal/artifact/lambda"
 	flocal "github.com/aquasecurity/trivy/pkg/fanal/artifact/local"
 	"github.com/aquasecurity/trivy/pkg/fanal/artifact/repo"
 	"github.com/aquasecurity/trivy/pkg/fanal/artifact/sbom"
@@ -71,6 +77,12 @@ var StandaloneVMSet = wire.NewSet(
 	StandaloneSuperSet,
 )
 
+// StandaloneLambdaSet binds Lambda function dependencies
+var StandaloneLambdaSet = wire.NewSet(
+	lambda.NewArtifact,
+	StandaloneSuperSet,
+)
+
 /////////////////
 // Client/Server
 /////////////////
@@ -107,6 +119,12 @@ var RemoteVMSet = wire.NewSet(
 	RemoteSuperSet,
 )
 
+// RemoteLambdaSet binds Lambda function dependencies for client/server mode
+var RemoteLambdaSet = wire.NewSet(
+	lambda.NewArtifact,
+	RemoteSuperSet,
+)
+
 // RemoteDockerSet binds remote docker dependencies
 var RemoteDockerSet = wire.NewSet(
 	aimage.NewArtifact,
@@ -123,8 +141,9 @@ var RemoteArchiveSet = wire.NewSet(
 
 // Scanner implements the Artifact and Driver operations
 type Scanner struct {
-	driver   Driver
-	artifact artifact.Artifact
+	driver      Driver
+	artifact    artifact.Artifact
+	resultCache ResultCache
 }
 
 // Driver defines operations of scanner
@@ -141,22 +160,52 @@ func NewScanner(driver Driver, ar artifact.Artifact) Scanner {
 	}
 }
 
+// ResultCache stores scan results keyed by the artifact and the scan options
+type ResultCache interface {
+	Get(ctx context.Context, key string) (types.Report, bool)
+	Put(ctx context.Context, key string, report types.Report) error
+}
+
+// WithResultCache returns the scanner reusing the results of unchanged artifacts
+func (s Scanner) WithResultCache(c ResultCache) Scanner {
+	s.resultCache = c
+	return s
+}
+
 // ScanArtifact scans the artifacts and returns results
 func (s Scanner) ScanArtifact(ctx context.Context, options types.ScanOptions) (types.Report, error) {
+	collector := debugreport.FromContext(ctx)
+
+	start := time.Now()
 	artifactInfo, err := s.artifact.Inspect(ctx)
 	if err != nil {
 		return types.Report{}, xerrors.Errorf("failed analysis: %w", err)
 	}
+	collector.AddPhase("analysis", time.Since(start))
 	defer func() {
 		if err := s.artifact.Clean(artifactInfo); err != nil {
 			log.Logger.Warnf("Failed to clean the artifact %q: %v", artifactInfo.Name, err)
 		}
 	}()
 
+	resultKey, err := s.resultKey(artifactInfo, options)
+	if err != nil {
+		return types.Report{}, xerrors.Errorf("result cache key error: %w", err)
+	}
+	if resultKey != "" {
+		if report, ok := s.resultCache.Get(ctx, resultKey); ok {
+			log.Logger.Infof("Reusing the cached scan result of %s", artifactInfo.Name)
+			report.CreatedAt = clock.Now(ctx)
+			return report, nil
+		}
+	}
+
+	start = time.Now()
 	results, osFound, err := s.driver.Scan(ctx, artifactInfo.Name, artifactInfo.ID, artifactInfo.BlobIDs, options)
 	if err != nil {
 		return types.Report{}, xerrors.Errorf("scan failed: %w", err)
 	}
+	collector.AddPhase("detection", time.Since(start))
 
 	ptros := &osFound
 	if osFound.Detected() && osFound.Eosl {
@@ -171,7 +220,7 @@ func (s Scanner) ScanArtifact(ctx context.Context, options types.ScanOptions) (t
 		removeLayer(results)
 	}
 
-	return types.Report{
+	r := types.Report{
 		SchemaVersion: report.SchemaVersion,
 		CreatedAt:     clock.Now(ctx),
 		ArtifactName:  artifactInfo.Name,
@@ -186,9 +235,39 @@ func (s Scanner) ScanArtifact(ctx context.Context, options types.ScanOptions) (t
 			RepoDigests: artifactInfo.ImageMetadata.RepoDigests,
 			ImageConfig: artifactInfo.ImageMetadata.ConfigFile,
 		},
-		CycloneDX: artifactInfo.CycloneDX,
-		Results:   results,
-	}, nil
+		CycloneDX:  artifactInfo.CycloneDX,
+		Results:    results,
+		Incomplete: artifactInfo.Incomplete,
+	}
+
+	// Partial results are not cached
+	if r.Incomplete != nil {
+		log.Logger.Warnf("The results are incomplete as %s. %d targets were not fully analyzed",
+			r.Incomplete.Reason, len(r.Incomplete.SkippedTargets))
+	} else if resultKey != "" {
+		if err = s.resultCache.Put(ctx, resultKey, r); err != nil {
+			log.Logger.Warnf("Unable to cache the scan result: %s", err)
+		}
+	}
+	return r, nil
+}
+
+// resultKey returns the key of the scan result. It is empty if the result cache is not used.
+// The artifact ID already depends on the image ID and the analysis options.
+func (s Scanner) resultKey(artifactInfo ftypes.ArtifactReference, options types.ScanOptions) (string, error) {
+	if s.resultCache == nil || artifactInfo.ID == "" {
+		return "", nil
+	}
+	h := sha256.New()
+	keyBase := struct {
+		ID          string
+		Name        string
+		ScanOptions types.ScanOptions
+	}{artifactInfo.ID, artifactInfo.Name, options}
+	if err := json.NewEncoder(h).Encode(keyBase); err != nil {
+		return "", xerrors.Errorf("json encode error: %w", err)
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
 }
 
 func removeLayer(results types.Results) {
@@ -213,3 +213,90 @@ func TestScanner_ScanArtifact(t *testing.T) {
 		})
 	}
 }
+
+type mapResultCache map[string]types.Report
+
+func (c mapResultCache) Get(_ context.Context, key string) (types.Report, bool) {
+	report, ok := c[key]
+	return report, ok
+}
+
+func (c mapResultCache) Put(_ context.Context, key string, report types.Report) error {
+	c[key] = report
+	return nil
+}
+
+func TestScanner_ScanArtifact_ResultCache(t *testing.T) {
+	ctx := clock.With(context.Background(), time.Date(2021, 8, 25, 12, 20, 30, 5, time.UTC))
+	options := types.ScanOptions{VulnType: []string{"os"}}
+
+	d := new(MockDriver)
+	d.ApplyScanExpectation(DriverScanExpectation{
+		Args: DriverScanArgs{
+			CtxAnything: true,
+			Target:      "alpine:3.11",
+			ImageID:     "sha256:e7d92cdc71feacf90708cb59182d0df1b911f8ae022d29e8e95d75ca6a99776a",
+			LayerIDs:    []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+			Options:     options,
+		},
+		Returns: DriverScanReturns{
+			Results: types.Results{
+				{
+					Target: "alpine:3.11",
+					Vulnerabilities: []types.DetectedVulnerability{
+						{
+							VulnerabilityID:  "CVE-2020-1967",
+							PkgName:          "libssl1.1",
+							InstalledVersion: "1.1.1c-r0",
+							FixedVersion:     "1.1.1g-r0",
+						},
+					},
+				},
+			},
+		},
+	})
+
+	mockArtifact := new(artifact.MockArtifact)
+	mockArtifact.ApplyInspectExpectation(artifact.ArtifactInspectExpectation{
+		Args: artifact.ArtifactInspectArgs{
+			CtxAnything: true,
+		},
+		Returns: artifact.ArtifactInspectReturns{
+			Reference: ftypes.ArtifactReference{
+				Name:    "alpine:3.11",
+				Type:    ftypes.ArtifactContainerImage,
+				ID:      "sha256:e7d92cdc71feacf90708cb59182d0df1b911f8ae022d29e8e95d75ca6a99776a",
+				BlobIDs: []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+			},
+		},
+	})
+
+	resultCache := mapResultCache{}
+	s := NewScanner(d, mockArtifact).WithResultCache(resultCache)
+
+	want, err := s.ScanArtifact(ctx, options)
+	require.NoError(t, err)
+	require.Len(t, resultCache, 1)
+
+	// The second scan reuses the cached result without detection
+	got, err := s.ScanArtifact(ctx, options)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	d.AssertNumberOfCalls(t, "Scan", 1)
+
+	// Different scan options don't share the result
+	options.ListAllPackages = true
+	d.ApplyScanExpectation(DriverScanExpectation{
+		Args: DriverScanArgs{
+			CtxAnything: true,
+			Target:      "alpine:3.11",
+			ImageID:     "sha256:e7d92cdc71feacf90708cb59182d0df1b911f8ae022d29e8e95d75ca6a99776a",
+			LayerIDs:    []string{"sha256:5216338b40a7b96416b8b9858974bbe4acc3096ee60acbc4dfb1ee02aecceb10"},
+			Options:     options,
+		},
+	})
+	_, err = s.ScanArtifact(ctx, options)
+	require.NoError(t, err)
+	assert.Len(t, resultCache, 2)
+	d.AssertNumberOfCalls(t, "Scan", 2)
+}
@@ -0,0 +1,164 @@
+package scanpolicy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/remote"
+	"github.com/aquasecurity/trivy/pkg/signature"
+)
+
+const (
+	// ArtifactType is the artifact type of scan policy bundles attached to images as OCI referrers
+	ArtifactType = "application/vnd.aquasec.trivy.scan-policy.v1"
+
+	// SignatureAnnotation is the manifest annotation with the base64-encoded signature of the bundle layer,
+	// e.g. the output of 'cosign sign-blob --key cosign.key bundle.tar.gz'
+	SignatureAnnotation = "org.aquasecurity.trivy.scan-policy.signature"
+
+	// The files in the bundle, which is a gzipped tarball. All are optional.
+	IgnoreFile           = "trivyignore.yaml"
+	VEXFile              = "vex.json"
+	SeverityOverrideFile = "severity-overrides.yaml"
+
+	maxBundleSize = 10 << 20
+)
+
+var bundleFiles = []string{
+	IgnoreFile,
+	VEXFile,
+	SeverityOverrideFile,
+}
+
+// Discover downloads the scan policy bundles attached to the image as OCI referrers into sub-directories of dir,
+// and returns the directories. Bundles not signed by the verifier's key are skipped with a warning
+// so that nobody but the producer can suppress findings.
+func Discover(ctx context.Context, digest name.Digest, verifier *signature.Verifier, dir string, opt types.RegistryOptions) ([]string, error) {
+	index, err := remote.Referrers(ctx, digest, opt)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to fetch referrers: %w", err)
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, xerrors.Errorf("unable to get manifest: %w", err)
+	}
+
+	var dirs []string
+	for _, m := range lo.FromPtr(manifest).Manifests {
+		if m.ArtifactType != ArtifactType {
+			continue
+		}
+		bundleDir := filepath.Join(dir, m.Digest.Hex)
+		if err = fetchBundle(ctx, digest.Context().Digest(m.Digest.String()), verifier, bundleDir, opt); err != nil {
+			log.Logger.Warnf("Skipping the scan policy bundle (%s): %s", m.Digest, err)
+			continue
+		}
+		log.Logger.Infof("Found the scan policy bundle (%s) in the OCI referrers", m.Digest)
+		dirs = append(dirs, bundleDir)
+	}
+	return dirs, nil
+}
+
+func fetchBundle(ctx context.Context, ref name.Digest, verifier *signature.Verifier, dir string, opt types.RegistryOptions) error {
+	img, err := remote.Image(ctx, ref, opt)
+	if err != nil {
+		return xerrors.Errorf("OCI repository error: %w", err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return xerrors.Errorf("OCI manifest error: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(manifest.Annotations[SignatureAnnotation])
+	if err != nil || len(sig) == 0 {
+		return xerrors.Errorf("no valid %s annotation", SignatureAnnotation)
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return xerrors.Errorf("OCI layer error: %w", err)
+	} else if len(layers) != 1 {
+		return xerrors.New("the bundle must be a single layer")
+	}
+	blob, err := readLayer(layers[0])
+	if err != nil {
+		return xerrors.Errorf("OCI layer error: %w", err)
+	}
+
+	if err = verifier.Verify(blob, sig); err != nil {
+		return xerrors.Errorf("signature verification error: %w", err)
+	}
+	if err = extract(blob, dir); err != nil {
+		return xerrors.Errorf("bundle extraction error: %w", err)
+	}
+	return nil
+}
+
+func readLayer(layer v1.Layer) ([]byte, error) {
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to fetch the layer: %w", err)
+	}
+	defer rc.Close()
+
+	blob, err := io.ReadAll(io.LimitReader(rc, maxBundleSize+1))
+	if err != nil {
+		return nil, xerrors.Errorf("read error: %w", err)
+	} else if len(blob) > maxBundleSize {
+		return nil, xerrors.Errorf("the bundle exceeds %d bytes", maxBundleSize)
+	}
+	return blob, nil
+}
+
+// extract writes the known files in the gzipped tarball into dir. The other files are ignored.
+func extract(blob []byte, dir string) error {
+	gr, err := gzip.NewReader(bytes.NewReader(blob))
+	if err != nil {
+		return xerrors.Errorf("gzip error: %w", err)
+	}
+	defer gr.Close()
+
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return xerrors.Errorf("mkdir error: %w", err)
+	}
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("tar error: %w", err)
+		}
+
+		fileName := path.Clean(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || !slices.Contains(bundleFiles, fileName) {
+			continue
+		}
+		// Compressed files can be much larger than the bundle
+		b, err := io.ReadAll(io.LimitReader(tr, maxBundleSize+1))
+		if err != nil {
+			return xerrors.Errorf("read error (%s): %w", fileName, err)
+		} else if len(b) > maxBundleSize {
+			return xerrors.Errorf("%s exceeds %d bytes", fileName, maxBundleSize)
+		}
+		if err = os.WriteFile(filepath.Join(dir, fileName), b, 0o600); err != nil {
+			return xerrors.Errorf("write error (%s): %w", fileName, err)
+		}
+	}
+}
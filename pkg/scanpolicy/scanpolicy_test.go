@@ -0,0 +1,174 @@
+package scanpolicy_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/scanpolicy"
+	"github.com/aquasecurity/trivy/pkg/signature"
+)
+
+func TestDiscover(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	files := map[string]string{
+		scanpolicy.IgnoreFile: "vulnerabilities:\n  - id: CVE-2024-0001\n",
+		scanpolicy.VEXFile:    `{"@context": "https://openvex.dev/ns/v0.2.0"}`,
+		"../escape.yaml":      "ignored",
+	}
+
+	tests := []struct {
+		name      string
+		signer    *ecdsa.PrivateKey
+		wantFiles map[string]string
+	}{
+		{
+			name:   "signed bundle",
+			signer: key,
+			wantFiles: map[string]string{
+				scanpolicy.IgnoreFile: files[scanpolicy.IgnoreFile],
+				scanpolicy.VEXFile:    files[scanpolicy.VEXFile],
+			},
+		},
+		{
+			name:   "bundle signed by another key",
+			signer: otherKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+			defer ts.Close()
+
+			digest := pushImageWithBundle(t, strings.TrimPrefix(ts.URL, "http://")+"/org/app", files, tt.signer)
+
+			keyPath := filepath.Join(t.TempDir(), "cosign.pub")
+			writePublicKey(t, keyPath, key)
+			verifier, err := signature.NewVerifier(keyPath)
+			require.NoError(t, err)
+
+			dirs, err := scanpolicy.Discover(context.Background(), digest, verifier, t.TempDir(), types.RegistryOptions{Insecure: true})
+			require.NoError(t, err)
+			if tt.wantFiles == nil {
+				assert.Empty(t, dirs)
+				return
+			}
+
+			require.Len(t, dirs, 1)
+			got := make(map[string]string)
+			entries, err := os.ReadDir(dirs[0])
+			require.NoError(t, err)
+			for _, e := range entries {
+				b, err := os.ReadFile(filepath.Join(dirs[0], e.Name()))
+				require.NoError(t, err)
+				got[e.Name()] = string(b)
+			}
+			assert.Equal(t, tt.wantFiles, got)
+		})
+	}
+}
+
+// pushImageWithBundle pushes a random image and the signed bundle referring to it, and returns the image digest
+func pushImageWithBundle(t *testing.T, repo string, files map[string]string, signer *ecdsa.PrivateKey) name.Digest {
+	img, err := random.Image(100, 1)
+	require.NoError(t, err)
+	ref, err := name.ParseReference(repo+":latest", name.Insecure)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+	imgManifest, err := img.RawManifest()
+	require.NoError(t, err)
+	mediaType, err := img.MediaType()
+	require.NoError(t, err)
+
+	blob := tarball(t, files)
+	hash := sha256.Sum256(blob)
+	sig, err := ecdsa.SignASN1(rand.Reader, signer, hash[:])
+	require.NoError(t, err)
+
+	bundle, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer(blob, "application/vnd.aquasec.trivy.scan-policy.layer.v1.tar+gzip"),
+	})
+	require.NoError(t, err)
+	bundle = mutate.MediaType(bundle, ggcrtypes.OCIManifestSchema1)
+	bundle = mutate.ConfigMediaType(bundle, scanpolicy.ArtifactType)
+	bundle = mutate.Annotations(bundle, map[string]string{
+		scanpolicy.SignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+	}).(v1.Image)
+	bundle = mutate.Subject(bundle, v1.Descriptor{
+		MediaType: mediaType,
+		Digest:    imgDigest,
+		Size:      int64(len(imgManifest)),
+	}).(v1.Image)
+
+	bundleDigest, err := bundle.Digest()
+	require.NoError(t, err)
+	bundleRef, err := name.ParseReference(repo+"@"+bundleDigest.String(), name.Insecure)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(bundleRef, bundle))
+
+	digest, err := name.NewDigest(repo+"@"+imgDigest.String(), name.Insecure)
+	require.NoError(t, err)
+	return digest
+}
+
+func tarball(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for fileName, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     fileName,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func writePublicKey(t *testing.T, keyPath string, key *ecdsa.PrivateKey) {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	b := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: der,
+	})
+	require.NoError(t, os.WriteFile(keyPath, b, 0o600))
+}
@@ -0,0 +1,478 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/samber/lo"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/log"
+	"github.com/aquasecurity/trivy/pkg/remote"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+// Annotations and media types of the layers which cosign pushes
+// cf. https://github.com/sigstore/cosign/blob/main/specs/SIGNATURE_SPEC.md
+const (
+	SignatureAnnotation   = "dev.cosignproject.cosign/signature"
+	CertificateAnnotation = "dev.sigstore.cosign/certificate"
+	ChainAnnotation       = "dev.sigstore.cosign/chain"
+	BundleAnnotation      = "dev.sigstore.cosign/bundle"
+
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	DSSEMediaType          = "application/vnd.dsse.envelope.v1+json"
+
+	maxPayloadSize = 10 << 20
+)
+
+// ErrUnsigned is returned when the image has no signature matching the policy and the signature is required
+var ErrUnsigned = xerrors.New("no valid signature")
+
+// Fulcio certificate extensions of the OIDC issuer
+// cf. https://github.com/sigstore/fulcio/blob/main/docs/oid-info.md
+var (
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// Policy specifies whose signatures are trusted, either the public key or the Fulcio certificate identity
+type Policy struct {
+	KeyPath string
+
+	// Keyless signatures
+	CertificateIdentity   string
+	CertificateOIDCIssuer string
+	FulcioRoot            string // PEM file with the root and intermediate certificates of Fulcio
+	RekorPublicKey        string // PEM file with the public key of Rekor to verify the signing time
+}
+
+// CosignVerifier verifies the signatures and attestations which cosign attaches to the image
+// with the tags "sha256-<hex>.sig" and "sha256-<hex>.att".
+// The inclusion in the Rekor transparency log is not verified.
+type CosignVerifier struct {
+	policy   Policy
+	verifier *Verifier      // Signatures with the key
+	roots    *x509.CertPool // Keyless signatures
+	rekor    *Verifier      // Signed entry timestamps of keyless signatures
+}
+
+// Attestation represents the in-toto statement of the attestation verified with the policy
+type Attestation struct {
+	Signer    types.Signature
+	Statement []byte
+}
+
+func NewCosignVerifier(policy Policy) (*CosignVerifier, error) {
+	switch {
+	case policy.KeyPath != "":
+		verifier, err := NewVerifier(policy.KeyPath)
+		if err != nil {
+			return nil, xerrors.Errorf("verifier error: %w", err)
+		}
+		return &CosignVerifier{
+			policy:   policy,
+			verifier: verifier,
+		}, nil
+	case policy.CertificateIdentity != "" && policy.CertificateOIDCIssuer != "" && policy.FulcioRoot != "":
+		b, err := os.ReadFile(policy.FulcioRoot)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to read the Fulcio root: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(b) {
+			return nil, xerrors.Errorf("no certificate in %s", policy.FulcioRoot)
+		}
+		var rekor *Verifier
+		if policy.RekorPublicKey != "" {
+			if rekor, err = NewVerifier(policy.RekorPublicKey); err != nil {
+				return nil, xerrors.Errorf("Rekor public key error: %w", err)
+			}
+		}
+		return &CosignVerifier{
+			policy: policy,
+			roots:  roots,
+			rekor:  rekor,
+		}, nil
+	}
+	return nil, xerrors.New("either the public key or the certificate identity, OIDC issuer and Fulcio root must be specified")
+}
+
+// Verify verifies the signatures and attestations of the image digest.
+// The image is verified if any of them is valid. Registry errors other than missing signatures are returned.
+func (v *CosignVerifier) Verify(ctx context.Context, digest name.Digest, opt ftypes.RegistryOptions) (types.SignatureVerification, error) {
+	result := types.SignatureVerification{
+		Digest: digest.DigestStr(),
+	}
+
+	sigs, numSigs, err := v.verifyTag(ctx, digest, ".sig", v.verifySignature, opt)
+	if err != nil {
+		return types.SignatureVerification{}, xerrors.Errorf("signature error: %w", err)
+	}
+	atts, numAtts, err := v.verifyTag(ctx, digest, ".att", func(desc v1.Descriptor, payload []byte, digest name.Digest) (types.Signature, error) {
+		att, err := v.verifyAttestation(desc, payload, digest)
+		return att.Signer, err
+	}, opt)
+	if err != nil {
+		return types.SignatureVerification{}, xerrors.Errorf("attestation error: %w", err)
+	}
+
+	result.Signatures = sigs
+	result.Attestations = atts
+	result.Verified = len(sigs)+len(atts) > 0
+	switch {
+	case numSigs+numAtts == 0:
+		result.Message = "no signature found"
+	case !result.Verified:
+		result.Message = "no signature matches the policy"
+	}
+	return result, nil
+}
+
+// ReferrerAttestations returns the attestations attached to the image as OCI referrers,
+// e.g. by 'cosign attest --registry-referrers-mode oci-1-1', which are signed as the policy requires.
+// The attestations with invalid signatures are skipped.
+func (v *CosignVerifier) ReferrerAttestations(ctx context.Context, digest name.Digest, opt ftypes.RegistryOptions) ([]Attestation, error) {
+	index, err := remote.Referrers(ctx, digest, opt)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to fetch referrers: %w", err)
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, xerrors.Errorf("unable to get manifest: %w", err)
+	}
+
+	var atts []Attestation
+	for _, m := range lo.FromPtr(manifest).Manifests {
+		if m.ArtifactType != DSSEMediaType {
+			continue
+		}
+		img, err := remote.Image(ctx, digest.Context().Digest(m.Digest.String()), opt)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to fetch the referrer (%s): %w", m.Digest, err)
+		}
+		referrer, err := img.Manifest()
+		if err != nil {
+			return nil, xerrors.Errorf("manifest error (%s): %w", m.Digest, err)
+		}
+		for _, desc := range referrer.Layers {
+			payload, err := readLayer(img, desc)
+			if err != nil {
+				return nil, xerrors.Errorf("layer error (%s): %w", desc.Digest, err)
+			}
+			att, err := v.verifyAttestation(desc, payload, digest)
+			if err != nil {
+				log.Logger.Debugf("Invalid attestation in the referrer (%s): %s", m.Digest, err)
+				continue
+			}
+			atts = append(atts, att)
+		}
+	}
+	return atts, nil
+}
+
+type verifyFunc func(desc v1.Descriptor, payload []byte, digest name.Digest) (types.Signature, error)
+
+// verifyTag verifies the layers of the manifest tagged with the digest and the suffix,
+// and returns the valid signatures and the number of the layers
+func (v *CosignVerifier) verifyTag(ctx context.Context, digest name.Digest, suffix string, verify verifyFunc,
+	opt ftypes.RegistryOptions) ([]types.Signature, int, error) {
+	tag := digest.Context().Tag(strings.Replace(digest.DigestStr(), ":", "-", 1) + suffix)
+	img, err := remote.Image(ctx, tag, opt)
+	if isNotFound(err) {
+		return nil, 0, nil
+	} else if err != nil {
+		return nil, 0, xerrors.Errorf("unable to fetch %s: %w", tag, err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, 0, xerrors.Errorf("manifest error: %w", err)
+	}
+
+	var sigs []types.Signature
+	for _, desc := range manifest.Layers {
+		payload, err := readLayer(img, desc)
+		if err != nil {
+			return nil, 0, xerrors.Errorf("layer error (%s): %w", desc.Digest, err)
+		}
+		sig, err := verify(desc, payload, digest)
+		if err != nil {
+			log.Logger.Debugf("Invalid signature in %s (%s): %s", tag, desc.Digest, err)
+			continue
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, len(manifest.Layers), nil
+}
+
+// verifySignature verifies the simple signing payload signed by 'cosign sign'
+func (v *CosignVerifier) verifySignature(desc v1.Descriptor, payload []byte, digest name.Digest) (types.Signature, error) {
+	if desc.MediaType != SimpleSigningMediaType {
+		return types.Signature{}, xerrors.Errorf("unexpected media type: %s", desc.MediaType)
+	}
+	sig, err := base64.StdEncoding.DecodeString(desc.Annotations[SignatureAnnotation])
+	if err != nil || len(sig) == 0 {
+		return types.Signature{}, xerrors.Errorf("no valid %s annotation", SignatureAnnotation)
+	}
+
+	signer, verifier, err := v.signer(desc)
+	if err != nil {
+		return types.Signature{}, xerrors.Errorf("signer error: %w", err)
+	}
+	if err = verifier.Verify(payload, sig); err != nil {
+		return types.Signature{}, xerrors.Errorf("signature verification error: %w", err)
+	}
+
+	var simpleSigning struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err = json.Unmarshal(payload, &simpleSigning); err != nil {
+		return types.Signature{}, xerrors.Errorf("payload decode error: %w", err)
+	}
+	if d := simpleSigning.Critical.Image.DockerManifestDigest; d != digest.DigestStr() {
+		return types.Signature{}, xerrors.Errorf("the signature is for another image: %s", d)
+	}
+	return signer, nil
+}
+
+// verifyAttestation verifies the DSSE envelope signed by 'cosign attest'
+func (v *CosignVerifier) verifyAttestation(desc v1.Descriptor, payload []byte, digest name.Digest) (Attestation, error) {
+	if desc.MediaType != DSSEMediaType {
+		return Attestation{}, xerrors.Errorf("unexpected media type: %s", desc.MediaType)
+	}
+	var envelope dsse.Envelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return Attestation{}, xerrors.Errorf("envelope decode error: %w", err)
+	}
+	body, err := envelope.DecodeB64Payload()
+	if err != nil {
+		return Attestation{}, xerrors.Errorf("payload decode error: %w", err)
+	}
+
+	signer, verifier, err := v.signer(desc)
+	if err != nil {
+		return Attestation{}, xerrors.Errorf("signer error: %w", err)
+	}
+	pae := dsse.PAE(envelope.PayloadType, body)
+	verified := lo.ContainsBy(envelope.Signatures, func(s dsse.Signature) bool {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		return err == nil && verifier.Verify(pae, sig) == nil
+	})
+	if !verified {
+		return Attestation{}, xerrors.New("no valid signature in the envelope")
+	}
+
+	if envelope.PayloadType != in_toto.PayloadType {
+		return Attestation{}, xerrors.Errorf("invalid payload type: %s", envelope.PayloadType)
+	}
+	var statement in_toto.StatementHeader
+	if err = json.Unmarshal(body, &statement); err != nil {
+		return Attestation{}, xerrors.Errorf("statement decode error: %w", err)
+	}
+	hash, err := v1.NewHash(digest.DigestStr())
+	if err != nil {
+		return Attestation{}, xerrors.Errorf("digest error: %w", err)
+	}
+	if !lo.ContainsBy(statement.Subject, func(s in_toto.Subject) bool {
+		return s.Digest[hash.Algorithm] == hash.Hex
+	}) {
+		return Attestation{}, xerrors.New("the attestation is for another image")
+	}
+
+	signer.PredicateType = statement.PredicateType
+	return Attestation{
+		Signer:    signer,
+		Statement: body,
+	}, nil
+}
+
+// signer returns the verifier of the signature in the layer.
+// The identity of the Fulcio certificate is returned as well for keyless signatures.
+func (v *CosignVerifier) signer(desc v1.Descriptor) (types.Signature, *Verifier, error) {
+	if v.verifier != nil {
+		return types.Signature{}, v.verifier, nil
+	}
+
+	cert, err := v.verifyCertificate(desc.Annotations)
+	if err != nil {
+		return types.Signature{}, nil, xerrors.Errorf("certificate error: %w", err)
+	}
+	identities := append(slices.Clone(cert.EmailAddresses), lo.Map(cert.URIs, func(u *url.URL, _ int) string {
+		return u.String()
+	})...)
+	if !slices.Contains(identities, v.policy.CertificateIdentity) {
+		return types.Signature{}, nil, xerrors.Errorf("the certificate identity doesn't match: %q", identities)
+	}
+	if issuer := certificateIssuer(cert); issuer != v.policy.CertificateOIDCIssuer {
+		return types.Signature{}, nil, xerrors.Errorf("the certificate issuer doesn't match: %q", issuer)
+	}
+
+	verifier, err := newVerifier(cert.PublicKey)
+	if err != nil {
+		return types.Signature{}, nil, xerrors.Errorf("verifier error: %w", err)
+	}
+	return types.Signature{
+		Identity: v.policy.CertificateIdentity,
+		Issuer:   v.policy.CertificateOIDCIssuer,
+	}, verifier, nil
+}
+
+// verifyCertificate verifies the Fulcio certificate in the annotations against the roots.
+// Fulcio certificates expire in minutes, so they are verified at the signing time in the Rekor bundle
+// if the bundle is verified with the Rekor public key, or at the current time otherwise.
+func (v *CosignVerifier) verifyCertificate(annotations map[string]string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(annotations[CertificateAnnotation]))
+	if block == nil {
+		return nil, xerrors.Errorf("no valid %s annotation", CertificateAnnotation)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, xerrors.Errorf("certificate parse error: %w", err)
+	}
+
+	signedAt := time.Now()
+	if bundle := annotations[BundleAnnotation]; bundle != "" && v.rekor != nil {
+		if signedAt, err = v.verifyBundle(bundle, cert); err != nil {
+			return nil, xerrors.Errorf("Rekor bundle error: %w", err)
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM([]byte(annotations[ChainAnnotation]))
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   signedAt,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("certificate verification error: %w", err)
+	}
+	return cert, nil
+}
+
+// rekorPayload is the log entry in the Rekor bundle.
+// The fields are in the order of the canonical JSON which the signed entry timestamp is calculated over.
+type rekorPayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// verifyBundle verifies the signed entry timestamp of the Rekor bundle with the Rekor public key,
+// and returns the time when the entry of the certificate was integrated into the log.
+func (v *CosignVerifier) verifyBundle(bundle string, cert *x509.Certificate) (time.Time, error) {
+	var b struct {
+		SignedEntryTimestamp []byte       `json:"SignedEntryTimestamp"`
+		Payload              rekorPayload `json:"Payload"`
+	}
+	if err := json.Unmarshal([]byte(bundle), &b); err != nil {
+		return time.Time{}, xerrors.Errorf("bundle decode error: %w", err)
+	}
+
+	payload, err := json.Marshal(b.Payload)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("payload encode error: %w", err)
+	}
+	if err = v.rekor.Verify(payload, b.SignedEntryTimestamp); err != nil {
+		return time.Time{}, xerrors.Errorf("signed entry timestamp verification error: %w", err)
+	}
+
+	// The entry must be of the certificate, otherwise the time of another entry could be used
+	body, err := base64.StdEncoding.DecodeString(b.Payload.Body)
+	if err != nil {
+		return time.Time{}, xerrors.Errorf("body decode error: %w", err)
+	}
+	var entry any
+	if err = json.Unmarshal(body, &entry); err != nil {
+		return time.Time{}, xerrors.Errorf("entry decode error: %w", err)
+	}
+	if !containsCertificate(entry, cert) {
+		return time.Time{}, xerrors.New("the log entry is for another certificate")
+	}
+	return time.Unix(b.Payload.IntegratedTime, 0), nil
+}
+
+// containsCertificate checks if the Rekor entry has the base64-encoded PEM certificate,
+// e.g. "spec.signature.publicKey.content" of hashedrekord entries.
+func containsCertificate(v any, cert *x509.Certificate) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		return lo.SomeBy(lo.Values(v), func(e any) bool { return containsCertificate(e, cert) })
+	case []any:
+		return lo.SomeBy(v, func(e any) bool { return containsCertificate(e, cert) })
+	case string:
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return false
+		}
+		block, _ := pem.Decode(b)
+		return block != nil && bytes.Equal(block.Bytes, cert.Raw)
+	}
+	return false
+}
+
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidIssuerV2):
+			var issuer string
+			if _, err := asn1.UnmarshalWithParams(ext.Value, &issuer, "utf8"); err == nil {
+				return issuer
+			}
+		case ext.Id.Equal(oidIssuerV1):
+			// The raw string, not DER-encoded
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+func readLayer(img v1.Image, desc v1.Descriptor) ([]byte, error) {
+	layer, err := img.LayerByDigest(desc.Digest)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to get the layer: %w", err)
+	}
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to fetch the layer: %w", err)
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(io.LimitReader(rc, maxPayloadSize+1))
+	if err != nil {
+		return nil, xerrors.Errorf("read error: %w", err)
+	} else if len(b) > maxPayloadSize {
+		return nil, xerrors.Errorf("the payload exceeds %d bytes", maxPayloadSize)
+	}
+	return b, nil
+}
+
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,468 @@
+package signature_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	"github.com/aquasecurity/trivy/pkg/signature"
+	"github.com/aquasecurity/trivy/pkg/types"
+)
+
+const (
+	identity = "release@example.com"
+	issuer   = "https://accounts.example.com"
+)
+
+type signer struct {
+	key    *ecdsa.PrivateKey
+	cert   string // PEM-encoded Fulcio certificate for keyless signatures
+	bundle string // Rekor bundle for keyless signatures
+}
+
+func (s signer) sign(t *testing.T, payload []byte) string {
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, s.key, hash[:])
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func (s signer) annotations(sig string) map[string]string {
+	annotations := make(map[string]string)
+	if sig != "" {
+		annotations[signature.SignatureAnnotation] = sig
+	}
+	if s.cert != "" {
+		annotations[signature.CertificateAnnotation] = s.cert
+	}
+	if s.bundle != "" {
+		annotations[signature.BundleAnnotation] = s.bundle
+	}
+	return annotations
+}
+
+func TestCosignVerifier_Verify(t *testing.T) {
+	ts := httptest.NewServer(registry.New())
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "cosign.pub")
+	fulcioRoot := filepath.Join(dir, "fulcio.pem")
+
+	key := newKey(t)
+	writePublicKey(t, keyPath, key)
+	caKey := newKey(t)
+	ca := newCA(t, caKey)
+	require.NoError(t, os.WriteFile(fulcioRoot, pemCert(ca), 0o600))
+
+	keySigner := signer{key: key}
+	otherSigner := signer{key: newKey(t)}
+	keylessSigner := newKeylessSigner(t, ca, caKey, identity, time.Now().Add(-time.Minute))
+	strangerSigner := newKeylessSigner(t, ca, caKey, "stranger@example.com", time.Now().Add(-time.Minute))
+
+	keyPolicy := signature.Policy{KeyPath: keyPath}
+	keylessPolicy := signature.Policy{
+		CertificateIdentity:   identity,
+		CertificateOIDCIssuer: issuer,
+		FulcioRoot:            fulcioRoot,
+	}
+
+	tests := []struct {
+		name   string
+		repo   string
+		push   func(t *testing.T, digest name.Digest)
+		policy signature.Policy
+		want   types.SignatureVerification
+	}{
+		{
+			name: "signed with the key",
+			repo: "app/signed",
+			push: func(t *testing.T, digest name.Digest) {
+				pushSignature(t, digest, keySigner, digest.DigestStr())
+			},
+			policy: keyPolicy,
+			want: types.SignatureVerification{
+				Verified:   true,
+				Signatures: []types.Signature{{}},
+			},
+		},
+		{
+			name: "attested with the key",
+			repo: "app/attested",
+			push: func(t *testing.T, digest name.Digest) {
+				pushAttestation(t, digest, keySigner, "https://slsa.dev/provenance/v1")
+			},
+			policy: keyPolicy,
+			want: types.SignatureVerification{
+				Verified:     true,
+				Attestations: []types.Signature{{PredicateType: "https://slsa.dev/provenance/v1"}},
+			},
+		},
+		{
+			name:   "unsigned",
+			repo:   "app/unsigned",
+			push:   func(t *testing.T, digest name.Digest) {},
+			policy: keyPolicy,
+			want: types.SignatureVerification{
+				Message: "no signature found",
+			},
+		},
+		{
+			name: "signed with another key",
+			repo: "app/other-key",
+			push: func(t *testing.T, digest name.Digest) {
+				pushSignature(t, digest, otherSigner, digest.DigestStr())
+			},
+			policy: keyPolicy,
+			want: types.SignatureVerification{
+				Message: "no signature matches the policy",
+			},
+		},
+		{
+			name: "signature of another image",
+			repo: "app/copied",
+			push: func(t *testing.T, digest name.Digest) {
+				pushSignature(t, digest, keySigner, "sha256:"+strings.Repeat("0", 64))
+			},
+			policy: keyPolicy,
+			want: types.SignatureVerification{
+				Message: "no signature matches the policy",
+			},
+		},
+		{
+			name: "keyless",
+			repo: "app/keyless",
+			push: func(t *testing.T, digest name.Digest) {
+				pushSignature(t, digest, keylessSigner, digest.DigestStr())
+				pushAttestation(t, digest, keylessSigner, "https://cyclonedx.org/bom")
+			},
+			policy: keylessPolicy,
+			want: types.SignatureVerification{
+				Verified: true,
+				Signatures: []types.Signature{
+					{
+						Identity: identity,
+						Issuer:   issuer,
+					},
+				},
+				Attestations: []types.Signature{
+					{
+						PredicateType: "https://cyclonedx.org/bom",
+						Identity:      identity,
+						Issuer:        issuer,
+					},
+				},
+			},
+		},
+		{
+			name: "keyless with another identity",
+			repo: "app/stranger",
+			push: func(t *testing.T, digest name.Digest) {
+				pushSignature(t, digest, strangerSigner, digest.DigestStr())
+			},
+			policy: keylessPolicy,
+			want: types.SignatureVerification{
+				Message: "no signature matches the policy",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := random.Image(64, 1)
+			require.NoError(t, err)
+			ref, err := name.ParseReference(host+"/"+tt.repo+":latest", name.Insecure)
+			require.NoError(t, err)
+			require.NoError(t, remote.Write(ref, img))
+			imgDigest, err := img.Digest()
+			require.NoError(t, err)
+			digest := ref.Context().Digest(imgDigest.String())
+
+			tt.push(t, digest)
+
+			v, err := signature.NewCosignVerifier(tt.policy)
+			require.NoError(t, err)
+			got, err := v.Verify(context.Background(), digest, ftypes.RegistryOptions{Insecure: true})
+			require.NoError(t, err)
+
+			tt.want.Digest = imgDigest.String()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCosignVerifier_VerifyRekorBundle(t *testing.T) {
+	ts := httptest.NewServer(registry.New())
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	dir := t.TempDir()
+	fulcioRoot := filepath.Join(dir, "fulcio.pem")
+	rekorPublicKey := filepath.Join(dir, "rekor.pub")
+
+	caKey := newKey(t)
+	ca := newCA(t, caKey)
+	require.NoError(t, os.WriteFile(fulcioRoot, pemCert(ca), 0o600))
+	rekorKey := newKey(t)
+	writePublicKey(t, rekorPublicKey, rekorKey)
+
+	// The certificate expired long before the scan
+	signedAt := time.Now().Add(-50 * time.Minute)
+	expiredSigner := newKeylessSigner(t, ca, caKey, identity, signedAt)
+	otherSigner := newKeylessSigner(t, ca, caKey, identity, signedAt)
+
+	policy := signature.Policy{
+		CertificateIdentity:   identity,
+		CertificateOIDCIssuer: issuer,
+		FulcioRoot:            fulcioRoot,
+		RekorPublicKey:        rekorPublicKey,
+	}
+	policyWithoutRekor := policy
+	policyWithoutRekor.RekorPublicKey = ""
+
+	tests := []struct {
+		name     string
+		bundle   string
+		policy   signature.Policy
+		verified bool
+	}{
+		{
+			name:     "signed while the certificate was valid",
+			bundle:   rekorBundle(t, rekorKey, expiredSigner.cert, signedAt.Unix(), 0),
+			policy:   policy,
+			verified: true,
+		},
+		{
+			name:   "bundle not verified without the Rekor public key",
+			bundle: rekorBundle(t, rekorKey, expiredSigner.cert, signedAt.Unix(), 0),
+			policy: policyWithoutRekor,
+		},
+		{
+			name:   "integrated time rewritten",
+			bundle: rekorBundle(t, rekorKey, expiredSigner.cert, time.Now().Unix(), signedAt.Unix()),
+			policy: policy,
+		},
+		{
+			name:   "bundle signed by another key",
+			bundle: rekorBundle(t, newKey(t), expiredSigner.cert, signedAt.Unix(), 0),
+			policy: policy,
+		},
+		{
+			name:   "bundle of another certificate",
+			bundle: rekorBundle(t, rekorKey, otherSigner.cert, signedAt.Unix(), 0),
+			policy: policy,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := random.Image(64, 1)
+			require.NoError(t, err)
+			ref, err := name.ParseReference(fmt.Sprintf("%s/app/bundle%d:latest", host, i), name.Insecure)
+			require.NoError(t, err)
+			require.NoError(t, remote.Write(ref, img))
+			imgDigest, err := img.Digest()
+			require.NoError(t, err)
+			digest := ref.Context().Digest(imgDigest.String())
+
+			s := expiredSigner
+			s.bundle = tt.bundle
+			pushSignature(t, digest, s, digest.DigestStr())
+
+			v, err := signature.NewCosignVerifier(tt.policy)
+			require.NoError(t, err)
+			got, err := v.Verify(context.Background(), digest, ftypes.RegistryOptions{Insecure: true})
+			require.NoError(t, err)
+			assert.Equal(t, tt.verified, got.Verified)
+		})
+	}
+}
+
+func TestNewCosignVerifier(t *testing.T) {
+	_, err := signature.NewCosignVerifier(signature.Policy{CertificateIdentity: identity})
+	assert.ErrorContains(t, err, "must be specified")
+
+	_, err = signature.NewCosignVerifier(signature.Policy{KeyPath: "testdata/missing.pub"})
+	assert.Error(t, err)
+}
+
+// pushSignature pushes the signature in the same way as 'cosign sign'
+func pushSignature(t *testing.T, digest name.Digest, s signer, signedDigest string) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":%q},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`,
+		digest.Context().Name(), signedDigest))
+	layer := mutate.Addendum{
+		Layer:       static.NewLayer(payload, signature.SimpleSigningMediaType),
+		Annotations: s.annotations(s.sign(t, payload)),
+	}
+	pushLayer(t, digest, ".sig", layer)
+}
+
+// pushAttestation pushes the in-toto attestation in the same way as 'cosign attest'
+func pushAttestation(t *testing.T, digest name.Digest, s signer, predicateType string) {
+	statement, err := json.Marshal(in_toto.Statement{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          in_toto.StatementInTotoV01,
+			PredicateType: predicateType,
+			Subject: []in_toto.Subject{
+				{
+					Name:   digest.Context().Name(),
+					Digest: map[string]string{"sha256": strings.TrimPrefix(digest.DigestStr(), "sha256:")},
+				},
+			},
+		},
+		Predicate: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	envelope, err := json.Marshal(dsse.Envelope{
+		PayloadType: in_toto.PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(statement),
+		Signatures: []dsse.Signature{
+			{Sig: s.sign(t, dsse.PAE(in_toto.PayloadType, statement))},
+		},
+	})
+	require.NoError(t, err)
+
+	layer := mutate.Addendum{
+		Layer:       static.NewLayer(envelope, signature.DSSEMediaType),
+		Annotations: s.annotations(""),
+	}
+	pushLayer(t, digest, ".att", layer)
+}
+
+func pushLayer(t *testing.T, digest name.Digest, suffix string, layer mutate.Addendum) {
+	img, err := mutate.Append(empty.Image, layer)
+	require.NoError(t, err)
+	img = mutate.MediaType(img, ggcrtypes.OCIManifestSchema1)
+
+	tag := digest.Context().Tag(strings.Replace(digest.DigestStr(), ":", "-", 1) + suffix)
+	require.NoError(t, remote.Write(tag, img))
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func newCA(t *testing.T, key *ecdsa.PrivateKey) *x509.Certificate {
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fulcio"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+// newKeylessSigner issues the certificate for the ephemeral key as Fulcio does
+func newKeylessSigner(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, email string, issuedAt time.Time) signer {
+	issuerExt, err := asn1.MarshalWithParams(issuer, "utf8")
+	require.NoError(t, err)
+
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber:   big.NewInt(2),
+		NotBefore:      issuedAt,
+		NotAfter:       issuedAt.Add(10 * time.Minute),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		EmailAddresses: []string{email},
+		ExtraExtensions: []pkix.Extension{
+			{
+				Id:    asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8},
+				Value: issuerExt,
+			},
+		},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return signer{
+		key:  key,
+		cert: string(pemCert(cert)),
+	}
+}
+
+// rekorBundle returns the Rekor bundle of the hashedrekord entry of the certificate.
+// The signed entry timestamp is calculated over signedTime if it is not zero, to simulate a rewritten bundle.
+func rekorBundle(t *testing.T, rekorKey *ecdsa.PrivateKey, cert string, integratedTime, signedTime int64) string {
+	body, err := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"signature": map[string]any{
+				"publicKey": map[string]any{
+					"content": base64.StdEncoding.EncodeToString([]byte(cert)),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	canonicalPayload := func(integratedTime int64) []byte {
+		return []byte(fmt.Sprintf(`{"body":%q,"integratedTime":%d,"logID":"c0d23d6a","logIndex":1}`,
+			base64.StdEncoding.EncodeToString(body), integratedTime))
+	}
+	if signedTime == 0 {
+		signedTime = integratedTime
+	}
+	set := signer{key: rekorKey}.sign(t, canonicalPayload(signedTime))
+
+	return fmt.Sprintf(`{"SignedEntryTimestamp":%q,"Payload":%s}`, set, canonicalPayload(integratedTime))
+}
+
+func pemCert(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	})
+}
+
+func writePublicKey(t *testing.T, keyPath string, key *ecdsa.PrivateKey) {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	b := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: der,
+	})
+	require.NoError(t, os.WriteFile(keyPath, b, 0o600))
+}
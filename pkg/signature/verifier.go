@@ -0,0 +1,65 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+
+	"golang.org/x/xerrors"
+)
+
+// Verifier verifies the signatures with the public key of the signer
+type Verifier struct {
+	key crypto.PublicKey
+}
+
+// NewVerifier loads the PEM-encoded ECDSA, RSA or Ed25519 public key
+func NewVerifier(keyPath string) (*Verifier, error) {
+	b, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, xerrors.Errorf("unable to read the public key: %w", err)
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, xerrors.Errorf("no PEM block in %s", keyPath)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, xerrors.Errorf("public key parse error: %w", err)
+	}
+	return newVerifier(key)
+}
+
+func newVerifier(key crypto.PublicKey) (*Verifier, error) {
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+	default:
+		return nil, xerrors.Errorf("unsupported public key type: %T", key)
+	}
+	return &Verifier{key: key}, nil
+}
+
+// Verify verifies the signature of the blob in the same way as 'cosign verify-blob'
+func (v *Verifier) Verify(blob, sig []byte) error {
+	digest := sha256.Sum256(blob)
+	switch key := v.key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], sig) {
+			return xerrors.New("invalid ECDSA signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+			return xerrors.Errorf("invalid RSA signature: %w", err)
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, blob, sig) {
+			return xerrors.New("invalid Ed25519 signature")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package types
+
+// ErrorCode is a stable identifier of the cause of a failed scan.
+// Wrappers can branch on it instead of matching error messages, which may change between versions.
+type ErrorCode string
+
+const (
+	ErrorCodeUnknown             ErrorCode = "UNKNOWN"
+	ErrorCodeAuthFailure         ErrorCode = "AUTH_FAILURE"
+	ErrorCodeRateLimit           ErrorCode = "RATE_LIMIT"
+	ErrorCodeUnsupportedArtifact ErrorCode = "UNSUPPORTED_ARTIFACT"
+	ErrorCodeDBCorrupt           ErrorCode = "DB_CORRUPT"
+	ErrorCodeUnsigned            ErrorCode = "UNSIGNED"
+)
+
+// Error represents the cause of a failed scan
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Hint    string `json:",omitempty"` // How to resolve the error
+}
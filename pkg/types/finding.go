@@ -1,5 +1,12 @@
 package types
 
+import (
+	"encoding/json"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
 type FindingType string
 type FindingStatus string
 
@@ -33,7 +40,9 @@ type ModifiedFinding struct {
 	Status    FindingStatus
 	Statement string
 	Source    string
-	Finding   finding // one of findings
+	Owner     string     `json:",omitempty"` // the person or team accountable for the exception
+	ExpiredAt *time.Time `json:",omitempty"` // the date the exception expires
+	Finding   finding    // one of findings
 }
 
 func NewModifiedFinding(f finding, status FindingStatus, statement, source string) ModifiedFinding {
@@ -45,3 +54,40 @@ func NewModifiedFinding(f finding, status FindingStatus, statement, source strin
 		Finding:   f,
 	}
 }
+
+// UnmarshalJSON unmarshals ModifiedFinding, decoding "Finding" according to "Type".
+func (m *ModifiedFinding) UnmarshalJSON(data []byte) error {
+	type alias ModifiedFinding
+	aux := &struct {
+		*alias
+		Finding json.RawMessage
+	}{
+		alias: (*alias)(m),
+	}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	var err error
+	switch m.Type {
+	case FindingTypeVulnerability:
+		m.Finding, err = unmarshalFinding[DetectedVulnerability](aux.Finding)
+	case FindingTypeMisconfiguration:
+		m.Finding, err = unmarshalFinding[DetectedMisconfiguration](aux.Finding)
+	case FindingTypeSecret:
+		m.Finding, err = unmarshalFinding[DetectedSecret](aux.Finding)
+	case FindingTypeLicense:
+		m.Finding, err = unmarshalFinding[DetectedLicense](aux.Finding)
+	default:
+		return xerrors.Errorf("unknown finding type: %s", m.Type)
+	}
+	return err
+}
+
+func unmarshalFinding[T finding](data []byte) (T, error) {
+	var f T
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, xerrors.Errorf("unable to unmarshal %s: %w", f.findingType(), err)
+	}
+	return f, nil
+}
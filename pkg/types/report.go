@@ -17,6 +17,9 @@ type Report struct {
 	Metadata      Metadata            `json:",omitempty"`
 	Results       Results             `json:",omitempty"`
 
+	// Incomplete is set when the analysis stopped early as the budget given by --budget was exceeded
+	Incomplete *ftypes.Incomplete `json:",omitempty"`
+
 	// SBOM
 	CycloneDX *ftypes.CycloneDX `json:"-"` // Just for internal usage, not exported in JSON
 }
@@ -32,6 +35,12 @@ type Metadata struct {
 	RepoTags    []string      `json:",omitempty"`
 	RepoDigests []string      `json:",omitempty"`
 	ImageConfig v1.ConfigFile `json:",omitempty"`
+
+	// Signature is set when the cosign signatures of the image are verified with '--verify-signature'
+	Signature *SignatureVerification `json:",omitempty"`
+
+	// Error is set when the scan failed, so that the cause can be read from the JSON report
+	Error *Error `json:",omitempty"`
 }
 
 // Results to hold list of Result
@@ -59,15 +68,27 @@ const (
 	ComplianceAWSCIS14         = Compliance("aws-cis-1.4")
 	ComplianceDockerCIS        = Compliance("docker-cis")
 
-	FormatTable      Format = "table"
-	FormatJSON       Format = "json"
-	FormatTemplate   Format = "template"
-	FormatSarif      Format = "sarif"
-	FormatCycloneDX  Format = "cyclonedx"
-	FormatSPDX       Format = "spdx"
-	FormatSPDXJSON   Format = "spdx-json"
-	FormatGitHub     Format = "github"
-	FormatCosignVuln Format = "cosign-vuln"
+	FormatTable       Format = "table"
+	FormatJSON        Format = "json"
+	FormatTemplate    Format = "template"
+	FormatSarif       Format = "sarif"
+	FormatCycloneDX   Format = "cyclonedx"
+	FormatSPDX        Format = "spdx"
+	FormatSPDXJSON    Format = "spdx-json"
+	FormatGitHub      Format = "github"
+	FormatCosignVuln  Format = "cosign-vuln"
+	FormatMetrics     Format = "metrics"
+	FormatPrometheus  Format = "prometheus"
+	FormatRemediation Format = "remediation"
+	FormatLayers      Format = "layers"
+	FormatPlain       Format = "plain"
+	FormatJUnit       Format = "junit"
+	FormatHTML        Format = "html"
+	FormatCSV         Format = "csv"
+	FormatXLSX        Format = "xlsx"
+
+	FormatGitLabDependencyScanning Format = "gitlab-dependency-scanning"
+	FormatGitLabContainerScanning  Format = "gitlab-container-scanning"
 )
 
 var (
@@ -81,6 +102,17 @@ var (
 		FormatSPDXJSON,
 		FormatGitHub,
 		FormatCosignVuln,
+		FormatMetrics,
+		FormatPrometheus,
+		FormatRemediation,
+		FormatLayers,
+		FormatPlain,
+		FormatJUnit,
+		FormatHTML,
+		FormatCSV,
+		FormatXLSX,
+		FormatGitLabDependencyScanning,
+		FormatGitLabContainerScanning,
 	}
 	SupportedSBOMFormats = []Format{
 		FormatCycloneDX,
@@ -110,17 +142,20 @@ type Result struct {
 	Misconfigurations []DetectedMisconfiguration `json:"Misconfigurations,omitempty"`
 	Secrets           []DetectedSecret           `json:"Secrets,omitempty"`
 	Licenses          []DetectedLicense          `json:"Licenses,omitempty"`
+	Certificates      []ftypes.Certificate       `json:"Certificates,omitempty"`
+	PrivateKeys       []ftypes.PrivateKey        `json:"PrivateKeys,omitempty"`
 	CustomResources   []ftypes.CustomResource    `json:"CustomResources,omitempty"`
 
 	// ModifiedFindings holds a list of findings that have been modified from their original state.
 	// This can include vulnerabilities that have been marked as ignored, not affected, or have had
-	// their severity adjusted. It is currently available only in the table format.
-	ModifiedFindings []ModifiedFinding `json:"-"`
+	// their severity adjusted. It is shown in the table and JSON formats with "--show-suppressed".
+	ModifiedFindings []ModifiedFinding `json:"ExperimentalModifiedFindings,omitempty"`
 }
 
 func (r *Result) IsEmpty() bool {
 	return len(r.Packages) == 0 && len(r.Vulnerabilities) == 0 && len(r.Misconfigurations) == 0 &&
-		len(r.Secrets) == 0 && len(r.Licenses) == 0 && len(r.CustomResources) == 0 && len(r.ModifiedFindings) == 0
+		len(r.Secrets) == 0 && len(r.Licenses) == 0 && len(r.Certificates) == 0 && len(r.PrivateKeys) == 0 &&
+		len(r.CustomResources) == 0 && len(r.ModifiedFindings) == 0
 }
 
 type MisconfSummary struct {
@@ -20,11 +20,15 @@ type SBOMSource = string
 const (
 	SBOMSourceOCI   = SBOMSource("oci")
 	SBOMSourceRekor = SBOMSource("rekor")
+
+	// SBOMSourceRegistry is the signed SBOM attestations attached to images as OCI referrers
+	SBOMSourceRegistry = SBOMSource("registry")
 )
 
 var (
 	SBOMSources = []string{
 		SBOMSourceOCI,
 		SBOMSourceRekor,
+		SBOMSourceRegistry,
 	}
 )
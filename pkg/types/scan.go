@@ -14,6 +14,7 @@ type ScanTarget struct {
 	Misconfigurations []types.Misconfiguration
 	Secrets           []types.Secret
 	Licenses          []types.LicenseFile
+	Certificates      []types.CertificateInventory
 
 	// CustomResources hold analysis results from custom analyzers.
 	// It is for extensibility and not used in OSS.
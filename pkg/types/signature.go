@@ -0,0 +1,17 @@
+package types
+
+// SignatureVerification represents the result of verifying the cosign signatures and attestations of the image
+type SignatureVerification struct {
+	Verified     bool
+	Digest       string      `json:",omitempty"` // The image digest the signatures are verified against
+	Signatures   []Signature `json:",omitempty"`
+	Attestations []Signature `json:",omitempty"`
+	Message      string      `json:",omitempty"` // Why no signature was verified
+}
+
+// Signature represents a valid signature or attestation
+type Signature struct {
+	PredicateType string `json:",omitempty"` // Attestations only
+	Identity      string `json:",omitempty"` // The subject of the Fulcio certificate for keyless signatures
+	Issuer        string `json:",omitempty"` // The OIDC issuer of the Fulcio certificate for keyless signatures
+}
@@ -9,12 +9,14 @@ import (
 type DetectedVulnerability struct {
 	VulnerabilityID  string               `json:",omitempty"`
 	VendorIDs        []string             `json:",omitempty"`
+	Aliases          []string             `json:",omitempty"` // IDs of the same issue from other advisory sources, merged into this vulnerability
 	PkgID            string               `json:",omitempty"` // It is used to construct dependency graph.
 	PkgName          string               `json:",omitempty"`
 	PkgPath          string               `json:",omitempty"` // This field is populated in the case of language-specific packages such as egg/wheel and gemspec
 	PkgIdentifier    ftypes.PkgIdentifier `json:",omitempty"`
 	InstalledVersion string               `json:",omitempty"`
 	FixedVersion     string               `json:",omitempty"`
+	PkgFixedVersion  string               `json:",omitempty"` // The lowest version of the package fixing all the detected vulnerabilities
 	Status           types.Status         `json:",omitempty"`
 	Layer            ftypes.Layer         `json:",omitempty"`
 	SeveritySource   types.SourceID       `json:",omitempty"`
@@ -23,6 +25,19 @@ type DetectedVulnerability struct {
 	// DataSource holds where the advisory comes from
 	DataSource *types.DataSource `json:",omitempty"`
 
+	// EPSS and KEV are filled only when the exploit data is required
+	EPSS *EPSS `json:",omitempty"`
+	KEV  *KEV  `json:",omitempty"`
+
+	// CVSSv40 holds CVSS v4.0 vectors and scores per vendor
+	CVSSv40 VendorCVSSv40 `json:",omitempty"`
+
+	// OriginalSeverity holds the severity before it was overridden by the severity override file
+	OriginalSeverity *OriginalSeverity `json:",omitempty"`
+
+	// Reachability is filled only when the affected-symbol metadata of the vulnerability is given
+	Reachability Reachability `json:",omitempty"`
+
 	// Custom is for extensibility and not supposed to be used in OSS
 	Custom interface{} `json:",omitempty"`
 
@@ -30,6 +45,44 @@ type DetectedVulnerability struct {
 	types.Vulnerability
 }
 
+// EPSS holds the probability of exploitation from the Exploit Prediction Scoring System
+type EPSS struct {
+	Score      float64
+	Percentile float64
+}
+
+// KEV holds the entry in the CISA Known Exploited Vulnerabilities catalog
+type KEV struct {
+	DateAdded                  string `json:",omitempty"`
+	DueDate                    string `json:",omitempty"`
+	KnownRansomwareCampaignUse string `json:",omitempty"`
+}
+
+// VendorCVSSv40 is a map of CVSS v4.0 per vendor.
+// The vulnerability DB schema doesn't have the fields for CVSS v4.0 yet, so they are kept separately.
+type VendorCVSSv40 map[types.SourceID]CVSSv40
+
+// CVSSv40 holds the CVSS v4.0 vector and score
+type CVSSv40 struct {
+	V40Vector string  `json:",omitempty"`
+	V40Score  float64 `json:",omitempty"`
+}
+
+// OriginalSeverity is kept for auditing when the organization overrides the severity
+type OriginalSeverity struct {
+	Severity       string
+	SeveritySource types.SourceID `json:",omitempty"`
+	Statement      string         `json:",omitempty"` // the reason for the override
+}
+
+// Reachability represents whether the vulnerable code can be called from the scanned artifact
+type Reachability string
+
+const (
+	Reachable   Reachability = "reachable"
+	Unreachable Reachability = "unreachable"
+)
+
 func (DetectedVulnerability) findingType() FindingType { return FindingTypeVulnerability }
 
 // BySeverity implements sort.Interface based on the Severity field.
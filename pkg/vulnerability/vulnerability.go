@@ -1,13 +1,19 @@
 package vulnerability
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/google/wire"
+	"github.com/samber/lo"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
 
 	"github.com/aquasecurity/trivy-db/pkg/db"
 	dbTypes "github.com/aquasecurity/trivy-db/pkg/types"
 	"github.com/aquasecurity/trivy-db/pkg/vulnsrc/vulnerability"
+	"github.com/aquasecurity/trivy/pkg/advisory"
+	"github.com/aquasecurity/trivy/pkg/cvss"
 	"github.com/aquasecurity/trivy/pkg/log"
 	"github.com/aquasecurity/trivy/pkg/types"
 )
@@ -71,6 +77,10 @@ func (c Client) FillInfo(vulns []types.DetectedVulnerability) {
 		// Get the vulnerability detail
 		vulnID := vulns[i].VulnerabilityID
 		vuln, err := c.dbc.GetVulnerability(vulnID)
+		if custom, ok := advisory.Vulnerability(vulnID); ok {
+			// The detail from additional advisory sources complements or replaces the one in trivy-db
+			vuln, err = mergeVulnerability(vuln, custom), nil
+		}
 		if err != nil {
 			log.Logger.Warnf("Error while getting vulnerability details: %s", err)
 			continue
@@ -100,6 +110,14 @@ func (c Client) FillInfo(vulns []types.DetectedVulnerability) {
 			vuln.VendorSeverity[severitySource] = s
 		}
 
+		// CVSS v4.0 is used for the severity only when no vendor provides it
+		vulns[i].CVSSv40 = c.getCVSSv40(vulnID)
+		if severity == dbTypes.SeverityUnknown.String() {
+			if s, src, ok := cvssV40Severity(vulnID, vulns[i].CVSSv40, source); ok {
+				severity, severitySource = s, src
+			}
+		}
+
 		// Add the vulnerability detail
 		vulns[i].Vulnerability = vuln
 
@@ -109,6 +127,40 @@ func (c Client) FillInfo(vulns []types.DetectedVulnerability) {
 	}
 }
 
+// mergeVulnerability fills the vulnerability detail with the one from an additional advisory source.
+// The vendor severities and CVSS are added so that they can be selected by the data source of the advisory.
+func mergeVulnerability(vuln, custom dbTypes.Vulnerability) dbTypes.Vulnerability {
+	if vuln.Title == "" {
+		vuln.Title = custom.Title
+	}
+	if vuln.Description == "" {
+		vuln.Description = custom.Description
+	}
+	if len(vuln.CweIDs) == 0 {
+		vuln.CweIDs = custom.CweIDs
+	}
+	if vuln.PublishedDate == nil {
+		vuln.PublishedDate = custom.PublishedDate
+	}
+	if vuln.LastModifiedDate == nil {
+		vuln.LastModifiedDate = custom.LastModifiedDate
+	}
+	for source, severity := range custom.VendorSeverity {
+		if vuln.VendorSeverity == nil {
+			vuln.VendorSeverity = make(dbTypes.VendorSeverity)
+		}
+		vuln.VendorSeverity[source] = severity
+	}
+	for source, cvss := range custom.CVSS {
+		if vuln.CVSS == nil {
+			vuln.CVSS = make(dbTypes.VendorCVSS)
+		}
+		vuln.CVSS[source] = cvss
+	}
+	vuln.References = lo.Uniq(append(vuln.References, custom.References...))
+	return vuln
+}
+
 func (c Client) getVendorSeverity(vulnID string, vuln *dbTypes.Vulnerability, source dbTypes.SourceID) (string, dbTypes.SourceID) {
 	if vs, ok := vuln.VendorSeverity[source]; ok {
 		return vs.String(), source
@@ -133,6 +185,73 @@ func (c Client) getVendorSeverity(vulnID string, vuln *dbTypes.Vulnerability, so
 	return vuln.Severity, ""
 }
 
+// getCVSSv40 reads CVSS v4.0 from the raw vulnerability detail, as dbTypes.Vulnerability doesn't have the fields.
+// Scores are calculated from the vectors if they are missing.
+func (c Client) getCVSSv40(vulnID string) types.VendorCVSSv40 {
+	conn, ok := c.dbc.(interface{ Connection() *bolt.DB })
+	if !ok || conn.Connection() == nil {
+		return nil
+	}
+
+	var detail struct {
+		CVSS map[dbTypes.SourceID]types.CVSSv40
+	}
+	err := conn.Connection().View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("vulnerability"))
+		if bucket == nil {
+			return nil
+		}
+		value := bucket.Get([]byte(vulnID))
+		if value == nil {
+			return nil
+		}
+		if err := json.Unmarshal(value, &detail); err != nil {
+			return xerrors.Errorf("failed to unmarshal JSON: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Logger.Debugf("Unable to get CVSS v4.0 of %s: %s", vulnID, err)
+		return nil
+	}
+
+	vendorCVSS := make(types.VendorCVSSv40)
+	for source, v := range detail.CVSS {
+		if v.V40Vector == "" {
+			continue
+		}
+		if v.V40Score == 0 {
+			vector, err := cvss.ParseV40(v.V40Vector)
+			if err != nil {
+				log.Logger.Debugf("Invalid CVSS v4.0 vector of %s from %s: %s", vulnID, source, err)
+				continue
+			}
+			v.V40Score = vector.Score()
+		}
+		vendorCVSS[source] = v
+	}
+	if len(vendorCVSS) == 0 {
+		return nil
+	}
+	return vendorCVSS
+}
+
+// cvssV40Severity selects CVSS v4.0 in the same order as the vendor severity and converts the score into the severity
+func cvssV40Severity(vulnID string, vendorCVSS types.VendorCVSSv40, source dbTypes.SourceID) (string, dbTypes.SourceID, bool) {
+	sources := []dbTypes.SourceID{source}
+	if strings.HasPrefix(vulnID, "GHSA-") {
+		sources = append(sources, vulnerability.GHSA)
+	}
+	sources = append(sources, vulnerability.NVD)
+
+	for _, src := range sources {
+		if v, ok := vendorCVSS[src]; ok && v.V40Score > 0 {
+			return cvss.V40Severity(v.V40Score).String(), src, true
+		}
+	}
+	return "", "", false
+}
+
 func (c Client) getPrimaryURL(vulnID string, refs []string, source dbTypes.SourceID) string {
 	switch {
 	case strings.HasPrefix(vulnID, "CVE-"):
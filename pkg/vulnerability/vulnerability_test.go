@@ -282,6 +282,44 @@ func TestClient_FillInfo(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "happy path. Severity gets from CVSS v4.0",
+			fixtures: []string{"testdata/fixtures/vulnerability.yaml"},
+			vulns: []types.DetectedVulnerability{
+				{VulnerabilityID: "CVE-2024-0001"},
+			},
+			expectedVulnerabilities: []types.DetectedVulnerability{
+				{
+					VulnerabilityID: "CVE-2024-0001",
+					Status:          dbTypes.StatusAffected,
+					SeveritySource:  vulnerability.NVD,
+					Vulnerability: dbTypes.Vulnerability{
+						Title:       "dos",
+						Description: "dos vulnerability",
+						Severity:    dbTypes.SeverityCritical.String(),
+						References:  []string{"http://example.com"},
+						CVSS: map[dbTypes.SourceID]dbTypes.CVSS{
+							vulnerability.NVD: {
+								V3Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+								V3Score:  9.8,
+							},
+							vulnerability.GHSA: {},
+						},
+					},
+					CVSSv40: types.VendorCVSSv40{
+						vulnerability.NVD: {
+							V40Vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+							V40Score:  9.3,
+						},
+						vulnerability.GHSA: {
+							V40Vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:L/VA:L/SC:N/SI:N/SA:N",
+							V40Score:  6.9,
+						},
+					},
+					PrimaryURL: "https://avd.aquasec.com/nvd/cve-2024-0001",
+				},
+			},
+		},
 		{
 			name:     "GetVulnerability returns an error",
 			fixtures: []string{"testdata/fixtures/sad.yaml"},